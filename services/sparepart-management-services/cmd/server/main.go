@@ -1,18 +1,31 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sparepart-management-services/internal/alerts"
+	"sparepart-management-services/internal/app"
 	"sparepart-management-services/internal/config"
 	"sparepart-management-services/internal/database"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/email"
+	"sparepart-management-services/internal/exports"
+	"sparepart-management-services/internal/handlers"
+	"sparepart-management-services/internal/messaging"
+	"sparepart-management-services/internal/middleware"
 	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
 	"sparepart-management-services/internal/routes"
+	"sparepart-management-services/internal/storage"
 	"sparepart-management-services/internal/utils"
+	"sparepart-management-services/internal/webhooks"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -28,32 +41,32 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Initialize logger
-	if err := utils.InitLogger(config.App.Logging.Level); err != nil {
-		log.Fatalf("Failed to initialize logger: %v", err)
+	// Build the application container shared by every command and handler
+	container, err := app.New(config.App)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
 	}
-	logger := utils.GetLogger()
-	defer logger.Sync()
+	defer container.Close()
+	logger := container.Logger
 
 	// Create database if not exists (before connecting)
 	logger.Info("Checking database existence...")
-	if err := database.CreateDatabaseIfNotExists(); err != nil {
+	if err := database.CreateDatabaseIfNotExists(container.Config.Database.URL); err != nil {
 		logger.Fatal("Failed to create database", zap.Error(err))
 	}
 	logger.Info("Database ready")
 
 	// Connect to database
-	if err := database.Connect(); err != nil {
+	if err := container.Connect(); err != nil {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
-	defer database.Close()
 
 	logger.Info("Database connected successfully")
 
 	// Check if migrate command
 	if len(os.Args) > 1 && os.Args[1] == "migrate" {
 		logger.Info("Running database migrations...")
-		if err := database.RunMigrations(); err != nil {
+		if err := database.RunMigrations(container.Config.Database.URL); err != nil {
 			logger.Fatal("Failed to run migrations", zap.Error(err))
 		}
 		logger.Info("Migrations completed successfully")
@@ -63,7 +76,7 @@ func main() {
 	// Check if migrate-down command
 	if len(os.Args) > 1 && os.Args[1] == "migrate-down" {
 		logger.Info("Rolling back last migration...")
-		if err := database.DownMigrations(); err != nil {
+		if err := database.DownMigrations(container.Config.Database.URL); err != nil {
 			logger.Fatal("Failed to rollback migration", zap.Error(err))
 		}
 		logger.Info("Migration rollback completed successfully")
@@ -74,10 +87,83 @@ func main() {
 	if len(os.Args) > 1 && os.Args[1] == "seed" {
 		logger.Info("Running database seeders...")
 		ctx := context.Background()
-		if err := models.Seed(ctx); err != nil {
+		summary, err := models.Seed(ctx, container.DB)
+		if err != nil {
 			logger.Fatal("Failed to seed database", zap.Error(err))
 		}
-		logger.Info("Database seeding completed successfully")
+		logger.Info("Database seeding completed successfully",
+			zap.Any("locations", summary.Locations),
+			zap.Any("contact_persons", summary.ContactPersons),
+			zap.Any("spareparts", summary.Spareparts),
+		)
+		return
+	}
+
+	// Check if create-user command: create-user <username> [ADMIN|USER], with the password in
+	// CREATE_USER_PASSWORD or on the first line of stdin, so it stays out of process listings
+	// and shell history
+	if len(os.Args) > 1 && os.Args[1] == "create-user" {
+		if len(os.Args) < 3 {
+			logger.Fatal("Usage: create-user <username> [ADMIN|USER] (password from CREATE_USER_PASSWORD or stdin)")
+		}
+		role := utils.RoleUser
+		if len(os.Args) > 3 {
+			role = strings.ToUpper(os.Args[3])
+		}
+		if role != utils.RoleAdmin && role != utils.RoleUser {
+			logger.Fatal("Role must be ADMIN or USER", zap.String("role", role))
+		}
+		password := os.Getenv("CREATE_USER_PASSWORD")
+		if password == "" {
+			fmt.Fprint(os.Stderr, "Password: ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				logger.Fatal("Failed to read password from stdin", zap.Error(err))
+			}
+			password = strings.TrimRight(line, "\r\n")
+		}
+		if password == "" {
+			logger.Fatal("Password must not be empty")
+		}
+		hash, err := utils.HashPassword(password)
+		if err != nil {
+			logger.Fatal("Failed to hash password", zap.Error(err))
+		}
+		user, err := container.Store.CreateAppUser(context.Background(), sqlcdb.CreateAppUserParams{
+			Username:     os.Args[2],
+			PasswordHash: hash,
+			Role:         role,
+		})
+		if err != nil {
+			logger.Fatal("Failed to create user", zap.Error(err))
+		}
+		logger.Info("User created successfully", zap.Int32("id", user.ID), zap.String("username", user.Username), zap.String("role", user.Role))
+		return
+	}
+
+	// Check if thumbnails command: create the missing thumbnails of photos uploaded before
+	// thumbnails were generated on upload
+	if len(os.Args) > 1 && os.Args[1] == "thumbnails" {
+		ctx := context.Background()
+		rows, err := container.Store.ListItemDocumentation(ctx)
+		if err != nil {
+			logger.Fatal("Failed to list photos", zap.Error(err))
+		}
+		created, failed := 0, 0
+		for _, row := range rows {
+			for _, photo := range utils.ParsePhotos(row.Documentation) {
+				ok, err := container.Uploads.EnsureThumbnail(ctx, photo.URL)
+				if err != nil {
+					failed++
+					logger.Warn("Failed to create thumbnail", zap.String("path", photo.URL), zap.Error(err))
+					continue
+				}
+				if ok {
+					created++
+				}
+			}
+		}
+		logger.Info("Thumbnails completed", zap.Int("created", created), zap.Int("failed", failed))
 		return
 	}
 
@@ -90,14 +176,22 @@ func main() {
 	}
 
 	// Setup Gin
-	if config.App.App.IsProd {
+	if container.Config.App.IsProd {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	r := gin.New()
+	// The client IP (rate limits, request log) only comes from X-Forwarded-For when the
+	// request passed one of the configured proxies
+	if err := r.SetTrustedProxies(container.Config.App.TrustedProxies); err != nil {
+		logger.Fatal("Invalid TRUSTED_PROXIES", zap.Error(err))
+	}
 
 	// Middleware
+	// The request log comes first so it also records the 500 of a recovered panic
+	r.Use(middleware.RequestLogger(logger))
 	r.Use(gin.Recovery())
+	r.Use(middleware.Tracing())
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
@@ -106,29 +200,204 @@ func main() {
 		AllowCredentials: true,
 	}))
 
-	// Serve static files (uploads)
-	r.Use(static.Serve("/uploads", static.LocalFile(config.App.Upload.Dir, false)))
+	// Serve uploaded photos: from disk as static files, or streamed from the bucket
+	if container.Config.Upload.Backend == storage.BackendS3 {
+		r.GET("/uploads/*filepath", handlers.NewUploadHandler(container.Storage, logger).Serve)
+	} else {
+		r.Use(static.Serve("/uploads", static.LocalFile(container.Config.Upload.Dir, false)))
+	}
 
 	// Setup routes
-	routes.SetupRoutes(r)
+	routes.SetupRoutes(r, container)
+
+	// Periodically remove stored reports whose links have expired
+	go func() {
+		ticker := time.NewTicker(10 * time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			container.Reports.CleanupExpired(ctx, logger)
+			cancel()
+		}
+	}()
+
+	// Periodically refresh the stock summary materialized views
+	if interval := container.Config.Summary.RefreshInterval; interval > 0 {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				if err := container.Store.RefreshStockSummaries(ctx); err != nil {
+					logger.Error("Failed to refresh stock summaries", zap.Error(err))
+				}
+				cancel()
+			}
+		}()
+	}
+
+	// Periodically record today's stock snapshot (see GET /sparepart/stock/trends?source=snapshot)
+	if interval := container.Config.Summary.SnapshotInterval; interval > 0 {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				if _, err := container.Store.RecordStockSnapshot(ctx); err != nil {
+					logger.Error("Failed to record stock snapshot", zap.Error(err))
+				}
+				cancel()
+			}
+		}()
+	}
+
+	// Periodically flag suspicious stock changes (see GET /sparepart/alerts/anomalies)
+	if anomaly := container.Config.Anomaly; anomaly.Interval > 0 {
+		go func() {
+			ticker := time.NewTicker(anomaly.Interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				flagged, err := container.Store.DetectAnomalies(ctx, repository.AnomalyRules{
+					Since:          time.Now().Add(-anomaly.Lookback),
+					MinDropPercent: int32(anomaly.QuantityDropPercent),
+					MinDeletes:     int32(anomaly.DeleteThreshold),
+				})
+				if err != nil {
+					logger.Error("Failed to detect anomalies", zap.Error(err))
+				} else if flagged > 0 {
+					logger.Info("Anomalies flagged", zap.Int64("count", flagged))
+				}
+				cancel()
+			}
+		}()
+	}
+
+	// Periodically evaluate the alert rules against the current stock
+	if alert := container.Config.Alert; alert.Interval > 0 {
+		notifiers := map[string]alerts.Notifier{alerts.ChannelLog: alerts.NewLogNotifier(logger)}
+		if alert.WebhookURL != "" {
+			notifiers[alerts.ChannelWebhook] = alerts.NewWebhookNotifier(alert.WebhookURL, alert.WebhookTimeout)
+		} else {
+			logger.Warn("ALERT_WEBHOOK_URL not configured, WEBHOOK alert rules will not notify")
+		}
+		// Recipients that cannot be reached through the rule's channel get an in-app notification
+		evaluator := alerts.NewEvaluator(container.Store, notifiers, alerts.NewInAppNotifier(container.Store), logger)
+
+		go func() {
+			ticker := time.NewTicker(alert.Interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				if err := evaluator.Evaluate(ctx); err != nil {
+					logger.Error("Failed to evaluate alert rules", zap.Error(err))
+				}
+				cancel()
+			}
+		}()
+	}
+
+	// Periodically send stock change events to the subscribed webhooks
+	if webhook := container.Config.Webhook; webhook.Interval > 0 {
+		dispatcher := webhooks.NewDispatcher(container.Store, webhook.Timeout, webhook.MaxAttempts, logger)
+
+		go func() {
+			ticker := time.NewTicker(webhook.Interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				if err := dispatcher.Dispatch(ctx); err != nil {
+					logger.Error("Failed to dispatch webhooks", zap.Error(err))
+				}
+				cancel()
+			}
+		}()
+	}
+
+	// Periodically render the queued background exports (see POST /sparepart/stock/export)
+	if export := container.Config.Export; export.Interval > 0 {
+		worker := exports.NewWorker(container.Store, container.Uploads, container.Reports, export.Timeout, logger)
+
+		go func() {
+			ticker := time.NewTicker(export.Interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				if err := worker.Run(context.Background()); err != nil {
+					logger.Error("Failed to run export jobs", zap.Error(err))
+				}
+			}
+		}()
+	}
+
+	// Periodically email the digest of low stock and sparepart requests awaiting approval
+	if digest := container.Config.Email; digest.Interval > 0 && digest.SMTPHost != "" {
+		mailer := email.NewSMTPMailer(digest.SMTPHost, digest.SMTPPort, digest.SMTPUsername, digest.SMTPPassword, digest.From)
+		digester := email.NewDigester(container.Store, mailer, digest.Recipients, digest.LowStockThreshold, logger)
+
+		go func() {
+			ticker := time.NewTicker(digest.Interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				if err := digester.Send(ctx); err != nil {
+					logger.Error("Failed to send email digests", zap.Error(err))
+				}
+				cancel()
+			}
+		}()
+	} else if digest.Interval > 0 {
+		logger.Warn("SMTP_HOST not configured, email digests are disabled")
+	}
+
+	// Periodically send the queued messages to contact persons (see GET /sparepart/admin/messages)
+	if message := container.Config.Message; message.Interval > 0 {
+		var provider messaging.Provider
+		switch message.Provider {
+		case messaging.ProviderTwilio:
+			provider = messaging.NewTwilioProvider(message.TwilioAccountSID, message.TwilioAuthToken, message.TwilioFrom, message.Timeout)
+		case messaging.ProviderGateway:
+			provider = messaging.NewGatewayProvider(message.GatewayURL, message.GatewayToken, message.Timeout)
+		default:
+			logger.Warn("MESSAGE_PROVIDER not configured, contact person messages will not be sent", zap.String("provider", message.Provider))
+		}
+
+		if provider != nil {
+			dispatcher := messaging.NewDispatcher(container.Store, provider, message.CountryCode, message.Timeout, message.MaxAttempts, message.MaxAge, logger)
+
+			go func() {
+				ticker := time.NewTicker(message.Interval)
+				defer ticker.Stop()
+				for range ticker.C {
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+					if err := dispatcher.Dispatch(ctx); err != nil {
+						logger.Error("Failed to dispatch messages", zap.Error(err))
+					}
+					cancel()
+				}
+			}()
+		}
+	}
+
+	// Leave room for the export budget so a slow export still gets its 504 written
+	writeTimeout := max(15*time.Second, container.Config.Timeout.Export+5*time.Second)
 
 	// Create HTTP server
 	srv := &http.Server{
-		Addr:         fmt.Sprintf("%s:%d", config.App.App.Host, config.App.App.Port),
+		Addr:         fmt.Sprintf("%s:%d", container.Config.App.Host, container.Config.App.Port),
 		Handler:      r,
 		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
+		WriteTimeout: writeTimeout,
 		IdleTimeout:  60 * time.Second,
 	}
 
 	// Start server in goroutine
 	go func() {
 		logger.Info("Starting Sparepart Management Service",
-			zap.String("host", config.App.App.Host),
-			zap.Int("port", config.App.App.Port),
-			zap.String("env", config.App.App.NodeEnv),
+			zap.String("host", container.Config.App.Host),
+			zap.Int("port", container.Config.App.Port),
+			zap.String("env", container.Config.App.NodeEnv),
 		)
-		logger.Info("API available at http://" + config.App.App.Host + ":" + strconv.Itoa(config.App.App.Port) + config.App.App.APIPrefix)
+		logger.Info("API available at http://" + container.Config.App.Host + ":" + strconv.Itoa(container.Config.App.Port) + container.Config.App.APIPrefix)
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start server", zap.Error(err))
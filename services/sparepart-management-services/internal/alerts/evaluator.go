@@ -0,0 +1,187 @@
+// Package alerts evaluates the admin-defined alert rules against the current stock and
+// notifies the rule recipients through the rule's channel.
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// Operators an alert rule compares the quantity with its threshold by
+const (
+	OperatorGT  = "GT"
+	OperatorGTE = "GTE"
+	OperatorLT  = "LT"
+	OperatorLTE = "LTE"
+	OperatorEQ  = "EQ"
+)
+
+// Holds reports whether quantity satisfies "quantity <operator> threshold"
+func Holds(operator string, quantity int64, threshold int32) bool {
+	t := int64(threshold)
+	switch operator {
+	case OperatorGT:
+		return quantity > t
+	case OperatorGTE:
+		return quantity >= t
+	case OperatorLT:
+		return quantity < t
+	case OperatorLTE:
+		return quantity <= t
+	case OperatorEQ:
+		return quantity == t
+	}
+	return false
+}
+
+// Evaluator checks every enabled rule per location in its scope. A rule notifies when a
+// location starts matching; it stays quiet while the location keeps matching and fires
+// again only after the condition cleared in between. When the rule's channel fails, the
+// notification goes to the fallback (the in-app notification center) instead.
+type Evaluator struct {
+	logger    *zap.Logger
+	queries   repository.AlertEvaluationRepository
+	notifiers map[string]Notifier
+	fallback  Notifier
+}
+
+// NewEvaluator builds the evaluator; fallback may be nil
+func NewEvaluator(queries repository.AlertEvaluationRepository, notifiers map[string]Notifier, fallback Notifier, logger *zap.Logger) *Evaluator {
+	return &Evaluator{
+		logger:    logger,
+		queries:   queries,
+		notifiers: notifiers,
+		fallback:  fallback,
+	}
+}
+
+// Evaluate runs every enabled rule; a failing rule does not stop the others
+func (e *Evaluator) Evaluate(ctx context.Context) error {
+	rules, err := e.queries.ListEnabledAlertRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	var errs []error
+	for _, rule := range rules {
+		if err := e.evaluateRule(ctx, rule); err != nil {
+			errs = append(errs, fmt.Errorf("alert rule %d: %w", rule.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Evaluator) evaluateRule(ctx context.Context, rule sqlcdb.AlertRule) error {
+	quantities, err := e.queries.ListAlertRuleQuantities(ctx, sqlcdb.ListAlertRuleQuantitiesParams{
+		SparepartID: rule.SparepartID,
+		StockType:   rule.StockType,
+		Region:      rule.Region,
+		LocationID:  rule.LocationID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get quantities: %w", err)
+	}
+
+	firings, err := e.queries.ListAlertRuleFirings(ctx, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get firing state: %w", err)
+	}
+	cleared := make(map[int32]bool, len(firings))
+	for _, firing := range firings {
+		cleared[firing.LocationID] = true
+	}
+
+	var matches []Match
+	for _, row := range quantities {
+		if !Holds(rule.Operator, row.Quantity, rule.Threshold) {
+			continue
+		}
+		if cleared[row.LocationID] {
+			// Still firing
+			delete(cleared, row.LocationID)
+			continue
+		}
+		matches = append(matches, Match{
+			LocationID: row.LocationID,
+			Region:     string(row.Region),
+			Regency:    row.Regency,
+			Cluster:    row.Cluster,
+			Quantity:   row.Quantity,
+		})
+	}
+
+	for locationID := range cleared {
+		if err := e.queries.DeleteAlertRuleFiring(ctx, sqlcdb.DeleteAlertRuleFiringParams{RuleID: rule.ID, LocationID: locationID}); err != nil {
+			return fmt.Errorf("failed to clear firing state: %w", err)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil
+	}
+
+	recipients := rule.Recipients
+	if recipients == nil {
+		recipients = []string{}
+	}
+	err = e.notify(ctx, rule.Channel, Notification{
+		RuleID:      rule.ID,
+		Rule:        rule.Name,
+		Condition:   fmt.Sprintf("quantity %s %d", rule.Operator, rule.Threshold),
+		Recipients:  recipients,
+		Matches:     matches,
+		TriggeredAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		// Firing state is only recorded after delivery, so the next run retries
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+
+	for _, match := range matches {
+		err := e.queries.CreateAlertRuleFiring(ctx, sqlcdb.CreateAlertRuleFiringParams{
+			RuleID:     rule.ID,
+			LocationID: match.LocationID,
+			Quantity:   match.Quantity,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record firing state: %w", err)
+		}
+	}
+
+	e.logger.Info("Alert rule triggered",
+		zap.Int32("rule_id", rule.ID),
+		zap.String("channel", rule.Channel),
+		zap.Int("locations", len(matches)),
+	)
+	return nil
+}
+
+// notify delivers through the channel's notifier, falling back when it is missing or fails
+func (e *Evaluator) notify(ctx context.Context, channel string, notification Notification) error {
+	err := fmt.Errorf("no notifier configured for channel %s", channel)
+	if notifier, ok := e.notifiers[channel]; ok {
+		if err = notifier.Notify(ctx, notification); err == nil {
+			return nil
+		}
+	}
+	if e.fallback == nil {
+		return err
+	}
+
+	e.logger.Warn("Alert delivery failed, using in-app fallback",
+		zap.Int32("rule_id", notification.RuleID),
+		zap.String("channel", channel),
+		zap.Error(err),
+	)
+	if fallbackErr := e.fallback.Notify(ctx, notification); fallbackErr != nil {
+		return errors.Join(err, fallbackErr)
+	}
+	return nil
+}
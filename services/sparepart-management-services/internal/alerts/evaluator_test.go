@@ -0,0 +1,138 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type recordingNotifier struct {
+	notifications []Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestEvaluatorNotifiesOnlyNewMatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAlertEvaluationRepository(ctrl)
+	notifier := &recordingNotifier{}
+	evaluator := NewEvaluator(repo, map[string]Notifier{ChannelWebhook: notifier}, nil, zap.NewNop())
+
+	rule := sqlcdb.AlertRule{
+		ID:          3,
+		Name:        "Used BMS in Maluku",
+		Region:      sqlcdb.NullRegionType{RegionType: sqlcdb.RegionTypeMALUKU, Valid: true},
+		SparepartID: pgtype.Int4{Int32: 7, Valid: true},
+		StockType:   sqlcdb.NullStockType{StockType: sqlcdb.StockTypeUSEDSTOCK, Valid: true},
+		Operator:    OperatorGT,
+		Threshold:   5,
+		Channel:     ChannelWebhook,
+		Recipients:  []string{"coordinator@example.com"},
+	}
+
+	repo.EXPECT().ListEnabledAlertRules(gomock.Any()).Return([]sqlcdb.AlertRule{rule}, nil)
+	repo.EXPECT().
+		ListAlertRuleQuantities(gomock.Any(), sqlcdb.ListAlertRuleQuantitiesParams{
+			SparepartID: rule.SparepartID,
+			StockType:   rule.StockType,
+			Region:      rule.Region,
+		}).
+		Return([]sqlcdb.ListAlertRuleQuantitiesRow{
+			{LocationID: 1, Region: sqlcdb.RegionTypeMALUKU, Regency: "Ambon", Quantity: 8},    // already firing
+			{LocationID: 2, Region: sqlcdb.RegionTypeMALUKU, Regency: "Tual", Quantity: 6},     // starts firing
+			{LocationID: 3, Region: sqlcdb.RegionTypeMALUKU, Regency: "Buru", Quantity: 5},     // not above threshold
+			{LocationID: 4, Region: sqlcdb.RegionTypeMALUKU, Regency: "Saumlaki", Quantity: 2}, // stopped firing
+		}, nil)
+	repo.EXPECT().
+		ListAlertRuleFirings(gomock.Any(), int32(3)).
+		Return([]sqlcdb.AlertRuleFiring{{RuleID: 3, LocationID: 1}, {RuleID: 3, LocationID: 4}}, nil)
+	repo.EXPECT().DeleteAlertRuleFiring(gomock.Any(), sqlcdb.DeleteAlertRuleFiringParams{RuleID: 3, LocationID: 4}).Return(nil)
+	repo.EXPECT().CreateAlertRuleFiring(gomock.Any(), sqlcdb.CreateAlertRuleFiringParams{RuleID: 3, LocationID: 2, Quantity: 6}).Return(nil)
+
+	if err := evaluator.Evaluate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	notification := notifier.notifications[0]
+	if len(notification.Matches) != 1 || notification.Matches[0].LocationID != 2 || notification.Condition != "quantity GT 5" {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+}
+
+func TestEvaluatorWithoutNotifierKeepsState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAlertEvaluationRepository(ctrl)
+	evaluator := NewEvaluator(repo, map[string]Notifier{}, nil, zap.NewNop())
+
+	rule := sqlcdb.AlertRule{ID: 1, Operator: OperatorLT, Threshold: 1, Channel: ChannelWebhook}
+	repo.EXPECT().ListEnabledAlertRules(gomock.Any()).Return([]sqlcdb.AlertRule{rule}, nil)
+	repo.EXPECT().ListAlertRuleQuantities(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListAlertRuleQuantitiesRow{{LocationID: 9, Quantity: 0}}, nil)
+	repo.EXPECT().ListAlertRuleFirings(gomock.Any(), int32(1)).Return(nil, nil)
+
+	if err := evaluator.Evaluate(context.Background()); err == nil {
+		t.Fatal("expected an error for a channel without notifier")
+	}
+}
+
+func TestHolds(t *testing.T) {
+	tests := []struct {
+		operator string
+		quantity int64
+		want     bool
+	}{
+		{OperatorGT, 6, true},
+		{OperatorGT, 5, false},
+		{OperatorGTE, 5, true},
+		{OperatorLT, 4, true},
+		{OperatorLTE, 6, false},
+		{OperatorEQ, 5, true},
+		{"UNKNOWN", 5, false},
+	}
+
+	for _, tt := range tests {
+		if got := Holds(tt.operator, tt.quantity, 5); got != tt.want {
+			t.Errorf("Holds(%s, %d, 5) = %v, want %v", tt.operator, tt.quantity, got, tt.want)
+		}
+	}
+}
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(ctx context.Context, notification Notification) error {
+	return errors.New("gateway unavailable")
+}
+
+func TestEvaluatorFallsBackWhenChannelFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAlertEvaluationRepository(ctrl)
+	fallback := &recordingNotifier{}
+	evaluator := NewEvaluator(repo, map[string]Notifier{ChannelWebhook: failingNotifier{}}, fallback, zap.NewNop())
+
+	rule := sqlcdb.AlertRule{ID: 2, Operator: OperatorEQ, Threshold: 0, Channel: ChannelWebhook, Recipients: []string{"user-7"}}
+	repo.EXPECT().ListEnabledAlertRules(gomock.Any()).Return([]sqlcdb.AlertRule{rule}, nil)
+	repo.EXPECT().ListAlertRuleQuantities(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListAlertRuleQuantitiesRow{{LocationID: 5, Quantity: 0}}, nil)
+	repo.EXPECT().ListAlertRuleFirings(gomock.Any(), int32(2)).Return(nil, nil)
+	repo.EXPECT().CreateAlertRuleFiring(gomock.Any(), sqlcdb.CreateAlertRuleFiringParams{RuleID: 2, LocationID: 5}).Return(nil)
+
+	if err := evaluator.Evaluate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fallback.notifications) != 1 || fallback.notifications[0].Recipients[0] != "user-7" {
+		t.Fatalf("unexpected fallback notifications: %+v", fallback.notifications)
+	}
+}
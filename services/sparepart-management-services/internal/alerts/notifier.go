@@ -0,0 +1,138 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// Channels an alert rule can notify through
+const (
+	ChannelLog     = "LOG"
+	ChannelWebhook = "WEBHOOK"
+)
+
+// Match is a location where a rule's condition holds
+type Match struct {
+	LocationID int32  `json:"location_id"`
+	Region     string `json:"region"`
+	Regency    string `json:"regency"`
+	Cluster    string `json:"cluster"`
+	Quantity   int64  `json:"quantity"`
+}
+
+// Notification is sent once per rule and evaluation with every location that started matching
+type Notification struct {
+	RuleID      int32    `json:"rule_id"`
+	Rule        string   `json:"rule"`
+	Condition   string   `json:"condition"`
+	Recipients  []string `json:"recipients"`
+	Matches     []Match  `json:"matches"`
+	TriggeredAt string   `json:"triggered_at"`
+}
+
+// Notifier delivers notifications for one channel
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes notifications to the service log
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	n.logger.Warn("Alert rule triggered",
+		zap.Int32("rule_id", notification.RuleID),
+		zap.String("rule", notification.Rule),
+		zap.String("condition", notification.Condition),
+		zap.Strings("recipients", notification.Recipients),
+		zap.Any("matches", notification.Matches),
+	)
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to a gateway, which delivers them to the recipients
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// InAppNotifier stores notifications in the notification center of each recipient, read
+// as user IDs. Recipients that turned the IN_APP channel off are skipped.
+type InAppNotifier struct {
+	queries repository.NotificationRepository
+}
+
+func NewInAppNotifier(queries repository.NotificationRepository) *InAppNotifier {
+	return &InAppNotifier{queries: queries}
+}
+
+func (n *InAppNotifier) Notify(ctx context.Context, notification Notification) error {
+	if len(notification.Recipients) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	locations := make([]string, 0, len(notification.Matches))
+	for _, match := range notification.Matches {
+		locations = append(locations, fmt.Sprintf("%s %s (%d)", match.Regency, match.Cluster, match.Quantity))
+	}
+
+	_, err = n.queries.CreateNotifications(ctx, sqlcdb.CreateNotificationsParams{
+		Category: "ALERT_RULE",
+		Title:    "Alert: " + notification.Rule,
+		Body:     fmt.Sprintf("%s at %s", notification.Condition, strings.Join(locations, ", ")),
+		Data:     data,
+		UserIds:  notification.Recipients,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store in-app notifications: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Package app wires the application dependencies built once in main and passed to the routes.
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	"sparepart-management-services/internal/database"
+	"sparepart-management-services/internal/ratelimit"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/storage"
+	"sparepart-management-services/internal/tracing"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Container holds the shared dependencies of the service
+type Container struct {
+	Config  *config.Config
+	Logger  *zap.Logger
+	DB      *pgxpool.Pool
+	Store   *repository.Store
+	Storage storage.Storage
+	// Uploads stores and removes the photos in Storage, recorded in Store; set by Connect
+	Uploads *uploads.Service
+	// Reports keeps stored exports and signs their download links; it notifies the links once
+	// Connect has opened the database
+	Reports *reports.Store
+	// Redis holds the rate limits shared by all replicas; nil keeps them in memory
+	Redis *redis.Client
+
+	shutdownTracing func(context.Context) error
+}
+
+// New builds the container with its logger, upload storage and report store; the database is
+// opened separately by Connect
+func New(cfg *config.Config) (*Container, error) {
+	logger, err := utils.NewLogger(cfg.Logging.Level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	// Photo uploads, lookups and deletes all go through the configured backend
+	uploadStorage, err := storage.New(cfg.Upload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize upload storage: %w", err)
+	}
+
+	// Stored reports are shared by every replica through the same backend, apart from the photos
+	reportStorage, err := storage.NewReports(cfg.Upload, cfg.Report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize report storage: %w", err)
+	}
+	reportStore, err := reports.New(cfg.Report, reportStorage, cfg.App.APIPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize report store: %w", err)
+	}
+
+	// Request and query spans go to the OTLP endpoint, if one is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+
+	container := &Container{
+		Config:          cfg,
+		Logger:          logger,
+		Storage:         uploadStorage,
+		Reports:         reportStore,
+		shutdownTracing: shutdownTracing,
+	}
+	if cfg.Limit.RedisURL != "" {
+		container.Redis, err = ratelimit.NewRedisClient(cfg.Limit.RedisURL, 2*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize rate limit store: %w", err)
+		}
+	}
+	return container, nil
+}
+
+// Connect opens the connection pool and the sqlc store on top of it, which also records the
+// stored photos
+func (c *Container) Connect() error {
+	pool, err := database.Connect(c.Config.Database)
+	if err != nil {
+		return err
+	}
+	c.DB = pool
+	c.Store = repository.NewStore(pool)
+	// Identical photos share one stored file, deleted with its last reference
+	c.Uploads = uploads.New(c.Storage, c.Store, c.Config.Upload)
+	// Links of stored reports go to the notification center of whoever requested them
+	c.Reports.SetNotifications(c.Store)
+	return nil
+}
+
+// Close releases the connection pool and the Redis connection and flushes the pending spans and the logger
+func (c *Container) Close() {
+	if c.DB != nil {
+		c.DB.Close()
+	}
+	if c.Redis != nil {
+		_ = c.Redis.Close()
+	}
+	if c.shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := c.shutdownTracing(ctx); err != nil {
+			c.Logger.Warn("Failed to flush traces", zap.Error(err))
+		}
+	}
+	_ = c.Logger.Sync()
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +15,20 @@ type Config struct {
 	Database DatabaseConfig
 	Logging  LoggingConfig
 	Upload   UploadConfig
+	Report   ReportConfig
+	Summary  SummaryConfig
+	Cache    CacheConfig
+	Timeout  TimeoutConfig
+	Anomaly  AnomalyConfig
+	Alert    AlertConfig
+	Share    ShareConfig
+	Auth     AuthConfig
+	Tracing  TracingConfig
+	Webhook  WebhookConfig
+	Export   ExportJobConfig
+	Email    EmailConfig
+	Message  MessageConfig
+	Limit    RateLimitConfig
 }
 
 type AppConfig struct {
@@ -22,10 +38,18 @@ type AppConfig struct {
 	APIPrefix string
 	IsDev     bool
 	IsProd    bool
+	// TrustedProxies are the addresses or CIDRs of the reverse proxies whose X-Forwarded-For
+	// gives the client IP (used by the rate limits); without any the peer address is used
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
 	URL string
+	// StatementCacheMode is "prepare" (cache prepared statements) or "describe" (cache only statement descriptions)
+	StatementCacheMode string
+	StatementCacheSize int
+	// PreferSimpleProtocol disables prepared statements entirely, e.g. behind a transaction-pooling PgBouncer
+	PreferSimpleProtocol bool
 }
 
 type LoggingConfig struct {
@@ -35,6 +59,159 @@ type LoggingConfig struct {
 type UploadConfig struct {
 	Dir         string
 	MaxFileSize int64
+	// MinFreeBytes is the free space below which readiness fails
+	MinFreeBytes uint64
+	// Reencode stores every uploaded photo decoded and encoded again as JPEG instead of only
+	// stripped of its metadata, which also drops anything hidden in the file
+	Reencode bool
+	// MaxStockPhotos and MaxToolsPhotos cap the photos of a stock or tools alker item; 0 is
+	// no limit
+	MaxStockPhotos int
+	MaxToolsPhotos int
+	// QuotaBytes caps the bytes of the photos recorded in upload_file; 0 is no quota
+	QuotaBytes int64
+	// Backend is where uploaded photos are kept: local (Dir) or s3; uploads are staged in
+	// Dir either way
+	Backend string
+	S3      S3Config
+}
+
+// S3Config is the bucket of the s3 upload backend; Endpoint is host[:port] without scheme
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+// ReportConfig is where stored exports are kept, on the upload backend: Dir on the local
+// backend, or S3Prefix in the upload bucket on s3
+type ReportConfig struct {
+	Dir        string
+	S3Prefix   string
+	LinkSecret string
+	LinkTTL    time.Duration
+}
+
+type SummaryConfig struct {
+	RefreshInterval time.Duration
+	// SnapshotInterval is how often today's stock snapshot is recorded; 0 disables snapshots
+	SnapshotInterval time.Duration
+}
+
+type CacheConfig struct {
+	TTL          time.Duration
+	DashboardTTL time.Duration
+}
+
+// TimeoutConfig holds the per-request time budgets; exports get a longer one
+type TimeoutConfig struct {
+	Request time.Duration
+	Export  time.Duration
+}
+
+// AnomalyConfig controls the background anomaly analyzer; a zero Interval disables it
+type AnomalyConfig struct {
+	Interval time.Duration
+	// Lookback is how far back each run re-checks the stock ledger and change history
+	Lookback            time.Duration
+	QuantityDropPercent int
+	DeleteThreshold     int
+}
+
+// AlertConfig controls the alert rule evaluator; a zero Interval disables it
+type AlertConfig struct {
+	Interval time.Duration
+	// WebhookURL receives the notifications of WEBHOOK rules; without it those rules cannot notify
+	WebhookURL     string
+	WebhookTimeout time.Duration
+}
+
+// ShareConfig controls the public share link endpoints; a zero RateLimit disables limiting
+type ShareConfig struct {
+	// RateLimit is the number of requests per minute one client IP may make
+	RateLimit int
+}
+
+// RateLimitConfig controls the rate limits of the export endpoints and of login; a zero
+// limit disables it
+type RateLimitConfig struct {
+	// ExportLimit is the number of export requests per minute one API key or client IP may make
+	ExportLimit int
+	// LoginLimit is the number of login attempts per minute one client IP may make
+	LoginLimit int
+	// RedisURL (redis://[user:password@]host[:port][/db], rediss:// for TLS) keeps the rate
+	// limits in Redis so all replicas share them; without it every process counts on its own
+	RedisURL string
+}
+
+// AuthConfig controls the JWTs issued by /auth/login and /auth/refresh
+type AuthConfig struct {
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// TracingConfig controls the OpenTelemetry trace export; without an Endpoint nothing is exported
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector URL, e.g. http://otel-collector:4318
+	Endpoint    string
+	ServiceName string
+}
+
+// WebhookConfig controls the webhook dispatcher; a zero Interval disables it
+type WebhookConfig struct {
+	Interval time.Duration
+	Timeout  time.Duration
+	// MaxAttempts is how often a delivery is tried before it is marked FAILED
+	MaxAttempts int
+}
+
+// ExportJobConfig controls the background export worker; a zero Interval disables it
+type ExportJobConfig struct {
+	// Interval is how often the worker looks for queued jobs
+	Interval time.Duration
+	// Timeout bounds the rendering of one job's file
+	Timeout time.Duration
+}
+
+// EmailConfig controls the email digest of low stock and pending sparepart requests; a zero
+// Interval or an empty SMTPHost disables it
+type EmailConfig struct {
+	Interval     time.Duration
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	// Recipients receive the digest of every location; contact persons with an email address
+	// receive the digest of their own location
+	Recipients []string
+	// LowStockThreshold is the quantity at or below which a stock item is reported
+	LowStockThreshold int
+}
+
+// MessageConfig controls the SMS/WhatsApp messages to contact persons; a zero Interval or an
+// unknown Provider disables sending
+type MessageConfig struct {
+	// Provider is twilio or gateway
+	Provider    string
+	Interval    time.Duration
+	Timeout     time.Duration
+	MaxAttempts int
+	// MaxAge is how long a message may wait to be sent before it is given up on
+	MaxAge time.Duration
+	// CountryCode is prepended to phone numbers written nationally, e.g. 62 for 0812-...
+	CountryCode      string
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	// TwilioFrom is the sending number; prefix it with whatsapp: to send over WhatsApp
+	TwilioFrom   string
+	GatewayURL   string
+	GatewayToken string
 }
 
 var App *Config
@@ -51,22 +228,126 @@ func Load() error {
 			APIPrefix: getEnv("API_PREFIX", "/api/v1"),
 			IsDev:     getEnv("NODE_ENV", "development") == "development",
 			IsProd:    getEnv("NODE_ENV", "development") == "production",
+
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
-			URL: getEnv("SPAREPART_DATABASE_URL", ""),
+			URL:                  getEnv("SPAREPART_DATABASE_URL", ""),
+			StatementCacheMode:   getEnv("DB_STATEMENT_CACHE_MODE", "prepare"),
+			StatementCacheSize:   getEnvAsInt("DB_STATEMENT_CACHE_SIZE", 512),
+			PreferSimpleProtocol: getEnvAsBool("DB_PREFER_SIMPLE_PROTOCOL", false),
 		},
 		Logging: LoggingConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
 		Upload: UploadConfig{
-			Dir:         getEnv("UPLOAD_DIR", "./uploads"),
-			MaxFileSize: getEnvAsInt64("MAX_FILE_SIZE", 5*1024*1024), // 5MB default
+			Dir:            getEnv("UPLOAD_DIR", "./uploads"),
+			MaxFileSize:    getEnvAsInt64("MAX_FILE_SIZE", 5*1024*1024), // 5MB default
+			MinFreeBytes:   uint64(max(getEnvAsInt64("UPLOAD_MIN_FREE_MB", 500), 0)) * 1024 * 1024,
+			Reencode:       getEnvAsBool("UPLOAD_REENCODE", false),
+			MaxStockPhotos: max(getEnvAsInt("UPLOAD_MAX_STOCK_PHOTOS", 0), 0),
+			MaxToolsPhotos: max(getEnvAsInt("UPLOAD_MAX_TOOLS_PHOTOS", 0), 0),
+			QuotaBytes:     max(getEnvAsInt64("UPLOAD_QUOTA_MB", 0), 0) * 1024 * 1024,
+			Backend:        strings.ToLower(getEnv("STORAGE_BACKEND", "local")),
+			S3: S3Config{
+				Endpoint:  getEnv("S3_ENDPOINT", ""),
+				Region:    getEnv("S3_REGION", ""),
+				Bucket:    getEnv("S3_BUCKET", ""),
+				Prefix:    getEnv("S3_PREFIX", ""),
+				AccessKey: getEnv("S3_ACCESS_KEY", ""),
+				SecretKey: getEnv("S3_SECRET_KEY", ""),
+				UseSSL:    getEnvAsBool("S3_USE_SSL", true),
+			},
+		},
+		Report: ReportConfig{
+			Dir:        getEnv("REPORT_DIR", "./reports"),
+			S3Prefix:   getEnv("REPORT_S3_PREFIX", "reports"),
+			LinkSecret: getEnv("REPORT_LINK_SECRET", ""),
+			LinkTTL:    time.Duration(getEnvAsInt("REPORT_LINK_TTL_MINUTES", 60)) * time.Minute,
+		},
+		Summary: SummaryConfig{
+			RefreshInterval:  time.Duration(getEnvAsInt("STOCK_SUMMARY_REFRESH_MINUTES", 5)) * time.Minute,
+			SnapshotInterval: time.Duration(getEnvAsInt("STOCK_SNAPSHOT_INTERVAL_MINUTES", 60)) * time.Minute,
+		},
+		Cache: CacheConfig{
+			TTL:          time.Duration(getEnvAsInt("LOOKUP_CACHE_TTL_SECONDS", 60)) * time.Second,
+			DashboardTTL: time.Duration(getEnvAsInt("DASHBOARD_CACHE_TTL_SECONDS", 60)) * time.Second,
+		},
+		Timeout: TimeoutConfig{
+			Request: time.Duration(getEnvAsInt("REQUEST_TIMEOUT_SECONDS", 10)) * time.Second,
+			Export:  time.Duration(getEnvAsInt("EXPORT_TIMEOUT_SECONDS", 60)) * time.Second,
+		},
+		Anomaly: AnomalyConfig{
+			Interval:            time.Duration(getEnvAsInt("ANOMALY_CHECK_MINUTES", 15)) * time.Minute,
+			Lookback:            time.Duration(getEnvAsInt("ANOMALY_LOOKBACK_HOURS", 48)) * time.Hour,
+			QuantityDropPercent: getEnvAsInt("ANOMALY_QUANTITY_DROP_PERCENT", 50),
+			DeleteThreshold:     getEnvAsInt("ANOMALY_DELETE_THRESHOLD", 5),
+		},
+		Alert: AlertConfig{
+			Interval:       time.Duration(getEnvAsInt("ALERT_RULE_CHECK_MINUTES", 5)) * time.Minute,
+			WebhookURL:     getEnv("ALERT_WEBHOOK_URL", ""),
+			WebhookTimeout: time.Duration(getEnvAsInt("ALERT_WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+		},
+		Share: ShareConfig{
+			RateLimit: getEnvAsInt("SHARE_RATE_LIMIT_PER_MINUTE", 30),
+		},
+		Limit: RateLimitConfig{
+			ExportLimit: getEnvAsInt("EXPORT_RATE_LIMIT_PER_MINUTE", 10),
+			LoginLimit:  getEnvAsInt("LOGIN_RATE_LIMIT_PER_MINUTE", 10),
+			RedisURL:    getEnv("RATE_LIMIT_REDIS_URL", ""),
+		},
+		Auth: AuthConfig{
+			JWTSecret:       getEnv("JWT_SECRET", ""),
+			AccessTokenTTL:  time.Duration(getEnvAsInt("JWT_ACCESS_TTL_MINUTES", 15)) * time.Minute,
+			RefreshTokenTTL: time.Duration(getEnvAsInt("JWT_REFRESH_TTL_HOURS", 168)) * time.Hour,
+		},
+		Tracing: TracingConfig{
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "sparepart-management-services"),
+		},
+		Webhook: WebhookConfig{
+			Interval:    time.Duration(getEnvAsInt("WEBHOOK_DISPATCH_SECONDS", 10)) * time.Second,
+			Timeout:     time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+			MaxAttempts: getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		},
+		Export: ExportJobConfig{
+			Interval: time.Duration(getEnvAsInt("EXPORT_JOB_POLL_SECONDS", 5)) * time.Second,
+			Timeout:  time.Duration(getEnvAsInt("EXPORT_JOB_TIMEOUT_MINUTES", 10)) * time.Minute,
+		},
+		Email: EmailConfig{
+			Interval:          time.Duration(getEnvAsInt("EMAIL_DIGEST_HOURS", 24)) * time.Hour,
+			SMTPHost:          getEnv("SMTP_HOST", ""),
+			SMTPPort:          getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername:      getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:      getEnv("SMTP_PASSWORD", ""),
+			From:              getEnv("SMTP_FROM", ""),
+			Recipients:        getEnvAsList("EMAIL_DIGEST_RECIPIENTS"),
+			LowStockThreshold: getEnvAsInt("EMAIL_LOW_STOCK_THRESHOLD", 2),
+		},
+		Message: MessageConfig{
+			Provider:         strings.ToLower(getEnv("MESSAGE_PROVIDER", "")),
+			Interval:         time.Duration(getEnvAsInt("MESSAGE_DISPATCH_SECONDS", 15)) * time.Second,
+			Timeout:          time.Duration(getEnvAsInt("MESSAGE_TIMEOUT_SECONDS", 10)) * time.Second,
+			MaxAttempts:      getEnvAsInt("MESSAGE_MAX_ATTEMPTS", 5),
+			MaxAge:           time.Duration(getEnvAsInt("MESSAGE_MAX_AGE_HOURS", 24)) * time.Hour,
+			CountryCode:      getEnv("MESSAGE_COUNTRY_CODE", "62"),
+			TwilioAccountSID: getEnv("TWILIO_ACCOUNT_SID", ""),
+			TwilioAuthToken:  getEnv("TWILIO_AUTH_TOKEN", ""),
+			TwilioFrom:       getEnv("TWILIO_FROM", ""),
+			GatewayURL:       getEnv("MESSAGE_GATEWAY_URL", ""),
+			GatewayToken:     getEnv("MESSAGE_GATEWAY_TOKEN", ""),
 		},
 	}
 
 	if App.Database.URL == "" {
 		return fmt.Errorf("SPAREPART_DATABASE_URL is required")
 	}
+	if App.Auth.JWTSecret == "" {
+		return fmt.Errorf("JWT_SECRET is required")
+	}
+	if App.Report.LinkSecret == "" {
+		return fmt.Errorf("REPORT_LINK_SECRET is required")
+	}
 
 	return nil
 }
@@ -101,3 +382,26 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	}
 	return value
 }
+
+// getEnvAsList reads a comma-separated list, skipping blank entries
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
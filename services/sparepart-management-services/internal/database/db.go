@@ -3,21 +3,20 @@ package database
 import (
 	"context"
 	"fmt"
-	"sparepart-management-services/internal/config"
 	"time"
 
+	"sparepart-management-services/internal/config"
+
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
-var DB *pgxpool.Pool
-
 // Connect establishes a connection pool to PostgreSQL
-func Connect() error {
-	cfg, err := pgxpool.ParseConfig(config.App.Database.URL)
+func Connect(dbCfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(dbCfg.URL)
 	if err != nil {
-		return fmt.Errorf("failed to parse database URL: %w", err)
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
 	}
 
 	// Configure connection pool for performance and concurrency
@@ -27,51 +26,77 @@ func Connect() error {
 	cfg.MaxConnIdleTime = 5 * time.Minute // Maximum idle time
 	cfg.HealthCheckPeriod = 1 * time.Minute
 
+	// Sessions run in UTC so CURRENT_TIMESTAMP and date casts don't depend on the server timezone
+	cfg.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	cfg.AfterConnect = registerUTCTimestamptz
+
+	// Every query gets a span under the request's span (see QueryTracer)
+	cfg.ConnConfig.Tracer = QueryTracer{}
+
+	if err := configureQueryExecMode(cfg.ConnConfig, dbCfg); err != nil {
+		return nil, err
+	}
+
 	// Create connection pool
-	DB, err = pgxpool.NewWithConfig(context.Background(), cfg)
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create connection pool: %w", err)
+		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := DB.Ping(ctx); err != nil {
-		return fmt.Errorf("failed to ping database: %w", err)
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return nil
+	return pool, nil
 }
 
-// Close closes the database connection pool
-func Close() {
-	if DB != nil {
-		DB.Close()
+// configureQueryExecMode applies the statement cache settings. The statement cache is sized
+// in every mode except simple protocol so PreparedQueryDB can still prepare the hot queries.
+func configureQueryExecMode(connCfg *pgx.ConnConfig, dbCfg config.DatabaseConfig) error {
+	if dbCfg.StatementCacheSize > 0 {
+		connCfg.StatementCacheCapacity = dbCfg.StatementCacheSize
+		connCfg.DescriptionCacheCapacity = dbCfg.StatementCacheSize
 	}
-}
 
-// GetDB returns the database connection pool
-func GetDB() *pgxpool.Pool {
-	return DB
+	if dbCfg.PreferSimpleProtocol {
+		connCfg.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+		return nil
+	}
+
+	switch dbCfg.StatementCacheMode {
+	case "", "prepare":
+		connCfg.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	case "describe":
+		connCfg.DefaultQueryExecMode = pgx.QueryExecModeCacheDescribe
+	default:
+		return fmt.Errorf("invalid statement cache mode %q (expected prepare or describe)", dbCfg.StatementCacheMode)
+	}
+	return nil
 }
 
 // HealthCheck checks database health
-func HealthCheck(ctx context.Context) error {
-	if DB == nil {
+func HealthCheck(ctx context.Context, pool *pgxpool.Pool) error {
+	if pool == nil {
 		return fmt.Errorf("database connection pool is nil")
 	}
-	return DB.Ping(ctx)
+	return pool.Ping(ctx)
 }
 
-// WithTransaction executes a function within a database transaction
-func WithTransaction(ctx context.Context, fn func(context.Context, pgx.Tx) error) error {
-	if DB == nil {
+// WithTransaction executes a function within a database transaction.
+// The transaction is rolled back if fn returns an error or panics, otherwise committed;
+// a failed commit is returned to the caller.
+func WithTransaction(ctx context.Context, pool *pgxpool.Pool, fn func(context.Context, pgx.Tx) error) (err error) {
+	if pool == nil {
 		return fmt.Errorf("database connection pool is nil")
 	}
 
 	// Acquire connection from pool
-	conn, err := DB.Acquire(ctx)
+	conn, err := pool.Acquire(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to acquire connection: %w", err)
 	}
@@ -89,21 +114,20 @@ func WithTransaction(ctx context.Context, fn func(context.Context, pgx.Tx) error
 			panic(p)
 		} else if err != nil {
 			_ = tx.Rollback(ctx)
-		} else {
-			err = tx.Commit(ctx)
+		} else if commitErr := tx.Commit(ctx); commitErr != nil {
+			err = fmt.Errorf("failed to commit transaction: %w", commitErr)
 		}
 	}()
 
-	err = fn(ctx, tx)
-	return err
+	return fn(ctx, tx)
 }
 
 // LogPoolStats logs connection pool statistics
-func LogPoolStats(logger *zap.Logger) {
-	if DB == nil {
+func LogPoolStats(pool *pgxpool.Pool, logger *zap.Logger) {
+	if pool == nil {
 		return
 	}
-	stats := DB.Stat()
+	stats := pool.Stat()
 	logger.Info("Database pool stats",
 		zap.Int32("max_conns", stats.MaxConns()),
 		zap.Int32("acquired_conns", stats.AcquiredConns()),
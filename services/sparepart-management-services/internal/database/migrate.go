@@ -3,7 +3,6 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"sparepart-management-services/internal/config"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -12,10 +11,7 @@ import (
 )
 
 // RunMigrations runs database migrations using golang-migrate
-func RunMigrations() error {
-	// Parse connection string to get database URL without database name
-	dbURL := config.App.Database.URL
-
+func RunMigrations(dbURL string) error {
 	// Open database connection
 	db, err := sql.Open("postgres", dbURL)
 	if err != nil {
@@ -48,9 +44,7 @@ func RunMigrations() error {
 }
 
 // DownMigrations rolls back the last migration
-func DownMigrations() error {
-	dbURL := config.App.Database.URL
-
+func DownMigrations(dbURL string) error {
 	db, err := sql.Open("postgres", dbURL)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
@@ -79,9 +73,7 @@ func DownMigrations() error {
 }
 
 // GetMigrationVersion returns the current migration version
-func GetMigrationVersion() (uint, bool, error) {
-	dbURL := config.App.Database.URL
-
+func GetMigrationVersion(dbURL string) (uint, bool, error) {
 	db, err := sql.Open("postgres", dbURL)
 	if err != nil {
 		return 0, false, fmt.Errorf("failed to open database: %w", err)
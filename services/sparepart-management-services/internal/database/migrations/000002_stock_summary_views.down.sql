@@ -0,0 +1,4 @@
+-- Drop materialized views
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_region;
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_sparepart;
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_location;
@@ -0,0 +1,6 @@
+-- Drop non-negative quantity constraints
+ALTER TABLE tools_alker_item
+    DROP CONSTRAINT IF EXISTS tools_alker_item_quantity_non_negative;
+
+ALTER TABLE sparepart_stock_item
+    DROP CONSTRAINT IF EXISTS sparepart_stock_item_quantity_non_negative;
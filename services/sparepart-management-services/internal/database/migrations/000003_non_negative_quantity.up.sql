@@ -0,0 +1,6 @@
+-- Add non-negative quantity constraints
+ALTER TABLE sparepart_stock_item
+    ADD CONSTRAINT sparepart_stock_item_quantity_non_negative CHECK (quantity >= 0);
+
+ALTER TABLE tools_alker_item
+    ADD CONSTRAINT tools_alker_item_quantity_non_negative CHECK (quantity >= 0);
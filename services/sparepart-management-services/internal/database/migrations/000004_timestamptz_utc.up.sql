@@ -0,0 +1,77 @@
+-- Store timestamps as timestamptz so they no longer depend on the session timezone.
+-- Existing values were written in the server timezone and are interpreted as such.
+
+ALTER TABLE location
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE list_sparepart
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE contact_person
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE sparepart_stock_item
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE tools_alker_item
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('TimeZone');
+
+-- Recreate the summary views with a timestamptz refreshed_at
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_region;
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_sparepart;
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_location;
+
+-- Stock totals per location
+CREATE MATERIALIZED VIEW stock_summary_by_location AS
+SELECT
+    l.id AS location_id,
+    l.region,
+    l.regency,
+    l.cluster,
+    COUNT(ssi.id)::bigint AS item_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM location l
+JOIN sparepart_stock_item ssi ON ssi.location_id = l.id
+GROUP BY l.id, l.region, l.regency, l.cluster;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_location_id ON stock_summary_by_location(location_id);
+CREATE INDEX idx_stock_summary_by_location_region ON stock_summary_by_location(region);
+
+-- Stock totals per sparepart across all locations
+CREATE MATERIALIZED VIEW stock_summary_by_sparepart AS
+SELECT
+    ls.id AS sparepart_id,
+    ls.name AS sparepart_name,
+    COUNT(DISTINCT ssi.location_id)::bigint AS location_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM list_sparepart ls
+JOIN sparepart_stock_item ssi ON ssi.sparepart_id = ls.id
+GROUP BY ls.id, ls.name;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_sparepart_id ON stock_summary_by_sparepart(sparepart_id);
+
+-- Stock totals per region
+CREATE MATERIALIZED VIEW stock_summary_by_region AS
+SELECT
+    l.region,
+    COUNT(DISTINCT ssi.location_id)::bigint AS location_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM location l
+JOIN sparepart_stock_item ssi ON ssi.location_id = l.id
+GROUP BY l.region;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_region ON stock_summary_by_region(region);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS record_sparepart_stock_item_ledger ON sparepart_stock_item;
+DROP FUNCTION IF EXISTS record_stock_ledger();
+DROP TABLE IF EXISTS stock_ledger;
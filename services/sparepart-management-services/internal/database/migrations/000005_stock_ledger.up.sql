@@ -0,0 +1,43 @@
+-- Stock ledger: one row per quantity change of a sparepart stock item.
+-- Rows are written by a trigger so every write path is recorded, and they
+-- are kept after the stock item is deleted.
+CREATE TABLE stock_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    stock_item_id INTEGER NOT NULL,
+    location_id INTEGER NOT NULL,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity_change INTEGER NOT NULL,
+    quantity_after INTEGER NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_ledger_location_recorded_at ON stock_ledger(location_id, recorded_at);
+CREATE INDEX idx_stock_ledger_sparepart_recorded_at ON stock_ledger(sparepart_id, recorded_at);
+
+CREATE OR REPLACE FUNCTION record_stock_ledger()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+        VALUES (NEW.id, NEW.location_id, NEW.sparepart_id, NEW.stock_type, NEW.quantity, NEW.quantity);
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF NEW.quantity IS DISTINCT FROM OLD.quantity THEN
+            INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+            VALUES (NEW.id, NEW.location_id, NEW.sparepart_id, NEW.stock_type, NEW.quantity - OLD.quantity, NEW.quantity);
+        END IF;
+    ELSIF TG_OP = 'DELETE' THEN
+        INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+        VALUES (OLD.id, OLD.location_id, OLD.sparepart_id, OLD.stock_type, -OLD.quantity, 0);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_sparepart_stock_item_ledger AFTER INSERT OR UPDATE OF quantity OR DELETE ON sparepart_stock_item
+    FOR EACH ROW EXECUTE FUNCTION record_stock_ledger();
+
+-- Baseline: existing items enter the ledger with their current quantity at creation time
+INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after, recorded_at)
+SELECT id, location_id, sparepart_id, stock_type, quantity, quantity, created_at
+FROM sparepart_stock_item;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS record_tools_alker_item_change_history ON tools_alker_item;
+DROP TRIGGER IF EXISTS record_sparepart_stock_item_change_history ON sparepart_stock_item;
+DROP TRIGGER IF EXISTS record_location_change_history ON location;
+DROP FUNCTION IF EXISTS record_change_history();
+DROP TABLE IF EXISTS change_history;
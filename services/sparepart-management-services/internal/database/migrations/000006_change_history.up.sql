@@ -0,0 +1,53 @@
+-- Change history: one row per insert, update or delete of a stock item, tools alker
+-- item or location, with the old/new value of every changed column. The actor is read
+-- from the app.actor setting (SET LOCAL app.actor = '...') and is NULL when unset.
+CREATE TABLE change_history (
+    id BIGSERIAL PRIMARY KEY,
+    table_name VARCHAR(64) NOT NULL,
+    record_id INTEGER NOT NULL,
+    operation VARCHAR(10) NOT NULL,
+    changes JSONB NOT NULL,
+    actor VARCHAR(255),
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_change_history_record ON change_history(table_name, record_id, changed_at);
+
+CREATE OR REPLACE FUNCTION record_change_history()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB := CASE WHEN TG_OP <> 'INSERT' THEN to_jsonb(OLD) ELSE '{}'::jsonb END;
+    new_row JSONB := CASE WHEN TG_OP <> 'DELETE' THEN to_jsonb(NEW) ELSE '{}'::jsonb END;
+    diff JSONB;
+BEGIN
+    SELECT COALESCE(jsonb_object_agg(k.key, jsonb_build_object('old', old_row -> k.key, 'new', new_row -> k.key)), '{}'::jsonb)
+    INTO diff
+    FROM (SELECT jsonb_object_keys(old_row || new_row) AS key) k
+    WHERE k.key NOT IN ('created_at', 'updated_at')
+        AND (old_row -> k.key) IS DISTINCT FROM (new_row -> k.key);
+
+    -- Updates that only touched updated_at are not history
+    IF TG_OP = 'UPDATE' AND diff = '{}'::jsonb THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO change_history (table_name, record_id, operation, changes, actor)
+    VALUES (
+        TG_TABLE_NAME,
+        COALESCE(new_row ->> 'id', old_row ->> 'id')::int,
+        TG_OP,
+        diff,
+        NULLIF(current_setting('app.actor', true), '')
+    );
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_location_change_history AFTER INSERT OR UPDATE OR DELETE ON location
+    FOR EACH ROW EXECUTE FUNCTION record_change_history();
+
+CREATE TRIGGER record_sparepart_stock_item_change_history AFTER INSERT OR UPDATE OR DELETE ON sparepart_stock_item
+    FOR EACH ROW EXECUTE FUNCTION record_change_history();
+
+CREATE TRIGGER record_tools_alker_item_change_history AFTER INSERT OR UPDATE OR DELETE ON tools_alker_item
+    FOR EACH ROW EXECUTE FUNCTION record_change_history();
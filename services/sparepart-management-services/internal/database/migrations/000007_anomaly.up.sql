@@ -0,0 +1,18 @@
+-- Anomalies flagged by the background analyzer. The fingerprint identifies what was
+-- detected (kind + record or actor + day) so repeated analyzer runs do not duplicate it.
+CREATE TABLE anomaly (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    fingerprint VARCHAR(255) NOT NULL UNIQUE,
+    table_name VARCHAR(64),
+    record_id INTEGER,
+    actor VARCHAR(255),
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'ACKNOWLEDGED', 'RESOLVED')),
+    note TEXT,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    acknowledged_at TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_anomaly_status_detected_at ON anomaly(status, detected_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS alert_rule_firing;
+DROP TABLE IF EXISTS alert_rule;
@@ -0,0 +1,30 @@
+-- Alert rules: "notify <recipients> via <channel> when the quantity of <sparepart>/<stock type>
+-- at a location in <scope> is <operator> <threshold>". Scope columns left NULL match everything.
+CREATE TABLE alert_rule (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    region region_type,
+    location_id INTEGER REFERENCES location(id) ON DELETE CASCADE,
+    sparepart_id INTEGER REFERENCES list_sparepart(id) ON DELETE CASCADE,
+    stock_type stock_type,
+    operator VARCHAR(3) NOT NULL CHECK (operator IN ('GT', 'GTE', 'LT', 'LTE', 'EQ')),
+    threshold INTEGER NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('LOG', 'WEBHOOK')),
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_alert_rule_updated_at BEFORE UPDATE ON alert_rule
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Locations a rule currently fires for; a rule notifies once when a location starts
+-- matching and again only after it stopped matching in between
+CREATE TABLE alert_rule_firing (
+    rule_id INTEGER NOT NULL REFERENCES alert_rule(id) ON DELETE CASCADE,
+    location_id INTEGER NOT NULL REFERENCES location(id) ON DELETE CASCADE,
+    quantity BIGINT NOT NULL,
+    fired_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rule_id, location_id)
+);
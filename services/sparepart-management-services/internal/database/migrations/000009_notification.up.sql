@@ -0,0 +1,23 @@
+-- In-app notifications per user; also the fallback when external delivery fails
+CREATE TABLE notification (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}'::jsonb,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notification_user_created_at ON notification(user_id, created_at);
+CREATE INDEX idx_notification_user_unread ON notification(user_id) WHERE read_at IS NULL;
+
+-- Delivery preferences per user and channel; a channel without a row is enabled
+CREATE TABLE notification_preference (
+    user_id VARCHAR(255) NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('IN_APP', 'WHATSAPP', 'TELEGRAM')),
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, channel)
+);
@@ -0,0 +1,18 @@
+-- Named filter sets per user for the stock (SPAREPART) and tools alker (TOOLS_ALKER) listings.
+-- filters holds the listing query params; sort and group_by are the UI's listing modes.
+CREATE TABLE saved_filter (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    item_type item_type NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    sort VARCHAR(50),
+    group_by VARCHAR(50),
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, item_type, name)
+);
+
+CREATE TRIGGER update_saved_filter_updated_at BEFORE UPDATE ON saved_filter
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,16 @@
+-- One row per finished export (PDF, Excel, labels), written by middleware.RecordExport.
+-- user_id is NULL when the request carried no X-User-ID header.
+CREATE TABLE export_log (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255),
+    entity VARCHAR(50) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    stored BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_export_log_created_at ON export_log (created_at DESC);
+CREATE INDEX idx_export_log_user_id ON export_log (user_id, created_at DESC);
@@ -0,0 +1,15 @@
+-- Expiring read-only links to one location's stock, for counterparts without an account.
+-- Only the SHA-256 of the token is stored; the token itself is shown once on creation.
+CREATE TABLE share_link (
+    id SERIAL PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    location_id INTEGER NOT NULL REFERENCES location(id) ON DELETE CASCADE,
+    created_by VARCHAR(255),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    last_accessed_at TIMESTAMPTZ,
+    access_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_share_link_location_id ON share_link(location_id);
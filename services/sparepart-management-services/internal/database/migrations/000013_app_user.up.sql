@@ -0,0 +1,15 @@
+-- Accounts that log in to the API (see /auth/login). Passwords are stored as bcrypt hashes;
+-- inactive users can neither log in nor refresh their tokens.
+CREATE TABLE app_user (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'USER' CHECK (role IN ('ADMIN', 'USER')),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    last_login_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_app_user_updated_at BEFORE UPDATE ON app_user
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
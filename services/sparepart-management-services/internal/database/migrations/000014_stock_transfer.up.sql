@@ -0,0 +1,19 @@
+-- Stock moved between locations by POST /sparepart/stock/transfer. Rows are an audit record
+-- and reference locations and stock items by ID only, so they outlive deletes.
+CREATE TABLE stock_transfer (
+    id SERIAL PRIMARY KEY,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    source_location_id INTEGER NOT NULL,
+    destination_location_id INTEGER NOT NULL,
+    source_stock_id INTEGER NOT NULL,
+    destination_stock_id INTEGER NOT NULL,
+    notes TEXT,
+    transferred_by VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (source_location_id <> destination_location_id)
+);
+
+CREATE INDEX idx_stock_transfer_source_location_id ON stock_transfer(source_location_id, created_at);
+CREATE INDEX idx_stock_transfer_destination_location_id ON stock_transfer(destination_location_id, created_at);
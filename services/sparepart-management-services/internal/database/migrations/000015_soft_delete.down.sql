@@ -0,0 +1,85 @@
+-- Soft deleted rows would reappear without deleted_at, so they are removed first
+DELETE FROM sparepart_stock_item WHERE deleted_at IS NOT NULL;
+DELETE FROM location WHERE deleted_at IS NOT NULL;
+
+DROP TRIGGER IF EXISTS record_sparepart_stock_item_ledger ON sparepart_stock_item;
+CREATE OR REPLACE FUNCTION record_stock_ledger()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+        VALUES (NEW.id, NEW.location_id, NEW.sparepart_id, NEW.stock_type, NEW.quantity, NEW.quantity);
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF NEW.quantity IS DISTINCT FROM OLD.quantity THEN
+            INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+            VALUES (NEW.id, NEW.location_id, NEW.sparepart_id, NEW.stock_type, NEW.quantity - OLD.quantity, NEW.quantity);
+        END IF;
+    ELSIF TG_OP = 'DELETE' THEN
+        INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+        VALUES (OLD.id, OLD.location_id, OLD.sparepart_id, OLD.stock_type, -OLD.quantity, 0);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_sparepart_stock_item_ledger AFTER INSERT OR UPDATE OF quantity OR DELETE ON sparepart_stock_item
+    FOR EACH ROW EXECUTE FUNCTION record_stock_ledger();
+
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_region;
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_sparepart;
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_location;
+
+DROP INDEX IF EXISTS idx_sparepart_stock_deleted_at;
+DROP INDEX IF EXISTS idx_location_deleted_at;
+ALTER TABLE sparepart_stock_item DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE location DROP COLUMN IF EXISTS deleted_at;
+
+-- Stock totals per location
+CREATE MATERIALIZED VIEW stock_summary_by_location AS
+SELECT
+    l.id AS location_id,
+    l.region,
+    l.regency,
+    l.cluster,
+    COUNT(ssi.id)::bigint AS item_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM location l
+JOIN sparepart_stock_item ssi ON ssi.location_id = l.id
+GROUP BY l.id, l.region, l.regency, l.cluster;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_location_id ON stock_summary_by_location(location_id);
+CREATE INDEX idx_stock_summary_by_location_region ON stock_summary_by_location(region);
+
+-- Stock totals per sparepart across all locations
+CREATE MATERIALIZED VIEW stock_summary_by_sparepart AS
+SELECT
+    ls.id AS sparepart_id,
+    ls.name AS sparepart_name,
+    COUNT(DISTINCT ssi.location_id)::bigint AS location_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM list_sparepart ls
+JOIN sparepart_stock_item ssi ON ssi.sparepart_id = ls.id
+GROUP BY ls.id, ls.name;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_sparepart_id ON stock_summary_by_sparepart(sparepart_id);
+
+-- Stock totals per region
+CREATE MATERIALIZED VIEW stock_summary_by_region AS
+SELECT
+    l.region,
+    COUNT(DISTINCT ssi.location_id)::bigint AS location_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM location l
+JOIN sparepart_stock_item ssi ON ssi.location_id = l.id
+GROUP BY l.region;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_region ON stock_summary_by_region(region);
@@ -0,0 +1,97 @@
+-- Soft delete: deleting a location or stock item sets deleted_at instead of removing the row,
+-- so it can be restored; rows are removed for good by the admin purge. Deleting a location
+-- soft deletes its stock items with the same deleted_at. The unique constraints still cover
+-- soft deleted rows here; 000047 limits them to the rows that aren't deleted.
+ALTER TABLE location ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE sparepart_stock_item ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_location_deleted_at ON location(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_sparepart_stock_deleted_at ON sparepart_stock_item(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- The ledger follows the quantity still in stock: a soft deleted item counts as 0, so soft
+-- deleting records the drop to 0, restoring records it coming back and purging records nothing
+CREATE OR REPLACE FUNCTION record_stock_ledger()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_quantity INTEGER := CASE WHEN TG_OP <> 'INSERT' AND OLD.deleted_at IS NULL THEN OLD.quantity ELSE 0 END;
+    new_quantity INTEGER := CASE WHEN TG_OP <> 'DELETE' AND NEW.deleted_at IS NULL THEN NEW.quantity ELSE 0 END;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+        VALUES (NEW.id, NEW.location_id, NEW.sparepart_id, NEW.stock_type, new_quantity, new_quantity);
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF new_quantity IS DISTINCT FROM old_quantity THEN
+            INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+            VALUES (NEW.id, NEW.location_id, NEW.sparepart_id, NEW.stock_type, new_quantity - old_quantity, new_quantity);
+        END IF;
+    ELSIF TG_OP = 'DELETE' THEN
+        IF OLD.deleted_at IS NULL THEN
+            INSERT INTO stock_ledger (stock_item_id, location_id, sparepart_id, stock_type, quantity_change, quantity_after)
+            VALUES (OLD.id, OLD.location_id, OLD.sparepart_id, OLD.stock_type, -old_quantity, 0);
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS record_sparepart_stock_item_ledger ON sparepart_stock_item;
+CREATE TRIGGER record_sparepart_stock_item_ledger AFTER INSERT OR UPDATE OF quantity, deleted_at OR DELETE ON sparepart_stock_item
+    FOR EACH ROW EXECUTE FUNCTION record_stock_ledger();
+
+-- Recreate the summary views without soft deleted stock items
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_region;
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_sparepart;
+DROP MATERIALIZED VIEW IF EXISTS stock_summary_by_location;
+
+-- Stock totals per location
+CREATE MATERIALIZED VIEW stock_summary_by_location AS
+SELECT
+    l.id AS location_id,
+    l.region,
+    l.regency,
+    l.cluster,
+    COUNT(ssi.id)::bigint AS item_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM location l
+JOIN sparepart_stock_item ssi ON ssi.location_id = l.id
+WHERE ssi.deleted_at IS NULL
+GROUP BY l.id, l.region, l.regency, l.cluster;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_location_id ON stock_summary_by_location(location_id);
+CREATE INDEX idx_stock_summary_by_location_region ON stock_summary_by_location(region);
+
+-- Stock totals per sparepart across all locations
+CREATE MATERIALIZED VIEW stock_summary_by_sparepart AS
+SELECT
+    ls.id AS sparepart_id,
+    ls.name AS sparepart_name,
+    COUNT(DISTINCT ssi.location_id)::bigint AS location_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM list_sparepart ls
+JOIN sparepart_stock_item ssi ON ssi.sparepart_id = ls.id
+WHERE ssi.deleted_at IS NULL
+GROUP BY ls.id, ls.name;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_sparepart_id ON stock_summary_by_sparepart(sparepart_id);
+
+-- Stock totals per region
+CREATE MATERIALIZED VIEW stock_summary_by_region AS
+SELECT
+    l.region,
+    COUNT(DISTINCT ssi.location_id)::bigint AS location_count,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_stock_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_stock_quantity,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS total_quantity,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM location l
+JOIN sparepart_stock_item ssi ON ssi.location_id = l.id
+WHERE ssi.deleted_at IS NULL
+GROUP BY l.region;
+
+CREATE UNIQUE INDEX idx_stock_summary_by_region ON stock_summary_by_region(region);
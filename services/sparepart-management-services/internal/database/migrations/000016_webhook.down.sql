@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS record_tools_alker_item_webhook_event ON tools_alker_item;
+DROP TRIGGER IF EXISTS record_sparepart_stock_item_webhook_event ON sparepart_stock_item;
+DROP FUNCTION IF EXISTS record_webhook_event();
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TABLE IF EXISTS webhook_event;
+DROP TABLE IF EXISTS webhook;
@@ -0,0 +1,103 @@
+-- Webhooks: downstream systems subscribe a URL to stock change events. Changes are written
+-- to the webhook_event outbox by triggers, in the transaction that made them, and the
+-- dispatcher fans every event out to a webhook_delivery per subscribed webhook.
+CREATE TABLE webhook (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    -- Key of the HMAC-SHA256 signature sent with every delivery
+    secret VARCHAR(255) NOT NULL,
+    -- Subscribed events; empty subscribes to all
+    events TEXT[] NOT NULL DEFAULT '{}',
+    -- stock.low is sent when an item's quantity drops to this or below
+    low_stock_threshold INTEGER NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_webhook_updated_at BEFORE UPDATE ON webhook
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE webhook_event (
+    id BIGSERIAL PRIMARY KEY,
+    event VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Set once the event has been fanned out to the webhooks
+    dispatched_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_event_pending ON webhook_event(id) WHERE dispatched_at IS NULL;
+
+CREATE TABLE webhook_delivery (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL REFERENCES webhook(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES webhook_event(id) ON DELETE CASCADE,
+    -- The delivered event; differs from the source event for stock.low
+    event VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    response_status INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_delivery_due ON webhook_delivery(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX idx_webhook_delivery_webhook_id ON webhook_delivery(webhook_id, id);
+
+-- Records stock.created/updated/deleted/restored and tools_alker.created/updated/deleted.
+-- The payload is the row without its documentation, plus previous_quantity on updates.
+-- Nothing is recorded while no webhook is enabled.
+CREATE OR REPLACE FUNCTION record_webhook_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    entity TEXT := CASE TG_TABLE_NAME WHEN 'sparepart_stock_item' THEN 'stock' ELSE 'tools_alker' END;
+    old_row JSONB := CASE WHEN TG_OP <> 'INSERT' THEN to_jsonb(OLD) - 'documentation' ELSE NULL END;
+    new_row JSONB := CASE WHEN TG_OP <> 'DELETE' THEN to_jsonb(NEW) - 'documentation' ELSE NULL END;
+    action TEXT;
+    payload JSONB;
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM webhook WHERE enabled) THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'INSERT' THEN
+        action := 'created';
+        payload := new_row;
+    ELSIF TG_OP = 'DELETE' THEN
+        -- A soft deleted stock item was reported when it was deleted; purging it is no news
+        IF old_row ->> 'deleted_at' IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        action := 'deleted';
+        payload := old_row;
+    ELSE
+        IF (old_row ->> 'deleted_at') IS NULL AND (new_row ->> 'deleted_at') IS NOT NULL THEN
+            action := 'deleted';
+        ELSIF (old_row ->> 'deleted_at') IS NOT NULL AND (new_row ->> 'deleted_at') IS NULL THEN
+            action := 'restored';
+        ELSIF new_row ->> 'deleted_at' IS NOT NULL THEN
+            RETURN NULL;
+        ELSIF (old_row - 'updated_at') = (new_row - 'updated_at') THEN
+            -- Updates that only touched updated_at are not changes
+            RETURN NULL;
+        ELSE
+            action := 'updated';
+        END IF;
+        payload := new_row || jsonb_build_object('previous_quantity', old_row -> 'quantity');
+    END IF;
+
+    INSERT INTO webhook_event (event, payload)
+    VALUES (entity || '.' || action, payload);
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_sparepart_stock_item_webhook_event AFTER INSERT OR UPDATE OR DELETE ON sparepart_stock_item
+    FOR EACH ROW EXECUTE FUNCTION record_webhook_event();
+
+CREATE TRIGGER record_tools_alker_item_webhook_event AFTER INSERT OR UPDATE OR DELETE ON tools_alker_item
+    FOR EACH ROW EXECUTE FUNCTION record_webhook_event();
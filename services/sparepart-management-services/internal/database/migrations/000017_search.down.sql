@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tools_alker_notes_trgm;
+DROP INDEX IF EXISTS idx_sparepart_stock_notes_trgm;
+DROP INDEX IF EXISTS idx_location_cluster_trgm;
+DROP INDEX IF EXISTS idx_location_regency_trgm;
+DROP INDEX IF EXISTS idx_list_sparepart_name_trgm;
+-- pg_trgm is left installed; other database objects may use it
@@ -0,0 +1,9 @@
+-- Trigram indexes behind GET /sparepart/search: they serve both the substring (ILIKE) and
+-- the fuzzy word similarity (<%) matches, so typos and partial names still find items
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_list_sparepart_name_trgm ON list_sparepart USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_location_regency_trgm ON location USING GIN (regency gin_trgm_ops);
+CREATE INDEX idx_location_cluster_trgm ON location USING GIN (cluster gin_trgm_ops);
+CREATE INDEX idx_sparepart_stock_notes_trgm ON sparepart_stock_item USING GIN (notes gin_trgm_ops);
+CREATE INDEX idx_tools_alker_notes_trgm ON tools_alker_item USING GIN (notes gin_trgm_ops);
@@ -0,0 +1,25 @@
+-- Export jobs: large exports are generated in the background by the export worker instead
+-- of within the request. A finished job's file is kept in the report directory and
+-- downloaded through a signed report link.
+CREATE TABLE export_job (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    entity VARCHAR(50) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    -- The export query parameters, as recorded in the export log
+    filters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    -- A RUNNING job whose lease has passed was abandoned (e.g. by a restart) and is claimed again
+    locked_until TIMESTAMPTZ,
+    filename VARCHAR(255),
+    stored_name VARCHAR(255),
+    row_count INTEGER,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_export_job_queue ON export_job(id) WHERE status IN ('PENDING', 'RUNNING');
+CREATE INDEX idx_export_job_user_id ON export_job(user_id, id DESC);
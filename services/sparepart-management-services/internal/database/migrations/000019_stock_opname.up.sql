@@ -0,0 +1,37 @@
+-- Stock opname (physical count) sessions. Counts are recorded per sparepart and stock type
+-- against the system quantity at the time of counting; approving a submitted session
+-- applies every variance to the location's stock, which the stock ledger records like any
+-- other quantity change. Sessions reference locations and spareparts by ID only, so they
+-- outlive deletes; 000048 adds the foreign keys.
+CREATE TABLE stock_opname (
+    id SERIAL PRIMARY KEY,
+    location_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'SUBMITTED', 'APPROVED')),
+    notes TEXT,
+    created_by VARCHAR(255),
+    submitted_by VARCHAR(255),
+    submitted_at TIMESTAMPTZ,
+    approved_by VARCHAR(255),
+    approved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_opname_location_id ON stock_opname(location_id, created_at);
+
+CREATE TABLE stock_opname_item (
+    id SERIAL PRIMARY KEY,
+    opname_id INTEGER NOT NULL REFERENCES stock_opname(id) ON DELETE CASCADE,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    system_quantity INTEGER NOT NULL,
+    counted_quantity INTEGER NOT NULL CHECK (counted_quantity >= 0),
+    -- Set on approval for counts with a variance: the adjusted stock item and its quantity after
+    stock_item_id INTEGER,
+    quantity_after INTEGER,
+    counted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_stock_opname_item UNIQUE (opname_id, sparepart_id, stock_type)
+);
+
+CREATE TRIGGER update_stock_opname_updated_at BEFORE UPDATE ON stock_opname
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sparepart_request_event;
+DROP TABLE IF EXISTS sparepart_request_item;
+DROP TABLE IF EXISTS sparepart_request;
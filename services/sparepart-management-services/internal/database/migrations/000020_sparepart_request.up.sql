@@ -0,0 +1,45 @@
+-- Sparepart requests: a field team asks for spareparts to be sent to a site, an admin
+-- approves or rejects the request, and fulfilling it moves the stock from the warehouse
+-- location to the site as stock transfers. Requests reference locations and spareparts by
+-- ID only, so they outlive deletes; 000049 adds the foreign keys.
+CREATE TABLE sparepart_request (
+    id SERIAL PRIMARY KEY,
+    destination_location_id INTEGER NOT NULL,
+    -- The location the stock was taken from, set on fulfillment
+    source_location_id INTEGER,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'FULFILLED')),
+    notes TEXT,
+    requested_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sparepart_request_status ON sparepart_request(status, created_at);
+CREATE INDEX idx_sparepart_request_destination_location_id ON sparepart_request(destination_location_id, created_at);
+CREATE INDEX idx_sparepart_request_requested_by ON sparepart_request(requested_by, created_at);
+
+CREATE TRIGGER update_sparepart_request_updated_at BEFORE UPDATE ON sparepart_request
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE sparepart_request_item (
+    id SERIAL PRIMARY KEY,
+    request_id INTEGER NOT NULL REFERENCES sparepart_request(id) ON DELETE CASCADE,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    -- The stock transfer that delivered the item, set on fulfillment
+    transfer_id INTEGER,
+    CONSTRAINT unique_sparepart_request_item UNIQUE (request_id, sparepart_id, stock_type)
+);
+
+-- Status history: one row per status a request entered, with who moved it there
+CREATE TABLE sparepart_request_event (
+    id BIGSERIAL PRIMARY KEY,
+    request_id INTEGER NOT NULL REFERENCES sparepart_request(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    actor VARCHAR(255),
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sparepart_request_event_request_id ON sparepart_request_event(request_id, id);
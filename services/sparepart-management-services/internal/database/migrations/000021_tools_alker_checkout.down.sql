@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tools_alker_checkout;
@@ -0,0 +1,17 @@
+-- Tools alker checkouts: tools lent to a technician until they are checked in again. The
+-- quantity of a tools alker item that is available is its quantity minus its open checkouts.
+CREATE TABLE tools_alker_checkout (
+    id SERIAL PRIMARY KEY,
+    tools_alker_item_id INTEGER NOT NULL REFERENCES tools_alker_item(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    technician VARCHAR(255) NOT NULL,
+    expected_return_date DATE NOT NULL,
+    notes TEXT,
+    checked_out_by VARCHAR(255),
+    checked_out_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    checked_in_by VARCHAR(255),
+    checked_in_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_tools_alker_checkout_open ON tools_alker_checkout(tools_alker_item_id) WHERE checked_in_at IS NULL;
+CREATE INDEX idx_tools_alker_checkout_expected_return_date ON tools_alker_checkout(expected_return_date) WHERE checked_in_at IS NULL;
@@ -0,0 +1,20 @@
+-- Stock units: the serial numbers of the individual units of a stock item, for high-value
+-- spareparts (BMS, SCC) tracked per unit. A serial number or asset tag is registered once
+-- across all locations; they are stored upper-cased so scans match regardless of case.
+CREATE TABLE stock_unit (
+    id SERIAL PRIMARY KEY,
+    stock_item_id INTEGER NOT NULL REFERENCES sparepart_stock_item(id) ON DELETE CASCADE,
+    serial_number VARCHAR(100) NOT NULL,
+    asset_tag VARCHAR(100),
+    notes TEXT,
+    registered_by VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_stock_unit_serial_number UNIQUE (serial_number),
+    CONSTRAINT unique_stock_unit_asset_tag UNIQUE (asset_tag)
+);
+
+CREATE INDEX idx_stock_unit_stock_item_id ON stock_unit(stock_item_id);
+
+CREATE TRIGGER update_stock_unit_updated_at BEFORE UPDATE ON stock_unit
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_contact_person_email;
+
+ALTER TABLE contact_person DROP COLUMN IF EXISTS email;
@@ -0,0 +1,5 @@
+-- Contact persons with an email address receive the email digest of their location
+-- (low stock and sparepart requests awaiting approval)
+ALTER TABLE contact_person ADD COLUMN email VARCHAR(255);
+
+CREATE INDEX idx_contact_person_email ON contact_person(location_id) WHERE email IS NOT NULL;
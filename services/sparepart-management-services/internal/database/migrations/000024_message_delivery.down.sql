@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS enqueue_sparepart_request_messages ON sparepart_request;
+DROP TRIGGER IF EXISTS enqueue_stock_transfer_messages ON stock_transfer;
+DROP FUNCTION IF EXISTS enqueue_contact_messages();
+DROP TABLE IF EXISTS message_delivery;
@@ -0,0 +1,77 @@
+-- Messages (SMS or WhatsApp) to the contact persons of a location. Stock transfers and
+-- sparepart requests enqueue a delivery per contact person of the affected locations, in
+-- the transaction that made them, and the message dispatcher sends them through the
+-- configured provider. Deliveries keep the number and name they were enqueued for, so
+-- they outlive changes to the contact person.
+CREATE TABLE message_delivery (
+    id BIGSERIAL PRIMARY KEY,
+    contact_person_id INTEGER REFERENCES contact_person(id) ON DELETE SET NULL,
+    location_id INTEGER NOT NULL,
+    pic VARCHAR(100) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    -- The text sent, set on the first attempt
+    body TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- The message ID the provider accepted the message as
+    provider_message_id VARCHAR(255),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_message_delivery_due ON message_delivery(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX idx_message_delivery_location_id ON message_delivery(location_id, id);
+
+-- Enqueues stock.transferred_in for the destination's and stock.transferred_out for the
+-- source's contact persons of a transfer, and request.created for the destination's contact
+-- persons of a sparepart request
+CREATE OR REPLACE FUNCTION enqueue_contact_messages()
+RETURNS TRIGGER AS $$
+DECLARE
+    payload JSONB;
+BEGIN
+    IF TG_TABLE_NAME = 'stock_transfer' THEN
+        payload := jsonb_build_object(
+            'transfer_id', NEW.id,
+            'sparepart', (SELECT name FROM list_sparepart WHERE id = NEW.sparepart_id),
+            'stock_type', NEW.stock_type,
+            'quantity', NEW.quantity,
+            'source_cluster', (SELECT cluster FROM location WHERE id = NEW.source_location_id),
+            'destination_cluster', (SELECT cluster FROM location WHERE id = NEW.destination_location_id),
+            'notes', NEW.notes
+        );
+
+        INSERT INTO message_delivery (contact_person_id, location_id, pic, phone, event, payload)
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'stock.transferred_in', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.destination_location_id
+        UNION ALL
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'stock.transferred_out', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.source_location_id;
+    ELSE
+        payload := jsonb_build_object(
+            'request_id', NEW.id,
+            'destination_cluster', (SELECT cluster FROM location WHERE id = NEW.destination_location_id),
+            'requested_by', NEW.requested_by,
+            'notes', NEW.notes
+        );
+
+        INSERT INTO message_delivery (contact_person_id, location_id, pic, phone, event, payload)
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'request.created', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.destination_location_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER enqueue_stock_transfer_messages AFTER INSERT ON stock_transfer
+    FOR EACH ROW EXECUTE FUNCTION enqueue_contact_messages();
+
+CREATE TRIGGER enqueue_sparepart_request_messages AFTER INSERT ON sparepart_request
+    FOR EACH ROW EXECUTE FUNCTION enqueue_contact_messages();
@@ -0,0 +1,16 @@
+-- API keys for machine-to-machine access (e.g. reporting cron jobs), sent as X-API-Key
+-- instead of a JWT. Only the SHA-256 of the key is stored; the key itself is shown once on
+-- creation and identified afterwards by its prefix.
+CREATE TABLE api_key (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    -- read-only allows GET requests; stock-write also allows changing the stock
+    scopes TEXT[] NOT NULL CHECK (cardinality(scopes) > 0 AND scopes <@ ARRAY['read-only', 'stock-write']),
+    created_by VARCHAR(255),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
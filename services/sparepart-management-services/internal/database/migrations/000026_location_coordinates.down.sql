@@ -0,0 +1,6 @@
+ALTER TABLE location
+    DROP CONSTRAINT IF EXISTS location_coordinates_pair,
+    DROP CONSTRAINT IF EXISTS location_longitude_range,
+    DROP CONSTRAINT IF EXISTS location_latitude_range,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
@@ -0,0 +1,8 @@
+-- Coordinates of a location for the map view (GET /location/geojson), in WGS84 degrees.
+-- Both are set or both are left empty.
+ALTER TABLE location
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD CONSTRAINT location_latitude_range CHECK (latitude BETWEEN -90 AND 90),
+    ADD CONSTRAINT location_longitude_range CHECK (longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT location_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL));
@@ -0,0 +1,5 @@
+-- The normalized location names are kept
+ALTER TABLE location DROP CONSTRAINT IF EXISTS location_cluster_fkey;
+
+DROP TABLE IF EXISTS cluster;
+DROP TABLE IF EXISTS regency;
@@ -0,0 +1,85 @@
+-- Regencies and clusters become reference data: a location's (region, regency, cluster) must
+-- name an existing cluster, so a typo can no longer create a new location. The location keeps
+-- its text columns, referenced through composite foreign keys with ON UPDATE CASCADE, so
+-- renaming a regency or cluster renames it on every location.
+CREATE TABLE regency (
+    id SERIAL PRIMARY KEY,
+    region region_type NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_regency UNIQUE (region, name),
+    CONSTRAINT regency_name_not_blank CHECK (btrim(name) <> '')
+);
+
+CREATE TABLE cluster (
+    id SERIAL PRIMARY KEY,
+    region region_type NOT NULL,
+    regency VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_cluster UNIQUE (region, regency, name),
+    CONSTRAINT cluster_name_not_blank CHECK (btrim(name) <> ''),
+    CONSTRAINT cluster_regency_fkey FOREIGN KEY (region, regency)
+        REFERENCES regency(region, name) ON UPDATE CASCADE ON DELETE RESTRICT
+);
+
+CREATE TRIGGER update_regency_updated_at BEFORE UPDATE ON regency
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_cluster_updated_at BEFORE UPDATE ON cluster
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Normalize the free text: collapse whitespace, then spell each regency (per region) and
+-- cluster (per regency) the way most locations do, ignoring case. A location whose
+-- normalized name is already taken by another location keeps its spelling, so it shows up
+-- as its own regency or cluster to be merged by hand.
+WITH trimmed AS (
+    SELECT
+        id, region,
+        btrim(regexp_replace(regency, '\s+', ' ', 'g')) AS regency,
+        btrim(regexp_replace(cluster, '\s+', ' ', 'g')) AS cluster
+    FROM location
+), regency_names AS (
+    SELECT region, lower(regency) AS name_key, mode() WITHIN GROUP (ORDER BY regency) AS name
+    FROM trimmed
+    GROUP BY region, lower(regency)
+), with_regency AS (
+    SELECT t.id, t.region, rn.name AS regency, t.cluster
+    FROM trimmed t
+    JOIN regency_names rn ON rn.region = t.region AND rn.name_key = lower(t.regency)
+), cluster_names AS (
+    SELECT region, regency, lower(cluster) AS name_key, mode() WITHIN GROUP (ORDER BY cluster) AS name
+    FROM with_regency
+    GROUP BY region, regency, lower(cluster)
+), normalized AS (
+    SELECT w.id, w.region, w.regency, cn.name AS cluster
+    FROM with_regency w
+    JOIN cluster_names cn ON cn.region = w.region AND cn.regency = w.regency AND cn.name_key = lower(w.cluster)
+), ranked AS (
+    -- A location already spelled the normalized way keeps the name; otherwise the oldest takes it
+    SELECT
+        n.*,
+        ROW_NUMBER() OVER (
+            PARTITION BY n.region, n.regency, n.cluster
+            ORDER BY (l.regency = n.regency AND l.cluster = n.cluster) DESC, n.id
+        ) AS name_rank
+    FROM normalized n
+    JOIN location l ON l.id = n.id
+)
+UPDATE location l
+SET regency = r.regency, cluster = r.cluster
+FROM ranked r
+WHERE l.id = r.id
+    AND r.name_rank = 1
+    AND (l.regency <> r.regency OR l.cluster <> r.cluster);
+
+INSERT INTO regency (region, name)
+SELECT DISTINCT region, regency FROM location;
+
+INSERT INTO cluster (region, regency, name)
+SELECT DISTINCT region, regency, cluster FROM location;
+
+ALTER TABLE location
+    ADD CONSTRAINT location_cluster_fkey FOREIGN KEY (region, regency, cluster)
+        REFERENCES cluster(region, regency, name) ON UPDATE CASCADE ON DELETE RESTRICT;
@@ -0,0 +1 @@
+ALTER TABLE location DROP COLUMN IF EXISTS is_active;
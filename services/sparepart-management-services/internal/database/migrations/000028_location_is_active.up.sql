@@ -0,0 +1,4 @@
+-- A location that no longer holds stock is deactivated rather than deleted, so the stock
+-- history referencing it stays intact. Inactive locations are left out of the default
+-- location list and the filter dropdowns.
+ALTER TABLE location ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS increment_tools_alker_item_version ON tools_alker_item;
+DROP TRIGGER IF EXISTS increment_sparepart_stock_item_version ON sparepart_stock_item;
+DROP FUNCTION IF EXISTS increment_version_column();
+
+ALTER TABLE tools_alker_item DROP COLUMN IF EXISTS version;
+ALTER TABLE sparepart_stock_item DROP COLUMN IF EXISTS version;
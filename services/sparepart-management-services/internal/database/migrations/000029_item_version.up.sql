@@ -0,0 +1,18 @@
+-- Row versions for optimistic locking: PUT/PATCH on a stock or tools alker item must name the
+-- version it was based on, and fail with 409 when someone else changed the item since. Every
+-- update of the row bumps the version, whichever endpoint makes it.
+ALTER TABLE sparepart_stock_item ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE tools_alker_item ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION increment_version_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER increment_sparepart_stock_item_version BEFORE UPDATE ON sparepart_stock_item
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
+CREATE TRIGGER increment_tools_alker_item_version BEFORE UPDATE ON tools_alker_item
+    FOR EACH ROW EXECUTE FUNCTION increment_version_column();
@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS stock_history_events(INTEGER);
@@ -0,0 +1,44 @@
+-- Stock item timeline: expands the change history of a stock item into one event per
+-- creation, quantity change, added or removed photo, notes edit, delete and restore. The
+-- values are the JSON old/new values of the change (photo paths for photo events); seq
+-- orders the events of a single change.
+CREATE OR REPLACE FUNCTION stock_history_events(item_id INTEGER)
+RETURNS TABLE (
+    change_id BIGINT,
+    seq INTEGER,
+    event TEXT,
+    old_value JSONB,
+    new_value JSONB,
+    actor VARCHAR(255),
+    changed_at TIMESTAMPTZ
+) AS $$
+    SELECT ch.id, e.seq, e.event, e.old_value, e.new_value, ch.actor, ch.changed_at
+    FROM change_history ch
+    CROSS JOIN LATERAL (
+        SELECT 1 AS seq, 'CREATED' AS event, NULL::jsonb AS old_value, ch.changes -> 'quantity' -> 'new' AS new_value
+        WHERE ch.operation = 'INSERT'
+        UNION ALL
+        SELECT 2, 'QUANTITY_CHANGED', ch.changes -> 'quantity' -> 'old', ch.changes -> 'quantity' -> 'new'
+        WHERE ch.operation = 'UPDATE' AND ch.changes ? 'quantity'
+        UNION ALL
+        SELECT 3, 'PHOTO_ADDED', NULL, photo
+        FROM jsonb_array_elements(COALESCE(NULLIF(ch.changes -> 'documentation' -> 'new', 'null'), '[]')) photo
+        WHERE ch.operation <> 'DELETE'
+            AND NOT COALESCE(NULLIF(ch.changes -> 'documentation' -> 'old', 'null'), '[]') @> jsonb_build_array(photo)
+        UNION ALL
+        SELECT 4, 'PHOTO_REMOVED', photo, NULL
+        FROM jsonb_array_elements(COALESCE(NULLIF(ch.changes -> 'documentation' -> 'old', 'null'), '[]')) photo
+        WHERE ch.operation = 'UPDATE'
+            AND NOT COALESCE(NULLIF(ch.changes -> 'documentation' -> 'new', 'null'), '[]') @> jsonb_build_array(photo)
+        UNION ALL
+        SELECT 5, 'NOTES_EDITED', ch.changes -> 'notes' -> 'old', ch.changes -> 'notes' -> 'new'
+        WHERE ch.operation = 'UPDATE' AND ch.changes ? 'notes'
+        UNION ALL
+        SELECT 6, CASE WHEN ch.changes -> 'deleted_at' -> 'new' = 'null' THEN 'RESTORED' ELSE 'DELETED' END, NULL, NULL
+        WHERE ch.operation = 'UPDATE' AND ch.changes ? 'deleted_at'
+        UNION ALL
+        SELECT 6, 'DELETED', ch.changes -> 'quantity' -> 'old', NULL
+        WHERE ch.operation = 'DELETE'
+    ) e
+    WHERE ch.table_name = 'sparepart_stock_item' AND ch.record_id = item_id;
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,15 @@
+-- Daily stock snapshots: the quantity of every (location, sparepart, stock type) per day,
+-- recorded by a scheduler. The row of the current day is overwritten on every run, so once
+-- the day is over it holds the quantity at the last run of that day.
+CREATE TABLE stock_snapshot (
+    snapshot_date DATE NOT NULL,
+    location_id INTEGER NOT NULL,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_date, location_id, sparepart_id, stock_type)
+);
+
+CREATE INDEX idx_stock_snapshot_location_date ON stock_snapshot(location_id, snapshot_date);
+CREATE INDEX idx_stock_snapshot_sparepart_date ON stock_snapshot(sparepart_id, snapshot_date);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS goods_receipt_item;
+DROP TABLE IF EXISTS goods_receipt;
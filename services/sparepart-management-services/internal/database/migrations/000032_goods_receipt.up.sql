@@ -0,0 +1,39 @@
+-- Goods receipts: an incoming shipment registered against a location with its supplier,
+-- delivery order (DO) number, items and packing list photos. Confirming a DRAFT receipt adds
+-- every item to the location's stock, which the stock ledger records like any other quantity
+-- change. Receipts reference locations and spareparts by ID only, so they outlive deletes;
+-- 000050 adds the foreign keys.
+CREATE TABLE goods_receipt (
+    id SERIAL PRIMARY KEY,
+    supplier VARCHAR(255) NOT NULL,
+    delivery_order_number VARCHAR(100) NOT NULL,
+    location_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'CONFIRMED')),
+    packing_list_photos JSONB NOT NULL DEFAULT '[]',
+    notes TEXT,
+    created_by VARCHAR(255),
+    confirmed_by VARCHAR(255),
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- A delivery order is received once
+    CONSTRAINT unique_goods_receipt_delivery_order UNIQUE (supplier, delivery_order_number)
+);
+
+CREATE INDEX idx_goods_receipt_status ON goods_receipt(status, created_at);
+CREATE INDEX idx_goods_receipt_location_id ON goods_receipt(location_id, created_at);
+
+CREATE TRIGGER update_goods_receipt_updated_at BEFORE UPDATE ON goods_receipt
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE goods_receipt_item (
+    id SERIAL PRIMARY KEY,
+    receipt_id INTEGER NOT NULL REFERENCES goods_receipt(id) ON DELETE CASCADE,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    -- Set on confirmation: the stock item the quantity was added to and its quantity after
+    stock_item_id INTEGER,
+    quantity_after INTEGER,
+    CONSTRAINT unique_goods_receipt_item UNIQUE (receipt_id, sparepart_id, stock_type)
+);
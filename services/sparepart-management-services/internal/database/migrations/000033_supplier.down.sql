@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_goods_receipt_item_stock_item_id;
+
+ALTER TABLE goods_receipt ADD COLUMN supplier VARCHAR(255);
+
+UPDATE goods_receipt gr
+SET supplier = s.name
+FROM supplier s
+WHERE s.id = gr.supplier_id;
+
+ALTER TABLE goods_receipt
+    ALTER COLUMN supplier SET NOT NULL,
+    DROP CONSTRAINT unique_goods_receipt_delivery_order,
+    DROP COLUMN supplier_id,
+    ADD CONSTRAINT unique_goods_receipt_delivery_order UNIQUE (supplier, delivery_order_number);
+
+DROP TABLE IF EXISTS supplier;
@@ -0,0 +1,40 @@
+-- Suppliers of incoming shipments. Goods receipts reference their supplier instead of naming
+-- it, so a supplier's receipts and the stock they brought in can be traced, e.g. for warranty
+-- claims; a supplier with receipts cannot be deleted.
+CREATE TABLE supplier (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    contact_person VARCHAR(255),
+    phone VARCHAR(50),
+    email VARCHAR(255),
+    address TEXT,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_supplier_name UNIQUE (name),
+    CONSTRAINT supplier_name_not_blank CHECK (btrim(name) <> '')
+);
+
+CREATE TRIGGER update_supplier_updated_at BEFORE UPDATE ON supplier
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Every supplier named by an existing receipt becomes a supplier row
+INSERT INTO supplier (name)
+SELECT DISTINCT supplier FROM goods_receipt;
+
+ALTER TABLE goods_receipt ADD COLUMN supplier_id INTEGER;
+
+UPDATE goods_receipt gr
+SET supplier_id = s.id
+FROM supplier s
+WHERE s.name = gr.supplier;
+
+ALTER TABLE goods_receipt
+    ALTER COLUMN supplier_id SET NOT NULL,
+    ADD CONSTRAINT goods_receipt_supplier_id_fkey FOREIGN KEY (supplier_id) REFERENCES supplier(id),
+    DROP CONSTRAINT unique_goods_receipt_delivery_order,
+    DROP COLUMN supplier,
+    ADD CONSTRAINT unique_goods_receipt_delivery_order UNIQUE (supplier_id, delivery_order_number);
+
+-- Stock movements are traced back to the receipt item that caused them by stock item
+CREATE INDEX idx_goods_receipt_item_stock_item_id ON goods_receipt_item(stock_item_id);
@@ -0,0 +1,10 @@
+DROP VIEW IF EXISTS purchase_order_item_progress;
+
+DROP INDEX IF EXISTS idx_goods_receipt_item_purchase_order_item_id;
+DROP INDEX IF EXISTS idx_goods_receipt_purchase_order_id;
+
+ALTER TABLE goods_receipt_item DROP COLUMN IF EXISTS purchase_order_item_id;
+ALTER TABLE goods_receipt DROP COLUMN IF EXISTS purchase_order_id;
+
+DROP TABLE IF EXISTS purchase_order_item;
+DROP TABLE IF EXISTS purchase_order;
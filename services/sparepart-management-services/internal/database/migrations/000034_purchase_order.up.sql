@@ -0,0 +1,53 @@
+-- Purchase orders: spareparts ordered from a supplier. A DRAFT order becomes ORDERED once
+-- placed; goods receipts for it link their items to the order lines, and confirming such a
+-- receipt moves the order to PARTIAL or, once every line is received in full, RECEIVED.
+CREATE TABLE purchase_order (
+    id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES supplier(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'ORDERED', 'PARTIAL', 'RECEIVED')),
+    expected_date DATE,
+    notes TEXT,
+    created_by VARCHAR(255),
+    ordered_by VARCHAR(255),
+    ordered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_purchase_order_status ON purchase_order(status, created_at);
+CREATE INDEX idx_purchase_order_supplier_id ON purchase_order(supplier_id, created_at);
+
+CREATE TRIGGER update_purchase_order_updated_at BEFORE UPDATE ON purchase_order
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE purchase_order_item (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES purchase_order(id) ON DELETE CASCADE,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    CONSTRAINT unique_purchase_order_item UNIQUE (order_id, sparepart_id, stock_type)
+);
+
+ALTER TABLE goods_receipt
+    ADD COLUMN purchase_order_id INTEGER REFERENCES purchase_order(id);
+
+ALTER TABLE goods_receipt_item
+    ADD COLUMN purchase_order_item_id INTEGER REFERENCES purchase_order_item(id);
+
+CREATE INDEX idx_goods_receipt_purchase_order_id ON goods_receipt(purchase_order_id);
+CREATE INDEX idx_goods_receipt_item_purchase_order_item_id ON goods_receipt_item(purchase_order_item_id);
+
+-- Quantity received per order line, counting confirmed receipts only. Receiving more than
+-- ordered is allowed; the outstanding quantity does not go below zero.
+CREATE VIEW purchase_order_item_progress AS
+SELECT
+    poi.id AS item_id,
+    poi.order_id,
+    poi.quantity,
+    COALESCE(SUM(gri.quantity) FILTER (WHERE gr.status = 'CONFIRMED'), 0)::bigint AS received_quantity,
+    GREATEST(poi.quantity - COALESCE(SUM(gri.quantity) FILTER (WHERE gr.status = 'CONFIRMED'), 0), 0)::bigint AS outstanding_quantity
+FROM purchase_order_item poi
+LEFT JOIN goods_receipt_item gri ON gri.purchase_order_item_id = poi.id
+LEFT JOIN goods_receipt gr ON gr.id = gri.receipt_id
+GROUP BY poi.id;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_stock_unit_warranty_end;
+
+ALTER TABLE stock_unit
+    DROP CONSTRAINT IF EXISTS stock_unit_warranty_complete,
+    DROP CONSTRAINT IF EXISTS stock_unit_warranty_months_positive,
+    DROP COLUMN IF EXISTS warranty_end,
+    DROP COLUMN IF EXISTS warranty_months,
+    DROP COLUMN IF EXISTS warranty_start,
+    DROP COLUMN IF EXISTS supplier_id;
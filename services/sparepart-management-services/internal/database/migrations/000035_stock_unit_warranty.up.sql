@@ -0,0 +1,12 @@
+-- Warranty of a stock unit: the supplier that warrants it, the day the warranty started and
+-- its length in months. RMAs of SCC and BMS units depend on proving the unit is still within
+-- its warranty window, which ends on warranty_end.
+ALTER TABLE stock_unit
+    ADD COLUMN supplier_id INTEGER REFERENCES supplier(id),
+    ADD COLUMN warranty_start DATE,
+    ADD COLUMN warranty_months INTEGER,
+    ADD COLUMN warranty_end DATE GENERATED ALWAYS AS ((warranty_start + warranty_months * INTERVAL '1 month')::date) STORED,
+    ADD CONSTRAINT stock_unit_warranty_months_positive CHECK (warranty_months > 0),
+    ADD CONSTRAINT stock_unit_warranty_complete CHECK ((warranty_start IS NULL) = (warranty_months IS NULL));
+
+CREATE INDEX idx_stock_unit_warranty_end ON stock_unit(warranty_end) WHERE warranty_end IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS damage_report;
@@ -0,0 +1,33 @@
+-- Damage reports: a quantity of a stock item found damaged or defective, with its severity, a
+-- description and photos. The disposition says what happened to the quantity when it was
+-- reported: NONE leaves it in the stock item, USED_STOCK moves it to the location's used stock
+-- and DAMAGED takes it out of the stock into the report, so it is never counted as available.
+-- A report stays OPEN until it is resolved. Reports reference the stock item, location and
+-- sparepart by ID only, so they outlive deletes and purges.
+CREATE TABLE damage_report (
+    id SERIAL PRIMARY KEY,
+    stock_item_id INTEGER NOT NULL,
+    location_id INTEGER NOT NULL,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    severity VARCHAR(20) NOT NULL CHECK (severity IN ('LOW', 'MEDIUM', 'HIGH', 'CRITICAL')),
+    description TEXT NOT NULL,
+    photos JSONB NOT NULL DEFAULT '[]',
+    disposition VARCHAR(20) NOT NULL DEFAULT 'NONE' CHECK (disposition IN ('NONE', 'USED_STOCK', 'DAMAGED')),
+    -- The USED_STOCK item the quantity was moved to
+    moved_to_stock_item_id INTEGER,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'RESOLVED')),
+    resolution TEXT,
+    reported_by VARCHAR(255),
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_damage_report_status ON damage_report(status, created_at);
+CREATE INDEX idx_damage_report_location_id ON damage_report(location_id, created_at);
+
+CREATE TRIGGER update_damage_report_updated_at BEFORE UPDATE ON damage_report
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
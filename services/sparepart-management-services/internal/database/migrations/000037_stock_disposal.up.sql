@@ -0,0 +1,22 @@
+-- Disposals (write-offs): a quantity taken out of a stock item for good, with the reason, who
+-- approved it and photos documenting it. The stock item's quantity afterwards is kept so the
+-- stock ledger row the disposal wrote can be attributed to it (see ListStockMovements). Like
+-- damage reports, disposals reference the stock item, location and sparepart by ID only, so
+-- the audit trail outlives deletes and purges.
+CREATE TABLE stock_disposal (
+    id SERIAL PRIMARY KEY,
+    stock_item_id INTEGER NOT NULL,
+    location_id INTEGER NOT NULL,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    quantity_after INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    approved_by VARCHAR(255) NOT NULL,
+    photos JSONB NOT NULL DEFAULT '[]',
+    disposed_by VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_disposal_created_at ON stock_disposal(created_at);
+CREATE INDEX idx_stock_disposal_stock_item_id ON stock_disposal(stock_item_id, created_at);
@@ -0,0 +1,11 @@
+-- PostgreSQL cannot drop values from an enum, so DAMAGED, IN_REPAIR and RESERVED stay in
+-- stock_type. Refuse to roll back while stock items still use them, since the code before this
+-- migration only knows NEW_STOCK and USED_STOCK.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM sparepart_stock_item WHERE stock_type::text NOT IN ('NEW_STOCK', 'USED_STOCK')
+    ) THEN
+        RAISE EXCEPTION 'stock items in the DAMAGED, IN_REPAIR or RESERVED state must be moved before rolling back';
+    END IF;
+END $$;
@@ -0,0 +1,9 @@
+-- Stock conditions beyond new and used: DAMAGED stock waits for a repair or disposal,
+-- IN_REPAIR stock is out for repair and RESERVED stock is set aside, e.g. for a planned site
+-- visit. Only NEW_STOCK and USED_STOCK count as available stock; the other states are still on
+-- hand, so they show up in totals per stock type but not in low stock checks. Damage reports
+-- with the DAMAGED disposition move the quantity to the location's DAMAGED stock item from now
+-- on instead of taking it out of the stock.
+ALTER TYPE stock_type ADD VALUE IF NOT EXISTS 'DAMAGED';
+ALTER TYPE stock_type ADD VALUE IF NOT EXISTS 'IN_REPAIR';
+ALTER TYPE stock_type ADD VALUE IF NOT EXISTS 'RESERVED';
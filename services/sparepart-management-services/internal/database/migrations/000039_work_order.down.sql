@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS work_order_tool;
+DROP TABLE IF EXISTS work_order_item;
+DROP TABLE IF EXISTS work_order;
@@ -0,0 +1,50 @@
+-- Work orders: maintenance tickets for field activity at a site. The spareparts used on the
+-- job and the tools taken along are attached while the ticket is OPEN; closing it takes the
+-- spareparts out of the site's stock, which the stock ledger records like any other quantity
+-- change. The stock item and its quantity afterwards are kept on each item so the ledger rows
+-- can be attributed to the work order (see ListStockMovements). Work orders reference
+-- locations, spareparts and tools by ID only, so they outlive deletes; 000051 adds the
+-- foreign keys.
+CREATE TABLE work_order (
+    id SERIAL PRIMARY KEY,
+    location_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    technician VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'CLOSED', 'CANCELLED')),
+    resolution TEXT,
+    created_by VARCHAR(255),
+    closed_by VARCHAR(255),
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_work_order_location_id ON work_order(location_id, created_at);
+CREATE INDEX idx_work_order_status ON work_order(status, created_at);
+
+CREATE TRIGGER update_work_order_updated_at BEFORE UPDATE ON work_order
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE work_order_item (
+    id SERIAL PRIMARY KEY,
+    work_order_id INTEGER NOT NULL REFERENCES work_order(id) ON DELETE CASCADE,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    -- Set on closing: the stock item the quantity was taken from and its quantity after
+    stock_item_id INTEGER,
+    quantity_after INTEGER,
+    CONSTRAINT unique_work_order_item UNIQUE (work_order_id, sparepart_id, stock_type)
+);
+
+CREATE INDEX idx_work_order_item_stock_item_id ON work_order_item(stock_item_id) WHERE stock_item_id IS NOT NULL;
+
+-- Tools used on the job; they are not consumed, so closing leaves the tools alker stock alone
+CREATE TABLE work_order_tool (
+    id SERIAL PRIMARY KEY,
+    work_order_id INTEGER NOT NULL REFERENCES work_order(id) ON DELETE CASCADE,
+    tools_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    CONSTRAINT unique_work_order_tool UNIQUE (work_order_id, tools_id)
+);
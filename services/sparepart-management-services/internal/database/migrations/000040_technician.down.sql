@@ -0,0 +1,4 @@
+ALTER TABLE sparepart_request DROP COLUMN IF EXISTS technician_id;
+ALTER TABLE work_order DROP COLUMN IF EXISTS technician_id;
+ALTER TABLE tools_alker_checkout DROP COLUMN IF EXISTS technician_id;
+DROP TABLE IF EXISTS technician;
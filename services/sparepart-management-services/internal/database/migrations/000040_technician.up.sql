@@ -0,0 +1,47 @@
+-- Technicians of the field teams, with the regions they cover. Tools alker checkouts, work
+-- orders and sparepart requests can name a technician from this directory; checkouts and
+-- work orders keep the technician's name as well, so their history reads the same after a
+-- rename. A technician still referenced cannot be deleted.
+CREATE TABLE technician (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50),
+    regions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_technician_name UNIQUE (name),
+    CONSTRAINT technician_name_not_blank CHECK (btrim(name) <> ''),
+    CONSTRAINT technician_regions_valid CHECK (regions <@ enum_range(NULL::region_type)::text[])
+);
+
+CREATE INDEX idx_technician_regions ON technician USING GIN (regions);
+
+CREATE TRIGGER update_technician_updated_at BEFORE UPDATE ON technician
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Every technician named by a checkout or work order becomes a technician row
+INSERT INTO technician (name)
+SELECT technician FROM tools_alker_checkout WHERE btrim(technician) <> ''
+UNION
+SELECT technician FROM work_order WHERE btrim(technician) <> '';
+
+ALTER TABLE tools_alker_checkout
+    ADD COLUMN technician_id INTEGER CONSTRAINT tools_alker_checkout_technician_id_fkey REFERENCES technician(id);
+ALTER TABLE work_order
+    ADD COLUMN technician_id INTEGER CONSTRAINT work_order_technician_id_fkey REFERENCES technician(id);
+ALTER TABLE sparepart_request
+    ADD COLUMN technician_id INTEGER CONSTRAINT sparepart_request_technician_id_fkey REFERENCES technician(id);
+
+UPDATE tools_alker_checkout tac
+SET technician_id = t.id
+FROM technician t
+WHERE t.name = tac.technician;
+
+UPDATE work_order wo
+SET technician_id = t.id
+FROM technician t
+WHERE t.name = wo.technician;
+
+CREATE INDEX idx_tools_alker_checkout_technician_id ON tools_alker_checkout(technician_id) WHERE technician_id IS NOT NULL;
+CREATE INDEX idx_work_order_technician_id ON work_order(technician_id, created_at) WHERE technician_id IS NOT NULL;
+CREATE INDEX idx_sparepart_request_technician_id ON sparepart_request(technician_id, created_at) WHERE technician_id IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE list_sparepart
+    DROP CONSTRAINT IF EXISTS list_sparepart_unit_valid,
+    DROP COLUMN IF EXISTS unit;
@@ -0,0 +1,5 @@
+-- Unit of measure of a master item: most are counted in pieces, cable in meters and kits in
+-- sets. Every quantity of the item (stock, transfers, requests, ...) is in this unit.
+ALTER TABLE list_sparepart
+    ADD COLUMN unit VARCHAR(10) NOT NULL DEFAULT 'PCS',
+    ADD CONSTRAINT list_sparepart_unit_valid CHECK (unit IN ('PCS', 'METER', 'SET'));
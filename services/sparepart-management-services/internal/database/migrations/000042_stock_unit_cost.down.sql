@@ -0,0 +1,11 @@
+ALTER TABLE goods_receipt_item
+    DROP CONSTRAINT IF EXISTS goods_receipt_item_unit_cost_valid,
+    DROP COLUMN IF EXISTS unit_cost;
+
+ALTER TABLE sparepart_stock_item
+    DROP CONSTRAINT IF EXISTS sparepart_stock_item_unit_cost_valid,
+    DROP COLUMN IF EXISTS unit_cost;
+
+ALTER TABLE list_sparepart
+    DROP CONSTRAINT IF EXISTS list_sparepart_unit_cost_valid,
+    DROP COLUMN IF EXISTS unit_cost;
@@ -0,0 +1,14 @@
+-- Cost price of one unit of an item, for valuing the inventory. The master holds the default
+-- cost; a stock item holds the average cost of what its location received, which takes
+-- precedence. A goods receipt item records the cost the shipment was bought at.
+ALTER TABLE list_sparepart
+    ADD COLUMN unit_cost NUMERIC(14, 2),
+    ADD CONSTRAINT list_sparepart_unit_cost_valid CHECK (unit_cost >= 0 AND unit_cost <> 'NaN');
+
+ALTER TABLE sparepart_stock_item
+    ADD COLUMN unit_cost NUMERIC(14, 2),
+    ADD CONSTRAINT sparepart_stock_item_unit_cost_valid CHECK (unit_cost >= 0 AND unit_cost <> 'NaN');
+
+ALTER TABLE goods_receipt_item
+    ADD COLUMN unit_cost NUMERIC(14, 2),
+    ADD CONSTRAINT goods_receipt_item_unit_cost_valid CHECK (unit_cost >= 0 AND unit_cost <> 'NaN');
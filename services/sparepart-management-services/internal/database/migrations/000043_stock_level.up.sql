@@ -0,0 +1,21 @@
+-- Minimum and maximum quantity of a sparepart to keep at a location, for planning the
+-- replenishment of each location on its own: unlike the global low stock threshold, a small
+-- site with few technicians can keep less than a busy one. Levels count the available stock
+-- (NEW_STOCK and USED_STOCK); without a maximum the stock is never above it.
+CREATE TABLE stock_level (
+    id SERIAL PRIMARY KEY,
+    location_id INTEGER NOT NULL REFERENCES location(id) ON DELETE CASCADE,
+    sparepart_id INTEGER NOT NULL REFERENCES list_sparepart(id) ON DELETE CASCADE,
+    min_quantity INTEGER NOT NULL,
+    max_quantity INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_stock_level UNIQUE (location_id, sparepart_id),
+    CONSTRAINT stock_level_min_non_negative CHECK (min_quantity >= 0),
+    CONSTRAINT stock_level_max_not_below_min CHECK (max_quantity >= min_quantity)
+);
+
+CREATE INDEX idx_stock_level_sparepart_id ON stock_level(sparepart_id);
+
+CREATE TRIGGER update_stock_level_updated_at BEFORE UPDATE ON stock_level
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
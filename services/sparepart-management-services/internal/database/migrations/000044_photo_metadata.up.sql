@@ -0,0 +1,53 @@
+-- Photos JSONB arrays (documentation, damage report, disposal and packing list photos) now
+-- hold {url, caption, taken_at, uploaded_by} objects; arrays written before are plain paths
+-- and stay as they are, both forms are read. The photo path of an entry of either form:
+CREATE OR REPLACE FUNCTION photo_url(photo JSONB)
+RETURNS TEXT AS $$
+    SELECT CASE jsonb_typeof(photo) WHEN 'string' THEN photo #>> '{}' ELSE photo ->> 'url' END;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Stock item timeline (see 000030): photo events compare photo paths, so converting a path to
+-- an object or editing a caption is not an added and removed photo, and report the path
+CREATE OR REPLACE FUNCTION stock_history_events(item_id INTEGER)
+RETURNS TABLE (
+    change_id BIGINT,
+    seq INTEGER,
+    event TEXT,
+    old_value JSONB,
+    new_value JSONB,
+    actor VARCHAR(255),
+    changed_at TIMESTAMPTZ
+) AS $$
+    SELECT ch.id, e.seq, e.event, e.old_value, e.new_value, ch.actor, ch.changed_at
+    FROM change_history ch
+    CROSS JOIN LATERAL (
+        SELECT
+            ARRAY(SELECT photo_url(p) FROM jsonb_array_elements(COALESCE(NULLIF(ch.changes -> 'documentation' -> 'old', 'null'), '[]')) p) AS old_urls,
+            ARRAY(SELECT photo_url(p) FROM jsonb_array_elements(COALESCE(NULLIF(ch.changes -> 'documentation' -> 'new', 'null'), '[]')) p) AS new_urls
+    ) docs
+    CROSS JOIN LATERAL (
+        SELECT 1 AS seq, 'CREATED' AS event, NULL::jsonb AS old_value, ch.changes -> 'quantity' -> 'new' AS new_value
+        WHERE ch.operation = 'INSERT'
+        UNION ALL
+        SELECT 2, 'QUANTITY_CHANGED', ch.changes -> 'quantity' -> 'old', ch.changes -> 'quantity' -> 'new'
+        WHERE ch.operation = 'UPDATE' AND ch.changes ? 'quantity'
+        UNION ALL
+        SELECT 3, 'PHOTO_ADDED', NULL, to_jsonb(url)
+        FROM unnest(docs.new_urls) url
+        WHERE ch.operation <> 'DELETE' AND NOT url = ANY(docs.old_urls)
+        UNION ALL
+        SELECT 4, 'PHOTO_REMOVED', to_jsonb(url), NULL
+        FROM unnest(docs.old_urls) url
+        WHERE ch.operation = 'UPDATE' AND NOT url = ANY(docs.new_urls)
+        UNION ALL
+        SELECT 5, 'NOTES_EDITED', ch.changes -> 'notes' -> 'old', ch.changes -> 'notes' -> 'new'
+        WHERE ch.operation = 'UPDATE' AND ch.changes ? 'notes'
+        UNION ALL
+        SELECT 6, CASE WHEN ch.changes -> 'deleted_at' -> 'new' = 'null' THEN 'RESTORED' ELSE 'DELETED' END, NULL, NULL
+        WHERE ch.operation = 'UPDATE' AND ch.changes ? 'deleted_at'
+        UNION ALL
+        SELECT 6, 'DELETED', ch.changes -> 'quantity' -> 'old', NULL
+        WHERE ch.operation = 'DELETE'
+    ) e
+    WHERE ch.table_name = 'sparepart_stock_item' AND ch.record_id = item_id;
+$$ LANGUAGE sql STABLE;
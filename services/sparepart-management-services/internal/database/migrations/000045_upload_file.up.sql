@@ -0,0 +1,19 @@
+-- Every stored photo with the SHA-256 of its content, so a photo that is uploaded again reuses
+-- the stored file instead of storing a copy. ref_count is the number of photo references
+-- (documentation, damage, disposal and packing list photos) to the file; the file is deleted
+-- with its last reference. Photos stored before this table have no row and keep one reference.
+CREATE TABLE upload_file (
+    path TEXT PRIMARY KEY,
+    sha256 CHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT upload_file_ref_count_non_negative CHECK (ref_count >= 0)
+);
+
+-- Not unique: two identical photos uploaded at the same time are both stored
+CREATE INDEX idx_upload_file_sha256 ON upload_file(sha256);
+
+CREATE TRIGGER update_upload_file_updated_at BEFORE UPDATE ON upload_file
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,55 @@
+-- Soft deleted rows would reappear without deleted_at, so they are removed first
+DELETE FROM contact_person WHERE deleted_at IS NOT NULL;
+DELETE FROM tools_alker_item WHERE deleted_at IS NOT NULL;
+DELETE FROM list_sparepart WHERE deleted_at IS NOT NULL;
+
+-- Enqueues stock.transferred_in for the destination's and stock.transferred_out for the
+-- source's contact persons of a transfer, and request.created for the destination's contact
+-- persons of a sparepart request
+CREATE OR REPLACE FUNCTION enqueue_contact_messages()
+RETURNS TRIGGER AS $$
+DECLARE
+    payload JSONB;
+BEGIN
+    IF TG_TABLE_NAME = 'stock_transfer' THEN
+        payload := jsonb_build_object(
+            'transfer_id', NEW.id,
+            'sparepart', (SELECT name FROM list_sparepart WHERE id = NEW.sparepart_id),
+            'stock_type', NEW.stock_type,
+            'quantity', NEW.quantity,
+            'source_cluster', (SELECT cluster FROM location WHERE id = NEW.source_location_id),
+            'destination_cluster', (SELECT cluster FROM location WHERE id = NEW.destination_location_id),
+            'notes', NEW.notes
+        );
+
+        INSERT INTO message_delivery (contact_person_id, location_id, pic, phone, event, payload)
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'stock.transferred_in', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.destination_location_id
+        UNION ALL
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'stock.transferred_out', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.source_location_id;
+    ELSE
+        payload := jsonb_build_object(
+            'request_id', NEW.id,
+            'destination_cluster', (SELECT cluster FROM location WHERE id = NEW.destination_location_id),
+            'requested_by', NEW.requested_by,
+            'notes', NEW.notes
+        );
+
+        INSERT INTO message_delivery (contact_person_id, location_id, pic, phone, event, payload)
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'request.created', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.destination_location_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP INDEX IF EXISTS idx_contact_person_deleted_at;
+DROP INDEX IF EXISTS idx_list_sparepart_deleted_at;
+DROP INDEX IF EXISTS idx_tools_alker_item_deleted_at;
+ALTER TABLE contact_person DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE list_sparepart DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE tools_alker_item DROP COLUMN IF EXISTS deleted_at;
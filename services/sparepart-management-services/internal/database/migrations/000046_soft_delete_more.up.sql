@@ -0,0 +1,55 @@
+-- Soft delete for tools alker items, sparepart masters and contact persons, as for locations
+-- and stock items: deleting sets deleted_at, restoring clears it and the admin purge removes
+-- the rows (and the photos of tools alker items) for good. The unique master name still covers
+-- soft deleted masters here; 000047 limits it to the masters that aren't deleted.
+ALTER TABLE tools_alker_item ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE list_sparepart ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE contact_person ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_tools_alker_item_deleted_at ON tools_alker_item(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_list_sparepart_deleted_at ON list_sparepart(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_contact_person_deleted_at ON contact_person(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Enqueues stock.transferred_in for the destination's and stock.transferred_out for the
+-- source's contact persons of a transfer, and request.created for the destination's contact
+-- persons of a sparepart request; soft deleted contact persons are skipped
+CREATE OR REPLACE FUNCTION enqueue_contact_messages()
+RETURNS TRIGGER AS $$
+DECLARE
+    payload JSONB;
+BEGIN
+    IF TG_TABLE_NAME = 'stock_transfer' THEN
+        payload := jsonb_build_object(
+            'transfer_id', NEW.id,
+            'sparepart', (SELECT name FROM list_sparepart WHERE id = NEW.sparepart_id),
+            'stock_type', NEW.stock_type,
+            'quantity', NEW.quantity,
+            'source_cluster', (SELECT cluster FROM location WHERE id = NEW.source_location_id),
+            'destination_cluster', (SELECT cluster FROM location WHERE id = NEW.destination_location_id),
+            'notes', NEW.notes
+        );
+
+        INSERT INTO message_delivery (contact_person_id, location_id, pic, phone, event, payload)
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'stock.transferred_in', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.destination_location_id AND cp.deleted_at IS NULL
+        UNION ALL
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'stock.transferred_out', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.source_location_id AND cp.deleted_at IS NULL;
+    ELSE
+        payload := jsonb_build_object(
+            'request_id', NEW.id,
+            'destination_cluster', (SELECT cluster FROM location WHERE id = NEW.destination_location_id),
+            'requested_by', NEW.requested_by,
+            'notes', NEW.notes
+        );
+
+        INSERT INTO message_delivery (contact_person_id, location_id, pic, phone, event, payload)
+        SELECT cp.id, cp.location_id, cp.pic, cp.phone, 'request.created', payload
+        FROM contact_person cp
+        WHERE cp.location_id = NEW.destination_location_id AND cp.deleted_at IS NULL;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
@@ -0,0 +1,45 @@
+-- A soft deleted row sharing its key with another row would break the full constraints, so
+-- it is removed first (items with their location or master); the live or latest row is kept
+DELETE FROM sparepart_stock_item d
+WHERE d.deleted_at IS NOT NULL AND EXISTS (
+    SELECT 1 FROM sparepart_stock_item o
+    WHERE o.id <> d.id
+      AND o.location_id = d.location_id AND o.sparepart_id = d.sparepart_id AND o.stock_type = d.stock_type
+      AND (o.deleted_at IS NULL OR o.id > d.id)
+);
+
+DELETE FROM tools_alker_item d
+WHERE d.deleted_at IS NOT NULL AND EXISTS (
+    SELECT 1 FROM tools_alker_item o
+    WHERE o.id <> d.id
+      AND o.location_id = d.location_id AND o.tools_id = d.tools_id
+      AND (o.deleted_at IS NULL OR o.id > d.id)
+);
+
+DELETE FROM location d
+WHERE d.deleted_at IS NOT NULL AND EXISTS (
+    SELECT 1 FROM location o
+    WHERE o.id <> d.id
+      AND o.region = d.region AND o.regency = d.regency AND o.cluster = d.cluster
+      AND (o.deleted_at IS NULL OR o.id > d.id)
+);
+
+DELETE FROM list_sparepart d
+WHERE d.deleted_at IS NOT NULL AND EXISTS (
+    SELECT 1 FROM list_sparepart o
+    WHERE o.id <> d.id
+      AND o.name = d.name
+      AND (o.deleted_at IS NULL OR o.id > d.id)
+);
+
+DROP INDEX IF EXISTS list_sparepart_name_key;
+ALTER TABLE list_sparepart ADD CONSTRAINT list_sparepart_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS unique_tools_alker;
+ALTER TABLE tools_alker_item ADD CONSTRAINT unique_tools_alker UNIQUE (location_id, tools_id);
+
+DROP INDEX IF EXISTS unique_sparepart_stock;
+ALTER TABLE sparepart_stock_item ADD CONSTRAINT unique_sparepart_stock UNIQUE (location_id, sparepart_id, stock_type);
+
+DROP INDEX IF EXISTS unique_location;
+ALTER TABLE location ADD CONSTRAINT unique_location UNIQUE (region, regency, cluster);
@@ -0,0 +1,16 @@
+-- Soft deleted rows no longer hold their unique key: a deleted location, stock item, tools
+-- alker item or sparepart master can be created again right away. The keys become partial
+-- unique indexes over the rows that aren't deleted, under the names of the constraints they
+-- replace so violations keep their messages. Restoring a row whose key was taken meanwhile
+-- fails as a duplicate.
+ALTER TABLE location DROP CONSTRAINT unique_location;
+CREATE UNIQUE INDEX unique_location ON location(region, regency, cluster) WHERE deleted_at IS NULL;
+
+ALTER TABLE sparepart_stock_item DROP CONSTRAINT unique_sparepart_stock;
+CREATE UNIQUE INDEX unique_sparepart_stock ON sparepart_stock_item(location_id, sparepart_id, stock_type) WHERE deleted_at IS NULL;
+
+ALTER TABLE tools_alker_item DROP CONSTRAINT unique_tools_alker;
+CREATE UNIQUE INDEX unique_tools_alker ON tools_alker_item(location_id, tools_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE list_sparepart DROP CONSTRAINT list_sparepart_name_key;
+CREATE UNIQUE INDEX list_sparepart_name_key ON list_sparepart(name) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE stock_opname_item DROP CONSTRAINT IF EXISTS stock_opname_item_sparepart_id_fkey;
+ALTER TABLE stock_opname DROP CONSTRAINT IF EXISTS stock_opname_location_id_fkey;
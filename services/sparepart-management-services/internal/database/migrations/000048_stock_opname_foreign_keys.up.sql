@@ -0,0 +1,11 @@
+-- Stock opname sessions and counts must refer to a location and sparepart that exist. They
+-- are history, so the references RESTRICT deletes: the admin purge keeps soft deleted
+-- locations and masters that a session still refers to. NOT VALID leaves rows written before
+-- this migration unchecked, as the records they refer to may already have been purged.
+ALTER TABLE stock_opname
+    ADD CONSTRAINT stock_opname_location_id_fkey
+    FOREIGN KEY (location_id) REFERENCES location(id) ON DELETE RESTRICT NOT VALID;
+
+ALTER TABLE stock_opname_item
+    ADD CONSTRAINT stock_opname_item_sparepart_id_fkey
+    FOREIGN KEY (sparepart_id) REFERENCES list_sparepart(id) ON DELETE RESTRICT NOT VALID;
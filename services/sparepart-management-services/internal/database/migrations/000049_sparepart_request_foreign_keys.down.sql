@@ -0,0 +1,4 @@
+ALTER TABLE sparepart_request_item DROP CONSTRAINT IF EXISTS sparepart_request_item_sparepart_id_fkey;
+ALTER TABLE sparepart_request
+    DROP CONSTRAINT IF EXISTS sparepart_request_source_location_id_fkey,
+    DROP CONSTRAINT IF EXISTS sparepart_request_destination_location_id_fkey;
@@ -0,0 +1,13 @@
+-- Sparepart requests and their items must refer to locations and spareparts that exist,
+-- restricting deletes as for stock opnames (000048): the admin purge keeps soft deleted
+-- locations and masters that a request still refers to. Rows written before this migration
+-- stay unchecked (NOT VALID).
+ALTER TABLE sparepart_request
+    ADD CONSTRAINT sparepart_request_destination_location_id_fkey
+    FOREIGN KEY (destination_location_id) REFERENCES location(id) ON DELETE RESTRICT NOT VALID,
+    ADD CONSTRAINT sparepart_request_source_location_id_fkey
+    FOREIGN KEY (source_location_id) REFERENCES location(id) ON DELETE RESTRICT NOT VALID;
+
+ALTER TABLE sparepart_request_item
+    ADD CONSTRAINT sparepart_request_item_sparepart_id_fkey
+    FOREIGN KEY (sparepart_id) REFERENCES list_sparepart(id) ON DELETE RESTRICT NOT VALID;
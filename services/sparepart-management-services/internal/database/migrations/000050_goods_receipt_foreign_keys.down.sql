@@ -0,0 +1,2 @@
+ALTER TABLE goods_receipt_item DROP CONSTRAINT IF EXISTS goods_receipt_item_sparepart_id_fkey;
+ALTER TABLE goods_receipt DROP CONSTRAINT IF EXISTS goods_receipt_location_id_fkey;
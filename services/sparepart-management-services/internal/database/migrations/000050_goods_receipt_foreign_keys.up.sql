@@ -0,0 +1,11 @@
+-- Goods receipts and their items must refer to a location and spareparts that exist,
+-- restricting deletes as for stock opnames (000048): the admin purge keeps soft deleted
+-- locations and masters that a receipt still refers to. Rows written before this migration
+-- stay unchecked (NOT VALID).
+ALTER TABLE goods_receipt
+    ADD CONSTRAINT goods_receipt_location_id_fkey
+    FOREIGN KEY (location_id) REFERENCES location(id) ON DELETE RESTRICT NOT VALID;
+
+ALTER TABLE goods_receipt_item
+    ADD CONSTRAINT goods_receipt_item_sparepart_id_fkey
+    FOREIGN KEY (sparepart_id) REFERENCES list_sparepart(id) ON DELETE RESTRICT NOT VALID;
@@ -0,0 +1,3 @@
+ALTER TABLE work_order_tool DROP CONSTRAINT IF EXISTS work_order_tool_tools_id_fkey;
+ALTER TABLE work_order_item DROP CONSTRAINT IF EXISTS work_order_item_sparepart_id_fkey;
+ALTER TABLE work_order DROP CONSTRAINT IF EXISTS work_order_location_id_fkey;
@@ -0,0 +1,15 @@
+-- Work orders, their spareparts and their tools must refer to a location and masters that
+-- exist, restricting deletes as for stock opnames (000048): the admin purge keeps soft deleted
+-- locations and masters that a work order still refers to. Rows written before this
+-- migration stay unchecked (NOT VALID).
+ALTER TABLE work_order
+    ADD CONSTRAINT work_order_location_id_fkey
+    FOREIGN KEY (location_id) REFERENCES location(id) ON DELETE RESTRICT NOT VALID;
+
+ALTER TABLE work_order_item
+    ADD CONSTRAINT work_order_item_sparepart_id_fkey
+    FOREIGN KEY (sparepart_id) REFERENCES list_sparepart(id) ON DELETE RESTRICT NOT VALID;
+
+ALTER TABLE work_order_tool
+    ADD CONSTRAINT work_order_tool_tools_id_fkey
+    FOREIGN KEY (tools_id) REFERENCES list_sparepart(id) ON DELETE RESTRICT NOT VALID;
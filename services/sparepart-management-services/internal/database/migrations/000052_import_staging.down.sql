@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sparepart_master_import;
+DROP TABLE IF EXISTS sparepart_stock_import;
@@ -0,0 +1,25 @@
+-- Staging tables of the spreadsheet imports. An import loads its rows here with COPY and
+-- moves them into the real table with one validating INSERT ... SELECT, in one transaction,
+-- so the rows never outlive it; import_tx keeps every import to its own rows all the same.
+-- UNLOGGED, since nothing here has to survive a crash.
+CREATE UNLOGGED TABLE sparepart_stock_import (
+    import_tx BIGINT NOT NULL DEFAULT txid_current(),
+    line INTEGER NOT NULL,
+    location_id INTEGER NOT NULL,
+    sparepart_id INTEGER NOT NULL,
+    stock_type stock_type NOT NULL,
+    quantity INTEGER NOT NULL,
+    notes TEXT NOT NULL
+);
+
+CREATE INDEX idx_sparepart_stock_import_tx ON sparepart_stock_import(import_tx);
+
+CREATE UNLOGGED TABLE sparepart_master_import (
+    import_tx BIGINT NOT NULL DEFAULT txid_current(),
+    line INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    item_type item_type NOT NULL,
+    unit VARCHAR(10) NOT NULL
+);
+
+CREATE INDEX idx_sparepart_master_import_tx ON sparepart_master_import(import_tx);
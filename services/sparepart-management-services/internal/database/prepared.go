@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// hotQueries are the list and count queries behind the paginated GET endpoints.
+// They run on every page load, so they are always executed as prepared statements.
+var hotQueries = map[string]bool{
+	"ListSparepartStocks":      true,
+	"CountSparepartStocks":     true,
+	"ListSparepartStockItems":  true,
+	"CountSparepartStockItems": true,
+	"ListToolsAlkers":          true,
+	"CountToolsAlkers":         true,
+	"ListToolsAlkerItems":      true,
+	"CountToolsAlkerItems":     true,
+	"ListLocations":            true,
+	"CountLocations":           true,
+	"ListContactPersons":       true,
+	"CountContactPersons":      true,
+}
+
+// PreparedQueryDB wraps the pool handed to sqlc so the hot queries use a cached prepared
+// statement even when the pool's default mode is "describe". In simple protocol mode
+// nothing is prepared, since the connection may not keep statements between queries.
+type PreparedQueryDB struct {
+	*pgxpool.Pool
+	prepare bool
+}
+
+func NewPreparedQueryDB(pool *pgxpool.Pool) *PreparedQueryDB {
+	return &PreparedQueryDB{
+		Pool:    pool,
+		prepare: pool.Config().ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeSimpleProtocol,
+	}
+}
+
+func (db *PreparedQueryDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return db.Pool.Query(ctx, sql, db.withExecMode(sql, args)...)
+}
+
+func (db *PreparedQueryDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return db.Pool.QueryRow(ctx, sql, db.withExecMode(sql, args)...)
+}
+
+// withExecMode prepends pgx.QueryExecModeCacheStatement to the arguments of a hot query
+func (db *PreparedQueryDB) withExecMode(sql string, args []any) []any {
+	if !db.prepare || !hotQueries[queryName(sql)] {
+		return args
+	}
+	return append([]any{pgx.QueryExecModeCacheStatement}, args...)
+}
+
+// queryName returns the name from the "-- name: X :kind" header sqlc puts on every query
+func queryName(sql string) string {
+	rest, ok := strings.CutPrefix(sql, "-- name: ")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	return name
+}
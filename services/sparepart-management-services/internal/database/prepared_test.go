@@ -0,0 +1,36 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestPreparedQueryDBWithExecMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		prepare  bool
+		sql      string
+		wantMode bool
+	}{
+		{name: "hot query", prepare: true, sql: "-- name: ListSparepartStocks :many\nSELECT 1", wantMode: true},
+		{name: "other query", prepare: true, sql: "-- name: GetSparepartStock :one\nSELECT 1"},
+		{name: "no header", prepare: true, sql: "SELECT 1"},
+		{name: "simple protocol", prepare: false, sql: "-- name: ListSparepartStocks :many\nSELECT 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &PreparedQueryDB{prepare: tt.prepare}
+			args := db.withExecMode(tt.sql, []any{int32(10)})
+
+			gotMode := len(args) == 2 && args[0] == pgx.QueryExecModeCacheStatement
+			if gotMode != tt.wantMode {
+				t.Fatalf("expected exec mode prepended = %v, got args %v", tt.wantMode, args)
+			}
+			if args[len(args)-1] != int32(10) {
+				t.Fatalf("query arguments not preserved: %v", args)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+-- name: GetAlertRule :one
+SELECT * FROM alert_rule
+WHERE id = $1 LIMIT 1;
+
+-- name: ListAlertRules :many
+SELECT * FROM alert_rule
+WHERE (sqlc.narg('enabled')::boolean IS NULL OR enabled = sqlc.narg('enabled')::boolean)
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountAlertRules :one
+SELECT COUNT(*) FROM alert_rule
+WHERE (sqlc.narg('enabled')::boolean IS NULL OR enabled = sqlc.narg('enabled')::boolean);
+
+-- name: ListEnabledAlertRules :many
+SELECT * FROM alert_rule
+WHERE enabled = true
+ORDER BY id;
+
+-- name: CreateAlertRule :one
+INSERT INTO alert_rule (name, region, location_id, sparepart_id, stock_type, operator, threshold, channel, recipients, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: UpdateAlertRule :one
+UPDATE alert_rule
+SET name = $2, region = $3, location_id = $4, sparepart_id = $5, stock_type = $6,
+    operator = $7, threshold = $8, channel = $9, recipients = $10, enabled = $11
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteAlertRule :exec
+DELETE FROM alert_rule
+WHERE id = $1;
+
+-- name: ListAlertRuleQuantities :many
+-- Quantity in scope of a rule per location; locations in scope without matching items count as 0.
+-- Rules without a stock type count the available stock, NEW_STOCK and USED_STOCK.
+SELECT
+    l.id AS location_id, l.region, l.regency, l.cluster,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS quantity
+FROM location l
+LEFT JOIN sparepart_stock_item ssi ON ssi.location_id = l.id
+    AND ssi.deleted_at IS NULL
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR ssi.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (
+        (sqlc.narg('stock_type')::stock_type IS NULL AND ssi.stock_type IN ('NEW_STOCK', 'USED_STOCK'))
+        OR ssi.stock_type = sqlc.narg('stock_type')::stock_type
+    )
+WHERE
+    l.deleted_at IS NULL
+    AND (sqlc.narg('region')::region_type IS NULL OR l.region = sqlc.narg('region')::region_type)
+    AND (sqlc.narg('location_id')::int IS NULL OR l.id = sqlc.narg('location_id')::int)
+GROUP BY l.id
+ORDER BY l.region, l.regency, l.cluster;
+
+-- name: ListAlertRuleFirings :many
+SELECT * FROM alert_rule_firing
+WHERE rule_id = $1;
+
+-- name: CreateAlertRuleFiring :exec
+INSERT INTO alert_rule_firing (rule_id, location_id, quantity)
+VALUES ($1, $2, $3)
+ON CONFLICT (rule_id, location_id) DO NOTHING;
+
+-- name: DeleteAlertRuleFiring :exec
+DELETE FROM alert_rule_firing
+WHERE rule_id = $1 AND location_id = $2;
@@ -0,0 +1,117 @@
+-- name: DetectQuantityDrops :execrows
+-- Stock items whose quantity dropped by at least min_drop_percent within one (UTC) day
+INSERT INTO anomaly (kind, fingerprint, table_name, record_id, details)
+SELECT
+    'QUANTITY_DROP',
+    'QUANTITY_DROP:' || d.stock_item_id || ':' || d.day,
+    'sparepart_stock_item',
+    d.stock_item_id,
+    jsonb_build_object(
+        'day', d.day,
+        'location_id', d.location_id,
+        'sparepart_id', d.sparepart_id,
+        'quantity_before', d.quantity_before,
+        'quantity_after', d.quantity_after
+    )
+FROM (
+    SELECT
+        sl.stock_item_id,
+        (sl.recorded_at AT TIME ZONE 'UTC')::date AS day,
+        (array_agg(sl.location_id ORDER BY sl.id DESC))[1] AS location_id,
+        (array_agg(sl.sparepart_id ORDER BY sl.id DESC))[1] AS sparepart_id,
+        (array_agg(sl.quantity_after - sl.quantity_change ORDER BY sl.id))[1] AS quantity_before,
+        (array_agg(sl.quantity_after ORDER BY sl.id DESC))[1] AS quantity_after
+    FROM stock_ledger sl
+    WHERE sl.recorded_at >= sqlc.arg('since')::timestamptz
+    GROUP BY sl.stock_item_id, day
+) d
+WHERE d.quantity_before > 0
+    AND (d.quantity_before - d.quantity_after) * 100 >= d.quantity_before * sqlc.arg('min_drop_percent')::int
+ON CONFLICT (fingerprint) DO UPDATE SET details = EXCLUDED.details
+WHERE anomaly.status = 'OPEN' AND anomaly.details IS DISTINCT FROM EXCLUDED.details;
+
+-- name: DetectRepeatedDeletes :execrows
+-- Actors that deleted at least min_deletes records within one (UTC) day; soft deletes count,
+-- purging an already deleted record does not
+INSERT INTO anomaly (kind, fingerprint, actor, details)
+SELECT
+    'REPEATED_DELETES',
+    'REPEATED_DELETES:' || d.actor || ':' || d.day,
+    d.actor,
+    jsonb_build_object('day', d.day, 'deletes', d.deletes, 'tables', d.tables)
+FROM (
+    SELECT
+        ch.actor,
+        (ch.changed_at AT TIME ZONE 'UTC')::date AS day,
+        COUNT(*) AS deletes,
+        jsonb_agg(DISTINCT ch.table_name) AS tables
+    FROM change_history ch
+    WHERE (ch.operation = 'DELETE' OR ch.changes -> 'deleted_at' ->> 'new' IS NOT NULL)
+        AND ch.changes -> 'deleted_at' ->> 'old' IS NULL
+        AND ch.actor IS NOT NULL
+        AND ch.changed_at >= sqlc.arg('since')::timestamptz
+    GROUP BY ch.actor, day
+    HAVING COUNT(*) >= sqlc.arg('min_deletes')::int
+) d
+ON CONFLICT (fingerprint) DO UPDATE SET details = EXCLUDED.details
+WHERE anomaly.status = 'OPEN' AND anomaly.details IS DISTINCT FROM EXCLUDED.details;
+
+-- name: DetectPhotoRemovals :execrows
+-- Updates that left a record with fewer photos than before, i.e. photos removed without replacement
+INSERT INTO anomaly (kind, fingerprint, table_name, record_id, actor, details)
+SELECT
+    'PHOTOS_REMOVED',
+    'PHOTOS_REMOVED:' || ch.id,
+    ch.table_name,
+    ch.record_id,
+    ch.actor,
+    jsonb_build_object(
+        'change_id', ch.id,
+        'photos_before', jsonb_array_length(ch.changes -> 'documentation' -> 'old'),
+        'photos_after', jsonb_array_length(ch.changes -> 'documentation' -> 'new')
+    )
+FROM change_history ch
+WHERE ch.operation = 'UPDATE'
+    AND ch.changed_at >= sqlc.arg('since')::timestamptz
+    AND jsonb_typeof(ch.changes -> 'documentation' -> 'old') = 'array'
+    AND jsonb_typeof(ch.changes -> 'documentation' -> 'new') = 'array'
+    AND jsonb_array_length(ch.changes -> 'documentation' -> 'new') < jsonb_array_length(ch.changes -> 'documentation' -> 'old')
+ON CONFLICT (fingerprint) DO NOTHING;
+
+-- name: GetAnomaly :one
+SELECT * FROM anomaly
+WHERE id = $1 LIMIT 1;
+
+-- name: ListAnomalies :many
+SELECT * FROM anomaly
+WHERE
+    (sqlc.narg('status')::text IS NULL OR status = UPPER(sqlc.narg('status')::text))
+    AND (sqlc.narg('kind')::text IS NULL OR kind = UPPER(sqlc.narg('kind')::text))
+ORDER BY detected_at DESC, id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountAnomalies :one
+SELECT COUNT(*) FROM anomaly
+WHERE
+    (sqlc.narg('status')::text IS NULL OR status = UPPER(sqlc.narg('status')::text))
+    AND (sqlc.narg('kind')::text IS NULL OR kind = UPPER(sqlc.narg('kind')::text));
+
+-- name: AcknowledgeAnomaly :one
+UPDATE anomaly
+SET
+    status = 'ACKNOWLEDGED',
+    acknowledged_at = CURRENT_TIMESTAMP,
+    note = COALESCE(sqlc.narg('note'), note)
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: ResolveAnomaly :one
+UPDATE anomaly
+SET
+    status = 'RESOLVED',
+    acknowledged_at = COALESCE(acknowledged_at, CURRENT_TIMESTAMP),
+    resolved_at = CURRENT_TIMESTAMP,
+    note = COALESCE(sqlc.narg('note'), note)
+WHERE id = sqlc.arg('id')
+RETURNING *;
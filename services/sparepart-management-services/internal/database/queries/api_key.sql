@@ -0,0 +1,44 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_key (name, key_prefix, key_hash, scopes, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetAPIKey :one
+SELECT * FROM api_key
+WHERE id = $1 LIMIT 1;
+
+-- name: GetActiveAPIKeyByHash :one
+-- Returns no row for unknown, revoked and expired keys
+SELECT * FROM api_key
+WHERE key_hash = $1
+    AND revoked_at IS NULL
+    AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+LIMIT 1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_key
+WHERE
+    sqlc.narg('active')::boolean IS NULL
+    OR (revoked_at IS NULL AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)) = sqlc.narg('active')::boolean
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountAPIKeys :one
+SELECT COUNT(*) FROM api_key
+WHERE
+    sqlc.narg('active')::boolean IS NULL
+    OR (revoked_at IS NULL AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)) = sqlc.narg('active')::boolean;
+
+-- name: RevokeAPIKey :one
+UPDATE api_key
+SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE id = $1
+RETURNING *;
+
+-- name: TouchAPIKey :exec
+-- Records a use; at most once a minute, so busy keys do not write on every request
+UPDATE api_key
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1
+    AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute');
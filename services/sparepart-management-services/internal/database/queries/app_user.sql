@@ -0,0 +1,17 @@
+-- name: GetAppUser :one
+SELECT * FROM app_user
+WHERE id = $1 LIMIT 1;
+
+-- name: GetAppUserByUsername :one
+SELECT * FROM app_user
+WHERE username = $1 LIMIT 1;
+
+-- name: CreateAppUser :one
+INSERT INTO app_user (username, password_hash, role)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: RecordAppUserLogin :exec
+UPDATE app_user
+SET last_login_at = CURRENT_TIMESTAMP
+WHERE id = $1;
@@ -0,0 +1,28 @@
+-- name: ListRecordChanges :many
+SELECT id, operation, changes, actor, changed_at
+FROM change_history
+WHERE table_name = sqlc.arg('table_name') AND record_id = sqlc.arg('record_id')
+ORDER BY changed_at DESC, id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountRecordChanges :one
+SELECT COUNT(*) FROM change_history
+WHERE table_name = sqlc.arg('table_name') AND record_id = sqlc.arg('record_id');
+
+-- name: ListStockHistory :many
+-- Timeline of a stock item, oldest first (see stock_history_events, migration 000030)
+SELECT change_id, event, old_value, new_value, actor, changed_at
+FROM stock_history_events(sqlc.arg('stock_item_id')::int)
+WHERE (sqlc.narg('event')::text IS NULL OR event = sqlc.narg('event'))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR changed_at >= sqlc.narg('since'))
+    AND (sqlc.narg('until')::timestamptz IS NULL OR changed_at < sqlc.narg('until'))
+ORDER BY changed_at, change_id, seq
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountStockHistory :one
+SELECT COUNT(*) FROM stock_history_events(sqlc.arg('stock_item_id')::int)
+WHERE (sqlc.narg('event')::text IS NULL OR event = sqlc.narg('event'))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR changed_at >= sqlc.narg('since'))
+    AND (sqlc.narg('until')::timestamptz IS NULL OR changed_at < sqlc.narg('until'));
@@ -0,0 +1,56 @@
+-- name: GetCluster :one
+SELECT c.id, c.region, c.regency, c.name, c.created_at, c.updated_at, r.id AS regency_id
+FROM cluster c
+JOIN regency r ON r.region = c.region AND r.name = c.regency
+WHERE c.id = $1 LIMIT 1;
+
+-- name: ListClusters :many
+SELECT c.id, c.region, c.regency, c.name, c.created_at, c.updated_at, r.id AS regency_id
+FROM cluster c
+JOIN regency r ON r.region = c.region AND r.name = c.regency
+WHERE
+    (sqlc.narg('region')::text IS NULL OR UPPER(c.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency_id')::int IS NULL OR r.id = sqlc.narg('regency_id'))
+    AND (sqlc.narg('name')::text IS NULL OR c.name ILIKE '%' || sqlc.narg('name') || '%')
+ORDER BY c.region, c.regency, c.name
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountClusters :one
+SELECT COUNT(*)
+FROM cluster c
+JOIN regency r ON r.region = c.region AND r.name = c.regency
+WHERE
+    (sqlc.narg('region')::text IS NULL OR UPPER(c.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency_id')::int IS NULL OR r.id = sqlc.narg('regency_id'))
+    AND (sqlc.narg('name')::text IS NULL OR c.name ILIKE '%' || sqlc.narg('name') || '%');
+
+-- name: CreateCluster :one
+-- Returns no row when the regency does not exist
+WITH inserted AS (
+    INSERT INTO cluster (region, regency, name)
+    SELECT r.region, r.name, sqlc.arg('name')
+    FROM regency r
+    WHERE r.id = sqlc.arg('regency_id')
+    RETURNING *
+)
+SELECT i.id, i.region, i.regency, i.name, i.created_at, i.updated_at, sqlc.arg('regency_id')::int AS regency_id
+FROM inserted i;
+
+-- name: UpdateCluster :one
+-- Renaming a cluster or moving it to another regency cascades to its locations. Returns no
+-- row when the cluster or the regency does not exist.
+WITH updated AS (
+    UPDATE cluster c
+    SET region = r.region, regency = r.name, name = sqlc.arg('name')
+    FROM regency r
+    WHERE c.id = sqlc.arg('id') AND r.id = sqlc.arg('regency_id')
+    RETURNING c.*
+)
+SELECT u.id, u.region, u.regency, u.name, u.created_at, u.updated_at, sqlc.arg('regency_id')::int AS regency_id
+FROM updated u;
+
+-- name: DeleteCluster :exec
+-- Fails with a foreign key violation while locations are still in the cluster
+DELETE FROM cluster
+WHERE id = $1;
@@ -1,37 +1,70 @@
 -- name: GetContactPerson :one
 SELECT 
-    cp.id, cp.location_id, cp.pic, cp.phone, cp.created_at, cp.updated_at,
+    cp.id, cp.location_id, cp.pic, cp.phone, cp.created_at, cp.updated_at, cp.email,
     l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at
 FROM contact_person cp
 JOIN location l ON l.id = cp.location_id
-WHERE cp.id = $1 LIMIT 1;
+WHERE cp.id = $1 AND cp.deleted_at IS NULL AND l.deleted_at IS NULL LIMIT 1;
 
 -- name: ListContactPersons :many
 SELECT 
-    cp.id, cp.location_id, cp.pic, cp.phone, cp.created_at, cp.updated_at,
+    cp.id, cp.location_id, cp.pic, cp.phone, cp.created_at, cp.updated_at, cp.email,
     l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at
 FROM contact_person cp
 JOIN location l ON l.id = cp.location_id
-WHERE ($1::int IS NULL OR $1 = 0 OR cp.location_id = $1)
+WHERE cp.deleted_at IS NULL AND l.deleted_at IS NULL
+    AND (sqlc.narg('location_id')::int IS NULL OR cp.location_id = sqlc.narg('location_id'))
 ORDER BY cp.id
-LIMIT $2
-OFFSET $3;
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
 
 -- name: CountContactPersons :one
-SELECT COUNT(*) FROM contact_person
-WHERE ($1::int IS NULL OR $1 = 0 OR location_id = $1);
+SELECT COUNT(*) FROM contact_person cp
+JOIN location l ON l.id = cp.location_id
+WHERE cp.deleted_at IS NULL AND l.deleted_at IS NULL
+    AND (sqlc.narg('location_id')::int IS NULL OR cp.location_id = sqlc.narg('location_id'));
 
 -- name: CreateContactPerson :one
-INSERT INTO contact_person (location_id, pic, phone)
-VALUES ($1, $2, $3)
+INSERT INTO contact_person (location_id, pic, phone, email)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: UpdateContactPerson :one
 UPDATE contact_person
-SET location_id = $2, pic = $3, phone = $4
+SET location_id = $2, pic = $3, phone = $4, email = $5
 WHERE id = $1
 RETURNING *;
 
+-- name: PatchContactPerson :one
+-- Only provided fields change
+UPDATE contact_person
+SET
+    location_id = COALESCE(sqlc.narg('location_id')::int, location_id),
+    pic = COALESCE(sqlc.narg('pic')::text, pic),
+    phone = COALESCE(sqlc.narg('phone')::text, phone),
+    email = COALESCE(sqlc.narg('email')::text, email)
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
 -- name: DeleteContactPerson :exec
-DELETE FROM contact_person
-WHERE id = $1;
+-- Soft delete: the contact person is kept until restored or purged
+UPDATE contact_person
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreContactPerson :one
+-- Not while the contact person's location is deleted
+UPDATE contact_person cp
+SET deleted_at = NULL
+FROM location l
+WHERE cp.id = $1
+    AND cp.deleted_at IS NOT NULL
+    AND l.id = cp.location_id
+    AND l.deleted_at IS NULL
+RETURNING cp.*;
+
+-- name: ListContactPersonsByLocations :many
+-- Contact persons of the locations of an availability response
+SELECT * FROM contact_person
+WHERE location_id = ANY(sqlc.arg('location_ids')::int[]) AND deleted_at IS NULL
+ORDER BY location_id, id;
@@ -0,0 +1,57 @@
+-- name: CreateDamageReport :one
+INSERT INTO damage_report (
+    stock_item_id, location_id, sparepart_id, stock_type, quantity, severity, description, photos,
+    disposition, moved_to_stock_item_id, reported_by
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetDamageReport :one
+SELECT dr.*, l.region, l.regency, l.cluster, ls.name AS sparepart_name
+FROM damage_report dr
+LEFT JOIN location l ON l.id = dr.location_id
+LEFT JOIN list_sparepart ls ON ls.id = dr.sparepart_id
+WHERE dr.id = $1;
+
+-- name: ListDamageReports :many
+SELECT dr.*, l.region, l.regency, l.cluster, ls.name AS sparepart_name
+FROM damage_report dr
+LEFT JOIN location l ON l.id = dr.location_id
+LEFT JOIN list_sparepart ls ON ls.id = dr.sparepart_id
+WHERE (sqlc.narg('status')::text IS NULL OR dr.status = sqlc.narg('status'))
+    AND (sqlc.narg('severity')::text IS NULL OR dr.severity = sqlc.narg('severity'))
+    AND (sqlc.narg('disposition')::text IS NULL OR dr.disposition = sqlc.narg('disposition'))
+    AND (sqlc.narg('location_id')::int IS NULL OR dr.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR dr.sparepart_id = sqlc.narg('sparepart_id')::int)
+ORDER BY dr.created_at DESC, dr.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountDamageReports :one
+SELECT COUNT(*) FROM damage_report dr
+WHERE (sqlc.narg('status')::text IS NULL OR dr.status = sqlc.narg('status'))
+    AND (sqlc.narg('severity')::text IS NULL OR dr.severity = sqlc.narg('severity'))
+    AND (sqlc.narg('disposition')::text IS NULL OR dr.disposition = sqlc.narg('disposition'))
+    AND (sqlc.narg('location_id')::int IS NULL OR dr.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR dr.sparepart_id = sqlc.narg('sparepart_id')::int);
+
+-- name: ListOpenDamageReportsForExport :many
+-- Read in keyset batches, newest first, so exports don't hold every row in memory
+SELECT dr.*, l.region, l.regency, l.cluster, ls.name AS sparepart_name
+FROM damage_report dr
+LEFT JOIN location l ON l.id = dr.location_id
+LEFT JOIN list_sparepart ls ON ls.id = dr.sparepart_id
+WHERE dr.status = 'OPEN'
+    AND (sqlc.narg('severity')::text IS NULL OR dr.severity = sqlc.narg('severity'))
+    AND (sqlc.narg('disposition')::text IS NULL OR dr.disposition = sqlc.narg('disposition'))
+    AND (sqlc.narg('location_id')::int IS NULL OR dr.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR dr.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('after_id')::int IS NULL OR dr.id < sqlc.narg('after_id')::int)
+ORDER BY dr.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ResolveDamageReport :one
+-- Returns no row when the report does not exist or is already resolved
+UPDATE damage_report
+SET status = 'RESOLVED', resolution = $2, resolved_by = $3, resolved_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'OPEN'
+RETURNING *;
@@ -0,0 +1,89 @@
+-- name: GetDashboardKPIs :one
+-- Headline numbers for the web dashboard, computed from the live tables
+SELECT
+    (SELECT COUNT(DISTINCT ssi.sparepart_id) FROM sparepart_stock_item ssi WHERE ssi.deleted_at IS NULL)::bigint AS skus_tracked,
+    (SELECT COUNT(*) FROM sparepart_stock_item ssi WHERE ssi.deleted_at IS NULL)::bigint AS stock_items,
+    (SELECT COUNT(DISTINCT ssi.location_id) FROM sparepart_stock_item ssi WHERE ssi.deleted_at IS NULL)::bigint AS locations_with_stock,
+    (SELECT COUNT(*) FROM sparepart_stock_item ssi WHERE ssi.deleted_at IS NULL AND jsonb_array_length(ssi.documentation) = 0)::bigint AS stock_items_without_photos,
+    (SELECT COUNT(*) FROM tools_alker_item tai WHERE tai.deleted_at IS NULL AND jsonb_array_length(tai.documentation) = 0)::bigint AS tools_items_without_photos;
+
+-- name: ListStockQuantityByType :many
+SELECT
+    ssi.stock_type,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS quantity
+FROM sparepart_stock_item ssi
+WHERE ssi.deleted_at IS NULL
+GROUP BY ssi.stock_type
+ORDER BY ssi.stock_type;
+
+-- name: GetStockSummaryTotals :one
+-- Item counts and quantities per stock type, and how many items have photos
+SELECT
+    COUNT(*)::bigint AS stock_items,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS quantity,
+    COUNT(*) FILTER (WHERE ssi.stock_type = 'NEW_STOCK')::bigint AS new_stock_items,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_quantity,
+    COUNT(*) FILTER (WHERE ssi.stock_type = 'USED_STOCK')::bigint AS used_stock_items,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_quantity,
+    COUNT(*) FILTER (WHERE ssi.stock_type = 'DAMAGED')::bigint AS damaged_items,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'DAMAGED'), 0)::bigint AS damaged_quantity,
+    COUNT(*) FILTER (WHERE ssi.stock_type = 'IN_REPAIR')::bigint AS in_repair_items,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'IN_REPAIR'), 0)::bigint AS in_repair_quantity,
+    COUNT(*) FILTER (WHERE ssi.stock_type = 'RESERVED')::bigint AS reserved_items,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'RESERVED'), 0)::bigint AS reserved_quantity,
+    COUNT(*) FILTER (WHERE jsonb_array_length(ssi.documentation) > 0)::bigint AS stock_items_with_photos,
+    (SELECT COUNT(*) FROM tools_alker_item tai JOIN location tl ON tl.id = tai.location_id WHERE tai.deleted_at IS NULL AND tl.deleted_at IS NULL)::bigint AS tools_items,
+    (SELECT COUNT(*) FROM tools_alker_item tai JOIN location tl ON tl.id = tai.location_id
+        WHERE tai.deleted_at IS NULL AND tl.deleted_at IS NULL AND jsonb_array_length(tai.documentation) > 0)::bigint AS tools_items_with_photos
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+WHERE ssi.deleted_at IS NULL AND l.deleted_at IS NULL;
+
+-- name: ListStockTotalsByArea :many
+-- Rolls the stock up per region, per regency and per cluster; level tells which one a row is,
+-- and the columns below its level are NULL
+SELECT
+    (CASE
+        WHEN GROUPING(l.regency) = 1 THEN 'REGION'
+        WHEN GROUPING(l.cluster) = 1 THEN 'REGENCY'
+        ELSE 'CLUSTER'
+    END)::text AS level,
+    l.region,
+    l.regency,
+    l.cluster,
+    COUNT(*)::bigint AS stock_items,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_quantity
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+WHERE ssi.deleted_at IS NULL AND l.deleted_at IS NULL
+GROUP BY ROLLUP (l.region, l.regency, l.cluster)
+HAVING GROUPING(l.region) = 0
+ORDER BY l.region, l.regency NULLS FIRST, l.cluster NULLS FIRST;
+
+-- name: ListStockTotalsByItemType :many
+SELECT
+    ls.item_type,
+    COUNT(*)::bigint AS stock_items,
+    COALESCE(SUM(ssi.quantity), 0)::bigint AS quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0)::bigint AS new_quantity,
+    COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0)::bigint AS used_quantity
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE ssi.deleted_at IS NULL AND l.deleted_at IS NULL
+GROUP BY ls.item_type
+ORDER BY ls.item_type;
+
+-- name: ListLowestQuantityStockItems :many
+SELECT
+    ssi.id, ssi.location_id, ssi.sparepart_id, ssi.stock_type, ssi.quantity,
+    l.region, l.regency, l.cluster,
+    ls.name AS sparepart_name
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE ssi.deleted_at IS NULL AND l.deleted_at IS NULL
+ORDER BY ssi.quantity, ssi.id
+LIMIT $1;
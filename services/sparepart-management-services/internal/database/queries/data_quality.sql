@@ -0,0 +1,89 @@
+-- Data quality checks for the admin cleanup report. Stock and tools alker items are
+-- scanned together and told apart by item_type. Soft deleted stock items and locations are
+-- left out until they are restored, as are soft deleted masters and contact persons.
+
+-- name: GetDataQualityCounts :one
+SELECT
+    ((SELECT COUNT(*) FROM sparepart_stock_item WHERE deleted_at IS NULL AND jsonb_array_length(documentation) = 0)
+        + (SELECT COUNT(*) FROM tools_alker_item WHERE deleted_at IS NULL AND jsonb_array_length(documentation) = 0))::bigint AS items_without_photos,
+    (SELECT COUNT(*) FROM location l
+        WHERE l.deleted_at IS NULL
+            AND NOT EXISTS (SELECT 1 FROM contact_person cp WHERE cp.location_id = l.id AND cp.deleted_at IS NULL))::bigint AS locations_without_contact,
+    (SELECT COUNT(*) FROM (
+        SELECT 1 FROM list_sparepart
+        WHERE deleted_at IS NULL
+        GROUP BY LOWER(REGEXP_REPLACE(TRIM(name), '\s+', ' ', 'g'))
+        HAVING COUNT(*) > 1
+    ) duplicates)::bigint AS duplicate_master_names,
+    ((SELECT COUNT(*) FROM sparepart_stock_item WHERE deleted_at IS NULL AND quantity = 0 AND updated_at < sqlc.arg('zero_before')::timestamptz)
+        + (SELECT COUNT(*) FROM tools_alker_item WHERE deleted_at IS NULL AND quantity = 0 AND updated_at < sqlc.arg('zero_before')::timestamptz))::bigint AS stale_zero_quantities;
+
+-- name: ListItemsWithoutPhotos :many
+SELECT * FROM (
+    SELECT 'SPAREPART'::text AS item_type, ssi.id, l.id AS location_id, l.region, l.regency, l.cluster,
+        ls.name, ssi.stock_type::text AS stock_type, ssi.quantity, ssi.updated_at
+    FROM sparepart_stock_item ssi
+    JOIN location l ON l.id = ssi.location_id
+    JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+    WHERE ssi.deleted_at IS NULL AND jsonb_array_length(ssi.documentation) = 0
+    UNION ALL
+    SELECT 'TOOLS_ALKER'::text, tai.id, l.id, l.region, l.regency, l.cluster,
+        ls.name, NULL::text, tai.quantity, tai.updated_at
+    FROM tools_alker_item tai
+    JOIN location l ON l.id = tai.location_id
+    JOIN list_sparepart ls ON ls.id = tai.tools_id
+    WHERE tai.deleted_at IS NULL AND l.deleted_at IS NULL AND jsonb_array_length(tai.documentation) = 0
+) items
+ORDER BY region, regency, cluster, name, item_type, id
+LIMIT sqlc.arg('limit');
+
+-- name: ListLocationsWithoutContact :many
+SELECT l.id, l.region, l.regency, l.cluster
+FROM location l
+WHERE l.deleted_at IS NULL
+    AND NOT EXISTS (SELECT 1 FROM contact_person cp WHERE cp.location_id = l.id AND cp.deleted_at IS NULL)
+ORDER BY l.region, l.regency, l.cluster
+LIMIT sqlc.arg('limit');
+
+-- name: ListDuplicateMasterNames :many
+-- Master names are unique as typed, so duplicates differ only in case or spacing
+SELECT
+    LOWER(REGEXP_REPLACE(TRIM(name), '\s+', ' ', 'g'))::text AS normalized_name,
+    ARRAY_AGG(id ORDER BY id)::int[] AS ids,
+    ARRAY_AGG(name ORDER BY id)::text[] AS names
+FROM list_sparepart
+WHERE deleted_at IS NULL
+GROUP BY LOWER(REGEXP_REPLACE(TRIM(name), '\s+', ' ', 'g'))
+HAVING COUNT(*) > 1
+ORDER BY normalized_name
+LIMIT sqlc.arg('limit');
+
+-- name: ListStaleZeroQuantities :many
+SELECT * FROM (
+    SELECT 'SPAREPART'::text AS item_type, ssi.id, l.id AS location_id, l.region, l.regency, l.cluster,
+        ls.name, ssi.stock_type::text AS stock_type, ssi.quantity, ssi.updated_at
+    FROM sparepart_stock_item ssi
+    JOIN location l ON l.id = ssi.location_id
+    JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+    WHERE ssi.deleted_at IS NULL AND ssi.quantity = 0 AND ssi.updated_at < sqlc.arg('zero_before')::timestamptz
+    UNION ALL
+    SELECT 'TOOLS_ALKER'::text, tai.id, l.id, l.region, l.regency, l.cluster,
+        ls.name, NULL::text, tai.quantity, tai.updated_at
+    FROM tools_alker_item tai
+    JOIN location l ON l.id = tai.location_id
+    JOIN list_sparepart ls ON ls.id = tai.tools_id
+    WHERE tai.deleted_at IS NULL AND l.deleted_at IS NULL AND tai.quantity = 0 AND tai.updated_at < sqlc.arg('zero_before')::timestamptz
+) items
+ORDER BY updated_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: ListItemDocumentation :many
+-- Every stored photo reference, checked against the uploads directory by the handler
+SELECT 'SPAREPART'::text AS item_type, ssi.id, ssi.location_id, ssi.documentation
+FROM sparepart_stock_item ssi
+WHERE jsonb_array_length(ssi.documentation) > 0
+UNION ALL
+SELECT 'TOOLS_ALKER'::text, tai.id, tai.location_id, tai.documentation
+FROM tools_alker_item tai
+WHERE jsonb_array_length(tai.documentation) > 0
+ORDER BY item_type, id;
@@ -0,0 +1,45 @@
+-- name: ListLowStockDigestItems :many
+-- Available (NEW_STOCK and USED_STOCK) stock items at or below the digest threshold, grouped
+-- by location
+SELECT
+    ssi.id,
+    ssi.location_id,
+    l.region,
+    l.regency,
+    l.cluster,
+    ls.name AS sparepart_name,
+    ssi.stock_type,
+    ssi.quantity
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE ssi.deleted_at IS NULL AND l.deleted_at IS NULL
+    AND ssi.stock_type IN ('NEW_STOCK', 'USED_STOCK')
+    AND ssi.quantity <= sqlc.arg('threshold')::int
+ORDER BY l.region, l.regency, l.cluster, ls.name, ssi.stock_type;
+
+-- name: ListPendingSparepartRequestDigest :many
+-- Sparepart requests awaiting approval, oldest first, with their item totals
+SELECT
+    sr.id,
+    sr.destination_location_id,
+    dst.cluster AS destination_cluster,
+    sr.requested_by,
+    sr.notes,
+    sr.created_at,
+    COUNT(sri.id)::bigint AS items,
+    COALESCE(SUM(sri.quantity), 0)::bigint AS quantity
+FROM sparepart_request sr
+LEFT JOIN location dst ON dst.id = sr.destination_location_id
+LEFT JOIN sparepart_request_item sri ON sri.request_id = sr.id
+WHERE sr.status = 'PENDING'
+GROUP BY sr.id, dst.cluster
+ORDER BY sr.created_at, sr.id;
+
+-- name: ListContactPersonEmails :many
+-- The email recipients of each location's digest
+SELECT cp.location_id, cp.pic, cp.email::text AS email
+FROM contact_person cp
+JOIN location l ON l.id = cp.location_id
+WHERE cp.email IS NOT NULL AND cp.deleted_at IS NULL AND l.deleted_at IS NULL
+ORDER BY cp.location_id, cp.id;
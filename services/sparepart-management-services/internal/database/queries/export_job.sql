@@ -0,0 +1,34 @@
+-- name: CreateExportJob :one
+INSERT INTO export_job (user_id, entity, format, filters)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetExportJob :one
+-- Jobs are only visible to the user who queued them
+SELECT * FROM export_job
+WHERE id = $1 AND user_id = $2;
+
+-- name: ClaimExportJob :one
+-- Claims the oldest waiting job, or a running one whose lease has passed, for lease_seconds
+UPDATE export_job
+SET status = 'RUNNING', attempts = attempts + 1, started_at = CURRENT_TIMESTAMP,
+    locked_until = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg('lease_seconds')::int)
+WHERE id = (
+    SELECT id FROM export_job
+    WHERE status = 'PENDING' OR (status = 'RUNNING' AND locked_until < CURRENT_TIMESTAMP)
+    ORDER BY id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteExportJob :exec
+UPDATE export_job
+SET status = 'COMPLETED', filename = $2, stored_name = $3, row_count = $4, error = NULL,
+    locked_until = NULL, finished_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: FailExportJob :exec
+UPDATE export_job
+SET status = 'FAILED', error = $2, locked_until = NULL, finished_at = CURRENT_TIMESTAMP
+WHERE id = $1;
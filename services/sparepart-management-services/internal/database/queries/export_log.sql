@@ -0,0 +1,37 @@
+-- name: CreateExportLog :exec
+INSERT INTO export_log (user_id, entity, format, filters, row_count, duration_ms, stored)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListExportLogs :many
+SELECT * FROM export_log
+WHERE
+    (sqlc.narg('user_id')::text IS NULL OR user_id = sqlc.narg('user_id')::text)
+    AND (sqlc.narg('entity')::text IS NULL OR entity = UPPER(sqlc.narg('entity')::text))
+    AND (sqlc.narg('format')::text IS NULL OR format = UPPER(sqlc.narg('format')::text))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR created_at >= sqlc.narg('since')::timestamptz)
+    AND (sqlc.narg('until')::timestamptz IS NULL OR created_at < sqlc.narg('until')::timestamptz)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountExportLogs :one
+SELECT COUNT(*) FROM export_log
+WHERE
+    (sqlc.narg('user_id')::text IS NULL OR user_id = sqlc.narg('user_id')::text)
+    AND (sqlc.narg('entity')::text IS NULL OR entity = UPPER(sqlc.narg('entity')::text))
+    AND (sqlc.narg('format')::text IS NULL OR format = UPPER(sqlc.narg('format')::text))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR created_at >= sqlc.narg('since')::timestamptz)
+    AND (sqlc.narg('until')::timestamptz IS NULL OR created_at < sqlc.narg('until')::timestamptz);
+
+-- name: ListExportLogsForExport :many
+-- Keyset batches (newest first) for the export log's own Excel export
+SELECT * FROM export_log
+WHERE
+    (sqlc.narg('user_id')::text IS NULL OR user_id = sqlc.narg('user_id')::text)
+    AND (sqlc.narg('entity')::text IS NULL OR entity = UPPER(sqlc.narg('entity')::text))
+    AND (sqlc.narg('format')::text IS NULL OR format = UPPER(sqlc.narg('format')::text))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR created_at >= sqlc.narg('since')::timestamptz)
+    AND (sqlc.narg('until')::timestamptz IS NULL OR created_at < sqlc.narg('until')::timestamptz)
+    AND (sqlc.narg('after_id')::bigint IS NULL OR id < sqlc.narg('after_id')::bigint)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit');
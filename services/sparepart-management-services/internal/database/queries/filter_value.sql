@@ -0,0 +1,45 @@
+-- name: ListFilterValues :many
+-- The distinct values the stock and tools alker listings can be filtered by, as present in
+-- the data: region, regency and cluster of the active locations, and the names of the spareparts in
+-- stock and of the tools alker. Regencies and clusters can be narrowed to a region for
+-- cascading dropdowns.
+SELECT field, value FROM (
+    SELECT DISTINCT 'region'::text AS field, l.region::text AS value
+    FROM location l
+    WHERE l.deleted_at IS NULL AND l.is_active
+
+    UNION ALL
+
+    SELECT DISTINCT 'regency'::text, l.regency
+    FROM location l
+    WHERE
+        l.deleted_at IS NULL
+        AND l.is_active
+        AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+
+    UNION ALL
+
+    SELECT DISTINCT 'cluster'::text, l.cluster
+    FROM location l
+    WHERE
+        l.deleted_at IS NULL
+        AND l.is_active
+        AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+
+    UNION ALL
+
+    SELECT DISTINCT 'sparepart_name'::text, ls.name
+    FROM sparepart_stock_item ssi
+    JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+    JOIN location l ON l.id = ssi.location_id
+    WHERE ssi.deleted_at IS NULL AND l.deleted_at IS NULL
+
+    UNION ALL
+
+    SELECT DISTINCT 'tools_name'::text, ls.name
+    FROM tools_alker_item tai
+    JOIN list_sparepart ls ON ls.id = tai.tools_id
+    JOIN location l ON l.id = tai.location_id
+    WHERE tai.deleted_at IS NULL AND l.deleted_at IS NULL
+) filter_values
+ORDER BY field, value;
@@ -0,0 +1,87 @@
+-- name: CreateGoodsReceipt :one
+-- Registers a DRAFT receipt; returns no row when the location does not exist or is deleted
+INSERT INTO goods_receipt (supplier_id, delivery_order_number, location_id, packing_list_photos, notes, created_by, purchase_order_id)
+SELECT sqlc.arg('supplier_id'), sqlc.arg('delivery_order_number'), l.id, sqlc.arg('packing_list_photos'), sqlc.narg('notes'), sqlc.narg('created_by'), sqlc.narg('purchase_order_id')
+FROM location l
+WHERE l.id = sqlc.arg('location_id') AND l.deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateGoodsReceiptItem :one
+-- Returns no row when the sparepart does not exist
+INSERT INTO goods_receipt_item (receipt_id, sparepart_id, stock_type, quantity, purchase_order_item_id, unit_cost)
+SELECT sqlc.arg('receipt_id'), ls.id, sqlc.arg('stock_type')::stock_type, sqlc.arg('quantity')::int, sqlc.narg('purchase_order_item_id'), sqlc.narg('unit_cost')::numeric
+FROM list_sparepart ls
+WHERE ls.id = sqlc.arg('sparepart_id') AND ls.deleted_at IS NULL
+RETURNING *;
+
+-- name: GetGoodsReceipt :one
+SELECT gr.*, s.name AS supplier_name, l.region, l.regency, l.cluster
+FROM goods_receipt gr
+JOIN supplier s ON s.id = gr.supplier_id
+LEFT JOIN location l ON l.id = gr.location_id
+WHERE gr.id = $1;
+
+-- name: GetGoodsReceiptForUpdate :one
+-- Locks the receipt until the transaction ends, so it is confirmed once
+SELECT * FROM goods_receipt
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListGoodsReceipts :many
+SELECT gr.*, s.name AS supplier_name, l.region, l.regency, l.cluster
+FROM goods_receipt gr
+JOIN supplier s ON s.id = gr.supplier_id
+LEFT JOIN location l ON l.id = gr.location_id
+WHERE (sqlc.narg('status')::text IS NULL OR gr.status = sqlc.narg('status'))
+    AND (sqlc.narg('location_id')::int IS NULL OR gr.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('supplier_id')::int IS NULL OR gr.supplier_id = sqlc.narg('supplier_id')::int)
+    AND (sqlc.narg('supplier')::text IS NULL OR s.name ILIKE '%' || sqlc.narg('supplier') || '%')
+    AND (sqlc.narg('delivery_order_number')::text IS NULL OR gr.delivery_order_number = sqlc.narg('delivery_order_number'))
+    AND (sqlc.narg('purchase_order_id')::int IS NULL OR gr.purchase_order_id = sqlc.narg('purchase_order_id')::int)
+ORDER BY gr.created_at DESC, gr.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountGoodsReceipts :one
+SELECT COUNT(*) FROM goods_receipt gr
+JOIN supplier s ON s.id = gr.supplier_id
+WHERE (sqlc.narg('status')::text IS NULL OR gr.status = sqlc.narg('status'))
+    AND (sqlc.narg('location_id')::int IS NULL OR gr.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('supplier_id')::int IS NULL OR gr.supplier_id = sqlc.narg('supplier_id')::int)
+    AND (sqlc.narg('supplier')::text IS NULL OR s.name ILIKE '%' || sqlc.narg('supplier') || '%')
+    AND (sqlc.narg('delivery_order_number')::text IS NULL OR gr.delivery_order_number = sqlc.narg('delivery_order_number'))
+    AND (sqlc.narg('purchase_order_id')::int IS NULL OR gr.purchase_order_id = sqlc.narg('purchase_order_id')::int);
+
+-- name: ListGoodsReceiptItems :many
+SELECT gri.*, ls.name AS sparepart_name
+FROM goods_receipt_item gri
+LEFT JOIN list_sparepart ls ON ls.id = gri.sparepart_id
+WHERE gri.receipt_id = $1
+ORDER BY gri.id;
+
+-- name: ConfirmGoodsReceipt :one
+UPDATE goods_receipt
+SET status = 'CONFIRMED', confirmed_by = $2, confirmed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'DRAFT'
+RETURNING *;
+
+-- name: SetGoodsReceiptItemStock :exec
+UPDATE goods_receipt_item
+SET stock_item_id = $2, quantity_after = $3
+WHERE id = $1;
+
+-- name: ApplyGoodsReceiptItemCost :exec
+-- Averages the cost of a received quantity into its stock item (whose quantity already
+-- includes it), weighted by the quantity held before and the quantity received. The units
+-- held before are at the stock item's cost, or the master's when it has none; without
+-- either the stock item takes the received cost.
+UPDATE sparepart_stock_item ssi
+SET unit_cost = CASE
+    WHEN COALESCE(ssi.unit_cost, ls.unit_cost) IS NULL OR ssi.quantity <= sqlc.arg('received')::int
+        THEN sqlc.arg('unit_cost')::numeric
+    ELSE ROUND(
+        (COALESCE(ssi.unit_cost, ls.unit_cost) * (ssi.quantity - sqlc.arg('received')::int)
+            + sqlc.arg('unit_cost')::numeric * sqlc.arg('received')::int) / ssi.quantity,
+        2)
+END
+FROM list_sparepart ls
+WHERE ssi.id = sqlc.arg('id') AND ls.id = ssi.sparepart_id;
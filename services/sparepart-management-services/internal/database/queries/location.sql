@@ -1,35 +1,130 @@
 -- name: GetLocation :one
 SELECT * FROM location
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: ListLocationsByIDs :many
+-- Locations of a batch of GraphQL stock, tools alker and contact person items, deleted ones
+-- included since the items still reference them
+SELECT * FROM location
+WHERE id = ANY(sqlc.arg('ids')::int[])
+ORDER BY id;
 
 -- name: ListLocations :many
 SELECT * FROM location
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR UPPER(region::text) = UPPER($1::text))
-    AND ($2::text IS NULL OR $2 = '' OR regency ILIKE '%' || $2 || '%')
-    AND ($3::text IS NULL OR $3 = '' OR cluster ILIKE '%' || $3 || '%')
+    deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (is_active OR sqlc.arg('include_inactive')::boolean)
 ORDER BY id
-LIMIT $4
-OFFSET $5;
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
 
 -- name: CountLocations :one
 SELECT COUNT(*) FROM location
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR UPPER(region::text) = UPPER($1::text))
-    AND ($2::text IS NULL OR $2 = '' OR regency ILIKE '%' || $2 || '%')
-    AND ($3::text IS NULL OR $3 = '' OR cluster ILIKE '%' || $3 || '%');
+    deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (is_active OR sqlc.arg('include_inactive')::boolean);
 
 -- name: CreateLocation :one
-INSERT INTO location (region, regency, cluster)
-VALUES ($1, $2, $3)
+INSERT INTO location (region, regency, cluster, latitude, longitude)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: UpdateLocation :one
 UPDATE location
-SET region = $2, regency = $3, cluster = $4
+SET region = $2, regency = $3, cluster = $4, latitude = $5, longitude = $6
 WHERE id = $1
 RETURNING *;
 
+-- name: PatchLocation :one
+-- Only provided fields change
+UPDATE location
+SET
+    region = COALESCE(sqlc.narg('region')::region_type, region),
+    regency = COALESCE(sqlc.narg('regency')::text, regency),
+    cluster = COALESCE(sqlc.narg('cluster')::text, cluster),
+    latitude = COALESCE(sqlc.narg('latitude')::float8, latitude),
+    longitude = COALESCE(sqlc.narg('longitude')::float8, longitude)
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
 -- name: DeleteLocation :exec
-DELETE FROM location
-WHERE id = $1;
+-- Soft delete; the location's stock items are soft deleted with it, sharing its deleted_at
+-- so that RestoreLocation brings back exactly those items
+WITH deleted AS (
+    UPDATE location
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE id = $1 AND deleted_at IS NULL
+    RETURNING id, deleted_at
+)
+UPDATE sparepart_stock_item ssi
+SET deleted_at = d.deleted_at
+FROM deleted d
+WHERE ssi.location_id = d.id AND ssi.deleted_at IS NULL;
+
+-- name: RestoreLocation :one
+WITH deleted AS (
+    SELECT id, deleted_at FROM location
+    WHERE id = $1 AND deleted_at IS NOT NULL
+    FOR UPDATE
+), restored_items AS (
+    UPDATE sparepart_stock_item ssi
+    SET deleted_at = NULL
+    FROM deleted d
+    WHERE ssi.location_id = d.id AND ssi.deleted_at = d.deleted_at
+)
+UPDATE location l
+SET deleted_at = NULL
+FROM deleted d
+WHERE l.id = d.id
+RETURNING l.*;
+
+-- name: SetLocationActive :one
+UPDATE location
+SET is_active = $2
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CountLocationStockReferences :one
+-- Live stock and tools alker items still held at the location
+SELECT
+    (SELECT COUNT(*) FROM sparepart_stock_item WHERE location_id = $1 AND deleted_at IS NULL)
+    + (SELECT COUNT(*) FROM tools_alker_item WHERE location_id = $1 AND deleted_at IS NULL) AS stock_references;
+
+-- name: ListLocationsWithCoordinates :many
+-- Locations placed on the map, with the stock and tools alker they hold
+SELECT
+    l.id, l.region, l.regency, l.cluster, l.latitude::float8 AS latitude, l.longitude::float8 AS longitude,
+    COALESCE(stock.item_count, 0)::bigint AS stock_item_count,
+    COALESCE(stock.new_stock_quantity, 0)::bigint AS new_stock_quantity,
+    COALESCE(stock.used_stock_quantity, 0)::bigint AS used_stock_quantity,
+    COALESCE(tools.quantity, 0)::bigint AS tools_alker_quantity
+FROM location l
+LEFT JOIN (
+    SELECT
+        location_id,
+        COUNT(*) AS item_count,
+        SUM(quantity) FILTER (WHERE stock_type = 'NEW_STOCK') AS new_stock_quantity,
+        SUM(quantity) FILTER (WHERE stock_type = 'USED_STOCK') AS used_stock_quantity
+    FROM sparepart_stock_item
+    WHERE deleted_at IS NULL
+    GROUP BY location_id
+) stock ON stock.location_id = l.id
+LEFT JOIN (
+    SELECT location_id, SUM(quantity) AS quantity
+    FROM tools_alker_item
+    WHERE deleted_at IS NULL
+    GROUP BY location_id
+) tools ON tools.location_id = l.id
+WHERE
+    l.deleted_at IS NULL
+    AND l.latitude IS NOT NULL AND l.longitude IS NOT NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+ORDER BY l.id;
@@ -0,0 +1,100 @@
+-- Documentation completeness per location, scored 0-100 from four equally weighted checks:
+-- a contact person exists, stock items are photographed, the stock was counted (a stock
+-- opname approved or any stock item updated) since opname_since, and stock items have notes. The photo and notes checks
+-- give partial credit by share of items; a location without stock items passes both.
+-- Soft deleted locations and stock items are left out, and the ranked report skips inactive
+-- locations.
+
+-- name: ListLocationCompleteness :many
+-- Ranked worst first, so coordinators know which clusters to chase
+WITH facts AS (
+    SELECT
+        l.id AS location_id,
+        l.region,
+        l.regency,
+        l.cluster,
+        EXISTS (SELECT 1 FROM contact_person cp WHERE cp.location_id = l.id AND cp.deleted_at IS NULL) AS has_contact_person,
+        COUNT(ssi.id) AS stock_items,
+        COUNT(ssi.id) FILTER (WHERE jsonb_array_length(ssi.documentation) > 0) AS photographed_items,
+        COUNT(ssi.id) FILTER (WHERE NULLIF(TRIM(ssi.notes), '') IS NOT NULL) AS noted_items,
+        MAX(ssi.updated_at) AS last_stock_update
+    FROM location l
+    LEFT JOIN sparepart_stock_item ssi ON ssi.location_id = l.id AND ssi.deleted_at IS NULL
+    WHERE
+        l.deleted_at IS NULL
+        AND l.is_active
+        AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+        AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+        AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    GROUP BY l.id
+), scored AS (
+    SELECT
+        facts.*,
+        COALESCE(last_stock_update >= sqlc.arg('opname_since')::timestamptz, false)
+            OR EXISTS (
+                SELECT 1 FROM stock_opname so
+                WHERE so.location_id = facts.location_id
+                    AND so.status = 'APPROVED'
+                    AND so.approved_at >= sqlc.arg('opname_since')::timestamptz
+            ) AS recent_stock_opname
+    FROM facts
+)
+SELECT
+    location_id, region, regency, cluster, has_contact_person,
+    stock_items::bigint AS stock_items,
+    photographed_items::bigint AS photographed_items,
+    noted_items::bigint AS noted_items,
+    last_stock_update::timestamptz AS last_stock_update,
+    recent_stock_opname,
+    (CASE WHEN has_contact_person THEN 25 ELSE 0 END
+        + CASE WHEN stock_items = 0 THEN 25 ELSE ROUND(25.0 * photographed_items / stock_items) END
+        + CASE WHEN recent_stock_opname THEN 25 ELSE 0 END
+        + CASE WHEN stock_items = 0 THEN 25 ELSE ROUND(25.0 * noted_items / stock_items) END
+    )::int AS score
+FROM scored
+ORDER BY score, region, regency, cluster
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListLocationCompletenessByIDs :many
+-- Same score for the locations of a grouped stock response
+WITH facts AS (
+    SELECT
+        l.id AS location_id,
+        l.region,
+        l.regency,
+        l.cluster,
+        EXISTS (SELECT 1 FROM contact_person cp WHERE cp.location_id = l.id AND cp.deleted_at IS NULL) AS has_contact_person,
+        COUNT(ssi.id) AS stock_items,
+        COUNT(ssi.id) FILTER (WHERE jsonb_array_length(ssi.documentation) > 0) AS photographed_items,
+        COUNT(ssi.id) FILTER (WHERE NULLIF(TRIM(ssi.notes), '') IS NOT NULL) AS noted_items,
+        MAX(ssi.updated_at) AS last_stock_update
+    FROM location l
+    LEFT JOIN sparepart_stock_item ssi ON ssi.location_id = l.id AND ssi.deleted_at IS NULL
+    WHERE l.id = ANY(sqlc.arg('location_ids')::int[])
+    GROUP BY l.id
+), scored AS (
+    SELECT
+        facts.*,
+        COALESCE(last_stock_update >= sqlc.arg('opname_since')::timestamptz, false)
+            OR EXISTS (
+                SELECT 1 FROM stock_opname so
+                WHERE so.location_id = facts.location_id
+                    AND so.status = 'APPROVED'
+                    AND so.approved_at >= sqlc.arg('opname_since')::timestamptz
+            ) AS recent_stock_opname
+    FROM facts
+)
+SELECT
+    location_id, region, regency, cluster, has_contact_person,
+    stock_items::bigint AS stock_items,
+    photographed_items::bigint AS photographed_items,
+    noted_items::bigint AS noted_items,
+    last_stock_update::timestamptz AS last_stock_update,
+    recent_stock_opname,
+    (CASE WHEN has_contact_person THEN 25 ELSE 0 END
+        + CASE WHEN stock_items = 0 THEN 25 ELSE ROUND(25.0 * photographed_items / stock_items) END
+        + CASE WHEN recent_stock_opname THEN 25 ELSE 0 END
+        + CASE WHEN stock_items = 0 THEN 25 ELSE ROUND(25.0 * noted_items / stock_items) END
+    )::int AS score
+FROM scored;
@@ -0,0 +1,50 @@
+-- name: ListMessageDeliveries :many
+SELECT * FROM message_delivery
+WHERE
+    (sqlc.narg('location_id')::int IS NULL OR location_id = sqlc.narg('location_id'))
+    AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status')::varchar)
+    AND (sqlc.narg('event')::varchar IS NULL OR event = sqlc.narg('event')::varchar)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountMessageDeliveries :one
+SELECT COUNT(*) FROM message_delivery
+WHERE
+    (sqlc.narg('location_id')::int IS NULL OR location_id = sqlc.narg('location_id'))
+    AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status')::varchar)
+    AND (sqlc.narg('event')::varchar IS NULL OR event = sqlc.narg('event')::varchar);
+
+-- name: ExpireMessageDeliveries :execrows
+-- Gives up on pending deliveries older than the maximum age, e.g. enqueued while no
+-- provider was configured, so they are not sent long after the fact
+UPDATE message_delivery
+SET status = 'FAILED', last_error = 'expired before it could be sent'
+WHERE status = 'PENDING'
+    AND created_at < CURRENT_TIMESTAMP - make_interval(secs => sqlc.arg('max_age_seconds')::int);
+
+-- name: ClaimMessageDeliveries :many
+-- Claims due deliveries by pushing their next attempt past the lease, so another replica
+-- does not send them while this one is
+UPDATE message_delivery
+SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg('lease_seconds')::int)
+WHERE id IN (
+    SELECT id FROM message_delivery
+    WHERE status = 'PENDING' AND next_attempt_at <= CURRENT_TIMESTAMP
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkMessageDeliverySent :exec
+UPDATE message_delivery
+SET status = 'SENT', attempts = attempts + 1, body = $2, provider_message_id = $3,
+    last_error = NULL, sent_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkMessageDeliveryFailed :exec
+-- Records a failed attempt; status stays PENDING while retries remain
+UPDATE message_delivery
+SET status = $2, attempts = attempts + 1, body = $3, last_error = $4, next_attempt_at = $5
+WHERE id = $1;
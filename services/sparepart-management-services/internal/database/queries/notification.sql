@@ -0,0 +1,48 @@
+-- name: GetNotification :one
+SELECT * FROM notification
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListNotifications :many
+SELECT * FROM notification
+WHERE user_id = sqlc.arg('user_id')
+    AND (sqlc.narg('unread')::boolean IS NULL OR (read_at IS NULL) = sqlc.narg('unread')::boolean)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountNotifications :one
+SELECT COUNT(*) FROM notification
+WHERE user_id = sqlc.arg('user_id')
+    AND (sqlc.narg('unread')::boolean IS NULL OR (read_at IS NULL) = sqlc.narg('unread')::boolean);
+
+-- name: MarkNotificationRead :one
+UPDATE notification
+SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notification
+SET read_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: CreateNotifications :execrows
+-- One notification per user, skipping users that disabled the IN_APP channel
+INSERT INTO notification (user_id, category, title, body, data)
+SELECT u.user_id, sqlc.arg('category'), sqlc.arg('title'), sqlc.arg('body'), sqlc.arg('data')
+FROM unnest(sqlc.arg('user_ids')::text[]) AS u(user_id)
+WHERE NOT EXISTS (
+    SELECT 1 FROM notification_preference p
+    WHERE p.user_id = u.user_id AND p.channel = 'IN_APP' AND NOT p.enabled
+);
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM notification_preference
+WHERE user_id = $1
+ORDER BY channel;
+
+-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preference (user_id, channel, enabled)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
@@ -0,0 +1,77 @@
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_order (supplier_id, expected_date, notes, created_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: CreatePurchaseOrderItem :one
+-- Returns no row when the sparepart does not exist
+INSERT INTO purchase_order_item (order_id, sparepart_id, stock_type, quantity)
+SELECT sqlc.arg('order_id'), ls.id, sqlc.arg('stock_type')::stock_type, sqlc.arg('quantity')::int
+FROM list_sparepart ls
+WHERE ls.id = sqlc.arg('sparepart_id') AND ls.deleted_at IS NULL
+RETURNING *;
+
+-- name: GetPurchaseOrder :one
+SELECT po.*, s.name AS supplier_name
+FROM purchase_order po
+JOIN supplier s ON s.id = po.supplier_id
+WHERE po.id = $1;
+
+-- name: GetPurchaseOrderForUpdate :one
+-- Locks the order until the transaction ends, so its status follows one receipt at a time
+SELECT * FROM purchase_order
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListPurchaseOrders :many
+SELECT
+    po.*, s.name AS supplier_name,
+    COALESCE(t.ordered_quantity, 0)::bigint AS ordered_quantity,
+    COALESCE(t.received_quantity, 0)::bigint AS received_quantity,
+    COALESCE(t.outstanding_quantity, 0)::bigint AS outstanding_quantity
+FROM purchase_order po
+JOIN supplier s ON s.id = po.supplier_id
+LEFT JOIN (
+    SELECT
+        p.order_id,
+        SUM(p.quantity) AS ordered_quantity,
+        SUM(p.received_quantity) AS received_quantity,
+        SUM(p.outstanding_quantity) AS outstanding_quantity
+    FROM purchase_order_item_progress p
+    GROUP BY p.order_id
+) t ON t.order_id = po.id
+WHERE (sqlc.narg('status')::text IS NULL OR po.status = sqlc.narg('status'))
+    AND (sqlc.narg('supplier_id')::int IS NULL OR po.supplier_id = sqlc.narg('supplier_id')::int)
+ORDER BY po.created_at DESC, po.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountPurchaseOrders :one
+SELECT COUNT(*) FROM purchase_order po
+WHERE (sqlc.narg('status')::text IS NULL OR po.status = sqlc.narg('status'))
+    AND (sqlc.narg('supplier_id')::int IS NULL OR po.supplier_id = sqlc.narg('supplier_id')::int);
+
+-- name: ListPurchaseOrderItems :many
+SELECT poi.*, ls.name AS sparepart_name, p.received_quantity, p.outstanding_quantity
+FROM purchase_order_item poi
+JOIN purchase_order_item_progress p ON p.item_id = poi.id
+LEFT JOIN list_sparepart ls ON ls.id = poi.sparepart_id
+WHERE poi.order_id = $1
+ORDER BY poi.id;
+
+-- name: PlacePurchaseOrder :one
+UPDATE purchase_order
+SET status = 'ORDERED', ordered_by = $2, ordered_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'DRAFT'
+RETURNING *;
+
+-- name: UpdatePurchaseOrderReceivedStatus :exec
+-- Moves a placed order to RECEIVED once no line is outstanding, to PARTIAL before that
+UPDATE purchase_order po
+SET status = CASE
+    WHEN EXISTS (
+        SELECT 1 FROM purchase_order_item_progress p
+        WHERE p.order_id = po.id AND p.outstanding_quantity > 0
+    ) THEN 'PARTIAL'
+    ELSE 'RECEIVED'
+END
+WHERE po.id = $1 AND po.status IN ('ORDERED', 'PARTIAL');
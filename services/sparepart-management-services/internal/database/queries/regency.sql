@@ -0,0 +1,35 @@
+-- name: GetRegency :one
+SELECT * FROM regency
+WHERE id = $1 LIMIT 1;
+
+-- name: ListRegencies :many
+SELECT * FROM regency
+WHERE
+    (sqlc.narg('region')::text IS NULL OR UPPER(region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%')
+ORDER BY region, name
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountRegencies :one
+SELECT COUNT(*) FROM regency
+WHERE
+    (sqlc.narg('region')::text IS NULL OR UPPER(region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%');
+
+-- name: CreateRegency :one
+INSERT INTO regency (region, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: UpdateRegency :one
+-- Renaming or moving a regency cascades to its clusters and their locations
+UPDATE regency
+SET region = $2, name = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteRegency :exec
+-- Fails with a foreign key violation while clusters still belong to the regency
+DELETE FROM regency
+WHERE id = $1;
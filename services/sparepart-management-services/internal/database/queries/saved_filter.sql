@@ -0,0 +1,40 @@
+-- name: ListSavedFilters :many
+SELECT * FROM saved_filter
+WHERE user_id = sqlc.arg('user_id')
+    AND (sqlc.narg('item_type')::text IS NULL OR item_type::text = UPPER(sqlc.narg('item_type')::text))
+ORDER BY item_type, is_default DESC, name;
+
+-- name: GetSavedFilter :one
+SELECT * FROM saved_filter
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: CreateSavedFilter :one
+INSERT INTO saved_filter (user_id, item_type, name, filters, sort, group_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: UpdateSavedFilter :one
+UPDATE saved_filter
+SET name = $3, filters = $4, sort = $5, group_by = $6
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteSavedFilter :exec
+DELETE FROM saved_filter
+WHERE id = $1 AND user_id = $2;
+
+-- name: SetDefaultSavedFilter :exec
+-- Makes the filter the user's only default for its listing, in one statement
+UPDATE saved_filter sf
+SET is_default = (sf.id = sqlc.arg('id'))
+FROM saved_filter target
+WHERE target.id = sqlc.arg('id')
+    AND target.user_id = sqlc.arg('user_id')
+    AND sf.user_id = target.user_id
+    AND sf.item_type = target.item_type
+    AND (sf.is_default OR sf.id = target.id);
+
+-- name: UnsetDefaultSavedFilter :exec
+UPDATE saved_filter
+SET is_default = false
+WHERE id = $1 AND user_id = $2;
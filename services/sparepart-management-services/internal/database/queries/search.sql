@@ -0,0 +1,78 @@
+-- name: SearchInventory :many
+-- Stock items, tools alker items and master entries matching q in their name, notes or
+-- location (regency, cluster), by substring or by fuzzy word similarity. Relevance is the
+-- best word similarity over the matched fields, with location and notes matches weighted
+-- below name matches.
+WITH matches AS (
+    SELECT
+        'STOCK'::text AS result_type, ssi.id, ls.id AS master_id, ls.name, ls.item_type::text AS item_type,
+        ssi.stock_type::text AS stock_type, ssi.quantity, ssi.notes,
+        l.id AS location_id, l.region::text AS region, l.regency, l.cluster,
+        GREATEST(
+            word_similarity(sqlc.arg('q')::text, ls.name),
+            0.8 * word_similarity(sqlc.arg('q')::text, l.regency),
+            0.8 * word_similarity(sqlc.arg('q')::text, l.cluster),
+            0.6 * word_similarity(sqlc.arg('q')::text, COALESCE(ssi.notes, ''))
+        ) AS score
+    FROM sparepart_stock_item ssi
+    JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+    JOIN location l ON l.id = ssi.location_id
+    WHERE
+        ssi.deleted_at IS NULL
+        AND l.deleted_at IS NULL
+        AND (sqlc.narg('result_type')::text IS NULL OR sqlc.narg('result_type')::text = 'STOCK')
+        AND (
+            ls.name ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% ls.name
+            OR l.regency ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% l.regency
+            OR l.cluster ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% l.cluster
+            OR ssi.notes ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% ssi.notes
+        )
+
+    UNION ALL
+
+    SELECT
+        'TOOLS_ALKER'::text, tai.id, ls.id, ls.name, ls.item_type::text,
+        NULL::text, tai.quantity, tai.notes,
+        l.id, l.region::text, l.regency, l.cluster,
+        GREATEST(
+            word_similarity(sqlc.arg('q')::text, ls.name),
+            0.8 * word_similarity(sqlc.arg('q')::text, l.regency),
+            0.8 * word_similarity(sqlc.arg('q')::text, l.cluster),
+            0.6 * word_similarity(sqlc.arg('q')::text, COALESCE(tai.notes, ''))
+        )
+    FROM tools_alker_item tai
+    JOIN list_sparepart ls ON ls.id = tai.tools_id
+    JOIN location l ON l.id = tai.location_id
+    WHERE
+        tai.deleted_at IS NULL
+        AND l.deleted_at IS NULL
+        AND (sqlc.narg('result_type')::text IS NULL OR sqlc.narg('result_type')::text = 'TOOLS_ALKER')
+        AND (
+            ls.name ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% ls.name
+            OR l.regency ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% l.regency
+            OR l.cluster ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% l.cluster
+            OR tai.notes ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% tai.notes
+        )
+
+    UNION ALL
+
+    SELECT
+        'MASTER'::text, ls.id, ls.id, ls.name, ls.item_type::text,
+        NULL::text, NULL::int, NULL::text,
+        NULL::int, NULL::text, NULL::varchar, NULL::varchar,
+        word_similarity(sqlc.arg('q')::text, ls.name)
+    FROM list_sparepart ls
+    WHERE
+        ls.deleted_at IS NULL
+        AND (sqlc.narg('result_type')::text IS NULL OR sqlc.narg('result_type')::text = 'MASTER')
+        AND (ls.name ILIKE '%' || sqlc.arg('q') || '%' OR sqlc.arg('q')::text <% ls.name)
+)
+-- Columns that are NULL for master entries are cast so they are generated as nullable
+SELECT
+    result_type, id, master_id, name, item_type, stock_type::text AS stock_type,
+    quantity::int AS quantity, notes::text AS notes, location_id::int AS location_id,
+    region::text AS region, regency::text AS regency, cluster::text AS cluster,
+    score::float8 AS score
+FROM matches
+ORDER BY score DESC, result_type, name, id
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,43 @@
+-- name: AcquireSeedLock :exec
+-- Serializes concurrent seed runs until the surrounding transaction ends
+SELECT pg_advisory_xact_lock(hashtext('sparepart-management-seed'));
+
+-- name: SeedLocation :one
+-- Seeds the location's regency and cluster along with it; returns no row when the location
+-- already exists
+WITH seeded_regency AS (
+    INSERT INTO regency (region, name)
+    VALUES ($1, $2)
+    ON CONFLICT ON CONSTRAINT unique_regency DO NOTHING
+), seeded_cluster AS (
+    INSERT INTO cluster (region, regency, name)
+    VALUES ($1, $2, $3)
+    ON CONFLICT ON CONSTRAINT unique_cluster DO NOTHING
+)
+INSERT INTO location (region, regency, cluster)
+VALUES ($1, $2, $3)
+ON CONFLICT (region, regency, cluster) WHERE deleted_at IS NULL DO NOTHING
+RETURNING id;
+
+-- name: GetLocationIDByKey :one
+SELECT id FROM location
+WHERE region = $1 AND regency = $2 AND cluster = $3 AND deleted_at IS NULL;
+
+-- name: SeedContactPerson :execrows
+-- contact_person has no natural unique key, so existence is checked under the seed lock
+INSERT INTO contact_person (location_id, pic, phone)
+SELECT sqlc.arg('location_id')::int, sqlc.arg('pic')::text, sqlc.arg('phone')::text
+WHERE NOT EXISTS (
+    SELECT 1 FROM contact_person
+    WHERE location_id = sqlc.arg('location_id')::int
+      AND pic = sqlc.arg('pic')::text
+      AND phone = sqlc.arg('phone')::text
+);
+
+-- name: SeedSparepartMaster :one
+-- Returns true for a new row, false when the item type changed, and no row when unchanged
+INSERT INTO list_sparepart (name, item_type)
+VALUES ($1, $2)
+ON CONFLICT (name) WHERE deleted_at IS NULL DO UPDATE SET item_type = EXCLUDED.item_type
+WHERE list_sparepart.item_type IS DISTINCT FROM EXCLUDED.item_type
+RETURNING (xmax = 0)::boolean AS inserted;
@@ -0,0 +1,40 @@
+-- name: CreateShareLink :one
+INSERT INTO share_link (token_hash, location_id, created_by, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetShareLink :one
+SELECT * FROM share_link
+WHERE id = $1 LIMIT 1;
+
+-- name: GetShareLinkByTokenHash :one
+SELECT * FROM share_link
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: ListShareLinks :many
+SELECT * FROM share_link
+WHERE
+    (sqlc.narg('location_id')::int IS NULL OR location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('active')::boolean IS NULL
+        OR (revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP) = sqlc.narg('active')::boolean)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountShareLinks :one
+SELECT COUNT(*) FROM share_link
+WHERE
+    (sqlc.narg('location_id')::int IS NULL OR location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('active')::boolean IS NULL
+        OR (revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP) = sqlc.narg('active')::boolean);
+
+-- name: RevokeShareLink :one
+UPDATE share_link
+SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE id = $1
+RETURNING *;
+
+-- name: RecordShareLinkAccess :exec
+UPDATE share_link
+SET access_count = access_count + 1, last_accessed_at = CURRENT_TIMESTAMP
+WHERE id = $1;
@@ -1,33 +1,118 @@
 -- name: GetSparepartMaster :one
 SELECT * FROM list_sparepart
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListSparepartMasters :many
 SELECT * FROM list_sparepart
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR name ILIKE '%' || $1 || '%')
-    AND ($2::text IS NULL OR $2 = '' OR item_type::text = $2)
+    deleted_at IS NULL
+    AND (sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%')
+    AND (sqlc.narg('item_type')::text IS NULL OR item_type::text = sqlc.narg('item_type'))
 ORDER BY name ASC
-LIMIT $3
-OFFSET $4;
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
 
 -- name: CountSparepartMasters :one
 SELECT COUNT(*) FROM list_sparepart
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR name ILIKE '%' || $1 || '%')
-    AND ($2::text IS NULL OR $2 = '' OR item_type::text = $2);
+    deleted_at IS NULL
+    AND (sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%')
+    AND (sqlc.narg('item_type')::text IS NULL OR item_type::text = sqlc.narg('item_type'));
 
 -- name: CreateSparepartMaster :one
-INSERT INTO list_sparepart (name, item_type)
-VALUES ($1, $2)
+-- Without a unit the item is counted in pieces
+INSERT INTO list_sparepart (name, item_type, unit, unit_cost)
+VALUES ($1, $2, COALESCE(sqlc.narg('unit')::text, 'PCS'), sqlc.narg('unit_cost')::numeric)
+RETURNING *;
+
+-- name: ListSparepartMastersMatchingNames :many
+-- Masters of any item type whose name matches one of the given lowercased names
+SELECT * FROM list_sparepart
+WHERE LOWER(name) = ANY(sqlc.arg('names')::text[]) AND deleted_at IS NULL;
+
+-- name: CopySparepartMasterImport :copyfrom
+-- Stages the rows of a master list import; see CreateSparepartMastersFromImport
+INSERT INTO sparepart_master_import (line, name, item_type, unit)
+VALUES ($1, $2, $3, $4);
+
+-- name: CreateSparepartMastersFromImport :many
+-- Moves the staged rows of the transaction into the master list, in line order. A name that
+-- already exists (case-insensitive) is skipped.
+WITH staged AS (
+    DELETE FROM sparepart_master_import
+    WHERE import_tx = txid_current()
+    RETURNING *
+)
+INSERT INTO list_sparepart (name, item_type, unit)
+SELECT s.name, s.item_type, s.unit
+FROM staged s
+WHERE NOT EXISTS (
+    SELECT 1 FROM list_sparepart ls WHERE LOWER(ls.name) = LOWER(s.name) AND ls.deleted_at IS NULL
+)
+ORDER BY s.line
+ON CONFLICT (name) WHERE deleted_at IS NULL DO NOTHING
 RETURNING *;
 
 -- name: UpdateSparepartMaster :one
+-- Without a unit or unit cost the item keeps its current one
 UPDATE list_sparepart
-SET name = $2, item_type = $3
+SET
+    name = $2,
+    item_type = $3,
+    unit = COALESCE(sqlc.narg('unit')::text, unit),
+    unit_cost = COALESCE(sqlc.narg('unit_cost')::numeric, unit_cost)
 WHERE id = $1
 RETURNING *;
 
+-- name: PatchSparepartMaster :one
+-- Only provided fields change
+UPDATE list_sparepart
+SET
+    name = COALESCE(sqlc.narg('name')::text, name),
+    item_type = COALESCE(sqlc.narg('item_type')::item_type, item_type),
+    unit = COALESCE(sqlc.narg('unit')::text, unit),
+    unit_cost = COALESCE(sqlc.narg('unit_cost')::numeric, unit_cost)
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: CountSparepartMasterReferences :one
+-- Items that still use the master; it can't be deleted while there are any
+SELECT
+    (SELECT COUNT(*) FROM sparepart_stock_item WHERE sparepart_id = $1 AND deleted_at IS NULL)
+    + (SELECT COUNT(*) FROM tools_alker_item WHERE tools_id = $1 AND deleted_at IS NULL) AS item_references;
+
 -- name: DeleteSparepartMaster :exec
-DELETE FROM list_sparepart
-WHERE id = $1;
+-- Soft delete: the master is kept until restored or purged
+UPDATE list_sparepart
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreSparepartMaster :one
+UPDATE list_sparepart
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListSparepartAvailability :many
+-- Every location holding the sparepart or tool: one row per stock item (per stock type) or
+-- tools alker item with a quantity left. Tools alker rows have no stock type and report how
+-- many are checked out.
+SELECT * FROM (
+    SELECT
+        l.id AS location_id, l.region, l.regency, l.cluster,
+        ssi.stock_type::text AS stock_type, ssi.quantity, 0::int AS checked_out
+    FROM sparepart_stock_item ssi
+    JOIN location l ON l.id = ssi.location_id
+    WHERE ssi.sparepart_id = $1 AND ssi.deleted_at IS NULL AND l.deleted_at IS NULL AND ssi.quantity > 0
+
+    UNION ALL
+
+    SELECT
+        l.id, l.region, l.regency, l.cluster,
+        NULL::text, tai.quantity,
+        (SELECT COALESCE(SUM(tac.quantity), 0) FROM tools_alker_checkout tac WHERE tac.tools_alker_item_id = tai.id AND tac.checked_in_at IS NULL)::int
+    FROM tools_alker_item tai
+    JOIN location l ON l.id = tai.location_id
+    WHERE tai.tools_id = $1 AND tai.deleted_at IS NULL AND l.deleted_at IS NULL AND tai.quantity > 0
+) availability
+ORDER BY region, regency, cluster, location_id, stock_type;
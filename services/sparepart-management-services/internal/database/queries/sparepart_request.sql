@@ -0,0 +1,90 @@
+-- name: CreateSparepartRequest :one
+-- Returns no row when the destination location does not exist or is deleted
+INSERT INTO sparepart_request (destination_location_id, notes, requested_by, technician_id)
+SELECT l.id, sqlc.narg('notes'), sqlc.arg('requested_by'), sqlc.narg('technician_id')::int
+FROM location l
+WHERE l.id = sqlc.arg('destination_location_id') AND l.deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateSparepartRequestItem :one
+-- Returns no row when the sparepart does not exist
+INSERT INTO sparepart_request_item (request_id, sparepart_id, stock_type, quantity)
+SELECT sqlc.arg('request_id'), ls.id, sqlc.arg('stock_type')::stock_type, sqlc.arg('quantity')::int
+FROM list_sparepart ls
+WHERE ls.id = sqlc.arg('sparepart_id') AND ls.deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateSparepartRequestEvent :exec
+INSERT INTO sparepart_request_event (request_id, status, actor, note)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetSparepartRequest :one
+SELECT
+    sr.*,
+    dst.cluster AS destination_cluster,
+    src.cluster AS source_cluster,
+    t.name AS technician_name
+FROM sparepart_request sr
+LEFT JOIN location dst ON dst.id = sr.destination_location_id
+LEFT JOIN location src ON src.id = sr.source_location_id
+LEFT JOIN technician t ON t.id = sr.technician_id
+WHERE sr.id = $1;
+
+-- name: GetSparepartRequestForUpdate :one
+-- Locks the request until the transaction ends, so its status changes are serialized
+SELECT * FROM sparepart_request
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListSparepartRequests :many
+SELECT
+    sr.*,
+    dst.cluster AS destination_cluster,
+    src.cluster AS source_cluster,
+    t.name AS technician_name
+FROM sparepart_request sr
+LEFT JOIN location dst ON dst.id = sr.destination_location_id
+LEFT JOIN location src ON src.id = sr.source_location_id
+LEFT JOIN technician t ON t.id = sr.technician_id
+WHERE (sqlc.narg('status')::text IS NULL OR sr.status = sqlc.narg('status'))
+    AND (sqlc.narg('destination_location_id')::int IS NULL OR sr.destination_location_id = sqlc.narg('destination_location_id')::int)
+    AND (sqlc.narg('requested_by')::text IS NULL OR sr.requested_by = sqlc.narg('requested_by'))
+    AND (sqlc.narg('technician_id')::int IS NULL OR sr.technician_id = sqlc.narg('technician_id')::int)
+ORDER BY sr.created_at DESC, sr.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSparepartRequests :one
+SELECT COUNT(*) FROM sparepart_request sr
+WHERE (sqlc.narg('status')::text IS NULL OR sr.status = sqlc.narg('status'))
+    AND (sqlc.narg('destination_location_id')::int IS NULL OR sr.destination_location_id = sqlc.narg('destination_location_id')::int)
+    AND (sqlc.narg('requested_by')::text IS NULL OR sr.requested_by = sqlc.narg('requested_by'))
+    AND (sqlc.narg('technician_id')::int IS NULL OR sr.technician_id = sqlc.narg('technician_id')::int);
+
+-- name: ListSparepartRequestItems :many
+SELECT sri.*, ls.name AS sparepart_name
+FROM sparepart_request_item sri
+LEFT JOIN list_sparepart ls ON ls.id = sri.sparepart_id
+WHERE sri.request_id = $1
+ORDER BY sri.id;
+
+-- name: ListSparepartRequestEvents :many
+SELECT * FROM sparepart_request_event
+WHERE request_id = $1
+ORDER BY id;
+
+-- name: SetSparepartRequestStatus :one
+UPDATE sparepart_request
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: FulfillSparepartRequest :one
+UPDATE sparepart_request
+SET status = 'FULFILLED', source_location_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: SetSparepartRequestItemTransfer :exec
+UPDATE sparepart_request_item
+SET transfer_id = $2
+WHERE id = $1;
@@ -1,36 +1,72 @@
 -- name: GetSparepartStock :one
 SELECT 
-    ssi.id, ssi.location_id, ssi.sparepart_id, ssi.stock_type, ssi.quantity, ssi.documentation, ssi.notes, ssi.created_at, ssi.updated_at,
+    ssi.id, ssi.location_id, ssi.sparepart_id, ssi.stock_type, ssi.quantity, ssi.documentation, ssi.notes, ssi.created_at, ssi.updated_at, ssi.version,
     l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
-    ls.id as sparepart_id_2, ls.name as sparepart_name, ls.item_type, ls.created_at as sparepart_created_at, ls.updated_at as sparepart_updated_at
+    ls.id as sparepart_id_2, ls.name as sparepart_name, ls.item_type, ls.created_at as sparepart_created_at, ls.updated_at as sparepart_updated_at, ls.unit,
+    ssi.unit_cost, ls.unit_cost AS sparepart_unit_cost
 FROM sparepart_stock_item ssi
 JOIN location l ON l.id = ssi.location_id
 JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
-WHERE ssi.id = $1 LIMIT 1;
+WHERE ssi.id = $1 AND ssi.deleted_at IS NULL LIMIT 1;
 
 -- name: ListSparepartStocks :many
+-- Paginates by location: LIMIT/OFFSET select a page of locations, then every matching stock item of those locations is returned
+WITH paged_locations AS (
+    SELECT DISTINCT ssi.location_id
+    FROM sparepart_stock_item ssi
+    JOIN location l ON l.id = ssi.location_id
+    JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+    WHERE 
+        ssi.deleted_at IS NULL
+        AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+        AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+        AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+        AND (sqlc.narg('stock_type')::text IS NULL OR ssi.stock_type::text = sqlc.narg('stock_type'))
+        AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n))
+    ORDER BY ssi.location_id
+    LIMIT sqlc.arg('limit')
+    OFFSET sqlc.arg('offset')
+)
 SELECT 
-    ssi.id, ssi.location_id, ssi.sparepart_id, ssi.stock_type, ssi.quantity, ssi.documentation, ssi.notes, ssi.created_at, ssi.updated_at,
+    ssi.id, ssi.location_id, ssi.sparepart_id, ssi.stock_type, ssi.quantity, ssi.documentation, ssi.notes, ssi.created_at, ssi.updated_at, ssi.version,
     l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
-    ls.id as sparepart_id_2, ls.name as sparepart_name, ls.item_type, ls.created_at as sparepart_created_at, ls.updated_at as sparepart_updated_at
-FROM sparepart_stock_item ssi
+    ls.id as sparepart_id_2, ls.name as sparepart_name, ls.item_type, ls.created_at as sparepart_created_at, ls.updated_at as sparepart_updated_at, ls.unit,
+    ssi.unit_cost, ls.unit_cost AS sparepart_unit_cost
+FROM paged_locations pl
+JOIN sparepart_stock_item ssi ON ssi.location_id = pl.location_id
 JOIN location l ON l.id = ssi.location_id
 JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR UPPER(l.region::text) = UPPER($1::text))
-    AND ($2::text IS NULL OR $2 = '' OR l.regency ILIKE '%' || $2 || '%')
-    AND ($3::text IS NULL OR $3 = '' OR l.cluster ILIKE '%' || $3 || '%')
-    AND ($4::text IS NULL OR $4 = '' OR ssi.stock_type::text = $4)
-    AND (
-        $5::text IS NULL OR $5 = '' OR 
-        ssi.sparepart_id IN (
-            SELECT id FROM list_sparepart 
-            WHERE name ILIKE '%' || $5 || '%'
-        )
-    )
-ORDER BY ssi.id
-LIMIT $6
-OFFSET $7;
+    -- Location filters are already applied by paged_locations, only item filters remain
+    ssi.deleted_at IS NULL
+    AND (sqlc.narg('stock_type')::text IS NULL OR ssi.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n))
+ORDER BY ssi.location_id, ssi.id;
+
+-- name: ListSparepartStocksByLocation :many
+SELECT 
+    ssi.id, ssi.location_id, ssi.sparepart_id, ssi.stock_type, ssi.quantity, ssi.documentation, ssi.notes, ssi.created_at, ssi.updated_at, ssi.version,
+    l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
+    ls.id as sparepart_id_2, ls.name as sparepart_name, ls.item_type, ls.created_at as sparepart_created_at, ls.updated_at as sparepart_updated_at, ls.unit,
+    ssi.unit_cost, ls.unit_cost AS sparepart_unit_cost
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE ssi.location_id = $1 AND ssi.deleted_at IS NULL
+ORDER BY ssi.id;
+
+-- name: ListSparepartStocksByLocations :many
+-- Stock of a batch of GraphQL locations
+SELECT 
+    ssi.id, ssi.location_id, ssi.sparepart_id, ssi.stock_type, ssi.quantity, ssi.documentation, ssi.notes, ssi.created_at, ssi.updated_at, ssi.version,
+    l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
+    ls.id as sparepart_id_2, ls.name as sparepart_name, ls.item_type, ls.created_at as sparepart_created_at, ls.updated_at as sparepart_updated_at, ls.unit,
+    ssi.unit_cost, ls.unit_cost AS sparepart_unit_cost
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE ssi.location_id = ANY(sqlc.arg('location_ids')::int[]) AND ssi.deleted_at IS NULL
+ORDER BY ssi.location_id, ssi.id;
 
 -- name: CountSparepartStocks :one
 SELECT COUNT(DISTINCT ssi.location_id)
@@ -38,57 +74,256 @@ FROM sparepart_stock_item ssi
 JOIN location l ON l.id = ssi.location_id
 JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR UPPER(l.region::text) = UPPER($1::text))
-    AND ($2::text IS NULL OR $2 = '' OR l.regency ILIKE '%' || $2 || '%')
-    AND ($3::text IS NULL OR $3 = '' OR l.cluster ILIKE '%' || $3 || '%')
-    AND ($4::text IS NULL OR $4 = '' OR ssi.stock_type::text = $4)
-    AND (
-        $5::text IS NULL OR $5 = '' OR 
-        ssi.sparepart_id IN (
-            SELECT id FROM list_sparepart 
-            WHERE name ILIKE '%' || $5 || '%'
-        )
-    );
+    ssi.deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('stock_type')::text IS NULL OR ssi.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n));
+
+-- name: ListSparepartStockItems :many
+-- Paginates by stock item for the flat (group_by=none) listing, same filters as ListSparepartStocks
+SELECT 
+    ssi.id, ssi.location_id, ssi.sparepart_id, ssi.stock_type, ssi.quantity, ssi.documentation, ssi.notes, ssi.created_at, ssi.updated_at, ssi.version,
+    l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
+    ls.id as sparepart_id_2, ls.name as sparepart_name, ls.item_type, ls.created_at as sparepart_created_at, ls.updated_at as sparepart_updated_at, ls.unit,
+    ssi.unit_cost, ls.unit_cost AS sparepart_unit_cost
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE 
+    ssi.deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('stock_type')::text IS NULL OR ssi.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n))
+ORDER BY ssi.location_id, ssi.id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountSparepartStockItems :one
+SELECT COUNT(*)
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE 
+    ssi.deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('stock_type')::text IS NULL OR ssi.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n));
 
 -- name: CreateSparepartStock :one
-INSERT INTO sparepart_stock_item (location_id, sparepart_id, stock_type, quantity, documentation, notes)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO sparepart_stock_item (location_id, sparepart_id, stock_type, quantity, documentation, notes, unit_cost)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: CreateSparepartStocksBatch :many
+-- Inserts many stock items in one statement; arrays are zipped by position. A NULL unit cost
+-- leaves the master's unit cost to apply.
+INSERT INTO sparepart_stock_item (location_id, sparepart_id, stock_type, quantity, notes, unit_cost)
+SELECT i.location_id, i.sparepart_id, i.stock_type, i.quantity, NULLIF(i.notes, ''), i.unit_cost
+FROM unnest(
+    sqlc.arg('location_ids')::int[],
+    sqlc.arg('sparepart_ids')::int[],
+    sqlc.arg('stock_types')::stock_type[],
+    sqlc.arg('quantities')::int[],
+    sqlc.arg('notes')::text[],
+    sqlc.arg('unit_costs')::numeric[]
+) AS i(location_id, sparepart_id, stock_type, quantity, notes, unit_cost)
 RETURNING *;
 
 -- name: UpdateSparepartStock :one
+-- Only provided fields change; an empty notes string clears the notes. Returns no row when
+-- the item is no longer at the expected version.
 UPDATE sparepart_stock_item
-SET quantity = $2, notes = $3
-WHERE id = $1
+SET
+    quantity = COALESCE(sqlc.narg('quantity')::int, quantity),
+    notes = CASE
+        WHEN sqlc.narg('notes')::text IS NULL THEN notes
+        ELSE NULLIF(sqlc.narg('notes')::text, '')
+    END,
+    unit_cost = COALESCE(sqlc.narg('unit_cost')::numeric, unit_cost)
+WHERE id = sqlc.arg('id') AND version = sqlc.arg('version')
 RETURNING *;
 
 -- name: UpdateSparepartStockDocumentation :one
+-- Photo changes write back the whole array read at version, so two of them at the same time
+-- cannot drop each other's photos. Returns no row when the item changed meanwhile.
 UPDATE sparepart_stock_item
 SET documentation = $2
-WHERE id = $1
+WHERE id = $1 AND version = $3
 RETURNING *;
 
 -- name: DeleteSparepartStock :exec
+-- Soft delete: the item and its photos are kept until restored or purged
+UPDATE sparepart_stock_item
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreSparepartStock :one
+-- Items of a deleted location are restored with the location
+UPDATE sparepart_stock_item ssi
+SET deleted_at = NULL
+FROM location l
+WHERE ssi.id = $1
+    AND ssi.deleted_at IS NOT NULL
+    AND l.id = ssi.location_id
+    AND l.deleted_at IS NULL
+RETURNING ssi.*;
+
+-- name: PurgeSparepartStocks :many
+-- Permanently deletes the items deleted before deleted_before, and every item of a location
+-- purged with them; returns the photos to remove
 DELETE FROM sparepart_stock_item
-WHERE id = $1;
+WHERE deleted_at < sqlc.arg('deleted_before')::timestamptz
+    OR location_id IN (SELECT id FROM location WHERE deleted_at < sqlc.arg('deleted_before')::timestamptz)
+RETURNING id, documentation;
+
+-- name: PurgeToolsAlkers :many
+-- Same as PurgeSparepartStocks for tools alker items
+DELETE FROM tools_alker_item
+WHERE deleted_at < sqlc.arg('deleted_before')::timestamptz
+    OR location_id IN (SELECT id FROM location WHERE deleted_at < sqlc.arg('deleted_before')::timestamptz)
+RETURNING id, documentation;
+
+-- name: PurgeContactPersons :execrows
+-- Permanently deletes the contact persons deleted before deleted_before; those of purged
+-- locations cascade with PurgeLocations
+DELETE FROM contact_person
+WHERE deleted_at < sqlc.arg('deleted_before')::timestamptz;
+
+-- name: PurgeLocations :execrows
+-- Permanently deletes the locations deleted before deleted_before; contact persons cascade.
+-- Locations that stock opname sessions, sparepart requests, goods receipts or work orders
+-- refer to are kept with their history.
+DELETE FROM location l
+WHERE l.deleted_at < sqlc.arg('deleted_before')::timestamptz
+    AND NOT EXISTS (SELECT 1 FROM stock_opname so WHERE so.location_id = l.id)
+    AND NOT EXISTS (
+        SELECT 1 FROM sparepart_request sr
+        WHERE sr.destination_location_id = l.id OR sr.source_location_id = l.id
+    )
+    AND NOT EXISTS (SELECT 1 FROM goods_receipt gr WHERE gr.location_id = l.id)
+    AND NOT EXISTS (SELECT 1 FROM work_order wo WHERE wo.location_id = l.id);
+
+-- name: PurgeSparepartMasters :execrows
+-- Permanently deletes the masters deleted before deleted_before once no item, stock opname
+-- count, sparepart request, goods receipt or work order refers to them, so run it after the
+-- items are purged
+DELETE FROM list_sparepart ls
+WHERE ls.deleted_at < sqlc.arg('deleted_before')::timestamptz
+    AND NOT EXISTS (SELECT 1 FROM sparepart_stock_item ssi WHERE ssi.sparepart_id = ls.id)
+    AND NOT EXISTS (SELECT 1 FROM tools_alker_item tai WHERE tai.tools_id = ls.id)
+    AND NOT EXISTS (SELECT 1 FROM stock_opname_item soi WHERE soi.sparepart_id = ls.id)
+    AND NOT EXISTS (SELECT 1 FROM sparepart_request_item sri WHERE sri.sparepart_id = ls.id)
+    AND NOT EXISTS (SELECT 1 FROM goods_receipt_item gri WHERE gri.sparepart_id = ls.id)
+    AND NOT EXISTS (SELECT 1 FROM work_order_item woi WHERE woi.sparepart_id = ls.id)
+    AND NOT EXISTS (SELECT 1 FROM work_order_tool wot WHERE wot.tools_id = ls.id);
 
 -- name: ListSparepartStocksForExport :many
+-- Read in keyset batches so exports don't hold every row in memory
 SELECT 
     ssi.*,
     l.id as location_id, l.region, l.regency, l.cluster,
-    ls.id as sparepart_id, ls.name as sparepart_name, ls.item_type
+    ls.id as sparepart_id, ls.name as sparepart_name, ls.item_type, ls.unit,
+    cp.pic, cp.phone,
+    w.units_under_warranty, w.units_warranty_expired, w.next_warranty_end::date AS next_warranty_end
 FROM sparepart_stock_item ssi
 JOIN location l ON l.id = ssi.location_id
 JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+-- Primary PIC of the location: its first registered contact person
+LEFT JOIN LATERAL (
+    SELECT c.pic, c.phone FROM contact_person c
+    WHERE c.location_id = l.id AND c.deleted_at IS NULL
+    ORDER BY c.id
+    LIMIT 1
+) cp ON true
+-- Warranty status of the item's registered units; units without a warranty count in neither
+LEFT JOIN LATERAL (
+    SELECT
+        COUNT(*) FILTER (WHERE su.warranty_end > CURRENT_DATE) AS units_under_warranty,
+        COUNT(*) FILTER (WHERE su.warranty_end <= CURRENT_DATE) AS units_warranty_expired,
+        MIN(su.warranty_end) FILTER (WHERE su.warranty_end > CURRENT_DATE) AS next_warranty_end
+    FROM stock_unit su
+    WHERE su.stock_item_id = ssi.id
+) w ON true
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR UPPER(l.region::text) = UPPER($1::text))
-    AND ($2::text IS NULL OR $2 = '' OR l.regency ILIKE '%' || $2 || '%')
-    AND ($3::text IS NULL OR $3 = '' OR l.cluster ILIKE '%' || $3 || '%')
-    AND ($4::text IS NULL OR $4 = '' OR ssi.stock_type::text = $4)
-    AND (
-        $5::text IS NULL OR $5 = '' OR 
-        ssi.sparepart_id IN (
-            SELECT id FROM list_sparepart 
-            WHERE name ILIKE '%' || $5 || '%'
-        )
-    )
-ORDER BY l.region, l.regency, ls.name;
+    ssi.deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('stock_type')::text IS NULL OR ssi.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n))
+    -- Keyset: continue after the last row of the previous batch
+    AND (sqlc.narg('after_id')::int IS NULL OR (l.region, l.regency, ls.name, ssi.id) > (
+        sqlc.narg('after_region')::region_type, sqlc.narg('after_regency')::text, sqlc.narg('after_name')::text, sqlc.narg('after_id')::int
+    ))
+ORDER BY l.region, l.regency, ls.name, ssi.id
+LIMIT sqlc.arg('limit');
+
+-- name: ListSparepartStocksForLabels :many
+SELECT 
+    ssi.id, ssi.location_id, ssi.stock_type, ssi.quantity,
+    l.region, l.regency, l.cluster,
+    ls.name as sparepart_name
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE 
+    ssi.deleted_at IS NULL
+    AND (sqlc.narg('ids')::int[] IS NULL OR ssi.id = ANY(sqlc.narg('ids')::int[]))
+    AND (sqlc.narg('location_id')::int IS NULL OR ssi.location_id = sqlc.narg('location_id'))
+ORDER BY l.region, l.regency, l.cluster, ls.name, ssi.stock_type;
+
+-- name: ListNearestStock :many
+-- Locations holding the sparepart or tool, nearest to the given point first. Distance is the
+-- great-circle (haversine) distance in km; locations without coordinates are left out.
+-- Tools alker count only what is not checked out.
+WITH holdings AS (
+    SELECT
+        ssi.location_id,
+        COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'NEW_STOCK'), 0) AS new_stock_quantity,
+        COALESCE(SUM(ssi.quantity) FILTER (WHERE ssi.stock_type = 'USED_STOCK'), 0) AS used_stock_quantity,
+        0 AS tools_alker_quantity
+    FROM sparepart_stock_item ssi
+    WHERE ssi.sparepart_id = sqlc.arg('sparepart_id') AND ssi.deleted_at IS NULL
+    GROUP BY ssi.location_id
+
+    UNION ALL
+
+    SELECT
+        tai.location_id, 0, 0,
+        tai.quantity - (SELECT COALESCE(SUM(tac.quantity), 0) FROM tools_alker_checkout tac WHERE tac.tools_alker_item_id = tai.id AND tac.checked_in_at IS NULL)
+    FROM tools_alker_item tai
+    WHERE tai.tools_id = sqlc.arg('sparepart_id')
+), per_location AS (
+    SELECT
+        location_id,
+        SUM(new_stock_quantity) AS new_stock_quantity,
+        SUM(used_stock_quantity) AS used_stock_quantity,
+        SUM(tools_alker_quantity) AS tools_alker_quantity
+    FROM holdings
+    GROUP BY location_id
+)
+SELECT
+    l.id AS location_id, l.region, l.regency, l.cluster,
+    l.latitude::float8 AS latitude, l.longitude::float8 AS longitude,
+    (6371 * 2 * ASIN(SQRT(
+        POWER(SIN(RADIANS(l.latitude - sqlc.arg('lat')::float8) / 2), 2)
+        + COS(RADIANS(sqlc.arg('lat')::float8)) * COS(RADIANS(l.latitude))
+        * POWER(SIN(RADIANS(l.longitude - sqlc.arg('lng')::float8) / 2), 2)
+    )))::float8 AS distance_km,
+    pl.new_stock_quantity::bigint AS new_stock_quantity,
+    pl.used_stock_quantity::bigint AS used_stock_quantity,
+    pl.tools_alker_quantity::bigint AS tools_alker_quantity
+FROM per_location pl
+JOIN location l ON l.id = pl.location_id
+WHERE
+    l.deleted_at IS NULL
+    AND l.latitude IS NOT NULL AND l.longitude IS NOT NULL
+    AND pl.new_stock_quantity + pl.used_stock_quantity + pl.tools_alker_quantity > 0
+ORDER BY distance_km, l.id
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,55 @@
+-- name: CreateStockDisposal :one
+INSERT INTO stock_disposal (
+    stock_item_id, location_id, sparepart_id, stock_type, quantity, quantity_after, reason,
+    approved_by, photos, disposed_by
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetStockDisposal :one
+SELECT d.*, l.region, l.regency, l.cluster, ls.name AS sparepart_name
+FROM stock_disposal d
+LEFT JOIN location l ON l.id = d.location_id
+LEFT JOIN list_sparepart ls ON ls.id = d.sparepart_id
+WHERE d.id = $1;
+
+-- name: ListStockDisposals :many
+SELECT d.*, l.region, l.regency, l.cluster, ls.name AS sparepart_name
+FROM stock_disposal d
+LEFT JOIN location l ON l.id = d.location_id
+LEFT JOIN list_sparepart ls ON ls.id = d.sparepart_id
+WHERE (sqlc.narg('location_id')::int IS NULL OR d.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR d.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('stock_type')::text IS NULL OR d.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR d.created_at >= sqlc.narg('since')::timestamptz)
+    AND (sqlc.narg('until')::timestamptz IS NULL OR d.created_at < sqlc.narg('until')::timestamptz)
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountStockDisposals :one
+SELECT COUNT(*) FROM stock_disposal d
+WHERE (sqlc.narg('location_id')::int IS NULL OR d.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR d.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('stock_type')::text IS NULL OR d.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR d.created_at >= sqlc.narg('since')::timestamptz)
+    AND (sqlc.narg('until')::timestamptz IS NULL OR d.created_at < sqlc.narg('until')::timestamptz);
+
+-- name: SummarizeStockDisposals :many
+-- Disposed quantities per location, sparepart and stock type, largest first
+SELECT
+    d.location_id, l.region, l.regency, l.cluster,
+    d.sparepart_id, ls.name AS sparepart_name, d.stock_type,
+    COUNT(*) AS disposals,
+    SUM(d.quantity)::bigint AS total_quantity,
+    MIN(d.created_at)::timestamptz AS first_disposed_at,
+    MAX(d.created_at)::timestamptz AS last_disposed_at
+FROM stock_disposal d
+LEFT JOIN location l ON l.id = d.location_id
+LEFT JOIN list_sparepart ls ON ls.id = d.sparepart_id
+WHERE (sqlc.narg('location_id')::int IS NULL OR d.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR d.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('stock_type')::text IS NULL OR d.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR d.created_at >= sqlc.narg('since')::timestamptz)
+    AND (sqlc.narg('until')::timestamptz IS NULL OR d.created_at < sqlc.narg('until')::timestamptz)
+GROUP BY d.location_id, l.region, l.regency, l.cluster, d.sparepart_id, ls.name, d.stock_type
+ORDER BY total_quantity DESC, d.location_id, d.sparepart_id, d.stock_type;
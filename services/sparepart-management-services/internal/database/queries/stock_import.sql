@@ -0,0 +1,47 @@
+-- name: ListLocationsForImport :many
+-- Locations an import refers to, by id or by cluster name (case-insensitive)
+SELECT * FROM location
+WHERE deleted_at IS NULL
+  AND (id = ANY(sqlc.arg('ids')::int[]) OR LOWER(cluster) = ANY(sqlc.arg('clusters')::text[]));
+
+-- name: ListSparepartMastersByNames :many
+-- Sparepart masters an import refers to; names are matched case-insensitively
+SELECT * FROM list_sparepart
+WHERE item_type = 'SPAREPART'
+  AND deleted_at IS NULL
+  AND LOWER(name) = ANY(sqlc.arg('names')::text[]);
+
+-- name: CopySparepartStockImport :copyfrom
+-- Stages the rows of a stock import; see CreateSparepartStocksFromImport
+INSERT INTO sparepart_stock_import (line, location_id, sparepart_id, stock_type, quantity, notes)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListSparepartStockImportConflicts :many
+-- Lines of the staged rows whose location, sparepart and stock type already have a stock
+-- item; soft deleted items don't hold their key
+SELECT s.line
+FROM sparepart_stock_import s
+JOIN sparepart_stock_item ssi
+  ON ssi.location_id = s.location_id
+ AND ssi.sparepart_id = s.sparepart_id
+ AND ssi.stock_type = s.stock_type
+ AND ssi.deleted_at IS NULL
+WHERE s.import_tx = txid_current()
+ORDER BY s.line;
+
+-- name: CreateSparepartStocksFromImport :many
+-- Moves the staged rows of the transaction into stock items, in line order. Rows whose
+-- location or sparepart was deleted since the import looked them up are dropped, so fewer
+-- items than staged rows means the import went stale. The master's unit cost applies.
+WITH staged AS (
+    DELETE FROM sparepart_stock_import
+    WHERE import_tx = txid_current()
+    RETURNING *
+)
+INSERT INTO sparepart_stock_item (location_id, sparepart_id, stock_type, quantity, notes)
+SELECT s.location_id, s.sparepart_id, s.stock_type, s.quantity, NULLIF(s.notes, '')
+FROM staged s
+JOIN location l ON l.id = s.location_id AND l.deleted_at IS NULL
+JOIN list_sparepart ls ON ls.id = s.sparepart_id AND ls.item_type = 'SPAREPART' AND ls.deleted_at IS NULL
+ORDER BY s.line
+RETURNING *;
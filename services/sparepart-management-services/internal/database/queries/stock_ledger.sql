@@ -0,0 +1,191 @@
+-- name: ListStockTrend :many
+-- Quantity per location over time, bucketed by day, week or month. The quantity of a
+-- bucket is the running total of all ledger changes up to its end, so history before
+-- `since` still counts. Buckets without changes are omitted.
+WITH buckets AS (
+    SELECT
+        sl.location_id,
+        date_trunc(sqlc.arg('interval')::text, sl.recorded_at) AS period,
+        SUM(sl.quantity_change)::bigint AS net_change,
+        COALESCE(SUM(sl.quantity_change) FILTER (WHERE sl.quantity_change > 0), 0)::bigint AS restocked,
+        COALESCE(-SUM(sl.quantity_change) FILTER (WHERE sl.quantity_change < 0), 0)::bigint AS consumed
+    FROM stock_ledger sl
+    WHERE
+        (sqlc.narg('sparepart_id')::int IS NULL OR sl.sparepart_id = sqlc.narg('sparepart_id')::int)
+        AND (sqlc.narg('location_id')::int IS NULL OR sl.location_id = sqlc.narg('location_id')::int)
+        AND (sqlc.narg('stock_type')::text IS NULL OR sl.stock_type::text = sqlc.narg('stock_type'))
+        AND sl.recorded_at < sqlc.arg('until')::timestamptz
+    GROUP BY sl.location_id, period
+), series AS (
+    SELECT
+        b.location_id,
+        b.period,
+        SUM(b.net_change) OVER (PARTITION BY b.location_id ORDER BY b.period)::bigint AS quantity,
+        b.restocked,
+        b.consumed
+    FROM buckets b
+)
+SELECT
+    s.location_id, l.region, l.regency, l.cluster,
+    s.period::timestamptz AS period, s.quantity, s.restocked, s.consumed
+FROM series s
+JOIN location l ON l.id = s.location_id
+WHERE s.period >= date_trunc(sqlc.arg('interval')::text, sqlc.arg('since')::timestamptz)
+ORDER BY l.region, l.regency, l.cluster, s.period;
+
+-- name: ListStockConsumption :many
+-- Quantity consumed per location and sparepart since `since`, next to the current quantity.
+-- Consumption is the sum of ledger decreases minus the quantity transferred out to other
+-- locations, which moves stock rather than using it. Pairs without consumption are omitted.
+-- Without a stock type the current quantity is the available stock, NEW_STOCK and USED_STOCK.
+WITH decreases AS (
+    SELECT sl.location_id, sl.sparepart_id, -SUM(sl.quantity_change)::bigint AS quantity
+    FROM stock_ledger sl
+    WHERE
+        sl.quantity_change < 0
+        AND sl.recorded_at >= sqlc.arg('since')::timestamptz
+        AND (sqlc.narg('sparepart_id')::int IS NULL OR sl.sparepart_id = sqlc.narg('sparepart_id')::int)
+        AND (sqlc.narg('location_id')::int IS NULL OR sl.location_id = sqlc.narg('location_id')::int)
+        AND (sqlc.narg('stock_type')::text IS NULL OR sl.stock_type::text = sqlc.narg('stock_type'))
+    GROUP BY sl.location_id, sl.sparepart_id
+), transfers AS (
+    SELECT st.source_location_id AS location_id, st.sparepart_id, SUM(st.quantity)::bigint AS quantity
+    FROM stock_transfer st
+    WHERE
+        st.created_at >= sqlc.arg('since')::timestamptz
+        AND (sqlc.narg('sparepart_id')::int IS NULL OR st.sparepart_id = sqlc.narg('sparepart_id')::int)
+        AND (sqlc.narg('location_id')::int IS NULL OR st.source_location_id = sqlc.narg('location_id')::int)
+        AND (sqlc.narg('stock_type')::text IS NULL OR st.stock_type::text = sqlc.narg('stock_type'))
+    GROUP BY st.source_location_id, st.sparepart_id
+), stock AS (
+    SELECT ssi.location_id, ssi.sparepart_id, SUM(ssi.quantity)::bigint AS quantity
+    FROM sparepart_stock_item ssi
+    WHERE
+        ssi.deleted_at IS NULL
+        AND (
+            (sqlc.narg('stock_type')::text IS NULL AND ssi.stock_type IN ('NEW_STOCK', 'USED_STOCK'))
+            OR ssi.stock_type::text = sqlc.narg('stock_type')
+        )
+    GROUP BY ssi.location_id, ssi.sparepart_id
+)
+SELECT
+    d.location_id, l.region, l.regency, l.cluster,
+    d.sparepart_id, ls.name AS sparepart_name,
+    (d.quantity - COALESCE(t.quantity, 0))::bigint AS consumed,
+    COALESCE(s.quantity, 0)::bigint AS current_quantity
+FROM decreases d
+JOIN location l ON l.id = d.location_id
+JOIN list_sparepart ls ON ls.id = d.sparepart_id
+LEFT JOIN transfers t ON t.location_id = d.location_id AND t.sparepart_id = d.sparepart_id
+LEFT JOIN stock s ON s.location_id = d.location_id AND s.sparepart_id = d.sparepart_id
+WHERE
+    l.deleted_at IS NULL
+    AND l.is_active
+    AND d.quantity > COALESCE(t.quantity, 0)
+ORDER BY l.region, l.regency, l.cluster, ls.name;
+
+-- name: ListStockMovements :many
+-- Stock ledger rows, newest first. An increase is attributed to the goods receipt whose
+-- confirmation wrote it: confirming stores the stock item and its quantity afterwards on the
+-- receipt item, in the same transaction and so at the same timestamp as the ledger row.
+-- Decreases are attributed to disposals and to the work orders whose closing consumed them
+-- the same way.
+SELECT
+    sl.id, sl.stock_item_id, sl.location_id, l.region, l.regency, l.cluster,
+    sl.sparepart_id, ls.name AS sparepart_name, sl.stock_type,
+    sl.quantity_change, sl.quantity_after, sl.recorded_at,
+    gr.id AS receipt_id, gr.delivery_order_number, s.id AS supplier_id, s.name AS supplier_name,
+    dp.id AS disposal_id, dp.reason AS disposal_reason, dp.approved_by AS disposal_approved_by,
+    wk.id AS work_order_id, wk.title AS work_order_title
+FROM stock_ledger sl
+JOIN location l ON l.id = sl.location_id
+JOIN list_sparepart ls ON ls.id = sl.sparepart_id
+LEFT JOIN LATERAL (
+    SELECT r.id, r.supplier_id, r.delivery_order_number
+    FROM goods_receipt_item gri
+    JOIN goods_receipt r ON r.id = gri.receipt_id
+    WHERE gri.stock_item_id = sl.stock_item_id
+        AND gri.quantity_after = sl.quantity_after
+        AND r.confirmed_at = sl.recorded_at
+        AND sl.quantity_change > 0
+    LIMIT 1
+) gr ON true
+LEFT JOIN LATERAL (
+    SELECT d.id, d.reason, d.approved_by
+    FROM stock_disposal d
+    WHERE d.stock_item_id = sl.stock_item_id
+        AND d.quantity_after = sl.quantity_after
+        AND d.created_at = sl.recorded_at
+        AND sl.quantity_change < 0
+    LIMIT 1
+) dp ON true
+LEFT JOIN LATERAL (
+    SELECT w.id, w.title
+    FROM work_order_item woi
+    JOIN work_order w ON w.id = woi.work_order_id
+    WHERE woi.stock_item_id = sl.stock_item_id
+        AND woi.quantity_after = sl.quantity_after
+        AND w.closed_at = sl.recorded_at
+        AND sl.quantity_change < 0
+    LIMIT 1
+) wk ON true
+LEFT JOIN supplier s ON s.id = gr.supplier_id
+WHERE
+    (sqlc.narg('sparepart_id')::int IS NULL OR sl.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('location_id')::int IS NULL OR sl.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('stock_type')::text IS NULL OR sl.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('supplier_id')::int IS NULL OR s.id = sqlc.narg('supplier_id')::int)
+    AND (sqlc.narg('movement_type')::text IS NULL
+        OR (sqlc.narg('movement_type') = 'RECEIPT' AND gr.id IS NOT NULL)
+        OR (sqlc.narg('movement_type') = 'DISPOSAL' AND dp.id IS NOT NULL)
+        OR (sqlc.narg('movement_type') = 'WORK_ORDER' AND wk.id IS NOT NULL))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR sl.recorded_at >= sqlc.narg('since')::timestamptz)
+    AND (sqlc.narg('until')::timestamptz IS NULL OR sl.recorded_at < sqlc.narg('until')::timestamptz)
+ORDER BY sl.recorded_at DESC, sl.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountStockMovements :one
+SELECT COUNT(*)
+FROM stock_ledger sl
+JOIN location l ON l.id = sl.location_id
+JOIN list_sparepart ls ON ls.id = sl.sparepart_id
+LEFT JOIN LATERAL (
+    SELECT r.id, r.supplier_id
+    FROM goods_receipt_item gri
+    JOIN goods_receipt r ON r.id = gri.receipt_id
+    WHERE gri.stock_item_id = sl.stock_item_id
+        AND gri.quantity_after = sl.quantity_after
+        AND r.confirmed_at = sl.recorded_at
+        AND sl.quantity_change > 0
+    LIMIT 1
+) gr ON true
+LEFT JOIN LATERAL (
+    SELECT d.id
+    FROM stock_disposal d
+    WHERE d.stock_item_id = sl.stock_item_id
+        AND d.quantity_after = sl.quantity_after
+        AND d.created_at = sl.recorded_at
+        AND sl.quantity_change < 0
+    LIMIT 1
+) dp ON true
+LEFT JOIN LATERAL (
+    SELECT w.id
+    FROM work_order_item woi
+    JOIN work_order w ON w.id = woi.work_order_id
+    WHERE woi.stock_item_id = sl.stock_item_id
+        AND woi.quantity_after = sl.quantity_after
+        AND w.closed_at = sl.recorded_at
+        AND sl.quantity_change < 0
+    LIMIT 1
+) wk ON true
+WHERE
+    (sqlc.narg('sparepart_id')::int IS NULL OR sl.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('location_id')::int IS NULL OR sl.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('stock_type')::text IS NULL OR sl.stock_type::text = sqlc.narg('stock_type'))
+    AND (sqlc.narg('supplier_id')::int IS NULL OR gr.supplier_id = sqlc.narg('supplier_id')::int)
+    AND (sqlc.narg('movement_type')::text IS NULL
+        OR (sqlc.narg('movement_type') = 'RECEIPT' AND gr.id IS NOT NULL)
+        OR (sqlc.narg('movement_type') = 'DISPOSAL' AND dp.id IS NOT NULL)
+        OR (sqlc.narg('movement_type') = 'WORK_ORDER' AND wk.id IS NOT NULL))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR sl.recorded_at >= sqlc.narg('since')::timestamptz)
+    AND (sqlc.narg('until')::timestamptz IS NULL OR sl.recorded_at < sqlc.narg('until')::timestamptz);
@@ -0,0 +1,65 @@
+-- name: ListStockLevels :many
+SELECT sl.*, l.region, l.regency, l.cluster, ls.name AS sparepart_name, ls.unit
+FROM stock_level sl
+JOIN location l ON l.id = sl.location_id
+JOIN list_sparepart ls ON ls.id = sl.sparepart_id
+WHERE
+    l.deleted_at IS NULL
+    AND (sqlc.narg('location_id')::int IS NULL OR sl.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR sl.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+ORDER BY l.region, l.regency, l.cluster, ls.name
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountStockLevels :one
+SELECT COUNT(*)
+FROM stock_level sl
+JOIN location l ON l.id = sl.location_id
+WHERE
+    l.deleted_at IS NULL
+    AND (sqlc.narg('location_id')::int IS NULL OR sl.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR sl.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text));
+
+-- name: UpsertStockLevel :one
+-- A location has one level per sparepart; setting it again replaces both quantities
+INSERT INTO stock_level (location_id, sparepart_id, min_quantity, max_quantity)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT ON CONSTRAINT unique_stock_level
+DO UPDATE SET min_quantity = EXCLUDED.min_quantity, max_quantity = EXCLUDED.max_quantity
+RETURNING *;
+
+-- name: DeleteStockLevel :execrows
+DELETE FROM stock_level
+WHERE id = $1;
+
+-- name: ListStockLevelPlanning :many
+-- Levels the available stock (NEW_STOCK and USED_STOCK) of their location is outside of:
+-- below the minimum, or above the maximum when there is one. A status keeps only one side.
+SELECT
+    sl.id, sl.location_id, l.region, l.regency, l.cluster,
+    sl.sparepart_id, ls.name AS sparepart_name, ls.unit,
+    sl.min_quantity, sl.max_quantity,
+    COALESCE(s.quantity, 0)::bigint AS quantity
+FROM stock_level sl
+JOIN location l ON l.id = sl.location_id
+JOIN list_sparepart ls ON ls.id = sl.sparepart_id
+LEFT JOIN LATERAL (
+    SELECT SUM(ssi.quantity) AS quantity
+    FROM sparepart_stock_item ssi
+    WHERE ssi.location_id = sl.location_id
+        AND ssi.sparepart_id = sl.sparepart_id
+        AND ssi.deleted_at IS NULL
+        AND ssi.stock_type IN ('NEW_STOCK', 'USED_STOCK')
+) s ON true
+WHERE
+    l.deleted_at IS NULL
+    AND (sqlc.narg('location_id')::int IS NULL OR sl.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR sl.sparepart_id = sqlc.narg('sparepart_id')::int)
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (
+        (COALESCE(sqlc.narg('status')::text, 'BELOW_MIN') = 'BELOW_MIN' AND COALESCE(s.quantity, 0) < sl.min_quantity)
+        OR (COALESCE(sqlc.narg('status')::text, 'ABOVE_MAX') = 'ABOVE_MAX' AND COALESCE(s.quantity, 0) > sl.max_quantity)
+    )
+ORDER BY l.region, l.regency, l.cluster, ls.name;
@@ -0,0 +1,87 @@
+-- name: CreateStockOpname :one
+-- Opens a DRAFT session; returns no row when the location does not exist or is deleted
+INSERT INTO stock_opname (location_id, notes, created_by)
+SELECT l.id, sqlc.narg('notes'), sqlc.narg('created_by')
+FROM location l
+WHERE l.id = sqlc.arg('location_id') AND l.deleted_at IS NULL
+RETURNING *;
+
+-- name: GetStockOpname :one
+SELECT so.*, l.region, l.regency, l.cluster
+FROM stock_opname so
+LEFT JOIN location l ON l.id = so.location_id
+WHERE so.id = $1;
+
+-- name: GetStockOpnameForUpdate :one
+-- Locks the session until the transaction ends, so counts, submission and approval of one
+-- session are serialized
+SELECT * FROM stock_opname
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListStockOpnames :many
+SELECT so.*, l.region, l.regency, l.cluster
+FROM stock_opname so
+LEFT JOIN location l ON l.id = so.location_id
+WHERE (sqlc.narg('location_id')::int IS NULL OR so.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('status')::text IS NULL OR so.status = sqlc.narg('status'))
+ORDER BY so.created_at DESC, so.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountStockOpnames :one
+SELECT COUNT(*) FROM stock_opname so
+WHERE (sqlc.narg('location_id')::int IS NULL OR so.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('status')::text IS NULL OR so.status = sqlc.narg('status'));
+
+-- name: UpsertStockOpnameItem :one
+-- Records a count with the location's current system quantity (0 without a stock item);
+-- counting the same sparepart and stock type again replaces the count. Returns no row
+-- when the sparepart does not exist.
+INSERT INTO stock_opname_item (opname_id, sparepart_id, stock_type, system_quantity, counted_quantity)
+SELECT so.id, ls.id, sqlc.arg('stock_type')::stock_type, COALESCE(ssi.quantity, 0), sqlc.arg('counted_quantity')::int
+FROM stock_opname so
+JOIN list_sparepart ls ON ls.id = sqlc.arg('sparepart_id')::int AND ls.deleted_at IS NULL
+LEFT JOIN sparepart_stock_item ssi ON ssi.location_id = so.location_id
+    AND ssi.sparepart_id = ls.id
+    AND ssi.stock_type = sqlc.arg('stock_type')::stock_type
+    AND ssi.deleted_at IS NULL
+WHERE so.id = sqlc.arg('opname_id')
+ON CONFLICT ON CONSTRAINT unique_stock_opname_item
+DO UPDATE SET
+    system_quantity = EXCLUDED.system_quantity,
+    counted_quantity = EXCLUDED.counted_quantity,
+    counted_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListStockOpnameItems :many
+SELECT soi.*, ls.name AS sparepart_name
+FROM stock_opname_item soi
+LEFT JOIN list_sparepart ls ON ls.id = soi.sparepart_id
+WHERE soi.opname_id = $1
+ORDER BY ls.name, soi.stock_type, soi.id;
+
+-- name: SubmitStockOpname :one
+UPDATE stock_opname
+SET status = 'SUBMITTED', submitted_by = $2, submitted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'DRAFT'
+RETURNING *;
+
+-- name: ApproveStockOpname :one
+UPDATE stock_opname
+SET status = 'APPROVED', approved_by = $2, approved_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'SUBMITTED'
+RETURNING *;
+
+-- name: AdjustSparepartStock :one
+-- Applies a counted variance to the location's stock row, creating it when the location has
+-- none yet; a soft deleted row stays deleted and a new one is created
+INSERT INTO sparepart_stock_item (location_id, sparepart_id, stock_type, quantity)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (location_id, sparepart_id, stock_type) WHERE deleted_at IS NULL
+DO UPDATE SET quantity = sparepart_stock_item.quantity + EXCLUDED.quantity
+RETURNING *;
+
+-- name: SetStockOpnameItemAdjustment :exec
+UPDATE stock_opname_item
+SET stock_item_id = $2, quantity_after = $3
+WHERE id = $1;
@@ -0,0 +1,53 @@
+-- name: RecordStockSnapshot :execrows
+-- Records today's (UTC) quantity of every live stock item, replacing an earlier run of the day
+INSERT INTO stock_snapshot (snapshot_date, location_id, sparepart_id, stock_type, quantity)
+SELECT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC')::date, location_id, sparepart_id, stock_type, quantity
+FROM sparepart_stock_item
+WHERE deleted_at IS NULL
+ON CONFLICT (snapshot_date, location_id, sparepart_id, stock_type)
+DO UPDATE SET quantity = EXCLUDED.quantity, recorded_at = CURRENT_TIMESTAMP;
+
+-- name: ListStockSnapshotTrend :many
+-- ListStockTrend read from the daily snapshots: the quantity of a bucket is the location total
+-- on its last snapshot day. Restocked and consumed are the day-over-day increases and
+-- decreases per item, so movements within a day net out.
+WITH item_days AS (
+    SELECT
+        ss.location_id,
+        ss.snapshot_date,
+        ss.quantity,
+        ss.quantity - COALESCE(LAG(ss.quantity) OVER (
+            PARTITION BY ss.location_id, ss.sparepart_id, ss.stock_type ORDER BY ss.snapshot_date
+        ), 0) AS change
+    FROM stock_snapshot ss
+    WHERE
+        (sqlc.narg('sparepart_id')::int IS NULL OR ss.sparepart_id = sqlc.narg('sparepart_id')::int)
+        AND (sqlc.narg('location_id')::int IS NULL OR ss.location_id = sqlc.narg('location_id')::int)
+        AND (sqlc.narg('stock_type')::text IS NULL OR ss.stock_type::text = sqlc.narg('stock_type'))
+        AND ss.snapshot_date < sqlc.arg('until')::timestamptz::date
+), location_days AS (
+    SELECT
+        location_id,
+        snapshot_date,
+        SUM(quantity)::bigint AS quantity,
+        COALESCE(SUM(change) FILTER (WHERE change > 0), 0)::bigint AS restocked,
+        COALESCE(-SUM(change) FILTER (WHERE change < 0), 0)::bigint AS consumed
+    FROM item_days
+    WHERE snapshot_date >= date_trunc(sqlc.arg('interval')::text, sqlc.arg('since')::timestamptz)::date
+    GROUP BY location_id, snapshot_date
+), buckets AS (
+    SELECT
+        location_id,
+        date_trunc(sqlc.arg('interval')::text, snapshot_date) AS period,
+        (array_agg(quantity ORDER BY snapshot_date DESC))[1] AS quantity,
+        SUM(restocked)::bigint AS restocked,
+        SUM(consumed)::bigint AS consumed
+    FROM location_days
+    GROUP BY location_id, period
+)
+SELECT
+    b.location_id, l.region, l.regency, l.cluster,
+    b.period::timestamptz AS period, b.quantity, b.restocked, b.consumed
+FROM buckets b
+JOIN location l ON l.id = b.location_id
+ORDER BY l.region, l.regency, l.cluster, b.period;
@@ -0,0 +1,48 @@
+-- name: ListStockSummaryByLocation :many
+SELECT * FROM stock_summary_by_location
+WHERE 
+    (sqlc.narg('region')::text IS NULL OR UPPER(region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+ORDER BY region, regency, cluster;
+
+-- name: ListStockSummaryBySparepart :many
+SELECT * FROM stock_summary_by_sparepart
+ORDER BY sparepart_name;
+
+-- name: ListStockSummaryByRegion :many
+SELECT * FROM stock_summary_by_region
+ORDER BY region;
+
+-- name: RefreshStockSummaryByLocation :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY stock_summary_by_location;
+
+-- name: RefreshStockSummaryBySparepart :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY stock_summary_by_sparepart;
+
+-- name: RefreshStockSummaryByRegion :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY stock_summary_by_region;
+
+-- name: ListStockValuation :many
+-- Inventory value per location: every stock item's quantity at its own unit cost, or at the
+-- master's when it has none. Items without either add nothing and are counted in
+-- items_without_cost. Read live rather than from the views, the value must be current.
+SELECT
+    l.id AS location_id, l.region, l.regency, l.cluster,
+    COUNT(*) AS item_count,
+    SUM(ssi.quantity)::bigint AS quantity,
+    COALESCE(SUM(ssi.quantity * COALESCE(ssi.unit_cost, ls.unit_cost)), 0)::numeric AS total_value,
+    COUNT(*) FILTER (WHERE COALESCE(ssi.unit_cost, ls.unit_cost) IS NULL) AS items_without_cost
+FROM sparepart_stock_item ssi
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE
+    ssi.deleted_at IS NULL
+    AND l.deleted_at IS NULL
+    AND ssi.quantity > 0
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('stock_type')::text IS NULL OR ssi.stock_type::text = sqlc.narg('stock_type'))
+GROUP BY l.id
+ORDER BY l.region, l.regency, l.cluster;
@@ -0,0 +1,59 @@
+-- name: GetSparepartStockByKeyForUpdate :one
+-- Locks the source row until the transfer's transaction ends
+SELECT * FROM sparepart_stock_item
+WHERE location_id = $1 AND sparepart_id = $2 AND stock_type = $3 AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: GetActiveLocationForShare :one
+-- The destination of a transfer; locked so it can't be deactivated or deleted until the
+-- transfer's transaction ends
+SELECT * FROM location
+WHERE id = $1 AND is_active AND deleted_at IS NULL
+FOR SHARE;
+
+-- name: TransferOutSparepartStock :one
+UPDATE sparepart_stock_item
+SET quantity = quantity - sqlc.arg('quantity')::int
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: TransferInSparepartStock :one
+-- Adds to the destination's stock row, creating it when the location has none yet; a soft
+-- deleted row stays deleted and a new one is created
+INSERT INTO sparepart_stock_item (location_id, sparepart_id, stock_type, quantity)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (location_id, sparepart_id, stock_type) WHERE deleted_at IS NULL
+DO UPDATE SET quantity = sparepart_stock_item.quantity + EXCLUDED.quantity
+RETURNING *;
+
+-- name: CreateStockTransfer :one
+INSERT INTO stock_transfer (
+    sparepart_id, stock_type, quantity, source_location_id, destination_location_id,
+    source_stock_id, destination_stock_id, notes, transferred_by
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: ListStockTransfers :many
+SELECT
+    st.*,
+    ls.name AS sparepart_name,
+    src.cluster AS source_cluster,
+    dst.cluster AS destination_cluster
+FROM stock_transfer st
+LEFT JOIN list_sparepart ls ON ls.id = st.sparepart_id
+LEFT JOIN location src ON src.id = st.source_location_id
+LEFT JOIN location dst ON dst.id = st.destination_location_id
+WHERE (sqlc.narg('location_id')::int IS NULL
+        OR st.source_location_id = sqlc.narg('location_id')::int
+        OR st.destination_location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR st.sparepart_id = sqlc.narg('sparepart_id')::int)
+ORDER BY st.created_at DESC, st.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountStockTransfers :one
+SELECT COUNT(*) FROM stock_transfer st
+WHERE (sqlc.narg('location_id')::int IS NULL
+        OR st.source_location_id = sqlc.narg('location_id')::int
+        OR st.destination_location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR st.sparepart_id = sqlc.narg('sparepart_id')::int);
@@ -0,0 +1,89 @@
+-- name: GetSparepartStockForUpdate :one
+-- Locks the stock item until the unit registration's transaction ends, so concurrent
+-- registrations cannot exceed its quantity
+SELECT * FROM sparepart_stock_item
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: CountStockUnits :one
+SELECT COUNT(*) FROM stock_unit WHERE stock_item_id = $1;
+
+-- name: ListStockUnitConflicts :many
+-- The registered units already holding any of the serial numbers or asset tags, wherever
+-- they are stocked
+SELECT su.id, su.serial_number, su.asset_tag, su.stock_item_id, ssi.location_id, l.cluster
+FROM stock_unit su
+JOIN sparepart_stock_item ssi ON ssi.id = su.stock_item_id
+JOIN location l ON l.id = ssi.location_id
+WHERE su.serial_number = ANY(sqlc.arg('serial_numbers')::text[])
+    OR su.asset_tag = ANY(sqlc.arg('asset_tags')::text[]);
+
+-- name: CreateStockUnit :one
+INSERT INTO stock_unit (stock_item_id, serial_number, asset_tag, notes, registered_by, supplier_id, warranty_start, warranty_months)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListStockUnits :many
+SELECT * FROM stock_unit
+WHERE stock_item_id = $1
+ORDER BY serial_number;
+
+-- name: ScanStockUnit :one
+-- Finds the unit by serial number or asset tag, with the stock item it belongs to
+SELECT
+    su.*,
+    ssi.location_id, ssi.sparepart_id, ssi.stock_type,
+    l.region, l.regency, l.cluster,
+    ls.name AS sparepart_name
+FROM stock_unit su
+JOIN sparepart_stock_item ssi ON ssi.id = su.stock_item_id
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+WHERE (su.serial_number = sqlc.arg('code') OR su.asset_tag = sqlc.arg('code'))
+    AND ssi.deleted_at IS NULL
+LIMIT 1;
+
+-- name: SetStockUnitWarranty :one
+-- Replaces the unit's warranty; a NULL start and length clear it
+UPDATE stock_unit
+SET supplier_id = $3, warranty_start = $4, warranty_months = $5
+WHERE id = $1 AND stock_item_id = $2
+RETURNING *;
+
+-- name: ListExpiringWarranties :many
+-- Registered units whose warranty ends after today and no later than the until date, the
+-- soonest first
+SELECT
+    su.id, su.stock_item_id, su.serial_number, su.asset_tag,
+    su.supplier_id, s.name AS supplier_name,
+    su.warranty_start, su.warranty_months, su.warranty_end,
+    ssi.location_id, ssi.sparepart_id, ssi.stock_type,
+    l.region, l.regency, l.cluster,
+    ls.name AS sparepart_name
+FROM stock_unit su
+JOIN sparepart_stock_item ssi ON ssi.id = su.stock_item_id
+JOIN location l ON l.id = ssi.location_id
+JOIN list_sparepart ls ON ls.id = ssi.sparepart_id
+LEFT JOIN supplier s ON s.id = su.supplier_id
+WHERE su.warranty_end > sqlc.arg('today')::date
+    AND su.warranty_end <= sqlc.arg('until')::date
+    AND ssi.deleted_at IS NULL
+    AND (sqlc.narg('location_id')::int IS NULL OR ssi.location_id = sqlc.narg('location_id'))
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR ssi.sparepart_id = sqlc.narg('sparepart_id'))
+    AND (sqlc.narg('supplier_id')::int IS NULL OR su.supplier_id = sqlc.narg('supplier_id'))
+ORDER BY su.warranty_end, su.serial_number
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountExpiringWarranties :one
+SELECT COUNT(*)
+FROM stock_unit su
+JOIN sparepart_stock_item ssi ON ssi.id = su.stock_item_id
+WHERE su.warranty_end > sqlc.arg('today')::date
+    AND su.warranty_end <= sqlc.arg('until')::date
+    AND ssi.deleted_at IS NULL
+    AND (sqlc.narg('location_id')::int IS NULL OR ssi.location_id = sqlc.narg('location_id'))
+    AND (sqlc.narg('sparepart_id')::int IS NULL OR ssi.sparepart_id = sqlc.narg('sparepart_id'))
+    AND (sqlc.narg('supplier_id')::int IS NULL OR su.supplier_id = sqlc.narg('supplier_id'));
+
+-- name: DeleteStockUnit :execrows
+DELETE FROM stock_unit WHERE id = $1 AND stock_item_id = $2;
@@ -0,0 +1,14 @@
+-- name: ListLocationPhotoPaths :many
+-- Photo paths of the stock and tools alker items of every location, for the storage usage
+-- report. Deleted items are included, their photos are stored until they are purged; a photo
+-- shared by several items of a location is listed once.
+SELECT DISTINCT p.location_id, l.region, l.regency, l.cluster, photo_url(p.photo)::text AS path
+FROM (
+    SELECT ssi.location_id, jsonb_array_elements(ssi.documentation) AS photo
+    FROM sparepart_stock_item ssi
+    UNION ALL
+    SELECT tai.location_id, jsonb_array_elements(tai.documentation)
+    FROM tools_alker_item tai
+) p
+JOIN location l ON l.id = p.location_id
+ORDER BY p.location_id, path;
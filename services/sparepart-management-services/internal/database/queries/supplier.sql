@@ -0,0 +1,30 @@
+-- name: GetSupplier :one
+SELECT * FROM supplier
+WHERE id = $1 LIMIT 1;
+
+-- name: ListSuppliers :many
+SELECT * FROM supplier
+WHERE sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%'
+ORDER BY name
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountSuppliers :one
+SELECT COUNT(*) FROM supplier
+WHERE sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%';
+
+-- name: CreateSupplier :one
+INSERT INTO supplier (name, contact_person, phone, email, address, notes)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: UpdateSupplier :one
+UPDATE supplier
+SET name = $2, contact_person = $3, phone = $4, email = $5, address = $6, notes = $7
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSupplier :exec
+-- Fails with a foreign key violation while goods receipts still reference the supplier
+DELETE FROM supplier
+WHERE id = $1;
@@ -0,0 +1,33 @@
+-- name: GetTechnician :one
+SELECT * FROM technician
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTechnicians :many
+SELECT * FROM technician
+WHERE (sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%')
+    AND (sqlc.narg('region')::text IS NULL OR sqlc.narg('region')::text = ANY(regions))
+ORDER BY name
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountTechnicians :one
+SELECT COUNT(*) FROM technician
+WHERE (sqlc.narg('name')::text IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%')
+    AND (sqlc.narg('region')::text IS NULL OR sqlc.narg('region')::text = ANY(regions));
+
+-- name: CreateTechnician :one
+INSERT INTO technician (name, phone, regions)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: UpdateTechnician :one
+UPDATE technician
+SET name = $2, phone = $3, regions = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteTechnician :exec
+-- Fails with a foreign key violation while checkouts, work orders or sparepart requests still
+-- reference the technician
+DELETE FROM technician
+WHERE id = $1;
@@ -1,35 +1,71 @@
 -- name: GetToolsAlker :one
 SELECT 
-    tai.id, tai.location_id, tai.tools_id, tai.quantity, tai.documentation, tai.notes, tai.created_at, tai.updated_at,
+    tai.id, tai.location_id, tai.tools_id, tai.quantity, tai.documentation, tai.notes, tai.created_at, tai.updated_at, tai.version,
     l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
-    ls.id as tools_id_2, ls.name as tools_name, ls.item_type, ls.created_at as tools_created_at, ls.updated_at as tools_updated_at
+    ls.id as tools_id_2, ls.name as tools_name, ls.item_type, ls.created_at as tools_created_at, ls.updated_at as tools_updated_at,
+    (SELECT COALESCE(SUM(tac.quantity), 0) FROM tools_alker_checkout tac WHERE tac.tools_alker_item_id = tai.id AND tac.checked_in_at IS NULL)::int AS checked_out
 FROM tools_alker_item tai
 JOIN location l ON l.id = tai.location_id
 JOIN list_sparepart ls ON ls.id = tai.tools_id
-WHERE tai.id = $1 LIMIT 1;
+WHERE tai.id = $1 AND tai.deleted_at IS NULL AND l.deleted_at IS NULL LIMIT 1;
 
 -- name: ListToolsAlkers :many
+-- Paginates by location: LIMIT/OFFSET select a page of locations, then every matching tools alker item of those locations is returned
+WITH paged_locations AS (
+    SELECT DISTINCT tai.location_id
+    FROM tools_alker_item tai
+    JOIN location l ON l.id = tai.location_id
+    JOIN list_sparepart ls ON ls.id = tai.tools_id
+    WHERE 
+        tai.deleted_at IS NULL
+        AND l.deleted_at IS NULL
+        AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+        AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+        AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+        AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n))
+    ORDER BY tai.location_id
+    LIMIT sqlc.arg('limit')
+    OFFSET sqlc.arg('offset')
+)
 SELECT 
-    tai.id, tai.location_id, tai.tools_id, tai.quantity, tai.documentation, tai.notes, tai.created_at, tai.updated_at,
+    tai.id, tai.location_id, tai.tools_id, tai.quantity, tai.documentation, tai.notes, tai.created_at, tai.updated_at, tai.version,
     l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
-    ls.id as tools_id_2, ls.name as tools_name, ls.item_type, ls.created_at as tools_created_at, ls.updated_at as tools_updated_at
-FROM tools_alker_item tai
+    ls.id as tools_id_2, ls.name as tools_name, ls.item_type, ls.created_at as tools_created_at, ls.updated_at as tools_updated_at,
+    (SELECT COALESCE(SUM(tac.quantity), 0) FROM tools_alker_checkout tac WHERE tac.tools_alker_item_id = tai.id AND tac.checked_in_at IS NULL)::int AS checked_out
+FROM paged_locations pl
+JOIN tools_alker_item tai ON tai.location_id = pl.location_id
 JOIN location l ON l.id = tai.location_id
 JOIN list_sparepart ls ON ls.id = tai.tools_id
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR UPPER(l.region::text) = UPPER($1::text))
-    AND ($2::text IS NULL OR $2 = '' OR l.regency ILIKE '%' || $2 || '%')
-    AND ($3::text IS NULL OR $3 = '' OR l.cluster ILIKE '%' || $3 || '%')
-    AND (
-        $4::text IS NULL OR $4 = '' OR 
-        tai.tools_id IN (
-            SELECT id FROM list_sparepart 
-            WHERE name ILIKE '%' || $4 || '%'
-        )
-    )
-ORDER BY tai.id
-LIMIT $5
-OFFSET $6;
+    -- Location filters are already applied by paged_locations, only item filters remain
+    tai.deleted_at IS NULL
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n))
+ORDER BY tai.location_id, tai.id;
+
+-- name: ListToolsAlkersByLocation :many
+SELECT 
+    tai.id, tai.location_id, tai.tools_id, tai.quantity, tai.documentation, tai.notes, tai.created_at, tai.updated_at, tai.version,
+    l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
+    ls.id as tools_id_2, ls.name as tools_name, ls.item_type, ls.created_at as tools_created_at, ls.updated_at as tools_updated_at,
+    (SELECT COALESCE(SUM(tac.quantity), 0) FROM tools_alker_checkout tac WHERE tac.tools_alker_item_id = tai.id AND tac.checked_in_at IS NULL)::int AS checked_out
+FROM tools_alker_item tai
+JOIN location l ON l.id = tai.location_id
+JOIN list_sparepart ls ON ls.id = tai.tools_id
+WHERE tai.location_id = $1 AND tai.deleted_at IS NULL
+ORDER BY tai.id;
+
+-- name: ListToolsAlkersByLocations :many
+-- Tools alker of a batch of GraphQL locations
+SELECT 
+    tai.id, tai.location_id, tai.tools_id, tai.quantity, tai.documentation, tai.notes, tai.created_at, tai.updated_at, tai.version,
+    l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
+    ls.id as tools_id_2, ls.name as tools_name, ls.item_type, ls.created_at as tools_created_at, ls.updated_at as tools_updated_at,
+    (SELECT COALESCE(SUM(tac.quantity), 0) FROM tools_alker_checkout tac WHERE tac.tools_alker_item_id = tai.id AND tac.checked_in_at IS NULL)::int AS checked_out
+FROM tools_alker_item tai
+JOIN location l ON l.id = tai.location_id
+JOIN list_sparepart ls ON ls.id = tai.tools_id
+WHERE tai.location_id = ANY(sqlc.arg('location_ids')::int[]) AND tai.deleted_at IS NULL
+ORDER BY tai.location_id, tai.id;
 
 -- name: CountToolsAlkers :one
 SELECT COUNT(DISTINCT tai.location_id)
@@ -37,55 +73,130 @@ FROM tools_alker_item tai
 JOIN location l ON l.id = tai.location_id
 JOIN list_sparepart ls ON ls.id = tai.tools_id
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR UPPER(l.region::text) = UPPER($1::text))
-    AND ($2::text IS NULL OR $2 = '' OR l.regency ILIKE '%' || $2 || '%')
-    AND ($3::text IS NULL OR $3 = '' OR l.cluster ILIKE '%' || $3 || '%')
-    AND (
-        $4::text IS NULL OR $4 = '' OR 
-        tai.tools_id IN (
-            SELECT id FROM list_sparepart 
-            WHERE name ILIKE '%' || $4 || '%'
-        )
-    );
+    tai.deleted_at IS NULL
+    AND l.deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n));
+
+-- name: ListToolsAlkerItems :many
+-- Paginates by tools alker item for the flat (group_by=none) listing, same filters as ListToolsAlkers
+SELECT 
+    tai.id, tai.location_id, tai.tools_id, tai.quantity, tai.documentation, tai.notes, tai.created_at, tai.updated_at, tai.version,
+    l.id as location_id_2, l.region, l.regency, l.cluster, l.created_at as location_created_at, l.updated_at as location_updated_at,
+    ls.id as tools_id_2, ls.name as tools_name, ls.item_type, ls.created_at as tools_created_at, ls.updated_at as tools_updated_at,
+    (SELECT COALESCE(SUM(tac.quantity), 0) FROM tools_alker_checkout tac WHERE tac.tools_alker_item_id = tai.id AND tac.checked_in_at IS NULL)::int AS checked_out
+FROM tools_alker_item tai
+JOIN location l ON l.id = tai.location_id
+JOIN list_sparepart ls ON ls.id = tai.tools_id
+WHERE 
+    tai.deleted_at IS NULL
+    AND l.deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n))
+ORDER BY tai.location_id, tai.id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountToolsAlkerItems :one
+SELECT COUNT(*)
+FROM tools_alker_item tai
+JOIN location l ON l.id = tai.location_id
+JOIN list_sparepart ls ON ls.id = tai.tools_id
+WHERE 
+    tai.deleted_at IS NULL
+    AND l.deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n));
 
 -- name: CreateToolsAlker :one
 INSERT INTO tools_alker_item (location_id, tools_id, quantity, documentation, notes)
 VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
+-- name: CreateToolsAlkersBatch :many
+-- Inserts many tools alker items in one statement; arrays are zipped by position
+INSERT INTO tools_alker_item (location_id, tools_id, quantity, notes)
+SELECT i.location_id, i.tools_id, i.quantity, NULLIF(i.notes, '')
+FROM unnest(
+    sqlc.arg('location_ids')::int[],
+    sqlc.arg('tools_ids')::int[],
+    sqlc.arg('quantities')::int[],
+    sqlc.arg('notes')::text[]
+) AS i(location_id, tools_id, quantity, notes)
+RETURNING *;
+
 -- name: UpdateToolsAlker :one
+-- Only provided fields change; an empty notes string clears the notes. Returns no row when
+-- the item is no longer at the expected version.
 UPDATE tools_alker_item
-SET quantity = $2, notes = $3
-WHERE id = $1
+SET
+    quantity = COALESCE(sqlc.narg('quantity')::int, quantity),
+    notes = CASE
+        WHEN sqlc.narg('notes')::text IS NULL THEN notes
+        ELSE NULLIF(sqlc.narg('notes')::text, '')
+    END,
+    documentation = COALESCE(sqlc.narg('documentation')::jsonb, documentation)
+WHERE id = sqlc.arg('id') AND version = sqlc.arg('version')
 RETURNING *;
 
 -- name: UpdateToolsAlkerDocumentation :one
+-- Photo changes write back the whole array read at version, so two of them at the same time
+-- cannot drop each other's photos. Returns no row when the item changed meanwhile.
 UPDATE tools_alker_item
 SET documentation = $2
-WHERE id = $1
+WHERE id = $1 AND version = $3
 RETURNING *;
 
 -- name: DeleteToolsAlker :exec
-DELETE FROM tools_alker_item
-WHERE id = $1;
+-- Soft delete: the item and its photos are kept until restored or purged
+UPDATE tools_alker_item
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreToolsAlker :one
+-- Not while the item's location is deleted
+UPDATE tools_alker_item tai
+SET deleted_at = NULL
+FROM location l
+WHERE tai.id = $1
+    AND tai.deleted_at IS NOT NULL
+    AND l.id = tai.location_id
+    AND l.deleted_at IS NULL
+RETURNING tai.*;
 
 -- name: ListToolsAlkersForExport :many
+-- Read in keyset batches so exports don't hold every row in memory
 SELECT 
     tai.*,
     l.id as location_id, l.region, l.regency, l.cluster,
-    ls.id as tools_id, ls.name as tools_name, ls.item_type
+    ls.id as tools_id, ls.name as tools_name, ls.item_type,
+    cp.pic, cp.phone
 FROM tools_alker_item tai
 JOIN location l ON l.id = tai.location_id
 JOIN list_sparepart ls ON ls.id = tai.tools_id
+-- Primary PIC of the location: its first registered contact person
+LEFT JOIN LATERAL (
+    SELECT c.pic, c.phone FROM contact_person c
+    WHERE c.location_id = l.id AND c.deleted_at IS NULL
+    ORDER BY c.id
+    LIMIT 1
+) cp ON true
 WHERE 
-    ($1::text IS NULL OR $1 = '' OR UPPER(l.region::text) = UPPER($1::text))
-    AND ($2::text IS NULL OR $2 = '' OR l.regency ILIKE '%' || $2 || '%')
-    AND ($3::text IS NULL OR $3 = '' OR l.cluster ILIKE '%' || $3 || '%')
-    AND (
-        $4::text IS NULL OR $4 = '' OR 
-        tai.tools_id IN (
-            SELECT id FROM list_sparepart 
-            WHERE name ILIKE '%' || $4 || '%'
-        )
-    )
-ORDER BY l.region, l.regency, ls.name;
+    tai.deleted_at IS NULL
+    AND l.deleted_at IS NULL
+    AND (sqlc.narg('region')::text IS NULL OR UPPER(l.region::text) = UPPER(sqlc.narg('region')::text))
+    AND (sqlc.narg('regency')::text IS NULL OR l.regency ILIKE '%' || sqlc.narg('regency') || '%')
+    AND (sqlc.narg('cluster')::text IS NULL OR l.cluster ILIKE '%' || sqlc.narg('cluster') || '%')
+    AND (sqlc.narg('names')::text[] IS NULL OR ls.name ILIKE ANY (SELECT '%' || n || '%' FROM unnest(sqlc.narg('names')::text[]) AS n))
+    -- Keyset: continue after the last row of the previous batch
+    AND (sqlc.narg('after_id')::int IS NULL OR (l.region, l.regency, ls.name, tai.id) > (
+        sqlc.narg('after_region')::region_type, sqlc.narg('after_regency')::text, sqlc.narg('after_name')::text, sqlc.narg('after_id')::int
+    ))
+ORDER BY l.region, l.regency, ls.name, tai.id
+LIMIT sqlc.arg('limit');
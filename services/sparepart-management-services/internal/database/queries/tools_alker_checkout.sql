@@ -0,0 +1,85 @@
+-- name: GetToolsAlkerForUpdate :one
+-- Locks the tools alker item until the checkout's transaction ends, so concurrent
+-- checkouts cannot lend out more than it holds
+SELECT tai.* FROM tools_alker_item tai
+JOIN location l ON l.id = tai.location_id
+WHERE tai.id = $1 AND tai.deleted_at IS NULL AND l.deleted_at IS NULL
+FOR UPDATE OF tai;
+
+-- name: SumOpenToolsAlkerCheckouts :one
+SELECT COALESCE(SUM(quantity), 0)::int FROM tools_alker_checkout
+WHERE tools_alker_item_id = $1 AND checked_in_at IS NULL;
+
+-- name: CreateToolsAlkerCheckout :one
+-- A checkout naming a technician from the directory stores the technician's current name
+INSERT INTO tools_alker_checkout (tools_alker_item_id, quantity, technician, expected_return_date, notes, checked_out_by, technician_id)
+VALUES (
+    sqlc.arg('tools_alker_item_id'),
+    sqlc.arg('quantity'),
+    COALESCE((SELECT t.name FROM technician t WHERE t.id = sqlc.narg('technician_id')::int), sqlc.arg('technician')),
+    sqlc.arg('expected_return_date'),
+    sqlc.narg('notes'),
+    sqlc.narg('checked_out_by'),
+    sqlc.narg('technician_id')::int
+)
+RETURNING *;
+
+-- name: CheckInToolsAlkerCheckout :one
+-- Returns no row unless the checkout is open and belongs to the tools alker item
+UPDATE tools_alker_checkout
+SET checked_in_at = CURRENT_TIMESTAMP, checked_in_by = sqlc.narg('checked_in_by')
+WHERE id = sqlc.arg('id') AND tools_alker_item_id = sqlc.arg('tools_alker_item_id') AND checked_in_at IS NULL
+RETURNING *;
+
+-- name: GetToolsAlkerCheckout :one
+SELECT
+    tac.*,
+    ls.name AS tools_name,
+    tai.location_id,
+    l.cluster,
+    (tac.checked_in_at IS NULL AND tac.expected_return_date < CURRENT_DATE) AS overdue,
+    GREATEST(CURRENT_DATE - tac.expected_return_date, 0)::int AS days_overdue
+FROM tools_alker_checkout tac
+JOIN tools_alker_item tai ON tai.id = tac.tools_alker_item_id
+JOIN location l ON l.id = tai.location_id
+JOIN list_sparepart ls ON ls.id = tai.tools_id
+WHERE tac.id = $1;
+
+-- name: ListToolsAlkerCheckouts :many
+-- Open checkouts first, the longest overdue at the top
+SELECT
+    tac.*,
+    ls.name AS tools_name,
+    tai.location_id,
+    l.cluster,
+    (tac.checked_in_at IS NULL AND tac.expected_return_date < CURRENT_DATE) AS overdue,
+    GREATEST(CURRENT_DATE - tac.expected_return_date, 0)::int AS days_overdue
+FROM tools_alker_checkout tac
+JOIN tools_alker_item tai ON tai.id = tac.tools_alker_item_id
+JOIN location l ON l.id = tai.location_id
+JOIN list_sparepart ls ON ls.id = tai.tools_id
+WHERE
+    (sqlc.narg('status')::text IS NULL
+        OR (sqlc.narg('status')::text = 'OPEN' AND tac.checked_in_at IS NULL)
+        OR (sqlc.narg('status')::text = 'OVERDUE' AND tac.checked_in_at IS NULL AND tac.expected_return_date < CURRENT_DATE)
+        OR (sqlc.narg('status')::text = 'RETURNED' AND tac.checked_in_at IS NOT NULL))
+    AND (sqlc.narg('technician')::text IS NULL OR tac.technician ILIKE '%' || sqlc.narg('technician') || '%')
+    AND (sqlc.narg('technician_id')::int IS NULL OR tac.technician_id = sqlc.narg('technician_id')::int)
+    AND (sqlc.narg('tools_alker_item_id')::int IS NULL OR tac.tools_alker_item_id = sqlc.narg('tools_alker_item_id')::int)
+    AND (sqlc.narg('location_id')::int IS NULL OR tai.location_id = sqlc.narg('location_id')::int)
+ORDER BY tac.checked_in_at IS NOT NULL, tac.expected_return_date, tac.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountToolsAlkerCheckouts :one
+SELECT COUNT(*)
+FROM tools_alker_checkout tac
+JOIN tools_alker_item tai ON tai.id = tac.tools_alker_item_id
+WHERE
+    (sqlc.narg('status')::text IS NULL
+        OR (sqlc.narg('status')::text = 'OPEN' AND tac.checked_in_at IS NULL)
+        OR (sqlc.narg('status')::text = 'OVERDUE' AND tac.checked_in_at IS NULL AND tac.expected_return_date < CURRENT_DATE)
+        OR (sqlc.narg('status')::text = 'RETURNED' AND tac.checked_in_at IS NOT NULL))
+    AND (sqlc.narg('technician')::text IS NULL OR tac.technician ILIKE '%' || sqlc.narg('technician') || '%')
+    AND (sqlc.narg('technician_id')::int IS NULL OR tac.technician_id = sqlc.narg('technician_id')::int)
+    AND (sqlc.narg('tools_alker_item_id')::int IS NULL OR tac.tools_alker_item_id = sqlc.narg('tools_alker_item_id')::int)
+    AND (sqlc.narg('location_id')::int IS NULL OR tai.location_id = sqlc.narg('location_id')::int);
@@ -0,0 +1,34 @@
+-- name: AcquireUploadFile :one
+-- Adds a reference to the oldest stored file with the hash. A file whose last reference was
+-- released is about to be deleted and is not reused.
+UPDATE upload_file
+SET ref_count = ref_count + 1
+WHERE path = (
+    SELECT uf.path FROM upload_file uf
+    WHERE uf.sha256 = $1 AND uf.ref_count > 0
+    ORDER BY uf.created_at
+    LIMIT 1
+)
+    AND ref_count > 0
+RETURNING path;
+
+-- name: CreateUploadFile :exec
+-- Stored file names are unique, a path that is already recorded fails on the primary key
+INSERT INTO upload_file (path, sha256, size)
+VALUES ($1, $2, $3);
+
+-- name: ReleaseUploadFile :one
+-- Drops a reference to a stored file and returns the references left
+UPDATE upload_file
+SET ref_count = ref_count - 1
+WHERE path = $1 AND ref_count > 0
+RETURNING ref_count;
+
+-- name: DeleteReleasedUploadFile :execrows
+-- Removes a file without references, unless it was reused in the meantime
+DELETE FROM upload_file
+WHERE path = $1 AND ref_count = 0;
+
+-- name: SumUploadFileSize :one
+-- Bytes of every stored photo, counted once however often it is reused; for the upload quota
+SELECT COALESCE(SUM(size), 0)::BIGINT FROM upload_file;
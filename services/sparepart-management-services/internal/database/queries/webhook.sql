@@ -0,0 +1,104 @@
+-- name: GetWebhook :one
+SELECT * FROM webhook
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhook
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountWebhooks :one
+SELECT COUNT(*) FROM webhook;
+
+-- name: CreateWebhook :one
+INSERT INTO webhook (name, url, secret, events, low_stock_threshold, enabled)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: UpdateWebhook :one
+UPDATE webhook
+SET name = $2, url = $3, events = $4, low_stock_threshold = $5, enabled = $6
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhook
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_delivery
+WHERE
+    webhook_id = sqlc.arg('webhook_id')
+    AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status')::varchar)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) FROM webhook_delivery
+WHERE
+    webhook_id = sqlc.arg('webhook_id')
+    AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status')::varchar);
+
+-- name: FanOutWebhookEvents :execrows
+-- Creates a delivery per pending event and enabled webhook subscribed to it, and marks the
+-- events dispatched. Stock events that take an item's quantity from above a webhook's
+-- low_stock_threshold to at or below it are also delivered as stock.low; only available stock
+-- (NEW_STOCK and USED_STOCK) runs low.
+WITH pending AS (
+    UPDATE webhook_event
+    SET dispatched_at = CURRENT_TIMESTAMP
+    WHERE id IN (
+        SELECT id FROM webhook_event
+        WHERE dispatched_at IS NULL
+        ORDER BY id
+        LIMIT sqlc.arg('limit')
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, event, payload
+)
+INSERT INTO webhook_delivery (webhook_id, event_id, event)
+SELECT w.id, e.id, e.event
+FROM pending e
+JOIN webhook w ON w.enabled AND (cardinality(w.events) = 0 OR e.event = ANY(w.events))
+UNION ALL
+SELECT w.id, e.id, 'stock.low'
+FROM pending e
+JOIN webhook w ON w.enabled AND (cardinality(w.events) = 0 OR 'stock.low' = ANY(w.events))
+WHERE
+    e.event IN ('stock.created', 'stock.updated', 'stock.restored')
+    AND e.payload ->> 'stock_type' IN ('NEW_STOCK', 'USED_STOCK')
+    AND (e.payload ->> 'quantity')::int <= w.low_stock_threshold
+    AND (e.event <> 'stock.updated' OR (e.payload ->> 'previous_quantity')::int > w.low_stock_threshold)
+ORDER BY 2, 1;
+
+-- name: ClaimWebhookDeliveries :many
+-- Claims due deliveries by pushing their next attempt past the lease, so another replica
+-- does not send them while this one is
+UPDATE webhook_delivery d
+SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg('lease_seconds')::int)
+FROM webhook w, webhook_event e
+WHERE
+    d.id IN (
+        SELECT id FROM webhook_delivery
+        WHERE status = 'PENDING' AND next_attempt_at <= CURRENT_TIMESTAMP
+        ORDER BY next_attempt_at
+        LIMIT sqlc.arg('limit')
+        FOR UPDATE SKIP LOCKED
+    )
+    AND w.id = d.webhook_id
+    AND e.id = d.event_id
+RETURNING d.id, d.event, d.attempts, w.url, w.secret, e.payload, e.created_at AS occurred_at;
+
+-- name: MarkWebhookDeliveryDelivered :exec
+UPDATE webhook_delivery
+SET status = 'DELIVERED', attempts = attempts + 1, response_status = $2, last_error = NULL,
+    delivered_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+-- Records a failed attempt; status stays PENDING while retries remain
+UPDATE webhook_delivery
+SET status = $2, attempts = attempts + 1, response_status = $3, last_error = $4, next_attempt_at = $5
+WHERE id = $1;
@@ -0,0 +1,94 @@
+-- name: CreateWorkOrder :one
+-- Opens a work order; returns no row when the location does not exist or is deleted. A work
+-- order naming a technician from the directory stores the technician's current name.
+INSERT INTO work_order (location_id, title, description, technician, created_by, technician_id)
+SELECT
+    l.id,
+    sqlc.arg('title'),
+    sqlc.narg('description'),
+    COALESCE((SELECT t.name FROM technician t WHERE t.id = sqlc.narg('technician_id')::int), sqlc.narg('technician')),
+    sqlc.narg('created_by'),
+    sqlc.narg('technician_id')::int
+FROM location l
+WHERE l.id = sqlc.arg('location_id') AND l.deleted_at IS NULL
+RETURNING *;
+
+-- name: GetWorkOrder :one
+SELECT wo.*, l.region, l.regency, l.cluster
+FROM work_order wo
+LEFT JOIN location l ON l.id = wo.location_id
+WHERE wo.id = $1;
+
+-- name: GetWorkOrderForUpdate :one
+-- Locks the work order until the transaction ends, so changes to its items and closing it
+-- are serialized
+SELECT * FROM work_order
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListWorkOrders :many
+SELECT wo.*, l.region, l.regency, l.cluster
+FROM work_order wo
+LEFT JOIN location l ON l.id = wo.location_id
+WHERE (sqlc.narg('location_id')::int IS NULL OR wo.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('status')::text IS NULL OR wo.status = sqlc.narg('status'))
+    AND (sqlc.narg('technician')::text IS NULL OR wo.technician = sqlc.narg('technician'))
+    AND (sqlc.narg('technician_id')::int IS NULL OR wo.technician_id = sqlc.narg('technician_id')::int)
+ORDER BY wo.created_at DESC, wo.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountWorkOrders :one
+SELECT COUNT(*) FROM work_order wo
+WHERE (sqlc.narg('location_id')::int IS NULL OR wo.location_id = sqlc.narg('location_id')::int)
+    AND (sqlc.narg('status')::text IS NULL OR wo.status = sqlc.narg('status'))
+    AND (sqlc.narg('technician')::text IS NULL OR wo.technician = sqlc.narg('technician'))
+    AND (sqlc.narg('technician_id')::int IS NULL OR wo.technician_id = sqlc.narg('technician_id')::int);
+
+-- name: DeleteWorkOrderItems :exec
+DELETE FROM work_order_item
+WHERE work_order_id = $1;
+
+-- name: CreateWorkOrderItem :one
+-- Returns no row when the sparepart does not exist
+INSERT INTO work_order_item (work_order_id, sparepart_id, stock_type, quantity)
+SELECT sqlc.arg('work_order_id'), ls.id, sqlc.arg('stock_type')::stock_type, sqlc.arg('quantity')::int
+FROM list_sparepart ls
+WHERE ls.id = sqlc.arg('sparepart_id') AND ls.deleted_at IS NULL
+RETURNING *;
+
+-- name: ListWorkOrderItems :many
+SELECT woi.*, ls.name AS sparepart_name
+FROM work_order_item woi
+LEFT JOIN list_sparepart ls ON ls.id = woi.sparepart_id
+WHERE woi.work_order_id = $1
+ORDER BY woi.id;
+
+-- name: SetWorkOrderItemConsumption :exec
+UPDATE work_order_item
+SET stock_item_id = $2, quantity_after = $3
+WHERE id = $1;
+
+-- name: DeleteWorkOrderTools :exec
+DELETE FROM work_order_tool
+WHERE work_order_id = $1;
+
+-- name: CreateWorkOrderTool :one
+-- Returns no row when the tool does not exist or is not a TOOLS_ALKER item
+INSERT INTO work_order_tool (work_order_id, tools_id, quantity)
+SELECT sqlc.arg('work_order_id'), ls.id, sqlc.arg('quantity')::int
+FROM list_sparepart ls
+WHERE ls.id = sqlc.arg('tools_id') AND ls.item_type = 'TOOLS_ALKER' AND ls.deleted_at IS NULL
+RETURNING *;
+
+-- name: ListWorkOrderTools :many
+SELECT wot.*, ls.name AS tools_name
+FROM work_order_tool wot
+LEFT JOIN list_sparepart ls ON ls.id = wot.tools_id
+WHERE wot.work_order_id = $1
+ORDER BY wot.id;
+
+-- name: CloseWorkOrder :one
+UPDATE work_order
+SET status = $2, resolution = $3, closed_by = $4, closed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'OPEN'
+RETURNING *;
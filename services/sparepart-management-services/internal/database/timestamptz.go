@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// utcTimestamptzCodec scans timestamptz values in UTC. pgx scans them in time.Local, so rows
+// encoded straight to JSON would otherwise carry the offset of the server they run on.
+type utcTimestamptzCodec struct {
+	pgtype.TimestamptzCodec
+}
+
+func (c utcTimestamptzCodec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	if _, ok := target.(pgtype.TimestamptzScanner); !ok {
+		return c.TimestamptzCodec.PlanScan(m, oid, format, target)
+	}
+	plan := c.TimestamptzCodec.PlanScan(m, oid, format, &pgtype.Timestamptz{})
+	if plan == nil {
+		return nil
+	}
+	return utcTimestamptzScanPlan{plan: plan}
+}
+
+func (c utcTimestamptzCodec) DecodeValue(m *pgtype.Map, oid uint32, format int16, src []byte) (any, error) {
+	value, err := c.TimestamptzCodec.DecodeValue(m, oid, format, src)
+	if t, ok := value.(time.Time); ok {
+		return t.UTC(), err
+	}
+	return value, err
+}
+
+type utcTimestamptzScanPlan struct {
+	plan pgtype.ScanPlan
+}
+
+func (p utcTimestamptzScanPlan) Scan(src []byte, dst any) error {
+	var tstz pgtype.Timestamptz
+	if err := p.plan.Scan(src, &tstz); err != nil {
+		return err
+	}
+	if tstz.Valid {
+		tstz.Time = tstz.Time.UTC()
+	}
+	return dst.(pgtype.TimestamptzScanner).ScanTimestamptz(tstz)
+}
+
+// registerUTCTimestamptz makes conn scan timestamptz values, and arrays of them, in UTC
+func registerUTCTimestamptz(ctx context.Context, conn *pgx.Conn) error {
+	useUTCTimestamptz(conn.TypeMap())
+	return nil
+}
+
+func useUTCTimestamptz(typeMap *pgtype.Map) {
+	timestamptz := &pgtype.Type{Name: "timestamptz", OID: pgtype.TimestamptzOID, Codec: utcTimestamptzCodec{}}
+	typeMap.RegisterType(timestamptz)
+	typeMap.RegisterType(&pgtype.Type{Name: "_timestamptz", OID: pgtype.TimestamptzArrayOID, Codec: &pgtype.ArrayCodec{ElementType: timestamptz}})
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestUTCTimestamptzScan(t *testing.T) {
+	typeMap := pgtype.NewMap()
+	useUTCTimestamptz(typeMap)
+	want := time.Date(2025, 3, 13, 23, 30, 0, 0, time.UTC)
+
+	var tstz pgtype.Timestamptz
+	if err := typeMap.Scan(pgtype.TimestamptzOID, pgtype.TextFormatCode, []byte("2025-03-14 08:30:00+09"), &tstz); err != nil {
+		t.Fatal(err)
+	}
+	if !tstz.Valid || tstz.Time.Location() != time.UTC || !tstz.Time.Equal(want) {
+		t.Fatalf("expected %s in UTC, got %s", want, tstz.Time)
+	}
+
+	binary, err := typeMap.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, want.In(time.FixedZone("WIB", 7*60*60)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scanned time.Time
+	if err := typeMap.Scan(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, binary, &scanned); err != nil {
+		t.Fatal(err)
+	}
+	if scanned.Location() != time.UTC || !scanned.Equal(want) {
+		t.Fatalf("expected %s in UTC, got %s", want, scanned)
+	}
+
+	var times []time.Time
+	if err := typeMap.Scan(pgtype.TimestamptzArrayOID, pgtype.TextFormatCode, []byte(`{"2025-03-14 08:30:00+09"}`), &times); err != nil {
+		t.Fatal(err)
+	}
+	if len(times) != 1 || times[0].Location() != time.UTC || !times[0].Equal(want) {
+		t.Fatalf("expected [%s] in UTC, got %v", want, times)
+	}
+
+	if err := typeMap.Scan(pgtype.TimestamptzOID, pgtype.TextFormatCode, nil, &tstz); err != nil || tstz.Valid {
+		t.Fatalf("expected NULL to scan as invalid, got %+v, %v", tstz, err)
+	}
+}
@@ -0,0 +1,85 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// LowStockItem is a stock item at or below the low stock threshold
+type LowStockItem struct {
+	LocationID    int32
+	Region        string
+	Regency       string
+	Cluster       string
+	SparepartName string
+	StockType     string
+	Quantity      int32
+}
+
+// PendingRequest is a sparepart request awaiting approval
+type PendingRequest struct {
+	ID                 int32
+	LocationID         int32
+	DestinationCluster string
+	RequestedBy        string
+	Notes              string
+	Items              int64
+	Quantity           int64
+	CreatedAt          string
+}
+
+// Digest is what one recipient is sent
+type Digest struct {
+	Name            string
+	Threshold       int
+	LowStock        []LowStockItem
+	PendingRequests []PendingRequest
+	GeneratedAt     string
+}
+
+// Empty reports whether there is nothing to send
+func (d Digest) Empty() bool {
+	return len(d.LowStock) == 0 && len(d.PendingRequests) == 0
+}
+
+// Subject summarizes the digest for the subject line
+func (d Digest) Subject() string {
+	return fmt.Sprintf("Sparepart digest: %d low stock items, %d requests awaiting approval", len(d.LowStock), len(d.PendingRequests))
+}
+
+var digestTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; font-size: 14px; color: #222;">
+<p>{{if .Name}}Dear {{.Name}},{{else}}Hello,{{end}}</p>
+{{- if .LowStock}}
+<h3>Low stock (quantity {{.Threshold}} or less)</h3>
+<table cellpadding="6" cellspacing="0" border="1" style="border-collapse: collapse;">
+<tr><th>Region</th><th>Regency</th><th>Cluster</th><th>Sparepart</th><th>Stock type</th><th>Quantity</th></tr>
+{{- range .LowStock}}
+<tr><td>{{.Region}}</td><td>{{.Regency}}</td><td>{{.Cluster}}</td><td>{{.SparepartName}}</td><td>{{.StockType}}</td><td align="right">{{.Quantity}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .PendingRequests}}
+<h3>Sparepart requests awaiting approval</h3>
+<table cellpadding="6" cellspacing="0" border="1" style="border-collapse: collapse;">
+<tr><th>Request</th><th>Destination</th><th>Requested by</th><th>Items</th><th>Quantity</th><th>Requested at</th><th>Notes</th></tr>
+{{- range .PendingRequests}}
+<tr><td>#{{.ID}}</td><td>{{.DestinationCluster}}</td><td>{{.RequestedBy}}</td><td align="right">{{.Items}}</td><td align="right">{{.Quantity}}</td><td>{{.CreatedAt}}</td><td>{{.Notes}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<p style="color: #777; font-size: 12px;">Generated at {{.GeneratedAt}} by the Sparepart Management Service.</p>
+</body>
+</html>
+`))
+
+// Render writes the digest as an HTML body
+func Render(digest Digest) (string, error) {
+	var buf bytes.Buffer
+	if err := digestTemplate.Execute(&buf, digest); err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return buf.String(), nil
+}
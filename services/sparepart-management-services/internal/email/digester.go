@@ -0,0 +1,158 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// Digester sends the digest of low stock and sparepart requests awaiting approval. The
+// configured recipients get the digest of every location; a contact person with an email
+// address gets the digest of the locations they are the contact of, where a request counts
+// for its destination location. Recipients with nothing to report are not sent an email.
+type Digester struct {
+	logger     *zap.Logger
+	queries    repository.EmailDigestRepository
+	mailer     Mailer
+	recipients []string
+	threshold  int
+}
+
+func NewDigester(queries repository.EmailDigestRepository, mailer Mailer, recipients []string, threshold int, logger *zap.Logger) *Digester {
+	return &Digester{
+		logger:     logger,
+		queries:    queries,
+		mailer:     mailer,
+		recipients: recipients,
+		threshold:  threshold,
+	}
+}
+
+// contact is a contact person recipient and the locations they receive the digest of
+type contact struct {
+	name      string
+	locations map[int32]bool
+}
+
+// Send sends one round of digests; a failing recipient does not stop the others
+func (d *Digester) Send(ctx context.Context) error {
+	rows, err := d.queries.ListLowStockDigestItems(ctx, int32(d.threshold))
+	if err != nil {
+		return fmt.Errorf("failed to list low stock items: %w", err)
+	}
+	lowStock := make([]LowStockItem, 0, len(rows))
+	for _, row := range rows {
+		lowStock = append(lowStock, LowStockItem{
+			LocationID:    row.LocationID,
+			Region:        string(row.Region),
+			Regency:       row.Regency,
+			Cluster:       row.Cluster,
+			SparepartName: row.SparepartName,
+			StockType:     string(row.StockType),
+			Quantity:      row.Quantity,
+		})
+	}
+
+	requestRows, err := d.queries.ListPendingSparepartRequestDigest(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list pending sparepart requests: %w", err)
+	}
+	pending := make([]PendingRequest, 0, len(requestRows))
+	for _, row := range requestRows {
+		pending = append(pending, PendingRequest{
+			ID:                 row.ID,
+			LocationID:         row.DestinationLocationID,
+			DestinationCluster: row.DestinationCluster.String,
+			RequestedBy:        row.RequestedBy,
+			Notes:              row.Notes.String,
+			Items:              row.Items,
+			Quantity:           row.Quantity,
+			CreatedAt:          utils.FormatTimestamp(row.CreatedAt),
+		})
+	}
+
+	contactRows, err := d.queries.ListContactPersonEmails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list contact person emails: %w", err)
+	}
+
+	// The configured recipients already get every location
+	all := make(map[string]bool, len(d.recipients))
+	for _, recipient := range d.recipients {
+		all[strings.ToLower(recipient)] = true
+	}
+	var addresses []string
+	contacts := make(map[string]*contact)
+	for _, row := range contactRows {
+		key := strings.ToLower(row.Email)
+		if all[key] {
+			continue
+		}
+		c, ok := contacts[key]
+		if !ok {
+			c = &contact{name: row.Pic, locations: make(map[int32]bool)}
+			contacts[key] = c
+			addresses = append(addresses, row.Email)
+		}
+		c.locations[row.LocationID] = true
+	}
+
+	generatedAt := time.Now().UTC().Format(time.RFC3339)
+	var errs []error
+	for _, recipient := range d.recipients {
+		digest := Digest{
+			Threshold:       d.threshold,
+			LowStock:        lowStock,
+			PendingRequests: pending,
+			GeneratedAt:     generatedAt,
+		}
+		if err := d.send(ctx, recipient, digest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, address := range addresses {
+		c := contacts[strings.ToLower(address)]
+		digest := Digest{Name: c.name, Threshold: d.threshold, GeneratedAt: generatedAt}
+		for _, item := range lowStock {
+			if c.locations[item.LocationID] {
+				digest.LowStock = append(digest.LowStock, item)
+			}
+		}
+		for _, request := range pending {
+			if c.locations[request.LocationID] {
+				digest.PendingRequests = append(digest.PendingRequests, request)
+			}
+		}
+		if err := d.send(ctx, address, digest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send renders and sends one digest, skipping an empty one
+func (d *Digester) send(ctx context.Context, recipient string, digest Digest) error {
+	if digest.Empty() {
+		return nil
+	}
+	body, err := Render(digest)
+	if err != nil {
+		return err
+	}
+	if err := d.mailer.Send(ctx, Message{To: recipient, Subject: digest.Subject(), HTML: body}); err != nil {
+		return err
+	}
+	d.logger.Info("Email digest sent",
+		zap.String("recipient", recipient),
+		zap.Int("low_stock", len(digest.LowStock)),
+		zap.Int("pending_requests", len(digest.PendingRequests)),
+	)
+	return nil
+}
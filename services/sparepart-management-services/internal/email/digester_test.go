@@ -0,0 +1,116 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type recordingMailer struct {
+	messages []Message
+	fail     string
+}
+
+func (m *recordingMailer) Send(ctx context.Context, message Message) error {
+	if message.To == m.fail {
+		return errors.New("mailbox unavailable")
+	}
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func expectDigestQueries(repo *mocks.MockEmailDigestRepository, contacts []sqlcdb.ListContactPersonEmailsRow) {
+	repo.EXPECT().ListLowStockDigestItems(gomock.Any(), int32(2)).Return([]sqlcdb.ListLowStockDigestItemsRow{
+		{ID: 1, LocationID: 1, Region: sqlcdb.RegionTypeMALUKU, Regency: "Ambon", Cluster: "Ambon 1", SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 1},
+		{ID: 2, LocationID: 2, Region: sqlcdb.RegionTypeMALUKU, Regency: "Tual", Cluster: "Tual 1", SparepartName: "Inverter", StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 0},
+	}, nil)
+	repo.EXPECT().ListPendingSparepartRequestDigest(gomock.Any()).Return([]sqlcdb.ListPendingSparepartRequestDigestRow{
+		{ID: 5, DestinationLocationID: 2, DestinationCluster: pgtype.Text{String: "Tual 1", Valid: true}, RequestedBy: "budi", Items: 2, Quantity: 3,
+			CreatedAt: pgtype.Timestamptz{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true}},
+	}, nil)
+	repo.EXPECT().ListContactPersonEmails(gomock.Any()).Return(contacts, nil)
+}
+
+func TestDigesterSendsPerLocationDigests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockEmailDigestRepository(ctrl)
+	mailer := &recordingMailer{}
+	digester := NewDigester(repo, mailer, []string{"warehouse@example.com"}, 2, zap.NewNop())
+
+	expectDigestQueries(repo, []sqlcdb.ListContactPersonEmailsRow{
+		{LocationID: 1, Pic: "Andi", Email: "andi@example.com"},
+		{LocationID: 3, Pic: "Citra", Email: "citra@example.com"},      // nothing to report
+		{LocationID: 2, Pic: "Gudang", Email: "Warehouse@example.com"}, // already gets every location
+	})
+
+	if err := digester.Send(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mailer.messages) != 2 {
+		t.Fatalf("expected 2 emails, got %d: %+v", len(mailer.messages), mailer.messages)
+	}
+
+	all := mailer.messages[0]
+	if all.To != "warehouse@example.com" || !strings.Contains(all.HTML, "Ambon 1") || !strings.Contains(all.HTML, "Inverter") || !strings.Contains(all.HTML, "#5") {
+		t.Fatalf("unexpected digest for the configured recipient: %+v", all)
+	}
+	if all.Subject != "Sparepart digest: 2 low stock items, 1 requests awaiting approval" {
+		t.Fatalf("unexpected subject: %q", all.Subject)
+	}
+
+	local := mailer.messages[1]
+	if local.To != "andi@example.com" || !strings.Contains(local.HTML, "Dear Andi") || !strings.Contains(local.HTML, "BMS") {
+		t.Fatalf("unexpected digest for the contact person: %+v", local)
+	}
+	if strings.Contains(local.HTML, "Inverter") || strings.Contains(local.HTML, "#5") {
+		t.Fatalf("contact person digest includes other locations: %s", local.HTML)
+	}
+}
+
+func TestDigesterContinuesAfterFailedRecipient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockEmailDigestRepository(ctrl)
+	mailer := &recordingMailer{fail: "warehouse@example.com"}
+	digester := NewDigester(repo, mailer, []string{"warehouse@example.com"}, 2, zap.NewNop())
+
+	expectDigestQueries(repo, []sqlcdb.ListContactPersonEmailsRow{{LocationID: 2, Pic: "Dewi", Email: "dewi@example.com"}})
+
+	if err := digester.Send(context.Background()); err == nil {
+		t.Fatal("expected the failed recipient to be reported")
+	}
+	if len(mailer.messages) != 1 || mailer.messages[0].To != "dewi@example.com" {
+		t.Fatalf("unexpected emails: %+v", mailer.messages)
+	}
+}
+
+func TestRenderEscapesValues(t *testing.T) {
+	body, err := Render(Digest{
+		Threshold:       2,
+		PendingRequests: []PendingRequest{{ID: 1, RequestedBy: "budi", Notes: "<script>alert(1)</script>"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(body, "<script>") || !strings.Contains(body, "&lt;script&gt;") {
+		t.Fatalf("notes were not escaped: %s", body)
+	}
+	if strings.Contains(body, "Low stock") {
+		t.Fatalf("empty low stock section was rendered: %s", body)
+	}
+}
+
+func TestBuildMessageEncodesSubject(t *testing.T) {
+	message := string(buildMessage("stock@example.com", Message{To: "andi@example.com", Subject: "Stok menipis – Ambon", HTML: "<p>hi</p>"}, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+	if !strings.Contains(message, "Subject: =?utf-8?q?") || !strings.Contains(message, "Content-Type: text/html; charset=UTF-8\r\n\r\n<p>hi</p>") {
+		t.Fatalf("unexpected message: %q", message)
+	}
+}
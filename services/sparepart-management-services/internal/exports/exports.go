@@ -0,0 +1,63 @@
+// Package exports generates exports in the background. POST /sparepart/stock/export queues an
+// export_job with the filters of the synchronous export, the Worker renders the file and
+// stores it as a report, and GET /sparepart/exports/{id} reports the job status and, once
+// it has completed, the signed report link to download the file from.
+package exports
+
+import (
+	"context"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Entities an export job can export, as recorded in the export log
+const (
+	EntitySparepartStock = "SPAREPART_STOCK"
+)
+
+// Formats an export job can produce
+const (
+	FormatPDF   = "PDF"
+	FormatExcel = "EXCEL"
+	FormatCSV   = "CSV"
+)
+
+// Statuses of an export job
+const (
+	StatusPending   = "PENDING"
+	StatusRunning   = "RUNNING"
+	StatusCompleted = "COMPLETED"
+	StatusFailed    = "FAILED"
+)
+
+// StockExportParams reads the sparepart stock export filters from query parameters (the
+// request's, or those recorded with a job), like the synchronous stock exports do
+func StockExportParams(query map[string]string) sqlcdb.ListSparepartStocksForExportParams {
+	return sqlcdb.ListSparepartStocksForExportParams{
+		Region:    utils.TextFilter(query["region"]),
+		Regency:   utils.TextFilter(query["regency"]),
+		Cluster:   utils.TextFilter(query["cluster"]),
+		StockType: utils.TextFilter(query["stock_type"]),
+		Names:     utils.ListFilter(query["sparepart_name"]),
+	}
+}
+
+// StockReader pages through the sparepart stock export query in batches of params.Limit rows,
+// with a keyset on the export sort order, so a large export holds one batch at a time
+func StockReader(ctx context.Context, list func(context.Context, sqlcdb.ListSparepartStocksForExportParams) ([]sqlcdb.ListSparepartStocksForExportRow, error), params sqlcdb.ListSparepartStocksForExportParams) utils.BatchReader[sqlcdb.ListSparepartStocksForExportRow] {
+	return func() ([]sqlcdb.ListSparepartStocksForExportRow, error) {
+		rows, err := list(ctx, params)
+		if err != nil || len(rows) == 0 {
+			return rows, err
+		}
+		last := rows[len(rows)-1]
+		params.AfterID = pgtype.Int4{Int32: last.ID, Valid: true}
+		params.AfterRegion = sqlcdb.NullRegionType{RegionType: last.Region, Valid: true}
+		params.AfterRegency = pgtype.Text{String: last.Regency, Valid: true}
+		params.AfterName = pgtype.Text{String: last.SparepartName, Valid: true}
+		return rows, nil
+	}
+}
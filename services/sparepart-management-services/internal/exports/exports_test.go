@@ -0,0 +1,16 @@
+package exports
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStockExportParamsKeepsEveryName(t *testing.T) {
+	params := StockExportParams(map[string]string{"sparepart_name": "BMS, EHUB,", "region": "MALUKU"})
+	if want := []string{"BMS", "EHUB"}; !reflect.DeepEqual(params.Names, want) {
+		t.Fatalf("names = %v, want %v", params.Names, want)
+	}
+	if params.Region.String != "MALUKU" {
+		t.Fatalf("unexpected region: %+v", params.Region)
+	}
+}
@@ -0,0 +1,192 @@
+package exports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	// batchSize is the number of rows read per query while a job's file is written
+	batchSize = 500
+	// maxAttempts is how often a job is claimed before it is failed; a job is only claimed
+	// again when the replica running it stopped before finishing it
+	maxAttempts = 3
+	// maxErrorLength caps the stored error of a failed job
+	maxErrorLength = 500
+	// statusTimeout bounds the writes recording a job's outcome
+	statusTimeout = 10 * time.Second
+)
+
+// Worker renders queued export jobs one at a time. A job is leased while it runs, so
+// several replicas can share the queue; a job whose replica stopped is picked up again
+// once its lease has passed.
+type Worker struct {
+	logger  *zap.Logger
+	queries repository.ExportWorkerRepository
+	uploads *uploads.Service
+	reports *reports.Store
+	timeout time.Duration
+}
+
+// NewWorker returns a worker giving every job at most timeout to render its file into the
+// reports store; the photos embedded in PDF exports are read from uploads
+func NewWorker(queries repository.ExportWorkerRepository, uploads *uploads.Service, reports *reports.Store, timeout time.Duration, logger *zap.Logger) *Worker {
+	return &Worker{
+		logger:  logger,
+		queries: queries,
+		uploads: uploads,
+		reports: reports,
+		timeout: timeout,
+	}
+}
+
+// Run works through the queued jobs until none is left; a failing job does not stop the others
+func (w *Worker) Run(ctx context.Context) error {
+	lease := w.timeout + time.Minute
+	for {
+		job, err := w.queries.ClaimExportJob(ctx, int32(lease.Seconds()))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to claim export job: %w", err)
+		}
+		w.process(ctx, job)
+	}
+}
+
+// process renders a claimed job, stores its file as a report, records the outcome and sends
+// the report link to the job's owner
+func (w *Worker) process(ctx context.Context, job sqlcdb.ExportJob) {
+	logger := w.logger.With(zap.Int64("job_id", job.ID), zap.String("entity", job.Entity), zap.String("format", job.Format))
+	if job.Attempts > maxAttempts {
+		w.fail(ctx, job, fmt.Errorf("export was interrupted %d times", maxAttempts), logger)
+		return
+	}
+
+	start := time.Now()
+	renderCtx, cancel := context.WithTimeout(ctx, w.timeout)
+	data, filename, rows, err := w.render(renderCtx, job)
+	cancel()
+	if err != nil {
+		w.fail(ctx, job, err, logger)
+		return
+	}
+
+	storedName, err := w.reports.Save(ctx, data, filename, logger)
+	if err != nil {
+		w.fail(ctx, job, err, logger)
+		return
+	}
+
+	statusCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusTimeout)
+	defer cancel()
+	err = w.queries.CompleteExportJob(statusCtx, sqlcdb.CompleteExportJobParams{
+		ID:         job.ID,
+		Filename:   pgtype.Text{String: filename, Valid: true},
+		StoredName: pgtype.Text{String: storedName, Valid: true},
+		RowCount:   pgtype.Int4{Int32: int32(rows), Valid: true},
+	})
+	if err != nil {
+		logger.Error("Failed to complete export job", zap.Error(err))
+		return
+	}
+
+	// Recorded like a stored synchronous export, see middleware.RecordExport
+	err = w.queries.CreateExportLog(statusCtx, sqlcdb.CreateExportLogParams{
+		UserID:     pgtype.Text{String: job.UserID, Valid: true},
+		Entity:     job.Entity,
+		Format:     job.Format,
+		Filters:    job.Filters,
+		RowCount:   int32(rows),
+		DurationMs: int32(time.Since(start).Milliseconds()),
+		Stored:     true,
+	})
+	if err != nil {
+		logger.Error("Failed to record export", zap.Error(err))
+	}
+
+	// The job's owner learns of the finished export without polling it
+	url, expiresAt := w.reports.Link(storedName, time.Now())
+	if err := w.reports.Notify(statusCtx, job.UserID, filename, url, expiresAt); err != nil {
+		logger.Warn("Failed to notify export job owner", zap.Error(err))
+	}
+	logger.Info("Export job completed", zap.Int("rows", rows), zap.Duration("duration", time.Since(start)))
+}
+
+// render writes the job's file, returning it with its download name and the rows written
+func (w *Worker) render(ctx context.Context, job sqlcdb.ExportJob) ([]byte, string, int, error) {
+	query := map[string]string{}
+	if len(job.Filters) > 0 {
+		if err := json.Unmarshal(job.Filters, &query); err != nil {
+			return nil, "", 0, fmt.Errorf("invalid export filters: %w", err)
+		}
+	}
+
+	if job.Entity != EntitySparepartStock {
+		return nil, "", 0, fmt.Errorf("unknown export entity %q", job.Entity)
+	}
+	params := StockExportParams(query)
+	params.Limit = batchSize
+	var rows int
+	next := utils.CountRows(StockReader(ctx, w.queries.ListSparepartStocksForExport, params), &rows)
+	name := "sparepart_stock_" + time.Now().UTC().Format("20060102_150405")
+
+	var buf *bytes.Buffer
+	var err error
+	switch job.Format {
+	case FormatPDF:
+		name += ".pdf"
+		var photos utils.ThumbnailLoader
+		if query["include_photos"] == "true" {
+			photos = w.uploads
+		}
+		buf, err = utils.ExportSparepartStockToPDF(ctx, next, photos, w.logger)
+	case FormatExcel:
+		name += ".xlsx"
+		buf, err = utils.ExportSparepartStockToExcel(next, w.logger)
+	case FormatCSV:
+		name += ".csv"
+		buf = new(bytes.Buffer)
+		err = utils.WriteSparepartStockCSV(buf, next)
+	default:
+		return nil, "", 0, fmt.Errorf("unknown export format %q", job.Format)
+	}
+	if err != nil {
+		return nil, "", 0, err
+	}
+	return buf.Bytes(), name, rows, nil
+}
+
+// fail marks the job FAILED with err as its error
+func (w *Worker) fail(ctx context.Context, job sqlcdb.ExportJob, err error, logger *zap.Logger) {
+	logger.Warn("Export job failed", zap.Error(err))
+
+	message := strings.ToValidUTF8(err.Error(), "")
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+	statusCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusTimeout)
+	defer cancel()
+	if err := w.queries.FailExportJob(statusCtx, sqlcdb.FailExportJobParams{
+		ID:    job.ID,
+		Error: pgtype.Text{String: message, Valid: true},
+	}); err != nil {
+		logger.Error("Failed to record export job failure", zap.Error(err))
+	}
+}
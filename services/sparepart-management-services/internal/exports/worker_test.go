@@ -0,0 +1,124 @@
+package exports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// newReportStore stores the reports of a test in a temporary directory
+func newReportStore(t *testing.T) (*reports.Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := reports.New(config.ReportConfig{LinkSecret: "test-secret", LinkTTL: time.Hour}, storage.NewLocal(dir), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, dir
+}
+
+func TestWorkerRunCompletesJob(t *testing.T) {
+	store, dir := newReportStore(t)
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockExportWorkerRepository(ctrl)
+	notifications := mocks.NewMockNotificationRepository(ctrl)
+	store.SetNotifications(notifications)
+	worker := NewWorker(repo, nil, store, time.Minute, zap.NewNop())
+
+	job := sqlcdb.ExportJob{
+		ID: 7, UserID: "budi", Entity: EntitySparepartStock, Format: FormatCSV,
+		Filters: []byte(`{"region":"MALUKU"}`), Status: StatusRunning, Attempts: 1,
+	}
+	gomock.InOrder(
+		repo.EXPECT().ClaimExportJob(gomock.Any(), int32(120)).Return(job, nil),
+		repo.EXPECT().ListSparepartStocksForExport(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params sqlcdb.ListSparepartStocksForExportParams) ([]sqlcdb.ListSparepartStocksForExportRow, error) {
+				if params.Region.String != "MALUKU" || params.Limit != batchSize {
+					t.Fatalf("unexpected export params: %+v", params)
+				}
+				return []sqlcdb.ListSparepartStocksForExportRow{{ID: 1, Regency: "Ambon", SparepartName: "Baterai", Quantity: 2}}, nil
+			}),
+		repo.EXPECT().ListSparepartStocksForExport(gomock.Any(), gomock.Any()).Return(nil, nil),
+		repo.EXPECT().CompleteExportJob(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg sqlcdb.CompleteExportJobParams) error {
+				if arg.ID != 7 || arg.RowCount.Int32 != 1 || !strings.HasSuffix(arg.Filename.String, ".csv") {
+					t.Fatalf("unexpected completion: %+v", arg)
+				}
+				data, err := os.ReadFile(filepath.Join(dir, arg.StoredName.String))
+				if err != nil {
+					t.Fatalf("stored report missing: %v", err)
+				}
+				if !strings.Contains(string(data), "Baterai") {
+					t.Fatalf("unexpected report content: %s", data)
+				}
+				return nil
+			}),
+		repo.EXPECT().CreateExportLog(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg sqlcdb.CreateExportLogParams) error {
+				if arg.UserID.String != "budi" || arg.Format != FormatCSV || arg.RowCount != 1 || !arg.Stored {
+					t.Fatalf("unexpected export log: %+v", arg)
+				}
+				return nil
+			}),
+		notifications.EXPECT().CreateNotifications(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg sqlcdb.CreateNotificationsParams) (int64, error) {
+				if len(arg.UserIds) != 1 || arg.UserIds[0] != "budi" || arg.Category != reports.NotificationCategory ||
+					!strings.Contains(arg.Body, "/sparepart/reports/") {
+					t.Fatalf("unexpected notification: %+v", arg)
+				}
+				return 1, nil
+			}),
+		repo.EXPECT().ClaimExportJob(gomock.Any(), gomock.Any()).Return(sqlcdb.ExportJob{}, pgx.ErrNoRows),
+	)
+
+	if err := worker.Run(context.Background()); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+}
+
+func TestWorkerRunFailsJob(t *testing.T) {
+	store, _ := newReportStore(t)
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockExportWorkerRepository(ctrl)
+	worker := NewWorker(repo, nil, store, time.Minute, zap.NewNop())
+
+	gomock.InOrder(
+		repo.EXPECT().ClaimExportJob(gomock.Any(), gomock.Any()).
+			Return(sqlcdb.ExportJob{ID: 1, Entity: EntitySparepartStock, Format: FormatPDF, Attempts: 1}, nil),
+		repo.EXPECT().ListSparepartStocksForExport(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection reset")),
+		repo.EXPECT().FailExportJob(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg sqlcdb.FailExportJobParams) error {
+				if arg.ID != 1 || !strings.Contains(arg.Error.String, "connection reset") {
+					t.Fatalf("unexpected failure: %+v", arg)
+				}
+				return nil
+			}),
+		// Claimed again after being interrupted too often, so it is failed without running
+		repo.EXPECT().ClaimExportJob(gomock.Any(), gomock.Any()).
+			Return(sqlcdb.ExportJob{ID: 2, Entity: EntitySparepartStock, Format: FormatPDF, Attempts: maxAttempts + 1}, nil),
+		repo.EXPECT().FailExportJob(gomock.Any(), sqlcdb.FailExportJobParams{
+			ID:    2,
+			Error: pgtype.Text{String: "export was interrupted 3 times", Valid: true},
+		}).Return(nil),
+		repo.EXPECT().ClaimExportJob(gomock.Any(), gomock.Any()).Return(sqlcdb.ExportJob{}, pgx.ErrNoRows),
+	)
+
+	if err := worker.Run(context.Background()); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+}
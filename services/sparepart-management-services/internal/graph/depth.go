@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// maxQueryDepth bounds how deeply fields may nest in a query. Location -> stock -> location
+// is a cycle, so without a bound one request could fan out without end; the deepest useful
+// query (locations -> items -> stock -> sparepart -> name) needs 5 levels.
+const maxQueryDepth = 8
+
+// checkDepth returns an error when an operation of doc nests fields deeper than maxDepth.
+// Fragments count at the depth they are spread at.
+func checkDepth(doc *ast.Document, maxDepth int) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if depth := selectionDepth(operation.SelectionSet, fragments, map[string]bool{}); depth > maxDepth {
+			return fmt.Errorf("query depth %d exceeds the maximum of %d", depth, maxDepth)
+		}
+	}
+	return nil
+}
+
+// selectionDepth is the deepest field nesting below set. A fragment spread within its own
+// expansion is skipped; validation reports such cycles.
+func selectionDepth(set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, expanding map[string]bool) int {
+	if set == nil {
+		return 0
+	}
+	deepest := 0
+	for _, selection := range set.Selections {
+		depth := 0
+		switch selection := selection.(type) {
+		case *ast.Field:
+			depth = 1 + selectionDepth(selection.SelectionSet, fragments, expanding)
+		case *ast.InlineFragment:
+			depth = selectionDepth(selection.SelectionSet, fragments, expanding)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			if fragment, ok := fragments[name]; ok && !expanding[name] {
+				expanding[name] = true
+				depth = selectionDepth(fragment.SelectionSet, fragments, expanding)
+				delete(expanding, name)
+			}
+		}
+		deepest = max(deepest, depth)
+	}
+	return deepest
+}
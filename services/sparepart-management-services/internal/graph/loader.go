@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// batch is a dataloader: the resolvers of one level of a query each ask for a key and get a
+// thunk, and the first thunk to run loads every key asked for so far in one call. graphql-go
+// runs the thunks breadth first, after every resolver of the level was called.
+type batch[K comparable, V any] struct {
+	mu      sync.Mutex
+	fetch   func(ctx context.Context, keys []K) (map[K]V, error)
+	pending []K
+	loaded  map[K]V
+	failed  map[K]error
+}
+
+func newBatch[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *batch[K, V] {
+	return &batch[K, V]{fetch: fetch, loaded: map[K]V{}, failed: map[K]error{}}
+}
+
+// load returns a thunk resolving to the value of key, the zero value when there is none
+func (b *batch[K, V]) load(ctx context.Context, key K) func() (interface{}, error) {
+	b.mu.Lock()
+	if _, ok := b.loaded[key]; !ok && !slices.Contains(b.pending, key) {
+		b.pending = append(b.pending, key)
+	}
+	b.mu.Unlock()
+
+	return func() (interface{}, error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if len(b.pending) > 0 {
+			keys := b.pending
+			b.pending = nil
+			values, err := b.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					b.failed[k] = err
+				} else {
+					b.loaded[k] = values[k]
+				}
+			}
+		}
+		if err := b.failed[key]; err != nil {
+			return nil, err
+		}
+		return b.loaded[key], nil
+	}
+}
+
+type loadersKey struct{}
+
+// loaders are the dataloaders of one request, so nothing is shared between users
+type loaders struct {
+	queries        repository.GraphRepository
+	logger         *zap.Logger
+	locations      *batch[int32, *Location]
+	stock          *batch[int32, []StockItem]
+	toolsAlker     *batch[int32, []ToolsAlkerItem]
+	contactPersons *batch[int32, []ContactPerson]
+}
+
+// withLoaders returns ctx with fresh dataloaders over queries
+func withLoaders(ctx context.Context, queries repository.GraphRepository, logger *zap.Logger) context.Context {
+	l := &loaders{queries: queries, logger: logger}
+	l.locations = newBatch(l.fetchLocations)
+	l.stock = newBatch(l.fetchStock)
+	l.toolsAlker = newBatch(l.fetchToolsAlker)
+	l.contactPersons = newBatch(l.fetchContactPersons)
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// failure logs err and returns the error shown to the client, without database details
+func (l *loaders) failure(ctx context.Context, message string, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return errors.New(message + ": request timed out")
+	}
+	if logger := utils.RequestLogger(ctx, l.logger); logger != nil {
+		logger.Error(message, zap.Error(err))
+	}
+	return errors.New(message)
+}
+
+func (l *loaders) fetchLocations(ctx context.Context, ids []int32) (map[int32]*Location, error) {
+	rows, err := l.queries.ListLocationsByIDs(ctx, ids)
+	if err != nil {
+		return nil, l.failure(ctx, "Failed to load locations", err)
+	}
+	locations := make(map[int32]*Location, len(rows))
+	for _, row := range rows {
+		locations[row.ID] = newLocation(row)
+	}
+	return locations, nil
+}
+
+func (l *loaders) fetchStock(ctx context.Context, locationIDs []int32) (map[int32][]StockItem, error) {
+	rows, err := l.queries.ListSparepartStocksByLocations(ctx, locationIDs)
+	if err != nil {
+		return nil, l.failure(ctx, "Failed to load stock", err)
+	}
+	stock := make(map[int32][]StockItem, len(locationIDs))
+	for _, id := range locationIDs {
+		stock[id] = []StockItem{}
+	}
+	for _, row := range rows {
+		stock[row.LocationID] = append(stock[row.LocationID], newStockItem(sqlcdb.ListSparepartStockItemsRow(row)))
+	}
+	return stock, nil
+}
+
+func (l *loaders) fetchToolsAlker(ctx context.Context, locationIDs []int32) (map[int32][]ToolsAlkerItem, error) {
+	rows, err := l.queries.ListToolsAlkersByLocations(ctx, locationIDs)
+	if err != nil {
+		return nil, l.failure(ctx, "Failed to load tools alker", err)
+	}
+	items := make(map[int32][]ToolsAlkerItem, len(locationIDs))
+	for _, id := range locationIDs {
+		items[id] = []ToolsAlkerItem{}
+	}
+	for _, row := range rows {
+		items[row.LocationID] = append(items[row.LocationID], newToolsAlkerItem(row))
+	}
+	return items, nil
+}
+
+func (l *loaders) fetchContactPersons(ctx context.Context, locationIDs []int32) (map[int32][]ContactPerson, error) {
+	rows, err := l.queries.ListContactPersonsByLocations(ctx, locationIDs)
+	if err != nil {
+		return nil, l.failure(ctx, "Failed to load contact persons", err)
+	}
+	contacts := make(map[int32][]ContactPerson, len(locationIDs))
+	for _, id := range locationIDs {
+		contacts[id] = []ContactPerson{}
+	}
+	for _, row := range rows {
+		contacts[row.LocationID] = append(contacts[row.LocationID], newContactPerson(row))
+	}
+	return contacts, nil
+}
@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"strconv"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/utils"
+)
+
+// The models below are what the resolvers return; graphql-go reads their fields by JSON name.
+// References to other objects are kept as IDs and resolved through the dataloaders.
+
+type Location struct {
+	ID        string   `json:"id"`
+	Region    string   `json:"region"`
+	Regency   string   `json:"regency"`
+	Cluster   string   `json:"cluster"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	IsActive  bool     `json:"isActive"`
+	rowID     int32
+}
+
+type Sparepart struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ItemType string `json:"itemType"`
+}
+
+type Photo struct {
+	URL          string  `json:"url"`
+	ThumbnailURL string  `json:"thumbnailUrl"`
+	Caption      *string `json:"caption"`
+	TakenAt      *string `json:"takenAt"`
+	UploadedBy   *string `json:"uploadedBy"`
+}
+
+type StockItem struct {
+	ID            string    `json:"id"`
+	Sparepart     Sparepart `json:"sparepart"`
+	StockType     string    `json:"stockType"`
+	Quantity      int32     `json:"quantity"`
+	Notes         *string   `json:"notes"`
+	Documentation []Photo   `json:"documentation"`
+	UpdatedAt     string    `json:"updatedAt"`
+	locationID    int32
+}
+
+type ToolsAlkerItem struct {
+	ID            string    `json:"id"`
+	Tools         Sparepart `json:"tools"`
+	Quantity      int32     `json:"quantity"`
+	CheckedOut    int32     `json:"checkedOut"`
+	Available     int32     `json:"available"`
+	Notes         *string   `json:"notes"`
+	Documentation []Photo   `json:"documentation"`
+	UpdatedAt     string    `json:"updatedAt"`
+	locationID    int32
+}
+
+type ContactPerson struct {
+	ID         string  `json:"id"`
+	Pic        string  `json:"pic"`
+	Phone      string  `json:"phone"`
+	Email      *string `json:"email"`
+	locationID int32
+}
+
+type PageInfo struct {
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	Total      int `json:"total"`
+	TotalPages int `json:"totalPages"`
+}
+
+type LocationPage struct {
+	Items    []*Location `json:"items"`
+	PageInfo PageInfo    `json:"pageInfo"`
+}
+
+type StockItemPage struct {
+	Items    []StockItem `json:"items"`
+	PageInfo PageInfo    `json:"pageInfo"`
+}
+
+func newLocation(row sqlcdb.Location) *Location {
+	location := &Location{
+		ID:       formatID(row.ID),
+		Region:   string(row.Region),
+		Regency:  row.Regency,
+		Cluster:  row.Cluster,
+		IsActive: row.IsActive,
+		rowID:    row.ID,
+	}
+	if row.Latitude.Valid && row.Longitude.Valid {
+		location.Latitude = &row.Latitude.Float64
+		location.Longitude = &row.Longitude.Float64
+	}
+	return location
+}
+
+func newStockItem(row sqlcdb.ListSparepartStockItemsRow) StockItem {
+	return StockItem{
+		ID: formatID(row.ID),
+		Sparepart: Sparepart{
+			ID:       formatID(row.SparepartID),
+			Name:     row.SparepartName,
+			ItemType: string(row.ItemType),
+		},
+		StockType:     string(row.StockType),
+		Quantity:      row.Quantity,
+		Notes:         optionalText(row.Notes.String, row.Notes.Valid),
+		Documentation: newPhotos(row.Documentation),
+		UpdatedAt:     row.UpdatedAt.Time.UTC().Format(time.RFC3339),
+		locationID:    row.LocationID,
+	}
+}
+
+func newToolsAlkerItem(row sqlcdb.ListToolsAlkersByLocationsRow) ToolsAlkerItem {
+	return ToolsAlkerItem{
+		ID: formatID(row.ID),
+		Tools: Sparepart{
+			ID:       formatID(row.ToolsID),
+			Name:     row.ToolsName,
+			ItemType: string(row.ItemType),
+		},
+		Quantity:      row.Quantity,
+		CheckedOut:    row.CheckedOut,
+		Available:     max(row.Quantity-row.CheckedOut, 0),
+		Notes:         optionalText(row.Notes.String, row.Notes.Valid),
+		Documentation: newPhotos(row.Documentation),
+		UpdatedAt:     row.UpdatedAt.Time.UTC().Format(time.RFC3339),
+		locationID:    row.LocationID,
+	}
+}
+
+func newContactPerson(row sqlcdb.ContactPerson) ContactPerson {
+	return ContactPerson{
+		ID:         formatID(row.ID),
+		Pic:        row.Pic,
+		Phone:      row.Phone,
+		Email:      optionalText(row.Email.String, row.Email.Valid),
+		locationID: row.LocationID,
+	}
+}
+
+// newPhotos converts a documentation JSONB array, see utils.ParsePhotos
+func newPhotos(data []byte) []Photo {
+	docs := utils.ParsePhotos(data)
+	photos := make([]Photo, 0, len(docs))
+	for _, doc := range docs {
+		photo := Photo{
+			URL:          doc.URL,
+			ThumbnailURL: utils.ThumbnailPath(doc.URL),
+			Caption:      optionalText(doc.Caption, doc.Caption != ""),
+			UploadedBy:   optionalText(doc.UploadedBy, doc.UploadedBy != ""),
+		}
+		if doc.TakenAt != nil {
+			takenAt := doc.TakenAt.UTC().Format(time.RFC3339)
+			photo.TakenAt = &takenAt
+		}
+		photos = append(photos, photo)
+	}
+	return photos
+}
+
+func optionalText(value string, valid bool) *string {
+	if !valid {
+		return nil
+	}
+	return &value
+}
+
+func formatID(id int32) string {
+	return strconv.FormatInt(int64(id), 10)
+}
@@ -0,0 +1,439 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Request is a GraphQL request as POSTed to /graphql
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Execute runs req against the schema of schema.graphqls, with fresh dataloaders over queries.
+// Queries nesting deeper than maxQueryDepth are rejected before anything is loaded.
+func Execute(ctx context.Context, queries repository.GraphRepository, logger *zap.Logger, req Request) *graphql.Result {
+	// A query that does not parse is left to graphql.Do, which reports the syntax error
+	if doc, err := parser.Parse(parser.ParseParams{Source: req.Query}); err == nil {
+		if err := checkDepth(doc, maxQueryDepth); err != nil {
+			return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())}}
+		}
+	}
+
+	return graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        withLoaders(ctx, queries, logger),
+	})
+}
+
+var schema = mustBuildSchema()
+
+func enumOf[T ~string](name string, values ...T) *graphql.Enum {
+	config := graphql.EnumValueConfigMap{}
+	for _, value := range values {
+		config[string(value)] = &graphql.EnumValueConfig{Value: string(value)}
+	}
+	return graphql.NewEnum(graphql.EnumConfig{Name: name, Values: config})
+}
+
+func nonNullList(of graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(of)))
+}
+
+func mustBuildSchema() graphql.Schema {
+	regionEnum := enumOf("Region",
+		sqlcdb.RegionTypeMALUKU, sqlcdb.RegionTypeMALUKUUTARA, sqlcdb.RegionTypePAPUA,
+		sqlcdb.RegionTypePAPUABARAT, sqlcdb.RegionTypePAPUABARATDAYA, sqlcdb.RegionTypePAPUASELATAN)
+	stockTypeEnum := enumOf("StockType",
+		sqlcdb.StockTypeNEWSTOCK, sqlcdb.StockTypeUSEDSTOCK, sqlcdb.StockTypeDAMAGED,
+		sqlcdb.StockTypeINREPAIR, sqlcdb.StockTypeRESERVED)
+	itemTypeEnum := enumOf("ItemType", sqlcdb.ItemTypeSPAREPART, sqlcdb.ItemTypeTOOLSALKER)
+
+	sparepartType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Sparepart",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"name":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"itemType": &graphql.Field{Type: graphql.NewNonNull(itemTypeEnum)},
+		},
+	})
+
+	photoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Photo",
+		Fields: graphql.Fields{
+			"url":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"thumbnailUrl": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"caption":      &graphql.Field{Type: graphql.String},
+			"takenAt":      &graphql.Field{Type: graphql.String},
+			"uploadedBy":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"page":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"limit":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"total":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"totalPages": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	// Location and the item types reference each other, so their fields are added once all exist
+	locationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Location",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"region":    &graphql.Field{Type: graphql.NewNonNull(regionEnum)},
+			"regency":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"cluster":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"latitude":  &graphql.Field{Type: graphql.Float},
+			"longitude": &graphql.Field{Type: graphql.Float},
+			"isActive":  &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		},
+	})
+	locationField := func(locationID func(source interface{}) int32) *graphql.Field {
+		return &graphql.Field{
+			Type: graphql.NewNonNull(locationType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loadersFrom(p.Context).location(p.Context, locationID(p.Source)), nil
+			},
+		}
+	}
+
+	stockItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "StockItem",
+		Fields: graphql.Fields{
+			"id":            &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"location":      locationField(func(source interface{}) int32 { return source.(StockItem).locationID }),
+			"sparepart":     &graphql.Field{Type: graphql.NewNonNull(sparepartType)},
+			"stockType":     &graphql.Field{Type: graphql.NewNonNull(stockTypeEnum)},
+			"quantity":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"notes":         &graphql.Field{Type: graphql.String},
+			"documentation": &graphql.Field{Type: nonNullList(photoType)},
+			"updatedAt":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	toolsAlkerItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ToolsAlkerItem",
+		Fields: graphql.Fields{
+			"id":            &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"location":      locationField(func(source interface{}) int32 { return source.(ToolsAlkerItem).locationID }),
+			"tools":         &graphql.Field{Type: graphql.NewNonNull(sparepartType)},
+			"quantity":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"checkedOut":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"available":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"notes":         &graphql.Field{Type: graphql.String},
+			"documentation": &graphql.Field{Type: nonNullList(photoType)},
+			"updatedAt":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	contactPersonType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ContactPerson",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"location": locationField(func(source interface{}) int32 { return source.(ContactPerson).locationID }),
+			"pic":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"phone":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"email":    &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	locationType.AddFieldConfig("stock", &graphql.Field{
+		Type: nonNullList(stockItemType),
+		Args: graphql.FieldConfigArgument{
+			"stockType": &graphql.ArgumentConfig{Type: stockTypeEnum},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			stockType, _ := p.Args["stockType"].(string)
+			load := loadersFrom(p.Context).stock.load(p.Context, p.Source.(*Location).rowID)
+			return func() (interface{}, error) {
+				items, err := load()
+				if err != nil || stockType == "" {
+					return items, err
+				}
+				filtered := []StockItem{}
+				for _, item := range items.([]StockItem) {
+					if item.StockType == stockType {
+						filtered = append(filtered, item)
+					}
+				}
+				return filtered, nil
+			}, nil
+		},
+	})
+	locationType.AddFieldConfig("toolsAlker", &graphql.Field{
+		Type: nonNullList(toolsAlkerItemType),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return loadersFrom(p.Context).toolsAlker.load(p.Context, p.Source.(*Location).rowID), nil
+		},
+	})
+	locationType.AddFieldConfig("contactPersons", &graphql.Field{
+		Type: nonNullList(contactPersonType),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return loadersFrom(p.Context).contactPersons.load(p.Context, p.Source.(*Location).rowID), nil
+		},
+	})
+
+	locationPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "LocationPage",
+		Fields: graphql.Fields{
+			"items":    &graphql.Field{Type: nonNullList(locationType)},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	stockItemPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "StockItemPage",
+		Fields: graphql.Fields{
+			"items":    &graphql.Field{Type: nonNullList(stockItemType)},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	pageArgs := func(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args["page"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1}
+		args["limit"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: utils.DefaultPageLimit}
+		return args
+	}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"locations": &graphql.Field{
+				Type: graphql.NewNonNull(locationPageType),
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"region":          &graphql.ArgumentConfig{Type: regionEnum},
+					"regency":         &graphql.ArgumentConfig{Type: graphql.String},
+					"cluster":         &graphql.ArgumentConfig{Type: graphql.String},
+					"includeInactive": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				}),
+				Resolve: resolveLocations,
+			},
+			"location": &graphql.Field{
+				Type: locationType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: resolveLocation,
+			},
+			"stock": &graphql.Field{
+				Type: graphql.NewNonNull(stockItemPageType),
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"region":    &graphql.ArgumentConfig{Type: regionEnum},
+					"regency":   &graphql.ArgumentConfig{Type: graphql.String},
+					"cluster":   &graphql.ArgumentConfig{Type: graphql.String},
+					"stockType": &graphql.ArgumentConfig{Type: stockTypeEnum},
+					"name":      &graphql.ArgumentConfig{Type: graphql.String},
+				}),
+				Resolve: resolveStock,
+			},
+			"toolsAlker": &graphql.Field{
+				Type: nonNullList(toolsAlkerItemType),
+				Args: graphql.FieldConfigArgument{
+					"locationId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := parseID(p.Args["locationId"], "locationId")
+					if err != nil {
+						return nil, err
+					}
+					return loadersFrom(p.Context).toolsAlker.load(p.Context, id), nil
+				},
+			},
+			"contactPersons": &graphql.Field{
+				Type: nonNullList(contactPersonType),
+				Args: graphql.FieldConfigArgument{
+					"locationId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := parseID(p.Args["locationId"], "locationId")
+					if err != nil {
+						return nil, err
+					}
+					return loadersFrom(p.Context).contactPersons.load(p.Context, id), nil
+				},
+			},
+		},
+	})
+
+	s, err := graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+	if err != nil {
+		panic("graph: invalid schema: " + err.Error())
+	}
+	return s
+}
+
+// location returns a thunk resolving to the location of an item, which always exists
+func (l *loaders) location(ctx context.Context, id int32) func() (interface{}, error) {
+	load := l.locations.load(ctx, id)
+	return func() (interface{}, error) {
+		location, err := load()
+		if err != nil {
+			return nil, err
+		}
+		if location.(*Location) == nil {
+			return nil, errors.New("location " + formatID(id) + " not found")
+		}
+		return location, nil
+	}
+}
+
+func resolveLocations(p graphql.ResolveParams) (interface{}, error) {
+	l := loadersFrom(p.Context)
+	page, err := parsePage(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	region, regency, cluster := textArg(p.Args, "region"), textArg(p.Args, "regency"), textArg(p.Args, "cluster")
+	includeInactive, _ := p.Args["includeInactive"].(bool)
+	rows, err := l.queries.ListLocations(p.Context, sqlcdb.ListLocationsParams{
+		Region:          region,
+		Regency:         regency,
+		Cluster:         cluster,
+		IncludeInactive: includeInactive,
+		Limit:           int32(page.Limit),
+		Offset:          int32(page.Offset()),
+	})
+	if err != nil {
+		return nil, l.failure(p.Context, "Failed to get locations", err)
+	}
+	total, err := l.queries.CountLocations(p.Context, sqlcdb.CountLocationsParams{
+		Region:          region,
+		Regency:         regency,
+		Cluster:         cluster,
+		IncludeInactive: includeInactive,
+	})
+	if err != nil {
+		return nil, l.failure(p.Context, "Failed to count locations", err)
+	}
+
+	items := make([]*Location, len(rows))
+	for i, row := range rows {
+		items[i] = newLocation(row)
+	}
+	return LocationPage{Items: items, PageInfo: newPageInfo(page, total)}, nil
+}
+
+func resolveLocation(p graphql.ResolveParams) (interface{}, error) {
+	l := loadersFrom(p.Context)
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, err
+	}
+	row, err := l.queries.GetLocation(p.Context, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, l.failure(p.Context, "Failed to get location", err)
+	}
+	return newLocation(row), nil
+}
+
+func resolveStock(p graphql.ResolveParams) (interface{}, error) {
+	l := loadersFrom(p.Context)
+	page, err := parsePage(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if name, ok := p.Args["name"].(string); ok && name != "" {
+		names = []string{name}
+	}
+	filter := sqlcdb.CountSparepartStockItemsParams{
+		Region:    textArg(p.Args, "region"),
+		Regency:   textArg(p.Args, "regency"),
+		Cluster:   textArg(p.Args, "cluster"),
+		StockType: textArg(p.Args, "stockType"),
+		Names:     names,
+	}
+	rows, err := l.queries.ListSparepartStockItems(p.Context, sqlcdb.ListSparepartStockItemsParams{
+		Region:    filter.Region,
+		Regency:   filter.Regency,
+		Cluster:   filter.Cluster,
+		StockType: filter.StockType,
+		Names:     filter.Names,
+		Limit:     int32(page.Limit),
+		Offset:    int32(page.Offset()),
+	})
+	if err != nil {
+		return nil, l.failure(p.Context, "Failed to get stock", err)
+	}
+	total, err := l.queries.CountSparepartStockItems(p.Context, filter)
+	if err != nil {
+		return nil, l.failure(p.Context, "Failed to count stock", err)
+	}
+
+	items := make([]StockItem, len(rows))
+	for i, row := range rows {
+		items[i] = newStockItem(row)
+	}
+	return StockItemPage{Items: items, PageInfo: newPageInfo(page, total)}, nil
+}
+
+// parsePage reads the page and limit arguments like utils.ParsePagination reads the query params
+func parsePage(args map[string]interface{}) (utils.Pagination, error) {
+	page, _ := args["page"].(int)
+	limit, _ := args["limit"].(int)
+	if page < 1 {
+		return utils.Pagination{}, errors.New("page must be a positive integer")
+	}
+	if limit < 1 {
+		return utils.Pagination{}, errors.New("limit must be a positive integer")
+	}
+	p := utils.Pagination{Page: page, Limit: min(limit, utils.MaxPageLimit)}
+	if int64(p.Page-1)*int64(p.Limit) > math.MaxInt32 {
+		return utils.Pagination{}, errors.New("page is too large")
+	}
+	return p, nil
+}
+
+func newPageInfo(page utils.Pagination, total int64) PageInfo {
+	return PageInfo{
+		Page:       page.Page,
+		Limit:      page.Limit,
+		Total:      int(total),
+		TotalPages: int((total + int64(page.Limit) - 1) / int64(page.Limit)),
+	}
+}
+
+func parseID(value interface{}, name string) (int32, error) {
+	s, _ := value.(string)
+	id, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || id < 1 {
+		return 0, errors.New(name + " must be a positive integer")
+	}
+	return int32(id), nil
+}
+
+// textArg is an optional string or enum argument as a nullable query parameter
+func textArg(args map[string]interface{}, name string) pgtype.Text {
+	value, ok := args[name].(string)
+	if !ok || value == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: value, Valid: true}
+}
@@ -0,0 +1,278 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func execute(t *testing.T, queries *mocks.MockGraphRepository, query string, variables map[string]interface{}) (map[string]interface{}, []string) {
+	t.Helper()
+
+	result := Execute(context.Background(), queries, zap.NewNop(), Request{Query: query, Variables: variables})
+	var messages []string
+	for _, err := range result.Errors {
+		messages = append(messages, err.Message)
+	}
+
+	// Round trip through JSON, as the handler responds
+	body, err := json.Marshal(result.Data)
+	if err != nil {
+		t.Fatalf("failed to encode data: %v", err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		t.Fatalf("failed to decode data: %v", err)
+	}
+	return data, messages
+}
+
+func TestLocationsLoadNestedListsOnceForThePage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	queries := mocks.NewMockGraphRepository(ctrl)
+
+	queries.EXPECT().ListLocations(gomock.Any(), sqlcdb.ListLocationsParams{
+		Region: pgtype.Text{String: "PAPUA", Valid: true},
+		Limit:  2,
+	}).Return([]sqlcdb.Location{
+		{ID: 4, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Sentani", IsActive: true},
+		{ID: 9, Region: sqlcdb.RegionTypePAPUA, Regency: "Merauke", Cluster: "Kurik", IsActive: true},
+	}, nil)
+	queries.EXPECT().CountLocations(gomock.Any(), sqlcdb.CountLocationsParams{
+		Region: pgtype.Text{String: "PAPUA", Valid: true},
+	}).Return(int64(3), nil)
+
+	// One query per level for the whole page, not one per location
+	queries.EXPECT().ListSparepartStocksByLocations(gomock.Any(), []int32{4, 9}).Return([]sqlcdb.ListSparepartStocksByLocationsRow{
+		{ID: 10, LocationID: 4, SparepartID: 2, SparepartName: "BMS", ItemType: sqlcdb.ItemTypeSPAREPART, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 3,
+			Documentation: []byte(`["/uploads/sparepart/new_stock/a.jpg"]`)},
+		{ID: 11, LocationID: 4, SparepartID: 3, SparepartName: "Inverter", ItemType: sqlcdb.ItemTypeSPAREPART, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 1},
+		{ID: 12, LocationID: 9, SparepartID: 2, SparepartName: "BMS", ItemType: sqlcdb.ItemTypeSPAREPART, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 5},
+	}, nil)
+	queries.EXPECT().ListContactPersonsByLocations(gomock.Any(), []int32{4, 9}).Return([]sqlcdb.ContactPerson{
+		{ID: 7, LocationID: 9, Pic: "Budi", Phone: "0812"},
+	}, nil)
+	queries.EXPECT().ListLocationsByIDs(gomock.Any(), []int32{4, 9}).Return([]sqlcdb.Location{
+		{ID: 4, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Sentani"},
+		{ID: 9, Region: sqlcdb.RegionTypePAPUA, Regency: "Merauke", Cluster: "Kurik"},
+	}, nil)
+
+	data, errs := execute(t, queries, `query ($region: Region) {
+		locations(region: $region, limit: 2) {
+			items {
+				id
+				stock(stockType: NEW_STOCK) { id quantity sparepart { name } location { cluster } documentation { url thumbnailUrl } }
+				contactPersons { pic }
+			}
+			pageInfo { total totalPages }
+		}
+	}`, map[string]interface{}{"region": "PAPUA"})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	page := data["locations"].(map[string]interface{})
+	if info := page["pageInfo"].(map[string]interface{}); info["total"] != 3.0 || info["totalPages"] != 2.0 {
+		t.Fatalf("unexpected page info %v", info)
+	}
+	items := page["items"].([]interface{})
+	if len(items) != 2 {
+		t.Fatalf("expected 2 locations, got %v", items)
+	}
+
+	first := items[0].(map[string]interface{})
+	stock := first["stock"].([]interface{})
+	if len(stock) != 1 {
+		t.Fatalf("expected the new stock of location 4 only, got %v", stock)
+	}
+	item := stock[0].(map[string]interface{})
+	if item["id"] != "10" || item["sparepart"].(map[string]interface{})["name"] != "BMS" || item["location"].(map[string]interface{})["cluster"] != "Sentani" {
+		t.Fatalf("unexpected stock item %v", item)
+	}
+	photo := item["documentation"].([]interface{})[0].(map[string]interface{})
+	if photo["url"] != "/uploads/sparepart/new_stock/a.jpg" || photo["thumbnailUrl"] != "/uploads/sparepart/new_stock/a_thumb.jpg" {
+		t.Fatalf("unexpected photo %v", photo)
+	}
+	if contacts := first["contactPersons"].([]interface{}); len(contacts) != 0 {
+		t.Fatalf("expected no contact persons at location 4, got %v", contacts)
+	}
+
+	second := items[1].(map[string]interface{})
+	if contacts := second["contactPersons"].([]interface{}); len(contacts) != 1 || contacts[0].(map[string]interface{})["pic"] != "Budi" {
+		t.Fatalf("unexpected contact persons at location 9: %v", contacts)
+	}
+}
+
+func TestLocationNotFoundIsNull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	queries := mocks.NewMockGraphRepository(ctrl)
+
+	queries.EXPECT().GetLocation(gomock.Any(), int32(5)).Return(sqlcdb.Location{}, pgx.ErrNoRows)
+
+	data, errs := execute(t, queries, `{ location(id: 5) { id } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if data["location"] != nil {
+		t.Fatalf("expected null, got %v", data["location"])
+	}
+}
+
+func TestStockHidesDatabaseErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	queries := mocks.NewMockGraphRepository(ctrl)
+
+	queries.EXPECT().ListSparepartStockItems(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	_, errs := execute(t, queries, `{ stock(name: "BMS") { items { id } } }`, nil)
+	if len(errs) != 1 || errs[0] != "Failed to get stock" {
+		t.Fatalf("expected the database error to be hidden, got %v", errs)
+	}
+}
+
+func TestStockRejectsInvalidPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	queries := mocks.NewMockGraphRepository(ctrl)
+
+	_, errs := execute(t, queries, `{ stock(page: 0) { items { id } } }`, nil)
+	if len(errs) != 1 || errs[0] != "page must be a positive integer" {
+		t.Fatalf("expected a page error, got %v", errs)
+	}
+}
+
+// describeSDL lists every type of an SDL document, one line per field (with its arguments
+// sorted, as graphql-go keeps them in a map) or enum value, e.g. "Query.stock(limit: Int = 10, page: Int = 1): StockItemPage!"
+func describeSDL(t *testing.T, sdl string) []string {
+	t.Helper()
+	doc, err := parser.Parse(parser.ParseParams{Source: sdl})
+	if err != nil {
+		t.Fatalf("failed to parse schema.graphqls: %v", err)
+	}
+
+	var typeString func(ast.Type) string
+	typeString = func(typ ast.Type) string {
+		switch typ := typ.(type) {
+		case *ast.NonNull:
+			return typeString(typ.Type) + "!"
+		case *ast.List:
+			return "[" + typeString(typ.Type) + "]"
+		case *ast.Named:
+			return typ.Name.Value
+		}
+		t.Fatalf("unexpected type %T", typ)
+		return ""
+	}
+
+	var lines []string
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.ObjectDefinition:
+			for _, field := range definition.Fields {
+				var args []string
+				for _, arg := range field.Arguments {
+					described := arg.Name.Value + ": " + typeString(arg.Type)
+					if arg.DefaultValue != nil {
+						described += fmt.Sprintf(" = %v", arg.DefaultValue.GetValue())
+					}
+					args = append(args, described)
+				}
+				slices.Sort(args)
+				lines = append(lines, fmt.Sprintf("%s.%s(%s): %s", definition.Name.Value, field.Name.Value, strings.Join(args, ", "), typeString(field.Type)))
+			}
+		case *ast.EnumDefinition:
+			for _, value := range definition.Values {
+				lines = append(lines, definition.Name.Value+"."+value.Name.Value)
+			}
+		default:
+			t.Fatalf("unexpected definition %T in schema.graphqls", definition)
+		}
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// describeSchema lists the types of the built schema the way describeSDL does
+func describeSchema() []string {
+	var lines []string
+	for name, typ := range schema.TypeMap() {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		switch typ := typ.(type) {
+		case *graphql.Object:
+			for fieldName, field := range typ.Fields() {
+				var args []string
+				for _, arg := range field.Args {
+					described := arg.Name() + ": " + arg.Type.String()
+					if arg.DefaultValue != nil {
+						described += fmt.Sprintf(" = %v", arg.DefaultValue)
+					}
+					args = append(args, described)
+				}
+				slices.Sort(args)
+				lines = append(lines, fmt.Sprintf("%s.%s(%s): %s", name, fieldName, strings.Join(args, ", "), field.Type.String()))
+			}
+		case *graphql.Enum:
+			for _, value := range typ.Values() {
+				lines = append(lines, name+"."+value.Name)
+			}
+		}
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+func TestSchemaMatchesSDL(t *testing.T) {
+	sdl, err := os.ReadFile("schema.graphqls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented, built := describeSDL(t, string(sdl)), describeSchema()
+	for _, line := range built {
+		if !slices.Contains(documented, line) {
+			t.Errorf("schema.graphqls is missing %s", line)
+		}
+	}
+	for _, line := range documented {
+		if !slices.Contains(built, line) {
+			t.Errorf("schema.go does not build %s", line)
+		}
+	}
+}
+
+func TestQueryDepthIsLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	queries := mocks.NewMockGraphRepository(ctrl)
+
+	// Location -> stock -> location repeats without end; nothing may be loaded for it
+	_, messages := execute(t, queries, `{ location(id: 1) { stock { location { stock { location { stock { location { stock { id } } } } } } } } }`, nil)
+	if len(messages) != 1 || !strings.Contains(messages[0], "exceeds the maximum") {
+		t.Fatalf("expected a depth error, got %v", messages)
+	}
+
+	// Fragments count where they are spread
+	_, messages = execute(t, queries, `
+		query { location(id: 1) { ...deep } }
+		fragment deep on Location { stock { location { stock { location { stock { location { stock { id } } } } } } } }
+	`, nil)
+	if len(messages) != 1 || !strings.Contains(messages[0], "exceeds the maximum") {
+		t.Fatalf("expected a depth error through a fragment, got %v", messages)
+	}
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// AlertRuleRequest defines an alert rule. Scope fields left empty match everything, e.g.
+// region MALUKU + sparepart_id of BMS + stock_type USED_STOCK + GT 5 fires for every
+// MALUKU location holding more than 5 used BMS.
+type AlertRuleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Region      *string  `json:"region" binding:"omitempty,oneof=MALUKU MALUKU_UTARA PAPUA PAPUA_BARAT PAPUA_BARAT_DAYA PAPUA_SELATAN"`
+	LocationID  *int     `json:"location_id" binding:"omitempty,min=1"`
+	SparepartID *int     `json:"sparepart_id" binding:"omitempty,min=1"`
+	StockType   *string  `json:"stock_type" binding:"omitempty,oneof=NEW_STOCK USED_STOCK DAMAGED IN_REPAIR RESERVED"`
+	Operator    string   `json:"operator" binding:"required,oneof=GT GTE LT LTE EQ"`
+	Threshold   *int     `json:"threshold" binding:"required,min=0"`
+	Channel     string   `json:"channel" binding:"required,oneof=LOG WEBHOOK"`
+	Recipients  []string `json:"recipients" binding:"omitempty,dive,required"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+// AlertRuleResponse is an alert rule with its scope flattened to nullable fields
+type AlertRuleResponse struct {
+	ID          int32    `json:"id"`
+	Name        string   `json:"name"`
+	Region      *string  `json:"region"`
+	LocationID  *int32   `json:"location_id"`
+	SparepartID *int32   `json:"sparepart_id"`
+	StockType   *string  `json:"stock_type"`
+	Operator    string   `json:"operator"`
+	Threshold   int32    `json:"threshold"`
+	Channel     string   `json:"channel"`
+	Recipients  []string `json:"recipients"`
+	Enabled     bool     `json:"enabled"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+type AlertRuleHandler struct {
+	logger  *zap.Logger
+	queries repository.AlertRuleRepository
+}
+
+func NewAlertRuleHandler(queries repository.AlertRuleRepository, logger *zap.Logger) *AlertRuleHandler {
+	return &AlertRuleHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get all alert rules
+// @Description Get all alert rules
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param enabled query bool false "Filter by enabled"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /alerts/rules [get]
+func (h *AlertRuleHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var enabled pgtype.Bool
+	if value := c.Query("enabled"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.ValidationError(c, utils.FieldError{Field: "enabled", Message: "must be true or false"})
+			return
+		}
+		enabled = pgtype.Bool{Bool: parsed, Valid: true}
+	}
+
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountAlertRules(ctx, enabled)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count alert rules", h.logger)
+		return
+	}
+
+	rules, err := h.queries.ListAlertRules(ctx, sqlcdb.ListAlertRulesParams{
+		Enabled: enabled,
+		Limit:   int32(pagination.Limit),
+		Offset:  int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get alert rules", h.logger)
+		return
+	}
+
+	response := make([]AlertRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		response = append(response, toAlertRuleResponse(rule))
+	}
+
+	utils.SuccessWithPagination(c, "Alert rules retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get alert rule by ID
+// @Description Get a single alert rule by ID
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert Rule ID"
+// @Success 200 {object} utils.Response
+// @Router /alerts/rules/{id} [get]
+func (h *AlertRuleHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid alert rule ID")
+		return
+	}
+
+	rule, err := h.queries.GetAlertRule(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Alert rule not found")
+		return
+	}
+
+	utils.Success(c, "Alert rule retrieved successfully", toAlertRuleResponse(rule))
+}
+
+// @Summary Create alert rule
+// @Description Create an alert rule; it is evaluated on the next scheduled run
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param rule body AlertRuleRequest true "Alert rule data"
+// @Success 201 {object} utils.Response
+// @Router /alerts/rules [post]
+func (h *AlertRuleHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	rule, err := h.queries.CreateAlertRule(ctx, alertRuleParams(req))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create alert rule", h.logger)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Alert rule created successfully",
+		Data:    toAlertRuleResponse(rule),
+	})
+}
+
+// @Summary Update alert rule
+// @Description Replace an existing alert rule
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert Rule ID"
+// @Param rule body AlertRuleRequest true "Alert rule data"
+// @Success 200 {object} utils.Response
+// @Router /alerts/rules/{id} [put]
+func (h *AlertRuleHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid alert rule ID")
+		return
+	}
+
+	// Check if alert rule exists
+	_, err = h.queries.GetAlertRule(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Alert rule not found")
+		return
+	}
+
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	params := alertRuleParams(req)
+	rule, err := h.queries.UpdateAlertRule(ctx, sqlcdb.UpdateAlertRuleParams{
+		ID:          int32(id),
+		Name:        params.Name,
+		Region:      params.Region,
+		LocationID:  params.LocationID,
+		SparepartID: params.SparepartID,
+		StockType:   params.StockType,
+		Operator:    params.Operator,
+		Threshold:   params.Threshold,
+		Channel:     params.Channel,
+		Recipients:  params.Recipients,
+		Enabled:     params.Enabled,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update alert rule", h.logger)
+		return
+	}
+
+	utils.Success(c, "Alert rule updated successfully", toAlertRuleResponse(rule))
+}
+
+// @Summary Delete alert rule
+// @Description Delete an alert rule
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert Rule ID"
+// @Success 200 {object} utils.Response
+// @Router /alerts/rules/{id} [delete]
+func (h *AlertRuleHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid alert rule ID")
+		return
+	}
+
+	// Check if alert rule exists
+	_, err = h.queries.GetAlertRule(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Alert rule not found")
+		return
+	}
+
+	if err := h.queries.DeleteAlertRule(ctx, int32(id)); err != nil {
+		utils.HandleError(c, err, "Failed to delete alert rule", h.logger)
+		return
+	}
+
+	utils.Success(c, "Alert rule deleted successfully", nil)
+}
+
+// alertRuleParams converts a validated request to query params; rules are enabled unless stated otherwise
+func alertRuleParams(req AlertRuleRequest) sqlcdb.CreateAlertRuleParams {
+	params := sqlcdb.CreateAlertRuleParams{
+		Name:        req.Name,
+		LocationID:  utils.OptionalInt(req.LocationID),
+		SparepartID: utils.OptionalInt(req.SparepartID),
+		Operator:    req.Operator,
+		Threshold:   int32(*req.Threshold),
+		Channel:     req.Channel,
+		Recipients:  req.Recipients,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+	}
+	if params.Recipients == nil {
+		params.Recipients = []string{}
+	}
+	if req.Region != nil {
+		params.Region = sqlcdb.NullRegionType{RegionType: sqlcdb.RegionType(*req.Region), Valid: true}
+	}
+	if req.StockType != nil {
+		params.StockType = sqlcdb.NullStockType{StockType: sqlcdb.StockType(*req.StockType), Valid: true}
+	}
+	return params
+}
+
+func toAlertRuleResponse(rule sqlcdb.AlertRule) AlertRuleResponse {
+	response := AlertRuleResponse{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Operator:   rule.Operator,
+		Threshold:  rule.Threshold,
+		Channel:    rule.Channel,
+		Recipients: rule.Recipients,
+		Enabled:    rule.Enabled,
+		CreatedAt:  utils.FormatTimestamp(rule.CreatedAt),
+		UpdatedAt:  utils.FormatTimestamp(rule.UpdatedAt),
+	}
+	if response.Recipients == nil {
+		response.Recipients = []string{}
+	}
+	if rule.Region.Valid {
+		region := string(rule.Region.RegionType)
+		response.Region = &region
+	}
+	if rule.LocationID.Valid {
+		response.LocationID = &rule.LocationID.Int32
+	}
+	if rule.SparepartID.Valid {
+		response.SparepartID = &rule.SparepartID.Int32
+	}
+	if rule.StockType.Valid {
+		stockType := string(rule.StockType.StockType)
+		response.StockType = &stockType
+	}
+	return response
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAlertRuleHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAlertRuleRepository(ctrl)
+	h := NewAlertRuleHandler(repo, testLogger)
+
+	params := sqlcdb.CreateAlertRuleParams{
+		Name:        "Used BMS in Maluku",
+		Region:      sqlcdb.NullRegionType{RegionType: sqlcdb.RegionTypeMALUKU, Valid: true},
+		SparepartID: pgtype.Int4{Int32: 7, Valid: true},
+		StockType:   sqlcdb.NullStockType{StockType: sqlcdb.StockTypeUSEDSTOCK, Valid: true},
+		Operator:    "GT",
+		Threshold:   5,
+		Channel:     "WEBHOOK",
+		Recipients:  []string{"coordinator@example.com"},
+		Enabled:     true,
+	}
+	repo.EXPECT().
+		CreateAlertRule(gomock.Any(), params).
+		Return(sqlcdb.AlertRule{
+			ID:          1,
+			Name:        params.Name,
+			Region:      params.Region,
+			SparepartID: params.SparepartID,
+			StockType:   params.StockType,
+			Operator:    params.Operator,
+			Threshold:   params.Threshold,
+			Channel:     params.Channel,
+			Recipients:  params.Recipients,
+			Enabled:     true,
+		}, nil)
+
+	body := `{"name": "Used BMS in Maluku", "region": "MALUKU", "sparepart_id": 7, "stock_type": "USED_STOCK",
+		"operator": "GT", "threshold": 5, "channel": "WEBHOOK", "recipients": ["coordinator@example.com"]}`
+	w := performRequest(http.MethodPost, "/alerts/rules", h.Create, "/alerts/rules", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var rule AlertRuleResponse
+	decodeResponse(t, w, &rule)
+	if rule.Region == nil || *rule.Region != "MALUKU" || rule.LocationID != nil || rule.SparepartID == nil || *rule.SparepartID != 7 {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+}
+
+func TestAlertRuleHandlerCreateValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAlertRuleRepository(ctrl)
+	h := NewAlertRuleHandler(repo, testLogger)
+
+	for _, body := range []string{
+		`{"name": "x", "operator": "ABOVE", "threshold": 5, "channel": "LOG"}`,
+		`{"name": "x", "operator": "GT", "channel": "LOG"}`,
+		`{"name": "x", "operator": "GT", "threshold": 5, "channel": "SMS"}`,
+		`{"name": "x", "region": "JAWA", "operator": "GT", "threshold": 5, "channel": "LOG"}`,
+	} {
+		w := performRequest(http.MethodPost, "/alerts/rules", h.Create, "/alerts/rules", body)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+}
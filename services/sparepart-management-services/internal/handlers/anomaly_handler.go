@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Anomaly statuses; an anomaly moves from OPEN to ACKNOWLEDGED to RESOLVED, or straight to RESOLVED
+const (
+	anomalyStatusOpen     = "OPEN"
+	anomalyStatusResolved = "RESOLVED"
+)
+
+// AnomalyResponse is an anomaly flagged by the background analyzer
+type AnomalyResponse struct {
+	ID             int64           `json:"id"`
+	Kind           string          `json:"kind"`
+	TableName      *string         `json:"table_name"`
+	RecordID       *int32          `json:"record_id"`
+	Actor          *string         `json:"actor"`
+	Details        json.RawMessage `json:"details"`
+	Status         string          `json:"status"`
+	Note           *string         `json:"note"`
+	DetectedAt     string          `json:"detected_at"`
+	AcknowledgedAt string          `json:"acknowledged_at,omitempty"`
+	ResolvedAt     string          `json:"resolved_at,omitempty"`
+}
+
+// AnomalyActionRequest is the optional body of the acknowledge and resolve actions
+type AnomalyActionRequest struct {
+	Note *string `json:"note"`
+}
+
+type AnomalyHandler struct {
+	logger  *zap.Logger
+	queries repository.AnomalyRepository
+}
+
+func NewAnomalyHandler(queries repository.AnomalyRepository, logger *zap.Logger) *AnomalyHandler {
+	return &AnomalyHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get anomalies
+// @Description Get suspicious stock changes flagged by the analyzer: QUANTITY_DROP, REPEATED_DELETES, PHOTOS_REMOVED
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (OPEN, ACKNOWLEDGED, RESOLVED)"
+// @Param kind query string false "Filter by kind"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /alerts/anomalies [get]
+func (h *AnomalyHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	status := utils.TextFilter(c.Query("status"))
+	kind := utils.TextFilter(c.Query("kind"))
+
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountAnomalies(ctx, sqlcdb.CountAnomaliesParams{Status: status, Kind: kind})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count anomalies", h.logger)
+		return
+	}
+
+	anomalies, err := h.queries.ListAnomalies(ctx, sqlcdb.ListAnomaliesParams{
+		Status: status,
+		Kind:   kind,
+		Limit:  int32(pagination.Limit),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get anomalies", h.logger)
+		return
+	}
+
+	response := make([]AnomalyResponse, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		response = append(response, toAnomalyResponse(anomaly))
+	}
+
+	utils.SuccessWithPagination(c, "Anomalies retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Acknowledge anomaly
+// @Description Mark an open anomaly as seen, optionally with a note
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Anomaly ID"
+// @Param request body AnomalyActionRequest false "Note"
+// @Success 200 {object} utils.Response
+// @Router /alerts/anomalies/{id}/acknowledge [post]
+func (h *AnomalyHandler) Acknowledge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, req, ok := h.parseAction(c)
+	if !ok {
+		return
+	}
+
+	current, ok := h.getAnomaly(c, id)
+	if !ok {
+		return
+	}
+	if current.Status != anomalyStatusOpen {
+		utils.Error(c, "Only open anomalies can be acknowledged", http.StatusConflict)
+		return
+	}
+
+	anomaly, err := h.queries.AcknowledgeAnomaly(ctx, sqlcdb.AcknowledgeAnomalyParams{ID: id, Note: utils.OptionalText(req.Note)})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to acknowledge anomaly", h.logger)
+		return
+	}
+
+	utils.Success(c, "Anomaly acknowledged successfully", toAnomalyResponse(anomaly))
+}
+
+// @Summary Resolve anomaly
+// @Description Close an open or acknowledged anomaly, optionally with a note
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Anomaly ID"
+// @Param request body AnomalyActionRequest false "Note"
+// @Success 200 {object} utils.Response
+// @Router /alerts/anomalies/{id}/resolve [post]
+func (h *AnomalyHandler) Resolve(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, req, ok := h.parseAction(c)
+	if !ok {
+		return
+	}
+
+	current, ok := h.getAnomaly(c, id)
+	if !ok {
+		return
+	}
+	if current.Status == anomalyStatusResolved {
+		utils.Error(c, "Anomaly is already resolved", http.StatusConflict)
+		return
+	}
+
+	anomaly, err := h.queries.ResolveAnomaly(ctx, sqlcdb.ResolveAnomalyParams{ID: id, Note: utils.OptionalText(req.Note)})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to resolve anomaly", h.logger)
+		return
+	}
+
+	utils.Success(c, "Anomaly resolved successfully", toAnomalyResponse(anomaly))
+}
+
+// parseAction reads the anomaly ID and the optional action body, writing a 400 on failure
+func (h *AnomalyHandler) parseAction(c *gin.Context) (int64, AnomalyActionRequest, bool) {
+	var req AnomalyActionRequest
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "Invalid anomaly ID")
+		return 0, req, false
+	}
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BindingError(c, err)
+			return 0, req, false
+		}
+	}
+	return id, req, true
+}
+
+func (h *AnomalyHandler) getAnomaly(c *gin.Context, id int64) (sqlcdb.Anomaly, bool) {
+	anomaly, err := h.queries.GetAnomaly(c.Request.Context(), id)
+	if err != nil {
+		utils.NotFound(c, "Anomaly not found")
+		return anomaly, false
+	}
+	return anomaly, true
+}
+
+func toAnomalyResponse(anomaly sqlcdb.Anomaly) AnomalyResponse {
+	response := AnomalyResponse{
+		ID:             anomaly.ID,
+		Kind:           anomaly.Kind,
+		Details:        json.RawMessage(anomaly.Details),
+		Status:         anomaly.Status,
+		DetectedAt:     utils.FormatTimestamp(anomaly.DetectedAt),
+		AcknowledgedAt: utils.FormatTimestamp(anomaly.AcknowledgedAt),
+		ResolvedAt:     utils.FormatTimestamp(anomaly.ResolvedAt),
+	}
+	if len(response.Details) == 0 {
+		response.Details = json.RawMessage("{}")
+	}
+	if anomaly.TableName.Valid {
+		response.TableName = &anomaly.TableName.String
+	}
+	if anomaly.RecordID.Valid {
+		response.RecordID = &anomaly.RecordID.Int32
+	}
+	if anomaly.Actor.Valid {
+		response.Actor = &anomaly.Actor.String
+	}
+	if anomaly.Note.Valid {
+		response.Note = &anomaly.Note.String
+	}
+	return response
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAnomalyHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAnomalyRepository(ctrl)
+	h := NewAnomalyHandler(repo, testLogger)
+
+	status := pgtype.Text{String: "open", Valid: true}
+	repo.EXPECT().
+		CountAnomalies(gomock.Any(), sqlcdb.CountAnomaliesParams{Status: status}).
+		Return(int64(1), nil)
+	repo.EXPECT().
+		ListAnomalies(gomock.Any(), sqlcdb.ListAnomaliesParams{Status: status, Limit: 10, Offset: 0}).
+		Return([]sqlcdb.Anomaly{
+			{
+				ID:        4,
+				Kind:      "QUANTITY_DROP",
+				TableName: pgtype.Text{String: "sparepart_stock_item", Valid: true},
+				RecordID:  pgtype.Int4{Int32: 12, Valid: true},
+				Details:   []byte(`{"quantity_before": 10, "quantity_after": 0}`),
+				Status:    "OPEN",
+			},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/alerts/anomalies", h.GetAll, "/alerts/anomalies?status=open", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var anomalies []AnomalyResponse
+	decodeResponse(t, w, &anomalies)
+	if len(anomalies) != 1 || anomalies[0].RecordID == nil || *anomalies[0].RecordID != 12 || anomalies[0].Actor != nil {
+		t.Fatalf("unexpected anomalies: %+v", anomalies)
+	}
+}
+
+func TestAnomalyHandlerAcknowledge(t *testing.T) {
+	note := pgtype.Text{String: "checked with site", Valid: true}
+
+	tests := []struct {
+		name       string
+		status     string
+		getErr     error
+		wantStatus int
+	}{
+		{name: "open anomaly", status: "OPEN", wantStatus: http.StatusOK},
+		{name: "already acknowledged", status: "ACKNOWLEDGED", wantStatus: http.StatusConflict},
+		{name: "not found", getErr: errors.New("no rows in result set"), wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockAnomalyRepository(ctrl)
+			h := NewAnomalyHandler(repo, testLogger)
+
+			repo.EXPECT().GetAnomaly(gomock.Any(), int64(4)).Return(sqlcdb.Anomaly{ID: 4, Status: tt.status}, tt.getErr)
+			if tt.wantStatus == http.StatusOK {
+				repo.EXPECT().
+					AcknowledgeAnomaly(gomock.Any(), sqlcdb.AcknowledgeAnomalyParams{ID: 4, Note: note}).
+					Return(sqlcdb.Anomaly{ID: 4, Status: "ACKNOWLEDGED", Note: note}, nil)
+			}
+
+			w := performRequest(http.MethodPost, "/alerts/anomalies/:id/acknowledge", h.Acknowledge, "/alerts/anomalies/4/acknowledge", `{"note": "checked with site"}`)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAnomalyHandlerResolveWithoutBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAnomalyRepository(ctrl)
+	h := NewAnomalyHandler(repo, testLogger)
+
+	repo.EXPECT().GetAnomaly(gomock.Any(), int64(4)).Return(sqlcdb.Anomaly{ID: 4, Status: "ACKNOWLEDGED"}, nil)
+	repo.EXPECT().
+		ResolveAnomaly(gomock.Any(), sqlcdb.ResolveAnomalyParams{ID: 4}).
+		Return(sqlcdb.Anomaly{ID: 4, Status: "RESOLVED"}, nil)
+
+	w := performRequest(http.MethodPost, "/alerts/anomalies/:id/resolve", h.Resolve, "/alerts/anomalies/4/resolve", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// CreateAPIKeyRequest issues an API key with the given scopes; without expires_in_days it
+// stays valid until revoked
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=read-only stock-write"`
+	ExpiresInDays *int     `json:"expires_in_days" binding:"omitempty,min=1,max=730"`
+}
+
+// APIKeyResponse describes an API key; Key is only returned on creation
+type APIKeyResponse struct {
+	ID         int32    `json:"id"`
+	Name       string   `json:"name"`
+	Key        string   `json:"key,omitempty"`
+	KeyPrefix  string   `json:"key_prefix"`
+	Scopes     []string `json:"scopes"`
+	CreatedBy  *string  `json:"created_by"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	RevokedAt  string   `json:"revoked_at,omitempty"`
+	Active     bool     `json:"active"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// APIKeyHandler lets admins issue and revoke the API keys machine clients (e.g. reporting
+// cron jobs) authenticate with instead of a user's token
+type APIKeyHandler struct {
+	logger  *zap.Logger
+	queries repository.APIKeyRepository
+}
+
+func NewAPIKeyHandler(queries repository.APIKeyRepository, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Create API key
+// @Description Issue an API key, sent as X-API-Key instead of a bearer token; read-only allows GET requests, stock-write also allows changing the stock. The key is only shown in this response
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param key body CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/admin/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.FieldError{Field: "name", Message: "is required"})
+		return
+	}
+
+	scopes := slices.Clone(req.Scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	var expiresAt pgtype.Timestamptz
+	if req.ExpiresInDays != nil {
+		expiresAt = pgtype.Timestamptz{Time: time.Now().UTC().AddDate(0, 0, *req.ExpiresInDays), Valid: true}
+	}
+
+	key, prefix, hash, err := utils.NewAPIKey()
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create API key", h.logger)
+		return
+	}
+
+	apiKey, err := h.queries.CreateAPIKey(ctx, sqlcdb.CreateAPIKeyParams{
+		Name:      name,
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		CreatedBy: utils.TextFilter(utils.UserID(c)),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create API key", h.logger)
+		return
+	}
+
+	response := toAPIKeyResponse(apiKey)
+	response.Key = key
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "API key created successfully",
+		Data:    response,
+	})
+}
+
+// @Summary Get API keys
+// @Description Get the API keys, newest first; the keys themselves are never shown again, only their prefix
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param active query bool false "Only keys that are usable (true) or expired/revoked (false)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/admin/api-keys [get]
+func (h *APIKeyHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	var active pgtype.Bool
+	if value := c.Query("active"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "active", Message: "must be true or false"})
+		} else {
+			active = pgtype.Bool{Bool: parsed, Valid: true}
+		}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountAPIKeys(ctx, active)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count API keys", h.logger)
+		return
+	}
+
+	apiKeys, err := h.queries.ListAPIKeys(ctx, sqlcdb.ListAPIKeysParams{
+		Active: active,
+		Limit:  int32(pagination.Limit),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get API keys", h.logger)
+		return
+	}
+
+	response := make([]APIKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		response = append(response, toAPIKeyResponse(apiKey))
+	}
+
+	utils.SuccessWithPagination(c, "API keys retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Revoke API key
+// @Description Revoke an API key; it stops working immediately
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid API key ID")
+		return
+	}
+
+	// Check if API key exists
+	_, err = h.queries.GetAPIKey(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "API key not found")
+		return
+	}
+
+	apiKey, err := h.queries.RevokeAPIKey(ctx, int32(id))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to revoke API key", h.logger)
+		return
+	}
+
+	utils.Success(c, "API key revoked successfully", toAPIKeyResponse(apiKey))
+}
+
+func toAPIKeyResponse(apiKey sqlcdb.ApiKey) APIKeyResponse {
+	response := APIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		KeyPrefix:  apiKey.KeyPrefix,
+		Scopes:     apiKey.Scopes,
+		ExpiresAt:  utils.FormatTimestamp(apiKey.ExpiresAt),
+		RevokedAt:  utils.FormatTimestamp(apiKey.RevokedAt),
+		Active:     !apiKey.RevokedAt.Valid && (!apiKey.ExpiresAt.Valid || apiKey.ExpiresAt.Time.After(time.Now())),
+		LastUsedAt: utils.FormatTimestamp(apiKey.LastUsedAt),
+		CreatedAt:  utils.FormatTimestamp(apiKey.CreatedAt),
+	}
+	if response.Scopes == nil {
+		response.Scopes = []string{}
+	}
+	if apiKey.CreatedBy.Valid {
+		response.CreatedBy = &apiKey.CreatedBy.String
+	}
+	return response
+}
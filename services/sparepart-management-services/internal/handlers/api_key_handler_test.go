@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	h := NewAPIKeyHandler(repo, testLogger)
+
+	var storedHash string
+	repo.EXPECT().
+		CreateAPIKey(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.CreateAPIKeyParams) (sqlcdb.ApiKey, error) {
+			if arg.Name != "reporting cron" || !slices.Equal(arg.Scopes, []string{"read-only"}) || arg.ExpiresAt.Valid {
+				t.Errorf("unexpected API key: %+v", arg)
+			}
+			if arg.CreatedBy.String != "admin-1" {
+				t.Errorf("unexpected creator: %+v", arg.CreatedBy)
+			}
+			storedHash = arg.KeyHash
+			return sqlcdb.ApiKey{ID: 1, Name: arg.Name, KeyPrefix: arg.KeyPrefix, KeyHash: arg.KeyHash, Scopes: arg.Scopes, CreatedBy: arg.CreatedBy}, nil
+		})
+
+	w := performRequestAs("admin-1", http.MethodPost, "/admin/api-keys", h.Create, "/admin/api-keys", `{"name": " reporting cron ", "scopes": ["read-only", "read-only"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var apiKey APIKeyResponse
+	decodeResponse(t, w, &apiKey)
+	// Only the hash of the returned key is stored
+	if !strings.HasPrefix(apiKey.Key, "spk_") || utils.HashAPIKey(apiKey.Key) != storedHash {
+		t.Fatalf("key does not match the stored hash: %+v", apiKey)
+	}
+	if !strings.HasPrefix(apiKey.Key, apiKey.KeyPrefix) || !apiKey.Active || apiKey.ExpiresAt != "" {
+		t.Fatalf("unexpected API key: %+v", apiKey)
+	}
+}
+
+func TestAPIKeyHandlerCreateRejectsUnknownScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h := NewAPIKeyHandler(mocks.NewMockAPIKeyRepository(ctrl), testLogger)
+
+	w := performRequest(http.MethodPost, "/admin/api-keys", h.Create, "/admin/api-keys", `{"name": "sync", "scopes": ["admin"]}`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "scopes") {
+		t.Fatalf("expected a scopes validation error, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAPIKeyHandlerRevoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	h := NewAPIKeyHandler(repo, testLogger)
+
+	revokedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	repo.EXPECT().GetAPIKey(gomock.Any(), int32(3)).Return(sqlcdb.ApiKey{ID: 3}, nil)
+	repo.EXPECT().RevokeAPIKey(gomock.Any(), int32(3)).Return(sqlcdb.ApiKey{ID: 3, Scopes: []string{"stock-write"}, RevokedAt: revokedAt}, nil)
+
+	w := performRequest(http.MethodDelete, "/admin/api-keys/:id", h.Revoke, "/admin/api-keys/3", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var apiKey APIKeyResponse
+	decodeResponse(t, w, &apiKey)
+	if apiKey.Active || apiKey.RevokedAt == "" || apiKey.Key != "" {
+		t.Fatalf("unexpected revoked API key: %+v", apiKey)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// dummyPasswordHash is a bcrypt hash (at bcrypt.DefaultCost, as utils.HashPassword uses) of a
+// random password nobody knows. A login for an unknown username is checked against it, so
+// it takes as long as one with a wrong password and the response time doesn't tell which
+// usernames exist.
+const dummyPasswordHash = "$2a$10$bvfy8448pKErwjj5GQtAauCGzeCOuJdghyYgw3nQiwpeElkorQcG."
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse is a new access and refresh token pair; ExpiresIn is the access token's
+// lifetime in seconds
+type TokenResponse struct {
+	AccessToken      string       `json:"access_token"`
+	RefreshToken     string       `json:"refresh_token"`
+	TokenType        string       `json:"token_type"`
+	ExpiresIn        int64        `json:"expires_in"`
+	RefreshExpiresAt string       `json:"refresh_expires_at"`
+	User             AuthUserInfo `json:"user"`
+}
+
+type AuthUserInfo struct {
+	ID       int32  `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// AuthHandler logs users in and renews their tokens; the tokens are checked by
+// middleware.Authenticate
+type AuthHandler struct {
+	logger  *zap.Logger
+	queries repository.AuthRepository
+	config  config.AuthConfig
+}
+
+func NewAuthHandler(queries repository.AuthRepository, cfg config.AuthConfig, logger *zap.Logger) *AuthHandler {
+	return &AuthHandler{
+		logger:  logger,
+		queries: queries,
+		config:  cfg,
+	}
+}
+
+// @Summary Login
+// @Description Exchange username and password for an access token and a refresh token; attempts are rate limited per client IP
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param credentials body LoginRequest true "Credentials"
+// @Success 200 {object} utils.Response
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	// Unknown users, wrong passwords and inactive users get the same answer, after the same
+	// password check
+	user, err := h.queries.GetAppUserByUsername(ctx, req.Username)
+	hash := user.PasswordHash
+	if err != nil {
+		hash = dummyPasswordHash
+	}
+	passwordMatches := utils.CheckPassword(hash, req.Password)
+	if err != nil || !user.IsActive || !passwordMatches {
+		utils.Unauthorized(c, "Invalid username or password")
+		return
+	}
+
+	response, err := h.issueTokens(user)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to issue tokens", h.logger)
+		return
+	}
+
+	if err := h.queries.RecordAppUserLogin(ctx, user.ID); err != nil {
+		utils.RequestLogger(ctx, h.logger).Warn("Failed to record login", zap.String("username", user.Username), zap.Error(err))
+	}
+
+	utils.Success(c, "Login successful", response)
+}
+
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} utils.Response
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	claims, err := utils.ParseToken(h.config.JWTSecret, req.RefreshToken, utils.RefreshToken)
+	if err != nil {
+		utils.Unauthorized(c, "Invalid or expired refresh token")
+		return
+	}
+	id, err := claims.UserID()
+	if err != nil {
+		utils.Unauthorized(c, "Invalid or expired refresh token")
+		return
+	}
+
+	// The user is read again so deactivations and role changes apply on the next refresh
+	user, err := h.queries.GetAppUser(ctx, id)
+	if err != nil || !user.IsActive {
+		utils.Unauthorized(c, "Invalid or expired refresh token")
+		return
+	}
+
+	response, err := h.issueTokens(user)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to issue tokens", h.logger)
+		return
+	}
+
+	utils.Success(c, "Token refreshed successfully", response)
+}
+
+func (h *AuthHandler) issueTokens(user sqlcdb.AppUser) (TokenResponse, error) {
+	access, _, err := utils.IssueToken(h.config.JWTSecret, user.ID, user.Username, user.Role, utils.AccessToken, h.config.AccessTokenTTL)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	refresh, refreshExpiresAt, err := utils.IssueToken(h.config.JWTSecret, user.ID, user.Username, user.Role, utils.RefreshToken, h.config.RefreshTokenTTL)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(h.config.AccessTokenTTL / time.Second),
+		RefreshExpiresAt: refreshExpiresAt.UTC().Format(time.RFC3339),
+		User: AuthUserInfo{
+			ID:       user.ID,
+			Username: user.Username,
+			Role:     user.Role,
+		},
+	}, nil
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testAuthConfig = config.AuthConfig{
+	JWTSecret:       "test-secret",
+	AccessTokenTTL:  15 * time.Minute,
+	RefreshTokenTTL: time.Hour,
+}
+
+func TestAuthHandlerLogin(t *testing.T) {
+	hash, err := utils.HashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := sqlcdb.AppUser{ID: 3, Username: "budi", PasswordHash: hash, Role: utils.RoleAdmin, IsActive: true}
+
+	t.Run("valid credentials", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockAuthRepository(ctrl)
+		h := NewAuthHandler(repo, testAuthConfig, testLogger)
+
+		repo.EXPECT().GetAppUserByUsername(gomock.Any(), "budi").Return(user, nil)
+		repo.EXPECT().RecordAppUserLogin(gomock.Any(), int32(3)).Return(nil)
+
+		w := performRequest(http.MethodPost, "/auth/login", h.Login, "/auth/login", `{"username": "budi", "password": "s3cret"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var tokens TokenResponse
+		decodeResponse(t, w, &tokens)
+		if tokens.TokenType != "Bearer" || tokens.ExpiresIn != 900 || tokens.User.Role != utils.RoleAdmin {
+			t.Fatalf("unexpected token response: %+v", tokens)
+		}
+		claims, err := utils.ParseToken(testAuthConfig.JWTSecret, tokens.AccessToken, utils.AccessToken)
+		if err != nil || claims.Username != "budi" || claims.Subject != "3" {
+			t.Fatalf("unexpected access token claims: %+v (%v)", claims, err)
+		}
+		// The refresh token cannot be used as an access token
+		if _, err := utils.ParseToken(testAuthConfig.JWTSecret, tokens.RefreshToken, utils.AccessToken); err == nil {
+			t.Fatal("expected the refresh token to be rejected as access token")
+		}
+	})
+
+	tests := []struct {
+		name     string
+		user     sqlcdb.AppUser
+		err      error
+		password string
+	}{
+		{"unknown user", sqlcdb.AppUser{}, errors.New("no rows in result set"), "s3cret"},
+		{"wrong password", user, nil, "wrong"},
+		{"inactive user", sqlcdb.AppUser{ID: 3, Username: "budi", PasswordHash: hash, Role: utils.RoleUser}, nil, "s3cret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockAuthRepository(ctrl)
+			h := NewAuthHandler(repo, testAuthConfig, testLogger)
+
+			repo.EXPECT().GetAppUserByUsername(gomock.Any(), "budi").Return(tt.user, tt.err)
+
+			w := performRequest(http.MethodPost, "/auth/login", h.Login, "/auth/login", `{"username": "budi", "password": "`+tt.password+`"}`)
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("expected status 401, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// An unknown username must cost a full bcrypt comparison, as a wrong password does
+func TestDummyPasswordHash(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	if err != nil || cost != bcrypt.DefaultCost {
+		t.Fatalf("expected a bcrypt hash at cost %d, got %d (%v)", bcrypt.DefaultCost, cost, err)
+	}
+	if utils.CheckPassword(dummyPasswordHash, "") {
+		t.Fatal("expected the dummy hash to match no password")
+	}
+}
+
+func TestAuthHandlerRefresh(t *testing.T) {
+	refresh, _, err := utils.IssueToken(testAuthConfig.JWTSecret, 3, "budi", utils.RoleUser, utils.RefreshToken, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	access, _, err := utils.IssueToken(testAuthConfig.JWTSecret, 3, "budi", utils.RoleUser, utils.AccessToken, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid refresh token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockAuthRepository(ctrl)
+		h := NewAuthHandler(repo, testAuthConfig, testLogger)
+
+		// The role is read again from the user, not taken from the old token
+		repo.EXPECT().GetAppUser(gomock.Any(), int32(3)).
+			Return(sqlcdb.AppUser{ID: 3, Username: "budi", Role: utils.RoleAdmin, IsActive: true}, nil)
+
+		w := performRequest(http.MethodPost, "/auth/refresh", h.Refresh, "/auth/refresh", `{"refresh_token": "`+refresh+`"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var tokens TokenResponse
+		decodeResponse(t, w, &tokens)
+		claims, err := utils.ParseToken(testAuthConfig.JWTSecret, tokens.AccessToken, utils.AccessToken)
+		if err != nil || claims.Role != utils.RoleAdmin {
+			t.Fatalf("unexpected access token claims: %+v (%v)", claims, err)
+		}
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockAuthRepository(ctrl)
+		h := NewAuthHandler(repo, testAuthConfig, testLogger)
+
+		repo.EXPECT().GetAppUser(gomock.Any(), int32(3)).Return(sqlcdb.AppUser{ID: 3, Username: "budi"}, nil)
+
+		w := performRequest(http.MethodPost, "/auth/refresh", h.Refresh, "/auth/refresh", `{"refresh_token": "`+refresh+`"}`)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status 401, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	for name, token := range map[string]string{"access token": access, "garbage": "not-a-token"} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			h := NewAuthHandler(mocks.NewMockAuthRepository(ctrl), testAuthConfig, testLogger)
+
+			w := performRequest(http.MethodPost, "/auth/refresh", h.Refresh, "/auth/refresh", `{"refresh_token": "`+token+`"}`)
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("expected status 401, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Tables with change history triggers (see migration 000006)
+const (
+	historyTableLocation   = "location"
+	historyTableStock      = "sparepart_stock_item"
+	historyTableToolsAlker = "tools_alker_item"
+)
+
+// FieldChange is the value of a column before and after a change; Old is null on
+// inserts and New is null on deletes
+type FieldChange struct {
+	Old json.RawMessage `json:"old"`
+	New json.RawMessage `json:"new"`
+}
+
+// RecordChange is one insert, update or delete of a record
+type RecordChange struct {
+	ID        int64                  `json:"id"`
+	Operation string                 `json:"operation"`
+	Changes   map[string]FieldChange `json:"changes"`
+	Actor     *string                `json:"actor"`
+	ChangedAt string                 `json:"changed_at"`
+}
+
+// Stock timeline events (see migration 000030)
+const (
+	stockEventCreated         = "CREATED"
+	stockEventQuantityChanged = "QUANTITY_CHANGED"
+	stockEventPhotoAdded      = "PHOTO_ADDED"
+	stockEventPhotoRemoved    = "PHOTO_REMOVED"
+	stockEventNotesEdited     = "NOTES_EDITED"
+	stockEventDeleted         = "DELETED"
+	stockEventRestored        = "RESTORED"
+)
+
+// StockHistoryEvent is one entry of a stock item's timeline. Old and new hold the quantity
+// for quantity changes (new only on creation, old on a hard delete), the photo path for photo
+// events and the notes for notes edits.
+type StockHistoryEvent struct {
+	ChangeID  int64           `json:"change_id"`
+	Event     string          `json:"event"`
+	Old       json.RawMessage `json:"old,omitempty"`
+	New       json.RawMessage `json:"new,omitempty"`
+	Actor     *string         `json:"actor"`
+	ChangedAt string          `json:"changed_at"`
+}
+
+// ChangeHistoryHandler serves the field-level change history of stock items, tools
+// alker items and locations. History outlives the record, so deleted records still
+// return their changes.
+type ChangeHistoryHandler struct {
+	logger  *zap.Logger
+	queries repository.ChangeHistoryRepository
+}
+
+func NewChangeHistoryHandler(queries repository.ChangeHistoryRepository, logger *zap.Logger) *ChangeHistoryHandler {
+	return &ChangeHistoryHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get sparepart stock change history
+// @Description Get the changes of a sparepart stock item, newest first, with old/new values per field
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /stock/{id}/changes [get]
+func (h *ChangeHistoryHandler) GetStockChanges(c *gin.Context) {
+	h.listChanges(c, historyTableStock, "Invalid sparepart stock ID")
+}
+
+// @Summary Get tools alker change history
+// @Description Get the changes of a tools alker item, newest first, with old/new values per field
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param id path int true "Tools Alker ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /tools-alker/{id}/changes [get]
+func (h *ChangeHistoryHandler) GetToolsAlkerChanges(c *gin.Context) {
+	h.listChanges(c, historyTableToolsAlker, "Invalid tools alker ID")
+}
+
+// @Summary Get location change history
+// @Description Get the changes of a location, newest first, with old/new values per field
+// @Tags Location
+// @Accept json
+// @Produce json
+// @Param id path int true "Location ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /location/{id}/changes [get]
+func (h *ChangeHistoryHandler) GetLocationChanges(c *gin.Context) {
+	h.listChanges(c, historyTableLocation, "Invalid location ID")
+}
+
+// @Summary Get sparepart stock history timeline
+// @Description Get the timeline of a sparepart stock item, oldest first: its creation, quantity changes, added and removed photos, notes edits, deletes and restores, read from the change history
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock ID"
+// @Param event query string false "Filter by event (CREATED, QUANTITY_CHANGED, PHOTO_ADDED, PHOTO_REMOVED, NOTES_EDITED, DELETED, RESTORED)"
+// @Param from query string false "Events on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Events on or before this date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse{data=[]StockHistoryEvent}
+// @Router /stock/{id}/history [get]
+func (h *ChangeHistoryHandler) GetStockHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock ID")
+		return
+	}
+
+	filters := sqlcdb.CountStockHistoryParams{
+		StockItemID: int32(id),
+		Event:       utils.TextFilter(strings.ToUpper(c.Query("event"))),
+	}
+	var errs []utils.FieldError
+	switch filters.Event.String {
+	case "", stockEventCreated, stockEventQuantityChanged, stockEventPhotoAdded, stockEventPhotoRemoved,
+		stockEventNotesEdited, stockEventDeleted, stockEventRestored:
+	default:
+		errs = append(errs, utils.FieldError{Field: "event", Message: "must be one of CREATED, QUANTITY_CHANGED, PHOTO_ADDED, PHOTO_REMOVED, NOTES_EDITED, DELETED, RESTORED"})
+	}
+	if value := c.Query("from"); value != "" {
+		from, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "from", Message: "must be a date (YYYY-MM-DD)"})
+		} else {
+			filters.Since = pgtype.Timestamptz{Time: from, Valid: true}
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "to", Message: "must be a date (YYYY-MM-DD)"})
+		} else {
+			filters.Until = pgtype.Timestamptz{Time: to.AddDate(0, 0, 1), Valid: true}
+		}
+	}
+	if len(errs) == 0 && filters.Since.Valid && filters.Until.Valid && !filters.Since.Time.Before(filters.Until.Time) {
+		errs = append(errs, utils.FieldError{Field: "from", Message: "must not be after to"})
+	}
+	pagination, pageErrs := utils.ParsePagination(c)
+	errs = append(errs, pageErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountStockHistory(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count stock history", h.logger)
+		return
+	}
+
+	rows, err := h.queries.ListStockHistory(ctx, sqlcdb.ListStockHistoryParams{
+		StockItemID: filters.StockItemID,
+		Event:       filters.Event,
+		Since:       filters.Since,
+		Until:       filters.Until,
+		Limit:       int32(pagination.Limit),
+		Offset:      int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock history", h.logger)
+		return
+	}
+
+	events := make([]StockHistoryEvent, 0, len(rows))
+	for _, row := range rows {
+		event := StockHistoryEvent{
+			ChangeID:  row.ChangeID,
+			Event:     row.Event,
+			Old:       row.OldValue,
+			New:       row.NewValue,
+			ChangedAt: utils.FormatTimestamp(row.ChangedAt),
+		}
+		if row.Actor.Valid {
+			event.Actor = &row.Actor.String
+		}
+		events = append(events, event)
+	}
+
+	utils.SuccessWithPagination(c, "Stock history retrieved successfully", events, pagination.Page, pagination.Limit, total)
+}
+
+func (h *ChangeHistoryHandler) listChanges(c *gin.Context, table, invalidIDMessage string) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, invalidIDMessage)
+		return
+	}
+
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountRecordChanges(ctx, sqlcdb.CountRecordChangesParams{
+		TableName: table,
+		RecordID:  int32(id),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count changes", h.logger)
+		return
+	}
+
+	rows, err := h.queries.ListRecordChanges(ctx, sqlcdb.ListRecordChangesParams{
+		TableName: table,
+		RecordID:  int32(id),
+		Limit:     int32(pagination.Limit),
+		Offset:    int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get changes", h.logger)
+		return
+	}
+
+	changes := make([]RecordChange, 0, len(rows))
+	for _, row := range rows {
+		change := RecordChange{
+			ID:        row.ID,
+			Operation: row.Operation,
+			ChangedAt: utils.FormatTimestamp(row.ChangedAt),
+		}
+		if err := json.Unmarshal(row.Changes, &change.Changes); err != nil {
+			utils.HandleError(c, err, "Failed to decode changes", h.logger)
+			return
+		}
+		if row.Actor.Valid {
+			change.Actor = &row.Actor.String
+		}
+		changes = append(changes, change)
+	}
+
+	utils.SuccessWithPagination(c, "Changes retrieved successfully", changes, pagination.Page, pagination.Limit, total)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangeHistoryHandlerGetStockChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockChangeHistoryRepository(ctrl)
+	h := NewChangeHistoryHandler(repo, testLogger)
+
+	changedAt := pgtype.Timestamptz{Time: time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC), Valid: true}
+	repo.EXPECT().
+		CountRecordChanges(gomock.Any(), sqlcdb.CountRecordChangesParams{TableName: "sparepart_stock_item", RecordID: 5}).
+		Return(int64(2), nil)
+	repo.EXPECT().
+		ListRecordChanges(gomock.Any(), sqlcdb.ListRecordChangesParams{TableName: "sparepart_stock_item", RecordID: 5, Limit: 10, Offset: 0}).
+		Return([]sqlcdb.ListRecordChangesRow{
+			{ID: 9, Operation: "UPDATE", Changes: []byte(`{"quantity": {"old": 4, "new": 0}}`), Actor: pgtype.Text{String: "budi", Valid: true}, ChangedAt: changedAt},
+			{ID: 3, Operation: "INSERT", Changes: []byte(`{"quantity": {"old": null, "new": 4}}`), ChangedAt: changedAt},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/:id/changes", h.GetStockChanges, "/stock/5/changes", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var changes []RecordChange
+	resp := decodeResponse(t, w, &changes)
+	if resp.Pagination.Total != 2 || len(changes) != 2 {
+		t.Fatalf("unexpected changes: %+v (total %d)", changes, resp.Pagination.Total)
+	}
+	quantity := changes[0].Changes["quantity"]
+	if string(quantity.Old) != "4" || string(quantity.New) != "0" {
+		t.Fatalf("unexpected quantity change: old=%s new=%s", quantity.Old, quantity.New)
+	}
+	if changes[0].Actor == nil || *changes[0].Actor != "budi" || changes[1].Actor != nil {
+		t.Fatalf("unexpected actors: %v, %v", changes[0].Actor, changes[1].Actor)
+	}
+}
+
+func TestChangeHistoryHandlerInvalidID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockChangeHistoryRepository(ctrl)
+	h := NewChangeHistoryHandler(repo, testLogger)
+
+	w := performRequest(http.MethodGet, "/location/:id/changes", h.GetLocationChanges, "/location/abc/changes", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestChangeHistoryHandlerGetStockHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockChangeHistoryRepository(ctrl)
+	h := NewChangeHistoryHandler(repo, testLogger)
+
+	since := pgtype.Timestamptz{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	until := pgtype.Timestamptz{Time: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	changedAt := pgtype.Timestamptz{Time: time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC), Valid: true}
+	repo.EXPECT().
+		CountStockHistory(gomock.Any(), sqlcdb.CountStockHistoryParams{StockItemID: 5, Since: since, Until: until}).
+		Return(int64(3), nil)
+	repo.EXPECT().
+		ListStockHistory(gomock.Any(), sqlcdb.ListStockHistoryParams{StockItemID: 5, Since: since, Until: until, Limit: 10}).
+		Return([]sqlcdb.ListStockHistoryRow{
+			{ChangeID: 3, Event: stockEventCreated, NewValue: []byte(`4`), ChangedAt: changedAt},
+			{ChangeID: 9, Event: stockEventQuantityChanged, OldValue: []byte(`4`), NewValue: []byte(`0`), Actor: pgtype.Text{String: "budi", Valid: true}, ChangedAt: changedAt},
+			{ChangeID: 9, Event: stockEventPhotoRemoved, OldValue: []byte(`"uploads/bms.jpg"`), ChangedAt: changedAt},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/:id/history", h.GetStockHistory, "/stock/5/history?from=2026-03-01&to=2026-03-31", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var events []StockHistoryEvent
+	resp := decodeResponse(t, w, &events)
+	if resp.Pagination.Total != 3 || len(events) != 3 {
+		t.Fatalf("unexpected events: %+v (total %d)", events, resp.Pagination.Total)
+	}
+	if events[1].Event != stockEventQuantityChanged || string(events[1].Old) != "4" || string(events[1].New) != "0" || *events[1].Actor != "budi" {
+		t.Fatalf("unexpected quantity event: %+v", events[1])
+	}
+	if events[0].Old != nil || string(events[2].Old) != `"uploads/bms.jpg"` || events[2].New != nil {
+		t.Fatalf("unexpected event values: %+v", events)
+	}
+}
+
+func TestChangeHistoryHandlerGetStockHistoryValidatesFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockChangeHistoryRepository(ctrl)
+	h := NewChangeHistoryHandler(repo, testLogger)
+
+	w := performRequest(http.MethodGet, "/stock/:id/history", h.GetStockHistory, "/stock/5/history?event=moved&from=2026-13-01", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 2 || resp.Errors[0].Field != "event" || resp.Errors[1].Field != "from" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
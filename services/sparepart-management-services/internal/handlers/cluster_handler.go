@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ClusterRequest holds a cluster's regency and name
+type ClusterRequest struct {
+	RegencyID int32  `json:"regency_id" binding:"required,min=1"`
+	Name      string `json:"name" binding:"required,max=100"`
+}
+
+// regencyNotFoundError is the field error for a cluster written against a missing regency
+var regencyNotFoundError = utils.FieldError{Field: "regency_id", Message: "does not exist"}
+
+type ClusterHandler struct {
+	logger  *zap.Logger
+	queries repository.ClusterRepository
+}
+
+func NewClusterHandler(queries repository.ClusterRepository, logger *zap.Logger) *ClusterHandler {
+	return &ClusterHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get all clusters
+// @Description Get the cluster reference list ordered by region, regency and name
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param region query string false "Filter by region"
+// @Param regency_id query int false "Filter by regency ID"
+// @Param name query string false "Filter by name (partial match)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /cluster [get]
+func (h *ClusterHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	region := utils.TextFilter(c.Query("region"))
+	name := utils.TextFilter(c.Query("name"))
+
+	var errs []utils.FieldError
+	var regencyID int64
+	if value := c.Query("regency_id"); value != "" {
+		var err error
+		regencyID, err = strconv.ParseInt(value, 10, 32)
+		if err != nil || regencyID < 1 {
+			errs = append(errs, utils.FieldError{Field: "regency_id", Message: "must be a positive integer"})
+		}
+	}
+	pagination, pageErrs := utils.ParsePagination(c)
+	errs = append(errs, pageErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountClusters(ctx, sqlcdb.CountClustersParams{
+		Region:    region,
+		RegencyID: utils.IntFilter(int32(regencyID)),
+		Name:      name,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count clusters", h.logger)
+		return
+	}
+
+	clusters, err := h.queries.ListClusters(ctx, sqlcdb.ListClustersParams{
+		Region:    region,
+		RegencyID: utils.IntFilter(int32(regencyID)),
+		Name:      name,
+		Limit:     int32(pagination.Limit),
+		Offset:    int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get clusters", h.logger)
+		return
+	}
+
+	utils.SuccessWithPagination(c, "Clusters retrieved successfully", clusters, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get cluster by ID
+// @Description Get a single cluster by ID
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param id path int true "Cluster ID"
+// @Success 200 {object} utils.Response
+// @Router /cluster/{id} [get]
+func (h *ClusterHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid cluster ID")
+		return
+	}
+
+	cluster, err := h.queries.GetCluster(c.Request.Context(), int32(id))
+	if err != nil {
+		utils.NotFound(c, "Cluster not found")
+		return
+	}
+
+	utils.Success(c, "Cluster retrieved successfully", cluster)
+}
+
+// @Summary Create cluster
+// @Description Create a cluster in a regency; its name is stored with whitespace collapsed
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param cluster body ClusterRequest true "Cluster data"
+// @Success 201 {object} utils.Response
+// @Router /cluster [post]
+func (h *ClusterHandler) Create(c *gin.Context) {
+	var req ClusterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := normalizeReferenceName(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.BlankNameError)
+		return
+	}
+
+	cluster, err := h.queries.CreateCluster(c.Request.Context(), sqlcdb.CreateClusterParams{
+		Name:      name,
+		RegencyID: req.RegencyID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ValidationError(c, regencyNotFoundError)
+			return
+		}
+		utils.HandleError(c, err, "Failed to create cluster", h.logger)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Cluster created successfully",
+		Data:    cluster,
+	})
+}
+
+// @Summary Update cluster
+// @Description Rename a cluster or move it to another regency; its locations follow
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param id path int true "Cluster ID"
+// @Param cluster body ClusterRequest true "Cluster data"
+// @Success 200 {object} utils.Response
+// @Router /cluster/{id} [put]
+func (h *ClusterHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid cluster ID")
+		return
+	}
+
+	// Check if cluster exists
+	_, err = h.queries.GetCluster(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Cluster not found")
+		return
+	}
+
+	var req ClusterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := normalizeReferenceName(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.BlankNameError)
+		return
+	}
+
+	cluster, err := h.queries.UpdateCluster(ctx, sqlcdb.UpdateClusterParams{
+		Name:      name,
+		ID:        int32(id),
+		RegencyID: req.RegencyID,
+	})
+	if err != nil {
+		// The cluster exists, so no row means the regency does not
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ValidationError(c, regencyNotFoundError)
+			return
+		}
+		utils.HandleError(c, err, "Failed to update cluster", h.logger)
+		return
+	}
+
+	utils.Success(c, "Cluster updated successfully", cluster)
+}
+
+// @Summary Delete cluster
+// @Description Delete a cluster; refused while locations are still in it
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param id path int true "Cluster ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /cluster/{id} [delete]
+func (h *ClusterHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid cluster ID")
+		return
+	}
+
+	// Check if cluster exists
+	_, err = h.queries.GetCluster(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Cluster not found")
+		return
+	}
+
+	err = h.queries.DeleteCluster(ctx, int32(id))
+	if err != nil {
+		if utils.IsForeignKeyViolation(err) {
+			c.JSON(http.StatusConflict, utils.Response{
+				Error: "Cluster still has locations",
+				Code:  utils.ErrCodeInUse,
+			})
+			return
+		}
+		utils.HandleError(c, err, "Failed to delete cluster", h.logger)
+		return
+	}
+
+	utils.Success(c, "Cluster deleted successfully", nil)
+}
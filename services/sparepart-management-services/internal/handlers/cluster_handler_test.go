@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClusterHandlerGetAllByRegency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockClusterRepository(ctrl)
+	h := NewClusterHandler(repo, testLogger)
+
+	regencyID := pgtype.Int4{Int32: 4, Valid: true}
+	repo.EXPECT().CountClusters(gomock.Any(), sqlcdb.CountClustersParams{RegencyID: regencyID}).Return(int64(1), nil)
+	repo.EXPECT().
+		ListClusters(gomock.Any(), sqlcdb.ListClustersParams{RegencyID: regencyID, Limit: 10}).
+		Return([]sqlcdb.ListClustersRow{{ID: 9, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Name: "Sentani", RegencyID: 4}}, nil)
+
+	w := performRequest(http.MethodGet, "/cluster", h.GetAll, "/cluster?regency_id=4", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var clusters []sqlcdb.ListClustersRow
+	decodeResponse(t, w, &clusters)
+	if len(clusters) != 1 || clusters[0].RegencyID != 4 {
+		t.Fatalf("unexpected clusters: %+v", clusters)
+	}
+}
+
+func TestClusterHandlerGetAllRejectsInvalidRegencyID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockClusterRepository(ctrl)
+	h := NewClusterHandler(repo, testLogger)
+
+	w := performRequest(http.MethodGet, "/cluster", h.GetAll, "/cluster?regency_id=abc", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestClusterHandlerCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(repo *mocks.MockClusterRepository)
+		wantStatus int
+		wantField  string
+	}{
+		{
+			name: "created",
+			body: `{"regency_id":4,"name":" Sentani "}`,
+			setup: func(repo *mocks.MockClusterRepository) {
+				repo.EXPECT().
+					CreateCluster(gomock.Any(), sqlcdb.CreateClusterParams{Name: "Sentani", RegencyID: 4}).
+					Return(sqlcdb.CreateClusterRow{ID: 9, Name: "Sentani", RegencyID: 4}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "unknown regency",
+			body: `{"regency_id":40,"name":"Sentani"}`,
+			setup: func(repo *mocks.MockClusterRepository) {
+				repo.EXPECT().
+					CreateCluster(gomock.Any(), sqlcdb.CreateClusterParams{Name: "Sentani", RegencyID: 40}).
+					Return(sqlcdb.CreateClusterRow{}, pgx.ErrNoRows)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantField:  "regency_id",
+		},
+		{
+			name: "duplicate",
+			body: `{"regency_id":4,"name":"Sentani"}`,
+			setup: func(repo *mocks.MockClusterRepository) {
+				repo.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(sqlcdb.CreateClusterRow{}, &pgconn.PgError{
+					Code:           "23505",
+					ConstraintName: "unique_cluster",
+				})
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockClusterRepository(ctrl)
+			tt.setup(repo)
+			h := NewClusterHandler(repo, testLogger)
+
+			w := performRequest(http.MethodPost, "/cluster", h.Create, "/cluster", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			resp := decodeResponse(t, w, nil)
+			if tt.wantField != "" && (len(resp.Errors) != 1 || resp.Errors[0].Field != tt.wantField) {
+				t.Fatalf("expected %s field error, got %+v", tt.wantField, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestClusterHandlerUpdateMovesToRegency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockClusterRepository(ctrl)
+	h := NewClusterHandler(repo, testLogger)
+
+	params := sqlcdb.UpdateClusterParams{Name: "Sentani", ID: 9, RegencyID: 5}
+	repo.EXPECT().GetCluster(gomock.Any(), int32(9)).Return(sqlcdb.GetClusterRow{ID: 9, RegencyID: 4}, nil)
+	repo.EXPECT().UpdateCluster(gomock.Any(), params).Return(sqlcdb.UpdateClusterRow{ID: 9, Name: "Sentani", RegencyID: 5}, nil)
+
+	w := performRequest(http.MethodPut, "/cluster/:id", h.Update, "/cluster/9", `{"regency_id":5,"name":"Sentani"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var cluster sqlcdb.UpdateClusterRow
+	decodeResponse(t, w, &cluster)
+	if cluster.RegencyID != 5 {
+		t.Fatalf("unexpected cluster: %+v", cluster)
+	}
+}
+
+func TestClusterHandlerDeleteInUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockClusterRepository(ctrl)
+	h := NewClusterHandler(repo, testLogger)
+
+	repo.EXPECT().GetCluster(gomock.Any(), int32(9)).Return(sqlcdb.GetClusterRow{ID: 9}, nil)
+	repo.EXPECT().DeleteCluster(gomock.Any(), int32(9)).Return(&pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "location_cluster_fkey",
+	})
+
+	w := performRequest(http.MethodDelete, "/cluster/:id", h.Delete, "/cluster/9", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.ErrCodeInUse {
+		t.Fatalf("expected %s code, got %+v", utils.ErrCodeInUse, resp)
+	}
+}
@@ -1,14 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
-	"sparepart-management-services/internal/database"
+	"net/mail"
 	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
 	"sparepart-management-services/internal/utils"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
@@ -18,6 +22,7 @@ type ContactPersonResponse struct {
 	Location  ContactPersonLocation  `json:"location"`
 	Pic       string                 `json:"pic"`
 	Phone     string                 `json:"phone"`
+	Email     *string                `json:"email"`
 	CreatedAt string                `json:"created_at"`
 	UpdatedAt string                `json:"updated_at"`
 }
@@ -33,22 +38,10 @@ type ContactPersonLocation struct {
 
 // transformContactPerson transforms sqlc flat structure to nested response
 func transformContactPerson(row sqlcdb.ListContactPersonsRow) ContactPersonResponse {
-	createdAt := ""
-	if row.CreatedAt.Valid {
-		createdAt = row.CreatedAt.Time.Format(time.RFC3339)
-	}
-	updatedAt := ""
-	if row.UpdatedAt.Valid {
-		updatedAt = row.UpdatedAt.Time.Format(time.RFC3339)
-	}
-	locationCreatedAt := ""
-	if row.LocationCreatedAt.Valid {
-		locationCreatedAt = row.LocationCreatedAt.Time.Format(time.RFC3339)
-	}
-	locationUpdatedAt := ""
-	if row.LocationUpdatedAt.Valid {
-		locationUpdatedAt = row.LocationUpdatedAt.Time.Format(time.RFC3339)
-	}
+	createdAt := utils.FormatTimestamp(row.CreatedAt)
+	updatedAt := utils.FormatTimestamp(row.UpdatedAt)
+	locationCreatedAt := utils.FormatTimestamp(row.LocationCreatedAt)
+	locationUpdatedAt := utils.FormatTimestamp(row.LocationUpdatedAt)
 
 	return ContactPersonResponse{
 		ID: row.ID,
@@ -62,6 +55,7 @@ func transformContactPerson(row sqlcdb.ListContactPersonsRow) ContactPersonRespo
 		},
 		Pic:       row.Pic,
 		Phone:     row.Phone,
+		Email:     contactEmail(row.Email),
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
 	}
@@ -69,22 +63,10 @@ func transformContactPerson(row sqlcdb.ListContactPersonsRow) ContactPersonRespo
 
 // transformContactPersonFromGet transforms GetContactPersonRow to nested response
 func transformContactPersonFromGet(row sqlcdb.GetContactPersonRow) ContactPersonResponse {
-	createdAt := ""
-	if row.CreatedAt.Valid {
-		createdAt = row.CreatedAt.Time.Format(time.RFC3339)
-	}
-	updatedAt := ""
-	if row.UpdatedAt.Valid {
-		updatedAt = row.UpdatedAt.Time.Format(time.RFC3339)
-	}
-	locationCreatedAt := ""
-	if row.LocationCreatedAt.Valid {
-		locationCreatedAt = row.LocationCreatedAt.Time.Format(time.RFC3339)
-	}
-	locationUpdatedAt := ""
-	if row.LocationUpdatedAt.Valid {
-		locationUpdatedAt = row.LocationUpdatedAt.Time.Format(time.RFC3339)
-	}
+	createdAt := utils.FormatTimestamp(row.CreatedAt)
+	updatedAt := utils.FormatTimestamp(row.UpdatedAt)
+	locationCreatedAt := utils.FormatTimestamp(row.LocationCreatedAt)
+	locationUpdatedAt := utils.FormatTimestamp(row.LocationUpdatedAt)
 
 	return ContactPersonResponse{
 		ID: row.ID,
@@ -98,20 +80,68 @@ func transformContactPersonFromGet(row sqlcdb.GetContactPersonRow) ContactPerson
 		},
 		Pic:       row.Pic,
 		Phone:     row.Phone,
+		Email:     contactEmail(row.Email),
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
 	}
 }
 
+// includeContactPerson is the include query value that adds the contact persons of each
+// location to the grouped stock and tools alker responses
+const includeContactPerson = "contact_person"
+
+// LocationContactPerson is a contact person listed under the location it belongs to
+type LocationContactPerson struct {
+	ID    int32   `json:"id"`
+	Pic   string  `json:"pic"`
+	Phone string  `json:"phone"`
+	Email *string `json:"email"`
+}
+
+// groupContactPersonsByLocation maps each location ID to its contact persons, keeping their order
+func groupContactPersonsByLocation(rows []sqlcdb.ContactPerson) map[int32][]LocationContactPerson {
+	contacts := make(map[int32][]LocationContactPerson)
+	for _, row := range rows {
+		contacts[row.LocationID] = append(contacts[row.LocationID], LocationContactPerson{
+			ID:    row.ID,
+			Pic:   row.Pic,
+			Phone: row.Phone,
+			Email: contactEmail(row.Email),
+		})
+	}
+	return contacts
+}
+
+func contactEmail(email pgtype.Text) *string {
+	if !email.Valid {
+		return nil
+	}
+	return &email.String
+}
+
+// normalizeContactEmail trims the email address of a contact person, treating a blank one as
+// absent, and reports whether it is valid
+func normalizeContactEmail(email pgtype.Text) (pgtype.Text, bool) {
+	email.String = strings.TrimSpace(email.String)
+	if !email.Valid || email.String == "" {
+		return pgtype.Text{}, true
+	}
+	address, err := mail.ParseAddress(email.String)
+	if err != nil || address.Address != email.String || len(email.String) > 255 {
+		return email, false
+	}
+	return email, true
+}
+
 type ContactPersonHandler struct {
 	logger  *zap.Logger
-	queries *sqlcdb.Queries
+	queries repository.ContactPersonRepository
 }
 
-func NewContactPersonHandler() *ContactPersonHandler {
+func NewContactPersonHandler(queries repository.ContactPersonRepository, logger *zap.Logger) *ContactPersonHandler {
 	return &ContactPersonHandler{
-		logger:  utils.GetLogger(),
-		queries: sqlcdb.New(database.GetDB()),
+		logger:  logger,
+		queries: queries,
 	}
 }
 
@@ -122,7 +152,7 @@ func NewContactPersonHandler() *ContactPersonHandler {
 // @Produce json
 // @Param location_id query int false "Filter by location ID"
 // @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
+// @Param limit query int false "Items per page (max 100)" default(10)
 // @Success 200 {object} utils.PaginatedResponse
 // @Router /contact-person [get]
 func (h *ContactPersonHandler) GetAll(c *gin.Context) {
@@ -137,12 +167,14 @@ func (h *ContactPersonHandler) GetAll(c *gin.Context) {
 	}
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset := (page - 1) * limit
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
 
 	// Count total
-	total, err := h.queries.CountContactPersons(ctx, locationID)
+	total, err := h.queries.CountContactPersons(ctx, utils.IntFilter(locationID))
 	if err != nil {
 		utils.HandleError(c, err, "Failed to count contact persons", h.logger)
 		return
@@ -150,9 +182,9 @@ func (h *ContactPersonHandler) GetAll(c *gin.Context) {
 
 	// List contact persons
 	listParams := sqlcdb.ListContactPersonsParams{
-		Column1: locationID,
-		Limit:   int32(limit),
-		Offset:  int32(offset),
+		LocationID: utils.IntFilter(locationID),
+		Limit:      int32(pagination.Limit),
+		Offset:     int32(pagination.Offset()),
 	}
 	contacts, err := h.queries.ListContactPersons(ctx, listParams)
 	if err != nil {
@@ -166,7 +198,7 @@ func (h *ContactPersonHandler) GetAll(c *gin.Context) {
 		responseData[i] = transformContactPerson(contact)
 	}
 
-	utils.SuccessWithPagination(c, "Contact persons retrieved successfully", responseData, page, limit, total)
+	utils.SuccessWithPagination(c, "Contact persons retrieved successfully", responseData, pagination.Page, pagination.Limit, total)
 }
 
 // @Summary Get contact person by ID
@@ -210,7 +242,12 @@ func (h *ContactPersonHandler) Create(c *gin.Context) {
 
 	var req sqlcdb.CreateContactPersonParams
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+		utils.BindingError(c, err)
+		return
+	}
+	var ok bool
+	if req.Email, ok = normalizeContactEmail(req.Email); !ok {
+		utils.ValidationError(c, utils.FieldError{Field: "email", Message: "must be a valid email address"})
 		return
 	}
 
@@ -254,7 +291,12 @@ func (h *ContactPersonHandler) Update(c *gin.Context) {
 
 	var req sqlcdb.UpdateContactPersonParams
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+		utils.BindingError(c, err)
+		return
+	}
+	var ok bool
+	if req.Email, ok = normalizeContactEmail(req.Email); !ok {
+		utils.ValidationError(c, utils.FieldError{Field: "email", Message: "must be a valid email address"})
 		return
 	}
 
@@ -268,8 +310,66 @@ func (h *ContactPersonHandler) Update(c *gin.Context) {
 	utils.Success(c, "Contact person updated successfully", contact)
 }
 
+// PatchContactPersonRequest holds the contact person fields to change; omitted fields are unchanged
+type PatchContactPersonRequest struct {
+	LocationID *int    `json:"location_id,omitempty" binding:"omitempty,min=1"`
+	Pic        *string `json:"pic,omitempty" binding:"omitempty,min=1"`
+	Phone      *string `json:"phone,omitempty" binding:"omitempty,min=1"`
+	Email      *string `json:"email,omitempty" binding:"omitempty,email,max=255"`
+}
+
+// @Summary Partially update contact person
+// @Description Update only the contact person fields present in the request body
+// @Tags Contact Person
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact Person ID"
+// @Param contact body PatchContactPersonRequest true "Fields to update (omitted fields are unchanged)"
+// @Success 200 {object} utils.Response
+// @Router /contact-person/{id} [patch]
+func (h *ContactPersonHandler) Patch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid contact person ID")
+		return
+	}
+
+	// Check if contact person exists
+	_, err = h.queries.GetContactPerson(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Contact person not found")
+		return
+	}
+
+	var req PatchContactPersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	if req.LocationID == nil && req.Pic == nil && req.Phone == nil && req.Email == nil {
+		utils.BadRequest(c, "No fields to update")
+		return
+	}
+
+	contact, err := h.queries.PatchContactPerson(ctx, sqlcdb.PatchContactPersonParams{
+		ID:         int32(id),
+		LocationID: utils.OptionalInt(req.LocationID),
+		Pic:        utils.OptionalText(req.Pic),
+		Phone:      utils.OptionalText(req.Phone),
+		Email:      utils.OptionalText(req.Email),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update contact person", h.logger)
+		return
+	}
+
+	utils.Success(c, "Contact person updated successfully", contact)
+}
+
 // @Summary Delete contact person
-// @Description Delete a contact person
+// @Description Soft delete a contact person; it is kept until restored or purged and gets no messages meanwhile
 // @Tags Contact Person
 // @Accept json
 // @Produce json
@@ -285,6 +385,13 @@ func (h *ContactPersonHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	// Check if contact person exists
+	_, err = h.queries.GetContactPerson(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Contact person not found")
+		return
+	}
+
 	err = h.queries.DeleteContactPerson(ctx, int32(id))
 	if err != nil {
 		utils.HandleError(c, err, "Failed to delete contact person", h.logger)
@@ -293,3 +400,33 @@ func (h *ContactPersonHandler) Delete(c *gin.Context) {
 
 	utils.Success(c, "Contact person deleted successfully", nil)
 }
+
+// @Summary Restore contact person
+// @Description Restore a soft deleted contact person; not while its location is deleted
+// @Tags Contact Person
+// @Accept json
+// @Produce json
+// @Param id path int true "Contact Person ID"
+// @Success 200 {object} utils.Response
+// @Router /contact-person/{id}/restore [post]
+func (h *ContactPersonHandler) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid contact person ID")
+		return
+	}
+
+	contact, err := h.queries.RestoreContactPerson(ctx, int32(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.NotFound(c, "Deleted contact person not found")
+			return
+		}
+		utils.HandleError(c, err, "Failed to restore contact person", h.logger)
+		return
+	}
+
+	utils.Success(c, "Contact person restored successfully", contact)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestContactPersonHandlerGetAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		locationID pgtype.Int4
+	}{
+		{name: "all locations", target: "/contact-person"},
+		{name: "by location", target: "/contact-person?location_id=4", locationID: pgtype.Int4{Int32: 4, Valid: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockContactPersonRepository(ctrl)
+			h := NewContactPersonHandler(repo, testLogger)
+
+			repo.EXPECT().CountContactPersons(gomock.Any(), tt.locationID).Return(int64(1), nil)
+			repo.EXPECT().
+				ListContactPersons(gomock.Any(), sqlcdb.ListContactPersonsParams{LocationID: tt.locationID, Limit: 10, Offset: 0}).
+				Return([]sqlcdb.ListContactPersonsRow{
+					{ID: 1, LocationID: 4, LocationID2: 4, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo", Pic: "Hendra", Phone: "0812-1801-2082"},
+				}, nil)
+
+			w := performRequest(http.MethodGet, "/contact-person", h.GetAll, tt.target, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var contacts []ContactPersonResponse
+			decodeResponse(t, w, &contacts)
+			if len(contacts) != 1 || contacts[0].Location.ID != 4 || contacts[0].Pic != "Hendra" {
+				t.Fatalf("unexpected contacts: %+v", contacts)
+			}
+		})
+	}
+}
+
+func TestContactPersonHandlerPatchPhoneOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockContactPersonRepository(ctrl)
+	h := NewContactPersonHandler(repo, testLogger)
+
+	repo.EXPECT().GetContactPerson(gomock.Any(), int32(1)).Return(sqlcdb.GetContactPersonRow{ID: 1}, nil)
+	repo.EXPECT().
+		PatchContactPerson(gomock.Any(), sqlcdb.PatchContactPersonParams{
+			ID:    1,
+			Phone: pgtype.Text{String: "0812-0000-0000", Valid: true},
+		}).
+		Return(sqlcdb.ContactPerson{ID: 1, LocationID: 4, Pic: "Hendra", Phone: "0812-0000-0000"}, nil)
+
+	w := performRequest(http.MethodPatch, "/contact-person/:id", h.Patch, "/contact-person/1", `{"phone":"0812-0000-0000"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var contact sqlcdb.ContactPerson
+	decodeResponse(t, w, &contact)
+	if contact.Pic != "Hendra" || contact.LocationID != 4 {
+		t.Fatalf("unexpected contact: %+v", contact)
+	}
+}
+
+func TestContactPersonHandlerCreateEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		email  string
+		status int
+		want   pgtype.Text
+	}{
+		{name: "trimmed", email: `" andi@example.com "`, status: http.StatusCreated, want: pgtype.Text{String: "andi@example.com", Valid: true}},
+		{name: "blank is absent", email: `""`, status: http.StatusCreated},
+		{name: "invalid", email: `"andi at example"`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockContactPersonRepository(ctrl)
+			h := NewContactPersonHandler(repo, testLogger)
+
+			if tt.status == http.StatusCreated {
+				repo.EXPECT().
+					CreateContactPerson(gomock.Any(), sqlcdb.CreateContactPersonParams{LocationID: 4, Pic: "Andi", Phone: "0812", Email: tt.want}).
+					Return(sqlcdb.ContactPerson{ID: 1, LocationID: 4, Pic: "Andi", Phone: "0812", Email: tt.want}, nil)
+			}
+
+			body := `{"location_id": 4, "pic": "Andi", "phone": "0812", "email": ` + tt.email + `}`
+			w := performRequest(http.MethodPost, "/contact-person", h.Create, "/contact-person", body)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestContactPersonHandlerDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockContactPersonRepository(ctrl)
+	h := NewContactPersonHandler(repo, testLogger)
+
+	repo.EXPECT().GetContactPerson(gomock.Any(), int32(3)).Return(sqlcdb.GetContactPersonRow{ID: 3, LocationID: 4}, nil)
+	repo.EXPECT().DeleteContactPerson(gomock.Any(), int32(3)).Return(nil)
+	repo.EXPECT().GetContactPerson(gomock.Any(), int32(5)).Return(sqlcdb.GetContactPersonRow{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodDelete, "/contact-person/:id", h.Delete, "/contact-person/3", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performRequest(http.MethodDelete, "/contact-person/:id", h.Delete, "/contact-person/5", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestContactPersonHandlerRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockContactPersonRepository(ctrl)
+	h := NewContactPersonHandler(repo, testLogger)
+
+	repo.EXPECT().RestoreContactPerson(gomock.Any(), int32(3)).Return(sqlcdb.ContactPerson{ID: 3, LocationID: 4, Pic: "Hendra"}, nil)
+	repo.EXPECT().RestoreContactPerson(gomock.Any(), int32(5)).Return(sqlcdb.ContactPerson{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/contact-person/:id/restore", h.Restore, "/contact-person/3/restore", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var contact sqlcdb.ContactPerson
+	decodeResponse(t, w, &contact)
+	if contact.Pic != "Hendra" {
+		t.Fatalf("unexpected contact: %+v", contact)
+	}
+
+	// Contact persons that are not deleted, or whose location is, cannot be restored
+	w = performRequest(http.MethodPost, "/contact-person/:id/restore", h.Restore, "/contact-person/5/restore", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,560 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Damage report statuses; a report moves from OPEN to RESOLVED
+const (
+	damageStatusOpen     = "OPEN"
+	damageStatusResolved = "RESOLVED"
+)
+
+// Damage report dispositions: what happened to the damaged quantity when it was reported.
+// USED_STOCK and DAMAGED move it to the location's stock item of that type.
+const (
+	dispositionNone      = "NONE"
+	dispositionUsedStock = "USED_STOCK"
+	dispositionDamaged   = "DAMAGED"
+)
+
+// Damage photos are stored under uploads/damage
+const (
+	damagePhotoSubDir = "damage"
+	damagePhotoPrefix = "damage_report"
+)
+
+// errDamageReportInvalid ends a damage report transaction early; the response is written after the rollback
+var errDamageReportInvalid = errors.New("damage report is invalid")
+
+// CreateDamageReportRequest reports a damaged quantity of a stock item. It is sent as multipart
+// form fields with the photos.
+type CreateDamageReportRequest struct {
+	Quantity    int    `json:"quantity" binding:"required,min=1"`
+	Severity    string `json:"severity" binding:"required,oneof=LOW MEDIUM HIGH CRITICAL"`
+	Description string `json:"description" binding:"required"`
+	Disposition string `json:"disposition" binding:"required,oneof=NONE USED_STOCK DAMAGED"`
+}
+
+// ResolveDamageReportRequest closes a damage report with what was done about it
+type ResolveDamageReportRequest struct {
+	Resolution string `json:"resolution" binding:"required"`
+}
+
+// DamageReportResponse is a damage report; the location and sparepart names are left out once
+// they are purged
+type DamageReportResponse struct {
+	ID                 int32                `json:"id"`
+	StockItemID        int32                `json:"stock_item_id"`
+	LocationID         int32                `json:"location_id"`
+	Region             *string              `json:"region,omitempty"`
+	Regency            *string              `json:"regency,omitempty"`
+	Cluster            *string              `json:"cluster,omitempty"`
+	SparepartID        int32                `json:"sparepart_id"`
+	SparepartName      *string              `json:"sparepart_name,omitempty"`
+	StockType          string               `json:"stock_type"`
+	Quantity           int32                `json:"quantity"`
+	Severity           string               `json:"severity"`
+	Description        string               `json:"description"`
+	Photos             []DocumentationPhoto `json:"photos"`
+	Disposition        string               `json:"disposition"`
+	MovedToStockItemID *int32               `json:"moved_to_stock_item_id,omitempty"`
+	Status             string               `json:"status"`
+	Resolution         *string              `json:"resolution"`
+	ReportedBy         *string              `json:"reported_by"`
+	ResolvedBy         *string              `json:"resolved_by"`
+	ResolvedAt         string               `json:"resolved_at,omitempty"`
+	CreatedAt          string               `json:"created_at"`
+	UpdatedAt          string               `json:"updated_at"`
+}
+
+// DamageReportHandler records damaged or defective stock and what was done with it
+type DamageReportHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+	uploads *uploads.Service
+	reports *reports.Store
+}
+
+func NewDamageReportHandler(queries repository.SparepartStockRepository, uploads *uploads.Service, reports *reports.Store, logger *zap.Logger) *DamageReportHandler {
+	return &DamageReportHandler{
+		logger:  logger,
+		queries: queries,
+		uploads: uploads,
+		reports: reports,
+	}
+}
+
+// @Summary Report damaged stock
+// @Description Report a damaged or defective quantity of a stock item with its severity, a description and photos. The disposition moves the quantity: NONE leaves it in the stock item, USED_STOCK moves it from a NEW_STOCK item to the location's USED_STOCK item and DAMAGED moves it to the location's DAMAGED item, so it is no longer available; the target item is created when missing. The report stays OPEN until it is resolved.
+// @Tags Damage Report
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Param quantity formData int true "Damaged quantity"
+// @Param severity formData string true "Severity (LOW, MEDIUM, HIGH, CRITICAL)"
+// @Param description formData string true "What is damaged and how"
+// @Param disposition formData string false "NONE, USED_STOCK or DAMAGED" default(NONE)
+// @Param photos formData file false "Photos of the damage (multiple files allowed)"
+// @Param captions formData []string false "Caption of each photo, in the order of the photos"
+// @Param taken_at formData []string false "When each photo was taken (RFC3339), in the order of the photos"
+// @Success 201 {object} utils.Response{data=DamageReportResponse}
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/stock/{id}/damage-reports [post]
+func (h *DamageReportHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	stockID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+	req, ok := bindDamageReportForm(c)
+	if !ok {
+		return
+	}
+
+	// Stage the photos; they are only moved into place once the report is created
+	photos, staged, ok := stagePhotos(c, h.uploads, damagePhotoSubDir, damagePhotoPrefix, h.logger)
+	if !ok {
+		return
+	}
+
+	var errs []utils.FieldError
+	var id int32
+	err = h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		item, err := repo.GetSparepartStockForUpdate(ctx, int32(stockID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errStockItemNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if req.Quantity > int(item.Quantity) {
+			errs = append(errs, utils.FieldError{Field: "quantity", Message: fmt.Sprintf("exceeds the stock quantity of %d", item.Quantity)})
+			return errDamageReportInvalid
+		}
+		if req.Disposition == dispositionUsedStock && item.StockType != sqlcdb.StockTypeNEWSTOCK {
+			errs = append(errs, utils.FieldError{Field: "disposition", Message: "USED_STOCK only applies to NEW_STOCK items"})
+			return errDamageReportInvalid
+		}
+		if req.Disposition == dispositionDamaged && item.StockType == sqlcdb.StockTypeDAMAGED {
+			errs = append(errs, utils.FieldError{Field: "disposition", Message: "the stock item is DAMAGED already"})
+			return errDamageReportInvalid
+		}
+
+		var movedTo pgtype.Int4
+		if req.Disposition != dispositionNone {
+			if _, err := repo.TransferOutSparepartStock(ctx, sqlcdb.TransferOutSparepartStockParams{ID: item.ID, Quantity: int32(req.Quantity)}); err != nil {
+				return err
+			}
+			target, err := repo.TransferInSparepartStock(ctx, sqlcdb.TransferInSparepartStockParams{
+				LocationID:  item.LocationID,
+				SparepartID: item.SparepartID,
+				StockType:   sqlcdb.StockType(req.Disposition),
+				Quantity:    int32(req.Quantity),
+			})
+			if err != nil {
+				return err
+			}
+			movedTo = pgtype.Int4{Int32: target.ID, Valid: true}
+		}
+
+		report, err := repo.CreateDamageReport(ctx, sqlcdb.CreateDamageReportParams{
+			StockItemID:        item.ID,
+			LocationID:         item.LocationID,
+			SparepartID:        item.SparepartID,
+			StockType:          item.StockType,
+			Quantity:           int32(req.Quantity),
+			Severity:           req.Severity,
+			Description:        req.Description,
+			Photos:             utils.PhotosJSON(photos),
+			Disposition:        req.Disposition,
+			MovedToStockItemID: movedTo,
+			ReportedBy:         utils.TextFilter(utils.UserID(c)),
+		})
+		if err != nil {
+			return err
+		}
+		id = report.ID
+
+		// Move photos into place before commit, a failed move rolls back the report
+		return h.uploads.CommitStaged(ctx, staged, h.logger)
+	})
+	if err != nil {
+		h.uploads.DiscardStaged(staged, h.logger)
+	}
+	switch {
+	case errors.Is(err, errStockItemNotFound):
+		utils.NotFound(c, "Sparepart stock item not found")
+		return
+	case errors.Is(err, errDamageReportInvalid):
+		utils.ValidationError(c, errs...)
+		return
+	case err != nil:
+		utils.HandleError(c, err, "Failed to create damage report", h.logger)
+		return
+	}
+
+	h.respond(c, http.StatusCreated, id, "Damage report created successfully")
+}
+
+// @Summary Get damage reports
+// @Description Get the damage reports, newest first
+// @Tags Damage Report
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (OPEN, RESOLVED)"
+// @Param severity query string false "Filter by severity (LOW, MEDIUM, HIGH, CRITICAL)"
+// @Param disposition query string false "Filter by disposition (NONE, USED_STOCK, DAMAGED)"
+// @Param location_id query int false "Filter by location ID"
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/damage-reports [get]
+func (h *DamageReportHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters, errs := parseDamageReportFilters(c)
+	switch status := c.Query("status"); status {
+	case "":
+	case damageStatusOpen, damageStatusResolved:
+		filters.Status = utils.TextFilter(status)
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of OPEN, RESOLVED"})
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountDamageReports(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count damage reports", h.logger)
+		return
+	}
+
+	reports, err := h.queries.ListDamageReports(ctx, sqlcdb.ListDamageReportsParams{
+		Status:      filters.Status,
+		Severity:    filters.Severity,
+		Disposition: filters.Disposition,
+		LocationID:  filters.LocationID,
+		SparepartID: filters.SparepartID,
+		Limit:       int32(pagination.Limit),
+		Offset:      int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get damage reports", h.logger)
+		return
+	}
+
+	response := make([]DamageReportResponse, 0, len(reports))
+	for _, report := range reports {
+		response = append(response, toDamageReportResponse(sqlcdb.GetDamageReportRow(report)))
+	}
+
+	utils.SuccessWithPagination(c, "Damage reports retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get damage report by ID
+// @Description Get a single damage report with the stock item it was reported on
+// @Tags Damage Report
+// @Accept json
+// @Produce json
+// @Param id path int true "Damage Report ID"
+// @Success 200 {object} utils.Response{data=DamageReportResponse}
+// @Router /sparepart/damage-reports/{id} [get]
+func (h *DamageReportHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid damage report ID")
+		return
+	}
+
+	h.respond(c, http.StatusOK, int32(id), "Damage report retrieved successfully")
+}
+
+// @Summary Resolve damage report
+// @Description Close an OPEN damage report with what was done about the damage, e.g. repaired or returned to the supplier. The stock does not change.
+// @Tags Damage Report
+// @Accept json
+// @Produce json
+// @Param id path int true "Damage Report ID"
+// @Param resolution body ResolveDamageReportRequest true "Resolution"
+// @Success 200 {object} utils.Response{data=DamageReportResponse}
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/damage-reports/{id}/resolve [post]
+func (h *DamageReportHandler) Resolve(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid damage report ID")
+		return
+	}
+	var req ResolveDamageReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	resolution := strings.TrimSpace(req.Resolution)
+	if resolution == "" {
+		utils.ValidationError(c, utils.FieldError{Field: "resolution", Message: "is required"})
+		return
+	}
+
+	_, err = h.queries.ResolveDamageReport(ctx, sqlcdb.ResolveDamageReportParams{
+		ID:         int32(id),
+		Resolution: utils.OptionalText(&resolution),
+		ResolvedBy: utils.TextFilter(utils.UserID(c)),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Either the report does not exist or it is resolved already
+		if _, err := h.queries.GetDamageReport(ctx, int32(id)); err != nil {
+			utils.NotFound(c, "Damage report not found")
+			return
+		}
+		utils.Error(c, "Only OPEN damage reports can be resolved; this one is RESOLVED", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to resolve damage report", h.logger)
+		return
+	}
+
+	h.respond(c, http.StatusOK, int32(id), "Damage report resolved successfully")
+}
+
+// @Summary Export open damage reports to Excel
+// @Description Export the OPEN damage reports, newest first, with the same filters as the listing
+// @Tags Damage Report
+// @Accept json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param severity query string false "Filter by severity (LOW, MEDIUM, HIGH, CRITICAL)"
+// @Param disposition query string false "Filter by disposition (NONE, USED_STOCK, DAMAGED)"
+// @Param location_id query int false "Filter by location ID"
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
+// @Success 200 {file} application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Router /sparepart/damage-reports/export/excel [get]
+func (h *DamageReportHandler) ExportExcel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters, errs := parseDamageReportFilters(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	var rows int
+	buf, err := utils.ExportDamageReportsToExcel(utils.CountRows(h.exportReader(ctx, filters), &rows), h.logger)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to generate Excel", h.logger)
+		return
+	}
+
+	c.Set(utils.ExportRowsKey, rows)
+	filename := fmt.Sprintf("damage_reports_%s.xlsx", time.Now().UTC().Format("20060102_150405"))
+	sendExport(c, h.reports, buf.Bytes(), filename, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", h.logger)
+}
+
+// @Summary Export open damage reports to CSV
+// @Description Export the OPEN damage reports to CSV with the Excel export's columns; the file is streamed while it is written
+// @Tags Damage Report
+// @Accept json
+// @Produce text/csv
+// @Param severity query string false "Filter by severity (LOW, MEDIUM, HIGH, CRITICAL)"
+// @Param disposition query string false "Filter by disposition (NONE, USED_STOCK, DAMAGED)"
+// @Param location_id query int false "Filter by location ID"
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
+// @Success 200 {file} text/csv
+// @Router /sparepart/damage-reports/export/csv [get]
+func (h *DamageReportHandler) ExportCSV(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters, errs := parseDamageReportFilters(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	var rows int
+	filename := fmt.Sprintf("damage_reports_%s.csv", time.Now().UTC().Format("20060102_150405"))
+	streamExport(c, h.reports, filename, "text/csv; charset=utf-8", func(w io.Writer) error {
+		err := utils.WriteDamageReportsCSV(w, utils.CountRows(h.exportReader(ctx, filters), &rows))
+		c.Set(utils.ExportRowsKey, rows)
+		return err
+	}, h.logger)
+}
+
+// exportReader pages through the open damage reports newest first with a keyset on the ID
+func (h *DamageReportHandler) exportReader(ctx context.Context, filters sqlcdb.CountDamageReportsParams) utils.BatchReader[sqlcdb.ListOpenDamageReportsForExportRow] {
+	params := sqlcdb.ListOpenDamageReportsForExportParams{
+		Severity:    filters.Severity,
+		Disposition: filters.Disposition,
+		LocationID:  filters.LocationID,
+		SparepartID: filters.SparepartID,
+		Limit:       exportBatchSize,
+	}
+	return func() ([]sqlcdb.ListOpenDamageReportsForExportRow, error) {
+		rows, err := h.queries.ListOpenDamageReportsForExport(ctx, params)
+		if err != nil || len(rows) == 0 {
+			return rows, err
+		}
+		params.AfterID = pgtype.Int4{Int32: rows[len(rows)-1].ID, Valid: true}
+		return rows, nil
+	}
+}
+
+// parseDamageReportFilters reads the damage report filters shared by the listing and the exports
+func parseDamageReportFilters(c *gin.Context) (sqlcdb.CountDamageReportsParams, []utils.FieldError) {
+	var errs []utils.FieldError
+	var filters sqlcdb.CountDamageReportsParams
+	switch severity := c.Query("severity"); severity {
+	case "":
+	case "LOW", "MEDIUM", "HIGH", "CRITICAL":
+		filters.Severity = utils.TextFilter(severity)
+	default:
+		errs = append(errs, utils.FieldError{Field: "severity", Message: "must be one of LOW, MEDIUM, HIGH, CRITICAL"})
+	}
+	switch disposition := c.Query("disposition"); disposition {
+	case "":
+	case dispositionNone, dispositionUsedStock, dispositionDamaged:
+		filters.Disposition = utils.TextFilter(disposition)
+	default:
+		errs = append(errs, utils.FieldError{Field: "disposition", Message: "must be one of NONE, USED_STOCK, DAMAGED"})
+	}
+	idFilters := []struct {
+		field  string
+		target *pgtype.Int4
+	}{
+		{"location_id", &filters.LocationID},
+		{"sparepart_id", &filters.SparepartID},
+	}
+	for _, filter := range idFilters {
+		if value := c.Query(filter.field); value != "" {
+			id, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || id < 1 {
+				errs = append(errs, utils.FieldError{Field: filter.field, Message: "must be a positive integer"})
+				continue
+			}
+			*filter.target = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	return filters, errs
+}
+
+// bindDamageReportForm reads the multipart fields of a damage report and validates them,
+// writing the response when they are invalid. Severity and disposition are case-insensitive;
+// the disposition defaults to NONE.
+func bindDamageReportForm(c *gin.Context) (CreateDamageReportRequest, bool) {
+	req := CreateDamageReportRequest{
+		Severity:    strings.ToUpper(strings.TrimSpace(c.PostForm("severity"))),
+		Description: strings.TrimSpace(c.PostForm("description")),
+		Disposition: strings.ToUpper(strings.TrimSpace(c.PostForm("disposition"))),
+	}
+	if req.Disposition == "" {
+		req.Disposition = dispositionNone
+	}
+	if value := c.PostForm("quantity"); value != "" {
+		quantity, err := strconv.Atoi(value)
+		if err != nil {
+			utils.ValidationError(c, utils.FieldError{Field: "quantity", Rule: "type", Message: "must be a number"})
+			return req, false
+		}
+		req.Quantity = quantity
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		utils.BindingError(c, err)
+		return req, false
+	}
+	return req, true
+}
+
+// respond writes the damage report
+func (h *DamageReportHandler) respond(c *gin.Context, code int, id int32, message string) {
+	report, err := h.queries.GetDamageReport(c.Request.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.NotFound(c, "Damage report not found")
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get damage report", h.logger)
+		return
+	}
+
+	c.JSON(code, utils.Response{
+		Success: true,
+		Message: message,
+		Data:    toDamageReportResponse(report),
+	})
+}
+
+func toDamageReportResponse(row sqlcdb.GetDamageReportRow) DamageReportResponse {
+	response := DamageReportResponse{
+		ID:          row.ID,
+		StockItemID: row.StockItemID,
+		LocationID:  row.LocationID,
+		SparepartID: row.SparepartID,
+		StockType:   string(row.StockType),
+		Quantity:    row.Quantity,
+		Severity:    row.Severity,
+		Description: row.Description,
+		Photos:      documentationPhotos(row.Photos),
+		Disposition: row.Disposition,
+		Status:      row.Status,
+		ResolvedAt:  utils.FormatTimestamp(row.ResolvedAt),
+		CreatedAt:   utils.FormatTimestamp(row.CreatedAt),
+		UpdatedAt:   utils.FormatTimestamp(row.UpdatedAt),
+	}
+	if row.Region.Valid {
+		region := string(row.Region.RegionType)
+		response.Region = &region
+	}
+	if row.Regency.Valid {
+		response.Regency = &row.Regency.String
+	}
+	if row.Cluster.Valid {
+		response.Cluster = &row.Cluster.String
+	}
+	if row.SparepartName.Valid {
+		response.SparepartName = &row.SparepartName.String
+	}
+	if row.MovedToStockItemID.Valid {
+		response.MovedToStockItemID = &row.MovedToStockItemID.Int32
+	}
+	if row.Resolution.Valid {
+		response.Resolution = &row.Resolution.String
+	}
+	if row.ReportedBy.Valid {
+		response.ReportedBy = &row.ReportedBy.String
+	}
+	if row.ResolvedBy.Valid {
+		response.ResolvedBy = &row.ResolvedBy.String
+	}
+	return response
+}
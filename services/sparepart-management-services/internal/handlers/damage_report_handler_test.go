@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDamageReportHandlerCreateMovesToUsedStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewDamageReportHandler(repo, nil, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{
+		ID: 4, LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 5,
+	}, nil)
+	repo.EXPECT().TransferOutSparepartStock(gomock.Any(), sqlcdb.TransferOutSparepartStockParams{ID: 4, Quantity: 2}).
+		Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 3}, nil)
+	repo.EXPECT().TransferInSparepartStock(gomock.Any(), sqlcdb.TransferInSparepartStockParams{
+		LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 2,
+	}).Return(sqlcdb.SparepartStockItem{ID: 9, Quantity: 2}, nil)
+	repo.EXPECT().CreateDamageReport(gomock.Any(), sqlcdb.CreateDamageReportParams{
+		StockItemID:        4,
+		LocationID:         3,
+		SparepartID:        7,
+		StockType:          sqlcdb.StockTypeNEWSTOCK,
+		Quantity:           2,
+		Severity:           "HIGH",
+		Description:        "Casing retak",
+		Photos:             utils.PhotosJSON(nil),
+		Disposition:        dispositionUsedStock,
+		MovedToStockItemID: pgtype.Int4{Int32: 9, Valid: true},
+	}).Return(sqlcdb.DamageReport{ID: 12}, nil)
+	repo.EXPECT().GetDamageReport(gomock.Any(), int32(12)).Return(sqlcdb.GetDamageReportRow{
+		ID: 12, StockItemID: 4, Quantity: 2, Severity: "HIGH", Disposition: dispositionUsedStock,
+		MovedToStockItemID: pgtype.Int4{Int32: 9, Valid: true}, Status: damageStatusOpen, Cluster: pgtype.Text{String: "Dobo", Valid: true},
+	}, nil)
+
+	w := performForm(t, "/stock/:id/damage-reports", h.Create, "/stock/4/damage-reports", map[string]string{
+		"quantity":    "2",
+		"severity":    "high",
+		"description": " Casing retak ",
+		"disposition": "used_stock",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var report DamageReportResponse
+	decodeResponse(t, w, &report)
+	if report.ID != 12 || report.MovedToStockItemID == nil || *report.MovedToStockItemID != 9 || report.Status != damageStatusOpen {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestDamageReportHandlerCreateMovesToDamagedStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewDamageReportHandler(repo, nil, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{
+		ID: 4, LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 5,
+	}, nil)
+	repo.EXPECT().TransferOutSparepartStock(gomock.Any(), sqlcdb.TransferOutSparepartStockParams{ID: 4, Quantity: 1}).
+		Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 4}, nil)
+	repo.EXPECT().TransferInSparepartStock(gomock.Any(), sqlcdb.TransferInSparepartStockParams{
+		LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeDAMAGED, Quantity: 1,
+	}).Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 1}, nil)
+	repo.EXPECT().
+		CreateDamageReport(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.CreateDamageReportParams) (sqlcdb.DamageReport, error) {
+			if arg.Disposition != dispositionDamaged || arg.MovedToStockItemID.Int32 != 10 {
+				t.Errorf("unexpected damage report params: %+v", arg)
+			}
+			return sqlcdb.DamageReport{ID: 13}, nil
+		})
+	repo.EXPECT().GetDamageReport(gomock.Any(), int32(13)).Return(sqlcdb.GetDamageReportRow{
+		ID: 13, Disposition: dispositionDamaged, MovedToStockItemID: pgtype.Int4{Int32: 10, Valid: true}, Status: damageStatusOpen,
+	}, nil)
+
+	w := performForm(t, "/stock/:id/damage-reports", h.Create, "/stock/4/damage-reports", map[string]string{
+		"quantity": "1", "severity": "MEDIUM", "description": "Konektor patah", "disposition": "DAMAGED",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDamageReportHandlerCreateRejectsInvalidDisposition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewDamageReportHandler(repo, nil, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{
+		ID: 4, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 5,
+	}, nil)
+
+	w := performForm(t, "/stock/:id/damage-reports", h.Create, "/stock/4/damage-reports", map[string]string{
+		"quantity": "1", "severity": "LOW", "description": "Kabel putus", "disposition": "USED_STOCK",
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "disposition" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestDamageReportHandlerCreateExceedsQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewDamageReportHandler(repo, nil, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 1}, nil)
+
+	w := performForm(t, "/stock/:id/damage-reports", h.Create, "/stock/4/damage-reports", map[string]string{
+		"quantity": "3", "severity": "CRITICAL", "description": "Terbakar", "disposition": "DAMAGED",
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "quantity" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestDamageReportHandlerGetAllValidatesFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewDamageReportHandler(repo, nil, nil, testLogger)
+
+	w := performRequest(http.MethodGet, "/damage-reports", h.GetAll, "/damage-reports?severity=SEVERE&location_id=x&status=CLOSED", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 3 || resp.Errors[0].Field != "severity" || resp.Errors[1].Field != "location_id" || resp.Errors[2].Field != "status" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestDamageReportHandlerResolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewDamageReportHandler(repo, nil, nil, testLogger)
+
+	repo.EXPECT().ResolveDamageReport(gomock.Any(), sqlcdb.ResolveDamageReportParams{
+		ID:         12,
+		Resolution: pgtype.Text{String: "Dikirim RMA ke supplier", Valid: true},
+		ResolvedBy: pgtype.Text{String: "budi", Valid: true},
+	}).Return(sqlcdb.DamageReport{ID: 12, Status: damageStatusResolved}, nil)
+	repo.EXPECT().GetDamageReport(gomock.Any(), int32(12)).Return(sqlcdb.GetDamageReportRow{ID: 12, Status: damageStatusResolved}, nil).Times(2)
+	repo.EXPECT().ResolveDamageReport(gomock.Any(), gomock.Any()).Return(sqlcdb.DamageReport{}, pgx.ErrNoRows)
+
+	route := "/damage-reports/:id/resolve"
+	w := performRequestAs("budi", http.MethodPost, route, h.Resolve, "/damage-reports/12/resolve", `{"resolution": "Dikirim RMA ke supplier"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performRequest(http.MethodPost, route, h.Resolve, "/damage-reports/12/resolve", `{"resolution": "Lagi"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDamageReportHandlerExportCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewDamageReportHandler(repo, nil, nil, testLogger)
+
+	severity := pgtype.Text{String: "HIGH", Valid: true}
+	gomock.InOrder(
+		repo.EXPECT().
+			ListOpenDamageReportsForExport(gomock.Any(), sqlcdb.ListOpenDamageReportsForExportParams{Severity: severity, Limit: exportBatchSize}).
+			Return([]sqlcdb.ListOpenDamageReportsForExportRow{
+				{ID: 12, Cluster: pgtype.Text{String: "Dobo", Valid: true}, SparepartName: pgtype.Text{String: "BMS", Valid: true}, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2, Severity: "HIGH",
+					Disposition: dispositionDamaged, Description: "Casing retak", Photos: []byte(`["a.jpg"]`)},
+			}, nil),
+		repo.EXPECT().
+			ListOpenDamageReportsForExport(gomock.Any(), sqlcdb.ListOpenDamageReportsForExportParams{
+				Severity: severity, AfterID: pgtype.Int4{Int32: 12, Valid: true}, Limit: exportBatchSize,
+			}).
+			Return([]sqlcdb.ListOpenDamageReportsForExportRow{}, nil),
+	)
+
+	w := performRequest(http.MethodGet, "/damage-reports/export/csv", h.ExportCSV, "/damage-reports/export/csv?severity=HIGH", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read exported CSV: %v", err)
+	}
+	if len(rows) != 2 || rows[0][7] != "Severity" || rows[1][4] != "BMS" || rows[1][8] != dispositionDamaged || rows[1][10] != "1" {
+		t.Fatalf("unexpected exported rows: %v", rows)
+	}
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DashboardKPIsResponse holds the headline numbers shown on the web dashboard
+type DashboardKPIsResponse struct {
+	SkusTracked         int64                 `json:"skus_tracked"`
+	StockItems          int64                 `json:"stock_items"`
+	LocationsWithStock  int64                 `json:"locations_with_stock"`
+	QuantityByStockType map[string]int64      `json:"quantity_by_stock_type"`
+	ItemsWithoutPhotos  ItemsWithoutPhotosKPI `json:"items_without_photos"`
+}
+
+type ItemsWithoutPhotosKPI struct {
+	SparepartStock int64 `json:"sparepart_stock"`
+	ToolsAlker     int64 `json:"tools_alker"`
+}
+
+// Bounds of the number of lowest quantity stock items in the summary
+const (
+	defaultSummaryTop = 10
+	maxSummaryTop     = 100
+)
+
+// DashboardSummaryResponse aggregates the stock for the summary dashboard
+type DashboardSummaryResponse struct {
+	Totals         SummaryTotals          `json:"totals"`
+	PhotoCoverage  SummaryPhotoCoverage   `json:"photo_coverage"`
+	ByRegion       []SummaryAreaTotals    `json:"by_region"`
+	ByRegency      []SummaryAreaTotals    `json:"by_regency"`
+	ByCluster      []SummaryAreaTotals    `json:"by_cluster"`
+	ByItemType     []SummaryItemTypeTotal `json:"by_item_type"`
+	LowestQuantity []SummaryLowStockItem  `json:"lowest_quantity"`
+}
+
+// SummaryTotals counts the stock items and their quantity, overall and per stock type
+type SummaryTotals struct {
+	StockItems int64                 `json:"stock_items"`
+	Quantity   int64                 `json:"quantity"`
+	NewStock   SummaryStockTypeTotal `json:"new_stock"`
+	UsedStock  SummaryStockTypeTotal `json:"used_stock"`
+	Damaged    SummaryStockTypeTotal `json:"damaged"`
+	InRepair   SummaryStockTypeTotal `json:"in_repair"`
+	Reserved   SummaryStockTypeTotal `json:"reserved"`
+}
+
+type SummaryStockTypeTotal struct {
+	StockItems int64 `json:"stock_items"`
+	Quantity   int64 `json:"quantity"`
+}
+
+// SummaryPhotoCoverage tells how many items have at least one photo
+type SummaryPhotoCoverage struct {
+	SparepartStock PhotoCoverage `json:"sparepart_stock"`
+	ToolsAlker     PhotoCoverage `json:"tools_alker"`
+}
+
+type PhotoCoverage struct {
+	Items      int64   `json:"items"`
+	WithPhotos int64   `json:"with_photos"`
+	Percentage float64 `json:"percentage"`
+}
+
+// SummaryAreaTotals is the stock of a region, regency or cluster; the fields below its level are omitted
+type SummaryAreaTotals struct {
+	Region       string  `json:"region"`
+	Regency      *string `json:"regency,omitempty"`
+	Cluster      *string `json:"cluster,omitempty"`
+	StockItems   int64   `json:"stock_items"`
+	Quantity     int64   `json:"quantity"`
+	NewQuantity  int64   `json:"new_quantity"`
+	UsedQuantity int64   `json:"used_quantity"`
+}
+
+type SummaryItemTypeTotal struct {
+	ItemType     string `json:"item_type"`
+	StockItems   int64  `json:"stock_items"`
+	Quantity     int64  `json:"quantity"`
+	NewQuantity  int64  `json:"new_quantity"`
+	UsedQuantity int64  `json:"used_quantity"`
+}
+
+// SummaryLowStockItem is one of the stock items with the lowest quantity
+type SummaryLowStockItem struct {
+	ID            int32  `json:"id"`
+	LocationID    int32  `json:"location_id"`
+	Region        string `json:"region"`
+	Regency       string `json:"regency"`
+	Cluster       string `json:"cluster"`
+	SparepartID   int32  `json:"sparepart_id"`
+	SparepartName string `json:"sparepart_name"`
+	StockType     string `json:"stock_type"`
+	Quantity      int32  `json:"quantity"`
+}
+
+func photoCoverage(items, withPhotos int64) PhotoCoverage {
+	coverage := PhotoCoverage{Items: items, WithPhotos: withPhotos}
+	if items > 0 {
+		coverage.Percentage = float64(withPhotos) * 100 / float64(items)
+	}
+	return coverage
+}
+
+func toSummaryAreaTotals(row sqlcdb.ListStockTotalsByAreaRow) SummaryAreaTotals {
+	totals := SummaryAreaTotals{
+		Region:       string(row.Region),
+		StockItems:   row.StockItems,
+		Quantity:     row.Quantity,
+		NewQuantity:  row.NewQuantity,
+		UsedQuantity: row.UsedQuantity,
+	}
+	if row.Regency.Valid {
+		totals.Regency = &row.Regency.String
+	}
+	if row.Cluster.Valid {
+		totals.Cluster = &row.Cluster.String
+	}
+	return totals
+}
+
+// DashboardHandler serves dashboard KPIs and the stock summary. The numbers are cached for a
+// short TTL by the repository, so they may lag behind the latest writes.
+type DashboardHandler struct {
+	logger  *zap.Logger
+	queries repository.DashboardRepository
+}
+
+func NewDashboardHandler(queries repository.DashboardRepository, logger *zap.Logger) *DashboardHandler {
+	return &DashboardHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get dashboard KPIs
+// @Description Get headline numbers: SKUs tracked, quantity per stock type and items without photos
+// @Tags Dashboard
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /sparepart/dashboard/kpis [get]
+func (h *DashboardHandler) GetKPIs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	kpis, err := h.queries.GetDashboardKPIs(ctx)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get dashboard KPIs", h.logger)
+		return
+	}
+
+	quantities, err := h.queries.ListStockQuantityByType(ctx)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock quantity by type", h.logger)
+		return
+	}
+
+	quantityByType := make(map[string]int64, len(quantities))
+	for _, row := range quantities {
+		quantityByType[string(row.StockType)] = row.Quantity
+	}
+
+	utils.Success(c, "Dashboard KPIs retrieved successfully", DashboardKPIsResponse{
+		SkusTracked:         kpis.SkusTracked,
+		StockItems:          kpis.StockItems,
+		LocationsWithStock:  kpis.LocationsWithStock,
+		QuantityByStockType: quantityByType,
+		ItemsWithoutPhotos: ItemsWithoutPhotosKPI{
+			SparepartStock: kpis.StockItemsWithoutPhotos,
+			ToolsAlker:     kpis.ToolsItemsWithoutPhotos,
+		},
+	})
+}
+
+// @Summary Get stock summary
+// @Description Get the stock aggregated for the summary dashboard: totals per stock type, photo coverage, totals per region, regency, cluster and item type, and the stock items with the lowest quantity
+// @Tags Dashboard
+// @Accept json
+// @Produce json
+// @Param top query int false "Number of lowest quantity stock items (max 100)" default(10)
+// @Success 200 {object} utils.Response
+// @Router /sparepart/summary [get]
+func (h *DashboardHandler) GetSummary(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	top := defaultSummaryTop
+	if value := c.Query("top"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxSummaryTop {
+			utils.ValidationError(c, utils.FieldError{Field: "top", Message: fmt.Sprintf("must be between 1 and %d", maxSummaryTop)})
+			return
+		}
+		top = n
+	}
+
+	totals, err := h.queries.GetStockSummaryTotals(ctx)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock totals", h.logger)
+		return
+	}
+
+	areas, err := h.queries.ListStockTotalsByArea(ctx)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock totals by area", h.logger)
+		return
+	}
+
+	itemTypes, err := h.queries.ListStockTotalsByItemType(ctx)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock totals by item type", h.logger)
+		return
+	}
+
+	lowest, err := h.queries.ListLowestQuantityStockItems(ctx, int32(top))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get lowest quantity stock items", h.logger)
+		return
+	}
+
+	response := DashboardSummaryResponse{
+		Totals: SummaryTotals{
+			StockItems: totals.StockItems,
+			Quantity:   totals.Quantity,
+			NewStock:   SummaryStockTypeTotal{StockItems: totals.NewStockItems, Quantity: totals.NewQuantity},
+			UsedStock:  SummaryStockTypeTotal{StockItems: totals.UsedStockItems, Quantity: totals.UsedQuantity},
+			Damaged:    SummaryStockTypeTotal{StockItems: totals.DamagedItems, Quantity: totals.DamagedQuantity},
+			InRepair:   SummaryStockTypeTotal{StockItems: totals.InRepairItems, Quantity: totals.InRepairQuantity},
+			Reserved:   SummaryStockTypeTotal{StockItems: totals.ReservedItems, Quantity: totals.ReservedQuantity},
+		},
+		PhotoCoverage: SummaryPhotoCoverage{
+			SparepartStock: photoCoverage(totals.StockItems, totals.StockItemsWithPhotos),
+			ToolsAlker:     photoCoverage(totals.ToolsItems, totals.ToolsItemsWithPhotos),
+		},
+		ByRegion:       []SummaryAreaTotals{},
+		ByRegency:      []SummaryAreaTotals{},
+		ByCluster:      []SummaryAreaTotals{},
+		ByItemType:     make([]SummaryItemTypeTotal, 0, len(itemTypes)),
+		LowestQuantity: make([]SummaryLowStockItem, 0, len(lowest)),
+	}
+	for _, row := range areas {
+		switch row.Level {
+		case "REGION":
+			response.ByRegion = append(response.ByRegion, toSummaryAreaTotals(row))
+		case "REGENCY":
+			response.ByRegency = append(response.ByRegency, toSummaryAreaTotals(row))
+		default:
+			response.ByCluster = append(response.ByCluster, toSummaryAreaTotals(row))
+		}
+	}
+	for _, row := range itemTypes {
+		response.ByItemType = append(response.ByItemType, SummaryItemTypeTotal{
+			ItemType:     string(row.ItemType),
+			StockItems:   row.StockItems,
+			Quantity:     row.Quantity,
+			NewQuantity:  row.NewQuantity,
+			UsedQuantity: row.UsedQuantity,
+		})
+	}
+	for _, row := range lowest {
+		response.LowestQuantity = append(response.LowestQuantity, SummaryLowStockItem{
+			ID:            row.ID,
+			LocationID:    row.LocationID,
+			Region:        string(row.Region),
+			Regency:       row.Regency,
+			Cluster:       row.Cluster,
+			SparepartID:   row.SparepartID,
+			SparepartName: row.SparepartName,
+			StockType:     string(row.StockType),
+			Quantity:      row.Quantity,
+		})
+	}
+
+	utils.Success(c, "Stock summary retrieved successfully", response)
+}
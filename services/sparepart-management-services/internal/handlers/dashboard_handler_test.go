@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDashboardHandlerGetKPIs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDashboardRepository(ctrl)
+	h := NewDashboardHandler(repo, testLogger)
+
+	repo.EXPECT().GetDashboardKPIs(gomock.Any()).Return(sqlcdb.GetDashboardKPIsRow{
+		SkusTracked:             7,
+		StockItems:              20,
+		LocationsWithStock:      4,
+		StockItemsWithoutPhotos: 3,
+		ToolsItemsWithoutPhotos: 1,
+	}, nil)
+	repo.EXPECT().ListStockQuantityByType(gomock.Any()).Return([]sqlcdb.ListStockQuantityByTypeRow{
+		{StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 42},
+		{StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 5},
+	}, nil)
+
+	w := performRequest(http.MethodGet, "/dashboard/kpis", h.GetKPIs, "/dashboard/kpis", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var kpis DashboardKPIsResponse
+	decodeResponse(t, w, &kpis)
+	if kpis.SkusTracked != 7 || kpis.QuantityByStockType["NEW_STOCK"] != 42 || kpis.QuantityByStockType["USED_STOCK"] != 5 {
+		t.Fatalf("unexpected KPIs: %+v", kpis)
+	}
+	if kpis.ItemsWithoutPhotos.SparepartStock != 3 || kpis.ItemsWithoutPhotos.ToolsAlker != 1 {
+		t.Fatalf("unexpected items without photos: %+v", kpis.ItemsWithoutPhotos)
+	}
+}
+
+func TestDashboardHandlerGetSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDashboardRepository(ctrl)
+	h := NewDashboardHandler(repo, testLogger)
+
+	repo.EXPECT().GetStockSummaryTotals(gomock.Any()).Return(sqlcdb.GetStockSummaryTotalsRow{
+		StockItems: 4, Quantity: 30, NewStockItems: 3, NewQuantity: 25, UsedStockItems: 1, UsedQuantity: 5,
+		StockItemsWithPhotos: 1, ToolsItems: 0,
+	}, nil)
+	repo.EXPECT().ListStockTotalsByArea(gomock.Any()).Return([]sqlcdb.ListStockTotalsByAreaRow{
+		{Level: "REGION", Region: "PAPUA", StockItems: 4, Quantity: 30},
+		{Level: "REGENCY", Region: "PAPUA", Regency: pgtype.Text{String: "Jayapura", Valid: true}, StockItems: 4, Quantity: 30},
+		{Level: "CLUSTER", Region: "PAPUA", Regency: pgtype.Text{String: "Jayapura", Valid: true}, Cluster: pgtype.Text{String: "Sentani", Valid: true}, StockItems: 4, Quantity: 30},
+	}, nil)
+	repo.EXPECT().ListStockTotalsByItemType(gomock.Any()).Return([]sqlcdb.ListStockTotalsByItemTypeRow{
+		{ItemType: "SPAREPART", StockItems: 4, Quantity: 30},
+	}, nil)
+	repo.EXPECT().ListLowestQuantityStockItems(gomock.Any(), int32(5)).Return([]sqlcdb.ListLowestQuantityStockItemsRow{
+		{ID: 9, SparepartName: "BMS", Quantity: 0},
+	}, nil)
+
+	w := performRequest(http.MethodGet, "/summary", h.GetSummary, "/summary?top=5", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var summary DashboardSummaryResponse
+	decodeResponse(t, w, &summary)
+	if summary.Totals.NewStock.Quantity != 25 || summary.Totals.UsedStock.StockItems != 1 {
+		t.Fatalf("unexpected totals: %+v", summary.Totals)
+	}
+	if summary.PhotoCoverage.SparepartStock.Percentage != 25 || summary.PhotoCoverage.ToolsAlker.Percentage != 0 {
+		t.Fatalf("unexpected photo coverage: %+v", summary.PhotoCoverage)
+	}
+	if len(summary.ByRegion) != 1 || len(summary.ByRegency) != 1 || len(summary.ByCluster) != 1 || summary.ByRegion[0].Regency != nil || *summary.ByCluster[0].Cluster != "Sentani" {
+		t.Fatalf("unexpected area totals: %+v", summary)
+	}
+	if len(summary.LowestQuantity) != 1 || summary.LowestQuantity[0].ID != 9 {
+		t.Fatalf("unexpected lowest quantity items: %+v", summary.LowestQuantity)
+	}
+}
+
+func TestDashboardHandlerGetSummaryRejectsInvalidTop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDashboardRepository(ctrl)
+	h := NewDashboardHandler(repo, testLogger)
+
+	w := performRequest(http.MethodGet, "/summary", h.GetSummary, "/summary?top=500", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultDataQualityLimit and maxDataQualityLimit bound each drill-down list
+	defaultDataQualityLimit = 50
+	maxDataQualityLimit     = 500
+
+	// defaultZeroQuantityMonths is how long a quantity of 0 may sit untouched before it is reported
+	defaultZeroQuantityMonths = 6
+)
+
+// DataQualityCheck is the number of issues one check found and the first of them for cleanup
+type DataQualityCheck[T any] struct {
+	Count int64 `json:"count"`
+	Items []T   `json:"items"`
+}
+
+// DataQualityReport lists the issues found by every check
+type DataQualityReport struct {
+	ItemsWithoutPhotos      DataQualityCheck[DataQualityItem]     `json:"items_without_photos"`
+	LocationsWithoutContact DataQualityCheck[DataQualityLocation] `json:"locations_without_contact"`
+	DuplicateMasterNames    DataQualityCheck[DuplicateMasterName] `json:"duplicate_master_names"`
+	StaleZeroQuantities     DataQualityCheck[DataQualityItem]     `json:"stale_zero_quantities"`
+	BrokenFileReferences    DataQualityCheck[BrokenFileReference] `json:"broken_file_references"`
+	ZeroQuantityMonths      int                                   `json:"zero_quantity_months"`
+	GeneratedAt             string                                `json:"generated_at"`
+}
+
+// DataQualityItem is a stock (SPAREPART) or tools alker (TOOLS_ALKER) item with its location
+type DataQualityItem struct {
+	ItemType   string  `json:"item_type"`
+	ID         int32   `json:"id"`
+	LocationID int32   `json:"location_id"`
+	Region     string  `json:"region"`
+	Regency    string  `json:"regency"`
+	Cluster    string  `json:"cluster"`
+	Name       string  `json:"name"`
+	StockType  *string `json:"stock_type"`
+	Quantity   int32   `json:"quantity"`
+	UpdatedAt  string  `json:"updated_at"`
+}
+
+type DataQualityLocation struct {
+	ID      int32  `json:"id"`
+	Region  string `json:"region"`
+	Regency string `json:"regency"`
+	Cluster string `json:"cluster"`
+}
+
+// DuplicateMasterName is a group of master entries whose names differ only in case or spacing
+type DuplicateMasterName struct {
+	NormalizedName string   `json:"normalized_name"`
+	IDs            []int32  `json:"ids"`
+	Names          []string `json:"names"`
+}
+
+// BrokenFileReference is a photo of an item whose file is missing from the uploads directory
+type BrokenFileReference struct {
+	ItemType   string `json:"item_type"`
+	ID         int32  `json:"id"`
+	LocationID int32  `json:"location_id"`
+	PhotoIndex int    `json:"photo_index"`
+	Path       string `json:"path"`
+}
+
+type DataQualityHandler struct {
+	logger  *zap.Logger
+	queries repository.DataQualityRepository
+	uploads *uploads.Service
+}
+
+func NewDataQualityHandler(queries repository.DataQualityRepository, uploads *uploads.Service, logger *zap.Logger) *DataQualityHandler {
+	return &DataQualityHandler{
+		logger:  logger,
+		queries: queries,
+		uploads: uploads,
+	}
+}
+
+// @Summary Get data quality report
+// @Description Scan for items without photos, locations without contact person, duplicate master names, quantities of 0 untouched for N months and photos whose file is missing; returns counts and drill-down lists
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param limit query int false "Items per drill-down list (max 500)" default(50)
+// @Param zero_quantity_months query int false "Report quantities of 0 not updated for this many months" default(6)
+// @Success 200 {object} utils.Response
+// @Router /sparepart/admin/data-quality [get]
+func (h *DataQualityHandler) GetReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	limit := defaultDataQualityLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			errs = append(errs, utils.FieldError{Field: "limit", Message: "must be a positive integer"})
+		} else {
+			limit = min(parsed, maxDataQualityLimit)
+		}
+	}
+	months := defaultZeroQuantityMonths
+	if value := c.Query("zero_quantity_months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 120 {
+			errs = append(errs, utils.FieldError{Field: "zero_quantity_months", Message: "must be between 1 and 120"})
+		} else {
+			months = parsed
+		}
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	now := time.Now().UTC()
+	zeroBefore := pgtype.Timestamptz{Time: now.AddDate(0, -months, 0), Valid: true}
+
+	counts, err := h.queries.GetDataQualityCounts(ctx, zeroBefore)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count data quality issues", h.logger)
+		return
+	}
+
+	withoutPhotos, err := h.queries.ListItemsWithoutPhotos(ctx, int32(limit))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get items without photos", h.logger)
+		return
+	}
+
+	withoutContact, err := h.queries.ListLocationsWithoutContact(ctx, int32(limit))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get locations without contact person", h.logger)
+		return
+	}
+
+	duplicates, err := h.queries.ListDuplicateMasterNames(ctx, int32(limit))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get duplicate master names", h.logger)
+		return
+	}
+
+	staleZero, err := h.queries.ListStaleZeroQuantities(ctx, sqlcdb.ListStaleZeroQuantitiesParams{ZeroBefore: zeroBefore, Limit: int32(limit)})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stale zero quantities", h.logger)
+		return
+	}
+
+	broken, err := h.findBrokenFileReferences(c, limit)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to check file references", h.logger)
+		return
+	}
+
+	report := DataQualityReport{
+		ItemsWithoutPhotos:      DataQualityCheck[DataQualityItem]{Count: counts.ItemsWithoutPhotos, Items: make([]DataQualityItem, 0, len(withoutPhotos))},
+		LocationsWithoutContact: DataQualityCheck[DataQualityLocation]{Count: counts.LocationsWithoutContact, Items: make([]DataQualityLocation, 0, len(withoutContact))},
+		DuplicateMasterNames:    DataQualityCheck[DuplicateMasterName]{Count: counts.DuplicateMasterNames, Items: make([]DuplicateMasterName, 0, len(duplicates))},
+		StaleZeroQuantities:     DataQualityCheck[DataQualityItem]{Count: counts.StaleZeroQuantities, Items: make([]DataQualityItem, 0, len(staleZero))},
+		BrokenFileReferences:    broken,
+		ZeroQuantityMonths:      months,
+		GeneratedAt:             now.Format(time.RFC3339),
+	}
+	for _, row := range withoutPhotos {
+		report.ItemsWithoutPhotos.Items = append(report.ItemsWithoutPhotos.Items, toDataQualityItem(sqlcdb.ListStaleZeroQuantitiesRow(row)))
+	}
+	for _, row := range withoutContact {
+		report.LocationsWithoutContact.Items = append(report.LocationsWithoutContact.Items, DataQualityLocation{
+			ID:      row.ID,
+			Region:  string(row.Region),
+			Regency: row.Regency,
+			Cluster: row.Cluster,
+		})
+	}
+	for _, row := range duplicates {
+		report.DuplicateMasterNames.Items = append(report.DuplicateMasterNames.Items, DuplicateMasterName{
+			NormalizedName: row.NormalizedName,
+			IDs:            row.Ids,
+			Names:          row.Names,
+		})
+	}
+	for _, row := range staleZero {
+		report.StaleZeroQuantities.Items = append(report.StaleZeroQuantities.Items, toDataQualityItem(row))
+	}
+
+	utils.Success(c, "Data quality report generated successfully", report)
+}
+
+// findBrokenFileReferences checks every stored photo against the uploads directory; all
+// missing files are counted, the first limit are listed
+func (h *DataQualityHandler) findBrokenFileReferences(c *gin.Context, limit int) (DataQualityCheck[BrokenFileReference], error) {
+	check := DataQualityCheck[BrokenFileReference]{Items: []BrokenFileReference{}}
+
+	rows, err := h.queries.ListItemDocumentation(c.Request.Context())
+	if err != nil {
+		return check, err
+	}
+
+	for _, row := range rows {
+		var docs []utils.Photo
+		if err := json.Unmarshal(row.Documentation, &docs); err != nil {
+			utils.RequestLogger(c.Request.Context(), h.logger).Warn("Skipping unreadable documentation",
+				zap.String("item_type", row.ItemType),
+				zap.Int32("id", row.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		for index, photo := range docs {
+			path := photo.URL
+			exists, err := h.uploads.Exists(c.Request.Context(), path)
+			if err != nil {
+				return check, err
+			}
+			if exists {
+				continue
+			}
+			check.Count++
+			if len(check.Items) < limit {
+				check.Items = append(check.Items, BrokenFileReference{
+					ItemType:   row.ItemType,
+					ID:         row.ID,
+					LocationID: row.LocationID,
+					PhotoIndex: index,
+					Path:       path,
+				})
+			}
+		}
+	}
+	return check, nil
+}
+
+func toDataQualityItem(row sqlcdb.ListStaleZeroQuantitiesRow) DataQualityItem {
+	item := DataQualityItem{
+		ItemType:   row.ItemType,
+		ID:         row.ID,
+		LocationID: row.LocationID,
+		Region:     string(row.Region),
+		Regency:    row.Regency,
+		Cluster:    row.Cluster,
+		Name:       row.Name,
+		Quantity:   row.Quantity,
+		UpdatedAt:  utils.FormatTimestamp(row.UpdatedAt),
+	}
+	if row.StockType.Valid {
+		item.StockType = &row.StockType.String
+	}
+	return item
+}
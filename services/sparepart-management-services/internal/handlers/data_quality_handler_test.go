@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestDataQualityHandlerGetReport(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+	if err := os.MkdirAll(filepath.Join(dir, "tools_alker"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tools_alker", "present.jpg"), []byte("jpg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDataQualityRepository(ctrl)
+	h := NewDataQualityHandler(repo, uploader, testLogger)
+
+	repo.EXPECT().GetDataQualityCounts(gomock.Any(), gomock.Any()).
+		Return(sqlcdb.GetDataQualityCountsRow{ItemsWithoutPhotos: 3, LocationsWithoutContact: 1, DuplicateMasterNames: 1, StaleZeroQuantities: 0}, nil)
+	repo.EXPECT().ListItemsWithoutPhotos(gomock.Any(), int32(2)).
+		Return([]sqlcdb.ListItemsWithoutPhotosRow{
+			{ItemType: "SPAREPART", ID: 4, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo", Name: "BMS"},
+			{ItemType: "TOOLS_ALKER", ID: 7, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo", Name: "Tang"},
+		}, nil)
+	repo.EXPECT().ListLocationsWithoutContact(gomock.Any(), int32(2)).
+		Return([]sqlcdb.ListLocationsWithoutContactRow{{ID: 9, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Sentani"}}, nil)
+	repo.EXPECT().ListDuplicateMasterNames(gomock.Any(), int32(2)).
+		Return([]sqlcdb.ListDuplicateMasterNamesRow{{NormalizedName: "bms", Ids: []int32{1, 12}, Names: []string{"BMS", "bms "}}}, nil)
+	repo.EXPECT().ListStaleZeroQuantities(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListStaleZeroQuantitiesRow{}, nil)
+	repo.EXPECT().ListItemDocumentation(gomock.Any()).
+		Return([]sqlcdb.ListItemDocumentationRow{
+			{ItemType: "TOOLS_ALKER", ID: 2, LocationID: 5, Documentation: []byte(`["/uploads/tools_alker/present.jpg","/uploads/tools_alker/missing.jpg"]`)},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/admin/data-quality", h.GetReport, "/admin/data-quality?limit=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var report DataQualityReport
+	decodeResponse(t, w, &report)
+	if report.ItemsWithoutPhotos.Count != 3 || len(report.ItemsWithoutPhotos.Items) != 2 || report.ItemsWithoutPhotos.Items[1].StockType != nil {
+		t.Fatalf("unexpected items without photos: %+v", report.ItemsWithoutPhotos)
+	}
+	if len(report.DuplicateMasterNames.Items) != 1 || len(report.DuplicateMasterNames.Items[0].IDs) != 2 {
+		t.Fatalf("unexpected duplicate master names: %+v", report.DuplicateMasterNames)
+	}
+	broken := report.BrokenFileReferences
+	if broken.Count != 1 || len(broken.Items) != 1 || broken.Items[0].PhotoIndex != 1 || broken.Items[0].Path != "/uploads/tools_alker/missing.jpg" {
+		t.Fatalf("unexpected broken file references: %+v", broken)
+	}
+	if report.ZeroQuantityMonths != defaultZeroQuantityMonths {
+		t.Fatalf("expected default zero quantity months, got %d", report.ZeroQuantityMonths)
+	}
+}
+
+func TestDataQualityHandlerGetReportRejectsInvalidMonths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDataQualityRepository(ctrl)
+	h := NewDataQualityHandler(repo, nil, testLogger)
+
+	w := performRequest(http.MethodGet, "/admin/data-quality", h.GetReport, "/admin/data-quality?zero_quantity_months=0", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/exports"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExportJobResponse describes a background export; DownloadURL is set once the job has
+// completed, until the report link expires
+type ExportJobResponse struct {
+	ID          int64             `json:"id"`
+	Entity      string            `json:"entity"`
+	Format      string            `json:"format"`
+	Filters     map[string]string `json:"filters"`
+	Status      string            `json:"status"`
+	Filename    *string           `json:"filename"`
+	RowCount    *int32            `json:"row_count"`
+	Error       *string           `json:"error"`
+	DownloadURL string            `json:"download_url,omitempty"`
+	ExpiresAt   string            `json:"expires_at,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+	StartedAt   string            `json:"started_at,omitempty"`
+	FinishedAt  string            `json:"finished_at,omitempty"`
+}
+
+// ExportJobHandler queues exports for the export worker and reports their status to the
+// user who queued them
+type ExportJobHandler struct {
+	logger  *zap.Logger
+	queries repository.ExportJobRepository
+	reports *reports.Store
+}
+
+func NewExportJobHandler(queries repository.ExportJobRepository, reports *reports.Store, logger *zap.Logger) *ExportJobHandler {
+	return &ExportJobHandler{
+		logger:  logger,
+		queries: queries,
+		reports: reports,
+	}
+}
+
+// @Summary Queue sparepart stock export
+// @Description Queue a sparepart stock export to be generated in the background, with the filters of the synchronous exports; poll GET /sparepart/exports/{id} for its status and download link
+// @Tags Export Job
+// @Accept json
+// @Produce json
+// @Param format query string true "Export format" Enums(pdf, excel, csv)
+// @Param sparepart_name query string false "Filter by sparepart name (comma-separated)"
+// @Param region query string false "Filter by region"
+// @Param regency query string false "Filter by regency"
+// @Param cluster query string false "Filter by cluster"
+// @Param stock_type query string false "Filter by stock type"
+// @Param include_photos query bool false "Append the thumbnails of every item's photos (PDF only)"
+// @Success 202 {object} utils.Response
+// @Router /sparepart/stock/export [post]
+func (h *ExportJobHandler) CreateStockExport(c *gin.Context) {
+	h.create(c, exports.EntitySparepartStock)
+}
+
+// create queues an export of entity in the requested format, recording the other query
+// parameters as its filters
+func (h *ExportJobHandler) create(c *gin.Context, entity string) {
+	ctx := c.Request.Context()
+
+	format := strings.ToUpper(c.Query("format"))
+	switch format {
+	case exports.FormatPDF, exports.FormatExcel, exports.FormatCSV:
+	default:
+		utils.ValidationError(c, utils.FieldError{Field: "format", Message: "must be one of pdf, excel, csv"})
+		return
+	}
+
+	filters := make(map[string]string)
+	for key, values := range c.Request.URL.Query() {
+		if key == "format" || key == "store" {
+			continue
+		}
+		filters[key] = strings.Join(values, ",")
+	}
+	encoded, err := json.Marshal(filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to queue export", h.logger)
+		return
+	}
+
+	job, err := h.queries.CreateExportJob(ctx, sqlcdb.CreateExportJobParams{
+		UserID:  utils.UserID(c),
+		Entity:  entity,
+		Format:  format,
+		Filters: encoded,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to queue export", h.logger)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.Response{
+		Success: true,
+		Message: "Export queued successfully",
+		Data:    toExportJobResponse(job, h.reports),
+	})
+}
+
+// @Summary Get export job
+// @Description Get the status of a background export queued by the requesting user; a completed job has a download link valid for REPORT_LINK_TTL_MINUTES after it finished
+// @Tags Export Job
+// @Accept json
+// @Produce json
+// @Param id path int true "Export job ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/exports/{id} [get]
+func (h *ExportJobHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "Invalid export job ID")
+		return
+	}
+
+	job, err := h.queries.GetExportJob(ctx, sqlcdb.GetExportJobParams{ID: id, UserID: utils.UserID(c)})
+	if err != nil {
+		utils.NotFound(c, "Export job not found")
+		return
+	}
+
+	utils.Success(c, "Export job retrieved successfully", toExportJobResponse(job, h.reports))
+}
+
+func toExportJobResponse(job sqlcdb.ExportJob, store *reports.Store) ExportJobResponse {
+	response := ExportJobResponse{
+		ID:         job.ID,
+		Entity:     job.Entity,
+		Format:     job.Format,
+		Filters:    map[string]string{},
+		Status:     job.Status,
+		CreatedAt:  utils.FormatTimestamp(job.CreatedAt),
+		StartedAt:  utils.FormatTimestamp(job.StartedAt),
+		FinishedAt: utils.FormatTimestamp(job.FinishedAt),
+	}
+	if len(job.Filters) > 0 {
+		_ = json.Unmarshal(job.Filters, &response.Filters)
+	}
+	if job.Filename.Valid {
+		response.Filename = &job.Filename.String
+	}
+	if job.RowCount.Valid {
+		response.RowCount = &job.RowCount.Int32
+	}
+	if job.Error.Valid {
+		response.Error = &job.Error.String
+	}
+
+	// The stored file is removed with the expired reports, so the link ends with it
+	if job.Status == exports.StatusCompleted && job.StoredName.Valid && job.FinishedAt.Valid {
+		url, expiresAt := store.Link(job.StoredName.String, job.FinishedAt.Time)
+		response.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+		if time.Now().Before(expiresAt) {
+			response.DownloadURL = url
+		}
+	}
+	return response
+}
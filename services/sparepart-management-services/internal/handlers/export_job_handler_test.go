@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/exports"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportJobHandlerCreateStockExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockExportJobRepository(ctrl)
+	h := NewExportJobHandler(repo, nil, testLogger)
+
+	repo.EXPECT().CreateExportJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg sqlcdb.CreateExportJobParams) (sqlcdb.ExportJob, error) {
+			var filters map[string]string
+			if err := json.Unmarshal(arg.Filters, &filters); err != nil {
+				t.Fatalf("invalid filters: %v", err)
+			}
+			if arg.UserID != "budi" || arg.Entity != exports.EntitySparepartStock || arg.Format != exports.FormatExcel ||
+				len(filters) != 1 || filters["region"] != "MALUKU" {
+				t.Fatalf("unexpected job: %+v %v", arg, filters)
+			}
+			return sqlcdb.ExportJob{ID: 3, UserID: arg.UserID, Entity: arg.Entity, Format: arg.Format, Filters: arg.Filters, Status: exports.StatusPending}, nil
+		})
+
+	w := performRequestAs("budi", http.MethodPost, "/stock/export", h.CreateStockExport, "/stock/export?format=excel&region=MALUKU&store=true", "")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var job ExportJobResponse
+	decodeResponse(t, w, &job)
+	if job.ID != 3 || job.Status != exports.StatusPending || job.DownloadURL != "" {
+		t.Fatalf("unexpected job response: %+v", job)
+	}
+}
+
+func TestExportJobHandlerCreateStockExportRequiresFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockExportJobRepository(ctrl)
+	h := NewExportJobHandler(repo, nil, testLogger)
+
+	for _, target := range []string{"/stock/export", "/stock/export?format=docx"} {
+		w := performRequestAs("budi", http.MethodPost, "/stock/export", h.CreateStockExport, target, "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", target, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestExportJobHandlerGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockExportJobRepository(ctrl)
+	store, err := reports.New(config.ReportConfig{LinkSecret: "test-secret", LinkTTL: time.Hour}, nil, "/api/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewExportJobHandler(repo, store, testLogger)
+
+	finishedAt := pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+	repo.EXPECT().GetExportJob(gomock.Any(), sqlcdb.GetExportJobParams{ID: 3, UserID: "budi"}).Return(sqlcdb.ExportJob{
+		ID: 3, UserID: "budi", Entity: exports.EntitySparepartStock, Format: exports.FormatPDF, Status: exports.StatusCompleted,
+		Filename:   pgtype.Text{String: "sparepart_stock.pdf", Valid: true},
+		StoredName: pgtype.Text{String: "abcd_sparepart_stock.pdf", Valid: true},
+		RowCount:   pgtype.Int4{Int32: 12, Valid: true},
+		FinishedAt: finishedAt,
+	}, nil)
+	repo.EXPECT().GetExportJob(gomock.Any(), sqlcdb.GetExportJobParams{ID: 4, UserID: "budi"}).Return(sqlcdb.ExportJob{}, pgx.ErrNoRows)
+
+	w := performRequestAs("budi", http.MethodGet, "/exports/:id", h.GetByID, "/exports/3", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var job ExportJobResponse
+	decodeResponse(t, w, &job)
+	if !strings.HasPrefix(job.DownloadURL, "/api/v1/sparepart/reports/") || job.RowCount == nil || *job.RowCount != 12 {
+		t.Fatalf("unexpected job response: %+v", job)
+	}
+
+	// Another user's job, or a missing one, is not found
+	w = performRequestAs("budi", http.MethodGet, "/exports/:id", h.GetByID, "/exports/4", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
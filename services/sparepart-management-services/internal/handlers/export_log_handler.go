@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// ExportLogResponse is one finished export
+type ExportLogResponse struct {
+	ID         int64             `json:"id"`
+	UserID     *string           `json:"user_id"`
+	Entity     string            `json:"entity"`
+	Format     string            `json:"format"`
+	Filters    map[string]string `json:"filters"`
+	RowCount   int32             `json:"row_count"`
+	DurationMs int32             `json:"duration_ms"`
+	Stored     bool              `json:"stored"`
+	CreatedAt  string            `json:"created_at"`
+}
+
+// ExportLogHandler serves the export log written by middleware.RecordExport
+type ExportLogHandler struct {
+	logger  *zap.Logger
+	queries repository.ExportLogRepository
+	reports *reports.Store
+}
+
+func NewExportLogHandler(queries repository.ExportLogRepository, reports *reports.Store, logger *zap.Logger) *ExportLogHandler {
+	return &ExportLogHandler{
+		logger:  logger,
+		queries: queries,
+		reports: reports,
+	}
+}
+
+// @Summary Get export log
+// @Description Get the recorded exports (user, entity, filters, format, row count, duration), newest first
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param user_id query string false "Filter by user"
+// @Param entity query string false "Filter by entity (SPAREPART_STOCK, STOCK_LABELS, TOOLS_ALKER, EXPORT_LOG, DAMAGE_REPORT, STOCK_VALUATION)"
+// @Param format query string false "Filter by format (PDF, EXCEL, CSV)"
+// @Param from query string false "Exports on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Exports on or before this date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/admin/export-log [get]
+func (h *ExportLogHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters, errs := parseExportLogFilters(c)
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountExportLogs(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count export log", h.logger)
+		return
+	}
+
+	logs, err := h.queries.ListExportLogs(ctx, sqlcdb.ListExportLogsParams{
+		UserID: filters.UserID,
+		Entity: filters.Entity,
+		Format: filters.Format,
+		Since:  filters.Since,
+		Until:  filters.Until,
+		Limit:  int32(pagination.Limit),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get export log", h.logger)
+		return
+	}
+
+	response := make([]ExportLogResponse, 0, len(logs))
+	for _, log := range logs {
+		response = append(response, toExportLogResponse(log))
+	}
+
+	utils.SuccessWithPagination(c, "Export log retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Export export log to Excel
+// @Description Export the recorded exports to Excel with the same filters as the listing
+// @Tags Admin
+// @Accept json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param user_id query string false "Filter by user"
+// @Param entity query string false "Filter by entity"
+// @Param format query string false "Filter by format"
+// @Param from query string false "Exports on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Exports on or before this date (YYYY-MM-DD)"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
+// @Success 200 {file} application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Router /sparepart/admin/export-log/export/excel [get]
+func (h *ExportLogHandler) ExportExcel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters, errs := parseExportLogFilters(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	var rows int
+	buf, err := utils.ExportLogToExcel(utils.CountRows(h.exportReader(ctx, filters), &rows), h.logger)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to generate Excel", h.logger)
+		return
+	}
+
+	c.Set(utils.ExportRowsKey, rows)
+	filename := fmt.Sprintf("export_log_%s.xlsx", time.Now().UTC().Format("20060102_150405"))
+	sendExport(c, h.reports, buf.Bytes(), filename, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", h.logger)
+}
+
+// exportReader pages through the export log newest first with a keyset on the ID
+func (h *ExportLogHandler) exportReader(ctx context.Context, filters sqlcdb.CountExportLogsParams) utils.BatchReader[sqlcdb.ExportLog] {
+	params := sqlcdb.ListExportLogsForExportParams{
+		UserID: filters.UserID,
+		Entity: filters.Entity,
+		Format: filters.Format,
+		Since:  filters.Since,
+		Until:  filters.Until,
+		Limit:  exportBatchSize,
+	}
+	return func() ([]sqlcdb.ExportLog, error) {
+		rows, err := h.queries.ListExportLogsForExport(ctx, params)
+		if err != nil || len(rows) == 0 {
+			return rows, err
+		}
+		params.AfterID = pgtype.Int8{Int64: rows[len(rows)-1].ID, Valid: true}
+		return rows, nil
+	}
+}
+
+// parseExportLogFilters reads the export log filters; to is inclusive of the whole day
+func parseExportLogFilters(c *gin.Context) (sqlcdb.CountExportLogsParams, []utils.FieldError) {
+	var errs []utils.FieldError
+	filters := sqlcdb.CountExportLogsParams{
+		UserID: utils.TextFilter(c.Query("user_id")),
+		Entity: utils.TextFilter(c.Query("entity")),
+		Format: utils.TextFilter(c.Query("format")),
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "from", Message: "must be a date (YYYY-MM-DD)"})
+		} else {
+			filters.Since = pgtype.Timestamptz{Time: from, Valid: true}
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "to", Message: "must be a date (YYYY-MM-DD)"})
+		} else {
+			filters.Until = pgtype.Timestamptz{Time: to.AddDate(0, 0, 1), Valid: true}
+		}
+	}
+
+	if len(errs) == 0 && filters.Since.Valid && filters.Until.Valid && !filters.Since.Time.Before(filters.Until.Time) {
+		errs = append(errs, utils.FieldError{Field: "from", Message: "must not be after to"})
+	}
+	return filters, errs
+}
+
+func toExportLogResponse(log sqlcdb.ExportLog) ExportLogResponse {
+	response := ExportLogResponse{
+		ID:         log.ID,
+		Entity:     log.Entity,
+		Format:     log.Format,
+		Filters:    map[string]string{},
+		RowCount:   log.RowCount,
+		DurationMs: log.DurationMs,
+		Stored:     log.Stored,
+		CreatedAt:  utils.FormatTimestamp(log.CreatedAt),
+	}
+	if log.UserID.Valid {
+		response.UserID = &log.UserID.String
+	}
+	if len(log.Filters) > 0 {
+		_ = json.Unmarshal(log.Filters, &response.Filters)
+	}
+	return response
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportLogHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockExportLogRepository(ctrl)
+	h := NewExportLogHandler(repo, nil, testLogger)
+
+	filters := sqlcdb.CountExportLogsParams{
+		Entity: pgtype.Text{String: "SPAREPART_STOCK", Valid: true},
+		Since:  pgtype.Timestamptz{Time: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		Until:  pgtype.Timestamptz{Time: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+	repo.EXPECT().CountExportLogs(gomock.Any(), filters).Return(int64(1), nil)
+	repo.EXPECT().
+		ListExportLogs(gomock.Any(), sqlcdb.ListExportLogsParams{Entity: filters.Entity, Since: filters.Since, Until: filters.Until, Limit: 10}).
+		Return([]sqlcdb.ExportLog{{
+			ID: 3, UserID: pgtype.Text{String: "user-7", Valid: true}, Entity: "SPAREPART_STOCK", Format: "EXCEL",
+			Filters: []byte(`{"region":"MALUKU"}`), RowCount: 120, DurationMs: 840,
+		}}, nil)
+
+	w := performRequest(http.MethodGet, "/admin/export-log", h.GetAll, "/admin/export-log?entity=SPAREPART_STOCK&from=2026-10-01&to=2026-10-15", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var logs []ExportLogResponse
+	decodeResponse(t, w, &logs)
+	if len(logs) != 1 || logs[0].UserID == nil || *logs[0].UserID != "user-7" || logs[0].Filters["region"] != "MALUKU" || logs[0].RowCount != 120 {
+		t.Fatalf("unexpected export log: %+v", logs)
+	}
+}
+
+func TestExportLogHandlerGetAllRejectsInvalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockExportLogRepository(ctrl)
+	h := NewExportLogHandler(repo, nil, testLogger)
+
+	w := performRequest(http.MethodGet, "/admin/export-log", h.GetAll, "/admin/export-log?from=2026-10-15&to=2026-10-01", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "from" {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+}
+
+func TestExportLogHandlerExportExcelReadsInBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockExportLogRepository(ctrl)
+	h := NewExportLogHandler(repo, nil, testLogger)
+
+	format := pgtype.Text{String: "PDF", Valid: true}
+	gomock.InOrder(
+		repo.EXPECT().
+			ListExportLogsForExport(gomock.Any(), sqlcdb.ListExportLogsForExportParams{Format: format, Limit: exportBatchSize}).
+			Return([]sqlcdb.ExportLog{{ID: 9, Entity: "TOOLS_ALKER", Format: "PDF"}, {ID: 5, Entity: "STOCK_LABELS", Format: "PDF"}}, nil),
+		repo.EXPECT().
+			ListExportLogsForExport(gomock.Any(), sqlcdb.ListExportLogsForExportParams{Format: format, AfterID: pgtype.Int8{Int64: 5, Valid: true}, Limit: exportBatchSize}).
+			Return([]sqlcdb.ExportLog{}, nil),
+	)
+
+	w := performRequest(http.MethodGet, "/admin/export-log/export/excel", h.ExportExcel, "/admin/export-log/export/excel?format=PDF", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FilterValuesResponse lists the values present in the data for each filter of the stock
+// and tools alker listings, sorted
+type FilterValuesResponse struct {
+	Regions        []string `json:"regions"`
+	Regencies      []string `json:"regencies"`
+	Clusters       []string `json:"clusters"`
+	SparepartNames []string `json:"sparepart_names"`
+	ToolsNames     []string `json:"tools_names"`
+}
+
+type FilterValueHandler struct {
+	logger  *zap.Logger
+	queries repository.FilterValueRepository
+}
+
+func NewFilterValueHandler(queries repository.FilterValueRepository, logger *zap.Logger) *FilterValueHandler {
+	return &FilterValueHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get filter values
+// @Description Get the distinct regions, regencies, clusters, sparepart names (of stock items) and tools names (of tools alker items) currently present in the data, to populate the filter dropdowns. Deleted locations and stock items are left out.
+// @Tags Filters
+// @Accept json
+// @Produce json
+// @Param region query string false "Only list the regencies and clusters of this region"
+// @Success 200 {object} utils.Response{data=FilterValuesResponse}
+// @Router /sparepart/filters [get]
+func (h *FilterValueHandler) GetAll(c *gin.Context) {
+	rows, err := h.queries.ListFilterValues(c.Request.Context(), utils.TextFilter(c.Query("region")))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get filter values", h.logger)
+		return
+	}
+
+	response := FilterValuesResponse{
+		Regions:        []string{},
+		Regencies:      []string{},
+		Clusters:       []string{},
+		SparepartNames: []string{},
+		ToolsNames:     []string{},
+	}
+	for _, row := range rows {
+		switch row.Field {
+		case "region":
+			response.Regions = append(response.Regions, row.Value)
+		case "regency":
+			response.Regencies = append(response.Regencies, row.Value)
+		case "cluster":
+			response.Clusters = append(response.Clusters, row.Value)
+		case "sparepart_name":
+			response.SparepartNames = append(response.SparepartNames, row.Value)
+		case "tools_name":
+			response.ToolsNames = append(response.ToolsNames, row.Value)
+		}
+	}
+
+	utils.Success(c, "Filter values retrieved successfully", response)
+}
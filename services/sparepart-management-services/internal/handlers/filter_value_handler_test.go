@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFilterValueHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockFilterValueRepository(ctrl)
+	h := NewFilterValueHandler(repo, testLogger)
+
+	repo.EXPECT().
+		ListFilterValues(gomock.Any(), pgtype.Text{String: "MALUKU", Valid: true}).
+		Return([]sqlcdb.ListFilterValuesRow{
+			{Field: "cluster", Value: "Nusaniwe"},
+			{Field: "regency", Value: "Ambon"},
+			{Field: "region", Value: "MALUKU"},
+			{Field: "region", Value: "PAPUA"},
+			{Field: "sparepart_name", Value: "Baterai 12V"},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/filters", h.GetAll, "/filters?region=%20MALUKU%20", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var values FilterValuesResponse
+	decodeResponse(t, w, &values)
+	if len(values.Regions) != 2 || values.Regions[1] != "PAPUA" {
+		t.Fatalf("unexpected regions: %v", values.Regions)
+	}
+	if len(values.Regencies) != 1 || values.Regencies[0] != "Ambon" || len(values.Clusters) != 1 || values.Clusters[0] != "Nusaniwe" {
+		t.Fatalf("unexpected regencies %v or clusters %v", values.Regencies, values.Clusters)
+	}
+	if len(values.SparepartNames) != 1 || values.SparepartNames[0] != "Baterai 12V" {
+		t.Fatalf("unexpected sparepart names: %v", values.SparepartNames)
+	}
+	if values.ToolsNames == nil || len(values.ToolsNames) != 0 {
+		t.Fatalf("expected empty tools names, got %v", values.ToolsNames)
+	}
+}
+
+func TestFilterValueHandlerGetAllError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockFilterValueRepository(ctrl)
+	h := NewFilterValueHandler(repo, testLogger)
+
+	repo.EXPECT().
+		ListFilterValues(gomock.Any(), pgtype.Text{}).
+		Return(nil, errors.New("connection refused"))
+
+	w := performRequest(http.MethodGet, "/filters", h.GetAll, "/filters", "")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+}
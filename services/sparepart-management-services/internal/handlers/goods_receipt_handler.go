@@ -0,0 +1,676 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Goods receipt statuses; a receipt moves from DRAFT to CONFIRMED
+const (
+	receiptStatusDraft     = "DRAFT"
+	receiptStatusConfirmed = "CONFIRMED"
+)
+
+// Packing list photos are stored under uploads/receipt
+const (
+	receiptPhotoSubDir = "receipt"
+	receiptPhotoPrefix = "goods_receipt"
+)
+
+// Errors ending a goods receipt transaction early; the response is written after the rollback
+var (
+	errReceiptNotFound = errors.New("goods receipt not found")
+	errReceiptStatus   = errors.New("goods receipt has the wrong status")
+	errReceiptInvalid  = errors.New("goods receipt is invalid")
+)
+
+// GoodsReceiptItemRequest is a quantity of one sparepart and stock type in a shipment
+type GoodsReceiptItemRequest struct {
+	SparepartID int              `json:"sparepart_id" binding:"required,min=1"`
+	StockType   models.StockType `json:"stock_type" binding:"required,oneof=NEW_STOCK USED_STOCK"`
+	Quantity    int              `json:"quantity" binding:"required,min=1"`
+	// Cost price of one unit of the shipment; confirming averages it into the stock item's cost
+	UnitCost *float64 `json:"unit_cost,omitempty" binding:"omitempty,min=0,max=999999999999.99"`
+}
+
+// CreateGoodsReceiptRequest registers an incoming shipment at a location. It is sent as
+// multipart form fields, with the items as a JSON array in the items field.
+type CreateGoodsReceiptRequest struct {
+	SupplierID          int                       `json:"supplier_id" binding:"required,min=1"`
+	DeliveryOrderNumber string                    `json:"delivery_order_number" binding:"required,max=100"`
+	LocationID          int                       `json:"location_id" binding:"required,min=1"`
+	PurchaseOrderID     *int                      `json:"purchase_order_id" binding:"omitempty,min=1"`
+	Notes               *string                   `json:"notes"`
+	Items               []GoodsReceiptItemRequest `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+// GoodsReceiptItemResponse is a received item; StockItemID and QuantityAfter are set once the
+// receipt is confirmed
+type GoodsReceiptItemResponse struct {
+	ID            int32    `json:"id"`
+	SparepartID   int32    `json:"sparepart_id"`
+	SparepartName *string  `json:"sparepart_name,omitempty"`
+	StockType     string   `json:"stock_type"`
+	Quantity      int32    `json:"quantity"`
+	StockItemID   *int32   `json:"stock_item_id,omitempty"`
+	QuantityAfter *int32   `json:"quantity_after,omitempty"`
+	UnitCost      *float64 `json:"unit_cost,omitempty"`
+	// The purchase order line the item was ordered on, when the receipt is for an order
+	PurchaseOrderItemID *int32 `json:"purchase_order_item_id,omitempty"`
+}
+
+// GoodsReceiptResponse is a goods receipt
+type GoodsReceiptResponse struct {
+	ID                  int32                `json:"id"`
+	Code                string               `json:"code"`
+	SupplierID          int32                `json:"supplier_id"`
+	Supplier            string               `json:"supplier"`
+	DeliveryOrderNumber string               `json:"delivery_order_number"`
+	LocationID          int32                `json:"location_id"`
+	PurchaseOrderID     *int32               `json:"purchase_order_id,omitempty"`
+	Region              *string              `json:"region,omitempty"`
+	Regency             *string              `json:"regency,omitempty"`
+	Cluster             *string              `json:"cluster,omitempty"`
+	Status              string               `json:"status"`
+	PackingListPhotos   []DocumentationPhoto `json:"packing_list_photos"`
+	Notes               *string              `json:"notes"`
+	CreatedBy           *string              `json:"created_by"`
+	ConfirmedBy         *string              `json:"confirmed_by"`
+	ConfirmedAt         string               `json:"confirmed_at,omitempty"`
+	CreatedAt           string               `json:"created_at"`
+	UpdatedAt           string               `json:"updated_at"`
+}
+
+// GoodsReceiptDetailResponse is a goods receipt with its items
+type GoodsReceiptDetailResponse struct {
+	GoodsReceiptResponse
+	Items []GoodsReceiptItemResponse `json:"items"`
+}
+
+// GoodsReceiptHandler registers incoming shipments; confirming a receipt adds its items to the
+// receiving location's stock
+type GoodsReceiptHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+	uploads *uploads.Service
+}
+
+func NewGoodsReceiptHandler(queries repository.SparepartStockRepository, uploads *uploads.Service, logger *zap.Logger) *GoodsReceiptHandler {
+	return &GoodsReceiptHandler{
+		logger:  logger,
+		queries: queries,
+		uploads: uploads,
+	}
+}
+
+// @Summary Create goods receipt
+// @Description Register an incoming shipment as a DRAFT receipt: the supplier, its delivery order (DO) number, the receiving location, the items and photos of the packing list. A supplier's DO number can be registered once. The stock does not change until the receipt is confirmed. A shipment for a purchase order names it; the order must be ORDERED or PARTIAL and from the same supplier, and every item must be on one of its lines.
+// @Tags Goods Receipt
+// @Accept multipart/form-data
+// @Produce json
+// @Param supplier_id formData int true "Supplier ID"
+// @Param delivery_order_number formData string true "Delivery order (DO) number"
+// @Param location_id formData int true "Receiving location ID"
+// @Param purchase_order_id formData int false "Purchase order the shipment delivers"
+// @Param notes formData string false "Notes"
+// @Param items formData string true "Items as a JSON array of {sparepart_id, stock_type, quantity, unit_cost}; unit_cost, the cost price of one unit, is optional"
+// @Param photos formData file false "Packing list photos (multiple files allowed)"
+// @Param captions formData []string false "Caption of each photo, in the order of the photos"
+// @Param taken_at formData []string false "When each photo was taken (RFC3339), in the order of the photos"
+// @Success 201 {object} utils.Response{data=GoodsReceiptDetailResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/receipts [post]
+func (h *GoodsReceiptHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req, ok := bindGoodsReceiptForm(c)
+	if !ok {
+		return
+	}
+
+	var errs []utils.FieldError
+	seen := make(map[string]int, len(req.Items))
+	for i, item := range req.Items {
+		key := receiptItemKey(item.SparepartID, string(item.StockType))
+		if first, ok := seen[key]; ok {
+			errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d]", i), Message: fmt.Sprintf("duplicates items[%d]", first)})
+			continue
+		}
+		seen[key] = i
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	// Stage the photos; they are only moved into place once the receipt is created
+	photos, staged, ok := stagePhotos(c, h.uploads, receiptPhotoSubDir, receiptPhotoPrefix, h.logger)
+	if !ok {
+		return
+	}
+
+	var id int32
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		var orderID pgtype.Int4
+		var orderLines map[string]pgtype.Int4
+		if req.PurchaseOrderID != nil {
+			orderID = pgtype.Int4{Int32: int32(*req.PurchaseOrderID), Valid: true}
+			var err error
+			orderLines, err = purchaseOrderLines(ctx, repo, orderID.Int32, req, &errs)
+			if err != nil {
+				return err
+			}
+		}
+
+		receipt, err := repo.CreateGoodsReceipt(ctx, sqlcdb.CreateGoodsReceiptParams{
+			SupplierID:          int32(req.SupplierID),
+			DeliveryOrderNumber: req.DeliveryOrderNumber,
+			PackingListPhotos:   utils.PhotosJSON(photos),
+			Notes:               utils.OptionalText(req.Notes),
+			CreatedBy:           utils.TextFilter(utils.UserID(c)),
+			PurchaseOrderID:     orderID,
+			LocationID:          int32(req.LocationID),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			errs = append(errs, utils.FieldError{Field: "location_id", Message: "does not exist"})
+			return errReceiptInvalid
+		}
+		if err != nil {
+			return err
+		}
+		id = receipt.ID
+
+		for i, item := range req.Items {
+			_, err := repo.CreateGoodsReceiptItem(ctx, sqlcdb.CreateGoodsReceiptItemParams{
+				ReceiptID:           receipt.ID,
+				StockType:           sqlcdb.StockType(item.StockType),
+				Quantity:            int32(item.Quantity),
+				PurchaseOrderItemID: orderLines[receiptItemKey(item.SparepartID, string(item.StockType))],
+				UnitCost:            utils.OptionalNumeric(item.UnitCost),
+				SparepartID:         int32(item.SparepartID),
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d].sparepart_id", i), Message: "does not exist"})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if len(errs) > 0 {
+			return errReceiptInvalid
+		}
+
+		// Move photos into place before commit, a failed move rolls back the receipt
+		return h.uploads.CommitStaged(ctx, staged, h.logger)
+	})
+	if err != nil {
+		h.uploads.DiscardStaged(staged, h.logger)
+	}
+	if !h.handleTransactionError(c, err, "", errs, "", "Failed to create goods receipt") {
+		return
+	}
+
+	h.respond(c, http.StatusCreated, id, "Goods receipt created successfully")
+}
+
+// @Summary Get goods receipts
+// @Description Get the goods receipts, newest first
+// @Tags Goods Receipt
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (DRAFT, CONFIRMED)"
+// @Param location_id query int false "Filter by receiving location"
+// @Param supplier_id query int false "Filter by supplier ID"
+// @Param supplier query string false "Filter by supplier name (partial match, case-insensitive)"
+// @Param delivery_order_number query string false "Filter by delivery order number (exact match)"
+// @Param purchase_order_id query int false "Filter by purchase order"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/receipts [get]
+func (h *GoodsReceiptHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	filters := sqlcdb.CountGoodsReceiptsParams{
+		Supplier:            utils.TextFilter(c.Query("supplier")),
+		DeliveryOrderNumber: utils.TextFilter(c.Query("delivery_order_number")),
+	}
+	switch status := c.Query("status"); status {
+	case "":
+	case receiptStatusDraft, receiptStatusConfirmed:
+		filters.Status = utils.TextFilter(status)
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of DRAFT, CONFIRMED"})
+	}
+	idFilters := []struct {
+		field  string
+		target *pgtype.Int4
+	}{
+		{"location_id", &filters.LocationID},
+		{"supplier_id", &filters.SupplierID},
+		{"purchase_order_id", &filters.PurchaseOrderID},
+	}
+	for _, filter := range idFilters {
+		if value := c.Query(filter.field); value != "" {
+			id, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || id < 1 {
+				errs = append(errs, utils.FieldError{Field: filter.field, Message: "must be a positive integer"})
+				continue
+			}
+			*filter.target = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountGoodsReceipts(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count goods receipts", h.logger)
+		return
+	}
+
+	receipts, err := h.queries.ListGoodsReceipts(ctx, sqlcdb.ListGoodsReceiptsParams{
+		Status:              filters.Status,
+		LocationID:          filters.LocationID,
+		SupplierID:          filters.SupplierID,
+		Supplier:            filters.Supplier,
+		DeliveryOrderNumber: filters.DeliveryOrderNumber,
+		PurchaseOrderID:     filters.PurchaseOrderID,
+		Limit:               int32(pagination.Limit),
+		Offset:              int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get goods receipts", h.logger)
+		return
+	}
+
+	response := make([]GoodsReceiptResponse, 0, len(receipts))
+	for _, receipt := range receipts {
+		response = append(response, toGoodsReceiptResponse(sqlcdb.GetGoodsReceiptRow(receipt)))
+	}
+
+	utils.SuccessWithPagination(c, "Goods receipts retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get goods receipt
+// @Description Get a goods receipt with its items
+// @Tags Goods Receipt
+// @Accept json
+// @Produce json
+// @Param id path int true "Goods receipt ID"
+// @Success 200 {object} utils.Response{data=GoodsReceiptDetailResponse}
+// @Failure 404 {object} utils.Response
+// @Router /sparepart/receipts/{id} [get]
+func (h *GoodsReceiptHandler) GetByID(c *gin.Context) {
+	id, ok := parseReceiptID(c)
+	if !ok {
+		return
+	}
+	h.respond(c, http.StatusOK, id, "Goods receipt retrieved successfully")
+}
+
+// @Summary Confirm goods receipt
+// @Description Confirm a DRAFT receipt, adding every item's quantity to the receiving location's stock (stock items are created when missing, and a deleted one is restored holding only the received quantity); the changes are recorded in the stock ledger. An item received with a unit cost averages it into the stock item's cost, weighted by the quantities held and received. The purchase order of the receipt, if any, becomes RECEIVED once none of its lines is outstanding and PARTIAL before that.
+// @Tags Goods Receipt
+// @Accept json
+// @Produce json
+// @Param id path int true "Goods receipt ID"
+// @Success 200 {object} utils.Response{data=GoodsReceiptDetailResponse}
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/receipts/{id}/confirm [post]
+func (h *GoodsReceiptHandler) Confirm(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseReceiptID(c)
+	if !ok {
+		return
+	}
+
+	var status string
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		receipt, err := repo.GetGoodsReceiptForUpdate(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errReceiptNotFound
+		}
+		if err != nil {
+			return err
+		}
+		status = receipt.Status
+		if receipt.Status != receiptStatusDraft {
+			return errReceiptStatus
+		}
+		// Receipts for the same order are confirmed one at a time, each seeing the others
+		if receipt.PurchaseOrderID.Valid {
+			if _, err := repo.GetPurchaseOrderForUpdate(ctx, receipt.PurchaseOrderID.Int32); err != nil {
+				return err
+			}
+		}
+
+		items, err := repo.ListGoodsReceiptItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			stock, err := repo.AdjustSparepartStock(ctx, sqlcdb.AdjustSparepartStockParams{
+				LocationID:  receipt.LocationID,
+				SparepartID: item.SparepartID,
+				StockType:   item.StockType,
+				Quantity:    item.Quantity,
+			})
+			if err != nil {
+				return err
+			}
+			err = repo.SetGoodsReceiptItemStock(ctx, sqlcdb.SetGoodsReceiptItemStockParams{
+				ID:            item.ID,
+				StockItemID:   pgtype.Int4{Int32: stock.ID, Valid: true},
+				QuantityAfter: pgtype.Int4{Int32: stock.Quantity, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			if item.UnitCost.Valid {
+				err = repo.ApplyGoodsReceiptItemCost(ctx, sqlcdb.ApplyGoodsReceiptItemCostParams{
+					Received: item.Quantity,
+					UnitCost: item.UnitCost,
+					ID:       stock.ID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		_, err = repo.ConfirmGoodsReceipt(ctx, sqlcdb.ConfirmGoodsReceiptParams{
+			ID:          id,
+			ConfirmedBy: utils.TextFilter(utils.UserID(c)),
+		})
+		if err != nil || !receipt.PurchaseOrderID.Valid {
+			return err
+		}
+		return repo.UpdatePurchaseOrderReceivedStatus(ctx, receipt.PurchaseOrderID.Int32)
+	})
+	if !h.handleTransactionError(c, err, status, nil, "Only DRAFT goods receipts can be confirmed", "Failed to confirm goods receipt") {
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, "Goods receipt confirmed successfully")
+}
+
+// @Summary Print goods receipt
+// @Description Download a goods receipt as PDF with the shipment details, its items and lines to sign for delivery and receipt
+// @Tags Goods Receipt
+// @Produce application/pdf
+// @Param id path int true "Goods receipt ID"
+// @Success 200 {file} application/pdf
+// @Failure 404 {object} utils.Response
+// @Router /sparepart/receipts/{id}/pdf [get]
+func (h *GoodsReceiptHandler) PDF(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseReceiptID(c)
+	if !ok {
+		return
+	}
+
+	receipt, err := h.queries.GetGoodsReceipt(ctx, id)
+	if err != nil {
+		utils.NotFound(c, "Goods receipt not found")
+		return
+	}
+	items, err := h.queries.ListGoodsReceiptItems(ctx, id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get goods receipt items", h.logger)
+		return
+	}
+
+	buf, err := utils.ExportGoodsReceiptToPDF(receipt, items, h.logger)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to generate PDF", h.logger)
+		return
+	}
+
+	filename := fmt.Sprintf("goods_receipt_%s.pdf", utils.GoodsReceiptCode(receipt.ID))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// bindGoodsReceiptForm reads the multipart form fields of a new receipt and validates them
+// like a JSON body; it writes the error response and reports false when they are invalid
+func bindGoodsReceiptForm(c *gin.Context) (CreateGoodsReceiptRequest, bool) {
+	req := CreateGoodsReceiptRequest{
+		DeliveryOrderNumber: strings.TrimSpace(c.PostForm("delivery_order_number")),
+	}
+	if notes := strings.TrimSpace(c.PostForm("notes")); notes != "" {
+		req.Notes = &notes
+	}
+
+	var errs []utils.FieldError
+	idFields := []struct {
+		field  string
+		target *int
+	}{
+		{"supplier_id", &req.SupplierID},
+		{"location_id", &req.LocationID},
+	}
+	for _, idField := range idFields {
+		if value := c.PostForm(idField.field); value != "" {
+			id, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				errs = append(errs, utils.FieldError{Field: idField.field, Rule: "type", Message: "must be a number"})
+			}
+			*idField.target = int(id)
+		}
+	}
+	if value := c.PostForm("items"); value != "" {
+		if err := json.Unmarshal([]byte(value), &req.Items); err != nil {
+			errs = append(errs, utils.FieldError{Field: "items", Rule: "type", Message: "must be a JSON array of items"})
+		}
+	}
+	if value := c.PostForm("purchase_order_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "purchase_order_id", Rule: "type", Message: "must be a number"})
+		}
+		orderID := int(id)
+		req.PurchaseOrderID = &orderID
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return req, false
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		utils.BindingError(c, err)
+		return req, false
+	}
+	return req, true
+}
+
+// handleTransactionError writes the response for a goods receipt transaction that failed,
+// reporting whether it succeeded instead
+func (h *GoodsReceiptHandler) handleTransactionError(c *gin.Context, err error, status string, errs []utils.FieldError, statusMessage, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errReceiptNotFound):
+		utils.NotFound(c, "Goods receipt not found")
+	case errors.Is(err, errReceiptStatus):
+		utils.Error(c, fmt.Sprintf("%s; this one is %s", statusMessage, status), http.StatusConflict)
+	case errors.Is(err, errReceiptInvalid):
+		utils.ValidationError(c, errs...)
+	default:
+		utils.HandleError(c, err, message, h.logger)
+	}
+	return false
+}
+
+// respond writes the receipt with its items
+func (h *GoodsReceiptHandler) respond(c *gin.Context, code int, id int32, message string) {
+	ctx := c.Request.Context()
+
+	receipt, err := h.queries.GetGoodsReceipt(ctx, id)
+	if err != nil {
+		utils.NotFound(c, "Goods receipt not found")
+		return
+	}
+
+	items, err := h.queries.ListGoodsReceiptItems(ctx, id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get goods receipt items", h.logger)
+		return
+	}
+
+	response := GoodsReceiptDetailResponse{
+		GoodsReceiptResponse: toGoodsReceiptResponse(receipt),
+		Items:                make([]GoodsReceiptItemResponse, 0, len(items)),
+	}
+	for _, item := range items {
+		response.Items = append(response.Items, toGoodsReceiptItemResponse(item))
+	}
+	c.JSON(code, utils.Response{
+		Success: true,
+		Message: message,
+		Data:    response,
+	})
+}
+
+// purchaseOrderLines locks the purchase order a new receipt is for and maps each received item
+// to the order line it was ordered on. It adds a field error and returns errReceiptInvalid
+// when the order cannot take the receipt or an item is not on it.
+func purchaseOrderLines(ctx context.Context, repo repository.SparepartStockRepository, orderID int32, req CreateGoodsReceiptRequest, errs *[]utils.FieldError) (map[string]pgtype.Int4, error) {
+	order, err := repo.GetPurchaseOrderForUpdate(ctx, orderID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		*errs = append(*errs, utils.FieldError{Field: "purchase_order_id", Message: "does not exist"})
+		return nil, errReceiptInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case order.Status != orderStatusOrdered && order.Status != orderStatusPartial:
+		*errs = append(*errs, utils.FieldError{Field: "purchase_order_id", Message: fmt.Sprintf("must be ORDERED or PARTIAL; this one is %s", order.Status)})
+		return nil, errReceiptInvalid
+	case order.SupplierID != int32(req.SupplierID):
+		*errs = append(*errs, utils.FieldError{Field: "purchase_order_id", Message: "is for another supplier"})
+		return nil, errReceiptInvalid
+	}
+
+	rows, err := repo.ListPurchaseOrderItems(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	lines := make(map[string]pgtype.Int4, len(rows))
+	for _, row := range rows {
+		lines[receiptItemKey(int(row.SparepartID), string(row.StockType))] = pgtype.Int4{Int32: row.ID, Valid: true}
+	}
+	for i, item := range req.Items {
+		if _, ok := lines[receiptItemKey(item.SparepartID, string(item.StockType))]; !ok {
+			*errs = append(*errs, utils.FieldError{Field: fmt.Sprintf("items[%d]", i), Message: "is not on the purchase order"})
+		}
+	}
+	if len(*errs) > 0 {
+		return nil, errReceiptInvalid
+	}
+	return lines, nil
+}
+
+// receiptItemKey identifies the sparepart and stock type of a received or ordered item
+func receiptItemKey(sparepartID int, stockType string) string {
+	return fmt.Sprintf("%d/%s", sparepartID, stockType)
+}
+
+func parseReceiptID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid goods receipt ID")
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func toGoodsReceiptResponse(row sqlcdb.GetGoodsReceiptRow) GoodsReceiptResponse {
+	response := GoodsReceiptResponse{
+		ID:                  row.ID,
+		Code:                utils.GoodsReceiptCode(row.ID),
+		SupplierID:          row.SupplierID,
+		Supplier:            row.SupplierName,
+		DeliveryOrderNumber: row.DeliveryOrderNumber,
+		LocationID:          row.LocationID,
+		Status:              row.Status,
+		PackingListPhotos:   documentationPhotos(row.PackingListPhotos),
+		ConfirmedAt:         utils.FormatTimestamp(row.ConfirmedAt),
+		CreatedAt:           utils.FormatTimestamp(row.CreatedAt),
+		UpdatedAt:           utils.FormatTimestamp(row.UpdatedAt),
+	}
+	if row.PurchaseOrderID.Valid {
+		response.PurchaseOrderID = &row.PurchaseOrderID.Int32
+	}
+	if row.Region.Valid {
+		region := string(row.Region.RegionType)
+		response.Region = &region
+	}
+	if row.Regency.Valid {
+		response.Regency = &row.Regency.String
+	}
+	if row.Cluster.Valid {
+		response.Cluster = &row.Cluster.String
+	}
+	if row.Notes.Valid {
+		response.Notes = &row.Notes.String
+	}
+	if row.CreatedBy.Valid {
+		response.CreatedBy = &row.CreatedBy.String
+	}
+	if row.ConfirmedBy.Valid {
+		response.ConfirmedBy = &row.ConfirmedBy.String
+	}
+	return response
+}
+
+func toGoodsReceiptItemResponse(row sqlcdb.ListGoodsReceiptItemsRow) GoodsReceiptItemResponse {
+	response := GoodsReceiptItemResponse{
+		ID:          row.ID,
+		SparepartID: row.SparepartID,
+		StockType:   string(row.StockType),
+		Quantity:    row.Quantity,
+	}
+	if row.SparepartName.Valid {
+		response.SparepartName = &row.SparepartName.String
+	}
+	if row.StockItemID.Valid {
+		response.StockItemID = &row.StockItemID.Int32
+	}
+	if row.QuantityAfter.Valid {
+		response.QuantityAfter = &row.QuantityAfter.Int32
+	}
+	response.UnitCost = utils.NumericValue(row.UnitCost)
+	if row.PurchaseOrderItemID.Valid {
+		response.PurchaseOrderItemID = &row.PurchaseOrderItemID.Int32
+	}
+	return response
+}
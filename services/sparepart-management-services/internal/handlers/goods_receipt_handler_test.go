@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"bytes"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+// performForm posts fields as a multipart form to handler mounted at route
+func performForm(t *testing.T, route string, handler gin.HandlerFunc, target string, fields map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatalf("failed to write form field: %v", err)
+		}
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	r := gin.New()
+	r.POST(route, handler)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGoodsReceiptHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().
+		CreateGoodsReceipt(gomock.Any(), sqlcdb.CreateGoodsReceiptParams{
+			SupplierID:          2,
+			DeliveryOrderNumber: "DO-0042",
+			PackingListPhotos:   []byte("[]"),
+			LocationID:          3,
+		}).
+		Return(sqlcdb.GoodsReceipt{ID: 5}, nil)
+	repo.EXPECT().
+		CreateGoodsReceiptItem(gomock.Any(), sqlcdb.CreateGoodsReceiptItemParams{ReceiptID: 5, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4}).
+		Return(sqlcdb.GoodsReceiptItem{ID: 1}, nil)
+	repo.EXPECT().GetGoodsReceipt(gomock.Any(), int32(5)).
+		Return(sqlcdb.GetGoodsReceiptRow{ID: 5, SupplierID: 2, SupplierName: "PT Sinar Jaya", DeliveryOrderNumber: "DO-0042", LocationID: 3, Status: receiptStatusDraft, PackingListPhotos: []byte("[]")}, nil)
+	repo.EXPECT().ListGoodsReceiptItems(gomock.Any(), int32(5)).
+		Return([]sqlcdb.ListGoodsReceiptItemsRow{{ID: 1, ReceiptID: 5, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4}}, nil)
+
+	w := performForm(t, "/receipts", h.Create, "/receipts", map[string]string{
+		"supplier_id":           "2",
+		"delivery_order_number": "DO-0042",
+		"location_id":           "3",
+		"items":                 `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 4}]`,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var receipt GoodsReceiptDetailResponse
+	decodeResponse(t, w, &receipt)
+	if receipt.Code != "GR-000005" || receipt.Supplier != "PT Sinar Jaya" || receipt.Status != receiptStatusDraft || len(receipt.Items) != 1 || receipt.Items[0].StockItemID != nil {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+}
+
+func TestGoodsReceiptHandlerCreateValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	valid := map[string]string{
+		"supplier_id":           "2",
+		"delivery_order_number": "DO-0042",
+		"location_id":           "3",
+		"items":                 `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 4}]`,
+	}
+	tests := []struct {
+		name  string
+		field string
+		value string
+		want  string
+	}{
+		{"missing supplier", "supplier_id", "", "supplier_id"},
+		{"supplier not a number", "supplier_id", "PT Sinar Jaya", "supplier_id"},
+		{"location not a number", "location_id", "abc", "location_id"},
+		{"items not JSON", "items", "7,NEW_STOCK,4", "items"},
+		{"no items", "items", "[]", "items"},
+		{"zero quantity", "items", `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 0}]`, "items[0].quantity"},
+		{"negative unit cost", "items", `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 1, "unit_cost": -5}]`, "items[0].unit_cost"},
+		{"duplicate item", "items", `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 1}, {"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 2}]`, "items[1]"},
+	}
+	for _, tt := range tests {
+		fields := make(map[string]string, len(valid))
+		for name, value := range valid {
+			fields[name] = value
+		}
+		fields[tt.field] = tt.value
+
+		w := performForm(t, "/receipts", h.Create, "/receipts", fields)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", tt.name, w.Code, w.Body.String())
+		}
+		resp := decodeResponse(t, w, nil)
+		if len(resp.Errors) != 1 || resp.Errors[0].Field != tt.want {
+			t.Fatalf("%s: unexpected field errors: %+v", tt.name, resp.Errors)
+		}
+	}
+}
+
+func TestGoodsReceiptHandlerCreateUnknownSparepart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().CreateGoodsReceipt(gomock.Any(), gomock.Any()).Return(sqlcdb.GoodsReceipt{ID: 5}, nil)
+	repo.EXPECT().CreateGoodsReceiptItem(gomock.Any(), gomock.Any()).Return(sqlcdb.GoodsReceiptItem{ID: 1}, nil)
+	repo.EXPECT().CreateGoodsReceiptItem(gomock.Any(), gomock.Any()).Return(sqlcdb.GoodsReceiptItem{}, pgx.ErrNoRows)
+
+	w := performForm(t, "/receipts", h.Create, "/receipts", map[string]string{
+		"supplier_id":           "2",
+		"delivery_order_number": "DO-0042",
+		"location_id":           "3",
+		"items":                 `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 4}, {"sparepart_id": 99, "stock_type": "USED_STOCK", "quantity": 1}]`,
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "items[1].sparepart_id" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestGoodsReceiptHandlerCreateUnknownSupplier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().CreateGoodsReceipt(gomock.Any(), gomock.Any()).Return(sqlcdb.GoodsReceipt{}, &pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "goods_receipt_supplier_id_fkey",
+	})
+
+	w := performForm(t, "/receipts", h.Create, "/receipts", map[string]string{
+		"supplier_id":           "99",
+		"delivery_order_number": "DO-0042",
+		"location_id":           "3",
+		"items":                 `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 4}]`,
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "supplier_id" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestGoodsReceiptHandlerConfirm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	unitCost := pgtype.Numeric{Int: big.NewInt(1250000), Exp: -2, Valid: true}
+	items := []sqlcdb.ListGoodsReceiptItemsRow{
+		{ID: 1, ReceiptID: 5, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4, UnitCost: unitCost},
+		{ID: 2, ReceiptID: 5, SparepartID: 8, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 1},
+	}
+	expectTransaction(repo)
+	repo.EXPECT().GetGoodsReceiptForUpdate(gomock.Any(), int32(5)).Return(sqlcdb.GoodsReceipt{ID: 5, LocationID: 3, Status: receiptStatusDraft}, nil)
+	repo.EXPECT().ListGoodsReceiptItems(gomock.Any(), int32(5)).Return(items, nil)
+	repo.EXPECT().
+		AdjustSparepartStock(gomock.Any(), sqlcdb.AdjustSparepartStockParams{LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4}).
+		Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 6}, nil)
+	repo.EXPECT().
+		SetGoodsReceiptItemStock(gomock.Any(), sqlcdb.SetGoodsReceiptItemStockParams{ID: 1, StockItemID: pgtype.Int4{Int32: 10, Valid: true}, QuantityAfter: pgtype.Int4{Int32: 6, Valid: true}}).
+		Return(nil)
+	// Only the item received with a cost changes the stock item's cost
+	repo.EXPECT().
+		ApplyGoodsReceiptItemCost(gomock.Any(), sqlcdb.ApplyGoodsReceiptItemCostParams{Received: 4, UnitCost: unitCost, ID: 10}).
+		Return(nil)
+	repo.EXPECT().
+		AdjustSparepartStock(gomock.Any(), sqlcdb.AdjustSparepartStockParams{LocationID: 3, SparepartID: 8, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 1}).
+		Return(sqlcdb.SparepartStockItem{ID: 11, Quantity: 1}, nil)
+	repo.EXPECT().
+		SetGoodsReceiptItemStock(gomock.Any(), sqlcdb.SetGoodsReceiptItemStockParams{ID: 2, StockItemID: pgtype.Int4{Int32: 11, Valid: true}, QuantityAfter: pgtype.Int4{Int32: 1, Valid: true}}).
+		Return(nil)
+	repo.EXPECT().ConfirmGoodsReceipt(gomock.Any(), gomock.Any()).Return(sqlcdb.GoodsReceipt{ID: 5, Status: receiptStatusConfirmed}, nil)
+	repo.EXPECT().GetGoodsReceipt(gomock.Any(), int32(5)).Return(sqlcdb.GetGoodsReceiptRow{ID: 5, LocationID: 3, Status: receiptStatusConfirmed}, nil)
+	repo.EXPECT().ListGoodsReceiptItems(gomock.Any(), int32(5)).Return(items, nil)
+
+	w := performRequest(http.MethodPost, "/receipts/:id/confirm", h.Confirm, "/receipts/5/confirm", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGoodsReceiptHandlerCreateForPurchaseOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetPurchaseOrderForUpdate(gomock.Any(), int32(8)).Return(sqlcdb.PurchaseOrder{ID: 8, SupplierID: 2, Status: orderStatusPartial}, nil)
+	repo.EXPECT().ListPurchaseOrderItems(gomock.Any(), int32(8)).Return([]sqlcdb.ListPurchaseOrderItemsRow{
+		{ID: 30, OrderID: 8, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 10},
+		{ID: 31, OrderID: 8, SparepartID: 7, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 2},
+	}, nil)
+	repo.EXPECT().
+		CreateGoodsReceipt(gomock.Any(), sqlcdb.CreateGoodsReceiptParams{
+			SupplierID:          2,
+			DeliveryOrderNumber: "DO-0042",
+			PackingListPhotos:   []byte("[]"),
+			PurchaseOrderID:     pgtype.Int4{Int32: 8, Valid: true},
+			LocationID:          3,
+		}).
+		Return(sqlcdb.GoodsReceipt{ID: 5}, nil)
+	repo.EXPECT().
+		CreateGoodsReceiptItem(gomock.Any(), sqlcdb.CreateGoodsReceiptItemParams{
+			ReceiptID:           5,
+			SparepartID:         7,
+			StockType:           sqlcdb.StockTypeNEWSTOCK,
+			Quantity:            4,
+			PurchaseOrderItemID: pgtype.Int4{Int32: 30, Valid: true},
+		}).
+		Return(sqlcdb.GoodsReceiptItem{ID: 1}, nil)
+	repo.EXPECT().GetGoodsReceipt(gomock.Any(), int32(5)).
+		Return(sqlcdb.GetGoodsReceiptRow{ID: 5, SupplierID: 2, PurchaseOrderID: pgtype.Int4{Int32: 8, Valid: true}, Status: receiptStatusDraft}, nil)
+	repo.EXPECT().ListGoodsReceiptItems(gomock.Any(), int32(5)).
+		Return([]sqlcdb.ListGoodsReceiptItemsRow{{ID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4, PurchaseOrderItemID: pgtype.Int4{Int32: 30, Valid: true}}}, nil)
+
+	w := performForm(t, "/receipts", h.Create, "/receipts", map[string]string{
+		"supplier_id":           "2",
+		"delivery_order_number": "DO-0042",
+		"location_id":           "3",
+		"purchase_order_id":     "8",
+		"items":                 `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 4}]`,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var receipt GoodsReceiptDetailResponse
+	decodeResponse(t, w, &receipt)
+	if receipt.PurchaseOrderID == nil || *receipt.PurchaseOrderID != 8 || receipt.Items[0].PurchaseOrderItemID == nil || *receipt.Items[0].PurchaseOrderItemID != 30 {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+}
+
+func TestGoodsReceiptHandlerCreateForPurchaseOrderValidation(t *testing.T) {
+	lines := []sqlcdb.ListPurchaseOrderItemsRow{{ID: 30, OrderID: 8, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 10}}
+	tests := []struct {
+		name  string
+		order sqlcdb.PurchaseOrder
+		items string
+		want  string
+	}{
+		{"draft order", sqlcdb.PurchaseOrder{ID: 8, SupplierID: 2, Status: orderStatusDraft}, `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 4}]`, "purchase_order_id"},
+		{"received order", sqlcdb.PurchaseOrder{ID: 8, SupplierID: 2, Status: orderStatusReceived}, `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 4}]`, "purchase_order_id"},
+		{"other supplier", sqlcdb.PurchaseOrder{ID: 8, SupplierID: 3, Status: orderStatusOrdered}, `[{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 4}]`, "purchase_order_id"},
+		{"item not ordered", sqlcdb.PurchaseOrder{ID: 8, SupplierID: 2, Status: orderStatusOrdered}, `[{"sparepart_id": 7, "stock_type": "USED_STOCK", "quantity": 4}]`, "items[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockSparepartStockRepository(ctrl)
+			h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+			expectTransaction(repo)
+			repo.EXPECT().GetPurchaseOrderForUpdate(gomock.Any(), int32(8)).Return(tt.order, nil)
+			repo.EXPECT().ListPurchaseOrderItems(gomock.Any(), int32(8)).Return(lines, nil).AnyTimes()
+
+			w := performForm(t, "/receipts", h.Create, "/receipts", map[string]string{
+				"supplier_id":           "2",
+				"delivery_order_number": "DO-0042",
+				"location_id":           "3",
+				"purchase_order_id":     "8",
+				"items":                 tt.items,
+			})
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+			resp := decodeResponse(t, w, nil)
+			if len(resp.Errors) != 1 || resp.Errors[0].Field != tt.want {
+				t.Fatalf("unexpected field errors: %+v", resp.Errors)
+			}
+		})
+	}
+}
+
+func TestGoodsReceiptHandlerConfirmUpdatesPurchaseOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	items := []sqlcdb.ListGoodsReceiptItemsRow{{ID: 1, ReceiptID: 5, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4}}
+	expectTransaction(repo)
+	gomock.InOrder(
+		repo.EXPECT().GetGoodsReceiptForUpdate(gomock.Any(), int32(5)).
+			Return(sqlcdb.GoodsReceipt{ID: 5, LocationID: 3, Status: receiptStatusDraft, PurchaseOrderID: pgtype.Int4{Int32: 8, Valid: true}}, nil),
+		repo.EXPECT().GetPurchaseOrderForUpdate(gomock.Any(), int32(8)).Return(sqlcdb.PurchaseOrder{ID: 8, Status: orderStatusOrdered}, nil),
+		repo.EXPECT().ListGoodsReceiptItems(gomock.Any(), int32(5)).Return(items, nil),
+		repo.EXPECT().AdjustSparepartStock(gomock.Any(), gomock.Any()).Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 4}, nil),
+		repo.EXPECT().SetGoodsReceiptItemStock(gomock.Any(), gomock.Any()).Return(nil),
+		repo.EXPECT().ConfirmGoodsReceipt(gomock.Any(), gomock.Any()).Return(sqlcdb.GoodsReceipt{ID: 5, Status: receiptStatusConfirmed}, nil),
+		repo.EXPECT().UpdatePurchaseOrderReceivedStatus(gomock.Any(), int32(8)).Return(nil),
+	)
+	repo.EXPECT().GetGoodsReceipt(gomock.Any(), int32(5)).Return(sqlcdb.GetGoodsReceiptRow{ID: 5, LocationID: 3, Status: receiptStatusConfirmed}, nil)
+	repo.EXPECT().ListGoodsReceiptItems(gomock.Any(), int32(5)).Return(items, nil)
+
+	w := performRequest(http.MethodPost, "/receipts/:id/confirm", h.Confirm, "/receipts/5/confirm", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGoodsReceiptHandlerConfirmRequiresDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetGoodsReceiptForUpdate(gomock.Any(), int32(5)).Return(sqlcdb.GoodsReceipt{ID: 5, Status: receiptStatusConfirmed}, nil)
+
+	w := performRequest(http.MethodPost, "/receipts/:id/confirm", h.Confirm, "/receipts/5/confirm", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGoodsReceiptHandlerGetAllValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	for _, query := range []string{"status=RECEIVED", "location_id=0", "page=0"} {
+		w := performRequest(http.MethodGet, "/receipts", h.GetAll, "/receipts?"+query, "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestGoodsReceiptHandlerPDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewGoodsReceiptHandler(repo, nil, testLogger)
+
+	repo.EXPECT().GetGoodsReceipt(gomock.Any(), int32(5)).
+		Return(sqlcdb.GetGoodsReceiptRow{ID: 5, SupplierID: 2, SupplierName: "PT Sinar Jaya", DeliveryOrderNumber: "DO-0042", LocationID: 3, Status: receiptStatusDraft}, nil)
+	repo.EXPECT().ListGoodsReceiptItems(gomock.Any(), int32(5)).
+		Return([]sqlcdb.ListGoodsReceiptItemsRow{{ID: 1, SparepartID: 7, SparepartName: pgtype.Text{String: "BMS", Valid: true}, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4}}, nil)
+
+	w := performRequest(http.MethodGet, "/receipts/:id/pdf", h.PDF, "/receipts/5/pdf", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.HasPrefix(w.Body.String(), "%PDF") || !strings.Contains(w.Header().Get("Content-Disposition"), "GR-000005") {
+		t.Fatalf("unexpected PDF response: %q", w.Header().Get("Content-Disposition"))
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"sparepart-management-services/internal/graph"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type GraphQLHandler struct {
+	logger  *zap.Logger
+	queries repository.GraphRepository
+}
+
+func NewGraphQLHandler(queries repository.GraphRepository, logger *zap.Logger) *GraphQLHandler {
+	return &GraphQLHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary GraphQL query
+// @Description Read-only GraphQL endpoint for the mobile dashboard (schema: internal/graph/schema.graphqls): locations with their stock, tools alker and contact persons in one round trip. Responds with the standard GraphQL {data, errors} body; errors of single fields do not fail the request.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Param request body graph.Request true "GraphQL query, operation name and variables"
+// @Success 200 {object} object
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graph.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	h.execute(c, req)
+}
+
+// @Summary GraphQL query (GET)
+// @Description The GraphQL endpoint for clients that may only read, such as API keys without the stock-write scope
+// @Tags GraphQL
+// @Produce json
+// @Param query query string true "GraphQL query"
+// @Param operationName query string false "Operation to run when the query has several"
+// @Param variables query string false "Variables as a JSON object"
+// @Success 200 {object} object
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/graphql [get]
+func (h *GraphQLHandler) QueryGet(c *gin.Context) {
+	req := graph.Request{Query: c.Query("query"), OperationName: c.Query("operationName")}
+	if req.Query == "" {
+		utils.ValidationError(c, utils.FieldError{Field: "query", Rule: "required", Message: "is required"})
+		return
+	}
+	if variables := c.Query("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+			utils.ValidationError(c, utils.FieldError{Field: "variables", Message: "must be a JSON object"})
+			return
+		}
+	}
+	h.execute(c, req)
+}
+
+func (h *GraphQLHandler) execute(c *gin.Context, req graph.Request) {
+	result := graph.Execute(c.Request.Context(), h.queries, h.logger, req)
+	c.JSON(http.StatusOK, result)
+}
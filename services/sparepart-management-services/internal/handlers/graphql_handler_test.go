@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestGraphQLHandlerQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockGraphRepository(ctrl)
+	h := NewGraphQLHandler(repo, testLogger)
+
+	repo.EXPECT().ListToolsAlkersByLocations(gomock.Any(), []int32{6}).Return([]sqlcdb.ListToolsAlkersByLocationsRow{
+		{ID: 3, LocationID: 6, ToolsID: 8, ToolsName: "Tang Ampere", ItemType: sqlcdb.ItemTypeTOOLSALKER, Quantity: 4, CheckedOut: 1},
+	}, nil)
+
+	body := `{"query": "query Tools($id: ID!) { toolsAlker(locationId: $id) { id tools { name } available } }", "variables": {"id": "6"}}`
+	w := performRequest(http.MethodPost, "/graphql", h.Query, "/graphql", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			ToolsAlker []struct {
+				ID    string `json:"id"`
+				Tools struct {
+					Name string `json:"name"`
+				} `json:"tools"`
+				Available int `json:"available"`
+			} `json:"toolsAlker"`
+		} `json:"data"`
+		Errors []json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %s", w.Body.String())
+	}
+	if len(resp.Data.ToolsAlker) != 1 || resp.Data.ToolsAlker[0].ID != "3" || resp.Data.ToolsAlker[0].Tools.Name != "Tang Ampere" || resp.Data.ToolsAlker[0].Available != 3 {
+		t.Fatalf("unexpected tools alker %+v", resp.Data.ToolsAlker)
+	}
+}
+
+func TestGraphQLHandlerQueryRequiresQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h := NewGraphQLHandler(mocks.NewMockGraphRepository(ctrl), testLogger)
+
+	w := performRequest(http.MethodPost, "/graphql", h.Query, "/graphql", `{"variables": {}}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGraphQLHandlerQueryGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockGraphRepository(ctrl)
+	h := NewGraphQLHandler(repo, testLogger)
+
+	repo.EXPECT().ListContactPersonsByLocations(gomock.Any(), []int32{6}).Return([]sqlcdb.ContactPerson{
+		{ID: 2, LocationID: 6, Pic: "Budi", Phone: "0812"},
+	}, nil)
+
+	target := "/graphql?" + url.Values{
+		"query":     {"query ($id: ID!) { contactPersons(locationId: $id) { pic } }"},
+		"variables": {`{"id": "6"}`},
+	}.Encode()
+	w := performRequest(http.MethodGet, "/graphql", h.QueryGet, target, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if want := `{"data":{"contactPersons":[{"pic":"Budi"}]}}`; w.Body.String() != want {
+		t.Fatalf("expected %s, got %s", want, w.Body.String())
+	}
+
+	w = performRequest(http.MethodGet, "/graphql", h.QueryGet, "/graphql?query=%7B%7D&variables=%5B", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unreadable variables, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+var testLogger = zap.NewNop()
+
+// testResponse mirrors utils.Response and utils.PaginatedResponse with raw data for decoding in tests
+type testResponse struct {
+	Success    bool               `json:"success"`
+	Message    string             `json:"message"`
+	Data       json.RawMessage    `json:"data"`
+	Error      string             `json:"error"`
+	Code       string             `json:"code"`
+	Errors     []utils.FieldError `json:"errors"`
+	Pagination struct {
+		Page       int   `json:"page"`
+		Limit      int   `json:"limit"`
+		Total      int64 `json:"total"`
+		TotalPages int   `json:"total_pages"`
+	} `json:"pagination"`
+}
+
+// performRequest serves a single request through a router with only the given handler registered
+func performRequest(method, route string, handler gin.HandlerFunc, target string, body string) *httptest.ResponseRecorder {
+	return performRequestAs("", method, route, handler, target, body)
+}
+
+// asUser authenticates every request as user, as middleware.Authenticate does for a valid token
+func asUser(user string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(utils.UserKey, user)
+	}
+}
+
+// performRequestAs is performRequest on behalf of a user (see utils.UserID); an empty user
+// leaves the request unauthenticated
+func performRequestAs(user, method, route string, handler gin.HandlerFunc, target string, body string) *httptest.ResponseRecorder {
+	r := gin.New()
+	if user != "" {
+		r.Use(asUser(user))
+	}
+	r.Handle(method, route, handler)
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decodeResponse decodes the JSON envelope and optionally the data payload into out
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder, out interface{}) testResponse {
+	t.Helper()
+
+	var resp testResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	if out != nil {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			t.Fatalf("failed to decode response data %q: %v", string(resp.Data), err)
+		}
+	}
+	return resp
+}
+
+// testJPEG is a small valid JPEG for photo upload tests, which check the file content
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatalf("failed to encode test photo: %v", err)
+	}
+	return buf.Bytes()
+}
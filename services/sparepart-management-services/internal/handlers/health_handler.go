@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"sparepart-management-services/internal/database"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// ReadinessCheck is the result of a single readiness check
+type ReadinessCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// UploadsCheck is the uploads directory check with its disk details
+type UploadsCheck struct {
+	Status string `json:"status"`
+	utils.UploadDirStatus
+}
+
+// ReadinessResponse lists every readiness check; the service is ready only when all pass
+type ReadinessResponse struct {
+	Status string          `json:"status"`
+	Checks ReadinessChecks `json:"checks"`
+}
+
+type ReadinessChecks struct {
+	Database ReadinessCheck `json:"database"`
+	Uploads  UploadsCheck   `json:"uploads"`
+}
+
+type HealthHandler struct {
+	logger       *zap.Logger
+	pool         *pgxpool.Pool
+	uploadDir    string
+	minFreeBytes uint64
+}
+
+func NewHealthHandler(pool *pgxpool.Pool, uploadDir string, minFreeBytes uint64, logger *zap.Logger) *HealthHandler {
+	return &HealthHandler{
+		logger:       logger,
+		pool:         pool,
+		uploadDir:    uploadDir,
+		minFreeBytes: minFreeBytes,
+	}
+}
+
+// @Summary Readiness check
+// @Description Check the database connection and that the uploads directory is writable with enough free space
+// @Tags Health
+// @Produce json
+// @Success 200 {object} ReadinessResponse
+// @Failure 503 {object} ReadinessResponse
+// @Router /ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	resp := ReadinessResponse{Status: "ready"}
+
+	resp.Checks.Database = ReadinessCheck{Status: "ok"}
+	if err := database.HealthCheck(ctx, h.pool); err != nil {
+		resp.Checks.Database = ReadinessCheck{Status: "error", Error: err.Error()}
+	}
+
+	uploads := utils.CheckUploadDir(h.uploadDir, h.minFreeBytes)
+	resp.Checks.Uploads = UploadsCheck{Status: "ok", UploadDirStatus: uploads}
+	if !uploads.OK() {
+		resp.Checks.Uploads.Status = "error"
+	}
+
+	statusCode := http.StatusOK
+	if resp.Checks.Database.Status != "ok" || resp.Checks.Uploads.Status != "ok" {
+		resp.Status = "not_ready"
+		statusCode = http.StatusServiceUnavailable
+		utils.RequestLogger(ctx, h.logger).Warn("Readiness check failed",
+			zap.String("database", resp.Checks.Database.Error),
+			zap.String("uploads", uploads.Error),
+			zap.Uint64("upload_free_bytes", uploads.FreeBytes),
+		)
+	}
+
+	c.JSON(statusCode, resp)
+}
@@ -1,25 +1,86 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
-	"sparepart-management-services/internal/database"
 	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
 	"sparepart-management-services/internal/utils"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
+// defaultOpnameDays is how recently a location's stock must have been counted (any stock
+// item updated) to pass the stock opname check of the completeness score
+const defaultOpnameDays = 90
+
+// LocationCompleteness scores how well a location is documented, 0-100. Each check is worth
+// 25: contact person, photographed stock items, a recent stock opname and stock item notes;
+// photos and notes give partial credit by share of items.
+type LocationCompleteness struct {
+	Score             int32  `json:"score"`
+	HasContactPerson  bool   `json:"has_contact_person"`
+	StockItems        int64  `json:"stock_items"`
+	PhotographedItems int64  `json:"photographed_items"`
+	NotedItems        int64  `json:"noted_items"`
+	RecentStockOpname bool   `json:"recent_stock_opname"`
+	LastStockUpdate   string `json:"last_stock_update,omitempty"`
+}
+
+// LocationCompletenessResponse is one row of the ranked completeness report
+type LocationCompletenessResponse struct {
+	LocationID int32  `json:"location_id"`
+	Region     string `json:"region"`
+	Regency    string `json:"regency"`
+	Cluster    string `json:"cluster"`
+	LocationCompleteness
+}
+
+// LocationFeatureCollection is a GeoJSON FeatureCollection of locations, for map views
+type LocationFeatureCollection struct {
+	Type     string            `json:"type"` // always FeatureCollection
+	Features []LocationFeature `json:"features"`
+}
+
+// LocationFeature is a location as a GeoJSON Point feature with its stock summary
+type LocationFeature struct {
+	Type       string                    `json:"type"` // always Feature
+	ID         int32                     `json:"id"`
+	Geometry   LocationPoint             `json:"geometry"`
+	Properties LocationFeatureProperties `json:"properties"`
+}
+
+// LocationPoint is a GeoJSON Point; coordinates are [longitude, latitude] as GeoJSON requires
+type LocationPoint struct {
+	Type        string     `json:"type"` // always Point
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type LocationFeatureProperties struct {
+	Region             string `json:"region"`
+	Regency            string `json:"regency"`
+	Cluster            string `json:"cluster"`
+	StockItemCount     int64  `json:"stock_item_count"`
+	NewStockQuantity   int64  `json:"new_stock_quantity"`
+	UsedStockQuantity  int64  `json:"used_stock_quantity"`
+	ToolsAlkerQuantity int64  `json:"tools_alker_quantity"`
+}
+
 type LocationHandler struct {
 	logger  *zap.Logger
-	queries *sqlcdb.Queries
+	queries repository.LocationRepository
 }
 
-func NewLocationHandler() *LocationHandler {
+func NewLocationHandler(queries repository.LocationRepository, logger *zap.Logger) *LocationHandler {
 	return &LocationHandler{
-		logger:  utils.GetLogger(),
-		queries: sqlcdb.New(database.GetDB()),
+		logger:  logger,
+		queries: queries,
 	}
 }
 
@@ -30,35 +91,43 @@ func NewLocationHandler() *LocationHandler {
 // @Produce json
 // @Param region query string false "Filter by region"
 // @Param regency query string false "Filter by regency"
+// @Param include_inactive query bool false "Also list deactivated locations" default(false)
 // @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
+// @Param limit query int false "Items per page (max 100)" default(10)
 // @Success 200 {object} utils.PaginatedResponse
 // @Router /location [get]
 func (h *LocationHandler) GetAll(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	// Get filter parameters
-	var region, regency, cluster string
-	if r := c.Query("region"); r != "" {
-		region = r
-	}
-	if r := c.Query("regency"); r != "" {
-		regency = r
-	}
-	if r := c.Query("cluster"); r != "" {
-		cluster = r
+	region := utils.TextFilter(c.Query("region"))
+	regency := utils.TextFilter(c.Query("regency"))
+	cluster := utils.TextFilter(c.Query("cluster"))
+
+	var errs []utils.FieldError
+	var includeInactive bool
+	if value := c.Query("include_inactive"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "include_inactive", Message: "must be true or false"})
+		}
+		includeInactive = parsed
 	}
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset := (page - 1) * limit
+	pagination, pageErrs := utils.ParsePagination(c)
+	errs = append(errs, pageErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
 
 	// Count total
 	countParams := sqlcdb.CountLocationsParams{
-		Column1: region,
-		Column2: regency,
-		Column3: cluster,
+		Region:          region,
+		Regency:         regency,
+		Cluster:         cluster,
+		IncludeInactive: includeInactive,
 	}
 	total, err := h.queries.CountLocations(ctx, countParams)
 	if err != nil {
@@ -68,11 +137,12 @@ func (h *LocationHandler) GetAll(c *gin.Context) {
 
 	// List locations
 	listParams := sqlcdb.ListLocationsParams{
-		Column1: region,
-		Column2: regency,
-		Column3: cluster,
-		Limit:   int32(limit),
-		Offset:  int32(offset),
+		Region:          region,
+		Regency:         regency,
+		Cluster:         cluster,
+		IncludeInactive: includeInactive,
+		Limit:           int32(pagination.Limit),
+		Offset:          int32(pagination.Offset()),
 	}
 	locations, err := h.queries.ListLocations(ctx, listParams)
 	if err != nil {
@@ -80,7 +150,120 @@ func (h *LocationHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessWithPagination(c, "Locations retrieved successfully", locations, page, limit, total)
+	utils.SuccessWithPagination(c, "Locations retrieved successfully", locations, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get location completeness report
+// @Description Rank active locations by documentation completeness score, worst first
+// @Tags Location
+// @Accept json
+// @Produce json
+// @Param region query string false "Filter by region"
+// @Param regency query string false "Filter by regency"
+// @Param cluster query string false "Filter by cluster"
+// @Param opname_days query int false "Days within which the stock must have been counted" default(90)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /location/completeness [get]
+func (h *LocationHandler) GetCompleteness(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	region := utils.TextFilter(c.Query("region"))
+	regency := utils.TextFilter(c.Query("regency"))
+	cluster := utils.TextFilter(c.Query("cluster"))
+
+	opnameDays, opnameErrs := parseOpnameDays(c)
+	pagination, errs := utils.ParsePagination(c)
+	errs = append(opnameErrs, errs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountLocations(ctx, sqlcdb.CountLocationsParams{
+		Region:  region,
+		Regency: regency,
+		Cluster: cluster,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count locations", h.logger)
+		return
+	}
+
+	rows, err := h.queries.ListLocationCompleteness(ctx, sqlcdb.ListLocationCompletenessParams{
+		Region:      region,
+		Regency:     regency,
+		Cluster:     cluster,
+		OpnameSince: opnameSince(opnameDays),
+		Limit:       int32(pagination.Limit),
+		Offset:      int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get location completeness", h.logger)
+		return
+	}
+
+	response := make([]LocationCompletenessResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, LocationCompletenessResponse{
+			LocationID:           row.LocationID,
+			Region:               string(row.Region),
+			Regency:              row.Regency,
+			Cluster:              row.Cluster,
+			LocationCompleteness: toLocationCompleteness(sqlcdb.ListLocationCompletenessByIDsRow(row)),
+		})
+	}
+
+	utils.SuccessWithPagination(c, "Location completeness retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get locations as GeoJSON
+// @Description Get the locations that have coordinates as a GeoJSON FeatureCollection of points, each with the stock item count, new and used stock quantities and tools alker quantity of the location, for map views. Locations without coordinates are left out.
+// @Tags Location
+// @Accept json
+// @Produce json
+// @Param region query string false "Filter by region"
+// @Param regency query string false "Filter by regency"
+// @Param cluster query string false "Filter by cluster"
+// @Success 200 {object} utils.Response{data=LocationFeatureCollection}
+// @Router /location/geojson [get]
+func (h *LocationHandler) GetGeoJSON(c *gin.Context) {
+	rows, err := h.queries.ListLocationsWithCoordinates(c.Request.Context(), sqlcdb.ListLocationsWithCoordinatesParams{
+		Region:  utils.TextFilter(c.Query("region")),
+		Regency: utils.TextFilter(c.Query("regency")),
+		Cluster: utils.TextFilter(c.Query("cluster")),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get locations", h.logger)
+		return
+	}
+
+	collection := LocationFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]LocationFeature, 0, len(rows)),
+	}
+	for _, row := range rows {
+		collection.Features = append(collection.Features, LocationFeature{
+			Type: "Feature",
+			ID:   row.ID,
+			Geometry: LocationPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{row.Longitude, row.Latitude},
+			},
+			Properties: LocationFeatureProperties{
+				Region:             string(row.Region),
+				Regency:            row.Regency,
+				Cluster:            row.Cluster,
+				StockItemCount:     row.StockItemCount,
+				NewStockQuantity:   row.NewStockQuantity,
+				UsedStockQuantity:  row.UsedStockQuantity,
+				ToolsAlkerQuantity: row.ToolsAlkerQuantity,
+			},
+		})
+	}
+
+	utils.Success(c, "Locations retrieved successfully", collection)
 }
 
 // @Summary Get location by ID
@@ -110,7 +293,7 @@ func (h *LocationHandler) GetByID(c *gin.Context) {
 }
 
 // @Summary Create location
-// @Description Create a new location
+// @Description Create a new location in an existing cluster (see /cluster); latitude and longitude are optional but given together
 // @Tags Location
 // @Accept json
 // @Produce json
@@ -122,7 +305,11 @@ func (h *LocationHandler) Create(c *gin.Context) {
 
 	var req sqlcdb.CreateLocationParams
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+		utils.BindingError(c, err)
+		return
+	}
+	if errs := utils.ValidateCoordinates(req.Latitude, req.Longitude); len(errs) > 0 {
+		utils.ValidationError(c, errs...)
 		return
 	}
 
@@ -140,7 +327,7 @@ func (h *LocationHandler) Create(c *gin.Context) {
 }
 
 // @Summary Update location
-// @Description Update an existing location
+// @Description Update an existing location; latitude and longitude are optional but given together, and leaving them out clears them
 // @Tags Location
 // @Accept json
 // @Produce json
@@ -166,7 +353,11 @@ func (h *LocationHandler) Update(c *gin.Context) {
 
 	var req sqlcdb.UpdateLocationParams
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+		utils.BindingError(c, err)
+		return
+	}
+	if errs := utils.ValidateCoordinates(req.Latitude, req.Longitude); len(errs) > 0 {
+		utils.ValidationError(c, errs...)
 		return
 	}
 
@@ -180,13 +371,83 @@ func (h *LocationHandler) Update(c *gin.Context) {
 	utils.Success(c, "Location updated successfully", location)
 }
 
+// PatchLocationRequest holds the location fields to change; omitted fields are unchanged
+type PatchLocationRequest struct {
+	Region  *string `json:"region,omitempty" binding:"omitempty,oneof=MALUKU MALUKU_UTARA PAPUA PAPUA_BARAT PAPUA_BARAT_DAYA PAPUA_SELATAN"`
+	Regency *string `json:"regency,omitempty" binding:"omitempty,min=1"`
+	Cluster *string `json:"cluster,omitempty" binding:"omitempty,min=1"`
+	// Latitude and Longitude are changed together
+	Latitude  *float64 `json:"latitude,omitempty" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude,omitempty" binding:"omitempty,min=-180,max=180"`
+}
+
+// @Summary Partially update location
+// @Description Update only the location fields present in the request body
+// @Tags Location
+// @Accept json
+// @Produce json
+// @Param id path int true "Location ID"
+// @Param location body PatchLocationRequest true "Fields to update (omitted fields are unchanged)"
+// @Success 200 {object} utils.Response
+// @Router /location/{id} [patch]
+func (h *LocationHandler) Patch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	// Check if location exists
+	_, err = h.queries.GetLocation(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Location not found")
+		return
+	}
+
+	var req PatchLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	if req.Region == nil && req.Regency == nil && req.Cluster == nil && req.Latitude == nil && req.Longitude == nil {
+		utils.BadRequest(c, "No fields to update")
+		return
+	}
+
+	params := sqlcdb.PatchLocationParams{
+		ID:        int32(id),
+		Regency:   utils.OptionalText(req.Regency),
+		Cluster:   utils.OptionalText(req.Cluster),
+		Latitude:  utils.OptionalFloat(req.Latitude),
+		Longitude: utils.OptionalFloat(req.Longitude),
+	}
+	if errs := utils.ValidateCoordinates(params.Latitude, params.Longitude); len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+	if req.Region != nil {
+		params.Region = sqlcdb.NullRegionType{RegionType: sqlcdb.RegionType(*req.Region), Valid: true}
+	}
+
+	location, err := h.queries.PatchLocation(ctx, params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update location", h.logger)
+		return
+	}
+
+	utils.Success(c, "Location updated successfully", location)
+}
+
 // @Summary Delete location
-// @Description Delete a location
+// @Description Soft delete a location that no longer holds stock; a location still holding stock or tools alker items is deactivated instead
 // @Tags Location
 // @Accept json
 // @Produce json
 // @Param id path int true "Location ID"
 // @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /location/{id} [delete]
 func (h *LocationHandler) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -197,6 +458,26 @@ func (h *LocationHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	// Check if location exists
+	_, err = h.queries.GetLocation(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Location not found")
+		return
+	}
+
+	references, err := h.queries.CountLocationStockReferences(ctx, int32(id))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to check location stock", h.logger)
+		return
+	}
+	if references > 0 {
+		c.JSON(http.StatusConflict, utils.Response{
+			Error: fmt.Sprintf("Location still holds %d stock and tools alker items; deactivate it instead", references),
+			Code:  utils.ErrCodeInUse,
+		})
+		return
+	}
+
 	err = h.queries.DeleteLocation(ctx, int32(id))
 	if err != nil {
 		utils.HandleError(c, err, "Failed to delete location", h.logger)
@@ -205,3 +486,111 @@ func (h *LocationHandler) Delete(c *gin.Context) {
 
 	utils.Success(c, "Location deleted successfully", nil)
 }
+
+// @Summary Restore location
+// @Description Restore a soft deleted location and the stock items deleted with it
+// @Tags Location
+// @Accept json
+// @Produce json
+// @Param id path int true "Location ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response "A location with the same region, regency and cluster was created meanwhile"
+// @Router /location/{id}/restore [post]
+func (h *LocationHandler) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	location, err := h.queries.RestoreLocation(ctx, int32(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.NotFound(c, "Deleted location not found")
+			return
+		}
+		utils.HandleError(c, err, "Failed to restore location", h.logger)
+		return
+	}
+
+	utils.Success(c, "Location restored successfully", location)
+}
+
+// @Summary Deactivate location
+// @Description Deactivate a location; it keeps its stock and history but is left out of the default location list, the completeness report and the filter dropdowns
+// @Tags Location
+// @Accept json
+// @Produce json
+// @Param id path int true "Location ID"
+// @Success 200 {object} utils.Response
+// @Router /location/{id}/deactivate [post]
+func (h *LocationHandler) Deactivate(c *gin.Context) {
+	h.setActive(c, false, "Location deactivated successfully")
+}
+
+// @Summary Activate location
+// @Description Reactivate a deactivated location
+// @Tags Location
+// @Accept json
+// @Produce json
+// @Param id path int true "Location ID"
+// @Success 200 {object} utils.Response
+// @Router /location/{id}/activate [post]
+func (h *LocationHandler) Activate(c *gin.Context) {
+	h.setActive(c, true, "Location activated successfully")
+}
+
+func (h *LocationHandler) setActive(c *gin.Context, active bool, message string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	location, err := h.queries.SetLocationActive(c.Request.Context(), sqlcdb.SetLocationActiveParams{
+		ID:       int32(id),
+		IsActive: active,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.NotFound(c, "Location not found")
+			return
+		}
+		utils.HandleError(c, err, "Failed to update location", h.logger)
+		return
+	}
+
+	utils.Success(c, message, location)
+}
+
+// parseOpnameDays reads the opname_days query param, defaulting to defaultOpnameDays
+func parseOpnameDays(c *gin.Context) (int, []utils.FieldError) {
+	value := c.Query("opname_days")
+	if value == "" {
+		return defaultOpnameDays, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 || days > 3650 {
+		return 0, []utils.FieldError{{Field: "opname_days", Message: "must be between 1 and 3650"}}
+	}
+	return days, nil
+}
+
+// opnameSince is the earliest stock update that still counts as a recent stock opname
+func opnameSince(days int) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Now().UTC().AddDate(0, 0, -days), Valid: true}
+}
+
+func toLocationCompleteness(row sqlcdb.ListLocationCompletenessByIDsRow) LocationCompleteness {
+	return LocationCompleteness{
+		Score:             row.Score,
+		HasContactPerson:  row.HasContactPerson,
+		StockItems:        row.StockItems,
+		PhotographedItems: row.PhotographedItems,
+		NotedItems:        row.NotedItems,
+		RecentStockOpname: row.RecentStockOpname,
+		LastStockUpdate:   utils.FormatTimestamp(row.LastStockUpdate),
+	}
+}
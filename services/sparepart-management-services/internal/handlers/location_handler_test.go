@@ -0,0 +1,489 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLocationHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	region := pgtype.Text{String: "MALUKU", Valid: true}
+	repo.EXPECT().
+		CountLocations(gomock.Any(), sqlcdb.CountLocationsParams{Region: region}).
+		Return(int64(3), nil)
+	repo.EXPECT().
+		ListLocations(gomock.Any(), sqlcdb.ListLocationsParams{Region: region, Limit: 2, Offset: 2}).
+		Return([]sqlcdb.Location{{ID: 3, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo"}}, nil)
+
+	w := performRequest(http.MethodGet, "/location", h.GetAll, "/location?region=MALUKU&page=2&limit=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var locations []sqlcdb.Location
+	resp := decodeResponse(t, w, &locations)
+	if len(locations) != 1 || locations[0].ID != 3 {
+		t.Fatalf("unexpected locations: %+v", locations)
+	}
+	if resp.Pagination.Total != 3 || resp.Pagination.TotalPages != 2 {
+		t.Fatalf("unexpected pagination: %+v", resp.Pagination)
+	}
+}
+
+func TestLocationHandlerGetAllPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		setup      func(repo *mocks.MockLocationRepository)
+		wantStatus int
+		wantLimit  int
+	}{
+		{name: "zero page", target: "/location?page=0", setup: func(repo *mocks.MockLocationRepository) {}, wantStatus: http.StatusBadRequest},
+		{name: "negative limit", target: "/location?limit=-5", setup: func(repo *mocks.MockLocationRepository) {}, wantStatus: http.StatusBadRequest},
+		{name: "non-numeric page", target: "/location?page=abc", setup: func(repo *mocks.MockLocationRepository) {}, wantStatus: http.StatusBadRequest},
+		{name: "offset overflow", target: "/location?page=99999999&limit=100", setup: func(repo *mocks.MockLocationRepository) {}, wantStatus: http.StatusBadRequest},
+		{
+			name:   "huge limit is clamped",
+			target: "/location?limit=100000",
+			setup: func(repo *mocks.MockLocationRepository) {
+				repo.EXPECT().CountLocations(gomock.Any(), sqlcdb.CountLocationsParams{}).Return(int64(0), nil)
+				repo.EXPECT().
+					ListLocations(gomock.Any(), sqlcdb.ListLocationsParams{Limit: utils.MaxPageLimit, Offset: 0}).
+					Return([]sqlcdb.Location{}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantLimit:  utils.MaxPageLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockLocationRepository(ctrl)
+			tt.setup(repo)
+			h := NewLocationHandler(repo, testLogger)
+
+			w := performRequest(http.MethodGet, "/location", h.GetAll, tt.target, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			resp := decodeResponse(t, w, nil)
+			if tt.wantStatus == http.StatusBadRequest && resp.Code != utils.ErrCodeValidation {
+				t.Fatalf("expected validation error code, got %q", resp.Code)
+			}
+			if tt.wantLimit != 0 && resp.Pagination.Limit != tt.wantLimit {
+				t.Fatalf("expected limit %d, got %d", tt.wantLimit, resp.Pagination.Limit)
+			}
+		})
+	}
+}
+
+func TestLocationHandlerGetByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		setup      func(repo *mocks.MockLocationRepository)
+		wantStatus int
+	}{
+		{
+			name:   "found",
+			target: "/location/1",
+			setup: func(repo *mocks.MockLocationRepository) {
+				repo.EXPECT().GetLocation(gomock.Any(), int32(1)).Return(sqlcdb.Location{ID: 1}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "not found",
+			target: "/location/2",
+			setup: func(repo *mocks.MockLocationRepository) {
+				repo.EXPECT().GetLocation(gomock.Any(), int32(2)).Return(sqlcdb.Location{}, pgx.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid id",
+			target:     "/location/abc",
+			setup:      func(repo *mocks.MockLocationRepository) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockLocationRepository(ctrl)
+			tt.setup(repo)
+			h := NewLocationHandler(repo, testLogger)
+
+			w := performRequest(http.MethodGet, "/location/:id", h.GetByID, tt.target, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLocationHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	params := sqlcdb.CreateLocationParams{Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Merauke/Wamena"}
+	repo.EXPECT().CreateLocation(gomock.Any(), params).Return(sqlcdb.Location{ID: 7, Region: params.Region, Regency: params.Regency, Cluster: params.Cluster}, nil)
+
+	w := performRequest(http.MethodPost, "/location", h.Create, "/location", `{"region":"PAPUA","regency":"Jayapura","cluster":"Merauke/Wamena"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var location sqlcdb.Location
+	decodeResponse(t, w, &location)
+	if location.ID != 7 {
+		t.Fatalf("unexpected location: %+v", location)
+	}
+}
+
+func TestLocationHandlerCreateWithCoordinates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	params := sqlcdb.CreateLocationParams{
+		Region:    sqlcdb.RegionTypeMALUKU,
+		Regency:   "Kepulauan Aru",
+		Cluster:   "Dobo",
+		Latitude:  pgtype.Float8{Float64: -5.76, Valid: true},
+		Longitude: pgtype.Float8{Float64: 134.22, Valid: true},
+	}
+	repo.EXPECT().CreateLocation(gomock.Any(), params).Return(sqlcdb.Location{ID: 8, Latitude: params.Latitude, Longitude: params.Longitude}, nil)
+
+	w := performRequest(http.MethodPost, "/location", h.Create, "/location", `{"region":"MALUKU","regency":"Kepulauan Aru","cluster":"Dobo","latitude":-5.76,"longitude":134.22}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestLocationHandlerCreateRejectsInvalidCoordinates(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{name: "longitude out of range", body: `{"region":"PAPUA","regency":"Jayapura","cluster":"Sentani","latitude":-2.5,"longitude":181}`, field: "longitude"},
+		{name: "latitude out of range", body: `{"region":"PAPUA","regency":"Jayapura","cluster":"Sentani","latitude":95,"longitude":140.5}`, field: "latitude"},
+		{name: "longitude without latitude", body: `{"region":"PAPUA","regency":"Jayapura","cluster":"Sentani","longitude":140.5}`, field: "longitude"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			h := NewLocationHandler(mocks.NewMockLocationRepository(ctrl), testLogger)
+
+			w := performRequest(http.MethodPost, "/location", h.Create, "/location", tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+			if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != tt.field {
+				t.Fatalf("expected a %s error, got %+v", tt.field, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestLocationHandlerGetGeoJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	repo.EXPECT().
+		ListLocationsWithCoordinates(gomock.Any(), sqlcdb.ListLocationsWithCoordinatesParams{Region: pgtype.Text{String: "MALUKU", Valid: true}}).
+		Return([]sqlcdb.ListLocationsWithCoordinatesRow{{
+			ID: 8, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo",
+			Latitude: -5.76, Longitude: 134.22, StockItemCount: 3, NewStockQuantity: 10, UsedStockQuantity: 2, ToolsAlkerQuantity: 4,
+		}}, nil)
+
+	w := performRequest(http.MethodGet, "/location/geojson", h.GetGeoJSON, "/location/geojson?region=MALUKU", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var collection LocationFeatureCollection
+	decodeResponse(t, w, &collection)
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 1 {
+		t.Fatalf("unexpected collection: %+v", collection)
+	}
+	feature := collection.Features[0]
+	// GeoJSON puts longitude first
+	if feature.Geometry.Type != "Point" || feature.Geometry.Coordinates != [2]float64{134.22, -5.76} {
+		t.Fatalf("unexpected geometry: %+v", feature.Geometry)
+	}
+	if feature.Properties.Cluster != "Dobo" || feature.Properties.NewStockQuantity != 10 || feature.Properties.ToolsAlkerQuantity != 4 {
+		t.Fatalf("unexpected properties: %+v", feature.Properties)
+	}
+}
+
+func TestLocationHandlerCreateDuplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	repo.EXPECT().CreateLocation(gomock.Any(), gomock.Any()).Return(sqlcdb.Location{}, &pgconn.PgError{
+		Code:           "23505",
+		ConstraintName: "unique_location",
+	})
+
+	w := performRequest(http.MethodPost, "/location", h.Create, "/location", `{"region":"PAPUA","regency":"Jayapura","cluster":"Merauke/Wamena"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.ErrCodeDuplicate {
+		t.Fatalf("expected %s code, got %+v", utils.ErrCodeDuplicate, resp)
+	}
+}
+
+func TestLocationHandlerCreateUnknownCluster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	repo.EXPECT().CreateLocation(gomock.Any(), gomock.Any()).Return(sqlcdb.Location{}, &pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "location_cluster_fkey",
+	})
+
+	w := performRequest(http.MethodPost, "/location", h.Create, "/location", `{"region":"PAPUA","regency":"Jayapura","cluster":"Sentanii"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != "cluster" {
+		t.Fatalf("expected cluster field error, got %+v", resp.Errors)
+	}
+}
+
+func TestLocationHandlerDeleteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	repo.EXPECT().GetLocation(gomock.Any(), int32(5)).Return(sqlcdb.Location{ID: 5}, nil)
+	repo.EXPECT().CountLocationStockReferences(gomock.Any(), int32(5)).Return(int64(0), nil)
+	repo.EXPECT().DeleteLocation(gomock.Any(), int32(5)).Return(errors.New("violates foreign key constraint"))
+
+	w := performRequest(http.MethodDelete, "/location/:id", h.Delete, "/location/5", "")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Success {
+		t.Fatalf("expected failed response, got %+v", resp)
+	}
+}
+
+func TestLocationHandlerDeleteBlockedByStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	repo.EXPECT().GetLocation(gomock.Any(), int32(5)).Return(sqlcdb.Location{ID: 5}, nil)
+	repo.EXPECT().CountLocationStockReferences(gomock.Any(), int32(5)).Return(int64(3), nil)
+
+	w := performRequest(http.MethodDelete, "/location/:id", h.Delete, "/location/5", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.ErrCodeInUse {
+		t.Fatalf("expected %s code, got %+v", utils.ErrCodeInUse, resp)
+	}
+}
+
+func TestLocationHandlerDeactivate(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(repo *mocks.MockLocationRepository)
+		wantStatus int
+	}{
+		{
+			name: "deactivated",
+			setup: func(repo *mocks.MockLocationRepository) {
+				repo.EXPECT().
+					SetLocationActive(gomock.Any(), sqlcdb.SetLocationActiveParams{ID: 5, IsActive: false}).
+					Return(sqlcdb.Location{ID: 5, IsActive: false}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			setup: func(repo *mocks.MockLocationRepository) {
+				repo.EXPECT().
+					SetLocationActive(gomock.Any(), sqlcdb.SetLocationActiveParams{ID: 5, IsActive: false}).
+					Return(sqlcdb.Location{}, pgx.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockLocationRepository(ctrl)
+			tt.setup(repo)
+			h := NewLocationHandler(repo, testLogger)
+
+			w := performRequest(http.MethodPost, "/location/:id/deactivate", h.Deactivate, "/location/5/deactivate", "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLocationHandlerGetAllIncludeInactive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	repo.EXPECT().CountLocations(gomock.Any(), sqlcdb.CountLocationsParams{IncludeInactive: true}).Return(int64(1), nil)
+	repo.EXPECT().
+		ListLocations(gomock.Any(), sqlcdb.ListLocationsParams{IncludeInactive: true, Limit: 10}).
+		Return([]sqlcdb.Location{{ID: 5, IsActive: false}}, nil)
+
+	w := performRequest(http.MethodGet, "/location", h.GetAll, "/location?include_inactive=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performRequest(http.MethodGet, "/location", h.GetAll, "/location?include_inactive=maybe", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestLocationHandlerGetCompleteness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	region := pgtype.Text{String: "PAPUA", Valid: true}
+	repo.EXPECT().CountLocations(gomock.Any(), sqlcdb.CountLocationsParams{Region: region}).Return(int64(2), nil)
+	repo.EXPECT().
+		ListLocationCompleteness(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.ListLocationCompletenessParams) ([]sqlcdb.ListLocationCompletenessRow, error) {
+			if arg.Region != region || arg.Limit != 10 || arg.Offset != 0 {
+				t.Errorf("unexpected params: %+v", arg)
+			}
+			// opname_days=30 counts stock updated within the last 30 days
+			if days := time.Since(arg.OpnameSince.Time).Hours() / 24; days < 29.9 || days > 30.1 {
+				t.Errorf("unexpected opname since: %v", arg.OpnameSince.Time)
+			}
+			return []sqlcdb.ListLocationCompletenessRow{
+				{LocationID: 3, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Sentani", StockItems: 4, PhotographedItems: 1, Score: 6},
+				{LocationID: 8, Region: sqlcdb.RegionTypePAPUA, Regency: "Merauke", Cluster: "Kota", HasContactPerson: true, RecentStockOpname: true, Score: 100},
+			}, nil
+		})
+
+	w := performRequest(http.MethodGet, "/location/completeness", h.GetCompleteness, "/location/completeness?region=PAPUA&opname_days=30", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var report []LocationCompletenessResponse
+	decodeResponse(t, w, &report)
+	if len(report) != 2 || report[0].LocationID != 3 || report[0].Score != 6 || report[0].PhotographedItems != 1 || !report[1].HasContactPerson {
+		t.Fatalf("unexpected completeness report: %+v", report)
+	}
+}
+
+func TestLocationHandlerPatchKeepsOmittedFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	repo.EXPECT().GetLocation(gomock.Any(), int32(3)).Return(sqlcdb.Location{ID: 3}, nil)
+	repo.EXPECT().
+		PatchLocation(gomock.Any(), sqlcdb.PatchLocationParams{
+			ID:      3,
+			Cluster: pgtype.Text{String: "Dobo", Valid: true},
+		}).
+		Return(sqlcdb.Location{ID: 3, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo"}, nil)
+
+	w := performRequest(http.MethodPatch, "/location/:id", h.Patch, "/location/3", `{"cluster":"Dobo"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestLocationHandlerPatchRejectsInvalidBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "no fields", body: `{}`},
+		{name: "unknown region", body: `{"region":"JAWA"}`},
+		{name: "empty cluster", body: `{"cluster":""}`},
+		{name: "latitude out of range", body: `{"latitude":-91,"longitude":130.5}`},
+		{name: "latitude without longitude", body: `{"latitude":-3.7}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockLocationRepository(ctrl)
+			h := NewLocationHandler(repo, testLogger)
+
+			repo.EXPECT().GetLocation(gomock.Any(), int32(3)).Return(sqlcdb.Location{ID: 3}, nil)
+
+			w := performRequest(http.MethodPatch, "/location/:id", h.Patch, "/location/3", tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLocationHandlerRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLocationRepository(ctrl)
+	h := NewLocationHandler(repo, testLogger)
+
+	repo.EXPECT().RestoreLocation(gomock.Any(), int32(5)).Return(sqlcdb.Location{ID: 5, Cluster: "Dobo"}, nil)
+	repo.EXPECT().RestoreLocation(gomock.Any(), int32(6)).Return(sqlcdb.Location{}, pgx.ErrNoRows)
+	repo.EXPECT().RestoreLocation(gomock.Any(), int32(7)).Return(sqlcdb.Location{}, &pgconn.PgError{
+		Code:           "23505",
+		ConstraintName: "unique_location",
+	})
+
+	w := performRequest(http.MethodPost, "/location/:id/restore", h.Restore, "/location/5/restore", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Locations that are not deleted cannot be restored
+	w = performRequest(http.MethodPost, "/location/:id/restore", h.Restore, "/location/6/restore", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The key of a deleted location is free, so it may have been taken by a new location
+	w = performRequest(http.MethodPost, "/location/:id/restore", h.Restore, "/location/7/restore", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.ErrCodeDuplicate {
+		t.Fatalf("expected %s code, got %+v", utils.ErrCodeDuplicate, resp)
+	}
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MasterImportResponse reports what a master list import created, or would create on a dry
+// run, and which rows were skipped because the catalogue already has them
+type MasterImportResponse struct {
+	Rows    int               `json:"rows"`
+	DryRun  bool              `json:"dry_run"`
+	Created []MasterImportRow `json:"created"`
+	Skipped []MasterImportRow `json:"skipped"`
+}
+
+// MasterImportRow is one imported row; Line is its row number in the file and ID the created
+// or already existing master (unset for rows a dry run would create)
+type MasterImportRow struct {
+	Line     int             `json:"line"`
+	ID       int32           `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	ItemType sqlcdb.ItemType `json:"item_type"`
+	Unit     models.Unit     `json:"unit"`
+}
+
+// masterImportTemplate is the file layout MasterImportHandler.Import reads
+var masterImportTemplate = utils.ImportTemplate{
+	Sheet: "Master Import",
+	Columns: []utils.ImportTemplateColumn{
+		{Name: "name", Note: "Sparepart or tool name, at most 100 characters"},
+		{Name: "item_type", Options: []string{string(sqlcdb.ItemTypeSPAREPART), string(sqlcdb.ItemTypeTOOLSALKER)}},
+		{Name: "unit", Options: []string{string(models.UnitPiece), string(models.UnitMeter), string(models.UnitSet)}, Note: "Optional, PCS when empty"},
+	},
+	Example: []string{"BMS", string(sqlcdb.ItemTypeSPAREPART), string(models.UnitPiece)},
+	Rows:    maxImportRows,
+}
+
+// MasterImportHandler loads the sparepart and tools alker catalogue from spreadsheets
+type MasterImportHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartMasterRepository
+}
+
+func NewMasterImportHandler(queries repository.SparepartMasterRepository, logger *zap.Logger) *MasterImportHandler {
+	return &MasterImportHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Import the sparepart master list from a spreadsheet
+// @Description Create masters from a .csv or .xlsx file whose header row names the columns name and item_type (SPAREPART or TOOLS_ALKER), optionally with unit (PCS, METER or SET; PCS when empty). Names are compared case-insensitively: a row repeating an earlier row's name is an error, a row already in the catalogue with the same item type is skipped and one there with the other item type is an error. On any error nothing is created and the errors are reported per row as rows[<line>].<column>, or with error_format=xlsx as the uploaded rows with an Errors column. With dry_run=true nothing is written and the response lists what would be created.
+// @Tags Sparepart Master
+// @Accept multipart/form-data
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param file formData file true "Spreadsheet (.csv or .xlsx, max 10000 rows)"
+// @Param dry_run query bool false "Validate and report without creating anything" default(false)
+// @Param error_format query string false "Format of row errors (json, xlsx)" default(json)
+// @Success 200 {object} utils.Response{data=MasterImportResponse} "Dry run"
+// @Success 201 {object} utils.Response{data=MasterImportResponse}
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/master/import [post]
+func (h *MasterImportHandler) Import(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	var dryRun bool
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "dry_run", Message: "must be true or false"})
+		}
+		dryRun = parsed
+	}
+	errorFormat := c.DefaultQuery("error_format", "json")
+	if errorFormat != "json" && errorFormat != "xlsx" {
+		errs = append(errs, utils.FieldError{Field: "error_format", Message: "must be json or xlsx"})
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	records, ok := readImportFile(c, h.logger)
+	if !ok {
+		return
+	}
+
+	rows, errs := parseMasterImportRows(records)
+	if len(errs) > 0 {
+		h.importErrors(c, records, errs, errorFormat)
+		return
+	}
+
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, strings.ToLower(row.Name))
+	}
+	existing, err := h.queries.ListSparepartMastersMatchingNames(ctx, names)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to look up imported names", h.logger)
+		return
+	}
+	existingByName := make(map[string]sqlcdb.ListSparepart, len(existing))
+	for _, master := range existing {
+		existingByName[strings.ToLower(master.Name)] = master
+	}
+	for _, row := range rows {
+		if master, ok := existingByName[strings.ToLower(row.Name)]; ok && master.ItemType != row.ItemType {
+			errs = append(errs, utils.FieldError{
+				Field:   fmt.Sprintf("rows[%d].item_type", row.Line),
+				Message: fmt.Sprintf("%s already exists as %s", master.Name, master.ItemType),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		h.importErrors(c, records, errs, errorFormat)
+		return
+	}
+
+	response := MasterImportResponse{
+		Rows:    len(rows),
+		DryRun:  dryRun,
+		Created: []MasterImportRow{},
+		Skipped: []MasterImportRow{},
+	}
+	staged := make([]sqlcdb.CopySparepartMasterImportParams, 0, len(rows))
+	for _, row := range rows {
+		if master, ok := existingByName[strings.ToLower(row.Name)]; ok {
+			row.ID = master.ID
+			response.Skipped = append(response.Skipped, row)
+			continue
+		}
+		response.Created = append(response.Created, row)
+		staged = append(staged, sqlcdb.CopySparepartMasterImportParams{
+			Line:     int32(row.Line),
+			Name:     row.Name,
+			ItemType: row.ItemType,
+			Unit:     string(row.Unit),
+		})
+	}
+
+	if dryRun {
+		utils.Success(c, fmt.Sprintf("%d spareparts would be created, %d skipped", len(response.Created), len(response.Skipped)), response)
+		return
+	}
+
+	if len(staged) > 0 {
+		// The rows are copied into the staging table and moved into the master list by one statement
+		var created []sqlcdb.ListSparepart
+		err := h.queries.WithinSparepartMasterTransaction(ctx, func(repo repository.SparepartMasterRepository) error {
+			var err error
+			if _, err = repo.CopySparepartMasterImport(ctx, staged); err != nil {
+				return err
+			}
+			created, err = repo.CreateSparepartMastersFromImport(ctx)
+			return err
+		})
+		if err != nil {
+			utils.HandleError(c, err, "Failed to import spareparts", h.logger)
+			return
+		}
+
+		// Rows created by someone else since the lookup are skipped by the insert and are
+		// missing from the created masters
+		createdIDs := make(map[string]int32, len(created))
+		for _, master := range created {
+			createdIDs[strings.ToLower(master.Name)] = master.ID
+		}
+		inserted := response.Created[:0]
+		for _, row := range response.Created {
+			if id, ok := createdIDs[strings.ToLower(row.Name)]; ok {
+				row.ID = id
+				inserted = append(inserted, row)
+			} else {
+				response.Skipped = append(response.Skipped, row)
+			}
+		}
+		response.Created = inserted
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: fmt.Sprintf("%d spareparts imported successfully, %d skipped", len(response.Created), len(response.Skipped)),
+		Data:    response,
+	})
+}
+
+// @Summary Download the sparepart master import template
+// @Description Download an empty master list import file with the expected header row and an example row to replace. The .xlsx template offers the item types as a dropdown.
+// @Tags Sparepart Master
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param format query string false "Template format (xlsx, csv)" default(xlsx)
+// @Success 200 {file} file
+// @Router /sparepart/master/import/template [get]
+func (h *MasterImportHandler) Template(c *gin.Context) {
+	sendImportTemplate(c, masterImportTemplate, "master_import_template", h.logger)
+}
+
+// importErrors reports row errors as JSON or, when asked for and every error belongs to a
+// row, as a workbook of the uploaded rows with their errors
+func (h *MasterImportHandler) importErrors(c *gin.Context, records [][]string, errs []utils.FieldError, format string) {
+	if format != "xlsx" {
+		utils.ValidationError(c, errs...)
+		return
+	}
+	for _, e := range errs {
+		if !strings.HasPrefix(e.Field, "rows[") {
+			utils.ValidationError(c, errs...)
+			return
+		}
+	}
+
+	buf, err := utils.ImportErrorsToExcel(records, errs)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to generate error workbook", h.logger)
+		return
+	}
+	filename := fmt.Sprintf("master_import_errors_%s.xlsx", time.Now().UTC().Format("20060102_150405"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusBadRequest, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
+
+// parseMasterImportRows maps the header row to the import columns and parses every non-empty
+// row after it; names are trimmed with inner whitespace collapsed and must be unique within
+// the file
+func parseMasterImportRows(records [][]string) ([]MasterImportRow, []utils.FieldError) {
+	if len(records) == 0 {
+		return nil, []utils.FieldError{{Field: "file", Message: "is empty"}}
+	}
+
+	columns := utils.ImportColumns(records[0])
+	var errs []utils.FieldError
+	for _, name := range []string{"name", "item_type"} {
+		if _, ok := columns[name]; !ok {
+			errs = append(errs, utils.FieldError{Field: "file", Message: "missing column " + name})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	// unit is optional, a file without the column counts everything in pieces
+	value := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []MasterImportRow
+	seen := make(map[string]int)
+	for index, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		line := index + 2
+		field := func(name string) string {
+			return fmt.Sprintf("rows[%d].%s", line, name)
+		}
+		row := MasterImportRow{Line: line, Name: normalizeReferenceName(value(record, "name"))}
+
+		switch {
+		case row.Name == "":
+			errs = append(errs, utils.FieldError{Field: field("name"), Message: "is required"})
+		case utf8.RuneCountInString(row.Name) > 100:
+			errs = append(errs, utils.FieldError{Field: field("name"), Message: "must be at most 100 characters"})
+		}
+
+		// "Tools Alker" reads as TOOLS_ALKER
+		itemType := sqlcdb.ItemType(strings.ReplaceAll(strings.ToUpper(value(record, "item_type")), " ", "_"))
+		switch itemType {
+		case sqlcdb.ItemTypeSPAREPART, sqlcdb.ItemTypeTOOLSALKER:
+			row.ItemType = itemType
+		default:
+			errs = append(errs, utils.FieldError{Field: field("item_type"), Message: "must be SPAREPART or TOOLS_ALKER"})
+		}
+
+		row.Unit = models.Unit(strings.ToUpper(value(record, "unit")))
+		if row.Unit == "" {
+			row.Unit = models.UnitPiece
+		} else if !row.Unit.Valid() {
+			errs = append(errs, utils.FieldError{Field: field("unit"), Message: utils.UnitError.Message})
+		}
+
+		if row.Name != "" {
+			key := strings.ToLower(row.Name)
+			if first, ok := seen[key]; ok {
+				errs = append(errs, utils.FieldError{Field: field("name"), Message: fmt.Sprintf("duplicates row %d", first)})
+			} else {
+				seen[key] = line
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	switch {
+	case len(rows) == 0 && len(errs) == 0:
+		errs = append(errs, utils.FieldError{Field: "file", Message: "has no rows"})
+	case len(rows) > maxImportRows:
+		errs = []utils.FieldError{{Field: "file", Message: fmt.Sprintf("has %d rows, at most %d can be imported at once", len(rows), maxImportRows)}}
+	}
+	return rows, errs
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMasterImportHandlerImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewMasterImportHandler(repo, testLogger)
+
+	content := "Name,Item Type\n" +
+		"BMS,sparepart\n" +
+		"  Kunci   Inggris ,tools alker\n" +
+		",\n" +
+		"ehub,SPAREPART\n"
+
+	repo.EXPECT().
+		ListSparepartMastersMatchingNames(gomock.Any(), []string{"bms", "kunci inggris", "ehub"}).
+		Return([]sqlcdb.ListSparepart{{ID: 8, Name: "EHUB", ItemType: sqlcdb.ItemTypeSPAREPART}}, nil)
+	repo.EXPECT().
+		WithinSparepartMasterTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(repository.SparepartMasterRepository) error) error {
+			return fn(repo)
+		})
+	repo.EXPECT().
+		CopySparepartMasterImport(gomock.Any(), []sqlcdb.CopySparepartMasterImportParams{
+			// without a unit column everything is counted in pieces
+			{Line: 2, Name: "BMS", ItemType: sqlcdb.ItemTypeSPAREPART, Unit: "PCS"},
+			{Line: 3, Name: "Kunci Inggris", ItemType: sqlcdb.ItemTypeTOOLSALKER, Unit: "PCS"},
+		}).
+		Return(int64(2), nil)
+	repo.EXPECT().
+		CreateSparepartMastersFromImport(gomock.Any()).
+		Return([]sqlcdb.ListSparepart{
+			{ID: 20, Name: "BMS", ItemType: sqlcdb.ItemTypeSPAREPART},
+			{ID: 21, Name: "Kunci Inggris", ItemType: sqlcdb.ItemTypeTOOLSALKER},
+		}, nil)
+
+	w := performUpload(t, "/master/import", h.Import, "/master/import", "master.csv", content)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var data MasterImportResponse
+	decodeResponse(t, w, &data)
+	if data.Rows != 3 || len(data.Created) != 2 || data.Created[1] != (MasterImportRow{Line: 3, ID: 21, Name: "Kunci Inggris", ItemType: sqlcdb.ItemTypeTOOLSALKER, Unit: models.UnitPiece}) {
+		t.Fatalf("unexpected created rows: %+v", data)
+	}
+	if len(data.Skipped) != 1 || data.Skipped[0].Line != 5 || data.Skipped[0].ID != 8 {
+		t.Fatalf("unexpected skipped rows: %+v", data.Skipped)
+	}
+}
+
+func TestMasterImportHandlerImportDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewMasterImportHandler(repo, testLogger)
+
+	repo.EXPECT().ListSparepartMastersMatchingNames(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListSparepart{{ID: 8, Name: "EHUB", ItemType: sqlcdb.ItemTypeSPAREPART}}, nil)
+
+	w := performUpload(t, "/master/import", h.Import, "/master/import?dry_run=true", "master.csv", "name,item_type\nBMS,SPAREPART\nEHUB,SPAREPART\n")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var data MasterImportResponse
+	decodeResponse(t, w, &data)
+	if !data.DryRun || len(data.Created) != 1 || data.Created[0].Name != "BMS" || data.Created[0].ID != 0 || len(data.Skipped) != 1 {
+		t.Fatalf("unexpected dry run result: %+v", data)
+	}
+}
+
+func TestMasterImportHandlerImportReportsRowErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewMasterImportHandler(repo, testLogger)
+
+	content := "name,item_type\n" +
+		"BMS,SPAREPART\n" +
+		",SPAREPART\n" +
+		"Rectifier,BROKEN\n" +
+		"bms,TOOLS_ALKER\n"
+
+	w := performUpload(t, "/master/import", h.Import, "/master/import", "master.csv", content)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	want := map[string]string{
+		"rows[3].name":      "is required",
+		"rows[4].item_type": "must be SPAREPART or TOOLS_ALKER",
+		"rows[5].name":      "duplicates row 2",
+	}
+	if len(resp.Errors) != len(want) {
+		t.Fatalf("expected %d row errors, got %+v", len(want), resp.Errors)
+	}
+	for _, fieldErr := range resp.Errors {
+		if want[fieldErr.Field] != fieldErr.Message {
+			t.Fatalf("unexpected row error %+v", fieldErr)
+		}
+	}
+}
+
+func TestMasterImportHandlerImportRejectsOtherItemType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewMasterImportHandler(repo, testLogger)
+
+	repo.EXPECT().ListSparepartMastersMatchingNames(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListSparepart{{ID: 8, Name: "Tang", ItemType: sqlcdb.ItemTypeTOOLSALKER}}, nil)
+
+	w := performUpload(t, "/master/import", h.Import, "/master/import", "master.csv", "name,item_type\ntang,SPAREPART\n")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "rows[2].item_type" {
+		t.Fatalf("expected an item type error on row 2, got %+v", resp.Errors)
+	}
+}
+
+func TestMasterImportHandlerImportErrorWorkbook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewMasterImportHandler(repo, testLogger)
+
+	content := "name,item_type\nBMS,SPAREPART\nRectifier,BROKEN\n"
+	w := performUpload(t, "/master/import", h.Import, "/master/import?error_format=xlsx", "master.csv", content)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("expected an error workbook: %v", err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Errors")
+	if err != nil {
+		t.Fatalf("failed to read error workbook: %v", err)
+	}
+	if len(rows) != 3 || rows[0][2] != "Errors" || len(rows[1]) > 2 || rows[2][2] != "item_type must be SPAREPART or TOOLS_ALKER" {
+		t.Fatalf("unexpected error workbook rows: %q", rows)
+	}
+}
+
+func TestMasterImportHandlerTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewMasterImportHandler(repo, testLogger)
+
+	w := performRequest(http.MethodGet, "/master/import/template", h.Template, "/master/import/template", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("expected a workbook: %v", err)
+	}
+	defer f.Close()
+	validations, err := f.GetDataValidations("Master Import")
+	if err != nil || len(validations) != 3 || validations[1].Sqref != "B2:B10001" || validations[1].Formula1 != `"SPAREPART,TOOLS_ALKER"` ||
+		validations[2].Formula1 != `"PCS,METER,SET"` {
+		t.Fatalf("unexpected template validations: %+v %v", validations, err)
+	}
+
+	records, err := f.GetRows("Master Import")
+	if err != nil {
+		t.Fatalf("failed to read template: %v", err)
+	}
+	if rows, errs := parseMasterImportRows(records); len(errs) > 0 || len(rows) != 1 {
+		t.Fatalf("template does not parse: %+v %+v", rows, errs)
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/messaging"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// MessageDeliveryResponse is a message to a contact person with the outcome of its last attempt
+type MessageDeliveryResponse struct {
+	ID                int64   `json:"id"`
+	ContactPersonID   *int32  `json:"contact_person_id"`
+	LocationID        int32   `json:"location_id"`
+	Pic               string  `json:"pic"`
+	Phone             string  `json:"phone"`
+	Event             string  `json:"event"`
+	Body              *string `json:"body"`
+	Status            string  `json:"status"`
+	Attempts          int32   `json:"attempts"`
+	NextAttemptAt     string  `json:"next_attempt_at,omitempty"`
+	ProviderMessageID *string `json:"provider_message_id"`
+	LastError         *string `json:"last_error"`
+	CreatedAt         string  `json:"created_at"`
+	SentAt            string  `json:"sent_at,omitempty"`
+}
+
+func toMessageDeliveryResponse(delivery sqlcdb.MessageDelivery) MessageDeliveryResponse {
+	response := MessageDeliveryResponse{
+		ID:         delivery.ID,
+		LocationID: delivery.LocationID,
+		Pic:        delivery.Pic,
+		Phone:      delivery.Phone,
+		Event:      delivery.Event,
+		Status:     delivery.Status,
+		Attempts:   delivery.Attempts,
+		CreatedAt:  utils.FormatTimestamp(delivery.CreatedAt),
+		SentAt:     utils.FormatTimestamp(delivery.SentAt),
+	}
+	if delivery.Status == "PENDING" {
+		response.NextAttemptAt = utils.FormatTimestamp(delivery.NextAttemptAt)
+	}
+	if delivery.ContactPersonID.Valid {
+		response.ContactPersonID = &delivery.ContactPersonID.Int32
+	}
+	if delivery.Body.Valid {
+		response.Body = &delivery.Body.String
+	}
+	if delivery.ProviderMessageID.Valid {
+		response.ProviderMessageID = &delivery.ProviderMessageID.String
+	}
+	if delivery.LastError.Valid {
+		response.LastError = &delivery.LastError.String
+	}
+	return response
+}
+
+// MessageDeliveryHandler serves the SMS/WhatsApp messages sent to contact persons
+type MessageDeliveryHandler struct {
+	logger  *zap.Logger
+	queries repository.MessageDeliveryRepository
+}
+
+func NewMessageDeliveryHandler(queries repository.MessageDeliveryRepository, logger *zap.Logger) *MessageDeliveryHandler {
+	return &MessageDeliveryHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get contact person messages
+// @Description Get the messages sent to contact persons about stock transfers and sparepart requests of their location, newest first, with their delivery status
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param location_id query int false "Filter by location"
+// @Param status query string false "Filter by status" Enums(PENDING, SENT, FAILED)
+// @Param event query string false "Filter by event" Enums(stock.transferred_in, stock.transferred_out, request.created)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/admin/messages [get]
+func (h *MessageDeliveryHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	var filters sqlcdb.CountMessageDeliveriesParams
+	if value := c.Query("location_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "location_id", Message: "must be a positive integer"})
+		} else {
+			filters.LocationID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	switch status := c.Query("status"); status {
+	case "", "PENDING", "SENT", "FAILED":
+		filters.Status = utils.TextFilter(status)
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of PENDING, SENT, FAILED"})
+	}
+	switch event := c.Query("event"); event {
+	case "", messaging.EventTransferredIn, messaging.EventTransferredOut, messaging.EventRequestCreated:
+		filters.Event = utils.TextFilter(event)
+	default:
+		errs = append(errs, utils.FieldError{Field: "event", Message: "must be one of stock.transferred_in, stock.transferred_out, request.created"})
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountMessageDeliveries(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count messages", h.logger)
+		return
+	}
+
+	deliveries, err := h.queries.ListMessageDeliveries(ctx, sqlcdb.ListMessageDeliveriesParams{
+		LocationID: filters.LocationID,
+		Status:     filters.Status,
+		Event:      filters.Event,
+		Limit:      int32(pagination.Limit),
+		Offset:     int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get messages", h.logger)
+		return
+	}
+
+	response := make([]MessageDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, toMessageDeliveryResponse(delivery))
+	}
+
+	utils.SuccessWithPagination(c, "Messages retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
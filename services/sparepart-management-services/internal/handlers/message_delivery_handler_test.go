@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMessageDeliveryHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockMessageDeliveryRepository(ctrl)
+	h := NewMessageDeliveryHandler(repo, testLogger)
+
+	filters := sqlcdb.CountMessageDeliveriesParams{
+		LocationID: pgtype.Int4{Int32: 4, Valid: true},
+		Status:     pgtype.Text{String: "FAILED", Valid: true},
+	}
+	repo.EXPECT().CountMessageDeliveries(gomock.Any(), filters).Return(int64(1), nil)
+	repo.EXPECT().
+		ListMessageDeliveries(gomock.Any(), sqlcdb.ListMessageDeliveriesParams{LocationID: filters.LocationID, Status: filters.Status, Limit: 10}).
+		Return([]sqlcdb.MessageDelivery{{
+			ID: 9, LocationID: 4, Pic: "Hendra", Phone: "0812-1801-2082", Event: "stock.transferred_in", Status: "FAILED", Attempts: 8,
+			LastError: pgtype.Text{String: "provider returned status 400", Valid: true},
+		}}, nil)
+
+	w := performRequest(http.MethodGet, "/admin/messages", h.GetAll, "/admin/messages?location_id=4&status=FAILED", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var messages []MessageDeliveryResponse
+	decodeResponse(t, w, &messages)
+	if len(messages) != 1 || messages[0].LastError == nil || messages[0].Body != nil || messages[0].NextAttemptAt != "" {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+}
+
+func TestMessageDeliveryHandlerGetAllRejectsUnknownFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockMessageDeliveryRepository(ctrl)
+	h := NewMessageDeliveryHandler(repo, testLogger)
+
+	w := performRequest(http.MethodGet, "/admin/messages", h.GetAll, "/admin/messages?status=DELIVERED&event=stock.low", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 2 || resp.Errors[0].Field != "status" || resp.Errors[1].Field != "event" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
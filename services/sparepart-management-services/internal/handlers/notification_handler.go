@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// notificationChannels are the delivery channels a user can turn on or off; all are on by default
+var notificationChannels = []string{"IN_APP", "WHATSAPP", "TELEGRAM"}
+
+// NotificationResponse is an in-app notification of the requesting user
+type NotificationResponse struct {
+	ID        int64           `json:"id"`
+	Category  string          `json:"category"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data"`
+	Read      bool            `json:"read"`
+	ReadAt    string          `json:"read_at,omitempty"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// NotificationPreferenceItem is the delivery setting of one channel
+type NotificationPreferenceItem struct {
+	Channel string `json:"channel" binding:"required,oneof=IN_APP WHATSAPP TELEGRAM"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceItem `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// NotificationHandler serves the notification center of the requesting user (see utils.UserID)
+type NotificationHandler struct {
+	logger  *zap.Logger
+	queries repository.NotificationRepository
+}
+
+func NewNotificationHandler(queries repository.NotificationRepository, logger *zap.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get notifications
+// @Description Get the notifications of the requesting user, newest first
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param unread query bool false "Only unread (true) or only read (false) notifications"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /notifications [get]
+func (h *NotificationHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := utils.UserID(c)
+
+	var unread pgtype.Bool
+	if value := c.Query("unread"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.ValidationError(c, utils.FieldError{Field: "unread", Message: "must be true or false"})
+			return
+		}
+		unread = pgtype.Bool{Bool: parsed, Valid: true}
+	}
+
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountNotifications(ctx, sqlcdb.CountNotificationsParams{UserID: userID, Unread: unread})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count notifications", h.logger)
+		return
+	}
+
+	notifications, err := h.queries.ListNotifications(ctx, sqlcdb.ListNotificationsParams{
+		UserID: userID,
+		Unread: unread,
+		Limit:  int32(pagination.Limit),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get notifications", h.logger)
+		return
+	}
+
+	response := make([]NotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		response = append(response, toNotificationResponse(notification))
+	}
+
+	utils.SuccessWithPagination(c, "Notifications retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get unread notification count
+// @Description Get the number of unread notifications of the requesting user
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	unread, err := h.queries.CountNotifications(ctx, sqlcdb.CountNotificationsParams{
+		UserID: utils.UserID(c),
+		Unread: pgtype.Bool{Bool: true, Valid: true},
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count unread notifications", h.logger)
+		return
+	}
+
+	utils.Success(c, "Unread notification count retrieved successfully", gin.H{"unread": unread})
+}
+
+// @Summary Mark notification as read
+// @Description Mark one notification of the requesting user as read
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} utils.Response
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := utils.UserID(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "Invalid notification ID")
+		return
+	}
+
+	// Check if the notification exists and belongs to the user
+	_, err = h.queries.GetNotification(ctx, sqlcdb.GetNotificationParams{ID: id, UserID: userID})
+	if err != nil {
+		utils.NotFound(c, "Notification not found")
+		return
+	}
+
+	notification, err := h.queries.MarkNotificationRead(ctx, sqlcdb.MarkNotificationReadParams{ID: id, UserID: userID})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to mark notification as read", h.logger)
+		return
+	}
+
+	utils.Success(c, "Notification marked as read", toNotificationResponse(notification))
+}
+
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the requesting user as read
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	updated, err := h.queries.MarkAllNotificationsRead(ctx, utils.UserID(c))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to mark notifications as read", h.logger)
+		return
+	}
+
+	utils.Success(c, "Notifications marked as read", gin.H{"updated": updated})
+}
+
+// @Summary Get notification preferences
+// @Description Get the delivery preference of every channel for the requesting user
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	stored, err := h.queries.ListNotificationPreferences(ctx, utils.UserID(c))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get notification preferences", h.logger)
+		return
+	}
+
+	utils.Success(c, "Notification preferences retrieved successfully", mergePreferences(stored))
+}
+
+// @Summary Update notification preferences
+// @Description Turn delivery channels on or off for the requesting user; channels not listed keep their setting
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param request body UpdateNotificationPreferencesRequest true "Preferences"
+// @Success 200 {object} utils.Response
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := utils.UserID(c)
+
+	var req UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	for _, preference := range req.Preferences {
+		_, err := h.queries.UpsertNotificationPreference(ctx, sqlcdb.UpsertNotificationPreferenceParams{
+			UserID:  userID,
+			Channel: preference.Channel,
+			Enabled: *preference.Enabled,
+		})
+		if err != nil {
+			utils.HandleError(c, err, "Failed to update notification preferences", h.logger)
+			return
+		}
+	}
+
+	stored, err := h.queries.ListNotificationPreferences(ctx, userID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get notification preferences", h.logger)
+		return
+	}
+
+	utils.Success(c, "Notification preferences updated successfully", mergePreferences(stored))
+}
+
+// mergePreferences lists every channel, enabled unless the user turned it off
+func mergePreferences(stored []sqlcdb.NotificationPreference) []NotificationPreferenceItem {
+	enabled := make(map[string]bool, len(stored))
+	for _, preference := range stored {
+		enabled[preference.Channel] = preference.Enabled
+	}
+
+	preferences := make([]NotificationPreferenceItem, 0, len(notificationChannels))
+	for _, channel := range notificationChannels {
+		on, ok := enabled[channel]
+		if !ok {
+			on = true
+		}
+		preferences = append(preferences, NotificationPreferenceItem{Channel: channel, Enabled: &on})
+	}
+	return preferences
+}
+
+func toNotificationResponse(notification sqlcdb.Notification) NotificationResponse {
+	response := NotificationResponse{
+		ID:        notification.ID,
+		Category:  notification.Category,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Data:      json.RawMessage(notification.Data),
+		Read:      notification.ReadAt.Valid,
+		ReadAt:    utils.FormatTimestamp(notification.ReadAt),
+		CreatedAt: utils.FormatTimestamp(notification.CreatedAt),
+	}
+	if len(response.Data) == 0 {
+		response.Data = json.RawMessage("{}")
+	}
+	return response
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNotificationHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	h := NewNotificationHandler(repo, testLogger)
+
+	unread := pgtype.Bool{Bool: true, Valid: true}
+	repo.EXPECT().
+		CountNotifications(gomock.Any(), sqlcdb.CountNotificationsParams{UserID: "user-7", Unread: unread}).
+		Return(int64(1), nil)
+	repo.EXPECT().
+		ListNotifications(gomock.Any(), sqlcdb.ListNotificationsParams{UserID: "user-7", Unread: unread, Limit: 10, Offset: 0}).
+		Return([]sqlcdb.Notification{{ID: 3, UserID: "user-7", Category: "ALERT_RULE", Title: "Alert: Used BMS"}}, nil)
+
+	w := performRequestAs("user-7", http.MethodGet, "/notifications", h.GetAll, "/notifications?unread=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var notifications []NotificationResponse
+	decodeResponse(t, w, &notifications)
+	if len(notifications) != 1 || notifications[0].Read || string(notifications[0].Data) != "{}" {
+		t.Fatalf("unexpected notifications: %+v", notifications)
+	}
+}
+
+func TestNotificationHandlerMarkReadOtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	h := NewNotificationHandler(repo, testLogger)
+
+	repo.EXPECT().
+		GetNotification(gomock.Any(), sqlcdb.GetNotificationParams{ID: 3, UserID: "user-8"}).
+		Return(sqlcdb.Notification{}, errors.New("no rows in result set"))
+
+	w := performRequestAs("user-8", http.MethodPost, "/notifications/:id/read", h.MarkRead, "/notifications/3/read", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestNotificationHandlerUpdatePreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	h := NewNotificationHandler(repo, testLogger)
+
+	repo.EXPECT().
+		UpsertNotificationPreference(gomock.Any(), sqlcdb.UpsertNotificationPreferenceParams{UserID: "user-7", Channel: "WHATSAPP", Enabled: false}).
+		Return(sqlcdb.NotificationPreference{UserID: "user-7", Channel: "WHATSAPP"}, nil)
+	repo.EXPECT().
+		ListNotificationPreferences(gomock.Any(), "user-7").
+		Return([]sqlcdb.NotificationPreference{{UserID: "user-7", Channel: "WHATSAPP", Enabled: false}}, nil)
+
+	w := performRequestAs("user-7", http.MethodPut, "/notifications/preferences", h.UpdatePreferences, "/notifications/preferences",
+		`{"preferences": [{"channel": "WHATSAPP", "enabled": false}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var preferences []NotificationPreferenceItem
+	decodeResponse(t, w, &preferences)
+	if len(preferences) != 3 {
+		t.Fatalf("expected every channel, got %+v", preferences)
+	}
+	for _, preference := range preferences {
+		if want := preference.Channel != "WHATSAPP"; *preference.Enabled != want {
+			t.Fatalf("channel %s: expected enabled %v", preference.Channel, want)
+		}
+	}
+}
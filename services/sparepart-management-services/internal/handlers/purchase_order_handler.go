@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Purchase order statuses; an order moves from DRAFT to ORDERED when placed, then to PARTIAL
+// and RECEIVED as receipts for it are confirmed
+const (
+	orderStatusDraft    = "DRAFT"
+	orderStatusOrdered  = "ORDERED"
+	orderStatusPartial  = "PARTIAL"
+	orderStatusReceived = "RECEIVED"
+)
+
+// Errors ending a purchase order transaction early; the response is written after the rollback
+var (
+	errOrderNotFound = errors.New("purchase order not found")
+	errOrderStatus   = errors.New("purchase order has the wrong status")
+	errOrderInvalid  = errors.New("purchase order is invalid")
+)
+
+// PurchaseOrderItemRequest is a quantity of one sparepart and stock type to order
+type PurchaseOrderItemRequest struct {
+	SparepartID int              `json:"sparepart_id" binding:"required,min=1"`
+	StockType   models.StockType `json:"stock_type" binding:"required,oneof=NEW_STOCK USED_STOCK"`
+	Quantity    int              `json:"quantity" binding:"required,min=1"`
+}
+
+// CreatePurchaseOrderRequest orders spareparts from a supplier
+type CreatePurchaseOrderRequest struct {
+	SupplierID   int                        `json:"supplier_id" binding:"required,min=1"`
+	ExpectedDate *string                    `json:"expected_date"`
+	Notes        *string                    `json:"notes"`
+	Items        []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+// PurchaseOrderItemResponse is an order line with the quantity received for it so far by
+// confirmed goods receipts
+type PurchaseOrderItemResponse struct {
+	ID                  int32   `json:"id"`
+	SparepartID         int32   `json:"sparepart_id"`
+	SparepartName       *string `json:"sparepart_name,omitempty"`
+	StockType           string  `json:"stock_type"`
+	Quantity            int32   `json:"quantity"`
+	ReceivedQuantity    int64   `json:"received_quantity"`
+	OutstandingQuantity int64   `json:"outstanding_quantity"`
+}
+
+// PurchaseOrderResponse is a purchase order with the quantities of all its lines
+type PurchaseOrderResponse struct {
+	ID                  int32   `json:"id"`
+	Code                string  `json:"code"`
+	SupplierID          int32   `json:"supplier_id"`
+	Supplier            string  `json:"supplier"`
+	Status              string  `json:"status"`
+	ExpectedDate        *string `json:"expected_date"`
+	Notes               *string `json:"notes"`
+	OrderedQuantity     int64   `json:"ordered_quantity"`
+	ReceivedQuantity    int64   `json:"received_quantity"`
+	OutstandingQuantity int64   `json:"outstanding_quantity"`
+	CreatedBy           *string `json:"created_by"`
+	OrderedBy           *string `json:"ordered_by"`
+	OrderedAt           string  `json:"ordered_at,omitempty"`
+	CreatedAt           string  `json:"created_at"`
+	UpdatedAt           string  `json:"updated_at"`
+}
+
+// PurchaseOrderDetailResponse is a purchase order with its lines
+type PurchaseOrderDetailResponse struct {
+	PurchaseOrderResponse
+	Items []PurchaseOrderItemResponse `json:"items"`
+}
+
+// PurchaseOrderHandler tracks spareparts ordered from suppliers; goods receipts for an order
+// link to its lines, which is how the outstanding quantities are known
+type PurchaseOrderHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+}
+
+func NewPurchaseOrderHandler(queries repository.SparepartStockRepository, logger *zap.Logger) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Create purchase order
+// @Description Create a DRAFT purchase order of spareparts from the master list. Each sparepart and stock type can be ordered once per order.
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Param order body CreatePurchaseOrderRequest true "Purchase order data"
+// @Success 201 {object} utils.Response{data=PurchaseOrderDetailResponse}
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/purchase-orders [post]
+func (h *PurchaseOrderHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	var errs []utils.FieldError
+	var expectedDate pgtype.Date
+	if req.ExpectedDate != nil {
+		parsed, err := time.Parse("2006-01-02", *req.ExpectedDate)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "expected_date", Message: "must be a date in YYYY-MM-DD format"})
+		}
+		expectedDate = pgtype.Date{Time: parsed, Valid: err == nil}
+	}
+	seen := make(map[string]int, len(req.Items))
+	for i, item := range req.Items {
+		key := fmt.Sprintf("%d/%s", item.SparepartID, item.StockType)
+		if first, ok := seen[key]; ok {
+			errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d]", i), Message: fmt.Sprintf("duplicates items[%d]", first)})
+			continue
+		}
+		seen[key] = i
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	var id int32
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		order, err := repo.CreatePurchaseOrder(ctx, sqlcdb.CreatePurchaseOrderParams{
+			SupplierID:   int32(req.SupplierID),
+			ExpectedDate: expectedDate,
+			Notes:        utils.OptionalText(req.Notes),
+			CreatedBy:    utils.TextFilter(utils.UserID(c)),
+		})
+		if err != nil {
+			return err
+		}
+		id = order.ID
+
+		for i, item := range req.Items {
+			_, err := repo.CreatePurchaseOrderItem(ctx, sqlcdb.CreatePurchaseOrderItemParams{
+				OrderID:     order.ID,
+				StockType:   sqlcdb.StockType(item.StockType),
+				Quantity:    int32(item.Quantity),
+				SparepartID: int32(item.SparepartID),
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d].sparepart_id", i), Message: "does not exist"})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if len(errs) > 0 {
+			return errOrderInvalid
+		}
+		return nil
+	})
+	if !h.handleTransactionError(c, err, "", errs, "", "Failed to create purchase order") {
+		return
+	}
+
+	h.respond(c, http.StatusCreated, id, "Purchase order created successfully")
+}
+
+// @Summary Get purchase orders
+// @Description Get the purchase orders, newest first, with the ordered, received and outstanding quantities over all their lines
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (DRAFT, ORDERED, PARTIAL, RECEIVED)"
+// @Param supplier_id query int false "Filter by supplier ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse{data=[]PurchaseOrderResponse}
+// @Router /sparepart/purchase-orders [get]
+func (h *PurchaseOrderHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	var filters sqlcdb.CountPurchaseOrdersParams
+	switch status := c.Query("status"); status {
+	case "":
+	case orderStatusDraft, orderStatusOrdered, orderStatusPartial, orderStatusReceived:
+		filters.Status = utils.TextFilter(status)
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of DRAFT, ORDERED, PARTIAL, RECEIVED"})
+	}
+	if value := c.Query("supplier_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "supplier_id", Message: "must be a positive integer"})
+		} else {
+			filters.SupplierID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountPurchaseOrders(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count purchase orders", h.logger)
+		return
+	}
+
+	orders, err := h.queries.ListPurchaseOrders(ctx, sqlcdb.ListPurchaseOrdersParams{
+		Status:     filters.Status,
+		SupplierID: filters.SupplierID,
+		Limit:      int32(pagination.Limit),
+		Offset:     int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get purchase orders", h.logger)
+		return
+	}
+
+	response := make([]PurchaseOrderResponse, 0, len(orders))
+	for _, order := range orders {
+		row := toPurchaseOrderResponse(sqlcdb.GetPurchaseOrderRow{
+			ID:           order.ID,
+			SupplierID:   order.SupplierID,
+			Status:       order.Status,
+			ExpectedDate: order.ExpectedDate,
+			Notes:        order.Notes,
+			CreatedBy:    order.CreatedBy,
+			OrderedBy:    order.OrderedBy,
+			OrderedAt:    order.OrderedAt,
+			CreatedAt:    order.CreatedAt,
+			UpdatedAt:    order.UpdatedAt,
+			SupplierName: order.SupplierName,
+		})
+		row.OrderedQuantity = order.OrderedQuantity
+		row.ReceivedQuantity = order.ReceivedQuantity
+		row.OutstandingQuantity = order.OutstandingQuantity
+		response = append(response, row)
+	}
+
+	utils.SuccessWithPagination(c, "Purchase orders retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get purchase order by ID
+// @Description Get a purchase order with its lines and the quantity received and outstanding per line
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} utils.Response{data=PurchaseOrderDetailResponse}
+// @Failure 404 {object} utils.Response
+// @Router /sparepart/purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetByID(c *gin.Context) {
+	id, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+	h.respond(c, http.StatusOK, id, "Purchase order retrieved successfully")
+}
+
+// @Summary Place purchase order
+// @Description Mark a DRAFT purchase order as ORDERED once it is sent to the supplier; from then on goods receipts can be registered for it
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} utils.Response{data=PurchaseOrderDetailResponse}
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/purchase-orders/{id}/order [post]
+func (h *PurchaseOrderHandler) Place(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+
+	var status string
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		order, err := repo.GetPurchaseOrderForUpdate(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errOrderNotFound
+		}
+		if err != nil {
+			return err
+		}
+		status = order.Status
+		if order.Status != orderStatusDraft {
+			return errOrderStatus
+		}
+
+		_, err = repo.PlacePurchaseOrder(ctx, sqlcdb.PlacePurchaseOrderParams{
+			ID:        id,
+			OrderedBy: utils.TextFilter(utils.UserID(c)),
+		})
+		return err
+	})
+	if !h.handleTransactionError(c, err, status, nil, "Only DRAFT purchase orders can be placed", "Failed to place purchase order") {
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, "Purchase order placed successfully")
+}
+
+// handleTransactionError writes the response for an error returned by a purchase order
+// transaction and reports whether the transaction succeeded
+func (h *PurchaseOrderHandler) handleTransactionError(c *gin.Context, err error, status string, errs []utils.FieldError, statusMessage, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errOrderNotFound):
+		utils.NotFound(c, "Purchase order not found")
+	case errors.Is(err, errOrderStatus):
+		utils.Error(c, fmt.Sprintf("%s; this one is %s", statusMessage, status), http.StatusConflict)
+	case errors.Is(err, errOrderInvalid):
+		utils.ValidationError(c, errs...)
+	default:
+		utils.HandleError(c, err, message, h.logger)
+	}
+	return false
+}
+
+// respond writes the purchase order with its lines
+func (h *PurchaseOrderHandler) respond(c *gin.Context, code int, id int32, message string) {
+	ctx := c.Request.Context()
+
+	order, err := h.queries.GetPurchaseOrder(ctx, id)
+	if err != nil {
+		utils.NotFound(c, "Purchase order not found")
+		return
+	}
+
+	items, err := h.queries.ListPurchaseOrderItems(ctx, id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get purchase order items", h.logger)
+		return
+	}
+
+	response := PurchaseOrderDetailResponse{
+		PurchaseOrderResponse: toPurchaseOrderResponse(order),
+		Items:                 make([]PurchaseOrderItemResponse, 0, len(items)),
+	}
+	for _, item := range items {
+		response.OrderedQuantity += int64(item.Quantity)
+		response.ReceivedQuantity += item.ReceivedQuantity
+		response.OutstandingQuantity += item.OutstandingQuantity
+		response.Items = append(response.Items, toPurchaseOrderItemResponse(item))
+	}
+	c.JSON(code, utils.Response{
+		Success: true,
+		Message: message,
+		Data:    response,
+	})
+}
+
+func parseOrderID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid purchase order ID")
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func toPurchaseOrderResponse(row sqlcdb.GetPurchaseOrderRow) PurchaseOrderResponse {
+	response := PurchaseOrderResponse{
+		ID:         row.ID,
+		Code:       utils.PurchaseOrderCode(row.ID),
+		SupplierID: row.SupplierID,
+		Supplier:   row.SupplierName,
+		Status:     row.Status,
+		OrderedAt:  utils.FormatTimestamp(row.OrderedAt),
+		CreatedAt:  utils.FormatTimestamp(row.CreatedAt),
+		UpdatedAt:  utils.FormatTimestamp(row.UpdatedAt),
+	}
+	if row.ExpectedDate.Valid {
+		expected := row.ExpectedDate.Time.Format("2006-01-02")
+		response.ExpectedDate = &expected
+	}
+	if row.Notes.Valid {
+		response.Notes = &row.Notes.String
+	}
+	if row.CreatedBy.Valid {
+		response.CreatedBy = &row.CreatedBy.String
+	}
+	if row.OrderedBy.Valid {
+		response.OrderedBy = &row.OrderedBy.String
+	}
+	return response
+}
+
+func toPurchaseOrderItemResponse(row sqlcdb.ListPurchaseOrderItemsRow) PurchaseOrderItemResponse {
+	response := PurchaseOrderItemResponse{
+		ID:                  row.ID,
+		SparepartID:         row.SparepartID,
+		StockType:           string(row.StockType),
+		Quantity:            row.Quantity,
+		ReceivedQuantity:    row.ReceivedQuantity,
+		OutstandingQuantity: row.OutstandingQuantity,
+	}
+	if row.SparepartName.Valid {
+		response.SparepartName = &row.SparepartName.String
+	}
+	return response
+}
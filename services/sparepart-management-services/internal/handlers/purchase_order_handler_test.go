@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPurchaseOrderHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewPurchaseOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().
+		CreatePurchaseOrder(gomock.Any(), sqlcdb.CreatePurchaseOrderParams{
+			SupplierID:   2,
+			ExpectedDate: pgtype.Date{Time: time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC), Valid: true},
+		}).
+		Return(sqlcdb.PurchaseOrder{ID: 8}, nil)
+	repo.EXPECT().
+		CreatePurchaseOrderItem(gomock.Any(), sqlcdb.CreatePurchaseOrderItemParams{OrderID: 8, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 10}).
+		Return(sqlcdb.PurchaseOrderItem{ID: 30}, nil)
+	repo.EXPECT().GetPurchaseOrder(gomock.Any(), int32(8)).
+		Return(sqlcdb.GetPurchaseOrderRow{ID: 8, SupplierID: 2, SupplierName: "PT Sinar Jaya", Status: orderStatusDraft}, nil)
+	repo.EXPECT().ListPurchaseOrderItems(gomock.Any(), int32(8)).
+		Return([]sqlcdb.ListPurchaseOrderItemsRow{{ID: 30, OrderID: 8, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 10, OutstandingQuantity: 10}}, nil)
+
+	w := performRequest(http.MethodPost, "/purchase-orders", h.Create, "/purchase-orders",
+		`{"supplier_id": 2, "expected_date": "2026-11-30", "items": [{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 10}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var order PurchaseOrderDetailResponse
+	decodeResponse(t, w, &order)
+	if order.Code != "PO-000008" || order.Supplier != "PT Sinar Jaya" || order.OrderedQuantity != 10 || order.OutstandingQuantity != 10 || len(order.Items) != 1 {
+		t.Fatalf("unexpected order: %+v", order)
+	}
+}
+
+func TestPurchaseOrderHandlerCreateValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewPurchaseOrderHandler(repo, testLogger)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"missing supplier", `{"items": [{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 1}]}`, "supplier_id"},
+		{"no items", `{"supplier_id": 2, "items": []}`, "items"},
+		{"bad expected date", `{"supplier_id": 2, "expected_date": "30/11/2026", "items": [{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 1}]}`, "expected_date"},
+		{"duplicate item", `{"supplier_id": 2, "items": [{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 1}, {"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 2}]}`, "items[1]"},
+	}
+	for _, tt := range tests {
+		w := performRequest(http.MethodPost, "/purchase-orders", h.Create, "/purchase-orders", tt.body)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", tt.name, w.Code, w.Body.String())
+		}
+		resp := decodeResponse(t, w, nil)
+		if len(resp.Errors) != 1 || resp.Errors[0].Field != tt.want {
+			t.Fatalf("%s: unexpected field errors: %+v", tt.name, resp.Errors)
+		}
+	}
+}
+
+func TestPurchaseOrderHandlerCreateUnknownSparepart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewPurchaseOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().CreatePurchaseOrder(gomock.Any(), gomock.Any()).Return(sqlcdb.PurchaseOrder{ID: 8}, nil)
+	repo.EXPECT().CreatePurchaseOrderItem(gomock.Any(), gomock.Any()).Return(sqlcdb.PurchaseOrderItem{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/purchase-orders", h.Create, "/purchase-orders",
+		`{"supplier_id": 2, "items": [{"sparepart_id": 99, "stock_type": "NEW_STOCK", "quantity": 1}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "items[0].sparepart_id" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestPurchaseOrderHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewPurchaseOrderHandler(repo, testLogger)
+
+	status := pgtype.Text{String: orderStatusPartial, Valid: true}
+	repo.EXPECT().CountPurchaseOrders(gomock.Any(), sqlcdb.CountPurchaseOrdersParams{Status: status}).Return(int64(1), nil)
+	repo.EXPECT().
+		ListPurchaseOrders(gomock.Any(), sqlcdb.ListPurchaseOrdersParams{Status: status, Limit: 10}).
+		Return([]sqlcdb.ListPurchaseOrdersRow{{ID: 8, SupplierID: 2, SupplierName: "PT Sinar Jaya", Status: orderStatusPartial, OrderedQuantity: 12, ReceivedQuantity: 4, OutstandingQuantity: 8}}, nil)
+
+	w := performRequest(http.MethodGet, "/purchase-orders", h.GetAll, "/purchase-orders?status=PARTIAL", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var orders []PurchaseOrderResponse
+	decodeResponse(t, w, &orders)
+	if len(orders) != 1 || orders[0].ReceivedQuantity != 4 || orders[0].OutstandingQuantity != 8 {
+		t.Fatalf("unexpected orders: %+v", orders)
+	}
+
+	w = performRequest(http.MethodGet, "/purchase-orders", h.GetAll, "/purchase-orders?status=CANCELLED", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPurchaseOrderHandlerPlace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewPurchaseOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetPurchaseOrderForUpdate(gomock.Any(), int32(8)).Return(sqlcdb.PurchaseOrder{ID: 8, Status: orderStatusDraft}, nil)
+	repo.EXPECT().PlacePurchaseOrder(gomock.Any(), gomock.Any()).Return(sqlcdb.PurchaseOrder{ID: 8, Status: orderStatusOrdered}, nil)
+	repo.EXPECT().GetPurchaseOrder(gomock.Any(), int32(8)).Return(sqlcdb.GetPurchaseOrderRow{ID: 8, Status: orderStatusOrdered}, nil)
+	repo.EXPECT().ListPurchaseOrderItems(gomock.Any(), int32(8)).Return(nil, nil)
+
+	w := performRequest(http.MethodPost, "/purchase-orders/:id/order", h.Place, "/purchase-orders/8/order", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPurchaseOrderHandlerPlaceRequiresDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewPurchaseOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetPurchaseOrderForUpdate(gomock.Any(), int32(8)).Return(sqlcdb.PurchaseOrder{ID: 8, Status: orderStatusOrdered}, nil)
+
+	w := performRequest(http.MethodPost, "/purchase-orders/:id/order", h.Place, "/purchase-orders/8/order", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
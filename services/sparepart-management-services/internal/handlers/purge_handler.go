@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"time"
+
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// defaultPurgeDays is how long soft deleted records are kept unless stated otherwise
+const defaultPurgeDays = 30
+
+// PurgeRequest purges the records deleted at least older_than_days ago; 0 purges every
+// deleted record
+type PurgeRequest struct {
+	OlderThanDays *int `json:"older_than_days" binding:"omitempty,min=0,max=3650"`
+}
+
+// PurgeResponse counts the records removed for good
+type PurgeResponse struct {
+	DeletedBefore   string `json:"deleted_before"`
+	StockItems      int    `json:"stock_items"`
+	ToolsAlkerItems int    `json:"tools_alker_items"`
+	ContactPersons  int64  `json:"contact_persons"`
+	Locations       int64  `json:"locations"`
+	Masters         int64  `json:"masters"`
+	Photos          int    `json:"photos"`
+}
+
+// PurgeHandler permanently removes soft deleted records, with their photos
+type PurgeHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+	uploads *uploads.Service
+}
+
+func NewPurgeHandler(queries repository.SparepartStockRepository, uploads *uploads.Service, logger *zap.Logger) *PurgeHandler {
+	return &PurgeHandler{
+		logger:  logger,
+		queries: queries,
+		uploads: uploads,
+	}
+}
+
+// @Summary Purge deleted records
+// @Description Permanently delete the stock items, tools alker items, contact persons, locations and sparepart masters soft deleted at least older_than_days (default 30) ago, with their photos; a purged location takes its items and contact persons along, and a location or master stays while items or history records (stock opnames, sparepart requests, goods receipts, work orders) still refer to it
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param purge body PurgeRequest false "Purge options"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/admin/purge [post]
+func (h *PurgeHandler) Purge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req PurgeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BindingError(c, err)
+			return
+		}
+	}
+	days := defaultPurgeDays
+	if req.OlderThanDays != nil {
+		days = *req.OlderThanDays
+	}
+	deletedBefore := time.Now().UTC().AddDate(0, 0, -days)
+	cutoff := pgtype.Timestamptz{Time: deletedBefore, Valid: true}
+
+	resp := PurgeResponse{DeletedBefore: deletedBefore.Format(time.RFC3339)}
+	var photos []string
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		stocks, err := repo.PurgeSparepartStocks(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		tools, err := repo.PurgeToolsAlkers(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		contacts, err := repo.PurgeContactPersons(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		locations, err := repo.PurgeLocations(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		// After the items, so masters only they referred to can go too
+		masters, err := repo.PurgeSparepartMasters(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+
+		resp.StockItems = len(stocks)
+		resp.ToolsAlkerItems = len(tools)
+		resp.ContactPersons = contacts
+		resp.Locations = locations
+		resp.Masters = masters
+		for _, item := range stocks {
+			photos = append(photos, utils.PhotoPaths(utils.ParsePhotos(item.Documentation))...)
+		}
+		for _, item := range tools {
+			photos = append(photos, utils.PhotoPaths(utils.ParsePhotos(item.Documentation))...)
+		}
+		return nil
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to purge deleted records", h.logger)
+		return
+	}
+
+	// Photos are only removed once the rows are gone for good
+	for _, path := range photos {
+		if err := h.uploads.DeleteFile(ctx, path, h.logger); err != nil {
+			utils.RequestLogger(ctx, h.logger).Warn("Failed to delete file", zap.Error(err), zap.String("path", path))
+		}
+	}
+	resp.Photos = len(photos)
+
+	utils.Success(c, "Deleted records purged successfully", resp)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/storage"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPurgeHandlerPurge(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+	files := storage.NewLocal(dir)
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewPurgeHandler(repo, uploader, testLogger)
+
+	ctx := context.Background()
+	for _, key := range []string{"sparepart/new_stock/a.jpg", "tools_alker/b.jpg"} {
+		if err := files.Put(ctx, key, strings.NewReader("jpg"), 3, "image/jpeg"); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+
+	var cutoff pgtype.Timestamptz
+	expectTransaction(repo)
+	repo.EXPECT().PurgeSparepartStocks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, deletedBefore pgtype.Timestamptz) ([]sqlcdb.PurgeSparepartStocksRow, error) {
+			cutoff = deletedBefore
+			return []sqlcdb.PurgeSparepartStocksRow{
+				{ID: 4, Documentation: []byte(`["/uploads/sparepart/new_stock/a.jpg"]`)},
+				{ID: 5, Documentation: []byte(`[]`)},
+			}, nil
+		})
+	repo.EXPECT().PurgeToolsAlkers(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.PurgeToolsAlkersRow{{ID: 8, Documentation: []byte(`["/uploads/tools_alker/b.jpg"]`)}}, nil)
+	repo.EXPECT().PurgeContactPersons(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+	repo.EXPECT().PurgeLocations(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+	repo.EXPECT().PurgeSparepartMasters(gomock.Any(), gomock.Any()).Return(int64(3), nil)
+
+	w := performRequest(http.MethodPost, "/admin/purge", h.Purge, "/admin/purge", `{"older_than_days":7}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp PurgeResponse
+	decodeResponse(t, w, &resp)
+	if resp.StockItems != 2 || resp.ToolsAlkerItems != 1 || resp.ContactPersons != 2 || resp.Locations != 1 || resp.Masters != 3 || resp.Photos != 2 {
+		t.Fatalf("unexpected purge result: %+v", resp)
+	}
+	if age := time.Since(cutoff.Time); !cutoff.Valid || age < 7*24*time.Hour || age > 7*24*time.Hour+time.Minute {
+		t.Fatalf("expected a cutoff 7 days ago, got %+v", cutoff)
+	}
+	for _, key := range []string{"sparepart/new_stock/a.jpg", "tools_alker/b.jpg"} {
+		if exists, _ := files.Exists(ctx, key); exists {
+			t.Fatalf("expected the purged item's photo %s to be deleted", key)
+		}
+	}
+}
+
+func TestPurgeHandlerPurgeRejectsNegativeDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewPurgeHandler(repo, nil, testLogger)
+
+	w := performRequest(http.MethodPost, "/admin/purge", h.Purge, "/admin/purge", `{"older_than_days":-1}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
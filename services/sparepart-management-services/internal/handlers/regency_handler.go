@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"net/http"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RegencyRequest holds a regency's region and name
+type RegencyRequest struct {
+	Region string `json:"region" binding:"required,oneof=MALUKU MALUKU_UTARA PAPUA PAPUA_BARAT PAPUA_BARAT_DAYA PAPUA_SELATAN"`
+	Name   string `json:"name" binding:"required,max=100"`
+}
+
+type RegencyHandler struct {
+	logger  *zap.Logger
+	queries repository.RegencyRepository
+}
+
+func NewRegencyHandler(queries repository.RegencyRepository, logger *zap.Logger) *RegencyHandler {
+	return &RegencyHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get all regencies
+// @Description Get the regency reference list ordered by region and name
+// @Tags Regency
+// @Accept json
+// @Produce json
+// @Param region query string false "Filter by region"
+// @Param name query string false "Filter by name (partial match)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /regency [get]
+func (h *RegencyHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	region := utils.TextFilter(c.Query("region"))
+	name := utils.TextFilter(c.Query("name"))
+
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountRegencies(ctx, sqlcdb.CountRegenciesParams{
+		Region: region,
+		Name:   name,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count regencies", h.logger)
+		return
+	}
+
+	regencies, err := h.queries.ListRegencies(ctx, sqlcdb.ListRegenciesParams{
+		Region: region,
+		Name:   name,
+		Limit:  int32(pagination.Limit),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get regencies", h.logger)
+		return
+	}
+
+	utils.SuccessWithPagination(c, "Regencies retrieved successfully", regencies, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get regency by ID
+// @Description Get a single regency by ID
+// @Tags Regency
+// @Accept json
+// @Produce json
+// @Param id path int true "Regency ID"
+// @Success 200 {object} utils.Response
+// @Router /regency/{id} [get]
+func (h *RegencyHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid regency ID")
+		return
+	}
+
+	regency, err := h.queries.GetRegency(c.Request.Context(), int32(id))
+	if err != nil {
+		utils.NotFound(c, "Regency not found")
+		return
+	}
+
+	utils.Success(c, "Regency retrieved successfully", regency)
+}
+
+// @Summary Create regency
+// @Description Create a regency; its name is stored with whitespace collapsed
+// @Tags Regency
+// @Accept json
+// @Produce json
+// @Param regency body RegencyRequest true "Regency data"
+// @Success 201 {object} utils.Response
+// @Router /regency [post]
+func (h *RegencyHandler) Create(c *gin.Context) {
+	var req RegencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := normalizeReferenceName(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.BlankNameError)
+		return
+	}
+
+	regency, err := h.queries.CreateRegency(c.Request.Context(), sqlcdb.CreateRegencyParams{
+		Region: sqlcdb.RegionType(req.Region),
+		Name:   name,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create regency", h.logger)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Regency created successfully",
+		Data:    regency,
+	})
+}
+
+// @Summary Update regency
+// @Description Rename a regency or move it to another region; its clusters and locations follow
+// @Tags Regency
+// @Accept json
+// @Produce json
+// @Param id path int true "Regency ID"
+// @Param regency body RegencyRequest true "Regency data"
+// @Success 200 {object} utils.Response
+// @Router /regency/{id} [put]
+func (h *RegencyHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid regency ID")
+		return
+	}
+
+	// Check if regency exists
+	_, err = h.queries.GetRegency(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Regency not found")
+		return
+	}
+
+	var req RegencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := normalizeReferenceName(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.BlankNameError)
+		return
+	}
+
+	regency, err := h.queries.UpdateRegency(ctx, sqlcdb.UpdateRegencyParams{
+		ID:     int32(id),
+		Region: sqlcdb.RegionType(req.Region),
+		Name:   name,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update regency", h.logger)
+		return
+	}
+
+	utils.Success(c, "Regency updated successfully", regency)
+}
+
+// @Summary Delete regency
+// @Description Delete a regency; refused while clusters still belong to it
+// @Tags Regency
+// @Accept json
+// @Produce json
+// @Param id path int true "Regency ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /regency/{id} [delete]
+func (h *RegencyHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid regency ID")
+		return
+	}
+
+	// Check if regency exists
+	_, err = h.queries.GetRegency(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Regency not found")
+		return
+	}
+
+	err = h.queries.DeleteRegency(ctx, int32(id))
+	if err != nil {
+		if utils.IsForeignKeyViolation(err) {
+			c.JSON(http.StatusConflict, utils.Response{
+				Error: "Regency still has clusters",
+				Code:  utils.ErrCodeInUse,
+			})
+			return
+		}
+		utils.HandleError(c, err, "Failed to delete regency", h.logger)
+		return
+	}
+
+	utils.Success(c, "Regency deleted successfully", nil)
+}
+
+// normalizeReferenceName trims a regency or cluster name and collapses inner whitespace, the
+// same normalization the location hierarchy migration applied to existing names
+func normalizeReferenceName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegencyHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRegencyRepository(ctrl)
+	h := NewRegencyHandler(repo, testLogger)
+
+	region := pgtype.Text{String: "PAPUA", Valid: true}
+	repo.EXPECT().CountRegencies(gomock.Any(), sqlcdb.CountRegenciesParams{Region: region}).Return(int64(1), nil)
+	repo.EXPECT().
+		ListRegencies(gomock.Any(), sqlcdb.ListRegenciesParams{Region: region, Limit: 10}).
+		Return([]sqlcdb.Regency{{ID: 4, Region: sqlcdb.RegionTypePAPUA, Name: "Jayapura"}}, nil)
+
+	w := performRequest(http.MethodGet, "/regency", h.GetAll, "/regency?region=PAPUA", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var regencies []sqlcdb.Regency
+	resp := decodeResponse(t, w, &regencies)
+	if len(regencies) != 1 || regencies[0].Name != "Jayapura" || resp.Pagination.Total != 1 {
+		t.Fatalf("unexpected regencies: %+v", regencies)
+	}
+}
+
+func TestRegencyHandlerCreateNormalizesName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRegencyRepository(ctrl)
+	h := NewRegencyHandler(repo, testLogger)
+
+	params := sqlcdb.CreateRegencyParams{Region: sqlcdb.RegionTypeMALUKU, Name: "Kepulauan Aru"}
+	repo.EXPECT().CreateRegency(gomock.Any(), params).Return(sqlcdb.Regency{ID: 2, Region: params.Region, Name: params.Name}, nil)
+
+	w := performRequest(http.MethodPost, "/regency", h.Create, "/regency", `{"region":"MALUKU","name":"  Kepulauan   Aru "}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRegencyHandlerCreateRejectsBlankName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRegencyRepository(ctrl)
+	h := NewRegencyHandler(repo, testLogger)
+
+	w := performRequest(http.MethodPost, "/regency", h.Create, "/regency", `{"region":"MALUKU","name":"   "}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != "name" {
+		t.Fatalf("expected name field error, got %+v", resp.Errors)
+	}
+}
+
+func TestRegencyHandlerCreateDuplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRegencyRepository(ctrl)
+	h := NewRegencyHandler(repo, testLogger)
+
+	repo.EXPECT().CreateRegency(gomock.Any(), gomock.Any()).Return(sqlcdb.Regency{}, &pgconn.PgError{
+		Code:           "23505",
+		ConstraintName: "unique_regency",
+	})
+
+	w := performRequest(http.MethodPost, "/regency", h.Create, "/regency", `{"region":"PAPUA","name":"Jayapura"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRegencyHandlerUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRegencyRepository(ctrl)
+	h := NewRegencyHandler(repo, testLogger)
+
+	params := sqlcdb.UpdateRegencyParams{ID: 4, Region: sqlcdb.RegionTypePAPUASELATAN, Name: "Merauke"}
+	repo.EXPECT().GetRegency(gomock.Any(), int32(4)).Return(sqlcdb.Regency{ID: 4}, nil)
+	repo.EXPECT().UpdateRegency(gomock.Any(), params).Return(sqlcdb.Regency{ID: 4, Region: params.Region, Name: params.Name}, nil)
+
+	w := performRequest(http.MethodPut, "/regency/:id", h.Update, "/regency/4", `{"region":"PAPUA_SELATAN","name":"Merauke"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRegencyHandlerDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(repo *mocks.MockRegencyRepository)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "deleted",
+			setup: func(repo *mocks.MockRegencyRepository) {
+				repo.EXPECT().GetRegency(gomock.Any(), int32(4)).Return(sqlcdb.Regency{ID: 4}, nil)
+				repo.EXPECT().DeleteRegency(gomock.Any(), int32(4)).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "still has clusters",
+			setup: func(repo *mocks.MockRegencyRepository) {
+				repo.EXPECT().GetRegency(gomock.Any(), int32(4)).Return(sqlcdb.Regency{ID: 4}, nil)
+				repo.EXPECT().DeleteRegency(gomock.Any(), int32(4)).Return(&pgconn.PgError{
+					Code:           "23503",
+					ConstraintName: "cluster_regency_fkey",
+				})
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   utils.ErrCodeInUse,
+		},
+		{
+			name: "not found",
+			setup: func(repo *mocks.MockRegencyRepository) {
+				repo.EXPECT().GetRegency(gomock.Any(), int32(4)).Return(sqlcdb.Regency{}, pgx.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockRegencyRepository(ctrl)
+			tt.setup(repo)
+			h := NewRegencyHandler(repo, testLogger)
+
+			w := performRequest(http.MethodDelete, "/regency/:id", h.Delete, "/regency/4", "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if resp := decodeResponse(t, w, nil); resp.Code != tt.wantCode {
+				t.Fatalf("expected code %q, got %q", tt.wantCode, resp.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/storage"
+	"sparepart-management-services/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// StoredReportResponse is returned when an export is stored instead of downloaded
+type StoredReportResponse struct {
+	Filename  string `json:"filename"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// exportBatchSize is the number of rows read per query while writing an export
+const exportBatchSize = 500
+
+// sendExport writes a generated export as a file download, or stores it and
+// returns a short-lived shareable link when the request has store=true; the link is
+// also sent to the requesting user's notification center
+func sendExport(c *gin.Context, store *reports.Store, data []byte, filename string, contentType string, logger *zap.Logger) {
+	if c.Query("store") != "true" {
+		c.Header("Content-Disposition", "attachment; filename="+filename)
+		c.Header("Content-Type", contentType)
+		c.Data(http.StatusOK, contentType, data)
+		return
+	}
+
+	ctx := c.Request.Context()
+	storedName, err := store.Save(ctx, data, filename, logger)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to store report", logger)
+		return
+	}
+
+	url, expiresAt := store.Link(storedName, time.Now())
+	// The link is in the response as well, so a failed notification does not fail the export
+	if err := store.Notify(ctx, utils.UserID(c), filename, url, expiresAt); err != nil {
+		utils.RequestLogger(ctx, logger).Warn("Failed to notify stored report", zap.String("filename", filename), zap.Error(err))
+	}
+
+	utils.Success(c, "Report stored successfully", StoredReportResponse{
+		Filename:  filename,
+		URL:       url,
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// streamExport writes an export produced by write straight to the response as a file
+// download, so large exports are never held in memory. With store=true the export is
+// buffered and stored through sendExport instead. Once part of the file has been sent a
+// failure can no longer become an error response; the request is aborted instead, which
+// also keeps it out of the export log.
+func streamExport(c *gin.Context, store *reports.Store, filename string, contentType string, write func(io.Writer) error, logger *zap.Logger) {
+	if c.Query("store") == "true" {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			utils.HandleError(c, err, "Failed to generate export", logger)
+			return
+		}
+		sendExport(c, store, buf.Bytes(), filename, contentType, logger)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", contentType)
+	if err := write(c.Writer); err != nil {
+		if !c.Writer.Written() {
+			// Nothing was sent yet, so the file headers can still give way to an error response
+			c.Writer.Header().Del("Content-Disposition")
+			c.Writer.Header().Del("Content-Type")
+			utils.HandleError(c, err, "Failed to generate export", logger)
+			return
+		}
+		utils.RequestLogger(c.Request.Context(), logger).Error("Export stream interrupted", zap.String("filename", filename), zap.Error(err))
+		c.Abort()
+	}
+}
+
+type ReportHandler struct {
+	logger  *zap.Logger
+	reports *reports.Store
+}
+
+func NewReportHandler(reports *reports.Store, logger *zap.Logger) *ReportHandler {
+	return &ReportHandler{
+		logger:  logger,
+		reports: reports,
+	}
+}
+
+// @Summary Download stored report
+// @Description Download a stored export through its shareable link token
+// @Tags Report
+// @Produce application/octet-stream
+// @Param token path string true "Report link token"
+// @Success 200 {file} application/octet-stream
+// @Router /sparepart/reports/{token} [get]
+func (h *ReportHandler) Download(c *gin.Context) {
+	storedName, err := h.reports.Verify(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, reports.ErrExpiredLink) {
+			utils.Error(c, "Report link has expired", http.StatusGone)
+			return
+		}
+		utils.NotFound(c, "Report not found")
+		return
+	}
+
+	body, info, err := h.reports.Open(c.Request.Context(), storedName)
+	if errors.Is(err, storage.ErrNotExist) {
+		utils.NotFound(c, "Report not found")
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to open report", h.logger)
+		return
+	}
+	defer body.Close()
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.DataFromReader(http.StatusOK, info.Size, contentType, body, map[string]string{
+		"Content-Disposition": "attachment; filename=" + reports.DownloadName(storedName),
+	})
+}
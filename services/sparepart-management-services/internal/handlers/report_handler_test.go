@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStoredReportDownload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dir := t.TempDir()
+	store, err := reports.New(config.ReportConfig{LinkSecret: "test-secret", LinkTTL: time.Hour}, storage.NewLocal(dir), "/api/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifications := mocks.NewMockNotificationRepository(ctrl)
+	store.SetNotifications(notifications)
+
+	notifications.EXPECT().CreateNotifications(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg sqlcdb.CreateNotificationsParams) (int64, error) {
+			if len(arg.UserIds) != 1 || arg.UserIds[0] != "budi" || arg.Category != reports.NotificationCategory ||
+				!strings.Contains(arg.Body, "/api/v1/sparepart/reports/") {
+				t.Fatalf("unexpected notification: %+v", arg)
+			}
+			return 1, nil
+		})
+
+	export := func(c *gin.Context) {
+		sendExport(c, store, []byte("id,name\n1,Baterai\n"), "stock.csv", "text/csv; charset=utf-8", testLogger)
+	}
+	w := performRequestAs("budi", http.MethodGet, "/export", export, "/export?store=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var stored StoredReportResponse
+	decodeResponse(t, w, &stored)
+	token, ok := strings.CutPrefix(stored.URL, "/api/v1/sparepart/reports/")
+	if !ok || stored.Filename != "stock.csv" {
+		t.Fatalf("unexpected stored report: %+v", stored)
+	}
+
+	h := NewReportHandler(store, testLogger)
+	w = performRequest(http.MethodGet, "/reports/:token", h.Download, "/reports/"+token, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Body.String() != "id,name\n1,Baterai\n" || w.Header().Get("Content-Disposition") != "attachment; filename=stock.csv" ||
+		w.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected download %q with headers %v", w.Body.String(), w.Header())
+	}
+
+	// A tampered link is not found, nor is a report removed from storage
+	w = performRequest(http.MethodGet, "/reports/:token", h.Download, "/reports/"+token+"x", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for a tampered link, got %d: %s", w.Code, w.Body.String())
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one stored report, got %v, %v", entries, err)
+	}
+	if err := os.Remove(filepath.Join(dir, entries[0].Name())); err != nil {
+		t.Fatal(err)
+	}
+	w = performRequest(http.MethodGet, "/reports/:token", h.Download, "/reports/"+token, "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for a removed report, got %d: %s", w.Code, w.Body.String())
+	}
+}
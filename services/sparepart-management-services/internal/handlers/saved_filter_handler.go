@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// savedFilterParams are the query params of each listing that a saved filter may hold
+var savedFilterParams = map[models.ItemType]map[string]bool{
+	models.ItemTypeSparepart:  {"sparepart_name": true, "region": true, "regency": true, "cluster": true, "stock_type": true},
+	models.ItemTypeToolsAlker: {"sparepart_name": true, "region": true, "regency": true, "cluster": true},
+}
+
+// CreateSavedFilterRequest saves a named filter set for the stock (SPAREPART) or tools alker (TOOLS_ALKER) listing
+type CreateSavedFilterRequest struct {
+	ItemType models.ItemType `json:"item_type" binding:"required,oneof=SPAREPART TOOLS_ALKER"`
+	SavedFilterFields
+}
+
+// UpdateSavedFilterRequest replaces the fields of a saved filter; its listing cannot change
+type UpdateSavedFilterRequest struct {
+	SavedFilterFields
+}
+
+type SavedFilterFields struct {
+	Name    string            `json:"name" binding:"required,max=100"`
+	Filters map[string]string `json:"filters"`
+	Sort    *string           `json:"sort" binding:"omitempty,max=50"`
+	GroupBy *string           `json:"group_by" binding:"omitempty,max=50"`
+}
+
+// SavedFilterResponse is a saved filter of the requesting user
+type SavedFilterResponse struct {
+	ID        int32             `json:"id"`
+	ItemType  string            `json:"item_type"`
+	Name      string            `json:"name"`
+	Filters   map[string]string `json:"filters"`
+	Sort      *string           `json:"sort"`
+	GroupBy   *string           `json:"group_by"`
+	IsDefault bool              `json:"is_default"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
+}
+
+// SavedFilterHandler serves the saved listing filters of the requesting user (see utils.UserID)
+type SavedFilterHandler struct {
+	logger  *zap.Logger
+	queries repository.SavedFilterRepository
+}
+
+func NewSavedFilterHandler(queries repository.SavedFilterRepository, logger *zap.Logger) *SavedFilterHandler {
+	return &SavedFilterHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get saved filters
+// @Description Get the saved filters of the requesting user; the default of each listing comes first
+// @Tags Saved Filters
+// @Accept json
+// @Produce json
+// @Param item_type query string false "Filter by listing (SPAREPART, TOOLS_ALKER)"
+// @Success 200 {object} utils.Response
+// @Router /saved-filters [get]
+func (h *SavedFilterHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters, err := h.queries.ListSavedFilters(ctx, sqlcdb.ListSavedFiltersParams{
+		UserID:   utils.UserID(c),
+		ItemType: utils.TextFilter(c.Query("item_type")),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get saved filters", h.logger)
+		return
+	}
+
+	response := make([]SavedFilterResponse, 0, len(filters))
+	for _, filter := range filters {
+		response = append(response, toSavedFilterResponse(filter))
+	}
+
+	utils.Success(c, "Saved filters retrieved successfully", response)
+}
+
+// @Summary Create saved filter
+// @Description Save a named filter set (listing query params, sort and grouping mode)
+// @Tags Saved Filters
+// @Accept json
+// @Produce json
+// @Param filter body CreateSavedFilterRequest true "Saved filter data"
+// @Success 201 {object} utils.Response
+// @Router /saved-filters [post]
+func (h *SavedFilterHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateSavedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	filters, errs := encodeSavedFilters(req.ItemType, req.Filters)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	filter, err := h.queries.CreateSavedFilter(ctx, sqlcdb.CreateSavedFilterParams{
+		UserID:   utils.UserID(c),
+		ItemType: sqlcdb.ItemType(req.ItemType),
+		Name:     req.Name,
+		Filters:  filters,
+		Sort:     utils.OptionalText(req.Sort),
+		GroupBy:  utils.OptionalText(req.GroupBy),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create saved filter", h.logger)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Saved filter created successfully",
+		Data:    toSavedFilterResponse(filter),
+	})
+}
+
+// @Summary Update saved filter
+// @Description Replace the name, filters, sort and grouping mode of a saved filter
+// @Tags Saved Filters
+// @Accept json
+// @Produce json
+// @Param id path int true "Saved Filter ID"
+// @Param filter body UpdateSavedFilterRequest true "Saved filter data"
+// @Success 200 {object} utils.Response
+// @Router /saved-filters/{id} [put]
+func (h *SavedFilterHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := utils.UserID(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid saved filter ID")
+		return
+	}
+
+	// Check if the saved filter exists and belongs to the user
+	current, err := h.queries.GetSavedFilter(ctx, sqlcdb.GetSavedFilterParams{ID: int32(id), UserID: userID})
+	if err != nil {
+		utils.NotFound(c, "Saved filter not found")
+		return
+	}
+
+	var req UpdateSavedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	filters, errs := encodeSavedFilters(models.ItemType(current.ItemType), req.Filters)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	filter, err := h.queries.UpdateSavedFilter(ctx, sqlcdb.UpdateSavedFilterParams{
+		ID:      int32(id),
+		UserID:  userID,
+		Name:    req.Name,
+		Filters: filters,
+		Sort:    utils.OptionalText(req.Sort),
+		GroupBy: utils.OptionalText(req.GroupBy),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update saved filter", h.logger)
+		return
+	}
+
+	utils.Success(c, "Saved filter updated successfully", toSavedFilterResponse(filter))
+}
+
+// @Summary Delete saved filter
+// @Description Delete a saved filter
+// @Tags Saved Filters
+// @Accept json
+// @Produce json
+// @Param id path int true "Saved Filter ID"
+// @Success 200 {object} utils.Response
+// @Router /saved-filters/{id} [delete]
+func (h *SavedFilterHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := utils.UserID(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid saved filter ID")
+		return
+	}
+
+	// Check if the saved filter exists and belongs to the user
+	_, err = h.queries.GetSavedFilter(ctx, sqlcdb.GetSavedFilterParams{ID: int32(id), UserID: userID})
+	if err != nil {
+		utils.NotFound(c, "Saved filter not found")
+		return
+	}
+
+	if err := h.queries.DeleteSavedFilter(ctx, sqlcdb.DeleteSavedFilterParams{ID: int32(id), UserID: userID}); err != nil {
+		utils.HandleError(c, err, "Failed to delete saved filter", h.logger)
+		return
+	}
+
+	utils.Success(c, "Saved filter deleted successfully", nil)
+}
+
+// @Summary Set default saved filter
+// @Description Make a saved filter the default of its listing; the previous default is unset
+// @Tags Saved Filters
+// @Accept json
+// @Produce json
+// @Param id path int true "Saved Filter ID"
+// @Success 200 {object} utils.Response
+// @Router /saved-filters/{id}/default [put]
+func (h *SavedFilterHandler) SetDefault(c *gin.Context) {
+	h.setDefault(c, true)
+}
+
+// @Summary Unset default saved filter
+// @Description Stop using a saved filter as the default of its listing
+// @Tags Saved Filters
+// @Accept json
+// @Produce json
+// @Param id path int true "Saved Filter ID"
+// @Success 200 {object} utils.Response
+// @Router /saved-filters/{id}/default [delete]
+func (h *SavedFilterHandler) UnsetDefault(c *gin.Context) {
+	h.setDefault(c, false)
+}
+
+func (h *SavedFilterHandler) setDefault(c *gin.Context, isDefault bool) {
+	ctx := c.Request.Context()
+	userID := utils.UserID(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid saved filter ID")
+		return
+	}
+
+	// Check if the saved filter exists and belongs to the user
+	_, err = h.queries.GetSavedFilter(ctx, sqlcdb.GetSavedFilterParams{ID: int32(id), UserID: userID})
+	if err != nil {
+		utils.NotFound(c, "Saved filter not found")
+		return
+	}
+
+	if isDefault {
+		err = h.queries.SetDefaultSavedFilter(ctx, sqlcdb.SetDefaultSavedFilterParams{ID: int32(id), UserID: userID})
+	} else {
+		err = h.queries.UnsetDefaultSavedFilter(ctx, sqlcdb.UnsetDefaultSavedFilterParams{ID: int32(id), UserID: userID})
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update default saved filter", h.logger)
+		return
+	}
+
+	filter, err := h.queries.GetSavedFilter(ctx, sqlcdb.GetSavedFilterParams{ID: int32(id), UserID: userID})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get saved filter", h.logger)
+		return
+	}
+
+	utils.Success(c, "Default saved filter updated successfully", toSavedFilterResponse(filter))
+}
+
+// encodeSavedFilters checks the filters against the listing's query params and encodes them for storage
+func encodeSavedFilters(itemType models.ItemType, filters map[string]string) ([]byte, []utils.FieldError) {
+	var errs []utils.FieldError
+	for key := range filters {
+		if !savedFilterParams[itemType][key] {
+			errs = append(errs, utils.FieldError{Field: "filters." + key, Message: "is not a filter of this listing"})
+		}
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+		return nil, errs
+	}
+
+	if filters == nil {
+		filters = map[string]string{}
+	}
+	encoded, err := json.Marshal(filters)
+	if err != nil {
+		return nil, []utils.FieldError{{Field: "filters", Message: err.Error()}}
+	}
+	return encoded, nil
+}
+
+func toSavedFilterResponse(filter sqlcdb.SavedFilter) SavedFilterResponse {
+	response := SavedFilterResponse{
+		ID:        filter.ID,
+		ItemType:  string(filter.ItemType),
+		Name:      filter.Name,
+		Filters:   map[string]string{},
+		IsDefault: filter.IsDefault,
+		CreatedAt: utils.FormatTimestamp(filter.CreatedAt),
+		UpdatedAt: utils.FormatTimestamp(filter.UpdatedAt),
+	}
+	if len(filter.Filters) > 0 {
+		_ = json.Unmarshal(filter.Filters, &response.Filters)
+	}
+	if filter.Sort.Valid {
+		response.Sort = &filter.Sort.String
+	}
+	if filter.GroupBy.Valid {
+		response.GroupBy = &filter.GroupBy.String
+	}
+	return response
+}
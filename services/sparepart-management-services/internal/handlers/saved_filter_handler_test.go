@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSavedFilterHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSavedFilterRepository(ctrl)
+	h := NewSavedFilterHandler(repo, testLogger)
+
+	params := sqlcdb.CreateSavedFilterParams{
+		UserID:   "user-7",
+		ItemType: sqlcdb.ItemTypeSPAREPART,
+		Name:     "Used stock Maluku",
+		Filters:  []byte(`{"region":"MALUKU","stock_type":"USED_STOCK"}`),
+		GroupBy:  pgtype.Text{String: "location", Valid: true},
+	}
+	repo.EXPECT().
+		CreateSavedFilter(gomock.Any(), params).
+		Return(sqlcdb.SavedFilter{ID: 1, UserID: "user-7", ItemType: params.ItemType, Name: params.Name, Filters: params.Filters, GroupBy: params.GroupBy}, nil)
+
+	body := `{"item_type": "SPAREPART", "name": "Used stock Maluku", "filters": {"region": "MALUKU", "stock_type": "USED_STOCK"}, "group_by": "location"}`
+	w := performRequestAs("user-7", http.MethodPost, "/saved-filters", h.Create, "/saved-filters", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var filter SavedFilterResponse
+	decodeResponse(t, w, &filter)
+	if filter.Filters["stock_type"] != "USED_STOCK" || filter.GroupBy == nil || *filter.GroupBy != "location" || filter.Sort != nil {
+		t.Fatalf("unexpected saved filter: %+v", filter)
+	}
+}
+
+func TestSavedFilterHandlerCreateRejectsUnknownParam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSavedFilterRepository(ctrl)
+	h := NewSavedFilterHandler(repo, testLogger)
+
+	// Tools alker listing has no stock_type filter
+	body := `{"item_type": "TOOLS_ALKER", "name": "Tools", "filters": {"stock_type": "NEW_STOCK"}}`
+	w := performRequestAs("user-7", http.MethodPost, "/saved-filters", h.Create, "/saved-filters", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "filters.stock_type" {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+}
+
+func TestSavedFilterHandlerSetDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSavedFilterRepository(ctrl)
+	h := NewSavedFilterHandler(repo, testLogger)
+
+	key := sqlcdb.GetSavedFilterParams{ID: 4, UserID: "user-7"}
+	gomock.InOrder(
+		repo.EXPECT().GetSavedFilter(gomock.Any(), key).Return(sqlcdb.SavedFilter{ID: 4}, nil),
+		repo.EXPECT().SetDefaultSavedFilter(gomock.Any(), sqlcdb.SetDefaultSavedFilterParams{ID: 4, UserID: "user-7"}).Return(nil),
+		repo.EXPECT().GetSavedFilter(gomock.Any(), key).Return(sqlcdb.SavedFilter{ID: 4, IsDefault: true}, nil),
+	)
+
+	w := performRequestAs("user-7", http.MethodPut, "/saved-filters/:id/default", h.SetDefault, "/saved-filters/4/default", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var filter SavedFilterResponse
+	decodeResponse(t, w, &filter)
+	if !filter.IsDefault {
+		t.Fatalf("expected default filter, got %+v", filter)
+	}
+}
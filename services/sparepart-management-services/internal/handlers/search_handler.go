@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultSearchLimit and maxSearchLimit bound the number of search results
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// minSearchLength is the shortest query searched; shorter ones match nearly everything
+	minSearchLength = 2
+)
+
+// SearchResult is a stock item (STOCK), tools alker item (TOOLS_ALKER) or master entry
+// (MASTER) matching the query; master entries have no stock fields or location
+type SearchResult struct {
+	Type      string                `json:"type"`
+	ID        int32                 `json:"id"`
+	MasterID  int32                 `json:"master_id"`
+	Name      string                `json:"name"`
+	ItemType  string                `json:"item_type"`
+	StockType *string               `json:"stock_type"`
+	Quantity  *int32                `json:"quantity"`
+	Notes     *string               `json:"notes"`
+	Location  *SearchResultLocation `json:"location"`
+	Score     float64               `json:"score"`
+}
+
+type SearchResultLocation struct {
+	ID      int32  `json:"id"`
+	Region  string `json:"region"`
+	Regency string `json:"regency"`
+	Cluster string `json:"cluster"`
+}
+
+type SearchHandler struct {
+	logger  *zap.Logger
+	queries repository.SearchRepository
+}
+
+func NewSearchHandler(queries repository.SearchRepository, logger *zap.Logger) *SearchHandler {
+	return &SearchHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Search inventory
+// @Description Search sparepart and tool names, notes, regency and cluster of stock items, tools alker items and master entries. Matches are by substring or similar words (typos are tolerated), most relevant first.
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text (at least 2 characters)"
+// @Param type query string false "Only results of this type" Enums(STOCK, TOOLS_ALKER, MASTER)
+// @Param limit query int false "Number of results (max 100)" default(20)
+// @Success 200 {object} utils.Response
+// @Router /sparepart/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	q := strings.TrimSpace(c.Query("q"))
+	if utf8.RuneCountInString(q) < minSearchLength {
+		errs = append(errs, utils.FieldError{Field: "q", Message: "must be at least 2 characters"})
+	}
+	resultType := strings.ToUpper(c.Query("type"))
+	switch resultType {
+	case "", "STOCK", "TOOLS_ALKER", "MASTER":
+	default:
+		errs = append(errs, utils.FieldError{Field: "type", Message: "must be one of STOCK, TOOLS_ALKER, MASTER"})
+	}
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			errs = append(errs, utils.FieldError{Field: "limit", Message: "must be a positive integer"})
+		} else {
+			limit = min(parsed, maxSearchLimit)
+		}
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	rows, err := h.queries.SearchInventory(ctx, sqlcdb.SearchInventoryParams{
+		Q:          q,
+		ResultType: utils.TextFilter(resultType),
+		Limit:      int32(limit),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to search inventory", h.logger)
+		return
+	}
+
+	results := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, toSearchResult(row))
+	}
+
+	utils.Success(c, "Search results retrieved successfully", results)
+}
+
+func toSearchResult(row sqlcdb.SearchInventoryRow) SearchResult {
+	result := SearchResult{
+		Type:     row.ResultType,
+		ID:       row.ID,
+		MasterID: row.MasterID,
+		Name:     row.Name,
+		ItemType: row.ItemType,
+		Score:    row.Score,
+	}
+	if row.StockType.Valid {
+		result.StockType = &row.StockType.String
+	}
+	if row.Quantity.Valid {
+		result.Quantity = &row.Quantity.Int32
+	}
+	if row.Notes.Valid {
+		result.Notes = &row.Notes.String
+	}
+	if row.LocationID.Valid {
+		result.Location = &SearchResultLocation{
+			ID:      row.LocationID.Int32,
+			Region:  row.Region.String,
+			Regency: row.Regency.String,
+			Cluster: row.Cluster.String,
+		}
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchHandlerSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSearchRepository(ctrl)
+	h := NewSearchHandler(repo, testLogger)
+
+	repo.EXPECT().
+		SearchInventory(gomock.Any(), sqlcdb.SearchInventoryParams{Q: "baterai", Limit: defaultSearchLimit}).
+		Return([]sqlcdb.SearchInventoryRow{
+			{
+				ResultType: "STOCK", ID: 4, MasterID: 2, Name: "Baterai 12V", ItemType: "SPAREPART",
+				StockType:  pgtype.Text{String: "NEW_STOCK", Valid: true},
+				Quantity:   pgtype.Int4{Int32: 3, Valid: true},
+				LocationID: pgtype.Int4{Int32: 7, Valid: true},
+				Region:     pgtype.Text{String: "MALUKU", Valid: true},
+				Regency:    pgtype.Text{String: "Ambon", Valid: true},
+				Cluster:    pgtype.Text{String: "Nusaniwe", Valid: true},
+				Score:      0.9,
+			},
+			{ResultType: "MASTER", ID: 2, MasterID: 2, Name: "Baterai 12V", ItemType: "SPAREPART", Score: 0.9},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/search", h.Search, "/search?q=%20baterai%20", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var results []SearchResult
+	decodeResponse(t, w, &results)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if stock := results[0]; stock.Type != "STOCK" || stock.Location == nil || stock.Location.Regency != "Ambon" || *stock.Quantity != 3 {
+		t.Fatalf("unexpected stock result: %+v", stock)
+	}
+	if master := results[1]; master.Type != "MASTER" || master.Location != nil || master.Quantity != nil {
+		t.Fatalf("unexpected master result: %+v", master)
+	}
+}
+
+func TestSearchHandlerSearchFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSearchRepository(ctrl)
+	h := NewSearchHandler(repo, testLogger)
+
+	repo.EXPECT().
+		SearchInventory(gomock.Any(), sqlcdb.SearchInventoryParams{
+			Q:          "tang",
+			ResultType: pgtype.Text{String: "TOOLS_ALKER", Valid: true},
+			Limit:      maxSearchLimit,
+		}).
+		Return([]sqlcdb.SearchInventoryRow{}, nil)
+
+	w := performRequest(http.MethodGet, "/search", h.Search, "/search?q=tang&type=tools_alker&limit=500", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSearchHandlerSearchValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{name: "missing query", target: "/search"},
+		{name: "query too short", target: "/search?q=%20a%20"},
+		{name: "unknown type", target: "/search?q=tang&type=LOCATION"},
+		{name: "invalid limit", target: "/search?q=tang&limit=0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockSearchRepository(ctrl)
+			h := NewSearchHandler(repo, testLogger)
+
+			w := performRequest(http.MethodGet, "/search", h.Search, tt.target, "")
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// defaultShareLinkHours is how long a share link stays valid unless stated otherwise
+const defaultShareLinkHours = 72
+
+// CreateShareLinkRequest shares a location's stock for expires_in_hours, at most 30 days
+type CreateShareLinkRequest struct {
+	LocationID     int  `json:"location_id" binding:"required,min=1"`
+	ExpiresInHours *int `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+}
+
+// ShareLinkResponse describes a share link; Token and URL are only returned on creation
+type ShareLinkResponse struct {
+	ID             int32   `json:"id"`
+	LocationID     int32   `json:"location_id"`
+	Token          string  `json:"token,omitempty"`
+	URL            string  `json:"url,omitempty"`
+	CreatedBy      *string `json:"created_by"`
+	ExpiresAt      string  `json:"expires_at"`
+	RevokedAt      string  `json:"revoked_at,omitempty"`
+	Active         bool    `json:"active"`
+	AccessCount    int32   `json:"access_count"`
+	LastAccessedAt string  `json:"last_accessed_at,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// SharedLocationStockResponse is the read-only stock snapshot behind a share link
+type SharedLocationStockResponse struct {
+	SparepartStockGroupedResponse
+	ExpiresAt   string `json:"expires_at"`
+	GeneratedAt string `json:"generated_at"`
+}
+
+// ShareLinkHandler lets admins create expiring share links to one location's stock, and
+// serves that stock without authentication to whoever holds the link
+type ShareLinkHandler struct {
+	logger    *zap.Logger
+	queries   repository.ShareLinkRepository
+	apiPrefix string // prefix of the returned share URLs, as the routes are mounted under it
+}
+
+func NewShareLinkHandler(queries repository.ShareLinkRepository, apiPrefix string, logger *zap.Logger) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		logger:    logger,
+		queries:   queries,
+		apiPrefix: apiPrefix,
+	}
+}
+
+// @Summary Create share link
+// @Description Create an expiring read-only link to a location's stock; the token is only shown in this response
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param link body CreateShareLinkRequest true "Share link data"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/admin/share-links [post]
+func (h *ShareLinkHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	// Check if location exists
+	if _, err := h.queries.GetLocation(ctx, int32(req.LocationID)); err != nil {
+		utils.NotFound(c, "Location not found")
+		return
+	}
+
+	hours := defaultShareLinkHours
+	if req.ExpiresInHours != nil {
+		hours = *req.ExpiresInHours
+	}
+
+	token, hash, err := utils.NewShareToken()
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create share link", h.logger)
+		return
+	}
+
+	link, err := h.queries.CreateShareLink(ctx, sqlcdb.CreateShareLinkParams{
+		TokenHash:  hash,
+		LocationID: int32(req.LocationID),
+		CreatedBy:  utils.TextFilter(utils.UserID(c)),
+		ExpiresAt:  pgtype.Timestamptz{Time: time.Now().UTC().Add(time.Duration(hours) * time.Hour), Valid: true},
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create share link", h.logger)
+		return
+	}
+
+	response := toShareLinkResponse(link)
+	response.Token = token
+	response.URL = h.apiPrefix + "/sparepart/share/" + token
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Share link created successfully",
+		Data:    response,
+	})
+}
+
+// @Summary Get share links
+// @Description Get the share links, newest first
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param location_id query int false "Filter by location"
+// @Param active query bool false "Only links that are usable (true) or expired/revoked (false)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/admin/share-links [get]
+func (h *ShareLinkHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	var filters sqlcdb.CountShareLinksParams
+	if value := c.Query("location_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "location_id", Message: "must be a positive integer"})
+		} else {
+			filters.LocationID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	if value := c.Query("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "active", Message: "must be true or false"})
+		} else {
+			filters.Active = pgtype.Bool{Bool: active, Valid: true}
+		}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountShareLinks(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count share links", h.logger)
+		return
+	}
+
+	links, err := h.queries.ListShareLinks(ctx, sqlcdb.ListShareLinksParams{
+		LocationID: filters.LocationID,
+		Active:     filters.Active,
+		Limit:      int32(pagination.Limit),
+		Offset:     int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get share links", h.logger)
+		return
+	}
+
+	response := make([]ShareLinkResponse, 0, len(links))
+	for _, link := range links {
+		response = append(response, toShareLinkResponse(link))
+	}
+
+	utils.SuccessWithPagination(c, "Share links retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Revoke share link
+// @Description Revoke a share link; it stops working immediately
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Share Link ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/admin/share-links/{id} [delete]
+func (h *ShareLinkHandler) Revoke(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid share link ID")
+		return
+	}
+
+	// Check if share link exists
+	_, err = h.queries.GetShareLink(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Share link not found")
+		return
+	}
+
+	link, err := h.queries.RevokeShareLink(ctx, int32(id))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to revoke share link", h.logger)
+		return
+	}
+
+	utils.Success(c, "Share link revoked successfully", toShareLinkResponse(link))
+}
+
+// @Summary Get shared location stock
+// @Description Read-only stock of the location behind a share link; no authentication, rate limited
+// @Tags Share
+// @Produce json
+// @Param token path string true "Share link token"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/share/{token} [get]
+func (h *ShareLinkHandler) GetShared(c *gin.Context) {
+	link, location, items, ok := h.loadShared(c)
+	if !ok {
+		return
+	}
+
+	response := SharedLocationStockResponse{
+		ExpiresAt:   utils.FormatTimestamp(link.ExpiresAt),
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if groups := groupSparepartStocksByLocation(sparepartStockByLocationRows(items)); len(groups) > 0 {
+		response.SparepartStockGroupedResponse = groups[0]
+	} else {
+		response.SparepartStockGroupedResponse = SparepartStockGroupedResponse{
+			ID:         location.ID,
+			LocationID: location.ID,
+			Location: SparepartStockLocation{
+				ID:        location.ID,
+				Region:    string(location.Region),
+				Regency:   location.Regency,
+				Cluster:   location.Cluster,
+				CreatedAt: utils.FormatTimestamp(location.CreatedAt),
+				UpdatedAt: utils.FormatTimestamp(location.UpdatedAt),
+			},
+			Sparepart: []SparepartStockGroupedItem{},
+		}
+	}
+
+	utils.Success(c, "Shared location stock retrieved successfully", response)
+}
+
+// @Summary Get shared location stock as PDF
+// @Description Read-only stock snapshot of the location behind a share link as PDF; no authentication, rate limited
+// @Tags Share
+// @Produce application/pdf
+// @Param token path string true "Share link token"
+// @Success 200 {file} application/pdf
+// @Router /sparepart/share/{token}/pdf [get]
+func (h *ShareLinkHandler) GetSharedPDF(c *gin.Context) {
+	_, location, items, ok := h.loadShared(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	buf, err := utils.ExportLocationStockToPDF(location, items, now, h.logger)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to generate PDF", h.logger)
+		return
+	}
+
+	filename := fmt.Sprintf("location_stock_%d_%s.pdf", location.ID, now.Format("20060102_150405"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// loadShared resolves the share link token and reads the location's stock, writing the
+// error response itself when the link is unknown, revoked or expired
+func (h *ShareLinkHandler) loadShared(c *gin.Context) (sqlcdb.ShareLink, sqlcdb.Location, []sqlcdb.ListSparepartStocksByLocationRow, bool) {
+	ctx := c.Request.Context()
+
+	link, err := h.queries.GetShareLinkByTokenHash(ctx, utils.HashShareToken(c.Param("token")))
+	if err != nil {
+		utils.NotFound(c, "Share link not found")
+		return link, sqlcdb.Location{}, nil, false
+	}
+	if link.RevokedAt.Valid || !link.ExpiresAt.Time.After(time.Now()) {
+		utils.Error(c, "Share link has expired", http.StatusGone)
+		return link, sqlcdb.Location{}, nil, false
+	}
+
+	location, err := h.queries.GetLocation(ctx, link.LocationID)
+	if err != nil {
+		utils.NotFound(c, "Location not found")
+		return link, location, nil, false
+	}
+
+	items, err := h.queries.ListSparepartStocksByLocation(ctx, link.LocationID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get shared location stock", h.logger)
+		return link, location, nil, false
+	}
+
+	if err := h.queries.RecordShareLinkAccess(ctx, link.ID); err != nil {
+		utils.RequestLogger(ctx, h.logger).Warn("Failed to record share link access", zap.Int32("id", link.ID), zap.Error(err))
+	}
+
+	return link, location, items, true
+}
+
+func toShareLinkResponse(link sqlcdb.ShareLink) ShareLinkResponse {
+	response := ShareLinkResponse{
+		ID:             link.ID,
+		LocationID:     link.LocationID,
+		ExpiresAt:      utils.FormatTimestamp(link.ExpiresAt),
+		RevokedAt:      utils.FormatTimestamp(link.RevokedAt),
+		Active:         !link.RevokedAt.Valid && link.ExpiresAt.Time.After(time.Now()),
+		AccessCount:    link.AccessCount,
+		LastAccessedAt: utils.FormatTimestamp(link.LastAccessedAt),
+		CreatedAt:      utils.FormatTimestamp(link.CreatedAt),
+	}
+	if link.CreatedBy.Valid {
+		response.CreatedBy = &link.CreatedBy.String
+	}
+	return response
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestShareLinkHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShareLinkRepository(ctrl)
+	h := NewShareLinkHandler(repo, "/api/v1", testLogger)
+
+	var storedHash string
+	repo.EXPECT().GetLocation(gomock.Any(), int32(4)).Return(sqlcdb.Location{ID: 4}, nil)
+	repo.EXPECT().
+		CreateShareLink(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.CreateShareLinkParams) (sqlcdb.ShareLink, error) {
+			if hours := time.Until(arg.ExpiresAt.Time).Hours(); hours < 23.9 || hours > 24 {
+				t.Errorf("unexpected expiry: %v", arg.ExpiresAt.Time)
+			}
+			if arg.CreatedBy.String != "admin-1" {
+				t.Errorf("unexpected creator: %+v", arg.CreatedBy)
+			}
+			storedHash = arg.TokenHash
+			return sqlcdb.ShareLink{ID: 1, TokenHash: arg.TokenHash, LocationID: arg.LocationID, CreatedBy: arg.CreatedBy, ExpiresAt: arg.ExpiresAt}, nil
+		})
+
+	w := performRequestAs("admin-1", http.MethodPost, "/admin/share-links", h.Create, "/admin/share-links", `{"location_id": 4, "expires_in_hours": 24}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var link ShareLinkResponse
+	decodeResponse(t, w, &link)
+	// Only the hash of the returned token is stored
+	if link.Token == "" || utils.HashShareToken(link.Token) != storedHash || link.Token == storedHash {
+		t.Fatalf("token does not match the stored hash: %+v", link)
+	}
+	if link.URL != "/api/v1/sparepart/share/"+link.Token || !link.Active {
+		t.Fatalf("unexpected share link: %+v", link)
+	}
+}
+
+func TestShareLinkHandlerGetShared(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShareLinkRepository(ctrl)
+	h := NewShareLinkHandler(repo, "/api/v1", testLogger)
+
+	expiresAt := pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+	repo.EXPECT().GetShareLinkByTokenHash(gomock.Any(), utils.HashShareToken("abc")).
+		Return(sqlcdb.ShareLink{ID: 2, LocationID: 4, ExpiresAt: expiresAt}, nil)
+	repo.EXPECT().GetLocation(gomock.Any(), int32(4)).Return(sqlcdb.Location{ID: 4, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo"}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(4)).Return([]sqlcdb.ListSparepartStocksByLocationRow{
+		{ID: 10, LocationID: 4, LocationID2: 4, Region: sqlcdb.RegionTypeMALUKU, SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2},
+	}, nil)
+	repo.EXPECT().RecordShareLinkAccess(gomock.Any(), int32(2)).Return(errors.New("connection reset"))
+
+	// A failed access count does not fail the view
+	w := performRequest(http.MethodGet, "/share/:token", h.GetShared, "/share/abc", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var shared SharedLocationStockResponse
+	decodeResponse(t, w, &shared)
+	if shared.LocationID != 4 || len(shared.Sparepart) != 1 || shared.Sparepart[0].Quantity != 2 || shared.ExpiresAt == "" {
+		t.Fatalf("unexpected shared stock: %+v", shared)
+	}
+}
+
+func TestShareLinkHandlerGetSharedPDFOfEmptyLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShareLinkRepository(ctrl)
+	h := NewShareLinkHandler(repo, "/api/v1", testLogger)
+
+	repo.EXPECT().GetShareLinkByTokenHash(gomock.Any(), gomock.Any()).
+		Return(sqlcdb.ShareLink{ID: 2, LocationID: 4, ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}}, nil)
+	repo.EXPECT().GetLocation(gomock.Any(), int32(4)).Return(sqlcdb.Location{ID: 4, Regency: "Jayapura", Cluster: "Sentani"}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(4)).Return([]sqlcdb.ListSparepartStocksByLocationRow{}, nil)
+	repo.EXPECT().RecordShareLinkAccess(gomock.Any(), int32(2)).Return(nil)
+
+	w := performRequest(http.MethodGet, "/share/:token/pdf", h.GetSharedPDF, "/share/abc/pdf", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF")) || !strings.Contains(w.Header().Get("Content-Disposition"), "location_stock_4_") {
+		t.Fatalf("expected a PDF download, got %q", w.Header().Get("Content-Disposition"))
+	}
+}
+
+func TestShareLinkHandlerGetSharedRejectsUnusableLinks(t *testing.T) {
+	past := pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+	future := pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+
+	tests := []struct {
+		name       string
+		link       sqlcdb.ShareLink
+		err        error
+		wantStatus int
+	}{
+		{"unknown", sqlcdb.ShareLink{}, errors.New("no rows in result set"), http.StatusNotFound},
+		{"expired", sqlcdb.ShareLink{ID: 1, ExpiresAt: past}, nil, http.StatusGone},
+		{"revoked", sqlcdb.ShareLink{ID: 1, ExpiresAt: future, RevokedAt: past}, nil, http.StatusGone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockShareLinkRepository(ctrl)
+			h := NewShareLinkHandler(repo, "/api/v1", testLogger)
+
+			repo.EXPECT().GetShareLinkByTokenHash(gomock.Any(), gomock.Any()).Return(tt.link, tt.err)
+
+			w := performRequest(http.MethodGet, "/share/:token", h.GetShared, "/share/abc", "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
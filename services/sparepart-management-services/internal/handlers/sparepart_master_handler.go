@@ -1,25 +1,55 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
-	"sparepart-management-services/internal/database"
 	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
 	"sparepart-management-services/internal/utils"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
+// SparepartAvailabilityResponse lists every location holding a sparepart or tool
+type SparepartAvailabilityResponse struct {
+	Sparepart     sqlcdb.ListSparepart   `json:"sparepart"`
+	TotalQuantity int64                  `json:"total_quantity"`
+	Locations     []AvailabilityLocation `json:"locations"`
+}
+
+// AvailabilityLocation is one location holding the item, with who to contact there
+type AvailabilityLocation struct {
+	LocationID     int32                   `json:"location_id"`
+	Region         string                  `json:"region"`
+	Regency        string                  `json:"regency"`
+	Cluster        string                  `json:"cluster"`
+	TotalQuantity  int64                   `json:"total_quantity"`
+	Stocks         []AvailabilityStock     `json:"stocks"`
+	ContactPersons []LocationContactPerson `json:"contact_persons"`
+}
+
+// AvailabilityStock is the quantity held per stock type. Tools alker have no stock type and
+// report how many are checked out instead.
+type AvailabilityStock struct {
+	StockType  string `json:"stock_type,omitempty"`
+	Quantity   int32  `json:"quantity"`
+	CheckedOut int32  `json:"checked_out,omitempty"`
+}
+
 type SparepartMasterHandler struct {
 	logger  *zap.Logger
-	queries *sqlcdb.Queries
+	queries repository.SparepartMasterRepository
 }
 
-func NewSparepartMasterHandler() *SparepartMasterHandler {
+func NewSparepartMasterHandler(queries repository.SparepartMasterRepository, logger *zap.Logger) *SparepartMasterHandler {
 	return &SparepartMasterHandler{
-		logger:  utils.GetLogger(),
-		queries: sqlcdb.New(database.GetDB()),
+		logger:  logger,
+		queries: queries,
 	}
 }
 
@@ -31,30 +61,27 @@ func NewSparepartMasterHandler() *SparepartMasterHandler {
 // @Param name query string false "Filter by name (partial match, case-insensitive)"
 // @Param item_type query string false "Filter by item type (SPAREPART, TOOLS_ALKER)"
 // @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
+// @Param limit query int false "Items per page (max 100)" default(10)
 // @Success 200 {object} utils.PaginatedResponse
 // @Router /sparepart/master [get]
 func (h *SparepartMasterHandler) GetAll(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	// Get filter parameters
-	var name, itemType string
-	if n := c.Query("name"); n != "" {
-		name = n
-	}
-	if it := c.Query("item_type"); it != "" {
-		itemType = it
-	}
+	name := utils.TextFilter(c.Query("name"))
+	itemType := utils.TextFilter(c.Query("item_type"))
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset := (page - 1) * limit
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
 
 	// Count total
 	countParams := sqlcdb.CountSparepartMastersParams{
-		Column1: name,
-		Column2: itemType,
+		Name:     name,
+		ItemType: itemType,
 	}
 	total, err := h.queries.CountSparepartMasters(ctx, countParams)
 	if err != nil {
@@ -64,10 +91,10 @@ func (h *SparepartMasterHandler) GetAll(c *gin.Context) {
 
 	// List spareparts
 	listParams := sqlcdb.ListSparepartMastersParams{
-		Column1: name,
-		Column2: itemType,
-		Limit:   int32(limit),
-		Offset:  int32(offset),
+		Name:     name,
+		ItemType: itemType,
+		Limit:    int32(pagination.Limit),
+		Offset:   int32(pagination.Offset()),
 	}
 	items, err := h.queries.ListSparepartMasters(ctx, listParams)
 	if err != nil {
@@ -75,7 +102,7 @@ func (h *SparepartMasterHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessWithPagination(c, "Spareparts retrieved successfully", items, page, limit, total)
+	utils.SuccessWithPagination(c, "Spareparts retrieved successfully", items, pagination.Page, pagination.Limit, total)
 }
 
 // @Summary Get sparepart by ID
@@ -104,8 +131,85 @@ func (h *SparepartMasterHandler) GetByID(c *gin.Context) {
 	utils.Success(c, "Sparepart retrieved successfully", item)
 }
 
+// @Summary Get sparepart availability
+// @Description Get every location holding a sparepart (stock items per stock type) or tool (tools alker items, with the quantity checked out), with quantities and the location's contact persons, ordered by region, regency and cluster. Deleted locations and stock items and empty items are left out.
+// @Tags Sparepart Master
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart ID"
+// @Success 200 {object} utils.Response{data=SparepartAvailabilityResponse}
+// @Failure 404 {object} utils.Response
+// @Router /sparepart/master/{id}/availability [get]
+func (h *SparepartMasterHandler) GetAvailability(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart ID")
+		return
+	}
+
+	sparepart, err := h.queries.GetSparepartMaster(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Sparepart not found")
+		return
+	}
+
+	rows, err := h.queries.ListSparepartAvailability(ctx, sparepart.ID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get sparepart availability", h.logger)
+		return
+	}
+
+	response := SparepartAvailabilityResponse{
+		Sparepart: sparepart,
+		Locations: []AvailabilityLocation{},
+	}
+	// Rows come ordered by location, so each location's stocks are adjacent
+	locationIDs := []int32{}
+	for _, row := range rows {
+		last := len(response.Locations) - 1
+		if last < 0 || response.Locations[last].LocationID != row.LocationID {
+			response.Locations = append(response.Locations, AvailabilityLocation{
+				LocationID:     row.LocationID,
+				Region:         string(row.Region),
+				Regency:        row.Regency,
+				Cluster:        row.Cluster,
+				Stocks:         []AvailabilityStock{},
+				ContactPersons: []LocationContactPerson{},
+			})
+			locationIDs = append(locationIDs, row.LocationID)
+			last++
+		}
+		location := &response.Locations[last]
+		location.Stocks = append(location.Stocks, AvailabilityStock{
+			StockType:  row.StockType.String,
+			Quantity:   row.Quantity,
+			CheckedOut: row.CheckedOut,
+		})
+		location.TotalQuantity += int64(row.Quantity)
+		response.TotalQuantity += int64(row.Quantity)
+	}
+
+	if len(locationIDs) > 0 {
+		contacts, err := h.queries.ListContactPersonsByLocations(ctx, locationIDs)
+		if err != nil {
+			utils.HandleError(c, err, "Failed to get contact persons", h.logger)
+			return
+		}
+		byLocation := groupContactPersonsByLocation(contacts)
+		for i := range response.Locations {
+			if persons, ok := byLocation[response.Locations[i].LocationID]; ok {
+				response.Locations[i].ContactPersons = persons
+			}
+		}
+	}
+
+	utils.Success(c, "Sparepart availability retrieved successfully", response)
+}
+
 // @Summary Create sparepart in master list
-// @Description Create a new sparepart in master list
+// @Description Create a new sparepart in master list; unit (PCS, METER or SET) is the unit its quantities are counted in, PCS when omitted. unit_cost is the default cost price of one unit, used to value stock items without a cost of their own
 // @Tags Sparepart Master
 // @Accept json
 // @Produce json
@@ -117,7 +221,15 @@ func (h *SparepartMasterHandler) Create(c *gin.Context) {
 
 	var req sqlcdb.CreateSparepartMasterParams
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+		utils.BindingError(c, err)
+		return
+	}
+	if req.Unit.Valid && !models.Unit(req.Unit.String).Valid() {
+		utils.ValidationError(c, utils.UnitError)
+		return
+	}
+	if !utils.ValidUnitCost(req.UnitCost) {
+		utils.ValidationError(c, utils.UnitCostError("unit_cost"))
 		return
 	}
 
@@ -135,7 +247,7 @@ func (h *SparepartMasterHandler) Create(c *gin.Context) {
 }
 
 // @Summary Update sparepart in master list
-// @Description Update an existing sparepart in master list
+// @Description Update an existing sparepart in master list; without unit or unit_cost the sparepart keeps its current one
 // @Tags Sparepart Master
 // @Accept json
 // @Produce json
@@ -161,7 +273,15 @@ func (h *SparepartMasterHandler) Update(c *gin.Context) {
 
 	var req sqlcdb.UpdateSparepartMasterParams
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+		utils.BindingError(c, err)
+		return
+	}
+	if req.Unit.Valid && !models.Unit(req.Unit.String).Valid() {
+		utils.ValidationError(c, utils.UnitError)
+		return
+	}
+	if !utils.ValidUnitCost(req.UnitCost) {
+		utils.ValidationError(c, utils.UnitCostError("unit_cost"))
 		return
 	}
 
@@ -175,13 +295,77 @@ func (h *SparepartMasterHandler) Update(c *gin.Context) {
 	utils.Success(c, "Sparepart updated successfully", item)
 }
 
+// PatchSparepartMasterRequest holds the master list fields to change; omitted fields are unchanged
+type PatchSparepartMasterRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1"`
+	ItemType *string `json:"item_type,omitempty" binding:"omitempty,oneof=SPAREPART TOOLS_ALKER"`
+	Unit     *string `json:"unit,omitempty" binding:"omitempty,oneof=PCS METER SET"`
+	// Default cost price of one unit
+	UnitCost *float64 `json:"unit_cost,omitempty" binding:"omitempty,min=0,max=999999999999.99"`
+}
+
+// @Summary Partially update sparepart in master list
+// @Description Update only the master list fields present in the request body
+// @Tags Sparepart Master
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart ID"
+// @Param sparepart body PatchSparepartMasterRequest true "Fields to update (omitted fields are unchanged)"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/master/{id} [patch]
+func (h *SparepartMasterHandler) Patch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart ID")
+		return
+	}
+
+	// Check if sparepart exists
+	_, err = h.queries.GetSparepartMaster(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Sparepart not found")
+		return
+	}
+
+	var req PatchSparepartMasterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	if req.Name == nil && req.ItemType == nil && req.Unit == nil && req.UnitCost == nil {
+		utils.BadRequest(c, "No fields to update")
+		return
+	}
+
+	params := sqlcdb.PatchSparepartMasterParams{
+		ID:       int32(id),
+		Name:     utils.OptionalText(req.Name),
+		Unit:     utils.OptionalText(req.Unit),
+		UnitCost: utils.OptionalNumeric(req.UnitCost),
+	}
+	if req.ItemType != nil {
+		params.ItemType = sqlcdb.NullItemType{ItemType: sqlcdb.ItemType(*req.ItemType), Valid: true}
+	}
+
+	item, err := h.queries.PatchSparepartMaster(ctx, params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update sparepart", h.logger)
+		return
+	}
+
+	utils.Success(c, "Sparepart updated successfully", item)
+}
+
 // @Summary Delete sparepart from master list
-// @Description Delete a sparepart from master list
+// @Description Soft delete a sparepart from the master list; it is kept until restored or purged. A sparepart still used by stock or tools alker items can't be deleted.
 // @Tags Sparepart Master
 // @Accept json
 // @Produce json
 // @Param id path int true "Sparepart ID"
 // @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /sparepart/master/{id} [delete]
 func (h *SparepartMasterHandler) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -192,6 +376,26 @@ func (h *SparepartMasterHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	// Check if sparepart exists
+	_, err = h.queries.GetSparepartMaster(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Sparepart not found")
+		return
+	}
+
+	references, err := h.queries.CountSparepartMasterReferences(ctx, int32(id))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to check sparepart usage", h.logger)
+		return
+	}
+	if references > 0 {
+		c.JSON(http.StatusConflict, utils.Response{
+			Error: fmt.Sprintf("Sparepart is still used by %d stock and tools alker items", references),
+			Code:  utils.ErrCodeInUse,
+		})
+		return
+	}
+
 	err = h.queries.DeleteSparepartMaster(ctx, int32(id))
 	if err != nil {
 		utils.HandleError(c, err, "Failed to delete sparepart", h.logger)
@@ -201,3 +405,34 @@ func (h *SparepartMasterHandler) Delete(c *gin.Context) {
 	utils.Success(c, "Sparepart deleted successfully", nil)
 }
 
+// @Summary Restore sparepart to master list
+// @Description Restore a soft deleted sparepart to the master list
+// @Tags Sparepart Master
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response "A sparepart with the same name was created meanwhile"
+// @Router /sparepart/master/{id}/restore [post]
+func (h *SparepartMasterHandler) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart ID")
+		return
+	}
+
+	item, err := h.queries.RestoreSparepartMaster(ctx, int32(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.NotFound(c, "Deleted sparepart not found")
+			return
+		}
+		utils.HandleError(c, err, "Failed to restore sparepart", h.logger)
+		return
+	}
+
+	utils.Success(c, "Sparepart restored successfully", item)
+}
+
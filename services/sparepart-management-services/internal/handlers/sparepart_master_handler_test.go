@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSparepartMasterHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewSparepartMasterHandler(repo, testLogger)
+
+	name := pgtype.Text{String: "busbar", Valid: true}
+	repo.EXPECT().CountSparepartMasters(gomock.Any(), sqlcdb.CountSparepartMastersParams{Name: name}).Return(int64(2), nil)
+	repo.EXPECT().
+		ListSparepartMasters(gomock.Any(), sqlcdb.ListSparepartMastersParams{Name: name, Limit: 10, Offset: 0}).
+		Return([]sqlcdb.ListSparepart{
+			{ID: 6, Name: "BUSBAR 12", ItemType: sqlcdb.ItemTypeSPAREPART},
+			{ID: 7, Name: "BUSBAR 4", ItemType: sqlcdb.ItemTypeSPAREPART},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/master", h.GetAll, "/master?name=busbar", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var items []sqlcdb.ListSparepart
+	decodeResponse(t, w, &items)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 spareparts, got %d", len(items))
+	}
+}
+
+func TestSparepartMasterHandlerCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(repo *mocks.MockSparepartMasterRepository)
+		wantStatus int
+	}{
+		{
+			name: "created",
+			body: `{"name":"BMS","item_type":"SPAREPART"}`,
+			setup: func(repo *mocks.MockSparepartMasterRepository) {
+				repo.EXPECT().
+					CreateSparepartMaster(gomock.Any(), sqlcdb.CreateSparepartMasterParams{Name: "BMS", ItemType: sqlcdb.ItemTypeSPAREPART}).
+					Return(sqlcdb.ListSparepart{ID: 9, Name: "BMS", ItemType: sqlcdb.ItemTypeSPAREPART}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "created with unit",
+			body: `{"name":"Kabel NYY","item_type":"SPAREPART","unit":"METER"}`,
+			setup: func(repo *mocks.MockSparepartMasterRepository) {
+				repo.EXPECT().
+					CreateSparepartMaster(gomock.Any(), sqlcdb.CreateSparepartMasterParams{
+						Name: "Kabel NYY", ItemType: sqlcdb.ItemTypeSPAREPART, Unit: pgtype.Text{String: "METER", Valid: true},
+					}).
+					Return(sqlcdb.ListSparepart{ID: 10, Name: "Kabel NYY", ItemType: sqlcdb.ItemTypeSPAREPART, Unit: "METER"}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "created with unit cost",
+			body: `{"name":"BMS","item_type":"SPAREPART","unit_cost":12500.5}`,
+			setup: func(repo *mocks.MockSparepartMasterRepository) {
+				repo.EXPECT().
+					CreateSparepartMaster(gomock.Any(), sqlcdb.CreateSparepartMasterParams{
+						Name: "BMS", ItemType: sqlcdb.ItemTypeSPAREPART, UnitCost: pgtype.Numeric{Int: big.NewInt(125005), Exp: -1, Valid: true},
+					}).
+					Return(sqlcdb.ListSparepart{ID: 9, Name: "BMS", ItemType: sqlcdb.ItemTypeSPAREPART}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "invalid unit",
+			body:       `{"name":"Kabel NYY","item_type":"SPAREPART","unit":"ROLL"}`,
+			setup:      func(repo *mocks.MockSparepartMasterRepository) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "negative unit cost",
+			body:       `{"name":"BMS","item_type":"SPAREPART","unit_cost":-1}`,
+			setup:      func(repo *mocks.MockSparepartMasterRepository) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid body",
+			body:       `{"name":`,
+			setup:      func(repo *mocks.MockSparepartMasterRepository) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			body: `{"name":"BMS","item_type":"SPAREPART"}`,
+			setup: func(repo *mocks.MockSparepartMasterRepository) {
+				repo.EXPECT().
+					CreateSparepartMaster(gomock.Any(), gomock.Any()).
+					Return(sqlcdb.ListSparepart{}, errors.New("duplicate key value"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockSparepartMasterRepository(ctrl)
+			tt.setup(repo)
+			h := NewSparepartMasterHandler(repo, testLogger)
+
+			w := performRequest(http.MethodPost, "/master", h.Create, "/master", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSparepartMasterHandlerPatchItemTypeOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewSparepartMasterHandler(repo, testLogger)
+
+	repo.EXPECT().GetSparepartMaster(gomock.Any(), int32(7)).Return(sqlcdb.ListSparepart{ID: 7, Name: "Tang Crimping"}, nil)
+	repo.EXPECT().
+		PatchSparepartMaster(gomock.Any(), sqlcdb.PatchSparepartMasterParams{
+			ID:       7,
+			ItemType: sqlcdb.NullItemType{ItemType: sqlcdb.ItemTypeTOOLSALKER, Valid: true},
+		}).
+		Return(sqlcdb.ListSparepart{ID: 7, Name: "Tang Crimping", ItemType: sqlcdb.ItemTypeTOOLSALKER}, nil)
+
+	w := performRequest(http.MethodPatch, "/master/:id", h.Patch, "/master/7", `{"item_type":"TOOLS_ALKER"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var item sqlcdb.ListSparepart
+	decodeResponse(t, w, &item)
+	if item.Name != "Tang Crimping" || item.ItemType != sqlcdb.ItemTypeTOOLSALKER {
+		t.Fatalf("unexpected sparepart: %+v", item)
+	}
+}
+
+func TestSparepartMasterHandlerPatchNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewSparepartMasterHandler(repo, testLogger)
+
+	repo.EXPECT().GetSparepartMaster(gomock.Any(), int32(7)).Return(sqlcdb.ListSparepart{}, errors.New("no rows in result set"))
+
+	w := performRequest(http.MethodPatch, "/master/:id", h.Patch, "/master/7", `{"name":"BMS"}`)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartMasterHandlerGetAvailability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewSparepartMasterHandler(repo, testLogger)
+
+	repo.EXPECT().GetSparepartMaster(gomock.Any(), int32(6)).Return(sqlcdb.ListSparepart{ID: 6, Name: "BUSBAR 12", ItemType: sqlcdb.ItemTypeSPAREPART}, nil)
+	repo.EXPECT().
+		ListSparepartAvailability(gomock.Any(), int32(6)).
+		Return([]sqlcdb.ListSparepartAvailabilityRow{
+			{LocationID: 3, Region: sqlcdb.RegionTypeMALUKU, Regency: "Ambon", Cluster: "Nusaniwe", StockType: pgtype.Text{String: "NEW_STOCK", Valid: true}, Quantity: 4},
+			{LocationID: 3, Region: sqlcdb.RegionTypeMALUKU, Regency: "Ambon", Cluster: "Nusaniwe", StockType: pgtype.Text{String: "USED_STOCK", Valid: true}, Quantity: 1},
+			{LocationID: 8, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Abepura", StockType: pgtype.Text{String: "NEW_STOCK", Valid: true}, Quantity: 2},
+		}, nil)
+	repo.EXPECT().
+		ListContactPersonsByLocations(gomock.Any(), []int32{3, 8}).
+		Return([]sqlcdb.ContactPerson{{ID: 11, LocationID: 8, Pic: "Yohanes", Phone: "0812"}}, nil)
+
+	w := performRequest(http.MethodGet, "/master/:id/availability", h.GetAvailability, "/master/6/availability", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var availability SparepartAvailabilityResponse
+	decodeResponse(t, w, &availability)
+	if availability.TotalQuantity != 7 || len(availability.Locations) != 2 {
+		t.Fatalf("unexpected availability: %+v", availability)
+	}
+	if ambon := availability.Locations[0]; ambon.TotalQuantity != 5 || len(ambon.Stocks) != 2 || len(ambon.ContactPersons) != 0 {
+		t.Fatalf("unexpected Ambon availability: %+v", ambon)
+	}
+	if jayapura := availability.Locations[1]; len(jayapura.ContactPersons) != 1 || jayapura.ContactPersons[0].Pic != "Yohanes" {
+		t.Fatalf("unexpected Jayapura contact persons: %+v", jayapura.ContactPersons)
+	}
+}
+
+func TestSparepartMasterHandlerGetAvailabilityNotHeld(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewSparepartMasterHandler(repo, testLogger)
+
+	repo.EXPECT().GetSparepartMaster(gomock.Any(), int32(9)).Return(sqlcdb.ListSparepart{ID: 9, Name: "Tang Ampere", ItemType: sqlcdb.ItemTypeTOOLSALKER}, nil)
+	repo.EXPECT().ListSparepartAvailability(gomock.Any(), int32(9)).Return([]sqlcdb.ListSparepartAvailabilityRow{}, nil)
+
+	w := performRequest(http.MethodGet, "/master/:id/availability", h.GetAvailability, "/master/9/availability", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var availability SparepartAvailabilityResponse
+	decodeResponse(t, w, &availability)
+	if availability.Locations == nil || len(availability.Locations) != 0 || availability.TotalQuantity != 0 {
+		t.Fatalf("expected no locations, got %+v", availability)
+	}
+}
+
+func TestSparepartMasterHandlerDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewSparepartMasterHandler(repo, testLogger)
+
+	repo.EXPECT().GetSparepartMaster(gomock.Any(), int32(9)).Return(sqlcdb.ListSparepart{ID: 9, Name: "Tang Ampere"}, nil)
+	repo.EXPECT().CountSparepartMasterReferences(gomock.Any(), int32(9)).Return(int64(0), nil)
+	repo.EXPECT().DeleteSparepartMaster(gomock.Any(), int32(9)).Return(nil)
+
+	w := performRequest(http.MethodDelete, "/master/:id", h.Delete, "/master/9", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartMasterHandlerDeleteInUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewSparepartMasterHandler(repo, testLogger)
+
+	repo.EXPECT().GetSparepartMaster(gomock.Any(), int32(9)).Return(sqlcdb.ListSparepart{ID: 9, Name: "BMS"}, nil)
+	repo.EXPECT().CountSparepartMasterReferences(gomock.Any(), int32(9)).Return(int64(2), nil)
+
+	w := performRequest(http.MethodDelete, "/master/:id", h.Delete, "/master/9", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.ErrCodeInUse {
+		t.Fatalf("expected %s code, got %+v", utils.ErrCodeInUse, resp)
+	}
+}
+
+func TestSparepartMasterHandlerRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartMasterRepository(ctrl)
+	h := NewSparepartMasterHandler(repo, testLogger)
+
+	repo.EXPECT().RestoreSparepartMaster(gomock.Any(), int32(9)).Return(sqlcdb.ListSparepart{ID: 9, Name: "BMS"}, nil)
+	repo.EXPECT().RestoreSparepartMaster(gomock.Any(), int32(10)).Return(sqlcdb.ListSparepart{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/master/:id/restore", h.Restore, "/master/9/restore", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performRequest(http.MethodPost, "/master/:id/restore", h.Restore, "/master/10/restore", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,602 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Sparepart request statuses; a request moves from PENDING to APPROVED to FULFILLED, or from
+// PENDING to REJECTED
+const (
+	requestStatusPending   = "PENDING"
+	requestStatusApproved  = "APPROVED"
+	requestStatusRejected  = "REJECTED"
+	requestStatusFulfilled = "FULFILLED"
+)
+
+// Errors ending a sparepart request transaction early; the response is written after the rollback
+var (
+	errRequestNotFound = errors.New("sparepart request not found")
+	errRequestStatus   = errors.New("sparepart request has the wrong status")
+	errRequestInvalid  = errors.New("sparepart request is invalid")
+)
+
+// SparepartRequestItemRequest is a quantity of one sparepart and stock type asked for
+type SparepartRequestItemRequest struct {
+	SparepartID int              `json:"sparepart_id" binding:"required,min=1"`
+	StockType   models.StockType `json:"stock_type" binding:"required,oneof=NEW_STOCK USED_STOCK"`
+	Quantity    int              `json:"quantity" binding:"required,min=1"`
+}
+
+// CreateSparepartRequestRequest asks for spareparts to be sent to a location, optionally for the
+// technician who will use them
+type CreateSparepartRequestRequest struct {
+	DestinationLocationID int                           `json:"destination_location_id" binding:"required,min=1"`
+	TechnicianID          *int                          `json:"technician_id" binding:"omitempty,min=1"`
+	Notes                 *string                       `json:"notes"`
+	Items                 []SparepartRequestItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// SparepartRequestActionRequest is the optional body of the approve and reject actions
+type SparepartRequestActionRequest struct {
+	Note *string `json:"note"`
+}
+
+// FulfillSparepartRequestRequest names the location the requested stock is taken from
+type FulfillSparepartRequestRequest struct {
+	SourceLocationID int     `json:"source_location_id" binding:"required,min=1"`
+	Note             *string `json:"note"`
+}
+
+// SparepartRequestItemResponse is a requested item; TransferID is set once it was delivered
+type SparepartRequestItemResponse struct {
+	ID            int32   `json:"id"`
+	SparepartID   int32   `json:"sparepart_id"`
+	SparepartName *string `json:"sparepart_name,omitempty"`
+	StockType     string  `json:"stock_type"`
+	Quantity      int32   `json:"quantity"`
+	TransferID    *int32  `json:"transfer_id,omitempty"`
+}
+
+// SparepartRequestEventResponse is a status a request entered
+type SparepartRequestEventResponse struct {
+	Status    string  `json:"status"`
+	Actor     *string `json:"actor"`
+	Note      *string `json:"note"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// SparepartRequestResponse is a sparepart request
+type SparepartRequestResponse struct {
+	ID                    int32   `json:"id"`
+	DestinationLocationID int32   `json:"destination_location_id"`
+	DestinationCluster    *string `json:"destination_cluster,omitempty"`
+	SourceLocationID      *int32  `json:"source_location_id"`
+	SourceCluster         *string `json:"source_cluster,omitempty"`
+	Status                string  `json:"status"`
+	Notes                 *string `json:"notes"`
+	RequestedBy           string  `json:"requested_by"`
+	TechnicianID          *int32  `json:"technician_id"`
+	Technician            *string `json:"technician,omitempty"`
+	CreatedAt             string  `json:"created_at"`
+	UpdatedAt             string  `json:"updated_at"`
+}
+
+// SparepartRequestDetailResponse is a sparepart request with its items and status history
+type SparepartRequestDetailResponse struct {
+	SparepartRequestResponse
+	Items   []SparepartRequestItemResponse  `json:"items"`
+	History []SparepartRequestEventResponse `json:"history"`
+}
+
+// SparepartRequestHandler takes the sparepart requests of the field teams through approval
+// to fulfillment, which moves the stock from a warehouse location to the requesting site
+type SparepartRequestHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+}
+
+func NewSparepartRequestHandler(queries repository.SparepartStockRepository, logger *zap.Logger) *SparepartRequestHandler {
+	return &SparepartRequestHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Create sparepart request
+// @Description Request spareparts to be sent to a location; the request is PENDING until an admin approves or rejects it
+// @Tags Sparepart Request
+// @Accept json
+// @Produce json
+// @Param request body CreateSparepartRequestRequest true "Request data"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/requests [post]
+func (h *SparepartRequestHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateSparepartRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	var errs []utils.FieldError
+	seen := make(map[string]int, len(req.Items))
+	for i, item := range req.Items {
+		key := fmt.Sprintf("%d/%s", item.SparepartID, item.StockType)
+		if first, ok := seen[key]; ok {
+			errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d]", i), Message: fmt.Sprintf("duplicates items[%d]", first)})
+			continue
+		}
+		seen[key] = i
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	user := utils.UserID(c)
+	var id int32
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		request, err := repo.CreateSparepartRequest(ctx, sqlcdb.CreateSparepartRequestParams{
+			DestinationLocationID: int32(req.DestinationLocationID),
+			Notes:                 utils.OptionalText(req.Notes),
+			RequestedBy:           user,
+			TechnicianID:          utils.OptionalInt(req.TechnicianID),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			errs = append(errs, utils.FieldError{Field: "destination_location_id", Message: "does not exist"})
+			return errRequestInvalid
+		}
+		if err != nil {
+			return err
+		}
+		id = request.ID
+
+		for i, item := range req.Items {
+			_, err := repo.CreateSparepartRequestItem(ctx, sqlcdb.CreateSparepartRequestItemParams{
+				RequestID:   request.ID,
+				SparepartID: int32(item.SparepartID),
+				StockType:   sqlcdb.StockType(item.StockType),
+				Quantity:    int32(item.Quantity),
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d].sparepart_id", i), Message: "does not exist"})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if len(errs) > 0 {
+			return errRequestInvalid
+		}
+
+		return repo.CreateSparepartRequestEvent(ctx, sqlcdb.CreateSparepartRequestEventParams{
+			RequestID: request.ID,
+			Status:    requestStatusPending,
+			Actor:     utils.TextFilter(user),
+		})
+	})
+	if !h.handleTransactionError(c, err, "", errs, "", "Failed to create sparepart request") {
+		return
+	}
+
+	h.respond(c, http.StatusCreated, id, "Sparepart request created successfully")
+}
+
+// @Summary Get sparepart requests
+// @Description Get the sparepart requests, newest first
+// @Tags Sparepart Request
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (PENDING, APPROVED, REJECTED, FULFILLED)"
+// @Param destination_location_id query int false "Filter by destination location"
+// @Param requested_by query string false "Filter by requesting user"
+// @Param technician_id query int false "Filter by technician"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/requests [get]
+func (h *SparepartRequestHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	filters := sqlcdb.CountSparepartRequestsParams{RequestedBy: utils.TextFilter(c.Query("requested_by"))}
+	switch status := c.Query("status"); status {
+	case "":
+	case requestStatusPending, requestStatusApproved, requestStatusRejected, requestStatusFulfilled:
+		filters.Status = utils.TextFilter(status)
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of PENDING, APPROVED, REJECTED, FULFILLED"})
+	}
+	if value := c.Query("destination_location_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "destination_location_id", Message: "must be a positive integer"})
+		} else {
+			filters.DestinationLocationID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	if value := c.Query("technician_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "technician_id", Message: "must be a positive integer"})
+		} else {
+			filters.TechnicianID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountSparepartRequests(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count sparepart requests", h.logger)
+		return
+	}
+
+	requests, err := h.queries.ListSparepartRequests(ctx, sqlcdb.ListSparepartRequestsParams{
+		Status:                filters.Status,
+		DestinationLocationID: filters.DestinationLocationID,
+		RequestedBy:           filters.RequestedBy,
+		TechnicianID:          filters.TechnicianID,
+		Limit:                 int32(pagination.Limit),
+		Offset:                int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get sparepart requests", h.logger)
+		return
+	}
+
+	response := make([]SparepartRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		response = append(response, toSparepartRequestResponse(sqlcdb.GetSparepartRequestRow(request)))
+	}
+
+	utils.SuccessWithPagination(c, "Sparepart requests retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get sparepart request
+// @Description Get a sparepart request with its items and status history
+// @Tags Sparepart Request
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart request ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/requests/{id} [get]
+func (h *SparepartRequestHandler) GetByID(c *gin.Context) {
+	id, ok := parseRequestID(c)
+	if !ok {
+		return
+	}
+	h.respond(c, http.StatusOK, id, "Sparepart request retrieved successfully")
+}
+
+// @Summary Approve sparepart request
+// @Description Approve a PENDING sparepart request
+// @Tags Sparepart Request
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart request ID"
+// @Param action body SparepartRequestActionRequest false "Approval note"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/requests/{id}/approve [post]
+func (h *SparepartRequestHandler) Approve(c *gin.Context) {
+	h.decide(c, requestStatusApproved, "Only PENDING sparepart requests can be approved", "Sparepart request approved successfully")
+}
+
+// @Summary Reject sparepart request
+// @Description Reject a PENDING sparepart request
+// @Tags Sparepart Request
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart request ID"
+// @Param action body SparepartRequestActionRequest false "Rejection note"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/requests/{id}/reject [post]
+func (h *SparepartRequestHandler) Reject(c *gin.Context) {
+	h.decide(c, requestStatusRejected, "Only PENDING sparepart requests can be rejected", "Sparepart request rejected successfully")
+}
+
+// decide moves a PENDING request to status
+func (h *SparepartRequestHandler) decide(c *gin.Context, status, statusMessage, message string) {
+	ctx := c.Request.Context()
+
+	id, ok := parseRequestID(c)
+	if !ok {
+		return
+	}
+	var req SparepartRequestActionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BindingError(c, err)
+			return
+		}
+	}
+
+	var current string
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		request, err := lockRequest(ctx, repo, id, requestStatusPending)
+		current = request.Status
+		if err != nil {
+			return err
+		}
+
+		if _, err := repo.SetSparepartRequestStatus(ctx, sqlcdb.SetSparepartRequestStatusParams{ID: id, Status: status}); err != nil {
+			return err
+		}
+		return repo.CreateSparepartRequestEvent(ctx, sqlcdb.CreateSparepartRequestEventParams{
+			RequestID: id,
+			Status:    status,
+			Actor:     utils.TextFilter(utils.UserID(c)),
+			Note:      utils.OptionalText(req.Note),
+		})
+	})
+	if !h.handleTransactionError(c, err, current, nil, statusMessage, "Failed to update sparepart request") {
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, message)
+}
+
+// @Summary Fulfill sparepart request
+// @Description Fulfill an APPROVED sparepart request: every item is moved from the source location's stock to the destination as a stock transfer, within one transaction. Fails, moving nothing, when the source lacks any item.
+// @Tags Sparepart Request
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart request ID"
+// @Param fulfillment body FulfillSparepartRequestRequest true "Source location"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/requests/{id}/fulfill [post]
+func (h *SparepartRequestHandler) Fulfill(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseRequestID(c)
+	if !ok {
+		return
+	}
+	var req FulfillSparepartRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	user := utils.UserID(c)
+	var current string
+	var errs []utils.FieldError
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		request, err := lockRequest(ctx, repo, id, requestStatusApproved)
+		current = request.Status
+		if err != nil {
+			return err
+		}
+		if request.DestinationLocationID == int32(req.SourceLocationID) {
+			errs = append(errs, utils.FieldError{Field: "source_location_id", Message: "must differ from the destination location"})
+			return errRequestInvalid
+		}
+
+		items, err := repo.ListSparepartRequestItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		// A short item does not stop the others, so every shortage is reported; the
+		// transaction is rolled back anyway
+		for i, item := range items {
+			source, _, transfer, err := moveStock(ctx, repo, sqlcdb.CreateStockTransferParams{
+				SparepartID:           item.SparepartID,
+				StockType:             item.StockType,
+				Quantity:              item.Quantity,
+				SourceLocationID:      int32(req.SourceLocationID),
+				DestinationLocationID: request.DestinationLocationID,
+				Notes:                 utils.TextFilter(fmt.Sprintf("Sparepart request #%d", id)),
+				TransferredBy:         utils.TextFilter(user),
+			})
+			switch {
+			case errors.Is(err, errTransferDestinationInactive):
+				errs = append(errs, utils.FieldError{Field: "destination_location_id", Message: "is deactivated or deleted"})
+				return errRequestInvalid
+			case errors.Is(err, errTransferSourceNotFound):
+				errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d]", i), Message: "not stocked at the source location"})
+				continue
+			case errors.Is(err, errInsufficientStock):
+				errs = append(errs, utils.FieldError{
+					Field:   fmt.Sprintf("items[%d].quantity", i),
+					Message: fmt.Sprintf("exceeds the %d available at the source location", source.Quantity),
+				})
+				continue
+			case err != nil:
+				return err
+			}
+
+			err = repo.SetSparepartRequestItemTransfer(ctx, sqlcdb.SetSparepartRequestItemTransferParams{
+				ID:         item.ID,
+				TransferID: pgtype.Int4{Int32: transfer.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(errs) > 0 {
+			return errRequestInvalid
+		}
+
+		_, err = repo.FulfillSparepartRequest(ctx, sqlcdb.FulfillSparepartRequestParams{
+			ID:               id,
+			SourceLocationID: pgtype.Int4{Int32: int32(req.SourceLocationID), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		return repo.CreateSparepartRequestEvent(ctx, sqlcdb.CreateSparepartRequestEventParams{
+			RequestID: id,
+			Status:    requestStatusFulfilled,
+			Actor:     utils.TextFilter(user),
+			Note:      utils.OptionalText(req.Note),
+		})
+	})
+	if !h.handleTransactionError(c, err, current, errs, "Only APPROVED sparepart requests can be fulfilled", "Failed to fulfill sparepart request") {
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, "Sparepart request fulfilled successfully")
+}
+
+// lockRequest locks the request for the rest of the transaction, failing unless it has status
+func lockRequest(ctx context.Context, repo repository.SparepartStockRepository, id int32, status string) (sqlcdb.SparepartRequest, error) {
+	request, err := repo.GetSparepartRequestForUpdate(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return request, errRequestNotFound
+	}
+	if err != nil {
+		return request, err
+	}
+	if request.Status != status {
+		return request, errRequestStatus
+	}
+	return request, nil
+}
+
+// handleTransactionError writes the response for a request transaction that failed,
+// reporting whether it succeeded instead
+func (h *SparepartRequestHandler) handleTransactionError(c *gin.Context, err error, status string, errs []utils.FieldError, statusMessage, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errRequestNotFound):
+		utils.NotFound(c, "Sparepart request not found")
+	case errors.Is(err, errRequestStatus):
+		utils.Error(c, fmt.Sprintf("%s; this one is %s", statusMessage, status), http.StatusConflict)
+	case errors.Is(err, errRequestInvalid):
+		utils.ValidationError(c, errs...)
+	default:
+		utils.HandleError(c, err, message, h.logger)
+	}
+	return false
+}
+
+// respond writes the request with its items and history
+func (h *SparepartRequestHandler) respond(c *gin.Context, statusCode int, id int32, message string) {
+	ctx := c.Request.Context()
+
+	request, err := h.queries.GetSparepartRequest(ctx, id)
+	if err != nil {
+		utils.NotFound(c, "Sparepart request not found")
+		return
+	}
+
+	items, err := h.queries.ListSparepartRequestItems(ctx, id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get sparepart request items", h.logger)
+		return
+	}
+
+	events, err := h.queries.ListSparepartRequestEvents(ctx, id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get sparepart request history", h.logger)
+		return
+	}
+
+	response := SparepartRequestDetailResponse{
+		SparepartRequestResponse: toSparepartRequestResponse(request),
+		Items:                    make([]SparepartRequestItemResponse, 0, len(items)),
+		History:                  make([]SparepartRequestEventResponse, 0, len(events)),
+	}
+	for _, item := range items {
+		response.Items = append(response.Items, toSparepartRequestItemResponse(item))
+	}
+	for _, event := range events {
+		response.History = append(response.History, toSparepartRequestEventResponse(event))
+	}
+
+	c.JSON(statusCode, utils.Response{
+		Success: true,
+		Message: message,
+		Data:    response,
+	})
+}
+
+func parseRequestID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart request ID")
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func toSparepartRequestResponse(row sqlcdb.GetSparepartRequestRow) SparepartRequestResponse {
+	response := SparepartRequestResponse{
+		ID:                    row.ID,
+		DestinationLocationID: row.DestinationLocationID,
+		Status:                row.Status,
+		RequestedBy:           row.RequestedBy,
+		CreatedAt:             utils.FormatTimestamp(row.CreatedAt),
+		UpdatedAt:             utils.FormatTimestamp(row.UpdatedAt),
+	}
+	if row.DestinationCluster.Valid {
+		response.DestinationCluster = &row.DestinationCluster.String
+	}
+	if row.SourceLocationID.Valid {
+		response.SourceLocationID = &row.SourceLocationID.Int32
+	}
+	if row.SourceCluster.Valid {
+		response.SourceCluster = &row.SourceCluster.String
+	}
+	if row.Notes.Valid {
+		response.Notes = &row.Notes.String
+	}
+	if row.TechnicianID.Valid {
+		response.TechnicianID = &row.TechnicianID.Int32
+	}
+	if row.TechnicianName.Valid {
+		response.Technician = &row.TechnicianName.String
+	}
+	return response
+}
+
+func toSparepartRequestItemResponse(row sqlcdb.ListSparepartRequestItemsRow) SparepartRequestItemResponse {
+	response := SparepartRequestItemResponse{
+		ID:          row.ID,
+		SparepartID: row.SparepartID,
+		StockType:   string(row.StockType),
+		Quantity:    row.Quantity,
+	}
+	if row.SparepartName.Valid {
+		response.SparepartName = &row.SparepartName.String
+	}
+	if row.TransferID.Valid {
+		response.TransferID = &row.TransferID.Int32
+	}
+	return response
+}
+
+func toSparepartRequestEventResponse(event sqlcdb.SparepartRequestEvent) SparepartRequestEventResponse {
+	response := SparepartRequestEventResponse{
+		Status:    event.Status,
+		CreatedAt: utils.FormatTimestamp(event.CreatedAt),
+	}
+	if event.Actor.Valid {
+		response.Actor = &event.Actor.String
+	}
+	if event.Note.Valid {
+		response.Note = &event.Note.String
+	}
+	return response
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+// expectRequestResponse expects the reads of the request written after a successful action
+func expectRequestResponse(repo *mocks.MockSparepartStockRepository, request sqlcdb.GetSparepartRequestRow) {
+	repo.EXPECT().GetSparepartRequest(gomock.Any(), request.ID).Return(request, nil)
+	repo.EXPECT().ListSparepartRequestItems(gomock.Any(), request.ID).Return([]sqlcdb.ListSparepartRequestItemsRow{}, nil)
+	repo.EXPECT().ListSparepartRequestEvents(gomock.Any(), request.ID).Return([]sqlcdb.SparepartRequestEvent{}, nil)
+}
+
+func TestSparepartRequestHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartRequestHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().CreateSparepartRequest(gomock.Any(), sqlcdb.CreateSparepartRequestParams{DestinationLocationID: 2, RequestedBy: "budi"}).
+		Return(sqlcdb.SparepartRequest{ID: 5, DestinationLocationID: 2, Status: requestStatusPending, RequestedBy: "budi"}, nil)
+	repo.EXPECT().CreateSparepartRequestItem(gomock.Any(), sqlcdb.CreateSparepartRequestItemParams{RequestID: 5, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2}).
+		Return(sqlcdb.SparepartRequestItem{ID: 1}, nil)
+	repo.EXPECT().CreateSparepartRequestEvent(gomock.Any(), sqlcdb.CreateSparepartRequestEventParams{
+		RequestID: 5, Status: requestStatusPending, Actor: pgtype.Text{String: "budi", Valid: true},
+	}).Return(nil)
+	expectRequestResponse(repo, sqlcdb.GetSparepartRequestRow{ID: 5, DestinationLocationID: 2, Status: requestStatusPending, RequestedBy: "budi"})
+
+	body := `{"destination_location_id": 2, "items": [{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 2}]}`
+	w := performRequestAs("budi", http.MethodPost, "/requests", h.Create, "/requests", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var request SparepartRequestDetailResponse
+	decodeResponse(t, w, &request)
+	if request.ID != 5 || request.Status != requestStatusPending || request.Items == nil || request.History == nil {
+		t.Fatalf("unexpected request response: %+v", request)
+	}
+}
+
+func TestSparepartRequestHandlerCreateRejectsDuplicateItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartRequestHandler(repo, testLogger)
+
+	body := `{"destination_location_id": 2, "items": [{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 2}, {"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 1}]}`
+	w := performRequestAs("budi", http.MethodPost, "/requests", h.Create, "/requests", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "items[1]" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestSparepartRequestHandlerApproveRequiresPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartRequestHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartRequestForUpdate(gomock.Any(), int32(5)).Return(sqlcdb.SparepartRequest{ID: 5, Status: requestStatusFulfilled}, nil)
+
+	w := performRequest(http.MethodPost, "/requests/:id/approve", h.Approve, "/requests/5/approve", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartRequestHandlerFulfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartRequestHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartRequestForUpdate(gomock.Any(), int32(5)).
+		Return(sqlcdb.SparepartRequest{ID: 5, DestinationLocationID: 2, Status: requestStatusApproved}, nil)
+	repo.EXPECT().ListSparepartRequestItems(gomock.Any(), int32(5)).Return([]sqlcdb.ListSparepartRequestItemsRow{
+		{ID: 1, RequestID: 5, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 3},
+	}, nil)
+	repo.EXPECT().GetActiveLocationForShare(gomock.Any(), int32(2)).Return(sqlcdb.Location{ID: 2, IsActive: true}, nil)
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), sqlcdb.GetSparepartStockByKeyForUpdateParams{LocationID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK}).
+		Return(sqlcdb.SparepartStockItem{ID: 10, LocationID: 1, Quantity: 5}, nil)
+	repo.EXPECT().TransferOutSparepartStock(gomock.Any(), sqlcdb.TransferOutSparepartStockParams{ID: 10, Quantity: 3}).
+		Return(sqlcdb.SparepartStockItem{ID: 10, LocationID: 1, Quantity: 2}, nil)
+	repo.EXPECT().TransferInSparepartStock(gomock.Any(), sqlcdb.TransferInSparepartStockParams{LocationID: 2, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 3}).
+		Return(sqlcdb.SparepartStockItem{ID: 20, LocationID: 2, Quantity: 3}, nil)
+	repo.EXPECT().CreateStockTransfer(gomock.Any(), sqlcdb.CreateStockTransferParams{
+		SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 3,
+		SourceLocationID: 1, DestinationLocationID: 2, SourceStockID: 10, DestinationStockID: 20,
+		Notes:         pgtype.Text{String: "Sparepart request #5", Valid: true},
+		TransferredBy: pgtype.Text{String: "admin", Valid: true},
+	}).Return(sqlcdb.StockTransfer{ID: 30}, nil)
+	repo.EXPECT().SetSparepartRequestItemTransfer(gomock.Any(), sqlcdb.SetSparepartRequestItemTransferParams{ID: 1, TransferID: pgtype.Int4{Int32: 30, Valid: true}}).
+		Return(nil)
+	repo.EXPECT().FulfillSparepartRequest(gomock.Any(), sqlcdb.FulfillSparepartRequestParams{ID: 5, SourceLocationID: pgtype.Int4{Int32: 1, Valid: true}}).
+		Return(sqlcdb.SparepartRequest{ID: 5, Status: requestStatusFulfilled}, nil)
+	repo.EXPECT().CreateSparepartRequestEvent(gomock.Any(), gomock.Any()).Return(nil)
+	expectRequestResponse(repo, sqlcdb.GetSparepartRequestRow{ID: 5, DestinationLocationID: 2, Status: requestStatusFulfilled})
+
+	w := performRequestAs("admin", http.MethodPost, "/requests/:id/fulfill", h.Fulfill, "/requests/5/fulfill", `{"source_location_id": 1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartRequestHandlerFulfillReportsShortages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartRequestHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartRequestForUpdate(gomock.Any(), int32(5)).
+		Return(sqlcdb.SparepartRequest{ID: 5, DestinationLocationID: 2, Status: requestStatusApproved}, nil)
+	repo.EXPECT().ListSparepartRequestItems(gomock.Any(), int32(5)).Return([]sqlcdb.ListSparepartRequestItemsRow{
+		{ID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 3},
+		{ID: 2, SparepartID: 8, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 1},
+	}, nil)
+	repo.EXPECT().GetActiveLocationForShare(gomock.Any(), int32(2)).Return(sqlcdb.Location{ID: 2, IsActive: true}, nil).Times(2)
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), gomock.Any()).Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 1}, nil)
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), gomock.Any()).Return(sqlcdb.SparepartStockItem{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/requests/:id/fulfill", h.Fulfill, "/requests/5/fulfill", `{"source_location_id": 1}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 2 || resp.Errors[0].Field != "items[0].quantity" || resp.Errors[1].Field != "items[1]" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
@@ -2,18 +2,24 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
-	"sparepart-management-services/internal/database"
 	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/exports"
 	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
 	"sparepart-management-services/internal/utils"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
@@ -23,26 +29,221 @@ type CreateSparepartStockRequest struct {
 	SparepartID uint             `json:"sparepart_id" binding:"required"`
 	StockType   models.StockType `json:"stock_type" binding:"required"`
 	Quantity    int              `json:"quantity"`
-	Notes       *string          `json:"notes,omitempty"`
+	// Unit the quantity is given in; when set it must be the unit of the sparepart
+	Unit  *string `json:"unit,omitempty"`
+	Notes *string `json:"notes,omitempty"`
+	// Cost price of one unit held at the location, instead of the master's
+	UnitCost *float64 `json:"unit_cost,omitempty"`
 }
 
-// Helper function to convert []string to []byte (JSONB)
-func documentationToBytes(docs []string) []byte {
-	if len(docs) == 0 {
-		return []byte("[]")
+// DocumentationPhoto is a documentation photo with its thumbnail (see utils.ThumbnailPath) and
+// the metadata sent with its upload
+type DocumentationPhoto struct {
+	URL          string  `json:"url"`
+	ThumbnailURL string  `json:"thumbnail_url"`
+	Caption      string  `json:"caption"`
+	TakenAt      *string `json:"taken_at"`
+	UploadedBy   string  `json:"uploaded_by"`
+}
+
+// documentationPhotos converts documentation JSONB to the photos returned in responses
+func documentationPhotos(data []byte) []DocumentationPhoto {
+	docs := utils.ParsePhotos(data)
+	photos := make([]DocumentationPhoto, 0, len(docs))
+	for _, doc := range docs {
+		photo := DocumentationPhoto{
+			URL:          doc.URL,
+			ThumbnailURL: utils.ThumbnailPath(doc.URL),
+			Caption:      doc.Caption,
+			UploadedBy:   doc.UploadedBy,
+		}
+		if doc.TakenAt != nil {
+			takenAt := doc.TakenAt.UTC().Format(time.RFC3339)
+			photo.TakenAt = &takenAt
+		}
+		photos = append(photos, photo)
+	}
+	return photos
+}
+
+// stagePhotos stages the "photos" files of a multipart form with the captions and taken_at
+// timestamps sent with them. When it fails the request is already answered and nothing stays
+// staged.
+func stagePhotos(c *gin.Context, uploader *uploads.Service, subDir, prefix string, logger *zap.Logger) ([]utils.Photo, []uploads.Staged, bool) {
+	form, err := c.MultipartForm()
+	if err != nil || form.File == nil {
+		return nil, nil, true
+	}
+	files := form.File["photos"]
+	metadata, errs := utils.ParsePhotoMetadata(c, len(files))
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return nil, nil, false
+	}
+
+	if !checkUploadQuota(c, uploader, files, logger) {
+		return nil, nil, false
+	}
+
+	var photos []utils.Photo
+	var staged []uploads.Staged
+	for i, file := range files {
+		upload, err := uploader.StageImage(c.Request.Context(), file, subDir, prefix, logger)
+		if err != nil {
+			uploader.DiscardStaged(staged, logger)
+			utils.BadRequest(c, "Failed to upload photo: "+err.Error())
+			return nil, nil, false
+		}
+		staged = append(staged, upload)
+		photos = append(photos, utils.NewPhoto(c, upload.Path, metadata[i]))
+	}
+	return photos, staged, true
+}
+
+// uploadPhotos stores the "photos" files of a multipart form added to an existing item, with the
+// captions and taken_at timestamps sent with them. When it fails the request is already
+// answered.
+func uploadPhotos(c *gin.Context, uploader *uploads.Service, subDir, prefix string, logger *zap.Logger) ([]utils.Photo, bool) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		utils.BadRequest(c, "Failed to parse multipart form")
+		return nil, false
+	}
+
+	files := form.File["photos"]
+	if len(files) == 0 {
+		utils.BadRequest(c, "No photos provided")
+		return nil, false
+	}
+	metadata, errs := utils.ParsePhotoMetadata(c, len(files))
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return nil, false
+	}
+
+	if !checkUploadQuota(c, uploader, files, logger) {
+		return nil, false
+	}
+
+	photos := make([]utils.Photo, 0, len(files))
+	for i, file := range files {
+		path, err := uploader.ProcessImage(c.Request.Context(), file, subDir, prefix, logger)
+		if err != nil {
+			// Nothing refers to the photos stored so far
+			releasePhotos(c.Request.Context(), uploader, photos, logger)
+			utils.BadRequest(c, "Failed to upload photo: "+err.Error())
+			return nil, false
+		}
+		photos = append(photos, utils.NewPhoto(c, path, metadata[i]))
+	}
+	return photos, true
+}
+
+// releasePhotos drops the references of uploaded photos that no item ended up referring to,
+// deleting the files that have no other reference
+func releasePhotos(ctx context.Context, uploader *uploads.Service, photos []utils.Photo, logger *zap.Logger) {
+	for _, photo := range photos {
+		if err := uploader.DeleteFile(ctx, photo.URL, logger); err != nil {
+			utils.RequestLogger(ctx, logger).Warn("Failed to release uploaded photo", zap.Error(err), zap.String("path", photo.URL))
+		}
+	}
+}
+
+// checkPhotoLimit checks that adding the "photos" files of a multipart form to an item with
+// existing photos keeps it within limit (0 is no limit). When it fails the request is already
+// answered.
+func checkPhotoLimit(c *gin.Context, existing, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+	added := 0
+	if form, err := c.MultipartForm(); err == nil && form.File != nil {
+		added = len(form.File["photos"])
+	}
+	if existing+added > limit {
+		utils.ValidationError(c, utils.FieldError{
+			Field:   "photos",
+			Message: fmt.Sprintf("an item can have at most %d photos; it has %d and %d were sent", limit, existing, added),
+		})
+		return false
+	}
+	return true
+}
+
+// checkUploadQuota checks that storing files stays within the upload quota (UPLOAD_QUOTA_MB).
+// When it fails the request is already answered.
+func checkUploadQuota(c *gin.Context, uploader *uploads.Service, files []*multipart.FileHeader, logger *zap.Logger) bool {
+	if len(files) == 0 {
+		return true
+	}
+	var size int64
+	for _, file := range files {
+		size += file.Size
+	}
+	err := uploader.CheckQuota(c.Request.Context(), size)
+	if errors.Is(err, uploads.ErrQuotaExceeded) {
+		utils.Error(c, "Failed to upload photo: "+err.Error(), http.StatusInsufficientStorage)
+		return false
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to check upload quota", logger)
+		return false
+	}
+	return true
+}
+
+// PhotoMetadataRequest changes the caption and taken-at timestamp of a stored photo
+type PhotoMetadataRequest struct {
+	Caption *string `json:"caption,omitempty"` // empty string clears the caption
+	// RFC3339 timestamp; empty string clears it
+	TakenAt *string `json:"taken_at,omitempty"`
+}
+
+// bindPhotoMetadata applies a PhotoMetadataRequest to photo. When it fails the request is
+// already answered.
+func bindPhotoMetadata(c *gin.Context, photo *utils.Photo) bool {
+	var req PhotoMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return false
+	}
+	if req.Caption == nil && req.TakenAt == nil {
+		utils.BadRequest(c, "No fields to update")
+		return false
+	}
+
+	var errs []utils.FieldError
+	if req.Caption != nil {
+		caption := strings.TrimSpace(*req.Caption)
+		if msg := utils.ValidatePhotoCaption(caption); msg != "" {
+			errs = append(errs, utils.FieldError{Field: "caption", Message: msg})
+		}
+		photo.Caption = caption
+	}
+	if req.TakenAt != nil {
+		photo.TakenAt = nil
+		if value := strings.TrimSpace(*req.TakenAt); value != "" {
+			takenAt, msg := utils.ParsePhotoTakenAt(value)
+			if msg != "" {
+				errs = append(errs, utils.FieldError{Field: "taken_at", Message: msg})
+			}
+			photo.TakenAt = &takenAt
+		}
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return false
 	}
-	data, _ := json.Marshal(docs)
-	return data
+	return true
 }
 
-// Helper function to convert []byte (JSONB) to []string
-func documentationFromBytes(data []byte) []string {
-	if len(data) == 0 {
-		return []string{}
+// replacedPhoto is the photo uploaded to path in place of old; it keeps the caption of old
+// unless a caption is sent with it
+func replacedPhoto(c *gin.Context, old utils.Photo, path string, metadata utils.PhotoMetadata) utils.Photo {
+	if _, sent := c.GetPostForm("caption"); !sent {
+		metadata.Caption = old.Caption
 	}
-	var docs []string
-	json.Unmarshal(data, &docs)
-	return docs
+	return utils.NewPhoto(c, path, metadata)
 }
 
 // SparepartStockResponse represents the nested response structure for sparepart stock
@@ -52,8 +253,10 @@ type SparepartStockResponse struct {
 	SparepartID   int32                   `json:"sparepart_id"`
 	StockType     string                  `json:"stock_type"`
 	Quantity      int32                   `json:"quantity"`
-	Documentation []string                `json:"documentation"`
+	Documentation []DocumentationPhoto    `json:"documentation"`
 	Notes         *string                 `json:"notes,omitempty"`
+	UnitCost      *float64                `json:"unit_cost,omitempty"`
+	Version       int32                   `json:"version"`
 	CreatedAt     string                  `json:"created_at"`
 	UpdatedAt     string                  `json:"updated_at"`
 	Location      SparepartStockLocation  `json:"location"`
@@ -70,11 +273,13 @@ type SparepartStockLocation struct {
 }
 
 type SparepartStockSparepart struct {
-	ID        int32  `json:"id"`
-	Name      string `json:"name"`
-	ItemType  string `json:"item_type"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        int32    `json:"id"`
+	Name      string   `json:"name"`
+	ItemType  string   `json:"item_type"`
+	Unit      string   `json:"unit"`
+	UnitCost  *float64 `json:"unit_cost,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
 }
 
 // SparepartStockGroupedResponse represents the grouped response structure (grouped by location)
@@ -85,46 +290,36 @@ type SparepartStockGroupedResponse struct {
 	Sparepart  []SparepartStockGroupedItem `json:"sparepart"`
 	CreatedAt  string                      `json:"created_at"` // from first stock item
 	UpdatedAt  string                      `json:"updated_at"` // from first stock item
+	// Completeness is the documentation completeness score of the location
+	Completeness *LocationCompleteness `json:"completeness,omitempty"`
+	// ContactPersons of the location, only with include=contact_person
+	ContactPersons []LocationContactPerson `json:"contact_persons,omitempty"`
 }
 
 // SparepartStockGroupedItem represents a sparepart item in the grouped response
 type SparepartStockGroupedItem struct {
-	ID            int32    `json:"id"`       // sparepart_id
-	StockID       int32    `json:"stock_id"` // stock item id (PK)
-	Name          string   `json:"name"`
-	ItemType      string   `json:"item_type"`
-	StockType     string   `json:"stock_type"`
-	Quantity      int32    `json:"quantity"`
-	Documentation []string `json:"documentation"`
-	Notes         *string  `json:"notes,omitempty"`
+	ID            int32                `json:"id"`       // sparepart_id
+	StockID       int32                `json:"stock_id"` // stock item id (PK)
+	Name          string               `json:"name"`
+	ItemType      string               `json:"item_type"`
+	StockType     string               `json:"stock_type"`
+	Quantity      int32                `json:"quantity"`
+	Unit          string               `json:"unit"`
+	Documentation []DocumentationPhoto `json:"documentation"`
+	Notes         *string              `json:"notes,omitempty"`
+	// Cost price of one unit: the item's own, or the master's when it has none
+	UnitCost *float64 `json:"unit_cost,omitempty"`
+	Version  int32    `json:"version"`
 }
 
 // transformSparepartStock transforms sqlc flat structure to nested response
 func transformSparepartStock(row sqlcdb.ListSparepartStocksRow) SparepartStockResponse {
-	createdAt := ""
-	if row.CreatedAt.Valid {
-		createdAt = row.CreatedAt.Time.Format(time.RFC3339)
-	}
-	updatedAt := ""
-	if row.UpdatedAt.Valid {
-		updatedAt = row.UpdatedAt.Time.Format(time.RFC3339)
-	}
-	locationCreatedAt := ""
-	if row.LocationCreatedAt.Valid {
-		locationCreatedAt = row.LocationCreatedAt.Time.Format(time.RFC3339)
-	}
-	locationUpdatedAt := ""
-	if row.LocationUpdatedAt.Valid {
-		locationUpdatedAt = row.LocationUpdatedAt.Time.Format(time.RFC3339)
-	}
-	sparepartCreatedAt := ""
-	if row.SparepartCreatedAt.Valid {
-		sparepartCreatedAt = row.SparepartCreatedAt.Time.Format(time.RFC3339)
-	}
-	sparepartUpdatedAt := ""
-	if row.SparepartUpdatedAt.Valid {
-		sparepartUpdatedAt = row.SparepartUpdatedAt.Time.Format(time.RFC3339)
-	}
+	createdAt := utils.FormatTimestamp(row.CreatedAt)
+	updatedAt := utils.FormatTimestamp(row.UpdatedAt)
+	locationCreatedAt := utils.FormatTimestamp(row.LocationCreatedAt)
+	locationUpdatedAt := utils.FormatTimestamp(row.LocationUpdatedAt)
+	sparepartCreatedAt := utils.FormatTimestamp(row.SparepartCreatedAt)
+	sparepartUpdatedAt := utils.FormatTimestamp(row.SparepartUpdatedAt)
 
 	var notes *string
 	if row.Notes.Valid {
@@ -137,8 +332,10 @@ func transformSparepartStock(row sqlcdb.ListSparepartStocksRow) SparepartStockRe
 		SparepartID:   row.SparepartID,
 		StockType:     string(row.StockType),
 		Quantity:      row.Quantity,
-		Documentation: documentationFromBytes(row.Documentation),
+		Documentation: documentationPhotos(row.Documentation),
 		Notes:         notes,
+		UnitCost:      utils.NumericValue(row.UnitCost),
+		Version:       row.Version,
 		CreatedAt:     createdAt,
 		UpdatedAt:     updatedAt,
 		Location: SparepartStockLocation{
@@ -153,6 +350,8 @@ func transformSparepartStock(row sqlcdb.ListSparepartStocksRow) SparepartStockRe
 			ID:        row.SparepartID2,
 			Name:      row.SparepartName,
 			ItemType:  string(row.ItemType),
+			Unit:      row.Unit,
+			UnitCost:  utils.NumericValue(row.SparepartUnitCost),
 			CreatedAt: sparepartCreatedAt,
 			UpdatedAt: sparepartUpdatedAt,
 		},
@@ -161,30 +360,12 @@ func transformSparepartStock(row sqlcdb.ListSparepartStocksRow) SparepartStockRe
 
 // transformSparepartStockFromGet transforms GetSparepartStockRow to nested response
 func transformSparepartStockFromGet(row sqlcdb.GetSparepartStockRow) SparepartStockResponse {
-	createdAt := ""
-	if row.CreatedAt.Valid {
-		createdAt = row.CreatedAt.Time.Format(time.RFC3339)
-	}
-	updatedAt := ""
-	if row.UpdatedAt.Valid {
-		updatedAt = row.UpdatedAt.Time.Format(time.RFC3339)
-	}
-	locationCreatedAt := ""
-	if row.LocationCreatedAt.Valid {
-		locationCreatedAt = row.LocationCreatedAt.Time.Format(time.RFC3339)
-	}
-	locationUpdatedAt := ""
-	if row.LocationUpdatedAt.Valid {
-		locationUpdatedAt = row.LocationUpdatedAt.Time.Format(time.RFC3339)
-	}
-	sparepartCreatedAt := ""
-	if row.SparepartCreatedAt.Valid {
-		sparepartCreatedAt = row.SparepartCreatedAt.Time.Format(time.RFC3339)
-	}
-	sparepartUpdatedAt := ""
-	if row.SparepartUpdatedAt.Valid {
-		sparepartUpdatedAt = row.SparepartUpdatedAt.Time.Format(time.RFC3339)
-	}
+	createdAt := utils.FormatTimestamp(row.CreatedAt)
+	updatedAt := utils.FormatTimestamp(row.UpdatedAt)
+	locationCreatedAt := utils.FormatTimestamp(row.LocationCreatedAt)
+	locationUpdatedAt := utils.FormatTimestamp(row.LocationUpdatedAt)
+	sparepartCreatedAt := utils.FormatTimestamp(row.SparepartCreatedAt)
+	sparepartUpdatedAt := utils.FormatTimestamp(row.SparepartUpdatedAt)
 
 	var notes *string
 	if row.Notes.Valid {
@@ -197,8 +378,10 @@ func transformSparepartStockFromGet(row sqlcdb.GetSparepartStockRow) SparepartSt
 		SparepartID:   row.SparepartID,
 		StockType:     string(row.StockType),
 		Quantity:      row.Quantity,
-		Documentation: documentationFromBytes(row.Documentation),
+		Documentation: documentationPhotos(row.Documentation),
 		Notes:         notes,
+		UnitCost:      utils.NumericValue(row.UnitCost),
+		Version:       row.Version,
 		CreatedAt:     createdAt,
 		UpdatedAt:     updatedAt,
 		Location: SparepartStockLocation{
@@ -213,16 +396,20 @@ func transformSparepartStockFromGet(row sqlcdb.GetSparepartStockRow) SparepartSt
 			ID:        row.SparepartID2,
 			Name:      row.SparepartName,
 			ItemType:  string(row.ItemType),
+			Unit:      row.Unit,
+			UnitCost:  utils.NumericValue(row.SparepartUnitCost),
 			CreatedAt: sparepartCreatedAt,
 			UpdatedAt: sparepartUpdatedAt,
 		},
 	}
 }
 
-// groupSparepartStocksByLocation groups flat list of stock items by location_id
+// groupSparepartStocksByLocation groups flat list of stock items by location_id,
+// keeping locations in the order they first appear in items
 func groupSparepartStocksByLocation(items []sqlcdb.ListSparepartStocksRow) []SparepartStockGroupedResponse {
 	// Map to store grouped data: location_id -> grouped response
 	locationMap := make(map[int32]*SparepartStockGroupedResponse)
+	var locationOrder []int32
 
 	for _, item := range items {
 		locationID := item.LocationID
@@ -230,23 +417,11 @@ func groupSparepartStocksByLocation(items []sqlcdb.ListSparepartStocksRow) []Spa
 		// Get or create grouped response for this location
 		grouped, exists := locationMap[locationID]
 		if !exists {
-			locationCreatedAt := ""
-			if item.LocationCreatedAt.Valid {
-				locationCreatedAt = item.LocationCreatedAt.Time.Format(time.RFC3339)
-			}
-			locationUpdatedAt := ""
-			if item.LocationUpdatedAt.Valid {
-				locationUpdatedAt = item.LocationUpdatedAt.Time.Format(time.RFC3339)
-			}
+			locationCreatedAt := utils.FormatTimestamp(item.LocationCreatedAt)
+			locationUpdatedAt := utils.FormatTimestamp(item.LocationUpdatedAt)
 
-			createdAt := ""
-			if item.CreatedAt.Valid {
-				createdAt = item.CreatedAt.Time.Format(time.RFC3339)
-			}
-			updatedAt := ""
-			if item.UpdatedAt.Valid {
-				updatedAt = item.UpdatedAt.Time.Format(time.RFC3339)
-			}
+			createdAt := utils.FormatTimestamp(item.CreatedAt)
+			updatedAt := utils.FormatTimestamp(item.UpdatedAt)
 
 			grouped = &SparepartStockGroupedResponse{
 				ID:         locationID,
@@ -264,6 +439,7 @@ func groupSparepartStocksByLocation(items []sqlcdb.ListSparepartStocksRow) []Spa
 				UpdatedAt: updatedAt,
 			}
 			locationMap[locationID] = grouped
+			locationOrder = append(locationOrder, locationID)
 		}
 
 		// Add sparepart item to the array
@@ -279,106 +455,155 @@ func groupSparepartStocksByLocation(items []sqlcdb.ListSparepartStocksRow) []Spa
 			ItemType:      string(item.ItemType),
 			StockType:     string(item.StockType),
 			Quantity:      item.Quantity,
-			Documentation: documentationFromBytes(item.Documentation),
+			Unit:          item.Unit,
+			Documentation: documentationPhotos(item.Documentation),
 			Notes:         notes,
+			UnitCost:      utils.NumericValue(item.UnitCost),
+			Version:       item.Version,
+		}
+		if sparepartItem.UnitCost == nil {
+			sparepartItem.UnitCost = utils.NumericValue(item.SparepartUnitCost)
 		}
 
 		grouped.Sparepart = append(grouped.Sparepart, sparepartItem)
 	}
 
 	// Convert map to slice
-	result := make([]SparepartStockGroupedResponse, 0, len(locationMap))
-	for _, grouped := range locationMap {
-		result = append(result, *grouped)
+	result := make([]SparepartStockGroupedResponse, 0, len(locationOrder))
+	for _, locationID := range locationOrder {
+		result = append(result, *locationMap[locationID])
 	}
 
 	return result
 }
 
-// getGroupedSparepartStockByLocationID gets all stock items for a location and returns grouped response
-func (h *SparepartStockHandler) getGroupedSparepartStockByLocationID(ctx context.Context, locationID int32) (*SparepartStockGroupedResponse, error) {
-	// Get all stock items for this location (no filters)
-	listParams := sqlcdb.ListSparepartStocksParams{
-		Column1: "",
-		Column2: "",
-		Column3: "",
-		Column4: "",
-		Column5: "",
-		Limit:   10000,
-		Offset:  0,
-	}
-	allItems, err := h.queries.ListSparepartStocks(ctx, listParams)
+// sparepartStockByLocationRows converts location-scoped query rows so they can share the grouping logic
+func sparepartStockByLocationRows(rows []sqlcdb.ListSparepartStocksByLocationRow) []sqlcdb.ListSparepartStocksRow {
+	items := make([]sqlcdb.ListSparepartStocksRow, len(rows))
+	for i, row := range rows {
+		items[i] = sqlcdb.ListSparepartStocksRow(row)
+	}
+	return items
+}
+
+// attachCompleteness sets the completeness score of every location in groups
+func (h *SparepartStockHandler) attachCompleteness(ctx context.Context, groups []SparepartStockGroupedResponse) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	locationIDs := make([]int32, len(groups))
+	for i, group := range groups {
+		locationIDs[i] = group.LocationID
+	}
+	rows, err := h.queries.ListLocationCompletenessByIDs(ctx, sqlcdb.ListLocationCompletenessByIDsParams{
+		LocationIds: locationIDs,
+		OpnameSince: opnameSince(defaultOpnameDays),
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Filter items by location_id
-	var locationItems []sqlcdb.ListSparepartStocksRow
-	for _, stockItem := range allItems {
-		if stockItem.LocationID == locationID {
-			locationItems = append(locationItems, stockItem)
+	scores := make(map[int32]LocationCompleteness, len(rows))
+	for _, row := range rows {
+		scores[row.LocationID] = toLocationCompleteness(row)
+	}
+	for i := range groups {
+		if score, ok := scores[groups[i].LocationID]; ok {
+			groups[i].Completeness = &score
 		}
 	}
+	return nil
+}
+
+// attachContactPersons sets the contact persons of every location in groups with one query
+func (h *SparepartStockHandler) attachContactPersons(ctx context.Context, groups []SparepartStockGroupedResponse) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	locationIDs := make([]int32, len(groups))
+	for i, group := range groups {
+		locationIDs[i] = group.LocationID
+	}
+	rows, err := h.queries.ListContactPersonsByLocations(ctx, locationIDs)
+	if err != nil {
+		return err
+	}
+
+	contacts := groupContactPersonsByLocation(rows)
+	for i := range groups {
+		groups[i].ContactPersons = contacts[groups[i].LocationID]
+	}
+	return nil
+}
+
+// getGroupedSparepartStockByLocationID gets all stock items for a location and returns grouped response
+func (h *SparepartStockHandler) getGroupedSparepartStockByLocationID(ctx context.Context, locationID int32) (*SparepartStockGroupedResponse, error) {
+	rows, err := h.queries.ListSparepartStocksByLocation(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Group by location_id
-	groupedItems := groupSparepartStocksByLocation(locationItems)
+	groupedItems := groupSparepartStocksByLocation(sparepartStockByLocationRows(rows))
 	if len(groupedItems) == 0 {
 		return nil, fmt.Errorf("no stock items found for location_id %d", locationID)
 	}
+	if err := h.attachCompleteness(ctx, groupedItems); err != nil {
+		return nil, err
+	}
 
 	return &groupedItems[0], nil
 }
 
+// UpdateSparepartStockRequest is a partial update: omitted fields keep their current value
 type UpdateSparepartStockRequest struct {
-	Quantity int     `json:"quantity"`
-	Notes    *string `json:"notes,omitempty"`
+	Quantity *int    `json:"quantity,omitempty"`
+	Unit     *string `json:"unit,omitempty"`  // must be the unit of the sparepart when set
+	Notes    *string `json:"notes,omitempty"` // empty string clears the notes
+	// Cost price of one unit held at the location
+	UnitCost *float64 `json:"unit_cost,omitempty" binding:"omitempty,min=0,max=999999999999.99"`
+	// Version the update is based on; required unless sent as the If-Match header
+	Version *int32 `json:"version,omitempty" binding:"omitempty,min=1"`
 }
 
 type SparepartStockHandler struct {
 	logger  *zap.Logger
-	queries *sqlcdb.Queries
+	queries repository.SparepartStockRepository
+	uploads *uploads.Service
+	reports *reports.Store
+	// maxPhotos caps the photos of an item; 0 is no limit
+	maxPhotos int
 }
 
-func NewSparepartStockHandler() *SparepartStockHandler {
+func NewSparepartStockHandler(queries repository.SparepartStockRepository, uploads *uploads.Service, reports *reports.Store, maxPhotos int, logger *zap.Logger) *SparepartStockHandler {
 	return &SparepartStockHandler{
-		logger:  utils.GetLogger(),
-		queries: sqlcdb.New(database.GetDB()),
+		logger:    logger,
+		queries:   queries,
+		uploads:   uploads,
+		reports:   reports,
+		maxPhotos: maxPhotos,
 	}
 }
 
 // buildSparepartStockParams builds filter parameters from query string
 func (h *SparepartStockHandler) buildSparepartStockParams(c *gin.Context) sqlcdb.CountSparepartStocksParams {
-	var region, regency, cluster, stockType, sparepartName string
-
-	if r := c.Query("region"); r != "" {
-		region = r
-	}
-	if r := c.Query("regency"); r != "" {
-		regency = r
-	}
-	if r := c.Query("cluster"); r != "" {
-		cluster = r
-	}
-	if st := c.Query("stock_type"); st != "" {
-		stockType = st
-	}
-	if sn := c.Query("sparepart_name"); sn != "" {
-		// For sparepart_name, we use the first name (sqlc query handles ILIKE)
-		names := strings.Split(sn, ",")
-		if len(names) > 0 {
-			sparepartName = strings.TrimSpace(names[0])
-		}
-	}
-
 	return sqlcdb.CountSparepartStocksParams{
-		Column1: region,
-		Column2: regency,
-		Column3: cluster,
-		Column4: stockType,
-		Column5: sparepartName,
+		Region:    utils.TextFilter(c.Query("region")),
+		Regency:   utils.TextFilter(c.Query("regency")),
+		Cluster:   utils.TextFilter(c.Query("cluster")),
+		StockType: utils.TextFilter(c.Query("stock_type")),
+		Names:     utils.ListFilter(c.Query("sparepart_name")),
 	}
 }
 
+// exportReader pages through the export query in batches of exportBatchSize rows
+func (h *SparepartStockHandler) exportReader(ctx context.Context, params sqlcdb.ListSparepartStocksForExportParams) utils.BatchReader[sqlcdb.ListSparepartStocksForExportRow] {
+	params.Limit = exportBatchSize
+	return exports.StockReader(ctx, h.queries.ListSparepartStocksForExport, params)
+}
+
 // @Summary Get all sparepart stock items
 // @Description Get all sparepart stock items with optional filters
 // @Tags Sparepart Stock
@@ -388,9 +613,13 @@ func (h *SparepartStockHandler) buildSparepartStockParams(c *gin.Context) sqlcdb
 // @Param region query string false "Filter by region (exact match)"
 // @Param regency query string false "Filter by regency (partial match, case-insensitive)"
 // @Param cluster query string false "Filter by cluster (partial match, case-insensitive)"
-// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK)"
+// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
+// @Param group_by query string false "location: items grouped per location, paginated by location; none: one entry per stock item, paginated by item" Enums(location, none) default(location)
+// @Param fields query string false "Fields to return, comma-separated, dotted for nested fields (e.g. id,location.cluster)"
+// @Param expand query string false "Nested objects to include in full; once given, other nested objects are reduced to their id"
+// @Param include query string false "Related data to add to each location: contact_person (group_by=location only)" Enums(contact_person)
 // @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
+// @Param limit query int false "Items per page (max 100)" default(10)
 // @Success 200 {object} utils.PaginatedResponse
 // @Router /sparepart/stock [get]
 func (h *SparepartStockHandler) GetAll(c *gin.Context) {
@@ -400,8 +629,24 @@ func (h *SparepartStockHandler) GetAll(c *gin.Context) {
 	filterParams := h.buildSparepartStockParams(c)
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	pagination, errs := utils.ParsePagination(c)
+	flat, groupByErrs := utils.ParseGroupBy(c)
+	errs = append(errs, groupByErrs...)
+	shape, shapeErrs := utils.ParseResponseShape(c)
+	errs = append(errs, shapeErrs...)
+	include, includeErrs := utils.ParseInclude(c, includeContactPerson)
+	errs = append(errs, includeErrs...)
+	if flat && include[includeContactPerson] {
+		errs = append(errs, utils.FieldError{Field: "include", Message: "contact_person needs group_by=location"})
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+	if flat {
+		h.getAllFlat(c, filterParams, pagination, shape)
+		return
+	}
 
 	// Count total (count distinct locations)
 	total, err := h.queries.CountSparepartStocks(ctx, filterParams)
@@ -410,16 +655,15 @@ func (h *SparepartStockHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	// List items - get all items (no limit/offset here, we'll group and paginate after)
-	// We need to fetch more items to ensure we have enough for grouping
+	// List items - limit/offset apply to locations, each location comes with all of its items
 	listParams := sqlcdb.ListSparepartStocksParams{
-		Column1: filterParams.Column1,
-		Column2: filterParams.Column2,
-		Column3: filterParams.Column3,
-		Column4: filterParams.Column4,
-		Column5: filterParams.Column5,
-		Limit:   10000, // Large limit to get all items for grouping
-		Offset:  0,
+		Region:    filterParams.Region,
+		Regency:   filterParams.Regency,
+		Cluster:   filterParams.Cluster,
+		StockType: filterParams.StockType,
+		Names:     filterParams.Names,
+		Limit:     int32(pagination.Limit),
+		Offset:    int32(pagination.Offset()),
 	}
 	items, err := h.queries.ListSparepartStocks(ctx, listParams)
 	if err != nil {
@@ -428,24 +672,63 @@ func (h *SparepartStockHandler) GetAll(c *gin.Context) {
 	}
 
 	// Group by location_id
-	groupedItems := groupSparepartStocksByLocation(items)
+	paginatedItems := groupSparepartStocksByLocation(items)
+	if err := h.attachCompleteness(ctx, paginatedItems); err != nil {
+		utils.HandleError(c, err, "Failed to get location completeness", h.logger)
+		return
+	}
+	if include[includeContactPerson] {
+		if err := h.attachContactPersons(ctx, paginatedItems); err != nil {
+			utils.HandleError(c, err, "Failed to get contact persons", h.logger)
+			return
+		}
+	}
+
+	data, err := shape.Apply(paginatedItems)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to shape response", h.logger)
+		return
+	}
+
+	utils.SuccessWithPagination(c, "Sparepart stock items retrieved successfully", data, pagination.Page, pagination.Limit, total)
+}
+
+// getAllFlat lists the stock items one per entry (group_by=none); limit/offset apply to items
+func (h *SparepartStockHandler) getAllFlat(c *gin.Context, filterParams sqlcdb.CountSparepartStocksParams, pagination utils.Pagination, shape *utils.ResponseShape) {
+	ctx := c.Request.Context()
+
+	total, err := h.queries.CountSparepartStockItems(ctx, sqlcdb.CountSparepartStockItemsParams(filterParams))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count sparepart stock items", h.logger)
+		return
+	}
 
-	// Apply pagination to grouped items (per location)
-	startIdx := (page - 1) * limit
-	endIdx := startIdx + limit
-	if startIdx > len(groupedItems) {
-		startIdx = len(groupedItems)
+	rows, err := h.queries.ListSparepartStockItems(ctx, sqlcdb.ListSparepartStockItemsParams{
+		Region:    filterParams.Region,
+		Regency:   filterParams.Regency,
+		Cluster:   filterParams.Cluster,
+		StockType: filterParams.StockType,
+		Names:     filterParams.Names,
+		Limit:     int32(pagination.Limit),
+		Offset:    int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get sparepart stock items", h.logger)
+		return
 	}
-	if endIdx > len(groupedItems) {
-		endIdx = len(groupedItems)
+
+	response := make([]SparepartStockResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, transformSparepartStock(sqlcdb.ListSparepartStocksRow(row)))
 	}
 
-	var paginatedItems []SparepartStockGroupedResponse
-	if startIdx < len(groupedItems) {
-		paginatedItems = groupedItems[startIdx:endIdx]
+	data, err := shape.Apply(response)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to shape response", h.logger)
+		return
 	}
 
-	utils.SuccessWithPagination(c, "Sparepart stock items retrieved successfully", paginatedItems, page, limit, total)
+	utils.SuccessWithPagination(c, "Sparepart stock items retrieved successfully", data, pagination.Page, pagination.Limit, total)
 }
 
 // @Summary Get sparepart stock item by ID (returns grouped by location)
@@ -454,6 +737,9 @@ func (h *SparepartStockHandler) GetAll(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Sparepart Stock Item ID"
+// @Param fields query string false "Fields to return, comma-separated, dotted for nested fields (e.g. id,location.cluster)"
+// @Param expand query string false "Nested objects to include in full; once given, other nested objects are reduced to their id"
+// @Param include query string false "Related data to add to the location: contact_person" Enums(contact_person)
 // @Success 200 {object} utils.Response
 // @Router /sparepart/stock/{id} [get]
 func (h *SparepartStockHandler) GetByID(c *gin.Context) {
@@ -464,6 +750,13 @@ func (h *SparepartStockHandler) GetByID(c *gin.Context) {
 		utils.BadRequest(c, "Invalid sparepart stock item ID")
 		return
 	}
+	shape, errs := utils.ParseResponseShape(c)
+	include, includeErrs := utils.ParseInclude(c, includeContactPerson)
+	errs = append(errs, includeErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
 
 	// Get the stock item to find its location_id
 	item, err := h.queries.GetSparepartStock(ctx, int32(id))
@@ -473,38 +766,37 @@ func (h *SparepartStockHandler) GetByID(c *gin.Context) {
 	}
 
 	// Get all stock items for this location
-	listParams := sqlcdb.ListSparepartStocksParams{
-		Column1: "", // No filters, just get all for this location
-		Column2: "",
-		Column3: "",
-		Column4: "",
-		Column5: "",
-		Limit:   10000,
-		Offset:  0,
-	}
-	allItems, err := h.queries.ListSparepartStocks(ctx, listParams)
+	rows, err := h.queries.ListSparepartStocksByLocation(ctx, item.LocationID)
 	if err != nil {
 		utils.HandleError(c, err, "Failed to get sparepart stock items", h.logger)
 		return
 	}
 
-	// Filter items by location_id
-	var locationItems []sqlcdb.ListSparepartStocksRow
-	for _, stockItem := range allItems {
-		if stockItem.LocationID == item.LocationID {
-			locationItems = append(locationItems, stockItem)
-		}
-	}
-
 	// Group by location_id (should be only one location)
-	groupedItems := groupSparepartStocksByLocation(locationItems)
+	groupedItems := groupSparepartStocksByLocation(sparepartStockByLocationRows(rows))
 	if len(groupedItems) == 0 {
 		utils.NotFound(c, "Location not found")
 		return
 	}
+	if err := h.attachCompleteness(ctx, groupedItems); err != nil {
+		utils.HandleError(c, err, "Failed to get location completeness", h.logger)
+		return
+	}
+	if include[includeContactPerson] {
+		if err := h.attachContactPersons(ctx, groupedItems); err != nil {
+			utils.HandleError(c, err, "Failed to get contact persons", h.logger)
+			return
+		}
+	}
 
 	// Return the first (and only) grouped item
-	utils.Success(c, "Sparepart stock items retrieved successfully", groupedItems[0])
+	data, err := shape.Apply(groupedItems[0])
+	if err != nil {
+		utils.HandleError(c, err, "Failed to shape response", h.logger)
+		return
+	}
+
+	utils.Success(c, "Sparepart stock items retrieved successfully", data)
 }
 
 // @Summary Create sparepart stock item with photos
@@ -514,11 +806,17 @@ func (h *SparepartStockHandler) GetByID(c *gin.Context) {
 // @Produce json
 // @Param location_id formData int true "Location ID"
 // @Param sparepart_id formData int true "Sparepart ID"
-// @Param stock_type formData string true "Stock Type (NEW_STOCK, USED_STOCK)"
+// @Param stock_type formData string true "Stock Type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
 // @Param quantity formData int false "Quantity"
+// @Param unit formData string false "Unit the quantity is given in; must be the unit of the sparepart"
 // @Param notes formData string false "Notes"
+// @Param unit_cost formData number false "Cost price of one unit held at the location; the master's unit cost applies without it"
 // @Param photos formData file false "Photo files (multiple allowed)"
+// @Param captions formData []string false "Caption of each photo, in the order of the photos"
+// @Param taken_at formData []string false "When each photo was taken (RFC3339), in the order of the photos"
 // @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response "Invalid request, or more photos than an item can have"
+// @Failure 507 {object} utils.Response "Upload quota exceeded"
 // @Router /sparepart/stock [post]
 func (h *SparepartStockHandler) Create(c *gin.Context) {
 	var req CreateSparepartStockRequest
@@ -548,8 +846,8 @@ func (h *SparepartStockHandler) Create(c *gin.Context) {
 
 	// Parse stock_type
 	req.StockType = models.StockType(stockTypeStr)
-	if req.StockType != models.StockTypeNew && req.StockType != models.StockTypeUsed {
-		utils.BadRequest(c, "Invalid stock_type. Must be NEW_STOCK or USED_STOCK")
+	if !req.StockType.Valid() {
+		utils.BadRequest(c, "Invalid stock_type. Must be NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR or RESERVED")
 		return
 	}
 
@@ -560,42 +858,54 @@ func (h *SparepartStockHandler) Create(c *gin.Context) {
 			req.Quantity = quantity
 		}
 	}
+	if req.Quantity < 0 {
+		utils.ValidationError(c, utils.NegativeQuantityError("quantity"))
+		return
+	}
 
 	// Parse notes
 	if notes != "" {
 		req.Notes = &notes
 	}
 
+	// Parse unit_cost
+	if unitCostStr := c.PostForm("unit_cost"); unitCostStr != "" {
+		unitCost, err := strconv.ParseFloat(unitCostStr, 64)
+		if err != nil || unitCost < 0 || unitCost > utils.MaxUnitCost {
+			utils.ValidationError(c, utils.UnitCostError("unit_cost"))
+			return
+		}
+		req.UnitCost = &unitCost
+	}
+
 	ctx := c.Request.Context()
 
-	// Process file uploads
-	var documentation []string
-	form, err := c.MultipartForm()
-	if err == nil && form.File != nil {
-		files := form.File["photos"]
-		subDir := utils.GetSubDirForSparepartStock(string(req.StockType))
-		prefix := utils.GetPrefixForSparepartStock(string(req.StockType))
-		for _, file := range files {
-			path, err := utils.ProcessImageUpload(file, subDir, prefix, h.logger)
-			if err != nil {
-				utils.BadRequest(c, "Failed to upload photo: "+err.Error())
-				return
-			}
-			documentation = append(documentation, path)
+	if unit := c.PostForm("unit"); unit != "" {
+		errs, err := h.validateUnits(ctx, []stockUnit{{field: "unit", sparepartID: int32(req.SparepartID), unit: unit}})
+		if err != nil {
+			utils.HandleError(c, err, "Failed to get sparepart", h.logger)
+			return
+		}
+		if len(errs) > 0 {
+			utils.ValidationError(c, errs...)
+			return
 		}
 	}
 
-	// Convert StockType to sqlc StockType
-	var stockType sqlcdb.StockType
-	if req.StockType == models.StockTypeNew {
-		stockType = sqlcdb.StockTypeNEWSTOCK
-	} else if req.StockType == models.StockTypeUsed {
-		stockType = sqlcdb.StockTypeUSEDSTOCK
-	} else {
-		utils.BadRequest(c, "Invalid stock_type. Must be NEW_STOCK or USED_STOCK")
+	// Stage file uploads; they are only moved into place once the item is created
+	subDir := utils.GetSubDirForSparepartStock(string(req.StockType))
+	prefix := utils.GetPrefixForSparepartStock(string(req.StockType))
+	if !checkPhotoLimit(c, 0, h.maxPhotos) {
+		return
+	}
+	documentation, staged, ok := stagePhotos(c, h.uploads, subDir, prefix, h.logger)
+	if !ok {
 		return
 	}
 
+	// Convert StockType to sqlc StockType
+	stockType := sqlcdb.StockType(req.StockType)
+
 	// Convert notes to pgtype.Text
 	var notesText pgtype.Text
 	if req.Notes != nil {
@@ -609,12 +919,23 @@ func (h *SparepartStockHandler) Create(c *gin.Context) {
 		SparepartID:   int32(req.SparepartID),
 		StockType:     stockType,
 		Quantity:      int32(req.Quantity),
-		Documentation: documentationToBytes(documentation),
+		Documentation: utils.PhotosJSON(documentation),
 		Notes:         notesText,
+		UnitCost:      utils.OptionalNumeric(req.UnitCost),
 	}
 
-	item, err := h.queries.CreateSparepartStock(ctx, createParams)
+	var item sqlcdb.SparepartStockItem
+	err = h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		var err error
+		item, err = repo.CreateSparepartStock(ctx, createParams)
+		if err != nil {
+			return err
+		}
+		// Move photos into place before commit, a failed move rolls back the insert
+		return h.uploads.CommitStaged(ctx, staged, h.logger)
+	})
 	if err != nil {
+		h.uploads.DiscardStaged(staged, h.logger)
 		utils.HandleError(c, err, "Failed to create sparepart stock item", h.logger)
 		return
 	}
@@ -634,15 +955,162 @@ func (h *SparepartStockHandler) Create(c *gin.Context) {
 	})
 }
 
+// stockUnit is the unit a quantity of a sparepart was given in, and the request field holding it
+type stockUnit struct {
+	field       string
+	sparepartID int32
+	unit        string
+}
+
+// validateUnits reports the units that are not the unit their sparepart is counted in. A
+// sparepart that does not exist is left to the insert to report.
+func (h *SparepartStockHandler) validateUnits(ctx context.Context, units []stockUnit) ([]utils.FieldError, error) {
+	var errs []utils.FieldError
+	counted := make(map[int32]string)
+	for _, u := range units {
+		unit, ok := counted[u.sparepartID]
+		if !ok {
+			master, err := h.queries.GetSparepartMaster(ctx, u.sparepartID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			unit = master.Unit
+			counted[u.sparepartID] = unit
+		}
+		if u.unit != unit {
+			errs = append(errs, utils.UnitMismatchError(u.field, unit))
+		}
+	}
+	return errs, nil
+}
+
+// CreateSparepartStockBatchRequest is the JSON body for creating many stock items at once
+type CreateSparepartStockBatchRequest struct {
+	Items []CreateSparepartStockRequest `json:"items" binding:"required,min=1,max=1000,dive"`
+}
+
+// SparepartStockBatchItem is a created stock item as returned by the batch create endpoint
+type SparepartStockBatchItem struct {
+	ID          int32    `json:"id"`
+	LocationID  int32    `json:"location_id"`
+	SparepartID int32    `json:"sparepart_id"`
+	StockType   string   `json:"stock_type"`
+	Quantity    int32    `json:"quantity"`
+	Notes       *string  `json:"notes,omitempty"`
+	UnitCost    *float64 `json:"unit_cost,omitempty"`
+}
+
+// @Summary Create sparepart stock items in batch
+// @Description Create many sparepart stock items in a single insert (all or nothing). Photos are added afterwards per item.
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param items body CreateSparepartStockBatchRequest true "Stock items"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/stock/batch [post]
+func (h *SparepartStockHandler) CreateBatch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateSparepartStockBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	params := sqlcdb.CreateSparepartStocksBatchParams{
+		LocationIds:  make([]int32, 0, len(req.Items)),
+		SparepartIds: make([]int32, 0, len(req.Items)),
+		StockTypes:   make([]sqlcdb.StockType, 0, len(req.Items)),
+		Quantities:   make([]int32, 0, len(req.Items)),
+		Notes:        make([]string, 0, len(req.Items)),
+		UnitCosts:    make([]pgtype.Numeric, 0, len(req.Items)),
+	}
+	var fieldErrors []utils.FieldError
+	var units []stockUnit
+	for i, item := range req.Items {
+		if !item.StockType.Valid() {
+			utils.BadRequest(c, fmt.Sprintf("Invalid stock_type at item %d. Must be NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR or RESERVED", i))
+			return
+		}
+		if item.Quantity < 0 {
+			fieldErrors = append(fieldErrors, utils.NegativeQuantityError(fmt.Sprintf("items[%d].quantity", i)))
+		}
+		if item.Unit != nil {
+			units = append(units, stockUnit{field: fmt.Sprintf("items[%d].unit", i), sparepartID: int32(item.SparepartID), unit: *item.Unit})
+		}
+		notes := ""
+		if item.Notes != nil {
+			notes = *item.Notes
+		}
+		params.LocationIds = append(params.LocationIds, int32(item.LocationID))
+		params.SparepartIds = append(params.SparepartIds, int32(item.SparepartID))
+		params.StockTypes = append(params.StockTypes, sqlcdb.StockType(item.StockType))
+		params.Quantities = append(params.Quantities, int32(item.Quantity))
+		params.Notes = append(params.Notes, notes)
+		params.UnitCosts = append(params.UnitCosts, utils.OptionalNumeric(item.UnitCost))
+	}
+	unitErrors, err := h.validateUnits(ctx, units)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get spareparts", h.logger)
+		return
+	}
+	fieldErrors = append(fieldErrors, unitErrors...)
+
+	if len(fieldErrors) > 0 {
+		utils.ValidationError(c, fieldErrors...)
+		return
+	}
+
+	items, err := h.queries.CreateSparepartStocksBatch(ctx, params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create sparepart stock items", h.logger)
+		return
+	}
+
+	created := make([]SparepartStockBatchItem, 0, len(items))
+	for _, item := range items {
+		created = append(created, toSparepartStockBatchItem(item))
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: fmt.Sprintf("%d sparepart stock items created successfully", len(created)),
+		Data:    created,
+	})
+}
+
+func toSparepartStockBatchItem(item sqlcdb.SparepartStockItem) SparepartStockBatchItem {
+	var notes *string
+	if item.Notes.Valid {
+		notes = &item.Notes.String
+	}
+	return SparepartStockBatchItem{
+		ID:          item.ID,
+		LocationID:  item.LocationID,
+		SparepartID: item.SparepartID,
+		StockType:   string(item.StockType),
+		Quantity:    item.Quantity,
+		Notes:       notes,
+		UnitCost:    utils.NumericValue(item.UnitCost),
+	}
+}
+
 // @Summary Update sparepart stock item
-// @Description Update an existing sparepart stock item
+// @Description Update an existing sparepart stock item. The update must name the item version it is based on (If-Match header or version field) and fails with 409 and the current version when the item was changed since.
 // @Tags Sparepart Stock
 // @Accept json
 // @Produce json
 // @Param id path int true "Sparepart Stock Item ID"
-// @Param item body UpdateSparepartStockRequest true "Update data"
+// @Param If-Match header string false "Item version the update is based on, e.g. \"3\""
+// @Param item body UpdateSparepartStockRequest true "Fields to update (omitted fields are unchanged)"
 // @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict}
+// @Failure 428 {object} utils.Response
 // @Router /sparepart/stock/{id} [put]
+// @Router /sparepart/stock/{id} [patch]
 func (h *SparepartStockHandler) Update(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -653,7 +1121,7 @@ func (h *SparepartStockHandler) Update(c *gin.Context) {
 	}
 
 	// Check if item exists
-	_, err = h.queries.GetSparepartStock(ctx, int32(id))
+	current, err := h.queries.GetSparepartStock(ctx, int32(id))
 	if err != nil {
 		utils.NotFound(c, "Sparepart stock item not found")
 		return
@@ -661,28 +1129,44 @@ func (h *SparepartStockHandler) Update(c *gin.Context) {
 
 	var req UpdateSparepartStockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+		utils.BindingError(c, err)
 		return
 	}
-
-	// Convert notes to pgtype.Text
-	var notes pgtype.Text
-	if req.Notes != nil {
-		notes.String = *req.Notes
-		notes.Valid = true
+	if req.Quantity == nil && req.Notes == nil && req.UnitCost == nil {
+		utils.BadRequest(c, "No fields to update")
+		return
+	}
+	if req.Quantity != nil && *req.Quantity < 0 {
+		utils.ValidationError(c, utils.NegativeQuantityError("quantity"))
+		return
+	}
+	if req.Unit != nil && *req.Unit != current.Unit {
+		utils.ValidationError(c, utils.UnitMismatchError("unit", current.Unit))
+		return
+	}
+	version, ok := utils.ExpectedVersion(c, req.Version)
+	if !ok {
+		return
 	}
 
 	updateParams := sqlcdb.UpdateSparepartStockParams{
 		ID:       int32(id),
-		Quantity: int32(req.Quantity),
-		Notes:    notes,
+		Quantity: utils.OptionalInt(req.Quantity),
+		Notes:    utils.OptionalText(req.Notes),
+		UnitCost: utils.OptionalNumeric(req.UnitCost),
+		Version:  version,
 	}
 
 	item, err := h.queries.UpdateSparepartStock(ctx, updateParams)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.respondVersionConflict(c, int32(id))
+			return
+		}
 		utils.HandleError(c, err, "Failed to update sparepart stock item", h.logger)
 		return
 	}
+	c.Header("ETag", utils.ETag(item.Version))
 
 	// Get full item with relations
 	// Get grouped response for this location
@@ -695,6 +1179,17 @@ func (h *SparepartStockHandler) Update(c *gin.Context) {
 	utils.Success(c, "Sparepart stock item updated successfully", groupedResponse)
 }
 
+// respondVersionConflict answers an update that matched no row: the item changed version
+// since the client read it, or was deleted meanwhile
+func (h *SparepartStockHandler) respondVersionConflict(c *gin.Context, id int32) {
+	current, err := h.queries.GetSparepartStock(c.Request.Context(), id)
+	if err != nil {
+		utils.NotFound(c, "Sparepart stock item not found")
+		return
+	}
+	utils.VersionConflictError(c, current.Version)
+}
+
 // @Summary Add photos to sparepart stock item
 // @Description Add photos to an existing sparepart stock item
 // @Tags Sparepart Stock
@@ -702,7 +1197,12 @@ func (h *SparepartStockHandler) Update(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Sparepart Stock Item ID"
 // @Param photos formData file true "Photo files (multiple allowed)"
+// @Param captions formData []string false "Caption of each photo, in the order of the photos"
+// @Param taken_at formData []string false "When each photo was taken (RFC3339), in the order of the photos"
 // @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response "Invalid request, or more photos than an item can have"
+// @Failure 507 {object} utils.Response "Upload quota exceeded"
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict} "The item changed while its photos were being changed"
 // @Router /sparepart/stock/{id}/photos [post]
 func (h *SparepartStockHandler) AddPhotos(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -720,53 +1220,40 @@ func (h *SparepartStockHandler) AddPhotos(c *gin.Context) {
 		return
 	}
 
-	// Process file uploads
-	form, err := c.MultipartForm()
-	if err != nil {
-		utils.BadRequest(c, "Failed to parse multipart form")
+	if !checkPhotoLimit(c, len(utils.ParsePhotos(item.Documentation)), h.maxPhotos) {
 		return
 	}
 
-	files := form.File["photos"]
-	if len(files) == 0 {
-		utils.BadRequest(c, "No photos provided")
+	// Process file uploads
+	subDir := utils.GetSubDirForSparepartStock(string(item.StockType))
+	prefix := utils.GetPrefixForSparepartStock(string(item.StockType))
+	photos, ok := uploadPhotos(c, h.uploads, subDir, prefix, h.logger)
+	if !ok {
 		return
 	}
 
-	// Get existing documentation
-	existingDocs := documentationFromBytes(item.Documentation)
-
 	// Append new photos to existing documentation
-	subDir := utils.GetSubDirForSparepartStock(string(item.StockType))
-	prefix := utils.GetPrefixForSparepartStock(string(item.StockType))
-	for _, file := range files {
-		path, err := utils.ProcessImageUpload(file, subDir, prefix, h.logger)
-		if err != nil {
-			utils.BadRequest(c, "Failed to upload photo: "+err.Error())
-			return
-		}
-		existingDocs = append(existingDocs, path)
-	}
+	existingDocs := append(utils.ParsePhotos(item.Documentation), photos...)
 
 	// Update documentation
 	updateParams := sqlcdb.UpdateSparepartStockDocumentationParams{
 		ID:            int32(id),
-		Documentation: documentationToBytes(existingDocs),
+		Documentation: utils.PhotosJSON(existingDocs),
+		Version:       item.Version,
 	}
 
 	_, err = h.queries.UpdateSparepartStockDocumentation(ctx, updateParams)
 	if err != nil {
+		// Nothing refers to the new photos
+		releasePhotos(ctx, h.uploads, photos, h.logger)
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.respondVersionConflict(c, int32(id))
+			return
+		}
 		utils.HandleError(c, err, "Failed to update photos", h.logger)
 		return
 	}
 
-	// Get the item to find its location_id (item already declared above, use = instead of :=)
-	item, err = h.queries.GetSparepartStock(ctx, int32(id))
-	if err != nil {
-		utils.HandleError(c, err, "Failed to retrieve item", h.logger)
-		return
-	}
-
 	// Get grouped response for this location
 	groupedResponse, err := h.getGroupedSparepartStockByLocationID(ctx, item.LocationID)
 	if err != nil {
@@ -785,6 +1272,7 @@ func (h *SparepartStockHandler) AddPhotos(c *gin.Context) {
 // @Param id path int true "Sparepart Stock Item ID"
 // @Param photo_index path int true "Photo index in documentation array"
 // @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict} "The item changed while its photos were being changed"
 // @Router /sparepart/stock/{id}/photos/{photo_index} [delete]
 func (h *SparepartStockHandler) DeletePhoto(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -795,8 +1283,8 @@ func (h *SparepartStockHandler) DeletePhoto(c *gin.Context) {
 		return
 	}
 
-	photoIndex, err := strconv.Atoi(c.Param("photo_index"))
-	if err != nil {
+	photoIndex, ok := utils.ParseIndexParam(c, "photo_index")
+	if !ok {
 		utils.BadRequest(c, "Invalid photo index")
 		return
 	}
@@ -809,17 +1297,13 @@ func (h *SparepartStockHandler) DeletePhoto(c *gin.Context) {
 	}
 
 	// Get existing documentation
-	docs := documentationFromBytes(item.Documentation)
+	docs := utils.ParsePhotos(item.Documentation)
 	if photoIndex < 0 || photoIndex >= len(docs) {
 		utils.BadRequest(c, "Photo index out of range")
 		return
 	}
 
-	// Delete file from storage
-	filePath := docs[photoIndex]
-	if err := utils.DeleteFile(filePath, h.logger); err != nil {
-		h.logger.Warn("Failed to delete file", zap.Error(err), zap.String("path", filePath))
-	}
+	filePath := docs[photoIndex].URL
 
 	// Remove from array
 	docs = append(docs[:photoIndex], docs[photoIndex+1:]...)
@@ -827,20 +1311,23 @@ func (h *SparepartStockHandler) DeletePhoto(c *gin.Context) {
 	// Update documentation
 	updateParams := sqlcdb.UpdateSparepartStockDocumentationParams{
 		ID:            int32(id),
-		Documentation: documentationToBytes(docs),
+		Documentation: utils.PhotosJSON(docs),
+		Version:       item.Version,
 	}
 
 	_, err = h.queries.UpdateSparepartStockDocumentation(ctx, updateParams)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.respondVersionConflict(c, int32(id))
+		return
+	}
 	if err != nil {
 		utils.HandleError(c, err, "Failed to delete photo", h.logger)
 		return
 	}
 
-	// Get the item to find its location_id (item already declared above, use = instead of :=)
-	item, err = h.queries.GetSparepartStock(ctx, int32(id))
-	if err != nil {
-		utils.HandleError(c, err, "Failed to retrieve item", h.logger)
-		return
+	// Delete file from storage once the item no longer refers to it
+	if err := h.uploads.DeleteFile(ctx, filePath, h.logger); err != nil {
+		utils.RequestLogger(ctx, h.logger).Warn("Failed to delete file", zap.Error(err), zap.String("path", filePath))
 	}
 
 	// Get grouped response for this location
@@ -853,15 +1340,19 @@ func (h *SparepartStockHandler) DeletePhoto(c *gin.Context) {
 	utils.Success(c, "Photo deleted successfully", groupedResponse)
 }
 
-// @Summary Delete sparepart stock item
-// @Description Delete a sparepart stock item
+// @Summary Update photo metadata of sparepart stock item
+// @Description Change the caption and taken-at timestamp of a photo by index, keeping the photo file
 // @Tags Sparepart Stock
 // @Accept json
 // @Produce json
 // @Param id path int true "Sparepart Stock Item ID"
+// @Param photo_index path int true "Photo index in documentation array"
+// @Param metadata body PhotoMetadataRequest true "Fields to update (omitted fields are unchanged)"
 // @Success 200 {object} utils.Response
-// @Router /sparepart/stock/{id} [delete]
-func (h *SparepartStockHandler) Delete(c *gin.Context) {
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict} "The item changed while its photos were being changed"
+// @Router /sparepart/stock/{id}/photos/{photo_index} [patch]
+func (h *SparepartStockHandler) UpdatePhotoMetadata(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
@@ -870,22 +1361,75 @@ func (h *SparepartStockHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// Get item to delete photos
+	photoIndex, ok := utils.ParseIndexParam(c, "photo_index")
+	if !ok {
+		utils.BadRequest(c, "Invalid photo index")
+		return
+	}
+
 	item, err := h.queries.GetSparepartStock(ctx, int32(id))
 	if err != nil {
 		utils.NotFound(c, "Sparepart stock item not found")
 		return
 	}
 
-	// Delete all photos from storage
-	docs := documentationFromBytes(item.Documentation)
-	for _, path := range docs {
-		if err := utils.DeleteFile(path, h.logger); err != nil {
-			h.logger.Warn("Failed to delete file", zap.Error(err), zap.String("path", path))
-		}
+	docs := utils.ParsePhotos(item.Documentation)
+	if photoIndex >= len(docs) {
+		utils.BadRequest(c, "Photo index out of range")
+		return
+	}
+	if !bindPhotoMetadata(c, &docs[photoIndex]) {
+		return
+	}
+
+	_, err = h.queries.UpdateSparepartStockDocumentation(ctx, sqlcdb.UpdateSparepartStockDocumentationParams{
+		ID:            int32(id),
+		Documentation: utils.PhotosJSON(docs),
+		Version:       item.Version,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.respondVersionConflict(c, int32(id))
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update photo", h.logger)
+		return
 	}
 
-	// Delete item
+	groupedResponse, err := h.getGroupedSparepartStockByLocationID(ctx, item.LocationID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to retrieve grouped stock items", h.logger)
+		return
+	}
+
+	utils.Success(c, "Photo updated successfully", groupedResponse)
+}
+
+// @Summary Delete sparepart stock item
+// @Description Soft delete a sparepart stock item; it and its photos are kept until restored or purged
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/{id} [delete]
+func (h *SparepartStockHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+
+	// Check if item exists
+	_, err = h.queries.GetSparepartStock(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Sparepart stock item not found")
+		return
+	}
+
+	// Photos stay in storage so the item can be restored; the admin purge removes them
 	err = h.queries.DeleteSparepartStock(ctx, int32(id))
 	if err != nil {
 		utils.HandleError(c, err, "Failed to delete sparepart stock item", h.logger)
@@ -895,6 +1439,131 @@ func (h *SparepartStockHandler) Delete(c *gin.Context) {
 	utils.Success(c, "Sparepart stock item deleted successfully", nil)
 }
 
+// @Summary Restore sparepart stock item
+// @Description Restore a soft deleted sparepart stock item; items of a deleted location are restored with the location
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response "A stock item with the same location, sparepart and stock type was created meanwhile"
+// @Router /sparepart/stock/{id}/restore [post]
+func (h *SparepartStockHandler) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+
+	item, err := h.queries.RestoreSparepartStock(ctx, int32(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.NotFound(c, "Deleted sparepart stock item not found")
+			return
+		}
+		utils.HandleError(c, err, "Failed to restore sparepart stock item", h.logger)
+		return
+	}
+
+	groupedResponse, err := h.getGroupedSparepartStockByLocationID(ctx, item.LocationID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to retrieve grouped stock items", h.logger)
+		return
+	}
+
+	utils.Success(c, "Sparepart stock item restored successfully", groupedResponse)
+}
+
+// ChangeStockConditionRequest moves a quantity of a stock item to another stock type at the same
+// location, e.g. from DAMAGED to IN_REPAIR
+type ChangeStockConditionRequest struct {
+	StockType models.StockType `json:"stock_type" binding:"required,oneof=NEW_STOCK USED_STOCK DAMAGED IN_REPAIR RESERVED"`
+	Quantity  int              `json:"quantity" binding:"required,min=1"`
+}
+
+// errStockConditionInvalid ends a condition change transaction early; the response is written after the rollback
+var errStockConditionInvalid = errors.New("stock condition change is invalid")
+
+// @Summary Change stock condition
+// @Description Move a quantity of a stock item to the item of another stock type at the same location, created when missing, e.g. DAMAGED stock sent IN_REPAIR or NEW_STOCK RESERVED for a site visit. Only NEW_STOCK and USED_STOCK count as available stock.
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Param request body ChangeStockConditionRequest true "Target stock type and quantity"
+// @Success 200 {object} utils.Response{data=SparepartStockGroupedResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /sparepart/stock/{id}/condition [post]
+func (h *SparepartStockHandler) ChangeCondition(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+	var req ChangeStockConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	var errs []utils.FieldError
+	var locationID int32
+	err = h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		item, err := repo.GetSparepartStockForUpdate(ctx, int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errStockItemNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if item.StockType == sqlcdb.StockType(req.StockType) {
+			errs = append(errs, utils.FieldError{Field: "stock_type", Message: "must differ from the stock item's stock type"})
+		}
+		if req.Quantity > int(item.Quantity) {
+			errs = append(errs, utils.FieldError{Field: "quantity", Message: fmt.Sprintf("exceeds the stock quantity of %d", item.Quantity)})
+		}
+		if len(errs) > 0 {
+			return errStockConditionInvalid
+		}
+
+		if _, err := repo.TransferOutSparepartStock(ctx, sqlcdb.TransferOutSparepartStockParams{ID: item.ID, Quantity: int32(req.Quantity)}); err != nil {
+			return err
+		}
+		_, err = repo.TransferInSparepartStock(ctx, sqlcdb.TransferInSparepartStockParams{
+			LocationID:  item.LocationID,
+			SparepartID: item.SparepartID,
+			StockType:   sqlcdb.StockType(req.StockType),
+			Quantity:    int32(req.Quantity),
+		})
+		locationID = item.LocationID
+		return err
+	})
+	switch {
+	case errors.Is(err, errStockItemNotFound):
+		utils.NotFound(c, "Sparepart stock item not found")
+		return
+	case errors.Is(err, errStockConditionInvalid):
+		utils.ValidationError(c, errs...)
+		return
+	case err != nil:
+		utils.HandleError(c, err, "Failed to change stock condition", h.logger)
+		return
+	}
+
+	groupedResponse, err := h.getGroupedSparepartStockByLocationID(ctx, locationID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to retrieve grouped stock items", h.logger)
+		return
+	}
+
+	utils.Success(c, "Stock condition changed successfully", groupedResponse)
+}
+
 // @Summary Export sparepart stock to PDF
 // @Description Export sparepart stock items to PDF with filters (landscape mode)
 // @Tags Sparepart Stock
@@ -905,6 +1574,8 @@ func (h *SparepartStockHandler) Delete(c *gin.Context) {
 // @Param regency query string false "Filter by regency"
 // @Param cluster query string false "Filter by cluster"
 // @Param stock_type query string false "Filter by stock type"
+// @Param include_photos query bool false "Append the thumbnails of every item's photos"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
 // @Success 200 {file} application/pdf
 // @Router /sparepart/stock/export/pdf [get]
 func (h *SparepartStockHandler) ExportPDF(c *gin.Context) {
@@ -913,31 +1584,29 @@ func (h *SparepartStockHandler) ExportPDF(c *gin.Context) {
 	// Get filter parameters
 	filterParams := h.buildSparepartStockParams(c)
 
-	// List items for export (no pagination)
+	// Items are read in keyset batches while the file is written
 	exportParams := sqlcdb.ListSparepartStocksForExportParams{
-		Column1: filterParams.Column1,
-		Column2: filterParams.Column2,
-		Column3: filterParams.Column3,
-		Column4: filterParams.Column4,
-		Column5: filterParams.Column5,
+		Region:    filterParams.Region,
+		Regency:   filterParams.Regency,
+		Cluster:   filterParams.Cluster,
+		StockType: filterParams.StockType,
+		Names:     filterParams.Names,
 	}
 
-	items, err := h.queries.ListSparepartStocksForExport(ctx, exportParams)
-	if err != nil {
-		utils.HandleError(c, err, "Failed to get sparepart stock items", h.logger)
-		return
+	var rows int
+	var photos utils.ThumbnailLoader
+	if c.Query("include_photos") == "true" {
+		photos = h.uploads
 	}
-
-	buf, err := utils.ExportSparepartStockToPDF(items, h.logger)
+	buf, err := utils.ExportSparepartStockToPDF(ctx, utils.CountRows(h.exportReader(ctx, exportParams), &rows), photos, h.logger)
 	if err != nil {
 		utils.HandleError(c, err, "Failed to generate PDF", h.logger)
 		return
 	}
 
-	filename := fmt.Sprintf("sparepart_stock_%s.pdf", time.Now().Format("20060102_150405"))
-	c.Header("Content-Disposition", "attachment; filename="+filename)
-	c.Header("Content-Type", "application/pdf")
-	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+	c.Set(utils.ExportRowsKey, rows)
+	filename := fmt.Sprintf("sparepart_stock_%s.pdf", time.Now().UTC().Format("20060102_150405"))
+	sendExport(c, h.reports, buf.Bytes(), filename, "application/pdf", h.logger)
 }
 
 // @Summary Export sparepart stock to Excel
@@ -950,6 +1619,7 @@ func (h *SparepartStockHandler) ExportPDF(c *gin.Context) {
 // @Param regency query string false "Filter by regency"
 // @Param cluster query string false "Filter by cluster"
 // @Param stock_type query string false "Filter by stock type"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
 // @Success 200 {file} application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Router /sparepart/stock/export/excel [get]
 func (h *SparepartStockHandler) ExportExcel(c *gin.Context) {
@@ -958,31 +1628,330 @@ func (h *SparepartStockHandler) ExportExcel(c *gin.Context) {
 	// Get filter parameters
 	filterParams := h.buildSparepartStockParams(c)
 
-	// List items for export (no pagination)
+	// Items are read in keyset batches while the file is written
 	exportParams := sqlcdb.ListSparepartStocksForExportParams{
-		Column1: filterParams.Column1,
-		Column2: filterParams.Column2,
-		Column3: filterParams.Column3,
-		Column4: filterParams.Column4,
-		Column5: filterParams.Column5,
+		Region:    filterParams.Region,
+		Regency:   filterParams.Regency,
+		Cluster:   filterParams.Cluster,
+		StockType: filterParams.StockType,
+		Names:     filterParams.Names,
 	}
 
-	items, err := h.queries.ListSparepartStocksForExport(ctx, exportParams)
+	var rows int
+	buf, err := utils.ExportSparepartStockToExcel(utils.CountRows(h.exportReader(ctx, exportParams), &rows), h.logger)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to generate Excel", h.logger)
+		return
+	}
+
+	c.Set(utils.ExportRowsKey, rows)
+	filename := fmt.Sprintf("sparepart_stock_%s.xlsx", time.Now().UTC().Format("20060102_150405"))
+	sendExport(c, h.reports, buf.Bytes(), filename, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", h.logger)
+}
+
+// @Summary Export sparepart stock to CSV
+// @Description Export sparepart stock items to CSV with filters; the file is streamed while it is written
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce text/csv
+// @Param sparepart_name query string false "Filter by sparepart name (comma-separated)"
+// @Param region query string false "Filter by region"
+// @Param regency query string false "Filter by regency"
+// @Param cluster query string false "Filter by cluster"
+// @Param stock_type query string false "Filter by stock type"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
+// @Success 200 {file} text/csv
+// @Router /sparepart/stock/export/csv [get]
+func (h *SparepartStockHandler) ExportCSV(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get filter parameters
+	filterParams := h.buildSparepartStockParams(c)
+
+	// Items are read in keyset batches while the file is written
+	exportParams := sqlcdb.ListSparepartStocksForExportParams{
+		Region:    filterParams.Region,
+		Regency:   filterParams.Regency,
+		Cluster:   filterParams.Cluster,
+		StockType: filterParams.StockType,
+		Names:     filterParams.Names,
+	}
+
+	var rows int
+	filename := fmt.Sprintf("sparepart_stock_%s.csv", time.Now().UTC().Format("20060102_150405"))
+	streamExport(c, h.reports, filename, "text/csv; charset=utf-8", func(w io.Writer) error {
+		err := utils.WriteSparepartStockCSV(w, utils.CountRows(h.exportReader(ctx, exportParams), &rows))
+		c.Set(utils.ExportRowsKey, rows)
+		return err
+	}, h.logger)
+}
+
+// @Summary Print QR label sheet for sparepart stock items
+// @Description Render an A4 PDF sheet of QR labels (item name, location, code) for selected stock items or a whole location
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce application/pdf
+// @Param ids query string false "Stock item IDs (comma-separated)"
+// @Param location_id query int false "Print labels for every stock item at this location"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
+// @Success 200 {file} application/pdf
+// @Router /sparepart/stock/labels/pdf [get]
+func (h *SparepartStockHandler) ExportLabelsPDF(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Parse selected stock item IDs
+	var ids []int32
+	if idsStr := c.Query("ids"); idsStr != "" {
+		for _, part := range strings.Split(idsStr, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(part, 10, 32)
+			if err != nil || id <= 0 {
+				utils.BadRequest(c, "Invalid ids. Must be comma-separated stock item IDs")
+				return
+			}
+			ids = append(ids, int32(id))
+		}
+	}
+
+	// Parse location_id
+	var locationID int32
+	if locationIDStr := c.Query("location_id"); locationIDStr != "" {
+		id, err := strconv.ParseInt(locationIDStr, 10, 32)
+		if err != nil || id <= 0 {
+			utils.BadRequest(c, "Invalid location_id")
+			return
+		}
+		locationID = int32(id)
+	}
+
+	if len(ids) == 0 && locationID == 0 {
+		utils.BadRequest(c, "ids or location_id is required")
+		return
+	}
+
+	labelParams := sqlcdb.ListSparepartStocksForLabelsParams{
+		Ids:        ids,
+		LocationID: utils.IntFilter(locationID),
+	}
+
+	items, err := h.queries.ListSparepartStocksForLabels(ctx, labelParams)
 	if err != nil {
 		utils.HandleError(c, err, "Failed to get sparepart stock items", h.logger)
 		return
 	}
+	if len(items) == 0 {
+		utils.NotFound(c, "No sparepart stock items found for labels")
+		return
+	}
 
-	buf, err := utils.ExportSparepartStockToExcel(items, h.logger)
+	buf, err := utils.ExportStockLabelsToPDF(items, h.logger)
 	if err != nil {
-		utils.HandleError(c, err, "Failed to generate Excel", h.logger)
+		utils.HandleError(c, err, "Failed to generate label sheet", h.logger)
+		return
+	}
+
+	c.Set(utils.ExportRowsKey, len(items))
+	filename := fmt.Sprintf("sparepart_stock_labels_%s.pdf", time.Now().UTC().Format("20060102_150405"))
+	sendExport(c, h.reports, buf.Bytes(), filename, "application/pdf", h.logger)
+}
+
+// Bounds of the QR code image size, in pixels
+const (
+	defaultQRCodeSize = 256
+	minQRCodeSize     = 64
+	maxQRCodeSize     = 1024
+)
+
+// @Summary Get QR code of sparepart stock item
+// @Description Render a PNG QR code encoding the stock item's code (as printed on labels), for labeling shelves. Scanned codes resolve through GET /sparepart/scan.
+// @Tags Sparepart Stock
+// @Produce image/png
+// @Param id path int true "Sparepart Stock Item ID"
+// @Param size query int false "Image width and height in pixels (64-1024)" default(256)
+// @Success 200 {file} image/png
+// @Router /sparepart/stock/{id}/qrcode [get]
+func (h *SparepartStockHandler) QRCode(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
 		return
 	}
+	size := defaultQRCodeSize
+	if value := c.Query("size"); value != "" {
+		size, err = strconv.Atoi(value)
+		if err != nil || size < minQRCodeSize || size > maxQRCodeSize {
+			utils.ValidationError(c, utils.FieldError{Field: "size", Message: fmt.Sprintf("must be between %d and %d", minQRCodeSize, maxQRCodeSize)})
+			return
+		}
+	}
+
+	if _, err := h.queries.GetSparepartStock(ctx, int32(id)); err != nil {
+		utils.NotFound(c, "Sparepart stock item not found")
+		return
+	}
+
+	png, err := utils.StockItemQRCode(int32(id), size)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to generate QR code", h.logger)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s.png", utils.StockItemCode(int32(id))))
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// defaultNearestLimit and maxNearestLimit bound the number of locations of a nearest stock lookup
+const (
+	defaultNearestLimit = 10
+	maxNearestLimit     = 100
+)
+
+// NearestStockResponse is a location holding the item, with its distance from the requested point
+type NearestStockResponse struct {
+	Location   NearestStockLocation `json:"location"`
+	DistanceKm float64              `json:"distance_km"`
+	// Quantities held of the item; tools alker only count what is not checked out
+	NewStockQuantity   int64 `json:"new_stock_quantity"`
+	UsedStockQuantity  int64 `json:"used_stock_quantity"`
+	ToolsAlkerQuantity int64 `json:"tools_alker_quantity"`
+}
+
+type NearestStockLocation struct {
+	ID        int32   `json:"id"`
+	Region    string  `json:"region"`
+	Regency   string  `json:"regency"`
+	Cluster   string  `json:"cluster"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
 
-	filename := fmt.Sprintf("sparepart_stock_%s.xlsx", time.Now().Format("20060102_150405"))
-	c.Header("Content-Disposition", "attachment; filename="+filename)
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+// parseNearestStockParams reads and validates the query params of a nearest stock lookup
+func parseNearestStockParams(c *gin.Context) (sqlcdb.ListNearestStockParams, []utils.FieldError) {
+	var errs []utils.FieldError
+	params := sqlcdb.ListNearestStockParams{Limit: defaultNearestLimit}
+
+	sparepartID, err := strconv.ParseInt(c.Query("sparepart_id"), 10, 32)
+	if err != nil || sparepartID < 1 {
+		errs = append(errs, utils.FieldError{Field: "sparepart_id", Message: "must be a positive integer"})
+	}
+	params.SparepartID = int32(sparepartID)
+
+	lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+	if latErr != nil || lat < -90 || lat > 90 {
+		errs = append(errs, utils.FieldError{Field: "lat", Message: "must be a number between -90 and 90"})
+	}
+	if lngErr != nil || lng < -180 || lng > 180 {
+		errs = append(errs, utils.FieldError{Field: "lng", Message: "must be a number between -180 and 180"})
+	}
+	params.Lat, params.Lng = lat, lng
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			errs = append(errs, utils.FieldError{Field: "limit", Message: "must be a positive integer"})
+		} else {
+			params.Limit = int32(min(limit, maxNearestLimit))
+		}
+	}
+	return params, errs
+}
+
+// @Summary Find nearest stock
+// @Description Get the locations holding a sparepart (stock items) or tool (tools alker items not checked out), nearest to the given point first. Distance is the great-circle distance in km; locations without coordinates are left out.
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param sparepart_id query int true "Sparepart or tool ID from the master list"
+// @Param lat query number true "Latitude of the point to search from (-90 to 90)"
+// @Param lng query number true "Longitude of the point to search from (-180 to 180)"
+// @Param limit query int false "Number of locations (max 100)" default(10)
+// @Success 200 {object} utils.Response{data=[]NearestStockResponse}
+// @Router /sparepart/stock/nearest [get]
+func (h *SparepartStockHandler) Nearest(c *gin.Context) {
+	params, errs := parseNearestStockParams(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	rows, err := h.queries.ListNearestStock(c.Request.Context(), params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to find nearest stock", h.logger)
+		return
+	}
+
+	response := make([]NearestStockResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, NearestStockResponse{
+			Location: NearestStockLocation{
+				ID:        row.LocationID,
+				Region:    string(row.Region),
+				Regency:   row.Regency,
+				Cluster:   row.Cluster,
+				Latitude:  row.Latitude,
+				Longitude: row.Longitude,
+			},
+			DistanceKm:         row.DistanceKm,
+			NewStockQuantity:   row.NewStockQuantity,
+			UsedStockQuantity:  row.UsedStockQuantity,
+			ToolsAlkerQuantity: row.ToolsAlkerQuantity,
+		})
+	}
+
+	utils.Success(c, "Nearest stock retrieved successfully", response)
+}
+
+// @Summary Resolve scanned code
+// @Description Resolve a scanned stock item code (from a QR code or label) or a stock unit serial number / asset tag to the stock items of its location, grouped by location
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param code query string true "Scanned code, e.g. STK-000012, or a serial number or asset tag"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/scan [get]
+func (h *SparepartStockHandler) Scan(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	code := strings.TrimSpace(c.Query("code"))
+	if code == "" {
+		utils.ValidationError(c, utils.FieldError{Field: "code", Message: "is required"})
+		return
+	}
+
+	var locationID int32
+	if id, ok := utils.ParseStockItemCode(code); ok {
+		item, err := h.queries.GetSparepartStock(ctx, id)
+		if err != nil {
+			utils.NotFound(c, "No sparepart stock item has this code")
+			return
+		}
+		locationID = item.LocationID
+	} else {
+		unit, err := h.queries.ScanStockUnit(ctx, normalizeUnitCode(code))
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.NotFound(c, "No sparepart stock item or stock unit has this code")
+			return
+		}
+		if err != nil {
+			utils.HandleError(c, err, "Failed to scan stock unit", h.logger)
+			return
+		}
+		locationID = unit.LocationID
+	}
+
+	grouped, err := h.getGroupedSparepartStockByLocationID(ctx, locationID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get sparepart stock items", h.logger)
+		return
+	}
+
+	utils.Success(c, "Sparepart stock items retrieved successfully", grouped)
 }
 
 // @Summary Update photo in sparepart stock item
@@ -993,7 +1962,10 @@ func (h *SparepartStockHandler) ExportExcel(c *gin.Context) {
 // @Param id path int true "Sparepart Stock Item ID"
 // @Param photo_index path int true "Photo index in documentation array"
 // @Param photo formData file true "New photo file"
+// @Param caption formData string false "Caption of the new photo; the old photo's caption is kept when omitted"
+// @Param taken_at formData string false "When the new photo was taken (RFC3339)"
 // @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict} "The item changed while its photos were being changed"
 // @Router /sparepart/stock/{id}/photos/{photo_index} [put]
 func (h *SparepartStockHandler) UpdatePhoto(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -1004,8 +1976,8 @@ func (h *SparepartStockHandler) UpdatePhoto(c *gin.Context) {
 		return
 	}
 
-	photoIndex, err := strconv.Atoi(c.Param("photo_index"))
-	if err != nil {
+	photoIndex, ok := utils.ParseIndexParam(c, "photo_index")
+	if !ok {
 		utils.BadRequest(c, "Invalid photo index")
 		return
 	}
@@ -1018,18 +1990,19 @@ func (h *SparepartStockHandler) UpdatePhoto(c *gin.Context) {
 	}
 
 	// Get existing documentation
-	docs := documentationFromBytes(item.Documentation)
+	docs := utils.ParsePhotos(item.Documentation)
 	if photoIndex < 0 || photoIndex >= len(docs) {
 		utils.BadRequest(c, "Photo index out of range")
 		return
 	}
-
-	// Delete old photo file
-	oldFilePath := docs[photoIndex]
-	if err := utils.DeleteFile(oldFilePath, h.logger); err != nil {
-		h.logger.Warn("Failed to delete old file", zap.Error(err), zap.String("path", oldFilePath))
+	metadata, errs := utils.ParseSinglePhotoMetadata(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
 	}
 
+	oldFilePath := docs[photoIndex].URL
+
 	// Get new photo from form
 	file, err := c.FormFile("photo")
 	if err != nil {
@@ -1040,32 +2013,39 @@ func (h *SparepartStockHandler) UpdatePhoto(c *gin.Context) {
 	// Upload new photo
 	subDir := utils.GetSubDirForSparepartStock(string(item.StockType))
 	prefix := utils.GetPrefixForSparepartStock(string(item.StockType))
-	newPath, err := utils.ProcessImageUpload(file, subDir, prefix, h.logger)
+	newPath, err := h.uploads.ProcessImage(ctx, file, subDir, prefix, h.logger)
 	if err != nil {
 		utils.BadRequest(c, "Failed to upload photo: "+err.Error())
 		return
 	}
 
 	// Update documentation array
-	docs[photoIndex] = newPath
+	docs[photoIndex] = replacedPhoto(c, docs[photoIndex], newPath, metadata)
 
 	// Update documentation
 	updateParams := sqlcdb.UpdateSparepartStockDocumentationParams{
 		ID:            int32(id),
-		Documentation: documentationToBytes(docs),
+		Documentation: utils.PhotosJSON(docs),
+		Version:       item.Version,
 	}
 
 	_, err = h.queries.UpdateSparepartStockDocumentation(ctx, updateParams)
 	if err != nil {
+		// Nothing refers to the new file, the old one stays in use
+		if cleanupErr := h.uploads.DeleteFile(ctx, newPath, h.logger); cleanupErr != nil {
+			utils.RequestLogger(ctx, h.logger).Warn("Failed to delete new file", zap.Error(cleanupErr), zap.String("path", newPath))
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.respondVersionConflict(c, int32(id))
+			return
+		}
 		utils.HandleError(c, err, "Failed to update photo", h.logger)
 		return
 	}
 
-	// Get the item to find its location_id (item already declared above, use = instead of :=)
-	item, err = h.queries.GetSparepartStock(ctx, int32(id))
-	if err != nil {
-		utils.HandleError(c, err, "Failed to retrieve item", h.logger)
-		return
+	// Delete old photo file once the item no longer refers to it
+	if err := h.uploads.DeleteFile(ctx, oldFilePath, h.logger); err != nil {
+		utils.RequestLogger(ctx, h.logger).Warn("Failed to delete old file", zap.Error(err), zap.String("path", oldFilePath))
 	}
 
 	// Get grouped response for this location
@@ -0,0 +1,1161 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/storage"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSparepartStockHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	filters := sqlcdb.CountSparepartStocksParams{
+		StockType: pgtype.Text{String: "NEW_STOCK", Valid: true},
+		Names:     []string{"BMS", "EHUB"},
+	}
+	repo.EXPECT().CountSparepartStocks(gomock.Any(), filters).Return(int64(12), nil)
+	repo.EXPECT().
+		ListSparepartStocks(gomock.Any(), sqlcdb.ListSparepartStocksParams{
+			StockType: filters.StockType,
+			Names:     filters.Names,
+			Limit:     5,
+			Offset:    5,
+		}).
+		Return([]sqlcdb.ListSparepartStocksRow{
+			{ID: 10, LocationID: 4, LocationID2: 4, SparepartID2: 1, SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2},
+			{ID: 11, LocationID: 4, LocationID2: 4, SparepartID2: 2, SparepartName: "EHUB", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 1},
+			{ID: 3, LocationID: 9, LocationID2: 9, SparepartID2: 1, SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 5},
+		}, nil)
+	repo.EXPECT().
+		ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.ListLocationCompletenessByIDsParams) ([]sqlcdb.ListLocationCompletenessByIDsRow, error) {
+			if len(arg.LocationIds) != 2 || arg.LocationIds[0] != 4 || arg.LocationIds[1] != 9 {
+				t.Errorf("unexpected location ids: %v", arg.LocationIds)
+			}
+			return []sqlcdb.ListLocationCompletenessByIDsRow{{LocationID: 9, Score: 75, HasContactPerson: true, StockItems: 1}}, nil
+		})
+
+	w := performRequest(http.MethodGet, "/stock", h.GetAll, "/stock?stock_type=NEW_STOCK&sparepart_name=BMS,%20EHUB&page=2&limit=5", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var grouped []SparepartStockGroupedResponse
+	resp := decodeResponse(t, w, &grouped)
+	if resp.Pagination.Total != 12 || resp.Pagination.Page != 2 {
+		t.Fatalf("unexpected pagination: %+v", resp.Pagination)
+	}
+	if len(grouped) != 2 {
+		t.Fatalf("expected 2 locations, got %d", len(grouped))
+	}
+	// Locations keep the order returned by the query
+	if grouped[0].LocationID != 4 || grouped[1].LocationID != 9 {
+		t.Fatalf("unexpected location order: %d, %d", grouped[0].LocationID, grouped[1].LocationID)
+	}
+	if len(grouped[0].Sparepart) != 2 || grouped[0].Sparepart[1].StockID != 11 {
+		t.Fatalf("unexpected items for location 4: %+v", grouped[0].Sparepart)
+	}
+	if grouped[0].Completeness != nil || grouped[1].Completeness == nil || grouped[1].Completeness.Score != 75 {
+		t.Fatalf("unexpected completeness: %+v, %+v", grouped[0].Completeness, grouped[1].Completeness)
+	}
+}
+
+func TestSparepartStockHandlerGetAllFlat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().
+		CountSparepartStockItems(gomock.Any(), sqlcdb.CountSparepartStockItemsParams{Names: []string{"BMS"}}).
+		Return(int64(7), nil)
+	// Limit and offset count items rather than locations
+	repo.EXPECT().
+		ListSparepartStockItems(gomock.Any(), sqlcdb.ListSparepartStockItemsParams{Names: []string{"BMS"}, Limit: 3, Offset: 3}).
+		Return([]sqlcdb.ListSparepartStockItemsRow{
+			{ID: 10, LocationID: 4, LocationID2: 4, SparepartID2: 1, SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2},
+			{ID: 3, LocationID: 9, LocationID2: 9, SparepartID2: 1, SparepartName: "BMS", StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 5},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock", h.GetAll, "/stock?group_by=none&sparepart_name=BMS&page=2&limit=3", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var items []SparepartStockResponse
+	resp := decodeResponse(t, w, &items)
+	if len(items) != 2 || items[0].ID != 10 || items[0].Location.ID != 4 || items[1].StockType != "USED_STOCK" || items[1].Sparepart.Name != "BMS" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if resp.Pagination.Total != 7 || resp.Pagination.TotalPages != 3 {
+		t.Fatalf("unexpected pagination: %+v", resp.Pagination)
+	}
+}
+
+func TestSparepartStockHandlerGetAllRejectsUnknownGroupBy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h := NewSparepartStockHandler(mocks.NewMockSparepartStockRepository(ctrl), nil, nil, 0, testLogger)
+
+	w := performRequest(http.MethodGet, "/stock", h.GetAll, "/stock?group_by=region", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerGetAllIncludesContactPersons(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().CountSparepartStocks(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+	repo.EXPECT().
+		ListSparepartStocks(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListSparepartStocksRow{
+			{ID: 10, LocationID: 4, LocationID2: 4, SparepartID2: 1, SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2},
+			{ID: 3, LocationID: 9, LocationID2: 9, SparepartID2: 1, SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 5},
+		}, nil)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil)
+	// One lookup for the whole page rather than one per location
+	repo.EXPECT().
+		ListContactPersonsByLocations(gomock.Any(), []int32{4, 9}).
+		Return([]sqlcdb.ContactPerson{
+			{ID: 1, LocationID: 9, Pic: "Budi", Phone: "0811"},
+			{ID: 2, LocationID: 9, Pic: "Sari", Phone: "0812", Email: pgtype.Text{String: "sari@example.com", Valid: true}},
+		}, nil).
+		Times(1)
+
+	w := performRequest(http.MethodGet, "/stock", h.GetAll, "/stock?include=contact_person", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var grouped []SparepartStockGroupedResponse
+	decodeResponse(t, w, &grouped)
+	if len(grouped) != 2 || len(grouped[0].ContactPersons) != 0 {
+		t.Fatalf("unexpected grouped response: %+v", grouped)
+	}
+	contacts := grouped[1].ContactPersons
+	if len(contacts) != 2 || contacts[0].Pic != "Budi" || contacts[1].Email == nil || *contacts[1].Email != "sari@example.com" {
+		t.Fatalf("unexpected contact persons: %+v", contacts)
+	}
+}
+
+func TestSparepartStockHandlerGetAllRejectsInvalidInclude(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{name: "unknown value", target: "/stock?include=photos"},
+		{name: "flat listing", target: "/stock?include=contact_person&group_by=none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			h := NewSparepartStockHandler(mocks.NewMockSparepartStockRepository(ctrl), nil, nil, 0, testLogger)
+
+			w := performRequest(http.MethodGet, "/stock", h.GetAll, tt.target, "")
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSparepartStockHandlerGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(10)).Return(sqlcdb.GetSparepartStockRow{ID: 10, LocationID: 4}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(4)).Return([]sqlcdb.ListSparepartStocksByLocationRow{
+		{ID: 10, LocationID: 4, LocationID2: 4, SparepartName: "BMS", Documentation: []byte(`["/uploads/a.jpg"]`)},
+		{ID: 11, LocationID: 4, LocationID2: 4, SparepartName: "Kabel NYY", Unit: "METER"},
+	}, nil)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/:id", h.GetByID, "/stock/10", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var grouped SparepartStockGroupedResponse
+	decodeResponse(t, w, &grouped)
+	if grouped.LocationID != 4 || len(grouped.Sparepart) != 2 {
+		t.Fatalf("unexpected grouped response: %+v", grouped)
+	}
+	if docs := grouped.Sparepart[0].Documentation; len(docs) != 1 || docs[0] != (DocumentationPhoto{URL: "/uploads/a.jpg", ThumbnailURL: "/uploads/a_thumb.jpg"}) {
+		t.Fatalf("unexpected documentation: %v", docs)
+	}
+	if grouped.Sparepart[1].Unit != "METER" {
+		t.Fatalf("expected the unit of the sparepart, got %q", grouped.Sparepart[1].Unit)
+	}
+}
+
+func TestSparepartStockHandlerGetByIDSelectsFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(10)).Return(sqlcdb.GetSparepartStockRow{ID: 10, LocationID: 4}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(4)).Return([]sqlcdb.ListSparepartStocksByLocationRow{
+		{ID: 10, LocationID: 4, LocationID2: 4, Cluster: "Dobo", SparepartName: "BMS", Quantity: 2},
+	}, nil)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/:id", h.GetByID, "/stock/10?fields=location,sparepart.name,sparepart.quantity&expand=", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var data map[string]any
+	decodeResponse(t, w, &data)
+	location, _ := data["location"].(map[string]any)
+	items, _ := data["sparepart"].([]any)
+	if len(data) != 2 || len(location) != 1 || location["id"] != float64(4) || len(items) != 1 {
+		t.Fatalf("unexpected shaped response: %v", data)
+	}
+	if item := items[0].(map[string]any); len(item) != 2 || item["name"] != "BMS" || item["quantity"] != float64(2) {
+		t.Fatalf("unexpected shaped item: %v", item)
+	}
+}
+
+func TestSparepartStockHandlerGetByIDNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(99)).Return(sqlcdb.GetSparepartStockRow{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodGet, "/stock/:id", h.GetByID, "/stock/99", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerQRCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(10)).Return(sqlcdb.GetSparepartStockRow{ID: 10, LocationID: 4}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/:id/qrcode", h.QRCode, "/stock/10/qrcode?size=128", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("expected image/png, got %q", ct)
+	}
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")) {
+		t.Fatal("expected a PNG image")
+	}
+
+	w = performRequest(http.MethodGet, "/stock/:id/qrcode", h.QRCode, "/stock/10/qrcode?size=5000", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerNearest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().
+		ListNearestStock(gomock.Any(), sqlcdb.ListNearestStockParams{SparepartID: 6, Lat: -3.69, Lng: 128.18, Limit: maxNearestLimit}).
+		Return([]sqlcdb.ListNearestStockRow{
+			{LocationID: 3, Region: sqlcdb.RegionTypeMALUKU, Regency: "Ambon", Cluster: "Nusaniwe", Latitude: -3.71, Longitude: 128.15, DistanceKm: 4.1, NewStockQuantity: 2},
+			{LocationID: 5, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo", Latitude: -5.76, Longitude: 134.22, DistanceKm: 707.5, UsedStockQuantity: 1},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/nearest", h.Nearest, "/stock/nearest?sparepart_id=6&lat=-3.69&lng=128.18&limit=500", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var nearest []NearestStockResponse
+	decodeResponse(t, w, &nearest)
+	if len(nearest) != 2 || nearest[0].Location.Cluster != "Nusaniwe" || nearest[0].DistanceKm != 4.1 || nearest[1].UsedStockQuantity != 1 {
+		t.Fatalf("unexpected nearest stock: %+v", nearest)
+	}
+}
+
+func TestSparepartStockHandlerNearestValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		field  string
+	}{
+		{name: "missing sparepart", target: "/stock/nearest?lat=-3.69&lng=128.18", field: "sparepart_id"},
+		{name: "missing point", target: "/stock/nearest?sparepart_id=6&lng=128.18", field: "lat"},
+		{name: "longitude out of range", target: "/stock/nearest?sparepart_id=6&lat=-3.69&lng=200", field: "lng"},
+		{name: "invalid limit", target: "/stock/nearest?sparepart_id=6&lat=-3.69&lng=128.18&limit=0", field: "limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			h := NewSparepartStockHandler(mocks.NewMockSparepartStockRepository(ctrl), nil, nil, 0, testLogger)
+
+			w := performRequest(http.MethodGet, "/stock/nearest", h.Nearest, tt.target, "")
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+			if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != tt.field {
+				t.Fatalf("expected a %s error, got %+v", tt.field, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestSparepartStockHandlerScan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	rows := []sqlcdb.ListSparepartStocksByLocationRow{{ID: 10, LocationID: 4, LocationID2: 4, SparepartName: "BMS"}}
+
+	// A label code resolves through the stock item, a serial number through its stock unit
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(10)).Return(sqlcdb.GetSparepartStockRow{ID: 10, LocationID: 4}, nil)
+	repo.EXPECT().ScanStockUnit(gomock.Any(), "BMS-001").Return(sqlcdb.ScanStockUnitRow{ID: 1, StockItemID: 10, LocationID: 4}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(4)).Return(rows, nil).Times(2)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil).Times(2)
+
+	for _, code := range []string{"stk-000010", "bms-001"} {
+		w := performRequest(http.MethodGet, "/scan", h.Scan, "/scan?code="+code, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", code, w.Code, w.Body.String())
+		}
+		var grouped SparepartStockGroupedResponse
+		decodeResponse(t, w, &grouped)
+		if grouped.LocationID != 4 || len(grouped.Sparepart) != 1 {
+			t.Fatalf("%s: unexpected grouped response: %+v", code, grouped)
+		}
+	}
+}
+
+func TestSparepartStockHandlerScanUnknownCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().ScanStockUnit(gomock.Any(), "XYZ").Return(sqlcdb.ScanStockUnitRow{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodGet, "/scan", h.Scan, "/scan?code=xyz", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerExportLabelsPDF(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		setup      func(repo *mocks.MockSparepartStockRepository)
+		wantStatus int
+	}{
+		{
+			name:       "missing selection",
+			target:     "/stock/labels/pdf",
+			setup:      func(repo *mocks.MockSparepartStockRepository) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid ids",
+			target:     "/stock/labels/pdf?ids=1,x",
+			setup:      func(repo *mocks.MockSparepartStockRepository) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "no items",
+			target: "/stock/labels/pdf?location_id=3",
+			setup: func(repo *mocks.MockSparepartStockRepository) {
+				repo.EXPECT().
+					ListSparepartStocksForLabels(gomock.Any(), sqlcdb.ListSparepartStocksForLabelsParams{LocationID: pgtype.Int4{Int32: 3, Valid: true}}).
+					Return([]sqlcdb.ListSparepartStocksForLabelsRow{}, nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "selected items",
+			target: "/stock/labels/pdf?ids=1,2",
+			setup: func(repo *mocks.MockSparepartStockRepository) {
+				repo.EXPECT().
+					ListSparepartStocksForLabels(gomock.Any(), sqlcdb.ListSparepartStocksForLabelsParams{Ids: []int32{1, 2}}).
+					Return([]sqlcdb.ListSparepartStocksForLabelsRow{
+						{ID: 1, SparepartName: "BMS", Regency: "Kepulauan Aru", Cluster: "Dobo", StockType: sqlcdb.StockTypeNEWSTOCK},
+						{ID: 2, SparepartName: "EHUB", Regency: "Kepulauan Aru", Cluster: "Dobo", StockType: sqlcdb.StockTypeUSEDSTOCK},
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockSparepartStockRepository(ctrl)
+			tt.setup(repo)
+			h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+			w := performRequest(http.MethodGet, "/stock/labels/pdf", h.ExportLabelsPDF, tt.target, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && w.Header().Get("Content-Type") != "application/pdf" {
+				t.Fatalf("expected PDF response, got %q", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestSparepartStockHandlerExportExcelReadsInBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	region := pgtype.Text{String: "MALUKU", Valid: true}
+	gomock.InOrder(
+		repo.EXPECT().
+			ListSparepartStocksForExport(gomock.Any(), sqlcdb.ListSparepartStocksForExportParams{Region: region, Limit: exportBatchSize}).
+			Return([]sqlcdb.ListSparepartStocksForExportRow{
+				{ID: 4, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo", SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2, Unit: "PCS",
+					Pic: pgtype.Text{String: "Andi", Valid: true}, Phone: pgtype.Text{String: "08123", Valid: true}},
+				{ID: 2, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo", SparepartName: "EHUB", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 1},
+			}, nil),
+		repo.EXPECT().
+			ListSparepartStocksForExport(gomock.Any(), sqlcdb.ListSparepartStocksForExportParams{
+				Region:       region,
+				AfterID:      pgtype.Int4{Int32: 2, Valid: true},
+				AfterRegion:  sqlcdb.NullRegionType{RegionType: sqlcdb.RegionTypeMALUKU, Valid: true},
+				AfterRegency: pgtype.Text{String: "Kepulauan Aru", Valid: true},
+				AfterName:    pgtype.Text{String: "EHUB", Valid: true},
+				Limit:        exportBatchSize,
+			}).
+			Return([]sqlcdb.ListSparepartStocksForExportRow{}, nil),
+	)
+
+	w := performRequest(http.MethodGet, "/stock/export/excel", h.ExportExcel, "/stock/export/excel?region=MALUKU", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	f, err := excelize.OpenReader(w.Body)
+	if err != nil {
+		t.Fatalf("failed to open exported workbook: %v", err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Sparepart Stock")
+	if err != nil {
+		t.Fatalf("failed to read exported rows: %v", err)
+	}
+	if len(rows) != 3 || rows[1][4] != "BMS" || rows[2][4] != "EHUB" {
+		t.Fatalf("unexpected exported rows: %v", rows)
+	}
+	if rows[0][7] != "Unit" || rows[1][7] != "PCS" {
+		t.Fatalf("unexpected unit column: %v", rows)
+	}
+	if rows[0][10] != "PIC" || rows[1][10] != "Andi" || rows[1][11] != "08123" {
+		t.Fatalf("unexpected PIC columns: %v", rows)
+	}
+}
+
+func TestSparepartStockHandlerExportCSVStreamsBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	stockType := pgtype.Text{String: "NEW_STOCK", Valid: true}
+	gomock.InOrder(
+		repo.EXPECT().
+			ListSparepartStocksForExport(gomock.Any(), sqlcdb.ListSparepartStocksForExportParams{StockType: stockType, Limit: exportBatchSize}).
+			Return([]sqlcdb.ListSparepartStocksForExportRow{
+				{ID: 4, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo", SparepartName: "BMS, 48V", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2, Unit: "SET",
+					Pic: pgtype.Text{String: "Andi", Valid: true}, Documentation: []byte(`["a.jpg","b.jpg"]`),
+					UnitsUnderWarranty: 1, UnitsWarrantyExpired: 1, NextWarrantyEnd: pgtype.Date{Time: time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}},
+			}, nil),
+		repo.EXPECT().
+			ListSparepartStocksForExport(gomock.Any(), sqlcdb.ListSparepartStocksForExportParams{
+				StockType:    stockType,
+				AfterID:      pgtype.Int4{Int32: 4, Valid: true},
+				AfterRegion:  sqlcdb.NullRegionType{RegionType: sqlcdb.RegionTypeMALUKU, Valid: true},
+				AfterRegency: pgtype.Text{String: "Kepulauan Aru", Valid: true},
+				AfterName:    pgtype.Text{String: "BMS, 48V", Valid: true},
+				Limit:        exportBatchSize,
+			}).
+			Return([]sqlcdb.ListSparepartStocksForExportRow{}, nil),
+	)
+
+	w := performRequest(http.MethodGet, "/stock/export/csv", h.ExportCSV, "/stock/export/csv?stock_type=NEW_STOCK", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read exported CSV: %v", err)
+	}
+	if len(rows) != 2 || rows[0][4] != "Sparepart Name" || rows[1][4] != "BMS, 48V" {
+		t.Fatalf("unexpected exported rows: %v", rows)
+	}
+	if rows[1][5] != "NEW_STOCK" || rows[1][6] != "2" || rows[1][7] != "SET" || rows[1][9] != "2" || rows[1][10] != "Andi" {
+		t.Fatalf("unexpected exported values: %v", rows[1])
+	}
+	if rows[0][12] != "Units Under Warranty" || rows[1][12] != "1" || rows[1][13] != "1" || rows[1][14] != "2027-03-01" {
+		t.Fatalf("unexpected warranty columns: %v", rows)
+	}
+}
+
+func TestSparepartStockHandlerExportCSVReportsErrorBeforeStreaming(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().
+		ListSparepartStocksForExport(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("connection reset"))
+
+	w := performRequest(http.MethodGet, "/stock/export/csv", h.ExportCSV, "/stock/export/csv", "")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "" {
+		t.Fatalf("expected no attachment header, got %q", got)
+	}
+}
+
+// newStockCreateRequest builds a multipart create request with a single photo
+func newStockCreateRequest(t *testing.T) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("location_id", "4")
+	_ = writer.WriteField("sparepart_id", "1")
+	_ = writer.WriteField("stock_type", "NEW_STOCK")
+	_ = writer.WriteField("quantity", "2")
+	part, err := writer.CreateFormFile("photos", "photo.jpg")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write(testJPEG(t))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/stock", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// tempUploads keeps the photos of a test in a temporary directory, returned with the service;
+// cfg gives the limits of the uploads
+func tempUploads(t *testing.T, cfg config.UploadConfig) (*uploads.Service, string) {
+	t.Helper()
+
+	cfg.Dir = t.TempDir()
+	if cfg.MaxFileSize == 0 {
+		cfg.MaxFileSize = 1 << 20
+	}
+	return uploads.New(storage.NewLocal(cfg.Dir), nil, cfg), cfg.Dir
+}
+
+// countUploadedFiles counts files stored under the upload directory, including the staging area
+func countUploadedFiles(t *testing.T, dir string) int {
+	t.Helper()
+
+	count := 0
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func TestSparepartStockHandlerCreateCommitsPhotos(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(repository.SparepartStockRepository) error) error {
+			return fn(repo)
+		})
+	repo.EXPECT().
+		CreateSparepartStock(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.CreateSparepartStockParams) (sqlcdb.SparepartStockItem, error) {
+			return sqlcdb.SparepartStockItem{ID: 1, LocationID: arg.LocationID, Documentation: arg.Documentation}, nil
+		})
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(4)).Return([]sqlcdb.ListSparepartStocksByLocationRow{
+		{ID: 1, LocationID: 4, LocationID2: 4},
+	}, nil)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil)
+
+	r := gin.New()
+	r.POST("/stock", h.Create)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newStockCreateRequest(t))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := countUploadedFiles(t, filepath.Join(dir, "sparepart", "new_stock")); n != 2 {
+		t.Fatalf("expected 1 committed photo and its thumbnail, found %d files", n)
+	}
+	if n := countUploadedFiles(t, filepath.Join(dir, ".staging")); n != 0 {
+		t.Fatalf("expected empty staging area, found %d files", n)
+	}
+}
+
+func TestSparepartStockHandlerCreateRollbackRemovesPhotos(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(repository.SparepartStockRepository) error) error {
+			return fn(repo)
+		})
+	repo.EXPECT().
+		CreateSparepartStock(gomock.Any(), gomock.Any()).
+		Return(sqlcdb.SparepartStockItem{}, errors.New("violates foreign key constraint"))
+
+	r := gin.New()
+	r.POST("/stock", h.Create)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newStockCreateRequest(t))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := countUploadedFiles(t, dir); n != 0 {
+		t.Fatalf("expected no photos left after rollback, found %d", n)
+	}
+}
+
+// The unique_sparepart_stock constraint keeps one row per location, sparepart and stock
+// type, so a second create is a 409 and its photos are discarded
+func TestSparepartStockHandlerCreateDuplicate(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(repository.SparepartStockRepository) error) error {
+			return fn(repo)
+		})
+	repo.EXPECT().
+		CreateSparepartStock(gomock.Any(), gomock.Any()).
+		Return(sqlcdb.SparepartStockItem{}, &pgconn.PgError{Code: "23505", ConstraintName: "unique_sparepart_stock"})
+
+	r := gin.New()
+	r.POST("/stock", h.Create)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newStockCreateRequest(t))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.ErrCodeDuplicate {
+		t.Fatalf("expected %s code, got %+v", utils.ErrCodeDuplicate, resp)
+	}
+	if n := countUploadedFiles(t, dir); n != 0 {
+		t.Fatalf("expected no photos left, found %d", n)
+	}
+}
+
+func TestSparepartStockHandlerCreateBatchStoresUnitCost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	cost := 1250000.5
+	repo.EXPECT().
+		CreateSparepartStocksBatch(gomock.Any(), sqlcdb.CreateSparepartStocksBatchParams{
+			LocationIds:  []int32{1, 3},
+			SparepartIds: []int32{2, 2},
+			StockTypes:   []sqlcdb.StockType{sqlcdb.StockTypeNEWSTOCK, sqlcdb.StockTypeNEWSTOCK},
+			Quantities:   []int32{5, 2},
+			Notes:        []string{"", ""},
+			// The second item has no unit cost of its own, so the master's applies
+			UnitCosts: []pgtype.Numeric{utils.OptionalNumeric(&cost), {}},
+		}).
+		Return([]sqlcdb.SparepartStockItem{
+			{ID: 10, LocationID: 1, SparepartID: 2, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 5, UnitCost: utils.OptionalNumeric(&cost)},
+			{ID: 11, LocationID: 3, SparepartID: 2, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2},
+		}, nil)
+
+	body := `{"items":[{"location_id":1,"sparepart_id":2,"stock_type":"NEW_STOCK","quantity":5,"unit_cost":1250000.5},{"location_id":3,"sparepart_id":2,"stock_type":"NEW_STOCK","quantity":2}]}`
+	w := performRequest(http.MethodPost, "/sparepart/stock/batch", h.CreateBatch, "/sparepart/stock/batch", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created []SparepartStockBatchItem
+	decodeResponse(t, w, &created)
+	if len(created) != 2 || created[0].UnitCost == nil || *created[0].UnitCost != cost || created[1].UnitCost != nil {
+		t.Fatalf("unexpected stored unit costs: %+v", created)
+	}
+}
+
+func TestSparepartStockHandlerCreateBatchRejectsInvalidStockType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	body := `{"items":[{"location_id":1,"sparepart_id":2,"stock_type":"NEW_STOCK"},{"location_id":1,"sparepart_id":3,"stock_type":"BROKEN"}]}`
+	w := performRequest(http.MethodPost, "/sparepart/stock/batch", h.CreateBatch, "/sparepart/stock/batch", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerCreateBatchReportsFieldErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	body := `{"items":[{"location_id":1,"sparepart_id":2,"stock_type":"NEW_STOCK"},{"sparepart_id":3,"stock_type":"NEW_STOCK"}]}`
+	w := performRequest(http.MethodPost, "/sparepart/stock/batch", h.CreateBatch, "/sparepart/stock/batch", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0] != (utils.FieldError{Field: "items[1].location_id", Rule: "required", Message: "is required"}) {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestSparepartStockHandlerCreateBatchRejectsOtherUnit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	// the sparepart is looked up once for both items
+	repo.EXPECT().GetSparepartMaster(gomock.Any(), int32(2)).Return(sqlcdb.ListSparepart{ID: 2, Unit: "METER"}, nil)
+
+	body := `{"items":[{"location_id":1,"sparepart_id":2,"stock_type":"NEW_STOCK","quantity":50,"unit":"METER"},{"location_id":3,"sparepart_id":2,"stock_type":"NEW_STOCK","quantity":2,"unit":"PCS"}]}`
+	w := performRequest(http.MethodPost, "/sparepart/stock/batch", h.CreateBatch, "/sparepart/stock/batch", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0] != utils.UnitMismatchError("items[1].unit", "METER") {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestSparepartStockHandlerUpdateRejectsOtherUnit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{ID: 4, Unit: "SET"}, nil)
+
+	w := performRequest(http.MethodPut, "/sparepart/stock/:id", h.Update, "/sparepart/stock/4", `{"quantity":3,"unit":"PCS","version":1}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "unit" || resp.Errors[0].Message != "must be SET, the unit of the sparepart" {
+		t.Fatalf("expected unit field error, got %+v", resp.Errors)
+	}
+}
+
+func TestSparepartStockHandlerUpdateRejectsNegativeQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{ID: 4}, nil)
+
+	w := performRequest(http.MethodPut, "/sparepart/stock/:id", h.Update, "/sparepart/stock/4", `{"quantity":-50}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "quantity" {
+		t.Fatalf("expected quantity field error, got %+v", resp.Errors)
+	}
+}
+
+func TestSparepartStockHandlerUpdateMapsCheckViolation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{ID: 4}, nil)
+	repo.EXPECT().UpdateSparepartStock(gomock.Any(), gomock.Any()).Return(sqlcdb.SparepartStockItem{}, &pgconn.PgError{
+		Code:           "23514",
+		ConstraintName: "sparepart_stock_item_quantity_non_negative",
+	})
+
+	w := performRequest(http.MethodPut, "/sparepart/stock/:id", h.Update, "/sparepart/stock/4", `{"quantity":1,"version":1}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "quantity" {
+		t.Fatalf("expected quantity field error, got %+v", resp.Errors)
+	}
+}
+
+func TestSparepartStockHandlerUpdateKeepsOmittedQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{ID: 4}, nil)
+	repo.EXPECT().UpdateSparepartStock(gomock.Any(), sqlcdb.UpdateSparepartStockParams{
+		ID:      4,
+		Notes:   pgtype.Text{String: "checked", Valid: true},
+		Version: 3,
+	}).Return(sqlcdb.SparepartStockItem{ID: 4, LocationID: 1, Quantity: 7, Version: 4}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(1)).Return([]sqlcdb.ListSparepartStocksByLocationRow{
+		{ID: 4, LocationID: 1, LocationID2: 1, SparepartID2: 2, SparepartName: "Battery", Quantity: 7},
+	}, nil)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil)
+
+	w := performRequest(http.MethodPut, "/sparepart/stock/:id", h.Update, "/sparepart/stock/4", `{"notes":"checked","version":3}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerDeleteKeepsPhotos(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+	files := storage.NewLocal(dir)
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, uploader, nil, 0, testLogger)
+
+	if err := files.Put(context.Background(), "sparepart/new_stock/a.jpg", strings.NewReader("jpg"), 3, "image/jpeg"); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).
+		Return(sqlcdb.GetSparepartStockRow{ID: 4, Documentation: []byte(`["/uploads/sparepart/new_stock/a.jpg"]`)}, nil)
+	repo.EXPECT().DeleteSparepartStock(gomock.Any(), int32(4)).Return(nil)
+
+	w := performRequest(http.MethodDelete, "/sparepart/stock/:id", h.Delete, "/sparepart/stock/4", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if exists, _ := files.Exists(context.Background(), "sparepart/new_stock/a.jpg"); !exists {
+		t.Fatal("expected the photo to be kept for a restore")
+	}
+}
+
+func TestSparepartStockHandlerRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().RestoreSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{ID: 4, LocationID: 1, Quantity: 7}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(1)).Return([]sqlcdb.ListSparepartStocksByLocationRow{
+		{ID: 4, LocationID: 1, LocationID2: 1, SparepartID2: 2, SparepartName: "Battery", Quantity: 7},
+	}, nil)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil)
+	repo.EXPECT().RestoreSparepartStock(gomock.Any(), int32(5)).Return(sqlcdb.SparepartStockItem{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/sparepart/stock/:id/restore", h.Restore, "/sparepart/stock/4/restore", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performRequest(http.MethodPost, "/sparepart/stock/:id/restore", h.Restore, "/sparepart/stock/5/restore", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerChangeCondition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{
+		ID: 4, LocationID: 1, SparepartID: 2, StockType: sqlcdb.StockTypeDAMAGED, Quantity: 3,
+	}, nil)
+	repo.EXPECT().TransferOutSparepartStock(gomock.Any(), sqlcdb.TransferOutSparepartStockParams{ID: 4, Quantity: 2}).
+		Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 1}, nil)
+	repo.EXPECT().TransferInSparepartStock(gomock.Any(), sqlcdb.TransferInSparepartStockParams{
+		LocationID: 1, SparepartID: 2, StockType: sqlcdb.StockTypeINREPAIR, Quantity: 2,
+	}).Return(sqlcdb.SparepartStockItem{ID: 6, Quantity: 2}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(1)).Return([]sqlcdb.ListSparepartStocksByLocationRow{
+		{ID: 4, LocationID: 1, LocationID2: 1, SparepartID2: 2, SparepartName: "Battery", StockType: sqlcdb.StockTypeDAMAGED, Quantity: 1},
+		{ID: 6, LocationID: 1, LocationID2: 1, SparepartID2: 2, SparepartName: "Battery", StockType: sqlcdb.StockTypeINREPAIR, Quantity: 2},
+	}, nil)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil)
+
+	route := "/sparepart/stock/:id/condition"
+	w := performRequest(http.MethodPost, route, h.ChangeCondition, "/sparepart/stock/4/condition", `{"stock_type": "IN_REPAIR", "quantity": 2}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performRequest(http.MethodPost, route, h.ChangeCondition, "/sparepart/stock/4/condition", `{"stock_type": "BROKEN", "quantity": 2}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerChangeConditionValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{
+		ID: 4, StockType: sqlcdb.StockTypeRESERVED, Quantity: 1,
+	}, nil)
+
+	w := performRequest(http.MethodPost, "/sparepart/stock/:id/condition", h.ChangeCondition, "/sparepart/stock/4/condition", `{"stock_type": "RESERVED", "quantity": 2}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 2 || resp.Errors[0].Field != "stock_type" || resp.Errors[1].Field != "quantity" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestSparepartStockHandlerUpdateVersionConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{ID: 4, Version: 2}, nil)
+	repo.EXPECT().UpdateSparepartStock(gomock.Any(), sqlcdb.UpdateSparepartStockParams{
+		ID:       4,
+		Quantity: pgtype.Int4{Int32: 5, Valid: true},
+		Version:  1,
+	}).Return(sqlcdb.SparepartStockItem{}, pgx.ErrNoRows)
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{ID: 4, Version: 3}, nil)
+
+	w := performRequest(http.MethodPut, "/sparepart/stock/:id", h.Update, "/sparepart/stock/4", `{"quantity":5,"version":1}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	var conflict utils.VersionConflict
+	if resp := decodeResponse(t, w, &conflict); resp.Code != utils.ErrCodeVersionConflict || conflict.CurrentVersion != 3 {
+		t.Fatalf("unexpected conflict response: %+v %+v", resp, conflict)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"3"` {
+		t.Fatalf("expected ETag \"3\", got %q", etag)
+	}
+}
+
+func TestSparepartStockHandlerUpdateRequiresVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{ID: 4}, nil)
+
+	w := performRequest(http.MethodPut, "/sparepart/stock/:id", h.Update, "/sparepart/stock/4", `{"quantity":5}`)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected status 428, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerUpdatePhotoMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, nil, nil, 0, testLogger)
+
+	// Documentation stored before photos had metadata is still read
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).
+		Return(sqlcdb.GetSparepartStockRow{ID: 4, LocationID: 1, Documentation: []byte(`["/uploads/sparepart/new_stock/a.jpg",{"url":"/uploads/sparepart/new_stock/b.jpg","uploaded_by":"budi"}]`)}, nil)
+	repo.EXPECT().UpdateSparepartStockDocumentation(gomock.Any(), sqlcdb.UpdateSparepartStockDocumentationParams{
+		ID:            4,
+		Documentation: []byte(`[{"url":"/uploads/sparepart/new_stock/a.jpg"},{"url":"/uploads/sparepart/new_stock/b.jpg","caption":"Battery bank","taken_at":"2024-04-30T23:30:00Z","uploaded_by":"budi"}]`),
+	}).Return(sqlcdb.SparepartStockItem{ID: 4, LocationID: 1}, nil)
+	repo.EXPECT().ListSparepartStocksByLocation(gomock.Any(), int32(1)).Return([]sqlcdb.ListSparepartStocksByLocationRow{
+		{ID: 4, LocationID: 1, LocationID2: 1, SparepartID2: 2, SparepartName: "Battery", Quantity: 7},
+	}, nil)
+	repo.EXPECT().ListLocationCompletenessByIDs(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListLocationCompletenessByIDsRow{}, nil)
+
+	body := `{"caption":" Battery bank ","taken_at":"2024-05-01T08:30:00+09:00"}`
+	w := performRequest(http.MethodPatch, "/sparepart/stock/:id/photos/:photo_index", h.UpdatePhotoMetadata, "/sparepart/stock/4/photos/1", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSparepartStockHandlerAddPhotosEnforcesPhotoLimit(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, uploader, nil, 1, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{
+		ID: 4, LocationID: 1, StockType: sqlcdb.StockTypeNEWSTOCK, Documentation: []byte(`["/uploads/sparepart/new_stock/a.jpg"]`),
+	}, nil)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("photos", "photo.jpg")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write(testJPEG(t))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/sparepart/stock/4/photos", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r := gin.New()
+	r.POST("/sparepart/stock/:id/photos", h.AddPhotos)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != "photos" {
+		t.Fatalf("expected photos field error, got %+v", resp.Errors)
+	}
+	if n := countUploadedFiles(t, dir); n != 0 {
+		t.Fatalf("expected no stored photo, found %d files", n)
+	}
+}
+
+func TestSparepartStockHandlerAddPhotosReleasesStoredPhotosOnFailure(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{
+		ID: 4, LocationID: 1, StockType: sqlcdb.StockTypeNEWSTOCK,
+	}, nil)
+
+	// The first photo is stored before the second one fails
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for i, content := range [][]byte{testJPEG(t), []byte("not an image")} {
+		part, err := writer.CreateFormFile("photos", fmt.Sprintf("photo%d.jpg", i))
+		if err != nil {
+			t.Fatalf("failed to create form file: %v", err)
+		}
+		_, _ = part.Write(content)
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/sparepart/stock/4/photos", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r := gin.New()
+	r.POST("/sparepart/stock/:id/photos", h.AddPhotos)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := countUploadedFiles(t, dir); n != 0 {
+		t.Fatalf("expected the stored photo to be released, found %d files", n)
+	}
+}
+
+func TestSparepartStockHandlerCreateEnforcesUploadQuota(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{QuotaBytes: 100})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, uploader, nil, 0, testLogger)
+
+	r := gin.New()
+	r.POST("/stock", h.Create)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newStockCreateRequest(t))
+
+	if w.Code != http.StatusInsufficientStorage {
+		t.Fatalf("expected status 507, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := countUploadedFiles(t, dir); n != 0 {
+		t.Fatalf("expected no stored photo, found %d files", n)
+	}
+}
+
+func TestSparepartStockHandlerAddPhotosRejectsExtraCaptions(t *testing.T) {
+	uploader, _ := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewSparepartStockHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().GetSparepartStock(gomock.Any(), int32(4)).Return(sqlcdb.GetSparepartStockRow{ID: 4, LocationID: 1, StockType: sqlcdb.StockTypeNEWSTOCK}, nil)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("photos", "photo.jpg")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write(testJPEG(t))
+	_ = writer.WriteField("captions", "Rack")
+	_ = writer.WriteField("captions", "Second rack")
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/sparepart/stock/4/photos", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r := gin.New()
+	r.POST("/sparepart/stock/:id/photos", h.AddPhotos)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != "captions" {
+		t.Fatalf("expected captions field error, got %+v", resp.Errors)
+	}
+}
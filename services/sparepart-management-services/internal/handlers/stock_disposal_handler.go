@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Disposal photos are stored under uploads/disposal
+const (
+	disposalPhotoSubDir = "disposal"
+	disposalPhotoPrefix = "stock_disposal"
+)
+
+// errDisposalInvalid ends a disposal transaction early; the response is written after the rollback
+var errDisposalInvalid = errors.New("disposal is invalid")
+
+// CreateStockDisposalRequest writes a quantity off a stock item. It is sent as multipart form
+// fields with the photos documenting it.
+type CreateStockDisposalRequest struct {
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
+	Reason     string `json:"reason" binding:"required"`
+	ApprovedBy string `json:"approved_by" binding:"required,max=255"`
+}
+
+// StockDisposalResponse is a disposal; the location and sparepart names are left out once they
+// are purged
+type StockDisposalResponse struct {
+	ID            int32                `json:"id"`
+	StockItemID   int32                `json:"stock_item_id"`
+	LocationID    int32                `json:"location_id"`
+	Region        *string              `json:"region,omitempty"`
+	Regency       *string              `json:"regency,omitempty"`
+	Cluster       *string              `json:"cluster,omitempty"`
+	SparepartID   int32                `json:"sparepart_id"`
+	SparepartName *string              `json:"sparepart_name,omitempty"`
+	StockType     string               `json:"stock_type"`
+	Quantity      int32                `json:"quantity"`
+	QuantityAfter int32                `json:"quantity_after"`
+	Reason        string               `json:"reason"`
+	ApprovedBy    string               `json:"approved_by"`
+	Photos        []DocumentationPhoto `json:"photos"`
+	DisposedBy    *string              `json:"disposed_by"`
+	CreatedAt     string               `json:"created_at"`
+}
+
+// StockDisposalSummaryItem is the disposed quantity of one sparepart and stock type at a location
+type StockDisposalSummaryItem struct {
+	LocationID      int32   `json:"location_id"`
+	Region          *string `json:"region,omitempty"`
+	Regency         *string `json:"regency,omitempty"`
+	Cluster         *string `json:"cluster,omitempty"`
+	SparepartID     int32   `json:"sparepart_id"`
+	SparepartName   *string `json:"sparepart_name,omitempty"`
+	StockType       string  `json:"stock_type"`
+	Disposals       int64   `json:"disposals"`
+	TotalQuantity   int64   `json:"total_quantity"`
+	FirstDisposedAt string  `json:"first_disposed_at"`
+	LastDisposedAt  string  `json:"last_disposed_at"`
+}
+
+// StockDisposalReport sums up the disposals of a period for reconciling shrinking stock
+type StockDisposalReport struct {
+	From           string                     `json:"from,omitempty"`
+	To             string                     `json:"to,omitempty"`
+	TotalDisposals int64                      `json:"total_disposals"`
+	TotalQuantity  int64                      `json:"total_quantity"`
+	Items          []StockDisposalSummaryItem `json:"items"`
+}
+
+// StockDisposalHandler writes stock off for good and reports what was written off
+type StockDisposalHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+	uploads *uploads.Service
+}
+
+func NewStockDisposalHandler(queries repository.SparepartStockRepository, uploads *uploads.Service, logger *zap.Logger) *StockDisposalHandler {
+	return &StockDisposalHandler{
+		logger:  logger,
+		queries: queries,
+		uploads: uploads,
+	}
+}
+
+// @Summary Dispose stock
+// @Description Write a quantity off a stock item for good, e.g. scrapped, lost or stolen. The reason, who approved the disposal and at least one photo documenting it are required. The quantity leaves the stock item and the change shows up as a DISPOSAL movement.
+// @Tags Stock Disposal
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Param quantity formData int true "Quantity to dispose"
+// @Param reason formData string true "Why the quantity is written off"
+// @Param approved_by formData string true "Who approved the disposal"
+// @Param photos formData file true "Photos documenting the disposal (multiple files allowed)"
+// @Param captions formData []string false "Caption of each photo, in the order of the photos"
+// @Param taken_at formData []string false "When each photo was taken (RFC3339), in the order of the photos"
+// @Success 201 {object} utils.Response{data=StockDisposalResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /sparepart/stock/{id}/disposals [post]
+func (h *StockDisposalHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	stockID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+	req, ok := bindStockDisposalForm(c)
+	if !ok {
+		return
+	}
+
+	// Stage the photos; they are only moved into place once the disposal is recorded
+	photos, staged, ok := stagePhotos(c, h.uploads, disposalPhotoSubDir, disposalPhotoPrefix, h.logger)
+	if !ok {
+		return
+	}
+	if len(photos) == 0 {
+		utils.ValidationError(c, utils.FieldError{Field: "photos", Rule: "required", Message: "at least one photo is required"})
+		return
+	}
+
+	var errs []utils.FieldError
+	var id int32
+	err = h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		item, err := repo.GetSparepartStockForUpdate(ctx, int32(stockID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errStockItemNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if req.Quantity > int(item.Quantity) {
+			errs = append(errs, utils.FieldError{Field: "quantity", Message: fmt.Sprintf("exceeds the stock quantity of %d", item.Quantity)})
+			return errDisposalInvalid
+		}
+
+		updated, err := repo.TransferOutSparepartStock(ctx, sqlcdb.TransferOutSparepartStockParams{ID: item.ID, Quantity: int32(req.Quantity)})
+		if err != nil {
+			return err
+		}
+		disposal, err := repo.CreateStockDisposal(ctx, sqlcdb.CreateStockDisposalParams{
+			StockItemID:   item.ID,
+			LocationID:    item.LocationID,
+			SparepartID:   item.SparepartID,
+			StockType:     item.StockType,
+			Quantity:      int32(req.Quantity),
+			QuantityAfter: updated.Quantity,
+			Reason:        req.Reason,
+			ApprovedBy:    req.ApprovedBy,
+			Photos:        utils.PhotosJSON(photos),
+			DisposedBy:    utils.TextFilter(utils.UserID(c)),
+		})
+		if err != nil {
+			return err
+		}
+		id = disposal.ID
+
+		// Move photos into place before commit, a failed move rolls back the disposal
+		return h.uploads.CommitStaged(ctx, staged, h.logger)
+	})
+	if err != nil {
+		h.uploads.DiscardStaged(staged, h.logger)
+	}
+	switch {
+	case errors.Is(err, errStockItemNotFound):
+		utils.NotFound(c, "Sparepart stock item not found")
+		return
+	case errors.Is(err, errDisposalInvalid):
+		utils.ValidationError(c, errs...)
+		return
+	case err != nil:
+		utils.HandleError(c, err, "Failed to dispose stock", h.logger)
+		return
+	}
+
+	h.respond(c, http.StatusCreated, id, "Stock disposed successfully")
+}
+
+// @Summary Get disposals
+// @Description Get the disposals, newest first
+// @Tags Stock Disposal
+// @Accept json
+// @Produce json
+// @Param location_id query int false "Filter by location ID"
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date inclusive (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse{data=[]StockDisposalResponse}
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/disposals [get]
+func (h *StockDisposalHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters, errs := parseStockDisposalFilters(c)
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountStockDisposals(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count disposals", h.logger)
+		return
+	}
+
+	disposals, err := h.queries.ListStockDisposals(ctx, sqlcdb.ListStockDisposalsParams{
+		LocationID:  filters.LocationID,
+		SparepartID: filters.SparepartID,
+		StockType:   filters.StockType,
+		Since:       filters.Since,
+		Until:       filters.Until,
+		Limit:       int32(pagination.Limit),
+		Offset:      int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get disposals", h.logger)
+		return
+	}
+
+	response := make([]StockDisposalResponse, 0, len(disposals))
+	for _, disposal := range disposals {
+		response = append(response, toStockDisposalResponse(sqlcdb.GetStockDisposalRow(disposal)))
+	}
+
+	utils.SuccessWithPagination(c, "Disposals retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get disposal by ID
+// @Description Get a single disposal with its reason, approver and photos
+// @Tags Stock Disposal
+// @Accept json
+// @Produce json
+// @Param id path int true "Disposal ID"
+// @Success 200 {object} utils.Response{data=StockDisposalResponse}
+// @Failure 404 {object} utils.Response
+// @Router /sparepart/disposals/{id} [get]
+func (h *StockDisposalHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid disposal ID")
+		return
+	}
+
+	h.respond(c, http.StatusOK, int32(id), "Disposal retrieved successfully")
+}
+
+// @Summary Get disposals report
+// @Description Sum up the disposed quantities per location, sparepart and stock type, largest first, so auditors can reconcile shrinking stock with the stock movements
+// @Tags Stock Disposal
+// @Accept json
+// @Produce json
+// @Param location_id query int false "Filter by location ID"
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date inclusive (YYYY-MM-DD)"
+// @Success 200 {object} utils.Response{data=StockDisposalReport}
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/disposals/report [get]
+func (h *StockDisposalHandler) GetReport(c *gin.Context) {
+	filters, errs := parseStockDisposalFilters(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	rows, err := h.queries.SummarizeStockDisposals(c.Request.Context(), sqlcdb.SummarizeStockDisposalsParams(filters))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get disposals report", h.logger)
+		return
+	}
+
+	report := StockDisposalReport{
+		From:  c.Query("from"),
+		To:    c.Query("to"),
+		Items: make([]StockDisposalSummaryItem, 0, len(rows)),
+	}
+	for _, row := range rows {
+		item := StockDisposalSummaryItem{
+			LocationID:      row.LocationID,
+			SparepartID:     row.SparepartID,
+			StockType:       string(row.StockType),
+			Disposals:       row.Disposals,
+			TotalQuantity:   row.TotalQuantity,
+			FirstDisposedAt: utils.FormatTimestamp(row.FirstDisposedAt),
+			LastDisposedAt:  utils.FormatTimestamp(row.LastDisposedAt),
+		}
+		if row.Region.Valid {
+			region := string(row.Region.RegionType)
+			item.Region = &region
+		}
+		if row.Regency.Valid {
+			item.Regency = &row.Regency.String
+		}
+		if row.Cluster.Valid {
+			item.Cluster = &row.Cluster.String
+		}
+		if row.SparepartName.Valid {
+			item.SparepartName = &row.SparepartName.String
+		}
+		report.TotalDisposals += row.Disposals
+		report.TotalQuantity += row.TotalQuantity
+		report.Items = append(report.Items, item)
+	}
+
+	utils.Success(c, "Disposals report retrieved successfully", report)
+}
+
+// parseStockDisposalFilters reads the disposal filters shared by the listing and the report
+func parseStockDisposalFilters(c *gin.Context) (sqlcdb.CountStockDisposalsParams, []utils.FieldError) {
+	filters := sqlcdb.CountStockDisposalsParams{StockType: utils.TextFilter(c.Query("stock_type"))}
+	errs := parseIDFilters(c, &filters.SparepartID, &filters.LocationID)
+	dates := []struct {
+		field  string
+		target *pgtype.Timestamptz
+		days   int
+	}{
+		{"from", &filters.Since, 0},
+		{"to", &filters.Until, 1},
+	}
+	for _, date := range dates {
+		if value := c.Query(date.field); value != "" {
+			parsed, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				errs = append(errs, utils.FieldError{Field: date.field, Message: "must be a date (YYYY-MM-DD)"})
+				continue
+			}
+			*date.target = pgtype.Timestamptz{Time: parsed.AddDate(0, 0, date.days), Valid: true}
+		}
+	}
+	if len(errs) == 0 && filters.Since.Valid && filters.Until.Valid && !filters.Since.Time.Before(filters.Until.Time) {
+		errs = append(errs, utils.FieldError{Field: "from", Message: "must not be after to"})
+	}
+	return filters, errs
+}
+
+// bindStockDisposalForm reads the multipart fields of a disposal and validates them, writing the
+// response when they are invalid
+func bindStockDisposalForm(c *gin.Context) (CreateStockDisposalRequest, bool) {
+	req := CreateStockDisposalRequest{
+		Reason:     strings.TrimSpace(c.PostForm("reason")),
+		ApprovedBy: strings.TrimSpace(c.PostForm("approved_by")),
+	}
+	if value := c.PostForm("quantity"); value != "" {
+		quantity, err := strconv.Atoi(value)
+		if err != nil {
+			utils.ValidationError(c, utils.FieldError{Field: "quantity", Rule: "type", Message: "must be a number"})
+			return req, false
+		}
+		req.Quantity = quantity
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		utils.BindingError(c, err)
+		return req, false
+	}
+	return req, true
+}
+
+// respond writes the disposal
+func (h *StockDisposalHandler) respond(c *gin.Context, code int, id int32, message string) {
+	disposal, err := h.queries.GetStockDisposal(c.Request.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.NotFound(c, "Disposal not found")
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get disposal", h.logger)
+		return
+	}
+
+	c.JSON(code, utils.Response{
+		Success: true,
+		Message: message,
+		Data:    toStockDisposalResponse(disposal),
+	})
+}
+
+func toStockDisposalResponse(row sqlcdb.GetStockDisposalRow) StockDisposalResponse {
+	response := StockDisposalResponse{
+		ID:            row.ID,
+		StockItemID:   row.StockItemID,
+		LocationID:    row.LocationID,
+		SparepartID:   row.SparepartID,
+		StockType:     string(row.StockType),
+		Quantity:      row.Quantity,
+		QuantityAfter: row.QuantityAfter,
+		Reason:        row.Reason,
+		ApprovedBy:    row.ApprovedBy,
+		Photos:        documentationPhotos(row.Photos),
+		CreatedAt:     utils.FormatTimestamp(row.CreatedAt),
+	}
+	if row.Region.Valid {
+		region := string(row.Region.RegionType)
+		response.Region = &region
+	}
+	if row.Regency.Valid {
+		response.Regency = &row.Regency.String
+	}
+	if row.Cluster.Valid {
+		response.Cluster = &row.Cluster.String
+	}
+	if row.SparepartName.Valid {
+		response.SparepartName = &row.SparepartName.String
+	}
+	if row.DisposedBy.Valid {
+		response.DisposedBy = &row.DisposedBy.String
+	}
+	return response
+}
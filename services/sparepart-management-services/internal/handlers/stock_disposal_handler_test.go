@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+// performDisposal posts a disposal of stock item 4 as budi, with a photo when withPhoto is set
+func performDisposal(t *testing.T, h *StockDisposalHandler, fields map[string]string, withPhoto bool) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		_ = writer.WriteField(name, value)
+	}
+	if withPhoto {
+		part, err := writer.CreateFormFile("photos", "photo.jpg")
+		if err != nil {
+			t.Fatalf("failed to create form file: %v", err)
+		}
+		_, _ = part.Write(testJPEG(t))
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/stock/4/disposals", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	r := gin.New()
+	r.POST("/stock/:id/disposals", asUser("budi"), h.Create)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestStockDisposalHandlerCreate(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockDisposalHandler(repo, uploader, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{
+		ID: 4, LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 5,
+	}, nil)
+	repo.EXPECT().TransferOutSparepartStock(gomock.Any(), sqlcdb.TransferOutSparepartStockParams{ID: 4, Quantity: 2}).
+		Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 3}, nil)
+	repo.EXPECT().
+		CreateStockDisposal(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.CreateStockDisposalParams) (sqlcdb.StockDisposal, error) {
+			if arg.StockItemID != 4 || arg.LocationID != 3 || arg.SparepartID != 7 || arg.StockType != sqlcdb.StockTypeUSEDSTOCK ||
+				arg.Quantity != 2 || arg.QuantityAfter != 3 || arg.Reason != "Terbakar" || arg.ApprovedBy != "Manajer Gudang" ||
+				arg.DisposedBy.String != "budi" || len(documentationPhotos(arg.Photos)) != 1 {
+				t.Errorf("unexpected disposal params: %+v", arg)
+			}
+			return sqlcdb.StockDisposal{ID: 3}, nil
+		})
+	repo.EXPECT().GetStockDisposal(gomock.Any(), int32(3)).Return(sqlcdb.GetStockDisposalRow{
+		ID: 3, StockItemID: 4, Quantity: 2, QuantityAfter: 3, Reason: "Terbakar", ApprovedBy: "Manajer Gudang",
+		DisposedBy: pgtype.Text{String: "budi", Valid: true},
+	}, nil)
+
+	w := performDisposal(t, h, map[string]string{"quantity": "2", "reason": " Terbakar ", "approved_by": "Manajer Gudang"}, true)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var disposal StockDisposalResponse
+	decodeResponse(t, w, &disposal)
+	if disposal.ID != 3 || disposal.QuantityAfter != 3 || disposal.DisposedBy == nil || *disposal.DisposedBy != "budi" {
+		t.Fatalf("unexpected disposal: %+v", disposal)
+	}
+	if n := countUploadedFiles(t, filepath.Join(dir, disposalPhotoSubDir)); n != 2 {
+		t.Fatalf("expected 1 committed photo and its thumbnail, found %d files", n)
+	}
+}
+
+func TestStockDisposalHandlerCreateRequiresReasonApproverAndPhotos(t *testing.T) {
+	uploader, _ := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockDisposalHandler(repo, uploader, testLogger)
+
+	w := performDisposal(t, h, map[string]string{"quantity": "1", "reason": " "}, true)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 2 || resp.Errors[0].Field != "reason" || resp.Errors[1].Field != "approved_by" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+
+	w = performDisposal(t, h, map[string]string{"quantity": "1", "reason": "Hilang", "approved_by": "Manajer Gudang"}, false)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp = decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "photos" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestStockDisposalHandlerCreateExceedsQuantity(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockDisposalHandler(repo, uploader, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 1}, nil)
+
+	w := performDisposal(t, h, map[string]string{"quantity": "3", "reason": "Hilang", "approved_by": "Manajer Gudang"}, true)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "quantity" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+	if n := countUploadedFiles(t, dir); n != 0 {
+		t.Fatalf("expected staged photos to be discarded, found %d files", n)
+	}
+}
+
+func TestStockDisposalHandlerGetReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockDisposalHandler(repo, nil, testLogger)
+
+	disposedAt := pgtype.Timestamptz{Time: time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC), Valid: true}
+	repo.EXPECT().SummarizeStockDisposals(gomock.Any(), sqlcdb.SummarizeStockDisposalsParams{
+		LocationID: pgtype.Int4{Int32: 3, Valid: true},
+		Since:      pgtype.Timestamptz{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		Until:      pgtype.Timestamptz{Time: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	}).Return([]sqlcdb.SummarizeStockDisposalsRow{
+		{LocationID: 3, SparepartID: 7, SparepartName: pgtype.Text{String: "BMS", Valid: true}, StockType: sqlcdb.StockTypeNEWSTOCK,
+			Disposals: 2, TotalQuantity: 5, FirstDisposedAt: disposedAt, LastDisposedAt: disposedAt},
+		{LocationID: 3, SparepartID: 8, StockType: sqlcdb.StockTypeUSEDSTOCK, Disposals: 1, TotalQuantity: 1,
+			FirstDisposedAt: disposedAt, LastDisposedAt: disposedAt},
+	}, nil)
+
+	w := performRequest(http.MethodGet, "/disposals/report", h.GetReport, "/disposals/report?location_id=3&from=2026-03-01&to=2026-03-31", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report StockDisposalReport
+	decodeResponse(t, w, &report)
+	if report.TotalDisposals != 3 || report.TotalQuantity != 6 || len(report.Items) != 2 || report.From != "2026-03-01" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Items[0].SparepartName == nil || *report.Items[0].SparepartName != "BMS" || report.Items[1].SparepartName != nil {
+		t.Fatalf("unexpected report items: %+v", report.Items)
+	}
+}
@@ -0,0 +1,437 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxImportFileSize and maxImportRows bound a single spreadsheet import
+	maxImportFileSize = 5 << 20
+	maxImportRows     = 10000
+)
+
+// stockImportTemplate is the file layout StockImportHandler.Import reads
+var stockImportTemplate = utils.ImportTemplate{
+	Sheet: "Stock Import",
+	Columns: []utils.ImportTemplateColumn{
+		{Name: "location_id", Whole: true, Note: "Location ID; leave empty to use the cluster"},
+		{Name: "cluster", Note: "Cluster name, used when location_id is empty"},
+		{Name: "sparepart_name", Note: "Name as registered in the sparepart master list"},
+		{Name: "stock_type", Options: stockTypeOptions()},
+		{Name: "quantity", Whole: true},
+		{Name: "notes"},
+	},
+	Example: []string{"", "Dobo", "BMS", string(models.StockTypeNew), "2", "Rak 1"},
+	Rows:    maxImportRows,
+}
+
+// stockTypeOptions lists the stock types accepted in the stock_type column
+func stockTypeOptions() []string {
+	options := make([]string, 0, len(models.StockTypes))
+	for _, stockType := range models.StockTypes {
+		options = append(options, string(stockType))
+	}
+	return options
+}
+
+var (
+	// errImportConflicts ends an import's transaction when rows collide with existing stock items
+	errImportConflicts = errors.New("imported rows already exist")
+	// errImportStale ends an import's transaction when a row's location or sparepart was
+	// deleted after it was looked up
+	errImportStale = errors.New("imported rows refer to deleted locations or spareparts")
+)
+
+// StockImportResponse lists the stock items created by an import
+type StockImportResponse struct {
+	Rows    int                       `json:"rows"`
+	Created []SparepartStockBatchItem `json:"created"`
+}
+
+// stockImportRow is one parsed spreadsheet row; Line is its row number in the file
+type stockImportRow struct {
+	Line          int
+	LocationID    int32
+	Cluster       string
+	SparepartName string
+	StockType     sqlcdb.StockType
+	Quantity      int32
+	Notes         string
+	SparepartID   int32
+}
+
+// StockImportHandler creates sparepart stock items from spreadsheets kept by field teams
+type StockImportHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+}
+
+func NewStockImportHandler(queries repository.SparepartStockRepository, logger *zap.Logger) *StockImportHandler {
+	return &StockImportHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Import sparepart stock from a spreadsheet
+// @Description Create stock items from a .csv or .xlsx file whose header row names the columns location_id or cluster, sparepart_name, stock_type, quantity and optionally notes (the CSV/Excel export headers are accepted too). Every row is validated first; on any error nothing is created and the errors are reported per row as rows[<line>].<column>. Otherwise all rows are copied into a staging table and inserted from it in one transaction; when a referenced location or sparepart is deleted meanwhile nothing is created and the response is 409.
+// @Tags Sparepart Stock
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Spreadsheet (.csv or .xlsx, max 10000 rows)"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/stock/import [post]
+func (h *StockImportHandler) Import(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	records, ok := readImportFile(c, h.logger)
+	if !ok {
+		return
+	}
+
+	rows, errs := parseStockImportRows(records)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	locationIDs, errs, err := h.resolveImportRows(c, rows)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to look up imported rows", h.logger)
+		return
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	staged := make([]sqlcdb.CopySparepartStockImportParams, 0, len(rows))
+	for i, row := range rows {
+		staged = append(staged, sqlcdb.CopySparepartStockImportParams{
+			Line:        int32(row.Line),
+			LocationID:  locationIDs[i],
+			SparepartID: row.SparepartID,
+			StockType:   row.StockType,
+			Quantity:    row.Quantity,
+			Notes:       row.Notes,
+		})
+	}
+
+	// The rows are copied into the staging table and moved into stock items by one statement,
+	// which checks their location and sparepart again
+	var items []sqlcdb.SparepartStockItem
+	var conflicts []utils.FieldError
+	err = h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		if _, err := repo.CopySparepartStockImport(ctx, staged); err != nil {
+			return err
+		}
+		lines, err := repo.ListSparepartStockImportConflicts(ctx)
+		if err != nil {
+			return err
+		}
+		if len(lines) > 0 {
+			conflicts = stockImportConflicts(lines)
+			return errImportConflicts
+		}
+
+		items, err = repo.CreateSparepartStocksFromImport(ctx)
+		if err != nil {
+			return err
+		}
+		if len(items) != len(staged) {
+			return errImportStale
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errImportConflicts):
+		utils.ValidationError(c, conflicts...)
+		return
+	case errors.Is(err, errImportStale):
+		utils.Error(c, "Imported locations or spareparts were deleted during the import, please retry", http.StatusConflict)
+		return
+	case err != nil:
+		utils.HandleError(c, err, "Failed to import sparepart stock items", h.logger)
+		return
+	}
+
+	created := make([]SparepartStockBatchItem, 0, len(items))
+	for _, item := range items {
+		created = append(created, toSparepartStockBatchItem(item))
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: fmt.Sprintf("%d sparepart stock items imported successfully", len(created)),
+		Data: StockImportResponse{
+			Rows:    len(rows),
+			Created: created,
+		},
+	})
+}
+
+// @Summary Download the sparepart stock import template
+// @Description Download an empty import file with the expected header row and an example row to replace. The .xlsx template offers the stock types as a dropdown and only accepts whole numbers for location_id and quantity.
+// @Tags Sparepart Stock
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param format query string false "Template format (xlsx, csv)" default(xlsx)
+// @Success 200 {file} file
+// @Router /sparepart/stock/import/template [get]
+func (h *StockImportHandler) Template(c *gin.Context) {
+	sendImportTemplate(c, stockImportTemplate, "stock_import_template", h.logger)
+}
+
+// sendImportTemplate writes template as a download named basename, as a workbook or, with
+// format=csv, as CSV
+func sendImportTemplate(c *gin.Context, template utils.ImportTemplate, basename string, logger *zap.Logger) {
+	switch format := c.DefaultQuery("format", "xlsx"); format {
+	case "xlsx":
+		buf, err := template.Excel()
+		if err != nil {
+			utils.HandleError(c, err, "Failed to generate template", logger)
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+basename+".xlsx")
+		c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+	case "csv":
+		var buf bytes.Buffer
+		if err := template.WriteCSV(&buf); err != nil {
+			utils.HandleError(c, err, "Failed to generate template", logger)
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+basename+".csv")
+		c.Data(http.StatusOK, "text/csv", buf.Bytes())
+	default:
+		utils.ValidationError(c, utils.FieldError{Field: "format", Message: "must be xlsx or csv"})
+	}
+}
+
+// readImportFile reads the spreadsheet uploaded as the file form field, responding with an
+// error and returning false when it is missing, too large or unreadable
+func readImportFile(c *gin.Context, logger *zap.Logger) ([][]string, bool) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "file is required")
+		return nil, false
+	}
+	if file.Size > maxImportFileSize {
+		utils.BadRequest(c, fmt.Sprintf("file size exceeds maximum allowed size of %d bytes", maxImportFileSize))
+		return nil, false
+	}
+	f, err := file.Open()
+	if err != nil {
+		utils.HandleError(c, err, "Failed to open uploaded file", logger)
+		return nil, false
+	}
+	defer f.Close()
+
+	records, err := utils.ReadSpreadsheet(f, file.Filename)
+	if err != nil {
+		utils.BadRequest(c, "Failed to read file: "+err.Error())
+		return nil, false
+	}
+	return records, true
+}
+
+// parseStockImportRows maps the header row to the import columns and parses every
+// non-empty row after it
+func parseStockImportRows(records [][]string) ([]stockImportRow, []utils.FieldError) {
+	if len(records) == 0 {
+		return nil, []utils.FieldError{{Field: "file", Message: "is empty"}}
+	}
+
+	columns := utils.ImportColumns(records[0])
+	var errs []utils.FieldError
+	for _, name := range []string{"sparepart_name", "stock_type", "quantity"} {
+		if _, ok := columns[name]; !ok {
+			errs = append(errs, utils.FieldError{Field: "file", Message: "missing column " + name})
+		}
+	}
+	_, hasLocationID := columns["location_id"]
+	_, hasCluster := columns["cluster"]
+	if !hasLocationID && !hasCluster {
+		errs = append(errs, utils.FieldError{Field: "file", Message: "missing column location_id or cluster"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	value := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []stockImportRow
+	for index, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		line := index + 2
+		field := func(name string) string {
+			return fmt.Sprintf("rows[%d].%s", line, name)
+		}
+		row := stockImportRow{
+			Line:          line,
+			Cluster:       value(record, "cluster"),
+			SparepartName: value(record, "sparepart_name"),
+			Notes:         value(record, "notes"),
+		}
+
+		if raw := value(record, "location_id"); raw != "" {
+			id, err := strconv.ParseInt(raw, 10, 32)
+			if err != nil || id < 1 {
+				errs = append(errs, utils.FieldError{Field: field("location_id"), Message: "must be a positive integer"})
+			}
+			row.LocationID = int32(id)
+		} else if row.Cluster == "" {
+			errs = append(errs, utils.FieldError{Field: field("location_id"), Message: "location_id or cluster is required"})
+		}
+
+		if row.SparepartName == "" {
+			errs = append(errs, utils.FieldError{Field: field("sparepart_name"), Message: "is required"})
+		}
+
+		if stockType := models.StockType(strings.ToUpper(value(record, "stock_type"))); stockType.Valid() {
+			row.StockType = sqlcdb.StockType(stockType)
+		} else {
+			errs = append(errs, utils.FieldError{Field: field("stock_type"), Message: "must be one of " + strings.Join(stockTypeOptions(), ", ")})
+		}
+
+		quantity, err := strconv.ParseInt(value(record, "quantity"), 10, 32)
+		switch {
+		case err != nil:
+			errs = append(errs, utils.FieldError{Field: field("quantity"), Message: "must be an integer"})
+		case quantity < 0:
+			errs = append(errs, utils.NegativeQuantityError(field("quantity")))
+		default:
+			row.Quantity = int32(quantity)
+		}
+
+		rows = append(rows, row)
+	}
+
+	switch {
+	case len(rows) == 0 && len(errs) == 0:
+		errs = append(errs, utils.FieldError{Field: "file", Message: "has no rows"})
+	case len(rows) > maxImportRows:
+		errs = []utils.FieldError{{Field: "file", Message: fmt.Sprintf("has %d rows, at most %d can be imported at once", len(rows), maxImportRows)}}
+	}
+	return rows, errs
+}
+
+// resolveImportRows looks up the location and sparepart of every row, setting SparepartID
+// and returning the location ids in row order; rows that cannot be resolved, or that repeat
+// an earlier row's location, sparepart and stock type, are reported as field errors
+func (h *StockImportHandler) resolveImportRows(c *gin.Context, rows []stockImportRow) ([]int32, []utils.FieldError, error) {
+	ctx := c.Request.Context()
+
+	var lookup sqlcdb.ListLocationsForImportParams
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.LocationID > 0 {
+			lookup.Ids = append(lookup.Ids, row.LocationID)
+		} else {
+			lookup.Clusters = append(lookup.Clusters, strings.ToLower(row.Cluster))
+		}
+		names = append(names, strings.ToLower(row.SparepartName))
+	}
+
+	locations, err := h.queries.ListLocationsForImport(ctx, lookup)
+	if err != nil {
+		return nil, nil, err
+	}
+	masters, err := h.queries.ListSparepartMastersByNames(ctx, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	locationsByID := make(map[int32]bool, len(locations))
+	locationsByCluster := make(map[string][]int32)
+	for _, location := range locations {
+		locationsByID[location.ID] = true
+		cluster := strings.ToLower(location.Cluster)
+		locationsByCluster[cluster] = append(locationsByCluster[cluster], location.ID)
+	}
+	mastersByName := make(map[string][]int32)
+	for _, master := range masters {
+		name := strings.ToLower(master.Name)
+		mastersByName[name] = append(mastersByName[name], master.ID)
+	}
+
+	var errs []utils.FieldError
+	locationIDs := make([]int32, 0, len(rows))
+	seen := make(map[string]int)
+	for i := range rows {
+		row := &rows[i]
+		field := func(name string) string {
+			return fmt.Sprintf("rows[%d].%s", row.Line, name)
+		}
+
+		locationID := row.LocationID
+		if locationID > 0 {
+			if !locationsByID[locationID] {
+				errs = append(errs, utils.FieldError{Field: field("location_id"), Message: "location not found"})
+			}
+		} else {
+			switch ids := locationsByCluster[strings.ToLower(row.Cluster)]; len(ids) {
+			case 0:
+				errs = append(errs, utils.FieldError{Field: field("cluster"), Message: "location not found"})
+			case 1:
+				locationID = ids[0]
+			default:
+				errs = append(errs, utils.FieldError{Field: field("cluster"), Message: fmt.Sprintf("matches %d locations, use location_id", len(ids))})
+			}
+		}
+
+		switch ids := mastersByName[strings.ToLower(row.SparepartName)]; len(ids) {
+		case 0:
+			errs = append(errs, utils.FieldError{Field: field("sparepart_name"), Message: "sparepart not found"})
+		case 1:
+			row.SparepartID = ids[0]
+		default:
+			errs = append(errs, utils.FieldError{Field: field("sparepart_name"), Message: fmt.Sprintf("matches %d spareparts", len(ids))})
+		}
+
+		if locationID > 0 && row.SparepartID > 0 {
+			key := fmt.Sprintf("%d/%d/%s", locationID, row.SparepartID, row.StockType)
+			if line, ok := seen[key]; ok {
+				errs = append(errs, utils.FieldError{Field: field("sparepart_name"), Message: fmt.Sprintf("duplicates row %d", line)})
+			} else {
+				seen[key] = row.Line
+			}
+		}
+		locationIDs = append(locationIDs, locationID)
+	}
+	return locationIDs, errs, nil
+}
+
+// stockImportConflicts reports the rows on lines whose location, sparepart and stock type
+// already have a stock item
+func stockImportConflicts(lines []int32) []utils.FieldError {
+	errs := make([]utils.FieldError, 0, len(lines))
+	for _, line := range lines {
+		errs = append(errs, utils.FieldError{
+			Field:   fmt.Sprintf("rows[%d].sparepart_name", line),
+			Message: "already has a stock item of this type at the location",
+		})
+	}
+	return errs
+}
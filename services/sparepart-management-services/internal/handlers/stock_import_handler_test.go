@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+// performImport uploads content as the stock import file named filename
+func performImport(t *testing.T, h *StockImportHandler, filename, content string) *httptest.ResponseRecorder {
+	t.Helper()
+	return performUpload(t, "/stock/import", h.Import, "/stock/import", filename, content)
+}
+
+// performUpload posts content as the file form field, named filename, to target
+func performUpload(t *testing.T, route string, handler gin.HandlerFunc, target, filename, content string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write([]byte(content))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	r := gin.New()
+	r.POST(route, handler)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestStockImportHandlerImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockImportHandler(repo, testLogger)
+
+	// Semicolon separated with a byte order mark, as saved by spreadsheet programs
+	content := "\xef\xbb\xbfCluster;Sparepart Name;Stock Type;Quantity;Notes\n" +
+		"Dobo;bms;new_stock;4;rak 2\n" +
+		";;;;\n" +
+		"Dobo;EHUB;USED_STOCK;0;\n"
+
+	repo.EXPECT().
+		ListLocationsForImport(gomock.Any(), sqlcdb.ListLocationsForImportParams{Clusters: []string{"dobo", "dobo"}}).
+		Return([]sqlcdb.Location{{ID: 3, Cluster: "Dobo"}}, nil)
+	repo.EXPECT().
+		ListSparepartMastersByNames(gomock.Any(), []string{"bms", "ehub"}).
+		Return([]sqlcdb.ListSparepart{{ID: 7, Name: "BMS"}, {ID: 8, Name: "EHUB"}}, nil)
+	expectTransaction(repo)
+	repo.EXPECT().
+		CopySparepartStockImport(gomock.Any(), []sqlcdb.CopySparepartStockImportParams{
+			{Line: 2, LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4, Notes: "rak 2"},
+			{Line: 4, LocationID: 3, SparepartID: 8, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 0},
+		}).
+		Return(int64(2), nil)
+	repo.EXPECT().ListSparepartStockImportConflicts(gomock.Any()).Return([]int32{}, nil)
+	repo.EXPECT().
+		CreateSparepartStocksFromImport(gomock.Any()).
+		Return([]sqlcdb.SparepartStockItem{
+			{ID: 30, LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 4, Notes: pgtype.Text{String: "rak 2", Valid: true}},
+			{ID: 31, LocationID: 3, SparepartID: 8, StockType: sqlcdb.StockTypeUSEDSTOCK},
+		}, nil)
+
+	w := performImport(t, h, "stock.csv", content)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var data StockImportResponse
+	decodeResponse(t, w, &data)
+	if data.Rows != 2 || len(data.Created) != 2 || data.Created[0].ID != 30 || *data.Created[0].Notes != "rak 2" {
+		t.Fatalf("unexpected import result: %+v", data)
+	}
+}
+
+func TestStockImportHandlerImportReportsRowErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockImportHandler(repo, testLogger)
+
+	content := "location_id,sparepart_name,stock_type,quantity\n" +
+		"3,BMS,NEW_STOCK,2\n" +
+		"9,BMS,NEW_STOCK,1\n" +
+		"3,Unknown,USED_STOCK,1\n" +
+		"3,bms,NEW_STOCK,5\n"
+
+	repo.EXPECT().
+		ListLocationsForImport(gomock.Any(), sqlcdb.ListLocationsForImportParams{Ids: []int32{3, 9, 3, 3}}).
+		Return([]sqlcdb.Location{{ID: 3, Cluster: "Dobo"}}, nil)
+	repo.EXPECT().
+		ListSparepartMastersByNames(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListSparepart{{ID: 7, Name: "BMS"}}, nil)
+
+	w := performImport(t, h, "stock.csv", content)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	want := map[string]string{
+		"rows[3].location_id":    "location not found",
+		"rows[4].sparepart_name": "sparepart not found",
+		"rows[5].sparepart_name": "duplicates row 2",
+	}
+	if len(resp.Errors) != len(want) {
+		t.Fatalf("expected %d row errors, got %+v", len(want), resp.Errors)
+	}
+	for _, fieldErr := range resp.Errors {
+		if want[fieldErr.Field] != fieldErr.Message {
+			t.Fatalf("unexpected row error %+v", fieldErr)
+		}
+	}
+}
+
+func TestStockImportHandlerImportRejectsInvalidRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockImportHandler(repo, testLogger)
+
+	tests := []struct {
+		name    string
+		content string
+		field   string
+	}{
+		{"missing column", "cluster,sparepart_name,quantity\nDobo,BMS,1\n", "file"},
+		{"invalid stock type", "cluster,sparepart_name,stock_type,quantity\nDobo,BMS,BROKEN,1\n", "rows[2].stock_type"},
+		{"negative quantity", "cluster,sparepart_name,stock_type,quantity\nDobo,BMS,NEW_STOCK,-1\n", "rows[2].quantity"},
+		{"no rows", "cluster,sparepart_name,stock_type,quantity\n", "file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performImport(t, h, "stock.csv", tt.content)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+			resp := decodeResponse(t, w, nil)
+			if len(resp.Errors) != 1 || resp.Errors[0].Field != tt.field {
+				t.Fatalf("expected an error on %s, got %+v", tt.field, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestStockImportHandlerImportRejectsExistingStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockImportHandler(repo, testLogger)
+
+	repo.EXPECT().ListLocationsForImport(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.Location{{ID: 3, Cluster: "Dobo"}}, nil)
+	repo.EXPECT().ListSparepartMastersByNames(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListSparepart{{ID: 7, Name: "BMS"}}, nil)
+	expectTransaction(repo)
+	repo.EXPECT().CopySparepartStockImport(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+	repo.EXPECT().ListSparepartStockImportConflicts(gomock.Any()).Return([]int32{3}, nil)
+
+	w := performImport(t, h, "stock.csv", "location_id,sparepart_name,stock_type,quantity\n3,BMS,NEW_STOCK,1\n3,BMS,USED_STOCK,1\n")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "rows[3].sparepart_name" {
+		t.Fatalf("expected a conflict on row 3, got %+v", resp.Errors)
+	}
+}
+
+func TestStockImportHandlerImportRejectsStaleRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockImportHandler(repo, testLogger)
+
+	repo.EXPECT().ListLocationsForImport(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.Location{{ID: 3, Cluster: "Dobo"}}, nil)
+	repo.EXPECT().ListSparepartMastersByNames(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListSparepart{{ID: 7, Name: "BMS"}}, nil)
+	expectTransaction(repo)
+	repo.EXPECT().CopySparepartStockImport(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+	repo.EXPECT().ListSparepartStockImportConflicts(gomock.Any()).Return([]int32{}, nil)
+	// The location was deleted after the lookup, so one row is left out of the insert
+	repo.EXPECT().CreateSparepartStocksFromImport(gomock.Any()).
+		Return([]sqlcdb.SparepartStockItem{{ID: 30, LocationID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK}}, nil)
+
+	w := performImport(t, h, "stock.csv", "location_id,sparepart_name,stock_type,quantity\n3,BMS,NEW_STOCK,1\n3,BMS,USED_STOCK,1\n")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStockImportHandlerImportRejectsUnsupportedFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockImportHandler(repo, testLogger)
+
+	w := performImport(t, h, "stock.txt", "cluster,sparepart_name,stock_type,quantity\n")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStockImportHandlerTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockImportHandler(repo, testLogger)
+
+	for _, format := range []string{"xlsx", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			w := performRequest(http.MethodGet, "/stock/import/template", h.Template, "/stock/import/template?format="+format, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			// The template, example row included, must be accepted by the import
+			records, err := utils.ReadSpreadsheet(w.Body, "stock_import_template."+format)
+			if err != nil {
+				t.Fatalf("failed to read template: %v", err)
+			}
+			rows, errs := parseStockImportRows(records)
+			if len(errs) > 0 || len(rows) != 1 || rows[0].Cluster != "Dobo" {
+				t.Fatalf("template does not parse: %+v %+v", rows, errs)
+			}
+		})
+	}
+
+	w := performRequest(http.MethodGet, "/stock/import/template", h.Template, "/stock/import/template?format=pdf", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Planning statuses of a stock level, for the stock outside of its range
+const (
+	stockLevelBelowMin = "BELOW_MIN"
+	stockLevelAboveMax = "ABOVE_MAX"
+)
+
+// StockLevelRequest sets the minimum and maximum quantity of a sparepart to keep at a location
+type StockLevelRequest struct {
+	LocationID  int  `json:"location_id" binding:"required,min=1"`
+	SparepartID int  `json:"sparepart_id" binding:"required,min=1"`
+	MinQuantity *int `json:"min_quantity" binding:"required,min=0"`
+	// Without a maximum the stock is never reported as above it
+	MaxQuantity *int `json:"max_quantity" binding:"omitempty,min=0"`
+}
+
+// StockLevelPlanningItem is a sparepart whose available stock at a location is outside of its
+// configured range
+type StockLevelPlanningItem struct {
+	StockLevelID  int32                  `json:"stock_level_id"`
+	Location      SparepartStockLocation `json:"location"`
+	SparepartID   int32                  `json:"sparepart_id"`
+	SparepartName string                 `json:"sparepart_name"`
+	Unit          string                 `json:"unit"`
+	MinQuantity   int32                  `json:"min_quantity"`
+	MaxQuantity   *int32                 `json:"max_quantity"`
+	Quantity      int64                  `json:"quantity"`
+	Status        string                 `json:"status"`
+	// Quantity to send to bring the stock up to the maximum, or to the minimum without one
+	OrderQuantity int64 `json:"order_quantity,omitempty"`
+	// Quantity above the maximum that could be moved to another location
+	ExcessQuantity int64 `json:"excess_quantity,omitempty"`
+}
+
+// StockLevelHandler manages the minimum and maximum stock levels per location and sparepart,
+// which plan each location's stock apart from the global low stock alerts
+type StockLevelHandler struct {
+	logger  *zap.Logger
+	queries repository.StockLevelRepository
+}
+
+func NewStockLevelHandler(queries repository.StockLevelRepository, logger *zap.Logger) *StockLevelHandler {
+	return &StockLevelHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get stock levels
+// @Description Get the minimum and maximum stock levels configured per location and sparepart, ordered by location and sparepart name
+// @Tags Stock Level
+// @Accept json
+// @Produce json
+// @Param location_id query int false "Filter by location ID"
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param region query string false "Filter by region (exact match)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/stock-levels [get]
+func (h *StockLevelHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := sqlcdb.CountStockLevelsParams{Region: utils.TextFilter(c.Query("region"))}
+	errs := parseIDFilters(c, &filters.SparepartID, &filters.LocationID)
+	pagination, pageErrs := utils.ParsePagination(c)
+	errs = append(errs, pageErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountStockLevels(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count stock levels", h.logger)
+		return
+	}
+
+	levels, err := h.queries.ListStockLevels(ctx, sqlcdb.ListStockLevelsParams{
+		LocationID:  filters.LocationID,
+		SparepartID: filters.SparepartID,
+		Region:      filters.Region,
+		Limit:       int32(pagination.Limit),
+		Offset:      int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock levels", h.logger)
+		return
+	}
+
+	utils.SuccessWithPagination(c, "Stock levels retrieved successfully", levels, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Set stock level
+// @Description Set the minimum and maximum quantity of a sparepart to keep at a location, replacing the level already set for them. The levels count the available stock (NEW_STOCK and USED_STOCK); without max_quantity the stock is never above it.
+// @Tags Stock Level
+// @Accept json
+// @Produce json
+// @Param level body StockLevelRequest true "Stock level"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/stock-levels [put]
+func (h *StockLevelHandler) Set(c *gin.Context) {
+	var req StockLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	if req.MaxQuantity != nil && *req.MaxQuantity < *req.MinQuantity {
+		utils.ValidationError(c, utils.StockLevelRangeError)
+		return
+	}
+
+	level, err := h.queries.UpsertStockLevel(c.Request.Context(), sqlcdb.UpsertStockLevelParams{
+		LocationID:  int32(req.LocationID),
+		SparepartID: int32(req.SparepartID),
+		MinQuantity: int32(*req.MinQuantity),
+		MaxQuantity: utils.OptionalInt(req.MaxQuantity),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to set stock level", h.logger)
+		return
+	}
+
+	utils.Success(c, "Stock level set successfully", level)
+}
+
+// @Summary Delete stock level
+// @Description Delete a stock level; the sparepart is no longer planned at the location
+// @Tags Stock Level
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock level ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /sparepart/stock-levels/{id} [delete]
+func (h *StockLevelHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid stock level ID")
+		return
+	}
+
+	deleted, err := h.queries.DeleteStockLevel(c.Request.Context(), int32(id))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to delete stock level", h.logger)
+		return
+	}
+	if deleted == 0 {
+		utils.NotFound(c, "Stock level not found")
+		return
+	}
+
+	utils.Success(c, "Stock level deleted successfully", nil)
+}
+
+// @Summary Get stock planning report
+// @Description Get the spareparts whose available stock (NEW_STOCK and USED_STOCK) at a location is below the minimum level set for them there, with the quantity to order up to the maximum (or the minimum without one), or above the maximum, with the excess quantity. Only spareparts with a stock level are planned; this is separate from the global low stock alerts.
+// @Tags Stock Level
+// @Accept json
+// @Produce json
+// @Param status query string false "Only one side of the range (BELOW_MIN, ABOVE_MAX)"
+// @Param location_id query int false "Filter by location ID"
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param region query string false "Filter by region (exact match)"
+// @Success 200 {object} utils.Response{data=[]StockLevelPlanningItem}
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/stock-levels/report [get]
+func (h *StockLevelHandler) GetReport(c *gin.Context) {
+	params := sqlcdb.ListStockLevelPlanningParams{
+		Region: utils.TextFilter(c.Query("region")),
+		Status: utils.TextFilter(c.Query("status")),
+	}
+	errs := parseIDFilters(c, &params.SparepartID, &params.LocationID)
+	if params.Status.Valid && params.Status.String != stockLevelBelowMin && params.Status.String != stockLevelAboveMax {
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of BELOW_MIN, ABOVE_MAX"})
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	rows, err := h.queries.ListStockLevelPlanning(c.Request.Context(), params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock planning report", h.logger)
+		return
+	}
+
+	items := make([]StockLevelPlanningItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toStockLevelPlanningItem(row))
+	}
+
+	utils.Success(c, "Stock planning report retrieved successfully", items)
+}
+
+func toStockLevelPlanningItem(row sqlcdb.ListStockLevelPlanningRow) StockLevelPlanningItem {
+	item := StockLevelPlanningItem{
+		StockLevelID: row.ID,
+		Location: SparepartStockLocation{
+			ID:      row.LocationID,
+			Region:  string(row.Region),
+			Regency: row.Regency,
+			Cluster: row.Cluster,
+		},
+		SparepartID:   row.SparepartID,
+		SparepartName: row.SparepartName,
+		Unit:          row.Unit,
+		MinQuantity:   row.MinQuantity,
+		Quantity:      row.Quantity,
+	}
+	if row.MaxQuantity.Valid {
+		item.MaxQuantity = &row.MaxQuantity.Int32
+	}
+
+	if row.Quantity < int64(row.MinQuantity) {
+		item.Status = stockLevelBelowMin
+		target := int64(row.MinQuantity)
+		if row.MaxQuantity.Valid {
+			target = int64(row.MaxQuantity.Int32)
+		}
+		item.OrderQuantity = target - row.Quantity
+	} else {
+		item.Status = stockLevelAboveMax
+		// The report only lists levels with a maximum above it
+		item.ExcessQuantity = row.Quantity - int64(row.MaxQuantity.Int32)
+	}
+	return item
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStockLevelHandlerSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockLevelRepository(ctrl)
+	h := NewStockLevelHandler(repo, testLogger)
+
+	params := sqlcdb.UpsertStockLevelParams{
+		LocationID:  4,
+		SparepartID: 9,
+		MinQuantity: 0,
+		MaxQuantity: pgtype.Int4{Int32: 6, Valid: true},
+	}
+	repo.EXPECT().UpsertStockLevel(gomock.Any(), params).Return(sqlcdb.StockLevel{ID: 2, LocationID: 4, SparepartID: 9, MaxQuantity: params.MaxQuantity}, nil)
+
+	w := performRequest(http.MethodPut, "/stock-levels", h.Set, "/stock-levels", `{"location_id":4,"sparepart_id":9,"min_quantity":0,"max_quantity":6}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStockLevelHandlerSetValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockLevelRepository(ctrl)
+	h := NewStockLevelHandler(repo, testLogger)
+
+	for body, field := range map[string]string{
+		`{"location_id":4,"sparepart_id":9}`:                                    "min_quantity",
+		`{"location_id":4,"sparepart_id":9,"min_quantity":-1}`:                  "min_quantity",
+		`{"location_id":4,"sparepart_id":9,"min_quantity":5,"max_quantity":2}`:  "max_quantity",
+		`{"location_id":0,"sparepart_id":9,"min_quantity":5,"max_quantity":10}`: "location_id",
+	} {
+		w := performRequest(http.MethodPut, "/stock-levels", h.Set, "/stock-levels", body)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", body, w.Code, w.Body.String())
+		}
+		if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != field {
+			t.Fatalf("%s: expected %s field error, got %+v", body, field, resp.Errors)
+		}
+	}
+}
+
+func TestStockLevelHandlerDeleteNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockLevelRepository(ctrl)
+	h := NewStockLevelHandler(repo, testLogger)
+
+	repo.EXPECT().DeleteStockLevel(gomock.Any(), int32(7)).Return(int64(0), nil)
+
+	w := performRequest(http.MethodDelete, "/stock-levels/:id", h.Delete, "/stock-levels/7", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStockLevelHandlerGetReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockLevelRepository(ctrl)
+	h := NewStockLevelHandler(repo, testLogger)
+
+	repo.EXPECT().ListStockLevelPlanning(gomock.Any(), sqlcdb.ListStockLevelPlanningParams{
+		Region: pgtype.Text{String: "MALUKU", Valid: true},
+	}).Return([]sqlcdb.ListStockLevelPlanningRow{
+		// Saumlaki keeps a small stock, below its minimum
+		{ID: 1, LocationID: 4, Regency: "Kepulauan Tanimbar", Cluster: "Saumlaki", SparepartID: 9, MinQuantity: 3, MaxQuantity: pgtype.Int4{Int32: 5, Valid: true}, Quantity: 1},
+		{ID: 2, LocationID: 4, Regency: "Kepulauan Tanimbar", Cluster: "Saumlaki", SparepartID: 10, MinQuantity: 2, Quantity: 0},
+		{ID: 3, LocationID: 5, Regency: "Kota Ambon", Cluster: "Ambon", SparepartID: 9, MinQuantity: 10, MaxQuantity: pgtype.Int4{Int32: 20, Valid: true}, Quantity: 26},
+	}, nil)
+
+	w := performRequest(http.MethodGet, "/stock-levels/report", h.GetReport, "/stock-levels/report?region=MALUKU", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var items []StockLevelPlanningItem
+	decodeResponse(t, w, &items)
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	if items[0].Status != "BELOW_MIN" || items[0].OrderQuantity != 4 {
+		t.Fatalf("expected order up to the maximum, got %+v", items[0])
+	}
+	if items[1].Status != "BELOW_MIN" || items[1].OrderQuantity != 2 || items[1].MaxQuantity != nil {
+		t.Fatalf("expected order up to the minimum without a maximum, got %+v", items[1])
+	}
+	if items[2].Status != "ABOVE_MAX" || items[2].ExcessQuantity != 6 || items[2].OrderQuantity != 0 {
+		t.Fatalf("expected excess above the maximum, got %+v", items[2])
+	}
+}
+
+func TestStockLevelHandlerGetReportInvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockLevelRepository(ctrl)
+	h := NewStockLevelHandler(repo, testLogger)
+
+	w := performRequest(http.MethodGet, "/stock-levels/report", h.GetReport, "/stock-levels/report?status=LOW", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != "status" {
+		t.Fatalf("expected status field error, got %+v", resp.Errors)
+	}
+}
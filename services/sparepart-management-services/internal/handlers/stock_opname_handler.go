@@ -0,0 +1,559 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Stock opname statuses; a session moves from DRAFT to SUBMITTED to APPROVED
+const (
+	opnameStatusDraft     = "DRAFT"
+	opnameStatusSubmitted = "SUBMITTED"
+	opnameStatusApproved  = "APPROVED"
+)
+
+// Errors ending an opname transaction early; the response is written after the rollback
+var (
+	errOpnameNotFound      = errors.New("stock opname not found")
+	errOpnameStatus        = errors.New("stock opname has the wrong status")
+	errOpnameInvalidCounts = errors.New("stock opname counts are invalid")
+)
+
+// CreateStockOpnameRequest opens a stock opname session at a location
+type CreateStockOpnameRequest struct {
+	LocationID int     `json:"location_id" binding:"required,min=1"`
+	Notes      *string `json:"notes"`
+}
+
+// StockOpnameCountRequest is the counted quantity of one sparepart and stock type
+type StockOpnameCountRequest struct {
+	SparepartID     int              `json:"sparepart_id" binding:"required,min=1"`
+	StockType       models.StockType `json:"stock_type" binding:"required,oneof=NEW_STOCK USED_STOCK DAMAGED IN_REPAIR RESERVED"`
+	CountedQuantity *int             `json:"counted_quantity" binding:"required,min=0"`
+}
+
+// RecordStockOpnameCountsRequest records counts in a DRAFT session; a sparepart and stock type
+// counted again replaces its earlier count
+type RecordStockOpnameCountsRequest struct {
+	Items []StockOpnameCountRequest `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+// StockOpnameItemResponse is a recorded count; Variance is the counted minus the system quantity
+type StockOpnameItemResponse struct {
+	ID              int32   `json:"id"`
+	SparepartID     int32   `json:"sparepart_id"`
+	SparepartName   *string `json:"sparepart_name,omitempty"`
+	StockType       string  `json:"stock_type"`
+	SystemQuantity  int32   `json:"system_quantity"`
+	CountedQuantity int32   `json:"counted_quantity"`
+	Variance        int32   `json:"variance"`
+	StockItemID     *int32  `json:"stock_item_id,omitempty"`
+	QuantityAfter   *int32  `json:"quantity_after,omitempty"`
+	CountedAt       string  `json:"counted_at"`
+}
+
+// StockOpnameResponse is a stock opname session
+type StockOpnameResponse struct {
+	ID          int32   `json:"id"`
+	LocationID  int32   `json:"location_id"`
+	Region      *string `json:"region,omitempty"`
+	Regency     *string `json:"regency,omitempty"`
+	Cluster     *string `json:"cluster,omitempty"`
+	Status      string  `json:"status"`
+	Notes       *string `json:"notes"`
+	CreatedBy   *string `json:"created_by"`
+	SubmittedBy *string `json:"submitted_by"`
+	SubmittedAt string  `json:"submitted_at,omitempty"`
+	ApprovedBy  *string `json:"approved_by"`
+	ApprovedAt  string  `json:"approved_at,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// StockOpnameDetailResponse is a stock opname session with its counts
+type StockOpnameDetailResponse struct {
+	StockOpnameResponse
+	Items []StockOpnameItemResponse `json:"items"`
+}
+
+// StockOpnameHandler runs stock opname (physical count) sessions: counts are recorded
+// against the system quantities, and approving a submitted session adjusts the stock by
+// every variance
+type StockOpnameHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+}
+
+func NewStockOpnameHandler(queries repository.SparepartStockRepository, logger *zap.Logger) *StockOpnameHandler {
+	return &StockOpnameHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Create stock opname
+// @Description Open a DRAFT stock opname session at a location
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Param opname body CreateStockOpnameRequest true "Opname data"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/opname [post]
+func (h *StockOpnameHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateStockOpnameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	opname, err := h.queries.CreateStockOpname(ctx, sqlcdb.CreateStockOpnameParams{
+		LocationID: int32(req.LocationID),
+		Notes:      utils.OptionalText(req.Notes),
+		CreatedBy:  utils.TextFilter(utils.UserID(c)),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.NotFound(c, "Location not found")
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create stock opname", h.logger)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Stock opname created successfully",
+		Data: StockOpnameDetailResponse{
+			StockOpnameResponse: toStockOpnameResponse(sqlcdb.GetStockOpnameRow{
+				ID:         opname.ID,
+				LocationID: opname.LocationID,
+				Status:     opname.Status,
+				Notes:      opname.Notes,
+				CreatedBy:  opname.CreatedBy,
+				CreatedAt:  opname.CreatedAt,
+				UpdatedAt:  opname.UpdatedAt,
+			}),
+			Items: []StockOpnameItemResponse{},
+		},
+	})
+}
+
+// @Summary Get stock opnames
+// @Description Get the stock opname sessions, newest first
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Param location_id query int false "Filter by location"
+// @Param status query string false "Filter by status (DRAFT, SUBMITTED, APPROVED)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/opname [get]
+func (h *StockOpnameHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	var filters sqlcdb.CountStockOpnamesParams
+	if value := c.Query("location_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "location_id", Message: "must be a positive integer"})
+		} else {
+			filters.LocationID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	switch status := c.Query("status"); status {
+	case "":
+	case opnameStatusDraft, opnameStatusSubmitted, opnameStatusApproved:
+		filters.Status = utils.TextFilter(status)
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of DRAFT, SUBMITTED, APPROVED"})
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountStockOpnames(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count stock opnames", h.logger)
+		return
+	}
+
+	opnames, err := h.queries.ListStockOpnames(ctx, sqlcdb.ListStockOpnamesParams{
+		LocationID: filters.LocationID,
+		Status:     filters.Status,
+		Limit:      int32(pagination.Limit),
+		Offset:     int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock opnames", h.logger)
+		return
+	}
+
+	response := make([]StockOpnameResponse, 0, len(opnames))
+	for _, opname := range opnames {
+		response = append(response, toStockOpnameResponse(sqlcdb.GetStockOpnameRow(opname)))
+	}
+
+	utils.SuccessWithPagination(c, "Stock opnames retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get stock opname
+// @Description Get a stock opname session with its counts and their variances
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock opname ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/opname/{id} [get]
+func (h *StockOpnameHandler) GetByID(c *gin.Context) {
+	id, ok := parseOpnameID(c)
+	if !ok {
+		return
+	}
+	h.respond(c, id, "Stock opname retrieved successfully")
+}
+
+// @Summary Record stock opname counts
+// @Description Record counted quantities in a DRAFT session; each count is stored with the location's current system quantity, and counting a sparepart and stock type again replaces its count
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock opname ID"
+// @Param counts body RecordStockOpnameCountsRequest true "Counted quantities"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/opname/{id}/items [put]
+func (h *StockOpnameHandler) RecordCounts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseOpnameID(c)
+	if !ok {
+		return
+	}
+	var req RecordStockOpnameCountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	var status string
+	var errs []utils.FieldError
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		opname, err := lockOpname(ctx, repo, id, opnameStatusDraft)
+		status = opname.Status
+		if err != nil {
+			return err
+		}
+
+		for i, item := range req.Items {
+			_, err := repo.UpsertStockOpnameItem(ctx, sqlcdb.UpsertStockOpnameItemParams{
+				OpnameID:        id,
+				SparepartID:     int32(item.SparepartID),
+				StockType:       sqlcdb.StockType(item.StockType),
+				CountedQuantity: int32(*item.CountedQuantity),
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d].sparepart_id", i), Message: "does not exist"})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if len(errs) > 0 {
+			return errOpnameInvalidCounts
+		}
+		return nil
+	})
+	if !h.handleTransactionError(c, err, status, errs, "Only DRAFT stock opnames can be counted", "Failed to record stock opname counts") {
+		return
+	}
+
+	h.respond(c, id, "Stock opname counts recorded successfully")
+}
+
+// @Summary Submit stock opname
+// @Description Submit a DRAFT session with at least one count for approval; its counts can no longer change
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock opname ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/opname/{id}/submit [post]
+func (h *StockOpnameHandler) Submit(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseOpnameID(c)
+	if !ok {
+		return
+	}
+
+	var status string
+	var errs []utils.FieldError
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		opname, err := lockOpname(ctx, repo, id, opnameStatusDraft)
+		status = opname.Status
+		if err != nil {
+			return err
+		}
+
+		items, err := repo.ListStockOpnameItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			errs = append(errs, utils.FieldError{Field: "items", Message: "record at least one count before submitting"})
+			return errOpnameInvalidCounts
+		}
+
+		_, err = repo.SubmitStockOpname(ctx, sqlcdb.SubmitStockOpnameParams{
+			ID:          id,
+			SubmittedBy: utils.TextFilter(utils.UserID(c)),
+		})
+		return err
+	})
+	if !h.handleTransactionError(c, err, status, errs, "Only DRAFT stock opnames can be submitted", "Failed to submit stock opname") {
+		return
+	}
+
+	h.respond(c, id, "Stock opname submitted successfully")
+}
+
+// @Summary Approve stock opname
+// @Description Approve a SUBMITTED session, adding every count's variance to the location's current stock (stock items are created when missing); the changes are recorded in the stock ledger. Fails when a variance would take a stock item below zero.
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock opname ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/opname/{id}/approve [post]
+func (h *StockOpnameHandler) Approve(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseOpnameID(c)
+	if !ok {
+		return
+	}
+
+	var status string
+	var errs []utils.FieldError
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		opname, err := lockOpname(ctx, repo, id, opnameStatusSubmitted)
+		status = opname.Status
+		if err != nil {
+			return err
+		}
+
+		items, err := repo.ListStockOpnameItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		for i, item := range items {
+			variance := item.CountedQuantity - item.SystemQuantity
+			if variance == 0 {
+				continue
+			}
+
+			// The variance is applied to the current quantity, so stock moved since the count
+			// is kept; a deleted or missing stock item holds nothing
+			var current int32
+			stock, err := repo.GetSparepartStockByKeyForUpdate(ctx, sqlcdb.GetSparepartStockByKeyForUpdateParams{
+				LocationID:  opname.LocationID,
+				SparepartID: item.SparepartID,
+				StockType:   item.StockType,
+			})
+			switch {
+			case err == nil:
+				current = stock.Quantity
+			case !errors.Is(err, pgx.ErrNoRows):
+				return err
+			}
+			if current+variance < 0 {
+				errs = append(errs, utils.FieldError{
+					Field:   fmt.Sprintf("items[%d].counted_quantity", i),
+					Message: fmt.Sprintf("variance of %d exceeds the %d currently in stock", variance, current),
+				})
+				continue
+			}
+			// Once one count fails the transaction is rolled back; the rest are only checked
+			if len(errs) > 0 {
+				continue
+			}
+
+			adjusted, err := repo.AdjustSparepartStock(ctx, sqlcdb.AdjustSparepartStockParams{
+				LocationID:  opname.LocationID,
+				SparepartID: item.SparepartID,
+				StockType:   item.StockType,
+				Quantity:    variance,
+			})
+			if err != nil {
+				return err
+			}
+			err = repo.SetStockOpnameItemAdjustment(ctx, sqlcdb.SetStockOpnameItemAdjustmentParams{
+				ID:            item.ID,
+				StockItemID:   pgtype.Int4{Int32: adjusted.ID, Valid: true},
+				QuantityAfter: pgtype.Int4{Int32: adjusted.Quantity, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(errs) > 0 {
+			return errOpnameInvalidCounts
+		}
+
+		_, err = repo.ApproveStockOpname(ctx, sqlcdb.ApproveStockOpnameParams{
+			ID:         id,
+			ApprovedBy: utils.TextFilter(utils.UserID(c)),
+		})
+		return err
+	})
+	if !h.handleTransactionError(c, err, status, errs, "Only SUBMITTED stock opnames can be approved", "Failed to approve stock opname") {
+		return
+	}
+
+	h.respond(c, id, "Stock opname approved successfully")
+}
+
+// lockOpname locks the session for the rest of the transaction, failing unless it has status
+func lockOpname(ctx context.Context, repo repository.SparepartStockRepository, id int32, status string) (sqlcdb.StockOpname, error) {
+	opname, err := repo.GetStockOpnameForUpdate(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return opname, errOpnameNotFound
+	}
+	if err != nil {
+		return opname, err
+	}
+	if opname.Status != status {
+		return opname, errOpnameStatus
+	}
+	return opname, nil
+}
+
+// handleTransactionError writes the response for an opname transaction that failed,
+// reporting whether it succeeded instead
+func (h *StockOpnameHandler) handleTransactionError(c *gin.Context, err error, status string, errs []utils.FieldError, statusMessage, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errOpnameNotFound):
+		utils.NotFound(c, "Stock opname not found")
+	case errors.Is(err, errOpnameStatus):
+		utils.Error(c, fmt.Sprintf("%s; this one is %s", statusMessage, status), http.StatusConflict)
+	case errors.Is(err, errOpnameInvalidCounts):
+		utils.ValidationError(c, errs...)
+	default:
+		utils.HandleError(c, err, message, h.logger)
+	}
+	return false
+}
+
+// respond writes the session with its counts
+func (h *StockOpnameHandler) respond(c *gin.Context, id int32, message string) {
+	ctx := c.Request.Context()
+
+	opname, err := h.queries.GetStockOpname(ctx, id)
+	if err != nil {
+		utils.NotFound(c, "Stock opname not found")
+		return
+	}
+
+	items, err := h.queries.ListStockOpnameItems(ctx, id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock opname items", h.logger)
+		return
+	}
+
+	response := StockOpnameDetailResponse{
+		StockOpnameResponse: toStockOpnameResponse(opname),
+		Items:               make([]StockOpnameItemResponse, 0, len(items)),
+	}
+	for _, item := range items {
+		response.Items = append(response.Items, toStockOpnameItemResponse(item))
+	}
+	utils.Success(c, message, response)
+}
+
+func parseOpnameID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid stock opname ID")
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func toStockOpnameResponse(row sqlcdb.GetStockOpnameRow) StockOpnameResponse {
+	response := StockOpnameResponse{
+		ID:          row.ID,
+		LocationID:  row.LocationID,
+		Status:      row.Status,
+		SubmittedAt: utils.FormatTimestamp(row.SubmittedAt),
+		ApprovedAt:  utils.FormatTimestamp(row.ApprovedAt),
+		CreatedAt:   utils.FormatTimestamp(row.CreatedAt),
+		UpdatedAt:   utils.FormatTimestamp(row.UpdatedAt),
+	}
+	if row.Region.Valid {
+		region := string(row.Region.RegionType)
+		response.Region = &region
+	}
+	if row.Regency.Valid {
+		response.Regency = &row.Regency.String
+	}
+	if row.Cluster.Valid {
+		response.Cluster = &row.Cluster.String
+	}
+	if row.Notes.Valid {
+		response.Notes = &row.Notes.String
+	}
+	if row.CreatedBy.Valid {
+		response.CreatedBy = &row.CreatedBy.String
+	}
+	if row.SubmittedBy.Valid {
+		response.SubmittedBy = &row.SubmittedBy.String
+	}
+	if row.ApprovedBy.Valid {
+		response.ApprovedBy = &row.ApprovedBy.String
+	}
+	return response
+}
+
+func toStockOpnameItemResponse(row sqlcdb.ListStockOpnameItemsRow) StockOpnameItemResponse {
+	response := StockOpnameItemResponse{
+		ID:              row.ID,
+		SparepartID:     row.SparepartID,
+		StockType:       string(row.StockType),
+		SystemQuantity:  row.SystemQuantity,
+		CountedQuantity: row.CountedQuantity,
+		Variance:        row.CountedQuantity - row.SystemQuantity,
+		CountedAt:       utils.FormatTimestamp(row.CountedAt),
+	}
+	if row.SparepartName.Valid {
+		response.SparepartName = &row.SparepartName.String
+	}
+	if row.StockItemID.Valid {
+		response.StockItemID = &row.StockItemID.Int32
+	}
+	if row.QuantityAfter.Valid {
+		response.QuantityAfter = &row.QuantityAfter.Int32
+	}
+	return response
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStockOpnameHandlerCreateUnknownLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockOpnameHandler(repo, testLogger)
+
+	repo.EXPECT().CreateStockOpname(gomock.Any(), gomock.Any()).Return(sqlcdb.StockOpname{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/opname", h.Create, "/opname", `{"location_id": 99}`)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStockOpnameHandlerRecordCountsUnknownSparepart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockOpnameHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetStockOpnameForUpdate(gomock.Any(), int32(3)).Return(sqlcdb.StockOpname{ID: 3, LocationID: 1, Status: opnameStatusDraft}, nil)
+	repo.EXPECT().UpsertStockOpnameItem(gomock.Any(), sqlcdb.UpsertStockOpnameItemParams{OpnameID: 3, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, CountedQuantity: 4}).
+		Return(sqlcdb.StockOpnameItem{ID: 1}, nil)
+	repo.EXPECT().UpsertStockOpnameItem(gomock.Any(), gomock.Any()).Return(sqlcdb.StockOpnameItem{}, pgx.ErrNoRows)
+
+	body := `{"items": [{"sparepart_id": 7, "stock_type": "NEW_STOCK", "counted_quantity": 4}, {"sparepart_id": 99, "stock_type": "USED_STOCK", "counted_quantity": 0}]}`
+	w := performRequest(http.MethodPut, "/opname/:id/items", h.RecordCounts, "/opname/3/items", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "items[1].sparepart_id" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestStockOpnameHandlerSubmitRequiresDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockOpnameHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetStockOpnameForUpdate(gomock.Any(), int32(3)).Return(sqlcdb.StockOpname{ID: 3, Status: opnameStatusApproved}, nil)
+
+	w := performRequest(http.MethodPost, "/opname/:id/submit", h.Submit, "/opname/3/submit", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStockOpnameHandlerApprove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockOpnameHandler(repo, testLogger)
+
+	items := []sqlcdb.ListStockOpnameItemsRow{
+		{ID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, SystemQuantity: 5, CountedQuantity: 3},
+		{ID: 2, SparepartID: 8, StockType: sqlcdb.StockTypeNEWSTOCK, SystemQuantity: 2, CountedQuantity: 2},
+		{ID: 3, SparepartID: 9, StockType: sqlcdb.StockTypeUSEDSTOCK, SystemQuantity: 0, CountedQuantity: 1},
+	}
+	expectTransaction(repo)
+	repo.EXPECT().GetStockOpnameForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.StockOpname{ID: 4, LocationID: 1, Status: opnameStatusSubmitted}, nil)
+	repo.EXPECT().ListStockOpnameItems(gomock.Any(), int32(4)).Return(items, nil)
+
+	// Stock taken since the count is kept: 6 in stock now, 2 short in the count
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), sqlcdb.GetSparepartStockByKeyForUpdateParams{LocationID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK}).
+		Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 6}, nil)
+	repo.EXPECT().AdjustSparepartStock(gomock.Any(), sqlcdb.AdjustSparepartStockParams{LocationID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: -2}).
+		Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 4}, nil)
+	repo.EXPECT().SetStockOpnameItemAdjustment(gomock.Any(), sqlcdb.SetStockOpnameItemAdjustmentParams{
+		ID: 1, StockItemID: pgtype.Int4{Int32: 10, Valid: true}, QuantityAfter: pgtype.Int4{Int32: 4, Valid: true},
+	}).Return(nil)
+
+	// A sparepart the location had no stock item for gets one
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), sqlcdb.GetSparepartStockByKeyForUpdateParams{LocationID: 1, SparepartID: 9, StockType: sqlcdb.StockTypeUSEDSTOCK}).
+		Return(sqlcdb.SparepartStockItem{}, pgx.ErrNoRows)
+	repo.EXPECT().AdjustSparepartStock(gomock.Any(), sqlcdb.AdjustSparepartStockParams{LocationID: 1, SparepartID: 9, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 1}).
+		Return(sqlcdb.SparepartStockItem{ID: 11, Quantity: 1}, nil)
+	repo.EXPECT().SetStockOpnameItemAdjustment(gomock.Any(), gomock.Any()).Return(nil)
+
+	repo.EXPECT().ApproveStockOpname(gomock.Any(), sqlcdb.ApproveStockOpnameParams{ID: 4, ApprovedBy: pgtype.Text{String: "budi", Valid: true}}).
+		Return(sqlcdb.StockOpname{ID: 4, Status: opnameStatusApproved}, nil)
+	repo.EXPECT().GetStockOpname(gomock.Any(), int32(4)).Return(sqlcdb.GetStockOpnameRow{ID: 4, LocationID: 1, Status: opnameStatusApproved}, nil)
+	repo.EXPECT().ListStockOpnameItems(gomock.Any(), int32(4)).Return(items, nil)
+
+	w := performRequestAs("budi", http.MethodPost, "/opname/:id/approve", h.Approve, "/opname/4/approve", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var opname StockOpnameDetailResponse
+	decodeResponse(t, w, &opname)
+	if opname.Status != opnameStatusApproved || len(opname.Items) != 3 || opname.Items[0].Variance != -2 {
+		t.Fatalf("unexpected opname response: %+v", opname)
+	}
+}
+
+func TestStockOpnameHandlerApproveRejectsNegativeStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockOpnameHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetStockOpnameForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.StockOpname{ID: 4, LocationID: 1, Status: opnameStatusSubmitted}, nil)
+	repo.EXPECT().ListStockOpnameItems(gomock.Any(), int32(4)).Return([]sqlcdb.ListStockOpnameItemsRow{
+		{ID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, SystemQuantity: 5, CountedQuantity: 1},
+	}, nil)
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), gomock.Any()).Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 2}, nil)
+
+	w := performRequest(http.MethodPost, "/opname/:id/approve", h.Approve, "/opname/4/approve", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "items[0].counted_quantity" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
@@ -0,0 +1,715 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// maxTrendPoints bounds the number of buckets a trend request may span
+const maxTrendPoints = 366
+
+// trendIntervals maps the interval query param to its default range, counted in buckets
+var trendIntervals = map[string]int{
+	"day":   30,
+	"week":  26,
+	"month": 12,
+}
+
+// StockTrendPoint is the stock quantity at the end of a period plus the movements within it
+type StockTrendPoint struct {
+	Period    string `json:"period"`
+	Quantity  int64  `json:"quantity"`
+	Restocked int64  `json:"restocked"`
+	Consumed  int64  `json:"consumed"`
+}
+
+// StockTrendSeries is the quantity-over-time series of one location (cluster)
+type StockTrendSeries struct {
+	Location SparepartStockLocation `json:"location"`
+	Points   []StockTrendPoint      `json:"points"`
+}
+
+// ReorderSuggestion is the reorder quantity for one sparepart at one location: enough to
+// cover the average monthly consumption for the coverage period on top of the current stock
+type ReorderSuggestion struct {
+	Location           SparepartStockLocation `json:"location"`
+	SparepartID        int32                  `json:"sparepart_id"`
+	SparepartName      string                 `json:"sparepart_name"`
+	CurrentQuantity    int64                  `json:"current_quantity"`
+	Consumed           int64                  `json:"consumed"`
+	MonthlyConsumption float64                `json:"monthly_consumption"`
+	TargetQuantity     int64                  `json:"target_quantity"`
+	SuggestedQuantity  int64                  `json:"suggested_quantity"`
+}
+
+// ReorderSuggestions lists the suggestions along with the periods they were computed for
+type ReorderSuggestions struct {
+	LookbackMonths int                 `json:"lookback_months"`
+	CoverageMonths int                 `json:"coverage_months"`
+	Since          string              `json:"since"`
+	Items          []ReorderSuggestion `json:"items"`
+}
+
+// StockMovementReceipt is the goods receipt, and so the supplier, a stock increase came from
+type StockMovementReceipt struct {
+	ID                  int32  `json:"id"`
+	Code                string `json:"code"`
+	DeliveryOrderNumber string `json:"delivery_order_number"`
+	SupplierID          int32  `json:"supplier_id"`
+	Supplier            string `json:"supplier"`
+}
+
+// StockMovementDisposal is the disposal a stock decrease was written off by
+type StockMovementDisposal struct {
+	ID         int32  `json:"id"`
+	Reason     string `json:"reason"`
+	ApprovedBy string `json:"approved_by"`
+}
+
+// StockMovementWorkOrder is the work order whose closing consumed a stock decrease
+type StockMovementWorkOrder struct {
+	ID    int32  `json:"id"`
+	Title string `json:"title"`
+}
+
+// Stock movement types, for the movements attributed to a goods receipt, a disposal or a
+// work order
+const (
+	movementTypeReceipt   = "RECEIPT"
+	movementTypeDisposal  = "DISPOSAL"
+	movementTypeWorkOrder = "WORK_ORDER"
+)
+
+// StockMovement is one change of a stock item's quantity, as recorded in the stock ledger
+type StockMovement struct {
+	ID             int64                   `json:"id"`
+	StockItemID    int32                   `json:"stock_item_id"`
+	Location       SparepartStockLocation  `json:"location"`
+	SparepartID    int32                   `json:"sparepart_id"`
+	SparepartName  string                  `json:"sparepart_name"`
+	StockType      string                  `json:"stock_type"`
+	QuantityChange int32                   `json:"quantity_change"`
+	QuantityAfter  int32                   `json:"quantity_after"`
+	RecordedAt     string                  `json:"recorded_at"`
+	Type           string                  `json:"type,omitempty"`
+	Receipt        *StockMovementReceipt   `json:"receipt,omitempty"`
+	Disposal       *StockMovementDisposal  `json:"disposal,omitempty"`
+	WorkOrder      *StockMovementWorkOrder `json:"work_order,omitempty"`
+}
+
+// StockValuationTotals is the value of a stock and what it covers
+type StockValuationTotals struct {
+	ItemCount  int64   `json:"item_count"`
+	Quantity   int64   `json:"quantity"`
+	TotalValue float64 `json:"total_value"`
+	// Stock items valued at zero since neither they nor their sparepart have a unit cost
+	ItemsWithoutCost int64 `json:"items_without_cost"`
+}
+
+// add sums other into t, rounding the value to cents
+func (t *StockValuationTotals) add(other StockValuationTotals) {
+	t.ItemCount += other.ItemCount
+	t.Quantity += other.Quantity
+	t.TotalValue = math.Round((t.TotalValue+other.TotalValue)*100) / 100
+	t.ItemsWithoutCost += other.ItemsWithoutCost
+}
+
+// StockValuationLocation is the value of the stock held at one location
+type StockValuationLocation struct {
+	Location SparepartStockLocation `json:"location"`
+	StockValuationTotals
+}
+
+// StockValuationRegion is the value of the stock held in a region, per location
+type StockValuationRegion struct {
+	Region string `json:"region"`
+	StockValuationTotals
+	Locations []StockValuationLocation `json:"locations"`
+}
+
+// StockValuation is the value of the inventory, per region and location
+type StockValuation struct {
+	StockValuationTotals
+	Regions []StockValuationRegion `json:"regions"`
+}
+
+// StockSummaryHandler serves dashboard aggregates from the stock summary materialized views.
+// The views are refreshed periodically, so totals may lag behind the latest writes.
+type StockSummaryHandler struct {
+	logger  *zap.Logger
+	queries repository.StockSummaryRepository
+	reports *reports.Store
+}
+
+func NewStockSummaryHandler(queries repository.StockSummaryRepository, reports *reports.Store, logger *zap.Logger) *StockSummaryHandler {
+	return &StockSummaryHandler{
+		logger:  logger,
+		queries: queries,
+		reports: reports,
+	}
+}
+
+// @Summary Get stock summary per location
+// @Description Get new/used/total stock quantities per location
+// @Tags Stock Summary
+// @Accept json
+// @Produce json
+// @Param region query string false "Filter by region (exact match)"
+// @Param regency query string false "Filter by regency (partial match, case-insensitive)"
+// @Param cluster query string false "Filter by cluster (partial match, case-insensitive)"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/summary/location [get]
+func (h *StockSummaryHandler) GetByLocation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params := sqlcdb.ListStockSummaryByLocationParams{
+		Region:  utils.TextFilter(c.Query("region")),
+		Regency: utils.TextFilter(c.Query("regency")),
+		Cluster: utils.TextFilter(c.Query("cluster")),
+	}
+	summary, err := h.queries.ListStockSummaryByLocation(ctx, params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock summary by location", h.logger)
+		return
+	}
+
+	utils.Success(c, "Stock summary by location retrieved successfully", summary)
+}
+
+// @Summary Get stock summary per sparepart
+// @Description Get new/used/total stock quantities per sparepart across all locations
+// @Tags Stock Summary
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/summary/sparepart [get]
+func (h *StockSummaryHandler) GetBySparepart(c *gin.Context) {
+	summary, err := h.queries.ListStockSummaryBySparepart(c.Request.Context())
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock summary by sparepart", h.logger)
+		return
+	}
+
+	utils.Success(c, "Stock summary by sparepart retrieved successfully", summary)
+}
+
+// @Summary Get stock summary per region
+// @Description Get new/used/total stock quantities per region
+// @Tags Stock Summary
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/summary/region [get]
+func (h *StockSummaryHandler) GetByRegion(c *gin.Context) {
+	summary, err := h.queries.ListStockSummaryByRegion(c.Request.Context())
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock summary by region", h.logger)
+		return
+	}
+
+	utils.Success(c, "Stock summary by region retrieved successfully", summary)
+}
+
+// @Summary Get stock quantity trends
+// @Description Get quantity-over-time series per location from the stock ledger, with restocked and consumed quantities per period. Periods without changes are omitted; the quantity carries over. With source=snapshot the series is read from the daily stock snapshots instead: a period's quantity is the total on its last snapshot day, restocked and consumed are day-over-day changes, and periods without snapshots are omitted.
+// @Tags Stock Summary
+// @Accept json
+// @Produce json
+// @Param interval query string false "Bucket size (day, week, month)" default(day)
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param location_id query int false "Filter by location ID"
+// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days, 26 weeks or 12 months before to"
+// @Param to query string false "End date inclusive (YYYY-MM-DD), defaults to today"
+// @Param source query string false "Data source (ledger, snapshot)" default(ledger)
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/trends [get]
+func (h *StockSummaryHandler) GetTrends(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, errs := parseTrendParams(c, time.Now().UTC())
+	source := c.DefaultQuery("source", "ledger")
+	if source != "ledger" && source != "snapshot" {
+		errs = append(errs, utils.FieldError{Field: "source", Message: "must be one of ledger, snapshot"})
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	var rows []sqlcdb.ListStockTrendRow
+	var err error
+	if source == "snapshot" {
+		rows, err = h.listSnapshotTrend(ctx, params)
+	} else {
+		rows, err = h.queries.ListStockTrend(ctx, params)
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock trends", h.logger)
+		return
+	}
+
+	// Rows are ordered by location, then period
+	series := []StockTrendSeries{}
+	for _, row := range rows {
+		if len(series) == 0 || series[len(series)-1].Location.ID != row.LocationID {
+			series = append(series, StockTrendSeries{
+				Location: SparepartStockLocation{
+					ID:      row.LocationID,
+					Region:  string(row.Region),
+					Regency: row.Regency,
+					Cluster: row.Cluster,
+				},
+			})
+		}
+		current := &series[len(series)-1]
+		current.Points = append(current.Points, StockTrendPoint{
+			Period:    row.Period.Time.UTC().Format("2006-01-02"),
+			Quantity:  row.Quantity,
+			Restocked: row.Restocked,
+			Consumed:  row.Consumed,
+		})
+	}
+
+	utils.Success(c, "Stock trends retrieved successfully", series)
+}
+
+// listSnapshotTrend reads the trend from the daily stock snapshots, in the rows of the ledger trend
+func (h *StockSummaryHandler) listSnapshotTrend(ctx context.Context, params sqlcdb.ListStockTrendParams) ([]sqlcdb.ListStockTrendRow, error) {
+	snapshots, err := h.queries.ListStockSnapshotTrend(ctx, sqlcdb.ListStockSnapshotTrendParams{
+		SparepartID: params.SparepartID,
+		LocationID:  params.LocationID,
+		StockType:   params.StockType,
+		Until:       params.Until,
+		Interval:    params.Interval,
+		Since:       params.Since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]sqlcdb.ListStockTrendRow, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		rows = append(rows, sqlcdb.ListStockTrendRow(snapshot))
+	}
+	return rows, nil
+}
+
+// @Summary Get reorder suggestions
+// @Description Estimate the average monthly consumption per location and sparepart from the stock ledger over the lookback period and suggest how much to reorder so the stock covers the coverage period. Consumption is every stock decrease except transfers to other locations; the target quantity is the monthly consumption times coverage_months, rounded up, and the suggested quantity is the target minus the current stock. Locations that are deleted or inactive are left out, as are pairs whose stock already covers the period unless include_covered=true.
+// @Tags Stock Summary
+// @Accept json
+// @Produce json
+// @Param lookback_months query int false "Months of history to average over (1-24)" default(6)
+// @Param coverage_months query int false "Months the reordered stock should last (1-24)" default(3)
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param location_id query int false "Filter by location ID"
+// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
+// @Param include_covered query bool false "Include pairs that need no reorder" default(false)
+// @Success 200 {object} utils.Response{data=ReorderSuggestions}
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/stock/reorder-suggestions [get]
+func (h *StockSummaryHandler) GetReorderSuggestions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	months := map[string]int{"lookback_months": 6, "coverage_months": 3}
+	for _, field := range []string{"lookback_months", "coverage_months"} {
+		if value := c.Query(field); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > 24 {
+				errs = append(errs, utils.FieldError{Field: field, Message: "must be a whole number from 1 to 24"})
+				continue
+			}
+			months[field] = parsed
+		}
+	}
+	params := sqlcdb.ListStockConsumptionParams{StockType: utils.TextFilter(c.Query("stock_type"))}
+	errs = append(errs, parseIDFilters(c, &params.SparepartID, &params.LocationID)...)
+	var includeCovered bool
+	if value := c.Query("include_covered"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "include_covered", Message: "must be true or false"})
+		}
+		includeCovered = parsed
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	lookback, coverage := months["lookback_months"], months["coverage_months"]
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, -lookback, 0)
+	params.Since = pgtype.Timestamptz{Time: since, Valid: true}
+
+	rows, err := h.queries.ListStockConsumption(ctx, params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock consumption", h.logger)
+		return
+	}
+
+	response := ReorderSuggestions{
+		LookbackMonths: lookback,
+		CoverageMonths: coverage,
+		Since:          since.Format("2006-01-02"),
+		Items:          []ReorderSuggestion{},
+	}
+	for _, row := range rows {
+		monthly := float64(row.Consumed) / float64(lookback)
+		target := int64(math.Ceil(monthly * float64(coverage)))
+		suggested := target - row.CurrentQuantity
+		if suggested <= 0 {
+			if !includeCovered {
+				continue
+			}
+			suggested = 0
+		}
+		response.Items = append(response.Items, ReorderSuggestion{
+			Location: SparepartStockLocation{
+				ID:      row.LocationID,
+				Region:  string(row.Region),
+				Regency: row.Regency,
+				Cluster: row.Cluster,
+			},
+			SparepartID:        row.SparepartID,
+			SparepartName:      row.SparepartName,
+			CurrentQuantity:    row.CurrentQuantity,
+			Consumed:           row.Consumed,
+			MonthlyConsumption: math.Round(monthly*100) / 100,
+			TargetQuantity:     target,
+			SuggestedQuantity:  suggested,
+		})
+	}
+
+	utils.Success(c, "Reorder suggestions retrieved successfully", response)
+}
+
+// @Summary Get inventory valuation
+// @Description Get the value of the stock per region and location: every stock item's quantity at its own unit cost, or at its sparepart's when it has none. Items without either are valued at zero and counted in items_without_cost. Read live from the stock, not from the summary views.
+// @Tags Stock Summary
+// @Accept json
+// @Produce json
+// @Param region query string false "Filter by region (exact match)"
+// @Param regency query string false "Filter by regency (partial match, case-insensitive)"
+// @Param cluster query string false "Filter by cluster (partial match, case-insensitive)"
+// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
+// @Success 200 {object} utils.Response{data=StockValuation}
+// @Router /sparepart/stock/valuation [get]
+func (h *StockSummaryHandler) GetValuation(c *gin.Context) {
+	rows, err := h.queries.ListStockValuation(c.Request.Context(), stockValuationParams(c))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock valuation", h.logger)
+		return
+	}
+
+	response := StockValuation{Regions: []StockValuationRegion{}}
+	for _, row := range rows {
+		totals := StockValuationTotals{
+			ItemCount:        row.ItemCount,
+			Quantity:         row.Quantity,
+			ItemsWithoutCost: row.ItemsWithoutCost,
+		}
+		if value := utils.NumericValue(row.TotalValue); value != nil {
+			totals.TotalValue = *value
+		}
+
+		// Rows are ordered by region, so a region's locations are adjacent
+		if n := len(response.Regions); n == 0 || response.Regions[n-1].Region != string(row.Region) {
+			response.Regions = append(response.Regions, StockValuationRegion{Region: string(row.Region)})
+		}
+		region := &response.Regions[len(response.Regions)-1]
+		region.Locations = append(region.Locations, StockValuationLocation{
+			Location: SparepartStockLocation{
+				ID:      row.LocationID,
+				Region:  string(row.Region),
+				Regency: row.Regency,
+				Cluster: row.Cluster,
+			},
+			StockValuationTotals: totals,
+		})
+		region.add(totals)
+		response.add(totals)
+	}
+
+	utils.Success(c, "Stock valuation retrieved successfully", response)
+}
+
+// @Summary Export inventory valuation to Excel
+// @Description Export the value of the stock per location, with a subtotal row per region and a grand total row, with the same filters as the valuation
+// @Tags Stock Summary
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param region query string false "Filter by region (exact match)"
+// @Param regency query string false "Filter by regency (partial match, case-insensitive)"
+// @Param cluster query string false "Filter by cluster (partial match, case-insensitive)"
+// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
+// @Success 200 {file} application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Router /sparepart/stock/valuation/export/excel [get]
+func (h *StockSummaryHandler) ExportValuationExcel(c *gin.Context) {
+	rows, err := h.queries.ListStockValuation(c.Request.Context(), stockValuationParams(c))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock valuation", h.logger)
+		return
+	}
+
+	buf, err := utils.ExportStockValuationToExcel(rows, h.logger)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to generate Excel", h.logger)
+		return
+	}
+
+	c.Set(utils.ExportRowsKey, len(rows))
+	filename := fmt.Sprintf("stock_valuation_%s.xlsx", time.Now().UTC().Format("20060102_150405"))
+	sendExport(c, h.reports, buf.Bytes(), filename, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", h.logger)
+}
+
+// stockValuationParams reads the valuation filters from the query
+func stockValuationParams(c *gin.Context) sqlcdb.ListStockValuationParams {
+	return sqlcdb.ListStockValuationParams{
+		Region:    utils.TextFilter(c.Query("region")),
+		Regency:   utils.TextFilter(c.Query("regency")),
+		Cluster:   utils.TextFilter(c.Query("cluster")),
+		StockType: utils.TextFilter(c.Query("stock_type")),
+	}
+}
+
+// @Summary Get stock movements
+// @Description Get the changes of stock quantities from the stock ledger, newest first. Increases made by confirming a goods receipt are RECEIPT movements carrying that receipt and its supplier, so stock can be traced back to the supplier it came from, e.g. for warranty claims; supplier_id keeps only those movements. Decreases made by a disposal are DISPOSAL movements carrying the disposal, and decreases made by closing a work order are WORK_ORDER movements carrying the work order.
+// @Tags Stock Summary
+// @Accept json
+// @Produce json
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param location_id query int false "Filter by location ID"
+// @Param supplier_id query int false "Filter by the supplier the stock was received from"
+// @Param stock_type query string false "Filter by stock type (NEW_STOCK, USED_STOCK, DAMAGED, IN_REPAIR, RESERVED)"
+// @Param type query string false "Filter by movement type (RECEIPT, DISPOSAL, WORK_ORDER)"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date inclusive (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse{data=[]StockMovement}
+// @Failure 400 {object} utils.Response
+// @Router /sparepart/stock/movements [get]
+func (h *StockSummaryHandler) GetMovements(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := sqlcdb.CountStockMovementsParams{StockType: utils.TextFilter(c.Query("stock_type"))}
+	errs := parseIDFilters(c, &filters.SparepartID, &filters.LocationID)
+	if value := c.Query("supplier_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "supplier_id", Message: "must be a positive integer"})
+		} else {
+			filters.SupplierID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	switch movementType := c.Query("type"); movementType {
+	case "":
+	case movementTypeReceipt, movementTypeDisposal, movementTypeWorkOrder:
+		filters.MovementType = utils.TextFilter(movementType)
+	default:
+		errs = append(errs, utils.FieldError{Field: "type", Message: "must be one of RECEIPT, DISPOSAL, WORK_ORDER"})
+	}
+	dates := []struct {
+		field  string
+		target *pgtype.Timestamptz
+		days   int
+	}{
+		{"from", &filters.Since, 0},
+		{"to", &filters.Until, 1},
+	}
+	for _, date := range dates {
+		if value := c.Query(date.field); value != "" {
+			parsed, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				errs = append(errs, utils.FieldError{Field: date.field, Message: "must be a date (YYYY-MM-DD)"})
+				continue
+			}
+			*date.target = pgtype.Timestamptz{Time: parsed.AddDate(0, 0, date.days), Valid: true}
+		}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+	if filters.Since.Valid && filters.Until.Valid && !filters.Since.Time.Before(filters.Until.Time) {
+		utils.ValidationError(c, utils.FieldError{Field: "from", Message: "must not be after to"})
+		return
+	}
+
+	total, err := h.queries.CountStockMovements(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count stock movements", h.logger)
+		return
+	}
+
+	rows, err := h.queries.ListStockMovements(ctx, sqlcdb.ListStockMovementsParams{
+		SparepartID:  filters.SparepartID,
+		LocationID:   filters.LocationID,
+		StockType:    filters.StockType,
+		SupplierID:   filters.SupplierID,
+		MovementType: filters.MovementType,
+		Since:        filters.Since,
+		Until:        filters.Until,
+		Limit:        int32(pagination.Limit),
+		Offset:       int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock movements", h.logger)
+		return
+	}
+
+	movements := make([]StockMovement, 0, len(rows))
+	for _, row := range rows {
+		movement := StockMovement{
+			ID:          row.ID,
+			StockItemID: row.StockItemID,
+			Location: SparepartStockLocation{
+				ID:      row.LocationID,
+				Region:  string(row.Region),
+				Regency: row.Regency,
+				Cluster: row.Cluster,
+			},
+			SparepartID:    row.SparepartID,
+			SparepartName:  row.SparepartName,
+			StockType:      string(row.StockType),
+			QuantityChange: row.QuantityChange,
+			QuantityAfter:  row.QuantityAfter,
+			RecordedAt:     utils.FormatTimestamp(row.RecordedAt),
+		}
+		if row.ReceiptID.Valid {
+			movement.Type = movementTypeReceipt
+			movement.Receipt = &StockMovementReceipt{
+				ID:                  row.ReceiptID.Int32,
+				Code:                utils.GoodsReceiptCode(row.ReceiptID.Int32),
+				DeliveryOrderNumber: row.DeliveryOrderNumber.String,
+				SupplierID:          row.SupplierID.Int32,
+				Supplier:            row.SupplierName.String,
+			}
+		}
+		if row.DisposalID.Valid {
+			movement.Type = movementTypeDisposal
+			movement.Disposal = &StockMovementDisposal{
+				ID:         row.DisposalID.Int32,
+				Reason:     row.DisposalReason.String,
+				ApprovedBy: row.DisposalApprovedBy.String,
+			}
+		}
+		if row.WorkOrderID.Valid {
+			movement.Type = movementTypeWorkOrder
+			movement.WorkOrder = &StockMovementWorkOrder{
+				ID:    row.WorkOrderID.Int32,
+				Title: row.WorkOrderTitle.String,
+			}
+		}
+		movements = append(movements, movement)
+	}
+
+	utils.SuccessWithPagination(c, "Stock movements retrieved successfully", movements, pagination.Page, pagination.Limit, total)
+}
+
+// parseTrendParams validates the trend query params; dates are whole UTC days
+func parseTrendParams(c *gin.Context, now time.Time) (sqlcdb.ListStockTrendParams, []utils.FieldError) {
+	var errs []utils.FieldError
+	params := sqlcdb.ListStockTrendParams{StockType: utils.TextFilter(c.Query("stock_type"))}
+
+	params.Interval = c.DefaultQuery("interval", "day")
+	defaultBuckets, ok := trendIntervals[params.Interval]
+	if !ok {
+		errs = append(errs, utils.FieldError{Field: "interval", Message: "must be one of day, week, month"})
+	}
+
+	errs = append(errs, parseIDFilters(c, &params.SparepartID, &params.LocationID)...)
+
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "to", Message: "must be a date (YYYY-MM-DD)"})
+		}
+		to = parsed
+	}
+
+	var from time.Time
+	switch params.Interval {
+	case "week":
+		from = to.AddDate(0, 0, -7*(defaultBuckets-1))
+	case "month":
+		from = to.AddDate(0, -(defaultBuckets - 1), 0)
+	default:
+		from = to.AddDate(0, 0, -(defaultBuckets - 1))
+	}
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "from", Message: "must be a date (YYYY-MM-DD)"})
+		}
+		from = parsed
+	}
+
+	if len(errs) > 0 {
+		return params, errs
+	}
+	if from.After(to) {
+		return params, []utils.FieldError{{Field: "from", Message: "must not be after to"}}
+	}
+	if trendBuckets(params.Interval, from, to) > maxTrendPoints {
+		return params, []utils.FieldError{{Field: "from", Message: fmt.Sprintf("range spans more than %d %ss", maxTrendPoints, params.Interval)}}
+	}
+
+	params.Since = pgtype.Timestamptz{Time: from, Valid: true}
+	params.Until = pgtype.Timestamptz{Time: to.AddDate(0, 0, 1), Valid: true}
+	return params, nil
+}
+
+// parseIDFilters reads the optional sparepart_id and location_id query params
+func parseIDFilters(c *gin.Context, sparepartID, locationID *pgtype.Int4) []utils.FieldError {
+	var errs []utils.FieldError
+	idFilters := []struct {
+		field  string
+		target *pgtype.Int4
+	}{
+		{"sparepart_id", sparepartID},
+		{"location_id", locationID},
+	}
+	for _, filter := range idFilters {
+		if value := c.Query(filter.field); value != "" {
+			id, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || id < 1 {
+				errs = append(errs, utils.FieldError{Field: filter.field, Message: "must be a positive integer"})
+				continue
+			}
+			*filter.target = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	return errs
+}
+
+// trendBuckets approximates the number of buckets between from and to
+func trendBuckets(interval string, from, to time.Time) int {
+	days := int(to.Sub(from).Hours()/24) + 1
+	switch interval {
+	case "week":
+		return days/7 + 1
+	case "month":
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	default:
+		return days
+	}
+}
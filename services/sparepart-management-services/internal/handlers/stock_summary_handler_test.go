@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStockSummaryHandlerGetByLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	repo.EXPECT().
+		ListStockSummaryByLocation(gomock.Any(), sqlcdb.ListStockSummaryByLocationParams{Region: pgtype.Text{String: "PAPUA", Valid: true}}).
+		Return([]sqlcdb.StockSummaryByLocation{
+			{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", NewStockQuantity: 4, UsedStockQuantity: 1, TotalQuantity: 5},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/summary/location", h.GetByLocation, "/stock/summary/location?region=PAPUA", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var summary []sqlcdb.StockSummaryByLocation
+	decodeResponse(t, w, &summary)
+	if len(summary) != 1 || summary[0].TotalQuantity != 5 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestStockSummaryHandlerGetValuation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	value := func(cents int64) pgtype.Numeric {
+		return pgtype.Numeric{Int: big.NewInt(cents), Exp: -2, Valid: true}
+	}
+	repo.EXPECT().
+		ListStockValuation(gomock.Any(), sqlcdb.ListStockValuationParams{StockType: pgtype.Text{String: "NEW_STOCK", Valid: true}}).
+		Return([]sqlcdb.ListStockValuationRow{
+			{LocationID: 15, Region: sqlcdb.RegionTypeMALUKU, Regency: "Ambon", ItemCount: 2, Quantity: 3, TotalValue: value(150050)},
+			{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", ItemCount: 3, Quantity: 10, TotalValue: value(1000010), ItemsWithoutCost: 1},
+			{LocationID: 13, Region: sqlcdb.RegionTypePAPUA, Regency: "Merauke", ItemCount: 1, Quantity: 2, TotalValue: value(20)},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/valuation", h.GetValuation, "/stock/valuation?stock_type=NEW_STOCK", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var valuation StockValuation
+	decodeResponse(t, w, &valuation)
+	if valuation.TotalValue != 11500.80 || valuation.ItemCount != 6 || valuation.Quantity != 15 || valuation.ItemsWithoutCost != 1 {
+		t.Fatalf("unexpected totals: %+v", valuation.StockValuationTotals)
+	}
+	if len(valuation.Regions) != 2 || valuation.Regions[1].Region != "PAPUA" || len(valuation.Regions[1].Locations) != 2 {
+		t.Fatalf("unexpected regions: %+v", valuation.Regions)
+	}
+	if papua := valuation.Regions[1]; papua.TotalValue != 10000.30 || papua.Locations[0].Location.Regency != "Jayapura" {
+		t.Fatalf("unexpected region: %+v", papua)
+	}
+}
+
+func TestStockSummaryHandlerExportValuationExcel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	repo.EXPECT().
+		ListStockValuation(gomock.Any(), sqlcdb.ListStockValuationParams{}).
+		Return([]sqlcdb.ListStockValuationRow{
+			{LocationID: 15, Region: sqlcdb.RegionTypeMALUKU, Regency: "Ambon", Cluster: "A1", ItemCount: 2, Quantity: 3, TotalValue: pgtype.Numeric{Int: big.NewInt(150050), Exp: -2, Valid: true}},
+			{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "J1", ItemCount: 1, Quantity: 4, TotalValue: pgtype.Numeric{Int: big.NewInt(40000), Exp: -2, Valid: true}},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/valuation/export/excel", h.ExportValuationExcel, "/stock/valuation/export/excel", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	f, err := excelize.OpenReader(w.Body)
+	if err != nil {
+		t.Fatalf("failed to open exported workbook: %v", err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Stock Valuation")
+	if err != nil {
+		t.Fatalf("failed to read exported rows: %v", err)
+	}
+	// Header, a location and a subtotal per region, then the grand total
+	if len(rows) != 6 || rows[1][1] != "Ambon" || rows[2][1] != "Subtotal" || rows[4][0] != "PAPUA" {
+		t.Fatalf("unexpected exported rows: %v", rows)
+	}
+	if rows[0][6] != "Total Value" || rows[1][6] != "1500.5" || rows[5][0] != "Total" || rows[5][6] != "1900.5" {
+		t.Fatalf("unexpected values: %v", rows)
+	}
+}
+
+func TestStockSummaryHandlerGetTrends(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	repo.EXPECT().
+		ListStockTrend(gomock.Any(), sqlcdb.ListStockTrendParams{
+			Interval:    "month",
+			SparepartID: pgtype.Int4{Int32: 7, Valid: true},
+			Since:       pgtype.Timestamptz{Time: since, Valid: true},
+			Until:       pgtype.Timestamptz{Time: until, Valid: true},
+		}).
+		Return([]sqlcdb.ListStockTrendRow{
+			{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Period: pgtype.Timestamptz{Time: since, Valid: true}, Quantity: 5, Restocked: 5},
+			{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Period: pgtype.Timestamptz{Time: since.AddDate(0, 2, 0), Valid: true}, Quantity: 3, Consumed: 2},
+			{LocationID: 15, Region: sqlcdb.RegionTypeMALUKU, Regency: "Ambon", Period: pgtype.Timestamptz{Time: since, Valid: true}, Quantity: 1, Restocked: 1},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/trends", h.GetTrends, "/stock/trends?interval=month&sparepart_id=7&from=2026-01-01&to=2026-03-31", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var series []StockTrendSeries
+	decodeResponse(t, w, &series)
+	if len(series) != 2 || len(series[0].Points) != 2 || len(series[1].Points) != 1 {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+	if point := series[0].Points[1]; point.Period != "2026-03-01" || point.Quantity != 3 || point.Consumed != 2 {
+		t.Fatalf("unexpected point: %+v", point)
+	}
+}
+
+func TestStockSummaryHandlerGetTrendsFromSnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	repo.EXPECT().
+		ListStockSnapshotTrend(gomock.Any(), sqlcdb.ListStockSnapshotTrendParams{
+			Interval:   "day",
+			LocationID: pgtype.Int4{Int32: 12, Valid: true},
+			Since:      pgtype.Timestamptz{Time: since, Valid: true},
+			Until:      pgtype.Timestamptz{Time: until, Valid: true},
+		}).
+		Return([]sqlcdb.ListStockSnapshotTrendRow{
+			{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Cluster: "Dobo", Period: pgtype.Timestamptz{Time: since, Valid: true}, Quantity: 4, Restocked: 4},
+			{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Cluster: "Dobo", Period: pgtype.Timestamptz{Time: since.AddDate(0, 0, 2), Valid: true}, Quantity: 0, Consumed: 4},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/trends", h.GetTrends, "/stock/trends?source=snapshot&location_id=12&from=2026-03-01&to=2026-03-03", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var series []StockTrendSeries
+	decodeResponse(t, w, &series)
+	if len(series) != 1 || series[0].Location.Cluster != "Dobo" || len(series[0].Points) != 2 {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+	if point := series[0].Points[1]; point.Period != "2026-03-03" || point.Quantity != 0 || point.Consumed != 4 {
+		t.Fatalf("unexpected point: %+v", point)
+	}
+}
+
+func TestStockSummaryHandlerGetTrendsValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	for _, query := range []string{
+		"interval=year",
+		"location_id=abc",
+		"from=2026-13-01",
+		"from=2026-03-01&to=2026-01-01",
+		"interval=day&from=2020-01-01&to=2026-01-01",
+		"source=cache",
+	} {
+		w := performRequest(http.MethodGet, "/stock/trends", h.GetTrends, "/stock/trends?"+query, "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestStockSummaryHandlerGetReorderSuggestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	repo.EXPECT().
+		ListStockConsumption(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, params sqlcdb.ListStockConsumptionParams) ([]sqlcdb.ListStockConsumptionRow, error) {
+			if params.LocationID != (pgtype.Int4{Int32: 12, Valid: true}) || params.SparepartID.Valid {
+				t.Fatalf("unexpected params: %+v", params)
+			}
+			if want := time.Now().UTC().AddDate(0, -4, 0); params.Since.Time.After(want) || want.Sub(params.Since.Time) > 24*time.Hour {
+				t.Fatalf("expected since about four months ago, got %s", params.Since.Time)
+			}
+			return []sqlcdb.ListStockConsumptionRow{
+				{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", SparepartID: 7, SparepartName: "BMS", Consumed: 10, CurrentQuantity: 3},
+				{LocationID: 12, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", SparepartID: 8, SparepartName: "SCC", Consumed: 4, CurrentQuantity: 9},
+			}, nil
+		})
+
+	w := performRequest(http.MethodGet, "/stock/reorder-suggestions", h.GetReorderSuggestions, "/stock/reorder-suggestions?location_id=12&lookback_months=4&coverage_months=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var suggestions ReorderSuggestions
+	decodeResponse(t, w, &suggestions)
+	if suggestions.LookbackMonths != 4 || suggestions.CoverageMonths != 2 || len(suggestions.Items) != 1 {
+		t.Fatalf("unexpected suggestions: %+v", suggestions)
+	}
+	// 10 consumed over 4 months is 2.5 a month, 5 for two months, 2 more than the 3 in stock
+	if item := suggestions.Items[0]; item.SparepartID != 7 || item.MonthlyConsumption != 2.5 || item.TargetQuantity != 5 || item.SuggestedQuantity != 2 {
+		t.Fatalf("unexpected suggestion: %+v", item)
+	}
+}
+
+func TestStockSummaryHandlerGetReorderSuggestionsIncludeCovered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	repo.EXPECT().
+		ListStockConsumption(gomock.Any(), gomock.Any()).
+		Return([]sqlcdb.ListStockConsumptionRow{
+			{LocationID: 12, SparepartID: 8, SparepartName: "SCC", Consumed: 4, CurrentQuantity: 9},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/reorder-suggestions", h.GetReorderSuggestions, "/stock/reorder-suggestions?include_covered=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var suggestions ReorderSuggestions
+	decodeResponse(t, w, &suggestions)
+	if len(suggestions.Items) != 1 || suggestions.Items[0].TargetQuantity != 2 || suggestions.Items[0].SuggestedQuantity != 0 {
+		t.Fatalf("unexpected suggestions: %+v", suggestions)
+	}
+}
+
+func TestStockSummaryHandlerGetReorderSuggestionsValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	for _, query := range []string{
+		"lookback_months=0",
+		"coverage_months=25",
+		"coverage_months=two",
+		"sparepart_id=-1",
+		"include_covered=maybe",
+	} {
+		w := performRequest(http.MethodGet, "/stock/reorder-suggestions", h.GetReorderSuggestions, "/stock/reorder-suggestions?"+query, "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestStockSummaryHandlerGetMovements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	filters := sqlcdb.CountStockMovementsParams{
+		SupplierID: pgtype.Int4{Int32: 2, Valid: true},
+		Since:      pgtype.Timestamptz{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		Until:      pgtype.Timestamptz{Time: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+	repo.EXPECT().CountStockMovements(gomock.Any(), filters).Return(int64(1), nil)
+	repo.EXPECT().
+		ListStockMovements(gomock.Any(), sqlcdb.ListStockMovementsParams{SupplierID: filters.SupplierID, Since: filters.Since, Until: filters.Until, Limit: 10}).
+		Return([]sqlcdb.ListStockMovementsRow{{
+			ID: 40, StockItemID: 9, LocationID: 3, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Sentani",
+			SparepartID: 7, SparepartName: "BMS", StockType: sqlcdb.StockTypeNEWSTOCK, QuantityChange: 4, QuantityAfter: 6,
+			ReceiptID:           pgtype.Int4{Int32: 5, Valid: true},
+			DeliveryOrderNumber: pgtype.Text{String: "DO-0042", Valid: true},
+			SupplierID:          pgtype.Int4{Int32: 2, Valid: true},
+			SupplierName:        pgtype.Text{String: "PT Sinar Jaya", Valid: true},
+		}}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/movements", h.GetMovements, "/stock/movements?supplier_id=2&from=2026-03-01&to=2026-03-31", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var movements []StockMovement
+	decodeResponse(t, w, &movements)
+	if len(movements) != 1 || movements[0].Location.Cluster != "Sentani" || movements[0].QuantityChange != 4 {
+		t.Fatalf("unexpected movements: %+v", movements)
+	}
+	if receipt := movements[0].Receipt; receipt == nil || receipt.Code != "GR-000005" || receipt.Supplier != "PT Sinar Jaya" {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+}
+
+func TestStockSummaryHandlerGetMovementsDisposals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	movementType := pgtype.Text{String: movementTypeDisposal, Valid: true}
+	repo.EXPECT().CountStockMovements(gomock.Any(), sqlcdb.CountStockMovementsParams{MovementType: movementType}).Return(int64(1), nil)
+	repo.EXPECT().
+		ListStockMovements(gomock.Any(), sqlcdb.ListStockMovementsParams{MovementType: movementType, Limit: 10}).
+		Return([]sqlcdb.ListStockMovementsRow{{
+			ID: 41, StockItemID: 9, StockType: sqlcdb.StockTypeNEWSTOCK, QuantityChange: -2, QuantityAfter: 4,
+			DisposalID:         pgtype.Int4{Int32: 3, Valid: true},
+			DisposalReason:     pgtype.Text{String: "Terbakar", Valid: true},
+			DisposalApprovedBy: pgtype.Text{String: "Manajer Gudang", Valid: true},
+		}}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/movements", h.GetMovements, "/stock/movements?type=DISPOSAL", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var movements []StockMovement
+	decodeResponse(t, w, &movements)
+	if len(movements) != 1 || movements[0].Type != movementTypeDisposal || movements[0].Receipt != nil {
+		t.Fatalf("unexpected movements: %+v", movements)
+	}
+	if disposal := movements[0].Disposal; disposal == nil || disposal.ID != 3 || disposal.ApprovedBy != "Manajer Gudang" {
+		t.Fatalf("unexpected disposal: %+v", disposal)
+	}
+}
+
+func TestStockSummaryHandlerGetMovementsWorkOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	movementType := pgtype.Text{String: movementTypeWorkOrder, Valid: true}
+	repo.EXPECT().CountStockMovements(gomock.Any(), sqlcdb.CountStockMovementsParams{MovementType: movementType}).Return(int64(1), nil)
+	repo.EXPECT().
+		ListStockMovements(gomock.Any(), sqlcdb.ListStockMovementsParams{MovementType: movementType, Limit: 10}).
+		Return([]sqlcdb.ListStockMovementsRow{{
+			ID: 42, StockItemID: 10, StockType: sqlcdb.StockTypeNEWSTOCK, QuantityChange: -2, QuantityAfter: 3,
+			WorkOrderID:    pgtype.Int4{Int32: 6, Valid: true},
+			WorkOrderTitle: pgtype.Text{String: "Ganti baterai", Valid: true},
+		}}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/movements", h.GetMovements, "/stock/movements?type=WORK_ORDER", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var movements []StockMovement
+	decodeResponse(t, w, &movements)
+	if len(movements) != 1 || movements[0].Type != movementTypeWorkOrder || movements[0].Disposal != nil {
+		t.Fatalf("unexpected movements: %+v", movements)
+	}
+	if order := movements[0].WorkOrder; order == nil || order.ID != 6 || order.Title != "Ganti baterai" {
+		t.Fatalf("unexpected work order: %+v", order)
+	}
+}
+
+func TestStockSummaryHandlerGetMovementsValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStockSummaryRepository(ctrl)
+	h := NewStockSummaryHandler(repo, nil, testLogger)
+
+	for _, query := range []string{
+		"supplier_id=0",
+		"type=ADJUSTMENT",
+		"location_id=abc",
+		"from=01-03-2026",
+		"from=2026-04-01&to=2026-03-31",
+	} {
+		w := performRequest(http.MethodGet, "/stock/movements", h.GetMovements, "/stock/movements?"+query, "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// errTransferSourceNotFound, errInsufficientStock and errTransferDestinationInactive end a
+// transfer's transaction early
+var (
+	errTransferSourceNotFound      = errors.New("source stock item not found")
+	errInsufficientStock           = errors.New("insufficient stock at source")
+	errTransferDestinationInactive = errors.New("destination location is deactivated or deleted")
+)
+
+// CreateStockTransferRequest moves quantity of one sparepart and stock type between locations
+type CreateStockTransferRequest struct {
+	SparepartID           int              `json:"sparepart_id" binding:"required,min=1"`
+	StockType             models.StockType `json:"stock_type" binding:"required,oneof=NEW_STOCK USED_STOCK DAMAGED IN_REPAIR RESERVED"`
+	SourceLocationID      int              `json:"source_location_id" binding:"required,min=1"`
+	DestinationLocationID int              `json:"destination_location_id" binding:"required,min=1"`
+	Quantity              int              `json:"quantity" binding:"required,min=1"`
+	Notes                 *string          `json:"notes"`
+}
+
+// StockTransferResponse is a recorded transfer; the quantities after are only returned on creation
+type StockTransferResponse struct {
+	ID                       int32   `json:"id"`
+	SparepartID              int32   `json:"sparepart_id"`
+	SparepartName            *string `json:"sparepart_name,omitempty"`
+	StockType                string  `json:"stock_type"`
+	Quantity                 int32   `json:"quantity"`
+	SourceLocationID         int32   `json:"source_location_id"`
+	SourceCluster            *string `json:"source_cluster,omitempty"`
+	SourceStockID            int32   `json:"source_stock_id"`
+	SourceQuantityAfter      *int32  `json:"source_quantity_after,omitempty"`
+	DestinationLocationID    int32   `json:"destination_location_id"`
+	DestinationCluster       *string `json:"destination_cluster,omitempty"`
+	DestinationStockID       int32   `json:"destination_stock_id"`
+	DestinationQuantityAfter *int32  `json:"destination_quantity_after,omitempty"`
+	Notes                    *string `json:"notes"`
+	TransferredBy            *string `json:"transferred_by"`
+	CreatedAt                string  `json:"created_at"`
+}
+
+// StockTransferHandler moves stock between locations and serves the transfer records
+type StockTransferHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+}
+
+func NewStockTransferHandler(queries repository.SparepartStockRepository, logger *zap.Logger) *StockTransferHandler {
+	return &StockTransferHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Transfer stock between locations
+// @Description Atomically take quantity from the source location's stock item and add it to the destination's (created when missing), recording the transfer. The destination must be an active location.
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param transfer body CreateStockTransferRequest true "Transfer data"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/stock/transfer [post]
+func (h *StockTransferHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateStockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	if req.SourceLocationID == req.DestinationLocationID {
+		utils.ValidationError(c, utils.FieldError{Field: "destination_location_id", Message: "must differ from source_location_id"})
+		return
+	}
+
+	var source, destination sqlcdb.SparepartStockItem
+	var transfer sqlcdb.StockTransfer
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		var err error
+		source, destination, transfer, err = moveStock(ctx, repo, sqlcdb.CreateStockTransferParams{
+			SparepartID:           int32(req.SparepartID),
+			StockType:             sqlcdb.StockType(req.StockType),
+			Quantity:              int32(req.Quantity),
+			SourceLocationID:      int32(req.SourceLocationID),
+			DestinationLocationID: int32(req.DestinationLocationID),
+			Notes:                 utils.OptionalText(req.Notes),
+			TransferredBy:         utils.TextFilter(utils.UserID(c)),
+		})
+		return err
+	})
+	switch {
+	case errors.Is(err, errTransferSourceNotFound):
+		utils.NotFound(c, "Source stock item not found")
+		return
+	case errors.Is(err, errTransferDestinationInactive):
+		utils.ValidationError(c, utils.FieldError{Field: "destination_location_id", Message: "is deactivated or deleted"})
+		return
+	case errors.Is(err, errInsufficientStock):
+		utils.ValidationError(c, utils.FieldError{
+			Field:   "quantity",
+			Message: fmt.Sprintf("exceeds the %d available at the source location", source.Quantity),
+		})
+		return
+	case err != nil:
+		utils.HandleError(c, err, "Failed to transfer stock", h.logger)
+		return
+	}
+
+	response := toStockTransferResponse(sqlcdb.ListStockTransfersRow{
+		ID:                    transfer.ID,
+		SparepartID:           transfer.SparepartID,
+		StockType:             transfer.StockType,
+		Quantity:              transfer.Quantity,
+		SourceLocationID:      transfer.SourceLocationID,
+		DestinationLocationID: transfer.DestinationLocationID,
+		SourceStockID:         transfer.SourceStockID,
+		DestinationStockID:    transfer.DestinationStockID,
+		Notes:                 transfer.Notes,
+		TransferredBy:         transfer.TransferredBy,
+		CreatedAt:             transfer.CreatedAt,
+	})
+	response.SourceQuantityAfter = &source.Quantity
+	response.DestinationQuantityAfter = &destination.Quantity
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Stock transferred successfully",
+		Data:    response,
+	})
+}
+
+// moveStock takes arg.Quantity from the source location's stock item and adds it to the
+// destination's (created when missing), recording the transfer; it must run within a
+// transaction. A destination that is deactivated or deleted returns
+// errTransferDestinationInactive. When the source cannot supply the quantity it returns
+// errTransferSourceNotFound, or errInsufficientStock with the source stock item as it is.
+func moveStock(ctx context.Context, repo repository.SparepartStockRepository, arg sqlcdb.CreateStockTransferParams) (source, destination sqlcdb.SparepartStockItem, transfer sqlcdb.StockTransfer, err error) {
+	_, err = repo.GetActiveLocationForShare(ctx, arg.DestinationLocationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return source, destination, transfer, errTransferDestinationInactive
+	}
+	if err != nil {
+		return source, destination, transfer, err
+	}
+
+	current, err := repo.GetSparepartStockByKeyForUpdate(ctx, sqlcdb.GetSparepartStockByKeyForUpdateParams{
+		LocationID:  arg.SourceLocationID,
+		SparepartID: arg.SparepartID,
+		StockType:   arg.StockType,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return source, destination, transfer, errTransferSourceNotFound
+	}
+	if err != nil {
+		return source, destination, transfer, err
+	}
+	if current.Quantity < arg.Quantity {
+		return current, destination, transfer, errInsufficientStock
+	}
+
+	source, err = repo.TransferOutSparepartStock(ctx, sqlcdb.TransferOutSparepartStockParams{
+		ID:       current.ID,
+		Quantity: arg.Quantity,
+	})
+	if err != nil {
+		return source, destination, transfer, err
+	}
+
+	destination, err = repo.TransferInSparepartStock(ctx, sqlcdb.TransferInSparepartStockParams{
+		LocationID:  arg.DestinationLocationID,
+		SparepartID: arg.SparepartID,
+		StockType:   arg.StockType,
+		Quantity:    arg.Quantity,
+	})
+	if err != nil {
+		return source, destination, transfer, err
+	}
+
+	arg.SourceLocationID = source.LocationID
+	arg.DestinationLocationID = destination.LocationID
+	arg.SourceStockID = source.ID
+	arg.DestinationStockID = destination.ID
+	transfer, err = repo.CreateStockTransfer(ctx, arg)
+	return source, destination, transfer, err
+}
+
+// @Summary Get stock transfers
+// @Description Get the recorded stock transfers, newest first
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param location_id query int false "Transfers from or to this location"
+// @Param sparepart_id query int false "Filter by sparepart"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/stock/transfer [get]
+func (h *StockTransferHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	var filters sqlcdb.CountStockTransfersParams
+	if value := c.Query("location_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "location_id", Message: "must be a positive integer"})
+		} else {
+			filters.LocationID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	if value := c.Query("sparepart_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "sparepart_id", Message: "must be a positive integer"})
+		} else {
+			filters.SparepartID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountStockTransfers(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count stock transfers", h.logger)
+		return
+	}
+
+	transfers, err := h.queries.ListStockTransfers(ctx, sqlcdb.ListStockTransfersParams{
+		LocationID:  filters.LocationID,
+		SparepartID: filters.SparepartID,
+		Limit:       int32(pagination.Limit),
+		Offset:      int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock transfers", h.logger)
+		return
+	}
+
+	response := make([]StockTransferResponse, 0, len(transfers))
+	for _, transfer := range transfers {
+		response = append(response, toStockTransferResponse(transfer))
+	}
+
+	utils.SuccessWithPagination(c, "Stock transfers retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+func toStockTransferResponse(row sqlcdb.ListStockTransfersRow) StockTransferResponse {
+	response := StockTransferResponse{
+		ID:                    row.ID,
+		SparepartID:           row.SparepartID,
+		StockType:             string(row.StockType),
+		Quantity:              row.Quantity,
+		SourceLocationID:      row.SourceLocationID,
+		SourceStockID:         row.SourceStockID,
+		DestinationLocationID: row.DestinationLocationID,
+		DestinationStockID:    row.DestinationStockID,
+		CreatedAt:             utils.FormatTimestamp(row.CreatedAt),
+	}
+	if row.SparepartName.Valid {
+		response.SparepartName = &row.SparepartName.String
+	}
+	if row.SourceCluster.Valid {
+		response.SourceCluster = &row.SourceCluster.String
+	}
+	if row.DestinationCluster.Valid {
+		response.DestinationCluster = &row.DestinationCluster.String
+	}
+	if row.Notes.Valid {
+		response.Notes = &row.Notes.String
+	}
+	if row.TransferredBy.Valid {
+		response.TransferredBy = &row.TransferredBy.String
+	}
+	return response
+}
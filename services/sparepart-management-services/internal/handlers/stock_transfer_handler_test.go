@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+const stockTransferBody = `{"sparepart_id": 7, "stock_type": "NEW_STOCK", "source_location_id": 1, "destination_location_id": 2, "quantity": 3}`
+
+func expectTransaction(repo *mocks.MockSparepartStockRepository) {
+	repo.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(repository.SparepartStockRepository) error) error {
+			return fn(repo)
+		})
+}
+
+func TestStockTransferHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockTransferHandler(repo, testLogger)
+
+	key := sqlcdb.GetSparepartStockByKeyForUpdateParams{LocationID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK}
+	expectTransaction(repo)
+	repo.EXPECT().GetActiveLocationForShare(gomock.Any(), int32(2)).Return(sqlcdb.Location{ID: 2, IsActive: true}, nil)
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), key).
+		Return(sqlcdb.SparepartStockItem{ID: 10, LocationID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 5}, nil)
+	repo.EXPECT().TransferOutSparepartStock(gomock.Any(), sqlcdb.TransferOutSparepartStockParams{ID: 10, Quantity: 3}).
+		Return(sqlcdb.SparepartStockItem{ID: 10, LocationID: 1, Quantity: 2}, nil)
+	repo.EXPECT().TransferInSparepartStock(gomock.Any(), sqlcdb.TransferInSparepartStockParams{LocationID: 2, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 3}).
+		Return(sqlcdb.SparepartStockItem{ID: 20, LocationID: 2, Quantity: 4}, nil)
+	repo.EXPECT().
+		CreateStockTransfer(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.CreateStockTransferParams) (sqlcdb.StockTransfer, error) {
+			if arg.SourceStockID != 10 || arg.DestinationStockID != 20 || arg.TransferredBy.String != "budi" {
+				t.Errorf("unexpected transfer record: %+v", arg)
+			}
+			return sqlcdb.StockTransfer{
+				ID: 1, SparepartID: arg.SparepartID, StockType: arg.StockType, Quantity: arg.Quantity,
+				SourceLocationID: arg.SourceLocationID, DestinationLocationID: arg.DestinationLocationID,
+				SourceStockID: arg.SourceStockID, DestinationStockID: arg.DestinationStockID, TransferredBy: arg.TransferredBy,
+			}, nil
+		})
+
+	w := performRequestAs("budi", http.MethodPost, "/stock/transfer", h.Create, "/stock/transfer", stockTransferBody)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var transfer StockTransferResponse
+	decodeResponse(t, w, &transfer)
+	if transfer.SourceQuantityAfter == nil || *transfer.SourceQuantityAfter != 2 ||
+		transfer.DestinationQuantityAfter == nil || *transfer.DestinationQuantityAfter != 4 {
+		t.Fatalf("unexpected transfer response: %+v", transfer)
+	}
+}
+
+func TestStockTransferHandlerCreateRejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(repo *mocks.MockSparepartStockRepository)
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "same location",
+			body:       `{"sparepart_id": 7, "stock_type": "NEW_STOCK", "source_location_id": 1, "destination_location_id": 1, "quantity": 3}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "zero quantity",
+			body:       `{"sparepart_id": 7, "stock_type": "NEW_STOCK", "source_location_id": 1, "destination_location_id": 2, "quantity": 0}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "deactivated or deleted destination",
+			body: stockTransferBody,
+			setup: func(repo *mocks.MockSparepartStockRepository) {
+				expectTransaction(repo)
+				repo.EXPECT().GetActiveLocationForShare(gomock.Any(), int32(2)).Return(sqlcdb.Location{}, pgx.ErrNoRows)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "is deactivated or deleted",
+		},
+		{
+			name: "no stock at source",
+			body: stockTransferBody,
+			setup: func(repo *mocks.MockSparepartStockRepository) {
+				expectTransaction(repo)
+				repo.EXPECT().GetActiveLocationForShare(gomock.Any(), int32(2)).Return(sqlcdb.Location{ID: 2, IsActive: true}, nil)
+				repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), gomock.Any()).
+					Return(sqlcdb.SparepartStockItem{}, pgx.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			// Only a missing row means there is no stock; anything else is a server error
+			name: "source lookup fails",
+			body: stockTransferBody,
+			setup: func(repo *mocks.MockSparepartStockRepository) {
+				expectTransaction(repo)
+				repo.EXPECT().GetActiveLocationForShare(gomock.Any(), int32(2)).Return(sqlcdb.Location{ID: 2, IsActive: true}, nil)
+				repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), gomock.Any()).
+					Return(sqlcdb.SparepartStockItem{}, errors.New("connection reset"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "insufficient stock",
+			body: stockTransferBody,
+			setup: func(repo *mocks.MockSparepartStockRepository) {
+				expectTransaction(repo)
+				repo.EXPECT().GetActiveLocationForShare(gomock.Any(), int32(2)).Return(sqlcdb.Location{ID: 2, IsActive: true}, nil)
+				repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), gomock.Any()).
+					Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 2}, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "exceeds the 2 available at the source location",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockSparepartStockRepository(ctrl)
+			h := NewStockTransferHandler(repo, testLogger)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+
+			w := performRequest(http.MethodPost, "/stock/transfer", h.Create, "/stock/transfer", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantError != "" {
+				resp := decodeResponse(t, w, nil)
+				if len(resp.Errors) != 1 || resp.Errors[0].Message != tt.wantError {
+					t.Fatalf("unexpected errors: %+v", resp.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestStockTransferHandlerGetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockTransferHandler(repo, testLogger)
+
+	filters := sqlcdb.CountStockTransfersParams{LocationID: pgtype.Int4{Int32: 2, Valid: true}}
+	repo.EXPECT().CountStockTransfers(gomock.Any(), filters).Return(int64(1), nil)
+	repo.EXPECT().ListStockTransfers(gomock.Any(), sqlcdb.ListStockTransfersParams{LocationID: pgtype.Int4{Int32: 2, Valid: true}, Limit: 10, Offset: 0}).
+		Return([]sqlcdb.ListStockTransfersRow{{ID: 1, SourceLocationID: 1, DestinationLocationID: 2, Quantity: 3, StockType: sqlcdb.StockTypeNEWSTOCK}}, nil)
+
+	w := performRequest(http.MethodGet, "/stock/transfer", h.GetAll, "/stock/transfer?location_id=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var transfers []StockTransferResponse
+	resp := decodeResponse(t, w, &transfers)
+	if resp.Pagination.Total != 1 || len(transfers) != 1 || transfers[0].SourceQuantityAfter != nil {
+		t.Fatalf("unexpected transfers: %+v", transfers)
+	}
+}
@@ -0,0 +1,629 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Errors ending a unit registration transaction early; the response is written after the rollback
+var (
+	errStockItemNotFound = errors.New("stock item not found")
+	errStockUnitsInvalid = errors.New("stock units are invalid")
+)
+
+// Warranty statuses of a stock unit
+const (
+	warrantyStatusActive  = "ACTIVE"
+	warrantyStatusExpired = "EXPIRED"
+)
+
+// maxExpiringWarrantyDays bounds how far ahead the expiring warranties report looks
+const maxExpiringWarrantyDays = 730
+
+// StockUnitWarrantyRequest is the warranty of a stock unit: the supplier warranting it, the
+// day it started and its length in months. Start and length are given together.
+type StockUnitWarrantyRequest struct {
+	SupplierID     *int    `json:"supplier_id" binding:"omitempty,min=1"`
+	WarrantyStart  *string `json:"warranty_start"`
+	WarrantyMonths *int    `json:"warranty_months" binding:"omitempty,min=1,max=240"`
+}
+
+// unitWarranty is a validated StockUnitWarrantyRequest
+type unitWarranty struct {
+	supplierID pgtype.Int4
+	start      pgtype.Date
+	months     pgtype.Int4
+}
+
+// parse validates the warranty, reporting errors on the fields named with the prefix
+func (r StockUnitWarrantyRequest) parse(prefix string) (unitWarranty, []utils.FieldError) {
+	var warranty unitWarranty
+	var errs []utils.FieldError
+	if r.SupplierID != nil {
+		warranty.supplierID = pgtype.Int4{Int32: int32(*r.SupplierID), Valid: true}
+	}
+	switch {
+	case r.WarrantyStart == nil && r.WarrantyMonths == nil:
+	case r.WarrantyStart == nil:
+		errs = append(errs, utils.FieldError{Field: prefix + "warranty_start", Message: "is required with warranty_months"})
+	case r.WarrantyMonths == nil:
+		errs = append(errs, utils.FieldError{Field: prefix + "warranty_months", Message: "is required with warranty_start"})
+	default:
+		start, err := time.Parse("2006-01-02", *r.WarrantyStart)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: prefix + "warranty_start", Message: "must be a date in YYYY-MM-DD format"})
+			break
+		}
+		warranty.start = pgtype.Date{Time: start, Valid: true}
+		warranty.months = pgtype.Int4{Int32: int32(*r.WarrantyMonths), Valid: true}
+	}
+	return warranty, errs
+}
+
+// StockUnitRequest is one unit to register on a stock item, with its optional warranty
+type StockUnitRequest struct {
+	SerialNumber string  `json:"serial_number" binding:"required,max=100"`
+	AssetTag     *string `json:"asset_tag" binding:"omitempty,max=100"`
+	Notes        *string `json:"notes"`
+	StockUnitWarrantyRequest
+}
+
+// RegisterStockUnitsRequest registers the serial numbers of units of a stock item
+type RegisterStockUnitsRequest struct {
+	Units []StockUnitRequest `json:"units" binding:"required,min=1,max=100,dive"`
+}
+
+// StockUnitResponse is a registered unit of a stock item
+type StockUnitResponse struct {
+	ID           int32                      `json:"id"`
+	StockItemID  int32                      `json:"stock_item_id"`
+	SerialNumber string                     `json:"serial_number"`
+	AssetTag     *string                    `json:"asset_tag"`
+	Notes        *string                    `json:"notes"`
+	RegisteredBy *string                    `json:"registered_by"`
+	SupplierID   *int32                     `json:"supplier_id"`
+	Warranty     *StockUnitWarrantyResponse `json:"warranty"`
+	CreatedAt    string                     `json:"created_at"`
+	UpdatedAt    string                     `json:"updated_at"`
+}
+
+// StockUnitWarrantyResponse is the warranty of a stock unit and whether it still holds today.
+// The warranty covers the unit until the day before End.
+type StockUnitWarrantyResponse struct {
+	Start    string `json:"start"`
+	Months   int32  `json:"months"`
+	End      string `json:"end"`
+	Status   string `json:"status"`
+	DaysLeft int    `json:"days_left"`
+}
+
+// ExpiringWarrantyResponse is a stock unit whose warranty ends soon, with where it is stocked
+type ExpiringWarrantyResponse struct {
+	UnitID        int32                     `json:"unit_id"`
+	StockItemID   int32                     `json:"stock_item_id"`
+	SerialNumber  string                    `json:"serial_number"`
+	AssetTag      *string                   `json:"asset_tag"`
+	SupplierID    *int32                    `json:"supplier_id"`
+	Supplier      *string                   `json:"supplier"`
+	LocationID    int32                     `json:"location_id"`
+	Region        string                    `json:"region"`
+	Regency       string                    `json:"regency"`
+	Cluster       string                    `json:"cluster"`
+	SparepartID   int32                     `json:"sparepart_id"`
+	SparepartName string                    `json:"sparepart_name"`
+	StockType     string                    `json:"stock_type"`
+	Warranty      StockUnitWarrantyResponse `json:"warranty"`
+}
+
+// currentDate is today's date at midnight UTC, the way DATE columns are scanned
+func currentDate() time.Time {
+	today, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+	return today
+}
+
+// toWarrantyResponse returns nil for a unit without a warranty
+func toWarrantyResponse(start pgtype.Date, months pgtype.Int4, end pgtype.Date, today time.Time) *StockUnitWarrantyResponse {
+	if !start.Valid || !months.Valid || !end.Valid {
+		return nil
+	}
+	response := &StockUnitWarrantyResponse{
+		Start:  start.Time.Format("2006-01-02"),
+		Months: months.Int32,
+		End:    end.Time.Format("2006-01-02"),
+		Status: warrantyStatusExpired,
+	}
+	if end.Time.After(today) {
+		response.Status = warrantyStatusActive
+		response.DaysLeft = int(end.Time.Sub(today).Hours() / 24)
+	}
+	return response
+}
+
+// StockUnitScanResponse is a scanned unit with the stock item it is stocked as
+type StockUnitScanResponse struct {
+	StockUnitResponse
+	LocationID    int32  `json:"location_id"`
+	Region        string `json:"region"`
+	Regency       string `json:"regency"`
+	Cluster       string `json:"cluster"`
+	SparepartID   int32  `json:"sparepart_id"`
+	SparepartName string `json:"sparepart_name"`
+	StockType     string `json:"stock_type"`
+}
+
+func toStockUnitResponse(unit sqlcdb.StockUnit) StockUnitResponse {
+	response := StockUnitResponse{
+		ID:           unit.ID,
+		StockItemID:  unit.StockItemID,
+		SerialNumber: unit.SerialNumber,
+		CreatedAt:    utils.FormatTimestamp(unit.CreatedAt),
+		UpdatedAt:    utils.FormatTimestamp(unit.UpdatedAt),
+	}
+	if unit.AssetTag.Valid {
+		response.AssetTag = &unit.AssetTag.String
+	}
+	if unit.Notes.Valid {
+		response.Notes = &unit.Notes.String
+	}
+	if unit.RegisteredBy.Valid {
+		response.RegisteredBy = &unit.RegisteredBy.String
+	}
+	if unit.SupplierID.Valid {
+		response.SupplierID = &unit.SupplierID.Int32
+	}
+	response.Warranty = toWarrantyResponse(unit.WarrantyStart, unit.WarrantyMonths, unit.WarrantyEnd, currentDate())
+	return response
+}
+
+// normalizeUnitCode makes serial numbers and asset tags match regardless of case and surrounding spaces
+func normalizeUnitCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// StockUnitHandler tracks the individual units of high-value stock items by serial number
+type StockUnitHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+}
+
+func NewStockUnitHandler(queries repository.SparepartStockRepository, logger *zap.Logger) *StockUnitHandler {
+	return &StockUnitHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Register stock units
+// @Description Register the serial numbers (and optional asset tags and warranties) of units of a stock item. A serial number or asset tag can only be registered once across all locations, and a stock item cannot have more units than its quantity.
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Param units body RegisterStockUnitsRequest true "Units to register"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/stock/{id}/units [post]
+func (h *StockUnitHandler) Register(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+	var req RegisterStockUnitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	// Codes are checked against each other first: the database only reports the first duplicate.
+	// codes maps each code to the field it was first given in.
+	var errs []utils.FieldError
+	codes := make(map[string]string, len(req.Units))
+	addCode := func(field, code string) {
+		if first, ok := codes[code]; ok {
+			errs = append(errs, utils.FieldError{Field: field, Message: fmt.Sprintf("duplicates %s", first)})
+			return
+		}
+		codes[code] = field
+	}
+	serialNumbers := make([]string, 0, len(req.Units))
+	var assetTags []string
+	warranties := make([]unitWarranty, len(req.Units))
+	for i := range req.Units {
+		unit := &req.Units[i]
+		var warrantyErrs []utils.FieldError
+		warranties[i], warrantyErrs = unit.parse(fmt.Sprintf("units[%d].", i))
+		errs = append(errs, warrantyErrs...)
+		unit.SerialNumber = normalizeUnitCode(unit.SerialNumber)
+		if unit.SerialNumber == "" {
+			errs = append(errs, utils.FieldError{Field: fmt.Sprintf("units[%d].serial_number", i), Message: "is required"})
+		} else {
+			addCode(fmt.Sprintf("units[%d].serial_number", i), unit.SerialNumber)
+			serialNumbers = append(serialNumbers, unit.SerialNumber)
+		}
+
+		if unit.AssetTag != nil {
+			tag := normalizeUnitCode(*unit.AssetTag)
+			unit.AssetTag = nil
+			if tag != "" {
+				unit.AssetTag = &tag
+				addCode(fmt.Sprintf("units[%d].asset_tag", i), tag)
+				assetTags = append(assetTags, tag)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	user := utils.UserID(c)
+	err = h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		item, err := repo.GetSparepartStockForUpdate(ctx, int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errStockItemNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		registered, err := repo.CountStockUnits(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+		if registered+int64(len(req.Units)) > int64(item.Quantity) {
+			errs = append(errs, utils.FieldError{
+				Field:   "units",
+				Message: fmt.Sprintf("exceeds the stock quantity of %d, of which %d units are registered", item.Quantity, registered),
+			})
+			return errStockUnitsInvalid
+		}
+
+		conflicts, err := repo.ListStockUnitConflicts(ctx, sqlcdb.ListStockUnitConflictsParams{
+			SerialNumbers: serialNumbers,
+			AssetTags:     assetTags,
+		})
+		if err != nil {
+			return err
+		}
+		for _, conflict := range conflicts {
+			message := fmt.Sprintf("already registered at %s (stock item %d)", conflict.Cluster, conflict.StockItemID)
+			for _, code := range []string{conflict.SerialNumber, conflict.AssetTag.String} {
+				if field, ok := codes[code]; ok {
+					errs = append(errs, utils.FieldError{Field: field, Message: message})
+				}
+			}
+		}
+		if len(errs) > 0 {
+			return errStockUnitsInvalid
+		}
+
+		for i, unit := range req.Units {
+			_, err := repo.CreateStockUnit(ctx, sqlcdb.CreateStockUnitParams{
+				StockItemID:    item.ID,
+				SerialNumber:   unit.SerialNumber,
+				AssetTag:       utils.OptionalText(unit.AssetTag),
+				Notes:          utils.OptionalText(unit.Notes),
+				RegisteredBy:   utils.TextFilter(user),
+				SupplierID:     warranties[i].supplierID,
+				WarrantyStart:  warranties[i].start,
+				WarrantyMonths: warranties[i].months,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errStockItemNotFound):
+		utils.NotFound(c, "Sparepart stock item not found")
+		return
+	case errors.Is(err, errStockUnitsInvalid):
+		utils.ValidationError(c, errs...)
+		return
+	case err != nil:
+		utils.HandleError(c, err, "Failed to register stock units", h.logger)
+		return
+	}
+
+	h.respond(c, http.StatusCreated, int32(id), "Stock units registered successfully")
+}
+
+// @Summary Get stock units
+// @Description Get the registered units of a stock item, by serial number
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/{id}/units [get]
+func (h *StockUnitHandler) GetAll(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+	if _, err := h.queries.GetSparepartStock(c.Request.Context(), int32(id)); err != nil {
+		utils.NotFound(c, "Sparepart stock item not found")
+		return
+	}
+	h.respond(c, http.StatusOK, int32(id), "Stock units retrieved successfully")
+}
+
+// @Summary Scan stock unit
+// @Description Find a stock unit by its serial number or asset tag, with the location and sparepart it is stocked as
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param code query string true "Serial number or asset tag"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/units/scan [get]
+func (h *StockUnitHandler) Scan(c *gin.Context) {
+	code := normalizeUnitCode(c.Query("code"))
+	if code == "" {
+		utils.ValidationError(c, utils.FieldError{Field: "code", Message: "is required"})
+		return
+	}
+
+	unit, err := h.queries.ScanStockUnit(c.Request.Context(), code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.NotFound(c, "No stock unit has this serial number or asset tag")
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to scan stock unit", h.logger)
+		return
+	}
+
+	utils.Success(c, "Stock unit retrieved successfully", StockUnitScanResponse{
+		StockUnitResponse: toStockUnitResponse(sqlcdb.StockUnit{
+			ID:             unit.ID,
+			StockItemID:    unit.StockItemID,
+			SerialNumber:   unit.SerialNumber,
+			AssetTag:       unit.AssetTag,
+			Notes:          unit.Notes,
+			RegisteredBy:   unit.RegisteredBy,
+			CreatedAt:      unit.CreatedAt,
+			UpdatedAt:      unit.UpdatedAt,
+			SupplierID:     unit.SupplierID,
+			WarrantyStart:  unit.WarrantyStart,
+			WarrantyMonths: unit.WarrantyMonths,
+			WarrantyEnd:    unit.WarrantyEnd,
+		}),
+		LocationID:    unit.LocationID,
+		Region:        string(unit.Region),
+		Regency:       unit.Regency,
+		Cluster:       unit.Cluster,
+		SparepartID:   unit.SparepartID,
+		SparepartName: unit.SparepartName,
+		StockType:     string(unit.StockType),
+	})
+}
+
+// @Summary Set stock unit warranty
+// @Description Replace the supplier and warranty of a registered unit; leaving out the start and length clears the warranty
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Param unit_id path int true "Stock Unit ID"
+// @Param warranty body StockUnitWarrantyRequest true "Warranty of the unit"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/{id}/units/{unit_id}/warranty [put]
+func (h *StockUnitHandler) SetWarranty(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+	unitID, err := strconv.ParseInt(c.Param("unit_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid stock unit ID")
+		return
+	}
+	var req StockUnitWarrantyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	warranty, errs := req.parse("")
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	unit, err := h.queries.SetStockUnitWarranty(c.Request.Context(), sqlcdb.SetStockUnitWarrantyParams{
+		ID:             int32(unitID),
+		StockItemID:    int32(id),
+		SupplierID:     warranty.supplierID,
+		WarrantyStart:  warranty.start,
+		WarrantyMonths: warranty.months,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.NotFound(c, "Stock unit not found")
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to set stock unit warranty", h.logger)
+		return
+	}
+
+	utils.Success(c, "Stock unit warranty updated successfully", toStockUnitResponse(unit))
+}
+
+// @Summary Get expiring warranties
+// @Description Get the registered units whose warranty ends within the given number of days, the soonest first, so RMAs can be filed while the warranty still holds
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param days query int false "Days ahead to look (max 730)" default(90)
+// @Param location_id query int false "Filter by location ID"
+// @Param sparepart_id query int false "Filter by sparepart ID"
+// @Param supplier_id query int false "Filter by supplier ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/warranty/expiring [get]
+func (h *StockUnitHandler) GetExpiring(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	days := 90
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxExpiringWarrantyDays {
+			errs = append(errs, utils.FieldError{Field: "days", Message: fmt.Sprintf("must be between 1 and %d", maxExpiringWarrantyDays)})
+		} else {
+			days = parsed
+		}
+	}
+	today := currentDate()
+	filters := sqlcdb.CountExpiringWarrantiesParams{
+		Today: pgtype.Date{Time: today, Valid: true},
+		Until: pgtype.Date{Time: today.AddDate(0, 0, days), Valid: true},
+	}
+	idFilters := []struct {
+		field  string
+		target *pgtype.Int4
+	}{
+		{"location_id", &filters.LocationID},
+		{"sparepart_id", &filters.SparepartID},
+		{"supplier_id", &filters.SupplierID},
+	}
+	for _, filter := range idFilters {
+		if value := c.Query(filter.field); value != "" {
+			id, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || id < 1 {
+				errs = append(errs, utils.FieldError{Field: filter.field, Message: "must be a positive integer"})
+				continue
+			}
+			*filter.target = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountExpiringWarranties(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count expiring warranties", h.logger)
+		return
+	}
+
+	units, err := h.queries.ListExpiringWarranties(ctx, sqlcdb.ListExpiringWarrantiesParams{
+		Today:       filters.Today,
+		Until:       filters.Until,
+		LocationID:  filters.LocationID,
+		SparepartID: filters.SparepartID,
+		SupplierID:  filters.SupplierID,
+		Limit:       int32(pagination.Limit),
+		Offset:      int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get expiring warranties", h.logger)
+		return
+	}
+
+	response := make([]ExpiringWarrantyResponse, 0, len(units))
+	for _, unit := range units {
+		item := ExpiringWarrantyResponse{
+			UnitID:        unit.ID,
+			StockItemID:   unit.StockItemID,
+			SerialNumber:  unit.SerialNumber,
+			LocationID:    unit.LocationID,
+			Region:        string(unit.Region),
+			Regency:       unit.Regency,
+			Cluster:       unit.Cluster,
+			SparepartID:   unit.SparepartID,
+			SparepartName: unit.SparepartName,
+			StockType:     string(unit.StockType),
+		}
+		if unit.AssetTag.Valid {
+			item.AssetTag = &unit.AssetTag.String
+		}
+		if unit.SupplierID.Valid {
+			item.SupplierID = &unit.SupplierID.Int32
+		}
+		if unit.SupplierName.Valid {
+			item.Supplier = &unit.SupplierName.String
+		}
+		if warranty := toWarrantyResponse(unit.WarrantyStart, unit.WarrantyMonths, unit.WarrantyEnd, today); warranty != nil {
+			item.Warranty = *warranty
+		}
+		response = append(response, item)
+	}
+
+	utils.SuccessWithPagination(c, "Expiring warranties retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Delete stock unit
+// @Description Unregister a unit of a stock item, e.g. one that was installed or written off
+// @Tags Sparepart Stock
+// @Accept json
+// @Produce json
+// @Param id path int true "Sparepart Stock Item ID"
+// @Param unit_id path int true "Stock Unit ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/stock/{id}/units/{unit_id} [delete]
+func (h *StockUnitHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid sparepart stock item ID")
+		return
+	}
+	unitID, err := strconv.ParseInt(c.Param("unit_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid stock unit ID")
+		return
+	}
+
+	deleted, err := h.queries.DeleteStockUnit(c.Request.Context(), sqlcdb.DeleteStockUnitParams{ID: int32(unitID), StockItemID: int32(id)})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to delete stock unit", h.logger)
+		return
+	}
+	if deleted == 0 {
+		utils.NotFound(c, "Stock unit not found")
+		return
+	}
+
+	h.respond(c, http.StatusOK, int32(id), "Stock unit deleted successfully")
+}
+
+// respond writes the registered units of the stock item
+func (h *StockUnitHandler) respond(c *gin.Context, statusCode int, id int32, message string) {
+	units, err := h.queries.ListStockUnits(c.Request.Context(), id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get stock units", h.logger)
+		return
+	}
+
+	response := make([]StockUnitResponse, 0, len(units))
+	for _, unit := range units {
+		response = append(response, toStockUnitResponse(unit))
+	}
+
+	c.JSON(statusCode, utils.Response{
+		Success: true,
+		Message: message,
+		Data:    response,
+	})
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStockUnitHandlerRegister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 3}, nil)
+	repo.EXPECT().CountStockUnits(gomock.Any(), int32(4)).Return(int64(1), nil)
+	repo.EXPECT().ListStockUnitConflicts(gomock.Any(), sqlcdb.ListStockUnitConflictsParams{
+		SerialNumbers: []string{"BMS-001", "BMS-002"},
+		AssetTags:     []string{"AT-9"},
+	}).Return(nil, nil)
+	repo.EXPECT().CreateStockUnit(gomock.Any(), sqlcdb.CreateStockUnitParams{
+		StockItemID: 4, SerialNumber: "BMS-001", RegisteredBy: pgtype.Text{String: "budi", Valid: true},
+	}).Return(sqlcdb.StockUnit{ID: 1}, nil)
+	repo.EXPECT().CreateStockUnit(gomock.Any(), sqlcdb.CreateStockUnitParams{
+		StockItemID: 4, SerialNumber: "BMS-002", AssetTag: pgtype.Text{String: "AT-9", Valid: true}, RegisteredBy: pgtype.Text{String: "budi", Valid: true},
+	}).Return(sqlcdb.StockUnit{ID: 2}, nil)
+	repo.EXPECT().ListStockUnits(gomock.Any(), int32(4)).Return([]sqlcdb.StockUnit{
+		{ID: 1, StockItemID: 4, SerialNumber: "BMS-001"},
+		{ID: 2, StockItemID: 4, SerialNumber: "BMS-002", AssetTag: pgtype.Text{String: "AT-9", Valid: true}},
+	}, nil)
+
+	body := `{"units": [{"serial_number": " bms-001 ", "asset_tag": " "}, {"serial_number": "BMS-002", "asset_tag": "at-9"}]}`
+	w := performRequestAs("budi", http.MethodPost, "/stock/:id/units", h.Register, "/stock/4/units", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var units []StockUnitResponse
+	decodeResponse(t, w, &units)
+	if len(units) != 2 || units[1].AssetTag == nil || *units[1].AssetTag != "AT-9" {
+		t.Fatalf("unexpected units: %+v", units)
+	}
+}
+
+func TestStockUnitHandlerRegisterRejectsDuplicateSerials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	body := `{"units": [{"serial_number": "BMS-001"}, {"serial_number": "bms-001"}]}`
+	w := performRequest(http.MethodPost, "/stock/:id/units", h.Register, "/stock/4/units", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "units[1].serial_number" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestStockUnitHandlerRegisterRejectsSerialsOfOtherLocations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 5}, nil)
+	repo.EXPECT().CountStockUnits(gomock.Any(), int32(4)).Return(int64(0), nil)
+	repo.EXPECT().ListStockUnitConflicts(gomock.Any(), gomock.Any()).Return([]sqlcdb.ListStockUnitConflictsRow{
+		{ID: 7, SerialNumber: "BMS-002", StockItemID: 11, LocationID: 3, Cluster: "Sorong"},
+	}, nil)
+
+	body := `{"units": [{"serial_number": "BMS-001"}, {"serial_number": "BMS-002"}]}`
+	w := performRequest(http.MethodPost, "/stock/:id/units", h.Register, "/stock/4/units", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "units[1].serial_number" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestStockUnitHandlerRegisterExceedsQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 1}, nil)
+	repo.EXPECT().CountStockUnits(gomock.Any(), int32(4)).Return(int64(1), nil)
+
+	w := performRequest(http.MethodPost, "/stock/:id/units", h.Register, "/stock/4/units", `{"units": [{"serial_number": "BMS-001"}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "units" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestStockUnitHandlerRegisterWithWarranty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	start := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	expectTransaction(repo)
+	repo.EXPECT().GetSparepartStockForUpdate(gomock.Any(), int32(4)).Return(sqlcdb.SparepartStockItem{ID: 4, Quantity: 1}, nil)
+	repo.EXPECT().CountStockUnits(gomock.Any(), int32(4)).Return(int64(0), nil)
+	repo.EXPECT().ListStockUnitConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().CreateStockUnit(gomock.Any(), sqlcdb.CreateStockUnitParams{
+		StockItemID:    4,
+		SerialNumber:   "SCC-001",
+		SupplierID:     pgtype.Int4{Int32: 2, Valid: true},
+		WarrantyStart:  pgtype.Date{Time: start, Valid: true},
+		WarrantyMonths: pgtype.Int4{Int32: 12, Valid: true},
+	}).Return(sqlcdb.StockUnit{ID: 1}, nil)
+	repo.EXPECT().ListStockUnits(gomock.Any(), int32(4)).Return([]sqlcdb.StockUnit{{
+		ID: 1, StockItemID: 4, SerialNumber: "SCC-001",
+		SupplierID:     pgtype.Int4{Int32: 2, Valid: true},
+		WarrantyStart:  pgtype.Date{Time: start, Valid: true},
+		WarrantyMonths: pgtype.Int4{Int32: 12, Valid: true},
+		WarrantyEnd:    pgtype.Date{Time: start.AddDate(1, 0, 0), Valid: true},
+	}}, nil)
+
+	body := `{"units": [{"serial_number": "SCC-001", "supplier_id": 2, "warranty_start": "2026-01-15", "warranty_months": 12}]}`
+	w := performRequest(http.MethodPost, "/stock/:id/units", h.Register, "/stock/4/units", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var units []StockUnitResponse
+	decodeResponse(t, w, &units)
+	if len(units) != 1 || units[0].Warranty == nil || units[0].Warranty.End != "2027-01-15" || *units[0].SupplierID != 2 {
+		t.Fatalf("unexpected units: %+v", units)
+	}
+}
+
+func TestStockUnitHandlerRegisterRejectsIncompleteWarranty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	body := `{"units": [{"serial_number": "SCC-001", "warranty_months": 12}, {"serial_number": "SCC-002", "warranty_start": "15-01-2026", "warranty_months": 12}]}`
+	w := performRequest(http.MethodPost, "/stock/:id/units", h.Register, "/stock/4/units", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 2 || resp.Errors[0].Field != "units[0].warranty_start" || resp.Errors[1].Field != "units[1].warranty_start" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestStockUnitHandlerSetWarranty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo.EXPECT().SetStockUnitWarranty(gomock.Any(), sqlcdb.SetStockUnitWarrantyParams{
+		ID:             1,
+		StockItemID:    4,
+		WarrantyStart:  pgtype.Date{Time: start, Valid: true},
+		WarrantyMonths: pgtype.Int4{Int32: 24, Valid: true},
+	}).Return(sqlcdb.StockUnit{
+		ID: 1, StockItemID: 4, SerialNumber: "BMS-001",
+		WarrantyStart:  pgtype.Date{Time: start, Valid: true},
+		WarrantyMonths: pgtype.Int4{Int32: 24, Valid: true},
+		WarrantyEnd:    pgtype.Date{Time: start.AddDate(2, 0, 0), Valid: true},
+	}, nil)
+	repo.EXPECT().SetStockUnitWarranty(gomock.Any(), gomock.Any()).Return(sqlcdb.StockUnit{}, pgx.ErrNoRows)
+
+	route := "/stock/:id/units/:unit_id/warranty"
+	w := performRequest(http.MethodPut, route, h.SetWarranty, "/stock/4/units/1/warranty", `{"warranty_start": "2020-06-01", "warranty_months": 24}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var unit StockUnitResponse
+	decodeResponse(t, w, &unit)
+	if unit.Warranty == nil || unit.Warranty.Status != warrantyStatusExpired || unit.Warranty.DaysLeft != 0 {
+		t.Fatalf("unexpected warranty: %+v", unit.Warranty)
+	}
+
+	w = performRequest(http.MethodPut, route, h.SetWarranty, "/stock/4/units/9/warranty", `{}`)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStockUnitHandlerGetExpiring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	today := currentDate()
+	filters := sqlcdb.CountExpiringWarrantiesParams{
+		Today:      pgtype.Date{Time: today, Valid: true},
+		Until:      pgtype.Date{Time: today.AddDate(0, 0, 30), Valid: true},
+		SupplierID: pgtype.Int4{Int32: 2, Valid: true},
+	}
+	repo.EXPECT().CountExpiringWarranties(gomock.Any(), filters).Return(int64(1), nil)
+	repo.EXPECT().ListExpiringWarranties(gomock.Any(), sqlcdb.ListExpiringWarrantiesParams{
+		Today: filters.Today, Until: filters.Until, SupplierID: filters.SupplierID, Limit: 10,
+	}).Return([]sqlcdb.ListExpiringWarrantiesRow{{
+		ID: 1, StockItemID: 4, SerialNumber: "SCC-001", Cluster: "Dobo", SparepartName: "SCC",
+		SupplierID:     pgtype.Int4{Int32: 2, Valid: true},
+		SupplierName:   pgtype.Text{String: "PT Surya", Valid: true},
+		WarrantyStart:  pgtype.Date{Time: today.AddDate(-1, 0, 10), Valid: true},
+		WarrantyMonths: pgtype.Int4{Int32: 12, Valid: true},
+		WarrantyEnd:    pgtype.Date{Time: today.AddDate(0, 0, 10), Valid: true},
+	}}, nil)
+
+	w := performRequest(http.MethodGet, "/warranty/expiring", h.GetExpiring, "/warranty/expiring?days=30&supplier_id=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var units []ExpiringWarrantyResponse
+	decodeResponse(t, w, &units)
+	if len(units) != 1 || *units[0].Supplier != "PT Surya" || units[0].Warranty.Status != warrantyStatusActive || units[0].Warranty.DaysLeft != 10 {
+		t.Fatalf("unexpected units: %+v", units)
+	}
+
+	w = performRequest(http.MethodGet, "/warranty/expiring", h.GetExpiring, "/warranty/expiring?days=0&location_id=x", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 2 || resp.Errors[0].Field != "days" || resp.Errors[1].Field != "location_id" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestStockUnitHandlerScan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewStockUnitHandler(repo, testLogger)
+
+	repo.EXPECT().ScanStockUnit(gomock.Any(), "BMS-001").Return(sqlcdb.ScanStockUnitRow{
+		ID: 1, StockItemID: 4, SerialNumber: "BMS-001", LocationID: 3, Cluster: "Sorong", SparepartName: "BMS",
+	}, nil)
+	repo.EXPECT().ScanStockUnit(gomock.Any(), "BMS-404").Return(sqlcdb.ScanStockUnitRow{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodGet, "/stock/units/scan", h.Scan, "/stock/units/scan?code=bms-001", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var unit StockUnitScanResponse
+	decodeResponse(t, w, &unit)
+	if unit.StockItemID != 4 || unit.Cluster != "Sorong" || unit.SparepartName != "BMS" {
+		t.Fatalf("unexpected unit: %+v", unit)
+	}
+
+	w = performRequest(http.MethodGet, "/stock/units/scan", h.Scan, "/stock/units/scan?code=BMS-404", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
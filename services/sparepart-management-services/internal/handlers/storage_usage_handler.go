@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"cmp"
+	"path"
+	"slices"
+	"time"
+
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// StorageUsageReport is what the photos in the upload storage take up, thumbnails included
+type StorageUsageReport struct {
+	TotalBytes int64 `json:"total_bytes"`
+	TotalFiles int   `json:"total_files"`
+	// UPLOAD_QUOTA_MB in bytes; null without a quota
+	QuotaBytes  *int64                 `json:"quota_bytes"`
+	Directories []DirectoryUsage       `json:"directories"`
+	Locations   []LocationStorageUsage `json:"locations"`
+	GeneratedAt string                 `json:"generated_at"`
+}
+
+// DirectoryUsage is what the files of one upload directory (e.g. sparepart/new_stock) take up
+type DirectoryUsage struct {
+	Directory string `json:"directory"`
+	Files     int    `json:"files"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// LocationStorageUsage is what the photos of the stock and tools alker items of a location take
+// up; a photo whose file is missing takes up nothing
+type LocationStorageUsage struct {
+	LocationID int32  `json:"location_id"`
+	Region     string `json:"region"`
+	Regency    string `json:"regency"`
+	Cluster    string `json:"cluster"`
+	Photos     int    `json:"photos"`
+	Bytes      int64  `json:"bytes"`
+}
+
+type StorageUsageHandler struct {
+	logger  *zap.Logger
+	queries repository.StorageUsageRepository
+	uploads *uploads.Service
+}
+
+func NewStorageUsageHandler(queries repository.StorageUsageRepository, uploads *uploads.Service, logger *zap.Logger) *StorageUsageHandler {
+	return &StorageUsageHandler{
+		logger:  logger,
+		queries: queries,
+		uploads: uploads,
+	}
+}
+
+// @Summary Get storage usage
+// @Description Bytes taken up by the upload storage, per directory and per location (photos of its stock and tools alker items with their thumbnails), largest first, with the upload quota
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response{data=StorageUsageReport}
+// @Router /sparepart/admin/storage/usage [get]
+func (h *StorageUsageHandler) GetUsage(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	usage, err := h.uploads.MeasureUsage(ctx)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to measure upload storage", h.logger)
+		return
+	}
+
+	rows, err := h.queries.ListLocationPhotoPaths(ctx)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get location photos", h.logger)
+		return
+	}
+
+	report := StorageUsageReport{
+		TotalBytes:  usage.Bytes,
+		TotalFiles:  usage.Files,
+		Directories: []DirectoryUsage{},
+		Locations:   []LocationStorageUsage{},
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if quota := h.uploads.QuotaBytes(); quota > 0 {
+		report.QuotaBytes = &quota
+	}
+
+	directories := map[string]int{}
+	for file, size := range usage.Sizes {
+		dir := path.Dir(utils.UploadKey(file))
+		index, ok := directories[dir]
+		if !ok {
+			index = len(report.Directories)
+			directories[dir] = index
+			report.Directories = append(report.Directories, DirectoryUsage{Directory: dir})
+		}
+		report.Directories[index].Files++
+		report.Directories[index].Bytes += size
+	}
+	slices.SortFunc(report.Directories, func(a, b DirectoryUsage) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), cmp.Compare(a.Directory, b.Directory))
+	})
+
+	// Rows come ordered by location
+	for _, row := range rows {
+		if n := len(report.Locations); n == 0 || report.Locations[n-1].LocationID != row.LocationID {
+			report.Locations = append(report.Locations, LocationStorageUsage{
+				LocationID: row.LocationID,
+				Region:     string(row.Region),
+				Regency:    row.Regency,
+				Cluster:    row.Cluster,
+			})
+		}
+		location := &report.Locations[len(report.Locations)-1]
+		location.Photos++
+		location.Bytes += usage.Sizes[row.Path] + usage.Sizes[utils.ThumbnailPath(row.Path)]
+	}
+	slices.SortStableFunc(report.Locations, func(a, b LocationStorageUsage) int {
+		return cmp.Compare(b.Bytes, a.Bytes)
+	})
+
+	utils.Success(c, "Storage usage retrieved successfully", report)
+}
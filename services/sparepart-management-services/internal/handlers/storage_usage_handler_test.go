@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestStorageUsageHandlerGetUsage(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{QuotaBytes: 1024 * 1024})
+	for name, size := range map[string]int{
+		"sparepart/new_stock/a.jpg":       100,
+		"sparepart/new_stock/a_thumb.jpg": 10,
+		"tools_alker/b.png":               300,
+		"tools_alker/b_thumb.jpg":         20,
+		"damage_report/c.jpg":             50,
+		".staging/d.jpg":                  1000,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStorageUsageRepository(ctrl)
+	h := NewStorageUsageHandler(repo, uploader, testLogger)
+
+	repo.EXPECT().ListLocationPhotoPaths(gomock.Any()).Return([]sqlcdb.ListLocationPhotoPathsRow{
+		{LocationID: 1, Region: sqlcdb.RegionTypeMALUKU, Regency: "Kepulauan Aru", Cluster: "Dobo", Path: "/uploads/sparepart/new_stock/a.jpg"},
+		{LocationID: 2, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Sentani", Path: "/uploads/sparepart/new_stock/a.jpg"},
+		{LocationID: 2, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Sentani", Path: "/uploads/tools_alker/b.png"},
+		{LocationID: 2, Region: sqlcdb.RegionTypePAPUA, Regency: "Jayapura", Cluster: "Sentani", Path: "/uploads/tools_alker/missing.jpg"},
+	}, nil)
+
+	w := performRequest(http.MethodGet, "/admin/storage/usage", h.GetUsage, "/admin/storage/usage", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var report StorageUsageReport
+	decodeResponse(t, w, &report)
+	if report.TotalBytes != 480 || report.TotalFiles != 5 {
+		t.Fatalf("expected 480 bytes in 5 files outside the staging area, got %d in %d", report.TotalBytes, report.TotalFiles)
+	}
+	if report.QuotaBytes == nil || *report.QuotaBytes != 1024*1024 {
+		t.Fatalf("expected the quota, got %v", report.QuotaBytes)
+	}
+
+	wantDirs := []DirectoryUsage{
+		{Directory: "tools_alker", Files: 2, Bytes: 320},
+		{Directory: "sparepart/new_stock", Files: 2, Bytes: 110},
+		{Directory: "damage_report", Files: 1, Bytes: 50},
+	}
+	if len(report.Directories) != len(wantDirs) {
+		t.Fatalf("expected %d directories, got %+v", len(wantDirs), report.Directories)
+	}
+	for i, want := range wantDirs {
+		if report.Directories[i] != want {
+			t.Errorf("directory %d = %+v, want %+v", i, report.Directories[i], want)
+		}
+	}
+
+	// A photo shared by two locations counts for both, a missing one for nothing
+	if len(report.Locations) != 2 {
+		t.Fatalf("expected 2 locations, got %+v", report.Locations)
+	}
+	if got := report.Locations[0]; got.LocationID != 2 || got.Photos != 3 || got.Bytes != 430 || got.Region != "PAPUA" {
+		t.Errorf("unexpected first location %+v", got)
+	}
+	if got := report.Locations[1]; got.LocationID != 1 || got.Photos != 1 || got.Bytes != 110 {
+		t.Errorf("unexpected second location %+v", got)
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// SupplierRequest holds a supplier's name and contact details
+type SupplierRequest struct {
+	Name          string  `json:"name" binding:"required,max=255"`
+	ContactPerson *string `json:"contact_person" binding:"omitempty,max=255"`
+	Phone         *string `json:"phone" binding:"omitempty,max=50"`
+	Email         *string `json:"email" binding:"omitempty,email,max=255"`
+	Address       *string `json:"address"`
+	Notes         *string `json:"notes"`
+}
+
+type SupplierHandler struct {
+	logger  *zap.Logger
+	queries repository.SupplierRepository
+}
+
+func NewSupplierHandler(queries repository.SupplierRepository, logger *zap.Logger) *SupplierHandler {
+	return &SupplierHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get all suppliers
+// @Description Get the suppliers ordered by name
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Param name query string false "Filter by name (partial match)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/supplier [get]
+func (h *SupplierHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	name := utils.TextFilter(c.Query("name"))
+
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountSuppliers(ctx, name)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count suppliers", h.logger)
+		return
+	}
+
+	suppliers, err := h.queries.ListSuppliers(ctx, sqlcdb.ListSuppliersParams{
+		Name:   name,
+		Limit:  int32(pagination.Limit),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get suppliers", h.logger)
+		return
+	}
+
+	utils.SuccessWithPagination(c, "Suppliers retrieved successfully", suppliers, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get supplier by ID
+// @Description Get a single supplier by ID
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/supplier/{id} [get]
+func (h *SupplierHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid supplier ID")
+		return
+	}
+
+	supplier, err := h.queries.GetSupplier(c.Request.Context(), int32(id))
+	if err != nil {
+		utils.NotFound(c, "Supplier not found")
+		return
+	}
+
+	utils.Success(c, "Supplier retrieved successfully", supplier)
+}
+
+// @Summary Create supplier
+// @Description Create a supplier; its name is stored with whitespace collapsed and must be unique
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Param supplier body SupplierRequest true "Supplier data"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/supplier [post]
+func (h *SupplierHandler) Create(c *gin.Context) {
+	var req SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := normalizeReferenceName(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.BlankNameError)
+		return
+	}
+
+	supplier, err := h.queries.CreateSupplier(c.Request.Context(), sqlcdb.CreateSupplierParams{
+		Name:          name,
+		ContactPerson: trimmedText(req.ContactPerson),
+		Phone:         trimmedText(req.Phone),
+		Email:         trimmedText(req.Email),
+		Address:       trimmedText(req.Address),
+		Notes:         trimmedText(req.Notes),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create supplier", h.logger)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Supplier created successfully",
+		Data:    supplier,
+	})
+}
+
+// @Summary Update supplier
+// @Description Replace a supplier's name and contact details; its goods receipts show the new name
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param supplier body SupplierRequest true "Supplier data"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/supplier/{id} [put]
+func (h *SupplierHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid supplier ID")
+		return
+	}
+
+	// Check if supplier exists
+	_, err = h.queries.GetSupplier(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Supplier not found")
+		return
+	}
+
+	var req SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := normalizeReferenceName(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.BlankNameError)
+		return
+	}
+
+	supplier, err := h.queries.UpdateSupplier(ctx, sqlcdb.UpdateSupplierParams{
+		ID:            int32(id),
+		Name:          name,
+		ContactPerson: trimmedText(req.ContactPerson),
+		Phone:         trimmedText(req.Phone),
+		Email:         trimmedText(req.Email),
+		Address:       trimmedText(req.Address),
+		Notes:         trimmedText(req.Notes),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update supplier", h.logger)
+		return
+	}
+
+	utils.Success(c, "Supplier updated successfully", supplier)
+}
+
+// @Summary Delete supplier
+// @Description Delete a supplier; refused while goods receipts, purchase orders or stock units still reference it
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/supplier/{id} [delete]
+func (h *SupplierHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid supplier ID")
+		return
+	}
+
+	// Check if supplier exists
+	_, err = h.queries.GetSupplier(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Supplier not found")
+		return
+	}
+
+	err = h.queries.DeleteSupplier(ctx, int32(id))
+	if err != nil {
+		if utils.IsForeignKeyViolation(err) {
+			c.JSON(http.StatusConflict, utils.Response{
+				Error: "Supplier still has goods receipts, purchase orders or stock units",
+				Code:  utils.ErrCodeInUse,
+			})
+			return
+		}
+		utils.HandleError(c, err, "Failed to delete supplier", h.logger)
+		return
+	}
+
+	utils.Success(c, "Supplier deleted successfully", nil)
+}
+
+// trimmedText trims an optional detail; a blank value clears it
+func trimmedText(value *string) pgtype.Text {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return pgtype.Text{}
+	}
+	trimmed := strings.TrimSpace(*value)
+	return utils.OptionalText(&trimmed)
+}
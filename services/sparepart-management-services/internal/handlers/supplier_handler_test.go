@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSupplierHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSupplierRepository(ctrl)
+	h := NewSupplierHandler(repo, testLogger)
+
+	params := sqlcdb.CreateSupplierParams{
+		Name:  "PT Sinar Jaya",
+		Phone: pgtype.Text{String: "0811", Valid: true},
+	}
+	repo.EXPECT().CreateSupplier(gomock.Any(), params).Return(sqlcdb.Supplier{ID: 2, Name: params.Name, Phone: params.Phone}, nil)
+
+	w := performRequest(http.MethodPost, "/supplier", h.Create, "/supplier", `{"name":"  PT  Sinar Jaya ","phone":" 0811 ","address":"  "}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSupplierHandlerCreateValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSupplierRepository(ctrl)
+	h := NewSupplierHandler(repo, testLogger)
+
+	for body, field := range map[string]string{
+		`{"name":"   "}`: "name",
+		`{"name":"PT Sinar Jaya","email":"nope"}`: "email",
+	} {
+		w := performRequest(http.MethodPost, "/supplier", h.Create, "/supplier", body)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", body, w.Code, w.Body.String())
+		}
+		if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != field {
+			t.Fatalf("%s: expected %s field error, got %+v", body, field, resp.Errors)
+		}
+	}
+}
+
+func TestSupplierHandlerCreateDuplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSupplierRepository(ctrl)
+	h := NewSupplierHandler(repo, testLogger)
+
+	repo.EXPECT().CreateSupplier(gomock.Any(), gomock.Any()).Return(sqlcdb.Supplier{}, &pgconn.PgError{
+		Code:           "23505",
+		ConstraintName: "unique_supplier_name",
+	})
+
+	w := performRequest(http.MethodPost, "/supplier", h.Create, "/supplier", `{"name":"PT Sinar Jaya"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSupplierHandlerDeleteInUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSupplierRepository(ctrl)
+	h := NewSupplierHandler(repo, testLogger)
+
+	repo.EXPECT().GetSupplier(gomock.Any(), int32(2)).Return(sqlcdb.Supplier{ID: 2}, nil)
+	repo.EXPECT().DeleteSupplier(gomock.Any(), int32(2)).Return(&pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "goods_receipt_supplier_id_fkey",
+	})
+
+	w := performRequest(http.MethodDelete, "/supplier/:id", h.Delete, "/supplier/2", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.ErrCodeInUse {
+		t.Fatalf("expected code %s, got %s", utils.ErrCodeInUse, resp.Code)
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TechnicianRequest holds a technician's name, phone and the regions they cover
+type TechnicianRequest struct {
+	Name    string   `json:"name" binding:"required,max=255"`
+	Phone   *string  `json:"phone" binding:"omitempty,max=50"`
+	Regions []string `json:"regions" binding:"max=6,dive,oneof=MALUKU MALUKU_UTARA PAPUA PAPUA_BARAT PAPUA_BARAT_DAYA PAPUA_SELATAN"`
+}
+
+// TechnicianHandler keeps the directory of field technicians that checkouts, work orders and
+// sparepart requests are assigned to
+type TechnicianHandler struct {
+	logger  *zap.Logger
+	queries repository.TechnicianRepository
+}
+
+func NewTechnicianHandler(queries repository.TechnicianRepository, logger *zap.Logger) *TechnicianHandler {
+	return &TechnicianHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get all technicians
+// @Description Get the technicians ordered by name
+// @Tags Technician
+// @Accept json
+// @Produce json
+// @Param name query string false "Filter by name (partial match)"
+// @Param region query string false "Filter by a covered region (MALUKU, MALUKU_UTARA, PAPUA, PAPUA_BARAT, PAPUA_BARAT_DAYA, PAPUA_SELATAN)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/technicians [get]
+func (h *TechnicianHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := sqlcdb.CountTechniciansParams{
+		Name:   utils.TextFilter(c.Query("name")),
+		Region: utils.TextFilter(c.Query("region")),
+	}
+
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountTechnicians(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count technicians", h.logger)
+		return
+	}
+
+	technicians, err := h.queries.ListTechnicians(ctx, sqlcdb.ListTechniciansParams{
+		Name:   filters.Name,
+		Region: filters.Region,
+		Limit:  int32(pagination.Limit),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get technicians", h.logger)
+		return
+	}
+
+	utils.SuccessWithPagination(c, "Technicians retrieved successfully", technicians, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get technician by ID
+// @Description Get a single technician by ID
+// @Tags Technician
+// @Accept json
+// @Produce json
+// @Param id path int true "Technician ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/technicians/{id} [get]
+func (h *TechnicianHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid technician ID")
+		return
+	}
+
+	technician, err := h.queries.GetTechnician(c.Request.Context(), int32(id))
+	if err != nil {
+		utils.NotFound(c, "Technician not found")
+		return
+	}
+
+	utils.Success(c, "Technician retrieved successfully", technician)
+}
+
+// @Summary Create technician
+// @Description Create a technician; the name is stored with whitespace collapsed and must be unique
+// @Tags Technician
+// @Accept json
+// @Produce json
+// @Param technician body TechnicianRequest true "Technician data"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/technicians [post]
+func (h *TechnicianHandler) Create(c *gin.Context) {
+	var req TechnicianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := normalizeReferenceName(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.BlankNameError)
+		return
+	}
+
+	technician, err := h.queries.CreateTechnician(c.Request.Context(), sqlcdb.CreateTechnicianParams{
+		Name:    name,
+		Phone:   trimmedText(req.Phone),
+		Regions: uniqueRegions(req.Regions),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create technician", h.logger)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Technician created successfully",
+		Data:    technician,
+	})
+}
+
+// @Summary Update technician
+// @Description Replace a technician's name, phone and regions; checkouts and work orders keep the name they were made with
+// @Tags Technician
+// @Accept json
+// @Produce json
+// @Param id path int true "Technician ID"
+// @Param technician body TechnicianRequest true "Technician data"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/technicians/{id} [put]
+func (h *TechnicianHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid technician ID")
+		return
+	}
+
+	// Check if technician exists
+	_, err = h.queries.GetTechnician(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Technician not found")
+		return
+	}
+
+	var req TechnicianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	name := normalizeReferenceName(req.Name)
+	if name == "" {
+		utils.ValidationError(c, utils.BlankNameError)
+		return
+	}
+
+	technician, err := h.queries.UpdateTechnician(ctx, sqlcdb.UpdateTechnicianParams{
+		ID:      int32(id),
+		Name:    name,
+		Phone:   trimmedText(req.Phone),
+		Regions: uniqueRegions(req.Regions),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update technician", h.logger)
+		return
+	}
+
+	utils.Success(c, "Technician updated successfully", technician)
+}
+
+// @Summary Delete technician
+// @Description Delete a technician; refused while tools alker checkouts, work orders or sparepart requests still reference them
+// @Tags Technician
+// @Accept json
+// @Produce json
+// @Param id path int true "Technician ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /sparepart/technicians/{id} [delete]
+func (h *TechnicianHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid technician ID")
+		return
+	}
+
+	// Check if technician exists
+	_, err = h.queries.GetTechnician(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Technician not found")
+		return
+	}
+
+	err = h.queries.DeleteTechnician(ctx, int32(id))
+	if err != nil {
+		if utils.IsForeignKeyViolation(err) {
+			c.JSON(http.StatusConflict, utils.Response{
+				Error: "Technician still has tools alker checkouts, work orders or sparepart requests",
+				Code:  utils.ErrCodeInUse,
+			})
+			return
+		}
+		utils.HandleError(c, err, "Failed to delete technician", h.logger)
+		return
+	}
+
+	utils.Success(c, "Technician deleted successfully", nil)
+}
+
+// uniqueRegions drops repeated regions, keeping the first occurrence of each
+func uniqueRegions(regions []string) []string {
+	unique := make([]string, 0, len(regions))
+	seen := make(map[string]bool, len(regions))
+	for _, region := range regions {
+		if !seen[region] {
+			seen[region] = true
+			unique = append(unique, region)
+		}
+	}
+	return unique
+}
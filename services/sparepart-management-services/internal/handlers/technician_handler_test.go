@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTechnicianHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockTechnicianRepository(ctrl)
+	h := NewTechnicianHandler(repo, testLogger)
+
+	params := sqlcdb.CreateTechnicianParams{
+		Name:    "Budi Santoso",
+		Phone:   pgtype.Text{String: "0812", Valid: true},
+		Regions: []string{"PAPUA", "MALUKU"},
+	}
+	repo.EXPECT().CreateTechnician(gomock.Any(), params).Return(sqlcdb.Technician{ID: 3, Name: params.Name, Phone: params.Phone, Regions: params.Regions}, nil)
+
+	w := performRequest(http.MethodPost, "/technicians", h.Create, "/technicians", `{"name":" Budi  Santoso ","phone":" 0812 ","regions":["PAPUA","MALUKU","PAPUA"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTechnicianHandlerCreateValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockTechnicianRepository(ctrl)
+	h := NewTechnicianHandler(repo, testLogger)
+
+	for body, field := range map[string]string{
+		`{"name":"   "}`:                     "name",
+		`{"name":"Budi","regions":["JAWA"]}`: "regions[0]",
+	} {
+		w := performRequest(http.MethodPost, "/technicians", h.Create, "/technicians", body)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", body, w.Code, w.Body.String())
+		}
+		if resp := decodeResponse(t, w, nil); len(resp.Errors) != 1 || resp.Errors[0].Field != field {
+			t.Fatalf("%s: expected %s field error, got %+v", body, field, resp.Errors)
+		}
+	}
+}
+
+func TestTechnicianHandlerDeleteInUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockTechnicianRepository(ctrl)
+	h := NewTechnicianHandler(repo, testLogger)
+
+	repo.EXPECT().GetTechnician(gomock.Any(), int32(3)).Return(sqlcdb.Technician{ID: 3}, nil)
+	repo.EXPECT().DeleteTechnician(gomock.Any(), int32(3)).Return(&pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "work_order_technician_id_fkey",
+	})
+
+	w := performRequest(http.MethodDelete, "/technicians/:id", h.Delete, "/technicians/3", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.ErrCodeInUse {
+		t.Fatalf("expected code %s, got %s", utils.ErrCodeInUse, resp.Code)
+	}
+}
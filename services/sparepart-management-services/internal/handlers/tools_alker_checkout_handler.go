@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Checkout statuses the checkout list filters by; OVERDUE checkouts are OPEN ones past their
+// expected return date
+const (
+	checkoutStatusOpen     = "OPEN"
+	checkoutStatusOverdue  = "OVERDUE"
+	checkoutStatusReturned = "RETURNED"
+)
+
+// Errors ending a checkout transaction early; the response is written after the rollback
+var (
+	errToolsAlkerNotFound  = errors.New("tools alker item not found")
+	errToolsAlkerAvailable = errors.New("tools alker quantity not available")
+)
+
+// CheckoutToolsAlkerRequest lends tools to a technician; with TechnicianID the name is taken from
+// the technician directory
+type CheckoutToolsAlkerRequest struct {
+	TechnicianID       *int    `json:"technician_id" binding:"omitempty,min=1"`
+	Technician         string  `json:"technician" binding:"required_without=TechnicianID,max=255"`
+	Quantity           int     `json:"quantity" binding:"omitempty,min=1"`
+	ExpectedReturnDate string  `json:"expected_return_date" binding:"required"`
+	Notes              *string `json:"notes"`
+}
+
+// CheckinToolsAlkerRequest returns the tools of an open checkout
+type CheckinToolsAlkerRequest struct {
+	CheckoutID int `json:"checkout_id" binding:"required,min=1"`
+}
+
+// ToolsAlkerCheckoutResponse is a checkout; CheckedInAt is set once the tools were returned
+type ToolsAlkerCheckoutResponse struct {
+	ID                 int32   `json:"id"`
+	ToolsAlkerItemID   int32   `json:"tools_alker_item_id"`
+	ToolsName          string  `json:"tools_name"`
+	LocationID         int32   `json:"location_id"`
+	Cluster            string  `json:"cluster"`
+	Quantity           int32   `json:"quantity"`
+	TechnicianID       *int32  `json:"technician_id"`
+	Technician         string  `json:"technician"`
+	ExpectedReturnDate string  `json:"expected_return_date"`
+	Notes              *string `json:"notes"`
+	CheckedOutBy       *string `json:"checked_out_by"`
+	CheckedOutAt       string  `json:"checked_out_at"`
+	CheckedInBy        *string `json:"checked_in_by"`
+	CheckedInAt        *string `json:"checked_in_at"`
+	Overdue            bool    `json:"overdue"`
+	DaysOverdue        int32   `json:"days_overdue"`
+}
+
+func toToolsAlkerCheckoutResponse(row sqlcdb.GetToolsAlkerCheckoutRow) ToolsAlkerCheckoutResponse {
+	response := ToolsAlkerCheckoutResponse{
+		ID:                 row.ID,
+		ToolsAlkerItemID:   row.ToolsAlkerItemID,
+		ToolsName:          row.ToolsName,
+		LocationID:         row.LocationID,
+		Cluster:            row.Cluster,
+		Quantity:           row.Quantity,
+		Technician:         row.Technician,
+		ExpectedReturnDate: row.ExpectedReturnDate.Time.Format("2006-01-02"),
+		CheckedOutAt:       utils.FormatTimestamp(row.CheckedOutAt),
+		Overdue:            row.Overdue,
+		DaysOverdue:        row.DaysOverdue,
+	}
+	if row.TechnicianID.Valid {
+		response.TechnicianID = &row.TechnicianID.Int32
+	}
+	if row.Notes.Valid {
+		response.Notes = &row.Notes.String
+	}
+	if row.CheckedOutBy.Valid {
+		response.CheckedOutBy = &row.CheckedOutBy.String
+	}
+	if row.CheckedInBy.Valid {
+		response.CheckedInBy = &row.CheckedInBy.String
+	}
+	if row.CheckedInAt.Valid {
+		checkedInAt := utils.FormatTimestamp(row.CheckedInAt)
+		response.CheckedInAt = &checkedInAt
+	}
+	return response
+}
+
+// ToolsAlkerCheckoutHandler tracks the tools alker lent to technicians, so lost tools can be
+// traced to whoever borrowed them
+type ToolsAlkerCheckoutHandler struct {
+	logger  *zap.Logger
+	queries repository.ToolsAlkerRepository
+}
+
+func NewToolsAlkerCheckoutHandler(queries repository.ToolsAlkerRepository, logger *zap.Logger) *ToolsAlkerCheckoutHandler {
+	return &ToolsAlkerCheckoutHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Check out tools alker
+// @Description Lend a quantity of a tools alker item to a technician until the expected return date. Fails when the item's open checkouts leave less than the quantity available.
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param id path int true "Tools Alker Item ID"
+// @Param checkout body CheckoutToolsAlkerRequest true "Checkout data"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/tools-alker/{id}/checkout [post]
+func (h *ToolsAlkerCheckoutHandler) Checkout(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tools alker item ID")
+		return
+	}
+	var req CheckoutToolsAlkerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	expectedReturn, err := time.Parse("2006-01-02", req.ExpectedReturnDate)
+	if err != nil {
+		utils.ValidationError(c, utils.FieldError{Field: "expected_return_date", Message: "must be a date in YYYY-MM-DD format"})
+		return
+	}
+	today, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+	if expectedReturn.Before(today) {
+		utils.ValidationError(c, utils.FieldError{Field: "expected_return_date", Message: "must not be in the past"})
+		return
+	}
+
+	var available int32
+	var checkout sqlcdb.ToolsAlkerCheckout
+	err = h.queries.WithinToolsAlkerTransaction(ctx, func(repo repository.ToolsAlkerRepository) error {
+		item, err := repo.GetToolsAlkerForUpdate(ctx, int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errToolsAlkerNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		checkedOut, err := repo.SumOpenToolsAlkerCheckouts(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+		available = availableToolsQuantity(item.Quantity, checkedOut)
+		if int32(req.Quantity) > available {
+			return errToolsAlkerAvailable
+		}
+
+		checkout, err = repo.CreateToolsAlkerCheckout(ctx, sqlcdb.CreateToolsAlkerCheckoutParams{
+			ToolsAlkerItemID:   item.ID,
+			Quantity:           int32(req.Quantity),
+			TechnicianID:       utils.OptionalInt(req.TechnicianID),
+			Technician:         req.Technician,
+			ExpectedReturnDate: pgtype.Date{Time: expectedReturn, Valid: true},
+			Notes:              utils.OptionalText(req.Notes),
+			CheckedOutBy:       utils.TextFilter(utils.UserID(c)),
+		})
+		return err
+	})
+	switch {
+	case errors.Is(err, errToolsAlkerNotFound):
+		utils.NotFound(c, "Tools alker item not found")
+		return
+	case errors.Is(err, errToolsAlkerAvailable):
+		utils.ValidationError(c, utils.FieldError{Field: "quantity", Message: fmt.Sprintf("exceeds the %d available", available)})
+		return
+	case err != nil:
+		utils.HandleError(c, err, "Failed to check out tools alker", h.logger)
+		return
+	}
+
+	h.respond(c, http.StatusCreated, checkout.ID, "Tools alker checked out successfully")
+}
+
+// @Summary Check in tools alker
+// @Description Return the tools of an open checkout of a tools alker item
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param id path int true "Tools Alker Item ID"
+// @Param checkin body CheckinToolsAlkerRequest true "Checkout to close"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/tools-alker/{id}/checkin [post]
+func (h *ToolsAlkerCheckoutHandler) Checkin(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tools alker item ID")
+		return
+	}
+	var req CheckinToolsAlkerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	checkout, err := h.queries.CheckInToolsAlkerCheckout(ctx, sqlcdb.CheckInToolsAlkerCheckoutParams{
+		CheckedInBy:      utils.TextFilter(utils.UserID(c)),
+		ID:               int32(req.CheckoutID),
+		ToolsAlkerItemID: int32(id),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.NotFound(c, "Open checkout not found for this tools alker item")
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to check in tools alker", h.logger)
+		return
+	}
+
+	h.respond(c, http.StatusOK, checkout.ID, "Tools alker checked in successfully")
+}
+
+// @Summary Get tools alker checkouts
+// @Description Get the tools alker checkouts, open ones first by expected return date
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (OPEN, OVERDUE, RETURNED)"
+// @Param technician query string false "Filter by technician (partial match)"
+// @Param technician_id query int false "Filter by technician in the directory"
+// @Param tools_alker_item_id query int false "Filter by tools alker item"
+// @Param location_id query int false "Filter by location"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/tools-alker/checkouts [get]
+func (h *ToolsAlkerCheckoutHandler) GetAll(c *gin.Context) {
+	h.list(c, c.Query("status"), pgtype.Int4{}, "Tools alker checkouts retrieved successfully")
+}
+
+// @Summary Get overdue tools alker checkouts
+// @Description Get the open tools alker checkouts past their expected return date, the longest overdue first
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param technician query string false "Filter by technician (partial match)"
+// @Param technician_id query int false "Filter by technician in the directory"
+// @Param tools_alker_item_id query int false "Filter by tools alker item"
+// @Param location_id query int false "Filter by location"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/tools-alker/checkouts/overdue [get]
+func (h *ToolsAlkerCheckoutHandler) GetOverdue(c *gin.Context) {
+	h.list(c, checkoutStatusOverdue, pgtype.Int4{}, "Overdue tools alker checkouts retrieved successfully")
+}
+
+// @Summary Get tools alker held by a technician
+// @Description Get the open checkouts of a technician in the directory, i.e. all tools they currently hold
+// @Tags Technician
+// @Accept json
+// @Produce json
+// @Param id path int true "Technician ID"
+// @Param tools_alker_item_id query int false "Filter by tools alker item"
+// @Param location_id query int false "Filter by location"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/technicians/{id}/tools [get]
+func (h *ToolsAlkerCheckoutHandler) GetHeldByTechnician(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid technician ID")
+		return
+	}
+
+	technician, err := h.queries.GetTechnician(c.Request.Context(), int32(id))
+	if err != nil {
+		utils.NotFound(c, "Technician not found")
+		return
+	}
+
+	h.list(c, checkoutStatusOpen, utils.IntFilter(technician.ID), "Tools alker held by technician retrieved successfully")
+}
+
+// list writes a page of checkouts; a valid technicianID pins the technician instead of the
+// technician_id query
+func (h *ToolsAlkerCheckoutHandler) list(c *gin.Context, status string, technicianID pgtype.Int4, message string) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	filters := sqlcdb.CountToolsAlkerCheckoutsParams{
+		Technician:   utils.TextFilter(c.Query("technician")),
+		TechnicianID: technicianID,
+	}
+	switch status {
+	case "":
+	case checkoutStatusOpen, checkoutStatusOverdue, checkoutStatusReturned:
+		filters.Status = utils.TextFilter(status)
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of OPEN, OVERDUE, RETURNED"})
+	}
+	idFilters := []struct {
+		field  string
+		filter *pgtype.Int4
+	}{
+		{"tools_alker_item_id", &filters.ToolsAlkerItemID},
+		{"location_id", &filters.LocationID},
+		{"technician_id", &filters.TechnicianID},
+	}
+	for _, f := range idFilters {
+		value := c.Query(f.field)
+		if value == "" || f.filter.Valid {
+			continue
+		}
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: f.field, Message: "must be a positive integer"})
+			continue
+		}
+		*f.filter = pgtype.Int4{Int32: int32(id), Valid: true}
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountToolsAlkerCheckouts(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count tools alker checkouts", h.logger)
+		return
+	}
+
+	checkouts, err := h.queries.ListToolsAlkerCheckouts(ctx, sqlcdb.ListToolsAlkerCheckoutsParams{
+		Status:           filters.Status,
+		Technician:       filters.Technician,
+		TechnicianID:     filters.TechnicianID,
+		ToolsAlkerItemID: filters.ToolsAlkerItemID,
+		LocationID:       filters.LocationID,
+		Limit:            int32(pagination.Limit),
+		Offset:           int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get tools alker checkouts", h.logger)
+		return
+	}
+
+	response := make([]ToolsAlkerCheckoutResponse, 0, len(checkouts))
+	for _, checkout := range checkouts {
+		response = append(response, toToolsAlkerCheckoutResponse(sqlcdb.GetToolsAlkerCheckoutRow(checkout)))
+	}
+
+	utils.SuccessWithPagination(c, message, response, pagination.Page, pagination.Limit, total)
+}
+
+// respond writes the checkout with its tools and location
+func (h *ToolsAlkerCheckoutHandler) respond(c *gin.Context, statusCode int, id int32, message string) {
+	checkout, err := h.queries.GetToolsAlkerCheckout(c.Request.Context(), id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get tools alker checkout", h.logger)
+		return
+	}
+
+	c.JSON(statusCode, utils.Response{
+		Success: true,
+		Message: message,
+		Data:    toToolsAlkerCheckoutResponse(checkout),
+	})
+}
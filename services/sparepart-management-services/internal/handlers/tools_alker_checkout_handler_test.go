@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+// expectToolsAlkerTransaction makes the mock run transactions against itself
+func expectToolsAlkerTransaction(repo *mocks.MockToolsAlkerRepository) {
+	repo.EXPECT().
+		WithinToolsAlkerTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(repository.ToolsAlkerRepository) error) error {
+			return fn(repo)
+		})
+}
+
+func TestToolsAlkerCheckoutHandlerCheckout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerCheckoutHandler(repo, testLogger)
+
+	returnDate := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	expected, _ := time.Parse("2006-01-02", returnDate)
+
+	expectToolsAlkerTransaction(repo)
+	repo.EXPECT().GetToolsAlkerForUpdate(gomock.Any(), int32(3)).Return(sqlcdb.ToolsAlkerItem{ID: 3, Quantity: 5}, nil)
+	repo.EXPECT().SumOpenToolsAlkerCheckouts(gomock.Any(), int32(3)).Return(int32(3), nil)
+	repo.EXPECT().CreateToolsAlkerCheckout(gomock.Any(), sqlcdb.CreateToolsAlkerCheckoutParams{
+		ToolsAlkerItemID:   3,
+		Quantity:           2,
+		Technician:         "Andi",
+		ExpectedReturnDate: pgtype.Date{Time: expected, Valid: true},
+		CheckedOutBy:       pgtype.Text{String: "budi", Valid: true},
+	}).Return(sqlcdb.ToolsAlkerCheckout{ID: 9}, nil)
+	repo.EXPECT().GetToolsAlkerCheckout(gomock.Any(), int32(9)).Return(sqlcdb.GetToolsAlkerCheckoutRow{
+		ID: 9, ToolsAlkerItemID: 3, Quantity: 2, Technician: "Andi", ExpectedReturnDate: pgtype.Date{Time: expected, Valid: true},
+	}, nil)
+
+	body := `{"technician": "Andi", "quantity": 2, "expected_return_date": "` + returnDate + `"}`
+	w := performRequestAs("budi", http.MethodPost, "/tools-alker/:id/checkout", h.Checkout, "/tools-alker/3/checkout", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var checkout ToolsAlkerCheckoutResponse
+	decodeResponse(t, w, &checkout)
+	if checkout.ID != 9 || checkout.ExpectedReturnDate != returnDate || checkout.CheckedInAt != nil {
+		t.Fatalf("unexpected checkout response: %+v", checkout)
+	}
+}
+
+func TestToolsAlkerCheckoutHandlerCheckoutExceedsAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerCheckoutHandler(repo, testLogger)
+
+	expectToolsAlkerTransaction(repo)
+	repo.EXPECT().GetToolsAlkerForUpdate(gomock.Any(), int32(3)).Return(sqlcdb.ToolsAlkerItem{ID: 3, Quantity: 5}, nil)
+	repo.EXPECT().SumOpenToolsAlkerCheckouts(gomock.Any(), int32(3)).Return(int32(4), nil)
+
+	body := `{"technician": "Andi", "quantity": 2, "expected_return_date": "` + time.Now().UTC().Format("2006-01-02") + `"}`
+	w := performRequest(http.MethodPost, "/tools-alker/:id/checkout", h.Checkout, "/tools-alker/3/checkout", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "quantity" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestToolsAlkerCheckoutHandlerCheckoutRejectsPastReturnDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerCheckoutHandler(repo, testLogger)
+
+	body := `{"technician": "Andi", "expected_return_date": "2020-01-01"}`
+	w := performRequest(http.MethodPost, "/tools-alker/:id/checkout", h.Checkout, "/tools-alker/3/checkout", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "expected_return_date" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestToolsAlkerCheckoutHandlerCheckinNoOpenCheckout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerCheckoutHandler(repo, testLogger)
+
+	repo.EXPECT().CheckInToolsAlkerCheckout(gomock.Any(), sqlcdb.CheckInToolsAlkerCheckoutParams{ID: 9, ToolsAlkerItemID: 3}).
+		Return(sqlcdb.ToolsAlkerCheckout{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/tools-alker/:id/checkin", h.Checkin, "/tools-alker/3/checkin", `{"checkout_id": 9}`)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestToolsAlkerCheckoutHandlerGetOverdue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerCheckoutHandler(repo, testLogger)
+
+	filters := sqlcdb.CountToolsAlkerCheckoutsParams{Status: pgtype.Text{String: checkoutStatusOverdue, Valid: true}, LocationID: pgtype.Int4{Int32: 2, Valid: true}}
+	repo.EXPECT().CountToolsAlkerCheckouts(gomock.Any(), filters).Return(int64(1), nil)
+	repo.EXPECT().ListToolsAlkerCheckouts(gomock.Any(), sqlcdb.ListToolsAlkerCheckoutsParams{
+		Status: filters.Status, LocationID: filters.LocationID, Limit: 10,
+	}).Return([]sqlcdb.ListToolsAlkerCheckoutsRow{{ID: 9, Overdue: true, DaysOverdue: 4}}, nil)
+
+	w := performRequest(http.MethodGet, "/tools-alker/checkouts/overdue", h.GetOverdue, "/tools-alker/checkouts/overdue?location_id=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var checkouts []ToolsAlkerCheckoutResponse
+	decodeResponse(t, w, &checkouts)
+	if len(checkouts) != 1 || !checkouts[0].Overdue || checkouts[0].DaysOverdue != 4 {
+		t.Fatalf("unexpected checkouts: %+v", checkouts)
+	}
+}
+
+func TestToolsAlkerCheckoutHandlerGetHeldByTechnician(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerCheckoutHandler(repo, testLogger)
+
+	repo.EXPECT().GetTechnician(gomock.Any(), int32(3)).Return(sqlcdb.Technician{ID: 3, Name: "Budi"}, nil)
+	// the path pins the technician over the technician_id query
+	filters := sqlcdb.CountToolsAlkerCheckoutsParams{Status: pgtype.Text{String: checkoutStatusOpen, Valid: true}, TechnicianID: pgtype.Int4{Int32: 3, Valid: true}}
+	repo.EXPECT().CountToolsAlkerCheckouts(gomock.Any(), filters).Return(int64(1), nil)
+	repo.EXPECT().ListToolsAlkerCheckouts(gomock.Any(), sqlcdb.ListToolsAlkerCheckoutsParams{
+		Status: filters.Status, TechnicianID: filters.TechnicianID, Limit: 10,
+	}).Return([]sqlcdb.ListToolsAlkerCheckoutsRow{{ID: 9, Technician: "Budi", TechnicianID: filters.TechnicianID}}, nil)
+
+	w := performRequest(http.MethodGet, "/technicians/:id/tools", h.GetHeldByTechnician, "/technicians/3/tools?technician_id=5", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var checkouts []ToolsAlkerCheckoutResponse
+	decodeResponse(t, w, &checkouts)
+	if len(checkouts) != 1 || checkouts[0].TechnicianID == nil || *checkouts[0].TechnicianID != 3 {
+		t.Fatalf("unexpected checkouts: %+v", checkouts)
+	}
+}
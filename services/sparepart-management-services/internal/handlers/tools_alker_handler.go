@@ -2,17 +2,20 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"sparepart-management-services/internal/database"
 	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/reports"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/uploads"
 	"sparepart-management-services/internal/utils"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
@@ -23,8 +26,11 @@ type ToolsAlkerResponse struct {
 	LocationID    int32                    `json:"location_id"`
 	ToolsID       int32                    `json:"tools_id"`
 	Quantity      int32                    `json:"quantity"`
-	Documentation []string                 `json:"documentation"`
+	CheckedOut    int32                    `json:"checked_out"`
+	Available     int32                    `json:"available"`
+	Documentation []DocumentationPhoto     `json:"documentation"`
 	Notes         *string                  `json:"notes,omitempty"`
+	Version       int32                    `json:"version"`
 	CreatedAt     string                   `json:"created_at"`
 	UpdatedAt     string                   `json:"updated_at"`
 	Location      ToolsAlkerLocation       `json:"location"`
@@ -56,44 +62,38 @@ type ToolsAlkerGroupedResponse struct {
 	Tools      []ToolsAlkerGroupedItem    `json:"tools"`
 	CreatedAt  string                     `json:"created_at"`  // from first tools item
 	UpdatedAt  string                     `json:"updated_at"`  // from first tools item
+	// ContactPersons of the location, only with include=contact_person
+	ContactPersons []LocationContactPerson `json:"contact_persons,omitempty"`
 }
 
 // ToolsAlkerGroupedItem represents a tools item in the grouped response
 type ToolsAlkerGroupedItem struct {
-	ID            int32    `json:"id"`            // tools_id
-	Name          string   `json:"name"`
-	ItemType      string   `json:"item_type"`
-	Quantity      int32    `json:"quantity"`
-	Documentation []string `json:"documentation"`
-	Notes         *string  `json:"notes,omitempty"`
+	ID            int32                `json:"id"`            // tools_id
+	ItemID        int32                `json:"item_id"`       // tools alker item id, used to check tools out
+	Name          string               `json:"name"`
+	ItemType      string               `json:"item_type"`
+	Quantity      int32                `json:"quantity"`
+	CheckedOut    int32                `json:"checked_out"`
+	Available     int32                `json:"available"`
+	Documentation []DocumentationPhoto `json:"documentation"`
+	Notes         *string              `json:"notes,omitempty"`
+	Version       int32                `json:"version"`
+}
+
+// availableToolsQuantity is the quantity of a tools alker item not lent out by open checkouts.
+// A quantity lowered below its open checkouts has none available rather than a negative count.
+func availableToolsQuantity(quantity, checkedOut int32) int32 {
+	return max(quantity-checkedOut, 0)
 }
 
 // transformToolsAlker transforms ListToolsAlkersRow to nested response
 func transformToolsAlker(row sqlcdb.ListToolsAlkersRow) ToolsAlkerResponse {
-	createdAt := ""
-	if row.CreatedAt.Valid {
-		createdAt = row.CreatedAt.Time.Format(time.RFC3339)
-	}
-	updatedAt := ""
-	if row.UpdatedAt.Valid {
-		updatedAt = row.UpdatedAt.Time.Format(time.RFC3339)
-	}
-	locationCreatedAt := ""
-	if row.LocationCreatedAt.Valid {
-		locationCreatedAt = row.LocationCreatedAt.Time.Format(time.RFC3339)
-	}
-	locationUpdatedAt := ""
-	if row.LocationUpdatedAt.Valid {
-		locationUpdatedAt = row.LocationUpdatedAt.Time.Format(time.RFC3339)
-	}
-	toolsCreatedAt := ""
-	if row.ToolsCreatedAt.Valid {
-		toolsCreatedAt = row.ToolsCreatedAt.Time.Format(time.RFC3339)
-	}
-	toolsUpdatedAt := ""
-	if row.ToolsUpdatedAt.Valid {
-		toolsUpdatedAt = row.ToolsUpdatedAt.Time.Format(time.RFC3339)
-	}
+	createdAt := utils.FormatTimestamp(row.CreatedAt)
+	updatedAt := utils.FormatTimestamp(row.UpdatedAt)
+	locationCreatedAt := utils.FormatTimestamp(row.LocationCreatedAt)
+	locationUpdatedAt := utils.FormatTimestamp(row.LocationUpdatedAt)
+	toolsCreatedAt := utils.FormatTimestamp(row.ToolsCreatedAt)
+	toolsUpdatedAt := utils.FormatTimestamp(row.ToolsUpdatedAt)
 
 	var notes *string
 	if row.Notes.Valid {
@@ -101,18 +101,18 @@ func transformToolsAlker(row sqlcdb.ListToolsAlkersRow) ToolsAlkerResponse {
 	}
 
 	// Parse documentation JSONB
-	var docs []string
-	if len(row.Documentation) > 0 {
-		json.Unmarshal(row.Documentation, &docs)
-	}
+	docs := documentationPhotos(row.Documentation)
 
 	return ToolsAlkerResponse{
 		ID:            row.ID,
 		LocationID:    row.LocationID,
 		ToolsID:       row.ToolsID,
 		Quantity:      row.Quantity,
+		CheckedOut:    row.CheckedOut,
+		Available:     availableToolsQuantity(row.Quantity, row.CheckedOut),
 		Documentation: docs,
 		Notes:         notes,
+		Version:       row.Version,
 		CreatedAt:     createdAt,
 		UpdatedAt:     updatedAt,
 		Location: ToolsAlkerLocation{
@@ -135,30 +135,12 @@ func transformToolsAlker(row sqlcdb.ListToolsAlkersRow) ToolsAlkerResponse {
 
 // transformToolsAlkerFromGet transforms GetToolsAlkerRow to nested response
 func transformToolsAlkerFromGet(row sqlcdb.GetToolsAlkerRow) ToolsAlkerResponse {
-	createdAt := ""
-	if row.CreatedAt.Valid {
-		createdAt = row.CreatedAt.Time.Format(time.RFC3339)
-	}
-	updatedAt := ""
-	if row.UpdatedAt.Valid {
-		updatedAt = row.UpdatedAt.Time.Format(time.RFC3339)
-	}
-	locationCreatedAt := ""
-	if row.LocationCreatedAt.Valid {
-		locationCreatedAt = row.LocationCreatedAt.Time.Format(time.RFC3339)
-	}
-	locationUpdatedAt := ""
-	if row.LocationUpdatedAt.Valid {
-		locationUpdatedAt = row.LocationUpdatedAt.Time.Format(time.RFC3339)
-	}
-	toolsCreatedAt := ""
-	if row.ToolsCreatedAt.Valid {
-		toolsCreatedAt = row.ToolsCreatedAt.Time.Format(time.RFC3339)
-	}
-	toolsUpdatedAt := ""
-	if row.ToolsUpdatedAt.Valid {
-		toolsUpdatedAt = row.ToolsUpdatedAt.Time.Format(time.RFC3339)
-	}
+	createdAt := utils.FormatTimestamp(row.CreatedAt)
+	updatedAt := utils.FormatTimestamp(row.UpdatedAt)
+	locationCreatedAt := utils.FormatTimestamp(row.LocationCreatedAt)
+	locationUpdatedAt := utils.FormatTimestamp(row.LocationUpdatedAt)
+	toolsCreatedAt := utils.FormatTimestamp(row.ToolsCreatedAt)
+	toolsUpdatedAt := utils.FormatTimestamp(row.ToolsUpdatedAt)
 
 	var notes *string
 	if row.Notes.Valid {
@@ -166,18 +148,18 @@ func transformToolsAlkerFromGet(row sqlcdb.GetToolsAlkerRow) ToolsAlkerResponse
 	}
 
 	// Parse documentation JSONB
-	var docs []string
-	if len(row.Documentation) > 0 {
-		json.Unmarshal(row.Documentation, &docs)
-	}
+	docs := documentationPhotos(row.Documentation)
 
 	return ToolsAlkerResponse{
 		ID:            row.ID,
 		LocationID:    row.LocationID,
 		ToolsID:       row.ToolsID,
 		Quantity:      row.Quantity,
+		CheckedOut:    row.CheckedOut,
+		Available:     availableToolsQuantity(row.Quantity, row.CheckedOut),
 		Documentation: docs,
 		Notes:         notes,
+		Version:       row.Version,
 		CreatedAt:     createdAt,
 		UpdatedAt:     updatedAt,
 		Location: ToolsAlkerLocation{
@@ -198,10 +180,12 @@ func transformToolsAlkerFromGet(row sqlcdb.GetToolsAlkerRow) ToolsAlkerResponse
 	}
 }
 
-// groupToolsAlkersByLocation groups flat list of tools alker items by location_id
+// groupToolsAlkersByLocation groups flat list of tools alker items by location_id,
+// keeping locations in the order they first appear in items
 func groupToolsAlkersByLocation(items []sqlcdb.ListToolsAlkersRow) []ToolsAlkerGroupedResponse {
 	// Map to store grouped data: location_id -> grouped response
 	locationMap := make(map[int32]*ToolsAlkerGroupedResponse)
+	var locationOrder []int32
 
 	for _, item := range items {
 		locationID := item.LocationID
@@ -209,23 +193,11 @@ func groupToolsAlkersByLocation(items []sqlcdb.ListToolsAlkersRow) []ToolsAlkerG
 		// Get or create grouped response for this location
 		grouped, exists := locationMap[locationID]
 		if !exists {
-			locationCreatedAt := ""
-			if item.LocationCreatedAt.Valid {
-				locationCreatedAt = item.LocationCreatedAt.Time.Format(time.RFC3339)
-			}
-			locationUpdatedAt := ""
-			if item.LocationUpdatedAt.Valid {
-				locationUpdatedAt = item.LocationUpdatedAt.Time.Format(time.RFC3339)
-			}
+			locationCreatedAt := utils.FormatTimestamp(item.LocationCreatedAt)
+			locationUpdatedAt := utils.FormatTimestamp(item.LocationUpdatedAt)
 
-			createdAt := ""
-			if item.CreatedAt.Valid {
-				createdAt = item.CreatedAt.Time.Format(time.RFC3339)
-			}
-			updatedAt := ""
-			if item.UpdatedAt.Valid {
-				updatedAt = item.UpdatedAt.Time.Format(time.RFC3339)
-			}
+			createdAt := utils.FormatTimestamp(item.CreatedAt)
+			updatedAt := utils.FormatTimestamp(item.UpdatedAt)
 
 			grouped = &ToolsAlkerGroupedResponse{
 				ID:         locationID,
@@ -243,6 +215,7 @@ func groupToolsAlkersByLocation(items []sqlcdb.ListToolsAlkersRow) []ToolsAlkerG
 				UpdatedAt: updatedAt,
 			}
 			locationMap[locationID] = grouped
+			locationOrder = append(locationOrder, locationID)
 		}
 
 		// Add tools item to the array
@@ -252,58 +225,73 @@ func groupToolsAlkersByLocation(items []sqlcdb.ListToolsAlkersRow) []ToolsAlkerG
 		}
 
 		// Parse documentation JSONB
-		var docs []string
-		if len(item.Documentation) > 0 {
-			json.Unmarshal(item.Documentation, &docs)
-		}
+		docs := documentationPhotos(item.Documentation)
 
 		toolsItem := ToolsAlkerGroupedItem{
 			ID:            item.ToolsID2,
+			ItemID:        item.ID,
 			Name:          item.ToolsName,
 			ItemType:      string(item.ItemType),
 			Quantity:      item.Quantity,
+			CheckedOut:    item.CheckedOut,
+			Available:     availableToolsQuantity(item.Quantity, item.CheckedOut),
 			Documentation: docs,
 			Notes:         notes,
+			Version:       item.Version,
 		}
 
 		grouped.Tools = append(grouped.Tools, toolsItem)
 	}
 
 	// Convert map to slice
-	result := make([]ToolsAlkerGroupedResponse, 0, len(locationMap))
-	for _, grouped := range locationMap {
-		result = append(result, *grouped)
+	result := make([]ToolsAlkerGroupedResponse, 0, len(locationOrder))
+	for _, locationID := range locationOrder {
+		result = append(result, *locationMap[locationID])
 	}
 
 	return result
 }
 
-// getGroupedToolsAlkerByLocationID gets all tools alker items for a location and returns grouped response
-func (h *ToolsAlkerHandler) getGroupedToolsAlkerByLocationID(ctx context.Context, locationID int32) (*ToolsAlkerGroupedResponse, error) {
-	// Get all tools alker items for this location (no filters)
-	listParams := sqlcdb.ListToolsAlkersParams{
-		Column1: "",
-		Column2: "",
-		Column3: "",
-		Column4: "",
-		Limit:   10000,
-		Offset:  0,
+// toolsAlkerByLocationRows converts location-scoped query rows so they can share the grouping logic
+func toolsAlkerByLocationRows(rows []sqlcdb.ListToolsAlkersByLocationRow) []sqlcdb.ListToolsAlkersRow {
+	items := make([]sqlcdb.ListToolsAlkersRow, len(rows))
+	for i, row := range rows {
+		items[i] = sqlcdb.ListToolsAlkersRow(row)
 	}
-	allItems, err := h.queries.ListToolsAlkers(ctx, listParams)
+	return items
+}
+
+// attachContactPersons sets the contact persons of every location in groups with one query
+func (h *ToolsAlkerHandler) attachContactPersons(ctx context.Context, groups []ToolsAlkerGroupedResponse) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	locationIDs := make([]int32, len(groups))
+	for i, group := range groups {
+		locationIDs[i] = group.LocationID
+	}
+	rows, err := h.queries.ListContactPersonsByLocations(ctx, locationIDs)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Filter items by location_id
-	var locationItems []sqlcdb.ListToolsAlkersRow
-	for _, toolsItem := range allItems {
-		if toolsItem.LocationID == locationID {
-			locationItems = append(locationItems, toolsItem)
-		}
+	contacts := groupContactPersonsByLocation(rows)
+	for i := range groups {
+		groups[i].ContactPersons = contacts[groups[i].LocationID]
+	}
+	return nil
+}
+
+// getGroupedToolsAlkerByLocationID gets all tools alker items for a location and returns grouped response
+func (h *ToolsAlkerHandler) getGroupedToolsAlkerByLocationID(ctx context.Context, locationID int32) (*ToolsAlkerGroupedResponse, error) {
+	rows, err := h.queries.ListToolsAlkersByLocation(ctx, locationID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Group by location_id
-	groupedItems := groupToolsAlkersByLocation(locationItems)
+	groupedItems := groupToolsAlkersByLocation(toolsAlkerByLocationRows(rows))
 	if len(groupedItems) == 0 {
 		return nil, fmt.Errorf("no tools alker items found for location_id %d", locationID)
 	}
@@ -318,44 +306,61 @@ type CreateToolsAlkerRequest struct {
 	Notes      *string `json:"notes,omitempty"`
 }
 
+// UpdateToolsAlkerRequest is a partial update: omitted fields keep their current value.
+// Documentation may only reorder or drop existing photo paths; new photos go through the photo endpoints.
+type UpdateToolsAlkerRequest struct {
+	Quantity      *int      `json:"quantity,omitempty"`
+	Notes         *string   `json:"notes,omitempty"` // empty string clears the notes
+	// Photo URLs to keep, in the new order; photos left out are deleted, the others keep their
+	// caption and metadata
+	Documentation *[]string `json:"documentation,omitempty"`
+	// Version the update is based on; required unless sent as the If-Match header
+	Version *int32 `json:"version,omitempty" binding:"omitempty,min=1"`
+}
+
 type ToolsAlkerHandler struct {
 	logger  *zap.Logger
-	queries *sqlcdb.Queries
+	queries repository.ToolsAlkerRepository
+	uploads *uploads.Service
+	reports *reports.Store
+	// maxPhotos caps the photos of an item; 0 is no limit
+	maxPhotos int
 }
 
-func NewToolsAlkerHandler() *ToolsAlkerHandler {
+func NewToolsAlkerHandler(queries repository.ToolsAlkerRepository, uploads *uploads.Service, reports *reports.Store, maxPhotos int, logger *zap.Logger) *ToolsAlkerHandler {
 	return &ToolsAlkerHandler{
-		logger:  utils.GetLogger(),
-		queries: sqlcdb.New(database.GetDB()),
+		logger:    logger,
+		queries:   queries,
+		uploads:   uploads,
+		reports:   reports,
+		maxPhotos: maxPhotos,
 	}
 }
 
 // buildToolsAlkerParams builds filter parameters from query string
 func (h *ToolsAlkerHandler) buildToolsAlkerParams(c *gin.Context) sqlcdb.CountToolsAlkersParams {
-	var region, regency, cluster, sparepartName string
-
-	if r := c.Query("region"); r != "" {
-		region = r
-	}
-	if r := c.Query("regency"); r != "" {
-		regency = r
-	}
-	if r := c.Query("cluster"); r != "" {
-		cluster = r
-	}
-	if sn := c.Query("sparepart_name"); sn != "" {
-		// For sparepart_name, we use the first name (sqlc query handles ILIKE)
-		names := strings.Split(sn, ",")
-		if len(names) > 0 {
-			sparepartName = strings.TrimSpace(names[0])
-		}
+	return sqlcdb.CountToolsAlkersParams{
+		Region:  utils.TextFilter(c.Query("region")),
+		Regency: utils.TextFilter(c.Query("regency")),
+		Cluster: utils.TextFilter(c.Query("cluster")),
+		Names:   utils.ListFilter(c.Query("sparepart_name")),
 	}
+}
 
-	return sqlcdb.CountToolsAlkersParams{
-		Column1: region,
-		Column2: regency,
-		Column3: cluster,
-		Column4: sparepartName,
+// exportReader pages through the export query with a keyset on the export sort order
+func (h *ToolsAlkerHandler) exportReader(ctx context.Context, params sqlcdb.ListToolsAlkersForExportParams) utils.BatchReader[sqlcdb.ListToolsAlkersForExportRow] {
+	params.Limit = exportBatchSize
+	return func() ([]sqlcdb.ListToolsAlkersForExportRow, error) {
+		rows, err := h.queries.ListToolsAlkersForExport(ctx, params)
+		if err != nil || len(rows) == 0 {
+			return rows, err
+		}
+		last := rows[len(rows)-1]
+		params.AfterID = pgtype.Int4{Int32: last.ID, Valid: true}
+		params.AfterRegion = sqlcdb.NullRegionType{RegionType: last.Region, Valid: true}
+		params.AfterRegency = pgtype.Text{String: last.Regency, Valid: true}
+		params.AfterName = pgtype.Text{String: last.ToolsName, Valid: true}
+		return rows, nil
 	}
 }
 
@@ -368,8 +373,12 @@ func (h *ToolsAlkerHandler) buildToolsAlkerParams(c *gin.Context) sqlcdb.CountTo
 // @Param region query string false "Filter by region (exact match)"
 // @Param regency query string false "Filter by regency (partial match, case-insensitive)"
 // @Param cluster query string false "Filter by cluster (partial match, case-insensitive)"
+// @Param group_by query string false "location: items grouped per location, paginated by location; none: one entry per tools alker item, paginated by item" Enums(location, none) default(location)
+// @Param fields query string false "Fields to return, comma-separated, dotted for nested fields (e.g. id,location.cluster)"
+// @Param expand query string false "Nested objects to include in full; once given, other nested objects are reduced to their id"
+// @Param include query string false "Related data to add to each location: contact_person (group_by=location only)" Enums(contact_person)
 // @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
+// @Param limit query int false "Items per page (max 100)" default(10)
 // @Success 200 {object} utils.PaginatedResponse
 // @Router /sparepart/tools-alker [get]
 func (h *ToolsAlkerHandler) GetAll(c *gin.Context) {
@@ -379,8 +388,24 @@ func (h *ToolsAlkerHandler) GetAll(c *gin.Context) {
 	filterParams := h.buildToolsAlkerParams(c)
 
 	// Get pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	pagination, errs := utils.ParsePagination(c)
+	flat, groupByErrs := utils.ParseGroupBy(c)
+	errs = append(errs, groupByErrs...)
+	shape, shapeErrs := utils.ParseResponseShape(c)
+	errs = append(errs, shapeErrs...)
+	include, includeErrs := utils.ParseInclude(c, includeContactPerson)
+	errs = append(errs, includeErrs...)
+	if flat && include[includeContactPerson] {
+		errs = append(errs, utils.FieldError{Field: "include", Message: "contact_person needs group_by=location"})
+	}
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+	if flat {
+		h.getAllFlat(c, filterParams, pagination, shape)
+		return
+	}
 
 	// Count total (count distinct locations)
 	total, err := h.queries.CountToolsAlkers(ctx, filterParams)
@@ -389,14 +414,14 @@ func (h *ToolsAlkerHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	// List items - get all items (no limit/offset here, we'll group and paginate after)
+	// List items - limit/offset apply to locations, each location comes with all of its items
 	listParams := sqlcdb.ListToolsAlkersParams{
-		Column1: filterParams.Column1,
-		Column2: filterParams.Column2,
-		Column3: filterParams.Column3,
-		Column4: filterParams.Column4,
-		Limit:   10000, // Large limit to get all items for grouping
-		Offset:  0,
+		Region:  filterParams.Region,
+		Regency: filterParams.Regency,
+		Cluster: filterParams.Cluster,
+		Names:   filterParams.Names,
+		Limit:   int32(pagination.Limit),
+		Offset:  int32(pagination.Offset()),
 	}
 	items, err := h.queries.ListToolsAlkers(ctx, listParams)
 	if err != nil {
@@ -405,24 +430,58 @@ func (h *ToolsAlkerHandler) GetAll(c *gin.Context) {
 	}
 
 	// Group by location_id
-	groupedItems := groupToolsAlkersByLocation(items)
+	paginatedItems := groupToolsAlkersByLocation(items)
+	if include[includeContactPerson] {
+		if err := h.attachContactPersons(ctx, paginatedItems); err != nil {
+			utils.HandleError(c, err, "Failed to get contact persons", h.logger)
+			return
+		}
+	}
+
+	data, err := shape.Apply(paginatedItems)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to shape response", h.logger)
+		return
+	}
 
-	// Apply pagination to grouped items (per location)
-	startIdx := (page - 1) * limit
-	endIdx := startIdx + limit
-	if startIdx > len(groupedItems) {
-		startIdx = len(groupedItems)
+	utils.SuccessWithPagination(c, "Tools alker items retrieved successfully", data, pagination.Page, pagination.Limit, total)
+}
+
+// getAllFlat lists the tools alker items one per entry (group_by=none); limit/offset apply to items
+func (h *ToolsAlkerHandler) getAllFlat(c *gin.Context, filterParams sqlcdb.CountToolsAlkersParams, pagination utils.Pagination, shape *utils.ResponseShape) {
+	ctx := c.Request.Context()
+
+	total, err := h.queries.CountToolsAlkerItems(ctx, sqlcdb.CountToolsAlkerItemsParams(filterParams))
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count tools alker items", h.logger)
+		return
+	}
+
+	rows, err := h.queries.ListToolsAlkerItems(ctx, sqlcdb.ListToolsAlkerItemsParams{
+		Region:  filterParams.Region,
+		Regency: filterParams.Regency,
+		Cluster: filterParams.Cluster,
+		Names:   filterParams.Names,
+		Limit:   int32(pagination.Limit),
+		Offset:  int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get tools alker items", h.logger)
+		return
 	}
-	if endIdx > len(groupedItems) {
-		endIdx = len(groupedItems)
+
+	response := make([]ToolsAlkerResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, transformToolsAlker(sqlcdb.ListToolsAlkersRow(row)))
 	}
 
-	var paginatedItems []ToolsAlkerGroupedResponse
-	if startIdx < len(groupedItems) {
-		paginatedItems = groupedItems[startIdx:endIdx]
+	data, err := shape.Apply(response)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to shape response", h.logger)
+		return
 	}
 
-	utils.SuccessWithPagination(c, "Tools alker items retrieved successfully", paginatedItems, page, limit, total)
+	utils.SuccessWithPagination(c, "Tools alker items retrieved successfully", data, pagination.Page, pagination.Limit, total)
 }
 
 // @Summary Get tools alker item by ID (returns grouped by location)
@@ -431,6 +490,9 @@ func (h *ToolsAlkerHandler) GetAll(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Tools Alker Item ID"
+// @Param fields query string false "Fields to return, comma-separated, dotted for nested fields (e.g. id,location.cluster)"
+// @Param expand query string false "Nested objects to include in full; once given, other nested objects are reduced to their id"
+// @Param include query string false "Related data to add to the location: contact_person" Enums(contact_person)
 // @Success 200 {object} utils.Response
 // @Router /sparepart/tools-alker/{id} [get]
 func (h *ToolsAlkerHandler) GetByID(c *gin.Context) {
@@ -441,6 +503,13 @@ func (h *ToolsAlkerHandler) GetByID(c *gin.Context) {
 		utils.BadRequest(c, "Invalid tools alker item ID")
 		return
 	}
+	shape, errs := utils.ParseResponseShape(c)
+	include, includeErrs := utils.ParseInclude(c, includeContactPerson)
+	errs = append(errs, includeErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
 
 	// Get the tools alker item to find its location_id
 	item, err := h.queries.GetToolsAlker(ctx, int32(id))
@@ -450,37 +519,33 @@ func (h *ToolsAlkerHandler) GetByID(c *gin.Context) {
 	}
 
 	// Get all tools alker items for this location
-	listParams := sqlcdb.ListToolsAlkersParams{
-		Column1: "",
-		Column2: "",
-		Column3: "",
-		Column4: "",
-		Limit:   10000,
-		Offset:  0,
-	}
-	allItems, err := h.queries.ListToolsAlkers(ctx, listParams)
+	rows, err := h.queries.ListToolsAlkersByLocation(ctx, item.LocationID)
 	if err != nil {
 		utils.HandleError(c, err, "Failed to get tools alker items", h.logger)
 		return
 	}
 
-	// Filter items by location_id
-	var locationItems []sqlcdb.ListToolsAlkersRow
-	for _, toolsItem := range allItems {
-		if toolsItem.LocationID == item.LocationID {
-			locationItems = append(locationItems, toolsItem)
-		}
-	}
-
 	// Group by location_id (should be only one location)
-	groupedItems := groupToolsAlkersByLocation(locationItems)
+	groupedItems := groupToolsAlkersByLocation(toolsAlkerByLocationRows(rows))
 	if len(groupedItems) == 0 {
 		utils.NotFound(c, "Location not found")
 		return
 	}
+	if include[includeContactPerson] {
+		if err := h.attachContactPersons(ctx, groupedItems); err != nil {
+			utils.HandleError(c, err, "Failed to get contact persons", h.logger)
+			return
+		}
+	}
 
 	// Return the first (and only) grouped item
-	utils.Success(c, "Tools alker items retrieved successfully", groupedItems[0])
+	data, err := shape.Apply(groupedItems[0])
+	if err != nil {
+		utils.HandleError(c, err, "Failed to shape response", h.logger)
+		return
+	}
+
+	utils.Success(c, "Tools alker items retrieved successfully", data)
 }
 
 // @Summary Create tools alker item with photos
@@ -493,7 +558,11 @@ func (h *ToolsAlkerHandler) GetByID(c *gin.Context) {
 // @Param quantity formData int false "Quantity"
 // @Param notes formData string false "Notes"
 // @Param photos formData file false "Photo files (multiple allowed)"
+// @Param captions formData []string false "Caption of each photo, in the order of the photos"
+// @Param taken_at formData []string false "When each photo was taken (RFC3339), in the order of the photos"
 // @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response "Invalid request, or more photos than an item can have"
+// @Failure 507 {object} utils.Response "Upload quota exceeded"
 // @Router /sparepart/tools-alker [post]
 func (h *ToolsAlkerHandler) Create(c *gin.Context) {
 	var req CreateToolsAlkerRequest
@@ -527,6 +596,10 @@ func (h *ToolsAlkerHandler) Create(c *gin.Context) {
 			req.Quantity = quantity
 		}
 	}
+	if req.Quantity < 0 {
+		utils.ValidationError(c, utils.NegativeQuantityError("quantity"))
+		return
+	}
 
 	// Parse notes
 	if notes != "" {
@@ -535,21 +608,13 @@ func (h *ToolsAlkerHandler) Create(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
-	// Process file uploads
-	var documentation []string
-	form, err := c.MultipartForm()
-	if err == nil && form.File != nil {
-		files := form.File["photos"]
-		subDir := "tools_alker"
-		prefix := "tools_alker"
-		for _, file := range files {
-			path, err := utils.ProcessImageUpload(file, subDir, prefix, h.logger)
-			if err != nil {
-				utils.BadRequest(c, "Failed to upload photo: "+err.Error())
-				return
-			}
-			documentation = append(documentation, path)
-		}
+	// Stage file uploads; they are only moved into place once the item is created
+	if !checkPhotoLimit(c, 0, h.maxPhotos) {
+		return
+	}
+	documentation, staged, ok := stagePhotos(c, h.uploads, "tools_alker", "tools_alker", h.logger)
+	if !ok {
+		return
 	}
 
 	// Convert notes to pgtype.Text
@@ -564,12 +629,22 @@ func (h *ToolsAlkerHandler) Create(c *gin.Context) {
 		LocationID:    int32(req.LocationID),
 		ToolsID:       int32(req.ToolsID),
 		Quantity:      int32(req.Quantity),
-		Documentation: documentationToBytes(documentation),
+		Documentation: utils.PhotosJSON(documentation),
 		Notes:         notesText,
 	}
 
-	item, err := h.queries.CreateToolsAlker(ctx, createParams)
+	var item sqlcdb.ToolsAlkerItem
+	err = h.queries.WithinToolsAlkerTransaction(ctx, func(repo repository.ToolsAlkerRepository) error {
+		var err error
+		item, err = repo.CreateToolsAlker(ctx, createParams)
+		if err != nil {
+			return err
+		}
+		// Move photos into place before commit, a failed move rolls back the insert
+		return h.uploads.CommitStaged(ctx, staged, h.logger)
+	})
 	if err != nil {
+		h.uploads.DiscardStaged(staged, h.logger)
 		utils.HandleError(c, err, "Failed to create tools alker item", h.logger)
 		return
 	}
@@ -589,15 +664,104 @@ func (h *ToolsAlkerHandler) Create(c *gin.Context) {
 	})
 }
 
+// CreateToolsAlkerBatchRequest is the JSON body for creating many tools alker items at once
+type CreateToolsAlkerBatchRequest struct {
+	Items []CreateToolsAlkerRequest `json:"items" binding:"required,min=1,max=1000,dive"`
+}
+
+// ToolsAlkerBatchItem is a created tools alker item as returned by the batch create endpoint
+type ToolsAlkerBatchItem struct {
+	ID         int32   `json:"id"`
+	LocationID int32   `json:"location_id"`
+	ToolsID    int32   `json:"tools_id"`
+	Quantity   int32   `json:"quantity"`
+	Notes      *string `json:"notes,omitempty"`
+}
+
+// @Summary Create tools alker items in batch
+// @Description Create many tools alker items in a single insert (all or nothing). Photos are added afterwards per item.
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param items body CreateToolsAlkerBatchRequest true "Tools alker items"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/tools-alker/batch [post]
+func (h *ToolsAlkerHandler) CreateBatch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateToolsAlkerBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	params := sqlcdb.CreateToolsAlkersBatchParams{
+		LocationIds: make([]int32, 0, len(req.Items)),
+		ToolsIds:    make([]int32, 0, len(req.Items)),
+		Quantities:  make([]int32, 0, len(req.Items)),
+		Notes:       make([]string, 0, len(req.Items)),
+	}
+	var fieldErrors []utils.FieldError
+	for i, item := range req.Items {
+		if item.Quantity < 0 {
+			fieldErrors = append(fieldErrors, utils.NegativeQuantityError(fmt.Sprintf("items[%d].quantity", i)))
+		}
+		notes := ""
+		if item.Notes != nil {
+			notes = *item.Notes
+		}
+		params.LocationIds = append(params.LocationIds, int32(item.LocationID))
+		params.ToolsIds = append(params.ToolsIds, int32(item.ToolsID))
+		params.Quantities = append(params.Quantities, int32(item.Quantity))
+		params.Notes = append(params.Notes, notes)
+	}
+
+	if len(fieldErrors) > 0 {
+		utils.ValidationError(c, fieldErrors...)
+		return
+	}
+
+	items, err := h.queries.CreateToolsAlkersBatch(ctx, params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create tools alker items", h.logger)
+		return
+	}
+
+	created := make([]ToolsAlkerBatchItem, 0, len(items))
+	for _, item := range items {
+		var notes *string
+		if item.Notes.Valid {
+			notes = &item.Notes.String
+		}
+		created = append(created, ToolsAlkerBatchItem{
+			ID:         item.ID,
+			LocationID: item.LocationID,
+			ToolsID:    item.ToolsID,
+			Quantity:   item.Quantity,
+			Notes:      notes,
+		})
+	}
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: fmt.Sprintf("%d tools alker items created successfully", len(created)),
+		Data:    created,
+	})
+}
+
 // @Summary Update tools alker item
-// @Description Update an existing tools alker item
+// @Description Update an existing tools alker item. The update must name the item version it is based on (If-Match header or version field) and fails with 409 and the current version when the item was changed since.
 // @Tags Tools Alker
 // @Accept json
 // @Produce json
 // @Param id path int true "Tools Alker Item ID"
-// @Param item body CreateToolsAlkerRequest true "Update data"
+// @Param If-Match header string false "Item version the update is based on, e.g. \"3\""
+// @Param item body UpdateToolsAlkerRequest true "Fields to update (omitted fields are unchanged)"
 // @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict}
+// @Failure 428 {object} utils.Response
 // @Router /sparepart/tools-alker/{id} [put]
+// @Router /sparepart/tools-alker/{id} [patch]
 func (h *ToolsAlkerHandler) Update(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -608,36 +772,84 @@ func (h *ToolsAlkerHandler) Update(c *gin.Context) {
 	}
 
 	// Check if item exists
-	_, err = h.queries.GetToolsAlker(ctx, int32(id))
+	existing, err := h.queries.GetToolsAlker(ctx, int32(id))
 	if err != nil {
 		utils.NotFound(c, "Tools alker item not found")
 		return
 	}
 
-	var req CreateToolsAlkerRequest
+	var req UpdateToolsAlkerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, err.Error())
+		utils.BindingError(c, err)
 		return
 	}
-
-	// Convert notes to pgtype.Text
-	var notes pgtype.Text
-	if req.Notes != nil {
-		notes.String = *req.Notes
-		notes.Valid = true
+	if req.Quantity == nil && req.Notes == nil && req.Documentation == nil {
+		utils.BadRequest(c, "No fields to update")
+		return
+	}
+	if req.Quantity != nil && *req.Quantity < 0 {
+		utils.ValidationError(c, utils.NegativeQuantityError("quantity"))
+		return
+	}
+	version, ok := utils.ExpectedVersion(c, req.Version)
+	if !ok {
+		return
 	}
 
 	updateParams := sqlcdb.UpdateToolsAlkerParams{
 		ID:       int32(id),
-		Quantity: int32(req.Quantity),
-		Notes:    notes,
+		Quantity: utils.OptionalInt(req.Quantity),
+		Notes:    utils.OptionalText(req.Notes),
+		Version:  version,
+	}
+
+	// Photos dropped from the documentation list are deleted after the update; the photos kept
+	// keep their metadata in the new order
+	var removedPhotos []string
+	if req.Documentation != nil {
+		current := utils.ParsePhotos(existing.Documentation)
+		byPath := make(map[string]utils.Photo, len(current))
+		for _, photo := range current {
+			byPath[photo.URL] = photo
+		}
+		ordered := make([]utils.Photo, 0, len(*req.Documentation))
+		kept := make(map[string]bool, len(*req.Documentation))
+		for i, path := range *req.Documentation {
+			photo, ok := byPath[path]
+			if !ok {
+				utils.ValidationError(c, utils.FieldError{
+					Field:   fmt.Sprintf("documentation[%d]", i),
+					Message: "must be an existing photo of this item",
+				})
+				return
+			}
+			kept[path] = true
+			ordered = append(ordered, photo)
+		}
+		for _, photo := range current {
+			if !kept[photo.URL] {
+				removedPhotos = append(removedPhotos, photo.URL)
+			}
+		}
+		updateParams.Documentation = utils.PhotosJSON(ordered)
 	}
 
 	item, err := h.queries.UpdateToolsAlker(ctx, updateParams)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.respondVersionConflict(c, int32(id))
+			return
+		}
 		utils.HandleError(c, err, "Failed to update tools alker item", h.logger)
 		return
 	}
+	c.Header("ETag", utils.ETag(item.Version))
+
+	for _, path := range removedPhotos {
+		if err := h.uploads.DeleteFile(ctx, path, h.logger); err != nil {
+			utils.RequestLogger(ctx, h.logger).Warn("Failed to delete removed photo", zap.String("path", path), zap.Error(err))
+		}
+	}
 
 	// Get full item with relations
 	// Get grouped response for this location
@@ -650,8 +862,19 @@ func (h *ToolsAlkerHandler) Update(c *gin.Context) {
 	utils.Success(c, "Tools alker item updated successfully", groupedResponse)
 }
 
+// respondVersionConflict answers an update that matched no row: the item changed version
+// since the client read it, or was deleted meanwhile
+func (h *ToolsAlkerHandler) respondVersionConflict(c *gin.Context, id int32) {
+	current, err := h.queries.GetToolsAlker(c.Request.Context(), id)
+	if err != nil {
+		utils.NotFound(c, "Tools alker item not found")
+		return
+	}
+	utils.VersionConflictError(c, current.Version)
+}
+
 // @Summary Delete tools alker item
-// @Description Delete a tools alker item
+// @Description Soft delete a tools alker item; it and its photos are kept until restored or purged
 // @Tags Tools Alker
 // @Accept json
 // @Produce json
@@ -667,22 +890,14 @@ func (h *ToolsAlkerHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// Get item to delete photos
-	item, err := h.queries.GetToolsAlker(ctx, int32(id))
+	// Check if item exists
+	_, err = h.queries.GetToolsAlker(ctx, int32(id))
 	if err != nil {
 		utils.NotFound(c, "Tools alker item not found")
 		return
 	}
 
-	// Delete all photos from storage
-	docs := documentationFromBytes(item.Documentation)
-	for _, path := range docs {
-		if err := utils.DeleteFile(path, h.logger); err != nil {
-			h.logger.Warn("Failed to delete file", zap.Error(err), zap.String("path", path))
-		}
-	}
-
-	// Delete item
+	// Photos stay in storage so the item can be restored; the admin purge removes them
 	err = h.queries.DeleteToolsAlker(ctx, int32(id))
 	if err != nil {
 		utils.HandleError(c, err, "Failed to delete tools alker item", h.logger)
@@ -692,6 +907,43 @@ func (h *ToolsAlkerHandler) Delete(c *gin.Context) {
 	utils.Success(c, "Tools alker item deleted successfully", nil)
 }
 
+// @Summary Restore tools alker item
+// @Description Restore a soft deleted tools alker item; not while its location is deleted
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param id path int true "Tools Alker Item ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response "A tools alker item with the same location and tool was created meanwhile"
+// @Router /sparepart/tools-alker/{id}/restore [post]
+func (h *ToolsAlkerHandler) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tools alker item ID")
+		return
+	}
+
+	item, err := h.queries.RestoreToolsAlker(ctx, int32(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.NotFound(c, "Deleted tools alker item not found")
+			return
+		}
+		utils.HandleError(c, err, "Failed to restore tools alker item", h.logger)
+		return
+	}
+
+	groupedResponse, err := h.getGroupedToolsAlkerByLocationID(ctx, item.LocationID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to retrieve grouped tools alker items", h.logger)
+		return
+	}
+
+	utils.Success(c, "Tools alker item restored successfully", groupedResponse)
+}
+
 // @Summary Export tools alker to PDF
 // @Description Export tools alker items to PDF with filters (landscape mode)
 // @Tags Tools Alker
@@ -701,6 +953,8 @@ func (h *ToolsAlkerHandler) Delete(c *gin.Context) {
 // @Param region query string false "Filter by region"
 // @Param regency query string false "Filter by regency"
 // @Param cluster query string false "Filter by cluster"
+// @Param include_photos query bool false "Append the thumbnails of every item's photos"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
 // @Success 200 {file} application/pdf
 // @Router /sparepart/tools-alker/export/pdf [get]
 func (h *ToolsAlkerHandler) ExportPDF(c *gin.Context) {
@@ -709,30 +963,28 @@ func (h *ToolsAlkerHandler) ExportPDF(c *gin.Context) {
 	// Get filter parameters
 	filterParams := h.buildToolsAlkerParams(c)
 
-	// List items for export (no pagination)
+	// Items are read in keyset batches while the file is written
 	exportParams := sqlcdb.ListToolsAlkersForExportParams{
-		Column1: filterParams.Column1,
-		Column2: filterParams.Column2,
-		Column3: filterParams.Column3,
-		Column4: filterParams.Column4,
+		Region:  filterParams.Region,
+		Regency: filterParams.Regency,
+		Cluster: filterParams.Cluster,
+		Names:   filterParams.Names,
 	}
 
-	items, err := h.queries.ListToolsAlkersForExport(ctx, exportParams)
-	if err != nil {
-		utils.HandleError(c, err, "Failed to get tools alker items", h.logger)
-		return
+	var rows int
+	var photos utils.ThumbnailLoader
+	if c.Query("include_photos") == "true" {
+		photos = h.uploads
 	}
-
-	buf, err := utils.ExportToolsAlkerToPDF(items, h.logger)
+	buf, err := utils.ExportToolsAlkerToPDF(ctx, utils.CountRows(h.exportReader(ctx, exportParams), &rows), photos, h.logger)
 	if err != nil {
 		utils.HandleError(c, err, "Failed to generate PDF", h.logger)
 		return
 	}
 
-	filename := fmt.Sprintf("tools_alker_%s.pdf", time.Now().Format("20060102_150405"))
-	c.Header("Content-Disposition", "attachment; filename="+filename)
-	c.Header("Content-Type", "application/pdf")
-	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+	c.Set(utils.ExportRowsKey, rows)
+	filename := fmt.Sprintf("tools_alker_%s.pdf", time.Now().UTC().Format("20060102_150405"))
+	sendExport(c, h.reports, buf.Bytes(), filename, "application/pdf", h.logger)
 }
 
 // @Summary Export tools alker to Excel
@@ -744,6 +996,7 @@ func (h *ToolsAlkerHandler) ExportPDF(c *gin.Context) {
 // @Param region query string false "Filter by region"
 // @Param regency query string false "Filter by regency"
 // @Param cluster query string false "Filter by cluster"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
 // @Success 200 {file} application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Router /sparepart/tools-alker/export/excel [get]
 func (h *ToolsAlkerHandler) ExportExcel(c *gin.Context) {
@@ -752,30 +1005,131 @@ func (h *ToolsAlkerHandler) ExportExcel(c *gin.Context) {
 	// Get filter parameters
 	filterParams := h.buildToolsAlkerParams(c)
 
-	// List items for export (no pagination)
+	// Items are read in keyset batches while the file is written
 	exportParams := sqlcdb.ListToolsAlkersForExportParams{
-		Column1: filterParams.Column1,
-		Column2: filterParams.Column2,
-		Column3: filterParams.Column3,
-		Column4: filterParams.Column4,
+		Region:  filterParams.Region,
+		Regency: filterParams.Regency,
+		Cluster: filterParams.Cluster,
+		Names:   filterParams.Names,
 	}
 
-	items, err := h.queries.ListToolsAlkersForExport(ctx, exportParams)
+	var rows int
+	buf, err := utils.ExportToolsAlkerToExcel(utils.CountRows(h.exportReader(ctx, exportParams), &rows), h.logger)
 	if err != nil {
-		utils.HandleError(c, err, "Failed to get tools alker items", h.logger)
+		utils.HandleError(c, err, "Failed to generate Excel", h.logger)
 		return
 	}
 
-	buf, err := utils.ExportToolsAlkerToExcel(items, h.logger)
+	c.Set(utils.ExportRowsKey, rows)
+	filename := fmt.Sprintf("tools_alker_%s.xlsx", time.Now().UTC().Format("20060102_150405"))
+	sendExport(c, h.reports, buf.Bytes(), filename, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", h.logger)
+}
+
+// @Summary Export tools alker to CSV
+// @Description Export tools alker items to CSV with filters; the file is streamed while it is written
+// @Tags Tools Alker
+// @Accept json
+// @Produce text/csv
+// @Param sparepart_name query string false "Filter by sparepart name (comma-separated)"
+// @Param region query string false "Filter by region"
+// @Param regency query string false "Filter by regency"
+// @Param cluster query string false "Filter by cluster"
+// @Param store query bool false "Store the report and return a shareable link instead of downloading"
+// @Success 200 {file} text/csv
+// @Router /sparepart/tools-alker/export/csv [get]
+func (h *ToolsAlkerHandler) ExportCSV(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get filter parameters
+	filterParams := h.buildToolsAlkerParams(c)
+
+	// Items are read in keyset batches while the file is written
+	exportParams := sqlcdb.ListToolsAlkersForExportParams{
+		Region:  filterParams.Region,
+		Regency: filterParams.Regency,
+		Cluster: filterParams.Cluster,
+		Names:   filterParams.Names,
+	}
+
+	var rows int
+	filename := fmt.Sprintf("tools_alker_%s.csv", time.Now().UTC().Format("20060102_150405"))
+	streamExport(c, h.reports, filename, "text/csv; charset=utf-8", func(w io.Writer) error {
+		err := utils.WriteToolsAlkerCSV(w, utils.CountRows(h.exportReader(ctx, exportParams), &rows))
+		c.Set(utils.ExportRowsKey, rows)
+		return err
+	}, h.logger)
+}
+
+// @Summary Add photos to tools alker item
+// @Description Add photos to an existing tools alker item
+// @Tags Tools Alker
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Tools Alker Item ID"
+// @Param photos formData file true "Photo files (multiple allowed)"
+// @Param captions formData []string false "Caption of each photo, in the order of the photos"
+// @Param taken_at formData []string false "When each photo was taken (RFC3339), in the order of the photos"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response "Invalid request, or more photos than an item can have"
+// @Failure 507 {object} utils.Response "Upload quota exceeded"
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict} "The item changed while its photos were being changed"
+// @Router /sparepart/tools-alker/{id}/photos [post]
+func (h *ToolsAlkerHandler) AddPhotos(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
 	if err != nil {
-		utils.HandleError(c, err, "Failed to generate Excel", h.logger)
+		utils.BadRequest(c, "Invalid tools alker item ID")
 		return
 	}
 
-	filename := fmt.Sprintf("tools_alker_%s.xlsx", time.Now().Format("20060102_150405"))
-	c.Header("Content-Disposition", "attachment; filename="+filename)
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+	// Get existing item
+	item, err := h.queries.GetToolsAlker(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Tools alker item not found")
+		return
+	}
+
+	if !checkPhotoLimit(c, len(utils.ParsePhotos(item.Documentation)), h.maxPhotos) {
+		return
+	}
+
+	// Process file uploads
+	photos, ok := uploadPhotos(c, h.uploads, "tools_alker", "tools_alker", h.logger)
+	if !ok {
+		return
+	}
+
+	// Append new photos to existing documentation
+	existingDocs := append(utils.ParsePhotos(item.Documentation), photos...)
+
+	// Update documentation
+	updateParams := sqlcdb.UpdateToolsAlkerDocumentationParams{
+		ID:            int32(id),
+		Documentation: utils.PhotosJSON(existingDocs),
+		Version:       item.Version,
+	}
+
+	_, err = h.queries.UpdateToolsAlkerDocumentation(ctx, updateParams)
+	if err != nil {
+		// Nothing refers to the new photos
+		releasePhotos(ctx, h.uploads, photos, h.logger)
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.respondVersionConflict(c, int32(id))
+			return
+		}
+		utils.HandleError(c, err, "Failed to update photos", h.logger)
+		return
+	}
+
+	// Get grouped response for this location
+	groupedResponse, err := h.getGroupedToolsAlkerByLocationID(ctx, item.LocationID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to retrieve grouped tools alker items", h.logger)
+		return
+	}
+
+	utils.Success(c, "Photos added successfully", groupedResponse)
 }
 
 // @Summary Update photo in tools alker item
@@ -786,7 +1140,10 @@ func (h *ToolsAlkerHandler) ExportExcel(c *gin.Context) {
 // @Param id path int true "Tools Alker Item ID"
 // @Param photo_index path int true "Photo index in documentation array"
 // @Param photo formData file true "New photo file"
+// @Param caption formData string false "Caption of the new photo; the old photo's caption is kept when omitted"
+// @Param taken_at formData string false "When the new photo was taken (RFC3339)"
 // @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict} "The item changed while its photos were being changed"
 // @Router /sparepart/tools-alker/{id}/photos/{photo_index} [put]
 func (h *ToolsAlkerHandler) UpdatePhoto(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -797,8 +1154,8 @@ func (h *ToolsAlkerHandler) UpdatePhoto(c *gin.Context) {
 		return
 	}
 
-	photoIndex, err := strconv.Atoi(c.Param("photo_index"))
-	if err != nil {
+	photoIndex, ok := utils.ParseIndexParam(c, "photo_index")
+	if !ok {
 		utils.BadRequest(c, "Invalid photo index")
 		return
 	}
@@ -811,18 +1168,19 @@ func (h *ToolsAlkerHandler) UpdatePhoto(c *gin.Context) {
 	}
 
 	// Get existing documentation
-	docs := documentationFromBytes(item.Documentation)
+	docs := utils.ParsePhotos(item.Documentation)
 	if photoIndex < 0 || photoIndex >= len(docs) {
 		utils.BadRequest(c, "Photo index out of range")
 		return
 	}
-
-	// Delete old photo file
-	oldFilePath := docs[photoIndex]
-	if err := utils.DeleteFile(oldFilePath, h.logger); err != nil {
-		h.logger.Warn("Failed to delete old file", zap.Error(err), zap.String("path", oldFilePath))
+	metadata, errs := utils.ParseSinglePhotoMetadata(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
 	}
 
+	oldFilePath := docs[photoIndex].URL
+
 	// Get new photo from form
 	file, err := c.FormFile("photo")
 	if err != nil {
@@ -833,35 +1191,107 @@ func (h *ToolsAlkerHandler) UpdatePhoto(c *gin.Context) {
 	// Upload new photo
 	subDir := "tools_alker"
 	prefix := "tools_alker"
-	newPath, err := utils.ProcessImageUpload(file, subDir, prefix, h.logger)
+	newPath, err := h.uploads.ProcessImage(ctx, file, subDir, prefix, h.logger)
 	if err != nil {
 		utils.BadRequest(c, "Failed to upload photo: "+err.Error())
 		return
 	}
 
 	// Update documentation array
-	docs[photoIndex] = newPath
+	docs[photoIndex] = replacedPhoto(c, docs[photoIndex], newPath, metadata)
 
 	// Update documentation
 	updateParams := sqlcdb.UpdateToolsAlkerDocumentationParams{
 		ID:            int32(id),
-		Documentation: documentationToBytes(docs),
+		Documentation: utils.PhotosJSON(docs),
+		Version:       item.Version,
 	}
 
 	_, err = h.queries.UpdateToolsAlkerDocumentation(ctx, updateParams)
 	if err != nil {
+		// Nothing refers to the new file, the old one stays in use
+		if cleanupErr := h.uploads.DeleteFile(ctx, newPath, h.logger); cleanupErr != nil {
+			utils.RequestLogger(ctx, h.logger).Warn("Failed to delete new file", zap.Error(cleanupErr), zap.String("path", newPath))
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.respondVersionConflict(c, int32(id))
+			return
+		}
 		utils.HandleError(c, err, "Failed to update photo", h.logger)
 		return
 	}
 
-	// Get the item to find its location_id (item already declared above, use = instead of :=)
-	item, err = h.queries.GetToolsAlker(ctx, int32(id))
+	// Delete old photo file once the item no longer refers to it
+	if err := h.uploads.DeleteFile(ctx, oldFilePath, h.logger); err != nil {
+		utils.RequestLogger(ctx, h.logger).Warn("Failed to delete old file", zap.Error(err), zap.String("path", oldFilePath))
+	}
+
+	// Get grouped response for this location
+	groupedResponse, err := h.getGroupedToolsAlkerByLocationID(ctx, item.LocationID)
 	if err != nil {
-		utils.HandleError(c, err, "Failed to retrieve item", h.logger)
+		utils.HandleError(c, err, "Failed to retrieve grouped tools alker items", h.logger)
+		return
+	}
+
+	utils.Success(c, "Photo updated successfully", groupedResponse)
+}
+
+// @Summary Update photo metadata of tools alker item
+// @Description Change the caption and taken-at timestamp of a photo by index, keeping the photo file
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param id path int true "Tools Alker Item ID"
+// @Param photo_index path int true "Photo index in documentation array"
+// @Param metadata body PhotoMetadataRequest true "Fields to update (omitted fields are unchanged)"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict} "The item changed while its photos were being changed"
+// @Router /sparepart/tools-alker/{id}/photos/{photo_index} [patch]
+func (h *ToolsAlkerHandler) UpdatePhotoMetadata(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tools alker item ID")
+		return
+	}
+
+	photoIndex, ok := utils.ParseIndexParam(c, "photo_index")
+	if !ok {
+		utils.BadRequest(c, "Invalid photo index")
+		return
+	}
+
+	item, err := h.queries.GetToolsAlker(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Tools alker item not found")
+		return
+	}
+
+	docs := utils.ParsePhotos(item.Documentation)
+	if photoIndex >= len(docs) {
+		utils.BadRequest(c, "Photo index out of range")
+		return
+	}
+	if !bindPhotoMetadata(c, &docs[photoIndex]) {
+		return
+	}
+
+	_, err = h.queries.UpdateToolsAlkerDocumentation(ctx, sqlcdb.UpdateToolsAlkerDocumentationParams{
+		ID:            int32(id),
+		Documentation: utils.PhotosJSON(docs),
+		Version:       item.Version,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.respondVersionConflict(c, int32(id))
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update photo", h.logger)
 		return
 	}
 
-	// Get grouped response for this location
 	groupedResponse, err := h.getGroupedToolsAlkerByLocationID(ctx, item.LocationID)
 	if err != nil {
 		utils.HandleError(c, err, "Failed to retrieve grouped tools alker items", h.logger)
@@ -871,3 +1301,79 @@ func (h *ToolsAlkerHandler) UpdatePhoto(c *gin.Context) {
 	utils.Success(c, "Photo updated successfully", groupedResponse)
 }
 
+// @Summary Delete photo from tools alker item
+// @Description Delete a photo from tools alker item by index
+// @Tags Tools Alker
+// @Accept json
+// @Produce json
+// @Param id path int true "Tools Alker Item ID"
+// @Param photo_index path int true "Photo index in documentation array"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=utils.VersionConflict} "The item changed while its photos were being changed"
+// @Router /sparepart/tools-alker/{id}/photos/{photo_index} [delete]
+func (h *ToolsAlkerHandler) DeletePhoto(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid tools alker item ID")
+		return
+	}
+
+	photoIndex, ok := utils.ParseIndexParam(c, "photo_index")
+	if !ok {
+		utils.BadRequest(c, "Invalid photo index")
+		return
+	}
+
+	// Get existing item
+	item, err := h.queries.GetToolsAlker(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Tools alker item not found")
+		return
+	}
+
+	// Get existing documentation
+	docs := utils.ParsePhotos(item.Documentation)
+	if photoIndex < 0 || photoIndex >= len(docs) {
+		utils.BadRequest(c, "Photo index out of range")
+		return
+	}
+
+	filePath := docs[photoIndex].URL
+
+	// Remove from array
+	docs = append(docs[:photoIndex], docs[photoIndex+1:]...)
+
+	// Update documentation
+	updateParams := sqlcdb.UpdateToolsAlkerDocumentationParams{
+		ID:            int32(id),
+		Documentation: utils.PhotosJSON(docs),
+		Version:       item.Version,
+	}
+
+	_, err = h.queries.UpdateToolsAlkerDocumentation(ctx, updateParams)
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.respondVersionConflict(c, int32(id))
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to delete photo", h.logger)
+		return
+	}
+
+	// Delete file from storage once the item no longer refers to it
+	if err := h.uploads.DeleteFile(ctx, filePath, h.logger); err != nil {
+		utils.RequestLogger(ctx, h.logger).Warn("Failed to delete file", zap.Error(err), zap.String("path", filePath))
+	}
+
+	// Get grouped response for this location
+	groupedResponse, err := h.getGroupedToolsAlkerByLocationID(ctx, item.LocationID)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to retrieve grouped tools alker items", h.logger)
+		return
+	}
+
+	utils.Success(c, "Photo deleted successfully", groupedResponse)
+}
+
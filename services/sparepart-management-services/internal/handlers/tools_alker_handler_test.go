@@ -0,0 +1,655 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/repository/mocks"
+	"sparepart-management-services/internal/storage"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestToolsAlkerHandlerGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6}, nil)
+	repo.EXPECT().ListToolsAlkersByLocation(gomock.Any(), int32(6)).Return([]sqlcdb.ListToolsAlkersByLocationRow{
+		{ID: 2, LocationID: 6, LocationID2: 6, ToolsID2: 20, ToolsName: "Tang Ampere", Quantity: 1},
+	}, nil)
+
+	w := performRequest(http.MethodGet, "/tools-alker/:id", h.GetByID, "/tools-alker/2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var grouped ToolsAlkerGroupedResponse
+	decodeResponse(t, w, &grouped)
+	if grouped.LocationID != 6 || len(grouped.Tools) != 1 {
+		t.Fatalf("unexpected grouped response: %+v", grouped)
+	}
+}
+
+func TestToolsAlkerHandlerGetByIDIncludesContactPersons(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6}, nil)
+	repo.EXPECT().ListToolsAlkersByLocation(gomock.Any(), int32(6)).Return([]sqlcdb.ListToolsAlkersByLocationRow{
+		{ID: 2, LocationID: 6, LocationID2: 6, ToolsID2: 20, ToolsName: "Tang Ampere", Quantity: 1},
+	}, nil)
+	repo.EXPECT().
+		ListContactPersonsByLocations(gomock.Any(), []int32{6}).
+		Return([]sqlcdb.ContactPerson{{ID: 3, LocationID: 6, Pic: "Yohanes", Phone: "0813"}}, nil)
+
+	w := performRequest(http.MethodGet, "/tools-alker/:id", h.GetByID, "/tools-alker/2?include=contact_person", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var grouped ToolsAlkerGroupedResponse
+	decodeResponse(t, w, &grouped)
+	if len(grouped.ContactPersons) != 1 || grouped.ContactPersons[0].Pic != "Yohanes" || grouped.ContactPersons[0].Email != nil {
+		t.Fatalf("unexpected contact persons: %+v", grouped.ContactPersons)
+	}
+}
+
+func TestToolsAlkerHandlerGetAllPaginatesByLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().CountToolsAlkers(gomock.Any(), gomock.Any()).Return(int64(5), nil)
+	// Page 2 of 2 locations per page skips the first two locations in SQL
+	repo.EXPECT().
+		ListToolsAlkers(gomock.Any(), sqlcdb.ListToolsAlkersParams{Limit: 2, Offset: 2}).
+		Return([]sqlcdb.ListToolsAlkersRow{
+			{ID: 4, LocationID: 3, LocationID2: 3, ToolsName: "Tang Ampere"},
+			{ID: 9, LocationID: 3, LocationID2: 3, ToolsName: "Obeng"},
+			{ID: 5, LocationID: 8, LocationID2: 8, ToolsName: "Tang Ampere"},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/tools-alker", h.GetAll, "/tools-alker?page=2&limit=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var groups []ToolsAlkerGroupedResponse
+	resp := decodeResponse(t, w, &groups)
+	if len(groups) != 2 || groups[0].LocationID != 3 || len(groups[0].Tools) != 2 || groups[1].LocationID != 8 {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	if resp.Pagination.Total != 5 || resp.Pagination.TotalPages != 3 {
+		t.Fatalf("unexpected pagination: %+v", resp.Pagination)
+	}
+}
+
+func TestToolsAlkerHandlerGetAllFlat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().CountToolsAlkerItems(gomock.Any(), sqlcdb.CountToolsAlkerItemsParams{}).Return(int64(3), nil)
+	repo.EXPECT().
+		ListToolsAlkerItems(gomock.Any(), sqlcdb.ListToolsAlkerItemsParams{Limit: 10, Offset: 0}).
+		Return([]sqlcdb.ListToolsAlkerItemsRow{
+			{ID: 4, LocationID: 3, LocationID2: 3, ToolsID2: 2, ToolsName: "Tang Ampere", Quantity: 3, CheckedOut: 1},
+			{ID: 9, LocationID: 3, LocationID2: 3, ToolsID2: 5, ToolsName: "Obeng", Quantity: 1},
+			{ID: 5, LocationID: 8, LocationID2: 8, ToolsID2: 2, ToolsName: "Tang Ampere", Quantity: 2},
+		}, nil)
+
+	w := performRequest(http.MethodGet, "/tools-alker", h.GetAll, "/tools-alker?group_by=none", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var items []ToolsAlkerResponse
+	resp := decodeResponse(t, w, &items)
+	if len(items) != 3 || items[0].Tools.Name != "Tang Ampere" || items[0].Available != 2 || items[2].Location.ID != 8 {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if resp.Pagination.Total != 3 {
+		t.Fatalf("unexpected pagination: %+v", resp.Pagination)
+	}
+}
+
+func TestToolsAlkerHandlerGetByIDInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	w := performRequest(http.MethodGet, "/tools-alker/:id", h.GetByID, "/tools-alker/abc", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestToolsAlkerHandlerCreateBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().CreateToolsAlkersBatch(gomock.Any(), sqlcdb.CreateToolsAlkersBatchParams{
+		LocationIds: []int32{6, 7},
+		ToolsIds:    []int32{20, 21},
+		Quantities:  []int32{1, 3},
+		Notes:       []string{"", "spare"},
+	}).Return([]sqlcdb.ToolsAlkerItem{
+		{ID: 10, LocationID: 6, ToolsID: 20, Quantity: 1},
+		{ID: 11, LocationID: 7, ToolsID: 21, Quantity: 3},
+	}, nil)
+
+	body := `{"items":[{"location_id":6,"tools_id":20,"quantity":1},{"location_id":7,"tools_id":21,"quantity":3,"notes":"spare"}]}`
+	w := performRequest(http.MethodPost, "/tools-alker/batch", h.CreateBatch, "/tools-alker/batch", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created []ToolsAlkerBatchItem
+	decodeResponse(t, w, &created)
+	if len(created) != 2 || created[1].ID != 11 {
+		t.Fatalf("unexpected created items: %+v", created)
+	}
+}
+
+func TestToolsAlkerHandlerCreateBatchRejectsNegativeQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	body := `{"items":[{"location_id":6,"tools_id":20,"quantity":1},{"location_id":7,"tools_id":21,"quantity":-3}]}`
+	w := performRequest(http.MethodPost, "/tools-alker/batch", h.CreateBatch, "/tools-alker/batch", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "items[1].quantity" {
+		t.Fatalf("expected items[1].quantity field error, got %+v", resp.Errors)
+	}
+}
+
+func TestToolsAlkerHandlerCreateBatchUnknownLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().CreateToolsAlkersBatch(gomock.Any(), gomock.Any()).Return(nil, &pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "tools_alker_item_location_id_fkey",
+	})
+
+	body := `{"items":[{"location_id":999,"tools_id":20,"quantity":1}]}`
+	w := performRequest(http.MethodPost, "/tools-alker/batch", h.CreateBatch, "/tools-alker/batch", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if resp.Code != utils.ErrCodeInvalidReference || resp.Error != "location_id does not exist" {
+		t.Fatalf("unexpected error response: %+v", resp)
+	}
+}
+
+func TestToolsAlkerHandlerUpdateQuantityOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6}, nil)
+	repo.EXPECT().UpdateToolsAlker(gomock.Any(), sqlcdb.UpdateToolsAlkerParams{
+		ID:       2,
+		Quantity: pgtype.Int4{Int32: 0, Valid: true},
+		Version:  1,
+	}).Return(sqlcdb.ToolsAlkerItem{ID: 2, LocationID: 6, Version: 2}, nil)
+	repo.EXPECT().ListToolsAlkersByLocation(gomock.Any(), int32(6)).Return([]sqlcdb.ListToolsAlkersByLocationRow{
+		{ID: 2, LocationID: 6, LocationID2: 6, ToolsID2: 20, ToolsName: "Tang Ampere"},
+	}, nil)
+
+	w := performRequest(http.MethodPut, "/tools-alker/:id", h.Update, "/tools-alker/2", `{"quantity":0,"version":1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestToolsAlkerHandlerUpdateRejectsUnknownDocumentation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{
+		ID:            2,
+		LocationID:    6,
+		Documentation: []byte(`["/uploads/tools/a.jpg"]`),
+	}, nil)
+
+	body := `{"documentation":["/uploads/tools/a.jpg","/etc/passwd"],"version":1}`
+	w := performRequest(http.MethodPut, "/tools-alker/:id", h.Update, "/tools-alker/2", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "documentation[1]" {
+		t.Fatalf("expected documentation[1] field error, got %+v", resp.Errors)
+	}
+}
+
+func TestToolsAlkerHandlerAddPhotos(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).
+		Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6, Documentation: []byte(`["/uploads/tools_alker/old.jpg"]`)}, nil)
+	repo.EXPECT().
+		UpdateToolsAlkerDocumentation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.UpdateToolsAlkerDocumentationParams) (sqlcdb.ToolsAlkerItem, error) {
+			docs := utils.ParsePhotos(arg.Documentation)
+			if len(docs) != 2 || docs[0].URL != "/uploads/tools_alker/old.jpg" {
+				t.Fatalf("expected the new photo appended to the existing one, got %v", docs)
+			}
+			if docs[1].Caption != "Genset room" || docs[1].TakenAt == nil || docs[1].UploadedBy != "budi" {
+				t.Fatalf("expected the metadata sent with the new photo, got %+v", docs[1])
+			}
+			return sqlcdb.ToolsAlkerItem{ID: 2, LocationID: 6, Documentation: arg.Documentation}, nil
+		})
+	repo.EXPECT().ListToolsAlkersByLocation(gomock.Any(), int32(6)).Return([]sqlcdb.ListToolsAlkersByLocationRow{
+		{ID: 2, LocationID: 6, LocationID2: 6},
+	}, nil)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("photos", "photo.jpg")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write(testJPEG(t))
+	_ = writer.WriteField("captions", " Genset room ")
+	_ = writer.WriteField("taken_at", "2024-05-01T08:30:00+09:00")
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/tools-alker/2/photos", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r := gin.New()
+	r.POST("/tools-alker/:id/photos", asUser("budi"), h.AddPhotos)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := countUploadedFiles(t, filepath.Join(dir, "tools_alker")); n != 2 {
+		t.Fatalf("expected 1 uploaded photo and its thumbnail, found %d files", n)
+	}
+}
+
+func TestToolsAlkerHandlerAddPhotosConflictReleasesPhotos(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6, Version: 3}, nil)
+	// Another request changed the photos after they were read
+	repo.EXPECT().
+		UpdateToolsAlkerDocumentation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.UpdateToolsAlkerDocumentationParams) (sqlcdb.ToolsAlkerItem, error) {
+			if arg.Version != 3 {
+				t.Fatalf("expected the version the photos were read at, got %d", arg.Version)
+			}
+			return sqlcdb.ToolsAlkerItem{}, pgx.ErrNoRows
+		})
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6, Version: 4}, nil)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("photos", "photo.jpg")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write(testJPEG(t))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/tools-alker/2/photos", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r := gin.New()
+	r.POST("/tools-alker/:id/photos", h.AddPhotos)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := countUploadedFiles(t, dir); n != 0 {
+		t.Fatalf("expected the uploaded photo to be released, found %d files", n)
+	}
+}
+
+func TestToolsAlkerHandlerAddPhotosRequiresPhotos(t *testing.T) {
+	uploader, _ := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6}, nil)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("notes", "no photos")
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/tools-alker/2/photos", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r := gin.New()
+	r.POST("/tools-alker/:id/photos", h.AddPhotos)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestToolsAlkerHandlerDeletePhoto(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+	files := storage.NewLocal(dir)
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	if err := files.Put(context.Background(), "tools_alker/a.jpg", strings.NewReader("jpg"), 3, "image/jpeg"); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).
+		Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6, Documentation: []byte(`["/uploads/tools_alker/a.jpg","/uploads/tools_alker/b.jpg"]`)}, nil)
+	repo.EXPECT().
+		UpdateToolsAlkerDocumentation(gomock.Any(), sqlcdb.UpdateToolsAlkerDocumentationParams{
+			ID:            2,
+			Documentation: utils.PhotosJSON([]utils.Photo{{URL: "/uploads/tools_alker/b.jpg"}}),
+		}).
+		Return(sqlcdb.ToolsAlkerItem{ID: 2, LocationID: 6}, nil)
+	repo.EXPECT().ListToolsAlkersByLocation(gomock.Any(), int32(6)).Return([]sqlcdb.ListToolsAlkersByLocationRow{
+		{ID: 2, LocationID: 6, LocationID2: 6},
+	}, nil)
+
+	w := performRequest(http.MethodDelete, "/tools-alker/:id/photos/:photo_index", h.DeletePhoto, "/tools-alker/2/photos/0", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if exists, _ := files.Exists(context.Background(), "tools_alker/a.jpg"); exists {
+		t.Fatal("expected the photo file to be deleted")
+	}
+}
+
+func TestToolsAlkerHandlerUpdatePhotoKeepsOldPhotoOnFailure(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	oldPhoto := filepath.Join(dir, "tools_alker", "old.jpg")
+	if err := os.MkdirAll(filepath.Dir(oldPhoto), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(oldPhoto, testJPEG(t), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).
+		Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6, Documentation: []byte(`["/uploads/tools_alker/old.jpg"]`)}, nil)
+	repo.EXPECT().UpdateToolsAlkerDocumentation(gomock.Any(), gomock.Any()).
+		Return(sqlcdb.ToolsAlkerItem{}, errors.New("connection reset"))
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("photo", "photo.jpg")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write(testJPEG(t))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPut, "/tools-alker/2/photos/0", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	r := gin.New()
+	r.PUT("/tools-alker/:id/photos/:photo_index", h.UpdatePhoto)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+	// The item still refers to the old photo, and nothing to the new one
+	if _, err := os.Stat(oldPhoto); err != nil {
+		t.Fatalf("expected the old photo to be kept: %v", err)
+	}
+	if n := countUploadedFiles(t, filepath.Join(dir, "tools_alker")); n != 1 {
+		t.Fatalf("expected only the old photo left, found %d files", n)
+	}
+}
+
+func TestToolsAlkerHandlerDeletePhotoKeepsFileOnFailure(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+	files := storage.NewLocal(dir)
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	if err := files.Put(context.Background(), "tools_alker/a.jpg", strings.NewReader("jpg"), 3, "image/jpeg"); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).
+		Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6, Documentation: []byte(`["/uploads/tools_alker/a.jpg"]`)}, nil)
+	repo.EXPECT().UpdateToolsAlkerDocumentation(gomock.Any(), gomock.Any()).
+		Return(sqlcdb.ToolsAlkerItem{}, errors.New("connection reset"))
+
+	w := performRequest(http.MethodDelete, "/tools-alker/:id/photos/:photo_index", h.DeletePhoto, "/tools-alker/2/photos/0", "")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+	if exists, _ := files.Exists(context.Background(), "tools_alker/a.jpg"); !exists {
+		t.Fatal("expected the photo the item still refers to to be kept")
+	}
+}
+
+func TestToolsAlkerHandlerDeleteKeepsPhotos(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+	files := storage.NewLocal(dir)
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	if err := files.Put(context.Background(), "tools_alker/a.jpg", strings.NewReader("jpg"), 3, "image/jpeg"); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).
+		Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6, Documentation: []byte(`["/uploads/tools_alker/a.jpg"]`)}, nil)
+	repo.EXPECT().DeleteToolsAlker(gomock.Any(), int32(2)).Return(nil)
+
+	w := performRequest(http.MethodDelete, "/tools-alker/:id", h.Delete, "/tools-alker/2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if exists, _ := files.Exists(context.Background(), "tools_alker/a.jpg"); !exists {
+		t.Fatal("expected the photo to be kept for a restore")
+	}
+}
+
+func TestToolsAlkerHandlerRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().RestoreToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.ToolsAlkerItem{ID: 2, LocationID: 6, Quantity: 1}, nil)
+	repo.EXPECT().ListToolsAlkersByLocation(gomock.Any(), int32(6)).Return([]sqlcdb.ListToolsAlkersByLocationRow{
+		{ID: 2, LocationID: 6, LocationID2: 6, ToolsID2: 20, ToolsName: "Tang Ampere", Quantity: 1},
+	}, nil)
+	repo.EXPECT().RestoreToolsAlker(gomock.Any(), int32(3)).Return(sqlcdb.ToolsAlkerItem{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/tools-alker/:id/restore", h.Restore, "/tools-alker/2/restore", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var grouped ToolsAlkerGroupedResponse
+	decodeResponse(t, w, &grouped)
+	if grouped.LocationID != 6 || len(grouped.Tools) != 1 {
+		t.Fatalf("unexpected grouped response: %+v", grouped)
+	}
+
+	// Items that are not deleted, or whose location is, cannot be restored
+	w = performRequest(http.MethodPost, "/tools-alker/:id/restore", h.Restore, "/tools-alker/3/restore", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestToolsAlkerHandlerDeletePhotoOutOfRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).
+		Return(sqlcdb.GetToolsAlkerRow{ID: 2, LocationID: 6, Documentation: []byte(`["/uploads/tools_alker/a.jpg"]`)}, nil)
+
+	w := performRequest(http.MethodDelete, "/tools-alker/:id/photos/:photo_index", h.DeletePhoto, "/tools-alker/2/photos/3", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// newToolsAlkerCreateRequest builds a multipart create request with a single photo
+func newToolsAlkerCreateRequest(t *testing.T) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("location_id", "6")
+	_ = writer.WriteField("tools_id", "20")
+	_ = writer.WriteField("quantity", "1")
+	part, err := writer.CreateFormFile("photos", "photo.jpg")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write(testJPEG(t))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/tools-alker", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestToolsAlkerHandlerCreateCommitsPhotos(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().
+		WithinToolsAlkerTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(repository.ToolsAlkerRepository) error) error {
+			return fn(repo)
+		})
+	repo.EXPECT().
+		CreateToolsAlker(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.CreateToolsAlkerParams) (sqlcdb.ToolsAlkerItem, error) {
+			return sqlcdb.ToolsAlkerItem{ID: 2, LocationID: arg.LocationID, Documentation: arg.Documentation}, nil
+		})
+	repo.EXPECT().ListToolsAlkersByLocation(gomock.Any(), int32(6)).Return([]sqlcdb.ListToolsAlkersByLocationRow{
+		{ID: 2, LocationID: 6, LocationID2: 6, ToolsID2: 20, ToolsName: "Tang Ampere", Quantity: 1},
+	}, nil)
+
+	r := gin.New()
+	r.POST("/tools-alker", h.Create)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newToolsAlkerCreateRequest(t))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := countUploadedFiles(t, filepath.Join(dir, "tools_alker")); n != 2 {
+		t.Fatalf("expected 1 committed photo and its thumbnail, found %d files", n)
+	}
+	if n := countUploadedFiles(t, filepath.Join(dir, ".staging")); n != 0 {
+		t.Fatalf("expected empty staging area, found %d files", n)
+	}
+}
+
+func TestToolsAlkerHandlerCreateRollbackRemovesPhotos(t *testing.T) {
+	uploader, dir := tempUploads(t, config.UploadConfig{})
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, uploader, nil, 0, testLogger)
+
+	repo.EXPECT().
+		WithinToolsAlkerTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(repository.ToolsAlkerRepository) error) error {
+			return fn(repo)
+		})
+	repo.EXPECT().
+		CreateToolsAlker(gomock.Any(), gomock.Any()).
+		Return(sqlcdb.ToolsAlkerItem{}, errors.New("violates foreign key constraint"))
+
+	r := gin.New()
+	r.POST("/tools-alker", h.Create)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newToolsAlkerCreateRequest(t))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := countUploadedFiles(t, dir); n != 0 {
+		t.Fatalf("expected no photos left after rollback, found %d", n)
+	}
+}
+
+func TestToolsAlkerHandlerUpdateVersionConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockToolsAlkerRepository(ctrl)
+	h := NewToolsAlkerHandler(repo, nil, nil, 0, testLogger)
+
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{ID: 2, Version: 4}, nil)
+	repo.EXPECT().UpdateToolsAlker(gomock.Any(), gomock.Any()).Return(sqlcdb.ToolsAlkerItem{}, pgx.ErrNoRows)
+	repo.EXPECT().GetToolsAlker(gomock.Any(), int32(2)).Return(sqlcdb.GetToolsAlkerRow{ID: 2, Version: 5}, nil)
+
+	w := performRequest(http.MethodPatch, "/tools-alker/:id", h.Update, "/tools-alker/2", `{"notes":"rusty","version":4}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	var conflict utils.VersionConflict
+	if decodeResponse(t, w, &conflict); conflict.CurrentVersion != 5 {
+		t.Fatalf("expected current version 5, got %+v", conflict)
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"sparepart-management-services/internal/storage"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// UploadHandler serves uploaded photos (/uploads/...) from a storage backend other than the
+// local disk, which is served as static files instead
+type UploadHandler struct {
+	logger  *zap.Logger
+	storage storage.Storage
+}
+
+func NewUploadHandler(storage storage.Storage, logger *zap.Logger) *UploadHandler {
+	return &UploadHandler{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+// Serve streams the file under the path after /uploads/
+func (h *UploadHandler) Serve(c *gin.Context) {
+	body, info, err := h.storage.Get(c.Request.Context(), c.Param("filepath"))
+	if errors.Is(err, storage.ErrNotExist) {
+		utils.NotFound(c, "File not found")
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err, "Failed to read file", h.logger)
+		return
+	}
+	defer body.Close()
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	c.DataFromReader(http.StatusOK, info.Size, contentType, body, nil)
+}
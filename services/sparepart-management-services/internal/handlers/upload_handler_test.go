@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"sparepart-management-services/internal/storage"
+)
+
+func TestUploadHandlerServe(t *testing.T) {
+	uploads := storage.NewLocal(t.TempDir())
+	if err := uploads.Put(context.Background(), "tools_alker/a.png", strings.NewReader("png"), 3, "image/png"); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	h := NewUploadHandler(uploads, testLogger)
+
+	w := performRequest(http.MethodGet, "/uploads/*filepath", h.Serve, "/uploads/tools_alker/a.png", "")
+	if w.Code != http.StatusOK || w.Body.String() != "png" {
+		t.Fatalf("expected the stored file, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "image/png" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+
+	w = performRequest(http.MethodGet, "/uploads/*filepath", h.Serve, "/uploads/tools_alker/missing.png", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+	"sparepart-management-services/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WebhookRequest subscribes a URL to stock change events; no events subscribes to all of
+// them. stock.low is sent when a stock item's quantity drops to low_stock_threshold
+// (default 0) or below.
+type WebhookRequest struct {
+	Name              string   `json:"name" binding:"required"`
+	URL               string   `json:"url" binding:"required,url,startswith=http"`
+	Events            []string `json:"events" binding:"omitempty,dive,oneof=stock.created stock.updated stock.deleted stock.restored stock.low tools_alker.created tools_alker.updated tools_alker.deleted"`
+	LowStockThreshold *int     `json:"low_stock_threshold" binding:"omitempty,min=0"`
+	Enabled           *bool    `json:"enabled"`
+}
+
+// WebhookResponse describes a webhook; Secret is only returned on creation
+type WebhookResponse struct {
+	ID                int32    `json:"id"`
+	Name              string   `json:"name"`
+	URL               string   `json:"url"`
+	Secret            string   `json:"secret,omitempty"`
+	Events            []string `json:"events"`
+	LowStockThreshold int32    `json:"low_stock_threshold"`
+	Enabled           bool     `json:"enabled"`
+	CreatedAt         string   `json:"created_at"`
+	UpdatedAt         string   `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse is one attempt log entry of a webhook
+type WebhookDeliveryResponse struct {
+	ID             int64   `json:"id"`
+	EventID        int64   `json:"event_id"`
+	Event          string  `json:"event"`
+	Status         string  `json:"status"`
+	Attempts       int32   `json:"attempts"`
+	NextAttemptAt  string  `json:"next_attempt_at,omitempty"`
+	ResponseStatus *int32  `json:"response_status"`
+	LastError      *string `json:"last_error"`
+	CreatedAt      string  `json:"created_at"`
+	DeliveredAt    string  `json:"delivered_at,omitempty"`
+}
+
+// WebhookHandler lets admins manage the webhooks stock change events are sent to
+type WebhookHandler struct {
+	logger  *zap.Logger
+	queries repository.WebhookRepository
+}
+
+func NewWebhookHandler(queries repository.WebhookRepository, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Get all webhooks
+// @Description Get all webhooks
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/admin/webhooks [get]
+func (h *WebhookHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	pagination, errs := utils.ParsePagination(c)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountWebhooks(ctx)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count webhooks", h.logger)
+		return
+	}
+
+	hooks, err := h.queries.ListWebhooks(ctx, sqlcdb.ListWebhooksParams{
+		Limit:  int32(pagination.Limit),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get webhooks", h.logger)
+		return
+	}
+
+	response := make([]WebhookResponse, 0, len(hooks))
+	for _, hook := range hooks {
+		response = append(response, toWebhookResponse(hook))
+	}
+
+	utils.SuccessWithPagination(c, "Webhooks retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get webhook by ID
+// @Description Get a single webhook by ID
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	hook, err := h.queries.GetWebhook(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Webhook not found")
+		return
+	}
+
+	utils.Success(c, "Webhook retrieved successfully", toWebhookResponse(hook))
+}
+
+// @Summary Create webhook
+// @Description Subscribe a URL to stock change events. Deliveries are signed with the returned secret, which is only shown in this response (see X-Webhook-Signature).
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param webhook body WebhookRequest true "Webhook data"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/admin/webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create webhook", h.logger)
+		return
+	}
+
+	params := webhookParams(req)
+	params.Secret = secret
+	hook, err := h.queries.CreateWebhook(ctx, params)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to create webhook", h.logger)
+		return
+	}
+
+	response := toWebhookResponse(hook)
+	response.Secret = hook.Secret
+
+	c.JSON(http.StatusCreated, utils.Response{
+		Success: true,
+		Message: "Webhook created successfully",
+		Data:    response,
+	})
+}
+
+// @Summary Update webhook
+// @Description Replace an existing webhook; its secret is kept
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body WebhookRequest true "Webhook data"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/admin/webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	// Check if webhook exists
+	_, err = h.queries.GetWebhook(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Webhook not found")
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+
+	params := webhookParams(req)
+	hook, err := h.queries.UpdateWebhook(ctx, sqlcdb.UpdateWebhookParams{
+		ID:                int32(id),
+		Name:              params.Name,
+		Url:               params.Url,
+		Events:            params.Events,
+		LowStockThreshold: params.LowStockThreshold,
+		Enabled:           params.Enabled,
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to update webhook", h.logger)
+		return
+	}
+
+	utils.Success(c, "Webhook updated successfully", toWebhookResponse(hook))
+}
+
+// @Summary Delete webhook
+// @Description Delete a webhook with its delivery log; pending deliveries are dropped
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/admin/webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	// Check if webhook exists
+	_, err = h.queries.GetWebhook(ctx, int32(id))
+	if err != nil {
+		utils.NotFound(c, "Webhook not found")
+		return
+	}
+
+	if err := h.queries.DeleteWebhook(ctx, int32(id)); err != nil {
+		utils.HandleError(c, err, "Failed to delete webhook", h.logger)
+		return
+	}
+
+	utils.Success(c, "Webhook deleted successfully", nil)
+}
+
+// @Summary Get webhook deliveries
+// @Description Get the deliveries of a webhook, newest first, with the outcome of their last attempt
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Filter by status" Enums(PENDING, DELIVERED, FAILED)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	var errs []utils.FieldError
+	status := c.Query("status")
+	switch status {
+	case "", "PENDING", "DELIVERED", "FAILED":
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of PENDING, DELIVERED, FAILED"})
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	// Check if webhook exists
+	if _, err := h.queries.GetWebhook(ctx, int32(id)); err != nil {
+		utils.NotFound(c, "Webhook not found")
+		return
+	}
+
+	filters := sqlcdb.CountWebhookDeliveriesParams{WebhookID: int32(id), Status: utils.TextFilter(status)}
+	total, err := h.queries.CountWebhookDeliveries(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count webhook deliveries", h.logger)
+		return
+	}
+
+	deliveries, err := h.queries.ListWebhookDeliveries(ctx, sqlcdb.ListWebhookDeliveriesParams{
+		WebhookID: filters.WebhookID,
+		Status:    filters.Status,
+		Limit:     int32(pagination.Limit),
+		Offset:    int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get webhook deliveries", h.logger)
+		return
+	}
+
+	response := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, toWebhookDeliveryResponse(delivery))
+	}
+
+	utils.SuccessWithPagination(c, "Webhook deliveries retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// webhookParams converts a validated request to query params without the secret; webhooks
+// are enabled unless stated otherwise
+func webhookParams(req WebhookRequest) sqlcdb.CreateWebhookParams {
+	params := sqlcdb.CreateWebhookParams{
+		Name:    req.Name,
+		Url:     req.URL,
+		Events:  req.Events,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	}
+	if params.Events == nil {
+		params.Events = []string{}
+	}
+	if req.LowStockThreshold != nil {
+		params.LowStockThreshold = int32(*req.LowStockThreshold)
+	}
+	return params
+}
+
+func toWebhookResponse(hook sqlcdb.Webhook) WebhookResponse {
+	response := WebhookResponse{
+		ID:                hook.ID,
+		Name:              hook.Name,
+		URL:               hook.Url,
+		Events:            hook.Events,
+		LowStockThreshold: hook.LowStockThreshold,
+		Enabled:           hook.Enabled,
+		CreatedAt:         utils.FormatTimestamp(hook.CreatedAt),
+		UpdatedAt:         utils.FormatTimestamp(hook.UpdatedAt),
+	}
+	if response.Events == nil {
+		response.Events = []string{}
+	}
+	return response
+}
+
+func toWebhookDeliveryResponse(delivery sqlcdb.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:          delivery.ID,
+		EventID:     delivery.EventID,
+		Event:       delivery.Event,
+		Status:      delivery.Status,
+		Attempts:    delivery.Attempts,
+		CreatedAt:   utils.FormatTimestamp(delivery.CreatedAt),
+		DeliveredAt: utils.FormatTimestamp(delivery.DeliveredAt),
+	}
+	if delivery.Status == "PENDING" {
+		response.NextAttemptAt = utils.FormatTimestamp(delivery.NextAttemptAt)
+	}
+	if delivery.ResponseStatus.Valid {
+		response.ResponseStatus = &delivery.ResponseStatus.Int32
+	}
+	if delivery.LastError.Valid {
+		response.LastError = &delivery.LastError.String
+	}
+	return response
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhookHandlerCreateReturnsSecretOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockWebhookRepository(ctrl)
+	h := NewWebhookHandler(repo, testLogger)
+
+	var stored sqlcdb.CreateWebhookParams
+	repo.EXPECT().
+		CreateWebhook(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, arg sqlcdb.CreateWebhookParams) (sqlcdb.Webhook, error) {
+			stored = arg
+			return sqlcdb.Webhook{ID: 1, Name: arg.Name, Url: arg.Url, Secret: arg.Secret, Events: arg.Events, LowStockThreshold: arg.LowStockThreshold, Enabled: arg.Enabled}, nil
+		})
+
+	body := `{"name":"Ticketing","url":"https://tickets.example.com/hooks/stock","events":["stock.low","tools_alker.deleted"],"low_stock_threshold":2}`
+	w := performRequest(http.MethodPost, "/admin/webhooks", h.Create, "/admin/webhooks", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created WebhookResponse
+	decodeResponse(t, w, &created)
+	if !strings.HasPrefix(created.Secret, "whsec_") || created.Secret != stored.Secret {
+		t.Fatalf("expected the generated secret in the response, got %q", created.Secret)
+	}
+	if !stored.Enabled || stored.LowStockThreshold != 2 || len(stored.Events) != 2 {
+		t.Fatalf("unexpected stored webhook: %+v", stored)
+	}
+
+	repo.EXPECT().GetWebhook(gomock.Any(), int32(1)).Return(sqlcdb.Webhook{ID: 1, Secret: stored.Secret}, nil)
+	w = performRequest(http.MethodGet, "/admin/webhooks/:id", h.GetByID, "/admin/webhooks/1", "")
+	var fetched WebhookResponse
+	decodeResponse(t, w, &fetched)
+	if fetched.Secret != "" {
+		t.Fatal("expected the secret to be hidden after creation")
+	}
+}
+
+func TestWebhookHandlerCreateValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "unknown event", body: `{"name":"Ticketing","url":"https://tickets.example.com","events":["stock.moved"]}`},
+		{name: "not a URL", body: `{"name":"Ticketing","url":"tickets"}`},
+		{name: "not HTTP", body: `{"name":"Ticketing","url":"ftp://tickets.example.com"}`},
+		{name: "negative threshold", body: `{"name":"Ticketing","url":"https://tickets.example.com","low_stock_threshold":-1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockWebhookRepository(ctrl)
+			h := NewWebhookHandler(repo, testLogger)
+
+			w := performRequest(http.MethodPost, "/admin/webhooks", h.Create, "/admin/webhooks", tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerGetDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockWebhookRepository(ctrl)
+	h := NewWebhookHandler(repo, testLogger)
+
+	filters := sqlcdb.CountWebhookDeliveriesParams{WebhookID: 3, Status: pgtype.Text{String: "FAILED", Valid: true}}
+	repo.EXPECT().GetWebhook(gomock.Any(), int32(3)).Return(sqlcdb.Webhook{ID: 3}, nil)
+	repo.EXPECT().CountWebhookDeliveries(gomock.Any(), filters).Return(int64(1), nil)
+	repo.EXPECT().
+		ListWebhookDeliveries(gomock.Any(), sqlcdb.ListWebhookDeliveriesParams{WebhookID: 3, Status: filters.Status, Limit: 10}).
+		Return([]sqlcdb.WebhookDelivery{{
+			ID:             9,
+			WebhookID:      3,
+			Event:          "stock.updated",
+			Status:         "FAILED",
+			Attempts:       8,
+			ResponseStatus: pgtype.Int4{Int32: 503, Valid: true},
+			LastError:      pgtype.Text{String: "webhook returned status 503", Valid: true},
+		}}, nil)
+
+	w := performRequest(http.MethodGet, "/admin/webhooks/:id/deliveries", h.GetDeliveries, "/admin/webhooks/3/deliveries?status=FAILED", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var deliveries []WebhookDeliveryResponse
+	decodeResponse(t, w, &deliveries)
+	if len(deliveries) != 1 || *deliveries[0].ResponseStatus != 503 || deliveries[0].NextAttemptAt != "" {
+		t.Fatalf("unexpected deliveries: %+v", deliveries)
+	}
+}
+
+func TestWebhookHandlerGetDeliveriesInvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockWebhookRepository(ctrl)
+	h := NewWebhookHandler(repo, testLogger)
+
+	w := performRequest(http.MethodGet, "/admin/webhooks/:id/deliveries", h.GetDeliveries, "/admin/webhooks/3/deliveries?status=LOST", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
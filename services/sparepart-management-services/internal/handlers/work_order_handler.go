@@ -0,0 +1,767 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/models"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Work order statuses; a work order moves from OPEN to CLOSED, or from OPEN to CANCELLED
+const (
+	workOrderStatusOpen      = "OPEN"
+	workOrderStatusClosed    = "CLOSED"
+	workOrderStatusCancelled = "CANCELLED"
+)
+
+// Errors ending a work order transaction early; the response is written after the rollback
+var (
+	errWorkOrderNotFound = errors.New("work order not found")
+	errWorkOrderStatus   = errors.New("work order has the wrong status")
+	errWorkOrderInvalid  = errors.New("work order is invalid")
+)
+
+// WorkOrderItemRequest is a quantity of one sparepart and stock type used on the job
+type WorkOrderItemRequest struct {
+	SparepartID int              `json:"sparepart_id" binding:"required,min=1"`
+	StockType   models.StockType `json:"stock_type" binding:"required,oneof=NEW_STOCK USED_STOCK RESERVED"`
+	Quantity    int              `json:"quantity" binding:"required,min=1"`
+}
+
+// WorkOrderToolRequest is a quantity of one tool taken along on the job
+type WorkOrderToolRequest struct {
+	ToolsID  int `json:"tools_id" binding:"required,min=1"`
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+// CreateWorkOrderRequest opens a work order at a site, optionally with the spareparts and
+// tools already known to be needed; with TechnicianID the technician's name is taken from the
+// technician directory
+type CreateWorkOrderRequest struct {
+	LocationID   int                    `json:"location_id" binding:"required,min=1"`
+	Title        string                 `json:"title" binding:"required,max=255"`
+	Description  *string                `json:"description"`
+	TechnicianID *int                   `json:"technician_id" binding:"omitempty,min=1"`
+	Technician   *string                `json:"technician" binding:"omitempty,max=255"`
+	Items        []WorkOrderItemRequest `json:"items" binding:"max=100,dive"`
+	Tools        []WorkOrderToolRequest `json:"tools" binding:"max=100,dive"`
+}
+
+// SetWorkOrderItemsRequest replaces the spareparts of an OPEN work order
+type SetWorkOrderItemsRequest struct {
+	Items []WorkOrderItemRequest `json:"items" binding:"max=100,dive"`
+}
+
+// SetWorkOrderToolsRequest replaces the tools of an OPEN work order
+type SetWorkOrderToolsRequest struct {
+	Tools []WorkOrderToolRequest `json:"tools" binding:"max=100,dive"`
+}
+
+// CloseWorkOrderRequest is the optional body of the close and cancel actions
+type CloseWorkOrderRequest struct {
+	Resolution *string `json:"resolution"`
+}
+
+// WorkOrderItemResponse is a sparepart used on the job; StockItemID and QuantityAfter are
+// set once closing took it out of the stock
+type WorkOrderItemResponse struct {
+	ID            int32   `json:"id"`
+	SparepartID   int32   `json:"sparepart_id"`
+	SparepartName *string `json:"sparepart_name,omitempty"`
+	StockType     string  `json:"stock_type"`
+	Quantity      int32   `json:"quantity"`
+	StockItemID   *int32  `json:"stock_item_id,omitempty"`
+	QuantityAfter *int32  `json:"quantity_after,omitempty"`
+}
+
+// WorkOrderToolResponse is a tool taken along on the job
+type WorkOrderToolResponse struct {
+	ID        int32   `json:"id"`
+	ToolsID   int32   `json:"tools_id"`
+	ToolsName *string `json:"tools_name,omitempty"`
+	Quantity  int32   `json:"quantity"`
+}
+
+// WorkOrderResponse is a work order
+type WorkOrderResponse struct {
+	ID           int32   `json:"id"`
+	LocationID   int32   `json:"location_id"`
+	Region       *string `json:"region,omitempty"`
+	Regency      *string `json:"regency,omitempty"`
+	Cluster      *string `json:"cluster,omitempty"`
+	Title        string  `json:"title"`
+	Description  *string `json:"description"`
+	TechnicianID *int32  `json:"technician_id"`
+	Technician   *string `json:"technician"`
+	Status       string  `json:"status"`
+	Resolution   *string `json:"resolution"`
+	CreatedBy    *string `json:"created_by"`
+	ClosedBy     *string `json:"closed_by"`
+	ClosedAt     string  `json:"closed_at,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+// WorkOrderDetailResponse is a work order with its spareparts and tools
+type WorkOrderDetailResponse struct {
+	WorkOrderResponse
+	Items []WorkOrderItemResponse `json:"items"`
+	Tools []WorkOrderToolResponse `json:"tools"`
+}
+
+// WorkOrderHandler runs the work orders (maintenance tickets) of the field teams: the
+// spareparts and tools used on a job are attached to its ticket, and closing the ticket
+// takes the spareparts out of the site's stock, tying consumption back to the activity
+type WorkOrderHandler struct {
+	logger  *zap.Logger
+	queries repository.SparepartStockRepository
+}
+
+func NewWorkOrderHandler(queries repository.SparepartStockRepository, logger *zap.Logger) *WorkOrderHandler {
+	return &WorkOrderHandler{
+		logger:  logger,
+		queries: queries,
+	}
+}
+
+// @Summary Create work order
+// @Description Open a work order at a site (location), optionally with the spareparts and tools used on the job
+// @Tags Work Order
+// @Accept json
+// @Produce json
+// @Param work_order body CreateWorkOrderRequest true "Work order data"
+// @Success 201 {object} utils.Response
+// @Router /sparepart/work-orders [post]
+func (h *WorkOrderHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateWorkOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	errs := append(duplicateWorkOrderItems(req.Items), duplicateWorkOrderTools(req.Tools)...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	var id int32
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		order, err := repo.CreateWorkOrder(ctx, sqlcdb.CreateWorkOrderParams{
+			Title:        req.Title,
+			Description:  utils.OptionalText(req.Description),
+			TechnicianID: utils.OptionalInt(req.TechnicianID),
+			Technician:   utils.OptionalText(req.Technician),
+			CreatedBy:    utils.TextFilter(utils.UserID(c)),
+			LocationID:   int32(req.LocationID),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			errs = append(errs, utils.FieldError{Field: "location_id", Message: "does not exist"})
+			return errWorkOrderInvalid
+		}
+		if err != nil {
+			return err
+		}
+		id = order.ID
+
+		itemErrs, err := addWorkOrderItems(ctx, repo, id, req.Items)
+		if err != nil {
+			return err
+		}
+		toolErrs, err := addWorkOrderTools(ctx, repo, id, req.Tools)
+		if err != nil {
+			return err
+		}
+		errs = append(itemErrs, toolErrs...)
+		if len(errs) > 0 {
+			return errWorkOrderInvalid
+		}
+		return nil
+	})
+	if !h.handleTransactionError(c, err, "", errs, "", "Failed to create work order") {
+		return
+	}
+
+	h.respond(c, http.StatusCreated, id, "Work order created successfully")
+}
+
+// @Summary Get work orders
+// @Description Get the work orders, newest first
+// @Tags Work Order
+// @Accept json
+// @Produce json
+// @Param location_id query int false "Filter by location"
+// @Param status query string false "Filter by status (OPEN, CLOSED, CANCELLED)"
+// @Param technician query string false "Filter by technician"
+// @Param technician_id query int false "Filter by technician in the directory"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /sparepart/work-orders [get]
+func (h *WorkOrderHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var errs []utils.FieldError
+	filters := sqlcdb.CountWorkOrdersParams{Technician: utils.TextFilter(c.Query("technician"))}
+	if value := c.Query("location_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "location_id", Message: "must be a positive integer"})
+		} else {
+			filters.LocationID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	if value := c.Query("technician_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id < 1 {
+			errs = append(errs, utils.FieldError{Field: "technician_id", Message: "must be a positive integer"})
+		} else {
+			filters.TechnicianID = pgtype.Int4{Int32: int32(id), Valid: true}
+		}
+	}
+	switch status := c.Query("status"); status {
+	case "":
+	case workOrderStatusOpen, workOrderStatusClosed, workOrderStatusCancelled:
+		filters.Status = utils.TextFilter(status)
+	default:
+		errs = append(errs, utils.FieldError{Field: "status", Message: "must be one of OPEN, CLOSED, CANCELLED"})
+	}
+	pagination, paginationErrs := utils.ParsePagination(c)
+	errs = append(errs, paginationErrs...)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	total, err := h.queries.CountWorkOrders(ctx, filters)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to count work orders", h.logger)
+		return
+	}
+
+	orders, err := h.queries.ListWorkOrders(ctx, sqlcdb.ListWorkOrdersParams{
+		LocationID:   filters.LocationID,
+		Status:       filters.Status,
+		Technician:   filters.Technician,
+		TechnicianID: filters.TechnicianID,
+		Limit:        int32(pagination.Limit),
+		Offset:       int32(pagination.Offset()),
+	})
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get work orders", h.logger)
+		return
+	}
+
+	response := make([]WorkOrderResponse, 0, len(orders))
+	for _, order := range orders {
+		response = append(response, toWorkOrderResponse(sqlcdb.GetWorkOrderRow(order)))
+	}
+
+	utils.SuccessWithPagination(c, "Work orders retrieved successfully", response, pagination.Page, pagination.Limit, total)
+}
+
+// @Summary Get work order
+// @Description Get a work order with its spareparts and tools
+// @Tags Work Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/work-orders/{id} [get]
+func (h *WorkOrderHandler) GetByID(c *gin.Context) {
+	id, ok := parseWorkOrderID(c)
+	if !ok {
+		return
+	}
+	h.respond(c, http.StatusOK, id, "Work order retrieved successfully")
+}
+
+// @Summary Set work order spareparts
+// @Description Replace the spareparts used on an OPEN work order; an empty list removes them all
+// @Tags Work Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param items body SetWorkOrderItemsRequest true "Spareparts used"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/work-orders/{id}/items [put]
+func (h *WorkOrderHandler) SetItems(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseWorkOrderID(c)
+	if !ok {
+		return
+	}
+	var req SetWorkOrderItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	errs := duplicateWorkOrderItems(req.Items)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	var status string
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		order, err := lockWorkOrder(ctx, repo, id)
+		status = order.Status
+		if err != nil {
+			return err
+		}
+
+		if err := repo.DeleteWorkOrderItems(ctx, id); err != nil {
+			return err
+		}
+		errs, err = addWorkOrderItems(ctx, repo, id, req.Items)
+		if err != nil {
+			return err
+		}
+		if len(errs) > 0 {
+			return errWorkOrderInvalid
+		}
+		return nil
+	})
+	if !h.handleTransactionError(c, err, status, errs, "Only OPEN work orders can be changed", "Failed to set work order spareparts") {
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, "Work order spareparts set successfully")
+}
+
+// @Summary Set work order tools
+// @Description Replace the tools used on an OPEN work order; an empty list removes them all. Tools are not consumed, so closing leaves the tools alker stock unchanged.
+// @Tags Work Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param tools body SetWorkOrderToolsRequest true "Tools used"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/work-orders/{id}/tools [put]
+func (h *WorkOrderHandler) SetTools(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseWorkOrderID(c)
+	if !ok {
+		return
+	}
+	var req SetWorkOrderToolsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingError(c, err)
+		return
+	}
+	errs := duplicateWorkOrderTools(req.Tools)
+	if len(errs) > 0 {
+		utils.ValidationError(c, errs...)
+		return
+	}
+
+	var status string
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		order, err := lockWorkOrder(ctx, repo, id)
+		status = order.Status
+		if err != nil {
+			return err
+		}
+
+		if err := repo.DeleteWorkOrderTools(ctx, id); err != nil {
+			return err
+		}
+		errs, err = addWorkOrderTools(ctx, repo, id, req.Tools)
+		if err != nil {
+			return err
+		}
+		if len(errs) > 0 {
+			return errWorkOrderInvalid
+		}
+		return nil
+	})
+	if !h.handleTransactionError(c, err, status, errs, "Only OPEN work orders can be changed", "Failed to set work order tools") {
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, "Work order tools set successfully")
+}
+
+// @Summary Close work order
+// @Description Close an OPEN work order: every sparepart used is taken out of the site's stock within one transaction, and the stock ledger records the decreases as WORK_ORDER movements. Fails, taking nothing, when the site lacks any sparepart.
+// @Tags Work Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param action body CloseWorkOrderRequest false "Resolution"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/work-orders/{id}/close [post]
+func (h *WorkOrderHandler) Close(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseWorkOrderID(c)
+	if !ok {
+		return
+	}
+	var req CloseWorkOrderRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BindingError(c, err)
+			return
+		}
+	}
+
+	var status string
+	var errs []utils.FieldError
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		order, err := lockWorkOrder(ctx, repo, id)
+		status = order.Status
+		if err != nil {
+			return err
+		}
+
+		items, err := repo.ListWorkOrderItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		// A short item does not stop the others, so every shortage is reported; the
+		// transaction is rolled back anyway
+		for i, item := range items {
+			stock, err := repo.GetSparepartStockByKeyForUpdate(ctx, sqlcdb.GetSparepartStockByKeyForUpdateParams{
+				LocationID:  order.LocationID,
+				SparepartID: item.SparepartID,
+				StockType:   item.StockType,
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d]", i), Message: "not stocked at the work order's location"})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if stock.Quantity < item.Quantity {
+				errs = append(errs, utils.FieldError{
+					Field:   fmt.Sprintf("items[%d].quantity", i),
+					Message: fmt.Sprintf("exceeds the %d in stock at the work order's location", stock.Quantity),
+				})
+				continue
+			}
+			if len(errs) > 0 {
+				continue
+			}
+
+			consumed, err := repo.TransferOutSparepartStock(ctx, sqlcdb.TransferOutSparepartStockParams{
+				ID:       stock.ID,
+				Quantity: item.Quantity,
+			})
+			if err != nil {
+				return err
+			}
+			err = repo.SetWorkOrderItemConsumption(ctx, sqlcdb.SetWorkOrderItemConsumptionParams{
+				ID:            item.ID,
+				StockItemID:   pgtype.Int4{Int32: consumed.ID, Valid: true},
+				QuantityAfter: pgtype.Int4{Int32: consumed.Quantity, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(errs) > 0 {
+			return errWorkOrderInvalid
+		}
+
+		_, err = repo.CloseWorkOrder(ctx, sqlcdb.CloseWorkOrderParams{
+			ID:         id,
+			Status:     workOrderStatusClosed,
+			Resolution: utils.OptionalText(req.Resolution),
+			ClosedBy:   utils.TextFilter(utils.UserID(c)),
+		})
+		return err
+	})
+	if !h.handleTransactionError(c, err, status, errs, "Only OPEN work orders can be closed", "Failed to close work order") {
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, "Work order closed successfully")
+}
+
+// @Summary Cancel work order
+// @Description Cancel an OPEN work order; its spareparts are left in the stock
+// @Tags Work Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param action body CloseWorkOrderRequest false "Reason"
+// @Success 200 {object} utils.Response
+// @Router /sparepart/work-orders/{id}/cancel [post]
+func (h *WorkOrderHandler) Cancel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := parseWorkOrderID(c)
+	if !ok {
+		return
+	}
+	var req CloseWorkOrderRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BindingError(c, err)
+			return
+		}
+	}
+
+	var status string
+	err := h.queries.WithinTransaction(ctx, func(repo repository.SparepartStockRepository) error {
+		order, err := lockWorkOrder(ctx, repo, id)
+		status = order.Status
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.CloseWorkOrder(ctx, sqlcdb.CloseWorkOrderParams{
+			ID:         id,
+			Status:     workOrderStatusCancelled,
+			Resolution: utils.OptionalText(req.Resolution),
+			ClosedBy:   utils.TextFilter(utils.UserID(c)),
+		})
+		return err
+	})
+	if !h.handleTransactionError(c, err, status, nil, "Only OPEN work orders can be cancelled", "Failed to cancel work order") {
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, "Work order cancelled successfully")
+}
+
+// duplicateWorkOrderItems reports the items naming a sparepart and stock type twice
+func duplicateWorkOrderItems(items []WorkOrderItemRequest) []utils.FieldError {
+	var errs []utils.FieldError
+	seen := make(map[string]int, len(items))
+	for i, item := range items {
+		key := fmt.Sprintf("%d/%s", item.SparepartID, item.StockType)
+		if first, ok := seen[key]; ok {
+			errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d]", i), Message: fmt.Sprintf("duplicates items[%d]", first)})
+			continue
+		}
+		seen[key] = i
+	}
+	return errs
+}
+
+// duplicateWorkOrderTools reports the tools named twice
+func duplicateWorkOrderTools(tools []WorkOrderToolRequest) []utils.FieldError {
+	var errs []utils.FieldError
+	seen := make(map[int]int, len(tools))
+	for i, tool := range tools {
+		if first, ok := seen[tool.ToolsID]; ok {
+			errs = append(errs, utils.FieldError{Field: fmt.Sprintf("tools[%d]", i), Message: fmt.Sprintf("duplicates tools[%d]", first)})
+			continue
+		}
+		seen[tool.ToolsID] = i
+	}
+	return errs
+}
+
+// addWorkOrderItems attaches the spareparts, reporting those that do not exist
+func addWorkOrderItems(ctx context.Context, repo repository.SparepartStockRepository, id int32, items []WorkOrderItemRequest) ([]utils.FieldError, error) {
+	var errs []utils.FieldError
+	for i, item := range items {
+		_, err := repo.CreateWorkOrderItem(ctx, sqlcdb.CreateWorkOrderItemParams{
+			WorkOrderID: id,
+			StockType:   sqlcdb.StockType(item.StockType),
+			Quantity:    int32(item.Quantity),
+			SparepartID: int32(item.SparepartID),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			errs = append(errs, utils.FieldError{Field: fmt.Sprintf("items[%d].sparepart_id", i), Message: "does not exist"})
+			continue
+		}
+		if err != nil {
+			return errs, err
+		}
+	}
+	return errs, nil
+}
+
+// addWorkOrderTools attaches the tools, reporting those that do not exist or are no tools
+func addWorkOrderTools(ctx context.Context, repo repository.SparepartStockRepository, id int32, tools []WorkOrderToolRequest) ([]utils.FieldError, error) {
+	var errs []utils.FieldError
+	for i, tool := range tools {
+		_, err := repo.CreateWorkOrderTool(ctx, sqlcdb.CreateWorkOrderToolParams{
+			WorkOrderID: id,
+			Quantity:    int32(tool.Quantity),
+			ToolsID:     int32(tool.ToolsID),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			errs = append(errs, utils.FieldError{Field: fmt.Sprintf("tools[%d].tools_id", i), Message: "is not a tools alker item"})
+			continue
+		}
+		if err != nil {
+			return errs, err
+		}
+	}
+	return errs, nil
+}
+
+// lockWorkOrder locks the work order for the rest of the transaction, failing unless it is OPEN
+func lockWorkOrder(ctx context.Context, repo repository.SparepartStockRepository, id int32) (sqlcdb.WorkOrder, error) {
+	order, err := repo.GetWorkOrderForUpdate(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return order, errWorkOrderNotFound
+	}
+	if err != nil {
+		return order, err
+	}
+	if order.Status != workOrderStatusOpen {
+		return order, errWorkOrderStatus
+	}
+	return order, nil
+}
+
+// handleTransactionError writes the response for a work order transaction that failed,
+// reporting whether it succeeded instead
+func (h *WorkOrderHandler) handleTransactionError(c *gin.Context, err error, status string, errs []utils.FieldError, statusMessage, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errWorkOrderNotFound):
+		utils.NotFound(c, "Work order not found")
+	case errors.Is(err, errWorkOrderStatus):
+		utils.Error(c, fmt.Sprintf("%s; this one is %s", statusMessage, status), http.StatusConflict)
+	case errors.Is(err, errWorkOrderInvalid):
+		utils.ValidationError(c, errs...)
+	default:
+		utils.HandleError(c, err, message, h.logger)
+	}
+	return false
+}
+
+// respond writes the work order with its spareparts and tools
+func (h *WorkOrderHandler) respond(c *gin.Context, statusCode int, id int32, message string) {
+	ctx := c.Request.Context()
+
+	order, err := h.queries.GetWorkOrder(ctx, id)
+	if err != nil {
+		utils.NotFound(c, "Work order not found")
+		return
+	}
+
+	items, err := h.queries.ListWorkOrderItems(ctx, id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get work order spareparts", h.logger)
+		return
+	}
+
+	tools, err := h.queries.ListWorkOrderTools(ctx, id)
+	if err != nil {
+		utils.HandleError(c, err, "Failed to get work order tools", h.logger)
+		return
+	}
+
+	response := WorkOrderDetailResponse{
+		WorkOrderResponse: toWorkOrderResponse(order),
+		Items:             make([]WorkOrderItemResponse, 0, len(items)),
+		Tools:             make([]WorkOrderToolResponse, 0, len(tools)),
+	}
+	for _, item := range items {
+		response.Items = append(response.Items, toWorkOrderItemResponse(item))
+	}
+	for _, tool := range tools {
+		response.Tools = append(response.Tools, toWorkOrderToolResponse(tool))
+	}
+
+	c.JSON(statusCode, utils.Response{
+		Success: true,
+		Message: message,
+		Data:    response,
+	})
+}
+
+func parseWorkOrderID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid work order ID")
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func toWorkOrderResponse(row sqlcdb.GetWorkOrderRow) WorkOrderResponse {
+	response := WorkOrderResponse{
+		ID:         row.ID,
+		LocationID: row.LocationID,
+		Title:      row.Title,
+		Status:     row.Status,
+		ClosedAt:   utils.FormatTimestamp(row.ClosedAt),
+		CreatedAt:  utils.FormatTimestamp(row.CreatedAt),
+		UpdatedAt:  utils.FormatTimestamp(row.UpdatedAt),
+	}
+	if row.Region.Valid {
+		region := string(row.Region.RegionType)
+		response.Region = &region
+	}
+	if row.Regency.Valid {
+		response.Regency = &row.Regency.String
+	}
+	if row.Cluster.Valid {
+		response.Cluster = &row.Cluster.String
+	}
+	if row.Description.Valid {
+		response.Description = &row.Description.String
+	}
+	if row.TechnicianID.Valid {
+		response.TechnicianID = &row.TechnicianID.Int32
+	}
+	if row.Technician.Valid {
+		response.Technician = &row.Technician.String
+	}
+	if row.Resolution.Valid {
+		response.Resolution = &row.Resolution.String
+	}
+	if row.CreatedBy.Valid {
+		response.CreatedBy = &row.CreatedBy.String
+	}
+	if row.ClosedBy.Valid {
+		response.ClosedBy = &row.ClosedBy.String
+	}
+	return response
+}
+
+func toWorkOrderItemResponse(row sqlcdb.ListWorkOrderItemsRow) WorkOrderItemResponse {
+	response := WorkOrderItemResponse{
+		ID:          row.ID,
+		SparepartID: row.SparepartID,
+		StockType:   string(row.StockType),
+		Quantity:    row.Quantity,
+	}
+	if row.SparepartName.Valid {
+		response.SparepartName = &row.SparepartName.String
+	}
+	if row.StockItemID.Valid {
+		response.StockItemID = &row.StockItemID.Int32
+	}
+	if row.QuantityAfter.Valid {
+		response.QuantityAfter = &row.QuantityAfter.Int32
+	}
+	return response
+}
+
+func toWorkOrderToolResponse(row sqlcdb.ListWorkOrderToolsRow) WorkOrderToolResponse {
+	response := WorkOrderToolResponse{
+		ID:       row.ID,
+		ToolsID:  row.ToolsID,
+		Quantity: row.Quantity,
+	}
+	if row.ToolsName.Valid {
+		response.ToolsName = &row.ToolsName.String
+	}
+	return response
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+// expectWorkOrderResponse expects the reads of the work order written after a successful action
+func expectWorkOrderResponse(repo *mocks.MockSparepartStockRepository, order sqlcdb.GetWorkOrderRow) {
+	repo.EXPECT().GetWorkOrder(gomock.Any(), order.ID).Return(order, nil)
+	repo.EXPECT().ListWorkOrderItems(gomock.Any(), order.ID).Return([]sqlcdb.ListWorkOrderItemsRow{}, nil)
+	repo.EXPECT().ListWorkOrderTools(gomock.Any(), order.ID).Return([]sqlcdb.ListWorkOrderToolsRow{}, nil)
+}
+
+func TestWorkOrderHandlerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewWorkOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().CreateWorkOrder(gomock.Any(), sqlcdb.CreateWorkOrderParams{
+		Title:      "Ganti baterai",
+		Technician: pgtype.Text{String: "andi", Valid: true},
+		CreatedBy:  pgtype.Text{String: "budi", Valid: true},
+		LocationID: 2,
+	}).Return(sqlcdb.WorkOrder{ID: 6, LocationID: 2, Title: "Ganti baterai", Status: workOrderStatusOpen}, nil)
+	repo.EXPECT().CreateWorkOrderItem(gomock.Any(), sqlcdb.CreateWorkOrderItemParams{WorkOrderID: 6, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2, SparepartID: 7}).
+		Return(sqlcdb.WorkOrderItem{ID: 1}, nil)
+	repo.EXPECT().CreateWorkOrderTool(gomock.Any(), sqlcdb.CreateWorkOrderToolParams{WorkOrderID: 6, Quantity: 1, ToolsID: 9}).
+		Return(sqlcdb.WorkOrderTool{ID: 1}, nil)
+	expectWorkOrderResponse(repo, sqlcdb.GetWorkOrderRow{ID: 6, LocationID: 2, Title: "Ganti baterai", Status: workOrderStatusOpen})
+
+	body := `{"location_id": 2, "title": "Ganti baterai", "technician": "andi",
+		"items": [{"sparepart_id": 7, "stock_type": "NEW_STOCK", "quantity": 2}], "tools": [{"tools_id": 9, "quantity": 1}]}`
+	w := performRequestAs("budi", http.MethodPost, "/work-orders", h.Create, "/work-orders", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var order WorkOrderDetailResponse
+	decodeResponse(t, w, &order)
+	if order.ID != 6 || order.Status != workOrderStatusOpen || order.Items == nil || order.Tools == nil {
+		t.Fatalf("unexpected work order response: %+v", order)
+	}
+}
+
+func TestWorkOrderHandlerSetToolsRejectsNonTools(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewWorkOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetWorkOrderForUpdate(gomock.Any(), int32(6)).Return(sqlcdb.WorkOrder{ID: 6, Status: workOrderStatusOpen}, nil)
+	repo.EXPECT().DeleteWorkOrderTools(gomock.Any(), int32(6)).Return(nil)
+	repo.EXPECT().CreateWorkOrderTool(gomock.Any(), gomock.Any()).Return(sqlcdb.WorkOrderTool{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPut, "/work-orders/:id/tools", h.SetTools, "/work-orders/6/tools", `{"tools": [{"tools_id": 7, "quantity": 1}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "tools[0].tools_id" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestWorkOrderHandlerClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewWorkOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetWorkOrderForUpdate(gomock.Any(), int32(6)).Return(sqlcdb.WorkOrder{ID: 6, LocationID: 2, Status: workOrderStatusOpen}, nil)
+	repo.EXPECT().ListWorkOrderItems(gomock.Any(), int32(6)).Return([]sqlcdb.ListWorkOrderItemsRow{
+		{ID: 1, WorkOrderID: 6, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2},
+	}, nil)
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), sqlcdb.GetSparepartStockByKeyForUpdateParams{LocationID: 2, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK}).
+		Return(sqlcdb.SparepartStockItem{ID: 10, LocationID: 2, Quantity: 5}, nil)
+	repo.EXPECT().TransferOutSparepartStock(gomock.Any(), sqlcdb.TransferOutSparepartStockParams{ID: 10, Quantity: 2}).
+		Return(sqlcdb.SparepartStockItem{ID: 10, LocationID: 2, Quantity: 3}, nil)
+	repo.EXPECT().SetWorkOrderItemConsumption(gomock.Any(), sqlcdb.SetWorkOrderItemConsumptionParams{
+		ID: 1, StockItemID: pgtype.Int4{Int32: 10, Valid: true}, QuantityAfter: pgtype.Int4{Int32: 3, Valid: true},
+	}).Return(nil)
+	repo.EXPECT().CloseWorkOrder(gomock.Any(), sqlcdb.CloseWorkOrderParams{
+		ID:         6,
+		Status:     workOrderStatusClosed,
+		Resolution: pgtype.Text{String: "Baterai diganti", Valid: true},
+		ClosedBy:   pgtype.Text{String: "andi", Valid: true},
+	}).Return(sqlcdb.WorkOrder{ID: 6, Status: workOrderStatusClosed}, nil)
+	expectWorkOrderResponse(repo, sqlcdb.GetWorkOrderRow{ID: 6, LocationID: 2, Status: workOrderStatusClosed})
+
+	w := performRequestAs("andi", http.MethodPost, "/work-orders/:id/close", h.Close, "/work-orders/6/close", `{"resolution": "Baterai diganti"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWorkOrderHandlerCloseReportsShortages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewWorkOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetWorkOrderForUpdate(gomock.Any(), int32(6)).Return(sqlcdb.WorkOrder{ID: 6, LocationID: 2, Status: workOrderStatusOpen}, nil)
+	repo.EXPECT().ListWorkOrderItems(gomock.Any(), int32(6)).Return([]sqlcdb.ListWorkOrderItemsRow{
+		{ID: 1, SparepartID: 7, StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 3},
+		{ID: 2, SparepartID: 8, StockType: sqlcdb.StockTypeUSEDSTOCK, Quantity: 1},
+	}, nil)
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), gomock.Any()).Return(sqlcdb.SparepartStockItem{ID: 10, Quantity: 1}, nil)
+	repo.EXPECT().GetSparepartStockByKeyForUpdate(gomock.Any(), gomock.Any()).Return(sqlcdb.SparepartStockItem{}, pgx.ErrNoRows)
+
+	w := performRequest(http.MethodPost, "/work-orders/:id/close", h.Close, "/work-orders/6/close", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeResponse(t, w, nil)
+	if len(resp.Errors) != 2 || resp.Errors[0].Field != "items[0].quantity" || resp.Errors[1].Field != "items[1]" {
+		t.Fatalf("unexpected field errors: %+v", resp.Errors)
+	}
+}
+
+func TestWorkOrderHandlerCloseRequiresOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSparepartStockRepository(ctrl)
+	h := NewWorkOrderHandler(repo, testLogger)
+
+	expectTransaction(repo)
+	repo.EXPECT().GetWorkOrderForUpdate(gomock.Any(), int32(6)).Return(sqlcdb.WorkOrder{ID: 6, Status: workOrderStatusCancelled}, nil)
+
+	w := performRequest(http.MethodPost, "/work-orders/:id/close", h.Close, "/work-orders/6/close", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,142 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	// deliveryBatch is how many deliveries one run sends at most
+	deliveryBatch = 20
+	// maxRetryDelay caps the growing delay between attempts
+	maxRetryDelay = time.Hour
+	// maxErrorLength caps the stored error of a failed attempt
+	maxErrorLength = 500
+)
+
+// Dispatcher sends the queued messages through the provider. A delivery is retried after
+// 1, 2, 4, ... minutes (at most an hour apart) until it succeeds or maxAttempts attempts
+// failed, which marks it FAILED; so does a phone number that cannot be sent to. Deliveries
+// still pending after maxAge are given up on.
+type Dispatcher struct {
+	logger      *zap.Logger
+	queries     repository.MessageDispatchRepository
+	provider    Provider
+	countryCode string
+	timeout     time.Duration
+	maxAttempts int
+	maxAge      time.Duration
+}
+
+func NewDispatcher(queries repository.MessageDispatchRepository, provider Provider, countryCode string, timeout time.Duration, maxAttempts int, maxAge time.Duration, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		logger:      logger,
+		queries:     queries,
+		provider:    provider,
+		countryCode: countryCode,
+		timeout:     timeout,
+		maxAttempts: max(maxAttempts, 1),
+		maxAge:      maxAge,
+	}
+}
+
+// Dispatch runs one round; a failing delivery does not stop the others
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	expired, err := d.queries.ExpireMessageDeliveries(ctx, int32(d.maxAge.Seconds()))
+	if err != nil {
+		return fmt.Errorf("failed to expire message deliveries: %w", err)
+	}
+	if expired > 0 {
+		d.logger.Warn("Message deliveries expired", zap.Int64("count", expired))
+	}
+
+	// The lease outlasts sending the whole batch, so no other replica picks it up meanwhile
+	lease := d.timeout*deliveryBatch + time.Minute
+	deliveries, err := d.queries.ClaimMessageDeliveries(ctx, sqlcdb.ClaimMessageDeliveriesParams{
+		LeaseSeconds: int32(lease.Seconds()),
+		Limit:        deliveryBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim message deliveries: %w", err)
+	}
+
+	var errs []error
+	for _, delivery := range deliveries {
+		if err := d.send(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("message delivery %d: %w", delivery.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send sends one delivery and records the outcome; only failing to record it is an error
+func (d *Dispatcher) send(ctx context.Context, delivery sqlcdb.MessageDelivery) error {
+	attempts := int(delivery.Attempts) + 1
+	params := sqlcdb.MarkMessageDeliveryFailedParams{
+		ID:            delivery.ID,
+		Status:        "PENDING",
+		NextAttemptAt: pgtype.Timestamptz{Time: time.Now().UTC().Add(retryDelay(attempts)), Valid: true},
+	}
+	if attempts >= d.maxAttempts {
+		params.Status = "FAILED"
+	}
+
+	body, err := Render(delivery.Event, delivery.Pic, delivery.Payload)
+	if err != nil {
+		params.Status = "FAILED"
+		return d.fail(ctx, delivery, params, err)
+	}
+	params.Body = pgtype.Text{String: body, Valid: true}
+
+	to, err := NormalizePhone(delivery.Phone, d.countryCode)
+	if err != nil {
+		params.Status = "FAILED"
+		return d.fail(ctx, delivery, params, err)
+	}
+
+	messageID, err := d.provider.Send(ctx, to, body)
+	if err != nil {
+		return d.fail(ctx, delivery, params, err)
+	}
+	return d.queries.MarkMessageDeliverySent(ctx, sqlcdb.MarkMessageDeliverySentParams{
+		ID:                delivery.ID,
+		Body:              params.Body,
+		ProviderMessageID: pgtype.Text{String: messageID, Valid: messageID != ""},
+	})
+}
+
+// fail records a failed attempt
+func (d *Dispatcher) fail(ctx context.Context, delivery sqlcdb.MessageDelivery, params sqlcdb.MarkMessageDeliveryFailedParams, sendErr error) error {
+	params.LastError = pgtype.Text{String: truncate(sendErr.Error(), maxErrorLength), Valid: true}
+	d.logger.Warn("Message delivery failed",
+		zap.Int64("delivery_id", delivery.ID),
+		zap.String("event", delivery.Event),
+		zap.Int("attempts", int(delivery.Attempts)+1),
+		zap.String("status", params.Status),
+		zap.Error(sendErr),
+	)
+	return d.queries.MarkMessageDeliveryFailed(ctx, params)
+}
+
+// retryDelay is the wait after the given number of failed attempts: 1, 2, 4, ... minutes
+func retryDelay(attempts int) time.Duration {
+	if attempts > 7 {
+		return maxRetryDelay
+	}
+	return min(time.Minute<<(attempts-1), maxRetryDelay)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,177 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type recordingProvider struct {
+	to, body string
+	err      error
+}
+
+func (p *recordingProvider) Send(ctx context.Context, to, body string) (string, error) {
+	p.to, p.body = to, body
+	return "msg-1", p.err
+}
+
+const transferPayload = `{"transfer_id": 30, "sparepart": "BMS", "stock_type": "NEW_STOCK", "quantity": 3, "source_cluster": "Gudang Ambon", "destination_cluster": "Dobo", "notes": null}`
+
+func TestDispatcherSendsDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockMessageDispatchRepository(ctrl)
+	provider := &recordingProvider{}
+	dispatcher := NewDispatcher(repo, provider, "62", 5*time.Second, 3, 24*time.Hour, zap.NewNop())
+
+	repo.EXPECT().ExpireMessageDeliveries(gomock.Any(), int32(86400)).Return(int64(0), nil)
+	repo.EXPECT().ClaimMessageDeliveries(gomock.Any(), gomock.Any()).Return([]sqlcdb.MessageDelivery{{
+		ID: 7, Pic: "Hendra", Phone: "0812-1801-2082", Event: EventTransferredIn, Payload: []byte(transferPayload),
+	}}, nil)
+	body := "Hi Hendra, 3 x BMS (NEW_STOCK) is being transferred from Gudang Ambon to Dobo (transfer #30)."
+	repo.EXPECT().MarkMessageDeliverySent(gomock.Any(), sqlcdb.MarkMessageDeliverySentParams{
+		ID:                7,
+		Body:              pgtype.Text{String: body, Valid: true},
+		ProviderMessageID: pgtype.Text{String: "msg-1", Valid: true},
+	}).Return(nil)
+
+	if err := dispatcher.Dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+	if provider.to != "+6281218012082" || provider.body != body {
+		t.Fatalf("unexpected message to %q: %q", provider.to, provider.body)
+	}
+}
+
+func TestDispatcherRetriesFailedDelivery(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int32
+		status   string
+	}{
+		{name: "retries remain", attempts: 0, status: "PENDING"},
+		{name: "last attempt", attempts: 2, status: "FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockMessageDispatchRepository(ctrl)
+			provider := &recordingProvider{err: errors.New("provider returned status 503")}
+			dispatcher := NewDispatcher(repo, provider, "62", 5*time.Second, 3, 24*time.Hour, zap.NewNop())
+
+			repo.EXPECT().ExpireMessageDeliveries(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+			repo.EXPECT().ClaimMessageDeliveries(gomock.Any(), gomock.Any()).Return([]sqlcdb.MessageDelivery{{
+				ID: 7, Pic: "Hendra", Phone: "+62 812 1801 2082", Event: EventTransferredOut, Payload: []byte(transferPayload), Attempts: tt.attempts,
+			}}, nil)
+			repo.EXPECT().MarkMessageDeliveryFailed(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, arg sqlcdb.MarkMessageDeliveryFailedParams) error {
+					if arg.Status != tt.status || arg.LastError.String != "provider returned status 503" || !arg.Body.Valid {
+						t.Fatalf("unexpected failure record: %+v", arg)
+					}
+					return nil
+				})
+
+			if err := dispatcher.Dispatch(context.Background()); err != nil {
+				t.Fatalf("dispatch failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestDispatcherFailsInvalidPhoneNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockMessageDispatchRepository(ctrl)
+	dispatcher := NewDispatcher(repo, &recordingProvider{}, "62", 5*time.Second, 3, 24*time.Hour, zap.NewNop())
+
+	repo.EXPECT().ExpireMessageDeliveries(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	repo.EXPECT().ClaimMessageDeliveries(gomock.Any(), gomock.Any()).Return([]sqlcdb.MessageDelivery{{
+		ID: 7, Pic: "Hendra", Phone: "-", Event: EventRequestCreated, Payload: []byte(`{"request_id": 5}`),
+	}}, nil)
+	repo.EXPECT().MarkMessageDeliveryFailed(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, arg sqlcdb.MarkMessageDeliveryFailedParams) error {
+			if arg.Status != "FAILED" {
+				t.Fatalf("expected an invalid number to fail at once, got %+v", arg)
+			}
+			return nil
+		})
+
+	if err := dispatcher.Dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{phone: "0812-1801-2082", want: "+6281218012082"},
+		{phone: "+62 812 1801 2082", want: "+6281218012082"},
+		{phone: "6281218012082", want: "+6281218012082"},
+		{phone: "81218012082", want: "+6281218012082"},
+		{phone: "+1 (415) 555-0100", want: "+14155550100"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizePhone(tt.phone, "62")
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, %v; want %q", tt.phone, got, err, tt.want)
+		}
+	}
+	if _, err := NormalizePhone("12", "62"); err == nil {
+		t.Error("expected a too short number to be rejected")
+	}
+}
+
+func TestTwilioProviderSendsWhatsApp(t *testing.T) {
+	var form url.Values
+	var user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ = r.BasicAuth()
+		body, _ := io.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(body))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"sid": "SM123"}`)
+	}))
+	defer server.Close()
+
+	provider := NewTwilioProvider("AC1", "secret", "whatsapp:+14155238886", 5*time.Second)
+	provider.endpoint = server.URL
+
+	id, err := provider.Send(context.Background(), "+6281218012082", "hello")
+	if err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if id != "SM123" || user != "AC1" || form.Get("To") != "whatsapp:+6281218012082" || form.Get("Body") != "hello" {
+		t.Fatalf("unexpected request: user %q, form %v, id %q", user, form, id)
+	}
+}
+
+func TestGatewayProviderReportsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing bearer token: %v", r.Header)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = io.WriteString(w, "device offline")
+	}))
+	defer server.Close()
+
+	_, err := NewGatewayProvider(server.URL, "token", 5*time.Second).Send(context.Background(), "+6281218012082", "hello")
+	if err == nil || !strings.Contains(err.Error(), "502: device offline") {
+		t.Fatalf("expected the gateway status in the error, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Events a contact person is messaged about
+const (
+	EventTransferredIn  = "stock.transferred_in"
+	EventTransferredOut = "stock.transferred_out"
+	EventRequestCreated = "request.created"
+)
+
+// Payload is what the database recorded about the transfer or request a delivery is for
+type Payload struct {
+	TransferID         int32  `json:"transfer_id"`
+	RequestID          int32  `json:"request_id"`
+	Sparepart          string `json:"sparepart"`
+	StockType          string `json:"stock_type"`
+	Quantity           int32  `json:"quantity"`
+	SourceCluster      string `json:"source_cluster"`
+	DestinationCluster string `json:"destination_cluster"`
+	RequestedBy        string `json:"requested_by"`
+	Notes              string `json:"notes"`
+}
+
+// Render writes the text sent to the contact person pic for event
+func Render(event, pic string, data []byte) (string, error) {
+	var p Payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return "", fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	var text string
+	switch event {
+	case EventTransferredIn:
+		text = fmt.Sprintf("Hi %s, %d x %s (%s) is being transferred from %s to %s (transfer #%d).",
+			pic, p.Quantity, p.Sparepart, p.StockType, p.SourceCluster, p.DestinationCluster, p.TransferID)
+	case EventTransferredOut:
+		text = fmt.Sprintf("Hi %s, %d x %s (%s) was taken from the %s stock for %s (transfer #%d).",
+			pic, p.Quantity, p.Sparepart, p.StockType, p.SourceCluster, p.DestinationCluster, p.TransferID)
+	case EventRequestCreated:
+		text = fmt.Sprintf("Hi %s, sparepart request #%d for %s was submitted by %s and awaits approval.",
+			pic, p.RequestID, p.DestinationCluster, p.RequestedBy)
+	default:
+		return "", fmt.Errorf("unknown event %q", event)
+	}
+	if p.Notes != "" {
+		text += " Notes: " + p.Notes
+	}
+	return text, nil
+}
+
+// NormalizePhone turns a contact person's phone number into E.164 form. Numbers written
+// nationally (with a leading 0) get countryCode, e.g. 0812-1801-2082 becomes +6281218012082
+// for country code 62.
+func NormalizePhone(phone, countryCode string) (string, error) {
+	international := strings.HasPrefix(strings.TrimSpace(phone), "+")
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	number := digits.String()
+
+	switch {
+	case international:
+	case strings.HasPrefix(number, "0"):
+		number = countryCode + strings.TrimLeft(number, "0")
+	case !strings.HasPrefix(number, countryCode):
+		number = countryCode + number
+	}
+	if len(number) < 8 || len(number) > 15 {
+		return "", fmt.Errorf("invalid phone number %q", phone)
+	}
+	return "+" + number, nil
+}
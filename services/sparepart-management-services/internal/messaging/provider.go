@@ -0,0 +1,129 @@
+// Package messaging sends SMS and WhatsApp messages to the contact persons of a location
+// when stock is transferred to or from it, or a sparepart request is made for it.
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Providers a message can be sent through
+const (
+	ProviderTwilio  = "twilio"
+	ProviderGateway = "gateway"
+)
+
+// maxResponseExcerpt caps the provider response quoted in an error
+const maxResponseExcerpt = 500
+
+// Provider sends one message to a phone number in E.164 form, returning the provider's
+// message ID (empty when it has none)
+type Provider interface {
+	Send(ctx context.Context, to, body string) (string, error)
+}
+
+// TwilioProvider sends through the Twilio Messages API. With a "whatsapp:" From number the
+// messages go out over WhatsApp, otherwise as SMS.
+type TwilioProvider struct {
+	endpoint   string
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+func NewTwilioProvider(accountSID, authToken, from string, timeout time.Duration) *TwilioProvider {
+	return &TwilioProvider{
+		endpoint:   "https://api.twilio.com/2010-04-01/Accounts/" + url.PathEscape(accountSID) + "/Messages.json",
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		client:     &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *TwilioProvider) Send(ctx context.Context, to, body string) (string, error) {
+	if strings.HasPrefix(p.from, "whatsapp:") {
+		to = "whatsapp:" + to
+	}
+	form := url.Values{"From": {p.from}, "To": {to}, "Body": {body}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create Twilio request: %w", err)
+	}
+	req.SetBasicAuth(p.accountSID, p.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var result struct {
+		SID string `json:"sid"`
+	}
+	if err := do(p.client, req, &result); err != nil {
+		return "", err
+	}
+	return result.SID, nil
+}
+
+// GatewayProvider posts {"to", "message"} as JSON to a messaging gateway (e.g. a WhatsApp
+// gateway), authenticated with a bearer token when one is configured. The gateway may
+// answer with {"id"} to report its message ID.
+type GatewayProvider struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewGatewayProvider(url, token string, timeout time.Duration) *GatewayProvider {
+	return &GatewayProvider{url: url, token: token, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *GatewayProvider) Send(ctx context.Context, to, body string) (string, error) {
+	payload, err := json.Marshal(map[string]string{"to": to, "message": body})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create gateway request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := do(p.client, req, &result); err != nil {
+		return "", err
+	}
+	return result.ID, nil
+}
+
+// do sends the request and decodes a successful JSON response into result; a response
+// that is not JSON is accepted without a message ID
+func do(client *http.Client, req *http.Request, result any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 300 {
+		if len(body) > maxResponseExcerpt {
+			body = body[:maxResponseExcerpt]
+		}
+		return fmt.Errorf("provider returned status %d: %s", resp.StatusCode, body)
+	}
+	_ = json.Unmarshal(body, result)
+	return nil
+}
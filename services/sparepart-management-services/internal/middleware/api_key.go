@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// apiKeyIDKey is the context key holding the ID of the API key that authenticated the request
+const apiKeyIDKey = "api_key_id"
+
+// APIKey authenticates requests carrying an API key in utils.APIKeyHeader, as an
+// alternative to the bearer token checked by Authenticate (which it must run before).
+// Unknown, revoked and expired keys get a 401. A key acts as the user "api-key:<name>"
+// without a role, so admin routes stay closed to it; GET and HEAD requests need no more
+// than the read-only scope, anything else needs the stock-write scope and a route at or under
+// stockPrefix. Requests without the header are left to Authenticate.
+func APIKey(queries repository.APIKeyAuthRepository, stockPrefix string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(utils.APIKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		apiKey, err := queries.GetActiveAPIKeyByHash(ctx, utils.HashAPIKey(key))
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.Unauthorized(c, "Invalid or expired API key")
+			c.Abort()
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to look up API key", zap.Error(err))
+			utils.InternalServerError(c, "Failed to verify API key")
+			c.Abort()
+			return
+		}
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			if !slices.Contains(apiKey.Scopes, utils.ScopeStockWrite) || !underPrefix(c.FullPath(), stockPrefix) {
+				utils.Error(c, "API key scope does not allow this request", http.StatusForbidden)
+				c.Abort()
+				return
+			}
+		}
+
+		// A failed touch only loses the last use time, so the request goes on
+		if err := queries.TouchAPIKey(ctx, apiKey.ID); err != nil {
+			logger.Warn("Failed to record API key use", zap.Int32("api_key_id", apiKey.ID), zap.Error(err))
+		}
+
+		username := "api-key:" + apiKey.Name
+		c.Set(claimsKey, utils.TokenClaims{Username: username})
+		c.Set(utils.UserKey, username)
+		c.Set(apiKeyIDKey, apiKey.ID)
+		c.Next()
+	}
+}
+
+// underPrefix reports whether route is prefix itself or one of its sub-routes; a sibling that
+// merely starts with the same characters (/stock-levels for /stock) is not
+func underPrefix(route, prefix string) bool {
+	return route == prefix || strings.HasPrefix(route, strings.TrimSuffix(prefix, "/")+"/")
+}
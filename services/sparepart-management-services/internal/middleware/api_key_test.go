@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// fakeAPIKeys holds the active API keys by hash
+type fakeAPIKeys struct {
+	keys    map[string]sqlcdb.ApiKey
+	touched []int32
+}
+
+func (f *fakeAPIKeys) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (sqlcdb.ApiKey, error) {
+	key, ok := f.keys[keyHash]
+	if !ok {
+		return sqlcdb.ApiKey{}, pgx.ErrNoRows
+	}
+	return key, nil
+}
+
+func (f *fakeAPIKeys) TouchAPIKey(ctx context.Context, id int32) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func TestAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const secret = "test-secret"
+
+	queries := &fakeAPIKeys{keys: map[string]sqlcdb.ApiKey{
+		utils.HashAPIKey("spk_reader"): {ID: 1, Name: "reporting", Scopes: []string{utils.ScopeReadOnly}},
+		utils.HashAPIKey("spk_writer"): {ID: 2, Name: "sync", Scopes: []string{utils.ScopeReadOnly, utils.ScopeStockWrite}},
+	}}
+
+	r := gin.New()
+	secured := r.Group("/api", APIKey(queries, "/api/stock", zap.NewNop()), Authenticate(secret))
+	ok := func(c *gin.Context) { utils.Success(c, "ok", utils.UserID(c)) }
+	secured.GET("/stock/export/csv", ok)
+	secured.POST("/stock", ok)
+	secured.POST("/stock/:id/photos", ok)
+	secured.POST("/stock-levels", ok)
+	secured.POST("/location", ok)
+	secured.GET("/admin/export-log", RequireRole(utils.RoleAdmin), ok)
+
+	token, _, err := utils.IssueToken(secret, 3, "budi", utils.RoleUser, utils.AccessToken, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		apiKey        string
+		authorization string
+		wantStatus    int
+		wantUser      string
+	}{
+		{"read-only key reads", http.MethodGet, "/api/stock/export/csv", "spk_reader", "", http.StatusOK, "api-key:reporting"},
+		{"read-only key writes", http.MethodPost, "/api/stock", "spk_reader", "", http.StatusForbidden, ""},
+		{"stock-write key writes stock", http.MethodPost, "/api/stock", "spk_writer", "", http.StatusOK, "api-key:sync"},
+		{"stock-write key writes stock photos", http.MethodPost, "/api/stock/4/photos", "spk_writer", "", http.StatusOK, "api-key:sync"},
+		{"stock-write key writes stock levels", http.MethodPost, "/api/stock-levels", "spk_writer", "", http.StatusForbidden, ""},
+		{"stock-write key writes locations", http.MethodPost, "/api/location", "spk_writer", "", http.StatusForbidden, ""},
+		{"key on admin route", http.MethodGet, "/api/admin/export-log", "spk_writer", "", http.StatusForbidden, ""},
+		{"unknown key", http.MethodGet, "/api/stock/export/csv", "spk_unknown", "", http.StatusUnauthorized, ""},
+		{"unknown key with token", http.MethodGet, "/api/stock/export/csv", "spk_unknown", "Bearer " + token, http.StatusUnauthorized, ""},
+		{"token without key", http.MethodGet, "/api/stock/export/csv", "", "Bearer " + token, http.StatusOK, "budi"},
+		{"neither", http.MethodGet, "/api/stock/export/csv", "", "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set(utils.APIKeyHeader, tt.apiKey)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantUser != "" && w.Body.String() != `{"success":true,"message":"ok","data":"`+tt.wantUser+`"}` {
+				t.Fatalf("expected user %q, got %s", tt.wantUser, w.Body.String())
+			}
+		})
+	}
+
+	// Only keys that passed their scope check are recorded as used
+	if len(queries.touched) != 4 {
+		t.Fatalf("expected 4 recorded key uses, got %v", queries.touched)
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// claimsKey is the context key holding the utils.TokenClaims of the authenticated request
+const claimsKey = "auth_claims"
+
+// Authenticate rejects requests without a valid access token in the Authorization header
+// ("Bearer <token>") with a 401. The username of the token becomes the requesting user
+// (see utils.UserID). Requests already authenticated by an API key (see APIKey) pass through.
+func Authenticate(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(claimsKey); ok {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			utils.Unauthorized(c, "Missing bearer token")
+			c.Abort()
+			return
+		}
+
+		claims, err := utils.ParseToken(secret, strings.TrimSpace(token), utils.AccessToken)
+		if err != nil {
+			utils.Unauthorized(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+
+		c.Set(claimsKey, claims)
+		c.Set(utils.UserKey, claims.Username)
+		c.Next()
+	}
+}
+
+// RequireRole rejects authenticated requests whose token lacks role with a 403; it must
+// run after Authenticate
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := c.Get(claimsKey)
+		if !ok {
+			utils.Unauthorized(c, "Missing bearer token")
+			c.Abort()
+			return
+		}
+		if claims.(utils.TokenClaims).Role != role {
+			utils.Error(c, "Insufficient permissions", http.StatusForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
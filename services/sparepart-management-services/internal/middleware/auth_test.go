@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const secret = "test-secret"
+
+	issue := func(secret, tokenType, role string, ttl time.Duration) string {
+		token, _, err := utils.IssueToken(secret, 3, "budi", role, tokenType, ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	r := gin.New()
+	secured := r.Group("", Authenticate(secret))
+	secured.GET("/stock", func(c *gin.Context) {
+		utils.Success(c, "ok", utils.UserID(c))
+	})
+	secured.GET("/admin", RequireRole(utils.RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+	}{
+		{"no token", "/stock", "", http.StatusUnauthorized},
+		{"not a bearer token", "/stock", "Basic YnVkaTpzM2NyZXQ=", http.StatusUnauthorized},
+		{"valid token", "/stock", "Bearer " + issue(secret, utils.AccessToken, utils.RoleUser, time.Minute), http.StatusOK},
+		{"expired token", "/stock", "Bearer " + issue(secret, utils.AccessToken, utils.RoleUser, -time.Minute), http.StatusUnauthorized},
+		{"other secret", "/stock", "Bearer " + issue("other", utils.AccessToken, utils.RoleUser, time.Minute), http.StatusUnauthorized},
+		{"refresh token", "/stock", "Bearer " + issue(secret, utils.RefreshToken, utils.RoleUser, time.Minute), http.StatusUnauthorized},
+		{"admin route as user", "/admin", "Bearer " + issue(secret, utils.AccessToken, utils.RoleUser, time.Minute), http.StatusForbidden},
+		{"admin route as admin", "/admin", "Bearer " + issue(secret, utils.AccessToken, utils.RoleAdmin, time.Minute), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.path == "/stock" && w.Code == http.StatusOK && w.Body.String() != `{"success":true,"message":"ok","data":"budi"}` {
+				t.Fatalf("expected the token's user, got %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// exportLogTimeout bounds the write of one export log entry
+const exportLogTimeout = 5 * time.Second
+
+// RecordExport adds every successful export of entity in format to the export log: the
+// requesting user (see utils.UserID), the query filters, the rows written (reported by the
+// handler under utils.ExportRowsKey) and the duration. A failing write is logged and never
+// fails the export.
+func RecordExport(queries repository.ExportLogRepository, entity, format string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// A streamed export that failed part way is aborted after its 200 was sent
+		if c.Writer.Status() != http.StatusOK || c.IsAborted() {
+			return
+		}
+
+		filters := make(map[string]string)
+		for key, values := range c.Request.URL.Query() {
+			if key == "store" {
+				continue
+			}
+			filters[key] = strings.Join(values, ",")
+		}
+		encoded, err := json.Marshal(filters)
+		if err != nil {
+			encoded = []byte("{}")
+		}
+
+		// The request context may already be past its timeout once the file is sent
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), exportLogTimeout)
+		defer cancel()
+
+		err = queries.CreateExportLog(ctx, sqlcdb.CreateExportLogParams{
+			UserID:     utils.TextFilter(utils.UserID(c)),
+			Entity:     entity,
+			Format:     format,
+			Filters:    encoded,
+			RowCount:   int32(c.GetInt(utils.ExportRowsKey)),
+			DurationMs: int32(time.Since(start).Milliseconds()),
+			Stored:     c.Query("store") == "true",
+		})
+		if err != nil {
+			logger.Error("Failed to record export",
+				zap.String("entity", entity),
+				zap.String("format", format),
+				zap.Error(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// fakeExportLog keeps the recorded exports in memory
+type fakeExportLog struct {
+	repository.ExportLogRepository
+	created []sqlcdb.CreateExportLogParams
+}
+
+func (f *fakeExportLog) CreateExportLog(ctx context.Context, arg sqlcdb.CreateExportLogParams) error {
+	f.created = append(f.created, arg)
+	return nil
+}
+
+func TestRecordExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	queries := &fakeExportLog{}
+	r := gin.New()
+	authenticated := func(c *gin.Context) { c.Set(utils.UserKey, "user-7") }
+	r.GET("/stock/export/excel", authenticated, RecordExport(queries, "SPAREPART_STOCK", "EXCEL", zap.NewNop()), func(c *gin.Context) {
+		if c.Query("region") == "" {
+			utils.BadRequest(c, "region is required")
+			return
+		}
+		c.Set(utils.ExportRowsKey, 42)
+		c.Data(http.StatusOK, "application/octet-stream", []byte("file"))
+	})
+
+	for _, target := range []string{"/stock/export/excel?region=MALUKU&store=true", "/stock/export/excel"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// The failed export is not recorded
+	if len(queries.created) != 1 {
+		t.Fatalf("expected 1 recorded export, got %d", len(queries.created))
+	}
+	got := queries.created[0]
+	if got.UserID.String != "user-7" || got.Entity != "SPAREPART_STOCK" || got.Format != "EXCEL" ||
+		string(got.Filters) != `{"region":"MALUKU"}` || got.RowCount != 42 || !got.Stored {
+		t.Fatalf("unexpected export log entry: %+v", got)
+	}
+}
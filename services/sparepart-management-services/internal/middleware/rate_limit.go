@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sparepart-management-services/internal/ratelimit"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimit takes a token from limiter for every request, keyed by the API key that
+// authenticated the request (see APIKey, which must run before) or else the client IP, and
+// answers with a 429 and Retry-After once the bucket is empty. Every response carries
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (seconds until the bucket is
+// full again). A nil limiter disables it; when the limiter fails the request is let through.
+func RateLimit(limiter ratelimit.Limiter, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		// An unverified API key header would give every made-up key a fresh bucket
+		key := "ip:" + c.ClientIP()
+		if id, ok := c.Get(apiKeyIDKey); ok {
+			key = fmt.Sprintf("key:%d", id)
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), key)
+		if err != nil {
+			logger.Warn("Rate limit check failed", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(max(seconds(result.RetryAfter), 1)))
+			utils.Error(c, "Too many requests", http.StatusTooManyRequests)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// seconds rounds d up to whole seconds
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/ratelimit"
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	queries := &fakeAPIKeys{keys: map[string]sqlcdb.ApiKey{
+		utils.HashAPIKey("spk_reporting"): {ID: 7, Name: "reporting", Scopes: []string{utils.ScopeReadOnly}},
+	}}
+	limit := RateLimit(ratelimit.NewMemory(2, time.Minute), zap.NewNop())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	r := gin.New()
+	// As in main without TRUSTED_PROXIES
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	r.GET("/share/:token", limit, ok)
+	r.GET("/stock/export/csv", APIKey(queries, "/stock", zap.NewNop()), limit, ok)
+
+	request := func(path, ip string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		for name, values := range header {
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		w := request("/share/abc", "10.0.0.1", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i+1, w.Code)
+		}
+		if w.Header().Get("X-RateLimit-Limit") != "2" || w.Header().Get("X-RateLimit-Remaining") != strconv.Itoa(1-i) {
+			t.Fatalf("request %d: unexpected rate limit headers %v", i+1, w.Header())
+		}
+	}
+	w := request("/share/abc", "10.0.0.1", nil)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "30" || w.Header().Get("X-RateLimit-Reset") != "60" {
+		t.Fatalf("expected 429 with Retry-After, got %d: %v", w.Code, w.Header())
+	}
+
+	// Neither a made-up API key on a route that does not check it nor a forwarded address
+	// from an untrusted peer gets a fresh bucket
+	for _, header := range []http.Header{
+		{utils.APIKeyHeader: {"spk_random"}},
+		{"X-Forwarded-For": {"10.0.0.9"}},
+	} {
+		if w := request("/share/abc", "10.0.0.1", header); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected status 429 with %v, got %d", header, w.Code)
+		}
+	}
+
+	// Other clients have their own budget, and so does an authenticated API key from the same IP
+	if w := request("/share/abc", "10.0.0.2", nil); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for another client, got %d", w.Code)
+	}
+	if w := request("/stock/export/csv", "10.0.0.1", http.Header{utils.APIKeyHeader: {"spk_reporting"}}); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for an API key, got %d", w.Code)
+	}
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestRateLimitLetsRequestsThroughWhenLimiterFails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/stock/export/csv", RateLimit(failingLimiter{}, zap.NewNop()), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stock/export/csv", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxRequestIDLength bounds the incoming X-Request-ID values that are kept
+const maxRequestIDLength = 128
+
+// RequestLogger logs every request once it has been handled: method, path, route, status,
+// latency, client IP, response size and user. 5xx responses are logged as errors, 4xx as
+// warnings, and successful health and readiness probes only at debug level.
+//
+// Each request gets an ID, kept from a valid incoming X-Request-ID (so a gateway's ID
+// carries over) or generated, which is echoed in the X-Request-ID response header and put
+// on the request context; utils.RequestLogger attaches it to the lines logged downstream.
+func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(utils.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(utils.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(utils.WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("request_id", id),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("route", c.FullPath()),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("size", c.Writer.Size()),
+		}
+		if user := utils.UserID(c); user != "" {
+			fields = append(fields, zap.String("user", user))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		level := zapcore.InfoLevel
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = zapcore.ErrorLevel
+		case status >= http.StatusBadRequest:
+			level = zapcore.WarnLevel
+		case c.Request.URL.Path == "/health" || c.Request.URL.Path == "/ready":
+			level = zapcore.DebugLevel
+		}
+		logger.Log(level, "Request", fields...)
+	}
+}
+
+// validRequestID reports whether id can be kept as the request ID: non-empty, not too long
+// and printable ASCII without spaces, so it cannot forge log lines or response headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128-bit request ID in hex
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
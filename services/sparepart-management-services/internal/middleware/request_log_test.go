@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	r := gin.New()
+	r.Use(RequestLogger(logger))
+	r.GET("/stock/:id", func(c *gin.Context) {
+		utils.HandleError(c, errors.New("database is down"), "Failed to get sparepart stock", logger)
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "generated", incoming: ""},
+		{name: "incoming kept", incoming: "gateway-42", keep: true},
+		{name: "invalid replaced", incoming: "bad id\r\nX-Injected: 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.TakeAll()
+
+			req := httptest.NewRequest(http.MethodGet, "/stock/7", nil)
+			if tt.incoming != "" {
+				req.Header.Set(utils.RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(utils.RequestIDHeader)
+			if id == "" || (tt.keep && id != tt.incoming) || (!tt.keep && id == tt.incoming) {
+				t.Fatalf("unexpected request ID %q for incoming %q", id, tt.incoming)
+			}
+
+			entries := logs.AllUntimed()
+			if len(entries) != 2 {
+				t.Fatalf("expected the handler's and the request's log lines, got %d", len(entries))
+			}
+			// The handler's error line carries the request ID too
+			for _, entry := range entries {
+				if entry.ContextMap()["request_id"] != id {
+					t.Fatalf("log line %q lacks request ID %q: %v", entry.Message, id, entry.ContextMap())
+				}
+			}
+			request := entries[1]
+			fields := request.ContextMap()
+			if request.Message != "Request" || request.Level != zapcore.ErrorLevel ||
+				fields["status"] != int64(http.StatusInternalServerError) || fields["route"] != "/stock/:id" || fields["path"] != "/stock/7" {
+				t.Fatalf("unexpected request log line: %s %v", request.Message, fields)
+			}
+		})
+	}
+}
+
+func TestRequestLoggerLevels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	r := gin.New()
+	r.Use(RequestLogger(zap.New(core)))
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/stock", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for path, want := range map[string]zapcore.Level{
+		"/health":  zapcore.DebugLevel,
+		"/stock":   zapcore.InfoLevel,
+		"/missing": zapcore.WarnLevel,
+	} {
+		logs.TakeAll()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		entries := logs.TakeAll()
+		if len(entries) != 1 || entries[0].Level != want {
+			t.Fatalf("%s: expected one %s line, got %v", path, want, entries)
+		}
+	}
+}
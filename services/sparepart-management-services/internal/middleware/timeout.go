@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout gives each request a time budget by cancelling its context after d, which
+// aborts any pgx query still running on it. Handlers turn the resulting error into a
+// 504 through utils.HandleError; if a handler returns without writing anything after
+// the deadline, the 504 is written here. A zero or negative d disables the timeout.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			utils.RequestTimeout(c)
+		}
+	}
+}
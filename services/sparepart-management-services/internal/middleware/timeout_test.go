@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "fast handler",
+			handler:    func(c *gin.Context) { utils.Success(c, "ok", nil) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "handler stops on cancellation without writing",
+			handler: func(c *gin.Context) {
+				<-c.Request.Context().Done()
+			},
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   utils.ErrCodeTimeout,
+		},
+		{
+			name: "query error from cancelled context",
+			handler: func(c *gin.Context) {
+				<-c.Request.Context().Done()
+				err := fmt.Errorf("timeout: %w", c.Request.Context().Err())
+				utils.HandleError(c, err, "Failed to get items", nil)
+			},
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   utils.ErrCodeTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/items", Timeout(20*time.Millisecond), tt.handler)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			var resp utils.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Fatalf("expected code %q, got %q", tt.wantCode, resp.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireUser rejects requests that were not authenticated as a user (see utils.UserID)
+// with a 401, so per-user handlers can rely on utils.UserID being set.
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if utils.UserID(c) == "" {
+			utils.Unauthorized(c, "Authentication required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sparepart-management-services/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	ok := func(c *gin.Context) { utils.Success(c, "ok", utils.UserID(c)) }
+	authenticated := func(c *gin.Context) { c.Set(utils.UserKey, "user-7") }
+	r.GET("/notifications", RequireUser(), ok)
+	r.GET("/authenticated/notifications", authenticated, RequireUser(), ok)
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+	}{
+		{"unauthenticated", "/notifications", "", http.StatusUnauthorized},
+		// A client can't name itself as the user
+		{"user header", "/notifications", "user-7", http.StatusUnauthorized},
+		{"authenticated", "/authenticated/notifications", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("X-User-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -4,10 +4,26 @@ package models
 type StockType string
 
 const (
-	StockTypeNew  StockType = "NEW_STOCK"
-	StockTypeUsed StockType = "USED_STOCK"
+	StockTypeNew      StockType = "NEW_STOCK"
+	StockTypeUsed     StockType = "USED_STOCK"
+	StockTypeDamaged  StockType = "DAMAGED"
+	StockTypeInRepair StockType = "IN_REPAIR"
+	StockTypeReserved StockType = "RESERVED"
 )
 
+// StockTypes lists every stock type; only NEW_STOCK and USED_STOCK count as available stock
+var StockTypes = []StockType{StockTypeNew, StockTypeUsed, StockTypeDamaged, StockTypeInRepair, StockTypeReserved}
+
+// Valid reports whether t is one of StockTypes
+func (t StockType) Valid() bool {
+	for _, stockType := range StockTypes {
+		if t == stockType {
+			return true
+		}
+	}
+	return false
+}
+
 type ItemType string
 
 const (
@@ -15,6 +31,28 @@ const (
 	ItemTypeToolsAlker ItemType = "TOOLS_ALKER"
 )
 
+// Unit is the unit of measure of a master item; every quantity of the item is in this unit
+type Unit string
+
+const (
+	UnitPiece Unit = "PCS"
+	UnitMeter Unit = "METER"
+	UnitSet   Unit = "SET"
+)
+
+// Units lists every unit of measure
+var Units = []Unit{UnitPiece, UnitMeter, UnitSet}
+
+// Valid reports whether u is one of Units
+func (u Unit) Valid() bool {
+	for _, unit := range Units {
+		if u == unit {
+			return true
+		}
+	}
+	return false
+}
+
 type Region string
 
 const (
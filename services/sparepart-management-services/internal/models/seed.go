@@ -2,15 +2,47 @@ package models
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sparepart-management-services/internal/database"
 	sqlcdb "sparepart-management-services/internal/database/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// Seed runs database seeders
-func Seed(ctx context.Context) error {
-	queries := sqlcdb.New(database.GetDB())
+// SeedCount counts what seeding did to one entity
+type SeedCount struct {
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
+
+// SeedSummary reports the outcome of Seed per entity
+type SeedSummary struct {
+	Locations      SeedCount `json:"locations"`
+	ContactPersons SeedCount `json:"contact_persons"`
+	Spareparts     SeedCount `json:"spareparts"`
+}
+
+// Seed runs database seeders in a single transaction. Every insert is idempotent
+// (ON CONFLICT / NOT EXISTS) and concurrent runs are serialized by an advisory lock.
+func Seed(ctx context.Context, pool *pgxpool.Pool) (SeedSummary, error) {
+	var summary SeedSummary
+	err := database.WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		queries := sqlcdb.New(tx)
+		if err := queries.AcquireSeedLock(ctx); err != nil {
+			return fmt.Errorf("failed to acquire seed lock: %w", err)
+		}
+		return seed(ctx, queries, &summary)
+	})
+	if err != nil {
+		return SeedSummary{}, err
+	}
+	return summary, nil
+}
 
+func seed(ctx context.Context, queries *sqlcdb.Queries, summary *SeedSummary) error {
 	// Seed Locations
 	locationData := []struct {
 		Region  sqlcdb.RegionType
@@ -34,68 +66,34 @@ func Seed(ctx context.Context) error {
 		{sqlcdb.RegionTypePAPUA, "Jayapura", "Merauke/Wamena"},
 	}
 
-	// Create locations and get their IDs
+	// Create locations and remember their IDs
 	locationMap := make(map[string]int32) // key: "region:regency:cluster"
-	
-	// Get all existing locations first
-	allLocs, err := queries.ListLocations(ctx, sqlcdb.ListLocationsParams{
-		Column1: "",
-		Column2: "",
-		Column3: "",
-		Limit:   1000,
-		Offset:  0,
-	})
-	if err != nil {
-		return err
-	}
-
-	// Build map of existing locations
-	existingLocMap := make(map[string]int32)
-	for _, loc := range allLocs {
-		key := string(loc.Region) + ":" + loc.Regency + ":" + loc.Cluster
-		existingLocMap[key] = loc.ID
-	}
-
-	// Create or use existing locations
 	for _, loc := range locationData {
 		key := string(loc.Region) + ":" + loc.Regency + ":" + loc.Cluster
-		
-		if existingID, exists := existingLocMap[key]; exists {
-			locationMap[key] = existingID
-		} else {
-			// Create new location
-			createParams := sqlcdb.CreateLocationParams{
+
+		id, err := queries.SeedLocation(ctx, sqlcdb.SeedLocationParams{
+			Region:  loc.Region,
+			Regency: loc.Regency,
+			Cluster: loc.Cluster,
+		})
+		switch {
+		case err == nil:
+			summary.Locations.Inserted++
+		case errors.Is(err, pgx.ErrNoRows):
+			// Already exists, look up its ID
+			id, err = queries.GetLocationIDByKey(ctx, sqlcdb.GetLocationIDByKeyParams{
 				Region:  loc.Region,
 				Regency: loc.Regency,
 				Cluster: loc.Cluster,
-			}
-			created, err := queries.CreateLocation(ctx, createParams)
+			})
 			if err != nil {
-				// If unique constraint error, location might have been created concurrently
-				// Try to find it again
-				allLocs, listErr := queries.ListLocations(ctx, sqlcdb.ListLocationsParams{
-					Column1: "",
-					Column2: "",
-					Column3: "",
-					Limit:   1000,
-					Offset:  0,
-				})
-				if listErr != nil {
-					return err
-				}
-				for _, l := range allLocs {
-					if l.Region == loc.Region && l.Regency == loc.Regency && l.Cluster == loc.Cluster {
-						locationMap[key] = l.ID
-						break
-					}
-				}
-				if locationMap[key] == 0 {
-					return err
-				}
-			} else {
-				locationMap[key] = created.ID
+				return fmt.Errorf("failed to find location %s: %w", key, err)
 			}
+			summary.Locations.Skipped++
+		default:
+			return fmt.Errorf("failed to seed location %s: %w", key, err)
 		}
+		locationMap[key] = id
 	}
 
 	// Seed Contact Persons
@@ -120,43 +118,24 @@ func Seed(ctx context.Context) error {
 		{"Soni", "0821-1446-0180", "MALUKU:Halmahera Barat:Haltim"},
 	}
 
-	// Get all existing contact persons
-	allContacts, err := queries.ListContactPersons(ctx, sqlcdb.ListContactPersonsParams{
-		Column1: 0, // NULL means all
-		Limit:   1000,
-		Offset:  0,
-	})
-	if err != nil {
-		return err
-	}
-
-	// Build map of existing contact persons
-	existingCPMap := make(map[string]bool) // key: "location_id:pic:phone"
-	for _, cp := range allContacts {
-		key := fmt.Sprintf("%d:%s:%s", cp.LocationID, cp.Pic, cp.Phone)
-		existingCPMap[key] = true
-	}
-
 	for _, cp := range contactPersons {
 		locationID, exists := locationMap[cp.Location]
 		if !exists {
-			continue // Skip if location not found
+			return fmt.Errorf("contact person %s references unknown location %s", cp.PIC, cp.Location)
 		}
 
-		key := fmt.Sprintf("%d:%s:%s", locationID, cp.PIC, cp.Phone)
-		if !existingCPMap[key] {
-			// Contact person doesn't exist, create it
-			createParams := sqlcdb.CreateContactPersonParams{
-				LocationID: locationID,
-				Pic:        cp.PIC,
-				Phone:      cp.Phone,
-			}
-			_, err := queries.CreateContactPerson(ctx, createParams)
-			if err != nil {
-				// Ignore unique constraint errors (might have been created concurrently)
-				// For simplicity, we'll continue on any error
-				continue
-			}
+		inserted, err := queries.SeedContactPerson(ctx, sqlcdb.SeedContactPersonParams{
+			LocationID: locationID,
+			Pic:        cp.PIC,
+			Phone:      cp.Phone,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed contact person %s: %w", cp.PIC, err)
+		}
+		if inserted > 0 {
+			summary.ContactPersons.Inserted++
+		} else {
+			summary.ContactPersons.Skipped++
 		}
 	}
 
@@ -177,36 +156,6 @@ func Seed(ctx context.Context) error {
 		{"Baterai JSPro", sqlcdb.ItemTypeSPAREPART},
 	}
 
-	// Get all existing spareparts
-	allSpareparts, err := queries.ListSparepartMasters(ctx, sqlcdb.ListSparepartMastersParams{
-		Column1: "",
-		Column2: "",
-		Limit:   1000,
-		Offset:  0,
-	})
-	if err != nil {
-		return err
-	}
-
-	existingSPMap := make(map[string]bool)
-	for _, sp := range allSpareparts {
-		existingSPMap[sp.Name] = true
-	}
-
-	for _, sp := range spareparts {
-		if !existingSPMap[sp.Name] {
-			createParams := sqlcdb.CreateSparepartMasterParams{
-				Name:     sp.Name,
-				ItemType: sp.ItemType,
-			}
-			_, err := queries.CreateSparepartMaster(ctx, createParams)
-			if err != nil {
-				// Ignore unique constraint errors
-				continue
-			}
-		}
-	}
-
 	// Seed Tools Alker
 	toolsAlker := []struct {
 		Name     string
@@ -217,17 +166,21 @@ func Seed(ctx context.Context) error {
 		{"Can Box Battery", sqlcdb.ItemTypeTOOLSALKER},
 	}
 
-	for _, tool := range toolsAlker {
-		if !existingSPMap[tool.Name] {
-			createParams := sqlcdb.CreateSparepartMasterParams{
-				Name:     tool.Name,
-				ItemType: tool.ItemType,
-			}
-			_, err := queries.CreateSparepartMaster(ctx, createParams)
-			if err != nil {
-				// Ignore unique constraint errors
-				continue
-			}
+	for _, sp := range append(spareparts, toolsAlker...) {
+		inserted, err := queries.SeedSparepartMaster(ctx, sqlcdb.SeedSparepartMasterParams{
+			Name:     sp.Name,
+			ItemType: sp.ItemType,
+		})
+		switch {
+		case err == nil && inserted:
+			summary.Spareparts.Inserted++
+		case err == nil:
+			summary.Spareparts.Updated++
+		case errors.Is(err, pgx.ErrNoRows):
+			// Unchanged
+			summary.Spareparts.Skipped++
+		default:
+			return fmt.Errorf("failed to seed sparepart %s: %w", sp.Name, err)
 		}
 	}
 
@@ -0,0 +1,115 @@
+// Package ratelimit limits how often one client may call an endpoint with token buckets:
+// a bucket holds up to limit tokens, refills at limit tokens per period and every request
+// takes one. Buckets are kept in memory per process, or in Redis when the replicas should
+// share them.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed bool
+	// Limit is the bucket size and Remaining the whole tokens left in it
+	Limit     int
+	Remaining int
+	// RetryAfter is the wait until the next token when the request was not allowed
+	RetryAfter time.Duration
+	// Reset is the wait until the bucket is full again
+	Reset time.Duration
+}
+
+// Limiter takes a token from the bucket of key
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// New returns a limiter of limit requests per period under name, backed by Redis when a
+// client is given and by memory otherwise; a zero or negative limit returns nil, which
+// disables limiting
+func New(client *redis.Client, name string, limit int, period time.Duration) Limiter {
+	if limit <= 0 {
+		return nil
+	}
+	if client != nil {
+		return NewRedis(client, name, limit, period)
+	}
+	return NewMemory(limit, period)
+}
+
+// bucket is the state of one key: the tokens left at the last request
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Memory keeps the buckets in this process; buckets that have filled up again are dropped
+// once per period
+type Memory struct {
+	limit  int
+	period time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]bucket
+	lastSweep time.Time
+}
+
+func NewMemory(limit int, period time.Duration) *Memory {
+	return &Memory{
+		limit:   limit,
+		period:  period,
+		buckets: make(map[string]bucket),
+	}
+}
+
+func (m *Memory) Allow(ctx context.Context, key string) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.lastSweep) >= m.period {
+		for k, b := range m.buckets {
+			if now.Sub(b.last) >= m.period {
+				delete(m.buckets, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = bucket{tokens: float64(m.limit), last: now}
+	}
+	// Refill for the time since the last request
+	b.tokens = math.Min(float64(m.limit), b.tokens+now.Sub(b.last).Seconds()*m.rate())
+	b.last = now
+
+	result := Result{Limit: m.limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = m.wait(1 - b.tokens)
+	}
+	m.buckets[key] = b
+
+	result.Remaining = int(b.tokens)
+	result.Reset = m.wait(float64(m.limit) - b.tokens)
+	return result, nil
+}
+
+// rate is the refill in tokens per second
+func (m *Memory) rate() float64 {
+	return float64(m.limit) / m.period.Seconds()
+}
+
+// wait is the time it takes to refill the given tokens
+func (m *Memory) wait(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / m.rate() * float64(time.Second)))
+}
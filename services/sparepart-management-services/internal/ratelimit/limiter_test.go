@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestMemoryRefills(t *testing.T) {
+	limiter := NewMemory(2, 100*time.Millisecond)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if result, _ := limiter.Allow(ctx, "ip:10.0.0.1"); !result.Allowed || result.Remaining != 1-i {
+			t.Fatalf("request %d: unexpected result %+v", i+1, result)
+		}
+	}
+	result, _ := limiter.Allow(ctx, "ip:10.0.0.1")
+	if result.Allowed || result.RetryAfter <= 0 || result.RetryAfter > 50*time.Millisecond {
+		t.Fatalf("expected an empty bucket, got %+v", result)
+	}
+
+	// One token comes back every 50ms
+	time.Sleep(result.RetryAfter)
+	if result, _ := limiter.Allow(ctx, "ip:10.0.0.1"); !result.Allowed {
+		t.Fatalf("expected a refilled token, got %+v", result)
+	}
+}
+
+func TestNewDisablesNonPositiveLimit(t *testing.T) {
+	if New(nil, "export", 0, time.Minute) != nil {
+		t.Fatal("expected no limiter for a zero limit")
+	}
+	if _, ok := New(nil, "export", 5, time.Minute).(*Memory); !ok {
+		t.Fatal("expected a memory limiter without Redis")
+	}
+}
+
+func TestRedisSharesBucketsBetweenReplicas(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	var replicas []*Redis
+	for range 2 {
+		client, err := NewRedisClient("redis://"+server.Addr(), time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		replicas = append(replicas, NewRedis(client, "export", 2, time.Minute))
+	}
+
+	for i, want := range []bool{true, true, false} {
+		result, err := replicas[i%2].Allow(ctx, "ip:10.0.0.1")
+		if err != nil {
+			t.Fatalf("allow failed: %v", err)
+		}
+		if result.Allowed != want || result.Limit != 2 {
+			t.Fatalf("request %d: unexpected result %+v", i, result)
+		}
+		if !want && (result.RetryAfter <= 0 || result.RetryAfter > 30*time.Second || result.Reset <= 0) {
+			t.Fatalf("unexpected waits %+v", result)
+		}
+	}
+	if !server.Exists("ratelimit:export:ip:10.0.0.1") {
+		t.Fatal("expected the bucket under its ratelimit key")
+	}
+
+	// Other keys have buckets of their own
+	if result, err := replicas[0].Allow(ctx, "ip:10.0.0.2"); err != nil || !result.Allowed {
+		t.Fatalf("expected another client to be allowed, got %+v, %v", result, err)
+	}
+}
+
+func TestRedisReportsUnreachableServer(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := NewRedisClient("redis://"+server.Addr(), 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	server.Close()
+
+	if _, err := NewRedis(client, "export", 2, time.Minute).Allow(context.Background(), "ip:10.0.0.1"); err == nil {
+		t.Fatal("expected an error without Redis")
+	}
+}
+
+func TestNewRedisClientRejectsInvalidURL(t *testing.T) {
+	for _, rawURL := range []string{"localhost:6379", "http://localhost", "redis://localhost/x"} {
+		if _, err := NewRedisClient(rawURL, time.Second); err == nil {
+			t.Errorf("expected %q to be rejected", rawURL)
+		}
+	}
+}
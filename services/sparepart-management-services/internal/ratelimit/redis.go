@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucket takes a token from the bucket in KEYS[1] with the same arithmetic as
+// Memory, using the Redis clock so replicas agree. ARGV: limit, period in milliseconds.
+// Returns allowed (0/1), remaining tokens, retry after and reset in milliseconds.
+var tokenBucket = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(state[1]) or limit
+local last = tonumber(state[2]) or now
+tokens = math.min(limit, tokens + math.max(now - last, 0) * limit / period)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * period / limit)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', now)
+redis.call('PEXPIRE', KEYS[1], period)
+return {allowed, math.floor(tokens), retry, math.ceil((limit - tokens) * period / limit)}
+`)
+
+// Redis keeps the buckets in Redis under "ratelimit:<name>:<key>", so every replica draws
+// from the same bucket
+type Redis struct {
+	client redis.Scripter
+	name   string
+	limit  int
+	period time.Duration
+}
+
+func NewRedis(client redis.Scripter, name string, limit int, period time.Duration) *Redis {
+	return &Redis{client: client, name: name, limit: limit, period: period}
+}
+
+func (r *Redis) Allow(ctx context.Context, key string) (Result, error) {
+	numbers, err := tokenBucket.Run(ctx, r.client, []string{"ratelimit:" + r.name + ":" + key},
+		r.limit, r.period.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(numbers) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit reply %v", numbers)
+	}
+	return Result{
+		Allowed:    numbers[0] == 1,
+		Limit:      r.limit,
+		Remaining:  int(numbers[1]),
+		RetryAfter: time.Duration(numbers[2]) * time.Millisecond,
+		Reset:      time.Duration(numbers[3]) * time.Millisecond,
+	}, nil
+}
+
+// NewRedisClient parses a redis:// or rediss:// (TLS) URL,
+// [user:password@]host[:port][/db], into a pooled client; timeout bounds dialing and every
+// command
+func NewRedisClient(rawURL string, timeout time.Duration) (*redis.Client, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	opts.DialTimeout = timeout
+	opts.ReadTimeout = timeout
+	opts.WriteTimeout = timeout
+	return redis.NewClient(opts), nil
+}
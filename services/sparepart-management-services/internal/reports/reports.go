@@ -0,0 +1,231 @@
+// Package reports keeps generated exports in the storage backend for a while, signs the links
+// they are downloaded through and sends those links to the notification center of whoever
+// requested them; the store is built once in main and passed to the handlers.
+package reports
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"sparepart-management-services/internal/config"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/storage"
+
+	"go.uber.org/zap"
+)
+
+// NotificationCategory is the category of the notifications carrying a report link
+const NotificationCategory = "REPORT"
+
+var (
+	ErrInvalidLink = errors.New("invalid report link")
+	ErrExpiredLink = errors.New("report link has expired")
+)
+
+// contentTypes are the content types reports are stored with, by file extension
+var contentTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".csv":  "text/csv; charset=utf-8",
+}
+
+// Store keeps reports in a storage backend and signs links to them with an HMAC key
+type Store struct {
+	storage storage.Storage
+	linkTTL time.Duration
+	// linkPrefix is the URL path a signed token is appended to
+	linkPrefix string
+	secret     []byte
+	// notifications receives the links of stored reports; nil sends none
+	notifications repository.NotificationRepository
+}
+
+// New returns the store of cfg keeping reports in backend, whose links are served under
+// apiPrefix. Links have to verify on every replica and after restarts, so a secret is required.
+func New(cfg config.ReportConfig, backend storage.Storage, apiPrefix string) (*Store, error) {
+	if cfg.LinkSecret == "" {
+		return nil, errors.New("REPORT_LINK_SECRET is required")
+	}
+	return &Store{
+		storage:    backend,
+		linkTTL:    cfg.LinkTTL,
+		linkPrefix: apiPrefix + "/sparepart/reports/",
+		secret:     []byte(cfg.LinkSecret),
+	}, nil
+}
+
+// SetNotifications sends the links of stored reports to the notification center through queries
+func (s *Store) SetNotifications(queries repository.NotificationRepository) {
+	s.notifications = queries
+}
+
+// Save stores a generated report and returns its stored name, which starts with the time it
+// was stored so expired reports are found without reading them
+func (s *Store) Save(ctx context.Context, data []byte, filename string, logger *zap.Logger) (string, error) {
+	// Random part keeps stored names unique and unguessable
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate report name: %w", err)
+	}
+	storedName := strconv.FormatInt(time.Now().Unix(), 10) + "-" + hex.EncodeToString(suffix) + "_" + path.Base(filename)
+
+	contentType, ok := contentTypes[strings.ToLower(path.Ext(filename))]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+	if err := s.storage.Put(ctx, storedName, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return "", fmt.Errorf("failed to save report: %w", err)
+	}
+
+	if logger != nil {
+		logger.Info("Report stored", zap.String("name", storedName), zap.Int("size", len(data)))
+	}
+
+	return storedName, nil
+}
+
+// Link returns the URL granting access to a stored report for the link TTL from createdAt,
+// and when it expires
+func (s *Store) Link(storedName string, createdAt time.Time) (string, time.Time) {
+	expiresAt := createdAt.Add(s.linkTTL)
+	return s.linkPrefix + s.sign(storedName, expiresAt), expiresAt
+}
+
+// Notify sends the link of a stored report to the notification center of userID; users that
+// turned the IN_APP channel off are skipped
+func (s *Store) Notify(ctx context.Context, userID string, filename string, url string, expiresAt time.Time) error {
+	if s.notifications == nil || userID == "" {
+		return nil
+	}
+
+	expires := expiresAt.UTC().Format(time.RFC3339)
+	data, err := json.Marshal(map[string]string{"filename": filename, "url": url, "expires_at": expires})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	_, err = s.notifications.CreateNotifications(ctx, sqlcdb.CreateNotificationsParams{
+		Category: NotificationCategory,
+		Title:    "Report ready: " + filename,
+		Body:     fmt.Sprintf("Download %s until %s", url, expires),
+		Data:     data,
+		UserIds:  []string{userID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store report notification: %w", err)
+	}
+	return nil
+}
+
+// sign creates a token granting access to a stored report until expiresAt
+func (s *Store) sign(storedName string, expiresAt time.Time) string {
+	payload := storedName + "|" + strconv.FormatInt(expiresAt.Unix(), 10)
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify validates a report token and returns the stored name of its report
+func (s *Store) Verify(token string) (string, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", ErrInvalidLink
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidLink
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalidLink
+	}
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", ErrInvalidLink
+	}
+
+	fields := strings.SplitN(string(payload), "|", 2)
+	if len(fields) != 2 {
+		return "", ErrInvalidLink
+	}
+	expiresAt, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return "", ErrInvalidLink
+	}
+	if time.Now().Unix() > expiresAt {
+		return "", ErrExpiredLink
+	}
+
+	return path.Base(fields[0]), nil
+}
+
+// Open opens a stored report; the caller closes it. A removed report is storage.ErrNotExist.
+func (s *Store) Open(ctx context.Context, storedName string) (io.ReadCloser, storage.FileInfo, error) {
+	return s.storage.Get(ctx, storedName)
+}
+
+// DownloadName strips the prefix added by Save
+func DownloadName(storedName string) string {
+	name := path.Base(storedName)
+	if idx := strings.Index(name, "_"); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+// CleanupExpired removes stored reports older than the link TTL
+func (s *Store) CleanupExpired(ctx context.Context, logger *zap.Logger) {
+	cutoff := time.Now().Add(-s.linkTTL)
+	var expired []string
+	err := s.storage.Walk(ctx, func(key string, size int64) error {
+		if storedAt, ok := s.storedAt(ctx, key); ok && storedAt.Before(cutoff) {
+			expired = append(expired, key)
+		}
+		return nil
+	})
+	if err != nil && logger != nil {
+		logger.Warn("Failed to list stored reports", zap.Error(err))
+	}
+
+	for _, key := range expired {
+		if err := s.storage.Delete(ctx, key); err != nil && logger != nil {
+			logger.Warn("Failed to remove expired report", zap.Error(err), zap.String("name", key))
+		}
+	}
+}
+
+// storedAt is when the report under key was stored: from its name, or for reports stored
+// before the time was part of the name, from the file itself
+func (s *Store) storedAt(ctx context.Context, key string) (time.Time, bool) {
+	if prefix, _, ok := strings.Cut(path.Base(key), "-"); ok {
+		if unix, err := strconv.ParseInt(prefix, 10, 64); err == nil {
+			return time.Unix(unix, 0), true
+		}
+	}
+
+	body, info, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return time.Time{}, false
+	}
+	body.Close()
+	return info.ModTime, true
+}
@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxCacheEntries bounds each lookup cache; a full cache is simply cleared
+const maxCacheEntries = 1024
+
+type cacheKey struct {
+	query string
+	arg   any
+}
+
+type cacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// lookupCache is a small in-memory TTL cache. A zero TTL disables caching.
+type lookupCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[cacheKey]cacheEntry
+}
+
+func newLookupCache(ttl time.Duration) *lookupCache {
+	return &lookupCache{
+		ttl:     ttl,
+		entries: make(map[cacheKey]cacheEntry),
+	}
+}
+
+func (c *lookupCache) get(key cacheKey) (any, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *lookupCache) set(key cacheKey, value any) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCacheEntries {
+		c.entries = make(map[cacheKey]cacheEntry)
+	}
+	c.entries[key] = cacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// clear drops every entry, used whenever the underlying table is written
+func (c *lookupCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]cacheEntry)
+}
+
+// readThrough returns the cached value for key or loads and caches it. Errors are never cached.
+func readThrough[T any](c *lookupCache, query string, arg any, load func() (T, error)) (T, error) {
+	key := cacheKey{query: query, arg: arg}
+	if value, ok := c.get(key); ok {
+		return value.(T), nil
+	}
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.set(key, value)
+	return value, nil
+}
+
+// CachedStore is a Store with read-through caching of location, sparepart master and
+// contact person lookups, including the contact persons the grouped stock and tools alker
+// listings load for every page. Its write methods, and every transaction run through it,
+// invalidate the affected caches, so handlers writing through it never serve stale data
+// from this process; other replicas see a change once their entries expire.
+// Dashboard KPIs and summaries are cached separately and only expire by TTL, since every stock write would invalidate them.
+// The filter dropdown values share that cache for the same reason.
+type CachedStore struct {
+	*Store
+	locations      *lookupCache
+	masters        *lookupCache
+	contactPersons *lookupCache
+	dashboard      *lookupCache
+}
+
+func NewCachedStore(store *Store, ttl, dashboardTTL time.Duration) *CachedStore {
+	return &CachedStore{
+		Store:          store,
+		locations:      newLookupCache(ttl),
+		masters:        newLookupCache(ttl),
+		contactPersons: newLookupCache(ttl),
+		dashboard:      newLookupCache(dashboardTTL),
+	}
+}
+
+func (s *CachedStore) GetLocation(ctx context.Context, id int32) (sqlcdb.Location, error) {
+	return readThrough(s.locations, "GetLocation", id, func() (sqlcdb.Location, error) {
+		return s.Store.GetLocation(ctx, id)
+	})
+}
+
+func (s *CachedStore) ListLocations(ctx context.Context, arg sqlcdb.ListLocationsParams) ([]sqlcdb.Location, error) {
+	return readThrough(s.locations, "ListLocations", arg, func() ([]sqlcdb.Location, error) {
+		return s.Store.ListLocations(ctx, arg)
+	})
+}
+
+func (s *CachedStore) CountLocations(ctx context.Context, arg sqlcdb.CountLocationsParams) (int64, error) {
+	return readThrough(s.locations, "CountLocations", arg, func() (int64, error) {
+		return s.Store.CountLocations(ctx, arg)
+	})
+}
+
+// invalidateLocations also drops contact persons, whose rows embed location fields
+func (s *CachedStore) invalidateLocations() {
+	s.locations.clear()
+	s.contactPersons.clear()
+}
+
+func (s *CachedStore) CreateLocation(ctx context.Context, arg sqlcdb.CreateLocationParams) (sqlcdb.Location, error) {
+	defer s.invalidateLocations()
+	return s.Store.CreateLocation(ctx, arg)
+}
+
+func (s *CachedStore) UpdateLocation(ctx context.Context, arg sqlcdb.UpdateLocationParams) (sqlcdb.Location, error) {
+	defer s.invalidateLocations()
+	return s.Store.UpdateLocation(ctx, arg)
+}
+
+func (s *CachedStore) PatchLocation(ctx context.Context, arg sqlcdb.PatchLocationParams) (sqlcdb.Location, error) {
+	defer s.invalidateLocations()
+	return s.Store.PatchLocation(ctx, arg)
+}
+
+func (s *CachedStore) DeleteLocation(ctx context.Context, id int32) error {
+	defer s.invalidateLocations()
+	return s.Store.DeleteLocation(ctx, id)
+}
+
+func (s *CachedStore) RestoreLocation(ctx context.Context, id int32) (sqlcdb.Location, error) {
+	defer s.invalidateLocations()
+	return s.Store.RestoreLocation(ctx, id)
+}
+
+func (s *CachedStore) SetLocationActive(ctx context.Context, arg sqlcdb.SetLocationActiveParams) (sqlcdb.Location, error) {
+	defer s.invalidateLocations()
+	return s.Store.SetLocationActive(ctx, arg)
+}
+
+// Regency and cluster renames cascade to the location rows
+func (s *CachedStore) UpdateRegency(ctx context.Context, arg sqlcdb.UpdateRegencyParams) (sqlcdb.Regency, error) {
+	defer s.invalidateLocations()
+	return s.Store.UpdateRegency(ctx, arg)
+}
+
+func (s *CachedStore) UpdateCluster(ctx context.Context, arg sqlcdb.UpdateClusterParams) (sqlcdb.UpdateClusterRow, error) {
+	defer s.invalidateLocations()
+	return s.Store.UpdateCluster(ctx, arg)
+}
+
+func (s *CachedStore) GetSparepartMaster(ctx context.Context, id int32) (sqlcdb.ListSparepart, error) {
+	return readThrough(s.masters, "GetSparepartMaster", id, func() (sqlcdb.ListSparepart, error) {
+		return s.Store.GetSparepartMaster(ctx, id)
+	})
+}
+
+func (s *CachedStore) ListSparepartMasters(ctx context.Context, arg sqlcdb.ListSparepartMastersParams) ([]sqlcdb.ListSparepart, error) {
+	return readThrough(s.masters, "ListSparepartMasters", arg, func() ([]sqlcdb.ListSparepart, error) {
+		return s.Store.ListSparepartMasters(ctx, arg)
+	})
+}
+
+func (s *CachedStore) CountSparepartMasters(ctx context.Context, arg sqlcdb.CountSparepartMastersParams) (int64, error) {
+	return readThrough(s.masters, "CountSparepartMasters", arg, func() (int64, error) {
+		return s.Store.CountSparepartMasters(ctx, arg)
+	})
+}
+
+func (s *CachedStore) CreateSparepartMaster(ctx context.Context, arg sqlcdb.CreateSparepartMasterParams) (sqlcdb.ListSparepart, error) {
+	defer s.masters.clear()
+	return s.Store.CreateSparepartMaster(ctx, arg)
+}
+
+func (s *CachedStore) UpdateSparepartMaster(ctx context.Context, arg sqlcdb.UpdateSparepartMasterParams) (sqlcdb.ListSparepart, error) {
+	defer s.masters.clear()
+	return s.Store.UpdateSparepartMaster(ctx, arg)
+}
+
+func (s *CachedStore) PatchSparepartMaster(ctx context.Context, arg sqlcdb.PatchSparepartMasterParams) (sqlcdb.ListSparepart, error) {
+	defer s.masters.clear()
+	return s.Store.PatchSparepartMaster(ctx, arg)
+}
+
+func (s *CachedStore) DeleteSparepartMaster(ctx context.Context, id int32) error {
+	defer s.masters.clear()
+	return s.Store.DeleteSparepartMaster(ctx, id)
+}
+
+func (s *CachedStore) RestoreSparepartMaster(ctx context.Context, id int32) (sqlcdb.ListSparepart, error) {
+	defer s.masters.clear()
+	return s.Store.RestoreSparepartMaster(ctx, id)
+}
+
+func (s *CachedStore) GetContactPerson(ctx context.Context, id int32) (sqlcdb.GetContactPersonRow, error) {
+	return readThrough(s.contactPersons, "GetContactPerson", id, func() (sqlcdb.GetContactPersonRow, error) {
+		return s.Store.GetContactPerson(ctx, id)
+	})
+}
+
+func (s *CachedStore) ListContactPersons(ctx context.Context, arg sqlcdb.ListContactPersonsParams) ([]sqlcdb.ListContactPersonsRow, error) {
+	return readThrough(s.contactPersons, "ListContactPersons", arg, func() ([]sqlcdb.ListContactPersonsRow, error) {
+		return s.Store.ListContactPersons(ctx, arg)
+	})
+}
+
+func (s *CachedStore) CountContactPersons(ctx context.Context, locationID pgtype.Int4) (int64, error) {
+	return readThrough(s.contactPersons, "CountContactPersons", locationID, func() (int64, error) {
+		return s.Store.CountContactPersons(ctx, locationID)
+	})
+}
+
+// ListContactPersonsByLocations backs the grouped listings; the IDs are a slice, so the key
+// is their string form
+func (s *CachedStore) ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]sqlcdb.ContactPerson, error) {
+	return readThrough(s.contactPersons, "ListContactPersonsByLocations", fmt.Sprint(locationIds), func() ([]sqlcdb.ContactPerson, error) {
+		return s.Store.ListContactPersonsByLocations(ctx, locationIds)
+	})
+}
+
+func (s *CachedStore) CreateContactPerson(ctx context.Context, arg sqlcdb.CreateContactPersonParams) (sqlcdb.ContactPerson, error) {
+	defer s.contactPersons.clear()
+	return s.Store.CreateContactPerson(ctx, arg)
+}
+
+func (s *CachedStore) UpdateContactPerson(ctx context.Context, arg sqlcdb.UpdateContactPersonParams) (sqlcdb.ContactPerson, error) {
+	defer s.contactPersons.clear()
+	return s.Store.UpdateContactPerson(ctx, arg)
+}
+
+func (s *CachedStore) PatchContactPerson(ctx context.Context, arg sqlcdb.PatchContactPersonParams) (sqlcdb.ContactPerson, error) {
+	defer s.contactPersons.clear()
+	return s.Store.PatchContactPerson(ctx, arg)
+}
+
+func (s *CachedStore) DeleteContactPerson(ctx context.Context, id int32) error {
+	defer s.contactPersons.clear()
+	return s.Store.DeleteContactPerson(ctx, id)
+}
+
+func (s *CachedStore) RestoreContactPerson(ctx context.Context, id int32) (sqlcdb.ContactPerson, error) {
+	defer s.contactPersons.clear()
+	return s.Store.RestoreContactPerson(ctx, id)
+}
+
+// The repository a transaction hands to fn writes past the cache (e.g. the purge of
+// soft-deleted locations, masters and contact persons), so the lookups are dropped once it
+// is done
+func (s *CachedStore) WithinTransaction(ctx context.Context, fn func(repo SparepartStockRepository) error) error {
+	defer s.invalidateLookups()
+	return s.Store.WithinTransaction(ctx, fn)
+}
+
+func (s *CachedStore) WithinToolsAlkerTransaction(ctx context.Context, fn func(repo ToolsAlkerRepository) error) error {
+	defer s.invalidateLookups()
+	return s.Store.WithinToolsAlkerTransaction(ctx, fn)
+}
+
+func (s *CachedStore) WithinSparepartMasterTransaction(ctx context.Context, fn func(repo SparepartMasterRepository) error) error {
+	defer s.masters.clear()
+	return s.Store.WithinSparepartMasterTransaction(ctx, fn)
+}
+
+func (s *CachedStore) invalidateLookups() {
+	s.invalidateLocations()
+	s.masters.clear()
+}
+
+func (s *CachedStore) GetDashboardKPIs(ctx context.Context) (sqlcdb.GetDashboardKPIsRow, error) {
+	return readThrough(s.dashboard, "GetDashboardKPIs", nil, func() (sqlcdb.GetDashboardKPIsRow, error) {
+		return s.Store.GetDashboardKPIs(ctx)
+	})
+}
+
+func (s *CachedStore) ListStockQuantityByType(ctx context.Context) ([]sqlcdb.ListStockQuantityByTypeRow, error) {
+	return readThrough(s.dashboard, "ListStockQuantityByType", nil, func() ([]sqlcdb.ListStockQuantityByTypeRow, error) {
+		return s.Store.ListStockQuantityByType(ctx)
+	})
+}
+
+func (s *CachedStore) GetStockSummaryTotals(ctx context.Context) (sqlcdb.GetStockSummaryTotalsRow, error) {
+	return readThrough(s.dashboard, "GetStockSummaryTotals", nil, func() (sqlcdb.GetStockSummaryTotalsRow, error) {
+		return s.Store.GetStockSummaryTotals(ctx)
+	})
+}
+
+func (s *CachedStore) ListStockTotalsByArea(ctx context.Context) ([]sqlcdb.ListStockTotalsByAreaRow, error) {
+	return readThrough(s.dashboard, "ListStockTotalsByArea", nil, func() ([]sqlcdb.ListStockTotalsByAreaRow, error) {
+		return s.Store.ListStockTotalsByArea(ctx)
+	})
+}
+
+func (s *CachedStore) ListStockTotalsByItemType(ctx context.Context) ([]sqlcdb.ListStockTotalsByItemTypeRow, error) {
+	return readThrough(s.dashboard, "ListStockTotalsByItemType", nil, func() ([]sqlcdb.ListStockTotalsByItemTypeRow, error) {
+		return s.Store.ListStockTotalsByItemType(ctx)
+	})
+}
+
+func (s *CachedStore) ListLowestQuantityStockItems(ctx context.Context, limit int32) ([]sqlcdb.ListLowestQuantityStockItemsRow, error) {
+	return readThrough(s.dashboard, "ListLowestQuantityStockItems", limit, func() ([]sqlcdb.ListLowestQuantityStockItemsRow, error) {
+		return s.Store.ListLowestQuantityStockItems(ctx, limit)
+	})
+}
+
+func (s *CachedStore) ListFilterValues(ctx context.Context, region pgtype.Text) ([]sqlcdb.ListFilterValuesRow, error) {
+	return readThrough(s.dashboard, "ListFilterValues", region, func() ([]sqlcdb.ListFilterValuesRow, error) {
+		return s.Store.ListFilterValues(ctx, region)
+	})
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReadThroughCachesValues(t *testing.T) {
+	cache := newLookupCache(time.Minute)
+	loads := 0
+	load := func() (string, error) {
+		loads++
+		return "location", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := readThrough(cache, "GetLocation", int32(1), load)
+		if err != nil || value != "location" {
+			t.Fatalf("unexpected result %q, %v", value, err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected 1 load, got %d", loads)
+	}
+
+	cache.clear()
+	if _, err := readThrough(cache, "GetLocation", int32(1), load); err != nil {
+		t.Fatal(err)
+	}
+	if loads != 2 {
+		t.Fatalf("expected reload after clear, got %d loads", loads)
+	}
+}
+
+func TestReadThroughSkipsErrorsAndDisabledCache(t *testing.T) {
+	cache := newLookupCache(time.Minute)
+	if _, err := readThrough(cache, "GetLocation", int32(1), func() (int, error) {
+		return 0, errors.New("not found")
+	}); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := cache.get(cacheKey{query: "GetLocation", arg: int32(1)}); ok {
+		t.Fatal("errors must not be cached")
+	}
+
+	disabled := newLookupCache(0)
+	loads := 0
+	for i := 0; i < 2; i++ {
+		readThrough(disabled, "CountLocations", nil, func() (int64, error) {
+			loads++
+			return 5, nil
+		})
+	}
+	if loads != 2 {
+		t.Fatalf("expected disabled cache to always load, got %d loads", loads)
+	}
+}
@@ -0,0 +1,5704 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=repository.go -destination=mocks/mock_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	db "sparepart-management-services/internal/database/sqlc"
+	repository "sparepart-management-services/internal/repository"
+
+	pgtype "github.com/jackc/pgx/v5/pgtype"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLocationRepository is a mock of LocationRepository interface.
+type MockLocationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLocationRepositoryMockRecorder is the mock recorder for MockLocationRepository.
+type MockLocationRepositoryMockRecorder struct {
+	mock *MockLocationRepository
+}
+
+// NewMockLocationRepository creates a new mock instance.
+func NewMockLocationRepository(ctrl *gomock.Controller) *MockLocationRepository {
+	mock := &MockLocationRepository{ctrl: ctrl}
+	mock.recorder = &MockLocationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocationRepository) EXPECT() *MockLocationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountLocationStockReferences mocks base method.
+func (m *MockLocationRepository) CountLocationStockReferences(ctx context.Context, locationID int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLocationStockReferences", ctx, locationID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLocationStockReferences indicates an expected call of CountLocationStockReferences.
+func (mr *MockLocationRepositoryMockRecorder) CountLocationStockReferences(ctx, locationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLocationStockReferences", reflect.TypeOf((*MockLocationRepository)(nil).CountLocationStockReferences), ctx, locationID)
+}
+
+// CountLocations mocks base method.
+func (m *MockLocationRepository) CountLocations(ctx context.Context, arg db.CountLocationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLocations", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLocations indicates an expected call of CountLocations.
+func (mr *MockLocationRepositoryMockRecorder) CountLocations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLocations", reflect.TypeOf((*MockLocationRepository)(nil).CountLocations), ctx, arg)
+}
+
+// CreateLocation mocks base method.
+func (m *MockLocationRepository) CreateLocation(ctx context.Context, arg db.CreateLocationParams) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLocation", ctx, arg)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLocation indicates an expected call of CreateLocation.
+func (mr *MockLocationRepositoryMockRecorder) CreateLocation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLocation", reflect.TypeOf((*MockLocationRepository)(nil).CreateLocation), ctx, arg)
+}
+
+// DeleteLocation mocks base method.
+func (m *MockLocationRepository) DeleteLocation(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLocation", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLocation indicates an expected call of DeleteLocation.
+func (mr *MockLocationRepositoryMockRecorder) DeleteLocation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLocation", reflect.TypeOf((*MockLocationRepository)(nil).DeleteLocation), ctx, id)
+}
+
+// GetLocation mocks base method.
+func (m *MockLocationRepository) GetLocation(ctx context.Context, id int32) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocation", ctx, id)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocation indicates an expected call of GetLocation.
+func (mr *MockLocationRepositoryMockRecorder) GetLocation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocation", reflect.TypeOf((*MockLocationRepository)(nil).GetLocation), ctx, id)
+}
+
+// ListLocationCompleteness mocks base method.
+func (m *MockLocationRepository) ListLocationCompleteness(ctx context.Context, arg db.ListLocationCompletenessParams) ([]db.ListLocationCompletenessRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocationCompleteness", ctx, arg)
+	ret0, _ := ret[0].([]db.ListLocationCompletenessRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocationCompleteness indicates an expected call of ListLocationCompleteness.
+func (mr *MockLocationRepositoryMockRecorder) ListLocationCompleteness(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocationCompleteness", reflect.TypeOf((*MockLocationRepository)(nil).ListLocationCompleteness), ctx, arg)
+}
+
+// ListLocations mocks base method.
+func (m *MockLocationRepository) ListLocations(ctx context.Context, arg db.ListLocationsParams) ([]db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocations", ctx, arg)
+	ret0, _ := ret[0].([]db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocations indicates an expected call of ListLocations.
+func (mr *MockLocationRepositoryMockRecorder) ListLocations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocations", reflect.TypeOf((*MockLocationRepository)(nil).ListLocations), ctx, arg)
+}
+
+// ListLocationsWithCoordinates mocks base method.
+func (m *MockLocationRepository) ListLocationsWithCoordinates(ctx context.Context, arg db.ListLocationsWithCoordinatesParams) ([]db.ListLocationsWithCoordinatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocationsWithCoordinates", ctx, arg)
+	ret0, _ := ret[0].([]db.ListLocationsWithCoordinatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocationsWithCoordinates indicates an expected call of ListLocationsWithCoordinates.
+func (mr *MockLocationRepositoryMockRecorder) ListLocationsWithCoordinates(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocationsWithCoordinates", reflect.TypeOf((*MockLocationRepository)(nil).ListLocationsWithCoordinates), ctx, arg)
+}
+
+// PatchLocation mocks base method.
+func (m *MockLocationRepository) PatchLocation(ctx context.Context, arg db.PatchLocationParams) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchLocation", ctx, arg)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchLocation indicates an expected call of PatchLocation.
+func (mr *MockLocationRepositoryMockRecorder) PatchLocation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchLocation", reflect.TypeOf((*MockLocationRepository)(nil).PatchLocation), ctx, arg)
+}
+
+// RestoreLocation mocks base method.
+func (m *MockLocationRepository) RestoreLocation(ctx context.Context, id int32) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreLocation", ctx, id)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreLocation indicates an expected call of RestoreLocation.
+func (mr *MockLocationRepositoryMockRecorder) RestoreLocation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreLocation", reflect.TypeOf((*MockLocationRepository)(nil).RestoreLocation), ctx, id)
+}
+
+// SetLocationActive mocks base method.
+func (m *MockLocationRepository) SetLocationActive(ctx context.Context, arg db.SetLocationActiveParams) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocationActive", ctx, arg)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLocationActive indicates an expected call of SetLocationActive.
+func (mr *MockLocationRepositoryMockRecorder) SetLocationActive(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocationActive", reflect.TypeOf((*MockLocationRepository)(nil).SetLocationActive), ctx, arg)
+}
+
+// UpdateLocation mocks base method.
+func (m *MockLocationRepository) UpdateLocation(ctx context.Context, arg db.UpdateLocationParams) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLocation", ctx, arg)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLocation indicates an expected call of UpdateLocation.
+func (mr *MockLocationRepositoryMockRecorder) UpdateLocation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLocation", reflect.TypeOf((*MockLocationRepository)(nil).UpdateLocation), ctx, arg)
+}
+
+// MockContactPersonRepository is a mock of ContactPersonRepository interface.
+type MockContactPersonRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactPersonRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockContactPersonRepositoryMockRecorder is the mock recorder for MockContactPersonRepository.
+type MockContactPersonRepositoryMockRecorder struct {
+	mock *MockContactPersonRepository
+}
+
+// NewMockContactPersonRepository creates a new mock instance.
+func NewMockContactPersonRepository(ctrl *gomock.Controller) *MockContactPersonRepository {
+	mock := &MockContactPersonRepository{ctrl: ctrl}
+	mock.recorder = &MockContactPersonRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactPersonRepository) EXPECT() *MockContactPersonRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountContactPersons mocks base method.
+func (m *MockContactPersonRepository) CountContactPersons(ctx context.Context, locationID pgtype.Int4) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountContactPersons", ctx, locationID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountContactPersons indicates an expected call of CountContactPersons.
+func (mr *MockContactPersonRepositoryMockRecorder) CountContactPersons(ctx, locationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountContactPersons", reflect.TypeOf((*MockContactPersonRepository)(nil).CountContactPersons), ctx, locationID)
+}
+
+// CreateContactPerson mocks base method.
+func (m *MockContactPersonRepository) CreateContactPerson(ctx context.Context, arg db.CreateContactPersonParams) (db.ContactPerson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContactPerson", ctx, arg)
+	ret0, _ := ret[0].(db.ContactPerson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateContactPerson indicates an expected call of CreateContactPerson.
+func (mr *MockContactPersonRepositoryMockRecorder) CreateContactPerson(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContactPerson", reflect.TypeOf((*MockContactPersonRepository)(nil).CreateContactPerson), ctx, arg)
+}
+
+// DeleteContactPerson mocks base method.
+func (m *MockContactPersonRepository) DeleteContactPerson(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContactPerson", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteContactPerson indicates an expected call of DeleteContactPerson.
+func (mr *MockContactPersonRepositoryMockRecorder) DeleteContactPerson(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContactPerson", reflect.TypeOf((*MockContactPersonRepository)(nil).DeleteContactPerson), ctx, id)
+}
+
+// GetContactPerson mocks base method.
+func (m *MockContactPersonRepository) GetContactPerson(ctx context.Context, id int32) (db.GetContactPersonRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContactPerson", ctx, id)
+	ret0, _ := ret[0].(db.GetContactPersonRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContactPerson indicates an expected call of GetContactPerson.
+func (mr *MockContactPersonRepositoryMockRecorder) GetContactPerson(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactPerson", reflect.TypeOf((*MockContactPersonRepository)(nil).GetContactPerson), ctx, id)
+}
+
+// ListContactPersons mocks base method.
+func (m *MockContactPersonRepository) ListContactPersons(ctx context.Context, arg db.ListContactPersonsParams) ([]db.ListContactPersonsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContactPersons", ctx, arg)
+	ret0, _ := ret[0].([]db.ListContactPersonsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContactPersons indicates an expected call of ListContactPersons.
+func (mr *MockContactPersonRepositoryMockRecorder) ListContactPersons(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContactPersons", reflect.TypeOf((*MockContactPersonRepository)(nil).ListContactPersons), ctx, arg)
+}
+
+// PatchContactPerson mocks base method.
+func (m *MockContactPersonRepository) PatchContactPerson(ctx context.Context, arg db.PatchContactPersonParams) (db.ContactPerson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchContactPerson", ctx, arg)
+	ret0, _ := ret[0].(db.ContactPerson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchContactPerson indicates an expected call of PatchContactPerson.
+func (mr *MockContactPersonRepositoryMockRecorder) PatchContactPerson(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchContactPerson", reflect.TypeOf((*MockContactPersonRepository)(nil).PatchContactPerson), ctx, arg)
+}
+
+// RestoreContactPerson mocks base method.
+func (m *MockContactPersonRepository) RestoreContactPerson(ctx context.Context, id int32) (db.ContactPerson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreContactPerson", ctx, id)
+	ret0, _ := ret[0].(db.ContactPerson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreContactPerson indicates an expected call of RestoreContactPerson.
+func (mr *MockContactPersonRepositoryMockRecorder) RestoreContactPerson(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreContactPerson", reflect.TypeOf((*MockContactPersonRepository)(nil).RestoreContactPerson), ctx, id)
+}
+
+// UpdateContactPerson mocks base method.
+func (m *MockContactPersonRepository) UpdateContactPerson(ctx context.Context, arg db.UpdateContactPersonParams) (db.ContactPerson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContactPerson", ctx, arg)
+	ret0, _ := ret[0].(db.ContactPerson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateContactPerson indicates an expected call of UpdateContactPerson.
+func (mr *MockContactPersonRepositoryMockRecorder) UpdateContactPerson(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContactPerson", reflect.TypeOf((*MockContactPersonRepository)(nil).UpdateContactPerson), ctx, arg)
+}
+
+// MockRegencyRepository is a mock of RegencyRepository interface.
+type MockRegencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRegencyRepositoryMockRecorder is the mock recorder for MockRegencyRepository.
+type MockRegencyRepositoryMockRecorder struct {
+	mock *MockRegencyRepository
+}
+
+// NewMockRegencyRepository creates a new mock instance.
+func NewMockRegencyRepository(ctrl *gomock.Controller) *MockRegencyRepository {
+	mock := &MockRegencyRepository{ctrl: ctrl}
+	mock.recorder = &MockRegencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegencyRepository) EXPECT() *MockRegencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountRegencies mocks base method.
+func (m *MockRegencyRepository) CountRegencies(ctx context.Context, arg db.CountRegenciesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRegencies", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRegencies indicates an expected call of CountRegencies.
+func (mr *MockRegencyRepositoryMockRecorder) CountRegencies(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRegencies", reflect.TypeOf((*MockRegencyRepository)(nil).CountRegencies), ctx, arg)
+}
+
+// CreateRegency mocks base method.
+func (m *MockRegencyRepository) CreateRegency(ctx context.Context, arg db.CreateRegencyParams) (db.Regency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRegency", ctx, arg)
+	ret0, _ := ret[0].(db.Regency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRegency indicates an expected call of CreateRegency.
+func (mr *MockRegencyRepositoryMockRecorder) CreateRegency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRegency", reflect.TypeOf((*MockRegencyRepository)(nil).CreateRegency), ctx, arg)
+}
+
+// DeleteRegency mocks base method.
+func (m *MockRegencyRepository) DeleteRegency(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRegency", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRegency indicates an expected call of DeleteRegency.
+func (mr *MockRegencyRepositoryMockRecorder) DeleteRegency(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRegency", reflect.TypeOf((*MockRegencyRepository)(nil).DeleteRegency), ctx, id)
+}
+
+// GetRegency mocks base method.
+func (m *MockRegencyRepository) GetRegency(ctx context.Context, id int32) (db.Regency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegency", ctx, id)
+	ret0, _ := ret[0].(db.Regency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegency indicates an expected call of GetRegency.
+func (mr *MockRegencyRepositoryMockRecorder) GetRegency(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegency", reflect.TypeOf((*MockRegencyRepository)(nil).GetRegency), ctx, id)
+}
+
+// ListRegencies mocks base method.
+func (m *MockRegencyRepository) ListRegencies(ctx context.Context, arg db.ListRegenciesParams) ([]db.Regency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRegencies", ctx, arg)
+	ret0, _ := ret[0].([]db.Regency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRegencies indicates an expected call of ListRegencies.
+func (mr *MockRegencyRepositoryMockRecorder) ListRegencies(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRegencies", reflect.TypeOf((*MockRegencyRepository)(nil).ListRegencies), ctx, arg)
+}
+
+// UpdateRegency mocks base method.
+func (m *MockRegencyRepository) UpdateRegency(ctx context.Context, arg db.UpdateRegencyParams) (db.Regency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRegency", ctx, arg)
+	ret0, _ := ret[0].(db.Regency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRegency indicates an expected call of UpdateRegency.
+func (mr *MockRegencyRepositoryMockRecorder) UpdateRegency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRegency", reflect.TypeOf((*MockRegencyRepository)(nil).UpdateRegency), ctx, arg)
+}
+
+// MockSupplierRepository is a mock of SupplierRepository interface.
+type MockSupplierRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSupplierRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSupplierRepositoryMockRecorder is the mock recorder for MockSupplierRepository.
+type MockSupplierRepositoryMockRecorder struct {
+	mock *MockSupplierRepository
+}
+
+// NewMockSupplierRepository creates a new mock instance.
+func NewMockSupplierRepository(ctrl *gomock.Controller) *MockSupplierRepository {
+	mock := &MockSupplierRepository{ctrl: ctrl}
+	mock.recorder = &MockSupplierRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSupplierRepository) EXPECT() *MockSupplierRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountSuppliers mocks base method.
+func (m *MockSupplierRepository) CountSuppliers(ctx context.Context, name pgtype.Text) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSuppliers", ctx, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSuppliers indicates an expected call of CountSuppliers.
+func (mr *MockSupplierRepositoryMockRecorder) CountSuppliers(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSuppliers", reflect.TypeOf((*MockSupplierRepository)(nil).CountSuppliers), ctx, name)
+}
+
+// CreateSupplier mocks base method.
+func (m *MockSupplierRepository) CreateSupplier(ctx context.Context, arg db.CreateSupplierParams) (db.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSupplier", ctx, arg)
+	ret0, _ := ret[0].(db.Supplier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSupplier indicates an expected call of CreateSupplier.
+func (mr *MockSupplierRepositoryMockRecorder) CreateSupplier(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSupplier", reflect.TypeOf((*MockSupplierRepository)(nil).CreateSupplier), ctx, arg)
+}
+
+// DeleteSupplier mocks base method.
+func (m *MockSupplierRepository) DeleteSupplier(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSupplier", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSupplier indicates an expected call of DeleteSupplier.
+func (mr *MockSupplierRepositoryMockRecorder) DeleteSupplier(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSupplier", reflect.TypeOf((*MockSupplierRepository)(nil).DeleteSupplier), ctx, id)
+}
+
+// GetSupplier mocks base method.
+func (m *MockSupplierRepository) GetSupplier(ctx context.Context, id int32) (db.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupplier", ctx, id)
+	ret0, _ := ret[0].(db.Supplier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupplier indicates an expected call of GetSupplier.
+func (mr *MockSupplierRepositoryMockRecorder) GetSupplier(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupplier", reflect.TypeOf((*MockSupplierRepository)(nil).GetSupplier), ctx, id)
+}
+
+// ListSuppliers mocks base method.
+func (m *MockSupplierRepository) ListSuppliers(ctx context.Context, arg db.ListSuppliersParams) ([]db.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSuppliers", ctx, arg)
+	ret0, _ := ret[0].([]db.Supplier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSuppliers indicates an expected call of ListSuppliers.
+func (mr *MockSupplierRepositoryMockRecorder) ListSuppliers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSuppliers", reflect.TypeOf((*MockSupplierRepository)(nil).ListSuppliers), ctx, arg)
+}
+
+// UpdateSupplier mocks base method.
+func (m *MockSupplierRepository) UpdateSupplier(ctx context.Context, arg db.UpdateSupplierParams) (db.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSupplier", ctx, arg)
+	ret0, _ := ret[0].(db.Supplier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSupplier indicates an expected call of UpdateSupplier.
+func (mr *MockSupplierRepositoryMockRecorder) UpdateSupplier(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSupplier", reflect.TypeOf((*MockSupplierRepository)(nil).UpdateSupplier), ctx, arg)
+}
+
+// MockTechnicianRepository is a mock of TechnicianRepository interface.
+type MockTechnicianRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTechnicianRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTechnicianRepositoryMockRecorder is the mock recorder for MockTechnicianRepository.
+type MockTechnicianRepositoryMockRecorder struct {
+	mock *MockTechnicianRepository
+}
+
+// NewMockTechnicianRepository creates a new mock instance.
+func NewMockTechnicianRepository(ctrl *gomock.Controller) *MockTechnicianRepository {
+	mock := &MockTechnicianRepository{ctrl: ctrl}
+	mock.recorder = &MockTechnicianRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTechnicianRepository) EXPECT() *MockTechnicianRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountTechnicians mocks base method.
+func (m *MockTechnicianRepository) CountTechnicians(ctx context.Context, arg db.CountTechniciansParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTechnicians", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTechnicians indicates an expected call of CountTechnicians.
+func (mr *MockTechnicianRepositoryMockRecorder) CountTechnicians(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTechnicians", reflect.TypeOf((*MockTechnicianRepository)(nil).CountTechnicians), ctx, arg)
+}
+
+// CreateTechnician mocks base method.
+func (m *MockTechnicianRepository) CreateTechnician(ctx context.Context, arg db.CreateTechnicianParams) (db.Technician, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTechnician", ctx, arg)
+	ret0, _ := ret[0].(db.Technician)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTechnician indicates an expected call of CreateTechnician.
+func (mr *MockTechnicianRepositoryMockRecorder) CreateTechnician(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTechnician", reflect.TypeOf((*MockTechnicianRepository)(nil).CreateTechnician), ctx, arg)
+}
+
+// DeleteTechnician mocks base method.
+func (m *MockTechnicianRepository) DeleteTechnician(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTechnician", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTechnician indicates an expected call of DeleteTechnician.
+func (mr *MockTechnicianRepositoryMockRecorder) DeleteTechnician(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTechnician", reflect.TypeOf((*MockTechnicianRepository)(nil).DeleteTechnician), ctx, id)
+}
+
+// GetTechnician mocks base method.
+func (m *MockTechnicianRepository) GetTechnician(ctx context.Context, id int32) (db.Technician, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTechnician", ctx, id)
+	ret0, _ := ret[0].(db.Technician)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTechnician indicates an expected call of GetTechnician.
+func (mr *MockTechnicianRepositoryMockRecorder) GetTechnician(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTechnician", reflect.TypeOf((*MockTechnicianRepository)(nil).GetTechnician), ctx, id)
+}
+
+// ListTechnicians mocks base method.
+func (m *MockTechnicianRepository) ListTechnicians(ctx context.Context, arg db.ListTechniciansParams) ([]db.Technician, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTechnicians", ctx, arg)
+	ret0, _ := ret[0].([]db.Technician)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTechnicians indicates an expected call of ListTechnicians.
+func (mr *MockTechnicianRepositoryMockRecorder) ListTechnicians(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTechnicians", reflect.TypeOf((*MockTechnicianRepository)(nil).ListTechnicians), ctx, arg)
+}
+
+// UpdateTechnician mocks base method.
+func (m *MockTechnicianRepository) UpdateTechnician(ctx context.Context, arg db.UpdateTechnicianParams) (db.Technician, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTechnician", ctx, arg)
+	ret0, _ := ret[0].(db.Technician)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTechnician indicates an expected call of UpdateTechnician.
+func (mr *MockTechnicianRepositoryMockRecorder) UpdateTechnician(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTechnician", reflect.TypeOf((*MockTechnicianRepository)(nil).UpdateTechnician), ctx, arg)
+}
+
+// MockClusterRepository is a mock of ClusterRepository interface.
+type MockClusterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockClusterRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockClusterRepositoryMockRecorder is the mock recorder for MockClusterRepository.
+type MockClusterRepositoryMockRecorder struct {
+	mock *MockClusterRepository
+}
+
+// NewMockClusterRepository creates a new mock instance.
+func NewMockClusterRepository(ctrl *gomock.Controller) *MockClusterRepository {
+	mock := &MockClusterRepository{ctrl: ctrl}
+	mock.recorder = &MockClusterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClusterRepository) EXPECT() *MockClusterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountClusters mocks base method.
+func (m *MockClusterRepository) CountClusters(ctx context.Context, arg db.CountClustersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountClusters", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountClusters indicates an expected call of CountClusters.
+func (mr *MockClusterRepositoryMockRecorder) CountClusters(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountClusters", reflect.TypeOf((*MockClusterRepository)(nil).CountClusters), ctx, arg)
+}
+
+// CreateCluster mocks base method.
+func (m *MockClusterRepository) CreateCluster(ctx context.Context, arg db.CreateClusterParams) (db.CreateClusterRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCluster", ctx, arg)
+	ret0, _ := ret[0].(db.CreateClusterRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCluster indicates an expected call of CreateCluster.
+func (mr *MockClusterRepositoryMockRecorder) CreateCluster(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCluster", reflect.TypeOf((*MockClusterRepository)(nil).CreateCluster), ctx, arg)
+}
+
+// DeleteCluster mocks base method.
+func (m *MockClusterRepository) DeleteCluster(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCluster", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCluster indicates an expected call of DeleteCluster.
+func (mr *MockClusterRepositoryMockRecorder) DeleteCluster(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCluster", reflect.TypeOf((*MockClusterRepository)(nil).DeleteCluster), ctx, id)
+}
+
+// GetCluster mocks base method.
+func (m *MockClusterRepository) GetCluster(ctx context.Context, id int32) (db.GetClusterRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCluster", ctx, id)
+	ret0, _ := ret[0].(db.GetClusterRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCluster indicates an expected call of GetCluster.
+func (mr *MockClusterRepositoryMockRecorder) GetCluster(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCluster", reflect.TypeOf((*MockClusterRepository)(nil).GetCluster), ctx, id)
+}
+
+// ListClusters mocks base method.
+func (m *MockClusterRepository) ListClusters(ctx context.Context, arg db.ListClustersParams) ([]db.ListClustersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClusters", ctx, arg)
+	ret0, _ := ret[0].([]db.ListClustersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusters indicates an expected call of ListClusters.
+func (mr *MockClusterRepositoryMockRecorder) ListClusters(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockClusterRepository)(nil).ListClusters), ctx, arg)
+}
+
+// UpdateCluster mocks base method.
+func (m *MockClusterRepository) UpdateCluster(ctx context.Context, arg db.UpdateClusterParams) (db.UpdateClusterRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCluster", ctx, arg)
+	ret0, _ := ret[0].(db.UpdateClusterRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCluster indicates an expected call of UpdateCluster.
+func (mr *MockClusterRepositoryMockRecorder) UpdateCluster(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCluster", reflect.TypeOf((*MockClusterRepository)(nil).UpdateCluster), ctx, arg)
+}
+
+// MockSparepartMasterRepository is a mock of SparepartMasterRepository interface.
+type MockSparepartMasterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSparepartMasterRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSparepartMasterRepositoryMockRecorder is the mock recorder for MockSparepartMasterRepository.
+type MockSparepartMasterRepositoryMockRecorder struct {
+	mock *MockSparepartMasterRepository
+}
+
+// NewMockSparepartMasterRepository creates a new mock instance.
+func NewMockSparepartMasterRepository(ctrl *gomock.Controller) *MockSparepartMasterRepository {
+	mock := &MockSparepartMasterRepository{ctrl: ctrl}
+	mock.recorder = &MockSparepartMasterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSparepartMasterRepository) EXPECT() *MockSparepartMasterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CopySparepartMasterImport mocks base method.
+func (m *MockSparepartMasterRepository) CopySparepartMasterImport(ctx context.Context, arg []db.CopySparepartMasterImportParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopySparepartMasterImport", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopySparepartMasterImport indicates an expected call of CopySparepartMasterImport.
+func (mr *MockSparepartMasterRepositoryMockRecorder) CopySparepartMasterImport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopySparepartMasterImport", reflect.TypeOf((*MockSparepartMasterRepository)(nil).CopySparepartMasterImport), ctx, arg)
+}
+
+// CountSparepartMasterReferences mocks base method.
+func (m *MockSparepartMasterRepository) CountSparepartMasterReferences(ctx context.Context, id int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSparepartMasterReferences", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSparepartMasterReferences indicates an expected call of CountSparepartMasterReferences.
+func (mr *MockSparepartMasterRepositoryMockRecorder) CountSparepartMasterReferences(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSparepartMasterReferences", reflect.TypeOf((*MockSparepartMasterRepository)(nil).CountSparepartMasterReferences), ctx, id)
+}
+
+// CountSparepartMasters mocks base method.
+func (m *MockSparepartMasterRepository) CountSparepartMasters(ctx context.Context, arg db.CountSparepartMastersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSparepartMasters", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSparepartMasters indicates an expected call of CountSparepartMasters.
+func (mr *MockSparepartMasterRepositoryMockRecorder) CountSparepartMasters(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSparepartMasters", reflect.TypeOf((*MockSparepartMasterRepository)(nil).CountSparepartMasters), ctx, arg)
+}
+
+// CreateSparepartMaster mocks base method.
+func (m *MockSparepartMasterRepository) CreateSparepartMaster(ctx context.Context, arg db.CreateSparepartMasterParams) (db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSparepartMaster", ctx, arg)
+	ret0, _ := ret[0].(db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSparepartMaster indicates an expected call of CreateSparepartMaster.
+func (mr *MockSparepartMasterRepositoryMockRecorder) CreateSparepartMaster(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSparepartMaster", reflect.TypeOf((*MockSparepartMasterRepository)(nil).CreateSparepartMaster), ctx, arg)
+}
+
+// CreateSparepartMastersFromImport mocks base method.
+func (m *MockSparepartMasterRepository) CreateSparepartMastersFromImport(ctx context.Context) ([]db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSparepartMastersFromImport", ctx)
+	ret0, _ := ret[0].([]db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSparepartMastersFromImport indicates an expected call of CreateSparepartMastersFromImport.
+func (mr *MockSparepartMasterRepositoryMockRecorder) CreateSparepartMastersFromImport(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSparepartMastersFromImport", reflect.TypeOf((*MockSparepartMasterRepository)(nil).CreateSparepartMastersFromImport), ctx)
+}
+
+// DeleteSparepartMaster mocks base method.
+func (m *MockSparepartMasterRepository) DeleteSparepartMaster(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSparepartMaster", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSparepartMaster indicates an expected call of DeleteSparepartMaster.
+func (mr *MockSparepartMasterRepositoryMockRecorder) DeleteSparepartMaster(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSparepartMaster", reflect.TypeOf((*MockSparepartMasterRepository)(nil).DeleteSparepartMaster), ctx, id)
+}
+
+// GetSparepartMaster mocks base method.
+func (m *MockSparepartMasterRepository) GetSparepartMaster(ctx context.Context, id int32) (db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSparepartMaster", ctx, id)
+	ret0, _ := ret[0].(db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSparepartMaster indicates an expected call of GetSparepartMaster.
+func (mr *MockSparepartMasterRepositoryMockRecorder) GetSparepartMaster(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSparepartMaster", reflect.TypeOf((*MockSparepartMasterRepository)(nil).GetSparepartMaster), ctx, id)
+}
+
+// ListSparepartMasters mocks base method.
+func (m *MockSparepartMasterRepository) ListSparepartMasters(ctx context.Context, arg db.ListSparepartMastersParams) ([]db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartMasters", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartMasters indicates an expected call of ListSparepartMasters.
+func (mr *MockSparepartMasterRepositoryMockRecorder) ListSparepartMasters(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartMasters", reflect.TypeOf((*MockSparepartMasterRepository)(nil).ListSparepartMasters), ctx, arg)
+}
+
+// ListSparepartMastersMatchingNames mocks base method.
+func (m *MockSparepartMasterRepository) ListSparepartMastersMatchingNames(ctx context.Context, names []string) ([]db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartMastersMatchingNames", ctx, names)
+	ret0, _ := ret[0].([]db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartMastersMatchingNames indicates an expected call of ListSparepartMastersMatchingNames.
+func (mr *MockSparepartMasterRepositoryMockRecorder) ListSparepartMastersMatchingNames(ctx, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartMastersMatchingNames", reflect.TypeOf((*MockSparepartMasterRepository)(nil).ListSparepartMastersMatchingNames), ctx, names)
+}
+
+// PatchSparepartMaster mocks base method.
+func (m *MockSparepartMasterRepository) PatchSparepartMaster(ctx context.Context, arg db.PatchSparepartMasterParams) (db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchSparepartMaster", ctx, arg)
+	ret0, _ := ret[0].(db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchSparepartMaster indicates an expected call of PatchSparepartMaster.
+func (mr *MockSparepartMasterRepositoryMockRecorder) PatchSparepartMaster(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchSparepartMaster", reflect.TypeOf((*MockSparepartMasterRepository)(nil).PatchSparepartMaster), ctx, arg)
+}
+
+// RestoreSparepartMaster mocks base method.
+func (m *MockSparepartMasterRepository) RestoreSparepartMaster(ctx context.Context, id int32) (db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSparepartMaster", ctx, id)
+	ret0, _ := ret[0].(db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSparepartMaster indicates an expected call of RestoreSparepartMaster.
+func (mr *MockSparepartMasterRepositoryMockRecorder) RestoreSparepartMaster(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSparepartMaster", reflect.TypeOf((*MockSparepartMasterRepository)(nil).RestoreSparepartMaster), ctx, id)
+}
+
+// UpdateSparepartMaster mocks base method.
+func (m *MockSparepartMasterRepository) UpdateSparepartMaster(ctx context.Context, arg db.UpdateSparepartMasterParams) (db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSparepartMaster", ctx, arg)
+	ret0, _ := ret[0].(db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSparepartMaster indicates an expected call of UpdateSparepartMaster.
+func (mr *MockSparepartMasterRepositoryMockRecorder) UpdateSparepartMaster(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSparepartMaster", reflect.TypeOf((*MockSparepartMasterRepository)(nil).UpdateSparepartMaster), ctx, arg)
+}
+
+// ListContactPersonsByLocations mocks base method.
+func (m *MockSparepartMasterRepository) ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]db.ContactPerson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContactPersonsByLocations", ctx, locationIds)
+	ret0, _ := ret[0].([]db.ContactPerson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContactPersonsByLocations indicates an expected call of ListContactPersonsByLocations.
+func (mr *MockSparepartMasterRepositoryMockRecorder) ListContactPersonsByLocations(ctx, locationIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContactPersonsByLocations", reflect.TypeOf((*MockSparepartMasterRepository)(nil).ListContactPersonsByLocations), ctx, locationIds)
+}
+
+// ListSparepartAvailability mocks base method.
+func (m *MockSparepartMasterRepository) ListSparepartAvailability(ctx context.Context, sparepartID int32) ([]db.ListSparepartAvailabilityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartAvailability", ctx, sparepartID)
+	ret0, _ := ret[0].([]db.ListSparepartAvailabilityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartAvailability indicates an expected call of ListSparepartAvailability.
+func (mr *MockSparepartMasterRepositoryMockRecorder) ListSparepartAvailability(ctx, sparepartID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartAvailability", reflect.TypeOf((*MockSparepartMasterRepository)(nil).ListSparepartAvailability), ctx, sparepartID)
+}
+
+// WithinSparepartMasterTransaction mocks base method.
+func (m *MockSparepartMasterRepository) WithinSparepartMasterTransaction(ctx context.Context, fn func(repository.SparepartMasterRepository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinSparepartMasterTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinSparepartMasterTransaction indicates an expected call of WithinSparepartMasterTransaction.
+func (mr *MockSparepartMasterRepositoryMockRecorder) WithinSparepartMasterTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinSparepartMasterTransaction", reflect.TypeOf((*MockSparepartMasterRepository)(nil).WithinSparepartMasterTransaction), ctx, fn)
+}
+
+// MockSparepartStockRepository is a mock of SparepartStockRepository interface.
+type MockSparepartStockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSparepartStockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSparepartStockRepositoryMockRecorder is the mock recorder for MockSparepartStockRepository.
+type MockSparepartStockRepositoryMockRecorder struct {
+	mock *MockSparepartStockRepository
+}
+
+// NewMockSparepartStockRepository creates a new mock instance.
+func NewMockSparepartStockRepository(ctrl *gomock.Controller) *MockSparepartStockRepository {
+	mock := &MockSparepartStockRepository{ctrl: ctrl}
+	mock.recorder = &MockSparepartStockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSparepartStockRepository) EXPECT() *MockSparepartStockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AdjustSparepartStock mocks base method.
+func (m *MockSparepartStockRepository) AdjustSparepartStock(ctx context.Context, arg db.AdjustSparepartStockParams) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustSparepartStock", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustSparepartStock indicates an expected call of AdjustSparepartStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) AdjustSparepartStock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustSparepartStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).AdjustSparepartStock), ctx, arg)
+}
+
+// ApplyGoodsReceiptItemCost mocks base method.
+func (m *MockSparepartStockRepository) ApplyGoodsReceiptItemCost(ctx context.Context, arg db.ApplyGoodsReceiptItemCostParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyGoodsReceiptItemCost", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyGoodsReceiptItemCost indicates an expected call of ApplyGoodsReceiptItemCost.
+func (mr *MockSparepartStockRepositoryMockRecorder) ApplyGoodsReceiptItemCost(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyGoodsReceiptItemCost", reflect.TypeOf((*MockSparepartStockRepository)(nil).ApplyGoodsReceiptItemCost), ctx, arg)
+}
+
+// ApproveStockOpname mocks base method.
+func (m *MockSparepartStockRepository) ApproveStockOpname(ctx context.Context, arg db.ApproveStockOpnameParams) (db.StockOpname, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveStockOpname", ctx, arg)
+	ret0, _ := ret[0].(db.StockOpname)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveStockOpname indicates an expected call of ApproveStockOpname.
+func (mr *MockSparepartStockRepositoryMockRecorder) ApproveStockOpname(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveStockOpname", reflect.TypeOf((*MockSparepartStockRepository)(nil).ApproveStockOpname), ctx, arg)
+}
+
+// CloseWorkOrder mocks base method.
+func (m *MockSparepartStockRepository) CloseWorkOrder(ctx context.Context, arg db.CloseWorkOrderParams) (db.WorkOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseWorkOrder", ctx, arg)
+	ret0, _ := ret[0].(db.WorkOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseWorkOrder indicates an expected call of CloseWorkOrder.
+func (mr *MockSparepartStockRepositoryMockRecorder) CloseWorkOrder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseWorkOrder", reflect.TypeOf((*MockSparepartStockRepository)(nil).CloseWorkOrder), ctx, arg)
+}
+
+// ConfirmGoodsReceipt mocks base method.
+func (m *MockSparepartStockRepository) ConfirmGoodsReceipt(ctx context.Context, arg db.ConfirmGoodsReceiptParams) (db.GoodsReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmGoodsReceipt", ctx, arg)
+	ret0, _ := ret[0].(db.GoodsReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmGoodsReceipt indicates an expected call of ConfirmGoodsReceipt.
+func (mr *MockSparepartStockRepositoryMockRecorder) ConfirmGoodsReceipt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmGoodsReceipt", reflect.TypeOf((*MockSparepartStockRepository)(nil).ConfirmGoodsReceipt), ctx, arg)
+}
+
+// CopySparepartStockImport mocks base method.
+func (m *MockSparepartStockRepository) CopySparepartStockImport(ctx context.Context, arg []db.CopySparepartStockImportParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopySparepartStockImport", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopySparepartStockImport indicates an expected call of CopySparepartStockImport.
+func (mr *MockSparepartStockRepositoryMockRecorder) CopySparepartStockImport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopySparepartStockImport", reflect.TypeOf((*MockSparepartStockRepository)(nil).CopySparepartStockImport), ctx, arg)
+}
+
+// CountDamageReports mocks base method.
+func (m *MockSparepartStockRepository) CountDamageReports(ctx context.Context, arg db.CountDamageReportsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDamageReports", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDamageReports indicates an expected call of CountDamageReports.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountDamageReports(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDamageReports", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountDamageReports), ctx, arg)
+}
+
+// CountExpiringWarranties mocks base method.
+func (m *MockSparepartStockRepository) CountExpiringWarranties(ctx context.Context, arg db.CountExpiringWarrantiesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountExpiringWarranties", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountExpiringWarranties indicates an expected call of CountExpiringWarranties.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountExpiringWarranties(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountExpiringWarranties", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountExpiringWarranties), ctx, arg)
+}
+
+// CountGoodsReceipts mocks base method.
+func (m *MockSparepartStockRepository) CountGoodsReceipts(ctx context.Context, arg db.CountGoodsReceiptsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountGoodsReceipts", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountGoodsReceipts indicates an expected call of CountGoodsReceipts.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountGoodsReceipts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountGoodsReceipts", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountGoodsReceipts), ctx, arg)
+}
+
+// CountPurchaseOrders mocks base method.
+func (m *MockSparepartStockRepository) CountPurchaseOrders(ctx context.Context, arg db.CountPurchaseOrdersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPurchaseOrders", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPurchaseOrders indicates an expected call of CountPurchaseOrders.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountPurchaseOrders(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPurchaseOrders", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountPurchaseOrders), ctx, arg)
+}
+
+// CountSparepartRequests mocks base method.
+func (m *MockSparepartStockRepository) CountSparepartRequests(ctx context.Context, arg db.CountSparepartRequestsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSparepartRequests", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSparepartRequests indicates an expected call of CountSparepartRequests.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountSparepartRequests(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSparepartRequests", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountSparepartRequests), ctx, arg)
+}
+
+// CountSparepartStockItems mocks base method.
+func (m *MockSparepartStockRepository) CountSparepartStockItems(ctx context.Context, arg db.CountSparepartStockItemsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSparepartStockItems", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSparepartStockItems indicates an expected call of CountSparepartStockItems.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountSparepartStockItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSparepartStockItems", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountSparepartStockItems), ctx, arg)
+}
+
+// CountSparepartStocks mocks base method.
+func (m *MockSparepartStockRepository) CountSparepartStocks(ctx context.Context, arg db.CountSparepartStocksParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSparepartStocks", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSparepartStocks indicates an expected call of CountSparepartStocks.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountSparepartStocks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSparepartStocks", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountSparepartStocks), ctx, arg)
+}
+
+// CountStockDisposals mocks base method.
+func (m *MockSparepartStockRepository) CountStockDisposals(ctx context.Context, arg db.CountStockDisposalsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStockDisposals", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStockDisposals indicates an expected call of CountStockDisposals.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountStockDisposals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStockDisposals", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountStockDisposals), ctx, arg)
+}
+
+// CountStockOpnames mocks base method.
+func (m *MockSparepartStockRepository) CountStockOpnames(ctx context.Context, arg db.CountStockOpnamesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStockOpnames", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStockOpnames indicates an expected call of CountStockOpnames.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountStockOpnames(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStockOpnames", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountStockOpnames), ctx, arg)
+}
+
+// CountStockTransfers mocks base method.
+func (m *MockSparepartStockRepository) CountStockTransfers(ctx context.Context, arg db.CountStockTransfersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStockTransfers", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStockTransfers indicates an expected call of CountStockTransfers.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountStockTransfers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStockTransfers", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountStockTransfers), ctx, arg)
+}
+
+// CountStockUnits mocks base method.
+func (m *MockSparepartStockRepository) CountStockUnits(ctx context.Context, stockItemID int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStockUnits", ctx, stockItemID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStockUnits indicates an expected call of CountStockUnits.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountStockUnits(ctx, stockItemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStockUnits", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountStockUnits), ctx, stockItemID)
+}
+
+// CountWorkOrders mocks base method.
+func (m *MockSparepartStockRepository) CountWorkOrders(ctx context.Context, arg db.CountWorkOrdersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWorkOrders", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWorkOrders indicates an expected call of CountWorkOrders.
+func (mr *MockSparepartStockRepositoryMockRecorder) CountWorkOrders(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorkOrders", reflect.TypeOf((*MockSparepartStockRepository)(nil).CountWorkOrders), ctx, arg)
+}
+
+// CreateDamageReport mocks base method.
+func (m *MockSparepartStockRepository) CreateDamageReport(ctx context.Context, arg db.CreateDamageReportParams) (db.DamageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDamageReport", ctx, arg)
+	ret0, _ := ret[0].(db.DamageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDamageReport indicates an expected call of CreateDamageReport.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateDamageReport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDamageReport", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateDamageReport), ctx, arg)
+}
+
+// CreateGoodsReceipt mocks base method.
+func (m *MockSparepartStockRepository) CreateGoodsReceipt(ctx context.Context, arg db.CreateGoodsReceiptParams) (db.GoodsReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGoodsReceipt", ctx, arg)
+	ret0, _ := ret[0].(db.GoodsReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGoodsReceipt indicates an expected call of CreateGoodsReceipt.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateGoodsReceipt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGoodsReceipt", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateGoodsReceipt), ctx, arg)
+}
+
+// CreateGoodsReceiptItem mocks base method.
+func (m *MockSparepartStockRepository) CreateGoodsReceiptItem(ctx context.Context, arg db.CreateGoodsReceiptItemParams) (db.GoodsReceiptItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGoodsReceiptItem", ctx, arg)
+	ret0, _ := ret[0].(db.GoodsReceiptItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGoodsReceiptItem indicates an expected call of CreateGoodsReceiptItem.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateGoodsReceiptItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGoodsReceiptItem", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateGoodsReceiptItem), ctx, arg)
+}
+
+// CreatePurchaseOrder mocks base method.
+func (m *MockSparepartStockRepository) CreatePurchaseOrder(ctx context.Context, arg db.CreatePurchaseOrderParams) (db.PurchaseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePurchaseOrder", ctx, arg)
+	ret0, _ := ret[0].(db.PurchaseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePurchaseOrder indicates an expected call of CreatePurchaseOrder.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreatePurchaseOrder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePurchaseOrder", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreatePurchaseOrder), ctx, arg)
+}
+
+// CreatePurchaseOrderItem mocks base method.
+func (m *MockSparepartStockRepository) CreatePurchaseOrderItem(ctx context.Context, arg db.CreatePurchaseOrderItemParams) (db.PurchaseOrderItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePurchaseOrderItem", ctx, arg)
+	ret0, _ := ret[0].(db.PurchaseOrderItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePurchaseOrderItem indicates an expected call of CreatePurchaseOrderItem.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreatePurchaseOrderItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePurchaseOrderItem", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreatePurchaseOrderItem), ctx, arg)
+}
+
+// CreateSparepartRequest mocks base method.
+func (m *MockSparepartStockRepository) CreateSparepartRequest(ctx context.Context, arg db.CreateSparepartRequestParams) (db.SparepartRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSparepartRequest", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSparepartRequest indicates an expected call of CreateSparepartRequest.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateSparepartRequest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSparepartRequest", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateSparepartRequest), ctx, arg)
+}
+
+// CreateSparepartRequestEvent mocks base method.
+func (m *MockSparepartStockRepository) CreateSparepartRequestEvent(ctx context.Context, arg db.CreateSparepartRequestEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSparepartRequestEvent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSparepartRequestEvent indicates an expected call of CreateSparepartRequestEvent.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateSparepartRequestEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSparepartRequestEvent", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateSparepartRequestEvent), ctx, arg)
+}
+
+// CreateSparepartRequestItem mocks base method.
+func (m *MockSparepartStockRepository) CreateSparepartRequestItem(ctx context.Context, arg db.CreateSparepartRequestItemParams) (db.SparepartRequestItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSparepartRequestItem", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartRequestItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSparepartRequestItem indicates an expected call of CreateSparepartRequestItem.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateSparepartRequestItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSparepartRequestItem", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateSparepartRequestItem), ctx, arg)
+}
+
+// CreateSparepartStock mocks base method.
+func (m *MockSparepartStockRepository) CreateSparepartStock(ctx context.Context, arg db.CreateSparepartStockParams) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSparepartStock", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSparepartStock indicates an expected call of CreateSparepartStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateSparepartStock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSparepartStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateSparepartStock), ctx, arg)
+}
+
+// CreateSparepartStocksBatch mocks base method.
+func (m *MockSparepartStockRepository) CreateSparepartStocksBatch(ctx context.Context, arg db.CreateSparepartStocksBatchParams) ([]db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSparepartStocksBatch", ctx, arg)
+	ret0, _ := ret[0].([]db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSparepartStocksBatch indicates an expected call of CreateSparepartStocksBatch.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateSparepartStocksBatch(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSparepartStocksBatch", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateSparepartStocksBatch), ctx, arg)
+}
+
+// CreateSparepartStocksFromImport mocks base method.
+func (m *MockSparepartStockRepository) CreateSparepartStocksFromImport(ctx context.Context) ([]db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSparepartStocksFromImport", ctx)
+	ret0, _ := ret[0].([]db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSparepartStocksFromImport indicates an expected call of CreateSparepartStocksFromImport.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateSparepartStocksFromImport(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSparepartStocksFromImport", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateSparepartStocksFromImport), ctx)
+}
+
+// CreateStockDisposal mocks base method.
+func (m *MockSparepartStockRepository) CreateStockDisposal(ctx context.Context, arg db.CreateStockDisposalParams) (db.StockDisposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStockDisposal", ctx, arg)
+	ret0, _ := ret[0].(db.StockDisposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStockDisposal indicates an expected call of CreateStockDisposal.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateStockDisposal(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStockDisposal", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateStockDisposal), ctx, arg)
+}
+
+// CreateStockOpname mocks base method.
+func (m *MockSparepartStockRepository) CreateStockOpname(ctx context.Context, arg db.CreateStockOpnameParams) (db.StockOpname, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStockOpname", ctx, arg)
+	ret0, _ := ret[0].(db.StockOpname)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStockOpname indicates an expected call of CreateStockOpname.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateStockOpname(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStockOpname", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateStockOpname), ctx, arg)
+}
+
+// CreateStockTransfer mocks base method.
+func (m *MockSparepartStockRepository) CreateStockTransfer(ctx context.Context, arg db.CreateStockTransferParams) (db.StockTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStockTransfer", ctx, arg)
+	ret0, _ := ret[0].(db.StockTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStockTransfer indicates an expected call of CreateStockTransfer.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateStockTransfer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStockTransfer", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateStockTransfer), ctx, arg)
+}
+
+// CreateStockUnit mocks base method.
+func (m *MockSparepartStockRepository) CreateStockUnit(ctx context.Context, arg db.CreateStockUnitParams) (db.StockUnit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStockUnit", ctx, arg)
+	ret0, _ := ret[0].(db.StockUnit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStockUnit indicates an expected call of CreateStockUnit.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateStockUnit(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStockUnit", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateStockUnit), ctx, arg)
+}
+
+// CreateWorkOrder mocks base method.
+func (m *MockSparepartStockRepository) CreateWorkOrder(ctx context.Context, arg db.CreateWorkOrderParams) (db.WorkOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkOrder", ctx, arg)
+	ret0, _ := ret[0].(db.WorkOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkOrder indicates an expected call of CreateWorkOrder.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateWorkOrder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkOrder", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateWorkOrder), ctx, arg)
+}
+
+// CreateWorkOrderItem mocks base method.
+func (m *MockSparepartStockRepository) CreateWorkOrderItem(ctx context.Context, arg db.CreateWorkOrderItemParams) (db.WorkOrderItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkOrderItem", ctx, arg)
+	ret0, _ := ret[0].(db.WorkOrderItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkOrderItem indicates an expected call of CreateWorkOrderItem.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateWorkOrderItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkOrderItem", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateWorkOrderItem), ctx, arg)
+}
+
+// CreateWorkOrderTool mocks base method.
+func (m *MockSparepartStockRepository) CreateWorkOrderTool(ctx context.Context, arg db.CreateWorkOrderToolParams) (db.WorkOrderTool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkOrderTool", ctx, arg)
+	ret0, _ := ret[0].(db.WorkOrderTool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkOrderTool indicates an expected call of CreateWorkOrderTool.
+func (mr *MockSparepartStockRepositoryMockRecorder) CreateWorkOrderTool(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkOrderTool", reflect.TypeOf((*MockSparepartStockRepository)(nil).CreateWorkOrderTool), ctx, arg)
+}
+
+// DeleteSparepartStock mocks base method.
+func (m *MockSparepartStockRepository) DeleteSparepartStock(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSparepartStock", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSparepartStock indicates an expected call of DeleteSparepartStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) DeleteSparepartStock(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSparepartStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).DeleteSparepartStock), ctx, id)
+}
+
+// DeleteStockUnit mocks base method.
+func (m *MockSparepartStockRepository) DeleteStockUnit(ctx context.Context, arg db.DeleteStockUnitParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStockUnit", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStockUnit indicates an expected call of DeleteStockUnit.
+func (mr *MockSparepartStockRepositoryMockRecorder) DeleteStockUnit(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStockUnit", reflect.TypeOf((*MockSparepartStockRepository)(nil).DeleteStockUnit), ctx, arg)
+}
+
+// DeleteWorkOrderItems mocks base method.
+func (m *MockSparepartStockRepository) DeleteWorkOrderItems(ctx context.Context, workOrderID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkOrderItems", ctx, workOrderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkOrderItems indicates an expected call of DeleteWorkOrderItems.
+func (mr *MockSparepartStockRepositoryMockRecorder) DeleteWorkOrderItems(ctx, workOrderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkOrderItems", reflect.TypeOf((*MockSparepartStockRepository)(nil).DeleteWorkOrderItems), ctx, workOrderID)
+}
+
+// DeleteWorkOrderTools mocks base method.
+func (m *MockSparepartStockRepository) DeleteWorkOrderTools(ctx context.Context, workOrderID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkOrderTools", ctx, workOrderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkOrderTools indicates an expected call of DeleteWorkOrderTools.
+func (mr *MockSparepartStockRepositoryMockRecorder) DeleteWorkOrderTools(ctx, workOrderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkOrderTools", reflect.TypeOf((*MockSparepartStockRepository)(nil).DeleteWorkOrderTools), ctx, workOrderID)
+}
+
+// FulfillSparepartRequest mocks base method.
+func (m *MockSparepartStockRepository) FulfillSparepartRequest(ctx context.Context, arg db.FulfillSparepartRequestParams) (db.SparepartRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FulfillSparepartRequest", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FulfillSparepartRequest indicates an expected call of FulfillSparepartRequest.
+func (mr *MockSparepartStockRepositoryMockRecorder) FulfillSparepartRequest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FulfillSparepartRequest", reflect.TypeOf((*MockSparepartStockRepository)(nil).FulfillSparepartRequest), ctx, arg)
+}
+
+// GetActiveLocationForShare mocks base method.
+func (m *MockSparepartStockRepository) GetActiveLocationForShare(ctx context.Context, id int32) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveLocationForShare", ctx, id)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveLocationForShare indicates an expected call of GetActiveLocationForShare.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetActiveLocationForShare(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveLocationForShare", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetActiveLocationForShare), ctx, id)
+}
+
+// GetDamageReport mocks base method.
+func (m *MockSparepartStockRepository) GetDamageReport(ctx context.Context, id int32) (db.GetDamageReportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDamageReport", ctx, id)
+	ret0, _ := ret[0].(db.GetDamageReportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDamageReport indicates an expected call of GetDamageReport.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetDamageReport(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDamageReport", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetDamageReport), ctx, id)
+}
+
+// GetGoodsReceipt mocks base method.
+func (m *MockSparepartStockRepository) GetGoodsReceipt(ctx context.Context, id int32) (db.GetGoodsReceiptRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoodsReceipt", ctx, id)
+	ret0, _ := ret[0].(db.GetGoodsReceiptRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoodsReceipt indicates an expected call of GetGoodsReceipt.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetGoodsReceipt(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoodsReceipt", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetGoodsReceipt), ctx, id)
+}
+
+// GetGoodsReceiptForUpdate mocks base method.
+func (m *MockSparepartStockRepository) GetGoodsReceiptForUpdate(ctx context.Context, id int32) (db.GoodsReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoodsReceiptForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.GoodsReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoodsReceiptForUpdate indicates an expected call of GetGoodsReceiptForUpdate.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetGoodsReceiptForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoodsReceiptForUpdate", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetGoodsReceiptForUpdate), ctx, id)
+}
+
+// GetPurchaseOrder mocks base method.
+func (m *MockSparepartStockRepository) GetPurchaseOrder(ctx context.Context, id int32) (db.GetPurchaseOrderRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseOrder", ctx, id)
+	ret0, _ := ret[0].(db.GetPurchaseOrderRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseOrder indicates an expected call of GetPurchaseOrder.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetPurchaseOrder(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseOrder", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetPurchaseOrder), ctx, id)
+}
+
+// GetPurchaseOrderForUpdate mocks base method.
+func (m *MockSparepartStockRepository) GetPurchaseOrderForUpdate(ctx context.Context, id int32) (db.PurchaseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseOrderForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.PurchaseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseOrderForUpdate indicates an expected call of GetPurchaseOrderForUpdate.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetPurchaseOrderForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseOrderForUpdate", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetPurchaseOrderForUpdate), ctx, id)
+}
+
+// GetSparepartMaster mocks base method.
+func (m *MockSparepartStockRepository) GetSparepartMaster(ctx context.Context, id int32) (db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSparepartMaster", ctx, id)
+	ret0, _ := ret[0].(db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSparepartMaster indicates an expected call of GetSparepartMaster.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetSparepartMaster(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSparepartMaster", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetSparepartMaster), ctx, id)
+}
+
+// GetSparepartRequest mocks base method.
+func (m *MockSparepartStockRepository) GetSparepartRequest(ctx context.Context, id int32) (db.GetSparepartRequestRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSparepartRequest", ctx, id)
+	ret0, _ := ret[0].(db.GetSparepartRequestRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSparepartRequest indicates an expected call of GetSparepartRequest.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetSparepartRequest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSparepartRequest", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetSparepartRequest), ctx, id)
+}
+
+// GetSparepartRequestForUpdate mocks base method.
+func (m *MockSparepartStockRepository) GetSparepartRequestForUpdate(ctx context.Context, id int32) (db.SparepartRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSparepartRequestForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.SparepartRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSparepartRequestForUpdate indicates an expected call of GetSparepartRequestForUpdate.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetSparepartRequestForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSparepartRequestForUpdate", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetSparepartRequestForUpdate), ctx, id)
+}
+
+// GetSparepartStock mocks base method.
+func (m *MockSparepartStockRepository) GetSparepartStock(ctx context.Context, id int32) (db.GetSparepartStockRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSparepartStock", ctx, id)
+	ret0, _ := ret[0].(db.GetSparepartStockRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSparepartStock indicates an expected call of GetSparepartStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetSparepartStock(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSparepartStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetSparepartStock), ctx, id)
+}
+
+// GetSparepartStockByKeyForUpdate mocks base method.
+func (m *MockSparepartStockRepository) GetSparepartStockByKeyForUpdate(ctx context.Context, arg db.GetSparepartStockByKeyForUpdateParams) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSparepartStockByKeyForUpdate", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSparepartStockByKeyForUpdate indicates an expected call of GetSparepartStockByKeyForUpdate.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetSparepartStockByKeyForUpdate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSparepartStockByKeyForUpdate", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetSparepartStockByKeyForUpdate), ctx, arg)
+}
+
+// GetSparepartStockForUpdate mocks base method.
+func (m *MockSparepartStockRepository) GetSparepartStockForUpdate(ctx context.Context, id int32) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSparepartStockForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSparepartStockForUpdate indicates an expected call of GetSparepartStockForUpdate.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetSparepartStockForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSparepartStockForUpdate", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetSparepartStockForUpdate), ctx, id)
+}
+
+// GetStockDisposal mocks base method.
+func (m *MockSparepartStockRepository) GetStockDisposal(ctx context.Context, id int32) (db.GetStockDisposalRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockDisposal", ctx, id)
+	ret0, _ := ret[0].(db.GetStockDisposalRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockDisposal indicates an expected call of GetStockDisposal.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetStockDisposal(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockDisposal", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetStockDisposal), ctx, id)
+}
+
+// GetStockOpname mocks base method.
+func (m *MockSparepartStockRepository) GetStockOpname(ctx context.Context, id int32) (db.GetStockOpnameRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockOpname", ctx, id)
+	ret0, _ := ret[0].(db.GetStockOpnameRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockOpname indicates an expected call of GetStockOpname.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetStockOpname(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockOpname", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetStockOpname), ctx, id)
+}
+
+// GetStockOpnameForUpdate mocks base method.
+func (m *MockSparepartStockRepository) GetStockOpnameForUpdate(ctx context.Context, id int32) (db.StockOpname, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockOpnameForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.StockOpname)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockOpnameForUpdate indicates an expected call of GetStockOpnameForUpdate.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetStockOpnameForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockOpnameForUpdate", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetStockOpnameForUpdate), ctx, id)
+}
+
+// GetWorkOrder mocks base method.
+func (m *MockSparepartStockRepository) GetWorkOrder(ctx context.Context, id int32) (db.GetWorkOrderRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkOrder", ctx, id)
+	ret0, _ := ret[0].(db.GetWorkOrderRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkOrder indicates an expected call of GetWorkOrder.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetWorkOrder(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkOrder", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetWorkOrder), ctx, id)
+}
+
+// GetWorkOrderForUpdate mocks base method.
+func (m *MockSparepartStockRepository) GetWorkOrderForUpdate(ctx context.Context, id int32) (db.WorkOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkOrderForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.WorkOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkOrderForUpdate indicates an expected call of GetWorkOrderForUpdate.
+func (mr *MockSparepartStockRepositoryMockRecorder) GetWorkOrderForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkOrderForUpdate", reflect.TypeOf((*MockSparepartStockRepository)(nil).GetWorkOrderForUpdate), ctx, id)
+}
+
+// ListDamageReports mocks base method.
+func (m *MockSparepartStockRepository) ListDamageReports(ctx context.Context, arg db.ListDamageReportsParams) ([]db.ListDamageReportsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDamageReports", ctx, arg)
+	ret0, _ := ret[0].([]db.ListDamageReportsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDamageReports indicates an expected call of ListDamageReports.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListDamageReports(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDamageReports", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListDamageReports), ctx, arg)
+}
+
+// ListContactPersonsByLocations mocks base method.
+func (m *MockSparepartStockRepository) ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]db.ContactPerson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContactPersonsByLocations", ctx, locationIds)
+	ret0, _ := ret[0].([]db.ContactPerson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContactPersonsByLocations indicates an expected call of ListContactPersonsByLocations.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListContactPersonsByLocations(ctx, locationIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContactPersonsByLocations", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListContactPersonsByLocations), ctx, locationIds)
+}
+
+// ListExpiringWarranties mocks base method.
+func (m *MockSparepartStockRepository) ListExpiringWarranties(ctx context.Context, arg db.ListExpiringWarrantiesParams) ([]db.ListExpiringWarrantiesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiringWarranties", ctx, arg)
+	ret0, _ := ret[0].([]db.ListExpiringWarrantiesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiringWarranties indicates an expected call of ListExpiringWarranties.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListExpiringWarranties(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiringWarranties", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListExpiringWarranties), ctx, arg)
+}
+
+// ListGoodsReceiptItems mocks base method.
+func (m *MockSparepartStockRepository) ListGoodsReceiptItems(ctx context.Context, receiptID int32) ([]db.ListGoodsReceiptItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGoodsReceiptItems", ctx, receiptID)
+	ret0, _ := ret[0].([]db.ListGoodsReceiptItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGoodsReceiptItems indicates an expected call of ListGoodsReceiptItems.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListGoodsReceiptItems(ctx, receiptID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoodsReceiptItems", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListGoodsReceiptItems), ctx, receiptID)
+}
+
+// ListGoodsReceipts mocks base method.
+func (m *MockSparepartStockRepository) ListGoodsReceipts(ctx context.Context, arg db.ListGoodsReceiptsParams) ([]db.ListGoodsReceiptsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGoodsReceipts", ctx, arg)
+	ret0, _ := ret[0].([]db.ListGoodsReceiptsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGoodsReceipts indicates an expected call of ListGoodsReceipts.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListGoodsReceipts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoodsReceipts", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListGoodsReceipts), ctx, arg)
+}
+
+// ListLocationCompletenessByIDs mocks base method.
+func (m *MockSparepartStockRepository) ListLocationCompletenessByIDs(ctx context.Context, arg db.ListLocationCompletenessByIDsParams) ([]db.ListLocationCompletenessByIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocationCompletenessByIDs", ctx, arg)
+	ret0, _ := ret[0].([]db.ListLocationCompletenessByIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocationCompletenessByIDs indicates an expected call of ListLocationCompletenessByIDs.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListLocationCompletenessByIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocationCompletenessByIDs", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListLocationCompletenessByIDs), ctx, arg)
+}
+
+// ListLocationsForImport mocks base method.
+func (m *MockSparepartStockRepository) ListLocationsForImport(ctx context.Context, arg db.ListLocationsForImportParams) ([]db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocationsForImport", ctx, arg)
+	ret0, _ := ret[0].([]db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocationsForImport indicates an expected call of ListLocationsForImport.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListLocationsForImport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocationsForImport", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListLocationsForImport), ctx, arg)
+}
+
+// ListOpenDamageReportsForExport mocks base method.
+func (m *MockSparepartStockRepository) ListOpenDamageReportsForExport(ctx context.Context, arg db.ListOpenDamageReportsForExportParams) ([]db.ListOpenDamageReportsForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenDamageReportsForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListOpenDamageReportsForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenDamageReportsForExport indicates an expected call of ListOpenDamageReportsForExport.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListOpenDamageReportsForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenDamageReportsForExport", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListOpenDamageReportsForExport), ctx, arg)
+}
+
+// ListPurchaseOrderItems mocks base method.
+func (m *MockSparepartStockRepository) ListPurchaseOrderItems(ctx context.Context, orderID int32) ([]db.ListPurchaseOrderItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPurchaseOrderItems", ctx, orderID)
+	ret0, _ := ret[0].([]db.ListPurchaseOrderItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPurchaseOrderItems indicates an expected call of ListPurchaseOrderItems.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListPurchaseOrderItems(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPurchaseOrderItems", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListPurchaseOrderItems), ctx, orderID)
+}
+
+// ListPurchaseOrders mocks base method.
+func (m *MockSparepartStockRepository) ListPurchaseOrders(ctx context.Context, arg db.ListPurchaseOrdersParams) ([]db.ListPurchaseOrdersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPurchaseOrders", ctx, arg)
+	ret0, _ := ret[0].([]db.ListPurchaseOrdersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPurchaseOrders indicates an expected call of ListPurchaseOrders.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListPurchaseOrders(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPurchaseOrders", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListPurchaseOrders), ctx, arg)
+}
+
+// ListSparepartMastersByNames mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartMastersByNames(ctx context.Context, names []string) ([]db.ListSparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartMastersByNames", ctx, names)
+	ret0, _ := ret[0].([]db.ListSparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartMastersByNames indicates an expected call of ListSparepartMastersByNames.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartMastersByNames(ctx, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartMastersByNames", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartMastersByNames), ctx, names)
+}
+
+// ListSparepartRequestEvents mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartRequestEvents(ctx context.Context, requestID int32) ([]db.SparepartRequestEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartRequestEvents", ctx, requestID)
+	ret0, _ := ret[0].([]db.SparepartRequestEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartRequestEvents indicates an expected call of ListSparepartRequestEvents.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartRequestEvents(ctx, requestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartRequestEvents", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartRequestEvents), ctx, requestID)
+}
+
+// ListSparepartRequestItems mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartRequestItems(ctx context.Context, requestID int32) ([]db.ListSparepartRequestItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartRequestItems", ctx, requestID)
+	ret0, _ := ret[0].([]db.ListSparepartRequestItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartRequestItems indicates an expected call of ListSparepartRequestItems.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartRequestItems(ctx, requestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartRequestItems", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartRequestItems), ctx, requestID)
+}
+
+// ListSparepartRequests mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartRequests(ctx context.Context, arg db.ListSparepartRequestsParams) ([]db.ListSparepartRequestsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartRequests", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSparepartRequestsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartRequests indicates an expected call of ListSparepartRequests.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartRequests(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartRequests", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartRequests), ctx, arg)
+}
+
+// ListNearestStock mocks base method.
+func (m *MockSparepartStockRepository) ListNearestStock(ctx context.Context, arg db.ListNearestStockParams) ([]db.ListNearestStockRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNearestStock", ctx, arg)
+	ret0, _ := ret[0].([]db.ListNearestStockRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNearestStock indicates an expected call of ListNearestStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListNearestStock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNearestStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListNearestStock), ctx, arg)
+}
+
+// ListSparepartStockImportConflicts mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartStockImportConflicts(ctx context.Context) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStockImportConflicts", ctx)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStockImportConflicts indicates an expected call of ListSparepartStockImportConflicts.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartStockImportConflicts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStockImportConflicts", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartStockImportConflicts), ctx)
+}
+
+// ListSparepartStockItems mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartStockItems(ctx context.Context, arg db.ListSparepartStockItemsParams) ([]db.ListSparepartStockItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStockItems", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSparepartStockItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStockItems indicates an expected call of ListSparepartStockItems.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartStockItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStockItems", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartStockItems), ctx, arg)
+}
+
+// ListSparepartStocks mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartStocks(ctx context.Context, arg db.ListSparepartStocksParams) ([]db.ListSparepartStocksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStocks", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSparepartStocksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStocks indicates an expected call of ListSparepartStocks.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartStocks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStocks", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartStocks), ctx, arg)
+}
+
+// ListSparepartStocksByLocation mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartStocksByLocation(ctx context.Context, locationID int32) ([]db.ListSparepartStocksByLocationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStocksByLocation", ctx, locationID)
+	ret0, _ := ret[0].([]db.ListSparepartStocksByLocationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStocksByLocation indicates an expected call of ListSparepartStocksByLocation.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartStocksByLocation(ctx, locationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStocksByLocation", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartStocksByLocation), ctx, locationID)
+}
+
+// ListSparepartStocksForExport mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartStocksForExport(ctx context.Context, arg db.ListSparepartStocksForExportParams) ([]db.ListSparepartStocksForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStocksForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSparepartStocksForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStocksForExport indicates an expected call of ListSparepartStocksForExport.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartStocksForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStocksForExport", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartStocksForExport), ctx, arg)
+}
+
+// ListSparepartStocksForLabels mocks base method.
+func (m *MockSparepartStockRepository) ListSparepartStocksForLabels(ctx context.Context, arg db.ListSparepartStocksForLabelsParams) ([]db.ListSparepartStocksForLabelsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStocksForLabels", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSparepartStocksForLabelsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStocksForLabels indicates an expected call of ListSparepartStocksForLabels.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListSparepartStocksForLabels(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStocksForLabels", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListSparepartStocksForLabels), ctx, arg)
+}
+
+// ListStockDisposals mocks base method.
+func (m *MockSparepartStockRepository) ListStockDisposals(ctx context.Context, arg db.ListStockDisposalsParams) ([]db.ListStockDisposalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockDisposals", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockDisposalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockDisposals indicates an expected call of ListStockDisposals.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListStockDisposals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockDisposals", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListStockDisposals), ctx, arg)
+}
+
+// ListStockOpnameItems mocks base method.
+func (m *MockSparepartStockRepository) ListStockOpnameItems(ctx context.Context, opnameID int32) ([]db.ListStockOpnameItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockOpnameItems", ctx, opnameID)
+	ret0, _ := ret[0].([]db.ListStockOpnameItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockOpnameItems indicates an expected call of ListStockOpnameItems.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListStockOpnameItems(ctx, opnameID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockOpnameItems", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListStockOpnameItems), ctx, opnameID)
+}
+
+// ListStockOpnames mocks base method.
+func (m *MockSparepartStockRepository) ListStockOpnames(ctx context.Context, arg db.ListStockOpnamesParams) ([]db.ListStockOpnamesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockOpnames", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockOpnamesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockOpnames indicates an expected call of ListStockOpnames.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListStockOpnames(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockOpnames", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListStockOpnames), ctx, arg)
+}
+
+// ListStockTransfers mocks base method.
+func (m *MockSparepartStockRepository) ListStockTransfers(ctx context.Context, arg db.ListStockTransfersParams) ([]db.ListStockTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockTransfers", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockTransfers indicates an expected call of ListStockTransfers.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListStockTransfers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockTransfers", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListStockTransfers), ctx, arg)
+}
+
+// ListStockUnitConflicts mocks base method.
+func (m *MockSparepartStockRepository) ListStockUnitConflicts(ctx context.Context, arg db.ListStockUnitConflictsParams) ([]db.ListStockUnitConflictsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockUnitConflicts", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockUnitConflictsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockUnitConflicts indicates an expected call of ListStockUnitConflicts.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListStockUnitConflicts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockUnitConflicts", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListStockUnitConflicts), ctx, arg)
+}
+
+// ListStockUnits mocks base method.
+func (m *MockSparepartStockRepository) ListStockUnits(ctx context.Context, stockItemID int32) ([]db.StockUnit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockUnits", ctx, stockItemID)
+	ret0, _ := ret[0].([]db.StockUnit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockUnits indicates an expected call of ListStockUnits.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListStockUnits(ctx, stockItemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockUnits", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListStockUnits), ctx, stockItemID)
+}
+
+// ListWorkOrderItems mocks base method.
+func (m *MockSparepartStockRepository) ListWorkOrderItems(ctx context.Context, workOrderID int32) ([]db.ListWorkOrderItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkOrderItems", ctx, workOrderID)
+	ret0, _ := ret[0].([]db.ListWorkOrderItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkOrderItems indicates an expected call of ListWorkOrderItems.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListWorkOrderItems(ctx, workOrderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkOrderItems", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListWorkOrderItems), ctx, workOrderID)
+}
+
+// ListWorkOrderTools mocks base method.
+func (m *MockSparepartStockRepository) ListWorkOrderTools(ctx context.Context, workOrderID int32) ([]db.ListWorkOrderToolsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkOrderTools", ctx, workOrderID)
+	ret0, _ := ret[0].([]db.ListWorkOrderToolsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkOrderTools indicates an expected call of ListWorkOrderTools.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListWorkOrderTools(ctx, workOrderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkOrderTools", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListWorkOrderTools), ctx, workOrderID)
+}
+
+// ListWorkOrders mocks base method.
+func (m *MockSparepartStockRepository) ListWorkOrders(ctx context.Context, arg db.ListWorkOrdersParams) ([]db.ListWorkOrdersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkOrders", ctx, arg)
+	ret0, _ := ret[0].([]db.ListWorkOrdersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkOrders indicates an expected call of ListWorkOrders.
+func (mr *MockSparepartStockRepositoryMockRecorder) ListWorkOrders(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkOrders", reflect.TypeOf((*MockSparepartStockRepository)(nil).ListWorkOrders), ctx, arg)
+}
+
+// PlacePurchaseOrder mocks base method.
+func (m *MockSparepartStockRepository) PlacePurchaseOrder(ctx context.Context, arg db.PlacePurchaseOrderParams) (db.PurchaseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlacePurchaseOrder", ctx, arg)
+	ret0, _ := ret[0].(db.PurchaseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlacePurchaseOrder indicates an expected call of PlacePurchaseOrder.
+func (mr *MockSparepartStockRepositoryMockRecorder) PlacePurchaseOrder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlacePurchaseOrder", reflect.TypeOf((*MockSparepartStockRepository)(nil).PlacePurchaseOrder), ctx, arg)
+}
+
+// PurgeContactPersons mocks base method.
+func (m *MockSparepartStockRepository) PurgeContactPersons(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeContactPersons", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeContactPersons indicates an expected call of PurgeContactPersons.
+func (mr *MockSparepartStockRepositoryMockRecorder) PurgeContactPersons(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeContactPersons", reflect.TypeOf((*MockSparepartStockRepository)(nil).PurgeContactPersons), ctx, deletedBefore)
+}
+
+// PurgeLocations mocks base method.
+func (m *MockSparepartStockRepository) PurgeLocations(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeLocations", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeLocations indicates an expected call of PurgeLocations.
+func (mr *MockSparepartStockRepositoryMockRecorder) PurgeLocations(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeLocations", reflect.TypeOf((*MockSparepartStockRepository)(nil).PurgeLocations), ctx, deletedBefore)
+}
+
+// PurgeSparepartMasters mocks base method.
+func (m *MockSparepartStockRepository) PurgeSparepartMasters(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSparepartMasters", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeSparepartMasters indicates an expected call of PurgeSparepartMasters.
+func (mr *MockSparepartStockRepositoryMockRecorder) PurgeSparepartMasters(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSparepartMasters", reflect.TypeOf((*MockSparepartStockRepository)(nil).PurgeSparepartMasters), ctx, deletedBefore)
+}
+
+// PurgeSparepartStocks mocks base method.
+func (m *MockSparepartStockRepository) PurgeSparepartStocks(ctx context.Context, deletedBefore pgtype.Timestamptz) ([]db.PurgeSparepartStocksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSparepartStocks", ctx, deletedBefore)
+	ret0, _ := ret[0].([]db.PurgeSparepartStocksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeSparepartStocks indicates an expected call of PurgeSparepartStocks.
+func (mr *MockSparepartStockRepositoryMockRecorder) PurgeSparepartStocks(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSparepartStocks", reflect.TypeOf((*MockSparepartStockRepository)(nil).PurgeSparepartStocks), ctx, deletedBefore)
+}
+
+// PurgeToolsAlkers mocks base method.
+func (m *MockSparepartStockRepository) PurgeToolsAlkers(ctx context.Context, deletedBefore pgtype.Timestamptz) ([]db.PurgeToolsAlkersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeToolsAlkers", ctx, deletedBefore)
+	ret0, _ := ret[0].([]db.PurgeToolsAlkersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeToolsAlkers indicates an expected call of PurgeToolsAlkers.
+func (mr *MockSparepartStockRepositoryMockRecorder) PurgeToolsAlkers(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeToolsAlkers", reflect.TypeOf((*MockSparepartStockRepository)(nil).PurgeToolsAlkers), ctx, deletedBefore)
+}
+
+// ResolveDamageReport mocks base method.
+func (m *MockSparepartStockRepository) ResolveDamageReport(ctx context.Context, arg db.ResolveDamageReportParams) (db.DamageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveDamageReport", ctx, arg)
+	ret0, _ := ret[0].(db.DamageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveDamageReport indicates an expected call of ResolveDamageReport.
+func (mr *MockSparepartStockRepositoryMockRecorder) ResolveDamageReport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveDamageReport", reflect.TypeOf((*MockSparepartStockRepository)(nil).ResolveDamageReport), ctx, arg)
+}
+
+// RestoreSparepartStock mocks base method.
+func (m *MockSparepartStockRepository) RestoreSparepartStock(ctx context.Context, id int32) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSparepartStock", ctx, id)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSparepartStock indicates an expected call of RestoreSparepartStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) RestoreSparepartStock(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSparepartStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).RestoreSparepartStock), ctx, id)
+}
+
+// ScanStockUnit mocks base method.
+func (m *MockSparepartStockRepository) ScanStockUnit(ctx context.Context, code string) (db.ScanStockUnitRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanStockUnit", ctx, code)
+	ret0, _ := ret[0].(db.ScanStockUnitRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanStockUnit indicates an expected call of ScanStockUnit.
+func (mr *MockSparepartStockRepositoryMockRecorder) ScanStockUnit(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanStockUnit", reflect.TypeOf((*MockSparepartStockRepository)(nil).ScanStockUnit), ctx, code)
+}
+
+// SetGoodsReceiptItemStock mocks base method.
+func (m *MockSparepartStockRepository) SetGoodsReceiptItemStock(ctx context.Context, arg db.SetGoodsReceiptItemStockParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGoodsReceiptItemStock", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGoodsReceiptItemStock indicates an expected call of SetGoodsReceiptItemStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) SetGoodsReceiptItemStock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGoodsReceiptItemStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).SetGoodsReceiptItemStock), ctx, arg)
+}
+
+// SetSparepartRequestItemTransfer mocks base method.
+func (m *MockSparepartStockRepository) SetSparepartRequestItemTransfer(ctx context.Context, arg db.SetSparepartRequestItemTransferParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSparepartRequestItemTransfer", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSparepartRequestItemTransfer indicates an expected call of SetSparepartRequestItemTransfer.
+func (mr *MockSparepartStockRepositoryMockRecorder) SetSparepartRequestItemTransfer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSparepartRequestItemTransfer", reflect.TypeOf((*MockSparepartStockRepository)(nil).SetSparepartRequestItemTransfer), ctx, arg)
+}
+
+// SetSparepartRequestStatus mocks base method.
+func (m *MockSparepartStockRepository) SetSparepartRequestStatus(ctx context.Context, arg db.SetSparepartRequestStatusParams) (db.SparepartRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSparepartRequestStatus", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSparepartRequestStatus indicates an expected call of SetSparepartRequestStatus.
+func (mr *MockSparepartStockRepositoryMockRecorder) SetSparepartRequestStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSparepartRequestStatus", reflect.TypeOf((*MockSparepartStockRepository)(nil).SetSparepartRequestStatus), ctx, arg)
+}
+
+// SetStockOpnameItemAdjustment mocks base method.
+func (m *MockSparepartStockRepository) SetStockOpnameItemAdjustment(ctx context.Context, arg db.SetStockOpnameItemAdjustmentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStockOpnameItemAdjustment", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStockOpnameItemAdjustment indicates an expected call of SetStockOpnameItemAdjustment.
+func (mr *MockSparepartStockRepositoryMockRecorder) SetStockOpnameItemAdjustment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStockOpnameItemAdjustment", reflect.TypeOf((*MockSparepartStockRepository)(nil).SetStockOpnameItemAdjustment), ctx, arg)
+}
+
+// SetStockUnitWarranty mocks base method.
+func (m *MockSparepartStockRepository) SetStockUnitWarranty(ctx context.Context, arg db.SetStockUnitWarrantyParams) (db.StockUnit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStockUnitWarranty", ctx, arg)
+	ret0, _ := ret[0].(db.StockUnit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStockUnitWarranty indicates an expected call of SetStockUnitWarranty.
+func (mr *MockSparepartStockRepositoryMockRecorder) SetStockUnitWarranty(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStockUnitWarranty", reflect.TypeOf((*MockSparepartStockRepository)(nil).SetStockUnitWarranty), ctx, arg)
+}
+
+// SetWorkOrderItemConsumption mocks base method.
+func (m *MockSparepartStockRepository) SetWorkOrderItemConsumption(ctx context.Context, arg db.SetWorkOrderItemConsumptionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWorkOrderItemConsumption", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWorkOrderItemConsumption indicates an expected call of SetWorkOrderItemConsumption.
+func (mr *MockSparepartStockRepositoryMockRecorder) SetWorkOrderItemConsumption(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkOrderItemConsumption", reflect.TypeOf((*MockSparepartStockRepository)(nil).SetWorkOrderItemConsumption), ctx, arg)
+}
+
+// SubmitStockOpname mocks base method.
+func (m *MockSparepartStockRepository) SubmitStockOpname(ctx context.Context, arg db.SubmitStockOpnameParams) (db.StockOpname, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitStockOpname", ctx, arg)
+	ret0, _ := ret[0].(db.StockOpname)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitStockOpname indicates an expected call of SubmitStockOpname.
+func (mr *MockSparepartStockRepositoryMockRecorder) SubmitStockOpname(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitStockOpname", reflect.TypeOf((*MockSparepartStockRepository)(nil).SubmitStockOpname), ctx, arg)
+}
+
+// SummarizeStockDisposals mocks base method.
+func (m *MockSparepartStockRepository) SummarizeStockDisposals(ctx context.Context, arg db.SummarizeStockDisposalsParams) ([]db.SummarizeStockDisposalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SummarizeStockDisposals", ctx, arg)
+	ret0, _ := ret[0].([]db.SummarizeStockDisposalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SummarizeStockDisposals indicates an expected call of SummarizeStockDisposals.
+func (mr *MockSparepartStockRepositoryMockRecorder) SummarizeStockDisposals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SummarizeStockDisposals", reflect.TypeOf((*MockSparepartStockRepository)(nil).SummarizeStockDisposals), ctx, arg)
+}
+
+// TransferInSparepartStock mocks base method.
+func (m *MockSparepartStockRepository) TransferInSparepartStock(ctx context.Context, arg db.TransferInSparepartStockParams) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferInSparepartStock", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferInSparepartStock indicates an expected call of TransferInSparepartStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) TransferInSparepartStock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferInSparepartStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).TransferInSparepartStock), ctx, arg)
+}
+
+// TransferOutSparepartStock mocks base method.
+func (m *MockSparepartStockRepository) TransferOutSparepartStock(ctx context.Context, arg db.TransferOutSparepartStockParams) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferOutSparepartStock", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferOutSparepartStock indicates an expected call of TransferOutSparepartStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) TransferOutSparepartStock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOutSparepartStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).TransferOutSparepartStock), ctx, arg)
+}
+
+// UpdatePurchaseOrderReceivedStatus mocks base method.
+func (m *MockSparepartStockRepository) UpdatePurchaseOrderReceivedStatus(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePurchaseOrderReceivedStatus", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePurchaseOrderReceivedStatus indicates an expected call of UpdatePurchaseOrderReceivedStatus.
+func (mr *MockSparepartStockRepositoryMockRecorder) UpdatePurchaseOrderReceivedStatus(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePurchaseOrderReceivedStatus", reflect.TypeOf((*MockSparepartStockRepository)(nil).UpdatePurchaseOrderReceivedStatus), ctx, id)
+}
+
+// UpdateSparepartStock mocks base method.
+func (m *MockSparepartStockRepository) UpdateSparepartStock(ctx context.Context, arg db.UpdateSparepartStockParams) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSparepartStock", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSparepartStock indicates an expected call of UpdateSparepartStock.
+func (mr *MockSparepartStockRepositoryMockRecorder) UpdateSparepartStock(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSparepartStock", reflect.TypeOf((*MockSparepartStockRepository)(nil).UpdateSparepartStock), ctx, arg)
+}
+
+// UpdateSparepartStockDocumentation mocks base method.
+func (m *MockSparepartStockRepository) UpdateSparepartStockDocumentation(ctx context.Context, arg db.UpdateSparepartStockDocumentationParams) (db.SparepartStockItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSparepartStockDocumentation", ctx, arg)
+	ret0, _ := ret[0].(db.SparepartStockItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSparepartStockDocumentation indicates an expected call of UpdateSparepartStockDocumentation.
+func (mr *MockSparepartStockRepositoryMockRecorder) UpdateSparepartStockDocumentation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSparepartStockDocumentation", reflect.TypeOf((*MockSparepartStockRepository)(nil).UpdateSparepartStockDocumentation), ctx, arg)
+}
+
+// UpsertStockOpnameItem mocks base method.
+func (m *MockSparepartStockRepository) UpsertStockOpnameItem(ctx context.Context, arg db.UpsertStockOpnameItemParams) (db.StockOpnameItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertStockOpnameItem", ctx, arg)
+	ret0, _ := ret[0].(db.StockOpnameItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertStockOpnameItem indicates an expected call of UpsertStockOpnameItem.
+func (mr *MockSparepartStockRepositoryMockRecorder) UpsertStockOpnameItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertStockOpnameItem", reflect.TypeOf((*MockSparepartStockRepository)(nil).UpsertStockOpnameItem), ctx, arg)
+}
+
+// WithinTransaction mocks base method.
+func (m *MockSparepartStockRepository) WithinTransaction(ctx context.Context, fn func(repository.SparepartStockRepository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockSparepartStockRepositoryMockRecorder) WithinTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockSparepartStockRepository)(nil).WithinTransaction), ctx, fn)
+}
+
+// MockToolsAlkerRepository is a mock of ToolsAlkerRepository interface.
+type MockToolsAlkerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockToolsAlkerRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockToolsAlkerRepositoryMockRecorder is the mock recorder for MockToolsAlkerRepository.
+type MockToolsAlkerRepositoryMockRecorder struct {
+	mock *MockToolsAlkerRepository
+}
+
+// NewMockToolsAlkerRepository creates a new mock instance.
+func NewMockToolsAlkerRepository(ctrl *gomock.Controller) *MockToolsAlkerRepository {
+	mock := &MockToolsAlkerRepository{ctrl: ctrl}
+	mock.recorder = &MockToolsAlkerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockToolsAlkerRepository) EXPECT() *MockToolsAlkerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CheckInToolsAlkerCheckout mocks base method.
+func (m *MockToolsAlkerRepository) CheckInToolsAlkerCheckout(ctx context.Context, arg db.CheckInToolsAlkerCheckoutParams) (db.ToolsAlkerCheckout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckInToolsAlkerCheckout", ctx, arg)
+	ret0, _ := ret[0].(db.ToolsAlkerCheckout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckInToolsAlkerCheckout indicates an expected call of CheckInToolsAlkerCheckout.
+func (mr *MockToolsAlkerRepositoryMockRecorder) CheckInToolsAlkerCheckout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInToolsAlkerCheckout", reflect.TypeOf((*MockToolsAlkerRepository)(nil).CheckInToolsAlkerCheckout), ctx, arg)
+}
+
+// CountToolsAlkerCheckouts mocks base method.
+func (m *MockToolsAlkerRepository) CountToolsAlkerCheckouts(ctx context.Context, arg db.CountToolsAlkerCheckoutsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountToolsAlkerCheckouts", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountToolsAlkerCheckouts indicates an expected call of CountToolsAlkerCheckouts.
+func (mr *MockToolsAlkerRepositoryMockRecorder) CountToolsAlkerCheckouts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountToolsAlkerCheckouts", reflect.TypeOf((*MockToolsAlkerRepository)(nil).CountToolsAlkerCheckouts), ctx, arg)
+}
+
+// CountToolsAlkerItems mocks base method.
+func (m *MockToolsAlkerRepository) CountToolsAlkerItems(ctx context.Context, arg db.CountToolsAlkerItemsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountToolsAlkerItems", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountToolsAlkerItems indicates an expected call of CountToolsAlkerItems.
+func (mr *MockToolsAlkerRepositoryMockRecorder) CountToolsAlkerItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountToolsAlkerItems", reflect.TypeOf((*MockToolsAlkerRepository)(nil).CountToolsAlkerItems), ctx, arg)
+}
+
+// CountToolsAlkers mocks base method.
+func (m *MockToolsAlkerRepository) CountToolsAlkers(ctx context.Context, arg db.CountToolsAlkersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountToolsAlkers", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountToolsAlkers indicates an expected call of CountToolsAlkers.
+func (mr *MockToolsAlkerRepositoryMockRecorder) CountToolsAlkers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountToolsAlkers", reflect.TypeOf((*MockToolsAlkerRepository)(nil).CountToolsAlkers), ctx, arg)
+}
+
+// CreateToolsAlker mocks base method.
+func (m *MockToolsAlkerRepository) CreateToolsAlker(ctx context.Context, arg db.CreateToolsAlkerParams) (db.ToolsAlkerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToolsAlker", ctx, arg)
+	ret0, _ := ret[0].(db.ToolsAlkerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateToolsAlker indicates an expected call of CreateToolsAlker.
+func (mr *MockToolsAlkerRepositoryMockRecorder) CreateToolsAlker(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToolsAlker", reflect.TypeOf((*MockToolsAlkerRepository)(nil).CreateToolsAlker), ctx, arg)
+}
+
+// CreateToolsAlkerCheckout mocks base method.
+func (m *MockToolsAlkerRepository) CreateToolsAlkerCheckout(ctx context.Context, arg db.CreateToolsAlkerCheckoutParams) (db.ToolsAlkerCheckout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToolsAlkerCheckout", ctx, arg)
+	ret0, _ := ret[0].(db.ToolsAlkerCheckout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateToolsAlkerCheckout indicates an expected call of CreateToolsAlkerCheckout.
+func (mr *MockToolsAlkerRepositoryMockRecorder) CreateToolsAlkerCheckout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToolsAlkerCheckout", reflect.TypeOf((*MockToolsAlkerRepository)(nil).CreateToolsAlkerCheckout), ctx, arg)
+}
+
+// CreateToolsAlkersBatch mocks base method.
+func (m *MockToolsAlkerRepository) CreateToolsAlkersBatch(ctx context.Context, arg db.CreateToolsAlkersBatchParams) ([]db.ToolsAlkerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToolsAlkersBatch", ctx, arg)
+	ret0, _ := ret[0].([]db.ToolsAlkerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateToolsAlkersBatch indicates an expected call of CreateToolsAlkersBatch.
+func (mr *MockToolsAlkerRepositoryMockRecorder) CreateToolsAlkersBatch(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToolsAlkersBatch", reflect.TypeOf((*MockToolsAlkerRepository)(nil).CreateToolsAlkersBatch), ctx, arg)
+}
+
+// DeleteToolsAlker mocks base method.
+func (m *MockToolsAlkerRepository) DeleteToolsAlker(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToolsAlker", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteToolsAlker indicates an expected call of DeleteToolsAlker.
+func (mr *MockToolsAlkerRepositoryMockRecorder) DeleteToolsAlker(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToolsAlker", reflect.TypeOf((*MockToolsAlkerRepository)(nil).DeleteToolsAlker), ctx, id)
+}
+
+// GetTechnician mocks base method.
+func (m *MockToolsAlkerRepository) GetTechnician(ctx context.Context, id int32) (db.Technician, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTechnician", ctx, id)
+	ret0, _ := ret[0].(db.Technician)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTechnician indicates an expected call of GetTechnician.
+func (mr *MockToolsAlkerRepositoryMockRecorder) GetTechnician(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTechnician", reflect.TypeOf((*MockToolsAlkerRepository)(nil).GetTechnician), ctx, id)
+}
+
+// GetToolsAlker mocks base method.
+func (m *MockToolsAlkerRepository) GetToolsAlker(ctx context.Context, id int32) (db.GetToolsAlkerRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetToolsAlker", ctx, id)
+	ret0, _ := ret[0].(db.GetToolsAlkerRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetToolsAlker indicates an expected call of GetToolsAlker.
+func (mr *MockToolsAlkerRepositoryMockRecorder) GetToolsAlker(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToolsAlker", reflect.TypeOf((*MockToolsAlkerRepository)(nil).GetToolsAlker), ctx, id)
+}
+
+// GetToolsAlkerCheckout mocks base method.
+func (m *MockToolsAlkerRepository) GetToolsAlkerCheckout(ctx context.Context, id int32) (db.GetToolsAlkerCheckoutRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetToolsAlkerCheckout", ctx, id)
+	ret0, _ := ret[0].(db.GetToolsAlkerCheckoutRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetToolsAlkerCheckout indicates an expected call of GetToolsAlkerCheckout.
+func (mr *MockToolsAlkerRepositoryMockRecorder) GetToolsAlkerCheckout(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToolsAlkerCheckout", reflect.TypeOf((*MockToolsAlkerRepository)(nil).GetToolsAlkerCheckout), ctx, id)
+}
+
+// GetToolsAlkerForUpdate mocks base method.
+func (m *MockToolsAlkerRepository) GetToolsAlkerForUpdate(ctx context.Context, id int32) (db.ToolsAlkerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetToolsAlkerForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.ToolsAlkerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetToolsAlkerForUpdate indicates an expected call of GetToolsAlkerForUpdate.
+func (mr *MockToolsAlkerRepositoryMockRecorder) GetToolsAlkerForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToolsAlkerForUpdate", reflect.TypeOf((*MockToolsAlkerRepository)(nil).GetToolsAlkerForUpdate), ctx, id)
+}
+
+// ListToolsAlkerCheckouts mocks base method.
+func (m *MockToolsAlkerRepository) ListToolsAlkerCheckouts(ctx context.Context, arg db.ListToolsAlkerCheckoutsParams) ([]db.ListToolsAlkerCheckoutsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolsAlkerCheckouts", ctx, arg)
+	ret0, _ := ret[0].([]db.ListToolsAlkerCheckoutsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolsAlkerCheckouts indicates an expected call of ListToolsAlkerCheckouts.
+func (mr *MockToolsAlkerRepositoryMockRecorder) ListToolsAlkerCheckouts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolsAlkerCheckouts", reflect.TypeOf((*MockToolsAlkerRepository)(nil).ListToolsAlkerCheckouts), ctx, arg)
+}
+
+// ListToolsAlkerItems mocks base method.
+func (m *MockToolsAlkerRepository) ListToolsAlkerItems(ctx context.Context, arg db.ListToolsAlkerItemsParams) ([]db.ListToolsAlkerItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolsAlkerItems", ctx, arg)
+	ret0, _ := ret[0].([]db.ListToolsAlkerItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolsAlkerItems indicates an expected call of ListToolsAlkerItems.
+func (mr *MockToolsAlkerRepositoryMockRecorder) ListToolsAlkerItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolsAlkerItems", reflect.TypeOf((*MockToolsAlkerRepository)(nil).ListToolsAlkerItems), ctx, arg)
+}
+
+// ListToolsAlkers mocks base method.
+func (m *MockToolsAlkerRepository) ListToolsAlkers(ctx context.Context, arg db.ListToolsAlkersParams) ([]db.ListToolsAlkersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolsAlkers", ctx, arg)
+	ret0, _ := ret[0].([]db.ListToolsAlkersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolsAlkers indicates an expected call of ListToolsAlkers.
+func (mr *MockToolsAlkerRepositoryMockRecorder) ListToolsAlkers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolsAlkers", reflect.TypeOf((*MockToolsAlkerRepository)(nil).ListToolsAlkers), ctx, arg)
+}
+
+// ListContactPersonsByLocations mocks base method.
+func (m *MockToolsAlkerRepository) ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]db.ContactPerson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContactPersonsByLocations", ctx, locationIds)
+	ret0, _ := ret[0].([]db.ContactPerson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContactPersonsByLocations indicates an expected call of ListContactPersonsByLocations.
+func (mr *MockToolsAlkerRepositoryMockRecorder) ListContactPersonsByLocations(ctx, locationIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContactPersonsByLocations", reflect.TypeOf((*MockToolsAlkerRepository)(nil).ListContactPersonsByLocations), ctx, locationIds)
+}
+
+// ListToolsAlkersByLocation mocks base method.
+func (m *MockToolsAlkerRepository) ListToolsAlkersByLocation(ctx context.Context, locationID int32) ([]db.ListToolsAlkersByLocationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolsAlkersByLocation", ctx, locationID)
+	ret0, _ := ret[0].([]db.ListToolsAlkersByLocationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolsAlkersByLocation indicates an expected call of ListToolsAlkersByLocation.
+func (mr *MockToolsAlkerRepositoryMockRecorder) ListToolsAlkersByLocation(ctx, locationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolsAlkersByLocation", reflect.TypeOf((*MockToolsAlkerRepository)(nil).ListToolsAlkersByLocation), ctx, locationID)
+}
+
+// ListToolsAlkersForExport mocks base method.
+func (m *MockToolsAlkerRepository) ListToolsAlkersForExport(ctx context.Context, arg db.ListToolsAlkersForExportParams) ([]db.ListToolsAlkersForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolsAlkersForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListToolsAlkersForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolsAlkersForExport indicates an expected call of ListToolsAlkersForExport.
+func (mr *MockToolsAlkerRepositoryMockRecorder) ListToolsAlkersForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolsAlkersForExport", reflect.TypeOf((*MockToolsAlkerRepository)(nil).ListToolsAlkersForExport), ctx, arg)
+}
+
+// RestoreToolsAlker mocks base method.
+func (m *MockToolsAlkerRepository) RestoreToolsAlker(ctx context.Context, id int32) (db.ToolsAlkerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreToolsAlker", ctx, id)
+	ret0, _ := ret[0].(db.ToolsAlkerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreToolsAlker indicates an expected call of RestoreToolsAlker.
+func (mr *MockToolsAlkerRepositoryMockRecorder) RestoreToolsAlker(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreToolsAlker", reflect.TypeOf((*MockToolsAlkerRepository)(nil).RestoreToolsAlker), ctx, id)
+}
+
+// SumOpenToolsAlkerCheckouts mocks base method.
+func (m *MockToolsAlkerRepository) SumOpenToolsAlkerCheckouts(ctx context.Context, toolsAlkerItemID int32) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumOpenToolsAlkerCheckouts", ctx, toolsAlkerItemID)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumOpenToolsAlkerCheckouts indicates an expected call of SumOpenToolsAlkerCheckouts.
+func (mr *MockToolsAlkerRepositoryMockRecorder) SumOpenToolsAlkerCheckouts(ctx, toolsAlkerItemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumOpenToolsAlkerCheckouts", reflect.TypeOf((*MockToolsAlkerRepository)(nil).SumOpenToolsAlkerCheckouts), ctx, toolsAlkerItemID)
+}
+
+// UpdateToolsAlker mocks base method.
+func (m *MockToolsAlkerRepository) UpdateToolsAlker(ctx context.Context, arg db.UpdateToolsAlkerParams) (db.ToolsAlkerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateToolsAlker", ctx, arg)
+	ret0, _ := ret[0].(db.ToolsAlkerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateToolsAlker indicates an expected call of UpdateToolsAlker.
+func (mr *MockToolsAlkerRepositoryMockRecorder) UpdateToolsAlker(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToolsAlker", reflect.TypeOf((*MockToolsAlkerRepository)(nil).UpdateToolsAlker), ctx, arg)
+}
+
+// UpdateToolsAlkerDocumentation mocks base method.
+func (m *MockToolsAlkerRepository) UpdateToolsAlkerDocumentation(ctx context.Context, arg db.UpdateToolsAlkerDocumentationParams) (db.ToolsAlkerItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateToolsAlkerDocumentation", ctx, arg)
+	ret0, _ := ret[0].(db.ToolsAlkerItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateToolsAlkerDocumentation indicates an expected call of UpdateToolsAlkerDocumentation.
+func (mr *MockToolsAlkerRepositoryMockRecorder) UpdateToolsAlkerDocumentation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToolsAlkerDocumentation", reflect.TypeOf((*MockToolsAlkerRepository)(nil).UpdateToolsAlkerDocumentation), ctx, arg)
+}
+
+// WithinToolsAlkerTransaction mocks base method.
+func (m *MockToolsAlkerRepository) WithinToolsAlkerTransaction(ctx context.Context, fn func(repository.ToolsAlkerRepository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinToolsAlkerTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinToolsAlkerTransaction indicates an expected call of WithinToolsAlkerTransaction.
+func (mr *MockToolsAlkerRepositoryMockRecorder) WithinToolsAlkerTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinToolsAlkerTransaction", reflect.TypeOf((*MockToolsAlkerRepository)(nil).WithinToolsAlkerTransaction), ctx, fn)
+}
+
+// MockStockSummaryRepository is a mock of StockSummaryRepository interface.
+type MockStockSummaryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockSummaryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStockSummaryRepositoryMockRecorder is the mock recorder for MockStockSummaryRepository.
+type MockStockSummaryRepositoryMockRecorder struct {
+	mock *MockStockSummaryRepository
+}
+
+// NewMockStockSummaryRepository creates a new mock instance.
+func NewMockStockSummaryRepository(ctrl *gomock.Controller) *MockStockSummaryRepository {
+	mock := &MockStockSummaryRepository{ctrl: ctrl}
+	mock.recorder = &MockStockSummaryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockSummaryRepository) EXPECT() *MockStockSummaryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountStockMovements mocks base method.
+func (m *MockStockSummaryRepository) CountStockMovements(ctx context.Context, arg db.CountStockMovementsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStockMovements", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStockMovements indicates an expected call of CountStockMovements.
+func (mr *MockStockSummaryRepositoryMockRecorder) CountStockMovements(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStockMovements", reflect.TypeOf((*MockStockSummaryRepository)(nil).CountStockMovements), ctx, arg)
+}
+
+// ListStockConsumption mocks base method.
+func (m *MockStockSummaryRepository) ListStockConsumption(ctx context.Context, arg db.ListStockConsumptionParams) ([]db.ListStockConsumptionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockConsumption", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockConsumptionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockConsumption indicates an expected call of ListStockConsumption.
+func (mr *MockStockSummaryRepositoryMockRecorder) ListStockConsumption(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockConsumption", reflect.TypeOf((*MockStockSummaryRepository)(nil).ListStockConsumption), ctx, arg)
+}
+
+// ListStockMovements mocks base method.
+func (m *MockStockSummaryRepository) ListStockMovements(ctx context.Context, arg db.ListStockMovementsParams) ([]db.ListStockMovementsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockMovements", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockMovementsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockMovements indicates an expected call of ListStockMovements.
+func (mr *MockStockSummaryRepositoryMockRecorder) ListStockMovements(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockMovements", reflect.TypeOf((*MockStockSummaryRepository)(nil).ListStockMovements), ctx, arg)
+}
+
+// ListStockSnapshotTrend mocks base method.
+func (m *MockStockSummaryRepository) ListStockSnapshotTrend(ctx context.Context, arg db.ListStockSnapshotTrendParams) ([]db.ListStockSnapshotTrendRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockSnapshotTrend", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockSnapshotTrendRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockSnapshotTrend indicates an expected call of ListStockSnapshotTrend.
+func (mr *MockStockSummaryRepositoryMockRecorder) ListStockSnapshotTrend(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockSnapshotTrend", reflect.TypeOf((*MockStockSummaryRepository)(nil).ListStockSnapshotTrend), ctx, arg)
+}
+
+// ListStockSummaryByLocation mocks base method.
+func (m *MockStockSummaryRepository) ListStockSummaryByLocation(ctx context.Context, arg db.ListStockSummaryByLocationParams) ([]db.StockSummaryByLocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockSummaryByLocation", ctx, arg)
+	ret0, _ := ret[0].([]db.StockSummaryByLocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockSummaryByLocation indicates an expected call of ListStockSummaryByLocation.
+func (mr *MockStockSummaryRepositoryMockRecorder) ListStockSummaryByLocation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockSummaryByLocation", reflect.TypeOf((*MockStockSummaryRepository)(nil).ListStockSummaryByLocation), ctx, arg)
+}
+
+// ListStockSummaryByRegion mocks base method.
+func (m *MockStockSummaryRepository) ListStockSummaryByRegion(ctx context.Context) ([]db.StockSummaryByRegion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockSummaryByRegion", ctx)
+	ret0, _ := ret[0].([]db.StockSummaryByRegion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockSummaryByRegion indicates an expected call of ListStockSummaryByRegion.
+func (mr *MockStockSummaryRepositoryMockRecorder) ListStockSummaryByRegion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockSummaryByRegion", reflect.TypeOf((*MockStockSummaryRepository)(nil).ListStockSummaryByRegion), ctx)
+}
+
+// ListStockSummaryBySparepart mocks base method.
+func (m *MockStockSummaryRepository) ListStockSummaryBySparepart(ctx context.Context) ([]db.StockSummaryBySparepart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockSummaryBySparepart", ctx)
+	ret0, _ := ret[0].([]db.StockSummaryBySparepart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockSummaryBySparepart indicates an expected call of ListStockSummaryBySparepart.
+func (mr *MockStockSummaryRepositoryMockRecorder) ListStockSummaryBySparepart(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockSummaryBySparepart", reflect.TypeOf((*MockStockSummaryRepository)(nil).ListStockSummaryBySparepart), ctx)
+}
+
+// ListStockTrend mocks base method.
+func (m *MockStockSummaryRepository) ListStockTrend(ctx context.Context, arg db.ListStockTrendParams) ([]db.ListStockTrendRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockTrend", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockTrendRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockTrend indicates an expected call of ListStockTrend.
+func (mr *MockStockSummaryRepositoryMockRecorder) ListStockTrend(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockTrend", reflect.TypeOf((*MockStockSummaryRepository)(nil).ListStockTrend), ctx, arg)
+}
+
+// ListStockValuation mocks base method.
+func (m *MockStockSummaryRepository) ListStockValuation(ctx context.Context, arg db.ListStockValuationParams) ([]db.ListStockValuationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockValuation", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockValuationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockValuation indicates an expected call of ListStockValuation.
+func (mr *MockStockSummaryRepositoryMockRecorder) ListStockValuation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockValuation", reflect.TypeOf((*MockStockSummaryRepository)(nil).ListStockValuation), ctx, arg)
+}
+
+// MockStockLevelRepository is a mock of StockLevelRepository interface.
+type MockStockLevelRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockLevelRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStockLevelRepositoryMockRecorder is the mock recorder for MockStockLevelRepository.
+type MockStockLevelRepositoryMockRecorder struct {
+	mock *MockStockLevelRepository
+}
+
+// NewMockStockLevelRepository creates a new mock instance.
+func NewMockStockLevelRepository(ctrl *gomock.Controller) *MockStockLevelRepository {
+	mock := &MockStockLevelRepository{ctrl: ctrl}
+	mock.recorder = &MockStockLevelRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockLevelRepository) EXPECT() *MockStockLevelRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountStockLevels mocks base method.
+func (m *MockStockLevelRepository) CountStockLevels(ctx context.Context, arg db.CountStockLevelsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStockLevels", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStockLevels indicates an expected call of CountStockLevels.
+func (mr *MockStockLevelRepositoryMockRecorder) CountStockLevels(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStockLevels", reflect.TypeOf((*MockStockLevelRepository)(nil).CountStockLevels), ctx, arg)
+}
+
+// DeleteStockLevel mocks base method.
+func (m *MockStockLevelRepository) DeleteStockLevel(ctx context.Context, id int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStockLevel", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStockLevel indicates an expected call of DeleteStockLevel.
+func (mr *MockStockLevelRepositoryMockRecorder) DeleteStockLevel(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStockLevel", reflect.TypeOf((*MockStockLevelRepository)(nil).DeleteStockLevel), ctx, id)
+}
+
+// ListStockLevelPlanning mocks base method.
+func (m *MockStockLevelRepository) ListStockLevelPlanning(ctx context.Context, arg db.ListStockLevelPlanningParams) ([]db.ListStockLevelPlanningRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockLevelPlanning", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockLevelPlanningRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockLevelPlanning indicates an expected call of ListStockLevelPlanning.
+func (mr *MockStockLevelRepositoryMockRecorder) ListStockLevelPlanning(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockLevelPlanning", reflect.TypeOf((*MockStockLevelRepository)(nil).ListStockLevelPlanning), ctx, arg)
+}
+
+// ListStockLevels mocks base method.
+func (m *MockStockLevelRepository) ListStockLevels(ctx context.Context, arg db.ListStockLevelsParams) ([]db.ListStockLevelsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockLevels", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockLevelsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockLevels indicates an expected call of ListStockLevels.
+func (mr *MockStockLevelRepositoryMockRecorder) ListStockLevels(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockLevels", reflect.TypeOf((*MockStockLevelRepository)(nil).ListStockLevels), ctx, arg)
+}
+
+// UpsertStockLevel mocks base method.
+func (m *MockStockLevelRepository) UpsertStockLevel(ctx context.Context, arg db.UpsertStockLevelParams) (db.StockLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertStockLevel", ctx, arg)
+	ret0, _ := ret[0].(db.StockLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertStockLevel indicates an expected call of UpsertStockLevel.
+func (mr *MockStockLevelRepositoryMockRecorder) UpsertStockLevel(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertStockLevel", reflect.TypeOf((*MockStockLevelRepository)(nil).UpsertStockLevel), ctx, arg)
+}
+
+// MockDashboardRepository is a mock of DashboardRepository interface.
+type MockDashboardRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDashboardRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDashboardRepositoryMockRecorder is the mock recorder for MockDashboardRepository.
+type MockDashboardRepositoryMockRecorder struct {
+	mock *MockDashboardRepository
+}
+
+// NewMockDashboardRepository creates a new mock instance.
+func NewMockDashboardRepository(ctrl *gomock.Controller) *MockDashboardRepository {
+	mock := &MockDashboardRepository{ctrl: ctrl}
+	mock.recorder = &MockDashboardRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDashboardRepository) EXPECT() *MockDashboardRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetDashboardKPIs mocks base method.
+func (m *MockDashboardRepository) GetDashboardKPIs(ctx context.Context) (db.GetDashboardKPIsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDashboardKPIs", ctx)
+	ret0, _ := ret[0].(db.GetDashboardKPIsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDashboardKPIs indicates an expected call of GetDashboardKPIs.
+func (mr *MockDashboardRepositoryMockRecorder) GetDashboardKPIs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDashboardKPIs", reflect.TypeOf((*MockDashboardRepository)(nil).GetDashboardKPIs), ctx)
+}
+
+// GetStockSummaryTotals mocks base method.
+func (m *MockDashboardRepository) GetStockSummaryTotals(ctx context.Context) (db.GetStockSummaryTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockSummaryTotals", ctx)
+	ret0, _ := ret[0].(db.GetStockSummaryTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockSummaryTotals indicates an expected call of GetStockSummaryTotals.
+func (mr *MockDashboardRepositoryMockRecorder) GetStockSummaryTotals(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockSummaryTotals", reflect.TypeOf((*MockDashboardRepository)(nil).GetStockSummaryTotals), ctx)
+}
+
+// ListLowestQuantityStockItems mocks base method.
+func (m *MockDashboardRepository) ListLowestQuantityStockItems(ctx context.Context, limit int32) ([]db.ListLowestQuantityStockItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLowestQuantityStockItems", ctx, limit)
+	ret0, _ := ret[0].([]db.ListLowestQuantityStockItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLowestQuantityStockItems indicates an expected call of ListLowestQuantityStockItems.
+func (mr *MockDashboardRepositoryMockRecorder) ListLowestQuantityStockItems(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLowestQuantityStockItems", reflect.TypeOf((*MockDashboardRepository)(nil).ListLowestQuantityStockItems), ctx, limit)
+}
+
+// ListStockQuantityByType mocks base method.
+func (m *MockDashboardRepository) ListStockQuantityByType(ctx context.Context) ([]db.ListStockQuantityByTypeRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockQuantityByType", ctx)
+	ret0, _ := ret[0].([]db.ListStockQuantityByTypeRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockQuantityByType indicates an expected call of ListStockQuantityByType.
+func (mr *MockDashboardRepositoryMockRecorder) ListStockQuantityByType(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockQuantityByType", reflect.TypeOf((*MockDashboardRepository)(nil).ListStockQuantityByType), ctx)
+}
+
+// ListStockTotalsByArea mocks base method.
+func (m *MockDashboardRepository) ListStockTotalsByArea(ctx context.Context) ([]db.ListStockTotalsByAreaRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockTotalsByArea", ctx)
+	ret0, _ := ret[0].([]db.ListStockTotalsByAreaRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockTotalsByArea indicates an expected call of ListStockTotalsByArea.
+func (mr *MockDashboardRepositoryMockRecorder) ListStockTotalsByArea(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockTotalsByArea", reflect.TypeOf((*MockDashboardRepository)(nil).ListStockTotalsByArea), ctx)
+}
+
+// ListStockTotalsByItemType mocks base method.
+func (m *MockDashboardRepository) ListStockTotalsByItemType(ctx context.Context) ([]db.ListStockTotalsByItemTypeRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockTotalsByItemType", ctx)
+	ret0, _ := ret[0].([]db.ListStockTotalsByItemTypeRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockTotalsByItemType indicates an expected call of ListStockTotalsByItemType.
+func (mr *MockDashboardRepositoryMockRecorder) ListStockTotalsByItemType(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockTotalsByItemType", reflect.TypeOf((*MockDashboardRepository)(nil).ListStockTotalsByItemType), ctx)
+}
+
+// MockChangeHistoryRepository is a mock of ChangeHistoryRepository interface.
+type MockChangeHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeHistoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockChangeHistoryRepositoryMockRecorder is the mock recorder for MockChangeHistoryRepository.
+type MockChangeHistoryRepositoryMockRecorder struct {
+	mock *MockChangeHistoryRepository
+}
+
+// NewMockChangeHistoryRepository creates a new mock instance.
+func NewMockChangeHistoryRepository(ctrl *gomock.Controller) *MockChangeHistoryRepository {
+	mock := &MockChangeHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockChangeHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChangeHistoryRepository) EXPECT() *MockChangeHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountRecordChanges mocks base method.
+func (m *MockChangeHistoryRepository) CountRecordChanges(ctx context.Context, arg db.CountRecordChangesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRecordChanges", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecordChanges indicates an expected call of CountRecordChanges.
+func (mr *MockChangeHistoryRepositoryMockRecorder) CountRecordChanges(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecordChanges", reflect.TypeOf((*MockChangeHistoryRepository)(nil).CountRecordChanges), ctx, arg)
+}
+
+// CountStockHistory mocks base method.
+func (m *MockChangeHistoryRepository) CountStockHistory(ctx context.Context, arg db.CountStockHistoryParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStockHistory", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStockHistory indicates an expected call of CountStockHistory.
+func (mr *MockChangeHistoryRepositoryMockRecorder) CountStockHistory(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStockHistory", reflect.TypeOf((*MockChangeHistoryRepository)(nil).CountStockHistory), ctx, arg)
+}
+
+// ListRecordChanges mocks base method.
+func (m *MockChangeHistoryRepository) ListRecordChanges(ctx context.Context, arg db.ListRecordChangesParams) ([]db.ListRecordChangesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecordChanges", ctx, arg)
+	ret0, _ := ret[0].([]db.ListRecordChangesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecordChanges indicates an expected call of ListRecordChanges.
+func (mr *MockChangeHistoryRepositoryMockRecorder) ListRecordChanges(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordChanges", reflect.TypeOf((*MockChangeHistoryRepository)(nil).ListRecordChanges), ctx, arg)
+}
+
+// ListStockHistory mocks base method.
+func (m *MockChangeHistoryRepository) ListStockHistory(ctx context.Context, arg db.ListStockHistoryParams) ([]db.ListStockHistoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockHistory", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStockHistoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockHistory indicates an expected call of ListStockHistory.
+func (mr *MockChangeHistoryRepositoryMockRecorder) ListStockHistory(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockHistory", reflect.TypeOf((*MockChangeHistoryRepository)(nil).ListStockHistory), ctx, arg)
+}
+
+// MockAnomalyRepository is a mock of AnomalyRepository interface.
+type MockAnomalyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnomalyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAnomalyRepositoryMockRecorder is the mock recorder for MockAnomalyRepository.
+type MockAnomalyRepositoryMockRecorder struct {
+	mock *MockAnomalyRepository
+}
+
+// NewMockAnomalyRepository creates a new mock instance.
+func NewMockAnomalyRepository(ctrl *gomock.Controller) *MockAnomalyRepository {
+	mock := &MockAnomalyRepository{ctrl: ctrl}
+	mock.recorder = &MockAnomalyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnomalyRepository) EXPECT() *MockAnomalyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcknowledgeAnomaly mocks base method.
+func (m *MockAnomalyRepository) AcknowledgeAnomaly(ctx context.Context, arg db.AcknowledgeAnomalyParams) (db.Anomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeAnomaly", ctx, arg)
+	ret0, _ := ret[0].(db.Anomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcknowledgeAnomaly indicates an expected call of AcknowledgeAnomaly.
+func (mr *MockAnomalyRepositoryMockRecorder) AcknowledgeAnomaly(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeAnomaly", reflect.TypeOf((*MockAnomalyRepository)(nil).AcknowledgeAnomaly), ctx, arg)
+}
+
+// CountAnomalies mocks base method.
+func (m *MockAnomalyRepository) CountAnomalies(ctx context.Context, arg db.CountAnomaliesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAnomalies", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAnomalies indicates an expected call of CountAnomalies.
+func (mr *MockAnomalyRepositoryMockRecorder) CountAnomalies(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAnomalies", reflect.TypeOf((*MockAnomalyRepository)(nil).CountAnomalies), ctx, arg)
+}
+
+// GetAnomaly mocks base method.
+func (m *MockAnomalyRepository) GetAnomaly(ctx context.Context, id int64) (db.Anomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnomaly", ctx, id)
+	ret0, _ := ret[0].(db.Anomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnomaly indicates an expected call of GetAnomaly.
+func (mr *MockAnomalyRepositoryMockRecorder) GetAnomaly(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnomaly", reflect.TypeOf((*MockAnomalyRepository)(nil).GetAnomaly), ctx, id)
+}
+
+// ListAnomalies mocks base method.
+func (m *MockAnomalyRepository) ListAnomalies(ctx context.Context, arg db.ListAnomaliesParams) ([]db.Anomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAnomalies", ctx, arg)
+	ret0, _ := ret[0].([]db.Anomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnomalies indicates an expected call of ListAnomalies.
+func (mr *MockAnomalyRepositoryMockRecorder) ListAnomalies(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnomalies", reflect.TypeOf((*MockAnomalyRepository)(nil).ListAnomalies), ctx, arg)
+}
+
+// ResolveAnomaly mocks base method.
+func (m *MockAnomalyRepository) ResolveAnomaly(ctx context.Context, arg db.ResolveAnomalyParams) (db.Anomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAnomaly", ctx, arg)
+	ret0, _ := ret[0].(db.Anomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveAnomaly indicates an expected call of ResolveAnomaly.
+func (mr *MockAnomalyRepositoryMockRecorder) ResolveAnomaly(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAnomaly", reflect.TypeOf((*MockAnomalyRepository)(nil).ResolveAnomaly), ctx, arg)
+}
+
+// MockAlertRuleRepository is a mock of AlertRuleRepository interface.
+type MockAlertRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertRuleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertRuleRepositoryMockRecorder is the mock recorder for MockAlertRuleRepository.
+type MockAlertRuleRepositoryMockRecorder struct {
+	mock *MockAlertRuleRepository
+}
+
+// NewMockAlertRuleRepository creates a new mock instance.
+func NewMockAlertRuleRepository(ctrl *gomock.Controller) *MockAlertRuleRepository {
+	mock := &MockAlertRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockAlertRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertRuleRepository) EXPECT() *MockAlertRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountAlertRules mocks base method.
+func (m *MockAlertRuleRepository) CountAlertRules(ctx context.Context, enabled pgtype.Bool) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAlertRules", ctx, enabled)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAlertRules indicates an expected call of CountAlertRules.
+func (mr *MockAlertRuleRepositoryMockRecorder) CountAlertRules(ctx, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAlertRules", reflect.TypeOf((*MockAlertRuleRepository)(nil).CountAlertRules), ctx, enabled)
+}
+
+// CreateAlertRule mocks base method.
+func (m *MockAlertRuleRepository) CreateAlertRule(ctx context.Context, arg db.CreateAlertRuleParams) (db.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlertRule", ctx, arg)
+	ret0, _ := ret[0].(db.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAlertRule indicates an expected call of CreateAlertRule.
+func (mr *MockAlertRuleRepositoryMockRecorder) CreateAlertRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlertRule", reflect.TypeOf((*MockAlertRuleRepository)(nil).CreateAlertRule), ctx, arg)
+}
+
+// DeleteAlertRule mocks base method.
+func (m *MockAlertRuleRepository) DeleteAlertRule(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlertRule", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlertRule indicates an expected call of DeleteAlertRule.
+func (mr *MockAlertRuleRepositoryMockRecorder) DeleteAlertRule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertRule", reflect.TypeOf((*MockAlertRuleRepository)(nil).DeleteAlertRule), ctx, id)
+}
+
+// GetAlertRule mocks base method.
+func (m *MockAlertRuleRepository) GetAlertRule(ctx context.Context, id int32) (db.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlertRule", ctx, id)
+	ret0, _ := ret[0].(db.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlertRule indicates an expected call of GetAlertRule.
+func (mr *MockAlertRuleRepositoryMockRecorder) GetAlertRule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlertRule", reflect.TypeOf((*MockAlertRuleRepository)(nil).GetAlertRule), ctx, id)
+}
+
+// ListAlertRules mocks base method.
+func (m *MockAlertRuleRepository) ListAlertRules(ctx context.Context, arg db.ListAlertRulesParams) ([]db.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertRules", ctx, arg)
+	ret0, _ := ret[0].([]db.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertRules indicates an expected call of ListAlertRules.
+func (mr *MockAlertRuleRepositoryMockRecorder) ListAlertRules(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertRules", reflect.TypeOf((*MockAlertRuleRepository)(nil).ListAlertRules), ctx, arg)
+}
+
+// UpdateAlertRule mocks base method.
+func (m *MockAlertRuleRepository) UpdateAlertRule(ctx context.Context, arg db.UpdateAlertRuleParams) (db.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlertRule", ctx, arg)
+	ret0, _ := ret[0].(db.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAlertRule indicates an expected call of UpdateAlertRule.
+func (mr *MockAlertRuleRepositoryMockRecorder) UpdateAlertRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlertRule", reflect.TypeOf((*MockAlertRuleRepository)(nil).UpdateAlertRule), ctx, arg)
+}
+
+// MockAlertEvaluationRepository is a mock of AlertEvaluationRepository interface.
+type MockAlertEvaluationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertEvaluationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertEvaluationRepositoryMockRecorder is the mock recorder for MockAlertEvaluationRepository.
+type MockAlertEvaluationRepositoryMockRecorder struct {
+	mock *MockAlertEvaluationRepository
+}
+
+// NewMockAlertEvaluationRepository creates a new mock instance.
+func NewMockAlertEvaluationRepository(ctrl *gomock.Controller) *MockAlertEvaluationRepository {
+	mock := &MockAlertEvaluationRepository{ctrl: ctrl}
+	mock.recorder = &MockAlertEvaluationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertEvaluationRepository) EXPECT() *MockAlertEvaluationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAlertRuleFiring mocks base method.
+func (m *MockAlertEvaluationRepository) CreateAlertRuleFiring(ctx context.Context, arg db.CreateAlertRuleFiringParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlertRuleFiring", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAlertRuleFiring indicates an expected call of CreateAlertRuleFiring.
+func (mr *MockAlertEvaluationRepositoryMockRecorder) CreateAlertRuleFiring(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlertRuleFiring", reflect.TypeOf((*MockAlertEvaluationRepository)(nil).CreateAlertRuleFiring), ctx, arg)
+}
+
+// DeleteAlertRuleFiring mocks base method.
+func (m *MockAlertEvaluationRepository) DeleteAlertRuleFiring(ctx context.Context, arg db.DeleteAlertRuleFiringParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlertRuleFiring", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlertRuleFiring indicates an expected call of DeleteAlertRuleFiring.
+func (mr *MockAlertEvaluationRepositoryMockRecorder) DeleteAlertRuleFiring(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertRuleFiring", reflect.TypeOf((*MockAlertEvaluationRepository)(nil).DeleteAlertRuleFiring), ctx, arg)
+}
+
+// ListAlertRuleFirings mocks base method.
+func (m *MockAlertEvaluationRepository) ListAlertRuleFirings(ctx context.Context, ruleID int32) ([]db.AlertRuleFiring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertRuleFirings", ctx, ruleID)
+	ret0, _ := ret[0].([]db.AlertRuleFiring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertRuleFirings indicates an expected call of ListAlertRuleFirings.
+func (mr *MockAlertEvaluationRepositoryMockRecorder) ListAlertRuleFirings(ctx, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertRuleFirings", reflect.TypeOf((*MockAlertEvaluationRepository)(nil).ListAlertRuleFirings), ctx, ruleID)
+}
+
+// ListAlertRuleQuantities mocks base method.
+func (m *MockAlertEvaluationRepository) ListAlertRuleQuantities(ctx context.Context, arg db.ListAlertRuleQuantitiesParams) ([]db.ListAlertRuleQuantitiesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertRuleQuantities", ctx, arg)
+	ret0, _ := ret[0].([]db.ListAlertRuleQuantitiesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertRuleQuantities indicates an expected call of ListAlertRuleQuantities.
+func (mr *MockAlertEvaluationRepositoryMockRecorder) ListAlertRuleQuantities(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertRuleQuantities", reflect.TypeOf((*MockAlertEvaluationRepository)(nil).ListAlertRuleQuantities), ctx, arg)
+}
+
+// ListEnabledAlertRules mocks base method.
+func (m *MockAlertEvaluationRepository) ListEnabledAlertRules(ctx context.Context) ([]db.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEnabledAlertRules", ctx)
+	ret0, _ := ret[0].([]db.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEnabledAlertRules indicates an expected call of ListEnabledAlertRules.
+func (mr *MockAlertEvaluationRepositoryMockRecorder) ListEnabledAlertRules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnabledAlertRules", reflect.TypeOf((*MockAlertEvaluationRepository)(nil).ListEnabledAlertRules), ctx)
+}
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountNotifications mocks base method.
+func (m *MockNotificationRepository) CountNotifications(ctx context.Context, arg db.CountNotificationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNotifications", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNotifications indicates an expected call of CountNotifications.
+func (mr *MockNotificationRepositoryMockRecorder) CountNotifications(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).CountNotifications), ctx, arg)
+}
+
+// CreateNotifications mocks base method.
+func (m *MockNotificationRepository) CreateNotifications(ctx context.Context, arg db.CreateNotificationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotifications", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotifications indicates an expected call of CreateNotifications.
+func (mr *MockNotificationRepositoryMockRecorder) CreateNotifications(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).CreateNotifications), ctx, arg)
+}
+
+// GetNotification mocks base method.
+func (m *MockNotificationRepository) GetNotification(ctx context.Context, arg db.GetNotificationParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotification", ctx, arg)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotification indicates an expected call of GetNotification.
+func (mr *MockNotificationRepositoryMockRecorder) GetNotification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockNotificationRepository)(nil).GetNotification), ctx, arg)
+}
+
+// ListNotificationPreferences mocks base method.
+func (m *MockNotificationRepository) ListNotificationPreferences(ctx context.Context, userID string) ([]db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotificationPreferences", ctx, userID)
+	ret0, _ := ret[0].([]db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotificationPreferences indicates an expected call of ListNotificationPreferences.
+func (mr *MockNotificationRepositoryMockRecorder) ListNotificationPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotificationPreferences", reflect.TypeOf((*MockNotificationRepository)(nil).ListNotificationPreferences), ctx, userID)
+}
+
+// ListNotifications mocks base method.
+func (m *MockNotificationRepository) ListNotifications(ctx context.Context, arg db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", ctx, arg)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockNotificationRepositoryMockRecorder) ListNotifications(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).ListNotifications), ctx, arg)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockNotificationRepository) MarkAllNotificationsRead(ctx context.Context, userID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkAllNotificationsRead(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkAllNotificationsRead), ctx, userID)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockNotificationRepository) MarkNotificationRead(ctx context.Context, arg db.MarkNotificationReadParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, arg)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkNotificationRead(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkNotificationRead), ctx, arg)
+}
+
+// UpsertNotificationPreference mocks base method.
+func (m *MockNotificationRepository) UpsertNotificationPreference(ctx context.Context, arg db.UpsertNotificationPreferenceParams) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreference", ctx, arg)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreference indicates an expected call of UpsertNotificationPreference.
+func (mr *MockNotificationRepositoryMockRecorder) UpsertNotificationPreference(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreference", reflect.TypeOf((*MockNotificationRepository)(nil).UpsertNotificationPreference), ctx, arg)
+}
+
+// MockSavedFilterRepository is a mock of SavedFilterRepository interface.
+type MockSavedFilterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSavedFilterRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSavedFilterRepositoryMockRecorder is the mock recorder for MockSavedFilterRepository.
+type MockSavedFilterRepositoryMockRecorder struct {
+	mock *MockSavedFilterRepository
+}
+
+// NewMockSavedFilterRepository creates a new mock instance.
+func NewMockSavedFilterRepository(ctrl *gomock.Controller) *MockSavedFilterRepository {
+	mock := &MockSavedFilterRepository{ctrl: ctrl}
+	mock.recorder = &MockSavedFilterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSavedFilterRepository) EXPECT() *MockSavedFilterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSavedFilter mocks base method.
+func (m *MockSavedFilterRepository) CreateSavedFilter(ctx context.Context, arg db.CreateSavedFilterParams) (db.SavedFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSavedFilter", ctx, arg)
+	ret0, _ := ret[0].(db.SavedFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSavedFilter indicates an expected call of CreateSavedFilter.
+func (mr *MockSavedFilterRepositoryMockRecorder) CreateSavedFilter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSavedFilter", reflect.TypeOf((*MockSavedFilterRepository)(nil).CreateSavedFilter), ctx, arg)
+}
+
+// DeleteSavedFilter mocks base method.
+func (m *MockSavedFilterRepository) DeleteSavedFilter(ctx context.Context, arg db.DeleteSavedFilterParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavedFilter", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSavedFilter indicates an expected call of DeleteSavedFilter.
+func (mr *MockSavedFilterRepositoryMockRecorder) DeleteSavedFilter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedFilter", reflect.TypeOf((*MockSavedFilterRepository)(nil).DeleteSavedFilter), ctx, arg)
+}
+
+// GetSavedFilter mocks base method.
+func (m *MockSavedFilterRepository) GetSavedFilter(ctx context.Context, arg db.GetSavedFilterParams) (db.SavedFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavedFilter", ctx, arg)
+	ret0, _ := ret[0].(db.SavedFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavedFilter indicates an expected call of GetSavedFilter.
+func (mr *MockSavedFilterRepositoryMockRecorder) GetSavedFilter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavedFilter", reflect.TypeOf((*MockSavedFilterRepository)(nil).GetSavedFilter), ctx, arg)
+}
+
+// ListSavedFilters mocks base method.
+func (m *MockSavedFilterRepository) ListSavedFilters(ctx context.Context, arg db.ListSavedFiltersParams) ([]db.SavedFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSavedFilters", ctx, arg)
+	ret0, _ := ret[0].([]db.SavedFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSavedFilters indicates an expected call of ListSavedFilters.
+func (mr *MockSavedFilterRepositoryMockRecorder) ListSavedFilters(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSavedFilters", reflect.TypeOf((*MockSavedFilterRepository)(nil).ListSavedFilters), ctx, arg)
+}
+
+// SetDefaultSavedFilter mocks base method.
+func (m *MockSavedFilterRepository) SetDefaultSavedFilter(ctx context.Context, arg db.SetDefaultSavedFilterParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultSavedFilter", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultSavedFilter indicates an expected call of SetDefaultSavedFilter.
+func (mr *MockSavedFilterRepositoryMockRecorder) SetDefaultSavedFilter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultSavedFilter", reflect.TypeOf((*MockSavedFilterRepository)(nil).SetDefaultSavedFilter), ctx, arg)
+}
+
+// UnsetDefaultSavedFilter mocks base method.
+func (m *MockSavedFilterRepository) UnsetDefaultSavedFilter(ctx context.Context, arg db.UnsetDefaultSavedFilterParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetDefaultSavedFilter", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsetDefaultSavedFilter indicates an expected call of UnsetDefaultSavedFilter.
+func (mr *MockSavedFilterRepositoryMockRecorder) UnsetDefaultSavedFilter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetDefaultSavedFilter", reflect.TypeOf((*MockSavedFilterRepository)(nil).UnsetDefaultSavedFilter), ctx, arg)
+}
+
+// UpdateSavedFilter mocks base method.
+func (m *MockSavedFilterRepository) UpdateSavedFilter(ctx context.Context, arg db.UpdateSavedFilterParams) (db.SavedFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSavedFilter", ctx, arg)
+	ret0, _ := ret[0].(db.SavedFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSavedFilter indicates an expected call of UpdateSavedFilter.
+func (mr *MockSavedFilterRepositoryMockRecorder) UpdateSavedFilter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSavedFilter", reflect.TypeOf((*MockSavedFilterRepository)(nil).UpdateSavedFilter), ctx, arg)
+}
+
+// MockDataQualityRepository is a mock of DataQualityRepository interface.
+type MockDataQualityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataQualityRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDataQualityRepositoryMockRecorder is the mock recorder for MockDataQualityRepository.
+type MockDataQualityRepositoryMockRecorder struct {
+	mock *MockDataQualityRepository
+}
+
+// NewMockDataQualityRepository creates a new mock instance.
+func NewMockDataQualityRepository(ctrl *gomock.Controller) *MockDataQualityRepository {
+	mock := &MockDataQualityRepository{ctrl: ctrl}
+	mock.recorder = &MockDataQualityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataQualityRepository) EXPECT() *MockDataQualityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetDataQualityCounts mocks base method.
+func (m *MockDataQualityRepository) GetDataQualityCounts(ctx context.Context, zeroBefore pgtype.Timestamptz) (db.GetDataQualityCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataQualityCounts", ctx, zeroBefore)
+	ret0, _ := ret[0].(db.GetDataQualityCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataQualityCounts indicates an expected call of GetDataQualityCounts.
+func (mr *MockDataQualityRepositoryMockRecorder) GetDataQualityCounts(ctx, zeroBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataQualityCounts", reflect.TypeOf((*MockDataQualityRepository)(nil).GetDataQualityCounts), ctx, zeroBefore)
+}
+
+// ListDuplicateMasterNames mocks base method.
+func (m *MockDataQualityRepository) ListDuplicateMasterNames(ctx context.Context, limit int32) ([]db.ListDuplicateMasterNamesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDuplicateMasterNames", ctx, limit)
+	ret0, _ := ret[0].([]db.ListDuplicateMasterNamesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDuplicateMasterNames indicates an expected call of ListDuplicateMasterNames.
+func (mr *MockDataQualityRepositoryMockRecorder) ListDuplicateMasterNames(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDuplicateMasterNames", reflect.TypeOf((*MockDataQualityRepository)(nil).ListDuplicateMasterNames), ctx, limit)
+}
+
+// ListItemDocumentation mocks base method.
+func (m *MockDataQualityRepository) ListItemDocumentation(ctx context.Context) ([]db.ListItemDocumentationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemDocumentation", ctx)
+	ret0, _ := ret[0].([]db.ListItemDocumentationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemDocumentation indicates an expected call of ListItemDocumentation.
+func (mr *MockDataQualityRepositoryMockRecorder) ListItemDocumentation(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemDocumentation", reflect.TypeOf((*MockDataQualityRepository)(nil).ListItemDocumentation), ctx)
+}
+
+// ListItemsWithoutPhotos mocks base method.
+func (m *MockDataQualityRepository) ListItemsWithoutPhotos(ctx context.Context, limit int32) ([]db.ListItemsWithoutPhotosRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemsWithoutPhotos", ctx, limit)
+	ret0, _ := ret[0].([]db.ListItemsWithoutPhotosRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemsWithoutPhotos indicates an expected call of ListItemsWithoutPhotos.
+func (mr *MockDataQualityRepositoryMockRecorder) ListItemsWithoutPhotos(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemsWithoutPhotos", reflect.TypeOf((*MockDataQualityRepository)(nil).ListItemsWithoutPhotos), ctx, limit)
+}
+
+// ListLocationsWithoutContact mocks base method.
+func (m *MockDataQualityRepository) ListLocationsWithoutContact(ctx context.Context, limit int32) ([]db.ListLocationsWithoutContactRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocationsWithoutContact", ctx, limit)
+	ret0, _ := ret[0].([]db.ListLocationsWithoutContactRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocationsWithoutContact indicates an expected call of ListLocationsWithoutContact.
+func (mr *MockDataQualityRepositoryMockRecorder) ListLocationsWithoutContact(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocationsWithoutContact", reflect.TypeOf((*MockDataQualityRepository)(nil).ListLocationsWithoutContact), ctx, limit)
+}
+
+// ListStaleZeroQuantities mocks base method.
+func (m *MockDataQualityRepository) ListStaleZeroQuantities(ctx context.Context, arg db.ListStaleZeroQuantitiesParams) ([]db.ListStaleZeroQuantitiesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStaleZeroQuantities", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStaleZeroQuantitiesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStaleZeroQuantities indicates an expected call of ListStaleZeroQuantities.
+func (mr *MockDataQualityRepositoryMockRecorder) ListStaleZeroQuantities(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStaleZeroQuantities", reflect.TypeOf((*MockDataQualityRepository)(nil).ListStaleZeroQuantities), ctx, arg)
+}
+
+// MockShareLinkRepository is a mock of ShareLinkRepository interface.
+type MockShareLinkRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareLinkRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockShareLinkRepositoryMockRecorder is the mock recorder for MockShareLinkRepository.
+type MockShareLinkRepositoryMockRecorder struct {
+	mock *MockShareLinkRepository
+}
+
+// NewMockShareLinkRepository creates a new mock instance.
+func NewMockShareLinkRepository(ctrl *gomock.Controller) *MockShareLinkRepository {
+	mock := &MockShareLinkRepository{ctrl: ctrl}
+	mock.recorder = &MockShareLinkRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareLinkRepository) EXPECT() *MockShareLinkRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountShareLinks mocks base method.
+func (m *MockShareLinkRepository) CountShareLinks(ctx context.Context, arg db.CountShareLinksParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountShareLinks", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountShareLinks indicates an expected call of CountShareLinks.
+func (mr *MockShareLinkRepositoryMockRecorder) CountShareLinks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountShareLinks", reflect.TypeOf((*MockShareLinkRepository)(nil).CountShareLinks), ctx, arg)
+}
+
+// CreateShareLink mocks base method.
+func (m *MockShareLinkRepository) CreateShareLink(ctx context.Context, arg db.CreateShareLinkParams) (db.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShareLink", ctx, arg)
+	ret0, _ := ret[0].(db.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateShareLink indicates an expected call of CreateShareLink.
+func (mr *MockShareLinkRepositoryMockRecorder) CreateShareLink(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShareLink", reflect.TypeOf((*MockShareLinkRepository)(nil).CreateShareLink), ctx, arg)
+}
+
+// GetLocation mocks base method.
+func (m *MockShareLinkRepository) GetLocation(ctx context.Context, id int32) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocation", ctx, id)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocation indicates an expected call of GetLocation.
+func (mr *MockShareLinkRepositoryMockRecorder) GetLocation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocation", reflect.TypeOf((*MockShareLinkRepository)(nil).GetLocation), ctx, id)
+}
+
+// GetShareLink mocks base method.
+func (m *MockShareLinkRepository) GetShareLink(ctx context.Context, id int32) (db.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShareLink", ctx, id)
+	ret0, _ := ret[0].(db.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShareLink indicates an expected call of GetShareLink.
+func (mr *MockShareLinkRepositoryMockRecorder) GetShareLink(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShareLink", reflect.TypeOf((*MockShareLinkRepository)(nil).GetShareLink), ctx, id)
+}
+
+// GetShareLinkByTokenHash mocks base method.
+func (m *MockShareLinkRepository) GetShareLinkByTokenHash(ctx context.Context, tokenHash string) (db.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShareLinkByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(db.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShareLinkByTokenHash indicates an expected call of GetShareLinkByTokenHash.
+func (mr *MockShareLinkRepositoryMockRecorder) GetShareLinkByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShareLinkByTokenHash", reflect.TypeOf((*MockShareLinkRepository)(nil).GetShareLinkByTokenHash), ctx, tokenHash)
+}
+
+// ListShareLinks mocks base method.
+func (m *MockShareLinkRepository) ListShareLinks(ctx context.Context, arg db.ListShareLinksParams) ([]db.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShareLinks", ctx, arg)
+	ret0, _ := ret[0].([]db.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShareLinks indicates an expected call of ListShareLinks.
+func (mr *MockShareLinkRepositoryMockRecorder) ListShareLinks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShareLinks", reflect.TypeOf((*MockShareLinkRepository)(nil).ListShareLinks), ctx, arg)
+}
+
+// ListSparepartStocksByLocation mocks base method.
+func (m *MockShareLinkRepository) ListSparepartStocksByLocation(ctx context.Context, locationID int32) ([]db.ListSparepartStocksByLocationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStocksByLocation", ctx, locationID)
+	ret0, _ := ret[0].([]db.ListSparepartStocksByLocationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStocksByLocation indicates an expected call of ListSparepartStocksByLocation.
+func (mr *MockShareLinkRepositoryMockRecorder) ListSparepartStocksByLocation(ctx, locationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStocksByLocation", reflect.TypeOf((*MockShareLinkRepository)(nil).ListSparepartStocksByLocation), ctx, locationID)
+}
+
+// RecordShareLinkAccess mocks base method.
+func (m *MockShareLinkRepository) RecordShareLinkAccess(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordShareLinkAccess", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordShareLinkAccess indicates an expected call of RecordShareLinkAccess.
+func (mr *MockShareLinkRepositoryMockRecorder) RecordShareLinkAccess(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordShareLinkAccess", reflect.TypeOf((*MockShareLinkRepository)(nil).RecordShareLinkAccess), ctx, id)
+}
+
+// RevokeShareLink mocks base method.
+func (m *MockShareLinkRepository) RevokeShareLink(ctx context.Context, id int32) (db.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeShareLink", ctx, id)
+	ret0, _ := ret[0].(db.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeShareLink indicates an expected call of RevokeShareLink.
+func (mr *MockShareLinkRepositoryMockRecorder) RevokeShareLink(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeShareLink", reflect.TypeOf((*MockShareLinkRepository)(nil).RevokeShareLink), ctx, id)
+}
+
+// MockAPIKeyRepository is a mock of APIKeyRepository interface.
+type MockAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyRepositoryMockRecorder is the mock recorder for MockAPIKeyRepository.
+type MockAPIKeyRepositoryMockRecorder struct {
+	mock *MockAPIKeyRepository
+}
+
+// NewMockAPIKeyRepository creates a new mock instance.
+func NewMockAPIKeyRepository(ctrl *gomock.Controller) *MockAPIKeyRepository {
+	mock := &MockAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountAPIKeys mocks base method.
+func (m *MockAPIKeyRepository) CountAPIKeys(ctx context.Context, active pgtype.Bool) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAPIKeys", ctx, active)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAPIKeys indicates an expected call of CountAPIKeys.
+func (mr *MockAPIKeyRepositoryMockRecorder) CountAPIKeys(ctx, active any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAPIKeys", reflect.TypeOf((*MockAPIKeyRepository)(nil).CountAPIKeys), ctx, active)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, arg)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) CreateAPIKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).CreateAPIKey), ctx, arg)
+}
+
+// GetAPIKey mocks base method.
+func (m *MockAPIKeyRepository) GetAPIKey(ctx context.Context, id int32) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKey", ctx, id)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKey indicates an expected call of GetAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) GetAPIKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).GetAPIKey), ctx, id)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockAPIKeyRepository) ListAPIKeys(ctx context.Context, arg db.ListAPIKeysParams) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, arg)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAPIKeyRepositoryMockRecorder) ListAPIKeys(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAPIKeyRepository)(nil).ListAPIKeys), ctx, arg)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id int32) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) RevokeAPIKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).RevokeAPIKey), ctx, id)
+}
+
+// MockAPIKeyAuthRepository is a mock of APIKeyAuthRepository interface.
+type MockAPIKeyAuthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyAuthRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyAuthRepositoryMockRecorder is the mock recorder for MockAPIKeyAuthRepository.
+type MockAPIKeyAuthRepositoryMockRecorder struct {
+	mock *MockAPIKeyAuthRepository
+}
+
+// NewMockAPIKeyAuthRepository creates a new mock instance.
+func NewMockAPIKeyAuthRepository(ctrl *gomock.Controller) *MockAPIKeyAuthRepository {
+	mock := &MockAPIKeyAuthRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyAuthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyAuthRepository) EXPECT() *MockAPIKeyAuthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetActiveAPIKeyByHash mocks base method.
+func (m *MockAPIKeyAuthRepository) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveAPIKeyByHash", ctx, keyHash)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveAPIKeyByHash indicates an expected call of GetActiveAPIKeyByHash.
+func (mr *MockAPIKeyAuthRepositoryMockRecorder) GetActiveAPIKeyByHash(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveAPIKeyByHash", reflect.TypeOf((*MockAPIKeyAuthRepository)(nil).GetActiveAPIKeyByHash), ctx, keyHash)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockAPIKeyAuthRepository) TouchAPIKey(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockAPIKeyAuthRepositoryMockRecorder) TouchAPIKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockAPIKeyAuthRepository)(nil).TouchAPIKey), ctx, id)
+}
+
+// MockExportLogRepository is a mock of ExportLogRepository interface.
+type MockExportLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportLogRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExportLogRepositoryMockRecorder is the mock recorder for MockExportLogRepository.
+type MockExportLogRepositoryMockRecorder struct {
+	mock *MockExportLogRepository
+}
+
+// NewMockExportLogRepository creates a new mock instance.
+func NewMockExportLogRepository(ctrl *gomock.Controller) *MockExportLogRepository {
+	mock := &MockExportLogRepository{ctrl: ctrl}
+	mock.recorder = &MockExportLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportLogRepository) EXPECT() *MockExportLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountExportLogs mocks base method.
+func (m *MockExportLogRepository) CountExportLogs(ctx context.Context, arg db.CountExportLogsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountExportLogs", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountExportLogs indicates an expected call of CountExportLogs.
+func (mr *MockExportLogRepositoryMockRecorder) CountExportLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountExportLogs", reflect.TypeOf((*MockExportLogRepository)(nil).CountExportLogs), ctx, arg)
+}
+
+// CreateExportLog mocks base method.
+func (m *MockExportLogRepository) CreateExportLog(ctx context.Context, arg db.CreateExportLogParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExportLog", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExportLog indicates an expected call of CreateExportLog.
+func (mr *MockExportLogRepositoryMockRecorder) CreateExportLog(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExportLog", reflect.TypeOf((*MockExportLogRepository)(nil).CreateExportLog), ctx, arg)
+}
+
+// ListExportLogs mocks base method.
+func (m *MockExportLogRepository) ListExportLogs(ctx context.Context, arg db.ListExportLogsParams) ([]db.ExportLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExportLogs", ctx, arg)
+	ret0, _ := ret[0].([]db.ExportLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExportLogs indicates an expected call of ListExportLogs.
+func (mr *MockExportLogRepositoryMockRecorder) ListExportLogs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExportLogs", reflect.TypeOf((*MockExportLogRepository)(nil).ListExportLogs), ctx, arg)
+}
+
+// ListExportLogsForExport mocks base method.
+func (m *MockExportLogRepository) ListExportLogsForExport(ctx context.Context, arg db.ListExportLogsForExportParams) ([]db.ExportLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExportLogsForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ExportLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExportLogsForExport indicates an expected call of ListExportLogsForExport.
+func (mr *MockExportLogRepositoryMockRecorder) ListExportLogsForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExportLogsForExport", reflect.TypeOf((*MockExportLogRepository)(nil).ListExportLogsForExport), ctx, arg)
+}
+
+// MockAuthRepository is a mock of AuthRepository interface.
+type MockAuthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthRepositoryMockRecorder is the mock recorder for MockAuthRepository.
+type MockAuthRepositoryMockRecorder struct {
+	mock *MockAuthRepository
+}
+
+// NewMockAuthRepository creates a new mock instance.
+func NewMockAuthRepository(ctrl *gomock.Controller) *MockAuthRepository {
+	mock := &MockAuthRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthRepository) EXPECT() *MockAuthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAppUser mocks base method.
+func (m *MockAuthRepository) CreateAppUser(ctx context.Context, arg db.CreateAppUserParams) (db.AppUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppUser", ctx, arg)
+	ret0, _ := ret[0].(db.AppUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAppUser indicates an expected call of CreateAppUser.
+func (mr *MockAuthRepositoryMockRecorder) CreateAppUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppUser", reflect.TypeOf((*MockAuthRepository)(nil).CreateAppUser), ctx, arg)
+}
+
+// GetAppUser mocks base method.
+func (m *MockAuthRepository) GetAppUser(ctx context.Context, id int32) (db.AppUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppUser", ctx, id)
+	ret0, _ := ret[0].(db.AppUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppUser indicates an expected call of GetAppUser.
+func (mr *MockAuthRepositoryMockRecorder) GetAppUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppUser", reflect.TypeOf((*MockAuthRepository)(nil).GetAppUser), ctx, id)
+}
+
+// GetAppUserByUsername mocks base method.
+func (m *MockAuthRepository) GetAppUserByUsername(ctx context.Context, username string) (db.AppUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppUserByUsername", ctx, username)
+	ret0, _ := ret[0].(db.AppUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppUserByUsername indicates an expected call of GetAppUserByUsername.
+func (mr *MockAuthRepositoryMockRecorder) GetAppUserByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppUserByUsername", reflect.TypeOf((*MockAuthRepository)(nil).GetAppUserByUsername), ctx, username)
+}
+
+// RecordAppUserLogin mocks base method.
+func (m *MockAuthRepository) RecordAppUserLogin(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAppUserLogin", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAppUserLogin indicates an expected call of RecordAppUserLogin.
+func (mr *MockAuthRepositoryMockRecorder) RecordAppUserLogin(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAppUserLogin", reflect.TypeOf((*MockAuthRepository)(nil).RecordAppUserLogin), ctx, id)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountWebhookDeliveries mocks base method.
+func (m *MockWebhookRepository) CountWebhookDeliveries(ctx context.Context, arg db.CountWebhookDeliveriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebhookDeliveries indicates an expected call of CountWebhookDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) CountWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebhookDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).CountWebhookDeliveries), ctx, arg)
+}
+
+// CountWebhooks mocks base method.
+func (m *MockWebhookRepository) CountWebhooks(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebhooks", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebhooks indicates an expected call of CountWebhooks.
+func (mr *MockWebhookRepositoryMockRecorder) CountWebhooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebhooks", reflect.TypeOf((*MockWebhookRepository)(nil).CountWebhooks), ctx)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookRepository) CreateWebhook(ctx context.Context, arg db.CreateWebhookParams) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, arg)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) CreateWebhook(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).CreateWebhook), ctx, arg)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookRepository) GetWebhook(ctx context.Context, id int32) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhook), ctx, id)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockWebhookRepository) ListWebhookDeliveries(ctx context.Context, arg db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) ListWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).ListWebhookDeliveries), ctx, arg)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookRepository) ListWebhooks(ctx context.Context, arg db.ListWebhooksParams) ([]db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx, arg)
+	ret0, _ := ret[0].([]db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookRepositoryMockRecorder) ListWebhooks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookRepository)(nil).ListWebhooks), ctx, arg)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookRepository) UpdateWebhook(ctx context.Context, arg db.UpdateWebhookParams) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, arg)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateWebhook(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateWebhook), ctx, arg)
+}
+
+// MockWebhookDispatchRepository is a mock of WebhookDispatchRepository interface.
+type MockWebhookDispatchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDispatchRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookDispatchRepositoryMockRecorder is the mock recorder for MockWebhookDispatchRepository.
+type MockWebhookDispatchRepositoryMockRecorder struct {
+	mock *MockWebhookDispatchRepository
+}
+
+// NewMockWebhookDispatchRepository creates a new mock instance.
+func NewMockWebhookDispatchRepository(ctrl *gomock.Controller) *MockWebhookDispatchRepository {
+	mock := &MockWebhookDispatchRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookDispatchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDispatchRepository) EXPECT() *MockWebhookDispatchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockWebhookDispatchRepository) ClaimWebhookDeliveries(ctx context.Context, arg db.ClaimWebhookDeliveriesParams) ([]db.ClaimWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]db.ClaimWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockWebhookDispatchRepositoryMockRecorder) ClaimWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockWebhookDispatchRepository)(nil).ClaimWebhookDeliveries), ctx, arg)
+}
+
+// FanOutWebhookEvents mocks base method.
+func (m *MockWebhookDispatchRepository) FanOutWebhookEvents(ctx context.Context, limit int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FanOutWebhookEvents", ctx, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FanOutWebhookEvents indicates an expected call of FanOutWebhookEvents.
+func (mr *MockWebhookDispatchRepositoryMockRecorder) FanOutWebhookEvents(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FanOutWebhookEvents", reflect.TypeOf((*MockWebhookDispatchRepository)(nil).FanOutWebhookEvents), ctx, limit)
+}
+
+// MarkWebhookDeliveryDelivered mocks base method.
+func (m *MockWebhookDispatchRepository) MarkWebhookDeliveryDelivered(ctx context.Context, arg db.MarkWebhookDeliveryDeliveredParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryDelivered", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryDelivered indicates an expected call of MarkWebhookDeliveryDelivered.
+func (mr *MockWebhookDispatchRepositoryMockRecorder) MarkWebhookDeliveryDelivered(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryDelivered", reflect.TypeOf((*MockWebhookDispatchRepository)(nil).MarkWebhookDeliveryDelivered), ctx, arg)
+}
+
+// MarkWebhookDeliveryFailed mocks base method.
+func (m *MockWebhookDispatchRepository) MarkWebhookDeliveryFailed(ctx context.Context, arg db.MarkWebhookDeliveryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryFailed indicates an expected call of MarkWebhookDeliveryFailed.
+func (mr *MockWebhookDispatchRepositoryMockRecorder) MarkWebhookDeliveryFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryFailed", reflect.TypeOf((*MockWebhookDispatchRepository)(nil).MarkWebhookDeliveryFailed), ctx, arg)
+}
+
+// MockSearchRepository is a mock of SearchRepository interface.
+type MockSearchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSearchRepositoryMockRecorder is the mock recorder for MockSearchRepository.
+type MockSearchRepositoryMockRecorder struct {
+	mock *MockSearchRepository
+}
+
+// NewMockSearchRepository creates a new mock instance.
+func NewMockSearchRepository(ctrl *gomock.Controller) *MockSearchRepository {
+	mock := &MockSearchRepository{ctrl: ctrl}
+	mock.recorder = &MockSearchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchRepository) EXPECT() *MockSearchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// SearchInventory mocks base method.
+func (m *MockSearchRepository) SearchInventory(ctx context.Context, arg db.SearchInventoryParams) ([]db.SearchInventoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchInventory", ctx, arg)
+	ret0, _ := ret[0].([]db.SearchInventoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchInventory indicates an expected call of SearchInventory.
+func (mr *MockSearchRepositoryMockRecorder) SearchInventory(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchInventory", reflect.TypeOf((*MockSearchRepository)(nil).SearchInventory), ctx, arg)
+}
+
+// MockFilterValueRepository is a mock of FilterValueRepository interface.
+type MockFilterValueRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFilterValueRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFilterValueRepositoryMockRecorder is the mock recorder for MockFilterValueRepository.
+type MockFilterValueRepositoryMockRecorder struct {
+	mock *MockFilterValueRepository
+}
+
+// NewMockFilterValueRepository creates a new mock instance.
+func NewMockFilterValueRepository(ctrl *gomock.Controller) *MockFilterValueRepository {
+	mock := &MockFilterValueRepository{ctrl: ctrl}
+	mock.recorder = &MockFilterValueRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFilterValueRepository) EXPECT() *MockFilterValueRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListFilterValues mocks base method.
+func (m *MockFilterValueRepository) ListFilterValues(ctx context.Context, region pgtype.Text) ([]db.ListFilterValuesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFilterValues", ctx, region)
+	ret0, _ := ret[0].([]db.ListFilterValuesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFilterValues indicates an expected call of ListFilterValues.
+func (mr *MockFilterValueRepositoryMockRecorder) ListFilterValues(ctx, region any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFilterValues", reflect.TypeOf((*MockFilterValueRepository)(nil).ListFilterValues), ctx, region)
+}
+
+// MockExportJobRepository is a mock of ExportJobRepository interface.
+type MockExportJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportJobRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExportJobRepositoryMockRecorder is the mock recorder for MockExportJobRepository.
+type MockExportJobRepositoryMockRecorder struct {
+	mock *MockExportJobRepository
+}
+
+// NewMockExportJobRepository creates a new mock instance.
+func NewMockExportJobRepository(ctrl *gomock.Controller) *MockExportJobRepository {
+	mock := &MockExportJobRepository{ctrl: ctrl}
+	mock.recorder = &MockExportJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportJobRepository) EXPECT() *MockExportJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateExportJob mocks base method.
+func (m *MockExportJobRepository) CreateExportJob(ctx context.Context, arg db.CreateExportJobParams) (db.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExportJob", ctx, arg)
+	ret0, _ := ret[0].(db.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExportJob indicates an expected call of CreateExportJob.
+func (mr *MockExportJobRepositoryMockRecorder) CreateExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExportJob", reflect.TypeOf((*MockExportJobRepository)(nil).CreateExportJob), ctx, arg)
+}
+
+// GetExportJob mocks base method.
+func (m *MockExportJobRepository) GetExportJob(ctx context.Context, arg db.GetExportJobParams) (db.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportJob", ctx, arg)
+	ret0, _ := ret[0].(db.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportJob indicates an expected call of GetExportJob.
+func (mr *MockExportJobRepositoryMockRecorder) GetExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportJob", reflect.TypeOf((*MockExportJobRepository)(nil).GetExportJob), ctx, arg)
+}
+
+// MockExportWorkerRepository is a mock of ExportWorkerRepository interface.
+type MockExportWorkerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportWorkerRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExportWorkerRepositoryMockRecorder is the mock recorder for MockExportWorkerRepository.
+type MockExportWorkerRepositoryMockRecorder struct {
+	mock *MockExportWorkerRepository
+}
+
+// NewMockExportWorkerRepository creates a new mock instance.
+func NewMockExportWorkerRepository(ctrl *gomock.Controller) *MockExportWorkerRepository {
+	mock := &MockExportWorkerRepository{ctrl: ctrl}
+	mock.recorder = &MockExportWorkerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportWorkerRepository) EXPECT() *MockExportWorkerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimExportJob mocks base method.
+func (m *MockExportWorkerRepository) ClaimExportJob(ctx context.Context, leaseSeconds int32) (db.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExportJob", ctx, leaseSeconds)
+	ret0, _ := ret[0].(db.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExportJob indicates an expected call of ClaimExportJob.
+func (mr *MockExportWorkerRepositoryMockRecorder) ClaimExportJob(ctx, leaseSeconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExportJob", reflect.TypeOf((*MockExportWorkerRepository)(nil).ClaimExportJob), ctx, leaseSeconds)
+}
+
+// CompleteExportJob mocks base method.
+func (m *MockExportWorkerRepository) CompleteExportJob(ctx context.Context, arg db.CompleteExportJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteExportJob", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteExportJob indicates an expected call of CompleteExportJob.
+func (mr *MockExportWorkerRepositoryMockRecorder) CompleteExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteExportJob", reflect.TypeOf((*MockExportWorkerRepository)(nil).CompleteExportJob), ctx, arg)
+}
+
+// CreateExportLog mocks base method.
+func (m *MockExportWorkerRepository) CreateExportLog(ctx context.Context, arg db.CreateExportLogParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExportLog", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExportLog indicates an expected call of CreateExportLog.
+func (mr *MockExportWorkerRepositoryMockRecorder) CreateExportLog(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExportLog", reflect.TypeOf((*MockExportWorkerRepository)(nil).CreateExportLog), ctx, arg)
+}
+
+// FailExportJob mocks base method.
+func (m *MockExportWorkerRepository) FailExportJob(ctx context.Context, arg db.FailExportJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailExportJob", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailExportJob indicates an expected call of FailExportJob.
+func (mr *MockExportWorkerRepositoryMockRecorder) FailExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailExportJob", reflect.TypeOf((*MockExportWorkerRepository)(nil).FailExportJob), ctx, arg)
+}
+
+// ListSparepartStocksForExport mocks base method.
+func (m *MockExportWorkerRepository) ListSparepartStocksForExport(ctx context.Context, arg db.ListSparepartStocksForExportParams) ([]db.ListSparepartStocksForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStocksForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSparepartStocksForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStocksForExport indicates an expected call of ListSparepartStocksForExport.
+func (mr *MockExportWorkerRepositoryMockRecorder) ListSparepartStocksForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStocksForExport", reflect.TypeOf((*MockExportWorkerRepository)(nil).ListSparepartStocksForExport), ctx, arg)
+}
+
+// MockEmailDigestRepository is a mock of EmailDigestRepository interface.
+type MockEmailDigestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailDigestRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailDigestRepositoryMockRecorder is the mock recorder for MockEmailDigestRepository.
+type MockEmailDigestRepositoryMockRecorder struct {
+	mock *MockEmailDigestRepository
+}
+
+// NewMockEmailDigestRepository creates a new mock instance.
+func NewMockEmailDigestRepository(ctrl *gomock.Controller) *MockEmailDigestRepository {
+	mock := &MockEmailDigestRepository{ctrl: ctrl}
+	mock.recorder = &MockEmailDigestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailDigestRepository) EXPECT() *MockEmailDigestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListContactPersonEmails mocks base method.
+func (m *MockEmailDigestRepository) ListContactPersonEmails(ctx context.Context) ([]db.ListContactPersonEmailsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContactPersonEmails", ctx)
+	ret0, _ := ret[0].([]db.ListContactPersonEmailsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContactPersonEmails indicates an expected call of ListContactPersonEmails.
+func (mr *MockEmailDigestRepositoryMockRecorder) ListContactPersonEmails(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContactPersonEmails", reflect.TypeOf((*MockEmailDigestRepository)(nil).ListContactPersonEmails), ctx)
+}
+
+// ListLowStockDigestItems mocks base method.
+func (m *MockEmailDigestRepository) ListLowStockDigestItems(ctx context.Context, threshold int32) ([]db.ListLowStockDigestItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLowStockDigestItems", ctx, threshold)
+	ret0, _ := ret[0].([]db.ListLowStockDigestItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLowStockDigestItems indicates an expected call of ListLowStockDigestItems.
+func (mr *MockEmailDigestRepositoryMockRecorder) ListLowStockDigestItems(ctx, threshold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLowStockDigestItems", reflect.TypeOf((*MockEmailDigestRepository)(nil).ListLowStockDigestItems), ctx, threshold)
+}
+
+// ListPendingSparepartRequestDigest mocks base method.
+func (m *MockEmailDigestRepository) ListPendingSparepartRequestDigest(ctx context.Context) ([]db.ListPendingSparepartRequestDigestRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingSparepartRequestDigest", ctx)
+	ret0, _ := ret[0].([]db.ListPendingSparepartRequestDigestRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingSparepartRequestDigest indicates an expected call of ListPendingSparepartRequestDigest.
+func (mr *MockEmailDigestRepositoryMockRecorder) ListPendingSparepartRequestDigest(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingSparepartRequestDigest", reflect.TypeOf((*MockEmailDigestRepository)(nil).ListPendingSparepartRequestDigest), ctx)
+}
+
+// MockMessageDeliveryRepository is a mock of MessageDeliveryRepository interface.
+type MockMessageDeliveryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageDeliveryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageDeliveryRepositoryMockRecorder is the mock recorder for MockMessageDeliveryRepository.
+type MockMessageDeliveryRepositoryMockRecorder struct {
+	mock *MockMessageDeliveryRepository
+}
+
+// NewMockMessageDeliveryRepository creates a new mock instance.
+func NewMockMessageDeliveryRepository(ctrl *gomock.Controller) *MockMessageDeliveryRepository {
+	mock := &MockMessageDeliveryRepository{ctrl: ctrl}
+	mock.recorder = &MockMessageDeliveryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageDeliveryRepository) EXPECT() *MockMessageDeliveryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountMessageDeliveries mocks base method.
+func (m *MockMessageDeliveryRepository) CountMessageDeliveries(ctx context.Context, arg db.CountMessageDeliveriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMessageDeliveries", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMessageDeliveries indicates an expected call of CountMessageDeliveries.
+func (mr *MockMessageDeliveryRepositoryMockRecorder) CountMessageDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMessageDeliveries", reflect.TypeOf((*MockMessageDeliveryRepository)(nil).CountMessageDeliveries), ctx, arg)
+}
+
+// ListMessageDeliveries mocks base method.
+func (m *MockMessageDeliveryRepository) ListMessageDeliveries(ctx context.Context, arg db.ListMessageDeliveriesParams) ([]db.MessageDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessageDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]db.MessageDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessageDeliveries indicates an expected call of ListMessageDeliveries.
+func (mr *MockMessageDeliveryRepositoryMockRecorder) ListMessageDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessageDeliveries", reflect.TypeOf((*MockMessageDeliveryRepository)(nil).ListMessageDeliveries), ctx, arg)
+}
+
+// MockMessageDispatchRepository is a mock of MessageDispatchRepository interface.
+type MockMessageDispatchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageDispatchRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageDispatchRepositoryMockRecorder is the mock recorder for MockMessageDispatchRepository.
+type MockMessageDispatchRepositoryMockRecorder struct {
+	mock *MockMessageDispatchRepository
+}
+
+// NewMockMessageDispatchRepository creates a new mock instance.
+func NewMockMessageDispatchRepository(ctrl *gomock.Controller) *MockMessageDispatchRepository {
+	mock := &MockMessageDispatchRepository{ctrl: ctrl}
+	mock.recorder = &MockMessageDispatchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageDispatchRepository) EXPECT() *MockMessageDispatchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimMessageDeliveries mocks base method.
+func (m *MockMessageDispatchRepository) ClaimMessageDeliveries(ctx context.Context, arg db.ClaimMessageDeliveriesParams) ([]db.MessageDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimMessageDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]db.MessageDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimMessageDeliveries indicates an expected call of ClaimMessageDeliveries.
+func (mr *MockMessageDispatchRepositoryMockRecorder) ClaimMessageDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimMessageDeliveries", reflect.TypeOf((*MockMessageDispatchRepository)(nil).ClaimMessageDeliveries), ctx, arg)
+}
+
+// ExpireMessageDeliveries mocks base method.
+func (m *MockMessageDispatchRepository) ExpireMessageDeliveries(ctx context.Context, maxAgeSeconds int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireMessageDeliveries", ctx, maxAgeSeconds)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireMessageDeliveries indicates an expected call of ExpireMessageDeliveries.
+func (mr *MockMessageDispatchRepositoryMockRecorder) ExpireMessageDeliveries(ctx, maxAgeSeconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireMessageDeliveries", reflect.TypeOf((*MockMessageDispatchRepository)(nil).ExpireMessageDeliveries), ctx, maxAgeSeconds)
+}
+
+// MarkMessageDeliveryFailed mocks base method.
+func (m *MockMessageDispatchRepository) MarkMessageDeliveryFailed(ctx context.Context, arg db.MarkMessageDeliveryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMessageDeliveryFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkMessageDeliveryFailed indicates an expected call of MarkMessageDeliveryFailed.
+func (mr *MockMessageDispatchRepositoryMockRecorder) MarkMessageDeliveryFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMessageDeliveryFailed", reflect.TypeOf((*MockMessageDispatchRepository)(nil).MarkMessageDeliveryFailed), ctx, arg)
+}
+
+// MarkMessageDeliverySent mocks base method.
+func (m *MockMessageDispatchRepository) MarkMessageDeliverySent(ctx context.Context, arg db.MarkMessageDeliverySentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMessageDeliverySent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkMessageDeliverySent indicates an expected call of MarkMessageDeliverySent.
+func (mr *MockMessageDispatchRepositoryMockRecorder) MarkMessageDeliverySent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMessageDeliverySent", reflect.TypeOf((*MockMessageDispatchRepository)(nil).MarkMessageDeliverySent), ctx, arg)
+}
+
+// MockStorageUsageRepository is a mock of StorageUsageRepository interface.
+type MockStorageUsageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageUsageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStorageUsageRepositoryMockRecorder is the mock recorder for MockStorageUsageRepository.
+type MockStorageUsageRepositoryMockRecorder struct {
+	mock *MockStorageUsageRepository
+}
+
+// NewMockStorageUsageRepository creates a new mock instance.
+func NewMockStorageUsageRepository(ctrl *gomock.Controller) *MockStorageUsageRepository {
+	mock := &MockStorageUsageRepository{ctrl: ctrl}
+	mock.recorder = &MockStorageUsageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageUsageRepository) EXPECT() *MockStorageUsageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListLocationPhotoPaths mocks base method.
+func (m *MockStorageUsageRepository) ListLocationPhotoPaths(ctx context.Context) ([]db.ListLocationPhotoPathsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocationPhotoPaths", ctx)
+	ret0, _ := ret[0].([]db.ListLocationPhotoPathsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocationPhotoPaths indicates an expected call of ListLocationPhotoPaths.
+func (mr *MockStorageUsageRepositoryMockRecorder) ListLocationPhotoPaths(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocationPhotoPaths", reflect.TypeOf((*MockStorageUsageRepository)(nil).ListLocationPhotoPaths), ctx)
+}
+
+// MockUploadFileRepository is a mock of UploadFileRepository interface.
+type MockUploadFileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadFileRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUploadFileRepositoryMockRecorder is the mock recorder for MockUploadFileRepository.
+type MockUploadFileRepositoryMockRecorder struct {
+	mock *MockUploadFileRepository
+}
+
+// NewMockUploadFileRepository creates a new mock instance.
+func NewMockUploadFileRepository(ctrl *gomock.Controller) *MockUploadFileRepository {
+	mock := &MockUploadFileRepository{ctrl: ctrl}
+	mock.recorder = &MockUploadFileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadFileRepository) EXPECT() *MockUploadFileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcquireUploadFile mocks base method.
+func (m *MockUploadFileRepository) AcquireUploadFile(ctx context.Context, sha256 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireUploadFile", ctx, sha256)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireUploadFile indicates an expected call of AcquireUploadFile.
+func (mr *MockUploadFileRepositoryMockRecorder) AcquireUploadFile(ctx, sha256 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireUploadFile", reflect.TypeOf((*MockUploadFileRepository)(nil).AcquireUploadFile), ctx, sha256)
+}
+
+// CreateUploadFile mocks base method.
+func (m *MockUploadFileRepository) CreateUploadFile(ctx context.Context, arg db.CreateUploadFileParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadFile", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUploadFile indicates an expected call of CreateUploadFile.
+func (mr *MockUploadFileRepositoryMockRecorder) CreateUploadFile(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadFile", reflect.TypeOf((*MockUploadFileRepository)(nil).CreateUploadFile), ctx, arg)
+}
+
+// DeleteReleasedUploadFile mocks base method.
+func (m *MockUploadFileRepository) DeleteReleasedUploadFile(ctx context.Context, path string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReleasedUploadFile", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteReleasedUploadFile indicates an expected call of DeleteReleasedUploadFile.
+func (mr *MockUploadFileRepositoryMockRecorder) DeleteReleasedUploadFile(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReleasedUploadFile", reflect.TypeOf((*MockUploadFileRepository)(nil).DeleteReleasedUploadFile), ctx, path)
+}
+
+// ReleaseUploadFile mocks base method.
+func (m *MockUploadFileRepository) ReleaseUploadFile(ctx context.Context, path string) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseUploadFile", ctx, path)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseUploadFile indicates an expected call of ReleaseUploadFile.
+func (mr *MockUploadFileRepositoryMockRecorder) ReleaseUploadFile(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseUploadFile", reflect.TypeOf((*MockUploadFileRepository)(nil).ReleaseUploadFile), ctx, path)
+}
+
+// SumUploadFileSize mocks base method.
+func (m *MockUploadFileRepository) SumUploadFileSize(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumUploadFileSize", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumUploadFileSize indicates an expected call of SumUploadFileSize.
+func (mr *MockUploadFileRepositoryMockRecorder) SumUploadFileSize(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumUploadFileSize", reflect.TypeOf((*MockUploadFileRepository)(nil).SumUploadFileSize), ctx)
+}
+
+// MockGraphRepository is a mock of GraphRepository interface.
+type MockGraphRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockGraphRepositoryMockRecorder is the mock recorder for MockGraphRepository.
+type MockGraphRepositoryMockRecorder struct {
+	mock *MockGraphRepository
+}
+
+// NewMockGraphRepository creates a new mock instance.
+func NewMockGraphRepository(ctrl *gomock.Controller) *MockGraphRepository {
+	mock := &MockGraphRepository{ctrl: ctrl}
+	mock.recorder = &MockGraphRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphRepository) EXPECT() *MockGraphRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountLocations mocks base method.
+func (m *MockGraphRepository) CountLocations(ctx context.Context, arg db.CountLocationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLocations", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLocations indicates an expected call of CountLocations.
+func (mr *MockGraphRepositoryMockRecorder) CountLocations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLocations", reflect.TypeOf((*MockGraphRepository)(nil).CountLocations), ctx, arg)
+}
+
+// CountSparepartStockItems mocks base method.
+func (m *MockGraphRepository) CountSparepartStockItems(ctx context.Context, arg db.CountSparepartStockItemsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSparepartStockItems", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSparepartStockItems indicates an expected call of CountSparepartStockItems.
+func (mr *MockGraphRepositoryMockRecorder) CountSparepartStockItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSparepartStockItems", reflect.TypeOf((*MockGraphRepository)(nil).CountSparepartStockItems), ctx, arg)
+}
+
+// GetLocation mocks base method.
+func (m *MockGraphRepository) GetLocation(ctx context.Context, id int32) (db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocation", ctx, id)
+	ret0, _ := ret[0].(db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocation indicates an expected call of GetLocation.
+func (mr *MockGraphRepositoryMockRecorder) GetLocation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocation", reflect.TypeOf((*MockGraphRepository)(nil).GetLocation), ctx, id)
+}
+
+// ListContactPersonsByLocations mocks base method.
+func (m *MockGraphRepository) ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]db.ContactPerson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContactPersonsByLocations", ctx, locationIds)
+	ret0, _ := ret[0].([]db.ContactPerson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContactPersonsByLocations indicates an expected call of ListContactPersonsByLocations.
+func (mr *MockGraphRepositoryMockRecorder) ListContactPersonsByLocations(ctx, locationIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContactPersonsByLocations", reflect.TypeOf((*MockGraphRepository)(nil).ListContactPersonsByLocations), ctx, locationIds)
+}
+
+// ListLocations mocks base method.
+func (m *MockGraphRepository) ListLocations(ctx context.Context, arg db.ListLocationsParams) ([]db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocations", ctx, arg)
+	ret0, _ := ret[0].([]db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocations indicates an expected call of ListLocations.
+func (mr *MockGraphRepositoryMockRecorder) ListLocations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocations", reflect.TypeOf((*MockGraphRepository)(nil).ListLocations), ctx, arg)
+}
+
+// ListLocationsByIDs mocks base method.
+func (m *MockGraphRepository) ListLocationsByIDs(ctx context.Context, ids []int32) ([]db.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocationsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]db.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocationsByIDs indicates an expected call of ListLocationsByIDs.
+func (mr *MockGraphRepositoryMockRecorder) ListLocationsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocationsByIDs", reflect.TypeOf((*MockGraphRepository)(nil).ListLocationsByIDs), ctx, ids)
+}
+
+// ListSparepartStockItems mocks base method.
+func (m *MockGraphRepository) ListSparepartStockItems(ctx context.Context, arg db.ListSparepartStockItemsParams) ([]db.ListSparepartStockItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStockItems", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSparepartStockItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStockItems indicates an expected call of ListSparepartStockItems.
+func (mr *MockGraphRepositoryMockRecorder) ListSparepartStockItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStockItems", reflect.TypeOf((*MockGraphRepository)(nil).ListSparepartStockItems), ctx, arg)
+}
+
+// ListSparepartStocksByLocations mocks base method.
+func (m *MockGraphRepository) ListSparepartStocksByLocations(ctx context.Context, locationIds []int32) ([]db.ListSparepartStocksByLocationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSparepartStocksByLocations", ctx, locationIds)
+	ret0, _ := ret[0].([]db.ListSparepartStocksByLocationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSparepartStocksByLocations indicates an expected call of ListSparepartStocksByLocations.
+func (mr *MockGraphRepositoryMockRecorder) ListSparepartStocksByLocations(ctx, locationIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSparepartStocksByLocations", reflect.TypeOf((*MockGraphRepository)(nil).ListSparepartStocksByLocations), ctx, locationIds)
+}
+
+// ListToolsAlkersByLocations mocks base method.
+func (m *MockGraphRepository) ListToolsAlkersByLocations(ctx context.Context, locationIds []int32) ([]db.ListToolsAlkersByLocationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolsAlkersByLocations", ctx, locationIds)
+	ret0, _ := ret[0].([]db.ListToolsAlkersByLocationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolsAlkersByLocations indicates an expected call of ListToolsAlkersByLocations.
+func (mr *MockGraphRepositoryMockRecorder) ListToolsAlkersByLocations(ctx, locationIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolsAlkersByLocations", reflect.TypeOf((*MockGraphRepository)(nil).ListToolsAlkersByLocations), ctx, locationIds)
+}
@@ -0,0 +1,580 @@
+// Package repository defines the data access interfaces used by the HTTP handlers.
+// Store (the sqlc generated queries plus transaction support) satisfies every interface,
+// CachedStore adds read-through caching of the lookup tables; tests use the mocks in ./mocks.
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/mock_repository.go -package=mocks
+
+import (
+	"context"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LocationRepository provides access to locations
+type LocationRepository interface {
+	GetLocation(ctx context.Context, id int32) (sqlcdb.Location, error)
+	ListLocations(ctx context.Context, arg sqlcdb.ListLocationsParams) ([]sqlcdb.Location, error)
+	CountLocations(ctx context.Context, arg sqlcdb.CountLocationsParams) (int64, error)
+	CreateLocation(ctx context.Context, arg sqlcdb.CreateLocationParams) (sqlcdb.Location, error)
+	UpdateLocation(ctx context.Context, arg sqlcdb.UpdateLocationParams) (sqlcdb.Location, error)
+	PatchLocation(ctx context.Context, arg sqlcdb.PatchLocationParams) (sqlcdb.Location, error)
+	DeleteLocation(ctx context.Context, id int32) error
+	RestoreLocation(ctx context.Context, id int32) (sqlcdb.Location, error)
+	SetLocationActive(ctx context.Context, arg sqlcdb.SetLocationActiveParams) (sqlcdb.Location, error)
+	CountLocationStockReferences(ctx context.Context, locationID int32) (int64, error)
+	ListLocationCompleteness(ctx context.Context, arg sqlcdb.ListLocationCompletenessParams) ([]sqlcdb.ListLocationCompletenessRow, error)
+	ListLocationsWithCoordinates(ctx context.Context, arg sqlcdb.ListLocationsWithCoordinatesParams) ([]sqlcdb.ListLocationsWithCoordinatesRow, error)
+}
+
+// ContactPersonRepository provides access to location contact persons
+type ContactPersonRepository interface {
+	GetContactPerson(ctx context.Context, id int32) (sqlcdb.GetContactPersonRow, error)
+	ListContactPersons(ctx context.Context, arg sqlcdb.ListContactPersonsParams) ([]sqlcdb.ListContactPersonsRow, error)
+	CountContactPersons(ctx context.Context, locationID pgtype.Int4) (int64, error)
+	CreateContactPerson(ctx context.Context, arg sqlcdb.CreateContactPersonParams) (sqlcdb.ContactPerson, error)
+	UpdateContactPerson(ctx context.Context, arg sqlcdb.UpdateContactPersonParams) (sqlcdb.ContactPerson, error)
+	PatchContactPerson(ctx context.Context, arg sqlcdb.PatchContactPersonParams) (sqlcdb.ContactPerson, error)
+	DeleteContactPerson(ctx context.Context, id int32) error
+	RestoreContactPerson(ctx context.Context, id int32) (sqlcdb.ContactPerson, error)
+}
+
+// RegencyRepository provides access to the regency reference table
+type RegencyRepository interface {
+	GetRegency(ctx context.Context, id int32) (sqlcdb.Regency, error)
+	ListRegencies(ctx context.Context, arg sqlcdb.ListRegenciesParams) ([]sqlcdb.Regency, error)
+	CountRegencies(ctx context.Context, arg sqlcdb.CountRegenciesParams) (int64, error)
+	CreateRegency(ctx context.Context, arg sqlcdb.CreateRegencyParams) (sqlcdb.Regency, error)
+	UpdateRegency(ctx context.Context, arg sqlcdb.UpdateRegencyParams) (sqlcdb.Regency, error)
+	DeleteRegency(ctx context.Context, id int32) error
+}
+
+// SupplierRepository provides access to the suppliers goods receipts come from
+type SupplierRepository interface {
+	GetSupplier(ctx context.Context, id int32) (sqlcdb.Supplier, error)
+	ListSuppliers(ctx context.Context, arg sqlcdb.ListSuppliersParams) ([]sqlcdb.Supplier, error)
+	CountSuppliers(ctx context.Context, name pgtype.Text) (int64, error)
+	CreateSupplier(ctx context.Context, arg sqlcdb.CreateSupplierParams) (sqlcdb.Supplier, error)
+	UpdateSupplier(ctx context.Context, arg sqlcdb.UpdateSupplierParams) (sqlcdb.Supplier, error)
+	DeleteSupplier(ctx context.Context, id int32) error
+}
+
+// TechnicianRepository provides access to the directory of field technicians
+type TechnicianRepository interface {
+	GetTechnician(ctx context.Context, id int32) (sqlcdb.Technician, error)
+	ListTechnicians(ctx context.Context, arg sqlcdb.ListTechniciansParams) ([]sqlcdb.Technician, error)
+	CountTechnicians(ctx context.Context, arg sqlcdb.CountTechniciansParams) (int64, error)
+	CreateTechnician(ctx context.Context, arg sqlcdb.CreateTechnicianParams) (sqlcdb.Technician, error)
+	UpdateTechnician(ctx context.Context, arg sqlcdb.UpdateTechnicianParams) (sqlcdb.Technician, error)
+	DeleteTechnician(ctx context.Context, id int32) error
+}
+
+// ClusterRepository provides access to the cluster reference table
+type ClusterRepository interface {
+	GetCluster(ctx context.Context, id int32) (sqlcdb.GetClusterRow, error)
+	ListClusters(ctx context.Context, arg sqlcdb.ListClustersParams) ([]sqlcdb.ListClustersRow, error)
+	CountClusters(ctx context.Context, arg sqlcdb.CountClustersParams) (int64, error)
+	CreateCluster(ctx context.Context, arg sqlcdb.CreateClusterParams) (sqlcdb.CreateClusterRow, error)
+	UpdateCluster(ctx context.Context, arg sqlcdb.UpdateClusterParams) (sqlcdb.UpdateClusterRow, error)
+	DeleteCluster(ctx context.Context, id int32) error
+}
+
+// SparepartMasterRepository provides access to the sparepart master list
+type SparepartMasterRepository interface {
+	GetSparepartMaster(ctx context.Context, id int32) (sqlcdb.ListSparepart, error)
+	ListSparepartMasters(ctx context.Context, arg sqlcdb.ListSparepartMastersParams) ([]sqlcdb.ListSparepart, error)
+	CountSparepartMasters(ctx context.Context, arg sqlcdb.CountSparepartMastersParams) (int64, error)
+	CreateSparepartMaster(ctx context.Context, arg sqlcdb.CreateSparepartMasterParams) (sqlcdb.ListSparepart, error)
+	UpdateSparepartMaster(ctx context.Context, arg sqlcdb.UpdateSparepartMasterParams) (sqlcdb.ListSparepart, error)
+	PatchSparepartMaster(ctx context.Context, arg sqlcdb.PatchSparepartMasterParams) (sqlcdb.ListSparepart, error)
+	DeleteSparepartMaster(ctx context.Context, id int32) error
+	RestoreSparepartMaster(ctx context.Context, id int32) (sqlcdb.ListSparepart, error)
+	CountSparepartMasterReferences(ctx context.Context, id int32) (int64, error)
+	ListSparepartAvailability(ctx context.Context, sparepartID int32) ([]sqlcdb.ListSparepartAvailabilityRow, error)
+	ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]sqlcdb.ContactPerson, error)
+
+	// Spreadsheet imports check the imported names against the catalogue, then stage the rows
+	// with COPY and move them into the master list within one transaction
+	ListSparepartMastersMatchingNames(ctx context.Context, names []string) ([]sqlcdb.ListSparepart, error)
+	CopySparepartMasterImport(ctx context.Context, arg []sqlcdb.CopySparepartMasterImportParams) (int64, error)
+	CreateSparepartMastersFromImport(ctx context.Context) ([]sqlcdb.ListSparepart, error)
+
+	// WithinSparepartMasterTransaction runs fn with a repository bound to a single database transaction
+	WithinSparepartMasterTransaction(ctx context.Context, fn func(repo SparepartMasterRepository) error) error
+}
+
+// SparepartStockRepository provides access to sparepart stock items
+type SparepartStockRepository interface {
+	GetSparepartStock(ctx context.Context, id int32) (sqlcdb.GetSparepartStockRow, error)
+	ListSparepartStocks(ctx context.Context, arg sqlcdb.ListSparepartStocksParams) ([]sqlcdb.ListSparepartStocksRow, error)
+	ListSparepartStocksByLocation(ctx context.Context, locationID int32) ([]sqlcdb.ListSparepartStocksByLocationRow, error)
+	ListSparepartStocksForExport(ctx context.Context, arg sqlcdb.ListSparepartStocksForExportParams) ([]sqlcdb.ListSparepartStocksForExportRow, error)
+	ListSparepartStocksForLabels(ctx context.Context, arg sqlcdb.ListSparepartStocksForLabelsParams) ([]sqlcdb.ListSparepartStocksForLabelsRow, error)
+	CountSparepartStocks(ctx context.Context, arg sqlcdb.CountSparepartStocksParams) (int64, error)
+	ListSparepartStockItems(ctx context.Context, arg sqlcdb.ListSparepartStockItemsParams) ([]sqlcdb.ListSparepartStockItemsRow, error)
+	CountSparepartStockItems(ctx context.Context, arg sqlcdb.CountSparepartStockItemsParams) (int64, error)
+	// Quantities given with a unit are checked against the unit of their sparepart
+	GetSparepartMaster(ctx context.Context, id int32) (sqlcdb.ListSparepart, error)
+	CreateSparepartStock(ctx context.Context, arg sqlcdb.CreateSparepartStockParams) (sqlcdb.SparepartStockItem, error)
+	CreateSparepartStocksBatch(ctx context.Context, arg sqlcdb.CreateSparepartStocksBatchParams) ([]sqlcdb.SparepartStockItem, error)
+	UpdateSparepartStock(ctx context.Context, arg sqlcdb.UpdateSparepartStockParams) (sqlcdb.SparepartStockItem, error)
+	UpdateSparepartStockDocumentation(ctx context.Context, arg sqlcdb.UpdateSparepartStockDocumentationParams) (sqlcdb.SparepartStockItem, error)
+	DeleteSparepartStock(ctx context.Context, id int32) error
+	RestoreSparepartStock(ctx context.Context, id int32) (sqlcdb.SparepartStockItem, error)
+	ListLocationCompletenessByIDs(ctx context.Context, arg sqlcdb.ListLocationCompletenessByIDsParams) ([]sqlcdb.ListLocationCompletenessByIDsRow, error)
+	ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]sqlcdb.ContactPerson, error)
+	ListNearestStock(ctx context.Context, arg sqlcdb.ListNearestStockParams) ([]sqlcdb.ListNearestStockRow, error)
+
+	// Transfers between locations; the stock writes run within one transaction
+	GetSparepartStockByKeyForUpdate(ctx context.Context, arg sqlcdb.GetSparepartStockByKeyForUpdateParams) (sqlcdb.SparepartStockItem, error)
+	GetActiveLocationForShare(ctx context.Context, id int32) (sqlcdb.Location, error)
+	TransferOutSparepartStock(ctx context.Context, arg sqlcdb.TransferOutSparepartStockParams) (sqlcdb.SparepartStockItem, error)
+	TransferInSparepartStock(ctx context.Context, arg sqlcdb.TransferInSparepartStockParams) (sqlcdb.SparepartStockItem, error)
+	CreateStockTransfer(ctx context.Context, arg sqlcdb.CreateStockTransferParams) (sqlcdb.StockTransfer, error)
+	ListStockTransfers(ctx context.Context, arg sqlcdb.ListStockTransfersParams) ([]sqlcdb.ListStockTransfersRow, error)
+	CountStockTransfers(ctx context.Context, arg sqlcdb.CountStockTransfersParams) (int64, error)
+
+	// Stock opname sessions; counts, submission and approval each run within one transaction
+	CreateStockOpname(ctx context.Context, arg sqlcdb.CreateStockOpnameParams) (sqlcdb.StockOpname, error)
+	GetStockOpname(ctx context.Context, id int32) (sqlcdb.GetStockOpnameRow, error)
+	GetStockOpnameForUpdate(ctx context.Context, id int32) (sqlcdb.StockOpname, error)
+	ListStockOpnames(ctx context.Context, arg sqlcdb.ListStockOpnamesParams) ([]sqlcdb.ListStockOpnamesRow, error)
+	CountStockOpnames(ctx context.Context, arg sqlcdb.CountStockOpnamesParams) (int64, error)
+	UpsertStockOpnameItem(ctx context.Context, arg sqlcdb.UpsertStockOpnameItemParams) (sqlcdb.StockOpnameItem, error)
+	ListStockOpnameItems(ctx context.Context, opnameID int32) ([]sqlcdb.ListStockOpnameItemsRow, error)
+	SubmitStockOpname(ctx context.Context, arg sqlcdb.SubmitStockOpnameParams) (sqlcdb.StockOpname, error)
+	ApproveStockOpname(ctx context.Context, arg sqlcdb.ApproveStockOpnameParams) (sqlcdb.StockOpname, error)
+	AdjustSparepartStock(ctx context.Context, arg sqlcdb.AdjustSparepartStockParams) (sqlcdb.SparepartStockItem, error)
+	SetStockOpnameItemAdjustment(ctx context.Context, arg sqlcdb.SetStockOpnameItemAdjustmentParams) error
+
+	// Sparepart requests of the field teams; every status change runs within one transaction,
+	// and fulfilling a request moves its stock as transfers
+	CreateSparepartRequest(ctx context.Context, arg sqlcdb.CreateSparepartRequestParams) (sqlcdb.SparepartRequest, error)
+	CreateSparepartRequestItem(ctx context.Context, arg sqlcdb.CreateSparepartRequestItemParams) (sqlcdb.SparepartRequestItem, error)
+	CreateSparepartRequestEvent(ctx context.Context, arg sqlcdb.CreateSparepartRequestEventParams) error
+	GetSparepartRequest(ctx context.Context, id int32) (sqlcdb.GetSparepartRequestRow, error)
+	GetSparepartRequestForUpdate(ctx context.Context, id int32) (sqlcdb.SparepartRequest, error)
+	ListSparepartRequests(ctx context.Context, arg sqlcdb.ListSparepartRequestsParams) ([]sqlcdb.ListSparepartRequestsRow, error)
+	CountSparepartRequests(ctx context.Context, arg sqlcdb.CountSparepartRequestsParams) (int64, error)
+	ListSparepartRequestItems(ctx context.Context, requestID int32) ([]sqlcdb.ListSparepartRequestItemsRow, error)
+	ListSparepartRequestEvents(ctx context.Context, requestID int32) ([]sqlcdb.SparepartRequestEvent, error)
+	SetSparepartRequestStatus(ctx context.Context, arg sqlcdb.SetSparepartRequestStatusParams) (sqlcdb.SparepartRequest, error)
+	FulfillSparepartRequest(ctx context.Context, arg sqlcdb.FulfillSparepartRequestParams) (sqlcdb.SparepartRequest, error)
+	SetSparepartRequestItemTransfer(ctx context.Context, arg sqlcdb.SetSparepartRequestItemTransferParams) error
+
+	// Goods receipts of incoming shipments; creating a receipt and confirming it, which adds its
+	// items to the stock, each run within one transaction
+	CreateGoodsReceipt(ctx context.Context, arg sqlcdb.CreateGoodsReceiptParams) (sqlcdb.GoodsReceipt, error)
+	CreateGoodsReceiptItem(ctx context.Context, arg sqlcdb.CreateGoodsReceiptItemParams) (sqlcdb.GoodsReceiptItem, error)
+	GetGoodsReceipt(ctx context.Context, id int32) (sqlcdb.GetGoodsReceiptRow, error)
+	GetGoodsReceiptForUpdate(ctx context.Context, id int32) (sqlcdb.GoodsReceipt, error)
+	ListGoodsReceipts(ctx context.Context, arg sqlcdb.ListGoodsReceiptsParams) ([]sqlcdb.ListGoodsReceiptsRow, error)
+	CountGoodsReceipts(ctx context.Context, arg sqlcdb.CountGoodsReceiptsParams) (int64, error)
+	ListGoodsReceiptItems(ctx context.Context, receiptID int32) ([]sqlcdb.ListGoodsReceiptItemsRow, error)
+	ConfirmGoodsReceipt(ctx context.Context, arg sqlcdb.ConfirmGoodsReceiptParams) (sqlcdb.GoodsReceipt, error)
+	SetGoodsReceiptItemStock(ctx context.Context, arg sqlcdb.SetGoodsReceiptItemStockParams) error
+	ApplyGoodsReceiptItemCost(ctx context.Context, arg sqlcdb.ApplyGoodsReceiptItemCostParams) error
+
+	// Purchase orders; a receipt for an order locks it while linking or confirming, and
+	// confirming moves the order to PARTIAL or RECEIVED
+	CreatePurchaseOrder(ctx context.Context, arg sqlcdb.CreatePurchaseOrderParams) (sqlcdb.PurchaseOrder, error)
+	CreatePurchaseOrderItem(ctx context.Context, arg sqlcdb.CreatePurchaseOrderItemParams) (sqlcdb.PurchaseOrderItem, error)
+	GetPurchaseOrder(ctx context.Context, id int32) (sqlcdb.GetPurchaseOrderRow, error)
+	GetPurchaseOrderForUpdate(ctx context.Context, id int32) (sqlcdb.PurchaseOrder, error)
+	ListPurchaseOrders(ctx context.Context, arg sqlcdb.ListPurchaseOrdersParams) ([]sqlcdb.ListPurchaseOrdersRow, error)
+	CountPurchaseOrders(ctx context.Context, arg sqlcdb.CountPurchaseOrdersParams) (int64, error)
+	ListPurchaseOrderItems(ctx context.Context, orderID int32) ([]sqlcdb.ListPurchaseOrderItemsRow, error)
+	PlacePurchaseOrder(ctx context.Context, arg sqlcdb.PlacePurchaseOrderParams) (sqlcdb.PurchaseOrder, error)
+	UpdatePurchaseOrderReceivedStatus(ctx context.Context, id int32) error
+
+	// Serial numbers of the units of a stock item; registration locks the stock item within one
+	// transaction so its units never exceed its quantity
+	GetSparepartStockForUpdate(ctx context.Context, id int32) (sqlcdb.SparepartStockItem, error)
+	CountStockUnits(ctx context.Context, stockItemID int32) (int64, error)
+	ListStockUnitConflicts(ctx context.Context, arg sqlcdb.ListStockUnitConflictsParams) ([]sqlcdb.ListStockUnitConflictsRow, error)
+	CreateStockUnit(ctx context.Context, arg sqlcdb.CreateStockUnitParams) (sqlcdb.StockUnit, error)
+	ListStockUnits(ctx context.Context, stockItemID int32) ([]sqlcdb.StockUnit, error)
+	ScanStockUnit(ctx context.Context, code string) (sqlcdb.ScanStockUnitRow, error)
+	DeleteStockUnit(ctx context.Context, arg sqlcdb.DeleteStockUnitParams) (int64, error)
+	SetStockUnitWarranty(ctx context.Context, arg sqlcdb.SetStockUnitWarrantyParams) (sqlcdb.StockUnit, error)
+	ListExpiringWarranties(ctx context.Context, arg sqlcdb.ListExpiringWarrantiesParams) ([]sqlcdb.ListExpiringWarrantiesRow, error)
+	CountExpiringWarranties(ctx context.Context, arg sqlcdb.CountExpiringWarrantiesParams) (int64, error)
+
+	// Damage reports of stock items; reporting locks the stock item within one transaction to
+	// move the damaged quantity out of it
+	CreateDamageReport(ctx context.Context, arg sqlcdb.CreateDamageReportParams) (sqlcdb.DamageReport, error)
+	GetDamageReport(ctx context.Context, id int32) (sqlcdb.GetDamageReportRow, error)
+	ListDamageReports(ctx context.Context, arg sqlcdb.ListDamageReportsParams) ([]sqlcdb.ListDamageReportsRow, error)
+	CountDamageReports(ctx context.Context, arg sqlcdb.CountDamageReportsParams) (int64, error)
+	ListOpenDamageReportsForExport(ctx context.Context, arg sqlcdb.ListOpenDamageReportsForExportParams) ([]sqlcdb.ListOpenDamageReportsForExportRow, error)
+	ResolveDamageReport(ctx context.Context, arg sqlcdb.ResolveDamageReportParams) (sqlcdb.DamageReport, error)
+
+	// Disposals write quantities off a stock item for good; disposing locks the stock item
+	// within one transaction and keeps its quantity afterwards for the stock ledger
+	CreateStockDisposal(ctx context.Context, arg sqlcdb.CreateStockDisposalParams) (sqlcdb.StockDisposal, error)
+	GetStockDisposal(ctx context.Context, id int32) (sqlcdb.GetStockDisposalRow, error)
+	ListStockDisposals(ctx context.Context, arg sqlcdb.ListStockDisposalsParams) ([]sqlcdb.ListStockDisposalsRow, error)
+	CountStockDisposals(ctx context.Context, arg sqlcdb.CountStockDisposalsParams) (int64, error)
+	SummarizeStockDisposals(ctx context.Context, arg sqlcdb.SummarizeStockDisposalsParams) ([]sqlcdb.SummarizeStockDisposalsRow, error)
+
+	// Work orders are maintenance tickets at a site; closing one locks it and takes its
+	// spareparts out of the site's stock within one transaction
+	CreateWorkOrder(ctx context.Context, arg sqlcdb.CreateWorkOrderParams) (sqlcdb.WorkOrder, error)
+	GetWorkOrder(ctx context.Context, id int32) (sqlcdb.GetWorkOrderRow, error)
+	GetWorkOrderForUpdate(ctx context.Context, id int32) (sqlcdb.WorkOrder, error)
+	ListWorkOrders(ctx context.Context, arg sqlcdb.ListWorkOrdersParams) ([]sqlcdb.ListWorkOrdersRow, error)
+	CountWorkOrders(ctx context.Context, arg sqlcdb.CountWorkOrdersParams) (int64, error)
+	DeleteWorkOrderItems(ctx context.Context, workOrderID int32) error
+	CreateWorkOrderItem(ctx context.Context, arg sqlcdb.CreateWorkOrderItemParams) (sqlcdb.WorkOrderItem, error)
+	ListWorkOrderItems(ctx context.Context, workOrderID int32) ([]sqlcdb.ListWorkOrderItemsRow, error)
+	SetWorkOrderItemConsumption(ctx context.Context, arg sqlcdb.SetWorkOrderItemConsumptionParams) error
+	DeleteWorkOrderTools(ctx context.Context, workOrderID int32) error
+	CreateWorkOrderTool(ctx context.Context, arg sqlcdb.CreateWorkOrderToolParams) (sqlcdb.WorkOrderTool, error)
+	ListWorkOrderTools(ctx context.Context, workOrderID int32) ([]sqlcdb.ListWorkOrderToolsRow, error)
+	CloseWorkOrder(ctx context.Context, arg sqlcdb.CloseWorkOrderParams) (sqlcdb.WorkOrder, error)
+
+	// Spreadsheet imports look up the referenced locations and spareparts, then stage the rows
+	// with COPY and move them into stock items within one transaction
+	ListLocationsForImport(ctx context.Context, arg sqlcdb.ListLocationsForImportParams) ([]sqlcdb.Location, error)
+	ListSparepartMastersByNames(ctx context.Context, names []string) ([]sqlcdb.ListSparepart, error)
+	CopySparepartStockImport(ctx context.Context, arg []sqlcdb.CopySparepartStockImportParams) (int64, error)
+	ListSparepartStockImportConflicts(ctx context.Context) ([]int32, error)
+	CreateSparepartStocksFromImport(ctx context.Context) ([]sqlcdb.SparepartStockItem, error)
+
+	// Purging removes soft deleted rows for good, within one transaction
+	PurgeSparepartStocks(ctx context.Context, deletedBefore pgtype.Timestamptz) ([]sqlcdb.PurgeSparepartStocksRow, error)
+	PurgeToolsAlkers(ctx context.Context, deletedBefore pgtype.Timestamptz) ([]sqlcdb.PurgeToolsAlkersRow, error)
+	PurgeContactPersons(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error)
+	PurgeLocations(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error)
+	PurgeSparepartMasters(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error)
+
+	// WithinTransaction runs fn with a repository bound to a single database transaction
+	WithinTransaction(ctx context.Context, fn func(repo SparepartStockRepository) error) error
+}
+
+// ToolsAlkerRepository provides access to tools alker items
+type ToolsAlkerRepository interface {
+	GetToolsAlker(ctx context.Context, id int32) (sqlcdb.GetToolsAlkerRow, error)
+	ListToolsAlkers(ctx context.Context, arg sqlcdb.ListToolsAlkersParams) ([]sqlcdb.ListToolsAlkersRow, error)
+	ListToolsAlkersByLocation(ctx context.Context, locationID int32) ([]sqlcdb.ListToolsAlkersByLocationRow, error)
+	ListToolsAlkersForExport(ctx context.Context, arg sqlcdb.ListToolsAlkersForExportParams) ([]sqlcdb.ListToolsAlkersForExportRow, error)
+	CountToolsAlkers(ctx context.Context, arg sqlcdb.CountToolsAlkersParams) (int64, error)
+	ListToolsAlkerItems(ctx context.Context, arg sqlcdb.ListToolsAlkerItemsParams) ([]sqlcdb.ListToolsAlkerItemsRow, error)
+	CountToolsAlkerItems(ctx context.Context, arg sqlcdb.CountToolsAlkerItemsParams) (int64, error)
+	CreateToolsAlker(ctx context.Context, arg sqlcdb.CreateToolsAlkerParams) (sqlcdb.ToolsAlkerItem, error)
+	CreateToolsAlkersBatch(ctx context.Context, arg sqlcdb.CreateToolsAlkersBatchParams) ([]sqlcdb.ToolsAlkerItem, error)
+	UpdateToolsAlker(ctx context.Context, arg sqlcdb.UpdateToolsAlkerParams) (sqlcdb.ToolsAlkerItem, error)
+	UpdateToolsAlkerDocumentation(ctx context.Context, arg sqlcdb.UpdateToolsAlkerDocumentationParams) (sqlcdb.ToolsAlkerItem, error)
+	DeleteToolsAlker(ctx context.Context, id int32) error
+	RestoreToolsAlker(ctx context.Context, id int32) (sqlcdb.ToolsAlkerItem, error)
+	ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]sqlcdb.ContactPerson, error)
+
+	// Checkouts lend tools to technicians; a checkout locks the item within one transaction
+	// so open checkouts never exceed its quantity
+	GetToolsAlkerForUpdate(ctx context.Context, id int32) (sqlcdb.ToolsAlkerItem, error)
+	SumOpenToolsAlkerCheckouts(ctx context.Context, toolsAlkerItemID int32) (int32, error)
+	CreateToolsAlkerCheckout(ctx context.Context, arg sqlcdb.CreateToolsAlkerCheckoutParams) (sqlcdb.ToolsAlkerCheckout, error)
+	CheckInToolsAlkerCheckout(ctx context.Context, arg sqlcdb.CheckInToolsAlkerCheckoutParams) (sqlcdb.ToolsAlkerCheckout, error)
+	GetToolsAlkerCheckout(ctx context.Context, id int32) (sqlcdb.GetToolsAlkerCheckoutRow, error)
+	ListToolsAlkerCheckouts(ctx context.Context, arg sqlcdb.ListToolsAlkerCheckoutsParams) ([]sqlcdb.ListToolsAlkerCheckoutsRow, error)
+	CountToolsAlkerCheckouts(ctx context.Context, arg sqlcdb.CountToolsAlkerCheckoutsParams) (int64, error)
+	GetTechnician(ctx context.Context, id int32) (sqlcdb.Technician, error)
+
+	// WithinToolsAlkerTransaction runs fn with a repository bound to a single database transaction
+	WithinToolsAlkerTransaction(ctx context.Context, fn func(repo ToolsAlkerRepository) error) error
+}
+
+// StockSummaryRepository provides access to the precomputed stock summary views and the stock ledger
+type StockSummaryRepository interface {
+	ListStockSummaryByLocation(ctx context.Context, arg sqlcdb.ListStockSummaryByLocationParams) ([]sqlcdb.StockSummaryByLocation, error)
+	ListStockSummaryBySparepart(ctx context.Context) ([]sqlcdb.StockSummaryBySparepart, error)
+	ListStockSummaryByRegion(ctx context.Context) ([]sqlcdb.StockSummaryByRegion, error)
+	ListStockTrend(ctx context.Context, arg sqlcdb.ListStockTrendParams) ([]sqlcdb.ListStockTrendRow, error)
+	ListStockSnapshotTrend(ctx context.Context, arg sqlcdb.ListStockSnapshotTrendParams) ([]sqlcdb.ListStockSnapshotTrendRow, error)
+	ListStockConsumption(ctx context.Context, arg sqlcdb.ListStockConsumptionParams) ([]sqlcdb.ListStockConsumptionRow, error)
+	ListStockMovements(ctx context.Context, arg sqlcdb.ListStockMovementsParams) ([]sqlcdb.ListStockMovementsRow, error)
+	CountStockMovements(ctx context.Context, arg sqlcdb.CountStockMovementsParams) (int64, error)
+	ListStockValuation(ctx context.Context, arg sqlcdb.ListStockValuationParams) ([]sqlcdb.ListStockValuationRow, error)
+}
+
+// StockLevelRepository provides the minimum and maximum stock levels configured per location
+// and sparepart, and the planning report comparing the stock against them
+type StockLevelRepository interface {
+	ListStockLevels(ctx context.Context, arg sqlcdb.ListStockLevelsParams) ([]sqlcdb.ListStockLevelsRow, error)
+	CountStockLevels(ctx context.Context, arg sqlcdb.CountStockLevelsParams) (int64, error)
+	UpsertStockLevel(ctx context.Context, arg sqlcdb.UpsertStockLevelParams) (sqlcdb.StockLevel, error)
+	DeleteStockLevel(ctx context.Context, id int32) (int64, error)
+	ListStockLevelPlanning(ctx context.Context, arg sqlcdb.ListStockLevelPlanningParams) ([]sqlcdb.ListStockLevelPlanningRow, error)
+}
+
+// DashboardRepository provides the headline numbers and aggregates for the dashboard
+type DashboardRepository interface {
+	GetDashboardKPIs(ctx context.Context) (sqlcdb.GetDashboardKPIsRow, error)
+	ListStockQuantityByType(ctx context.Context) ([]sqlcdb.ListStockQuantityByTypeRow, error)
+	GetStockSummaryTotals(ctx context.Context) (sqlcdb.GetStockSummaryTotalsRow, error)
+	ListStockTotalsByArea(ctx context.Context) ([]sqlcdb.ListStockTotalsByAreaRow, error)
+	ListStockTotalsByItemType(ctx context.Context) ([]sqlcdb.ListStockTotalsByItemTypeRow, error)
+	ListLowestQuantityStockItems(ctx context.Context, limit int32) ([]sqlcdb.ListLowestQuantityStockItemsRow, error)
+}
+
+// ChangeHistoryRepository provides the per-record change history written by the history triggers
+type ChangeHistoryRepository interface {
+	ListRecordChanges(ctx context.Context, arg sqlcdb.ListRecordChangesParams) ([]sqlcdb.ListRecordChangesRow, error)
+	CountRecordChanges(ctx context.Context, arg sqlcdb.CountRecordChangesParams) (int64, error)
+
+	// The stock item timeline expands the change history into events
+	ListStockHistory(ctx context.Context, arg sqlcdb.ListStockHistoryParams) ([]sqlcdb.ListStockHistoryRow, error)
+	CountStockHistory(ctx context.Context, arg sqlcdb.CountStockHistoryParams) (int64, error)
+}
+
+// AnomalyRepository provides the anomalies flagged by the background analyzer
+type AnomalyRepository interface {
+	GetAnomaly(ctx context.Context, id int64) (sqlcdb.Anomaly, error)
+	ListAnomalies(ctx context.Context, arg sqlcdb.ListAnomaliesParams) ([]sqlcdb.Anomaly, error)
+	CountAnomalies(ctx context.Context, arg sqlcdb.CountAnomaliesParams) (int64, error)
+	AcknowledgeAnomaly(ctx context.Context, arg sqlcdb.AcknowledgeAnomalyParams) (sqlcdb.Anomaly, error)
+	ResolveAnomaly(ctx context.Context, arg sqlcdb.ResolveAnomalyParams) (sqlcdb.Anomaly, error)
+}
+
+// AlertRuleRepository provides CRUD access to the alert rules
+type AlertRuleRepository interface {
+	GetAlertRule(ctx context.Context, id int32) (sqlcdb.AlertRule, error)
+	ListAlertRules(ctx context.Context, arg sqlcdb.ListAlertRulesParams) ([]sqlcdb.AlertRule, error)
+	CountAlertRules(ctx context.Context, enabled pgtype.Bool) (int64, error)
+	CreateAlertRule(ctx context.Context, arg sqlcdb.CreateAlertRuleParams) (sqlcdb.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, arg sqlcdb.UpdateAlertRuleParams) (sqlcdb.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, id int32) error
+}
+
+// AlertEvaluationRepository provides what the alert rule evaluator reads and the firing state it keeps
+type AlertEvaluationRepository interface {
+	ListEnabledAlertRules(ctx context.Context) ([]sqlcdb.AlertRule, error)
+	ListAlertRuleQuantities(ctx context.Context, arg sqlcdb.ListAlertRuleQuantitiesParams) ([]sqlcdb.ListAlertRuleQuantitiesRow, error)
+	ListAlertRuleFirings(ctx context.Context, ruleID int32) ([]sqlcdb.AlertRuleFiring, error)
+	CreateAlertRuleFiring(ctx context.Context, arg sqlcdb.CreateAlertRuleFiringParams) error
+	DeleteAlertRuleFiring(ctx context.Context, arg sqlcdb.DeleteAlertRuleFiringParams) error
+}
+
+// NotificationRepository provides the in-app notifications and delivery preferences of a user
+type NotificationRepository interface {
+	GetNotification(ctx context.Context, arg sqlcdb.GetNotificationParams) (sqlcdb.Notification, error)
+	ListNotifications(ctx context.Context, arg sqlcdb.ListNotificationsParams) ([]sqlcdb.Notification, error)
+	CountNotifications(ctx context.Context, arg sqlcdb.CountNotificationsParams) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg sqlcdb.MarkNotificationReadParams) (sqlcdb.Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, userID string) (int64, error)
+	CreateNotifications(ctx context.Context, arg sqlcdb.CreateNotificationsParams) (int64, error)
+	ListNotificationPreferences(ctx context.Context, userID string) ([]sqlcdb.NotificationPreference, error)
+	UpsertNotificationPreference(ctx context.Context, arg sqlcdb.UpsertNotificationPreferenceParams) (sqlcdb.NotificationPreference, error)
+}
+
+// SavedFilterRepository provides the saved listing filters of a user
+type SavedFilterRepository interface {
+	ListSavedFilters(ctx context.Context, arg sqlcdb.ListSavedFiltersParams) ([]sqlcdb.SavedFilter, error)
+	GetSavedFilter(ctx context.Context, arg sqlcdb.GetSavedFilterParams) (sqlcdb.SavedFilter, error)
+	CreateSavedFilter(ctx context.Context, arg sqlcdb.CreateSavedFilterParams) (sqlcdb.SavedFilter, error)
+	UpdateSavedFilter(ctx context.Context, arg sqlcdb.UpdateSavedFilterParams) (sqlcdb.SavedFilter, error)
+	DeleteSavedFilter(ctx context.Context, arg sqlcdb.DeleteSavedFilterParams) error
+	SetDefaultSavedFilter(ctx context.Context, arg sqlcdb.SetDefaultSavedFilterParams) error
+	UnsetDefaultSavedFilter(ctx context.Context, arg sqlcdb.UnsetDefaultSavedFilterParams) error
+}
+
+// DataQualityRepository provides the checks of the admin data quality report
+type DataQualityRepository interface {
+	GetDataQualityCounts(ctx context.Context, zeroBefore pgtype.Timestamptz) (sqlcdb.GetDataQualityCountsRow, error)
+	ListItemsWithoutPhotos(ctx context.Context, limit int32) ([]sqlcdb.ListItemsWithoutPhotosRow, error)
+	ListLocationsWithoutContact(ctx context.Context, limit int32) ([]sqlcdb.ListLocationsWithoutContactRow, error)
+	ListDuplicateMasterNames(ctx context.Context, limit int32) ([]sqlcdb.ListDuplicateMasterNamesRow, error)
+	ListStaleZeroQuantities(ctx context.Context, arg sqlcdb.ListStaleZeroQuantitiesParams) ([]sqlcdb.ListStaleZeroQuantitiesRow, error)
+	ListItemDocumentation(ctx context.Context) ([]sqlcdb.ListItemDocumentationRow, error)
+}
+
+// ShareLinkRepository manages the public share links of a location's stock and reads the
+// stock they expose
+type ShareLinkRepository interface {
+	CreateShareLink(ctx context.Context, arg sqlcdb.CreateShareLinkParams) (sqlcdb.ShareLink, error)
+	GetShareLink(ctx context.Context, id int32) (sqlcdb.ShareLink, error)
+	GetShareLinkByTokenHash(ctx context.Context, tokenHash string) (sqlcdb.ShareLink, error)
+	ListShareLinks(ctx context.Context, arg sqlcdb.ListShareLinksParams) ([]sqlcdb.ShareLink, error)
+	CountShareLinks(ctx context.Context, arg sqlcdb.CountShareLinksParams) (int64, error)
+	RevokeShareLink(ctx context.Context, id int32) (sqlcdb.ShareLink, error)
+	RecordShareLinkAccess(ctx context.Context, id int32) error
+	GetLocation(ctx context.Context, id int32) (sqlcdb.Location, error)
+	ListSparepartStocksByLocation(ctx context.Context, locationID int32) ([]sqlcdb.ListSparepartStocksByLocationRow, error)
+}
+
+// APIKeyRepository manages the API keys machine clients authenticate with
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, arg sqlcdb.CreateAPIKeyParams) (sqlcdb.ApiKey, error)
+	GetAPIKey(ctx context.Context, id int32) (sqlcdb.ApiKey, error)
+	ListAPIKeys(ctx context.Context, arg sqlcdb.ListAPIKeysParams) ([]sqlcdb.ApiKey, error)
+	CountAPIKeys(ctx context.Context, active pgtype.Bool) (int64, error)
+	RevokeAPIKey(ctx context.Context, id int32) (sqlcdb.ApiKey, error)
+}
+
+// APIKeyAuthRepository looks up the API key of a request and records its use
+type APIKeyAuthRepository interface {
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (sqlcdb.ApiKey, error)
+	TouchAPIKey(ctx context.Context, id int32) error
+}
+
+// ExportLogRepository records finished exports and lists them for the admin export log
+type ExportLogRepository interface {
+	CreateExportLog(ctx context.Context, arg sqlcdb.CreateExportLogParams) error
+	ListExportLogs(ctx context.Context, arg sqlcdb.ListExportLogsParams) ([]sqlcdb.ExportLog, error)
+	CountExportLogs(ctx context.Context, arg sqlcdb.CountExportLogsParams) (int64, error)
+	ListExportLogsForExport(ctx context.Context, arg sqlcdb.ListExportLogsForExportParams) ([]sqlcdb.ExportLog, error)
+}
+
+// AuthRepository reads the accounts that log in to the API
+type AuthRepository interface {
+	GetAppUser(ctx context.Context, id int32) (sqlcdb.AppUser, error)
+	GetAppUserByUsername(ctx context.Context, username string) (sqlcdb.AppUser, error)
+	CreateAppUser(ctx context.Context, arg sqlcdb.CreateAppUserParams) (sqlcdb.AppUser, error)
+	RecordAppUserLogin(ctx context.Context, id int32) error
+}
+
+// WebhookRepository manages the webhooks stock change events are sent to and lists their deliveries
+type WebhookRepository interface {
+	GetWebhook(ctx context.Context, id int32) (sqlcdb.Webhook, error)
+	ListWebhooks(ctx context.Context, arg sqlcdb.ListWebhooksParams) ([]sqlcdb.Webhook, error)
+	CountWebhooks(ctx context.Context) (int64, error)
+	CreateWebhook(ctx context.Context, arg sqlcdb.CreateWebhookParams) (sqlcdb.Webhook, error)
+	UpdateWebhook(ctx context.Context, arg sqlcdb.UpdateWebhookParams) (sqlcdb.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int32) error
+	ListWebhookDeliveries(ctx context.Context, arg sqlcdb.ListWebhookDeliveriesParams) ([]sqlcdb.WebhookDelivery, error)
+	CountWebhookDeliveries(ctx context.Context, arg sqlcdb.CountWebhookDeliveriesParams) (int64, error)
+}
+
+// WebhookDispatchRepository provides the event outbox and delivery queue the webhook dispatcher works through
+type WebhookDispatchRepository interface {
+	FanOutWebhookEvents(ctx context.Context, limit int32) (int64, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg sqlcdb.ClaimWebhookDeliveriesParams) ([]sqlcdb.ClaimWebhookDeliveriesRow, error)
+	MarkWebhookDeliveryDelivered(ctx context.Context, arg sqlcdb.MarkWebhookDeliveryDeliveredParams) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg sqlcdb.MarkWebhookDeliveryFailedParams) error
+}
+
+// SearchRepository searches stock items, tools alker items and master entries by text
+type SearchRepository interface {
+	SearchInventory(ctx context.Context, arg sqlcdb.SearchInventoryParams) ([]sqlcdb.SearchInventoryRow, error)
+}
+
+// FilterValueRepository lists the distinct values present in the data for the filter dropdowns
+type FilterValueRepository interface {
+	ListFilterValues(ctx context.Context, region pgtype.Text) ([]sqlcdb.ListFilterValuesRow, error)
+}
+
+// ExportJobRepository queues background exports and reads their status for the user who queued them
+type ExportJobRepository interface {
+	CreateExportJob(ctx context.Context, arg sqlcdb.CreateExportJobParams) (sqlcdb.ExportJob, error)
+	GetExportJob(ctx context.Context, arg sqlcdb.GetExportJobParams) (sqlcdb.ExportJob, error)
+}
+
+// ExportWorkerRepository provides the export job queue the export worker works through, the
+// rows it exports and the export log it records finished jobs in
+type ExportWorkerRepository interface {
+	ClaimExportJob(ctx context.Context, leaseSeconds int32) (sqlcdb.ExportJob, error)
+	CompleteExportJob(ctx context.Context, arg sqlcdb.CompleteExportJobParams) error
+	FailExportJob(ctx context.Context, arg sqlcdb.FailExportJobParams) error
+	ListSparepartStocksForExport(ctx context.Context, arg sqlcdb.ListSparepartStocksForExportParams) ([]sqlcdb.ListSparepartStocksForExportRow, error)
+	CreateExportLog(ctx context.Context, arg sqlcdb.CreateExportLogParams) error
+}
+
+// EmailDigestRepository provides what the email digest reports and the contact persons it is sent to
+type EmailDigestRepository interface {
+	ListLowStockDigestItems(ctx context.Context, threshold int32) ([]sqlcdb.ListLowStockDigestItemsRow, error)
+	ListPendingSparepartRequestDigest(ctx context.Context) ([]sqlcdb.ListPendingSparepartRequestDigestRow, error)
+	ListContactPersonEmails(ctx context.Context) ([]sqlcdb.ListContactPersonEmailsRow, error)
+}
+
+// MessageDeliveryRepository reads the messages sent to contact persons and their delivery status
+type MessageDeliveryRepository interface {
+	ListMessageDeliveries(ctx context.Context, arg sqlcdb.ListMessageDeliveriesParams) ([]sqlcdb.MessageDelivery, error)
+	CountMessageDeliveries(ctx context.Context, arg sqlcdb.CountMessageDeliveriesParams) (int64, error)
+}
+
+// MessageDispatchRepository provides the message delivery queue the message dispatcher works through
+type MessageDispatchRepository interface {
+	ExpireMessageDeliveries(ctx context.Context, maxAgeSeconds int32) (int64, error)
+	ClaimMessageDeliveries(ctx context.Context, arg sqlcdb.ClaimMessageDeliveriesParams) ([]sqlcdb.MessageDelivery, error)
+	MarkMessageDeliverySent(ctx context.Context, arg sqlcdb.MarkMessageDeliverySentParams) error
+	MarkMessageDeliveryFailed(ctx context.Context, arg sqlcdb.MarkMessageDeliveryFailedParams) error
+}
+
+// StorageUsageRepository lists the photos of every location for the storage usage report
+type StorageUsageRepository interface {
+	ListLocationPhotoPaths(ctx context.Context) ([]sqlcdb.ListLocationPhotoPathsRow, error)
+}
+
+// UploadFileRepository records the content hash and references of stored photos, so an
+// identical photo uploaded again reuses the stored file
+type UploadFileRepository interface {
+	AcquireUploadFile(ctx context.Context, sha256 string) (string, error)
+	CreateUploadFile(ctx context.Context, arg sqlcdb.CreateUploadFileParams) error
+	ReleaseUploadFile(ctx context.Context, path string) (int32, error)
+	DeleteReleasedUploadFile(ctx context.Context, path string) (int64, error)
+	SumUploadFileSize(ctx context.Context) (int64, error)
+}
+
+// GraphRepository provides the read-only queries behind the GraphQL endpoint; nested lists are
+// loaded for a batch of locations at once
+type GraphRepository interface {
+	GetLocation(ctx context.Context, id int32) (sqlcdb.Location, error)
+	ListLocations(ctx context.Context, arg sqlcdb.ListLocationsParams) ([]sqlcdb.Location, error)
+	CountLocations(ctx context.Context, arg sqlcdb.CountLocationsParams) (int64, error)
+	ListLocationsByIDs(ctx context.Context, ids []int32) ([]sqlcdb.Location, error)
+	ListSparepartStockItems(ctx context.Context, arg sqlcdb.ListSparepartStockItemsParams) ([]sqlcdb.ListSparepartStockItemsRow, error)
+	CountSparepartStockItems(ctx context.Context, arg sqlcdb.CountSparepartStockItemsParams) (int64, error)
+	ListSparepartStocksByLocations(ctx context.Context, locationIds []int32) ([]sqlcdb.ListSparepartStocksByLocationsRow, error)
+	ListToolsAlkersByLocations(ctx context.Context, locationIds []int32) ([]sqlcdb.ListToolsAlkersByLocationsRow, error)
+	ListContactPersonsByLocations(ctx context.Context, locationIds []int32) ([]sqlcdb.ContactPerson, error)
+}
+
+// Compile-time checks that Store implements every repository
+var (
+	_ LocationRepository        = (*Store)(nil)
+	_ ContactPersonRepository   = (*Store)(nil)
+	_ RegencyRepository         = (*Store)(nil)
+	_ ClusterRepository         = (*Store)(nil)
+	_ SparepartMasterRepository = (*Store)(nil)
+	_ SparepartStockRepository  = (*Store)(nil)
+	_ ToolsAlkerRepository      = (*Store)(nil)
+	_ StockSummaryRepository    = (*Store)(nil)
+	_ DashboardRepository       = (*Store)(nil)
+	_ ChangeHistoryRepository   = (*Store)(nil)
+	_ AnomalyRepository         = (*Store)(nil)
+	_ AlertRuleRepository       = (*Store)(nil)
+	_ AlertEvaluationRepository = (*Store)(nil)
+	_ NotificationRepository    = (*Store)(nil)
+	_ SavedFilterRepository     = (*Store)(nil)
+	_ ExportLogRepository       = (*Store)(nil)
+	_ DataQualityRepository     = (*Store)(nil)
+	_ ShareLinkRepository       = (*Store)(nil)
+	_ APIKeyRepository          = (*Store)(nil)
+	_ APIKeyAuthRepository      = (*Store)(nil)
+	_ AuthRepository            = (*Store)(nil)
+	_ WebhookRepository         = (*Store)(nil)
+	_ WebhookDispatchRepository = (*Store)(nil)
+	_ SearchRepository          = (*Store)(nil)
+	_ FilterValueRepository     = (*Store)(nil)
+	_ ExportJobRepository       = (*Store)(nil)
+	_ ExportWorkerRepository    = (*Store)(nil)
+	_ EmailDigestRepository     = (*Store)(nil)
+	_ MessageDeliveryRepository = (*Store)(nil)
+	_ MessageDispatchRepository = (*Store)(nil)
+	_ SupplierRepository        = (*Store)(nil)
+	_ TechnicianRepository      = (*Store)(nil)
+	_ UploadFileRepository      = (*Store)(nil)
+	_ StorageUsageRepository    = (*Store)(nil)
+	_ GraphRepository           = (*Store)(nil)
+
+	_ LocationRepository        = (*CachedStore)(nil)
+	_ ContactPersonRepository   = (*CachedStore)(nil)
+	_ RegencyRepository         = (*CachedStore)(nil)
+	_ ClusterRepository         = (*CachedStore)(nil)
+	_ SparepartMasterRepository = (*CachedStore)(nil)
+	_ DashboardRepository       = (*CachedStore)(nil)
+	_ FilterValueRepository     = (*CachedStore)(nil)
+	_ GraphRepository           = (*CachedStore)(nil)
+)
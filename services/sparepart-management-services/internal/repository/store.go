@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sparepart-management-services/internal/database"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Store implements the repositories with sqlc queries
+type Store struct {
+	*sqlcdb.Queries
+	pool *pgxpool.Pool
+}
+
+// NewStore builds the store; the hot list queries run as prepared statements (see database.PreparedQueryDB)
+func NewStore(pool *pgxpool.Pool) *Store {
+	return &Store{Queries: sqlcdb.New(database.NewPreparedQueryDB(pool)), pool: pool}
+}
+
+// WithinTransaction runs fn inside database.WithTransaction with queries bound to the transaction.
+// The transaction-bound store has no pool, so transactions cannot be nested.
+func (s *Store) WithinTransaction(ctx context.Context, fn func(repo SparepartStockRepository) error) error {
+	return database.WithTransaction(ctx, s.pool, func(ctx context.Context, tx pgx.Tx) error {
+		return fn(&Store{Queries: s.Queries.WithTx(tx)})
+	})
+}
+
+// WithinToolsAlkerTransaction is WithinTransaction for the tools alker repository
+func (s *Store) WithinToolsAlkerTransaction(ctx context.Context, fn func(repo ToolsAlkerRepository) error) error {
+	return database.WithTransaction(ctx, s.pool, func(ctx context.Context, tx pgx.Tx) error {
+		return fn(&Store{Queries: s.Queries.WithTx(tx)})
+	})
+}
+
+// WithinSparepartMasterTransaction is WithinTransaction for the sparepart master repository
+func (s *Store) WithinSparepartMasterTransaction(ctx context.Context, fn func(repo SparepartMasterRepository) error) error {
+	return database.WithTransaction(ctx, s.pool, func(ctx context.Context, tx pgx.Tx) error {
+		return fn(&Store{Queries: s.Queries.WithTx(tx)})
+	})
+}
+
+// RefreshStockSummaries recomputes the stock summary materialized views
+func (s *Store) RefreshStockSummaries(ctx context.Context) error {
+	if err := s.RefreshStockSummaryByLocation(ctx); err != nil {
+		return fmt.Errorf("failed to refresh stock summary by location: %w", err)
+	}
+	if err := s.RefreshStockSummaryBySparepart(ctx); err != nil {
+		return fmt.Errorf("failed to refresh stock summary by sparepart: %w", err)
+	}
+	if err := s.RefreshStockSummaryByRegion(ctx); err != nil {
+		return fmt.Errorf("failed to refresh stock summary by region: %w", err)
+	}
+	return nil
+}
+
+// AnomalyRules are the thresholds of the anomaly analyzer
+type AnomalyRules struct {
+	// Since bounds the ledger and history rows that are analyzed
+	Since time.Time
+	// MinDropPercent flags a stock item whose quantity dropped by at least this much in a day
+	MinDropPercent int32
+	// MinDeletes flags an actor that deleted at least this many records in a day
+	MinDeletes int32
+}
+
+// DetectAnomalies runs every anomaly check and returns the number of anomalies flagged or updated
+func (s *Store) DetectAnomalies(ctx context.Context, rules AnomalyRules) (int64, error) {
+	since := pgtype.Timestamptz{Time: rules.Since, Valid: true}
+
+	drops, err := s.DetectQuantityDrops(ctx, sqlcdb.DetectQuantityDropsParams{Since: since, MinDropPercent: rules.MinDropPercent})
+	if err != nil {
+		return 0, fmt.Errorf("failed to detect quantity drops: %w", err)
+	}
+	deletes, err := s.DetectRepeatedDeletes(ctx, sqlcdb.DetectRepeatedDeletesParams{Since: since, MinDeletes: rules.MinDeletes})
+	if err != nil {
+		return 0, fmt.Errorf("failed to detect repeated deletes: %w", err)
+	}
+	removals, err := s.DetectPhotoRemovals(ctx, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to detect photo removals: %w", err)
+	}
+	return drops + deletes + removals, nil
+}
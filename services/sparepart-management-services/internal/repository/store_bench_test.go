@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"sparepart-management-services/internal/config"
+	"sparepart-management-services/internal/database"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+)
+
+// BenchmarkListSparepartStocks compares the stock listing path (count + first page) across
+// query exec modes. It needs a migrated, seeded database:
+//
+//	SPAREPART_BENCH_DATABASE_URL=postgresql://... go test -run '^$' -bench ListSparepartStocks ./internal/repository
+//
+// "describe" is the path without prepared statements, "describe_prepared_hot" is the same
+// pool with the hot queries prepared by the Store, and "prepare" caches every statement.
+func BenchmarkListSparepartStocks(b *testing.B) {
+	dbURL := os.Getenv("SPAREPART_BENCH_DATABASE_URL")
+	if dbURL == "" {
+		b.Skip("SPAREPART_BENCH_DATABASE_URL not set")
+	}
+
+	cases := []struct {
+		name       string
+		cfg        config.DatabaseConfig
+		preparedDB bool
+	}{
+		{name: "simple_protocol", cfg: config.DatabaseConfig{PreferSimpleProtocol: true}},
+		{name: "describe", cfg: config.DatabaseConfig{StatementCacheMode: "describe"}},
+		{name: "describe_prepared_hot", cfg: config.DatabaseConfig{StatementCacheMode: "describe"}, preparedDB: true},
+		{name: "prepare", cfg: config.DatabaseConfig{StatementCacheMode: "prepare"}, preparedDB: true},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			tc.cfg.URL = dbURL
+			pool, err := database.Connect(tc.cfg)
+			if err != nil {
+				b.Fatalf("failed to connect: %v", err)
+			}
+			defer pool.Close()
+
+			queries := sqlcdb.New(pool)
+			if tc.preparedDB {
+				queries = NewStore(pool).Queries
+			}
+
+			ctx := context.Background()
+			filters := sqlcdb.CountSparepartStocksParams{}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := queries.CountSparepartStocks(ctx, filters); err != nil {
+					b.Fatalf("count failed: %v", err)
+				}
+				if _, err := queries.ListSparepartStocks(ctx, sqlcdb.ListSparepartStocksParams{Limit: 10}); err != nil {
+					b.Fatalf("list failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package routes
 
 import (
-	"sparepart-management-services/internal/config"
+	"sparepart-management-services/internal/app"
 	"sparepart-management-services/internal/handlers"
+	"sparepart-management-services/internal/middleware"
+	"sparepart-management-services/internal/ratelimit"
+	"sparepart-management-services/internal/repository"
 	"sparepart-management-services/internal/utils"
 	"time"
 
@@ -11,84 +14,472 @@ import (
 
 var appStartTime = time.Now()
 
-func SetupRoutes(r *gin.Engine) {
+func SetupRoutes(r *gin.Engine, container *app.Container) {
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
 		uptimeSeconds := time.Since(appStartTime).Seconds()
 		c.JSON(200, gin.H{
 			"status":         "ok",
-			"timestamp":      time.Now().Format(time.RFC3339),
+			"timestamp":      time.Now().UTC().Format(time.RFC3339),
 			"uptime":         uptimeSeconds,
 			"uptimeReadable": utils.FormatUptime(uptimeSeconds),
 		})
 	})
 
+	// Readiness: database and uploads directory (writable, enough free space)
+	healthHandler := handlers.NewHealthHandler(container.DB, container.Config.Upload.Dir, container.Config.Upload.MinFreeBytes, container.Logger)
+	r.GET("/ready", healthHandler.Ready)
+
+	// Handler dependencies
+	// Location, master and contact person lookups are cached and invalidated on writes
+	queries := repository.NewCachedStore(container.Store, container.Config.Cache.TTL, container.Config.Cache.DashboardTTL)
+	logger := container.Logger
+
+	// Request time budgets: exports and report downloads render whole files, so they get a
+	// longer budget on separate groups sharing the same path prefix
+	requestTimeout := middleware.Timeout(container.Config.Timeout.Request)
+	exportTimeout := middleware.Timeout(container.Config.Timeout.Export)
+
+	// Exports are rate limited per API key or client IP, in Redis when one is configured
+	exportLimit := middleware.RateLimit(ratelimit.New(container.Redis, "export", container.Config.Limit.ExportLimit, time.Minute), logger)
+	// Logins per client IP, so passwords can't be guessed at speed
+	loginLimit := middleware.RateLimit(ratelimit.New(container.Redis, "login", container.Config.Limit.LoginLimit, time.Minute), logger)
+
+	// Every export is recorded in the export log (see /sparepart/admin/export-log)
+	recordExport := func(entity, format string) gin.HandlerFunc {
+		return middleware.RecordExport(queries, entity, format, logger)
+	}
+
+	// Field-level change history, shared by the stock, tools alker and location routes
+	changeHistoryHandler := handlers.NewChangeHistoryHandler(queries, logger)
+
 	// API prefix routes
-	api := r.Group(config.App.App.APIPrefix)
+	api := r.Group(container.Config.App.APIPrefix)
 	// Sparepart routes group
 	sparepartApi := api.Group("/sparepart")
 	{
+		// Auth routes: login and token refresh are the only unauthenticated API routes besides
+		// the public share links and the signed report links
+		authHandler := handlers.NewAuthHandler(queries, container.Config.Auth, logger)
+		auth := sparepartApi.Group("/auth", requestTimeout)
+		{
+			auth.POST("/login", loginLimit, authHandler.Login)
+			auth.POST("/refresh", authHandler.Refresh)
+		}
+
+		// Every other route needs a valid access token (Authorization: Bearer <token>) or API
+		// key (X-API-Key); API keys may only change the stock and never reach the admin routes
+		apiKeyAuth := middleware.APIKey(queries, container.Config.App.APIPrefix+"/sparepart/stock", logger)
+		secured := sparepartApi.Group("", apiKeyAuth, middleware.Authenticate(container.Config.Auth.JWTSecret))
+
 		// Location routes
-		locationHandler := handlers.NewLocationHandler()
-		locations := sparepartApi.Group("/location")
+		locationHandler := handlers.NewLocationHandler(queries, logger)
+		locations := secured.Group("/location", requestTimeout)
 		{
 			locations.GET("", locationHandler.GetAll)
+			locations.GET("/completeness", locationHandler.GetCompleteness)
+			locations.GET("/geojson", locationHandler.GetGeoJSON)
 			locations.GET("/:id", locationHandler.GetByID)
 			locations.POST("", locationHandler.Create)
 			locations.PUT("/:id", locationHandler.Update)
+			locations.PATCH("/:id", locationHandler.Patch)
 			locations.DELETE("/:id", locationHandler.Delete)
+			locations.POST("/:id/restore", locationHandler.Restore)
+			locations.POST("/:id/deactivate", locationHandler.Deactivate)
+			locations.POST("/:id/activate", locationHandler.Activate)
+			locations.GET("/:id/changes", changeHistoryHandler.GetLocationChanges)
+		}
+
+		// Regency and cluster reference routes; a location must name an existing cluster
+		regencyHandler := handlers.NewRegencyHandler(queries, logger)
+		regencies := secured.Group("/regency", requestTimeout)
+		{
+			regencies.GET("", regencyHandler.GetAll)
+			regencies.GET("/:id", regencyHandler.GetByID)
+			regencies.POST("", regencyHandler.Create)
+			regencies.PUT("/:id", regencyHandler.Update)
+			regencies.DELETE("/:id", regencyHandler.Delete)
+		}
+
+		clusterHandler := handlers.NewClusterHandler(queries, logger)
+		clusters := secured.Group("/cluster", requestTimeout)
+		{
+			clusters.GET("", clusterHandler.GetAll)
+			clusters.GET("/:id", clusterHandler.GetByID)
+			clusters.POST("", clusterHandler.Create)
+			clusters.PUT("/:id", clusterHandler.Update)
+			clusters.DELETE("/:id", clusterHandler.Delete)
 		}
 
 		// Contact Person routes
-		contactPersonHandler := handlers.NewContactPersonHandler()
-		contactPersons := sparepartApi.Group("/contact-person")
+		contactPersonHandler := handlers.NewContactPersonHandler(queries, logger)
+		contactPersons := secured.Group("/contact-person", requestTimeout)
 		{
 			contactPersons.GET("", contactPersonHandler.GetAll)
 			contactPersons.GET("/:id", contactPersonHandler.GetByID)
 			contactPersons.POST("", contactPersonHandler.Create)
 			contactPersons.PUT("/:id", contactPersonHandler.Update)
+			contactPersons.PATCH("/:id", contactPersonHandler.Patch)
 			contactPersons.DELETE("/:id", contactPersonHandler.Delete)
+			contactPersons.POST("/:id/restore", contactPersonHandler.Restore)
 		}
 
 		// Sparepart Master routes
-		sparepartMasterHandler := handlers.NewSparepartMasterHandler()
-		sparepartMasters := sparepartApi.Group("/master")
+		sparepartMasterHandler := handlers.NewSparepartMasterHandler(queries, logger)
+		masterImportHandler := handlers.NewMasterImportHandler(queries, logger)
+		sparepartMasters := secured.Group("/master", requestTimeout)
 		{
 			sparepartMasters.GET("", sparepartMasterHandler.GetAll)
 			sparepartMasters.GET("/:id", sparepartMasterHandler.GetByID)
+			sparepartMasters.GET("/:id/availability", sparepartMasterHandler.GetAvailability)
 			sparepartMasters.POST("", sparepartMasterHandler.Create)
+			sparepartMasters.POST("/import", masterImportHandler.Import)
+			sparepartMasters.GET("/import/template", masterImportHandler.Template)
 			sparepartMasters.PUT("/:id", sparepartMasterHandler.Update)
+			sparepartMasters.PATCH("/:id", sparepartMasterHandler.Patch)
 			sparepartMasters.DELETE("/:id", sparepartMasterHandler.Delete)
+			sparepartMasters.POST("/:id/restore", sparepartMasterHandler.Restore)
 		}
 
 		// Sparepart Stock routes
-		sparepartStockHandler := handlers.NewSparepartStockHandler()
-		sparepartStocks := sparepartApi.Group("/stock")
+		sparepartStockHandler := handlers.NewSparepartStockHandler(queries, container.Uploads, container.Reports, container.Config.Upload.MaxStockPhotos, logger)
+		stockTransferHandler := handlers.NewStockTransferHandler(queries, logger)
+		stockUnitHandler := handlers.NewStockUnitHandler(queries, logger)
+		damageReportHandler := handlers.NewDamageReportHandler(queries, container.Uploads, container.Reports, logger)
+		stockDisposalHandler := handlers.NewStockDisposalHandler(queries, container.Uploads, logger)
+		stockImportHandler := handlers.NewStockImportHandler(queries, logger)
+		exportJobHandler := handlers.NewExportJobHandler(queries, container.Reports, logger)
+		sparepartStocks := secured.Group("/stock", requestTimeout)
+		stockExports := secured.Group("/stock", exportTimeout, exportLimit)
 		{
 			sparepartStocks.GET("", sparepartStockHandler.GetAll)
+			sparepartStocks.GET("/nearest", sparepartStockHandler.Nearest)
 			sparepartStocks.GET("/:id", sparepartStockHandler.GetByID)
 			sparepartStocks.POST("", sparepartStockHandler.Create)
+			sparepartStocks.POST("/batch", sparepartStockHandler.CreateBatch)
+			sparepartStocks.POST("/import", stockImportHandler.Import)
+			sparepartStocks.GET("/import/template", stockImportHandler.Template)
+			sparepartStocks.POST("/transfer", stockTransferHandler.Create)
+			sparepartStocks.GET("/transfer", stockTransferHandler.GetAll)
 			sparepartStocks.PUT("/:id", sparepartStockHandler.Update)
+			sparepartStocks.PATCH("/:id", sparepartStockHandler.Update)
 			sparepartStocks.DELETE("/:id", sparepartStockHandler.Delete)
-			sparepartStocks.GET("/export/pdf", sparepartStockHandler.ExportPDF)
-			sparepartStocks.GET("/export/excel", sparepartStockHandler.ExportExcel)
+			sparepartStocks.POST("/:id/restore", sparepartStockHandler.Restore)
+			sparepartStocks.POST("/:id/condition", sparepartStockHandler.ChangeCondition)
+			sparepartStocks.GET("/:id/changes", changeHistoryHandler.GetStockChanges)
+			sparepartStocks.GET("/:id/history", changeHistoryHandler.GetStockHistory)
+			stockExports.GET("/export/pdf", recordExport("SPAREPART_STOCK", "PDF"), sparepartStockHandler.ExportPDF)
+			stockExports.GET("/export/excel", recordExport("SPAREPART_STOCK", "EXCEL"), sparepartStockHandler.ExportExcel)
+			stockExports.GET("/export/csv", recordExport("SPAREPART_STOCK", "CSV"), sparepartStockHandler.ExportCSV)
+			sparepartStocks.POST("/export", exportLimit, middleware.RequireUser(), exportJobHandler.CreateStockExport)
+			stockExports.GET("/labels/pdf", recordExport("STOCK_LABELS", "PDF"), sparepartStockHandler.ExportLabelsPDF)
 			sparepartStocks.POST("/:id/photos", sparepartStockHandler.AddPhotos)
 			sparepartStocks.PUT("/:id/photos/:photo_index", sparepartStockHandler.UpdatePhoto)
+			sparepartStocks.PATCH("/:id/photos/:photo_index", sparepartStockHandler.UpdatePhotoMetadata)
 			sparepartStocks.DELETE("/:id/photos/:photo_index", sparepartStockHandler.DeletePhoto)
+			sparepartStocks.GET("/:id/qrcode", sparepartStockHandler.QRCode)
+			sparepartStocks.GET("/units/scan", stockUnitHandler.Scan)
+			sparepartStocks.GET("/:id/units", stockUnitHandler.GetAll)
+			sparepartStocks.POST("/:id/units", stockUnitHandler.Register)
+			sparepartStocks.DELETE("/:id/units/:unit_id", stockUnitHandler.Delete)
+			sparepartStocks.PUT("/:id/units/:unit_id/warranty", stockUnitHandler.SetWarranty)
+			sparepartStocks.POST("/:id/damage-reports", damageReportHandler.Create)
+			sparepartStocks.POST("/:id/disposals", middleware.RequireUser(), stockDisposalHandler.Create)
+		}
+
+		// Damage reports of stock items; resolving one closes it, so only admins can
+		damageReports := secured.Group("/damage-reports", requestTimeout)
+		damageReportExports := secured.Group("/damage-reports", exportTimeout, exportLimit)
+		{
+			damageReports.GET("", damageReportHandler.GetAll)
+			damageReportExports.GET("/export/excel", recordExport("DAMAGE_REPORT", "EXCEL"), damageReportHandler.ExportExcel)
+			damageReportExports.GET("/export/csv", recordExport("DAMAGE_REPORT", "CSV"), damageReportHandler.ExportCSV)
+			damageReports.GET("/:id", damageReportHandler.GetByID)
+			damageReports.POST("/:id/resolve", middleware.RequireRole(utils.RoleAdmin), damageReportHandler.Resolve)
+		}
+
+		// Disposals (write-offs) of stock and the report reconciling them
+		disposals := secured.Group("/disposals", requestTimeout)
+		{
+			disposals.GET("", stockDisposalHandler.GetAll)
+			disposals.GET("/report", stockDisposalHandler.GetReport)
+			disposals.GET("/:id", stockDisposalHandler.GetByID)
+		}
+
+		// Warranties of registered stock units, for filing RMAs before they end
+		warranties := secured.Group("/warranty", requestTimeout)
+		{
+			warranties.GET("/expiring", stockUnitHandler.GetExpiring)
+		}
+
+		// Stock summary routes (served from materialized views)
+		stockSummaryHandler := handlers.NewStockSummaryHandler(queries, container.Reports, logger)
+		stockSummary := sparepartStocks.Group("/summary")
+		{
+			stockSummary.GET("/location", stockSummaryHandler.GetByLocation)
+			stockSummary.GET("/sparepart", stockSummaryHandler.GetBySparepart)
+			stockSummary.GET("/region", stockSummaryHandler.GetByRegion)
+		}
+		sparepartStocks.GET("/trends", stockSummaryHandler.GetTrends)
+		sparepartStocks.GET("/reorder-suggestions", stockSummaryHandler.GetReorderSuggestions)
+		sparepartStocks.GET("/movements", stockSummaryHandler.GetMovements)
+		sparepartStocks.GET("/valuation", stockSummaryHandler.GetValuation)
+		stockExports.GET("/valuation/export/excel", recordExport("STOCK_VALUATION", "EXCEL"), stockSummaryHandler.ExportValuationExcel)
+
+		// Minimum and maximum stock levels per location and sparepart, planned apart from the
+		// global low stock alerts
+		stockLevelHandler := handlers.NewStockLevelHandler(queries, logger)
+		stockLevels := secured.Group("/stock-levels", requestTimeout)
+		{
+			stockLevels.GET("", stockLevelHandler.GetAll)
+			stockLevels.GET("/report", stockLevelHandler.GetReport)
+			stockLevels.PUT("", stockLevelHandler.Set)
+			stockLevels.DELETE("/:id", stockLevelHandler.Delete)
+		}
+
+		// Stock opname (physical count) routes; approving adjusts the stock, so only admins can
+		stockOpnameHandler := handlers.NewStockOpnameHandler(queries, logger)
+		stockOpnames := secured.Group("/opname", requestTimeout)
+		{
+			stockOpnames.GET("", stockOpnameHandler.GetAll)
+			stockOpnames.GET("/:id", stockOpnameHandler.GetByID)
+			stockOpnames.POST("", stockOpnameHandler.Create)
+			stockOpnames.PUT("/:id/items", stockOpnameHandler.RecordCounts)
+			stockOpnames.POST("/:id/submit", stockOpnameHandler.Submit)
+			stockOpnames.POST("/:id/approve", middleware.RequireRole(utils.RoleAdmin), stockOpnameHandler.Approve)
+		}
+
+		// Suppliers of incoming shipments; every goods receipt names one
+		supplierHandler := handlers.NewSupplierHandler(queries, logger)
+		suppliers := secured.Group("/supplier", requestTimeout)
+		{
+			suppliers.GET("", supplierHandler.GetAll)
+			suppliers.GET("/:id", supplierHandler.GetByID)
+			suppliers.POST("", supplierHandler.Create)
+			suppliers.PUT("/:id", supplierHandler.Update)
+			suppliers.DELETE("/:id", supplierHandler.Delete)
+		}
+
+		// Purchase orders to suppliers; placing an order commits to it, so only admins can
+		purchaseOrderHandler := handlers.NewPurchaseOrderHandler(queries, logger)
+		purchaseOrders := secured.Group("/purchase-orders", requestTimeout)
+		{
+			purchaseOrders.GET("", purchaseOrderHandler.GetAll)
+			purchaseOrders.GET("/:id", purchaseOrderHandler.GetByID)
+			purchaseOrders.POST("", purchaseOrderHandler.Create)
+			purchaseOrders.POST("/:id/order", middleware.RequireRole(utils.RoleAdmin), purchaseOrderHandler.Place)
+		}
+
+		// Goods receipts of incoming shipments; confirming adds the items to the stock, so only
+		// admins can
+		goodsReceiptHandler := handlers.NewGoodsReceiptHandler(queries, container.Uploads, logger)
+		goodsReceipts := secured.Group("/receipts", requestTimeout)
+		{
+			goodsReceipts.GET("", goodsReceiptHandler.GetAll)
+			goodsReceipts.GET("/:id", goodsReceiptHandler.GetByID)
+			goodsReceipts.GET("/:id/pdf", goodsReceiptHandler.PDF)
+			goodsReceipts.POST("", goodsReceiptHandler.Create)
+			goodsReceipts.POST("/:id/confirm", middleware.RequireRole(utils.RoleAdmin), goodsReceiptHandler.Confirm)
+		}
+
+		// Sparepart requests of the field teams; admins decide on them and fulfill them from
+		// the warehouse stock
+		sparepartRequestHandler := handlers.NewSparepartRequestHandler(queries, logger)
+		sparepartRequests := secured.Group("/requests", requestTimeout, middleware.RequireUser())
+		{
+			sparepartRequests.GET("", sparepartRequestHandler.GetAll)
+			sparepartRequests.GET("/:id", sparepartRequestHandler.GetByID)
+			sparepartRequests.POST("", sparepartRequestHandler.Create)
+			sparepartRequests.POST("/:id/approve", middleware.RequireRole(utils.RoleAdmin), sparepartRequestHandler.Approve)
+			sparepartRequests.POST("/:id/reject", middleware.RequireRole(utils.RoleAdmin), sparepartRequestHandler.Reject)
+			sparepartRequests.POST("/:id/fulfill", middleware.RequireRole(utils.RoleAdmin), sparepartRequestHandler.Fulfill)
+		}
+
+		// Work orders (maintenance tickets) of the field teams; closing one takes its spareparts
+		// out of the site's stock
+		workOrderHandler := handlers.NewWorkOrderHandler(queries, logger)
+		workOrders := secured.Group("/work-orders", requestTimeout, middleware.RequireUser())
+		{
+			workOrders.GET("", workOrderHandler.GetAll)
+			workOrders.GET("/:id", workOrderHandler.GetByID)
+			workOrders.POST("", workOrderHandler.Create)
+			workOrders.PUT("/:id/items", workOrderHandler.SetItems)
+			workOrders.PUT("/:id/tools", workOrderHandler.SetTools)
+			workOrders.POST("/:id/close", workOrderHandler.Close)
+			workOrders.POST("/:id/cancel", workOrderHandler.Cancel)
 		}
 
 		// Tools Alker routes
-		toolsAlkerHandler := handlers.NewToolsAlkerHandler()
-		toolsAlkers := sparepartApi.Group("/tools-alker")
+		toolsAlkerHandler := handlers.NewToolsAlkerHandler(queries, container.Uploads, container.Reports, container.Config.Upload.MaxToolsPhotos, logger)
+		toolsAlkerCheckoutHandler := handlers.NewToolsAlkerCheckoutHandler(queries, logger)
+		toolsAlkers := secured.Group("/tools-alker", requestTimeout)
+		toolsAlkerExports := secured.Group("/tools-alker", exportTimeout, exportLimit)
 		{
 			toolsAlkers.GET("", toolsAlkerHandler.GetAll)
 			toolsAlkers.GET("/:id", toolsAlkerHandler.GetByID)
 			toolsAlkers.POST("", toolsAlkerHandler.Create)
+			toolsAlkers.POST("/batch", toolsAlkerHandler.CreateBatch)
 			toolsAlkers.PUT("/:id", toolsAlkerHandler.Update)
+			toolsAlkers.PATCH("/:id", toolsAlkerHandler.Update)
 			toolsAlkers.DELETE("/:id", toolsAlkerHandler.Delete)
-			toolsAlkers.GET("/export/pdf", toolsAlkerHandler.ExportPDF)
-			toolsAlkers.GET("/export/excel", toolsAlkerHandler.ExportExcel)
+			toolsAlkers.POST("/:id/restore", toolsAlkerHandler.Restore)
+			toolsAlkers.GET("/:id/changes", changeHistoryHandler.GetToolsAlkerChanges)
+			toolsAlkerExports.GET("/export/pdf", recordExport("TOOLS_ALKER", "PDF"), toolsAlkerHandler.ExportPDF)
+			toolsAlkerExports.GET("/export/excel", recordExport("TOOLS_ALKER", "EXCEL"), toolsAlkerHandler.ExportExcel)
+			toolsAlkerExports.GET("/export/csv", recordExport("TOOLS_ALKER", "CSV"), toolsAlkerHandler.ExportCSV)
+			toolsAlkers.POST("/:id/photos", toolsAlkerHandler.AddPhotos)
 			toolsAlkers.PUT("/:id/photos/:photo_index", toolsAlkerHandler.UpdatePhoto)
+			toolsAlkers.PATCH("/:id/photos/:photo_index", toolsAlkerHandler.UpdatePhotoMetadata)
+			toolsAlkers.DELETE("/:id/photos/:photo_index", toolsAlkerHandler.DeletePhoto)
+			toolsAlkers.GET("/checkouts", toolsAlkerCheckoutHandler.GetAll)
+			toolsAlkers.GET("/checkouts/overdue", toolsAlkerCheckoutHandler.GetOverdue)
+			toolsAlkers.POST("/:id/checkout", toolsAlkerCheckoutHandler.Checkout)
+			toolsAlkers.POST("/:id/checkin", toolsAlkerCheckoutHandler.Checkin)
+		}
+
+		// Directory of field technicians that checkouts, work orders and sparepart requests are
+		// assigned to
+		technicianHandler := handlers.NewTechnicianHandler(queries, logger)
+		technicians := secured.Group("/technicians", requestTimeout)
+		{
+			technicians.GET("", technicianHandler.GetAll)
+			technicians.GET("/:id", technicianHandler.GetByID)
+			technicians.GET("/:id/tools", toolsAlkerCheckoutHandler.GetHeldByTechnician)
+			technicians.POST("", technicianHandler.Create)
+			technicians.PUT("/:id", technicianHandler.Update)
+			technicians.DELETE("/:id", technicianHandler.Delete)
+		}
+
+		// Background export jobs of the requesting user (see exports.Worker)
+		exportJobs := secured.Group("/exports", requestTimeout, middleware.RequireUser())
+		{
+			exportJobs.GET("/:id", exportJobHandler.GetByID)
 		}
+
+		// Resolves scanned label codes and stock unit serials to the stock of their location
+		scan := secured.Group("/scan", requestTimeout)
+		{
+			scan.GET("", sparepartStockHandler.Scan)
+		}
+
+		// Search routes
+		searchHandler := handlers.NewSearchHandler(queries, logger)
+		search := secured.Group("/search", requestTimeout)
+		{
+			search.GET("", searchHandler.Search)
+		}
+
+		// Distinct values for the filter dropdowns of the stock and tools alker listings
+		filterValueHandler := handlers.NewFilterValueHandler(queries, logger)
+		filters := secured.Group("/filters", requestTimeout)
+		{
+			filters.GET("", filterValueHandler.GetAll)
+		}
+
+		// Read-only GraphQL endpoint for the mobile dashboard (see internal/graph); read-only
+		// API keys use GET
+		graphQLHandler := handlers.NewGraphQLHandler(queries, logger)
+		secured.POST("/graphql", requestTimeout, graphQLHandler.Query)
+		secured.GET("/graphql", requestTimeout, graphQLHandler.QueryGet)
+
+		// Dashboard routes
+		dashboardHandler := handlers.NewDashboardHandler(queries, logger)
+		dashboard := secured.Group("/dashboard", requestTimeout)
+		{
+			dashboard.GET("/kpis", dashboardHandler.GetKPIs)
+		}
+		summary := secured.Group("/summary", requestTimeout)
+		{
+			summary.GET("", dashboardHandler.GetSummary)
+		}
+
+		// Anomaly alerts (flagged by the background analyzer)
+		anomalyHandler := handlers.NewAnomalyHandler(queries, logger)
+		anomalies := secured.Group("/alerts/anomalies", requestTimeout)
+		{
+			anomalies.GET("", anomalyHandler.GetAll)
+			anomalies.POST("/:id/acknowledge", anomalyHandler.Acknowledge)
+			anomalies.POST("/:id/resolve", anomalyHandler.Resolve)
+		}
+
+		// Alert rule routes (evaluated on a schedule, see alerts.Evaluator)
+		alertRuleHandler := handlers.NewAlertRuleHandler(queries, logger)
+		alertRules := secured.Group("/alerts/rules", requestTimeout)
+		{
+			alertRules.GET("", alertRuleHandler.GetAll)
+			alertRules.GET("/:id", alertRuleHandler.GetByID)
+			alertRules.POST("", alertRuleHandler.Create)
+			alertRules.PUT("/:id", alertRuleHandler.Update)
+			alertRules.DELETE("/:id", alertRuleHandler.Delete)
+		}
+
+		// Notification center of the requesting user (see utils.UserID)
+		notificationHandler := handlers.NewNotificationHandler(queries, logger)
+		notifications := secured.Group("/notifications", requestTimeout, middleware.RequireUser())
+		{
+			notifications.GET("", notificationHandler.GetAll)
+			notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+			notifications.POST("/read-all", notificationHandler.MarkAllRead)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+			notifications.GET("/preferences", notificationHandler.GetPreferences)
+			notifications.PUT("/preferences", notificationHandler.UpdatePreferences)
+		}
+
+		// Saved listing filters of the requesting user
+		savedFilterHandler := handlers.NewSavedFilterHandler(queries, logger)
+		savedFilters := secured.Group("/saved-filters", requestTimeout, middleware.RequireUser())
+		{
+			savedFilters.GET("", savedFilterHandler.GetAll)
+			savedFilters.POST("", savedFilterHandler.Create)
+			savedFilters.PUT("/:id", savedFilterHandler.Update)
+			savedFilters.DELETE("/:id", savedFilterHandler.Delete)
+			savedFilters.PUT("/:id/default", savedFilterHandler.SetDefault)
+			savedFilters.DELETE("/:id/default", savedFilterHandler.UnsetDefault)
+		}
+
+		// Admin routes, for users with the ADMIN role
+		exportLogHandler := handlers.NewExportLogHandler(queries, container.Reports, logger)
+		dataQualityHandler := handlers.NewDataQualityHandler(queries, container.Uploads, logger)
+		shareLinkHandler := handlers.NewShareLinkHandler(queries, container.Config.App.APIPrefix, logger)
+		apiKeyHandler := handlers.NewAPIKeyHandler(queries, logger)
+		purgeHandler := handlers.NewPurgeHandler(queries, container.Uploads, logger)
+		storageUsageHandler := handlers.NewStorageUsageHandler(queries, container.Uploads, logger)
+		webhookHandler := handlers.NewWebhookHandler(queries, logger)
+		messageDeliveryHandler := handlers.NewMessageDeliveryHandler(queries, logger)
+		adminOnly := middleware.RequireRole(utils.RoleAdmin)
+		admin := secured.Group("/admin", requestTimeout, adminOnly)
+		adminExports := secured.Group("/admin", exportTimeout, exportLimit, adminOnly)
+		{
+			admin.GET("/export-log", exportLogHandler.GetAll)
+			admin.GET("/data-quality", dataQualityHandler.GetReport)
+			admin.POST("/share-links", shareLinkHandler.Create)
+			admin.GET("/share-links", shareLinkHandler.GetAll)
+			admin.DELETE("/share-links/:id", shareLinkHandler.Revoke)
+			admin.POST("/api-keys", apiKeyHandler.Create)
+			admin.GET("/api-keys", apiKeyHandler.GetAll)
+			admin.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
+			admin.POST("/purge", purgeHandler.Purge)
+			admin.GET("/storage/usage", storageUsageHandler.GetUsage)
+			admin.GET("/webhooks", webhookHandler.GetAll)
+			admin.GET("/webhooks/:id", webhookHandler.GetByID)
+			admin.POST("/webhooks", webhookHandler.Create)
+			admin.PUT("/webhooks/:id", webhookHandler.Update)
+			admin.DELETE("/webhooks/:id", webhookHandler.Delete)
+			admin.GET("/webhooks/:id/deliveries", webhookHandler.GetDeliveries)
+			admin.GET("/messages", messageDeliveryHandler.GetAll)
+			adminExports.GET("/export-log/export/excel", recordExport("EXPORT_LOG", "EXCEL"), exportLogHandler.ExportExcel)
+		}
+
+		// Public share links: read-only, no authentication, rate limited per client IP
+		shareLimit := middleware.RateLimit(ratelimit.New(container.Redis, "share", container.Config.Share.RateLimit, time.Minute), logger)
+		shared := sparepartApi.Group("/share", requestTimeout, shareLimit)
+		{
+			shared.GET("/:token", shareLinkHandler.GetShared)
+			shared.GET("/:token/pdf", shareLinkHandler.GetSharedPDF)
+		}
+
+		// Stored report routes
+		reportHandler := handlers.NewReportHandler(container.Reports, logger)
+		sparepartApi.GET("/reports/:token", exportTimeout, exportLimit, reportHandler.Download)
 	}
 }
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalPutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewLocal(dir)
+
+	if err := s.Put(ctx, "sparepart/new_stock/a.jpg", strings.NewReader("photo"), 5, "image/jpeg"); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if exists, err := s.Exists(ctx, "sparepart/new_stock/a.jpg"); err != nil || !exists {
+		t.Fatalf("expected file to exist, got %v, %v", exists, err)
+	}
+
+	body, info, err := s.Get(ctx, "sparepart/new_stock/a.jpg")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "photo" || info.Size != 5 || info.ContentType != "image/jpeg" {
+		t.Fatalf("unexpected file %q with %+v", data, info)
+	}
+
+	if err := s.Delete(ctx, "sparepart/new_stock/a.jpg"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := s.Delete(ctx, "sparepart/new_stock/a.jpg"); err != nil {
+		t.Fatalf("deleting a missing file should succeed, got %v", err)
+	}
+	if _, _, err := s.Get(ctx, "sparepart/new_stock/a.jpg"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("expected ErrNotExist, got %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "sparepart", "new_stock", ".upload-*"))
+	if len(matches) != 0 {
+		t.Fatalf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestLocalKeysStayInsideDir(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	dir := filepath.Join(root, "uploads")
+	s := NewLocal(dir)
+
+	if err := s.Put(ctx, "../outside.jpg", strings.NewReader("x"), 1, "image/jpeg"); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "outside.jpg")); !os.IsNotExist(err) {
+		t.Fatalf("file was written outside the uploads directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "outside.jpg")); err != nil {
+		t.Fatalf("expected file inside the uploads directory: %v", err)
+	}
+}
+
+func TestLocalWalk(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewLocal(dir)
+
+	if err := NewLocal(filepath.Join(dir, "missing")).Walk(ctx, func(string, int64) error { return nil }); err != nil {
+		t.Fatalf("walking a directory that does not exist yet should succeed, got %v", err)
+	}
+
+	for key, body := range map[string]string{
+		"sparepart/new_stock/a.jpg": "photo",
+		"tools_alker/b.png":         "ab",
+		".staging/c.jpg":            "staged",
+	} {
+		if err := s.Put(ctx, key, strings.NewReader(body), int64(len(body)), "image/jpeg"); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+
+	files := map[string]int64{}
+	err := s.Walk(ctx, func(key string, size int64) error {
+		files[key] = size
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk failed: %v", err)
+	}
+	if len(files) != 2 || files["sparepart/new_stock/a.jpg"] != 5 || files["tools_alker/b.png"] != 2 {
+		t.Fatalf("unexpected files %v", files)
+	}
+}
@@ -0,0 +1,73 @@
+// Package storage keeps uploaded files (documentation photos) on the local disk or in an
+// S3 compatible bucket (AWS S3, MinIO), selected by STORAGE_BACKEND. Files are addressed by
+// keys relative to the uploads root, e.g. sparepart/new_stock/x.jpg for the stored reference
+// /uploads/sparepart/new_stock/x.jpg.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"sparepart-management-services/internal/config"
+)
+
+// Backends selectable through config.UploadConfig.Backend
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// ErrNotExist is returned by Get for a key without a file
+var ErrNotExist = errors.New("file does not exist")
+
+// FileInfo describes a stored file
+type FileInfo struct {
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage stores uploaded files by key
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any existing file
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the file under key; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, FileInfo, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes the file under key; a missing file is not an error
+	Delete(ctx context.Context, key string) error
+	// Walk calls fn with the key and size of every stored file, stopping at the first error
+	Walk(ctx context.Context, fn func(key string, size int64) error) error
+}
+
+// New returns the storage backend selected by cfg
+func New(cfg config.UploadConfig) (Storage, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocal(cfg.Dir), nil
+	case BackendS3:
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// NewReports returns the backend stored reports are kept in: cfg.Dir on the local backend, or
+// cfg.S3Prefix in the bucket of upload on s3. The prefix may not contain the uploads or lie
+// within them, since the upload usage and the expired report cleanup each walk their whole prefix.
+func NewReports(upload config.UploadConfig, cfg config.ReportConfig) (Storage, error) {
+	if upload.Backend == BackendS3 {
+		uploads, reports := strings.Trim(upload.S3.Prefix, "/"), strings.Trim(cfg.S3Prefix, "/")
+		if uploads == "" || reports == "" || uploads == reports ||
+			strings.HasPrefix(reports, uploads+"/") || strings.HasPrefix(uploads, reports+"/") {
+			return nil, fmt.Errorf("S3_PREFIX %q and REPORT_S3_PREFIX %q must not overlap", uploads, reports)
+		}
+	}
+	upload.Dir = cfg.Dir
+	upload.S3.Prefix = cfg.S3Prefix
+	return New(upload)
+}
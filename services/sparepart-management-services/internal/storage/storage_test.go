@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"testing"
+
+	"sparepart-management-services/internal/config"
+)
+
+func TestNewReportsRejectsOverlappingPrefixes(t *testing.T) {
+	upload := config.UploadConfig{
+		Backend: BackendS3,
+		S3:      config.S3Config{Endpoint: "minio:9000", Bucket: "sparepart"},
+	}
+
+	tests := []struct {
+		uploads string
+		reports string
+		wantErr bool
+	}{
+		{"uploads", "reports", false},
+		{"/uploads/", "reports/", false},
+		{"", "reports", true},
+		{"uploads", "", true},
+		{"uploads", "uploads", true},
+		{"uploads", "uploads/reports", true},
+		{"data/uploads", "data", true},
+		{"uploads", "uploads-reports", false},
+	}
+	for _, tt := range tests {
+		upload.S3.Prefix = tt.uploads
+		_, err := NewReports(upload, config.ReportConfig{S3Prefix: tt.reports})
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("uploads %q, reports %q: expected error %v, got %v", tt.uploads, tt.reports, tt.wantErr, err)
+		}
+	}
+}
+
+func TestNewReportsLocal(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewReports(config.UploadConfig{Dir: "./uploads"}, config.ReportConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if local, ok := s.(*Local); !ok || local.dir != dir {
+		t.Fatalf("expected the local report directory, got %+v", s)
+	}
+}
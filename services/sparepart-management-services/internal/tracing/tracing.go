@@ -0,0 +1,58 @@
+// Package tracing sets up OpenTelemetry tracing. Spans are exported over OTLP/HTTP when an
+// OTLP endpoint is configured; the exporter reads the rest of its settings (headers,
+// TLS, timeout) from the standard OTEL_EXPORTER_OTLP_* variables and the SDK reads the
+// sampler from OTEL_TRACES_SAMPLER. Without an endpoint the global no-op tracer is kept,
+// so the spans started by the middleware and the pgx tracer cost next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"sparepart-management-services/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name is the instrumentation scope of the spans started by this service
+const Name = "sparepart-management-services"
+
+// Tracer returns the tracer of the globally registered provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(Name)
+}
+
+// Setup registers the W3C trace context propagator and, when cfg has an endpoint, a
+// tracer provider exporting to it. The returned function flushes and stops the provider.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	// Incoming traceparent headers are honoured even when this service exports nothing,
+	// so the trace IDs in downstream calls stay connected
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,85 @@
+package uploads
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// contentHash is the SHA-256 of a photo as stored, after its metadata was stripped
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// reuse adds a reference to a stored photo with the hash and returns its path, or an
+// empty path when there is none and the photo has to be stored
+func (s *Service) reuse(ctx context.Context, hash string, logger *zap.Logger) string {
+	if s.index == nil {
+		return ""
+	}
+	path, err := s.index.AcquireUploadFile(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) && logger != nil {
+			logger.Warn("Failed to look up identical photo", zap.Error(err))
+		}
+		return ""
+	}
+
+	// A file removed from the storage by hand is stored again
+	exists, err := s.Exists(ctx, path)
+	if err != nil || !exists {
+		if _, err := s.release(ctx, path); err != nil && logger != nil {
+			logger.Warn("Failed to release missing photo", zap.Error(err), zap.String("path", path))
+		}
+		return ""
+	}
+
+	if logger != nil {
+		logger.Info("Identical photo reused", zap.String("path", path))
+	}
+	return path
+}
+
+// register records a newly stored photo with one reference
+func (s *Service) register(ctx context.Context, path, hash string, size int64) error {
+	if s.index == nil {
+		return nil
+	}
+	err := s.index.CreateUploadFile(ctx, sqlcdb.CreateUploadFileParams{Path: path, Sha256: hash, Size: size})
+	if err != nil {
+		return fmt.Errorf("failed to record uploaded file: %w", err)
+	}
+	return nil
+}
+
+// release drops a reference to a stored photo and reports whether it was the last one,
+// so the file can be deleted. A photo stored before the index has a single reference.
+func (s *Service) release(ctx context.Context, path string) (bool, error) {
+	if s.index == nil {
+		return true, nil
+	}
+	left, err := s.index.ReleaseUploadFile(ctx, path)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to release uploaded file: %w", err)
+	}
+	if left > 0 {
+		return false, nil
+	}
+	deleted, err := s.index.DeleteReleasedUploadFile(ctx, path)
+	if err != nil {
+		return false, fmt.Errorf("failed to release uploaded file: %w", err)
+	}
+	// Not deleted when the file was reused in the meantime
+	return deleted > 0, nil
+}
@@ -0,0 +1,194 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// memoryUploadIndex is an in-memory repository.UploadFileRepository
+type memoryUploadIndex struct {
+	hashes map[string]string
+	refs   map[string]int32
+	sizes  map[string]int64
+}
+
+func newMemoryUploadIndex() *memoryUploadIndex {
+	return &memoryUploadIndex{hashes: map[string]string{}, refs: map[string]int32{}, sizes: map[string]int64{}}
+}
+
+func (m *memoryUploadIndex) AcquireUploadFile(ctx context.Context, sha256 string) (string, error) {
+	for path, hash := range m.hashes {
+		if hash == sha256 && m.refs[path] > 0 {
+			m.refs[path]++
+			return path, nil
+		}
+	}
+	return "", pgx.ErrNoRows
+}
+
+func (m *memoryUploadIndex) CreateUploadFile(ctx context.Context, arg sqlcdb.CreateUploadFileParams) error {
+	if _, ok := m.hashes[arg.Path]; ok {
+		return errors.New("duplicate key value violates unique constraint \"upload_file_pkey\"")
+	}
+	m.hashes[arg.Path] = arg.Sha256
+	m.refs[arg.Path]++
+	m.sizes[arg.Path] = arg.Size
+	return nil
+}
+
+func (m *memoryUploadIndex) ReleaseUploadFile(ctx context.Context, path string) (int32, error) {
+	if m.refs[path] == 0 {
+		return 0, pgx.ErrNoRows
+	}
+	m.refs[path]--
+	return m.refs[path], nil
+}
+
+func (m *memoryUploadIndex) DeleteReleasedUploadFile(ctx context.Context, path string) (int64, error) {
+	if _, ok := m.hashes[path]; !ok || m.refs[path] > 0 {
+		return 0, nil
+	}
+	delete(m.hashes, path)
+	delete(m.refs, path)
+	delete(m.sizes, path)
+	return 1, nil
+}
+
+func (m *memoryUploadIndex) SumUploadFileSize(ctx context.Context) (int64, error) {
+	var total int64
+	for _, size := range m.sizes {
+		total += size
+	}
+	return total, nil
+}
+
+// storedFiles counts the files under the upload directory, thumbnails and staging area included
+func storedFiles(t *testing.T, s *Service) int {
+	t.Helper()
+
+	count := 0
+	_ = filepath.WalkDir(s.cfg.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func TestProcessImageReusesIdenticalPhoto(t *testing.T) {
+	index := newMemoryUploadIndex()
+	s := newTestService(t, index, false)
+	ctx := context.Background()
+
+	// The same photo with different metadata is the same photo once stripped
+	first, err := s.ProcessImage(ctx, uploadedFile(t, "a.jpg", encodeJPEG(t, 8, 8, 1)), "tools_alker", "tools_alker", nil)
+	if err != nil {
+		t.Fatalf("first upload failed: %v", err)
+	}
+	second, err := s.ProcessImage(ctx, uploadedFile(t, "b.jpg", encodeJPEG(t, 8, 8, 0)), "tools_alker", "tools_alker", nil)
+	if err != nil {
+		t.Fatalf("second upload failed: %v", err)
+	}
+	if second != first {
+		t.Fatalf("expected the identical photo to reuse %s, got %s", first, second)
+	}
+	if n := storedFiles(t, s); n != 2 {
+		t.Fatalf("expected 1 photo and its thumbnail, found %d files", n)
+	}
+	if index.refs[first] != 2 {
+		t.Fatalf("expected 2 references, got %d", index.refs[first])
+	}
+
+	if err := s.DeleteFile(ctx, first, nil); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	if n := storedFiles(t, s); n != 2 {
+		t.Fatalf("expected the photo to be kept while referenced, found %d files", n)
+	}
+	if err := s.DeleteFile(ctx, second, nil); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	if n := storedFiles(t, s); n != 0 {
+		t.Fatalf("expected the photo to be deleted with its last reference, found %d files", n)
+	}
+	if _, ok := index.hashes[first]; ok {
+		t.Fatal("expected the record of the deleted photo to be removed")
+	}
+}
+
+func TestStageImageReusesIdenticalPhoto(t *testing.T) {
+	index := newMemoryUploadIndex()
+	s := newTestService(t, index, false)
+	ctx := context.Background()
+
+	photo := encodeJPEG(t, 8, 8, 0)
+	stored, err := s.StageImage(ctx, uploadedFile(t, "a.jpg", photo), "sparepart/new_stock", "sparepart_stock_new", nil)
+	if err != nil {
+		t.Fatalf("StageImage failed: %v", err)
+	}
+	if err := s.CommitStaged(ctx, []Staged{stored}, nil); err != nil {
+		t.Fatalf("CommitStaged failed: %v", err)
+	}
+
+	reused, err := s.StageImage(ctx, uploadedFile(t, "b.jpg", photo), "sparepart/used_stock", "sparepart_stock_used", nil)
+	if err != nil {
+		t.Fatalf("StageImage failed: %v", err)
+	}
+	if reused.Path != stored.Path {
+		t.Fatalf("expected the identical photo to reuse %s, got %s", stored.Path, reused.Path)
+	}
+
+	// Discarding the reused photo only drops its reference
+	s.DiscardStaged([]Staged{reused}, nil)
+	if n := storedFiles(t, s); n != 2 {
+		t.Fatalf("expected the stored photo and its thumbnail to be kept, found %d files", n)
+	}
+	if index.refs[stored.Path] != 1 {
+		t.Fatalf("expected 1 reference, got %d", index.refs[stored.Path])
+	}
+
+	// A photo stored before the index has no record and is deleted with its only reference
+	legacy, err := New(s.storage, nil, s.cfg).ProcessImage(ctx, uploadedFile(t, "c.png", pngWithText(t)), "tools_alker", "tools_alker", nil)
+	if err != nil {
+		t.Fatalf("ProcessImage failed: %v", err)
+	}
+	if err := s.DeleteFile(ctx, legacy, nil); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	if exists, _ := s.Exists(ctx, legacy); exists {
+		t.Fatal("expected the photo without a record to be deleted")
+	}
+}
+
+func TestProcessImageKeepsPhotosOfTheSameSecondApart(t *testing.T) {
+	index := newMemoryUploadIndex()
+	s := newTestService(t, index, false)
+	ctx := context.Background()
+
+	// Different photos uploaded back to back, as in one AddPhotos request
+	first, err := s.ProcessImage(ctx, uploadedFile(t, "a.jpg", encodeJPEG(t, 8, 8, 0)), "tools_alker", "tools_alker", nil)
+	if err != nil {
+		t.Fatalf("first upload failed: %v", err)
+	}
+	second, err := s.ProcessImage(ctx, uploadedFile(t, "b.jpg", encodeJPEG(t, 16, 8, 0)), "tools_alker", "tools_alker", nil)
+	if err != nil {
+		t.Fatalf("second upload failed: %v", err)
+	}
+	if first == second {
+		t.Fatalf("expected different photos to be stored apart, both are %s", first)
+	}
+	if n := storedFiles(t, s); n != 4 {
+		t.Fatalf("expected 2 photos and their thumbnails, found %d files", n)
+	}
+	if index.hashes[first] == index.hashes[second] || index.refs[first] != 1 || index.refs[second] != 1 {
+		t.Fatalf("expected a record with one reference per photo, got %v %v", index.hashes, index.refs)
+	}
+}
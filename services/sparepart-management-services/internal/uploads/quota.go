@@ -0,0 +1,71 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrQuotaExceeded is returned by CheckQuota when an upload would take the upload storage
+// over UPLOAD_QUOTA_MB
+var ErrQuotaExceeded = errors.New("upload quota exceeded")
+
+// Usage is what the files in the upload storage take up, thumbnails included
+type Usage struct {
+	Bytes int64
+	Files int
+	// Sizes maps the stored reference (/uploads/...) of every file to its size
+	Sizes map[string]int64
+}
+
+// QuotaBytes is the cap on the bytes kept in the upload storage; 0 is no quota
+func (s *Service) QuotaBytes() int64 {
+	return s.cfg.QuotaBytes
+}
+
+// MeasureUsage lists every file in the upload storage
+func (s *Service) MeasureUsage(ctx context.Context) (Usage, error) {
+	usage := Usage{Sizes: map[string]int64{}}
+	err := s.storage.Walk(ctx, func(key string, size int64) error {
+		usage.Bytes += size
+		usage.Files++
+		usage.Sizes["/uploads/"+key] = size
+		return nil
+	})
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to measure upload storage: %w", err)
+	}
+	return usage, nil
+}
+
+// CheckQuota returns ErrQuotaExceeded when storing size more bytes would take the stored photos
+// over the quota. The photos are counted in the upload index, which every replica shares and
+// which survives restarts; thumbnails and photos stored before the index are not counted.
+// Without an index the upload storage is measured instead.
+func (s *Service) CheckQuota(ctx context.Context, size int64) error {
+	quota := s.cfg.QuotaBytes
+	if quota <= 0 {
+		return nil
+	}
+
+	used, err := s.usedBytes(ctx)
+	if err != nil {
+		return err
+	}
+	if used+size > quota {
+		return fmt.Errorf("%w: %d of %d MB used", ErrQuotaExceeded, used/(1024*1024), quota/(1024*1024))
+	}
+	return nil
+}
+
+func (s *Service) usedBytes(ctx context.Context) (int64, error) {
+	if s.index == nil {
+		usage, err := s.MeasureUsage(ctx)
+		return usage.Bytes, err
+	}
+	used, err := s.index.SumUploadFileSize(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure upload storage: %w", err)
+	}
+	return used, nil
+}
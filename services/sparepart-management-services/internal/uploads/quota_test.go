@@ -0,0 +1,66 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckQuota(t *testing.T) {
+	index := newMemoryUploadIndex()
+	s := newTestService(t, index, false)
+	ctx := context.Background()
+
+	if _, err := s.ProcessImage(ctx, uploadedFile(t, "a.png", pngWithText(t)), "tools_alker", "tools_alker", nil); err != nil {
+		t.Fatalf("ProcessImage failed: %v", err)
+	}
+	used, err := index.SumUploadFileSize(ctx)
+	if err != nil || used == 0 {
+		t.Fatalf("expected the photo to be recorded, got %d, %v", used, err)
+	}
+
+	s.cfg.QuotaBytes = used + 100
+	if err := s.CheckQuota(ctx, 100); err != nil {
+		t.Fatalf("expected an upload within the quota to be accepted, got %v", err)
+	}
+	if err := s.CheckQuota(ctx, 101); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	// A photo stored by another replica counts as soon as it is recorded
+	if _, err := New(s.storage, index, s.cfg).ProcessImage(ctx, uploadedFile(t, "b.jpg", encodeJPEG(t, 8, 8, 1)), "tools_alker", "tools_alker", nil); err != nil {
+		t.Fatalf("ProcessImage failed: %v", err)
+	}
+	if err := s.CheckQuota(ctx, 100); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	s.cfg.QuotaBytes = 0
+	if err := s.CheckQuota(ctx, 1<<40); err != nil {
+		t.Fatalf("expected no quota, got %v", err)
+	}
+}
+
+func TestCheckQuotaWithoutIndex(t *testing.T) {
+	s := newTestService(t, nil, false)
+	ctx := context.Background()
+
+	if _, err := s.ProcessImage(ctx, uploadedFile(t, "a.png", pngWithText(t)), "tools_alker", "tools_alker", nil); err != nil {
+		t.Fatalf("ProcessImage failed: %v", err)
+	}
+	usage, err := s.MeasureUsage(ctx)
+	if err != nil {
+		t.Fatalf("MeasureUsage failed: %v", err)
+	}
+	if usage.Files != 2 || usage.Bytes == 0 {
+		t.Fatalf("expected the photo and its thumbnail, got %+v", usage)
+	}
+
+	s.cfg.QuotaBytes = usage.Bytes + 100
+	if err := s.CheckQuota(ctx, 100); err != nil {
+		t.Fatalf("expected an upload within the quota to be accepted, got %v", err)
+	}
+	if err := s.CheckQuota(ctx, 101); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,425 @@
+package uploads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// reencodeQuality is the JPEG quality of re-encoded and rotated photos
+const reencodeQuality = 90
+
+// imageExtensions maps the sniffed content type of every accepted photo type to the file
+// extensions it may be uploaded with
+var imageExtensions = map[string][]string{
+	"image/jpeg": {".jpg", ".jpeg"},
+	"image/png":  {".png"},
+	"image/gif":  {".gif"},
+	"image/webp": {".webp"},
+}
+
+// sanitizedImage is an uploaded photo ready to be stored: its type checked against its
+// content and its metadata removed
+type sanitizedImage struct {
+	data        []byte
+	ext         string
+	contentType string
+}
+
+// sanitize reads an uploaded photo and checks its content is an image of the type
+// its name says, then strips its EXIF, GPS and other metadata, or with UPLOAD_REENCODE
+// re-encodes it as JPEG
+func (s *Service) sanitize(file *multipart.FileHeader) (sanitizedImage, error) {
+	maxFileSize := s.cfg.MaxFileSize
+
+	// Validate file size
+	if file.Size > maxFileSize {
+		return sanitizedImage{}, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", maxFileSize)
+	}
+
+	// Validate file type by name first, so an unsupported file is not read
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	allowed := false
+	for _, exts := range imageExtensions {
+		allowed = allowed || slices.Contains(exts, ext)
+	}
+	if !allowed {
+		return sanitizedImage{}, fmt.Errorf("invalid file type. Allowed: jpg, jpeg, png, gif, webp")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return sanitizedImage{}, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, maxFileSize+1))
+	if err != nil {
+		return sanitizedImage{}, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	if int64(len(data)) > maxFileSize {
+		return sanitizedImage{}, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", maxFileSize)
+	}
+
+	// The content decides the type, the name must agree with it
+	contentType := http.DetectContentType(data)
+	exts, ok := imageExtensions[contentType]
+	if !ok {
+		return sanitizedImage{}, fmt.Errorf("file content is not a supported image (jpg, png, gif, webp)")
+	}
+	if !slices.Contains(exts, ext) {
+		return sanitizedImage{}, fmt.Errorf("file content is %s but the file name ends in %s", strings.TrimPrefix(contentType, "image/"), ext)
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return sanitizedImage{}, fmt.Errorf("file content is not a valid %s image", strings.TrimPrefix(contentType, "image/"))
+	}
+
+	orientation := 1
+	if contentType == "image/jpeg" {
+		data, orientation, err = stripJPEGMetadata(data)
+	} else {
+		data, err = stripImageMetadata(contentType, data)
+	}
+	if err != nil {
+		return sanitizedImage{}, fmt.Errorf("file content is not a valid %s image: %w", strings.TrimPrefix(contentType, "image/"), err)
+	}
+
+	// A photo turned by its EXIF orientation is turned for real, the tag is gone
+	if s.cfg.Reencode || orientation != 1 {
+		data, err = reencodeImage(data, orientation)
+		if err != nil {
+			return sanitizedImage{}, err
+		}
+		return sanitizedImage{data: data, ext: ".jpg", contentType: "image/jpeg"}, nil
+	}
+	return sanitizedImage{data: data, ext: ext, contentType: contentType}, nil
+}
+
+// reencodeImage decodes a photo, applies its EXIF orientation and encodes it as JPEG;
+// transparent areas become white
+func reencodeImage(data []byte, orientation int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	src = orientImage(src, orientation)
+
+	dst := image.NewRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: reencodeQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// orientImage turns src upright according to its EXIF orientation (1 to 8)
+func orientImage(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // rotated 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // mirrored and rotated 270 clockwise
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // mirrored and rotated 90 clockwise
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 270 clockwise
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// stripImageMetadata removes the metadata of a PNG, GIF or WebP photo
+func stripImageMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/png":
+		return stripPNGMetadata(data)
+	case "image/gif":
+		return stripGIFMetadata(data)
+	case "image/webp":
+		return stripWebPMetadata(data)
+	}
+	return data, nil
+}
+
+var errTruncatedImage = errors.New("truncated image")
+
+// stripJPEGMetadata drops the APPn segments except JFIF (APP0), the ICC profile (APP2) and
+// Adobe (APP14), which hold the EXIF, GPS, XMP and IPTC data, and comments. The segments are
+// copied up to the first scan, the image data after it as it is. It also returns the EXIF
+// orientation of the photo, 1 when it has none.
+func stripJPEGMetadata(data []byte) ([]byte, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, 0, errors.New("missing JPEG start marker")
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	orientation := 1
+	pos := 2
+	for {
+		// Markers may be preceded by fill bytes
+		for pos < len(data) && data[pos] == 0xFF && pos+1 < len(data) && data[pos+1] == 0xFF {
+			pos++
+		}
+		if pos+2 > len(data) || data[pos] != 0xFF {
+			return nil, 0, errTruncatedImage
+		}
+		marker := data[pos+1]
+		if marker == 0xD9 { // end of image without a scan
+			out = append(out, 0xFF, 0xD9)
+			return out, orientation, nil
+		}
+		if marker >= 0xD0 && marker <= 0xD7 || marker == 0x01 { // no length
+			out = append(out, data[pos:pos+2]...)
+			pos += 2
+			continue
+		}
+		if pos+4 > len(data) {
+			return nil, 0, errTruncatedImage
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, 0, errTruncatedImage
+		}
+		if marker == 0xDA { // start of scan: the rest is image data
+			out = append(out, data[pos:]...)
+			return out, orientation, nil
+		}
+
+		keep := true
+		switch {
+		case marker == 0xE1:
+			if o := exifOrientation(data[pos+4 : end]); o != 0 {
+				orientation = o
+			}
+			keep = false
+		case marker >= 0xE3 && marker <= 0xEF && marker != 0xEE, marker == 0xFE:
+			keep = false
+		}
+		if keep {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+}
+
+// exifOrientation reads the orientation tag from an EXIF APP1 payload, or 0 when it has none
+func exifOrientation(payload []byte) int {
+	if !bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+		return 0
+	}
+	tiff := payload[6:]
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		// Tag 0x0112 is the orientation, a SHORT held in the value field itself
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// pngMetadataChunks are the PNG chunks holding EXIF data, text (which may be XMP) and the
+// modification time
+var pngMetadataChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+// stripPNGMetadata drops the metadata chunks of a PNG and anything after its end
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	const signatureLength = 8
+	if len(data) < signatureLength {
+		return nil, errTruncatedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:signatureLength]...)
+	pos := signatureLength
+	for pos+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 12 + length // length, type, data and CRC
+		if length < 0 || end > len(data) {
+			return nil, errTruncatedImage
+		}
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+		if chunkType == "IEND" {
+			return out, nil
+		}
+	}
+	return nil, errTruncatedImage
+}
+
+// stripGIFMetadata drops the comment extensions of a GIF and its application extensions other
+// than the animation loop count (e.g. XMP), and anything after its trailer
+func stripGIFMetadata(data []byte) ([]byte, error) {
+	// Header and logical screen descriptor, then the global color table when flagged
+	pos := 13
+	if len(data) < pos {
+		return nil, errTruncatedImage
+	}
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << (flags&0x07 + 1)
+	}
+	if pos > len(data) {
+		return nil, errTruncatedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:pos]...)
+	for pos < len(data) {
+		start := pos
+		keep := true
+		switch data[pos] {
+		case 0x3B: // trailer
+			return append(out, 0x3B), nil
+		case 0x21: // extension: label, then data sub-blocks
+			if pos+2 > len(data) {
+				return nil, errTruncatedImage
+			}
+			label := data[pos+1]
+			pos += 2
+			if label == 0xFE {
+				keep = false
+			}
+			if label == 0xFF {
+				keep = pos+12 <= len(data) && data[pos] == 11 &&
+					(string(data[pos+1:pos+12]) == "NETSCAPE2.0" || string(data[pos+1:pos+12]) == "ANIMEXTS1.0")
+			}
+		case 0x2C: // image descriptor, local color table, LZW code size, then data sub-blocks
+			if pos+10 > len(data) {
+				return nil, errTruncatedImage
+			}
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1)
+			}
+			pos++
+		default:
+			return nil, fmt.Errorf("unexpected GIF block 0x%02x", data[pos])
+		}
+
+		// Data sub-blocks end with an empty one
+		for {
+			if pos >= len(data) {
+				return nil, errTruncatedImage
+			}
+			size := int(data[pos])
+			pos += 1 + size
+			if size == 0 {
+				break
+			}
+		}
+		if pos > len(data) {
+			return nil, errTruncatedImage
+		}
+		if keep {
+			out = append(out, data[start:pos]...)
+		}
+	}
+	return nil, errTruncatedImage
+}
+
+// stripWebPMetadata drops the EXIF and XMP chunks of a WebP, clears their flags in the VP8X
+// header and drops anything after the RIFF container
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 {
+		return nil, errTruncatedImage
+	}
+	riffEnd := 8 + int(binary.LittleEndian.Uint32(data[4:]))
+	if riffEnd > len(data) || riffEnd < 12 {
+		return nil, errTruncatedImage
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	pos := 12
+	for pos+8 <= riffEnd {
+		fourCC := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + size + size%2 // chunks are padded to an even size
+		if end > riffEnd {
+			if end-1 != riffEnd || size%2 == 0 { // tolerate a missing final pad byte
+				return nil, errTruncatedImage
+			}
+			end = riffEnd
+		}
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := slices.Clone(data[pos:end])
+			if len(chunk) > 8 {
+				chunk[8] &^= 0x08 | 0x04 // EXIF and XMP present
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
@@ -0,0 +1,204 @@
+package uploads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sparepart-management-services/internal/config"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/storage"
+)
+
+// newTestService returns a service keeping photos in a temporary directory, recorded in index
+// (nil for none)
+func newTestService(t *testing.T, index repository.UploadFileRepository, reencode bool) *Service {
+	t.Helper()
+
+	dir := t.TempDir()
+	return New(storage.NewLocal(dir), index, config.UploadConfig{Dir: dir, MaxFileSize: 1 << 20, Reencode: reencode})
+}
+
+// uploadedFile returns the header of a file uploaded as name with the given content
+func uploadedFile(t *testing.T, name string, data []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("photo", name)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	part.Write(data)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatalf("failed to parse form: %v", err)
+	}
+	return req.MultipartForm.File["photo"][0]
+}
+
+// encodeJPEG encodes a width x height JPEG, red on its left half, with an EXIF segment
+// holding orientation (none when 0) and a GPS-looking marker
+func encodeJPEG(t *testing.T, width, height, orientation int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width/2; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("failed to encode test photo: %v", err)
+	}
+	if orientation == 0 {
+		return buf.Bytes()
+	}
+
+	// Little-endian TIFF header with one IFD entry: the orientation
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00")
+	tiff = append(tiff, 0x12, 0x01, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00, byte(orientation), 0x00, 0x00, 0x00)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	payload = append(payload, []byte("GPSLatitude-6.2")...)
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestSanitizeImageUploadRejectsMismatchedContent(t *testing.T) {
+	s := newTestService(t, nil, false)
+
+	tests := map[string]struct {
+		name string
+		data []byte
+		want string
+	}{
+		"png named jpg":    {"photo.jpg", pngWithText(t), "file content is png but the file name ends in .jpg"},
+		"text named jpg":   {"photo.jpg", []byte("not an image at all"), "not a supported image"},
+		"script named png": {"photo.png", []byte("<html><script>alert(1)</script></html>"), "not a supported image"},
+		"unsupported name": {"photo.svg", encodeJPEG(t, 8, 8, 0), "invalid file type"},
+	}
+	for name, tt := range tests {
+		_, err := s.sanitize(uploadedFile(t, tt.name, tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, tt.want, err)
+		}
+	}
+}
+
+func TestSanitizeImageUploadStripsJPEGMetadata(t *testing.T) {
+	s := newTestService(t, nil, false)
+
+	img, err := s.sanitize(uploadedFile(t, "photo.JPG", encodeJPEG(t, 8, 8, 1)))
+	if err != nil {
+		t.Fatalf("sanitize failed: %v", err)
+	}
+	if bytes.Contains(img.data, []byte("Exif")) || bytes.Contains(img.data, []byte("GPSLatitude")) {
+		t.Error("expected the EXIF segment to be removed")
+	}
+	if img.ext != ".jpg" || img.contentType != "image/jpeg" {
+		t.Errorf("unexpected type %s %s", img.ext, img.contentType)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(img.data)); err != nil {
+		t.Errorf("stripped photo is not a valid JPEG: %v", err)
+	}
+}
+
+func TestSanitizeImageUploadAppliesOrientation(t *testing.T) {
+	s := newTestService(t, nil, false)
+
+	// Orientation 6 is turned 90 degrees clockwise
+	img, err := s.sanitize(uploadedFile(t, "photo.jpg", encodeJPEG(t, 16, 8, 6)))
+	if err != nil {
+		t.Fatalf("sanitize failed: %v", err)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(img.data))
+	if err != nil {
+		t.Fatalf("rotated photo is not a valid JPEG: %v", err)
+	}
+	if got := decoded.Bounds().Size(); got != image.Pt(8, 16) {
+		t.Fatalf("expected the photo to be turned to 8x16, got %v", got)
+	}
+	// The red left half is now the top
+	if r, _, _, _ := decoded.At(4, 2).RGBA(); r < 0xC000 {
+		t.Errorf("expected the top of the turned photo to be red")
+	}
+	if bytes.Contains(img.data, []byte("Exif")) {
+		t.Error("expected the EXIF segment to be removed")
+	}
+}
+
+// pngWithText encodes a PNG with a tEXt chunk after its header
+func pngWithText(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	data := buf.Bytes()
+
+	text := []byte("Comment\x00taken at the shelter")
+	chunk := make([]byte, 8, 12+len(text))
+	binary.BigEndian.PutUint32(chunk, uint32(len(text)))
+	copy(chunk[4:], "tEXt")
+	chunk = append(chunk, text...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	// The signature (8 bytes) and IHDR chunk (25 bytes) come first
+	out := append([]byte{}, data[:33]...)
+	out = append(out, chunk...)
+	return append(out, data[33:]...)
+}
+
+func TestSanitizeImageUploadStripsPNGText(t *testing.T) {
+	s := newTestService(t, nil, false)
+
+	img, err := s.sanitize(uploadedFile(t, "photo.png", pngWithText(t)))
+	if err != nil {
+		t.Fatalf("sanitize failed: %v", err)
+	}
+	if bytes.Contains(img.data, []byte("tEXt")) {
+		t.Error("expected the text chunk to be removed")
+	}
+	if img.ext != ".png" || img.contentType != "image/png" {
+		t.Errorf("unexpected type %s %s", img.ext, img.contentType)
+	}
+	if _, err := png.Decode(bytes.NewReader(img.data)); err != nil {
+		t.Errorf("stripped photo is not a valid PNG: %v", err)
+	}
+}
+
+func TestSanitizeImageUploadReencodes(t *testing.T) {
+	s := newTestService(t, nil, true)
+
+	img, err := s.sanitize(uploadedFile(t, "photo.png", pngWithText(t)))
+	if err != nil {
+		t.Fatalf("sanitize failed: %v", err)
+	}
+	if img.ext != ".jpg" || img.contentType != "image/jpeg" {
+		t.Fatalf("expected a JPEG, got %s %s", img.ext, img.contentType)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(img.data)); err != nil {
+		t.Errorf("re-encoded photo is not a valid JPEG: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package uploads
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sparepart-management-services/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// stagingSubDir holds uploads that are not yet referenced by a committed database row
+const stagingSubDir = ".staging"
+
+// Staged is an image saved to the local staging area, waiting to be moved to the
+// upload storage, or an identical photo already stored
+type Staged struct {
+	Path        string // relative path stored in the database (e.g. /uploads/sparepart/new_stock/x.jpg)
+	tempPath    string // empty for a reused photo
+	size        int64
+	contentType string
+	hash        string
+	thumbnail   []byte // JPEG stored at utils.ThumbnailPath(Path); nil when the photo could not be decoded
+}
+
+// uploadFilename returns a new name for an uploaded photo. The random suffix keeps names unique
+// when several photos are uploaded in the same second, in one request or in concurrent ones.
+func uploadFilename(prefix, ext string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate file name: %w", err)
+	}
+	return fmt.Sprintf("%s_%d_%s%s", prefix, time.Now().Unix(), hex.EncodeToString(suffix), ext), nil
+}
+
+// StageImage validates an image, strips its metadata (see sanitize) and saves it to the
+// staging area. The returned Path is where the file will live after CommitStaged; for a photo
+// identical to a stored one it is the path of that one, which is referenced until the upload
+// is discarded.
+func (s *Service) StageImage(ctx context.Context, file *multipart.FileHeader, subDir string, prefix string, logger *zap.Logger) (Staged, error) {
+	img, err := s.sanitize(file)
+	if err != nil {
+		return Staged{}, err
+	}
+
+	hash := contentHash(img.data)
+	if path := s.reuse(ctx, hash, logger); path != "" {
+		return Staged{Path: path}, nil
+	}
+
+	stagingDir := filepath.Join(s.cfg.Dir, stagingSubDir)
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		return Staged{}, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	filename, err := uploadFilename(prefix, img.ext)
+	if err != nil {
+		return Staged{}, err
+	}
+
+	staged := Staged{
+		Path:        fmt.Sprintf("/uploads/%s/%s", subDir, filename),
+		tempPath:    filepath.Join(stagingDir, filename),
+		size:        int64(len(img.data)),
+		contentType: img.contentType,
+		hash:        hash,
+	}
+
+	if err := os.WriteFile(staged.tempPath, img.data, 0644); err != nil {
+		_ = os.Remove(staged.tempPath)
+		return Staged{}, fmt.Errorf("failed to save file: %w", err)
+	}
+
+	// The thumbnail is made now so the commit, which runs inside a transaction, only stores it
+	staged.thumbnail, err = makeThumbnail(bytes.NewReader(img.data))
+	if err != nil && logger != nil {
+		logger.Warn("Photo staged without thumbnail", zap.String("filename", filename), zap.Error(err))
+	}
+
+	if logger != nil {
+		logger.Debug("File staged", zap.String("filename", filename), zap.String("subDir", subDir))
+	}
+
+	return staged, nil
+}
+
+// CommitStaged moves staged files to the upload storage; reused photos are already there
+func (s *Service) CommitStaged(ctx context.Context, uploads []Staged, logger *zap.Logger) error {
+	logger = utils.RequestLogger(ctx, logger)
+	for _, upload := range uploads {
+		if err := s.commit(ctx, upload); err != nil {
+			return err
+		}
+
+		if logger != nil {
+			logger.Info("File uploaded successfully", zap.String("path", upload.Path))
+		}
+	}
+	return nil
+}
+
+func (s *Service) commit(ctx context.Context, upload Staged) error {
+	if upload.tempPath == "" {
+		return nil
+	}
+
+	f, err := os.Open(upload.tempPath)
+	if err != nil {
+		return fmt.Errorf("failed to open staged file: %w", err)
+	}
+	defer f.Close()
+
+	if err := s.storage.Put(ctx, utils.UploadKey(upload.Path), f, upload.size, upload.contentType); err != nil {
+		return fmt.Errorf("failed to move staged file: %w", err)
+	}
+	if upload.thumbnail != nil {
+		thumbKey := utils.UploadKey(utils.ThumbnailPath(upload.Path))
+		if err := s.storage.Put(ctx, thumbKey, bytes.NewReader(upload.thumbnail), int64(len(upload.thumbnail)), "image/jpeg"); err != nil {
+			return fmt.Errorf("failed to store thumbnail: %w", err)
+		}
+	}
+	if err := s.register(ctx, upload.Path, upload.hash, upload.size); err != nil {
+		return err
+	}
+	_ = os.Remove(upload.tempPath)
+	return nil
+}
+
+// DiscardStaged removes staged files and any that were already moved to the upload storage,
+// and drops the references to reused photos
+func (s *Service) DiscardStaged(uploads []Staged, logger *zap.Logger) {
+	// Cleanup also runs after the request was cancelled
+	ctx := context.Background()
+	for _, upload := range uploads {
+		if upload.tempPath != "" {
+			if err := os.Remove(upload.tempPath); err != nil && !os.IsNotExist(err) && logger != nil {
+				logger.Warn("Failed to remove discarded upload", zap.Error(err), zap.String("path", upload.tempPath))
+			}
+		}
+		last, err := s.release(ctx, upload.Path)
+		if err != nil && logger != nil {
+			logger.Warn("Failed to release discarded upload", zap.Error(err), zap.String("path", upload.Path))
+		}
+		if !last {
+			continue
+		}
+		for _, path := range []string{upload.Path, utils.ThumbnailPath(upload.Path)} {
+			if err := s.storage.Delete(ctx, utils.UploadKey(path)); err != nil && logger != nil {
+				logger.Warn("Failed to remove discarded upload", zap.Error(err), zap.String("path", path))
+			}
+		}
+	}
+}
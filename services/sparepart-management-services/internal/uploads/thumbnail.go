@@ -0,0 +1,113 @@
+package uploads
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// Decoders for every accepted photo type
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"sparepart-management-services/internal/storage"
+	"sparepart-management-services/internal/utils"
+
+	"golang.org/x/image/draw"
+)
+
+// thumbnailMaxSize is the longer side of a thumbnail in pixels
+const thumbnailMaxSize = 320
+
+// makeThumbnail decodes a photo and encodes it scaled down to thumbnailMaxSize as JPEG;
+// photos that are already small enough keep their size
+func makeThumbnail(r io.Reader) ([]byte, error) {
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longer := max(width, height); longer > thumbnailMaxSize {
+		width = max(1, width*thumbnailMaxSize/longer)
+		height = max(1, height*thumbnailMaxSize/longer)
+	}
+
+	// JPEG has no transparency, so transparent areas become white
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// saveThumbnail stores the thumbnail of the photo read from r under the photo's thumbnail
+// path. A photo that cannot be decoded gets no thumbnail, which only costs clients the
+// smaller download, so the failure is logged by the callers instead of failing the upload.
+func (s *Service) saveThumbnail(ctx context.Context, filePath string, r io.Reader) error {
+	thumb, err := makeThumbnail(r)
+	if err != nil {
+		return err
+	}
+	return s.storage.Put(ctx, utils.UploadKey(utils.ThumbnailPath(filePath)), bytes.NewReader(thumb), int64(len(thumb)), "image/jpeg")
+}
+
+// EnsureThumbnail creates the missing thumbnail of a stored photo, reporting whether one was
+// created; used to backfill photos uploaded before thumbnails existed
+func (s *Service) EnsureThumbnail(ctx context.Context, filePath string) (bool, error) {
+	exists, err := s.storage.Exists(ctx, utils.UploadKey(utils.ThumbnailPath(filePath)))
+	if err != nil || exists {
+		return false, err
+	}
+
+	body, _, err := s.storage.Get(ctx, utils.UploadKey(filePath))
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+
+	if err := s.saveThumbnail(ctx, filePath, body); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LoadThumbnail returns the JPEG thumbnail of a stored photo from the upload storage (local
+// disk or S3); a photo without a stored thumbnail, e.g. one uploaded before thumbnails existed,
+// is scaled down on the fly
+func (s *Service) LoadThumbnail(ctx context.Context, filePath string) ([]byte, error) {
+	body, _, err := s.storage.Get(ctx, utils.UploadKey(utils.ThumbnailPath(filePath)))
+	if errors.Is(err, storage.ErrNotExist) {
+		original, _, err := s.storage.Get(ctx, utils.UploadKey(filePath))
+		if err != nil {
+			return nil, err
+		}
+		defer original.Close()
+		return makeThumbnail(original)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	thumb, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	// A file gofpdf cannot parse would fail the whole PDF, so it is checked first
+	if _, err := jpeg.DecodeConfig(bytes.NewReader(thumb)); err != nil {
+		return nil, fmt.Errorf("invalid thumbnail: %w", err)
+	}
+	return thumb, nil
+}
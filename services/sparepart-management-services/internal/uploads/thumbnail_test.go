@@ -0,0 +1,82 @@
+package uploads
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"sparepart-management-services/internal/config"
+	"sparepart-management-services/internal/storage"
+)
+
+func TestMakeThumbnail(t *testing.T) {
+	encode := func(width, height int) *bytes.Buffer {
+		img := image.NewNRGBA(image.Rect(0, 0, width, height))
+		img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatalf("failed to encode test image: %v", err)
+		}
+		return &buf
+	}
+
+	tests := []struct {
+		width, height int
+		want          image.Point
+	}{
+		{800, 400, image.Pt(320, 160)},
+		{300, 1200, image.Pt(80, 320)},
+		{100, 50, image.Pt(100, 50)},
+	}
+	for _, tt := range tests {
+		thumb, err := makeThumbnail(encode(tt.width, tt.height))
+		if err != nil {
+			t.Fatalf("makeThumbnail failed: %v", err)
+		}
+		img, err := jpeg.Decode(bytes.NewReader(thumb))
+		if err != nil {
+			t.Fatalf("thumbnail is not a JPEG: %v", err)
+		}
+		if got := img.Bounds().Size(); got != tt.want {
+			t.Fatalf("thumbnail of %dx%d is %v, want %v", tt.width, tt.height, got, tt.want)
+		}
+	}
+
+	if _, err := makeThumbnail(bytes.NewReader([]byte("not an image"))); err == nil {
+		t.Fatal("expected an error for data that is not an image")
+	}
+}
+
+func TestLoadThumbnail(t *testing.T) {
+	ctx := context.Background()
+	s := New(storage.NewLocal(t.TempDir()), nil, config.UploadConfig{})
+
+	var photo bytes.Buffer
+	if err := png.Encode(&photo, image.NewNRGBA(image.Rect(0, 0, 640, 480))); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	// Only the original is stored, so its thumbnail is made on the fly
+	if err := s.storage.Put(ctx, "sparepart/new_stock/a.png", &photo, int64(photo.Len()), "image/png"); err != nil {
+		t.Fatalf("failed to store photo: %v", err)
+	}
+
+	thumb, err := s.LoadThumbnail(ctx, "/uploads/sparepart/new_stock/a.png")
+	if err != nil {
+		t.Fatalf("LoadThumbnail failed: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatalf("thumbnail is not a JPEG: %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(320, 240) {
+		t.Fatalf("thumbnail is %v, want 320x240", got)
+	}
+
+	if _, err := s.LoadThumbnail(ctx, "/uploads/sparepart/new_stock/missing.png"); err == nil {
+		t.Fatal("expected an error for a missing photo")
+	}
+}
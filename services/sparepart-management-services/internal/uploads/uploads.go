@@ -0,0 +1,119 @@
+// Package uploads stores, deduplicates and removes the photos uploaded with stock, tools alker
+// and other items; the service is built once in main and passed to the handlers.
+package uploads
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+
+	"sparepart-management-services/internal/config"
+	"sparepart-management-services/internal/repository"
+	"sparepart-management-services/internal/storage"
+	"sparepart-management-services/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// Service keeps photos in the upload storage. With an index, an uploaded photo identical to a
+// stored one reuses its file, and a file is only deleted with its last reference; without one
+// every upload is stored as a new file.
+type Service struct {
+	storage storage.Storage
+	index   repository.UploadFileRepository
+	cfg     config.UploadConfig
+}
+
+// New returns the service keeping photos in s, recorded in index (nil for none); cfg gives
+// the size limit, re-encoding, staging directory and quota of uploads
+func New(s storage.Storage, index repository.UploadFileRepository, cfg config.UploadConfig) *Service {
+	return &Service{storage: s, index: index, cfg: cfg}
+}
+
+// ProcessImage handles image upload with subdirectory support; the photo is checked and
+// stripped of its metadata first (see sanitize), and a photo identical to a stored one returns
+// the path of that one instead of being stored again
+// subDir: subdirectory within uploads (e.g., "sparepart/new_stock", "tools_alker")
+// prefix: filename prefix (e.g., "sparepart_stock_new", "tools_alker")
+func (s *Service) ProcessImage(ctx context.Context, file *multipart.FileHeader, subDir string, prefix string, logger *zap.Logger) (string, error) {
+	logger = utils.RequestLogger(ctx, logger)
+	img, err := s.sanitize(file)
+	if err != nil {
+		return "", err
+	}
+
+	hash := contentHash(img.data)
+	if path := s.reuse(ctx, hash, logger); path != "" {
+		return path, nil
+	}
+
+	filename, err := uploadFilename(prefix, img.ext)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.storage.Put(ctx, subDir+"/"+filename, bytes.NewReader(img.data), int64(len(img.data)), img.contentType); err != nil {
+		return "", err
+	}
+
+	// Return relative path for storage in database
+	relativePath := fmt.Sprintf("/uploads/%s/%s", subDir, filename)
+
+	err = s.saveThumbnail(ctx, relativePath, bytes.NewReader(img.data))
+	if err != nil && logger != nil {
+		logger.Warn("Photo uploaded without thumbnail", zap.String("path", relativePath), zap.Error(err))
+	}
+
+	// A file the index does not know about would be deleted while another item reuses it
+	if err := s.register(ctx, relativePath, hash, int64(len(img.data))); err != nil {
+		for _, path := range []string{relativePath, utils.ThumbnailPath(relativePath)} {
+			_ = s.storage.Delete(ctx, utils.UploadKey(path))
+		}
+		return "", err
+	}
+
+	if logger != nil {
+		logger.Info("File uploaded successfully",
+			zap.String("filename", filename),
+			zap.String("path", relativePath),
+			zap.String("subDir", subDir),
+		)
+	}
+
+	return relativePath, nil
+}
+
+// Exists reports whether a stored /uploads/... reference points to an existing file
+func (s *Service) Exists(ctx context.Context, filePath string) (bool, error) {
+	return s.storage.Exists(ctx, utils.UploadKey(filePath))
+}
+
+// DeleteFile drops a reference to a stored photo, and removes the photo and its thumbnail
+// when it was the last one
+func (s *Service) DeleteFile(ctx context.Context, filePath string, logger *zap.Logger) error {
+	logger = utils.RequestLogger(ctx, logger)
+	last, err := s.release(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	if !last {
+		if logger != nil {
+			logger.Info("File kept, still referenced", zap.String("path", filePath))
+		}
+		return nil
+	}
+
+	if err := s.storage.Delete(ctx, utils.UploadKey(filePath)); err != nil {
+		return err
+	}
+	if err := s.storage.Delete(ctx, utils.UploadKey(utils.ThumbnailPath(filePath))); err != nil {
+		return err
+	}
+
+	if logger != nil {
+		logger.Info("File deleted", zap.String("path", filePath))
+	}
+
+	return nil
+}
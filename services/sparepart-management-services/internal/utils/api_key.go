@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// APIKeyHeader carries an API key, the alternative to a bearer token for machine clients
+const APIKeyHeader = "X-API-Key"
+
+// API key scopes: read-only allows GET requests, stock-write also allows changing the stock
+const (
+	ScopeReadOnly   = "read-only"
+	ScopeStockWrite = "stock-write"
+)
+
+// apiKeyPrefixLength is how much of a key is kept in clear to tell keys apart
+const apiKeyPrefixLength = 12
+
+// NewAPIKey returns a random API key ("spk_..."), the prefix it is listed under and the
+// hash it is stored under
+func NewAPIKey() (key string, prefix string, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key = "spk_" + base64.RawURLEncoding.EncodeToString(raw)
+	return key, key[:apiKeyPrefixLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex SHA-256 of an API key; only the hash is stored
+func HashAPIKey(key string) string {
+	return HashShareToken(key)
+}
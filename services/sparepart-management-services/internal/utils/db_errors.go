@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Stable error codes returned in Response.Code so clients don't have to parse messages
+const (
+	ErrCodeValidation       = "VALIDATION_FAILED"
+	ErrCodeInvalidReference = "INVALID_REFERENCE"
+	ErrCodeDuplicate        = "DUPLICATE"
+	ErrCodeInUse            = "IN_USE"
+	ErrCodeVersionConflict  = "VERSION_CONFLICT"
+	ErrCodeTimeout          = "TIMEOUT"
+)
+
+// PostgreSQL SQLSTATE codes for constraint violations
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+)
+
+// checkConstraintErrors maps CHECK constraints to the request field they guard
+var checkConstraintErrors = map[string]FieldError{
+	"sparepart_stock_item_quantity_non_negative": NegativeQuantityError("quantity"),
+	"tools_alker_item_quantity_non_negative":     NegativeQuantityError("quantity"),
+	"location_latitude_range":                    LatitudeRangeError,
+	"location_longitude_range":                   LongitudeRangeError,
+	"location_coordinates_pair":                  CoordinatesPairError,
+	"regency_name_not_blank":                     BlankNameError,
+	"cluster_name_not_blank":                     BlankNameError,
+	"supplier_name_not_blank":                    BlankNameError,
+	"technician_name_not_blank":                  BlankNameError,
+	"list_sparepart_unit_valid":                  UnitError,
+	"stock_level_min_non_negative":               NegativeQuantityError("min_quantity"),
+	"stock_level_max_not_below_min":              StockLevelRangeError,
+	"list_sparepart_unit_cost_valid":             UnitCostError("unit_cost"),
+	"sparepart_stock_item_unit_cost_valid":       UnitCostError("unit_cost"),
+}
+
+// foreignKeyFields maps foreign key constraints to the request field holding the reference
+var foreignKeyFields = map[string]string{
+	"contact_person_location_id_fkey":                "location_id",
+	"location_cluster_fkey":                          "cluster",
+	"sparepart_stock_item_location_id_fkey":          "location_id",
+	"sparepart_stock_item_sparepart_id_fkey":         "sparepart_id",
+	"tools_alker_item_location_id_fkey":              "location_id",
+	"tools_alker_item_tools_id_fkey":                 "tools_id",
+	"goods_receipt_supplier_id_fkey":                 "supplier_id",
+	"purchase_order_supplier_id_fkey":                "supplier_id",
+	"stock_unit_supplier_id_fkey":                    "supplier_id",
+	"tools_alker_checkout_technician_id_fkey":        "technician_id",
+	"work_order_technician_id_fkey":                  "technician_id",
+	"sparepart_request_technician_id_fkey":           "technician_id",
+	"stock_level_location_id_fkey":                   "location_id",
+	"stock_level_sparepart_id_fkey":                  "sparepart_id",
+	"stock_opname_location_id_fkey":                  "location_id",
+	"stock_opname_item_sparepart_id_fkey":            "sparepart_id",
+	"sparepart_request_destination_location_id_fkey": "destination_location_id",
+	"sparepart_request_source_location_id_fkey":      "source_location_id",
+	"sparepart_request_item_sparepart_id_fkey":       "sparepart_id",
+	"goods_receipt_location_id_fkey":                 "location_id",
+	"goods_receipt_item_sparepart_id_fkey":           "sparepart_id",
+	"work_order_location_id_fkey":                    "location_id",
+	"work_order_item_sparepart_id_fkey":              "sparepart_id",
+	"work_order_tool_tools_id_fkey":                  "tools_id",
+}
+
+// uniqueConstraintMessages describes what a unique constraint violation means to the client
+var uniqueConstraintMessages = map[string]string{
+	"unique_location":                     "Location with the same region, regency and cluster already exists",
+	"unique_regency":                      "Regency with the same region and name already exists",
+	"unique_cluster":                      "Cluster with the same name already exists in this regency",
+	"list_sparepart_name_key":             "Sparepart with the same name already exists",
+	"unique_sparepart_stock":              "Stock item for this location, sparepart and stock type already exists",
+	"unique_tools_alker":                  "Tools alker item for this location and tool already exists",
+	"unique_stock_unit_serial_number":     "Stock unit with the same serial number is already registered",
+	"unique_stock_unit_asset_tag":         "Stock unit with the same asset tag is already registered",
+	"unique_goods_receipt_delivery_order": "Goods receipt for this supplier's delivery order number already exists",
+	"unique_supplier_name":                "Supplier with the same name already exists",
+	"unique_technician_name":              "Technician with the same name already exists",
+}
+
+// IsForeignKeyViolation reports whether err is a foreign key violation, e.g. deleting a row
+// that other rows still reference
+func IsForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
+
+// dbErrorResponse translates a constraint violation into a client error response.
+// It reports false for any other error, which stays a server error.
+func dbErrorResponse(err error) (int, Response, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return 0, Response{}, false
+	}
+
+	switch pgErr.Code {
+	case pgCheckViolation:
+		field, ok := checkConstraintErrors[pgErr.ConstraintName]
+		if !ok {
+			return 0, Response{}, false
+		}
+		return http.StatusBadRequest, Response{
+			Error:  "Validation failed",
+			Code:   ErrCodeValidation,
+			Errors: []FieldError{field},
+		}, true
+
+	case pgForeignKeyViolation:
+		field, ok := foreignKeyFields[pgErr.ConstraintName]
+		if !ok {
+			return http.StatusBadRequest, Response{
+				Error: "Referenced record does not exist",
+				Code:  ErrCodeInvalidReference,
+			}, true
+		}
+		return http.StatusBadRequest, Response{
+			Error:  field + " does not exist",
+			Code:   ErrCodeInvalidReference,
+			Errors: []FieldError{{Field: field, Message: "does not exist"}},
+		}, true
+
+	case pgUniqueViolation:
+		message, ok := uniqueConstraintMessages[pgErr.ConstraintName]
+		if !ok {
+			message = "Duplicate record"
+		}
+		return http.StatusConflict, Response{
+			Error: message,
+			Code:  ErrCodeDuplicate,
+		}, true
+	}
+
+	return 0, Response{}, false
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+)
+
+// UploadDirStatus is the result of checking the uploads directory for readiness
+type UploadDirStatus struct {
+	Path         string `json:"path"`
+	Writable     bool   `json:"writable"`
+	FreeBytes    uint64 `json:"free_bytes"`
+	MinFreeBytes uint64 `json:"min_free_bytes"`
+	Error        string `json:"error,omitempty"`
+}
+
+// OK reports whether uploads can be written and the free space is above the threshold
+func (s UploadDirStatus) OK() bool {
+	return s.Writable && s.Error == "" && s.FreeBytes >= s.MinFreeBytes
+}
+
+// CheckUploadDir verifies dir is writable by creating and removing a probe file, and
+// reports the free space of the filesystem holding it
+func CheckUploadDir(dir string, minFreeBytes uint64) UploadDirStatus {
+	status := UploadDirStatus{Path: dir, MinFreeBytes: minFreeBytes}
+
+	if err := probeWritable(dir); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Writable = true
+
+	free, err := freeDiskBytes(dir)
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to read free space: %v", err)
+		return status
+	}
+	status.FreeBytes = free
+	if free < minFreeBytes {
+		status.Error = "free space below threshold"
+	}
+	return status
+}
+
+func probeWritable(dir string) error {
+	probe, err := os.CreateTemp(dir, ".ready-*")
+	if err != nil {
+		return fmt.Errorf("uploads directory is not writable: %w", err)
+	}
+	name := probe.Name()
+	defer os.Remove(name)
+
+	if _, err := probe.Write([]byte{0}); err != nil {
+		probe.Close()
+		return fmt.Errorf("uploads directory is not writable: %w", err)
+	}
+	if err := probe.Close(); err != nil {
+		return fmt.Errorf("uploads directory is not writable: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckUploadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	status := CheckUploadDir(dir, 0)
+	if !status.OK() || !status.Writable || status.FreeBytes == 0 {
+		t.Fatalf("expected writable directory with free space, got %+v", status)
+	}
+
+	status = CheckUploadDir(dir, math.MaxUint64)
+	if status.OK() || !status.Writable {
+		t.Fatalf("expected free space threshold to fail, got %+v", status)
+	}
+
+	status = CheckUploadDir(filepath.Join(dir, "missing"), 0)
+	if status.OK() || status.Writable {
+		t.Fatalf("expected missing directory to fail, got %+v", status)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, ".ready-*"))
+	if len(matches) != 0 {
+		t.Fatalf("probe files left behind: %v", matches)
+	}
+}
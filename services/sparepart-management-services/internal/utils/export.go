@@ -2,9 +2,13 @@ package utils
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"encoding/csv"
 	"fmt"
+	"io"
+	"math"
 	"strconv"
+	"time"
 
 	"github.com/jung-kurt/gofpdf"
 	"github.com/xuri/excelize/v2"
@@ -12,8 +16,57 @@ import (
 	sqlcdb "sparepart-management-services/internal/database/sqlc"
 )
 
-// ExportSparepartStockToPDF exports sparepart stock items to PDF in landscape mode
-func ExportSparepartStockToPDF(items []sqlcdb.ListSparepartStocksForExportRow, logger *zap.Logger) (*bytes.Buffer, error) {
+// BatchReader returns the next batch of export rows; an empty batch means there are no more rows.
+// Exports read through it so only one batch is held in memory at a time.
+type BatchReader[T any] func() ([]T, error)
+
+// forEachRow calls fn for every row returned by next, batch by batch
+func forEachRow[T any](next BatchReader[T], fn func(T) error) error {
+	for {
+		batch, err := next()
+		if err != nil {
+			return fmt.Errorf("failed to read export rows: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		for _, item := range batch {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ExportRowsKey is the gin context key export handlers report the number of written rows under,
+// for the export log (see middleware.RecordExport)
+const ExportRowsKey = "export_rows"
+
+// CountRows wraps next so that *n holds the number of rows read through it
+func CountRows[T any](next BatchReader[T], n *int) BatchReader[T] {
+	return func() ([]T, error) {
+		batch, err := next()
+		*n += len(batch)
+		return batch, err
+	}
+}
+
+// truncateText shortens s to max bytes with an ellipsis so it fits a PDF table cell
+func truncateText(s string, max int) string {
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
+
+// countDocs returns the number of photos in a documentation JSONB array
+func countDocs(documentation []byte) int {
+	return len(documentationPaths(documentation))
+}
+
+// ExportSparepartStockToPDF exports sparepart stock items to PDF in landscape mode; with
+// photos the thumbnails of the items' photos follow the table in a photos appendix
+func ExportSparepartStockToPDF(ctx context.Context, next BatchReader[sqlcdb.ListSparepartStocksForExportRow], photos ThumbnailLoader, logger *zap.Logger) (*bytes.Buffer, error) {
 	pdf := gofpdf.New("L", "mm", "A4", "") // Landscape, mm, A4
 	pdf.AddPage()
 	pdf.SetFont("Arial", "B", 16)
@@ -23,9 +76,9 @@ func ExportSparepartStockToPDF(items []sqlcdb.ListSparepartStocksForExportRow, l
 	// Table header
 	pdf.SetFont("Arial", "B", 9)
 	pdf.SetFillColor(200, 200, 200)
-	headers := []string{"ID", "Location", "Sparepart", "Stock Type", "Quantity", "Notes", "Photos"}
-	colWidths := []float64{15, 50, 50, 30, 20, 40, 30}
-	
+	headers := []string{"ID", "Location", "Sparepart", "Stock Type", "Quantity", "Unit", "Notes", "Photos", "PIC", "Phone"}
+	colWidths := []float64{12, 45, 45, 25, 16, 14, 28, 20, 38, 30}
+
 	// Print header
 	for i, header := range headers {
 		pdf.CellFormat(colWidths[i], 7, header, "1", 0, "C", true, 0, "")
@@ -35,24 +88,17 @@ func ExportSparepartStockToPDF(items []sqlcdb.ListSparepartStocksForExportRow, l
 	// Table data
 	pdf.SetFont("Arial", "", 8)
 	pdf.SetFillColor(255, 255, 255)
-	for _, item := range items {
+	var sections []photoSection
+	err := forEachRow(next, func(item sqlcdb.ListSparepartStocksForExportRow) error {
 		location := fmt.Sprintf("%s - %s", item.Regency, item.Cluster)
 		sparepart := item.SparepartName
 		stockType := string(item.StockType)
 		quantity := strconv.Itoa(int(item.Quantity))
 		notes := ""
 		if item.Notes.Valid {
-			notes = item.Notes.String
-			if len(notes) > 30 {
-				notes = notes[:30] + "..."
-			}
-		}
-		// Parse documentation JSONB
-		var docs []string
-		if len(item.Documentation) > 0 {
-			json.Unmarshal(item.Documentation, &docs)
+			notes = truncateText(item.Notes.String, 24)
 		}
-		photos := fmt.Sprintf("%d photo(s)", len(docs))
+		photoCount := fmt.Sprintf("%d photo(s)", countDocs(item.Documentation))
 
 		// Handle text wrapping for long content
 		rowHeight := 7.0
@@ -65,9 +111,26 @@ func ExportSparepartStockToPDF(items []sqlcdb.ListSparepartStocksForExportRow, l
 		pdf.CellFormat(colWidths[2], rowHeight, sparepart, "1", 0, "L", false, 0, "")
 		pdf.CellFormat(colWidths[3], rowHeight, stockType, "1", 0, "C", false, 0, "")
 		pdf.CellFormat(colWidths[4], rowHeight, quantity, "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths[5], rowHeight, notes, "1", 0, "L", false, 0, "")
-		pdf.CellFormat(colWidths[6], rowHeight, photos, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[5], rowHeight, item.Unit, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[6], rowHeight, notes, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colWidths[7], rowHeight, photoCount, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[8], rowHeight, truncateText(item.Pic.String, 25), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colWidths[9], rowHeight, item.Phone.String, "1", 0, "L", false, 0, "")
 		pdf.Ln(-1)
+
+		if paths := documentationPaths(item.Documentation); photos != nil && len(paths) > 0 {
+			sections = append(sections, photoSection{
+				title: fmt.Sprintf("#%d %s - %s", item.ID, sparepart, location),
+				paths: paths,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if photos != nil {
+		writePhotoAppendix(ctx, pdf, sections, photos, logger)
 	}
 
 	var buf bytes.Buffer
@@ -81,76 +144,93 @@ func ExportSparepartStockToPDF(items []sqlcdb.ListSparepartStocksForExportRow, l
 	return &buf, nil
 }
 
-// ExportSparepartStockToExcel exports sparepart stock items to Excel
-func ExportSparepartStockToExcel(items []sqlcdb.ListSparepartStocksForExportRow, logger *zap.Logger) (*bytes.Buffer, error) {
-	f := excelize.NewFile()
-	defer func() {
-		if err := f.Close(); err != nil {
-			if logger != nil {
-				logger.Error("Failed to close Excel file", zap.Error(err))
-			}
-		}
-	}()
+// ExportLocationStockToPDF renders the stock of one location as a snapshot, e.g. for a share link
+func ExportLocationStockToPDF(location sqlcdb.Location, items []sqlcdb.ListSparepartStocksByLocationRow, generatedAt time.Time, logger *zap.Logger) (*bytes.Buffer, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, "Location Stock Snapshot")
+	pdf.Ln(10)
 
-	sheetName := "Sparepart Stock"
-	f.NewSheet(sheetName)
-	f.DeleteSheet("Sheet1")
+	pdf.SetFont("Arial", "", 10)
+	pdf.Cell(40, 6, fmt.Sprintf("%s - %s - %s", location.Region, location.Regency, location.Cluster))
+	pdf.Ln(6)
+	pdf.Cell(40, 6, "Generated at "+generatedAt.UTC().Format("2006-01-02 15:04:05")+" UTC")
+	pdf.Ln(10)
 
-	// Set header
-	headers := []string{"ID", "Region", "Regency", "Cluster", "Sparepart Name", "Stock Type", "Quantity", "Notes", "Photos Count", "Created At"}
+	// Table header
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(200, 200, 200)
+	headers := []string{"Sparepart", "Stock Type", "Quantity", "Photos", "Notes"}
+	colWidths := []float64{55, 25, 20, 20, 70}
 	for i, header := range headers {
-		cell := fmt.Sprintf("%c1", 'A'+i)
-		f.SetCellValue(sheetName, cell, header)
-		f.SetCellStyle(sheetName, cell, cell, getHeaderStyle(f))
+		pdf.CellFormat(colWidths[i], 7, header, "1", 0, "C", true, 0, "")
 	}
+	pdf.Ln(-1)
 
-	// Set data
-	for i, item := range items {
-		row := i + 2
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), item.ID)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), string(item.Region))
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), item.Regency)
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), item.Cluster)
-		f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), item.SparepartName)
-		f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), string(item.StockType))
-		f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), item.Quantity)
+	// Table data
+	pdf.SetFont("Arial", "", 8)
+	for _, item := range items {
 		notes := ""
 		if item.Notes.Valid {
-			notes = item.Notes.String
+			notes = truncateText(item.Notes.String, 45)
 		}
-		f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), notes)
-		// Parse documentation JSONB
-		var docs []string
-		if len(item.Documentation) > 0 {
-			json.Unmarshal(item.Documentation, &docs)
-		}
-		f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), len(docs))
-		createdAt := ""
-		if item.CreatedAt.Valid {
-			createdAt = item.CreatedAt.Time.Format("2006-01-02 15:04:05")
-		}
-		f.SetCellValue(sheetName, fmt.Sprintf("J%d", row), createdAt)
-	}
-
-	// Auto-fit columns
-	for i := 0; i < len(headers); i++ {
-		col := string(rune('A' + i))
-		f.SetColWidth(sheetName, col, col, 15)
+		pdf.CellFormat(colWidths[0], 7, truncateText(item.SparepartName, 35), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colWidths[1], 7, string(item.StockType), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[2], 7, strconv.Itoa(int(item.Quantity)), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[3], 7, strconv.Itoa(countDocs(item.Documentation)), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[4], 7, notes, "1", 0, "L", false, 0, "")
+		pdf.Ln(-1)
 	}
 
 	var buf bytes.Buffer
-	if err := f.Write(&buf); err != nil {
+	if err := pdf.Output(&buf); err != nil {
 		if logger != nil {
-			logger.Error("Failed to write Excel file", zap.Error(err))
+			logger.Error("Failed to generate PDF", zap.Error(err))
 		}
-		return nil, fmt.Errorf("failed to write Excel file: %w", err)
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
 	}
 
 	return &buf, nil
 }
 
-// ExportToolsAlkerToPDF exports tools alker items to PDF in landscape mode
-func ExportToolsAlkerToPDF(items []sqlcdb.ListToolsAlkersForExportRow, logger *zap.Logger) (*bytes.Buffer, error) {
+// sparepartStockExportHeaders are the columns of the tabular sparepart stock exports (Excel, CSV)
+var sparepartStockExportHeaders = []string{"ID", "Region", "Regency", "Cluster", "Sparepart Name", "Stock Type", "Quantity", "Unit", "Notes", "Photos Count", "PIC", "Phone", "Units Under Warranty", "Units Warranty Expired", "Next Warranty End", "Created At"}
+
+// sparepartStockExportRow returns the values of a sparepart stock item in sparepartStockExportHeaders order
+func sparepartStockExportRow(item sqlcdb.ListSparepartStocksForExportRow) []interface{} {
+	notes := ""
+	if item.Notes.Valid {
+		notes = item.Notes.String
+	}
+	createdAt := ""
+	if item.CreatedAt.Valid {
+		createdAt = item.CreatedAt.Time.UTC().Format("2006-01-02 15:04:05")
+	}
+	nextWarrantyEnd := ""
+	if item.NextWarrantyEnd.Valid {
+		nextWarrantyEnd = item.NextWarrantyEnd.Time.Format("2006-01-02")
+	}
+	return []interface{}{
+		item.ID, string(item.Region), item.Regency, item.Cluster, item.SparepartName,
+		string(item.StockType), item.Quantity, item.Unit, notes, countDocs(item.Documentation),
+		item.Pic.String, item.Phone.String, item.UnitsUnderWarranty, item.UnitsWarrantyExpired, nextWarrantyEnd, createdAt,
+	}
+}
+
+// ExportSparepartStockToExcel exports sparepart stock items to Excel
+func ExportSparepartStockToExcel(next BatchReader[sqlcdb.ListSparepartStocksForExportRow], logger *zap.Logger) (*bytes.Buffer, error) {
+	return writeExcelStream("Sparepart Stock", sparepartStockExportHeaders, next, sparepartStockExportRow, logger)
+}
+
+// WriteSparepartStockCSV writes sparepart stock items to w as CSV, with the Excel export's columns
+func WriteSparepartStockCSV(w io.Writer, next BatchReader[sqlcdb.ListSparepartStocksForExportRow]) error {
+	return writeCSVStream(w, sparepartStockExportHeaders, next, sparepartStockExportRow)
+}
+
+// ExportToolsAlkerToPDF exports tools alker items to PDF in landscape mode; with photos the
+// thumbnails of the items' photos follow the table in a photos appendix
+func ExportToolsAlkerToPDF(ctx context.Context, next BatchReader[sqlcdb.ListToolsAlkersForExportRow], photos ThumbnailLoader, logger *zap.Logger) (*bytes.Buffer, error) {
 	pdf := gofpdf.New("L", "mm", "A4", "") // Landscape, mm, A4
 	pdf.AddPage()
 	pdf.SetFont("Arial", "B", 16)
@@ -160,9 +240,9 @@ func ExportToolsAlkerToPDF(items []sqlcdb.ListToolsAlkersForExportRow, logger *z
 	// Table header
 	pdf.SetFont("Arial", "B", 9)
 	pdf.SetFillColor(200, 200, 200)
-	headers := []string{"ID", "Location", "Tools", "Quantity", "Notes", "Photos"}
-	colWidths := []float64{15, 60, 60, 20, 50, 30}
-	
+	headers := []string{"ID", "Location", "Tools", "Quantity", "Notes", "Photos", "PIC", "Phone"}
+	colWidths := []float64{12, 50, 50, 16, 40, 20, 50, 35}
+
 	// Print header
 	for i, header := range headers {
 		pdf.CellFormat(colWidths[i], 7, header, "1", 0, "C", true, 0, "")
@@ -172,23 +252,16 @@ func ExportToolsAlkerToPDF(items []sqlcdb.ListToolsAlkersForExportRow, logger *z
 	// Table data
 	pdf.SetFont("Arial", "", 8)
 	pdf.SetFillColor(255, 255, 255)
-	for _, item := range items {
+	var sections []photoSection
+	err := forEachRow(next, func(item sqlcdb.ListToolsAlkersForExportRow) error {
 		location := fmt.Sprintf("%s - %s", item.Regency, item.Cluster)
 		tools := item.ToolsName
 		quantity := strconv.Itoa(int(item.Quantity))
 		notes := ""
 		if item.Notes.Valid {
-			notes = item.Notes.String
-			if len(notes) > 30 {
-				notes = notes[:30] + "..."
-			}
-		}
-		// Parse documentation JSONB
-		var docs []string
-		if len(item.Documentation) > 0 {
-			json.Unmarshal(item.Documentation, &docs)
+			notes = truncateText(item.Notes.String, 30)
 		}
-		photos := fmt.Sprintf("%d photo(s)", len(docs))
+		photoCount := fmt.Sprintf("%d photo(s)", countDocs(item.Documentation))
 
 		rowHeight := 7.0
 		if len(location) > 30 || len(tools) > 30 {
@@ -200,8 +273,24 @@ func ExportToolsAlkerToPDF(items []sqlcdb.ListToolsAlkersForExportRow, logger *z
 		pdf.CellFormat(colWidths[2], rowHeight, tools, "1", 0, "L", false, 0, "")
 		pdf.CellFormat(colWidths[3], rowHeight, quantity, "1", 0, "C", false, 0, "")
 		pdf.CellFormat(colWidths[4], rowHeight, notes, "1", 0, "L", false, 0, "")
-		pdf.CellFormat(colWidths[5], rowHeight, photos, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[5], rowHeight, photoCount, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[6], rowHeight, truncateText(item.Pic.String, 30), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colWidths[7], rowHeight, item.Phone.String, "1", 0, "L", false, 0, "")
 		pdf.Ln(-1)
+
+		if paths := documentationPaths(item.Documentation); photos != nil && len(paths) > 0 {
+			sections = append(sections, photoSection{
+				title: fmt.Sprintf("#%d %s - %s", item.ID, tools, location),
+				paths: paths,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if photos != nil {
+		writePhotoAppendix(ctx, pdf, sections, photos, logger)
 	}
 
 	var buf bytes.Buffer
@@ -215,8 +304,139 @@ func ExportToolsAlkerToPDF(items []sqlcdb.ListToolsAlkersForExportRow, logger *z
 	return &buf, nil
 }
 
+// toolsAlkerExportHeaders are the columns of the tabular tools alker exports (Excel, CSV)
+var toolsAlkerExportHeaders = []string{"ID", "Region", "Regency", "Cluster", "Tools Name", "Quantity", "Notes", "Photos Count", "PIC", "Phone", "Created At"}
+
+// toolsAlkerExportRow returns the values of a tools alker item in toolsAlkerExportHeaders order
+func toolsAlkerExportRow(item sqlcdb.ListToolsAlkersForExportRow) []interface{} {
+	notes := ""
+	if item.Notes.Valid {
+		notes = item.Notes.String
+	}
+	createdAt := ""
+	if item.CreatedAt.Valid {
+		createdAt = item.CreatedAt.Time.UTC().Format("2006-01-02 15:04:05")
+	}
+	return []interface{}{
+		item.ID, string(item.Region), item.Regency, item.Cluster, item.ToolsName,
+		item.Quantity, notes, countDocs(item.Documentation),
+		item.Pic.String, item.Phone.String, createdAt,
+	}
+}
+
 // ExportToolsAlkerToExcel exports tools alker items to Excel
-func ExportToolsAlkerToExcel(items []sqlcdb.ListToolsAlkersForExportRow, logger *zap.Logger) (*bytes.Buffer, error) {
+func ExportToolsAlkerToExcel(next BatchReader[sqlcdb.ListToolsAlkersForExportRow], logger *zap.Logger) (*bytes.Buffer, error) {
+	return writeExcelStream("Tools Alker", toolsAlkerExportHeaders, next, toolsAlkerExportRow, logger)
+}
+
+// WriteToolsAlkerCSV writes tools alker items to w as CSV, with the Excel export's columns
+func WriteToolsAlkerCSV(w io.Writer, next BatchReader[sqlcdb.ListToolsAlkersForExportRow]) error {
+	return writeCSVStream(w, toolsAlkerExportHeaders, next, toolsAlkerExportRow)
+}
+
+// ExportLogToExcel exports export log entries to Excel
+func ExportLogToExcel(next BatchReader[sqlcdb.ExportLog], logger *zap.Logger) (*bytes.Buffer, error) {
+	headers := []string{"ID", "User", "Entity", "Format", "Filters", "Rows", "Duration (ms)", "Stored", "Created At"}
+	return writeExcelStream("Export Log", headers, next, func(item sqlcdb.ExportLog) []interface{} {
+		createdAt := ""
+		if item.CreatedAt.Valid {
+			createdAt = item.CreatedAt.Time.UTC().Format("2006-01-02 15:04:05")
+		}
+		return []interface{}{
+			item.ID, item.UserID.String, item.Entity, item.Format, string(item.Filters),
+			item.RowCount, item.DurationMs, item.Stored, createdAt,
+		}
+	}, logger)
+}
+
+// damageReportExportHeaders are the columns of the open damage report exports (Excel, CSV)
+var damageReportExportHeaders = []string{"ID", "Region", "Regency", "Cluster", "Sparepart Name", "Stock Type", "Quantity", "Severity", "Disposition", "Description", "Photos Count", "Reported By", "Reported At"}
+
+// damageReportExportRow returns the values of a damage report in damageReportExportHeaders order
+func damageReportExportRow(report sqlcdb.ListOpenDamageReportsForExportRow) []interface{} {
+	reportedAt := ""
+	if report.CreatedAt.Valid {
+		reportedAt = report.CreatedAt.Time.UTC().Format("2006-01-02 15:04:05")
+	}
+	return []interface{}{
+		report.ID, string(report.Region.RegionType), report.Regency.String, report.Cluster.String, report.SparepartName.String,
+		string(report.StockType), report.Quantity, report.Severity, report.Disposition, report.Description,
+		countDocs(report.Photos), report.ReportedBy.String, reportedAt,
+	}
+}
+
+// ExportDamageReportsToExcel exports damage reports to Excel
+func ExportDamageReportsToExcel(next BatchReader[sqlcdb.ListOpenDamageReportsForExportRow], logger *zap.Logger) (*bytes.Buffer, error) {
+	return writeExcelStream("Damage Reports", damageReportExportHeaders, next, damageReportExportRow, logger)
+}
+
+// WriteDamageReportsCSV writes damage reports to w as CSV, with the Excel export's columns
+func WriteDamageReportsCSV(w io.Writer, next BatchReader[sqlcdb.ListOpenDamageReportsForExportRow]) error {
+	return writeCSVStream(w, damageReportExportHeaders, next, damageReportExportRow)
+}
+
+// stockValuationExportHeaders are the columns of the stock valuation export
+var stockValuationExportHeaders = []string{"Region", "Regency", "Cluster", "Stock Items", "Quantity", "Items Without Cost", "Total Value"}
+
+// stockValuationTotals sums the valuation of locations; the value is kept in cents so the
+// sums stay exact
+type stockValuationTotals struct {
+	items, quantity, withoutCost, cents int64
+}
+
+func (t *stockValuationTotals) add(row sqlcdb.ListStockValuationRow) {
+	t.items += row.ItemCount
+	t.quantity += row.Quantity
+	t.withoutCost += row.ItemsWithoutCost
+	if value := NumericValue(row.TotalValue); value != nil {
+		t.cents += int64(math.Round(*value * 100))
+	}
+}
+
+func (t stockValuationTotals) row(region, label string) []interface{} {
+	return []interface{}{region, label, "", t.items, t.quantity, t.withoutCost, float64(t.cents) / 100}
+}
+
+// ExportStockValuationToExcel exports the inventory value per location, with a subtotal row
+// after the locations of each region and a grand total row at the end. rows must be ordered
+// by region.
+func ExportStockValuationToExcel(rows []sqlcdb.ListStockValuationRow, logger *zap.Logger) (*bytes.Buffer, error) {
+	var lines [][]interface{}
+	var region, total stockValuationTotals
+	for i, row := range rows {
+		var value float64
+		if v := NumericValue(row.TotalValue); v != nil {
+			value = *v
+		}
+		lines = append(lines, []interface{}{
+			string(row.Region), row.Regency, row.Cluster, row.ItemCount, row.Quantity, row.ItemsWithoutCost, value,
+		})
+		region.add(row)
+		total.add(row)
+		if i == len(rows)-1 || rows[i+1].Region != row.Region {
+			lines = append(lines, region.row(string(row.Region), "Subtotal"))
+			region = stockValuationTotals{}
+		}
+	}
+	lines = append(lines, total.row("Total", ""))
+
+	return writeExcelStream("Stock Valuation", stockValuationExportHeaders, sliceReader(lines), func(line []interface{}) []interface{} {
+		return line
+	}, logger)
+}
+
+// sliceReader returns items as a single batch, for exports small enough to read at once
+func sliceReader[T any](items []T) BatchReader[T] {
+	return func() ([]T, error) {
+		batch := items
+		items = nil
+		return batch, nil
+	}
+}
+
+// writeExcelStream writes a single-sheet workbook through excelize's stream writer,
+// which spills rows to a temp file instead of building the whole sheet in memory
+func writeExcelStream[T any](sheetName string, headers []string, next BatchReader[T], toRow func(T) []interface{}, logger *zap.Logger) (*bytes.Buffer, error) {
 	f := excelize.NewFile()
 	defer func() {
 		if err := f.Close(); err != nil {
@@ -226,49 +446,43 @@ func ExportToolsAlkerToExcel(items []sqlcdb.ListToolsAlkersForExportRow, logger
 		}
 	}()
 
-	sheetName := "Tools Alker"
-	f.NewSheet(sheetName)
-	f.DeleteSheet("Sheet1")
+	if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	sw, err := f.NewStreamWriter(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream writer: %w", err)
+	}
+
+	// Column widths must be set before the first row is written
+	if err := sw.SetColWidth(1, len(headers), 15); err != nil {
+		return nil, fmt.Errorf("failed to set column width: %w", err)
+	}
 
 	// Set header
-	headers := []string{"ID", "Region", "Regency", "Cluster", "Tools Name", "Quantity", "Notes", "Photos Count", "Created At"}
+	headerStyle := getHeaderStyle(f)
+	headerRow := make([]interface{}, len(headers))
 	for i, header := range headers {
-		cell := fmt.Sprintf("%c1", 'A'+i)
-		f.SetCellValue(sheetName, cell, header)
-		f.SetCellStyle(sheetName, cell, cell, getHeaderStyle(f))
+		headerRow[i] = excelize.Cell{StyleID: headerStyle, Value: header}
+	}
+	if err := sw.SetRow("A1", headerRow); err != nil {
+		return nil, fmt.Errorf("failed to write Excel header: %w", err)
 	}
 
 	// Set data
-	for i, item := range items {
-		row := i + 2
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), item.ID)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), string(item.Region))
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), item.Regency)
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), item.Cluster)
-		f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), item.ToolsName)
-		f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), item.Quantity)
-		notes := ""
-		if item.Notes.Valid {
-			notes = item.Notes.String
+	row := 2
+	err = forEachRow(next, func(item T) error {
+		if err := sw.SetRow(fmt.Sprintf("A%d", row), toRow(item)); err != nil {
+			return fmt.Errorf("failed to write Excel row: %w", err)
 		}
-		f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), notes)
-		// Parse documentation JSONB
-		var docs []string
-		if len(item.Documentation) > 0 {
-			json.Unmarshal(item.Documentation, &docs)
-		}
-		f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), len(docs))
-		createdAt := ""
-		if item.CreatedAt.Valid {
-			createdAt = item.CreatedAt.Time.Format("2006-01-02 15:04:05")
-		}
-		f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), createdAt)
+		row++
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	// Auto-fit columns
-	for i := 0; i < len(headers); i++ {
-		col := string(rune('A' + i))
-		f.SetColWidth(sheetName, col, col, 15)
+	if err := sw.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to flush Excel rows: %w", err)
 	}
 
 	var buf bytes.Buffer
@@ -282,6 +496,39 @@ func ExportToolsAlkerToExcel(items []sqlcdb.ListToolsAlkersForExportRow, logger
 	return &buf, nil
 }
 
+// writeCSVStream writes a header line and then one line per row to w, flushing after every
+// batch so the output reaches the client while later batches are still being read. Nothing
+// is written to w before the first batch has been read.
+func writeCSVStream[T any](w io.Writer, headers []string, next BatchReader[T], toRow func(T) []interface{}) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(headers); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	record := make([]string, len(headers))
+	for {
+		batch, err := next()
+		if err != nil {
+			return fmt.Errorf("failed to read export rows: %w", err)
+		}
+		for _, item := range batch {
+			for i, value := range toRow(item) {
+				record[i] = fmt.Sprint(value)
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV rows: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+	}
+}
+
 // getHeaderStyle returns a style for Excel header cells
 func getHeaderStyle(f *excelize.File) int {
 	styleID, _ := f.NewStyle(&excelize.Style{
@@ -300,4 +547,3 @@ func getHeaderStyle(f *excelize.File) int {
 	})
 	return styleID
 }
-
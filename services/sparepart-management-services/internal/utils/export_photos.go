@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+	"go.uber.org/zap"
+)
+
+const (
+	// pdfPhotoSize is the side in mm of the box a photo is fitted in on the photos appendix
+	pdfPhotoSize = 40.0
+	// pdfPhotoGap is the space in mm between photos and below every row of photos
+	pdfPhotoGap = 4.0
+	// pdfPhotoLimit caps the photos embedded in one PDF so a large export stays a sane size;
+	// the photos past it are only counted
+	pdfPhotoLimit = 300
+)
+
+// photoSection is an item listed in the photos appendix of a PDF export
+type photoSection struct {
+	title string
+	paths []string
+}
+
+// documentationPaths returns the photo paths of a documentation JSONB array
+func documentationPaths(documentation []byte) []string {
+	return PhotoPaths(ParsePhotos(documentation))
+}
+
+// ThumbnailLoader returns the JPEG thumbnail of a stored photo for the photos appendix of a
+// PDF export
+type ThumbnailLoader interface {
+	LoadThumbnail(ctx context.Context, filePath string) ([]byte, error)
+}
+
+// writePhotoAppendix adds a "Photos" section listing the thumbnails of every item in sections,
+// starting on a new page. Photos that cannot be loaded are drawn as an empty box marked
+// "Missing" and logged, so one missing file does not fail the export.
+func writePhotoAppendix(ctx context.Context, pdf *gofpdf.Fpdf, sections []photoSection, photos ThumbnailLoader, logger *zap.Logger) {
+	logger = RequestLogger(ctx, logger)
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(40, 10, "Photos")
+	pdf.Ln(12)
+	if len(sections) == 0 {
+		pdf.SetFont("Arial", "", 9)
+		pdf.Cell(40, 6, "No photos")
+		return
+	}
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	_, bottom := pdf.GetAutoPageBreak()
+	perRow := max(1, int((pageWidth-left-right+pdfPhotoGap)/(pdfPhotoSize+pdfPhotoGap)))
+
+	// ensureSpace starts a new page unless height mm still fit above the bottom margin
+	ensureSpace := func(height float64) {
+		if pdf.GetY()+height > pageHeight-bottom {
+			pdf.AddPage()
+		}
+	}
+
+	embedded, skipped := 0, 0
+	for _, section := range sections {
+		if embedded == pdfPhotoLimit {
+			skipped += len(section.paths)
+			continue
+		}
+		ensureSpace(7 + pdfPhotoSize)
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(0, 6, section.title, "", 1, "L", false, 0, "")
+		pdf.Ln(1)
+
+		pdf.SetFont("Arial", "", 7)
+		for i, path := range section.paths {
+			if embedded == pdfPhotoLimit {
+				skipped += len(section.paths) - i
+				break
+			}
+			column := i % perRow
+			if column == 0 && i > 0 {
+				pdf.SetY(pdf.GetY() + pdfPhotoSize + pdfPhotoGap)
+			}
+			if column == 0 {
+				ensureSpace(pdfPhotoSize)
+			}
+			x, y := left+float64(column)*(pdfPhotoSize+pdfPhotoGap), pdf.GetY()
+
+			thumb, err := photos.LoadThumbnail(ctx, path)
+			if err != nil {
+				if logger != nil {
+					logger.Warn("Failed to load photo for PDF export", zap.Error(err), zap.String("path", path))
+				}
+				pdf.Rect(x, y, pdfPhotoSize, pdfPhotoSize, "D")
+				pdf.SetXY(x, y+pdfPhotoSize/2-3)
+				pdf.CellFormat(pdfPhotoSize, 6, "Missing", "", 0, "C", false, 0, "")
+				pdf.SetY(y)
+				continue
+			}
+			embedded++
+
+			// Fitted in the box keeping its aspect ratio
+			options := gofpdf.ImageOptions{ImageType: "JPG"}
+			info := pdf.RegisterImageOptionsReader(path, options, bytes.NewReader(thumb))
+			width, height := pdfPhotoSize, pdfPhotoSize
+			if info.Width() > info.Height() {
+				height = pdfPhotoSize * info.Height() / info.Width()
+			} else {
+				width = pdfPhotoSize * info.Width() / info.Height()
+			}
+			pdf.ImageOptions(path, x+(pdfPhotoSize-width)/2, y+(pdfPhotoSize-height)/2, width, height, false, options, 0, "")
+			pdf.SetY(y)
+		}
+		pdf.SetY(pdf.GetY() + pdfPhotoSize + pdfPhotoGap)
+	}
+
+	if skipped > 0 {
+		ensureSpace(6)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.CellFormat(0, 6, fmt.Sprintf("%d more photo(s) not shown; at most %d photos are embedded per export", skipped, pdfPhotoLimit), "", 1, "L", false, 0, "")
+	}
+}
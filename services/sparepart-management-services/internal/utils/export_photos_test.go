@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+)
+
+// memoryThumbnails is a ThumbnailLoader of the thumbnails it holds
+type memoryThumbnails map[string][]byte
+
+func (m memoryThumbnails) LoadThumbnail(ctx context.Context, filePath string) ([]byte, error) {
+	thumb, ok := m[filePath]
+	if !ok {
+		return nil, errors.New("file does not exist")
+	}
+	return thumb, nil
+}
+
+func TestExportSparepartStockToPDFIncludePhotos(t *testing.T) {
+	ctx := context.Background()
+
+	var thumb bytes.Buffer
+	if err := jpeg.Encode(&thumb, image.NewNRGBA(image.Rect(0, 0, 320, 240)), nil); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	thumbnails := memoryThumbnails{"/uploads/sparepart/new_stock/a.png": thumb.Bytes()}
+
+	items := []sqlcdb.ListSparepartStocksForExportRow{
+		{ID: 1, Regency: "Ambon", Cluster: "Nusaniwe", SparepartName: "Baterai", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 2,
+			Documentation: []byte(`["/uploads/sparepart/new_stock/a.png","/uploads/sparepart/new_stock/missing.png"]`)},
+		{ID: 2, Regency: "Ambon", Cluster: "Nusaniwe", SparepartName: "Inverter", StockType: sqlcdb.StockTypeNEWSTOCK, Quantity: 1},
+	}
+	export := func(photos ThumbnailLoader) []byte {
+		done := false
+		next := func() ([]sqlcdb.ListSparepartStocksForExportRow, error) {
+			if done {
+				return nil, nil
+			}
+			done = true
+			return items, nil
+		}
+		buf, err := ExportSparepartStockToPDF(ctx, next, photos, nil)
+		if err != nil {
+			t.Fatalf("ExportSparepartStockToPDF failed: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	if images := bytes.Count(export(nil), []byte("/Subtype /Image")); images != 0 {
+		t.Fatalf("expected no embedded images without include_photos, got %d", images)
+	}
+	// The missing photo is drawn as a placeholder instead of failing the export
+	if images := bytes.Count(export(thumbnails), []byte("/Subtype /Image")); images != 1 {
+		t.Fatalf("expected 1 embedded image, got %d", images)
+	}
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TextFilter converts an optional query value to a nullable filter param (empty means no filter)
+func TextFilter(value string) pgtype.Text {
+	value = strings.TrimSpace(value)
+	return pgtype.Text{String: value, Valid: value != ""}
+}
+
+// IntFilter converts an optional ID to a nullable filter param (zero means no filter)
+func IntFilter(value int32) pgtype.Int4 {
+	return pgtype.Int4{Int32: value, Valid: value != 0}
+}
+
+// ListFilter splits a comma-separated query value into a filter list (nil means no filter)
+func ListFilter(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// OptionalInt converts an optional request field to a nullable param (nil means not provided)
+func OptionalInt(value *int) pgtype.Int4 {
+	if value == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*value), Valid: true}
+}
+
+// OptionalText converts an optional request field to a nullable param (nil means not provided)
+func OptionalText(value *string) pgtype.Text {
+	if value == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *value, Valid: true}
+}
+
+// OptionalFloat converts an optional request field to a nullable param (nil means not provided)
+func OptionalFloat(value *float64) pgtype.Float8 {
+	if value == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *value, Valid: true}
+}
+
+// OptionalNumeric converts an optional amount to a nullable NUMERIC param rounded to cents
+// (nil means not provided)
+func OptionalNumeric(value *float64) pgtype.Numeric {
+	if value == nil {
+		return pgtype.Numeric{}
+	}
+	return pgtype.Numeric{Int: big.NewInt(int64(math.Round(*value * 100))), Exp: -2, Valid: true}
+}
+
+// NumericValue converts a nullable NUMERIC column to an optional response field
+func NumericValue(value pgtype.Numeric) *float64 {
+	f, err := value.Float64Value()
+	if err != nil || !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
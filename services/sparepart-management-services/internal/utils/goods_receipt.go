@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+
+	"github.com/jung-kurt/gofpdf"
+	"go.uber.org/zap"
+)
+
+// GoodsReceiptCode returns the number printed on a goods receipt
+func GoodsReceiptCode(id int32) string {
+	return fmt.Sprintf("GR-%06d", id)
+}
+
+// PurchaseOrderCode returns the number of a purchase order, as given to the supplier
+func PurchaseOrderCode(id int32) string {
+	return fmt.Sprintf("PO-%06d", id)
+}
+
+// ExportGoodsReceiptToPDF renders a goods receipt for printing: the shipment details, its
+// items and lines to sign for delivery and receipt
+func ExportGoodsReceiptToPDF(receipt sqlcdb.GetGoodsReceiptRow, items []sqlcdb.ListGoodsReceiptItemsRow, logger *zap.Logger) (*bytes.Buffer, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, "Goods Receipt "+GoodsReceiptCode(receipt.ID))
+	pdf.Ln(12)
+
+	location := fmt.Sprintf("Location #%d", receipt.LocationID)
+	if receipt.Cluster.Valid {
+		location = fmt.Sprintf("%s - %s - %s", receipt.Region.RegionType, receipt.Regency.String, receipt.Cluster.String)
+	}
+	details := [][2]string{
+		{"Supplier", receipt.SupplierName},
+		{"DO Number", receipt.DeliveryOrderNumber},
+	}
+	if receipt.PurchaseOrderID.Valid {
+		details = append(details, [2]string{"PO Number", PurchaseOrderCode(receipt.PurchaseOrderID.Int32)})
+	}
+	details = append(details, [][2]string{
+		{"Location", location},
+		{"Status", receipt.Status},
+		{"Registered", FormatTimestamp(receipt.CreatedAt) + byline(receipt.CreatedBy.String)},
+	}...)
+	if receipt.ConfirmedAt.Valid {
+		details = append(details, [2]string{"Confirmed", FormatTimestamp(receipt.ConfirmedAt) + byline(receipt.ConfirmedBy.String)})
+	}
+	if receipt.Notes.Valid {
+		details = append(details, [2]string{"Notes", truncateText(receipt.Notes.String, 100)})
+	}
+	for _, detail := range details {
+		pdf.SetFont("Arial", "B", 10)
+		pdf.Cell(30, 6, detail[0])
+		pdf.SetFont("Arial", "", 10)
+		pdf.Cell(150, 6, detail[1])
+		pdf.Ln(6)
+	}
+	pdf.Ln(4)
+
+	// Table header
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(200, 200, 200)
+	headers := []string{"No", "Sparepart", "Stock Type", "Quantity"}
+	colWidths := []float64{12, 103, 40, 35}
+	for i, header := range headers {
+		pdf.CellFormat(colWidths[i], 7, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	// Table data
+	pdf.SetFont("Arial", "", 9)
+	var total int64
+	for i, item := range items {
+		name := fmt.Sprintf("Sparepart #%d", item.SparepartID)
+		if item.SparepartName.Valid {
+			name = item.SparepartName.String
+		}
+		pdf.CellFormat(colWidths[0], 7, strconv.Itoa(i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[1], 7, truncateText(name, 60), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colWidths[2], 7, string(item.StockType), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[3], 7, strconv.Itoa(int(item.Quantity)), "1", 0, "C", false, 0, "")
+		pdf.Ln(-1)
+		total += int64(item.Quantity)
+	}
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(colWidths[0]+colWidths[1]+colWidths[2], 7, "Total", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(colWidths[3], 7, strconv.FormatInt(total, 10), "1", 0, "C", false, 0, "")
+	pdf.Ln(20)
+
+	// Signatures
+	pdf.SetFont("Arial", "", 10)
+	pdf.CellFormat(95, 6, "Delivered by", "", 0, "C", false, 0, "")
+	pdf.CellFormat(95, 6, "Received by", "", 0, "C", false, 0, "")
+	pdf.Ln(25)
+	pdf.CellFormat(95, 6, "(______________________)", "", 0, "C", false, 0, "")
+	pdf.CellFormat(95, 6, "(______________________)", "", 0, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		if logger != nil {
+			logger.Error("Failed to generate PDF", zap.Error(err))
+		}
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	return &buf, nil
+}
+
+// byline appends who did something to its timestamp, when known
+func byline(user string) string {
+	if user == "" {
+		return ""
+	}
+	return " by " + user
+}
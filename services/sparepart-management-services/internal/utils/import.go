@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ReadSpreadsheet reads every row of an uploaded .csv or .xlsx file (the first sheet of a
+// workbook), header row included. CSV files may be comma or semicolon separated, as saved
+// by spreadsheet programs under different locales.
+func ReadSpreadsheet(r io.Reader, filename string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return readCSV(r)
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open workbook: %w", err)
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("workbook has no sheets")
+		}
+		return f.GetRows(sheets[0])
+	default:
+		return nil, fmt.Errorf("unsupported file type %q, expected .csv or .xlsx", filepath.Ext(filename))
+	}
+}
+
+// ImportColumns maps the header row of an import file to column positions. Headers are
+// matched case-insensitively, with spaces read as underscores; the first of repeated headers wins.
+func ImportColumns(header []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	return columns
+}
+
+// ImportErrorsToExcel returns the imported rows as a workbook with an Errors column after
+// the original ones, so the file can be corrected and uploaded again. Errors are matched to
+// rows by their rows[<line>].<column> field; errors about the whole file are left out.
+func ImportErrorsToExcel(records [][]string, errs []FieldError) (*bytes.Buffer, error) {
+	byLine := make(map[int][]string)
+	for _, e := range errs {
+		var line int
+		if _, err := fmt.Sscanf(e.Field, "rows[%d]", &line); err != nil {
+			continue
+		}
+		message := e.Message
+		if _, column, ok := strings.Cut(e.Field, "]."); ok {
+			message = column + " " + message
+		}
+		byLine[line] = append(byLine[line], message)
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const sheet = "Errors"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	var width int
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+	headerStyle := getHeaderStyle(f)
+	errorStyle, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "#C00000"}})
+
+	for i, record := range records {
+		line := i + 1
+		row := make([]interface{}, width+1)
+		for j, value := range record {
+			row[j] = value
+		}
+		if line == 1 {
+			row[width] = "Errors"
+		} else {
+			row[width] = strings.Join(byLine[line], "; ")
+		}
+
+		cell, _ := excelize.CoordinatesToCellName(1, line)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return nil, fmt.Errorf("failed to write Excel row: %w", err)
+		}
+	}
+
+	errorColumn, _ := excelize.ColumnNumberToName(width + 1)
+	_ = f.SetCellStyle(sheet, "A1", errorColumn+"1", headerStyle)
+	if len(records) > 1 {
+		_ = f.SetCellStyle(sheet, errorColumn+"2", fmt.Sprintf("%s%d", errorColumn, len(records)), errorStyle)
+	}
+	_ = f.SetColWidth(sheet, "A", errorColumn, 15)
+	_ = f.SetColWidth(sheet, errorColumn, errorColumn, 60)
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write Excel file: %w", err)
+	}
+	return &buf, nil
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+
+	// Spreadsheet programs often start UTF-8 CSV files with a byte order mark
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		_, _ = br.Discard(3)
+	}
+
+	// The header line decides the separator
+	reader := csv.NewReader(br)
+	if header, err := br.Peek(br.Buffered()); err == nil {
+		if line, _, _ := bytes.Cut(header, []byte("\n")); bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+			reader.Comma = ';'
+		}
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	return rows, nil
+}
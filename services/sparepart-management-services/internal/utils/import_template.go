@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ImportTemplate describes the file an import endpoint expects: its columns in order and an
+// example row
+type ImportTemplate struct {
+	Sheet   string
+	Columns []ImportTemplateColumn
+	Example []string
+	// Rows is the number of rows below the header that get the column validations
+	Rows int
+}
+
+// ImportTemplateColumn is one template column. Options become a dropdown in the workbook,
+// Whole restricts the column to non-negative whole numbers, and Note is shown as the cell
+// prompt while filling in the column.
+type ImportTemplateColumn struct {
+	Name    string
+	Options []string
+	Whole   bool
+	Note    string
+}
+
+// Excel renders the template as a workbook with a styled, frozen header row, the example row
+// and the column validations
+func (t ImportTemplate) Excel() (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", t.Sheet); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	header := make([]interface{}, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = column.Name
+	}
+	example := make([]interface{}, len(t.Example))
+	for i, value := range t.Example {
+		example[i] = value
+	}
+	if err := f.SetSheetRow(t.Sheet, "A1", &header); err != nil {
+		return nil, fmt.Errorf("failed to write Excel header: %w", err)
+	}
+	if err := f.SetSheetRow(t.Sheet, "A2", &example); err != nil {
+		return nil, fmt.Errorf("failed to write Excel row: %w", err)
+	}
+
+	last, _ := excelize.ColumnNumberToName(len(t.Columns))
+	if err := f.SetCellStyle(t.Sheet, "A1", last+"1", getHeaderStyle(f)); err != nil {
+		return nil, fmt.Errorf("failed to style Excel header: %w", err)
+	}
+	if err := f.SetColWidth(t.Sheet, "A", last, 20); err != nil {
+		return nil, fmt.Errorf("failed to set column width: %w", err)
+	}
+	if err := f.SetPanes(t.Sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return nil, fmt.Errorf("failed to freeze header row: %w", err)
+	}
+
+	for i, column := range t.Columns {
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		dv := excelize.NewDataValidation(true)
+		dv.SetSqref(fmt.Sprintf("%s2:%s%d", name, name, t.Rows+1))
+		switch {
+		case len(column.Options) > 0:
+			if err := dv.SetDropList(column.Options); err != nil {
+				return nil, fmt.Errorf("failed to set %s options: %w", column.Name, err)
+			}
+			dv.SetError(excelize.DataValidationErrorStyleStop, column.Name, "Choose one of the listed values")
+		case column.Whole:
+			if err := dv.SetRange(0, math.MaxInt32, excelize.DataValidationTypeWhole, excelize.DataValidationOperatorBetween); err != nil {
+				return nil, fmt.Errorf("failed to set %s range: %w", column.Name, err)
+			}
+			dv.SetError(excelize.DataValidationErrorStyleStop, column.Name, "Enter a whole number of 0 or more")
+		case column.Note == "":
+			continue
+		}
+		if column.Note != "" {
+			dv.SetInput(column.Name, column.Note)
+		}
+		if err := f.AddDataValidation(t.Sheet, dv); err != nil {
+			return nil, fmt.Errorf("failed to add %s validation: %w", column.Name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write Excel file: %w", err)
+	}
+	return &buf, nil
+}
+
+// WriteCSV writes the template header and example row as CSV
+func (t ImportTemplate) WriteCSV(w io.Writer) error {
+	header := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = column.Name
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll([][]string{header, t.Example}); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
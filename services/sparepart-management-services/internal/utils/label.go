@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+)
+
+// Label sheet layout for standard A4 label paper (3 x 8 = 24 labels, 70 x 36 mm)
+const (
+	labelColumns      = 3
+	labelRows         = 8
+	labelWidth        = 70.0
+	labelHeight       = 36.0
+	labelTopMargin    = 4.5
+	labelPadding      = 2.5
+	labelQRSize       = 31.0
+	labelQRResolution = 256
+)
+
+// StockItemCode returns the code printed on labels and encoded in QR codes for a stock item
+func StockItemCode(id int32) string {
+	return fmt.Sprintf("STK-%06d", id)
+}
+
+// ParseStockItemCode reads the stock item ID back from a code made by StockItemCode. The
+// prefix is matched regardless of case and the zero padding is optional, so typed codes resolve too.
+func ParseStockItemCode(code string) (int32, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	digits, ok := strings.CutPrefix(code, "STK-")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(digits, 10, 32)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return int32(id), true
+}
+
+// StockItemQRCode renders the QR code of a stock item's code as a PNG image of size x size pixels
+func StockItemQRCode(id int32, size int) ([]byte, error) {
+	return qrcode.Encode(StockItemCode(id), qrcode.Medium, size)
+}
+
+// ExportStockLabelsToPDF renders an A4 sheet of QR labels for sparepart stock items
+func ExportStockLabelsToPDF(items []sqlcdb.ListSparepartStocksForLabelsRow, logger *zap.Logger) (*bytes.Buffer, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "") // Portrait, mm, A4
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+
+	perPage := labelColumns * labelRows
+	for i, item := range items {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+
+		slot := i % perPage
+		x := float64(slot%labelColumns) * labelWidth
+		y := labelTopMargin + float64(slot/labelColumns)*labelHeight
+
+		code := StockItemCode(item.ID)
+		png, err := qrcode.Encode(code, qrcode.Medium, labelQRResolution)
+		if err != nil {
+			if logger != nil {
+				logger.Error("Failed to generate QR code", zap.Error(err), zap.String("code", code))
+			}
+			return nil, fmt.Errorf("failed to generate QR code for %s: %w", code, err)
+		}
+
+		imageOptions := gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(code, imageOptions, bytes.NewReader(png))
+		pdf.ImageOptions(code, x+labelPadding, y+(labelHeight-labelQRSize)/2, labelQRSize, labelQRSize, false, imageOptions, 0, "")
+
+		// Text block on the right of the QR code
+		textX := x + labelPadding + labelQRSize + labelPadding
+		textWidth := labelWidth - (textX - x) - labelPadding
+
+		pdf.SetXY(textX, y+labelPadding+2)
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(textWidth, 5, fitText(pdf, item.SparepartName, textWidth), "", 2, "L", false, 0, "")
+
+		pdf.SetFont("Arial", "", 7)
+		pdf.CellFormat(textWidth, 4, fitText(pdf, item.Regency, textWidth), "", 2, "L", false, 0, "")
+		pdf.CellFormat(textWidth, 4, fitText(pdf, item.Cluster, textWidth), "", 2, "L", false, 0, "")
+		pdf.CellFormat(textWidth, 4, fitText(pdf, string(item.StockType), textWidth), "", 2, "L", false, 0, "")
+
+		pdf.SetFont("Courier", "B", 9)
+		pdf.CellFormat(textWidth, 6, code, "", 2, "L", false, 0, "")
+	}
+
+	if err := pdf.Error(); err != nil {
+		if logger != nil {
+			logger.Error("Failed to render label sheet", zap.Error(err))
+		}
+		return nil, fmt.Errorf("failed to render label sheet: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		if logger != nil {
+			logger.Error("Failed to generate PDF", zap.Error(err))
+		}
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	return &buf, nil
+}
+
+// fitText truncates text with an ellipsis so it fits within the given width using the current font
+func fitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := string(runes) + "..."
+		if pdf.GetStringWidth(candidate) <= width {
+			return candidate
+		}
+	}
+	return ""
+}
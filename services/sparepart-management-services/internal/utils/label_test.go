@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestParseStockItemCode(t *testing.T) {
+	tests := []struct {
+		code string
+		id   int32
+		ok   bool
+	}{
+		{StockItemCode(42), 42, true},
+		{" stk-7 ", 7, true},
+		{"STK-0", 0, false},
+		{"STK-abc", 0, false},
+		{"42", 0, false},
+		{"BMS-001", 0, false},
+	}
+	for _, tt := range tests {
+		id, ok := ParseStockItemCode(tt.code)
+		if id != tt.id || ok != tt.ok {
+			t.Errorf("ParseStockItemCode(%q) = %d, %v; want %d, %v", tt.code, id, ok, tt.id, tt.ok)
+		}
+	}
+}
@@ -5,9 +5,8 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-var Logger *zap.Logger
-
-func InitLogger(level string) error {
+// NewLogger builds the JSON production logger at the given level
+func NewLogger(level string) (*zap.Logger, error) {
 	var zapLevel zapcore.Level
 	switch level {
 	case "debug":
@@ -31,19 +30,6 @@ func InitLogger(level string) error {
 	config.EncoderConfig.MessageKey = "message"
 	config.EncoderConfig.LevelKey = "level"
 
-	var err error
-	Logger, err = config.Build()
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func GetLogger() *zap.Logger {
-	if Logger == nil {
-		_ = InitLogger("info")
-	}
-	return Logger
+	return config.Build()
 }
 
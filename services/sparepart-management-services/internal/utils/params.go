@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPageLimit = 10
+	// MaxPageLimit caps the limit query param; larger values are clamped rather than rejected
+	MaxPageLimit = 100
+)
+
+// Pagination is a validated page/limit pair from the query string
+type Pagination struct {
+	Page  int
+	Limit int
+}
+
+// Offset returns the row offset of the page; ParsePagination guarantees it fits in an int32
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// ParsePagination reads the page and limit query params. Values that are not positive
+// integers are returned as field errors, a limit above MaxPageLimit is clamped.
+func ParsePagination(c *gin.Context) (Pagination, []FieldError) {
+	p := Pagination{Page: 1, Limit: DefaultPageLimit}
+	var errs []FieldError
+
+	if value := strings.TrimSpace(c.Query("page")); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			errs = append(errs, FieldError{Field: "page", Message: "must be a positive integer"})
+		} else {
+			p.Page = page
+		}
+	}
+
+	if value := strings.TrimSpace(c.Query("limit")); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			errs = append(errs, FieldError{Field: "limit", Message: "must be a positive integer"})
+		} else {
+			p.Limit = min(limit, MaxPageLimit)
+		}
+	}
+
+	if len(errs) == 0 && int64(p.Page-1)*int64(p.Limit) > math.MaxInt32 {
+		errs = append(errs, FieldError{Field: "page", Message: "is too large"})
+	}
+
+	return p, errs
+}
+
+// ParseGroupBy reads the group_by query param of the stock and tools alker listings: true
+// for "none" (one entry per item), false for "location" (the default, items grouped per
+// location); anything else is returned as a field error
+func ParseGroupBy(c *gin.Context) (bool, []FieldError) {
+	switch strings.TrimSpace(c.Query("group_by")) {
+	case "", "location":
+		return false, nil
+	case "none":
+		return true, nil
+	}
+	return false, []FieldError{{Field: "group_by", Message: "must be one of none, location"}}
+}
+
+// ParseInclude reads the include query param, a comma-separated list of related data to add
+// to the response; values outside allowed are returned as a field error
+func ParseInclude(c *gin.Context, allowed ...string) (map[string]bool, []FieldError) {
+	include := map[string]bool{}
+	for _, value := range ListFilter(c.Query("include")) {
+		if !slices.Contains(allowed, value) {
+			return nil, []FieldError{{Field: "include", Message: "must be one of " + strings.Join(allowed, ", ")}}
+		}
+		include[value] = true
+	}
+	return include, nil
+}
+
+// ParseIndexParam parses a zero-based index path param such as photo_index.
+// The upper bound depends on the loaded record, so callers still check it against its length.
+func ParseIndexParam(c *gin.Context, name string) (int, bool) {
+	index, err := strconv.Atoi(c.Param(name))
+	if err != nil || index < 0 {
+		return 0, false
+	}
+	return index, true
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxPhotoCaptionLength caps the caption of a photo, in characters
+const MaxPhotoCaptionLength = 500
+
+// Photo is a stored photo of a photos JSONB array (item documentation, damage report, disposal
+// and packing list photos) with its metadata
+type Photo struct {
+	URL     string `json:"url"`
+	Caption string `json:"caption,omitempty"`
+	// When the photo was taken, as given by the uploader; unknown when nil
+	TakenAt *time.Time `json:"taken_at,omitempty"`
+	// User that uploaded the photo (see UserID)
+	UploadedBy string `json:"uploaded_by,omitempty"`
+}
+
+// UnmarshalJSON also reads the plain path that photos were stored as before they had metadata
+func (p *Photo) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*p = Photo{URL: path}
+		return nil
+	}
+
+	type photo Photo
+	var decoded photo
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*p = Photo(decoded)
+	return nil
+}
+
+// ParsePhotos returns the photos of a photos JSONB array; an empty or unreadable array has none
+func ParsePhotos(data []byte) []Photo {
+	if len(data) == 0 {
+		return []Photo{}
+	}
+	var photos []Photo
+	if err := json.Unmarshal(data, &photos); err != nil || photos == nil {
+		return []Photo{}
+	}
+	return photos
+}
+
+// PhotosJSON encodes photos for a photos JSONB column
+func PhotosJSON(photos []Photo) []byte {
+	if len(photos) == 0 {
+		return []byte("[]")
+	}
+	data, _ := json.Marshal(photos)
+	return data
+}
+
+// PhotoPaths returns the upload paths of photos
+func PhotoPaths(photos []Photo) []string {
+	paths := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		paths = append(paths, photo.URL)
+	}
+	return paths
+}
+
+// PhotoMetadata is the caption and taken-at timestamp sent with an uploaded photo
+type PhotoMetadata struct {
+	Caption string
+	TakenAt *time.Time
+}
+
+// ParsePhotoMetadata reads the captions and taken_at form values of count uploaded photos;
+// the n-th value of each field belongs to the n-th photo and either can be left out or empty
+func ParsePhotoMetadata(c *gin.Context, count int) ([]PhotoMetadata, []FieldError) {
+	captions := c.PostFormArray("captions")
+	takenAt := c.PostFormArray("taken_at")
+
+	var errs []FieldError
+	if len(captions) > count {
+		errs = append(errs, FieldError{Field: "captions", Message: fmt.Sprintf("must not have more values than photos (%d)", count)})
+	}
+	if len(takenAt) > count {
+		errs = append(errs, FieldError{Field: "taken_at", Message: fmt.Sprintf("must not have more values than photos (%d)", count)})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	metadata := make([]PhotoMetadata, count)
+	for i := range metadata {
+		var caption, taken string
+		if i < len(captions) {
+			caption = captions[i]
+		}
+		if i < len(takenAt) {
+			taken = takenAt[i]
+		}
+		var fieldErrs []FieldError
+		metadata[i], fieldErrs = parsePhotoMetadata(caption, taken, fmt.Sprintf("captions[%d]", i), fmt.Sprintf("taken_at[%d]", i))
+		errs = append(errs, fieldErrs...)
+	}
+	return metadata, errs
+}
+
+// ParseSinglePhotoMetadata reads the caption and taken_at form values of a single uploaded photo
+func ParseSinglePhotoMetadata(c *gin.Context) (PhotoMetadata, []FieldError) {
+	return parsePhotoMetadata(c.PostForm("caption"), c.PostForm("taken_at"), "caption", "taken_at")
+}
+
+func parsePhotoMetadata(caption, takenAt, captionField, takenAtField string) (PhotoMetadata, []FieldError) {
+	var metadata PhotoMetadata
+	var errs []FieldError
+
+	metadata.Caption = strings.TrimSpace(caption)
+	if err := ValidatePhotoCaption(metadata.Caption); err != "" {
+		errs = append(errs, FieldError{Field: captionField, Message: err})
+	}
+
+	if takenAt = strings.TrimSpace(takenAt); takenAt != "" {
+		parsed, err := ParsePhotoTakenAt(takenAt)
+		if err != "" {
+			errs = append(errs, FieldError{Field: takenAtField, Message: err})
+		} else {
+			metadata.TakenAt = &parsed
+		}
+	}
+	return metadata, errs
+}
+
+// ValidatePhotoCaption returns why a caption is invalid, or an empty string
+func ValidatePhotoCaption(caption string) string {
+	if len([]rune(caption)) > MaxPhotoCaptionLength {
+		return fmt.Sprintf("must be at most %d characters", MaxPhotoCaptionLength)
+	}
+	return ""
+}
+
+// ParsePhotoTakenAt parses a taken-at timestamp (RFC3339), returning why it is invalid instead
+// when it cannot be used
+func ParsePhotoTakenAt(value string) (time.Time, string) {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, "must be an RFC3339 timestamp, e.g. 2024-05-01T08:30:00+09:00"
+	}
+	// Allow for a camera clock running a little ahead
+	if parsed.After(time.Now().Add(24 * time.Hour)) {
+		return time.Time{}, "must not be in the future"
+	}
+	return parsed.UTC(), ""
+}
+
+// NewPhoto returns the photo stored at path with the metadata sent with its upload
+func NewPhoto(c *gin.Context, path string, metadata PhotoMetadata) Photo {
+	return Photo{
+		URL:        path,
+		Caption:    metadata.Caption,
+		TakenAt:    metadata.TakenAt,
+		UploadedBy: UserID(c),
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePhotosReadsPathsAndObjects(t *testing.T) {
+	photos := ParsePhotos([]byte(`["/uploads/a.jpg",{"url":"/uploads/b.jpg","caption":"Rectifier","taken_at":"2024-05-01T08:30:00Z","uploaded_by":"budi"}]`))
+	if len(photos) != 2 {
+		t.Fatalf("expected 2 photos, got %d", len(photos))
+	}
+	if photos[0] != (Photo{URL: "/uploads/a.jpg"}) {
+		t.Errorf("expected a photo with only the path, got %+v", photos[0])
+	}
+	b := photos[1]
+	if b.URL != "/uploads/b.jpg" || b.Caption != "Rectifier" || b.UploadedBy != "budi" {
+		t.Errorf("unexpected photo %+v", b)
+	}
+	if b.TakenAt == nil || !b.TakenAt.Equal(time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected taken_at %v", b.TakenAt)
+	}
+
+	for _, data := range []string{"", "null", "{}"} {
+		if photos := ParsePhotos([]byte(data)); photos == nil || len(photos) != 0 {
+			t.Errorf("ParsePhotos(%q) = %v; want no photos", data, photos)
+		}
+	}
+}
+
+func TestPhotosJSON(t *testing.T) {
+	if got := string(PhotosJSON(nil)); got != "[]" {
+		t.Errorf("PhotosJSON(nil) = %s; want []", got)
+	}
+	got := string(PhotosJSON([]Photo{{URL: "/uploads/a.jpg"}, {URL: "/uploads/b.jpg", Caption: "Panel"}}))
+	want := `[{"url":"/uploads/a.jpg"},{"url":"/uploads/b.jpg","caption":"Panel"}]`
+	if got != want {
+		t.Errorf("PhotosJSON = %s; want %s", got, want)
+	}
+}
+
+func TestParsePhotoTakenAt(t *testing.T) {
+	if _, msg := ParsePhotoTakenAt("2024-05-01T08:30:00+09:00"); msg != "" {
+		t.Errorf("expected a valid timestamp, got %q", msg)
+	}
+	for _, value := range []string{"2024-05-01", "yesterday", time.Now().Add(72 * time.Hour).Format(time.RFC3339)} {
+		if _, msg := ParsePhotoTakenAt(value); msg == "" {
+			t.Errorf("ParsePhotoTakenAt(%q) accepted", value)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader carries the ID correlating a request with its log lines; it is echoed in
+// every response (see middleware.RequestLogger)
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns ctx carrying the request ID id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestLogger returns logger with the request ID carried by ctx attached, so the lines
+// logged while handling a request can be found by the ID in its X-Request-ID header
+func RequestLogger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if logger == nil {
+		return nil
+	}
+	if id := RequestID(ctx); id != "" {
+		return logger.With(zap.String("request_id", id))
+	}
+	return logger
+}
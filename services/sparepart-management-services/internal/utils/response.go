@@ -1,17 +1,28 @@
 package utils
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 )
 
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Data    interface{}  `json:"data,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Code    string       `json:"code,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
 }
 
 type PaginationMeta struct {
@@ -60,6 +71,23 @@ func Error(c *gin.Context, message string, statusCode int) {
 }
 
 func HandleError(c *gin.Context, err error, message string, logger *zap.Logger) {
+	logger = RequestLogger(c.Request.Context(), logger)
+
+	// Constraint violations are client errors, not server failures
+	if status, resp, ok := dbErrorResponse(err); ok {
+		c.JSON(status, resp)
+		return
+	}
+
+	// The request budget set by the timeout middleware ran out while querying
+	if errors.Is(err, context.DeadlineExceeded) {
+		if logger != nil {
+			logger.Warn(message, zap.Error(err), zap.String("path", c.FullPath()))
+		}
+		RequestTimeout(c)
+		return
+	}
+
 	if logger != nil {
 		logger.Error(message, zap.Error(err))
 	}
@@ -81,11 +109,152 @@ func BadRequest(c *gin.Context, message string) {
 	Error(c, message, http.StatusBadRequest)
 }
 
+func Unauthorized(c *gin.Context, message string) {
+	Error(c, message, http.StatusUnauthorized)
+}
+
 func NotFound(c *gin.Context, message string) {
 	Error(c, message, http.StatusNotFound)
 }
 
+// RequestTimeout responds 504 when the request exceeded its time budget
+func RequestTimeout(c *gin.Context) {
+	c.JSON(http.StatusGatewayTimeout, Response{
+		Success: false,
+		Error:   "Request timed out",
+		Code:    ErrCodeTimeout,
+	})
+}
+
 func InternalServerError(c *gin.Context, message string) {
 	Error(c, message, http.StatusInternalServerError)
 }
 
+func init() {
+	// Validation errors name fields by their JSON name (location_id, items[0].quantity)
+	// instead of the Go struct path (CreateToolsAlkerRequest.LocationID)
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// BindingError responds to a request body that failed binding (c.ShouldBindJSON): a 400
+// with a field error per failed validation rule, or per field of the wrong JSON type, and
+// a plain 400 for a body that is not valid JSON
+func BindingError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, FieldError{
+				Field:   validationField(fieldErr),
+				Rule:    fieldErr.Tag(),
+				Message: validationMessage(fieldErr),
+			})
+		}
+		ValidationError(c, fields...)
+	case errors.As(err, &typeErr):
+		ValidationError(c, FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be " + jsonTypeName(typeErr.Type),
+		})
+	case errors.Is(err, io.EOF):
+		BadRequest(c, "Request body is required")
+	default:
+		BadRequest(c, "Invalid request body: "+err.Error())
+	}
+}
+
+// validationField is the JSON path of a failed field, without the request struct's name
+func validationField(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+// validationMessage describes a failed validation rule in the register of the handlers'
+// own field errors ("is required", "must be greater than or equal to 0")
+func validationMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	// Length rules count characters of strings and elements of lists
+	sized := fieldErr.Kind() == reflect.String || fieldErr.Kind() == reflect.Slice || fieldErr.Kind() == reflect.Map
+	unit := "items"
+	if fieldErr.Kind() == reflect.String {
+		unit = "characters"
+	}
+
+	switch fieldErr.Tag() {
+	case "required", "required_if", "required_with", "required_without":
+		return "is required"
+	case "min", "gte":
+		if sized {
+			return fmt.Sprintf("must have at least %s %s", param, unit)
+		}
+		return "must be greater than or equal to " + param
+	case "max", "lte":
+		if sized {
+			return fmt.Sprintf("must have at most %s %s", param, unit)
+		}
+		return "must be less than or equal to " + param
+	case "gt":
+		if sized {
+			return fmt.Sprintf("must have more than %s %s", param, unit)
+		}
+		return "must be greater than " + param
+	case "lt":
+		if sized {
+			return fmt.Sprintf("must have fewer than %s %s", param, unit)
+		}
+		return "must be less than " + param
+	case "len":
+		if sized {
+			return fmt.Sprintf("must have exactly %s %s", param, unit)
+		}
+		return "must be equal to " + param
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "startswith":
+		return "must start with " + param
+	case "unique":
+		return "must not contain duplicates"
+	default:
+		return "failed the " + fieldErr.Tag() + " rule"
+	}
+}
+
+// jsonTypeName names the JSON type expected for a Go type
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
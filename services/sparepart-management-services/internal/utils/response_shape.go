@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseShape trims a response to what the client asked for with the fields and expand
+// query params:
+//
+//   - fields lists the JSON fields to keep, comma-separated, with dots for nested fields
+//     (fields=id,location.cluster,sparepart.name); arrays are looked through, so
+//     sparepart.name keeps the name of every item of a sparepart list
+//   - expand lists the nested objects to include in full (expand=location); once expand is
+//     given, every other nested object that has an id (location, sparepart, tools) is
+//     reduced to {"id": ...}
+//
+// Without either param the response is sent as is.
+type ResponseShape struct {
+	fields pathTree
+	expand pathTree
+}
+
+// pathTree is a set of dotted JSON paths, one level per segment. A nil tree stands for
+// everything below it.
+type pathTree map[string]pathTree
+
+// ParseResponseShape reads the fields and expand query params; it returns nil when neither
+// was given. Malformed paths are returned as field errors.
+func ParseResponseShape(c *gin.Context) (*ResponseShape, []FieldError) {
+	fieldsValue, hasFields := c.GetQuery("fields")
+	expandValue, hasExpand := c.GetQuery("expand")
+	if !hasFields && !hasExpand {
+		return nil, nil
+	}
+
+	shape := &ResponseShape{}
+	var errs []FieldError
+	if hasFields {
+		fields, err := parsePathTree(fieldsValue)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "fields", Message: err.Error()})
+		} else if len(fields) == 0 {
+			errs = append(errs, FieldError{Field: "fields", Message: "must list at least one field"})
+		}
+		shape.fields = fields
+	}
+	if hasExpand {
+		expand, err := parsePathTree(expandValue)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "expand", Message: err.Error()})
+		}
+		// An empty expand is not nil, so it still collapses the nested objects
+		shape.expand = expand
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return shape, nil
+}
+
+// parsePathTree splits a comma-separated list of dotted paths; a path that is a prefix of
+// another one keeps everything below it
+func parsePathTree(value string) (pathTree, error) {
+	tree := pathTree{}
+	for _, path := range ListFilter(value) {
+		segments := strings.Split(path, ".")
+		for _, segment := range segments {
+			if !isPathSegment(segment) {
+				return nil, fmt.Errorf("invalid field %q", path)
+			}
+		}
+
+		node := tree
+		for i, segment := range segments {
+			sub, seen := node[segment]
+			if seen && sub == nil {
+				// A shorter path already keeps everything below
+				break
+			}
+			if i == len(segments)-1 {
+				node[segment] = nil
+				break
+			}
+			if !seen {
+				sub = pathTree{}
+				node[segment] = sub
+			}
+			node = sub
+		}
+	}
+	return tree, nil
+}
+
+func isPathSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, r := range segment {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns data trimmed to the shape; a nil shape returns data unchanged
+func (s *ResponseShape) Apply(data any) (any, error) {
+	if s == nil || data == nil {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	// Keep numbers as written instead of turning IDs into floats
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return shapeValue(value, s.fields, s.expand), nil
+}
+
+// shapeValue keeps the fields of value listed in fields (nil keeps all of them) and
+// collapses the nested objects not listed in expand (nil expands all of them)
+func shapeValue(value any, fields, expand pathTree) any {
+	switch v := value.(type) {
+	case []any:
+		for i := range v {
+			v[i] = shapeValue(v[i], fields, expand)
+		}
+		return v
+	case map[string]any:
+		for key, child := range v {
+			var subFields pathTree
+			if fields != nil {
+				sub, keep := fields[key]
+				if !keep {
+					delete(v, key)
+					continue
+				}
+				subFields = sub
+			}
+
+			var subExpand pathTree
+			if expand != nil {
+				sub, expanded := expand[key]
+				if !expanded {
+					if object, ok := child.(map[string]any); ok {
+						if id, ok := object["id"]; ok {
+							v[key] = map[string]any{"id": id}
+							continue
+						}
+					}
+					sub = pathTree{}
+				}
+				subExpand = sub
+			}
+			v[key] = shapeValue(child, subFields, subExpand)
+		}
+		return v
+	default:
+		return value
+	}
+}
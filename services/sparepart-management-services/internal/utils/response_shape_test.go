@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type shapeLocation struct {
+	ID      int32  `json:"id"`
+	Cluster string `json:"cluster"`
+	Regency string `json:"regency"`
+}
+
+type shapeItem struct {
+	ID       int32  `json:"id"`
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+type shapeGroup struct {
+	ID        int32         `json:"id"`
+	Location  shapeLocation `json:"location"`
+	Sparepart []shapeItem   `json:"sparepart"`
+	CreatedAt string        `json:"created_at"`
+}
+
+func shapeFromQuery(t *testing.T, query string) (*ResponseShape, []FieldError) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?"+query, nil)
+	return ParseResponseShape(c)
+}
+
+func TestResponseShapeApply(t *testing.T) {
+	groups := []shapeGroup{{
+		ID:        4,
+		Location:  shapeLocation{ID: 4, Cluster: "Dobo", Regency: "Kepulauan Aru"},
+		Sparepart: []shapeItem{{ID: 1, Name: "BMS", Quantity: 2}, {ID: 2, Name: "EHUB", Quantity: 1}},
+		CreatedAt: "2025-01-02T03:04:05Z",
+	}}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "no params",
+			query: "",
+			want:  `[{"id":4,"location":{"id":4,"cluster":"Dobo","regency":"Kepulauan Aru"},"sparepart":[{"id":1,"name":"BMS","quantity":2},{"id":2,"name":"EHUB","quantity":1}],"created_at":"2025-01-02T03:04:05Z"}]`,
+		},
+		{
+			name:  "fields look through arrays",
+			query: "fields=id,location.cluster,sparepart.name",
+			want:  `[{"id":4,"location":{"cluster":"Dobo"},"sparepart":[{"name":"BMS"},{"name":"EHUB"}]}]`,
+		},
+		{
+			name:  "a shorter path keeps the whole object",
+			query: "fields=location.cluster,location",
+			want:  `[{"location":{"id":4,"cluster":"Dobo","regency":"Kepulauan Aru"}}]`,
+		},
+		{
+			name:  "empty expand collapses nested objects",
+			query: "expand=",
+			want:  `[{"id":4,"location":{"id":4},"sparepart":[{"id":1,"name":"BMS","quantity":2},{"id":2,"name":"EHUB","quantity":1}],"created_at":"2025-01-02T03:04:05Z"}]`,
+		},
+		{
+			name:  "expanded objects stay whole",
+			query: "fields=id,location&expand=location",
+			want:  `[{"id":4,"location":{"id":4,"cluster":"Dobo","regency":"Kepulauan Aru"}}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shape, errs := shapeFromQuery(t, tt.query)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %+v", errs)
+			}
+			data, err := shape.Apply(groups)
+			if err != nil {
+				t.Fatalf("apply failed: %v", err)
+			}
+			got, _ := json.Marshal(data)
+			if canonicalJSON(t, string(got)) != canonicalJSON(t, tt.want) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// canonicalJSON re-encodes value with sorted object keys
+func canonicalJSON(t *testing.T, value string) string {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		t.Fatalf("invalid JSON %s: %v", value, err)
+	}
+	canonical, _ := json.Marshal(v)
+	return string(canonical)
+}
+
+func TestParseResponseShapeRejectsInvalidPaths(t *testing.T) {
+	tests := []struct {
+		query string
+		field string
+	}{
+		{query: "fields=", field: "fields"},
+		{query: "fields=location..cluster", field: "fields"},
+		{query: "expand=Location", field: "expand"},
+	}
+	for _, tt := range tests {
+		_, errs := shapeFromQuery(t, tt.query)
+		if len(errs) != 1 || errs[0].Field != tt.field {
+			t.Errorf("%s: unexpected errors %+v", tt.query, errs)
+		}
+	}
+}
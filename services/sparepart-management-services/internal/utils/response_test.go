@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bindingItem struct {
+	LocationID uint   `json:"location_id" binding:"required"`
+	StockType  string `json:"stock_type" binding:"omitempty,oneof=NEW_STOCK USED_STOCK"`
+}
+
+type bindingRequest struct {
+	Name  string        `json:"name" binding:"required,min=3"`
+	Limit int           `json:"limit" binding:"max=100"`
+	Items []bindingItem `json:"items" binding:"required,min=1,dive"`
+}
+
+func TestBindingError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/", func(c *gin.Context) {
+		var req bindingRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			BindingError(c, err)
+			return
+		}
+		Success(c, "ok", nil)
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantErrors []FieldError
+		wantError  string
+	}{
+		{
+			name: "rules",
+			body: `{"name":"ab","limit":500,"items":[{"location_id":1},{"stock_type":"OLD"}]}`,
+			wantErrors: []FieldError{
+				{Field: "name", Rule: "min", Message: "must have at least 3 characters"},
+				{Field: "limit", Rule: "max", Message: "must be less than or equal to 100"},
+				{Field: "items[1].location_id", Rule: "required", Message: "is required"},
+				{Field: "items[1].stock_type", Rule: "oneof", Message: "must be one of NEW_STOCK, USED_STOCK"},
+			},
+		},
+		{
+			name:       "wrong type",
+			body:       `{"name":"abc","limit":"ten","items":[{"location_id":1}]}`,
+			wantErrors: []FieldError{{Field: "limit", Rule: "type", Message: "must be an integer"}},
+		},
+		{name: "empty body", body: "", wantError: "Request body is required"},
+		{name: "malformed", body: `{"name":`, wantError: "Invalid request body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var resp Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tt.wantErrors != nil {
+				if resp.Code != ErrCodeValidation || !reflect.DeepEqual(resp.Errors, tt.wantErrors) {
+					t.Fatalf("unexpected errors: %+v", resp)
+				}
+				return
+			}
+			if !strings.HasPrefix(resp.Error, tt.wantError) || len(resp.Errors) != 0 {
+				t.Fatalf("unexpected error: %+v", resp)
+			}
+		})
+	}
+}
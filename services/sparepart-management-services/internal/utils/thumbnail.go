@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"path"
+	"strings"
+)
+
+// thumbnailSuffix is appended to a photo's name (before .jpg) for its thumbnail
+const thumbnailSuffix = "_thumb"
+
+// ThumbnailPath returns the stored reference of a photo's thumbnail, a JPEG next to the
+// original: /uploads/tools_alker/x.png has /uploads/tools_alker/x_thumb.jpg
+func ThumbnailPath(filePath string) string {
+	return strings.TrimSuffix(filePath, path.Ext(filePath)) + thumbnailSuffix + ".jpg"
+}
@@ -0,0 +1,15 @@
+package utils
+
+import "testing"
+
+func TestThumbnailPath(t *testing.T) {
+	tests := map[string]string{
+		"/uploads/tools_alker/x.png":           "/uploads/tools_alker/x_thumb.jpg",
+		"/uploads/sparepart/new_stock/a.b.jpg": "/uploads/sparepart/new_stock/a.b_thumb.jpg",
+	}
+	for path, want := range tests {
+		if got := ThumbnailPath(path); got != want {
+			t.Fatalf("ThumbnailPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
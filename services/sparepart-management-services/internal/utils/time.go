@@ -3,8 +3,19 @@ package utils
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// FormatTimestamp renders a database timestamp as RFC3339 in UTC, or an empty string when NULL
+func FormatTimestamp(ts pgtype.Timestamptz) string {
+	if !ts.Valid {
+		return ""
+	}
+	return ts.Time.UTC().Format(time.RFC3339)
+}
+
 // FormatUptime converts seconds to human-readable format (e.g., "6m 16s", "1h 2m 3s", "2d 3h 4m 5s")
 func FormatUptime(seconds float64) string {
 	days := int(seconds / 86400)
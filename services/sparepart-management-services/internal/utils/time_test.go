@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestFormatTimestampRoundTrip(t *testing.T) {
+	jayapura := time.FixedZone("WIT", 9*60*60)
+	stored := time.Date(2025, 3, 14, 8, 30, 0, 0, jayapura)
+
+	formatted := FormatTimestamp(pgtype.Timestamptz{Time: stored, Valid: true})
+	if formatted != "2025-03-13T23:30:00Z" {
+		t.Fatalf("expected UTC timestamp, got %q", formatted)
+	}
+
+	parsed, err := time.Parse(time.RFC3339, formatted)
+	if err != nil {
+		t.Fatalf("formatted timestamp does not parse: %v", err)
+	}
+	if !parsed.Equal(stored) {
+		t.Fatalf("round trip changed the instant: %s != %s", parsed, stored)
+	}
+}
+
+func TestFormatTimestampSameInstantSameString(t *testing.T) {
+	instant := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	zones := []*time.Location{time.UTC, time.FixedZone("WIB", 7*60*60), time.FixedZone("PST", -8*60*60)}
+
+	for _, zone := range zones {
+		got := FormatTimestamp(pgtype.Timestamptz{Time: instant.In(zone), Valid: true})
+		if got != "2025-01-01T00:00:00Z" {
+			t.Fatalf("timestamp in %s formatted as %q", zone, got)
+		}
+	}
+}
+
+func TestFormatTimestampNull(t *testing.T) {
+	if got := FormatTimestamp(pgtype.Timestamptz{}); got != "" {
+		t.Fatalf("expected empty string for NULL, got %q", got)
+	}
+}
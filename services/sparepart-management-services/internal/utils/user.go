@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// UserKey is the context key under which middleware.Authenticate stores the username of
+// the authenticated user
+const UserKey = "auth_user"
+
+// UserID returns the requesting user: the username of the access token or the name of the
+// API key that authenticated the request, or "" when it wasn't authenticated
+func UserID(c *gin.Context) string {
+	return c.GetString(UserKey)
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// FieldError describes why a single request field was rejected; Rule names the failed
+// validation rule (e.g. required, min, oneof) for errors found by request binding
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+// NegativeQuantityError is the field error for a quantity below zero
+func NegativeQuantityError(field string) FieldError {
+	return FieldError{Field: field, Message: "must be greater than or equal to 0"}
+}
+
+// BlankNameError is the field error for a regency, cluster or supplier name that is only whitespace
+var BlankNameError = FieldError{Field: "name", Message: "must not be blank"}
+
+// UnitError is the field error for a unit of measure that is not one of models.Units
+var UnitError = FieldError{Field: "unit", Message: "must be one of PCS, METER, SET"}
+
+// UnitMismatchError is the field error for a quantity given in another unit than the one its
+// sparepart is counted in
+func UnitMismatchError(field, unit string) FieldError {
+	return FieldError{Field: field, Message: fmt.Sprintf("must be %s, the unit of the sparepart", unit)}
+}
+
+// StockLevelRangeError is the field error for a maximum stock level below the minimum
+var StockLevelRangeError = FieldError{Field: "max_quantity", Message: "must be greater than or equal to min_quantity"}
+
+// MaxUnitCost is the highest unit cost the NUMERIC(14, 2) cost columns hold
+const MaxUnitCost = 999999999999.99
+
+// UnitCostError is the field error for a unit cost that is negative or too large to store
+func UnitCostError(field string) FieldError {
+	return FieldError{Field: field, Message: "must be between 0 and 999999999999.99"}
+}
+
+// ValidUnitCost reports whether an optional unit cost bound straight into query params fits
+// the cost columns
+func ValidUnitCost(cost pgtype.Numeric) bool {
+	if !cost.Valid {
+		return true
+	}
+	f, err := cost.Float64Value()
+	return err == nil && !cost.NaN && f.Float64 >= 0 && f.Float64 <= MaxUnitCost
+}
+
+// Field errors for location coordinates, in WGS84 degrees and set as a pair
+var (
+	LatitudeRangeError   = FieldError{Field: "latitude", Message: "must be between -90 and 90"}
+	LongitudeRangeError  = FieldError{Field: "longitude", Message: "must be between -180 and 180"}
+	CoordinatesPairError = FieldError{Field: "longitude", Message: "latitude and longitude must be given together"}
+)
+
+// ValidateCoordinates checks an optional latitude/longitude pair
+func ValidateCoordinates(latitude, longitude pgtype.Float8) []FieldError {
+	if latitude.Valid != longitude.Valid {
+		return []FieldError{CoordinatesPairError}
+	}
+	var errs []FieldError
+	if latitude.Valid && (latitude.Float64 < -90 || latitude.Float64 > 90) {
+		errs = append(errs, LatitudeRangeError)
+	}
+	if longitude.Valid && (longitude.Float64 < -180 || longitude.Float64 > 180) {
+		errs = append(errs, LongitudeRangeError)
+	}
+	return errs
+}
+
+// ValidationError responds 400 with field-level errors
+func ValidationError(c *gin.Context, fields ...FieldError) {
+	c.JSON(http.StatusBadRequest, Response{
+		Success: false,
+		Error:   "Validation failed",
+		Code:    ErrCodeValidation,
+		Errors:  fields,
+	})
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VersionConflict is the data of a version conflict response, so the client can reload
+type VersionConflict struct {
+	CurrentVersion int32 `json:"current_version"`
+}
+
+// ETag formats a row version as a strong entity tag
+func ETag(version int32) string {
+	return `"` + strconv.Itoa(int(version)) + `"`
+}
+
+// ExpectedVersion reads the row version an update is based on: the If-Match header (an ETag
+// as returned by ETag, weak tags accepted) or else the version field of the body. It writes
+// the error response and reports false when neither is given or the header is malformed.
+func ExpectedVersion(c *gin.Context, bodyVersion *int32) (int32, bool) {
+	if header := strings.TrimSpace(c.GetHeader("If-Match")); header != "" {
+		tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+		version, err := strconv.ParseInt(tag, 10, 32)
+		if err != nil || version < 1 {
+			ValidationError(c, FieldError{Field: "If-Match", Message: "must be the version of the item, e.g. \"3\""})
+			return 0, false
+		}
+		return int32(version), true
+	}
+	if bodyVersion != nil {
+		return *bodyVersion, true
+	}
+	c.JSON(http.StatusPreconditionRequired, Response{
+		Error:  "If-Match header or version field is required",
+		Code:   ErrCodeValidation,
+		Errors: []FieldError{{Field: "version", Rule: "required", Message: "is required"}},
+	})
+	return 0, false
+}
+
+// VersionConflictError responds that the row was changed since the client read it
+func VersionConflictError(c *gin.Context, currentVersion int32) {
+	c.Header("ETag", ETag(currentVersion))
+	c.JSON(http.StatusConflict, Response{
+		Error: "Item was changed by someone else; reload it and try again",
+		Code:  ErrCodeVersionConflict,
+		Data:  VersionConflict{CurrentVersion: currentVersion},
+	})
+}
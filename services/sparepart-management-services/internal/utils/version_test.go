@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	five := int32(5)
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		bodyVersion *int32
+		wantVersion int32
+		wantStatus  int
+	}{
+		{name: "strong etag", ifMatch: `"3"`, wantVersion: 3},
+		{name: "weak etag", ifMatch: `W/"3"`, wantVersion: 3},
+		{name: "header wins over body", ifMatch: `"3"`, bodyVersion: &five, wantVersion: 3},
+		{name: "body version", bodyVersion: &five, wantVersion: 5},
+		{name: "malformed header", ifMatch: `"abc"`, wantStatus: http.StatusBadRequest},
+		{name: "missing", wantStatus: http.StatusPreconditionRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			version, ok := ExpectedVersion(c, tt.bodyVersion)
+			if tt.wantStatus != 0 {
+				if ok || w.Code != tt.wantStatus {
+					t.Fatalf("expected status %d, got ok=%v status %d", tt.wantStatus, ok, w.Code)
+				}
+				return
+			}
+			if !ok || version != tt.wantVersion {
+				t.Fatalf("expected version %d, got %d (ok=%v)", tt.wantVersion, version, ok)
+			}
+		})
+	}
+}
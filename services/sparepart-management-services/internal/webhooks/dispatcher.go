@@ -0,0 +1,170 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	// fanOutBatch is how many outbox events one run fans out at most
+	fanOutBatch = 500
+	// deliveryBatch is how many deliveries one run sends at most
+	deliveryBatch = 20
+	// maxRetryDelay caps the growing delay between attempts
+	maxRetryDelay = time.Hour
+	// maxErrorLength caps the stored error and response excerpt of a failed attempt
+	maxErrorLength = 500
+)
+
+// Payload is the JSON body of a delivery; Data is the changed row (without its
+// documentation) and has previous_quantity on updates
+type Payload struct {
+	ID         int64           `json:"id"`
+	Event      string          `json:"event"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Dispatcher fans new events out to deliveries and sends the deliveries that are due.
+// A delivery is retried after 1, 2, 4, ... minutes (at most an hour apart) until it
+// succeeds or maxAttempts attempts failed, which marks it FAILED.
+type Dispatcher struct {
+	logger      *zap.Logger
+	queries     repository.WebhookDispatchRepository
+	client      *http.Client
+	timeout     time.Duration
+	maxAttempts int
+}
+
+func NewDispatcher(queries repository.WebhookDispatchRepository, timeout time.Duration, maxAttempts int, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		logger:      logger,
+		queries:     queries,
+		client:      &http.Client{Timeout: timeout},
+		timeout:     timeout,
+		maxAttempts: max(maxAttempts, 1),
+	}
+}
+
+// Dispatch runs one round; a failing delivery does not stop the others
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	if _, err := d.queries.FanOutWebhookEvents(ctx, fanOutBatch); err != nil {
+		return fmt.Errorf("failed to fan out webhook events: %w", err)
+	}
+
+	// The lease outlasts sending the whole batch, so no other replica picks it up meanwhile
+	lease := d.timeout*deliveryBatch + time.Minute
+	deliveries, err := d.queries.ClaimWebhookDeliveries(ctx, sqlcdb.ClaimWebhookDeliveriesParams{
+		LeaseSeconds: int32(lease.Seconds()),
+		Limit:        deliveryBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	var errs []error
+	for _, delivery := range deliveries {
+		if err := d.send(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("webhook delivery %d: %w", delivery.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send posts one delivery and records the outcome; only failing to record it is an error
+func (d *Dispatcher) send(ctx context.Context, delivery sqlcdb.ClaimWebhookDeliveriesRow) error {
+	status, sendErr := d.post(ctx, delivery)
+	responseStatus := pgtype.Int4{Int32: int32(status), Valid: status != 0}
+
+	if sendErr == nil {
+		return d.queries.MarkWebhookDeliveryDelivered(ctx, sqlcdb.MarkWebhookDeliveryDeliveredParams{
+			ID:             delivery.ID,
+			ResponseStatus: responseStatus,
+		})
+	}
+
+	attempts := int(delivery.Attempts) + 1
+	params := sqlcdb.MarkWebhookDeliveryFailedParams{
+		ID:             delivery.ID,
+		Status:         "PENDING",
+		ResponseStatus: responseStatus,
+		LastError:      pgtype.Text{String: truncate(sendErr.Error(), maxErrorLength), Valid: true},
+		NextAttemptAt:  pgtype.Timestamptz{Time: time.Now().UTC().Add(retryDelay(attempts)), Valid: true},
+	}
+	if attempts >= d.maxAttempts {
+		params.Status = "FAILED"
+	}
+
+	d.logger.Warn("Webhook delivery failed",
+		zap.Int64("delivery_id", delivery.ID),
+		zap.String("event", delivery.Event),
+		zap.Int("attempts", attempts),
+		zap.String("status", params.Status),
+		zap.Error(sendErr),
+	)
+	return d.queries.MarkWebhookDeliveryFailed(ctx, params)
+}
+
+// post sends the signed delivery, returning the response status (0 without a response)
+func (d *Dispatcher) post(ctx context.Context, delivery sqlcdb.ClaimWebhookDeliveriesRow) (int, error) {
+	body, err := json.Marshal(Payload{
+		ID:         delivery.ID,
+		Event:      delivery.Event,
+		OccurredAt: delivery.OccurredAt.Time.UTC().Format(time.RFC3339),
+		Data:       delivery.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, excerpt)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryDelay is the wait after the given number of failed attempts: 1, 2, 4, ... minutes
+func retryDelay(attempts int) time.Duration {
+	if attempts > 7 {
+		return maxRetryDelay
+	}
+	return min(time.Minute<<(attempts-1), maxRetryDelay)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	sqlcdb "sparepart-management-services/internal/database/sqlc"
+	"sparepart-management-services/internal/repository/mocks"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestDispatcherSendsSignedDelivery(t *testing.T) {
+	var received http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockWebhookDispatchRepository(ctrl)
+	dispatcher := NewDispatcher(repo, 5*time.Second, 3, zap.NewNop())
+
+	repo.EXPECT().FanOutWebhookEvents(gomock.Any(), int32(fanOutBatch)).Return(int64(1), nil)
+	repo.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Return([]sqlcdb.ClaimWebhookDeliveriesRow{{
+		ID:         12,
+		Event:      EventStockLow,
+		Url:        server.URL,
+		Secret:     "whsec_test",
+		Payload:    []byte(`{"id":4,"quantity":0,"previous_quantity":3}`),
+		OccurredAt: pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+	}}, nil)
+	repo.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), sqlcdb.MarkWebhookDeliveryDeliveredParams{
+		ID:             12,
+		ResponseStatus: pgtype.Int4{Int32: http.StatusNoContent, Valid: true},
+	}).Return(nil)
+
+	if err := dispatcher.Dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+
+	if received.Get(HeaderEvent) != EventStockLow || received.Get(HeaderDelivery) != "12" {
+		t.Fatalf("unexpected headers: %v", received)
+	}
+	timestamp, err := strconv.ParseInt(received.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+	if got := received.Get(HeaderSignature); got != Sign("whsec_test", timestamp, body) {
+		t.Fatalf("signature %q does not match the body", got)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.ID != 12 || payload.Event != EventStockLow || payload.OccurredAt != "2025-01-02T03:04:05Z" || string(payload.Data) != `{"id":4,"quantity":0,"previous_quantity":3}` {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestDispatcherRetriesFailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "ticketing unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		attempts   int32
+		wantStatus string
+	}{
+		{name: "retries remain", attempts: 1, wantStatus: "PENDING"},
+		{name: "last attempt", attempts: 2, wantStatus: "FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockWebhookDispatchRepository(ctrl)
+			dispatcher := NewDispatcher(repo, 5*time.Second, 3, zap.NewNop())
+
+			repo.EXPECT().FanOutWebhookEvents(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+			repo.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Return([]sqlcdb.ClaimWebhookDeliveriesRow{{
+				ID:       7,
+				Event:    EventStockUpdated,
+				Attempts: tt.attempts,
+				Url:      server.URL,
+				Payload:  []byte(`{}`),
+			}}, nil)
+			repo.EXPECT().
+				MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, arg sqlcdb.MarkWebhookDeliveryFailedParams) error {
+					if arg.ID != 7 || arg.Status != tt.wantStatus || arg.ResponseStatus.Int32 != http.StatusServiceUnavailable {
+						t.Fatalf("unexpected failure record: %+v", arg)
+					}
+					wantDelay := retryDelay(int(tt.attempts) + 1)
+					if delay := time.Until(arg.NextAttemptAt.Time); delay < wantDelay-time.Minute || delay > wantDelay {
+						t.Fatalf("expected the next attempt in about %v, got %v", wantDelay, delay)
+					}
+					return nil
+				})
+
+			if err := dispatcher.Dispatch(context.Background()); err != nil {
+				t.Fatalf("dispatch failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 4, want: 8 * time.Minute},
+		{attempts: 7, want: time.Hour},
+		{attempts: 40, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package webhooks sends stock change events to the webhooks subscribed to them. Database
+// triggers record the events in an outbox in the same transaction as the change; the
+// dispatcher fans them out to one delivery per subscribed webhook and posts each delivery,
+// signed with the webhook's secret, retrying failures with a growing delay.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// Events a webhook can subscribe to
+const (
+	EventStockCreated      = "stock.created"
+	EventStockUpdated      = "stock.updated"
+	EventStockDeleted      = "stock.deleted"
+	EventStockRestored     = "stock.restored"
+	EventStockLow          = "stock.low"
+	EventToolsAlkerCreated = "tools_alker.created"
+	EventToolsAlkerUpdated = "tools_alker.updated"
+	EventToolsAlkerDeleted = "tools_alker.deleted"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// NewSecret returns a random signing secret for a new webhook
+func NewSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Sign returns the X-Webhook-Signature of a delivery: "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook's secret. Receivers recompute
+// it from the X-Webhook-Timestamp header and the raw body, and can reject old timestamps
+// to stop replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}